@@ -52,6 +52,7 @@ type Todo struct {
 	Duration    time.Duration
 	RepeatAfter int64
 	RepeatMode  models.TaskRepeatMode
+	RepeatRule  string
 	Alarms      []Alarm
 
 	Created time.Time
@@ -167,7 +168,10 @@ CREATED:` + makeCalDavTimeFromTimeStamp(t.Created)
 PRIORITY:` + strconv.Itoa(mapPriorityToCaldav(t.Priority))
 		}
 
-		if t.RepeatAfter > 0 || t.RepeatMode == models.TaskRepeatModeMonth {
+		if t.RepeatRule != "" {
+			caldavtodos += `
+RRULE:` + t.RepeatRule
+		} else if t.RepeatAfter > 0 || t.RepeatMode == models.TaskRepeatModeMonth {
 			if t.RepeatMode == models.TaskRepeatModeMonth {
 				caldavtodos += `
 RRULE:FREQ=MONTHLY;BYMONTHDAY=` + t.DueDate.Format("02") // Day of the month
@@ -253,6 +257,14 @@ RELATED-TO;RELTYPE=CHILD:`
 			continue
 		case models.RelationKindCopiedTo:
 			continue
+		case models.RelationKindCausedBy:
+			continue
+		case models.RelationKindCauses:
+			continue
+		case models.RelationKindFixes:
+			continue
+		case models.RelationKindFixedBy:
+			continue
 		default:
 			caldavrelatedtos += `
 RELATED-TO:`
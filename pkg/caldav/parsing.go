@@ -77,6 +77,7 @@ func GetCaldavTodosForTasks(project *models.ProjectWithTasksAndBuckets, projectT
 			Duration:    duration,
 			RepeatAfter: t.RepeatAfter,
 			RepeatMode:  t.RepeatMode,
+			RepeatRule:  t.RepeatRule,
 			Alarms:      alarms,
 			Relations:   relations,
 		})
@@ -182,6 +183,10 @@ func ParseTaskFromVTODO(content string) (vTask *models.Task, err error) {
 		vTask.Done = true
 	}
 
+	if val, ok := task["RRULE"]; ok {
+		vTask = parseRRule(val.Value, vTask)
+	}
+
 	if duration > 0 && !vTask.StartDate.IsZero() {
 		vTask.EndDate = vTask.StartDate.Add(duration)
 	}
@@ -195,6 +200,27 @@ func ParseTaskFromVTODO(content string) (vTask *models.Task, err error) {
 	return
 }
 
+// parseRRule maps a recurrence rule to the repeat settings of a task. Rules in the form Vikunja uses to export
+// tasks repeating after a fixed amount of seconds are mapped back to that, all other rules are kept as they are.
+func parseRRule(rule string, vTask *models.Task) *models.Task {
+	if after, found := strings.CutPrefix(rule, "FREQ=SECONDLY;INTERVAL="); found {
+		repeatAfter, err := strconv.ParseInt(after, 10, 64)
+		if err == nil {
+			vTask.RepeatAfter = repeatAfter
+			return vTask
+		}
+	}
+
+	normalized, err := models.NormalizeRepeatRule(rule)
+	if err != nil {
+		log.Debugf("[CALDAV] Ignoring unsupported recurrence rule %s of task %s: %s", rule, vTask.UID, err)
+		return vTask
+	}
+
+	vTask.RepeatRule = normalized
+	return vTask
+}
+
 func parseVAlarm(vAlarm *ics.VAlarm, vTask *models.Task) *models.Task {
 	for _, property := range vAlarm.UnknownPropertiesIANAProperties() {
 		if property.IANAToken != "TRIGGER" {
@@ -0,0 +1,74 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/initialize"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	tokenCmd.AddCommand(tokenRateLimitCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage api tokens of all users locally through the cli.",
+}
+
+var tokenRateLimitCmd = &cobra.Command{
+	Use:   "ratelimit [token id] [limit]",
+	Short: "Set the rate limit of an api token.",
+	Long:  "Set the max number of requests an api token can do in the configured rate limit period. Use 0 to reset it to the limit configured for all tokens.",
+	Args:  cobra.ExactArgs(2),
+	PreRun: func(_ *cobra.Command, _ []string) {
+		initialize.FullInit()
+	},
+	Run: func(_ *cobra.Command, args []string) {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid token id: %s", err)
+		}
+		limit, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || limit < 0 {
+			log.Fatalf("Invalid limit: %s", args[1])
+		}
+
+		s := db.NewSession()
+		defer s.Close()
+
+		err = models.SetAPITokenRateLimit(s, id, limit)
+		if err != nil {
+			_ = s.Rollback()
+			log.Fatalf("Could not set the rate limit: %s", err)
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Fatalf("Error saving everything: %s", err)
+		}
+
+		fmt.Printf("Rate limit of token %d successfully set to %d\n", id, limit)
+	},
+}
@@ -0,0 +1,42 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(webPushKeysCmd)
+}
+
+var webPushKeysCmd = &cobra.Command{
+	Use:   "webpush-keys",
+	Short: "Generate a new VAPID key pair to use for Web Push notifications",
+	Run: func(_ *cobra.Command, _ []string) {
+		publicKey, privateKey, err := notifications.GenerateVAPIDKeys()
+		if err != nil {
+			log.Fatalf("Could not generate keys: %s", err)
+		}
+
+		fmt.Printf("Add these keys to the webpush section of your config:\n\npublickey: %s\nprivatekey: %s\n", publicKey, privateKey)
+	},
+}
@@ -38,23 +38,25 @@ type Key string
 // These constants hold all config value keys
 const (
 	// #nosec
-	ServiceJWTSecret       Key = `service.JWTSecret`
-	ServiceJWTTTL          Key = `service.jwtttl`
-	ServiceJWTTTLLong      Key = `service.jwtttllong`
-	ServiceInterface       Key = `service.interface`
-	ServiceUnixSocket      Key = `service.unixsocket`
-	ServiceUnixSocketMode  Key = `service.unixsocketmode`
-	ServicePublicURL       Key = `service.publicurl`
-	ServiceEnableCaldav    Key = `service.enablecaldav`
-	ServiceRootpath        Key = `service.rootpath`
-	ServiceMaxItemsPerPage Key = `service.maxitemsperpage`
-	ServiceDemoMode        Key = `service.demomode`
+	ServiceJWTSecret          Key = `service.JWTSecret`
+	ServiceJWTTTL             Key = `service.jwtttl`
+	ServiceJWTTTLLong         Key = `service.jwtttllong`
+	ServiceInterface          Key = `service.interface`
+	ServiceUnixSocket         Key = `service.unixsocket`
+	ServiceUnixSocketMode     Key = `service.unixsocketmode`
+	ServicePublicURL          Key = `service.publicurl`
+	ServiceEnableCaldav       Key = `service.enablecaldav`
+	ServiceRootpath           Key = `service.rootpath`
+	ServiceMaxItemsPerPage    Key = `service.maxitemsperpage`
+	ServiceMaxBatchOperations Key = `service.maxbatchoperations`
+	ServiceDemoMode           Key = `service.demomode`
 	// Deprecated: Use metrics.enabled
 	ServiceEnableMetrics         Key = `service.enablemetrics`
 	ServiceMotd                  Key = `service.motd`
 	ServiceEnableLinkSharing     Key = `service.enablelinksharing`
 	ServiceEnableRegistration    Key = `service.enableregistration`
 	ServiceEnableTaskAttachments Key = `service.enabletaskattachments`
+	ServiceEnableLinkPreviews    Key = `service.enablelinkpreviews`
 	ServiceTimeZone              Key = `service.timezone`
 	ServiceEnableTaskComments    Key = `service.enabletaskcomments`
 	ServiceEnableTotp            Key = `service.enabletotp`
@@ -65,6 +67,9 @@ const (
 	ServiceAllowIconChanges      Key = `service.allowiconchanges`
 	ServiceCustomLogoURL         Key = `service.customlogourl`
 	ServiceEnablePublicTeams     Key = `service.enablepublicteams`
+	ServiceMaxTaskDepth          Key = `service.maxtaskdepth`
+	ServiceMaxProjectDepth       Key = `service.maxprojectdepth`
+	ServiceEnableFuzzySearch     Key = `service.enablefuzzysearch`
 
 	SentryEnabled         Key = `sentry.enabled`
 	SentryDsn             Key = `sentry.dsn`
@@ -133,6 +138,7 @@ const (
 	RateLimitLimit             Key = `ratelimit.limit`
 	RateLimitStore             Key = `ratelimit.store`
 	RateLimitNoAuthRoutesLimit Key = `ratelimit.noauthlimit`
+	RateLimitAPITokenLimit     Key = `ratelimit.apitokenlimit`
 
 	FilesBasePath Key = `files.basepath`
 	FilesMaxSize  Key = `files.maxsize`
@@ -157,6 +163,7 @@ const (
 
 	BackgroundsEnabled               Key = `backgrounds.enabled`
 	BackgroundsUploadEnabled         Key = `backgrounds.providers.upload.enabled`
+	BackgroundsURLEnabled            Key = `backgrounds.providers.url.enabled`
 	BackgroundsUnsplashEnabled       Key = `backgrounds.providers.unsplash.enabled`
 	BackgroundsUnsplashAccessToken   Key = `backgrounds.providers.unsplash.accesstoken`
 	BackgroundsUnsplashApplicationID Key = `backgrounds.providers.unsplash.applicationid`
@@ -179,10 +186,71 @@ const (
 	DefaultSettingsTimezone                    Key = `defaultsettings.timezone`
 	DefaultSettingsOverdueTaskRemindersTime    Key = `defaultsettings.overdue_tasks_reminders_time`
 
-	WebhooksEnabled        Key = `webhooks.enabled`
-	WebhooksTimeoutSeconds Key = `webhooks.timeoutseconds`
-	WebhooksProxyURL       Key = `webhooks.proxyurl`
-	WebhooksProxyPassword  Key = `webhooks.proxypassword`
+	WebhooksEnabled               Key = `webhooks.enabled`
+	WebhooksTimeoutSeconds        Key = `webhooks.timeoutseconds`
+	WebhooksProxyURL              Key = `webhooks.proxyurl`
+	WebhooksProxyPassword         Key = `webhooks.proxypassword`
+	WebhooksMaxRetries            Key = `webhooks.maxretries`
+	WebhooksRetryDelaySeconds     Key = `webhooks.retrydelayseconds`
+	WebhooksDeliveryRetentionDays Key = `webhooks.deliveryretentiondays`
+	WebhooksInboundEnabled        Key = `webhooks.inboundenabled`
+
+	OutboundBlockNonPublicAddresses Key = `outbound.blocknonpublicaddresses`
+	OutboundAllowedCIDRs            Key = `outbound.allowedcidrs`
+	OutboundDeniedCIDRs             Key = `outbound.deniedcidrs`
+	OutboundProxyURL                Key = `outbound.proxyurl`
+
+	EventStreamEnabled             Key = `eventstream.enabled`
+	EventStreamPollIntervalSeconds Key = `eventstream.pollintervalseconds`
+	EventStreamRetentionHours      Key = `eventstream.retentionhours`
+
+	IdempotencyEnabled        Key = `idempotency.enabled`
+	IdempotencyRetentionHours Key = `idempotency.retentionhours`
+
+	GraphQLMaxDepth      Key = `graphql.maxdepth`
+	GraphQLMaxComplexity Key = `graphql.maxcomplexity`
+
+	KanbanBucketTemplates Key = `kanban.buckettemplates`
+
+	ProjectsTemplates Key = `projects.templates`
+
+	InboundMailEnabled Key = `inboundmail.enabled`
+	InboundMailDomain  Key = `inboundmail.domain`
+	InboundMailSecret  Key = `inboundmail.secret`
+
+	WebPushEnabled    Key = `webpush.enabled`
+	WebPushPublicKey  Key = `webpush.publickey`
+	WebPushPrivateKey Key = `webpush.privatekey`
+	WebPushSubject    Key = `webpush.subject`
+
+	NtfyEnabled  Key = `ntfy.enabled`
+	NtfyURL      Key = `ntfy.url`
+	NtfyToken    Key = `ntfy.token`
+	NtfyPriority Key = `ntfy.priority`
+
+	GotifyEnabled  Key = `gotify.enabled`
+	GotifyURL      Key = `gotify.url`
+	GotifyPriority Key = `gotify.priority`
+
+	MatrixEnabled       Key = `matrix.enabled`
+	MatrixHomeserverURL Key = `matrix.homeserverurl`
+	MatrixAccessToken   Key = `matrix.accesstoken`
+
+	SlackEnabled       Key = `slack.enabled`
+	SlackSigningSecret Key = `slack.signingsecret`
+
+	DiscordEnabled Key = `discord.enabled`
+
+	NotificationsRetentionDays       Key = `notifications.retentiondays`
+	NotificationsUnreadRetentionDays Key = `notifications.unreadretentiondays`
+	NotificationsGroupingWindow      Key = `notifications.groupingwindow`
+
+	NotificationTargetsEnabled    Key = `notificationtargets.enabled`
+	NotificationTargetsAppriseURL Key = `notificationtargets.appriseurl`
+
+	PluginsEnabled             Key = `plugins.enabled`
+	PluginsPath                Key = `plugins.path`
+	PluginsTimeoutMilliseconds Key = `plugins.timeoutmilliseconds`
 )
 
 // GetString returns a string config value
@@ -220,6 +288,11 @@ func (k Key) Get() interface{} {
 	return viper.Get(string(k))
 }
 
+// Unmarshal decodes a structured config option (like a list of objects) into target
+func (k Key) Unmarshal(target interface{}) error {
+	return viper.UnmarshalKey(string(k), target)
+}
+
 var timezone *time.Location
 
 // GetTimeZone returns the time zone configured for vikunja
@@ -300,11 +373,13 @@ func InitDefaultConfig() {
 
 	ServiceRootpath.setDefault(getBinaryDirLocation())
 	ServiceMaxItemsPerPage.setDefault(50)
+	ServiceMaxBatchOperations.setDefault(50)
 	ServiceEnableMetrics.setDefault(false)
 	ServiceMotd.setDefault("")
 	ServiceEnableLinkSharing.setDefault(true)
 	ServiceEnableRegistration.setDefault(true)
 	ServiceEnableTaskAttachments.setDefault(true)
+	ServiceEnableLinkPreviews.setDefault(true)
 	ServiceTimeZone.setDefault("GMT")
 	ServiceEnableTaskComments.setDefault(true)
 	ServiceEnableTotp.setDefault(true)
@@ -314,6 +389,9 @@ func InitDefaultConfig() {
 	ServiceDemoMode.setDefault(false)
 	ServiceAllowIconChanges.setDefault(true)
 	ServiceEnablePublicTeams.setDefault(false)
+	ServiceMaxTaskDepth.setDefault(10)
+	ServiceMaxProjectDepth.setDefault(10)
+	ServiceEnableFuzzySearch.setDefault(true)
 
 	// Sentry
 	SentryDsn.setDefault("https://440eedc957d545a795c17bbaf477497c@o1047380.ingest.sentry.io/4504254983634944")
@@ -379,6 +457,7 @@ func InitDefaultConfig() {
 	RateLimitPeriod.setDefault(60)
 	RateLimitStore.setDefault("memory")
 	RateLimitNoAuthRoutesLimit.setDefault(10)
+	RateLimitAPITokenLimit.setDefault(0)
 	// Files
 	FilesBasePath.setDefault("files")
 	FilesMaxSize.setDefault("20MB")
@@ -395,6 +474,7 @@ func InitDefaultConfig() {
 	// Project Backgrounds
 	BackgroundsEnabled.setDefault(true)
 	BackgroundsUploadEnabled.setDefault(true)
+	BackgroundsURLEnabled.setDefault(true)
 	BackgroundsUnsplashEnabled.setDefault(false)
 	// Key Value
 	KeyvalueType.setDefault("memory")
@@ -407,6 +487,50 @@ func InitDefaultConfig() {
 	// Webhook
 	WebhooksEnabled.setDefault(true)
 	WebhooksTimeoutSeconds.setDefault(30)
+	WebhooksMaxRetries.setDefault(5)
+	WebhooksRetryDelaySeconds.setDefault(60)
+	WebhooksDeliveryRetentionDays.setDefault(30)
+	WebhooksInboundEnabled.setDefault(true)
+	// Outbound
+	OutboundBlockNonPublicAddresses.setDefault(true)
+	OutboundAllowedCIDRs.setDefault([]string{})
+	OutboundDeniedCIDRs.setDefault([]string{})
+	// Event stream
+	EventStreamEnabled.setDefault(true)
+	EventStreamPollIntervalSeconds.setDefault(2)
+	EventStreamRetentionHours.setDefault(24)
+	// Idempotency
+	IdempotencyEnabled.setDefault(true)
+	IdempotencyRetentionHours.setDefault(24)
+	// GraphQL
+	GraphQLMaxDepth.setDefault(10)
+	GraphQLMaxComplexity.setDefault(500)
+	// Inbound mail
+	InboundMailEnabled.setDefault(false)
+	// Web Push
+	WebPushEnabled.setDefault(false)
+	// ntfy
+	NtfyEnabled.setDefault(false)
+	NtfyURL.setDefault("https://ntfy.sh")
+	NtfyPriority.setDefault(3)
+	// Gotify
+	GotifyEnabled.setDefault(false)
+	GotifyPriority.setDefault(5)
+	// Matrix
+	MatrixEnabled.setDefault(false)
+	// Slack
+	SlackEnabled.setDefault(false)
+	// Discord
+	DiscordEnabled.setDefault(false)
+	// Notifications
+	NotificationsRetentionDays.setDefault(0)
+	NotificationsUnreadRetentionDays.setDefault(0)
+	NotificationsGroupingWindow.setDefault(0)
+	// Notification targets
+	NotificationTargetsEnabled.setDefault(false)
+	// Plugins
+	PluginsEnabled.setDefault(false)
+	PluginsTimeoutMilliseconds.setDefault(1000)
 }
 
 // InitConfig initializes the config, sets defaults etc.
@@ -19,6 +19,7 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"sync"
 	"time"
 
 	"code.vikunja.io/api/pkg/config"
@@ -29,10 +30,17 @@ import (
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
 	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"xorm.io/xorm"
 )
 
 var pubsub *gochannel.GoChannel
 
+var (
+	pendingEventsLock sync.Mutex
+	// The events of all sessions which dispatch their events only after they were committed
+	pendingEvents = make(map[*xorm.Session][]Event)
+)
+
 // Event represents the event interface used by all events
 type Event interface {
 	Name() string
@@ -111,3 +119,51 @@ func Dispatch(event Event) error {
 	msg := message.NewMessage(watermill.NewUUID(), content)
 	return pubsub.Publish(event.Name(), msg)
 }
+
+// DeferUntilCommit makes all events which are dispatched with DispatchOnCommit for the session wait until
+// DispatchPending is called after the session was committed.
+// Call DiscardPending if the session is rolled back so that the events of the rolled back changes are never dispatched.
+func DeferUntilCommit(s *xorm.Session) {
+	pendingEventsLock.Lock()
+	defer pendingEventsLock.Unlock()
+	pendingEvents[s] = []Event{}
+}
+
+// DispatchOnCommit dispatches an event for changes made with the session. If the events of the session are deferred
+// with DeferUntilCommit, the event is only dispatched once DispatchPending is called. Otherwise, it is dispatched right away.
+func DispatchOnCommit(s *xorm.Session, event Event) error {
+	pendingEventsLock.Lock()
+	pending, deferred := pendingEvents[s]
+	if deferred {
+		pendingEvents[s] = append(pending, event)
+	}
+	pendingEventsLock.Unlock()
+
+	if deferred {
+		return nil
+	}
+
+	return Dispatch(event)
+}
+
+// DispatchPending dispatches all deferred events of the session in the order they were queued.
+// Call it after the session was committed. Since the changes are already saved at that point, errors are only logged.
+func DispatchPending(s *xorm.Session) {
+	pendingEventsLock.Lock()
+	pending := pendingEvents[s]
+	delete(pendingEvents, s)
+	pendingEventsLock.Unlock()
+
+	for _, event := range pending {
+		if err := Dispatch(event); err != nil {
+			log.Errorf("Could not dispatch event %s: %s", event.Name(), err)
+		}
+	}
+}
+
+// DiscardPending drops all deferred events of the session without dispatching them.
+func DiscardPending(s *xorm.Session) {
+	pendingEventsLock.Lock()
+	defer pendingEventsLock.Unlock()
+	delete(pendingEvents, s)
+}
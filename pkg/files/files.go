@@ -17,6 +17,9 @@
 package files
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io"
 	"os"
@@ -41,6 +44,8 @@ type File struct {
 	Name string `xorm:"text not null" json:"name"`
 	Mime string `xorm:"text null" json:"mime"`
 	Size uint64 `xorm:"bigint not null" json:"size"`
+	// The hex encoded sha256 hash of the file content. Only set for files created with CreateDeduplicated.
+	Sha256 string `xorm:"varchar(64) null index" json:"-"`
 
 	Created     time.Time `xorm:"created" json:"created"`
 	CreatedByID int64     `xorm:"bigint not null" json:"-"`
@@ -122,6 +127,56 @@ func CreateWithMimeAndSession(s *xorm.Session, f io.Reader, realname string, rea
 	return
 }
 
+// CreateDeduplicated creates a new file like Create does, but returns an existing file instead if a file with the
+// same content was created with CreateDeduplicated before. The content of the file is then not stored again.
+// Because the returned file might be used in multiple places, callers must make sure it is not used anywhere else
+// before deleting it.
+func CreateDeduplicated(f io.Reader, realname string, realsize uint64, a web.Auth) (file *File, err error) {
+	var maxSize datasize.ByteSize
+	err = maxSize.UnmarshalText([]byte(config.FilesMaxSize.GetString()))
+	if err != nil {
+		return nil, err
+	}
+	if realsize > maxSize.Bytes() {
+		return nil, ErrFileIsTooLarge{Size: realsize}
+	}
+
+	// The whole content is needed to calculate the hash before deciding whether to store the file
+	content, err := io.ReadAll(io.LimitReader(f, int64(maxSize.Bytes())+1))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(content)) > maxSize.Bytes() {
+		return nil, ErrFileIsTooLarge{Size: uint64(len(content))}
+	}
+	hash := sha256.Sum256(content)
+
+	s := db.NewSession()
+	defer s.Close()
+
+	file = &File{}
+	exists, err := s.
+		Where("sha256 = ? AND size = ?", hex.EncodeToString(hash[:]), realsize).
+		OrderBy("id asc").
+		Get(file)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return file, nil
+	}
+
+	file, err = CreateWithMimeAndSession(s, bytes.NewReader(content), realname, realsize, a, "", true)
+	if err != nil {
+		_ = s.Rollback()
+		return nil, err
+	}
+
+	file.Sha256 = hex.EncodeToString(hash[:])
+	_, err = s.ID(file.ID).Cols("sha256").Update(file)
+	return file, err
+}
+
 // Delete removes a file from the DB and the file system
 func (f *File) Delete() (err error) {
 	s := db.NewSession()
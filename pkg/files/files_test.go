@@ -17,6 +17,7 @@
 package files
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"testing"
@@ -82,6 +83,48 @@ func TestCreate(t *testing.T) {
 	})
 }
 
+func TestCreateDeduplicated(t *testing.T) {
+	t.Run("Same content", func(t *testing.T) {
+		initFixtures(t)
+		ta := &testauth{id: 1}
+		first, err := CreateDeduplicated(bytes.NewReader([]byte("image")), "first.png", 5, ta)
+		require.NoError(t, err)
+		assert.NotEmpty(t, first.Sha256)
+
+		second, err := CreateDeduplicated(bytes.NewReader([]byte("image")), "second.png", 5, ta)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, "first.png", second.Name)
+	})
+	t.Run("Different content", func(t *testing.T) {
+		initFixtures(t)
+		ta := &testauth{id: 1}
+		first, err := CreateDeduplicated(bytes.NewReader([]byte("image")), "first.png", 5, ta)
+		require.NoError(t, err)
+
+		second, err := CreateDeduplicated(bytes.NewReader([]byte("other")), "second.png", 5, ta)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, second.ID)
+	})
+	t.Run("Not deduplicated with normal files", func(t *testing.T) {
+		initFixtures(t)
+		ta := &testauth{id: 1}
+		first, err := Create(bytes.NewReader([]byte("image")), "first.png", 5, ta)
+		require.NoError(t, err)
+
+		second, err := CreateDeduplicated(bytes.NewReader([]byte("image")), "second.png", 5, ta)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, second.ID)
+	})
+	t.Run("Too Large", func(t *testing.T) {
+		initFixtures(t)
+		ta := &testauth{id: 1}
+		_, err := CreateDeduplicated(bytes.NewReader([]byte("image")), "first.png", 99999999999, ta)
+		require.Error(t, err)
+		assert.True(t, IsErrFileIsTooLarge(err))
+	})
+}
+
 func TestFile_Delete(t *testing.T) {
 	t.Run("Normal", func(t *testing.T) {
 		initFixtures(t)
@@ -30,6 +30,9 @@ import (
 	"code.vikunja.io/api/pkg/modules/auth/openid"
 	"code.vikunja.io/api/pkg/modules/keyvalue"
 	migrationHandler "code.vikunja.io/api/pkg/modules/migration/handler"
+	"code.vikunja.io/api/pkg/modules/plugins"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/outbound"
 	"code.vikunja.io/api/pkg/red"
 	"code.vikunja.io/api/pkg/user"
 )
@@ -77,6 +80,18 @@ func FullInitWithoutAsync() {
 	// Init Typesense
 	models.InitTypesense()
 
+	// Load plugins
+	err := plugins.Init()
+	if err != nil {
+		log.Fatalf("Could not load plugins: %s", err)
+	}
+
+	// Check the outbound http configuration
+	err = outbound.Init()
+	if err != nil {
+		log.Fatalf("Invalid outbound configuration: %s", err)
+	}
+
 	// Start the mail daemon
 	mail.StartMailDaemon()
 }
@@ -90,10 +105,23 @@ func FullInit() {
 	cron.Init()
 	models.RegisterReminderCron()
 	models.RegisterOverdueReminderCron()
+	models.RegisterDigestCron()
+	models.RegisterPriorityEscalationCron()
+	models.RegisterSLACheckCron()
+	models.RegisterAutomationDueCron()
+	models.RegisterScheduledAutomationCron()
+	models.RegisterSavedFilterSubscriptionCron()
+	models.RegisterDoneTasksRetentionCron()
 	user.RegisterTokenCleanupCron()
 	user.RegisterDeletionNotificationCron()
+	user.RegisterQueuedNotificationsCron()
 	models.RegisterUserDeletionCron()
 	models.RegisterOldExportCleanupCron()
+	models.RegisterInlineAttachmentCleanupCron()
+	models.RegisterWebhookDeliveryCron()
+	models.RegisterProjectEventRetentionCron()
+	models.RegisterIdempotencyKeyCleanupCron()
+	notifications.RegisterNotificationRetentionCron()
 	openid.CleanupSavedOpenIDProviders()
 	openid.RegisterEmptyOpenIDTeamCleanupCron()
 
@@ -17,11 +17,13 @@
 package integrations
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
 	"code.vikunja.io/api/pkg/modules/auth"
 	"code.vikunja.io/api/pkg/routes"
 	"code.vikunja.io/api/pkg/user"
@@ -112,3 +114,19 @@ func TestAPIToken(t *testing.T) {
 		require.NoError(t, h(c))
 	})
 }
+
+func TestAPITokenRoutes(t *testing.T) {
+	e, err := setupTestEnv()
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/routes", nil)
+	res := httptest.NewRecorder()
+	c := e.NewContext(req, res)
+	require.NoError(t, models.GetAvailableAPIRoutesForToken(c))
+
+	tokenRoutes := map[string]*models.APITokenRoute{}
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &tokenRoutes))
+	for _, group := range []string{"projects", "tasks", "projects_buckets"} {
+		require.Contains(t, tokenRoutes, group)
+		assert.NotNil(t, tokenRoutes[group].Update, group)
+	}
+}
@@ -0,0 +1,65 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/routes"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency(t *testing.T) {
+	testRequest := func(t *testing.T, path string) {
+		c, rec := bootstrapTestRequest(t, http.MethodPut, `{"title":"Lorem"}`, nil, nil)
+		c.SetPath(path)
+		c.Request().Header.Set("Idempotency-Key", "idempotency-test")
+		addUserTokenToContext(t, &testuser1, c)
+
+		h := routes.Idempotency()(func(c echo.Context) error {
+			return c.JSON(http.StatusCreated, map[string]string{"token": "secret"})
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	}
+
+	t.Run("stores the response", func(t *testing.T) {
+		testRequest(t, "/api/v1/projects/:project/tasks")
+		db.AssertExists(t, "idempotency_keys", map[string]interface{}{
+			"client_key":      "idempotency-test",
+			"owner":           "user_1",
+			"response_status": http.StatusCreated,
+		}, false)
+	})
+	t.Run("does not store responses with secrets", func(t *testing.T) {
+		for _, path := range []string{
+			"/api/v1/tokens",
+			"/api/v1/user/token",
+			"/api/v1/user/settings/totp/enroll",
+		} {
+			testRequest(t, path)
+			db.AssertMissing(t, "idempotency_keys", map[string]interface{}{
+				"client_key": "idempotency-test",
+			})
+		}
+	})
+}
@@ -0,0 +1,41 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	apiv1 "code.vikunja.io/api/pkg/routes/api/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboundWebhook(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		rec, err := newTestRequest(t, http.MethodPost, apiv1.HandleInboundWebhook, `{"title":"Disk almost full"}`, nil, map[string]string{"token": "inboundwebhooktoken1"})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"identifier":`)
+		assert.Contains(t, rec.Body.String(), `"index":`)
+		// The sender is not authenticated and must not see anything about the creator of the webhook
+		assert.NotContains(t, rec.Body.String(), "user1@example.com")
+		assert.NotContains(t, rec.Body.String(), `"created_by"`)
+		assert.NotContains(t, rec.Body.String(), `"title"`)
+	})
+}
@@ -0,0 +1,56 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type bucketTemplateBucket20261014101527 struct {
+	Title        string `json:"title"`
+	Limit        int64  `json:"limit"`
+	IsDoneBucket bool   `json:"is_done_bucket"`
+}
+
+type bucketTemplates20261014101527 struct {
+	ID      int64                                 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	Title   string                                `xorm:"varchar(250) not null" json:"title"`
+	Buckets []*bucketTemplateBucket20261014101527 `xorm:"JSON null" json:"buckets"`
+	OwnerID int64                                 `xorm:"bigint not null INDEX" json:"-"`
+	Created time.Time                             `xorm:"created not null" json:"created"`
+	Updated time.Time                             `xorm:"updated not null" json:"updated"`
+}
+
+func (bucketTemplates20261014101527) TableName() string {
+	return "bucket_templates"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014101527",
+		Description: "Create bucket templates table",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(bucketTemplates20261014101527{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
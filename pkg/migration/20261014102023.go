@@ -0,0 +1,49 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type savedFilterBucketConfiguration20261014102023 struct {
+	Title  string `json:"title"`
+	Filter string `json:"filter"`
+}
+
+type savedFilters20261014102023 struct {
+	BucketConfigurationMode int                                             `xorm:"not null default 0" json:"bucket_configuration_mode"`
+	BucketConfiguration     []*savedFilterBucketConfiguration20261014102023 `xorm:"JSON null" json:"bucket_configuration"`
+}
+
+func (savedFilters20261014102023) TableName() string {
+	return "saved_filters"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014102023",
+		Description: "Add kanban bucket configuration to saved filters",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(savedFilters20261014102023{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,101 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type taskBucketTransitions20261014103141 struct {
+	ID           int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID       int64     `xorm:"bigint not null INDEX"`
+	ProjectID    int64     `xorm:"bigint not null INDEX"`
+	FromBucketID int64     `xorm:"bigint not null default 0"`
+	ToBucketID   int64     `xorm:"bigint not null default 0"`
+	Created      time.Time `xorm:"not null"`
+}
+
+func (taskBucketTransitions20261014103141) TableName() string {
+	return "task_bucket_transitions"
+}
+
+type tasks20261014103141 struct {
+	ID        int64     `xorm:"bigint autoincr not null unique pk"`
+	ProjectID int64     `xorm:"bigint INDEX not null"`
+	BucketID  int64     `xorm:"bigint null"`
+	Created   time.Time `xorm:"created not null"`
+}
+
+func (tasks20261014103141) TableName() string {
+	return "tasks"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014103141",
+		Description: "Add task bucket transitions table",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(taskBucketTransitions20261014103141{})
+			if err != nil {
+				return err
+			}
+
+			// All existing tasks are recorded as having been created in the bucket they are currently in.
+			// The tasks are handled in batches to keep the memory usage low on big instances.
+			const batchSize = 1000
+			var lastID int64
+			for {
+				tasks := []*tasks20261014103141{}
+				err = tx.
+					Where("bucket_id != 0 AND id > ?", lastID).
+					OrderBy("id asc").
+					Limit(batchSize).
+					Find(&tasks)
+				if err != nil {
+					return err
+				}
+
+				if len(tasks) == 0 {
+					return nil
+				}
+
+				transitions := make([]*taskBucketTransitions20261014103141, 0, len(tasks))
+				for _, t := range tasks {
+					transitions = append(transitions, &taskBucketTransitions20261014103141{
+						TaskID:     t.ID,
+						ProjectID:  t.ProjectID,
+						ToBucketID: t.BucketID,
+						Created:    t.Created,
+					})
+				}
+
+				_, err = tx.Insert(transitions)
+				if err != nil {
+					return err
+				}
+
+				lastID = tasks[len(tasks)-1].ID
+			}
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,87 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type tasks20261014104108 struct {
+	ID           int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	ParentTaskID int64 `xorm:"bigint null INDEX" json:"parent_task_id"`
+}
+
+func (tasks20261014104108) TableName() string {
+	return "tasks"
+}
+
+type taskRelations20261014104108 struct {
+	ID           int64  `xorm:"bigint autoincr not null unique pk"`
+	TaskID       int64  `xorm:"bigint not null"`
+	OtherTaskID  int64  `xorm:"bigint not null"`
+	RelationKind string `xorm:"varchar(50) not null"`
+}
+
+func (taskRelations20261014104108) TableName() string {
+	return "task_relations"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014104108",
+		Description: "Add parent task id to tasks",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(tasks20261014104108{})
+			if err != nil {
+				return err
+			}
+
+			// Use the existing parenttask relations as parents. If a task has more than one parent,
+			// the oldest relation wins.
+			relations := []*taskRelations20261014104108{}
+			err = tx.
+				Where("relation_kind = ?", "parenttask").
+				OrderBy("id asc").
+				Find(&relations)
+			if err != nil {
+				return err
+			}
+
+			seen := make(map[int64]bool)
+			for _, rel := range relations {
+				if seen[rel.TaskID] {
+					continue
+				}
+				seen[rel.TaskID] = true
+
+				_, err = tx.
+					Where("id = ?", rel.TaskID).
+					Cols("parent_task_id").
+					Update(&tasks20261014104108{ParentTaskID: rel.OtherTaskID})
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
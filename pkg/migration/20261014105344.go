@@ -0,0 +1,67 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type customFields20261014105344 struct {
+	ID          int64     `xorm:"bigint autoincr not null unique pk"`
+	ProjectID   int64     `xorm:"bigint not null INDEX"`
+	Title       string    `xorm:"text not null"`
+	Type        string    `xorm:"varchar(50) not null"`
+	Options     []string  `xorm:"JSON null"`
+	Position    float64   `xorm:"double null"`
+	CreatedByID int64     `xorm:"bigint not null"`
+	Created     time.Time `xorm:"created not null"`
+	Updated     time.Time `xorm:"updated not null"`
+}
+
+func (customFields20261014105344) TableName() string {
+	return "custom_fields"
+}
+
+type taskCustomFieldValues20261014105344 struct {
+	ID            int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID        int64     `xorm:"bigint not null INDEX"`
+	CustomFieldID int64     `xorm:"bigint not null INDEX"`
+	StringValue   string    `xorm:"text null"`
+	NumberValue   float64   `xorm:"double null"`
+	DateValue     time.Time `xorm:"DATETIME null"`
+	BoolValue     bool      `xorm:"null"`
+}
+
+func (taskCustomFieldValues20261014105344) TableName() string {
+	return "task_custom_field_values"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014105344",
+		Description: "Add custom fields",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(customFields20261014105344{}, taskCustomFieldValues20261014105344{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,126 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type taskChecklistItems20261014105947 struct {
+	ID          int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID      int64     `xorm:"bigint not null INDEX"`
+	Title       string    `xorm:"text not null"`
+	Done        bool      `xorm:"INDEX null"`
+	DoneAt      time.Time `xorm:"DATETIME null 'done_at'"`
+	Position    float64   `xorm:"double null"`
+	CreatedByID int64     `xorm:"bigint not null"`
+	Created     time.Time `xorm:"created not null"`
+	Updated     time.Time `xorm:"updated not null"`
+}
+
+func (taskChecklistItems20261014105947) TableName() string {
+	return "task_checklist_items"
+}
+
+type tasks20261014105947 struct {
+	ID          int64  `xorm:"bigint autoincr not null unique pk"`
+	Description string `xorm:"longtext null"`
+	CreatedByID int64  `xorm:"bigint not null"`
+}
+
+func (tasks20261014105947) TableName() string {
+	return "tasks"
+}
+
+var (
+	taskList20261014105947 = regexp.MustCompile(`(?s)<ul[^>]*data-type="taskList"[^>]*>(.*?)</ul>`)
+	taskItem20261014105947 = regexp.MustCompile(`(?s)<li([^>]*data-type="taskItem"[^>]*)>(.*?)</li>`)
+	htmlTag20261014105947  = regexp.MustCompile(`<[^>]*>`)
+)
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014105947",
+		Description: "Add task checklists and convert checklists from task descriptions",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(taskChecklistItems20261014105947{})
+			if err != nil {
+				return err
+			}
+
+			tasks := []*tasks20261014105947{}
+			err = tx.Where("description LIKE ?", `%data-type="taskItem"%`).Find(&tasks)
+			if err != nil {
+				return err
+			}
+
+			for _, task := range tasks {
+				var position int64
+				for _, list := range taskList20261014105947.FindAllStringSubmatch(task.Description, -1) {
+					for _, item := range taskItem20261014105947.FindAllStringSubmatch(list[1], -1) {
+						title := strings.TrimSpace(html.UnescapeString(htmlTag20261014105947.ReplaceAllString(item[2], "")))
+						if title == "" {
+							continue
+						}
+
+						position++
+						checklistItem := &taskChecklistItems20261014105947{
+							TaskID:      task.ID,
+							Title:       title,
+							Done:        strings.Contains(item[1], `data-checked="true"`),
+							Position:    float64(position) * math.Pow(2, 16),
+							CreatedByID: task.CreatedByID,
+						}
+						if checklistItem.Done {
+							checklistItem.DoneAt = time.Now()
+						}
+						_, err = tx.Insert(checklistItem)
+						if err != nil {
+							return err
+						}
+					}
+				}
+
+				if position == 0 {
+					continue
+				}
+
+				task.Description = strings.TrimSpace(taskList20261014105947.ReplaceAllString(task.Description, ""))
+				_, err = tx.
+					Where("id = ?", task.ID).
+					Cols("description").
+					NoAutoTime().
+					Update(task)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
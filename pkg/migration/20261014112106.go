@@ -0,0 +1,52 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type tasks20261014112106 struct {
+	DescriptionFormat string `xorm:"varchar(20) null" json:"description_format"`
+}
+
+func (tasks20261014112106) TableName() string {
+	return "tasks"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014112106",
+		Description: "Add description format to tasks",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(tasks20261014112106{})
+			if err != nil {
+				return err
+			}
+
+			// All existing descriptions were created as html
+			_, err = tx.Where("description_format IS NULL").
+				Cols("description_format").
+				Update(&tasks20261014112106{DescriptionFormat: "html"})
+			return err
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,48 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type priorityEscalationRule20261014113127 struct {
+	OverdueFor int64 `json:"overdue_for"`
+	Priority   int64 `json:"priority"`
+}
+
+type projects20261014113127 struct {
+	PriorityEscalation []*priorityEscalationRule20261014113127 `xorm:"JSON null" json:"priority_escalation"`
+}
+
+func (projects20261014113127) TableName() string {
+	return "projects"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014113127",
+		Description: "Add priority escalation rules to projects",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projects20261014113127{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
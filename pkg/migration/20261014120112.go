@@ -0,0 +1,51 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type files20261014120112 struct {
+	Sha256 string `xorm:"varchar(64) null index"`
+}
+
+func (files20261014120112) TableName() string {
+	return "files"
+}
+
+type taskAttachments20261014120112 struct {
+	IsInline bool `xorm:"not null default false"`
+}
+
+func (taskAttachments20261014120112) TableName() string {
+	return "task_attachments"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014120112",
+		Description: "Add content hashes to files and mark inline task attachments",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(files20261014120112{}, taskAttachments20261014120112{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,65 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type slaRule20261014121310 struct {
+	Title    string `json:"title"`
+	BucketID int64  `json:"bucket_id"`
+	MaxAge   int64  `json:"max_age"`
+}
+
+type projects20261014121310 struct {
+	SLARules []*slaRule20261014121310 `xorm:"'sla_rules' JSON null" json:"sla_rules"`
+}
+
+func (projects20261014121310) TableName() string {
+	return "projects"
+}
+
+type taskSLABreaches20261014121310 struct {
+	ID        int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID    int64     `xorm:"bigint not null INDEX"`
+	ProjectID int64     `xorm:"bigint not null INDEX"`
+	BucketID  int64     `xorm:"bigint not null default 0"`
+	MaxAge    int64     `xorm:"bigint not null"`
+	Since     time.Time `xorm:"not null"`
+	Created   time.Time `xorm:"created not null"`
+}
+
+func (taskSLABreaches20261014121310) TableName() string {
+	return "task_sla_breaches"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014121310",
+		Description: "Add sla rules to projects",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projects20261014121310{}, taskSLABreaches20261014121310{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
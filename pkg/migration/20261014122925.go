@@ -0,0 +1,55 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projectTemplates20261014122925 struct {
+	ID           int64                    `xorm:"bigint autoincr not null unique pk"`
+	Title        string                   `xorm:"varchar(250) not null"`
+	Description  string                   `xorm:"longtext null"`
+	HexColor     string                   `xorm:"varchar(6) null"`
+	Buckets      []map[string]interface{} `xorm:"JSON null"`
+	Labels       []map[string]interface{} `xorm:"JSON null"`
+	CustomFields []map[string]interface{} `xorm:"JSON null"`
+	Tasks        []map[string]interface{} `xorm:"JSON null"`
+	OwnerID      int64                    `xorm:"bigint not null INDEX"`
+	Created      time.Time                `xorm:"created not null"`
+	Updated      time.Time                `xorm:"updated not null"`
+}
+
+func (projectTemplates20261014122925) TableName() string {
+	return "project_templates"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014122925",
+		Description: "Add project templates table",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projectTemplates20261014122925{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,60 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projectStatuses20261014123635 struct {
+	ID          int64     `xorm:"bigint autoincr not null unique pk"`
+	ProjectID   int64     `xorm:"bigint not null INDEX"`
+	Title       string    `xorm:"varchar(250) not null"`
+	HexColor    string    `xorm:"varchar(6) null"`
+	Position    float64   `xorm:"double null"`
+	CreatedByID int64     `xorm:"bigint not null"`
+	Created     time.Time `xorm:"created not null"`
+	Updated     time.Time `xorm:"updated not null"`
+}
+
+func (projectStatuses20261014123635) TableName() string {
+	return "project_statuses"
+}
+
+type tasks20261014123635 struct {
+	StatusID int64 `xorm:"bigint null INDEX"`
+}
+
+func (tasks20261014123635) TableName() string {
+	return "tasks"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014123635",
+		Description: "Add project statuses",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projectStatuses20261014123635{}, tasks20261014123635{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,72 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projectRoles20261014124636 struct {
+	ID                int64     `xorm:"bigint autoincr not null unique pk"`
+	ProjectID         int64     `xorm:"bigint not null INDEX"`
+	Title             string    `xorm:"varchar(250) not null"`
+	Description       string    `xorm:"longtext null"`
+	CreateTasks       bool      `xorm:"bool not null default false"`
+	UpdateTasks       bool      `xorm:"bool not null default false"`
+	DeleteTasks       bool      `xorm:"bool not null default false"`
+	Comment           bool      `xorm:"bool not null default false"`
+	ManageAttachments bool      `xorm:"bool not null default false"`
+	CreatedByID       int64     `xorm:"bigint not null"`
+	Created           time.Time `xorm:"created not null"`
+	Updated           time.Time `xorm:"updated not null"`
+}
+
+func (projectRoles20261014124636) TableName() string {
+	return "project_roles"
+}
+
+type usersProjects20261014124636 struct {
+	RoleID int64 `xorm:"bigint null default 0"`
+}
+
+func (usersProjects20261014124636) TableName() string {
+	return "users_projects"
+}
+
+type teamProjects20261014124636 struct {
+	RoleID int64 `xorm:"bigint null default 0"`
+}
+
+func (teamProjects20261014124636) TableName() string {
+	return "team_projects"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014124636",
+		Description: "Add project roles",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projectRoles20261014124636{}, usersProjects20261014124636{}, teamProjects20261014124636{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
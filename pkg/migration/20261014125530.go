@@ -0,0 +1,60 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projectArchiveOptions20261014125530 struct {
+	ArchiveChildProjects    bool    `json:"archive_child_projects"`
+	HoldOpenTasks           bool    `json:"hold_open_tasks"`
+	PauseReminders          bool    `json:"pause_reminders"`
+	PauseRecurringTasks     bool    `json:"pause_recurring_tasks"`
+	SuppressNotifications   bool    `json:"suppress_notifications"`
+	ArchivedChildProjectIDs []int64 `json:"archived_child_project_ids"`
+}
+
+type projects20261014125530 struct {
+	ArchiveOptions *projectArchiveOptions20261014125530 `xorm:"JSON null"`
+}
+
+func (projects20261014125530) TableName() string {
+	return "projects"
+}
+
+type tasks20261014125530 struct {
+	OnHold bool `xorm:"not null default false"`
+}
+
+func (tasks20261014125530) TableName() string {
+	return "tasks"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014125530",
+		Description: "Add archive options to projects and on hold state to tasks",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projects20261014125530{}, tasks20261014125530{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
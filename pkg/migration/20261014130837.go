@@ -0,0 +1,50 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projectTaskDefaults20261014130837 struct {
+	LabelIDs              []int64 `json:"label_ids"`
+	Priority              int64   `json:"priority"`
+	AssigneeID            int64   `json:"assignee_id"`
+	DueDateReminderOffset *int64  `json:"due_date_reminder_offset"`
+}
+
+type projects20261014130837 struct {
+	TaskDefaults *projectTaskDefaults20261014130837 `xorm:"JSON null"`
+}
+
+func (projects20261014130837) TableName() string {
+	return "projects"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014130837",
+		Description: "Add task defaults to projects",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projects20261014130837{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
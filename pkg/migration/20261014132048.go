@@ -0,0 +1,63 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type savedFilterUsers20261014132048 struct {
+	ID       int64     `xorm:"bigint autoincr not null unique pk"`
+	UserID   int64     `xorm:"bigint not null INDEX"`
+	FilterID int64     `xorm:"bigint not null INDEX"`
+	Right    int       `xorm:"bigint INDEX not null default 0"`
+	Created  time.Time `xorm:"created not null"`
+	Updated  time.Time `xorm:"updated not null"`
+}
+
+func (savedFilterUsers20261014132048) TableName() string {
+	return "saved_filter_users"
+}
+
+type savedFilterTeams20261014132048 struct {
+	ID       int64     `xorm:"bigint autoincr not null unique pk"`
+	TeamID   int64     `xorm:"bigint not null INDEX"`
+	FilterID int64     `xorm:"bigint not null INDEX"`
+	Right    int       `xorm:"bigint INDEX not null default 0"`
+	Created  time.Time `xorm:"created not null"`
+	Updated  time.Time `xorm:"updated not null"`
+}
+
+func (savedFilterTeams20261014132048) TableName() string {
+	return "saved_filter_teams"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014132048",
+		Description: "Add saved filter sharing",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(savedFilterUsers20261014132048{}, savedFilterTeams20261014132048{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(savedFilterUsers20261014132048{}, savedFilterTeams20261014132048{})
+		},
+	})
+}
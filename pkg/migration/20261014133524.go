@@ -0,0 +1,45 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type savedFilterTaskMatches20261014133524 struct {
+	ID             int64 `xorm:"autoincr not null unique pk"`
+	SubscriptionID int64 `xorm:"bigint not null INDEX"`
+	TaskID         int64 `xorm:"bigint not null INDEX"`
+}
+
+func (savedFilterTaskMatches20261014133524) TableName() string {
+	return "saved_filter_task_matches"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014133524",
+		Description: "Add saved filter task matches table for saved filter subscriptions",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(savedFilterTaskMatches20261014133524{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(savedFilterTaskMatches20261014133524{})
+		},
+	})
+}
@@ -0,0 +1,81 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/utils"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+type tasks20261014134750 struct {
+	ID              int64  `xorm:"bigint autoincr not null unique pk"`
+	Title           string `xorm:"TEXT not null"`
+	NormalizedTitle string `xorm:"TEXT null"`
+}
+
+func (tasks20261014134750) TableName() string {
+	return "tasks"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014134750",
+		Description: "Add normalized task title for typo-tolerant search",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(tasks20261014134750{})
+			if err != nil {
+				return err
+			}
+
+			tasks := []*tasks20261014134750{}
+			err = tx.Cols("id", "title").Find(&tasks)
+			if err != nil {
+				return err
+			}
+
+			for _, task := range tasks {
+				task.NormalizedTitle = utils.NormalizeSearchText(task.Title)
+				_, err = tx.Where("id = ?", task.ID).Cols("normalized_title").Update(task)
+				if err != nil {
+					return err
+				}
+			}
+
+			if tx.Dialect().URI().DBType == schemas.POSTGRES {
+				// The extension is optional, the search falls back to the normalized title if it is not available.
+				_, err = tx.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm")
+				if err != nil {
+					log.Warningf("Could not enable the pg_trgm extension, fuzzy search will use the normalized title instead: %s", err)
+					return nil
+				}
+				_, err = tx.Exec("CREATE INDEX IF NOT EXISTS IDX_tasks_title_trgm ON tasks USING GIN (title gin_trgm_ops)")
+				if err != nil {
+					log.Warningf("Could not create the trigram index on task titles: %s", err)
+				}
+			}
+
+			return nil
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,48 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type taskViews20261014135232 struct {
+	ID     int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID int64     `xorm:"bigint INDEX not null"`
+	UserID int64     `xorm:"bigint INDEX not null"`
+	Viewed time.Time `xorm:"DATETIME INDEX not null"`
+}
+
+func (taskViews20261014135232) TableName() string {
+	return "task_views"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014135232",
+		Description: "Add task views table",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(taskViews20261014135232{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(taskViews20261014135232{})
+		},
+	})
+}
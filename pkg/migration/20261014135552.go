@@ -0,0 +1,53 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projectLabelRules20261014135552 struct {
+	ID          int64     `xorm:"bigint autoincr not null unique pk"`
+	ProjectID   int64     `xorm:"bigint not null INDEX"`
+	Title       string    `xorm:"varchar(250) null"`
+	Filter      string    `xorm:"text not null"`
+	LabelIDs    []int64   `xorm:"'label_ids' JSON null"`
+	Priority    int64     `xorm:"bigint null"`
+	CreatedByID int64     `xorm:"bigint not null"`
+	Created     time.Time `xorm:"created not null"`
+	Updated     time.Time `xorm:"updated not null"`
+}
+
+func (projectLabelRules20261014135552) TableName() string {
+	return "project_label_rules"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014135552",
+		Description: "Add project label rules table",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projectLabelRules20261014135552{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(projectLabelRules20261014135552{})
+		},
+	})
+}
@@ -0,0 +1,51 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type savedFilterViewSettings20261014135914 struct {
+	DefaultView                string   `json:"default_view"`
+	GanttStartDateField        string   `json:"gantt_start_date_field"`
+	GanttEndDateField          string   `json:"gantt_end_date_field"`
+	GanttShowTasksWithoutDates bool     `json:"gantt_show_tasks_without_dates"`
+	TableColumns               []string `json:"table_columns"`
+}
+
+type savedFilters20261014135914 struct {
+	ViewSettings *savedFilterViewSettings20261014135914 `xorm:"JSON null"`
+}
+
+func (savedFilters20261014135914) TableName() string {
+	return "saved_filters"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014135914",
+		Description: "Add view settings to saved filters",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(savedFilters20261014135914{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
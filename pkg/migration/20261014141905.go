@@ -0,0 +1,50 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type pushSubscriptions20261014141905 struct {
+	ID           int64                  `xorm:"bigint autoincr not null unique pk"`
+	NotifiableID int64                  `xorm:"bigint not null index"`
+	Endpoint     string                 `xorm:"text not null"`
+	Keys         map[string]interface{} `xorm:"'subscription_keys' json not null"`
+	Name         string                 `xorm:"varchar(250) null"`
+	Created      time.Time              `xorm:"created not null"`
+}
+
+func (pushSubscriptions20261014141905) TableName() string {
+	return "push_subscriptions"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014141905",
+		Description: "Add push subscriptions table",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(pushSubscriptions20261014141905{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(pushSubscriptions20261014141905{})
+		},
+	})
+}
@@ -0,0 +1,56 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type taskMentions20261014144142 struct {
+	ID      int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID  int64     `xorm:"bigint not null unique(task_user)"`
+	UserID  int64     `xorm:"bigint not null unique(task_user)"`
+	Created time.Time `xorm:"created not null"`
+}
+
+func (taskMentions20261014144142) TableName() string {
+	return "task_mentions"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014144142",
+		Description: "Add task mentions table",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(taskMentions20261014144142{})
+			if err != nil {
+				return err
+			}
+
+			// Users who were already notified about a mention in a task should not be notified again.
+			_, err = tx.Exec("INSERT INTO task_mentions (task_id, user_id, created) "+
+				"SELECT DISTINCT subject_id, notifiable_id, ? FROM notifications WHERE name = 'task.mentioned'", time.Now())
+			return err
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(taskMentions20261014144142{})
+		},
+	})
+}
@@ -0,0 +1,49 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type taskReminders20261014144527 struct {
+	EscalateAfter          int64     `xorm:"bigint null"`
+	EscalateToProjectOwner bool      `xorm:"null"`
+	EscalateToUserID       int64     `xorm:"bigint null"`
+	EscalateToTeamID       int64     `xorm:"bigint null"`
+	EscalatesAt            time.Time `xorm:"DATETIME null INDEX 'escalates_at'"`
+}
+
+func (taskReminders20261014144527) TableName() string {
+	return "task_reminders"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014144527",
+		Description: "Add escalation settings to task reminders",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(taskReminders20261014144527{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,65 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type users20261014150158 struct {
+	QuietHoursStart        string `xorm:"varchar(5) null"`
+	QuietHoursEnd          string `xorm:"varchar(5) null"`
+	QuietHoursExemptUrgent bool   `xorm:"bool null"`
+}
+
+func (users20261014150158) TableName() string {
+	return "users"
+}
+
+type queuedNotifications20261014150158 struct {
+	ID               int64       `xorm:"bigint autoincr not null unique pk"`
+	NotifiableID     int64       `xorm:"bigint not null INDEX"`
+	NotificationName string      `xorm:"varchar(250) not null 'name'"`
+	Mail             interface{} `xorm:"json null"`
+	Push             interface{} `xorm:"json null"`
+	DeliverAt        time.Time   `xorm:"datetime not null INDEX"`
+	Created          time.Time   `xorm:"created not null"`
+}
+
+func (queuedNotifications20261014150158) TableName() string {
+	return "queued_notifications"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014150158",
+		Description: "Add quiet hours to users and queued notifications",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(users20261014150158{})
+			if err != nil {
+				return err
+			}
+			return tx.Sync2(queuedNotifications20261014150158{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(queuedNotifications20261014150158{})
+		},
+	})
+}
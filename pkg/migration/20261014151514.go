@@ -0,0 +1,57 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type webhookDeliveries20261014151514 struct {
+	ID                 int64     `xorm:"bigint autoincr not null unique pk"`
+	WebhookID          int64     `xorm:"bigint not null index"`
+	EventName          string    `xorm:"varchar(250) not null"`
+	Payload            string    `xorm:"longtext not null"`
+	Status             string    `xorm:"varchar(20) not null index"`
+	Attempts           int64     `xorm:"bigint not null default 0"`
+	ResponseStatusCode int       `xorm:"int not null default 0"`
+	LastError          string    `xorm:"text null"`
+	RedeliveryOf       int64     `xorm:"bigint null"`
+	NextAttemptAt      time.Time `xorm:"DATETIME null index"`
+	DeliveredAt        time.Time `xorm:"DATETIME null"`
+	Created            time.Time `xorm:"created not null"`
+	Updated            time.Time `xorm:"updated not null"`
+}
+
+func (webhookDeliveries20261014151514) TableName() string {
+	return "webhook_deliveries"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014151514",
+		Description: "Add webhook deliveries",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(webhookDeliveries20261014151514{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(webhookDeliveries20261014151514{})
+		},
+	})
+}
@@ -0,0 +1,56 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projectScheduledAutomations20261014153359 struct {
+	ID          int64     `xorm:"bigint autoincr not null unique pk"`
+	ProjectID   int64     `xorm:"bigint not null index"`
+	Title       string    `xorm:"varchar(250) not null"`
+	Enabled     bool      `xorm:"bool not null default true"`
+	Schedule    string    `xorm:"varchar(100) not null"`
+	Action      string    `xorm:"varchar(50) not null"`
+	ActionValue int64     `xorm:"bigint not null"`
+	NextRunAt   time.Time `xorm:"DATETIME null index"`
+	LastRunAt   time.Time `xorm:"DATETIME null"`
+	CreatedByID int64     `xorm:"bigint not null"`
+	Created     time.Time `xorm:"created not null"`
+	Updated     time.Time `xorm:"updated not null"`
+}
+
+func (projectScheduledAutomations20261014153359) TableName() string {
+	return "project_scheduled_automations"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014153359",
+		Description: "Add project scheduled automations",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projectScheduledAutomations20261014153359{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(projectScheduledAutomations20261014153359{})
+		},
+	})
+}
@@ -0,0 +1,55 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type idempotencyKeys20261014162023 struct {
+	ID                  int64     `xorm:"bigint autoincr not null unique pk"`
+	ClientKey           string    `xorm:"varchar(250) not null unique(owner_client_key)"`
+	Owner               string    `xorm:"varchar(250) not null unique(owner_client_key)"`
+	Method              string    `xorm:"varchar(10) not null"`
+	Path                string    `xorm:"text not null"`
+	RequestHash         string    `xorm:"varchar(64) not null"`
+	ResponseStatus      int       `xorm:"int not null default 0"`
+	ResponseContentType string    `xorm:"varchar(250) null"`
+	ResponseBody        []byte    `xorm:"longblob null"`
+	Created             time.Time `xorm:"created not null index"`
+	Updated             time.Time `xorm:"updated not null"`
+}
+
+func (idempotencyKeys20261014162023) TableName() string {
+	return "idempotency_keys"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261014162023",
+		Description: "Add idempotency keys table",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(idempotencyKeys20261014162023{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(idempotencyKeys20261014162023{})
+		},
+	})
+}
@@ -60,7 +60,11 @@ func getRouteGroupName(path string) string {
 	case "projects_tasks":
 		fallthrough
 	case "tasks_all":
+		fallthrough
+	case "tasks_changes":
 		return "tasks"
+	case "projects_changes":
+		return "projects"
 	default:
 		return finalName
 	}
@@ -188,6 +192,11 @@ func CanDoAPIRoute(c echo.Context, token *APIToken) (can bool) {
 		route = "read_all"
 	}
 
+	// The changes endpoints are not web handlers but are covered by the read_all permission of their group.
+	if (path == "/api/v1/tasks/changes" || path == "/api/v1/projects/changes") && c.Request().Method == http.MethodGet {
+		route = "read_all"
+	}
+
 	for _, p := range group {
 		if p == route {
 			return true
@@ -25,6 +25,7 @@ import (
 	"xorm.io/builder"
 
 	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/api/pkg/utils"
 
 	"code.vikunja.io/web"
@@ -47,6 +48,12 @@ type APIToken struct {
 	TokenLastEight string `xorm:"not null index varchar(8)" json:"-"`
 	// The permissions this token has. Possible values are available via the /routes endpoint and consist of the keys of the list from that endpoint. For example, if the token should be able to read all tasks as well as update existing tasks, you should add `{"tasks":["read_all","update"]}`.
 	Permissions APIPermissions `xorm:"json not null" json:"permissions" valid:"required"`
+	// The ids of the projects this token is restricted to. The token can only access these projects, their tasks and
+	// everything else belonging to them. If empty, the token can access all projects of the user.
+	ProjectIDs []int64 `xorm:"'project_ids' json null" json:"project_ids"`
+	// The max number of requests this token can do in the configured rate limit period. Can only be set by
+	// administrators of the instance. If 0, the configured limit for all tokens is used.
+	RateLimit int64 `xorm:"bigint null" json:"rate_limit"`
 	// The date when this key expires.
 	ExpiresAt time.Time `xorm:"not null" json:"expires_at" valid:"required"`
 
@@ -101,11 +108,22 @@ func (t *APIToken) Create(s *xorm.Session, a web.Auth) (err error) {
 	t.TokenLastEight = t.Token[len(t.Token)-8:]
 
 	t.OwnerID = a.GetID()
+	t.RateLimit = 0
 
 	if err := PermissionsAreValid(t.Permissions); err != nil {
 		return err
 	}
 
+	for _, projectID := range t.ProjectIDs {
+		can, _, err := (&Project{ID: projectID}).CanRead(s, a)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return ErrGenericForbidden{}
+		}
+	}
+
 	_, err = s.Insert(t)
 	return err
 }
@@ -170,6 +188,65 @@ func (t *APIToken) Delete(s *xorm.Session, a web.Auth) (err error) {
 	return err
 }
 
+// SetAPITokenRateLimit sets the rate limit of a token. 0 resets it to the configured limit for all tokens.
+func SetAPITokenRateLimit(s *xorm.Session, id int64, limit int64) (err error) {
+	token, err := GetAPITokenByID(s, id)
+	if err != nil {
+		return err
+	}
+	if token.ID == 0 {
+		return &ErrAPITokenDoesNotExist{TokenID: id}
+	}
+
+	token.RateLimit = limit
+	_, err = s.ID(id).Cols("rate_limit").Update(token)
+	return err
+}
+
+// getAPITokenProjectIDs returns the projects the api token of the current request is restricted to, if any
+func getAPITokenProjectIDs(a web.Auth) []int64 {
+	u, is := a.(*user.User)
+	if !is {
+		return nil
+	}
+	return u.APITokenProjectIDs
+}
+
+// apiTokenCanAccessProject checks if the api token of the current request, if any, allows access to a project.
+// Child projects of the projects a token is restricted to are accessible as well.
+func apiTokenCanAccessProject(s *xorm.Session, a web.Auth, projectID int64) (bool, error) {
+	projectIDs := getAPITokenProjectIDs(a)
+	if len(projectIDs) == 0 {
+		return true, nil
+	}
+
+	parents, err := GetAllParentProjects(s, projectID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range projectIDs {
+		if _, has := parents[id]; has {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getAPITokenAccessibleProjectIDs returns the projects the api token of the current request is restricted to
+// including all of their child projects. It returns nil if the token is not restricted.
+func getAPITokenAccessibleProjectIDs(s *xorm.Session, a web.Auth) (ids []int64, err error) {
+	for _, projectID := range getAPITokenProjectIDs(a) {
+		childIDs, err := getAllChildProjectIDs(s, projectID)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, projectID)
+		ids = append(ids, childIDs...)
+	}
+	return
+}
+
 // GetTokenFromTokenString returns the full token object from the original token string.
 func GetTokenFromTokenString(s *xorm.Session, token string) (apiToken *APIToken, err error) {
 	lastEight := token[len(token)-8:]
@@ -85,13 +85,197 @@ func TestAPIToken_CanDelete(t *testing.T) {
 func TestAPIToken_Create(t *testing.T) {
 	t.Run("normal", func(t *testing.T) {
 		u := &user.User{ID: 1}
-		token := &APIToken{}
+		token := &APIToken{RateLimit: 1000}
 		s := db.NewSession()
 		defer s.Close()
 		db.LoadAndAssertFixtures(t)
 
 		err := token.Create(s, u)
 		require.NoError(t, err)
+		assert.Equal(t, int64(0), token.RateLimit)
+	})
+	t.Run("restricted to projects", func(t *testing.T) {
+		u := &user.User{ID: 1}
+		token := &APIToken{ProjectIDs: []int64{1, 3}}
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		err := token.Create(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("restricted to a project the user has no access to", func(t *testing.T) {
+		u := &user.User{ID: 1}
+		token := &APIToken{ProjectIDs: []int64{20}}
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		err := token.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
+
+func TestAPIToken_ProjectRestrictions(t *testing.T) {
+	u := &user.User{ID: 1, AuthenticatedWithAPIToken: true, APITokenProjectIDs: []int64{1}}
+
+	t.Run("allowed project", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		can, _, err := (&Project{ID: 1}).CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, err = (&Task{ID: 1}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("other project", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		can, _, err := (&Project{ID: 2}).CanRead(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+		can, err = (&Project{ID: 2}).CanWrite(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+		can, err = (&Task{ID: 2, ProjectID: 2}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("new top level project", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		can, err := (&Project{}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("all projects", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		result, _, _, err := (&Project{}).ReadAll(s, u, "", 1, 50)
+		require.NoError(t, err)
+		projects := result.([]*Project)
+		require.Len(t, projects, 1)
+		assert.Equal(t, int64(1), projects[0].ID)
+	})
+	t.Run("all tasks", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		result, _, _, err := (&TaskCollection{}).ReadAll(s, u, "", 1, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.NotEmpty(t, tasks)
+		for _, task := range tasks {
+			assert.Equal(t, int64(1), task.ProjectID)
+		}
+	})
+	t.Run("all labels", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		result, _, _, err := (&Label{}).ReadAll(s, u, "", 1, 50)
+		require.NoError(t, err)
+		labelIDs := []int64{}
+		for _, l := range result.([]*LabelWithTaskID) {
+			labelIDs = append(labelIDs, l.ID)
+		}
+		// Label 4 is used on task 1 in project 1
+		assert.ElementsMatch(t, []int64{1, 2, 4}, labelIDs)
+
+		project := &Project{Title: "without labels"}
+		err = project.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+
+		other := &user.User{ID: 1, AuthenticatedWithAPIToken: true, APITokenProjectIDs: []int64{project.ID}}
+		result, _, _, err = (&Label{}).ReadAll(s, other, "", 1, 50)
+		require.NoError(t, err)
+		labelIDs = []int64{}
+		for _, l := range result.([]*LabelWithTaskID) {
+			labelIDs = append(labelIDs, l.ID)
+		}
+		// Only the labels the user created themselves
+		assert.ElementsMatch(t, []int64{1, 2}, labelIDs)
+	})
+	t.Run("child project", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		child := &Project{Title: "child", ParentProjectID: 1}
+		can, err := child.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = child.Create(s, u)
+		require.NoError(t, err)
+
+		can, _, err = (&Project{ID: child.ID}).CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, err = (&Project{ID: child.ID}).CanWrite(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		result, _, _, err := (&Project{}).ReadAll(s, u, "", 1, 50)
+		require.NoError(t, err)
+		projects := result.([]*Project)
+		require.Len(t, projects, 2)
+		assert.Equal(t, child.ID, projects[1].ID)
+	})
+	t.Run("role on other project", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+		// Contributor role on project 3
+		setProjectUserRole(t, s, 1, 2)
+
+		can, err := (&Task{ProjectID: 3}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+		can, err = (&Task{ID: 32}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+		can, err = (&TaskComment{TaskID: 32}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+		can, err = (&TaskAttachment{TaskID: 32}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestSetAPITokenRateLimit(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		err := SetAPITokenRateLimit(s, 1, 500)
+		require.NoError(t, err)
+		db.AssertExists(t, "api_tokens", map[string]interface{}{
+			"id":         1,
+			"rate_limit": 500,
+		}, false)
+	})
+	t.Run("nonexisting token", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		err := SetAPITokenRateLimit(s, 999, 500)
+		require.Error(t, err)
+		assert.True(t, IsErrAPITokenDoesNotExist(err))
 	})
 }
 
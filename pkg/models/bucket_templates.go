@@ -0,0 +1,398 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// BucketTemplate is a set of buckets which can be used to create the kanban buckets of a project
+type BucketTemplate struct {
+	// The unique, numeric id of this bucket template. Templates configured for the whole instance have a negative id.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"buckettemplate"`
+	// The title of this bucket template.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// The buckets which will be created from this template, in this order.
+	Buckets []*BucketTemplateBucket `xorm:"JSON null" json:"buckets"`
+	// True if this template is configured for the whole instance. These templates cannot be changed through the api.
+	IsInstanceTemplate bool `xorm:"-" json:"is_instance_template"`
+
+	OwnerID int64 `xorm:"bigint not null INDEX" json:"-"`
+	// The user who owns this template. Empty for instance templates.
+	Owner *user.User `xorm:"-" json:"owner" valid:"-"`
+
+	// A timestamp when this template was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this template was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// BucketTemplateBucket is a single bucket of a bucket template
+type BucketTemplateBucket struct {
+	// The title of the bucket.
+	Title string `json:"title"`
+	// How many tasks can be at the same time in the bucket.
+	Limit int64 `json:"limit"`
+	// If true, the bucket will be set as the done bucket of the project.
+	IsDoneBucket bool `json:"is_done_bucket"`
+}
+
+// TableName returns the table name for bucket templates
+func (bt *BucketTemplate) TableName() string {
+	return "bucket_templates"
+}
+
+// getInstanceBucketTemplates returns all bucket templates from the config. They get a negative id
+// derived from their position in the config so that they can be referenced like all other templates.
+func getInstanceBucketTemplates() (templates []*BucketTemplate, err error) {
+	templates = []*BucketTemplate{}
+	err = config.KanbanBucketTemplates.Unmarshal(&templates)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, t := range templates {
+		t.ID = int64(i+1) * -1
+		t.IsInstanceTemplate = true
+	}
+
+	return
+}
+
+func getBucketTemplateByID(s *xorm.Session, id int64) (template *BucketTemplate, err error) {
+	if id < 0 {
+		templates, err := getInstanceBucketTemplates()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range templates {
+			if t.ID == id {
+				return t, nil
+			}
+		}
+		return nil, &ErrBucketTemplateDoesNotExist{BucketTemplateID: id}
+	}
+
+	template = &BucketTemplate{}
+	exists, err := s.Where("id = ?", id).Get(template)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrBucketTemplateDoesNotExist{BucketTemplateID: id}
+	}
+	return
+}
+
+func (bt *BucketTemplate) validate() error {
+	if len(bt.Buckets) == 0 {
+		return &ErrInvalidBucketTemplate{BucketTemplateID: bt.ID}
+	}
+
+	var doneBuckets int
+	for _, b := range bt.Buckets {
+		if b.Title == "" || b.Limit < 0 {
+			return &ErrInvalidBucketTemplate{BucketTemplateID: bt.ID}
+		}
+		if b.IsDoneBucket {
+			doneBuckets++
+		}
+	}
+
+	if doneBuckets > 1 {
+		return &ErrInvalidBucketTemplate{BucketTemplateID: bt.ID}
+	}
+
+	return nil
+}
+
+// applyToProject creates all buckets of the template in the project and sets the done bucket if the template has one.
+// An existing done bucket of the project is only replaced if replaceDoneBucket is true.
+func (bt *BucketTemplate) applyToProject(s *xorm.Session, project *Project, a web.Auth, replaceDoneBucket bool) (buckets []*Bucket, err error) {
+	buckets = make([]*Bucket, 0, len(bt.Buckets))
+	for _, tb := range bt.Buckets {
+		b := &Bucket{
+			ProjectID: project.ID,
+			Title:     tb.Title,
+			Limit:     tb.Limit,
+		}
+		err = b.Create(s, a)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+
+		if tb.IsDoneBucket && (project.DoneBucketID == 0 || replaceDoneBucket) {
+			project.DoneBucketID = b.ID
+			_, err = s.Where("id = ?", project.ID).Cols("done_bucket_id").Update(project)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return
+}
+
+// Create creates a new bucket template
+// @Summary Create a new bucket template
+// @Description Creates a new bucket template for the current user. It can be used to create the buckets of new or existing projects.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param template body models.BucketTemplate true "The bucket template"
+// @Success 201 {object} models.BucketTemplate "The created bucket template."
+// @Failure 400 {object} web.HTTPError "Invalid bucket template object provided."
+// @Failure 403 {object} web.HTTPError "Link shares cannot create bucket templates."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /buckettemplates [put]
+func (bt *BucketTemplate) Create(s *xorm.Session, a web.Auth) (err error) {
+	bt.ID = 0
+	bt.IsInstanceTemplate = false
+
+	err = bt.validate()
+	if err != nil {
+		return err
+	}
+
+	bt.Owner, err = user.GetUserByID(s, a.GetID())
+	if err != nil {
+		return err
+	}
+	bt.OwnerID = bt.Owner.ID
+
+	_, err = s.Insert(bt)
+	return
+}
+
+// ReadOne returns one bucket template
+// @Summary Get a bucket template
+// @Description Returns a single bucket template by its id.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Bucket template ID"
+// @Success 200 {object} models.BucketTemplate "The bucket template"
+// @Failure 403 {object} web.HTTPError "The user does not have access to that bucket template."
+// @Failure 404 {object} web.HTTPError "The bucket template does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /buckettemplates/{id} [get]
+func (bt *BucketTemplate) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	// The template was already loaded in CanRead
+	if bt.IsInstanceTemplate {
+		return nil
+	}
+
+	bt.Owner, err = user.GetUserByID(s, bt.OwnerID)
+	return
+}
+
+// ReadAll returns all bucket templates available to the current user
+// @Summary Get all bucket templates
+// @Description Returns all bucket templates configured for this instance and all bucket templates the current user created.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param s query string false "Search bucket templates by their title."
+// @Success 200 {array} models.BucketTemplate "The bucket templates"
+// @Failure 403 {object} web.HTTPError "Link shares cannot access bucket templates."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /buckettemplates [get]
+func (bt *BucketTemplate) ReadAll(s *xorm.Session, a web.Auth, search string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	if _, is := a.(*LinkSharing); is {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	instanceTemplates, err := getInstanceBucketTemplates()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	templates := make([]*BucketTemplate, 0, len(instanceTemplates))
+	for _, t := range instanceTemplates {
+		if search != "" && !strings.Contains(strings.ToLower(t.Title), strings.ToLower(search)) {
+			continue
+		}
+		templates = append(templates, t)
+	}
+
+	var where builder.Cond = builder.Eq{"owner_id": a.GetID()}
+	if search != "" {
+		where = builder.And(
+			where,
+			db.ILIKE("title", search),
+		)
+	}
+
+	userTemplates := []*BucketTemplate{}
+	err = s.
+		Where(where).
+		OrderBy("id asc").
+		Find(&userTemplates)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if len(userTemplates) > 0 {
+		owner, err := user.GetUserByID(s, a.GetID())
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		for _, t := range userTemplates {
+			t.Owner = owner
+		}
+	}
+
+	templates = append(templates, userTemplates...)
+
+	return templates, len(templates), int64(len(templates)), nil
+}
+
+// Update updates a bucket template
+// @Summary Update a bucket template
+// @Description Updates a bucket template of the current user. Templates configured for the instance cannot be updated.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Bucket template ID"
+// @Param template body models.BucketTemplate true "The bucket template"
+// @Success 200 {object} models.BucketTemplate "The updated bucket template."
+// @Failure 400 {object} web.HTTPError "Invalid bucket template object provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to that bucket template."
+// @Failure 404 {object} web.HTTPError "The bucket template does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /buckettemplates/{id} [post]
+func (bt *BucketTemplate) Update(s *xorm.Session, _ web.Auth) (err error) {
+	err = bt.validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("id = ?", bt.ID).
+		Cols(
+			"title",
+			"buckets",
+		).
+		Update(bt)
+	return
+}
+
+// Delete deletes a bucket template
+// @Summary Delete a bucket template
+// @Description Deletes a bucket template of the current user. Projects created from it are not changed.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Bucket template ID"
+// @Success 200 {object} models.Message "The bucket template was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to that bucket template."
+// @Failure 404 {object} web.HTTPError "The bucket template does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /buckettemplates/{id} [delete]
+func (bt *BucketTemplate) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("id = ?", bt.ID).Delete(&BucketTemplate{})
+	return
+}
+
+// ProjectBucketTemplate holds everything needed to apply a bucket template to an existing project
+type ProjectBucketTemplate struct {
+	// The project the template will be applied to
+	ProjectID int64 `json:"-" param:"project"`
+	// The id of the bucket template to apply
+	TemplateID int64 `json:"template_id"`
+	// If true, the done bucket of the template replaces the current done bucket of the project. Otherwise it is only
+	// used as done bucket if the project does not have one yet.
+	ReplaceDoneBucket bool `json:"replace_done_bucket"`
+
+	// The buckets which were created from the template
+	Buckets []*Bucket `json:"buckets"`
+
+	template *BucketTemplate
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// Create applies a bucket template to a project
+// @Summary Apply a bucket template to a project
+// @Description Creates all buckets of a bucket template in an existing project. The buckets are added after all existing buckets of the project. If the template contains a done bucket, it becomes the done bucket of the project if the project does not have one yet. Set `replace_done_bucket` to replace an existing done bucket.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param template body models.ProjectBucketTemplate true "The bucket template to apply"
+// @Success 201 {object} models.ProjectBucketTemplate "The created buckets."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project or bucket template."
+// @Failure 404 {object} web.HTTPError "The bucket template does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/buckettemplate [put]
+func (pbt *ProjectBucketTemplate) Create(s *xorm.Session, a web.Auth) (err error) {
+	project, err := GetProjectSimpleByID(s, pbt.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	if pbt.template == nil {
+		pbt.template, err = getBucketTemplateByID(s, pbt.TemplateID)
+		if err != nil {
+			return err
+		}
+	}
+
+	maxPosition := 0.0
+	_, err = s.
+		Table("buckets").
+		Where("project_id = ?", project.ID).
+		Select("max(position)").
+		Get(&maxPosition)
+	if err != nil {
+		return err
+	}
+
+	pbt.Buckets, err = pbt.template.applyToProject(s, project, a, pbt.ReplaceDoneBucket)
+	if err != nil {
+		return err
+	}
+
+	// Move all new buckets after the existing ones while keeping their order
+	for _, b := range pbt.Buckets {
+		b.Position += maxPosition
+		_, err = s.Where("id = ?", b.ID).Cols("position").Update(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
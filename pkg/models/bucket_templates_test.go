@@ -0,0 +1,232 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketTemplate_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bt := &BucketTemplate{
+			Title: "New template",
+			Buckets: []*BucketTemplateBucket{
+				{Title: "Todo"},
+				{Title: "Done", IsDoneBucket: true},
+			},
+		}
+		err := bt.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "bucket_templates", map[string]interface{}{
+			"id":       bt.ID,
+			"title":    "New template",
+			"owner_id": 1,
+		}, false)
+	})
+	t.Run("without buckets", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bt := &BucketTemplate{
+			Title: "New template",
+		}
+		err := bt.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidBucketTemplate(err))
+	})
+	t.Run("multiple done buckets", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bt := &BucketTemplate{
+			Title: "New template",
+			Buckets: []*BucketTemplateBucket{
+				{Title: "Done", IsDoneBucket: true},
+				{Title: "Also done", IsDoneBucket: true},
+			},
+		}
+		err := bt.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidBucketTemplate(err))
+	})
+}
+
+func TestBucketTemplate_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	config.KanbanBucketTemplates.Set([]map[string]interface{}{
+		{
+			"title": "Instance template",
+			"buckets": []map[string]interface{}{
+				{"title": "Backlog"},
+				{"title": "Done", "isdonebucket": true},
+			},
+		},
+	})
+	defer config.KanbanBucketTemplates.Set(nil)
+
+	bt := &BucketTemplate{}
+	result, _, _, err := bt.ReadAll(s, &user.User{ID: 1}, "", 1, 50)
+	require.NoError(t, err)
+	templates := result.([]*BucketTemplate)
+	require.Len(t, templates, 2)
+	assert.Equal(t, int64(-1), templates[0].ID)
+	assert.True(t, templates[0].IsInstanceTemplate)
+	assert.True(t, templates[0].Buckets[1].IsDoneBucket)
+	assert.Equal(t, int64(1), templates[1].ID)
+}
+
+func TestBucketTemplate_CanUpdate(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("own template", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bt := &BucketTemplate{ID: 1}
+		can, err := bt.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("template of another user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bt := &BucketTemplate{ID: 2}
+		can, err := bt.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("instance template", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.KanbanBucketTemplates.Set([]map[string]interface{}{
+			{
+				"title":   "Instance template",
+				"buckets": []map[string]interface{}{{"title": "Backlog"}},
+			},
+		})
+		defer config.KanbanBucketTemplates.Set(nil)
+
+		bt := &BucketTemplate{ID: -1}
+		can, err := bt.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestProjectBucketTemplate_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pbt := &ProjectBucketTemplate{
+			ProjectID:  1,
+			TemplateID: 1,
+		}
+		can, err := pbt.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pbt.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		require.Len(t, pbt.Buckets, 4)
+		db.AssertExists(t, "buckets", map[string]interface{}{
+			"id":         pbt.Buckets[0].ID,
+			"project_id": 1,
+			"title":      "Backlog",
+		}, false)
+		// The project already has a done bucket
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":             1,
+			"done_bucket_id": 3,
+		}, false)
+	})
+	t.Run("replace done bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pbt := &ProjectBucketTemplate{
+			ProjectID:         1,
+			TemplateID:        1,
+			ReplaceDoneBucket: true,
+		}
+		err := pbt.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		require.Len(t, pbt.Buckets, 4)
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":             1,
+			"done_bucket_id": pbt.Buckets[3].ID,
+		}, false)
+	})
+	t.Run("project without done bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 1).Cols("done_bucket_id").Update(&Project{DoneBucketID: 0})
+		require.NoError(t, err)
+
+		pbt := &ProjectBucketTemplate{
+			ProjectID:  1,
+			TemplateID: 1,
+		}
+		err = pbt.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		require.Len(t, pbt.Buckets, 4)
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":             1,
+			"done_bucket_id": pbt.Buckets[3].ID,
+		}, false)
+	})
+}
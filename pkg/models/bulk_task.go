@@ -17,6 +17,7 @@
 package models
 
 import (
+	"code.vikunja.io/api/pkg/utils"
 	"code.vikunja.io/web"
 
 	"dario.cat/mergo"
@@ -81,6 +82,8 @@ func (bt *BulkTask) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
 // @Router /tasks/bulk [post]
 func (bt *BulkTask) Update(s *xorm.Session, a web.Auth) (err error) {
 	for _, oldtask := range bt.Tasks {
+		// Keep the values from before the update for the history
+		before := *oldtask
 
 		// When a repeating task is marked as done, we update all deadlines and reminders and set it as undone
 		updateDone(oldtask, &bt.Task)
@@ -102,8 +105,18 @@ func (bt *BulkTask) Update(s *xorm.Session, a web.Auth) (err error) {
 			oldtask.Done = false
 		}
 
+		// The format can't be changed in bulk, the description is interpreted in the format of each task.
+		oldtask.DescriptionFormat = before.DescriptionFormat
+		err = oldtask.sanitizeDescription(TaskDescriptionFormatHTML)
+		if err != nil {
+			return err
+		}
+
+		oldtask.NormalizedTitle = utils.NormalizeSearchText(oldtask.Title)
+
 		_, err = s.ID(oldtask.ID).
 			Cols("title",
+				"normalized_title",
 				"description",
 				"done",
 				"due_date",
@@ -116,6 +129,16 @@ func (bt *BulkTask) Update(s *xorm.Session, a web.Auth) (err error) {
 		if err != nil {
 			return err
 		}
+
+		err = recordTaskChanges(s, a, &before, oldtask)
+		if err != nil {
+			return err
+		}
+
+		err = updateAutomaticPercentDoneByID(s, oldtask.ParentTaskID)
+		if err != nil {
+			return err
+		}
 	}
 
 	return
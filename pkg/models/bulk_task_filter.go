@@ -0,0 +1,273 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"math"
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskPatch holds the changes which are applied to every task of a bulk update.
+// All fields are optional, only the ones which are provided are changed.
+type TaskPatch struct {
+	// If provided, marks the tasks as done or undone.
+	Done *bool `json:"done"`
+	// If provided, sets the priority of the tasks.
+	Priority *int64 `json:"priority"`
+	// If provided, sets the due date of the tasks. Pass a zero date to remove the due date.
+	DueDate *time.Time `json:"due_date"`
+	// If provided, moves the tasks into this bucket. The bucket must belong to the project.
+	BucketID int64 `json:"bucket_id"`
+	// The ids of labels to add to the tasks.
+	AddLabels []int64 `json:"add_labels"`
+	// The ids of labels to remove from the tasks.
+	RemoveLabels []int64 `json:"remove_labels"`
+	// The ids of users to assign to the tasks.
+	AddAssignees []int64 `json:"add_assignees"`
+	// The ids of users to unassign from the tasks.
+	RemoveAssignees []int64 `json:"remove_assignees"`
+}
+
+func (p *TaskPatch) isEmpty() bool {
+	return p.Done == nil &&
+		p.Priority == nil &&
+		p.DueDate == nil &&
+		p.BucketID == 0 &&
+		len(p.AddLabels) == 0 &&
+		len(p.RemoveLabels) == 0 &&
+		len(p.AddAssignees) == 0 &&
+		len(p.RemoveAssignees) == 0
+}
+
+// BulkTaskFilterUpdate applies a patch to all tasks of a project matching a filter
+type BulkTaskFilterUpdate struct {
+	// The project the tasks belong to.
+	ProjectID int64 `json:"-" param:"project"`
+	// The filter query to match the tasks which should be updated. If empty, all tasks of the project are updated.
+	// Check out https://vikunja.io/docs/filters for a full explanation of the feature.
+	Filter string `json:"filter"`
+	// The time zone which should be used for date match (statements like "now" resolve to different actual times)
+	FilterTimezone string `json:"filter_timezone"`
+	// The changes to apply to all matching tasks.
+	Patch *TaskPatch `json:"patch"`
+	// If true, only the matching tasks are returned without changing them.
+	DryRun bool `json:"dry_run"`
+
+	// The number of tasks matching the filter.
+	Count int `json:"count"`
+	// The ids of all tasks matching the filter.
+	TaskIDs []int64 `json:"task_ids"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanUpdate checks if the user can update tasks in the project
+func (bu *BulkTaskFilterUpdate) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	if getSavedFilterIDFromProjectID(bu.ProjectID) > 0 {
+		return false, nil
+	}
+
+	project := &Project{ID: bu.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+func (bu *BulkTaskFilterUpdate) getTasks(s *xorm.Session, a web.Auth) (tasks []*Task, err error) {
+	opts, err := getTaskFilterOptsFromCollection(&TaskCollection{
+		Filter:         bu.Filter,
+		FilterTimezone: bu.FilterTimezone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	opts.projectIDs = []int64{bu.ProjectID}
+	opts.sortby = []*sortParam{
+		{
+			orderBy: orderAscending,
+			sortBy:  taskPropertyID,
+		},
+	}
+
+	searcher := &dbTaskSearcher{
+		s: s,
+		a: a,
+	}
+	tasks, _, err = searcher.Search(opts)
+	return
+}
+
+func (p *TaskPatch) applyToLabels(labels []*Label) (changed bool, newLabels []*Label) {
+	remove := make(map[int64]bool, len(p.RemoveLabels))
+	for _, id := range p.RemoveLabels {
+		remove[id] = true
+	}
+
+	existing := make(map[int64]bool, len(labels))
+	newLabels = make([]*Label, 0, len(labels)+len(p.AddLabels))
+	for _, l := range labels {
+		existing[l.ID] = true
+		if remove[l.ID] {
+			changed = true
+			continue
+		}
+		newLabels = append(newLabels, l)
+	}
+
+	for _, id := range p.AddLabels {
+		if existing[id] || remove[id] {
+			continue
+		}
+		existing[id] = true
+		changed = true
+		newLabels = append(newLabels, &Label{ID: id})
+	}
+
+	return
+}
+
+func (p *TaskPatch) applyToAssignees(assignees []*user.User) (newAssignees []*user.User) {
+	remove := make(map[int64]bool, len(p.RemoveAssignees))
+	for _, id := range p.RemoveAssignees {
+		remove[id] = true
+	}
+
+	existing := make(map[int64]bool, len(assignees))
+	newAssignees = make([]*user.User, 0, len(assignees)+len(p.AddAssignees))
+	for _, u := range assignees {
+		existing[u.ID] = true
+		if !remove[u.ID] {
+			newAssignees = append(newAssignees, u)
+		}
+	}
+
+	for _, id := range p.AddAssignees {
+		if existing[id] || remove[id] {
+			continue
+		}
+		existing[id] = true
+		newAssignees = append(newAssignees, &user.User{ID: id})
+	}
+
+	return
+}
+
+// Update applies the patch to all matching tasks
+// @Summary Update all tasks matching a filter
+// @Description Applies a patch to all tasks of a project which match a filter in one transaction. If one of the tasks cannot be updated, no task is changed. With `dry_run` set, only the matching tasks are returned.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param update body models.BulkTaskFilterUpdate true "The filter and the patch to apply."
+// @Success 200 {object} models.BulkTaskFilterUpdate "The number and ids of the matching tasks."
+// @Failure 400 {object} web.HTTPError "Invalid filter or an empty patch was provided."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/tasks/bulk [post]
+func (bu *BulkTaskFilterUpdate) Update(s *xorm.Session, a web.Auth) (err error) {
+	if bu.Patch == nil || bu.Patch.isEmpty() {
+		return ErrEmptyTaskPatch{}
+	}
+
+	tasks, err := bu.getTasks(s, a)
+	if err != nil {
+		return err
+	}
+
+	bu.Count = len(tasks)
+	bu.TaskIDs = make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		bu.TaskIDs = append(bu.TaskIDs, t.ID)
+	}
+
+	if bu.DryRun || len(tasks) == 0 {
+		return nil
+	}
+
+	project, err := GetProjectSimpleByID(s, bu.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	taskMap := make(map[int64]*Task, len(tasks))
+	for _, t := range tasks {
+		taskMap[t.ID] = t
+	}
+	// Task.Update replaces all fields of a task, so we need to get the full tasks first
+	err = addMoreInfoToTasks(s, taskMap, a)
+	if err != nil {
+		return err
+	}
+
+	// Put all moved tasks after the tasks already in the target bucket
+	last := &Task{}
+	if bu.Patch.BucketID != 0 {
+		_, err = s.
+			Where("bucket_id = ?", bu.Patch.BucketID).
+			OrderBy("kanban_position desc").
+			Get(last)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, t := range tasks {
+		if bu.Patch.Done != nil {
+			t.Done = *bu.Patch.Done
+		}
+		if bu.Patch.Priority != nil {
+			t.Priority = *bu.Patch.Priority
+		}
+		if bu.Patch.DueDate != nil {
+			t.DueDate = *bu.Patch.DueDate
+		}
+		if bu.Patch.BucketID != 0 && bu.Patch.BucketID != t.BucketID {
+			if t.BucketID == project.DoneBucketID && bu.Patch.Done == nil {
+				t.Done = false
+			}
+			t.BucketID = bu.Patch.BucketID
+			t.KanbanPosition = last.KanbanPosition + float64(i+1)*math.Pow(2, 16)
+		}
+		if len(bu.Patch.AddAssignees) > 0 || len(bu.Patch.RemoveAssignees) > 0 {
+			t.Assignees = bu.Patch.applyToAssignees(t.Assignees)
+		}
+
+		err = t.Update(s, a)
+		if err != nil {
+			return err
+		}
+
+		changed, labels := bu.Patch.applyToLabels(t.Labels)
+		if changed {
+			err = t.UpdateTaskLabels(s, a, labels)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if bu.Patch.BucketID != 0 {
+		return recalculateTaskKanbanPositions(s, bu.Patch.BucketID)
+	}
+
+	return nil
+}
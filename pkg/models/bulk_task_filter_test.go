@@ -0,0 +1,254 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkTaskFilterUpdate_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+	priority := int64(5)
+	done := true
+
+	t.Run("dry run", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{
+			ProjectID: 1,
+			Filter:    "bucket_id = 2",
+			Patch:     &TaskPatch{Priority: &priority},
+			DryRun:    true,
+		}
+		err := bu.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, 3, bu.Count)
+		assert.Equal(t, []int64{3, 4, 5}, bu.TaskIDs)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":       3,
+			"priority": 100,
+		}, false)
+	})
+	t.Run("set priority and add label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{
+			ProjectID: 1,
+			Filter:    "bucket_id = 2",
+			Patch: &TaskPatch{
+				Priority:  &priority,
+				AddLabels: []int64{1},
+			},
+		}
+		err := bu.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, 3, bu.Count)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		for _, id := range []int64{3, 4, 5} {
+			db.AssertExists(t, "tasks", map[string]interface{}{
+				"id":       id,
+				"priority": 5,
+			}, false)
+			db.AssertExists(t, "label_tasks", map[string]interface{}{
+				"task_id":  id,
+				"label_id": 1,
+			}, false)
+		}
+		db.AssertMissing(t, "label_tasks", map[string]interface{}{
+			"task_id":  1,
+			"label_id": 1,
+		})
+	})
+	t.Run("remove label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{
+			ProjectID: 1,
+			Filter:    "done = true",
+			Patch:     &TaskPatch{RemoveLabels: []int64{4}},
+		}
+		err := bu.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2}, bu.TaskIDs)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "label_tasks", map[string]interface{}{
+			"task_id":  2,
+			"label_id": 4,
+		})
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  1,
+			"label_id": 4,
+		}, false)
+	})
+	t.Run("move bucket and assign", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{
+			ProjectID: 1,
+			Filter:    "bucket_id = 3",
+			Patch: &TaskPatch{
+				BucketID:     1,
+				AddAssignees: []int64{1},
+			},
+		}
+		err := bu.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{6, 7, 8}, bu.TaskIDs)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		for _, id := range bu.TaskIDs {
+			db.AssertExists(t, "tasks", map[string]interface{}{
+				"id":        id,
+				"bucket_id": 1,
+			}, false)
+			db.AssertExists(t, "task_assignees", map[string]interface{}{
+				"task_id": id,
+				"user_id": 1,
+			}, false)
+		}
+	})
+	t.Run("mark done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{
+			ProjectID: 1,
+			Filter:    "bucket_id = 3",
+			Patch:     &TaskPatch{Done: &done},
+		}
+		err := bu.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":   6,
+			"done": true,
+		}, false)
+	})
+	t.Run("no matching tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{
+			ProjectID: 1,
+			Filter:    "priority = 42",
+			Patch:     &TaskPatch{Priority: &priority},
+		}
+		err := bu.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, 0, bu.Count)
+		assert.Empty(t, bu.TaskIDs)
+	})
+	t.Run("bucket limit exceeded", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{
+			ProjectID: 1,
+			Filter:    "bucket_id = 3",
+			Patch:     &TaskPatch{BucketID: 2},
+		}
+		err := bu.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBucketLimitExceeded(err))
+	})
+	t.Run("empty patch", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{
+			ProjectID: 1,
+			Patch:     &TaskPatch{},
+		}
+		err := bu.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrEmptyTaskPatch(err))
+	})
+	t.Run("invalid filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{
+			ProjectID: 1,
+			Filter:    "foo = bar",
+			Patch:     &TaskPatch{Priority: &priority},
+		}
+		err := bu.Update(s, u)
+		require.Error(t, err)
+	})
+}
+
+func TestBulkTaskFilterUpdate_CanUpdate(t *testing.T) {
+	t.Run("own project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{ProjectID: 1}
+		can, err := bu.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("read only", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{ProjectID: 9}
+		can, err := bu.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("saved filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bu := &BulkTaskFilterUpdate{ProjectID: -2}
+		can, err := bu.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
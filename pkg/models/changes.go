@@ -0,0 +1,338 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// EntityTombstoneKind is the kind of entity a tombstone was recorded for
+type EntityTombstoneKind string
+
+const (
+	// EntityTombstoneKindTask is the tombstone of a deleted task.
+	EntityTombstoneKindTask EntityTombstoneKind = "task"
+	// EntityTombstoneKindProject is the tombstone of a deleted project.
+	EntityTombstoneKindProject EntityTombstoneKind = "project"
+)
+
+// EntityTombstone records that a task or project was deleted, so integrations polling for changes can remove it too.
+type EntityTombstone struct {
+	TombstoneID int64 `xorm:"'id' bigint autoincr not null unique pk" json:"-"`
+	// The kind of the deleted entity, `task` or `project`.
+	Kind EntityTombstoneKind `xorm:"varchar(20) not null index" json:"kind"`
+	// The id of the deleted entity.
+	ID int64 `xorm:"'entity_id' bigint not null" json:"id"`
+	// The project the deleted task belonged to or the id of the deleted project.
+	ProjectID int64 `xorm:"bigint not null index" json:"project_id"`
+	// Project tombstones are saved once for every user who had access to the project when it was deleted.
+	UserID int64 `xorm:"bigint not null default 0 index" json:"-"`
+	// When the entity was deleted.
+	Deleted time.Time `xorm:"created not null" json:"deleted"`
+}
+
+// TableName returns the table name for entity tombstones
+func (*EntityTombstone) TableName() string {
+	return "entity_tombstones"
+}
+
+func recordTaskTombstone(s *xorm.Session, task *Task) (err error) {
+	_, err = s.Insert(&EntityTombstone{
+		Kind:      EntityTombstoneKindTask,
+		ID:        task.ID,
+		ProjectID: task.ProjectID,
+	})
+	return
+}
+
+// recordProjectTombstone saves a tombstone of the project for every user who has access to it. It needs to be called
+// before the project is deleted.
+func recordProjectTombstone(s *xorm.Session, project *Project) (err error) {
+	// The owner is only included when it's set on the project
+	project, err = GetProjectSimpleByID(s, project.ID)
+	if err != nil {
+		return err
+	}
+
+	users, err := ListUsersFromProject(s, project, "")
+	if err != nil {
+		return err
+	}
+
+	tombstones := make([]*EntityTombstone, 0, len(users))
+	for _, u := range users {
+		tombstones = append(tombstones, &EntityTombstone{
+			Kind:      EntityTombstoneKindProject,
+			ID:        project.ID,
+			ProjectID: project.ID,
+			UserID:    u.ID,
+		})
+	}
+	if len(tombstones) == 0 {
+		return nil
+	}
+
+	_, err = s.Insert(&tombstones)
+	return
+}
+
+// changesCursor is the position of a client in the stream of changes. Entities are ordered by the time they were
+// last updated and their id, tombstones by the order they were recorded in.
+type changesCursor struct {
+	Updated   int64 `json:"u"`
+	ID        int64 `json:"i"`
+	Tombstone int64 `json:"t"`
+}
+
+func parseChangesCursor(cursor string) (c *changesCursor, err error) {
+	c = &changesCursor{}
+	if cursor == "" {
+		return c, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, &ErrInvalidChangesCursor{Cursor: cursor}
+	}
+	err = json.Unmarshal(raw, c)
+	if err != nil {
+		return nil, &ErrInvalidChangesCursor{Cursor: cursor}
+	}
+	return c, nil
+}
+
+func (c *changesCursor) String() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// after returns the condition for all entities which changed after the cursor. Entities which changed in the current
+// second are left out because more changes with the same timestamp could still be made, which would otherwise be
+// missed by the next request.
+func (c *changesCursor) after(now time.Time) builder.Cond {
+	updated := time.Unix(c.Updated, 0)
+	return builder.And(
+		builder.Or(
+			builder.Gt{"updated": updated},
+			builder.And(builder.Eq{"updated": updated}, builder.Gt{"id": c.ID}),
+		),
+		builder.Lt{"updated": now.Truncate(time.Second)},
+	)
+}
+
+// TaskChanges holds all tasks which were created, updated or deleted since a cursor
+type TaskChanges struct {
+	// The tasks which were created or updated, in the order they were changed.
+	Tasks []*Task `json:"tasks"`
+	// The tasks which were deleted. If a project was deleted, only the project is returned
+	// by the project changes, not all of its tasks.
+	Deleted []*EntityTombstone `json:"deleted"`
+	// The cursor to pass to the next request to get all changes after these.
+	Cursor string `json:"cursor"`
+	// Whether there are more changes than returned. If true, request the next changes right away.
+	HasMore bool `json:"has_more"`
+}
+
+// ProjectChanges holds all projects which were created, updated or deleted since a cursor
+type ProjectChanges struct {
+	// The projects which were created or updated, in the order they were changed.
+	Projects []*Project `json:"projects"`
+	// The projects which were deleted.
+	Deleted []*EntityTombstone `json:"deleted"`
+	// The cursor to pass to the next request to get all changes after these.
+	Cursor string `json:"cursor"`
+	// Whether there are more changes than returned. If true, request the next changes right away.
+	HasMore bool `json:"has_more"`
+}
+
+// getReadableProjects returns all projects a user or link share has access to, including archived ones.
+func getReadableProjects(s *xorm.Session, a web.Auth) (projects []*Project, err error) {
+	if share, is := a.(*LinkSharing); is {
+		project, err := GetProjectSimpleByID(s, share.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		return []*Project{project}, nil
+	}
+
+	projects, _, err = getAllProjectsForUser(s, a.GetID(), &projectOptions{
+		user:        &user.User{ID: a.GetID(), APITokenProjectIDs: getAPITokenProjectIDs(a)},
+		getArchived: true,
+	})
+	return
+}
+
+// GetTaskChanges returns up to limit tasks the user has access to which changed after the cursor and up to limit tasks
+// which were deleted after it. An empty cursor returns all tasks from the beginning.
+func GetTaskChanges(s *xorm.Session, a web.Auth, cursor string, limit int) (changes *TaskChanges, err error) {
+	c, err := parseChangesCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := getReadableProjects(s, a)
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make([]int64, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	changes = &TaskChanges{
+		Tasks:   []*Task{},
+		Deleted: []*EntityTombstone{},
+	}
+	if len(projectIDs) == 0 {
+		changes.Cursor = c.String()
+		return changes, nil
+	}
+
+	tasks := []*Task{}
+	err = s.
+		Where(builder.And(builder.In("project_id", projectIDs), c.after(time.Now()))).
+		OrderBy("updated asc, id asc").
+		Limit(limit + 1).
+		Find(&tasks)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		changes.HasMore = true
+	}
+
+	err = s.
+		Where("kind = ? AND id > ?", EntityTombstoneKindTask, c.Tombstone).
+		In("project_id", projectIDs).
+		OrderBy("id asc").
+		Limit(limit + 1).
+		Find(&changes.Deleted)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes.Deleted) > limit {
+		changes.Deleted = changes.Deleted[:limit]
+		changes.HasMore = true
+	}
+
+	if len(tasks) > 0 {
+		last := tasks[len(tasks)-1]
+		c.Updated = last.Updated.Unix()
+		c.ID = last.ID
+
+		taskMap := make(map[int64]*Task, len(tasks))
+		for _, t := range tasks {
+			taskMap[t.ID] = t
+		}
+		err = addMoreInfoToTasks(s, taskMap, a)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			changes.Tasks = append(changes.Tasks, taskMap[t.ID])
+		}
+	}
+	if len(changes.Deleted) > 0 {
+		c.Tombstone = changes.Deleted[len(changes.Deleted)-1].TombstoneID
+	}
+
+	changes.Cursor = c.String()
+	return changes, nil
+}
+
+// GetProjectChanges returns up to limit projects the user has access to which changed after the cursor and up to limit
+// projects which were deleted after it. An empty cursor returns all projects from the beginning.
+func GetProjectChanges(s *xorm.Session, a web.Auth, cursor string, limit int) (changes *ProjectChanges, err error) {
+	c, err := parseChangesCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := getReadableProjects(s, a)
+	if err != nil {
+		return nil, err
+	}
+
+	changes = &ProjectChanges{
+		Projects: []*Project{},
+		Deleted:  []*EntityTombstone{},
+	}
+
+	// Projects are already loaded with all access checks, which is why the changes are filtered here instead of in the db
+	currentSecond := time.Now().Truncate(time.Second)
+	for _, p := range projects {
+		updated := p.Updated.Unix()
+		if !p.Updated.Before(currentSecond) {
+			continue
+		}
+		if updated > c.Updated || (updated == c.Updated && p.ID > c.ID) {
+			changes.Projects = append(changes.Projects, p)
+		}
+	}
+	sort.Slice(changes.Projects, func(i, j int) bool {
+		if changes.Projects[i].Updated.Unix() == changes.Projects[j].Updated.Unix() {
+			return changes.Projects[i].ID < changes.Projects[j].ID
+		}
+		return changes.Projects[i].Updated.Before(changes.Projects[j].Updated)
+	})
+	if len(changes.Projects) > limit {
+		changes.Projects = changes.Projects[:limit]
+		changes.HasMore = true
+	}
+
+	if _, is := a.(*LinkSharing); !is {
+		err = s.
+			Where("kind = ? AND user_id = ? AND id > ?", EntityTombstoneKindProject, a.GetID(), c.Tombstone).
+			OrderBy("id asc").
+			Limit(limit + 1).
+			Find(&changes.Deleted)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes.Deleted) > limit {
+			changes.Deleted = changes.Deleted[:limit]
+			changes.HasMore = true
+		}
+	}
+
+	if len(changes.Projects) > 0 {
+		last := changes.Projects[len(changes.Projects)-1]
+		c.Updated = last.Updated.Unix()
+		c.ID = last.ID
+
+		err = addProjectDetails(s, changes.Projects, a)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(changes.Deleted) > 0 {
+		c.Tombstone = changes.Deleted[len(changes.Deleted)-1].TombstoneID
+	}
+
+	changes.Cursor = c.String()
+	return changes, nil
+}
@@ -0,0 +1,191 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskChanges(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("all pages", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		seen := map[int64]bool{}
+		deleted := []int64{}
+		cursor := ""
+		for i := 0; i < 100; i++ {
+			changes, err := GetTaskChanges(s, u, cursor, 5)
+			require.NoError(t, err)
+			for _, task := range changes.Tasks {
+				assert.False(t, seen[task.ID], "task %d returned twice", task.ID)
+				seen[task.ID] = true
+			}
+			for _, tombstone := range changes.Deleted {
+				deleted = append(deleted, tombstone.ID)
+			}
+			cursor = changes.Cursor
+			if !changes.HasMore {
+				break
+			}
+		}
+
+		assert.True(t, seen[1])
+		assert.True(t, seen[2])
+		// Task 13 belongs to project 2 which user 1 has no access to
+		assert.False(t, seen[13])
+		assert.Equal(t, []int64{500}, deleted)
+
+		// Nothing changed since
+		changes, err := GetTaskChanges(s, u, cursor, 5)
+		require.NoError(t, err)
+		assert.Empty(t, changes.Tasks)
+		assert.Empty(t, changes.Deleted)
+		assert.False(t, changes.HasMore)
+		assert.Equal(t, cursor, changes.Cursor)
+	})
+	t.Run("changes after cursor", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		changes, err := GetTaskChanges(s, u, "", 1000)
+		require.NoError(t, err)
+		require.False(t, changes.HasMore)
+		cursor := changes.Cursor
+
+		_, err = s.
+			Where("id = ?", 3).
+			NoAutoTime().
+			Cols("updated").
+			Update(&Task{Updated: time.Now().Add(-time.Hour)})
+		require.NoError(t, err)
+
+		task := &Task{ID: 4}
+		err = task.Delete(s, u)
+		require.NoError(t, err)
+
+		changes, err = GetTaskChanges(s, u, cursor, 1000)
+		require.NoError(t, err)
+		require.Len(t, changes.Tasks, 1)
+		assert.Equal(t, int64(3), changes.Tasks[0].ID)
+		require.Len(t, changes.Deleted, 1)
+		assert.Equal(t, EntityTombstoneKindTask, changes.Deleted[0].Kind)
+		assert.Equal(t, int64(4), changes.Deleted[0].ID)
+		assert.Equal(t, int64(1), changes.Deleted[0].ProjectID)
+	})
+	t.Run("invalid cursor", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := GetTaskChanges(s, u, "not a cursor", 10)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidChangesCursor(err))
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		changes, err := GetTaskChanges(s, &LinkSharing{ID: 1, ProjectID: 1, Right: RightRead}, "", 1000)
+		require.NoError(t, err)
+		require.NotEmpty(t, changes.Tasks)
+		for _, task := range changes.Tasks {
+			assert.Equal(t, int64(1), task.ProjectID)
+		}
+	})
+	t.Run("api token restricted to a project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		token := &user.User{ID: 1, AuthenticatedWithAPIToken: true, APITokenProjectIDs: []int64{1}}
+		changes, err := GetTaskChanges(s, token, "", 1000)
+		require.NoError(t, err)
+		require.NotEmpty(t, changes.Tasks)
+		for _, task := range changes.Tasks {
+			assert.Equal(t, int64(1), task.ProjectID)
+		}
+	})
+}
+
+func TestGetProjectChanges(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("all", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		changes, err := GetProjectChanges(s, u, "", 1000)
+		require.NoError(t, err)
+		assert.False(t, changes.HasMore)
+		ids := map[int64]bool{}
+		for _, p := range changes.Projects {
+			ids[p.ID] = true
+		}
+		assert.True(t, ids[1])
+		assert.False(t, ids[2])
+		require.Len(t, changes.Deleted, 1)
+		assert.Equal(t, int64(600), changes.Deleted[0].ID)
+
+		changes, err = GetProjectChanges(s, u, changes.Cursor, 1000)
+		require.NoError(t, err)
+		assert.Empty(t, changes.Projects)
+		assert.Empty(t, changes.Deleted)
+	})
+	t.Run("deleted project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		changes, err := GetProjectChanges(s, u, "", 1000)
+		require.NoError(t, err)
+
+		project := &Project{ID: 1}
+		err = project.Delete(s, u)
+		require.NoError(t, err)
+
+		changes, err = GetProjectChanges(s, u, changes.Cursor, 1000)
+		require.NoError(t, err)
+		require.Len(t, changes.Deleted, 1)
+		assert.Equal(t, EntityTombstoneKindProject, changes.Deleted[0].Kind)
+		assert.Equal(t, int64(1), changes.Deleted[0].ID)
+	})
+	t.Run("api token restricted to a project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		token := &user.User{ID: 1, AuthenticatedWithAPIToken: true, APITokenProjectIDs: []int64{1}}
+		changes, err := GetProjectChanges(s, token, "", 1000)
+		require.NoError(t, err)
+		require.Len(t, changes.Projects, 1)
+		assert.Equal(t, int64(1), changes.Projects[0].ID)
+	})
+}
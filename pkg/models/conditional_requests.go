@@ -0,0 +1,79 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+// UnmodifiedSinceSetter is implemented by all entities whose update can be made conditional with an
+// If-Unmodified-Since header.
+type UnmodifiedSinceSetter interface {
+	SetUnmodifiedSince(since time.Time)
+}
+
+// SetUnmodifiedSince makes the next update of the task fail if the task was changed after the passed time.
+func (t *Task) SetUnmodifiedSince(since time.Time) {
+	t.unmodifiedSince = since
+}
+
+// SetUnmodifiedSince makes the next update of the project fail if the project was changed after the passed time.
+func (p *Project) SetUnmodifiedSince(since time.Time) {
+	p.unmodifiedSince = since
+}
+
+// SetUnmodifiedSince makes the next update of the bucket fail if the bucket was changed after the passed time.
+func (b *Bucket) SetUnmodifiedSince(since time.Time) {
+	b.unmodifiedSince = since
+}
+
+// CheckUnmodifiedSince returns an error if the task, project or bucket with the id was changed after the passed time.
+// The updates of these entities call it first, which means after the rights check and in the same transaction as
+// the update. The row of the entity is locked until the end of the transaction so that no other update can happen
+// in between.
+// Since http dates have a precision of one second, changes in the same second as the passed time are not detected.
+// If the time is zero, nothing is checked. If the entity does not exist, it is up to the update to handle that.
+func CheckUnmodifiedSince(s *xorm.Session, bean interface{}, id int64, since time.Time) error {
+	if since.IsZero() {
+		return nil
+	}
+
+	query := "SELECT `updated` FROM `" + s.Engine().TableName(bean) + "` WHERE `id` = ?"
+	if db.Type() != schemas.SQLITE {
+		// Sqlite does not support row locks and serializes all writes instead
+		query += " FOR UPDATE"
+	}
+
+	entity := struct {
+		Updated time.Time `xorm:"'updated'"`
+	}{}
+	exists, err := s.SQL(query, id).Get(&entity)
+	if err != nil || !exists {
+		return err
+	}
+
+	if entity.Updated.Truncate(time.Second).After(since) {
+		return &ErrModifiedSince{Since: since, Modified: entity.Updated}
+	}
+
+	return nil
+}
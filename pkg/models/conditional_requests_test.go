@@ -0,0 +1,139 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUnmodifiedSince(t *testing.T) {
+	taskUpdated := time.Date(2018, 12, 1, 1, 12, 4, 0, time.Local)
+	bucketUpdated := time.Date(2020, 4, 18, 21, 13, 52, 0, time.Local)
+
+	t.Run("unmodified", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := CheckUnmodifiedSince(s, &Task{}, 1, taskUpdated)
+		assert.NoError(t, err)
+	})
+	t.Run("modified", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := CheckUnmodifiedSince(s, &Task{}, 1, taskUpdated.Add(-time.Hour))
+		assert.Error(t, err)
+		assert.True(t, IsErrModifiedSince(err))
+	})
+	t.Run("bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := CheckUnmodifiedSince(s, &Bucket{}, 1, bucketUpdated.Add(-time.Hour))
+		assert.Error(t, err)
+		assert.True(t, IsErrModifiedSince(err))
+	})
+	t.Run("in the transaction of the update", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		require.NoError(t, s.Begin())
+
+		err := CheckUnmodifiedSince(s, &Task{}, 1, taskUpdated)
+		require.NoError(t, err)
+		_, err = s.ID(1).Cols("title").Update(&Task{Title: "changed"})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		err = CheckUnmodifiedSince(s, &Task{}, 1, taskUpdated)
+		assert.True(t, IsErrModifiedSince(err))
+	})
+	t.Run("nonexistent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := CheckUnmodifiedSince(s, &Task{}, 9999, taskUpdated.Add(-time.Hour))
+		assert.NoError(t, err)
+	})
+	t.Run("no time", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := CheckUnmodifiedSince(s, &Task{}, 1, time.Time{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestConditionalUpdate(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1, Title: "changed", ProjectID: 1}
+		task.SetUnmodifiedSince(time.Date(2018, 12, 1, 0, 0, 0, 0, time.Local))
+		err := task.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrModifiedSince(err))
+	})
+	t.Run("project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project := &Project{ID: 1, Title: "changed"}
+		project.SetUnmodifiedSince(time.Date(2018, 12, 1, 0, 0, 0, 0, time.Local))
+		err := project.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrModifiedSince(err))
+	})
+	t.Run("bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bucket := &Bucket{ID: 1, Title: "changed", ProjectID: 1}
+		bucket.SetUnmodifiedSince(time.Date(2020, 4, 18, 0, 0, 0, 0, time.Local))
+		err := bucket.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrModifiedSince(err))
+	})
+	t.Run("unmodified", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1, Title: "changed", ProjectID: 1}
+		task.SetUnmodifiedSince(time.Now())
+		err := task.Update(s, u)
+		require.NoError(t, err)
+	})
+}
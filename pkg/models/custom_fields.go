@@ -0,0 +1,354 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// CustomFieldType defines which kind of values a custom field holds
+type CustomFieldType string
+
+const (
+	// CustomFieldTypeText holds arbitrary text.
+	CustomFieldTypeText CustomFieldType = "text"
+	// CustomFieldTypeNumber holds a number.
+	CustomFieldTypeNumber CustomFieldType = "number"
+	// CustomFieldTypeDate holds a date, formatted as ISO 8601.
+	CustomFieldTypeDate CustomFieldType = "date"
+	// CustomFieldTypeSelect holds one of the options of the field.
+	CustomFieldTypeSelect CustomFieldType = "select"
+	// CustomFieldTypeMultiSelect holds any number of the options of the field.
+	CustomFieldTypeMultiSelect CustomFieldType = "multiselect"
+	// CustomFieldTypeCheckbox holds true or false.
+	CustomFieldTypeCheckbox CustomFieldType = "checkbox"
+	// CustomFieldTypeURL holds an http or https url.
+	CustomFieldTypeURL CustomFieldType = "url"
+)
+
+func (t CustomFieldType) isValid() bool {
+	switch t {
+	case CustomFieldTypeText,
+		CustomFieldTypeNumber,
+		CustomFieldTypeDate,
+		CustomFieldTypeSelect,
+		CustomFieldTypeMultiSelect,
+		CustomFieldTypeCheckbox,
+		CustomFieldTypeURL:
+		return true
+	}
+	return false
+}
+
+func (t CustomFieldType) hasOptions() bool {
+	return t == CustomFieldTypeSelect || t == CustomFieldTypeMultiSelect
+}
+
+// CustomField defines an additional field all tasks of a project can have a value for
+type CustomField struct {
+	// The unique, numeric id of this custom field.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"customfield"`
+	// The project this custom field belongs to.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id" param:"project"`
+	// The title of this custom field.
+	Title string `xorm:"text not null" valid:"required" minLength:"1" json:"title"`
+	// The type of this custom field. Can be `text`, `number`, `date`, `select`, `multiselect`, `checkbox` or `url`.
+	// The type cannot be changed once the field was created.
+	Type CustomFieldType `xorm:"varchar(50) not null" json:"type"`
+	// The options a user can choose from. Only used for `select` and `multiselect` fields.
+	// Removing an option also removes it from all tasks which have it set.
+	Options []string `xorm:"JSON null" json:"options"`
+	// The position of this custom field in relation to the other fields of the project.
+	Position float64 `xorm:"double null" json:"position"`
+
+	// The user who initially created the custom field.
+	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this custom field was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this custom field was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for custom fields
+func (*CustomField) TableName() string {
+	return "custom_fields"
+}
+
+func getCustomFieldByID(s *xorm.Session, id int64) (cf *CustomField, err error) {
+	cf = &CustomField{}
+	exists, err := s.
+		Where("id = ?", id).
+		Get(cf)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrCustomFieldDoesNotExist{CustomFieldID: id}
+	}
+	return cf, nil
+}
+
+func getCustomFieldsForProjects(s *xorm.Session, projectIDs []int64) (fields []*CustomField, err error) {
+	fields = []*CustomField{}
+	if len(projectIDs) == 0 {
+		return
+	}
+	err = s.
+		In("project_id", projectIDs).
+		OrderBy("position asc, id asc").
+		Find(&fields)
+	return
+}
+
+func (cf *CustomField) validate() error {
+	if !cf.Type.isValid() {
+		return &ErrInvalidCustomFieldType{Type: cf.Type}
+	}
+
+	if !cf.Type.hasOptions() {
+		cf.Options = nil
+		return nil
+	}
+
+	if len(cf.Options) == 0 {
+		return &ErrInvalidCustomFieldOptions{CustomFieldID: cf.ID}
+	}
+	seen := make(map[string]bool, len(cf.Options))
+	for _, option := range cf.Options {
+		if option == "" || seen[option] {
+			return &ErrInvalidCustomFieldOptions{CustomFieldID: cf.ID}
+		}
+		seen[option] = true
+	}
+
+	return nil
+}
+
+// Create creates a new custom field
+// @Summary Create a custom field
+// @Description Creates a new custom field for all tasks in a project.
+// @tags custom fields
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param field body models.CustomField true "The custom field"
+// @Success 201 {object} models.CustomField "The created custom field."
+// @Failure 400 {object} web.HTTPError "Invalid custom field provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/customfields [put]
+func (cf *CustomField) Create(s *xorm.Session, a web.Auth) (err error) {
+	cf.ID = 0
+	err = cf.validate()
+	if err != nil {
+		return
+	}
+
+	cf.CreatedBy, err = GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return
+	}
+	cf.CreatedByID = cf.CreatedBy.ID
+
+	_, err = s.Insert(cf)
+	if err != nil {
+		return
+	}
+
+	cf.Position = calculateDefaultPosition(cf.ID, cf.Position)
+	_, err = s.
+		Where("id = ?", cf.ID).
+		Cols("position").
+		Update(cf)
+	return
+}
+
+// ReadAll returns all custom fields of a project
+// @Summary Get all custom fields of a project
+// @Description Returns all custom fields of a project, sorted by their position.
+// @tags custom fields
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {array} models.CustomField "The custom fields."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/customfields [get]
+func (cf *CustomField) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	project := &Project{ID: cf.ProjectID}
+	canRead, _, err := project.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	fields, err := getCustomFieldsForProjects(s, []int64{cf.ProjectID})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addCreatorsToCustomFields(s, fields)
+	return fields, len(fields), int64(len(fields)), err
+}
+
+// ReadOne returns one custom field
+// @Summary Get one custom field
+// @Description Returns one custom field of a project.
+// @tags custom fields
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param fieldID path int true "Custom field ID"
+// @Success 200 {object} models.CustomField "The custom field."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The custom field does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/customfields/{fieldID} [get]
+func (cf *CustomField) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	field, err := getCustomFieldByID(s, cf.ID)
+	if err != nil {
+		return err
+	}
+	*cf = *field
+	return addCreatorsToCustomFields(s, []*CustomField{cf})
+}
+
+// Update updates a custom field
+// @Summary Update a custom field
+// @Description Updates the title, options and position of a custom field. The type of a field cannot be changed.
+// @tags custom fields
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param fieldID path int true "Custom field ID"
+// @Param field body models.CustomField true "The custom field"
+// @Success 200 {object} models.CustomField "The updated custom field."
+// @Failure 400 {object} web.HTTPError "Invalid custom field provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The custom field does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/customfields/{fieldID} [post]
+func (cf *CustomField) Update(s *xorm.Session, _ web.Auth) (err error) {
+	old, err := getCustomFieldByID(s, cf.ID)
+	if err != nil {
+		return err
+	}
+
+	if cf.Type == "" {
+		cf.Type = old.Type
+	}
+	if cf.Type != old.Type {
+		return &ErrInvalidCustomFieldType{Type: cf.Type}
+	}
+
+	err = cf.validate()
+	if err != nil {
+		return
+	}
+
+	cf.Position = calculateDefaultPosition(cf.ID, cf.Position)
+	_, err = s.
+		Where("id = ?", cf.ID).
+		Cols("title", "options", "position").
+		Update(cf)
+	if err != nil {
+		return
+	}
+
+	if cf.Type.hasOptions() {
+		err = removeStaleCustomFieldOptions(s, cf)
+		if err != nil {
+			return
+		}
+	}
+
+	return cf.ReadOne(s, nil)
+}
+
+// Delete deletes a custom field and all values tasks have for it
+// @Summary Delete a custom field
+// @Description Deletes a custom field and the values of all tasks for it.
+// @tags custom fields
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param fieldID path int true "Custom field ID"
+// @Success 200 {object} models.Message "The custom field was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The custom field does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/customfields/{fieldID} [delete]
+func (cf *CustomField) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("custom_field_id = ?", cf.ID).Delete(&TaskCustomFieldValue{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("id = ?", cf.ID).Delete(&CustomField{})
+	return
+}
+
+func addCreatorsToCustomFields(s *xorm.Session, fields []*CustomField) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(fields))
+	for _, cf := range fields {
+		userIDs = append(userIDs, cf.CreatedByID)
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, cf := range fields {
+		cf.CreatedBy = users[cf.CreatedByID]
+	}
+
+	return nil
+}
+
+// deleteCustomFieldsForProject removes all custom fields of a project together with their values.
+func deleteCustomFieldsForProject(s *xorm.Session, projectID int64) (err error) {
+	_, err = s.
+		Where(builder.In("custom_field_id", builder.Select("id").From("custom_fields").Where(builder.Eq{"project_id": projectID}))).
+		Delete(&TaskCustomFieldValue{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", projectID).Delete(&CustomField{})
+	return
+}
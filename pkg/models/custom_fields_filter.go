@@ -0,0 +1,206 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"xorm.io/builder"
+)
+
+// Filters like `custom_fields.3 = foo` filter by the value of the custom field with the id 3
+const customFieldFilterPrefix = "custom_fields."
+
+// customFieldFilter holds the value of a filter on a custom field in all representations it can be compared as.
+// Because the type of the field is only known in the database, the filter is applied to all typed value columns
+// of the field the value can be parsed into.
+type customFieldFilter struct {
+	fieldID int64
+	strings []string
+	numbers []float64
+	dates   []time.Time
+	bools   []bool
+}
+
+var customFieldDateType, _ = reflect.TypeOf(Task{}).FieldByName("DueDate")
+
+func parseCustomFieldFilterValue(f *taskFilter, rawValue string, loc *time.Location) (cf *customFieldFilter, err error) {
+	fieldID, err := strconv.ParseInt(strings.TrimPrefix(f.field, customFieldFilterPrefix), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidTaskField{TaskField: f.field}
+	}
+
+	cf = &customFieldFilter{fieldID: fieldID}
+
+	rawValues := []string{rawValue}
+	if f.comparator == taskFilterComparatorIn {
+		rawValues = splitFilterValues(rawValue)
+	}
+
+	for _, raw := range rawValues {
+		raw = strings.TrimSpace(raw)
+		cf.strings = append(cf.strings, raw)
+
+		if number, err := strconv.ParseFloat(raw, 64); err == nil {
+			cf.numbers = append(cf.numbers, number)
+		}
+		if checked, err := strconv.ParseBool(raw); err == nil {
+			cf.bools = append(cf.bools, checked)
+		}
+		if date, err := getValueForField(customFieldDateType, raw, loc); err == nil {
+			cf.dates = append(cf.dates, date.(time.Time))
+		}
+	}
+
+	return cf, nil
+}
+
+func hasCustomFieldFilter(filters []*taskFilter) bool {
+	for _, f := range filters {
+		if nested, is := f.value.([]*taskFilter); is && hasCustomFieldFilter(nested) {
+			return true
+		}
+		if _, is := f.value.(*customFieldFilter); is {
+			return true
+		}
+	}
+	return false
+}
+
+// getTypedValueCond returns the filter condition for one value column or nil if the filter can't be applied to it.
+func getTypedValueCond[T any](column string, values []T, comparator taskFilterComparator, allowedComparators ...taskFilterComparator) builder.Cond {
+	if len(values) == 0 {
+		return nil
+	}
+
+	var value interface{} = values[0]
+	if comparator == taskFilterComparatorIn {
+		value = values
+	}
+
+	for _, allowed := range allowedComparators {
+		if allowed != comparator {
+			continue
+		}
+		cond, err := getFilterCond(&taskFilter{
+			field:      column,
+			value:      value,
+			comparator: comparator,
+		}, false)
+		if err != nil {
+			return nil
+		}
+		return cond
+	}
+	return nil
+}
+
+func getMultiSelectValueCond(values []string, comparator taskFilterComparator) builder.Cond {
+	switch comparator {
+	case taskFilterComparatorLike:
+		return &builder.Like{"string_value", "%" + values[0] + "%"}
+	case taskFilterComparatorEquals, taskFilterComparatorNotEquals, taskFilterComparatorIn:
+		conds := make([]builder.Cond, 0, len(values))
+		for _, value := range values {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			conds = append(conds, &builder.Like{"string_value", "%" + string(encoded) + "%"})
+		}
+		if comparator == taskFilterComparatorNotEquals {
+			return builder.Not{builder.Or(conds...)}
+		}
+		return builder.Or(conds...)
+	default:
+		return nil
+	}
+}
+
+func getCustomFieldFilterCond(cf *customFieldFilter, comparator taskFilterComparator, includeNulls bool) builder.Cond {
+	allComparators := []taskFilterComparator{
+		taskFilterComparatorEquals,
+		taskFilterComparatorNotEquals,
+		taskFilterComparatorGreater,
+		taskFilterComparatorGreateEquals,
+		taskFilterComparatorLess,
+		taskFilterComparatorLessEquals,
+		taskFilterComparatorIn,
+	}
+	typedConds := []struct {
+		types []CustomFieldType
+		cond  builder.Cond
+	}{
+		{
+			types: []CustomFieldType{CustomFieldTypeText, CustomFieldTypeURL, CustomFieldTypeSelect},
+			cond:  getTypedValueCond("string_value", cf.strings, comparator, append(allComparators, taskFilterComparatorLike)...),
+		},
+		{
+			types: []CustomFieldType{CustomFieldTypeMultiSelect},
+			cond:  getMultiSelectValueCond(cf.strings, comparator),
+		},
+		{
+			types: []CustomFieldType{CustomFieldTypeNumber},
+			cond:  getTypedValueCond("number_value", cf.numbers, comparator, allComparators...),
+		},
+		{
+			types: []CustomFieldType{CustomFieldTypeDate},
+			cond:  getTypedValueCond("date_value", cf.dates, comparator, allComparators...),
+		},
+		{
+			types: []CustomFieldType{CustomFieldTypeCheckbox},
+			cond:  getTypedValueCond("bool_value", cf.bools, comparator, taskFilterComparatorEquals, taskFilterComparatorNotEquals),
+		},
+	}
+
+	// Each condition is only applied to values of fields with a matching type
+	valueConds := []builder.Cond{}
+	for _, typed := range typedConds {
+		if typed.cond == nil {
+			continue
+		}
+		valueConds = append(valueConds, builder.And(
+			builder.In("custom_field_id", builder.Select("id").From("custom_fields").Where(builder.In("type", typed.types))),
+			typed.cond,
+		))
+	}
+
+	var valueCond builder.Cond = builder.Expr("1 = 0")
+	if len(valueConds) > 0 {
+		valueCond = builder.Or(valueConds...)
+	}
+
+	cond := getFilterCondForSeparateTable("task_custom_field_values", builder.And(
+		builder.Eq{"custom_field_id": cf.fieldID},
+		valueCond,
+	))
+
+	if includeNulls {
+		cond = builder.Or(cond, builder.NotIn("id",
+			builder.
+				Select("task_id").
+				From("task_custom_field_values").
+				Where(builder.Eq{"custom_field_id": cf.fieldID}),
+		))
+	}
+
+	return cond
+}
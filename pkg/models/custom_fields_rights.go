@@ -0,0 +1,75 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see a custom field
+func (cf *CustomField) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	field, err := cf.getForProject(s)
+	if err != nil {
+		return false, 0, err
+	}
+
+	project := &Project{ID: field.ProjectID}
+	return project.CanRead(s, a)
+}
+
+// CanCreate checks if the user can create a custom field in a project
+func (cf *CustomField) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if getSavedFilterIDFromProjectID(cf.ProjectID) > 0 {
+		return false, nil
+	}
+
+	project := &Project{ID: cf.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// CanUpdate checks if the user can update a custom field
+func (cf *CustomField) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return cf.canDoCustomField(s, a)
+}
+
+// CanDelete checks if the user can delete a custom field
+func (cf *CustomField) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return cf.canDoCustomField(s, a)
+}
+
+func (cf *CustomField) canDoCustomField(s *xorm.Session, a web.Auth) (bool, error) {
+	field, err := cf.getForProject(s)
+	if err != nil {
+		return false, err
+	}
+
+	project := &Project{ID: field.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// getForProject returns the custom field and makes sure it belongs to the project from the request
+func (cf *CustomField) getForProject(s *xorm.Session) (*CustomField, error) {
+	field, err := getCustomFieldByID(s, cf.ID)
+	if err != nil {
+		return nil, err
+	}
+	if cf.ProjectID != 0 && field.ProjectID != cf.ProjectID {
+		return nil, &ErrCustomFieldDoesNotExist{CustomFieldID: cf.ID}
+	}
+	return field, nil
+}
@@ -0,0 +1,458 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomField_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		cf := &CustomField{
+			ProjectID: 1,
+			Title:     "Due in sprint",
+			Type:      CustomFieldTypeDate,
+			Options:   []string{"ignored"},
+		}
+		can, err := cf.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = cf.Create(s, u)
+		require.NoError(t, err)
+		assert.Nil(t, cf.Options)
+		assert.Equal(t, int64(1), cf.CreatedBy.ID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "custom_fields", map[string]interface{}{
+			"id":         cf.ID,
+			"project_id": 1,
+			"title":      "Due in sprint",
+			"type":       "date",
+		}, false)
+	})
+	t.Run("invalid type", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		cf := &CustomField{
+			ProjectID: 1,
+			Title:     "Lorem",
+			Type:      "color",
+		}
+		err := cf.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidCustomFieldType(err))
+	})
+	t.Run("select without options", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		cf := &CustomField{
+			ProjectID: 1,
+			Title:     "Lorem",
+			Type:      CustomFieldTypeSelect,
+		}
+		err := cf.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidCustomFieldOptions(err))
+	})
+	t.Run("duplicate options", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		cf := &CustomField{
+			ProjectID: 1,
+			Title:     "Lorem",
+			Type:      CustomFieldTypeMultiSelect,
+			Options:   []string{"a", "a"},
+		}
+		err := cf.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidCustomFieldOptions(err))
+	})
+	t.Run("no access to the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		cf := &CustomField{ProjectID: 2}
+		can, err := cf.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("saved filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		cf := &CustomField{ProjectID: -2}
+		can, err := cf.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestCustomField_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	cf := &CustomField{ProjectID: 1}
+	result, _, total, err := cf.ReadAll(s, &user.User{ID: 1}, "", 1, 50)
+	require.NoError(t, err)
+	fields := result.([]*CustomField)
+	assert.Len(t, fields, 4)
+	assert.Equal(t, int64(4), total)
+	assert.Equal(t, int64(1), fields[0].ID)
+	assert.Equal(t, []string{"S", "M", "L"}, fields[2].Options)
+
+	_, _, _, err = (&CustomField{ProjectID: 2}).ReadAll(s, &user.User{ID: 1}, "", 1, 50)
+	require.Error(t, err)
+	assert.True(t, IsErrGenericForbidden(err))
+}
+
+func TestCustomField_CanRead(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	can, _, err := (&CustomField{ID: 1, ProjectID: 1}).CanRead(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.True(t, can)
+
+	can, _, err = (&CustomField{ID: 5, ProjectID: 2}).CanRead(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.False(t, can)
+
+	_, _, err = (&CustomField{ID: 1, ProjectID: 2}).CanRead(s, &user.User{ID: 3})
+	require.Error(t, err)
+	assert.True(t, IsErrCustomFieldDoesNotExist(err))
+}
+
+func TestCustomField_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		cf := &CustomField{
+			ID:        1,
+			ProjectID: 1,
+			Title:     "Client",
+		}
+		err := cf.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, CustomFieldTypeText, cf.Type)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "custom_fields", map[string]interface{}{
+			"id":    1,
+			"title": "Client",
+			"type":  "text",
+		}, false)
+	})
+	t.Run("change type", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		cf := &CustomField{
+			ID:    1,
+			Title: "Customer",
+			Type:  CustomFieldTypeNumber,
+		}
+		err := cf.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidCustomFieldType(err))
+	})
+	t.Run("removed options are removed from tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1, ProjectID: 1, CustomFields: map[int64]interface{}{3: "M"}}
+		err := task.setCustomFieldValues(s)
+		require.NoError(t, err)
+
+		cf := &CustomField{
+			ID:      3,
+			Title:   "Size",
+			Options: []string{"S", "L", "XL"},
+		}
+		err = cf.Update(s, u)
+		require.NoError(t, err)
+
+		cf = &CustomField{
+			ID:      4,
+			Title:   "Platforms",
+			Options: []string{"web", "desktop"},
+		}
+		err = cf.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_custom_field_values", map[string]interface{}{
+			"task_id":         1,
+			"custom_field_id": 3,
+		})
+		db.AssertExists(t, "task_custom_field_values", map[string]interface{}{
+			"id":           4,
+			"string_value": `["web"]`,
+		}, false)
+	})
+}
+
+func TestCustomField_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	cf := &CustomField{ID: 2, ProjectID: 1}
+	can, err := cf.CanDelete(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.True(t, can)
+	err = cf.Delete(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "custom_fields", map[string]interface{}{
+		"id": 2,
+	})
+	db.AssertMissing(t, "task_custom_field_values", map[string]interface{}{
+		"custom_field_id": 2,
+	})
+	db.AssertExists(t, "task_custom_field_values", map[string]interface{}{
+		"id": 1,
+	}, false)
+}
+
+func TestTask_CustomFields(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("create with values", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:     "Lorem",
+			ProjectID: 1,
+			CustomFields: map[int64]interface{}{
+				1: "Foo",
+				2: float64(3.5),
+				3: "L",
+				4: []interface{}{"desktop", "web", "web"},
+			},
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"desktop", "web"}, task.CustomFields[4])
+
+		err = task.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]interface{}{
+			1: "Foo",
+			2: 3.5,
+			3: "L",
+			4: []string{"desktop", "web"},
+		}, task.CustomFields)
+	})
+	t.Run("update only changes the passed values", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:    1,
+			Title: "task #1",
+			CustomFields: map[int64]interface{}{
+				1: nil,
+				3: "S",
+			},
+		}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_custom_field_values", map[string]interface{}{
+			"task_id":         1,
+			"custom_field_id": 1,
+		})
+		db.AssertExists(t, "task_custom_field_values", map[string]interface{}{
+			"task_id":         1,
+			"custom_field_id": 2,
+		}, false)
+		db.AssertExists(t, "task_custom_field_values", map[string]interface{}{
+			"task_id":         1,
+			"custom_field_id": 3,
+			"string_value":    "S",
+		}, false)
+	})
+	t.Run("invalid values", func(t *testing.T) {
+		invalid := map[int64]interface{}{
+			1: float64(3),
+			2: "three",
+			3: "XXL",
+			4: "web",
+		}
+		for fieldID, value := range invalid {
+			db.LoadAndAssertFixtures(t)
+			s := db.NewSession()
+
+			task := &Task{
+				ID:           1,
+				Title:        "task #1",
+				CustomFields: map[int64]interface{}{fieldID: value},
+			}
+			err := task.Update(s, u)
+			require.Error(t, err)
+			assert.True(t, IsErrInvalidCustomFieldValue(err), "field %d", fieldID)
+			s.Close()
+		}
+	})
+	t.Run("url", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		cf := &CustomField{ID: 99, Type: CustomFieldTypeURL}
+		_, err := cf.newValue(1, "https://vikunja.io/docs")
+		require.NoError(t, err)
+		_, err = cf.newValue(1, "javascript:alert(1)")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidCustomFieldValue(err))
+	})
+	t.Run("field of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:           1,
+			Title:        "task #1",
+			CustomFields: map[int64]interface{}{5: true},
+		}
+		err := task.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrCustomFieldDoesNotExist(err))
+	})
+	t.Run("moving a task removes values of the old project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:           1,
+			Title:        "task #1",
+			ProjectID:    2,
+			CustomFields: map[int64]interface{}{5: true},
+		}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_custom_field_values", map[string]interface{}{
+			"task_id":         1,
+			"custom_field_id": 1,
+		})
+		db.AssertExists(t, "task_custom_field_values", map[string]interface{}{
+			"task_id":         1,
+			"custom_field_id": 5,
+			"bool_value":      true,
+		}, false)
+	})
+}
+
+func TestTaskCollection_ReadAll_CustomFields(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	tests := []struct {
+		name   string
+		filter string
+		nulls  bool
+		want   []int64
+	}{
+		{name: "text", filter: "custom_fields.1 = ACME", want: []int64{1}},
+		{name: "text like", filter: "custom_fields.1 ~ acm", want: []int64{1}},
+		{name: "number", filter: "custom_fields.2 > 6", want: []int64{2}},
+		{name: "number in", filter: "custom_fields.2 in 5, 8", want: []int64{1, 2}},
+		{name: "number in brackets", filter: "custom_fields.2 in [5, 8]", want: []int64{1, 2}},
+		{name: "text in brackets", filter: "custom_fields.1 in [ACME, foo]", want: []int64{1}},
+		{name: "multiselect", filter: "custom_fields.4 = mobile", want: []int64{2}},
+		{name: "text does not match number values", filter: "custom_fields.1 = 5", want: []int64{}},
+		{name: "combined", filter: "custom_fields.2 >= 5 && done = true", want: []int64{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.LoadAndAssertFixtures(t)
+			s := db.NewSession()
+			defer s.Close()
+
+			tc := &TaskCollection{
+				ProjectID:          1,
+				Filter:             tt.filter,
+				FilterIncludeNulls: tt.nulls,
+			}
+			result, _, _, err := tc.ReadAll(s, u, "", 0, 50)
+			require.NoError(t, err)
+
+			ids := []int64{}
+			for _, task := range result.([]*Task) {
+				ids = append(ids, task.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+
+	t.Run("invalid field", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{
+			ProjectID: 1,
+			Filter:    "custom_fields.foo = bar",
+		}
+		_, _, _, err := tc.ReadAll(s, u, "", 0, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskField(err))
+	})
+}
@@ -0,0 +1,302 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// digestMaxTasksPerSection limits how many tasks are listed in each section of a digest
+const digestMaxTasksPerSection = 50
+
+// digestActivity is a task in a subscribed project which changed since the last digest
+type digestActivity struct {
+	Task     *Task
+	Created  bool
+	Done     bool
+	Comments int64
+}
+
+// getUsersDueForDigest returns all users whose digest should be sent in the minute of now
+func getUsersDueForDigest(s *xorm.Session, now time.Time) (users []*user.User, err error) {
+	now = utils.GetTimeWithoutSeconds(now)
+	nextMinute := now.Add(1 * time.Minute)
+
+	candidates := []*user.User{}
+	err = s.
+		Where("digest_frequency IS NOT NULL AND digest_frequency != '' AND status != ?", user.StatusDisabled).
+		Find(&candidates)
+	if err != nil {
+		return
+	}
+
+	tzs := make(map[string]*time.Location)
+	for _, u := range candidates {
+		if u.Timezone == "" {
+			u.Timezone = config.GetTimeZone().String()
+		}
+
+		tz, exists := tzs[u.Timezone]
+		if !exists {
+			tz, err = time.LoadLocation(u.Timezone)
+			if err != nil {
+				return nil, err
+			}
+			tzs[u.Timezone] = tz
+		}
+
+		if u.DigestTime == "" {
+			u.DigestTime = user.DefaultDigestTime
+		}
+		tm, err := time.Parse("15:04", u.DigestTime)
+		if err != nil {
+			return nil, err
+		}
+
+		local := now.In(tz)
+		if u.DigestFrequency == user.DigestFrequencyWeekly && int(local.Weekday()) != u.WeekStart%7 {
+			continue
+		}
+
+		digestTime := time.Date(local.Year(), local.Month(), local.Day(), tm.Hour(), tm.Minute(), 0, 0, tz)
+		if !digestTime.Before(now) && digestTime.Before(nextMinute) {
+			users = append(users, u)
+		}
+	}
+
+	return
+}
+
+// getDigestForUser collects everything which goes into the digest of a user. The days are calculated in the time
+// zone of the user. It returns nil if there is nothing to tell the user about.
+func getDigestForUser(s *xorm.Session, u *user.User, now time.Time) (digest *DigestNotification, err error) {
+	tz := config.GetTimeZone()
+	if u.Timezone != "" {
+		tz, err = time.LoadLocation(u.Timezone)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	local := now.In(tz)
+	startOfDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, tz)
+	days := 1
+	if u.DigestFrequency == user.DigestFrequencyWeekly {
+		days = 7
+	}
+	endOfPeriod := startOfDay.AddDate(0, 0, days)
+	since := now.AddDate(0, 0, -days)
+
+	userTasks := builder.And(
+		builder.Eq{"tasks.done": false},
+		builder.Eq{"tasks.is_archived": false},
+		builder.Eq{"projects.is_archived": false},
+		builder.Or(
+			builder.Eq{"tasks.created_by_id": u.ID},
+			builder.In("tasks.id", builder.Select("task_id").From("task_assignees").Where(builder.Eq{"user_id": u.ID})),
+		),
+	)
+
+	due := []*Task{}
+	err = s.
+		Select("tasks.*").
+		Join("LEFT", "projects", "projects.id = tasks.project_id").
+		Where(userTasks).
+		And("tasks.due_date IS NOT NULL AND tasks.due_date >= ? AND tasks.due_date < ?", startOfDay, endOfPeriod).
+		OrderBy("tasks.due_date ASC, tasks.id ASC").
+		Limit(digestMaxTasksPerSection).
+		Find(&due)
+	if err != nil {
+		return nil, err
+	}
+
+	overdue := []*Task{}
+	err = s.
+		Select("tasks.*").
+		Join("LEFT", "projects", "projects.id = tasks.project_id").
+		Where(userTasks).
+		And("tasks.due_date IS NOT NULL AND tasks.due_date < ?", startOfDay).
+		OrderBy("tasks.due_date ASC, tasks.id ASC").
+		Limit(digestMaxTasksPerSection).
+		Find(&overdue)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := getDigestActivity(s, u, since)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(due) == 0 && len(overdue) == 0 && len(activity) == 0 {
+		return nil, nil
+	}
+
+	taskIDs := []int64{}
+	for _, t := range due {
+		taskIDs = append(taskIDs, t.ID)
+	}
+	for _, t := range overdue {
+		taskIDs = append(taskIDs, t.ID)
+	}
+	for _, a := range activity {
+		taskIDs = append(taskIDs, a.Task.ID)
+	}
+	projects, err := GetProjectsMapSimplByTaskIDs(s, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DigestNotification{
+		User:     u,
+		Weekly:   u.DigestFrequency == user.DigestFrequencyWeekly,
+		Now:      local,
+		Due:      due,
+		Overdue:  overdue,
+		Activity: activity,
+		Projects: projects,
+	}, nil
+}
+
+// getDigestActivity returns the tasks created, done or commented on since a time in all projects the user
+// subscribed to and still has access to.
+func getDigestActivity(s *xorm.Session, u *user.User, since time.Time) (activity []*digestActivity, err error) {
+	subscriptions := []*Subscription{}
+	err = s.
+		Where("user_id = ? AND entity_type = ?", u.ID, SubscriptionEntityProject).
+		Find(&subscriptions)
+	if err != nil || len(subscriptions) == 0 {
+		return nil, err
+	}
+
+	projectIDs := []int64{}
+	for _, sub := range subscriptions {
+		p := &Project{ID: sub.EntityID}
+		can, _, err := p.CanRead(s, u)
+		if err != nil && !IsErrProjectDoesNotExist(err) {
+			return nil, err
+		}
+		if can && !p.IsArchived {
+			projectIDs = append(projectIDs, p.ID)
+		}
+	}
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	commentCounts := []*taskSearchFacetCount{}
+	err = s.
+		Table("task_comments").
+		Select("task_id AS id, COUNT(*) AS count").
+		Where("created >= ?", since).
+		And(builder.In("task_id", builder.Select("id").From("tasks").Where(builder.In("project_id", projectIDs)))).
+		GroupBy("task_id").
+		Find(&commentCounts)
+	if err != nil {
+		return nil, err
+	}
+	comments := make(map[int64]int64, len(commentCounts))
+	for _, c := range commentCounts {
+		comments[c.ID] = c.Count
+	}
+
+	tasks := []*Task{}
+	err = s.
+		In("project_id", projectIDs).
+		And(builder.Or(
+			builder.Gte{"created": since},
+			builder.And(builder.Eq{"done": true}, builder.Gte{"done_at": since}),
+			builder.In("id", facetIDs(commentCounts)),
+		)).
+		OrderBy("updated DESC, id DESC").
+		Limit(digestMaxTasksPerSection).
+		Find(&tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tasks {
+		activity = append(activity, &digestActivity{
+			Task:     t,
+			Created:  !t.Created.Before(since),
+			Done:     t.Done && !t.DoneAt.Before(since),
+			Comments: comments[t.ID],
+		})
+	}
+
+	return
+}
+
+// RegisterDigestCron registers a function which checks every minute for users whose daily or weekly digest is due.
+func RegisterDigestCron() {
+	if !config.ServiceEnableEmailReminders.GetBool() {
+		return
+	}
+
+	if !config.MailerEnabled.GetBool() {
+		log.Info("Mailer is disabled, not sending digests per mail")
+		return
+	}
+
+	err := cron.Schedule("* * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		now := time.Now()
+		users, err := getUsersDueForDigest(s, now)
+		if err != nil {
+			log.Errorf("[Digest] Could not get users to send a digest to: %s", err)
+			return
+		}
+
+		log.Debugf("[Digest] Sending digests to %d users", len(users))
+
+		for _, u := range users {
+			digest, err := getDigestForUser(s, u, now)
+			if err != nil {
+				log.Errorf("[Digest] Could not get digest for user %d: %s", u.ID, err)
+				continue
+			}
+			if digest == nil {
+				log.Debugf("[Digest] Nothing to send to user %d", u.ID)
+				continue
+			}
+
+			err = notifications.Notify(u, digest)
+			if err != nil {
+				log.Errorf("[Digest] Could not notify user %d: %s", u.ID, err)
+				continue
+			}
+
+			log.Debugf("[Digest] Sent digest to user %d", u.ID)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register digest cron: %s", err)
+	}
+}
@@ -0,0 +1,167 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func digestTaskIDs(tasks []*Task) []int64 {
+	ids := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestGetUsersDueForDigest(t *testing.T) {
+	setDigest := func(t *testing.T, frequency string) {
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.
+			Where("id = ?", 1).
+			Cols("digest_frequency", "digest_time", "timezone").
+			Update(&user.User{DigestFrequency: frequency, DigestTime: "03:00", Timezone: "UTC"})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+	}
+	userIDs := func(users []*user.User) []int64 {
+		ids := []int64{}
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		return ids
+	}
+
+	t.Run("daily", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setDigest(t, user.DigestFrequencyDaily)
+		s := db.NewSession()
+		defer s.Close()
+
+		users, err := getUsersDueForDigest(s, time.Date(2018, 12, 1, 3, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, userIDs(users))
+
+		users, err = getUsersDueForDigest(s, time.Date(2018, 12, 1, 3, 1, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+	t.Run("weekly only on the first day of the week", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setDigest(t, user.DigestFrequencyWeekly)
+		s := db.NewSession()
+		defer s.Close()
+
+		// 2018-12-01 is a saturday, user 1 starts their week on sunday
+		users, err := getUsersDueForDigest(s, time.Date(2018, 12, 1, 3, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Empty(t, users)
+
+		users, err = getUsersDueForDigest(s, time.Date(2018, 12, 2, 3, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, userIDs(users))
+	})
+	t.Run("disabled", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		users, err := getUsersDueForDigest(s, time.Date(2018, 12, 1, 8, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+}
+
+func TestGetDigestForUser(t *testing.T) {
+	now := time.Date(2018, 12, 1, 3, 0, 0, 0, time.UTC)
+
+	t.Run("due and overdue tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1, Timezone: "UTC", DigestFrequency: user.DigestFrequencyDaily}
+		digest, err := getDigestForUser(s, u, now)
+		require.NoError(t, err)
+		require.NotNil(t, digest)
+		assert.False(t, digest.Weekly)
+		assert.Contains(t, digestTaskIDs(digest.Due), int64(5))
+		assert.NotContains(t, digestTaskIDs(digest.Due), int64(6))
+		assert.Contains(t, digestTaskIDs(digest.Overdue), int64(6))
+		assert.NotContains(t, digestTaskIDs(digest.Overdue), int64(38)) // Done
+		assert.Empty(t, digest.Activity)
+		assert.Contains(t, digest.Projects, int64(1))
+	})
+	t.Run("due today depends on the time zone", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// In New York it is still the 30th of november, task 6 is due today there.
+		u := &user.User{ID: 1, Timezone: "America/New_York", DigestFrequency: user.DigestFrequencyDaily}
+		digest, err := getDigestForUser(s, u, now)
+		require.NoError(t, err)
+		require.NotNil(t, digest)
+		assert.Contains(t, digestTaskIDs(digest.Due), int64(6))
+		assert.NotContains(t, digestTaskIDs(digest.Overdue), int64(6))
+	})
+	t.Run("activity in subscribed projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&Subscription{EntityType: SubscriptionEntityProject, EntityID: 1, UserID: 1})
+		require.NoError(t, err)
+
+		u := &user.User{ID: 1, Timezone: "UTC", DigestFrequency: user.DigestFrequencyDaily}
+		digest, err := getDigestForUser(s, u, now)
+		require.NoError(t, err)
+		require.NotNil(t, digest)
+
+		var task1 *digestActivity
+		for _, a := range digest.Activity {
+			assert.Equal(t, int64(1), a.Task.ProjectID)
+			if a.Task.ID == 1 {
+				task1 = a
+			}
+		}
+		require.NotNil(t, task1)
+		assert.True(t, task1.Created)
+		assert.False(t, task1.Done)
+
+		mail := digest.ToMail()
+		assert.NotNil(t, mail)
+	})
+	t.Run("nothing to send", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1, Timezone: "UTC", DigestFrequency: user.DigestFrequencyDaily}
+		digest, err := getDigestForUser(s, u, time.Date(2018, 1, 1, 3, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Nil(t, digest)
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/web"
@@ -96,6 +97,245 @@ func (err ErrInvalidData) HTTPError() web.HTTPError {
 	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeInvalidData, Message: err.Message}
 }
 
+// ErrInvalidChangesCursor represents an error where the cursor passed to a changes endpoint could not be decoded
+type ErrInvalidChangesCursor struct {
+	Cursor string
+}
+
+// IsErrInvalidChangesCursor checks if an error is ErrInvalidChangesCursor.
+func IsErrInvalidChangesCursor(err error) bool {
+	_, ok := err.(*ErrInvalidChangesCursor)
+	return ok
+}
+
+func (err *ErrInvalidChangesCursor) Error() string {
+	return fmt.Sprintf("Changes cursor is invalid [Cursor: %s]", err.Cursor)
+}
+
+// ErrCodeInvalidChangesCursor holds the unique world-error code of this error
+const ErrCodeInvalidChangesCursor = 2003
+
+// HTTPError holds the http error description
+func (err *ErrInvalidChangesCursor) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeInvalidChangesCursor, Message: "The cursor is invalid. Use the cursor returned by the last request or none to start from the beginning."}
+}
+
+// ErrInvalidEventStreamID represents an error where the last event id passed to the event stream could not be decoded
+type ErrInvalidEventStreamID struct {
+	EventID string
+}
+
+// IsErrInvalidEventStreamID checks if an error is ErrInvalidEventStreamID.
+func IsErrInvalidEventStreamID(err error) bool {
+	_, ok := err.(*ErrInvalidEventStreamID)
+	return ok
+}
+
+func (err *ErrInvalidEventStreamID) Error() string {
+	return fmt.Sprintf("Event stream id is invalid [EventID: %s]", err.EventID)
+}
+
+// ErrCodeInvalidEventStreamID holds the unique world-error code of this error
+const ErrCodeInvalidEventStreamID = 2004
+
+// HTTPError holds the http error description
+func (err *ErrInvalidEventStreamID) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeInvalidEventStreamID, Message: "The last event id is invalid. Use the id of the last event you received or none to only get new events."}
+}
+
+// ErrInvalidBatchOperation represents an error where an operation of a batch request is not supported or invalid
+type ErrInvalidBatchOperation struct {
+	Index   int
+	Method  string
+	Path    string
+	Message string
+}
+
+// IsErrInvalidBatchOperation checks if an error is ErrInvalidBatchOperation.
+func IsErrInvalidBatchOperation(err error) bool {
+	_, ok := err.(*ErrInvalidBatchOperation)
+	return ok
+}
+
+func (err *ErrInvalidBatchOperation) Error() string {
+	return fmt.Sprintf("Batch operation is invalid [Index: %d, Method: %s, Path: %s, Message: %s]", err.Index, err.Method, err.Path, err.Message)
+}
+
+// ErrCodeInvalidBatchOperation holds the unique world-error code of this error
+const ErrCodeInvalidBatchOperation = 2005
+
+// HTTPError holds the http error description
+func (err *ErrInvalidBatchOperation) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidBatchOperation,
+		Message:  fmt.Sprintf("Operation %d (%s %s) is invalid: %s", err.Index, err.Method, err.Path, err.Message),
+	}
+}
+
+// ErrTooManyBatchOperations represents an error where a batch request contains more operations than allowed
+type ErrTooManyBatchOperations struct {
+	Operations int
+	Max        int
+}
+
+// IsErrTooManyBatchOperations checks if an error is ErrTooManyBatchOperations.
+func IsErrTooManyBatchOperations(err error) bool {
+	_, ok := err.(*ErrTooManyBatchOperations)
+	return ok
+}
+
+func (err *ErrTooManyBatchOperations) Error() string {
+	return fmt.Sprintf("Too many batch operations [Operations: %d, Max: %d]", err.Operations, err.Max)
+}
+
+// ErrCodeTooManyBatchOperations holds the unique world-error code of this error
+const ErrCodeTooManyBatchOperations = 2006
+
+// HTTPError holds the http error description
+func (err *ErrTooManyBatchOperations) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTooManyBatchOperations,
+		Message:  fmt.Sprintf("A batch request can contain at most %d operations.", err.Max),
+	}
+}
+
+// ErrInvalidIdempotencyKey represents an error where an idempotency key is empty or too long
+type ErrInvalidIdempotencyKey struct{}
+
+// IsErrInvalidIdempotencyKey checks if an error is ErrInvalidIdempotencyKey.
+func IsErrInvalidIdempotencyKey(err error) bool {
+	_, ok := err.(*ErrInvalidIdempotencyKey)
+	return ok
+}
+
+func (err *ErrInvalidIdempotencyKey) Error() string {
+	return "Idempotency key is invalid"
+}
+
+// ErrCodeInvalidIdempotencyKey holds the unique world-error code of this error
+const ErrCodeInvalidIdempotencyKey = 2007
+
+// HTTPError holds the http error description
+func (err *ErrInvalidIdempotencyKey) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidIdempotencyKey,
+		Message:  fmt.Sprintf("The idempotency key must not be empty and at most %d characters long.", maxIdempotencyKeyLength),
+	}
+}
+
+// ErrIdempotencyKeyInProgress represents an error where a request with the same idempotency key is still running
+type ErrIdempotencyKeyInProgress struct {
+	Key string
+}
+
+// IsErrIdempotencyKeyInProgress checks if an error is ErrIdempotencyKeyInProgress.
+func IsErrIdempotencyKeyInProgress(err error) bool {
+	_, ok := err.(*ErrIdempotencyKeyInProgress)
+	return ok
+}
+
+func (err *ErrIdempotencyKeyInProgress) Error() string {
+	return fmt.Sprintf("Request with idempotency key is still in progress [Key: %s]", err.Key)
+}
+
+// ErrCodeIdempotencyKeyInProgress holds the unique world-error code of this error
+const ErrCodeIdempotencyKeyInProgress = 2008
+
+// HTTPError holds the http error description
+func (err *ErrIdempotencyKeyInProgress) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusConflict,
+		Code:     ErrCodeIdempotencyKeyInProgress,
+		Message:  "A request with this idempotency key is still in progress. Retry it later.",
+	}
+}
+
+// ErrIdempotencyKeyReused represents an error where an idempotency key is used for a different request
+type ErrIdempotencyKeyReused struct {
+	Key string
+}
+
+// IsErrIdempotencyKeyReused checks if an error is ErrIdempotencyKeyReused.
+func IsErrIdempotencyKeyReused(err error) bool {
+	_, ok := err.(*ErrIdempotencyKeyReused)
+	return ok
+}
+
+func (err *ErrIdempotencyKeyReused) Error() string {
+	return fmt.Sprintf("Idempotency key was already used for a different request [Key: %s]", err.Key)
+}
+
+// ErrCodeIdempotencyKeyReused holds the unique world-error code of this error
+const ErrCodeIdempotencyKeyReused = 2009
+
+// HTTPError holds the http error description
+func (err *ErrIdempotencyKeyReused) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusUnprocessableEntity,
+		Code:     ErrCodeIdempotencyKeyReused,
+		Message:  "This idempotency key was already used for a different request.",
+	}
+}
+
+// ErrModifiedSince represents an error where an entity was modified after the time passed in If-Unmodified-Since
+type ErrModifiedSince struct {
+	Since    time.Time
+	Modified time.Time
+}
+
+// IsErrModifiedSince checks if an error is ErrModifiedSince.
+func IsErrModifiedSince(err error) bool {
+	_, ok := err.(*ErrModifiedSince)
+	return ok
+}
+
+func (err *ErrModifiedSince) Error() string {
+	return fmt.Sprintf("Entity was modified since the passed time [Since: %s, Modified: %s]", err.Since, err.Modified)
+}
+
+// ErrCodeModifiedSince holds the unique world-error code of this error
+const ErrCodeModifiedSince = 2010
+
+// HTTPError holds the http error description
+func (err *ErrModifiedSince) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeModifiedSince,
+		Message:  "This was modified by someone else since you loaded it. Load it again and retry your changes.",
+	}
+}
+
+// ErrGraphQLQueryTooComplex represents an error where a GraphQL query is nested too deeply or selects too many fields
+type ErrGraphQLQueryTooComplex struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// IsErrGraphQLQueryTooComplex checks if an error is ErrGraphQLQueryTooComplex.
+func IsErrGraphQLQueryTooComplex(err error) bool {
+	_, ok := err.(*ErrGraphQLQueryTooComplex)
+	return ok
+}
+
+func (err *ErrGraphQLQueryTooComplex) Error() string {
+	return fmt.Sprintf("GraphQL query is too complex [MaxDepth: %d, MaxComplexity: %d]", err.MaxDepth, err.MaxComplexity)
+}
+
+// ErrCodeGraphQLQueryTooComplex holds the unique world-error code of this error
+const ErrCodeGraphQLQueryTooComplex = 2011
+
+// HTTPError holds the http error description
+func (err *ErrGraphQLQueryTooComplex) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeGraphQLQueryTooComplex,
+		Message:  fmt.Sprintf("The query can be nested at most %d levels deep and select at most %d fields.", err.MaxDepth, err.MaxComplexity),
+	}
+}
+
 // ValidationHTTPError is the http error when a validation fails
 type ValidationHTTPError struct {
 	web.HTTPError
@@ -412,678 +652,2078 @@ func (err *ErrCannotArchiveDefaultProject) HTTPError() web.HTTPError {
 	}
 }
 
-// ==============
-// Task errors
-// ==============
-
-// ErrTaskCannotBeEmpty represents a "ErrTaskCannotBeEmpty" kind of error.
-type ErrTaskCannotBeEmpty struct{}
+// ErrInvalidPriorityEscalationRule represents an error where a priority escalation rule of a project is invalid
+type ErrInvalidPriorityEscalationRule struct {
+	ProjectID  int64
+	OverdueFor int64
+	Priority   int64
+}
 
-// IsErrTaskCannotBeEmpty checks if an error is a ErrProjectDoesNotExist.
-func IsErrTaskCannotBeEmpty(err error) bool {
-	_, ok := err.(ErrTaskCannotBeEmpty)
+// IsErrInvalidPriorityEscalationRule checks if an error is ErrInvalidPriorityEscalationRule.
+func IsErrInvalidPriorityEscalationRule(err error) bool {
+	_, ok := err.(*ErrInvalidPriorityEscalationRule)
 	return ok
 }
 
-func (err ErrTaskCannotBeEmpty) Error() string {
-	return "Task title cannot be empty."
+func (err *ErrInvalidPriorityEscalationRule) Error() string {
+	return fmt.Sprintf("Invalid priority escalation rule [ProjectID: %d, OverdueFor: %d, Priority: %d]", err.ProjectID, err.OverdueFor, err.Priority)
 }
 
-// ErrCodeTaskCannotBeEmpty holds the unique world-error code of this error
-const ErrCodeTaskCannotBeEmpty = 4001
+// ErrCodeInvalidPriorityEscalationRule holds the unique world-error code of this error
+const ErrCodeInvalidPriorityEscalationRule = 3014
 
 // HTTPError holds the http error description
-func (err ErrTaskCannotBeEmpty) HTTPError() web.HTTPError {
-	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeTaskCannotBeEmpty, Message: "You must provide at least a task title."}
+func (err *ErrInvalidPriorityEscalationRule) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidPriorityEscalationRule,
+		Message:  "A priority escalation rule needs a positive overdue time and a priority between 1 and 5.",
+	}
 }
 
-// ErrTaskDoesNotExist represents a "ErrProjectDoesNotExist" kind of error. Used if the project does not exist.
-type ErrTaskDoesNotExist struct {
-	ID int64
+// ErrInvalidSLARule represents an error where an sla rule of a project is invalid
+type ErrInvalidSLARule struct {
+	ProjectID int64
+	BucketID  int64
+	MaxAge    int64
 }
 
-// IsErrTaskDoesNotExist checks if an error is a ErrProjectDoesNotExist.
-func IsErrTaskDoesNotExist(err error) bool {
-	_, ok := err.(ErrTaskDoesNotExist)
+// IsErrInvalidSLARule checks if an error is ErrInvalidSLARule.
+func IsErrInvalidSLARule(err error) bool {
+	_, ok := err.(*ErrInvalidSLARule)
 	return ok
 }
 
-func (err ErrTaskDoesNotExist) Error() string {
-	return fmt.Sprintf("Project task does not exist. [ID: %d]", err.ID)
+func (err *ErrInvalidSLARule) Error() string {
+	return fmt.Sprintf("Invalid sla rule [ProjectID: %d, BucketID: %d, MaxAge: %d]", err.ProjectID, err.BucketID, err.MaxAge)
 }
 
-// ErrCodeTaskDoesNotExist holds the unique world-error code of this error
-const ErrCodeTaskDoesNotExist = 4002
+// ErrCodeInvalidSLARule holds the unique world-error code of this error
+const ErrCodeInvalidSLARule = 3015
 
 // HTTPError holds the http error description
-func (err ErrTaskDoesNotExist) HTTPError() web.HTTPError {
-	return web.HTTPError{HTTPCode: http.StatusNotFound, Code: ErrCodeTaskDoesNotExist, Message: "This task does not exist"}
+func (err *ErrInvalidSLARule) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidSLARule,
+		Message:  "An sla rule needs a positive maximum age and its bucket must belong to the project.",
+	}
 }
 
-// ErrBulkTasksMustBeInSameProject represents a "ErrBulkTasksMustBeInSameProject" kind of error.
-type ErrBulkTasksMustBeInSameProject struct {
-	ShouldBeID int64
-	IsID       int64
+// ErrInvalidDoneTasksRetention represents an error where the done tasks retention settings of a project are invalid
+type ErrInvalidDoneTasksRetention struct {
+	ProjectID int64
+	Days      int64
+	BucketID  int64
 }
 
-// IsErrBulkTasksMustBeInSameProject checks if an error is a ErrBulkTasksMustBeInSameProject.
-func IsErrBulkTasksMustBeInSameProject(err error) bool {
-	_, ok := err.(ErrBulkTasksMustBeInSameProject)
+// IsErrInvalidDoneTasksRetention checks if an error is ErrInvalidDoneTasksRetention.
+func IsErrInvalidDoneTasksRetention(err error) bool {
+	_, ok := err.(*ErrInvalidDoneTasksRetention)
 	return ok
 }
 
-func (err ErrBulkTasksMustBeInSameProject) Error() string {
-	return fmt.Sprintf("All bulk editing tasks must be in the same project. [Should be: %d, is: %d]", err.ShouldBeID, err.IsID)
+func (err *ErrInvalidDoneTasksRetention) Error() string {
+	return fmt.Sprintf("Invalid done tasks retention [ProjectID: %d, Days: %d, BucketID: %d]", err.ProjectID, err.Days, err.BucketID)
 }
 
-// ErrCodeBulkTasksMustBeInSameProject holds the unique world-error code of this error
-const ErrCodeBulkTasksMustBeInSameProject = 4003
+// ErrCodeInvalidDoneTasksRetention holds the unique world-error code of this error
+const ErrCodeInvalidDoneTasksRetention = 3016
 
 // HTTPError holds the http error description
-func (err ErrBulkTasksMustBeInSameProject) HTTPError() web.HTTPError {
-	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeBulkTasksMustBeInSameProject, Message: "All tasks must be in the same project."}
+func (err *ErrInvalidDoneTasksRetention) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidDoneTasksRetention,
+		Message:  "The number of days after which done tasks are archived must not be negative and the bucket must belong to the project.",
+	}
 }
 
-// ErrBulkTasksNeedAtLeastOne represents a "ErrBulkTasksNeedAtLeastOne" kind of error.
-type ErrBulkTasksNeedAtLeastOne struct{}
+// ErrProjectTemplateDoesNotExist represents an error where a project template does not exist
+type ErrProjectTemplateDoesNotExist struct {
+	ProjectTemplateID int64
+}
 
-// IsErrBulkTasksNeedAtLeastOne checks if an error is a ErrBulkTasksNeedAtLeastOne.
-func IsErrBulkTasksNeedAtLeastOne(err error) bool {
-	_, ok := err.(ErrBulkTasksNeedAtLeastOne)
+// IsErrProjectTemplateDoesNotExist checks if an error is ErrProjectTemplateDoesNotExist.
+func IsErrProjectTemplateDoesNotExist(err error) bool {
+	_, ok := err.(*ErrProjectTemplateDoesNotExist)
 	return ok
 }
 
-func (err ErrBulkTasksNeedAtLeastOne) Error() string {
-	return "Need at least one task when bulk editing tasks"
+func (err *ErrProjectTemplateDoesNotExist) Error() string {
+	return fmt.Sprintf("Project template does not exist [ProjectTemplateID: %d]", err.ProjectTemplateID)
 }
 
-// ErrCodeBulkTasksNeedAtLeastOne holds the unique world-error code of this error
-const ErrCodeBulkTasksNeedAtLeastOne = 4004
+// ErrCodeProjectTemplateDoesNotExist holds the unique world-error code of this error
+const ErrCodeProjectTemplateDoesNotExist = 3017
 
 // HTTPError holds the http error description
-func (err ErrBulkTasksNeedAtLeastOne) HTTPError() web.HTTPError {
-	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeBulkTasksNeedAtLeastOne, Message: "Need at least one tasks to do bulk editing."}
+func (err *ErrProjectTemplateDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeProjectTemplateDoesNotExist,
+		Message:  "This project template does not exist.",
+	}
 }
 
-// ErrNoRightToSeeTask represents an error where a user does not have the right to see a task
-type ErrNoRightToSeeTask struct {
-	TaskID int64
-	UserID int64
+// ErrInvalidProjectTemplate represents an error where a project template contains invalid buckets, labels, custom fields or tasks
+type ErrInvalidProjectTemplate struct {
+	ProjectTemplateID int64
+	Reason            string
 }
 
-// IsErrNoRightToSeeTask checks if an error is ErrNoRightToSeeTask.
-func IsErrNoRightToSeeTask(err error) bool {
-	_, ok := err.(ErrNoRightToSeeTask)
+// IsErrInvalidProjectTemplate checks if an error is ErrInvalidProjectTemplate.
+func IsErrInvalidProjectTemplate(err error) bool {
+	_, ok := err.(*ErrInvalidProjectTemplate)
 	return ok
 }
 
-func (err ErrNoRightToSeeTask) Error() string {
-	return fmt.Sprintf("User does not have the right to see the task [TaskID: %v, ID: %v]", err.TaskID, err.UserID)
+func (err *ErrInvalidProjectTemplate) Error() string {
+	return fmt.Sprintf("Project template is invalid [ProjectTemplateID: %d, Reason: %s]", err.ProjectTemplateID, err.Reason)
 }
 
-// ErrCodeNoRightToSeeTask holds the unique world-error code of this error
-const ErrCodeNoRightToSeeTask = 4005
+// ErrCodeInvalidProjectTemplate holds the unique world-error code of this error
+const ErrCodeInvalidProjectTemplate = 3018
 
 // HTTPError holds the http error description
-func (err ErrNoRightToSeeTask) HTTPError() web.HTTPError {
+func (err *ErrInvalidProjectTemplate) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusForbidden,
-		Code:     ErrCodeNoRightToSeeTask,
-		Message:  "You don't have the right to see this task.",
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidProjectTemplate,
+		Message:  "The project template is invalid: " + err.Reason,
 	}
 }
 
-// ErrParentTaskCannotBeTheSame represents an error where the user tries to set a tasks parent as the same
-type ErrParentTaskCannotBeTheSame struct {
-	TaskID int64
+// ErrProjectStatusDoesNotExist represents an error where a project status does not exist
+type ErrProjectStatusDoesNotExist struct {
+	StatusID int64
 }
 
-// IsErrParentTaskCannotBeTheSame checks if an error is ErrParentTaskCannotBeTheSame.
-func IsErrParentTaskCannotBeTheSame(err error) bool {
-	_, ok := err.(ErrParentTaskCannotBeTheSame)
+// IsErrProjectStatusDoesNotExist checks if an error is ErrProjectStatusDoesNotExist.
+func IsErrProjectStatusDoesNotExist(err error) bool {
+	_, ok := err.(*ErrProjectStatusDoesNotExist)
 	return ok
 }
 
-func (err ErrParentTaskCannotBeTheSame) Error() string {
-	return fmt.Sprintf("Tried to set a parents task as the same [TaskID: %v]", err.TaskID)
+func (err *ErrProjectStatusDoesNotExist) Error() string {
+	return fmt.Sprintf("Project status does not exist [StatusID: %d]", err.StatusID)
 }
 
-// ErrCodeParentTaskCannotBeTheSame holds the unique world-error code of this error
-const ErrCodeParentTaskCannotBeTheSame = 4006
+// ErrCodeProjectStatusDoesNotExist holds the unique world-error code of this error
+const ErrCodeProjectStatusDoesNotExist = 3019
 
 // HTTPError holds the http error description
-func (err ErrParentTaskCannotBeTheSame) HTTPError() web.HTTPError {
+func (err *ErrProjectStatusDoesNotExist) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusForbidden,
-		Code:     ErrCodeParentTaskCannotBeTheSame,
-		Message:  "You cannot set a parent task to the task itself.",
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeProjectStatusDoesNotExist,
+		Message:  "This status does not exist.",
 	}
 }
 
-// ErrInvalidRelationKind represents an error where the user tries to use an invalid relation kind
-type ErrInvalidRelationKind struct {
-	Kind RelationKind
+// ErrProjectStatusDoesNotBelongToProject represents an error where a task gets a status of another project
+type ErrProjectStatusDoesNotBelongToProject struct {
+	StatusID  int64
+	ProjectID int64
 }
 
-// IsErrInvalidRelationKind checks if an error is ErrInvalidRelationKind.
-func IsErrInvalidRelationKind(err error) bool {
-	_, ok := err.(ErrInvalidRelationKind)
+// IsErrProjectStatusDoesNotBelongToProject checks if an error is ErrProjectStatusDoesNotBelongToProject.
+func IsErrProjectStatusDoesNotBelongToProject(err error) bool {
+	_, ok := err.(*ErrProjectStatusDoesNotBelongToProject)
 	return ok
 }
 
-func (err ErrInvalidRelationKind) Error() string {
-	return fmt.Sprintf("Invalid task relation kind [Kind: %v]", err.Kind)
+func (err *ErrProjectStatusDoesNotBelongToProject) Error() string {
+	return fmt.Sprintf("Project status does not belong to project [StatusID: %d, ProjectID: %d]", err.StatusID, err.ProjectID)
 }
 
-// ErrCodeInvalidRelationKind holds the unique world-error code of this error
-const ErrCodeInvalidRelationKind = 4007
+// ErrCodeProjectStatusDoesNotBelongToProject holds the unique world-error code of this error
+const ErrCodeProjectStatusDoesNotBelongToProject = 3020
 
 // HTTPError holds the http error description
-func (err ErrInvalidRelationKind) HTTPError() web.HTTPError {
+func (err *ErrProjectStatusDoesNotBelongToProject) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeInvalidRelationKind,
-		Message:  "The task relation is invalid.",
+		Code:     ErrCodeProjectStatusDoesNotBelongToProject,
+		Message:  "This status does not belong to the project of the task.",
 	}
 }
 
-// ErrRelationAlreadyExists represents an error where the user tries to create an already existing relation
-type ErrRelationAlreadyExists struct {
-	Kind        RelationKind
-	TaskID      int64
-	OtherTaskID int64
+// ErrMilestoneDoesNotExist represents an error where a milestone does not exist
+type ErrMilestoneDoesNotExist struct {
+	MilestoneID int64
 }
 
-// IsErrRelationAlreadyExists checks if an error is ErrRelationAlreadyExists.
-func IsErrRelationAlreadyExists(err error) bool {
-	_, ok := err.(ErrRelationAlreadyExists)
+// IsErrMilestoneDoesNotExist checks if an error is ErrMilestoneDoesNotExist.
+func IsErrMilestoneDoesNotExist(err error) bool {
+	_, ok := err.(*ErrMilestoneDoesNotExist)
 	return ok
 }
 
-func (err ErrRelationAlreadyExists) Error() string {
-	return fmt.Sprintf("Task relation already exists [TaskID: %v, OtherTaskID: %v, Kind: %v]", err.TaskID, err.OtherTaskID, err.Kind)
+func (err *ErrMilestoneDoesNotExist) Error() string {
+	return fmt.Sprintf("Milestone does not exist [MilestoneID: %d]", err.MilestoneID)
 }
 
-// ErrCodeRelationAlreadyExists holds the unique world-error code of this error
-const ErrCodeRelationAlreadyExists = 4008
+// ErrCodeMilestoneDoesNotExist holds the unique world-error code of this error
+const ErrCodeMilestoneDoesNotExist = 3021
 
 // HTTPError holds the http error description
-func (err ErrRelationAlreadyExists) HTTPError() web.HTTPError {
+func (err *ErrMilestoneDoesNotExist) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusConflict,
-		Code:     ErrCodeRelationAlreadyExists,
-		Message:  "The task relation already exists.",
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeMilestoneDoesNotExist,
+		Message:  "This milestone does not exist.",
 	}
 }
 
-// ErrRelationDoesNotExist represents an error where a task relation does not exist.
-type ErrRelationDoesNotExist struct {
-	Kind        RelationKind
-	TaskID      int64
-	OtherTaskID int64
+// ErrMilestoneDoesNotBelongToProject represents an error where a task gets a milestone of another project
+type ErrMilestoneDoesNotBelongToProject struct {
+	MilestoneID int64
+	ProjectID   int64
 }
 
-// IsErrRelationDoesNotExist checks if an error is ErrRelationDoesNotExist.
+// IsErrMilestoneDoesNotBelongToProject checks if an error is ErrMilestoneDoesNotBelongToProject.
+func IsErrMilestoneDoesNotBelongToProject(err error) bool {
+	_, ok := err.(*ErrMilestoneDoesNotBelongToProject)
+	return ok
+}
+
+func (err *ErrMilestoneDoesNotBelongToProject) Error() string {
+	return fmt.Sprintf("Milestone does not belong to project [MilestoneID: %d, ProjectID: %d]", err.MilestoneID, err.ProjectID)
+}
+
+// ErrCodeMilestoneDoesNotBelongToProject holds the unique world-error code of this error
+const ErrCodeMilestoneDoesNotBelongToProject = 3022
+
+// HTTPError holds the http error description
+func (err *ErrMilestoneDoesNotBelongToProject) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeMilestoneDoesNotBelongToProject,
+		Message:  "This milestone does not belong to the project of the task.",
+	}
+}
+
+// ErrProjectRoleDoesNotExist represents an error where a project role does not exist
+type ErrProjectRoleDoesNotExist struct {
+	RoleID int64
+}
+
+// IsErrProjectRoleDoesNotExist checks if an error is ErrProjectRoleDoesNotExist.
+func IsErrProjectRoleDoesNotExist(err error) bool {
+	_, ok := err.(*ErrProjectRoleDoesNotExist)
+	return ok
+}
+
+func (err *ErrProjectRoleDoesNotExist) Error() string {
+	return fmt.Sprintf("Project role does not exist [RoleID: %d]", err.RoleID)
+}
+
+// ErrCodeProjectRoleDoesNotExist holds the unique world-error code of this error
+const ErrCodeProjectRoleDoesNotExist = 3023
+
+// HTTPError holds the http error description
+func (err *ErrProjectRoleDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeProjectRoleDoesNotExist,
+		Message:  "This role does not exist.",
+	}
+}
+
+// ErrProjectRoleDoesNotBelongToProject represents an error where a project is shared with a role of another project
+type ErrProjectRoleDoesNotBelongToProject struct {
+	RoleID    int64
+	ProjectID int64
+}
+
+// IsErrProjectRoleDoesNotBelongToProject checks if an error is ErrProjectRoleDoesNotBelongToProject.
+func IsErrProjectRoleDoesNotBelongToProject(err error) bool {
+	_, ok := err.(*ErrProjectRoleDoesNotBelongToProject)
+	return ok
+}
+
+func (err *ErrProjectRoleDoesNotBelongToProject) Error() string {
+	return fmt.Sprintf("Project role does not belong to project [RoleID: %d, ProjectID: %d]", err.RoleID, err.ProjectID)
+}
+
+// ErrCodeProjectRoleDoesNotBelongToProject holds the unique world-error code of this error
+const ErrCodeProjectRoleDoesNotBelongToProject = 3024
+
+// HTTPError holds the http error description
+func (err *ErrProjectRoleDoesNotBelongToProject) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeProjectRoleDoesNotBelongToProject,
+		Message:  "This role does not belong to the shared project.",
+	}
+}
+
+// ErrInvalidProjectIcon represents an error where the icon of a project is not a single emoji
+type ErrInvalidProjectIcon struct {
+	Icon string
+}
+
+// IsErrInvalidProjectIcon checks if an error is ErrInvalidProjectIcon.
+func IsErrInvalidProjectIcon(err error) bool {
+	_, ok := err.(*ErrInvalidProjectIcon)
+	return ok
+}
+
+func (err *ErrInvalidProjectIcon) Error() string {
+	return fmt.Sprintf("Project icon is not a single emoji [Icon: %s]", err.Icon)
+}
+
+// ErrCodeInvalidProjectIcon holds the unique world-error code of this error
+const ErrCodeInvalidProjectIcon = 3025
+
+// HTTPError holds the http error description
+func (err *ErrInvalidProjectIcon) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidProjectIcon,
+		Message:  "The project icon must be a single emoji.",
+	}
+}
+
+// ErrProjectIconIsNotAnImage represents an error where a file uploaded as project icon is not an image
+type ErrProjectIconIsNotAnImage struct {
+	ProjectID int64
+	Mime      string
+}
+
+// IsErrProjectIconIsNotAnImage checks if an error is ErrProjectIconIsNotAnImage.
+func IsErrProjectIconIsNotAnImage(err error) bool {
+	_, ok := err.(*ErrProjectIconIsNotAnImage)
+	return ok
+}
+
+func (err *ErrProjectIconIsNotAnImage) Error() string {
+	return fmt.Sprintf("Project icon is not an image [ProjectID: %d, Mime: %s]", err.ProjectID, err.Mime)
+}
+
+// ErrCodeProjectIconIsNotAnImage holds the unique world-error code of this error
+const ErrCodeProjectIconIsNotAnImage = 3026
+
+// HTTPError holds the http error description
+func (err *ErrProjectIconIsNotAnImage) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeProjectIconIsNotAnImage,
+		Message:  "The project icon must be an image.",
+	}
+}
+
+// ErrProjectIconIsTooLarge represents an error where a file uploaded as project icon is too large
+type ErrProjectIconIsTooLarge struct {
+	Size uint64
+}
+
+// IsErrProjectIconIsTooLarge checks if an error is ErrProjectIconIsTooLarge.
+func IsErrProjectIconIsTooLarge(err error) bool {
+	_, ok := err.(*ErrProjectIconIsTooLarge)
+	return ok
+}
+
+func (err *ErrProjectIconIsTooLarge) Error() string {
+	return fmt.Sprintf("Project icon is too large [Size: %d]", err.Size)
+}
+
+// ErrCodeProjectIconIsTooLarge holds the unique world-error code of this error
+const ErrCodeProjectIconIsTooLarge = 3027
+
+// HTTPError holds the http error description
+func (err *ErrProjectIconIsTooLarge) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeProjectIconIsTooLarge,
+		Message:  fmt.Sprintf("The project icon image must not be larger than %d bytes.", maxProjectIconSize),
+	}
+}
+
+// ErrInvalidProjectBackgroundURL represents an error where a project background should be downloaded from an invalid url
+type ErrInvalidProjectBackgroundURL struct {
+	URL string
+}
+
+// IsErrInvalidProjectBackgroundURL checks if an error is ErrInvalidProjectBackgroundURL.
+func IsErrInvalidProjectBackgroundURL(err error) bool {
+	_, ok := err.(*ErrInvalidProjectBackgroundURL)
+	return ok
+}
+
+func (err *ErrInvalidProjectBackgroundURL) Error() string {
+	return fmt.Sprintf("Project background url is invalid or could not be downloaded [URL: %s]", err.URL)
+}
+
+// ErrCodeInvalidProjectBackgroundURL holds the unique world-error code of this error
+const ErrCodeInvalidProjectBackgroundURL = 3028
+
+// HTTPError holds the http error description
+func (err *ErrInvalidProjectBackgroundURL) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidProjectBackgroundURL,
+		Message:  "The background url is invalid or could not be downloaded.",
+	}
+}
+
+// ErrProjectBackgroundIsNotAnImage represents an error where a file which should be used as project background is not an image
+type ErrProjectBackgroundIsNotAnImage struct {
+	ProjectID int64
+	Mime      string
+}
+
+// IsErrProjectBackgroundIsNotAnImage checks if an error is ErrProjectBackgroundIsNotAnImage.
+func IsErrProjectBackgroundIsNotAnImage(err error) bool {
+	_, ok := err.(*ErrProjectBackgroundIsNotAnImage)
+	return ok
+}
+
+func (err *ErrProjectBackgroundIsNotAnImage) Error() string {
+	return fmt.Sprintf("Project background is not an image [ProjectID: %d, Mime: %s]", err.ProjectID, err.Mime)
+}
+
+// ErrCodeProjectBackgroundIsNotAnImage holds the unique world-error code of this error
+const ErrCodeProjectBackgroundIsNotAnImage = 3029
+
+// HTTPError holds the http error description
+func (err *ErrProjectBackgroundIsNotAnImage) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeProjectBackgroundIsNotAnImage,
+		Message:  "The project background must be an image.",
+	}
+}
+
+// ErrInvalidProjectUserPositionKind represents an error where a project user position has an invalid kind
+type ErrInvalidProjectUserPositionKind struct {
+	Kind ProjectUserPositionKind
+}
+
+// IsErrInvalidProjectUserPositionKind checks if an error is ErrInvalidProjectUserPositionKind.
+func IsErrInvalidProjectUserPositionKind(err error) bool {
+	_, ok := err.(*ErrInvalidProjectUserPositionKind)
+	return ok
+}
+
+func (err *ErrInvalidProjectUserPositionKind) Error() string {
+	return fmt.Sprintf("Project user position kind is invalid [Kind: %s]", err.Kind)
+}
+
+// ErrCodeInvalidProjectUserPositionKind holds the unique world-error code of this error
+const ErrCodeInvalidProjectUserPositionKind = 3030
+
+// HTTPError holds the http error description
+func (err *ErrInvalidProjectUserPositionKind) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidProjectUserPositionKind,
+		Message:  "The position kind must be either root or favorites.",
+	}
+}
+
+// ErrProjectEmailAddressDoesNotExist represents an error where a project does not have an email address
+type ErrProjectEmailAddressDoesNotExist struct {
+	ProjectID int64
+}
+
+// IsErrProjectEmailAddressDoesNotExist checks if an error is ErrProjectEmailAddressDoesNotExist.
+func IsErrProjectEmailAddressDoesNotExist(err error) bool {
+	_, ok := err.(*ErrProjectEmailAddressDoesNotExist)
+	return ok
+}
+
+func (err *ErrProjectEmailAddressDoesNotExist) Error() string {
+	return fmt.Sprintf("Project email address does not exist [ProjectID: %d]", err.ProjectID)
+}
+
+// ErrCodeProjectEmailAddressDoesNotExist holds the unique world-error code of this error
+const ErrCodeProjectEmailAddressDoesNotExist = 3031
+
+// HTTPError holds the http error description
+func (err *ErrProjectEmailAddressDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeProjectEmailAddressDoesNotExist,
+		Message:  "This project does not have an email address.",
+	}
+}
+
+// ErrInboundMailRecipientDoesNotExist represents an error where an inbound email was not sent to the email address of any project
+type ErrInboundMailRecipientDoesNotExist struct {
+	Recipients []string
+}
+
+// IsErrInboundMailRecipientDoesNotExist checks if an error is ErrInboundMailRecipientDoesNotExist.
+func IsErrInboundMailRecipientDoesNotExist(err error) bool {
+	_, ok := err.(*ErrInboundMailRecipientDoesNotExist)
+	return ok
+}
+
+func (err *ErrInboundMailRecipientDoesNotExist) Error() string {
+	return fmt.Sprintf("Inbound email was not sent to a project email address [Recipients: %v]", err.Recipients)
+}
+
+// ErrCodeInboundMailRecipientDoesNotExist holds the unique world-error code of this error
+const ErrCodeInboundMailRecipientDoesNotExist = 3032
+
+// HTTPError holds the http error description
+func (err *ErrInboundMailRecipientDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeInboundMailRecipientDoesNotExist,
+		Message:  "The email was not sent to the email address of a project.",
+	}
+}
+
+// ErrInboundMailSenderNotAllowed represents an error where the sender of an inbound email is not allowed to create tasks in a project
+type ErrInboundMailSenderNotAllowed struct {
+	ProjectID int64
+	Sender    string
+}
+
+// IsErrInboundMailSenderNotAllowed checks if an error is ErrInboundMailSenderNotAllowed.
+func IsErrInboundMailSenderNotAllowed(err error) bool {
+	_, ok := err.(*ErrInboundMailSenderNotAllowed)
+	return ok
+}
+
+func (err *ErrInboundMailSenderNotAllowed) Error() string {
+	return fmt.Sprintf("Inbound email sender is not allowed [ProjectID: %d, Sender: %s]", err.ProjectID, err.Sender)
+}
+
+// ErrCodeInboundMailSenderNotAllowed holds the unique world-error code of this error
+const ErrCodeInboundMailSenderNotAllowed = 3033
+
+// HTTPError holds the http error description
+func (err *ErrInboundMailSenderNotAllowed) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusForbidden,
+		Code:     ErrCodeInboundMailSenderNotAllowed,
+		Message:  "The sender of the email is not allowed to create tasks in this project.",
+	}
+}
+
+// ErrInvalidInboundMail represents an error where an inbound email could not be parsed
+type ErrInvalidInboundMail struct {
+	Message string
+}
+
+// IsErrInvalidInboundMail checks if an error is ErrInvalidInboundMail.
+func IsErrInvalidInboundMail(err error) bool {
+	_, ok := err.(*ErrInvalidInboundMail)
+	return ok
+}
+
+func (err *ErrInvalidInboundMail) Error() string {
+	return fmt.Sprintf("Inbound email is invalid [Message: %s]", err.Message)
+}
+
+// ErrCodeInvalidInboundMail holds the unique world-error code of this error
+const ErrCodeInvalidInboundMail = 3034
+
+// HTTPError holds the http error description
+func (err *ErrInvalidInboundMail) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidInboundMail,
+		Message:  "The email could not be parsed: " + err.Message,
+	}
+}
+
+// ErrProjectHierarchyTooDeep represents an error where a project would be nested deeper than allowed
+type ErrProjectHierarchyTooDeep struct {
+	ProjectID int64
+	MaxDepth  int64
+}
+
+// IsErrProjectHierarchyTooDeep checks if an error is ErrProjectHierarchyTooDeep.
+func IsErrProjectHierarchyTooDeep(err error) bool {
+	_, ok := err.(*ErrProjectHierarchyTooDeep)
+	return ok
+}
+
+func (err *ErrProjectHierarchyTooDeep) Error() string {
+	return fmt.Sprintf("Project hierarchy is too deep [ProjectID: %d, MaxDepth: %d]", err.ProjectID, err.MaxDepth)
+}
+
+// ErrCodeProjectHierarchyTooDeep holds the unique world-error code of this error
+const ErrCodeProjectHierarchyTooDeep = 3035
+
+// HTTPError holds the http error description
+func (err *ErrProjectHierarchyTooDeep) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeProjectHierarchyTooDeep,
+		Message:  fmt.Sprintf("Projects can only be nested %d levels deep.", err.MaxDepth),
+	}
+}
+
+// ErrProjectLabelRuleDoesNotExist represents an error where a label rule does not exist
+type ErrProjectLabelRuleDoesNotExist struct {
+	RuleID int64
+}
+
+// IsErrProjectLabelRuleDoesNotExist checks if an error is ErrProjectLabelRuleDoesNotExist.
+func IsErrProjectLabelRuleDoesNotExist(err error) bool {
+	_, ok := err.(*ErrProjectLabelRuleDoesNotExist)
+	return ok
+}
+
+func (err *ErrProjectLabelRuleDoesNotExist) Error() string {
+	return fmt.Sprintf("Project label rule does not exist [RuleID: %d]", err.RuleID)
+}
+
+// ErrCodeProjectLabelRuleDoesNotExist holds the unique world-error code of this error
+const ErrCodeProjectLabelRuleDoesNotExist = 3036
+
+// HTTPError holds the http error description
+func (err *ErrProjectLabelRuleDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeProjectLabelRuleDoesNotExist,
+		Message:  "This label rule does not exist.",
+	}
+}
+
+// ErrProjectLabelRuleHasNoEffect represents an error where a label rule would neither add labels nor set a priority
+type ErrProjectLabelRuleHasNoEffect struct{}
+
+// IsErrProjectLabelRuleHasNoEffect checks if an error is ErrProjectLabelRuleHasNoEffect.
+func IsErrProjectLabelRuleHasNoEffect(err error) bool {
+	_, ok := err.(*ErrProjectLabelRuleHasNoEffect)
+	return ok
+}
+
+func (err *ErrProjectLabelRuleHasNoEffect) Error() string {
+	return "Project label rule has neither labels nor a priority"
+}
+
+// ErrCodeProjectLabelRuleHasNoEffect holds the unique world-error code of this error
+const ErrCodeProjectLabelRuleHasNoEffect = 3037
+
+// HTTPError holds the http error description
+func (err *ErrProjectLabelRuleHasNoEffect) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeProjectLabelRuleHasNoEffect,
+		Message:  "A label rule needs at least one label or a priority.",
+	}
+}
+
+// ErrProjectIntegrationDoesNotExist represents an error where a project integration does not exist
+type ErrProjectIntegrationDoesNotExist struct {
+	IntegrationID int64
+}
+
+// IsErrProjectIntegrationDoesNotExist checks if an error is ErrProjectIntegrationDoesNotExist.
+func IsErrProjectIntegrationDoesNotExist(err error) bool {
+	_, ok := err.(*ErrProjectIntegrationDoesNotExist)
+	return ok
+}
+
+func (err *ErrProjectIntegrationDoesNotExist) Error() string {
+	return fmt.Sprintf("Project integration does not exist [IntegrationID: %d]", err.IntegrationID)
+}
+
+// ErrCodeProjectIntegrationDoesNotExist holds the unique world-error code of this error
+const ErrCodeProjectIntegrationDoesNotExist = 3038
+
+// HTTPError holds the http error description
+func (err *ErrProjectIntegrationDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeProjectIntegrationDoesNotExist,
+		Message:  "This integration does not exist.",
+	}
+}
+
+// ErrInvalidProjectIntegrationKind represents an error where a project integration has an unknown or disabled kind
+type ErrInvalidProjectIntegrationKind struct {
+	Kind ProjectIntegrationKind
+}
+
+// IsErrInvalidProjectIntegrationKind checks if an error is ErrInvalidProjectIntegrationKind.
+func IsErrInvalidProjectIntegrationKind(err error) bool {
+	_, ok := err.(*ErrInvalidProjectIntegrationKind)
+	return ok
+}
+
+func (err *ErrInvalidProjectIntegrationKind) Error() string {
+	return fmt.Sprintf("Project integration kind is invalid or not enabled [Kind: %s]", err.Kind)
+}
+
+// ErrCodeInvalidProjectIntegrationKind holds the unique world-error code of this error
+const ErrCodeInvalidProjectIntegrationKind = 3039
+
+// HTTPError holds the http error description
+func (err *ErrInvalidProjectIntegrationKind) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidProjectIntegrationKind,
+		Message:  fmt.Sprintf("The integration kind '%s' does not exist or is not enabled on this instance.", err.Kind),
+	}
+}
+
+// ErrSlackChannelNotConnected represents an error where a Slack command was used in a channel without a project integration
+type ErrSlackChannelNotConnected struct {
+	ChannelID string
+}
+
+// IsErrSlackChannelNotConnected checks if an error is ErrSlackChannelNotConnected.
+func IsErrSlackChannelNotConnected(err error) bool {
+	_, ok := err.(*ErrSlackChannelNotConnected)
+	return ok
+}
+
+func (err *ErrSlackChannelNotConnected) Error() string {
+	return fmt.Sprintf("Slack channel is not connected to a project [ChannelID: %s]", err.ChannelID)
+}
+
+// ErrCodeSlackChannelNotConnected holds the unique world-error code of this error
+const ErrCodeSlackChannelNotConnected = 3040
+
+// HTTPError holds the http error description
+func (err *ErrSlackChannelNotConnected) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeSlackChannelNotConnected,
+		Message:  "This channel is not connected to a Vikunja project. Add a Slack integration with the id of this channel to a project first.",
+	}
+}
+
+// ErrAutomationRuleDoesNotExist represents an error where an automation rule does not exist
+type ErrAutomationRuleDoesNotExist struct {
+	RuleID int64
+}
+
+// IsErrAutomationRuleDoesNotExist checks if an error is ErrAutomationRuleDoesNotExist.
+func IsErrAutomationRuleDoesNotExist(err error) bool {
+	_, ok := err.(*ErrAutomationRuleDoesNotExist)
+	return ok
+}
+
+func (err *ErrAutomationRuleDoesNotExist) Error() string {
+	return fmt.Sprintf("Automation rule does not exist [RuleID: %d]", err.RuleID)
+}
+
+// ErrCodeAutomationRuleDoesNotExist holds the unique world-error code of this error
+const ErrCodeAutomationRuleDoesNotExist = 3041
+
+// HTTPError holds the http error description
+func (err *ErrAutomationRuleDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeAutomationRuleDoesNotExist,
+		Message:  "The automation rule does not exist.",
+	}
+}
+
+// ErrInvalidAutomationRule represents an error where the trigger or an action of an automation rule is invalid
+type ErrInvalidAutomationRule struct {
+	Reason string
+}
+
+// IsErrInvalidAutomationRule checks if an error is ErrInvalidAutomationRule.
+func IsErrInvalidAutomationRule(err error) bool {
+	_, ok := err.(*ErrInvalidAutomationRule)
+	return ok
+}
+
+func (err *ErrInvalidAutomationRule) Error() string {
+	return fmt.Sprintf("Automation rule is invalid [Reason: %s]", err.Reason)
+}
+
+// ErrCodeInvalidAutomationRule holds the unique world-error code of this error
+const ErrCodeInvalidAutomationRule = 3042
+
+// HTTPError holds the http error description
+func (err *ErrInvalidAutomationRule) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidAutomationRule,
+		Message:  "The automation rule is invalid: " + err.Reason,
+	}
+}
+
+// ErrScheduledAutomationDoesNotExist represents an error where a scheduled automation does not exist
+type ErrScheduledAutomationDoesNotExist struct {
+	AutomationID int64
+}
+
+// IsErrScheduledAutomationDoesNotExist checks if an error is ErrScheduledAutomationDoesNotExist.
+func IsErrScheduledAutomationDoesNotExist(err error) bool {
+	_, ok := err.(*ErrScheduledAutomationDoesNotExist)
+	return ok
+}
+
+func (err *ErrScheduledAutomationDoesNotExist) Error() string {
+	return fmt.Sprintf("Scheduled automation does not exist [AutomationID: %d]", err.AutomationID)
+}
+
+// ErrCodeScheduledAutomationDoesNotExist holds the unique world-error code of this error
+const ErrCodeScheduledAutomationDoesNotExist = 3043
+
+// HTTPError holds the http error description
+func (err *ErrScheduledAutomationDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeScheduledAutomationDoesNotExist,
+		Message:  "The scheduled automation does not exist.",
+	}
+}
+
+// ErrInvalidScheduledAutomation represents an error where the schedule or the action of a scheduled automation is invalid
+type ErrInvalidScheduledAutomation struct {
+	Reason string
+}
+
+// IsErrInvalidScheduledAutomation checks if an error is ErrInvalidScheduledAutomation.
+func IsErrInvalidScheduledAutomation(err error) bool {
+	_, ok := err.(*ErrInvalidScheduledAutomation)
+	return ok
+}
+
+func (err *ErrInvalidScheduledAutomation) Error() string {
+	return fmt.Sprintf("Scheduled automation is invalid [Reason: %s]", err.Reason)
+}
+
+// ErrCodeInvalidScheduledAutomation holds the unique world-error code of this error
+const ErrCodeInvalidScheduledAutomation = 3044
+
+// HTTPError holds the http error description
+func (err *ErrInvalidScheduledAutomation) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidScheduledAutomation,
+		Message:  "The scheduled automation is invalid: " + err.Reason,
+	}
+}
+
+// ==============
+// Task errors
+// ==============
+
+// ErrTaskCannotBeEmpty represents a "ErrTaskCannotBeEmpty" kind of error.
+type ErrTaskCannotBeEmpty struct{}
+
+// IsErrTaskCannotBeEmpty checks if an error is a ErrProjectDoesNotExist.
+func IsErrTaskCannotBeEmpty(err error) bool {
+	_, ok := err.(ErrTaskCannotBeEmpty)
+	return ok
+}
+
+func (err ErrTaskCannotBeEmpty) Error() string {
+	return "Task title cannot be empty."
+}
+
+// ErrCodeTaskCannotBeEmpty holds the unique world-error code of this error
+const ErrCodeTaskCannotBeEmpty = 4001
+
+// HTTPError holds the http error description
+func (err ErrTaskCannotBeEmpty) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeTaskCannotBeEmpty, Message: "You must provide at least a task title."}
+}
+
+// ErrTaskDoesNotExist represents a "ErrProjectDoesNotExist" kind of error. Used if the project does not exist.
+type ErrTaskDoesNotExist struct {
+	ID int64
+}
+
+// IsErrTaskDoesNotExist checks if an error is a ErrProjectDoesNotExist.
+func IsErrTaskDoesNotExist(err error) bool {
+	_, ok := err.(ErrTaskDoesNotExist)
+	return ok
+}
+
+func (err ErrTaskDoesNotExist) Error() string {
+	return fmt.Sprintf("Project task does not exist. [ID: %d]", err.ID)
+}
+
+// ErrCodeTaskDoesNotExist holds the unique world-error code of this error
+const ErrCodeTaskDoesNotExist = 4002
+
+// HTTPError holds the http error description
+func (err ErrTaskDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusNotFound, Code: ErrCodeTaskDoesNotExist, Message: "This task does not exist"}
+}
+
+// ErrBulkTasksMustBeInSameProject represents a "ErrBulkTasksMustBeInSameProject" kind of error.
+type ErrBulkTasksMustBeInSameProject struct {
+	ShouldBeID int64
+	IsID       int64
+}
+
+// IsErrBulkTasksMustBeInSameProject checks if an error is a ErrBulkTasksMustBeInSameProject.
+func IsErrBulkTasksMustBeInSameProject(err error) bool {
+	_, ok := err.(ErrBulkTasksMustBeInSameProject)
+	return ok
+}
+
+func (err ErrBulkTasksMustBeInSameProject) Error() string {
+	return fmt.Sprintf("All bulk editing tasks must be in the same project. [Should be: %d, is: %d]", err.ShouldBeID, err.IsID)
+}
+
+// ErrCodeBulkTasksMustBeInSameProject holds the unique world-error code of this error
+const ErrCodeBulkTasksMustBeInSameProject = 4003
+
+// HTTPError holds the http error description
+func (err ErrBulkTasksMustBeInSameProject) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeBulkTasksMustBeInSameProject, Message: "All tasks must be in the same project."}
+}
+
+// ErrBulkTasksNeedAtLeastOne represents a "ErrBulkTasksNeedAtLeastOne" kind of error.
+type ErrBulkTasksNeedAtLeastOne struct{}
+
+// IsErrBulkTasksNeedAtLeastOne checks if an error is a ErrBulkTasksNeedAtLeastOne.
+func IsErrBulkTasksNeedAtLeastOne(err error) bool {
+	_, ok := err.(ErrBulkTasksNeedAtLeastOne)
+	return ok
+}
+
+func (err ErrBulkTasksNeedAtLeastOne) Error() string {
+	return "Need at least one task when bulk editing tasks"
+}
+
+// ErrCodeBulkTasksNeedAtLeastOne holds the unique world-error code of this error
+const ErrCodeBulkTasksNeedAtLeastOne = 4004
+
+// HTTPError holds the http error description
+func (err ErrBulkTasksNeedAtLeastOne) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeBulkTasksNeedAtLeastOne, Message: "Need at least one tasks to do bulk editing."}
+}
+
+// ErrNoRightToSeeTask represents an error where a user does not have the right to see a task
+type ErrNoRightToSeeTask struct {
+	TaskID int64
+	UserID int64
+}
+
+// IsErrNoRightToSeeTask checks if an error is ErrNoRightToSeeTask.
+func IsErrNoRightToSeeTask(err error) bool {
+	_, ok := err.(ErrNoRightToSeeTask)
+	return ok
+}
+
+func (err ErrNoRightToSeeTask) Error() string {
+	return fmt.Sprintf("User does not have the right to see the task [TaskID: %v, ID: %v]", err.TaskID, err.UserID)
+}
+
+// ErrCodeNoRightToSeeTask holds the unique world-error code of this error
+const ErrCodeNoRightToSeeTask = 4005
+
+// HTTPError holds the http error description
+func (err ErrNoRightToSeeTask) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusForbidden,
+		Code:     ErrCodeNoRightToSeeTask,
+		Message:  "You don't have the right to see this task.",
+	}
+}
+
+// ErrParentTaskCannotBeTheSame represents an error where the user tries to set a tasks parent as the same
+type ErrParentTaskCannotBeTheSame struct {
+	TaskID int64
+}
+
+// IsErrParentTaskCannotBeTheSame checks if an error is ErrParentTaskCannotBeTheSame.
+func IsErrParentTaskCannotBeTheSame(err error) bool {
+	_, ok := err.(ErrParentTaskCannotBeTheSame)
+	return ok
+}
+
+func (err ErrParentTaskCannotBeTheSame) Error() string {
+	return fmt.Sprintf("Tried to set a parents task as the same [TaskID: %v]", err.TaskID)
+}
+
+// ErrCodeParentTaskCannotBeTheSame holds the unique world-error code of this error
+const ErrCodeParentTaskCannotBeTheSame = 4006
+
+// HTTPError holds the http error description
+func (err ErrParentTaskCannotBeTheSame) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusForbidden,
+		Code:     ErrCodeParentTaskCannotBeTheSame,
+		Message:  "You cannot set a parent task to the task itself.",
+	}
+}
+
+// ErrInvalidRelationKind represents an error where the user tries to use an invalid relation kind
+type ErrInvalidRelationKind struct {
+	Kind RelationKind
+}
+
+// IsErrInvalidRelationKind checks if an error is ErrInvalidRelationKind.
+func IsErrInvalidRelationKind(err error) bool {
+	_, ok := err.(ErrInvalidRelationKind)
+	return ok
+}
+
+func (err ErrInvalidRelationKind) Error() string {
+	return fmt.Sprintf("Invalid task relation kind [Kind: %v]", err.Kind)
+}
+
+// ErrCodeInvalidRelationKind holds the unique world-error code of this error
+const ErrCodeInvalidRelationKind = 4007
+
+// HTTPError holds the http error description
+func (err ErrInvalidRelationKind) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidRelationKind,
+		Message:  "The task relation is invalid.",
+	}
+}
+
+// ErrRelationAlreadyExists represents an error where the user tries to create an already existing relation
+type ErrRelationAlreadyExists struct {
+	Kind        RelationKind
+	TaskID      int64
+	OtherTaskID int64
+}
+
+// IsErrRelationAlreadyExists checks if an error is ErrRelationAlreadyExists.
+func IsErrRelationAlreadyExists(err error) bool {
+	_, ok := err.(ErrRelationAlreadyExists)
+	return ok
+}
+
+func (err ErrRelationAlreadyExists) Error() string {
+	return fmt.Sprintf("Task relation already exists [TaskID: %v, OtherTaskID: %v, Kind: %v]", err.TaskID, err.OtherTaskID, err.Kind)
+}
+
+// ErrCodeRelationAlreadyExists holds the unique world-error code of this error
+const ErrCodeRelationAlreadyExists = 4008
+
+// HTTPError holds the http error description
+func (err ErrRelationAlreadyExists) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusConflict,
+		Code:     ErrCodeRelationAlreadyExists,
+		Message:  "The task relation already exists.",
+	}
+}
+
+// ErrRelationDoesNotExist represents an error where a task relation does not exist.
+type ErrRelationDoesNotExist struct {
+	Kind        RelationKind
+	TaskID      int64
+	OtherTaskID int64
+}
+
+// IsErrRelationDoesNotExist checks if an error is ErrRelationDoesNotExist.
 func IsErrRelationDoesNotExist(err error) bool {
 	_, ok := err.(ErrRelationDoesNotExist)
 	return ok
 }
 
-func (err ErrRelationDoesNotExist) Error() string {
-	return fmt.Sprintf("Task relation does not exist [TaskID: %v, OtherTaskID: %v, Kind: %v]", err.TaskID, err.OtherTaskID, err.Kind)
+func (err ErrRelationDoesNotExist) Error() string {
+	return fmt.Sprintf("Task relation does not exist [TaskID: %v, OtherTaskID: %v, Kind: %v]", err.TaskID, err.OtherTaskID, err.Kind)
+}
+
+// ErrCodeRelationDoesNotExist holds the unique world-error code of this error
+const ErrCodeRelationDoesNotExist = 4009
+
+// HTTPError holds the http error description
+func (err ErrRelationDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeRelationDoesNotExist,
+		Message:  "The task relation does not exist.",
+	}
+}
+
+// ErrRelationTasksCannotBeTheSame represents an error where the user tries to relate a task with itself
+type ErrRelationTasksCannotBeTheSame struct {
+	TaskID      int64
+	OtherTaskID int64
+}
+
+// IsErrRelationTasksCannotBeTheSame checks if an error is ErrRelationTasksCannotBeTheSame.
+func IsErrRelationTasksCannotBeTheSame(err error) bool {
+	_, ok := err.(ErrRelationTasksCannotBeTheSame)
+	return ok
+}
+
+func (err ErrRelationTasksCannotBeTheSame) Error() string {
+	return fmt.Sprintf("Tried to relate a task with itself [TaskID: %v, OtherTaskID: %v]", err.TaskID, err.OtherTaskID)
+}
+
+// ErrCodeRelationTasksCannotBeTheSame holds the unique world-error code of this error
+const ErrCodeRelationTasksCannotBeTheSame = 4010
+
+// HTTPError holds the http error description
+func (err ErrRelationTasksCannotBeTheSame) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeRelationTasksCannotBeTheSame,
+		Message:  "You cannot relate a task with itself",
+	}
+}
+
+// ErrTaskAttachmentDoesNotExist represents an error where the user tries to relate a task with itself
+type ErrTaskAttachmentDoesNotExist struct {
+	TaskID       int64
+	AttachmentID int64
+	FileID       int64
+}
+
+// IsErrTaskAttachmentDoesNotExist checks if an error is ErrTaskAttachmentDoesNotExist.
+func IsErrTaskAttachmentDoesNotExist(err error) bool {
+	_, ok := err.(ErrTaskAttachmentDoesNotExist)
+	return ok
+}
+
+func (err ErrTaskAttachmentDoesNotExist) Error() string {
+	return fmt.Sprintf("Task attachment does not exist [TaskID: %d, AttachmentID: %d, FileID: %d]", err.TaskID, err.AttachmentID, err.FileID)
+}
+
+// ErrCodeTaskAttachmentDoesNotExist holds the unique world-error code of this error
+const ErrCodeTaskAttachmentDoesNotExist = 4011
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeTaskAttachmentDoesNotExist,
+		Message:  "This task attachment does not exist.",
+	}
+}
+
+// ErrTaskAttachmentIsTooLarge represents an error where the user tries to relate a task with itself
+type ErrTaskAttachmentIsTooLarge struct {
+	Size uint64
+}
+
+// IsErrTaskAttachmentIsTooLarge checks if an error is ErrTaskAttachmentIsTooLarge.
+func IsErrTaskAttachmentIsTooLarge(err error) bool {
+	_, ok := err.(ErrTaskAttachmentIsTooLarge)
+	return ok
+}
+
+func (err ErrTaskAttachmentIsTooLarge) Error() string {
+	return fmt.Sprintf("Task attachment is too large [Size: %d]", err.Size)
+}
+
+// ErrCodeTaskAttachmentIsTooLarge holds the unique world-error code of this error
+const ErrCodeTaskAttachmentIsTooLarge = 4012
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentIsTooLarge) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTaskAttachmentIsTooLarge,
+		Message:  fmt.Sprintf("The task attachment exceeds the configured file size of %d bytes, filesize was %d", config.FilesMaxSize.GetInt64(), err.Size),
+	}
+}
+
+// ErrInvalidSortParam represents an error where the provided sort param is invalid
+type ErrInvalidSortParam struct {
+	SortBy string
+}
+
+// IsErrInvalidSortParam checks if an error is ErrInvalidSortParam.
+func IsErrInvalidSortParam(err error) bool {
+	_, ok := err.(ErrInvalidSortParam)
+	return ok
+}
+
+func (err ErrInvalidSortParam) Error() string {
+	return fmt.Sprintf("Sort param is invalid [SortBy: %s]", err.SortBy)
+}
+
+// ErrCodeInvalidSortParam holds the unique world-error code of this error
+const ErrCodeInvalidSortParam = 4013
+
+// HTTPError holds the http error description
+func (err ErrInvalidSortParam) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidSortParam,
+		Message:  fmt.Sprintf("The task sort param '%s' is invalid.", err.SortBy),
+	}
+}
+
+// ErrInvalidSortOrder represents an error where the provided sort order is invalid
+type ErrInvalidSortOrder struct {
+	OrderBy sortOrder
+}
+
+// IsErrInvalidSortOrder checks if an error is ErrInvalidSortOrder.
+func IsErrInvalidSortOrder(err error) bool {
+	_, ok := err.(ErrInvalidSortOrder)
+	return ok
+}
+
+func (err ErrInvalidSortOrder) Error() string {
+	return fmt.Sprintf("Sort order is invalid [OrderBy: %s]", err.OrderBy)
+}
+
+// ErrCodeInvalidSortOrder holds the unique world-error code of this error
+const ErrCodeInvalidSortOrder = 4014
+
+// HTTPError holds the http error description
+func (err ErrInvalidSortOrder) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidSortOrder,
+		Message:  fmt.Sprintf("The task sort order '%s' is invalid. Allowed is either asc or desc.", err.OrderBy),
+	}
+}
+
+// ErrTaskCommentDoesNotExist represents an error where a task comment does not exist
+type ErrTaskCommentDoesNotExist struct {
+	ID     int64
+	TaskID int64
+}
+
+// IsErrTaskCommentDoesNotExist checks if an error is ErrTaskCommentDoesNotExist.
+func IsErrTaskCommentDoesNotExist(err error) bool {
+	_, ok := err.(ErrTaskCommentDoesNotExist)
+	return ok
+}
+
+func (err ErrTaskCommentDoesNotExist) Error() string {
+	return fmt.Sprintf("Task comment does not exist [ID: %d, TaskID: %d]", err.ID, err.TaskID)
+}
+
+// ErrCodeTaskCommentDoesNotExist holds the unique world-error code of this error
+const ErrCodeTaskCommentDoesNotExist = 4015
+
+// HTTPError holds the http error description
+func (err ErrTaskCommentDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeTaskCommentDoesNotExist,
+		Message:  "This task comment does not exist",
+	}
+}
+
+// ErrInvalidTaskField represents an error where the provided task field is invalid
+type ErrInvalidTaskField struct {
+	TaskField string
+}
+
+// IsErrInvalidTaskField checks if an error is ErrInvalidTaskField.
+func IsErrInvalidTaskField(err error) bool {
+	_, ok := err.(ErrInvalidTaskField)
+	return ok
+}
+
+func (err ErrInvalidTaskField) Error() string {
+	return fmt.Sprintf("Task Field is invalid [TaskField: %s]", err.TaskField)
+}
+
+// ErrCodeInvalidTaskField holds the unique world-error code of this error
+const ErrCodeInvalidTaskField = 4016
+
+// HTTPError holds the http error description
+func (err ErrInvalidTaskField) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskField,
+		Message:  fmt.Sprintf("The task field '%s' is invalid.", err.TaskField),
+	}
+}
+
+// ErrInvalidTaskFilterComparator represents an error where the provided task field is invalid
+type ErrInvalidTaskFilterComparator struct {
+	Comparator taskFilterComparator
+}
+
+// IsErrInvalidTaskFilterComparator checks if an error is ErrInvalidTaskFilterComparator.
+func IsErrInvalidTaskFilterComparator(err error) bool {
+	_, ok := err.(ErrInvalidTaskFilterComparator)
+	return ok
+}
+
+func (err ErrInvalidTaskFilterComparator) Error() string {
+	return fmt.Sprintf("Task filter comparator is invalid [Comparator: %s]", err.Comparator)
+}
+
+// ErrCodeInvalidTaskFilterComparator holds the unique world-error code of this error
+const ErrCodeInvalidTaskFilterComparator = 4017
+
+// HTTPError holds the http error description
+func (err ErrInvalidTaskFilterComparator) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskFilterComparator,
+		Message:  fmt.Sprintf("The task filter comparator '%s' is invalid.", err.Comparator),
+	}
+}
+
+// ErrInvalidTaskFilterConcatinator represents an error where the provided task field is invalid
+type ErrInvalidTaskFilterConcatinator struct {
+	Concatinator taskFilterConcatinator
+}
+
+// IsErrInvalidTaskFilterConcatinator checks if an error is ErrInvalidTaskFilterConcatinator.
+func IsErrInvalidTaskFilterConcatinator(err error) bool {
+	_, ok := err.(ErrInvalidTaskFilterConcatinator)
+	return ok
+}
+
+func (err ErrInvalidTaskFilterConcatinator) Error() string {
+	return fmt.Sprintf("Task filter concatinator is invalid [Concatinator: %s]", err.Concatinator)
+}
+
+// ErrCodeInvalidTaskFilterConcatinator holds the unique world-error code of this error
+const ErrCodeInvalidTaskFilterConcatinator = 4018
+
+// HTTPError holds the http error description
+func (err ErrInvalidTaskFilterConcatinator) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskFilterConcatinator,
+		Message:  fmt.Sprintf("The task filter concatinator '%s' is invalid.", err.Concatinator),
+	}
+}
+
+// ErrInvalidTaskFilterValue represents an error where the provided task filter value is invalid
+type ErrInvalidTaskFilterValue struct {
+	Value interface{}
+	Field string
+}
+
+// IsErrInvalidTaskFilterValue checks if an error is ErrInvalidTaskFilterValue.
+func IsErrInvalidTaskFilterValue(err error) bool {
+	_, ok := err.(ErrInvalidTaskFilterValue)
+	return ok
+}
+
+func (err ErrInvalidTaskFilterValue) Error() string {
+	return fmt.Sprintf("Task filter value is invalid [Value: %v, Field: %s]", err.Value, err.Field)
+}
+
+// ErrCodeInvalidTaskFilterValue holds the unique world-error code of this error
+const ErrCodeInvalidTaskFilterValue = 4019
+
+// HTTPError holds the http error description
+func (err ErrInvalidTaskFilterValue) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskFilterValue,
+		Message:  fmt.Sprintf("The task filter value '%s' for field '%s' is invalid.", err.Value, err.Field),
+	}
+}
+
+// ErrAttachmentDoesNotBelongToTask represents an error where the provided task cover attachment does not belong to the same task
+type ErrAttachmentDoesNotBelongToTask struct {
+	TaskID       int64
+	AttachmentID int64
+}
+
+// IsErrAttachmentAndCoverMustBelongToTheSameTask checks if an error is ErrAttachmentDoesNotBelongToTask.
+func IsErrAttachmentAndCoverMustBelongToTheSameTask(err error) bool {
+	_, ok := err.(ErrAttachmentDoesNotBelongToTask)
+	return ok
+}
+
+func (err ErrAttachmentDoesNotBelongToTask) Error() string {
+	return fmt.Sprintf("Task attachment and cover image do not belong to the same task [TaskID: %d, AttachmentID: %d]", err.TaskID, err.AttachmentID)
+}
+
+// ErrCodeAttachmentDoesNotBelongToTask holds the unique world-error code of this error
+const ErrCodeAttachmentDoesNotBelongToTask = 4020
+
+// HTTPError holds the http error description
+func (err ErrAttachmentDoesNotBelongToTask) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeAttachmentDoesNotBelongToTask,
+		Message:  "This attachment does not belong to that task.",
+	}
+}
+
+// ErrUserAlreadyAssigned represents an error where the user is already assigned to this task
+type ErrUserAlreadyAssigned struct {
+	TaskID int64
+	UserID int64
+}
+
+// IsErrUserAlreadyAssigned checks if an error is ErrUserAlreadyAssigned.
+func IsErrUserAlreadyAssigned(err error) bool {
+	_, ok := err.(ErrUserAlreadyAssigned)
+	return ok
+}
+
+func (err ErrUserAlreadyAssigned) Error() string {
+	return fmt.Sprintf("User is already assigned to task [TaskID: %d, ID: %d]", err.TaskID, err.UserID)
+}
+
+// ErrCodeUserAlreadyAssigned holds the unique world-error code of this error
+const ErrCodeUserAlreadyAssigned = 4021
+
+// HTTPError holds the http error description
+func (err ErrUserAlreadyAssigned) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeUserAlreadyAssigned,
+		Message:  "This user is already assigned to that task.",
+	}
+}
+
+// ErrReminderRelativeToMissing represents an error where a task has a relative reminder without reference date
+type ErrReminderRelativeToMissing struct {
+	TaskID int64
+}
+
+// IsErrReminderRelativeToMissing checks if an error is ErrReminderRelativeToMissing.
+func IsErrReminderRelativeToMissing(err error) bool {
+	_, ok := err.(ErrReminderRelativeToMissing)
+	return ok
+}
+
+func (err ErrReminderRelativeToMissing) Error() string {
+	return fmt.Sprintf("Task [TaskID: %v] has a relative reminder without relative_to", err.TaskID)
+}
+
+// ErrCodeRelationDoesNotExist holds the unique world-error code of this error
+const ErrCodeReminderRelativeToMissing = 4022
+
+// HTTPError holds the http error description
+func (err ErrReminderRelativeToMissing) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeReminderRelativeToMissing,
+		Message:  "Please provide what the reminder date is relative to",
+	}
+}
+
+// ErrTaskRelationCycle represents an error where the user tries to create an already existing relation
+type ErrTaskRelationCycle struct {
+	Kind        RelationKind
+	TaskID      int64
+	OtherTaskID int64
+}
+
+// IsErrTaskRelationCycle checks if an error is ErrTaskRelationCycle.
+func IsErrTaskRelationCycle(err error) bool {
+	_, ok := err.(ErrTaskRelationCycle)
+	return ok
+}
+
+func (err ErrTaskRelationCycle) Error() string {
+	return fmt.Sprintf("Task relation cycle detectetd [TaskID: %v, OtherTaskID: %v, Kind: %v]", err.TaskID, err.OtherTaskID, err.Kind)
+}
+
+// ErrCodeTaskRelationCycle holds the unique world-error code of this error
+const ErrCodeTaskRelationCycle = 4023
+
+// HTTPError holds the http error description
+func (err ErrTaskRelationCycle) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusConflict,
+		Code:     ErrCodeTaskRelationCycle,
+		Message:  "This task relation would create a cycle.",
+	}
+}
+
+// ErrInvalidFilterExpression represents an error where the task filter expression was invalid
+type ErrInvalidFilterExpression struct {
+	Expression      string
+	ExpressionError error
+}
+
+// IsErrInvalidFilterExpression checks if an error is ErrInvalidFilterExpression.
+func IsErrInvalidFilterExpression(err error) bool {
+	_, ok := err.(ErrInvalidFilterExpression)
+	return ok
+}
+
+func (err ErrInvalidFilterExpression) Error() string {
+	return fmt.Sprintf("Task filter expression '%s' is invalid [ExpressionError: %v]", err.Expression, err.ExpressionError)
+}
+
+// ErrCodeInvalidFilterExpression holds the unique world-error code of this error
+const ErrCodeInvalidFilterExpression = 4024
+
+// HTTPError holds the http error description
+func (err ErrInvalidFilterExpression) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidFilterExpression,
+		Message:  fmt.Sprintf("The filter expression '%s' is invalid: %v", err.Expression, err.ExpressionError),
+	}
+}
+
+// ErrInvalidReactionEntityKind represents an error where the reaction kind is invalid
+type ErrInvalidReactionEntityKind struct {
+	Kind string
+}
+
+// IsErrInvalidReactionEntityKind checks if an error is ErrInvalidReactionEntityKind.
+func IsErrInvalidReactionEntityKind(err error) bool {
+	_, ok := err.(ErrInvalidReactionEntityKind)
+	return ok
+}
+
+func (err ErrInvalidReactionEntityKind) Error() string {
+	return fmt.Sprintf("Reaction kind %s is invalid", err.Kind)
+}
+
+// ErrCodeInvalidReactionEntityKind holds the unique world-error code of this error
+const ErrCodeInvalidReactionEntityKind = 4025
+
+// HTTPError holds the http error description
+func (err ErrInvalidReactionEntityKind) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidReactionEntityKind,
+		Message:  fmt.Sprintf("The reaction kind '%s' is invalid.", err.Kind),
+	}
+}
+
+// ErrTaskParentCycle represents an error where a task would be moved below one of its own subtasks
+type ErrTaskParentCycle struct {
+	TaskID       int64
+	ParentTaskID int64
+}
+
+// IsErrTaskParentCycle checks if an error is ErrTaskParentCycle.
+func IsErrTaskParentCycle(err error) bool {
+	_, ok := err.(ErrTaskParentCycle)
+	return ok
+}
+
+func (err ErrTaskParentCycle) Error() string {
+	return fmt.Sprintf("Task parent cycle detected [TaskID: %v, ParentTaskID: %v]", err.TaskID, err.ParentTaskID)
+}
+
+// ErrCodeTaskParentCycle holds the unique world-error code of this error
+const ErrCodeTaskParentCycle = 4026
+
+// HTTPError holds the http error description
+func (err ErrTaskParentCycle) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusConflict,
+		Code:     ErrCodeTaskParentCycle,
+		Message:  "A task cannot be moved below one of its own subtasks.",
+	}
+}
+
+// ErrTaskHierarchyTooDeep represents an error where a task hierarchy would exceed the maximum depth
+type ErrTaskHierarchyTooDeep struct {
+	TaskID   int64
+	MaxDepth int64
+}
+
+// IsErrTaskHierarchyTooDeep checks if an error is ErrTaskHierarchyTooDeep.
+func IsErrTaskHierarchyTooDeep(err error) bool {
+	_, ok := err.(ErrTaskHierarchyTooDeep)
+	return ok
+}
+
+func (err ErrTaskHierarchyTooDeep) Error() string {
+	return fmt.Sprintf("Task hierarchy is too deep [TaskID: %v, MaxDepth: %v]", err.TaskID, err.MaxDepth)
+}
+
+// ErrCodeTaskHierarchyTooDeep holds the unique world-error code of this error
+const ErrCodeTaskHierarchyTooDeep = 4027
+
+// HTTPError holds the http error description
+func (err ErrTaskHierarchyTooDeep) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTaskHierarchyTooDeep,
+		Message:  fmt.Sprintf("Tasks can only be nested %d levels deep.", err.MaxDepth),
+	}
+}
+
+// ErrTaskIsBlocked represents an error where a task with unresolved blockers is marked as done
+type ErrTaskIsBlocked struct {
+	TaskID          int64
+	BlockingTaskIDs []int64
+}
+
+// IsErrTaskIsBlocked checks if an error is ErrTaskIsBlocked.
+func IsErrTaskIsBlocked(err error) bool {
+	_, ok := err.(ErrTaskIsBlocked)
+	return ok
+}
+
+func (err ErrTaskIsBlocked) Error() string {
+	return fmt.Sprintf("Task is blocked by other tasks [TaskID: %v, BlockingTaskIDs: %v]", err.TaskID, err.BlockingTaskIDs)
 }
 
-// ErrCodeRelationDoesNotExist holds the unique world-error code of this error
-const ErrCodeRelationDoesNotExist = 4009
+// ErrCodeTaskIsBlocked holds the unique world-error code of this error
+const ErrCodeTaskIsBlocked = 4028
 
 // HTTPError holds the http error description
-func (err ErrRelationDoesNotExist) HTTPError() web.HTTPError {
+func (err ErrTaskIsBlocked) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusNotFound,
-		Code:     ErrCodeRelationDoesNotExist,
-		Message:  "The task relation does not exist.",
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeTaskIsBlocked,
+		Message:  "This task is blocked by other tasks which are not done yet.",
 	}
 }
 
-// ErrRelationTasksCannotBeTheSame represents an error where the user tries to relate a task with itself
-type ErrRelationTasksCannotBeTheSame struct {
-	TaskID      int64
-	OtherTaskID int64
+// ErrChecklistItemDoesNotExist represents an error where a checklist item does not exist
+type ErrChecklistItemDoesNotExist struct {
+	ID int64
 }
 
-// IsErrRelationTasksCannotBeTheSame checks if an error is ErrRelationTasksCannotBeTheSame.
-func IsErrRelationTasksCannotBeTheSame(err error) bool {
-	_, ok := err.(ErrRelationTasksCannotBeTheSame)
+// IsErrChecklistItemDoesNotExist checks if an error is ErrChecklistItemDoesNotExist.
+func IsErrChecklistItemDoesNotExist(err error) bool {
+	_, ok := err.(ErrChecklistItemDoesNotExist)
 	return ok
 }
 
-func (err ErrRelationTasksCannotBeTheSame) Error() string {
-	return fmt.Sprintf("Tried to relate a task with itself [TaskID: %v, OtherTaskID: %v]", err.TaskID, err.OtherTaskID)
+func (err ErrChecklistItemDoesNotExist) Error() string {
+	return fmt.Sprintf("Checklist item does not exist [ID: %v]", err.ID)
 }
 
-// ErrCodeRelationTasksCannotBeTheSame holds the unique world-error code of this error
-const ErrCodeRelationTasksCannotBeTheSame = 4010
+// ErrCodeChecklistItemDoesNotExist holds the unique world-error code of this error
+const ErrCodeChecklistItemDoesNotExist = 4029
 
 // HTTPError holds the http error description
-func (err ErrRelationTasksCannotBeTheSame) HTTPError() web.HTTPError {
+func (err ErrChecklistItemDoesNotExist) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeRelationTasksCannotBeTheSame,
-		Message:  "You cannot relate a task with itself",
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeChecklistItemDoesNotExist,
+		Message:  "This checklist item does not exist.",
 	}
 }
 
-// ErrTaskAttachmentDoesNotExist represents an error where the user tries to relate a task with itself
-type ErrTaskAttachmentDoesNotExist struct {
-	TaskID       int64
-	AttachmentID int64
-	FileID       int64
+// ErrInvalidRepeatRule represents an error where a task has an invalid or unsupported repeat rule
+type ErrInvalidRepeatRule struct {
+	Rule   string
+	Reason string
 }
 
-// IsErrTaskAttachmentDoesNotExist checks if an error is ErrTaskAttachmentDoesNotExist.
-func IsErrTaskAttachmentDoesNotExist(err error) bool {
-	_, ok := err.(ErrTaskAttachmentDoesNotExist)
+// IsErrInvalidRepeatRule checks if an error is ErrInvalidRepeatRule.
+func IsErrInvalidRepeatRule(err error) bool {
+	_, ok := err.(ErrInvalidRepeatRule)
 	return ok
 }
 
-func (err ErrTaskAttachmentDoesNotExist) Error() string {
-	return fmt.Sprintf("Task attachment does not exist [TaskID: %d, AttachmentID: %d, FileID: %d]", err.TaskID, err.AttachmentID, err.FileID)
+func (err ErrInvalidRepeatRule) Error() string {
+	return fmt.Sprintf("Repeat rule is invalid [Rule: %s, Reason: %s]", err.Rule, err.Reason)
 }
 
-// ErrCodeTaskAttachmentDoesNotExist holds the unique world-error code of this error
-const ErrCodeTaskAttachmentDoesNotExist = 4011
+// ErrCodeInvalidRepeatRule holds the unique world-error code of this error
+const ErrCodeInvalidRepeatRule = 4030
 
 // HTTPError holds the http error description
-func (err ErrTaskAttachmentDoesNotExist) HTTPError() web.HTTPError {
+func (err ErrInvalidRepeatRule) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusNotFound,
-		Code:     ErrCodeTaskAttachmentDoesNotExist,
-		Message:  "This task attachment does not exist.",
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidRepeatRule,
+		Message:  fmt.Sprintf("The repeat rule is invalid: %s.", err.Reason),
 	}
 }
 
-// ErrTaskAttachmentIsTooLarge represents an error where the user tries to relate a task with itself
-type ErrTaskAttachmentIsTooLarge struct {
-	Size uint64
+// ErrInvalidReminderSnooze represents an error where a reminder snooze has no valid duration or target time
+type ErrInvalidReminderSnooze struct {
+	TaskID int64
 }
 
-// IsErrTaskAttachmentIsTooLarge checks if an error is ErrTaskAttachmentIsTooLarge.
-func IsErrTaskAttachmentIsTooLarge(err error) bool {
-	_, ok := err.(ErrTaskAttachmentIsTooLarge)
+// IsErrInvalidReminderSnooze checks if an error is ErrInvalidReminderSnooze.
+func IsErrInvalidReminderSnooze(err error) bool {
+	_, ok := err.(ErrInvalidReminderSnooze)
 	return ok
 }
 
-func (err ErrTaskAttachmentIsTooLarge) Error() string {
-	return fmt.Sprintf("Task attachment is too large [Size: %d]", err.Size)
+func (err ErrInvalidReminderSnooze) Error() string {
+	return fmt.Sprintf("Reminder snooze is invalid [TaskID: %d]", err.TaskID)
 }
 
-// ErrCodeTaskAttachmentIsTooLarge holds the unique world-error code of this error
-const ErrCodeTaskAttachmentIsTooLarge = 4012
+// ErrCodeInvalidReminderSnooze holds the unique world-error code of this error
+const ErrCodeInvalidReminderSnooze = 4031
 
 // HTTPError holds the http error description
-func (err ErrTaskAttachmentIsTooLarge) HTTPError() web.HTTPError {
+func (err ErrInvalidReminderSnooze) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeTaskAttachmentIsTooLarge,
-		Message:  fmt.Sprintf("The task attachment exceeds the configured file size of %d bytes, filesize was %d", config.FilesMaxSize.GetInt64(), err.Size),
+		Code:     ErrCodeInvalidReminderSnooze,
+		Message:  "A reminder must be snoozed either by a positive duration or until a time in the future.",
 	}
 }
 
-// ErrInvalidSortParam represents an error where the provided sort param is invalid
-type ErrInvalidSortParam struct {
-	SortBy string
+// ErrReminderDoesNotExist represents an error where a reminder does not exist on a task
+type ErrReminderDoesNotExist struct {
+	TaskID   int64
+	Reminder time.Time
 }
 
-// IsErrInvalidSortParam checks if an error is ErrInvalidSortParam.
-func IsErrInvalidSortParam(err error) bool {
-	_, ok := err.(ErrInvalidSortParam)
+// IsErrReminderDoesNotExist checks if an error is ErrReminderDoesNotExist.
+func IsErrReminderDoesNotExist(err error) bool {
+	_, ok := err.(ErrReminderDoesNotExist)
 	return ok
 }
 
-func (err ErrInvalidSortParam) Error() string {
-	return fmt.Sprintf("Sort param is invalid [SortBy: %s]", err.SortBy)
+func (err ErrReminderDoesNotExist) Error() string {
+	return fmt.Sprintf("Reminder does not exist [TaskID: %d, Reminder: %s]", err.TaskID, err.Reminder)
 }
 
-// ErrCodeInvalidSortParam holds the unique world-error code of this error
-const ErrCodeInvalidSortParam = 4013
+// ErrCodeReminderDoesNotExist holds the unique world-error code of this error
+const ErrCodeReminderDoesNotExist = 4032
 
 // HTTPError holds the http error description
-func (err ErrInvalidSortParam) HTTPError() web.HTTPError {
+func (err ErrReminderDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeReminderDoesNotExist,
+		Message:  "This task does not have a reminder at this time.",
+	}
+}
+
+// ErrEmptyTaskPatch represents an error where a bulk task update does not change anything
+type ErrEmptyTaskPatch struct{}
+
+// IsErrEmptyTaskPatch checks if an error is ErrEmptyTaskPatch.
+func IsErrEmptyTaskPatch(err error) bool {
+	_, ok := err.(ErrEmptyTaskPatch)
+	return ok
+}
+
+func (err ErrEmptyTaskPatch) Error() string {
+	return "Task patch is empty"
+}
+
+// ErrCodeEmptyTaskPatch holds the unique world-error code of this error
+const ErrCodeEmptyTaskPatch = 4033
+
+// HTTPError holds the http error description
+func (err ErrEmptyTaskPatch) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeInvalidSortParam,
-		Message:  fmt.Sprintf("The task sort param '%s' is invalid.", err.SortBy),
+		Code:     ErrCodeEmptyTaskPatch,
+		Message:  "The patch needs to change at least one field of the tasks.",
 	}
 }
 
-// ErrInvalidSortOrder represents an error where the provided sort order is invalid
-type ErrInvalidSortOrder struct {
-	OrderBy sortOrder
+// ErrInvalidDescriptionFormat represents an error where a task description format is unknown
+type ErrInvalidDescriptionFormat struct {
+	Format TaskDescriptionFormat
 }
 
-// IsErrInvalidSortOrder checks if an error is ErrInvalidSortOrder.
-func IsErrInvalidSortOrder(err error) bool {
-	_, ok := err.(ErrInvalidSortOrder)
+// IsErrInvalidDescriptionFormat checks if an error is ErrInvalidDescriptionFormat.
+func IsErrInvalidDescriptionFormat(err error) bool {
+	_, ok := err.(ErrInvalidDescriptionFormat)
 	return ok
 }
 
-func (err ErrInvalidSortOrder) Error() string {
-	return fmt.Sprintf("Sort order is invalid [OrderBy: %s]", err.OrderBy)
+func (err ErrInvalidDescriptionFormat) Error() string {
+	return fmt.Sprintf("Invalid task description format [Format: %s]", err.Format)
 }
 
-// ErrCodeInvalidSortOrder holds the unique world-error code of this error
-const ErrCodeInvalidSortOrder = 4014
+// ErrCodeInvalidDescriptionFormat holds the unique world-error code of this error
+const ErrCodeInvalidDescriptionFormat = 4034
 
 // HTTPError holds the http error description
-func (err ErrInvalidSortOrder) HTTPError() web.HTTPError {
+func (err ErrInvalidDescriptionFormat) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeInvalidSortOrder,
-		Message:  fmt.Sprintf("The task sort order '%s' is invalid. Allowed is either asc or desc.", err.OrderBy),
+		Code:     ErrCodeInvalidDescriptionFormat,
+		Message:  "The description format must be either 'html' or 'markdown'.",
 	}
 }
 
-// ErrTaskCommentDoesNotExist represents an error where a task comment does not exist
-type ErrTaskCommentDoesNotExist struct {
-	ID     int64
-	TaskID int64
+// ErrInvalidTaskCoverURL represents an error where the url of a task cover image is invalid or can't be downloaded
+type ErrInvalidTaskCoverURL struct {
+	URL string
 }
 
-// IsErrTaskCommentDoesNotExist checks if an error is ErrTaskCommentDoesNotExist.
-func IsErrTaskCommentDoesNotExist(err error) bool {
-	_, ok := err.(ErrTaskCommentDoesNotExist)
+// IsErrInvalidTaskCoverURL checks if an error is ErrInvalidTaskCoverURL.
+func IsErrInvalidTaskCoverURL(err error) bool {
+	_, ok := err.(ErrInvalidTaskCoverURL)
 	return ok
 }
 
-func (err ErrTaskCommentDoesNotExist) Error() string {
-	return fmt.Sprintf("Task comment does not exist [ID: %d, TaskID: %d]", err.ID, err.TaskID)
+func (err ErrInvalidTaskCoverURL) Error() string {
+	return fmt.Sprintf("Invalid task cover url [URL: %s]", err.URL)
 }
 
-// ErrCodeTaskCommentDoesNotExist holds the unique world-error code of this error
-const ErrCodeTaskCommentDoesNotExist = 4015
+// ErrCodeInvalidTaskCoverURL holds the unique world-error code of this error
+const ErrCodeInvalidTaskCoverURL = 4035
 
 // HTTPError holds the http error description
-func (err ErrTaskCommentDoesNotExist) HTTPError() web.HTTPError {
+func (err ErrInvalidTaskCoverURL) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusNotFound,
-		Code:     ErrCodeTaskCommentDoesNotExist,
-		Message:  "This task comment does not exist",
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskCoverURL,
+		Message:  "The cover image url is invalid or the image could not be downloaded.",
 	}
 }
 
-// ErrInvalidTaskField represents an error where the provided task field is invalid
-type ErrInvalidTaskField struct {
-	TaskField string
+// ErrTaskCoverIsNotAnImage represents an error where a file which should become a task cover is not an image
+type ErrTaskCoverIsNotAnImage struct {
+	TaskID int64
+	Mime   string
 }
 
-// IsErrInvalidTaskField checks if an error is ErrInvalidTaskField.
-func IsErrInvalidTaskField(err error) bool {
-	_, ok := err.(ErrInvalidTaskField)
+// IsErrTaskCoverIsNotAnImage checks if an error is ErrTaskCoverIsNotAnImage.
+func IsErrTaskCoverIsNotAnImage(err error) bool {
+	_, ok := err.(ErrTaskCoverIsNotAnImage)
 	return ok
 }
 
-func (err ErrInvalidTaskField) Error() string {
-	return fmt.Sprintf("Task Field is invalid [TaskField: %s]", err.TaskField)
+func (err ErrTaskCoverIsNotAnImage) Error() string {
+	return fmt.Sprintf("Task cover is not an image [TaskID: %d, Mime: %s]", err.TaskID, err.Mime)
 }
 
-// ErrCodeInvalidTaskField holds the unique world-error code of this error
-const ErrCodeInvalidTaskField = 4016
+// ErrCodeTaskCoverIsNotAnImage holds the unique world-error code of this error
+const ErrCodeTaskCoverIsNotAnImage = 4036
 
 // HTTPError holds the http error description
-func (err ErrInvalidTaskField) HTTPError() web.HTTPError {
+func (err ErrTaskCoverIsNotAnImage) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeInvalidTaskField,
-		Message:  fmt.Sprintf("The task field '%s' is invalid.", err.TaskField),
+		Code:     ErrCodeTaskCoverIsNotAnImage,
+		Message:  "The cover image of a task must be an image.",
 	}
 }
 
-// ErrInvalidTaskFilterComparator represents an error where the provided task field is invalid
-type ErrInvalidTaskFilterComparator struct {
-	Comparator taskFilterComparator
+// ErrUserAlreadyWatchesTask represents an error where a user already watches a task
+type ErrUserAlreadyWatchesTask struct {
+	TaskID int64
+	UserID int64
 }
 
-// IsErrInvalidTaskFilterComparator checks if an error is ErrInvalidTaskFilterComparator.
-func IsErrInvalidTaskFilterComparator(err error) bool {
-	_, ok := err.(ErrInvalidTaskFilterComparator)
+// IsErrUserAlreadyWatchesTask checks if an error is ErrUserAlreadyWatchesTask.
+func IsErrUserAlreadyWatchesTask(err error) bool {
+	_, ok := err.(ErrUserAlreadyWatchesTask)
 	return ok
 }
 
-func (err ErrInvalidTaskFilterComparator) Error() string {
-	return fmt.Sprintf("Task filter comparator is invalid [Comparator: %s]", err.Comparator)
+func (err ErrUserAlreadyWatchesTask) Error() string {
+	return fmt.Sprintf("User already watches task [TaskID: %d, UserID: %d]", err.TaskID, err.UserID)
 }
 
-// ErrCodeInvalidTaskFilterComparator holds the unique world-error code of this error
-const ErrCodeInvalidTaskFilterComparator = 4017
+// ErrCodeUserAlreadyWatchesTask holds the unique world-error code of this error
+const ErrCodeUserAlreadyWatchesTask = 4037
 
 // HTTPError holds the http error description
-func (err ErrInvalidTaskFilterComparator) HTTPError() web.HTTPError {
+func (err ErrUserAlreadyWatchesTask) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeInvalidTaskFilterComparator,
-		Message:  fmt.Sprintf("The task filter comparator '%s' is invalid.", err.Comparator),
+		Code:     ErrCodeUserAlreadyWatchesTask,
+		Message:  "This user already watches that task.",
 	}
 }
 
-// ErrInvalidTaskFilterConcatinator represents an error where the provided task field is invalid
-type ErrInvalidTaskFilterConcatinator struct {
-	Concatinator taskFilterConcatinator
+// ErrInvalidQuickAddMagicMode represents an error where the quick add magic mode is invalid
+type ErrInvalidQuickAddMagicMode struct {
+	Mode QuickAddMagicMode
 }
 
-// IsErrInvalidTaskFilterConcatinator checks if an error is ErrInvalidTaskFilterConcatinator.
-func IsErrInvalidTaskFilterConcatinator(err error) bool {
-	_, ok := err.(ErrInvalidTaskFilterConcatinator)
+// IsErrInvalidQuickAddMagicMode checks if an error is ErrInvalidQuickAddMagicMode.
+func IsErrInvalidQuickAddMagicMode(err error) bool {
+	_, ok := err.(ErrInvalidQuickAddMagicMode)
 	return ok
 }
 
-func (err ErrInvalidTaskFilterConcatinator) Error() string {
-	return fmt.Sprintf("Task filter concatinator is invalid [Concatinator: %s]", err.Concatinator)
+func (err ErrInvalidQuickAddMagicMode) Error() string {
+	return fmt.Sprintf("Invalid quick add magic mode [Mode: %s]", err.Mode)
 }
 
-// ErrCodeInvalidTaskFilterConcatinator holds the unique world-error code of this error
-const ErrCodeInvalidTaskFilterConcatinator = 4018
+// ErrCodeInvalidQuickAddMagicMode holds the unique world-error code of this error
+const ErrCodeInvalidQuickAddMagicMode = 4038
 
 // HTTPError holds the http error description
-func (err ErrInvalidTaskFilterConcatinator) HTTPError() web.HTTPError {
+func (err ErrInvalidQuickAddMagicMode) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeInvalidTaskFilterConcatinator,
-		Message:  fmt.Sprintf("The task filter concatinator '%s' is invalid.", err.Concatinator),
+		Code:     ErrCodeInvalidQuickAddMagicMode,
+		Message:  "The quick add magic mode must be one of 'vikunja', 'todoist' or 'disabled'.",
 	}
 }
 
-// ErrInvalidTaskFilterValue represents an error where the provided task filter value is invalid
-type ErrInvalidTaskFilterValue struct {
-	Value interface{}
-	Field string
+// ErrInvalidTaskAttachmentURL represents an error where the url of a link attachment is invalid
+type ErrInvalidTaskAttachmentURL struct {
+	URL string
 }
 
-// IsErrInvalidTaskFilterValue checks if an error is ErrInvalidTaskFilterValue.
-func IsErrInvalidTaskFilterValue(err error) bool {
-	_, ok := err.(ErrInvalidTaskFilterValue)
+// IsErrInvalidTaskAttachmentURL checks if an error is ErrInvalidTaskAttachmentURL.
+func IsErrInvalidTaskAttachmentURL(err error) bool {
+	_, ok := err.(ErrInvalidTaskAttachmentURL)
 	return ok
 }
 
-func (err ErrInvalidTaskFilterValue) Error() string {
-	return fmt.Sprintf("Task filter value is invalid [Value: %v, Field: %s]", err.Value, err.Field)
+func (err ErrInvalidTaskAttachmentURL) Error() string {
+	return fmt.Sprintf("Invalid task attachment url [URL: %s]", err.URL)
 }
 
-// ErrCodeInvalidTaskFilterValue holds the unique world-error code of this error
-const ErrCodeInvalidTaskFilterValue = 4019
+// ErrCodeInvalidTaskAttachmentURL holds the unique world-error code of this error
+const ErrCodeInvalidTaskAttachmentURL = 4039
 
 // HTTPError holds the http error description
-func (err ErrInvalidTaskFilterValue) HTTPError() web.HTTPError {
+func (err ErrInvalidTaskAttachmentURL) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeInvalidTaskFilterValue,
-		Message:  fmt.Sprintf("The task filter value '%s' for field '%s' is invalid.", err.Value, err.Field),
+		Code:     ErrCodeInvalidTaskAttachmentURL,
+		Message:  "The attachment url is invalid. It must be a http or https url.",
 	}
 }
 
-// ErrAttachmentDoesNotBelongToTask represents an error where the provided task cover attachment does not belong to the same task
-type ErrAttachmentDoesNotBelongToTask struct {
+// ErrTaskAttachmentIsLink represents an error where a link attachment was requested for download
+type ErrTaskAttachmentIsLink struct {
 	TaskID       int64
 	AttachmentID int64
 }
 
-// IsErrAttachmentAndCoverMustBelongToTheSameTask checks if an error is ErrAttachmentDoesNotBelongToTask.
-func IsErrAttachmentAndCoverMustBelongToTheSameTask(err error) bool {
-	_, ok := err.(ErrAttachmentDoesNotBelongToTask)
+// IsErrTaskAttachmentIsLink checks if an error is ErrTaskAttachmentIsLink.
+func IsErrTaskAttachmentIsLink(err error) bool {
+	_, ok := err.(ErrTaskAttachmentIsLink)
 	return ok
 }
 
-func (err ErrAttachmentDoesNotBelongToTask) Error() string {
-	return fmt.Sprintf("Task attachment and cover image do not belong to the same task [TaskID: %d, AttachmentID: %d]", err.TaskID, err.AttachmentID)
+func (err ErrTaskAttachmentIsLink) Error() string {
+	return fmt.Sprintf("Task attachment is a link [TaskID: %d, AttachmentID: %d]", err.TaskID, err.AttachmentID)
 }
 
-// ErrCodeAttachmentDoesNotBelongToTask holds the unique world-error code of this error
-const ErrCodeAttachmentDoesNotBelongToTask = 4020
+// ErrCodeTaskAttachmentIsLink holds the unique world-error code of this error
+const ErrCodeTaskAttachmentIsLink = 4040
 
 // HTTPError holds the http error description
-func (err ErrAttachmentDoesNotBelongToTask) HTTPError() web.HTTPError {
+func (err ErrTaskAttachmentIsLink) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeAttachmentDoesNotBelongToTask,
-		Message:  "This attachment does not belong to that task.",
+		Code:     ErrCodeTaskAttachmentIsLink,
+		Message:  "This attachment is a link and has no file which could be downloaded.",
 	}
 }
 
-// ErrUserAlreadyAssigned represents an error where the user is already assigned to this task
-type ErrUserAlreadyAssigned struct {
-	TaskID int64
-	UserID int64
+// ErrInvalidTaskLocation represents an error where the location of a task is invalid
+type ErrInvalidTaskLocation struct {
+	TaskID    int64
+	Latitude  *float64
+	Longitude *float64
 }
 
-// IsErrUserAlreadyAssigned checks if an error is ErrUserAlreadyAssigned.
-func IsErrUserAlreadyAssigned(err error) bool {
-	_, ok := err.(ErrUserAlreadyAssigned)
+// IsErrInvalidTaskLocation checks if an error is ErrInvalidTaskLocation.
+func IsErrInvalidTaskLocation(err error) bool {
+	_, ok := err.(ErrInvalidTaskLocation)
 	return ok
 }
 
-func (err ErrUserAlreadyAssigned) Error() string {
-	return fmt.Sprintf("User is already assigned to task [TaskID: %d, ID: %d]", err.TaskID, err.UserID)
+func (err ErrInvalidTaskLocation) Error() string {
+	return fmt.Sprintf("Invalid task location [TaskID: %d]", err.TaskID)
 }
 
-// ErrCodeUserAlreadyAssigned holds the unique world-error code of this error
-const ErrCodeUserAlreadyAssigned = 4021
+// ErrCodeInvalidTaskLocation holds the unique world-error code of this error
+const ErrCodeInvalidTaskLocation = 4041
 
 // HTTPError holds the http error description
-func (err ErrUserAlreadyAssigned) HTTPError() web.HTTPError {
+func (err ErrInvalidTaskLocation) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeUserAlreadyAssigned,
-		Message:  "This user is already assigned to that task.",
+		Code:     ErrCodeInvalidTaskLocation,
+		Message:  "The task location is invalid. Latitude and longitude must be provided together, the latitude must be between -90 and 90 and the longitude between -180 and 180.",
 	}
 }
 
-// ErrReminderRelativeToMissing represents an error where a task has a relative reminder without reference date
-type ErrReminderRelativeToMissing struct {
-	TaskID int64
+// ErrInvalidTaskPercentDoneMode represents an error where the percent done mode of a task is invalid
+type ErrInvalidTaskPercentDoneMode struct {
+	Mode TaskPercentDoneMode
 }
 
-// IsErrReminderRelativeToMissing checks if an error is ErrReminderRelativeToMissing.
-func IsErrReminderRelativeToMissing(err error) bool {
-	_, ok := err.(ErrReminderRelativeToMissing)
+// IsErrInvalidTaskPercentDoneMode checks if an error is ErrInvalidTaskPercentDoneMode.
+func IsErrInvalidTaskPercentDoneMode(err error) bool {
+	_, ok := err.(ErrInvalidTaskPercentDoneMode)
 	return ok
 }
 
-func (err ErrReminderRelativeToMissing) Error() string {
-	return fmt.Sprintf("Task [TaskID: %v] has a relative reminder without relative_to", err.TaskID)
+func (err ErrInvalidTaskPercentDoneMode) Error() string {
+	return fmt.Sprintf("Invalid task percent done mode [Mode: %s]", err.Mode)
 }
 
-// ErrCodeRelationDoesNotExist holds the unique world-error code of this error
-const ErrCodeReminderRelativeToMissing = 4022
+// ErrCodeInvalidTaskPercentDoneMode holds the unique world-error code of this error
+const ErrCodeInvalidTaskPercentDoneMode = 4042
 
 // HTTPError holds the http error description
-func (err ErrReminderRelativeToMissing) HTTPError() web.HTTPError {
+func (err ErrInvalidTaskPercentDoneMode) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeReminderRelativeToMissing,
-		Message:  "Please provide what the reminder date is relative to",
+		Code:     ErrCodeInvalidTaskPercentDoneMode,
+		Message:  "The percent done mode must be one of 'manual', 'subtasks', 'checklist' or 'subtasks_and_checklist'.",
 	}
 }
 
-// ErrTaskRelationCycle represents an error where the user tries to create an already existing relation
-type ErrTaskRelationCycle struct {
-	Kind        RelationKind
-	TaskID      int64
-	OtherTaskID int64
+// ErrTaskDependencyCycle represents an error where the blocking relations of tasks form a cycle
+type ErrTaskDependencyCycle struct {
+	ProjectID int64
+	TaskIDs   []int64
 }
 
-// IsErrTaskRelationCycle checks if an error is ErrTaskRelationCycle.
-func IsErrTaskRelationCycle(err error) bool {
-	_, ok := err.(ErrTaskRelationCycle)
+// IsErrTaskDependencyCycle checks if an error is ErrTaskDependencyCycle.
+func IsErrTaskDependencyCycle(err error) bool {
+	_, ok := err.(ErrTaskDependencyCycle)
 	return ok
 }
 
-func (err ErrTaskRelationCycle) Error() string {
-	return fmt.Sprintf("Task relation cycle detectetd [TaskID: %v, OtherTaskID: %v, Kind: %v]", err.TaskID, err.OtherTaskID, err.Kind)
+func (err ErrTaskDependencyCycle) Error() string {
+	return fmt.Sprintf("Blocking relations of tasks form a cycle [ProjectID: %d, TaskIDs: %v]", err.ProjectID, err.TaskIDs)
 }
 
-// ErrCodeTaskRelationCycle holds the unique world-error code of this error
-const ErrCodeTaskRelationCycle = 4023
+// ErrCodeTaskDependencyCycle holds the unique world-error code of this error
+const ErrCodeTaskDependencyCycle = 4043
 
 // HTTPError holds the http error description
-func (err ErrTaskRelationCycle) HTTPError() web.HTTPError {
+func (err ErrTaskDependencyCycle) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusConflict,
-		Code:     ErrCodeTaskRelationCycle,
-		Message:  "This task relation would create a cycle.",
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeTaskDependencyCycle,
+		Message:  "The tasks can't be scheduled because their blocking relations form a cycle.",
 	}
 }
 
-// ErrInvalidFilterExpression represents an error where the task filter expression was invalid
-type ErrInvalidFilterExpression struct {
-	Expression      string
-	ExpressionError error
+// ErrInvalidFilterTimezone represents an error where the time zone of a task filter does not exist
+type ErrInvalidFilterTimezone struct {
+	Timezone string
 }
 
-// IsErrInvalidFilterExpression checks if an error is ErrInvalidFilterExpression.
-func IsErrInvalidFilterExpression(err error) bool {
-	_, ok := err.(ErrInvalidFilterExpression)
+// IsErrInvalidFilterTimezone checks if an error is ErrInvalidFilterTimezone.
+func IsErrInvalidFilterTimezone(err error) bool {
+	_, ok := err.(*ErrInvalidFilterTimezone)
 	return ok
 }
 
-func (err ErrInvalidFilterExpression) Error() string {
-	return fmt.Sprintf("Task filter expression '%s' is invalid [ExpressionError: %v]", err.Expression, err.ExpressionError)
+func (err *ErrInvalidFilterTimezone) Error() string {
+	return fmt.Sprintf("Task filter time zone is invalid [Timezone: %s]", err.Timezone)
 }
 
-// ErrCodeInvalidFilterExpression holds the unique world-error code of this error
-const ErrCodeInvalidFilterExpression = 4024
+// ErrCodeInvalidFilterTimezone holds the unique world-error code of this error
+const ErrCodeInvalidFilterTimezone = 4044
 
 // HTTPError holds the http error description
-func (err ErrInvalidFilterExpression) HTTPError() web.HTTPError {
+func (err *ErrInvalidFilterTimezone) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeInvalidFilterExpression,
-		Message:  fmt.Sprintf("The filter expression '%s' is invalid: %v", err.Expression, err.ExpressionError),
+		Code:     ErrCodeInvalidFilterTimezone,
+		Message:  fmt.Sprintf("The filter time zone '%s' does not exist.", err.Timezone),
 	}
 }
 
-// ErrInvalidReactionEntityKind represents an error where the reaction kind is invalid
-type ErrInvalidReactionEntityKind struct {
-	Kind string
+// ErrTaskVetoedByPlugin represents an error where a plugin rejected creating, updating or deleting a task
+type ErrTaskVetoedByPlugin struct {
+	TaskID int64
+	Plugin string
+	Reason string
 }
 
-// IsErrInvalidReactionEntityKind checks if an error is ErrInvalidReactionEntityKind.
-func IsErrInvalidReactionEntityKind(err error) bool {
-	_, ok := err.(ErrInvalidReactionEntityKind)
+// IsErrTaskVetoedByPlugin checks if an error is ErrTaskVetoedByPlugin.
+func IsErrTaskVetoedByPlugin(err error) bool {
+	_, ok := err.(*ErrTaskVetoedByPlugin)
 	return ok
 }
 
-func (err ErrInvalidReactionEntityKind) Error() string {
-	return fmt.Sprintf("Reaction kind %s is invalid", err.Kind)
+func (err *ErrTaskVetoedByPlugin) Error() string {
+	return fmt.Sprintf("Task was rejected by a plugin [TaskID: %d, Plugin: %s, Reason: %s]", err.TaskID, err.Plugin, err.Reason)
 }
 
-// ErrCodeInvalidReactionEntityKind holds the unique world-error code of this error
-const ErrCodeInvalidReactionEntityKind = 4025
-
+// ErrCodeTaskVetoedByPlugin holds the unique world-error code of this error
+const ErrCodeTaskVetoedByPlugin = 4045
+
 // HTTPError holds the http error description
-func (err ErrInvalidReactionEntityKind) HTTPError() web.HTTPError {
+func (err *ErrTaskVetoedByPlugin) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusBadRequest,
-		Code:     ErrCodeInvalidReactionEntityKind,
-		Message:  fmt.Sprintf("The reaction kind '%s' is invalid.", err.Kind),
+		Code:     ErrCodeTaskVetoedByPlugin,
+		Message:  "The task was rejected by the plugin " + err.Plugin + ": " + err.Reason,
 	}
 }
 
@@ -1589,68 +3229,481 @@ const ErrCodeOnlyOneDoneBucketPerProject = 10005
 // HTTPError holds the http error description
 func (err *ErrOnlyOneDoneBucketPerProject) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusPreconditionFailed,
-		Code:     ErrCodeOnlyOneDoneBucketPerProject,
-		Message:  "There can be only one done bucket per project.",
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeOnlyOneDoneBucketPerProject,
+		Message:  "There can be only one done bucket per project.",
+	}
+}
+
+// ErrInvalidBucketAction represents an error where a bucket action has an unknown kind.
+type ErrInvalidBucketAction struct {
+	BucketID int64
+	Kind     BucketActionKind
+}
+
+// IsErrInvalidBucketAction checks if an error is ErrInvalidBucketAction.
+func IsErrInvalidBucketAction(err error) bool {
+	_, ok := err.(*ErrInvalidBucketAction)
+	return ok
+}
+
+func (err *ErrInvalidBucketAction) Error() string {
+	return fmt.Sprintf("Bucket action is invalid [BucketID: %d, Kind: %s]", err.BucketID, err.Kind)
+}
+
+// ErrCodeInvalidBucketAction holds the unique world-error code of this error
+const ErrCodeInvalidBucketAction = 10006
+
+// HTTPError holds the http error description
+func (err *ErrInvalidBucketAction) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidBucketAction,
+		Message:  "The bucket action is invalid.",
+	}
+}
+
+// ErrBucketTemplateDoesNotExist represents an error where a bucket template does not exist
+type ErrBucketTemplateDoesNotExist struct {
+	BucketTemplateID int64
+}
+
+// IsErrBucketTemplateDoesNotExist checks if an error is ErrBucketTemplateDoesNotExist.
+func IsErrBucketTemplateDoesNotExist(err error) bool {
+	_, ok := err.(*ErrBucketTemplateDoesNotExist)
+	return ok
+}
+
+func (err *ErrBucketTemplateDoesNotExist) Error() string {
+	return fmt.Sprintf("Bucket template does not exist [BucketTemplateID: %d]", err.BucketTemplateID)
+}
+
+// ErrCodeBucketTemplateDoesNotExist holds the unique world-error code of this error
+const ErrCodeBucketTemplateDoesNotExist = 10007
+
+// HTTPError holds the http error description
+func (err *ErrBucketTemplateDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeBucketTemplateDoesNotExist,
+		Message:  "This bucket template does not exist.",
+	}
+}
+
+// ErrInvalidBucketTemplate represents an error where a bucket template has no buckets, a bucket without a title or more than one done bucket
+type ErrInvalidBucketTemplate struct {
+	BucketTemplateID int64
+}
+
+// IsErrInvalidBucketTemplate checks if an error is ErrInvalidBucketTemplate.
+func IsErrInvalidBucketTemplate(err error) bool {
+	_, ok := err.(*ErrInvalidBucketTemplate)
+	return ok
+}
+
+func (err *ErrInvalidBucketTemplate) Error() string {
+	return fmt.Sprintf("Bucket template is invalid [BucketTemplateID: %d]", err.BucketTemplateID)
+}
+
+// ErrCodeInvalidBucketTemplate holds the unique world-error code of this error
+const ErrCodeInvalidBucketTemplate = 10008
+
+// HTTPError holds the http error description
+func (err *ErrInvalidBucketTemplate) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidBucketTemplate,
+		Message:  "A bucket template needs at least one bucket, all buckets need a title and there can be only one done bucket.",
+	}
+}
+
+// ErrInvalidBucketSortMode represents an error where a bucket has an unknown sort mode
+type ErrInvalidBucketSortMode struct {
+	BucketID int64
+	SortMode BucketSortMode
+}
+
+// IsErrInvalidBucketSortMode checks if an error is ErrInvalidBucketSortMode.
+func IsErrInvalidBucketSortMode(err error) bool {
+	_, ok := err.(*ErrInvalidBucketSortMode)
+	return ok
+}
+
+func (err *ErrInvalidBucketSortMode) Error() string {
+	return fmt.Sprintf("Bucket sort mode is invalid [BucketID: %d, SortMode: %d]", err.BucketID, err.SortMode)
+}
+
+// ErrCodeInvalidBucketSortMode holds the unique world-error code of this error
+const ErrCodeInvalidBucketSortMode = 10009
+
+// HTTPError holds the http error description
+func (err *ErrInvalidBucketSortMode) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidBucketSortMode,
+		Message:  "The bucket sort mode is invalid.",
+	}
+}
+
+// ErrBucketIsSortedAutomatically represents an error where a task's position is changed in a bucket which is not sorted manually
+type ErrBucketIsSortedAutomatically struct {
+	BucketID int64
+	SortMode BucketSortMode
+}
+
+// IsErrBucketIsSortedAutomatically checks if an error is ErrBucketIsSortedAutomatically.
+func IsErrBucketIsSortedAutomatically(err error) bool {
+	_, ok := err.(*ErrBucketIsSortedAutomatically)
+	return ok
+}
+
+func (err *ErrBucketIsSortedAutomatically) Error() string {
+	return fmt.Sprintf("Bucket is sorted automatically [BucketID: %d, SortMode: %d]", err.BucketID, err.SortMode)
+}
+
+// ErrCodeBucketIsSortedAutomatically holds the unique world-error code of this error
+const ErrCodeBucketIsSortedAutomatically = 10010
+
+// HTTPError holds the http error description
+func (err *ErrBucketIsSortedAutomatically) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeBucketIsSortedAutomatically,
+		Message:  "You cannot change the position of a task in this bucket because its tasks are sorted automatically.",
+	}
+}
+
+// ErrNotAllowedToMoveTaskIntoBucket represents an error where a user is not allowed to move a task into a bucket
+type ErrNotAllowedToMoveTaskIntoBucket struct {
+	BucketID int64
+	UserID   int64
+}
+
+// IsErrNotAllowedToMoveTaskIntoBucket checks if an error is ErrNotAllowedToMoveTaskIntoBucket.
+func IsErrNotAllowedToMoveTaskIntoBucket(err error) bool {
+	_, ok := err.(*ErrNotAllowedToMoveTaskIntoBucket)
+	return ok
+}
+
+func (err *ErrNotAllowedToMoveTaskIntoBucket) Error() string {
+	return fmt.Sprintf("User is not allowed to move tasks into this bucket [BucketID: %d, UserID: %d]", err.BucketID, err.UserID)
+}
+
+// ErrCodeNotAllowedToMoveTaskIntoBucket holds the unique world-error code of this error
+const ErrCodeNotAllowedToMoveTaskIntoBucket = 10011
+
+// HTTPError holds the http error description
+func (err *ErrNotAllowedToMoveTaskIntoBucket) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusForbidden,
+		Code:     ErrCodeNotAllowedToMoveTaskIntoBucket,
+		Message:  "You are not allowed to move tasks into this bucket.",
+	}
+}
+
+// ErrTaskIsNotInBucket represents an error where a task which should be moved out of a bucket is not in that bucket
+type ErrTaskIsNotInBucket struct {
+	TaskID   int64
+	BucketID int64
+}
+
+// IsErrTaskIsNotInBucket checks if an error is ErrTaskIsNotInBucket.
+func IsErrTaskIsNotInBucket(err error) bool {
+	_, ok := err.(*ErrTaskIsNotInBucket)
+	return ok
+}
+
+func (err *ErrTaskIsNotInBucket) Error() string {
+	return fmt.Sprintf("Task is not in bucket [TaskID: %d, BucketID: %d]", err.TaskID, err.BucketID)
+}
+
+// ErrCodeTaskIsNotInBucket holds the unique world-error code of this error
+const ErrCodeTaskIsNotInBucket = 10012
+
+// HTTPError holds the http error description
+func (err *ErrTaskIsNotInBucket) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTaskIsNotInBucket,
+		Message:  "This task is not in the bucket it should be moved out of.",
+	}
+}
+
+// ErrInvalidBucketTaskCursor represents an error where a cursor to paginate the tasks of a bucket is invalid
+type ErrInvalidBucketTaskCursor struct{}
+
+// IsErrInvalidBucketTaskCursor checks if an error is ErrInvalidBucketTaskCursor.
+func IsErrInvalidBucketTaskCursor(err error) bool {
+	_, ok := err.(*ErrInvalidBucketTaskCursor)
+	return ok
+}
+
+func (err *ErrInvalidBucketTaskCursor) Error() string {
+	return "Bucket task cursor is invalid"
+}
+
+// ErrCodeInvalidBucketTaskCursor holds the unique world-error code of this error
+const ErrCodeInvalidBucketTaskCursor = 10013
+
+// HTTPError holds the http error description
+func (err *ErrInvalidBucketTaskCursor) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidBucketTaskCursor,
+		Message:  "The cursor is invalid or does not belong to a bucket of this project.",
+	}
+}
+
+// =============
+// Saved Filters
+// =============
+
+// ErrSavedFilterDoesNotExist represents an error where a kanban bucket does not exist
+type ErrSavedFilterDoesNotExist struct {
+	SavedFilterID int64
+}
+
+// IsErrSavedFilterDoesNotExist checks if an error is ErrSavedFilterDoesNotExist.
+func IsErrSavedFilterDoesNotExist(err error) bool {
+	_, ok := err.(ErrSavedFilterDoesNotExist)
+	return ok
+}
+
+func (err ErrSavedFilterDoesNotExist) Error() string {
+	return fmt.Sprintf("Saved filter does not exist [SavedFilterID: %d]", err.SavedFilterID)
+}
+
+// ErrCodeSavedFilterDoesNotExist holds the unique world-error code of this error
+const ErrCodeSavedFilterDoesNotExist = 11001
+
+// HTTPError holds the http error description
+func (err ErrSavedFilterDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeSavedFilterDoesNotExist,
+		Message:  "This saved filter does not exist.",
+	}
+}
+
+// ErrSavedFilterNotAvailableForLinkShare represents an error where a kanban bucket does not exist
+type ErrSavedFilterNotAvailableForLinkShare struct {
+	SavedFilterID int64
+	LinkShareID   int64
+}
+
+// IsErrSavedFilterNotAvailableForLinkShare checks if an error is ErrSavedFilterNotAvailableForLinkShare.
+func IsErrSavedFilterNotAvailableForLinkShare(err error) bool {
+	_, ok := err.(ErrSavedFilterNotAvailableForLinkShare)
+	return ok
+}
+
+func (err ErrSavedFilterNotAvailableForLinkShare) Error() string {
+	return fmt.Sprintf("Saved filters are not available for link shares [SavedFilterID: %d, LinkShareID: %d]", err.SavedFilterID, err.LinkShareID)
+}
+
+// ErrCodeSavedFilterNotAvailableForLinkShare holds the unique world-error code of this error
+const ErrCodeSavedFilterNotAvailableForLinkShare = 11002
+
+// HTTPError holds the http error description
+func (err ErrSavedFilterNotAvailableForLinkShare) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeSavedFilterNotAvailableForLinkShare,
+		Message:  "Saved filters are not available for link shares.",
+	}
+}
+
+// ErrInvalidSavedFilterBucketConfiguration represents an error where the kanban bucket configuration of a saved filter is invalid
+type ErrInvalidSavedFilterBucketConfiguration struct {
+	SavedFilterID int64
+}
+
+// IsErrInvalidSavedFilterBucketConfiguration checks if an error is ErrInvalidSavedFilterBucketConfiguration.
+func IsErrInvalidSavedFilterBucketConfiguration(err error) bool {
+	_, ok := err.(ErrInvalidSavedFilterBucketConfiguration)
+	return ok
+}
+
+func (err ErrInvalidSavedFilterBucketConfiguration) Error() string {
+	return fmt.Sprintf("Saved filter bucket configuration is invalid [SavedFilterID: %d]", err.SavedFilterID)
+}
+
+// ErrCodeInvalidSavedFilterBucketConfiguration holds the unique world-error code of this error
+const ErrCodeInvalidSavedFilterBucketConfiguration = 11003
+
+// HTTPError holds the http error description
+func (err ErrInvalidSavedFilterBucketConfiguration) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidSavedFilterBucketConfiguration,
+		Message:  "The bucket configuration of this saved filter is invalid.",
+	}
+}
+
+// ErrSavedFilterHasNoKanbanBoard represents an error where the buckets of a saved filter without a kanban board are requested
+type ErrSavedFilterHasNoKanbanBoard struct {
+	SavedFilterID int64
+}
+
+// IsErrSavedFilterHasNoKanbanBoard checks if an error is ErrSavedFilterHasNoKanbanBoard.
+func IsErrSavedFilterHasNoKanbanBoard(err error) bool {
+	_, ok := err.(ErrSavedFilterHasNoKanbanBoard)
+	return ok
+}
+
+func (err ErrSavedFilterHasNoKanbanBoard) Error() string {
+	return fmt.Sprintf("Saved filter has no kanban board [SavedFilterID: %d]", err.SavedFilterID)
+}
+
+// ErrCodeSavedFilterHasNoKanbanBoard holds the unique world-error code of this error
+const ErrCodeSavedFilterHasNoKanbanBoard = 11004
+
+// HTTPError holds the http error description
+func (err ErrSavedFilterHasNoKanbanBoard) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeSavedFilterHasNoKanbanBoard,
+		Message:  "This saved filter has no kanban board.",
+	}
+}
+
+// ErrUserAlreadyHasAccessToSavedFilter represents an error where a user already has access to a saved filter
+type ErrUserAlreadyHasAccessToSavedFilter struct {
+	UserID        int64
+	SavedFilterID int64
+}
+
+// IsErrUserAlreadyHasAccessToSavedFilter checks if an error is ErrUserAlreadyHasAccessToSavedFilter.
+func IsErrUserAlreadyHasAccessToSavedFilter(err error) bool {
+	_, ok := err.(ErrUserAlreadyHasAccessToSavedFilter)
+	return ok
+}
+
+func (err ErrUserAlreadyHasAccessToSavedFilter) Error() string {
+	return fmt.Sprintf("User already has access to the saved filter [UserID: %d, SavedFilterID: %d]", err.UserID, err.SavedFilterID)
+}
+
+// ErrCodeUserAlreadyHasAccessToSavedFilter holds the unique world-error code of this error
+const ErrCodeUserAlreadyHasAccessToSavedFilter = 11005
+
+// HTTPError holds the http error description
+func (err ErrUserAlreadyHasAccessToSavedFilter) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusConflict,
+		Code:     ErrCodeUserAlreadyHasAccessToSavedFilter,
+		Message:  "This user already has access to this saved filter.",
+	}
+}
+
+// ErrTeamAlreadyHasAccessToSavedFilter represents an error where a team already has access to a saved filter
+type ErrTeamAlreadyHasAccessToSavedFilter struct {
+	TeamID        int64
+	SavedFilterID int64
+}
+
+// IsErrTeamAlreadyHasAccessToSavedFilter checks if an error is ErrTeamAlreadyHasAccessToSavedFilter.
+func IsErrTeamAlreadyHasAccessToSavedFilter(err error) bool {
+	_, ok := err.(ErrTeamAlreadyHasAccessToSavedFilter)
+	return ok
+}
+
+func (err ErrTeamAlreadyHasAccessToSavedFilter) Error() string {
+	return fmt.Sprintf("Team already has access to the saved filter [TeamID: %d, SavedFilterID: %d]", err.TeamID, err.SavedFilterID)
+}
+
+// ErrCodeTeamAlreadyHasAccessToSavedFilter holds the unique world-error code of this error
+const ErrCodeTeamAlreadyHasAccessToSavedFilter = 11006
+
+// HTTPError holds the http error description
+func (err ErrTeamAlreadyHasAccessToSavedFilter) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusConflict,
+		Code:     ErrCodeTeamAlreadyHasAccessToSavedFilter,
+		Message:  "This team already has access to this saved filter.",
+	}
+}
+
+// ErrUserDoesNotHaveAccessToSavedFilter represents an error where a saved filter is not shared with a user
+type ErrUserDoesNotHaveAccessToSavedFilter struct {
+	UserID        int64
+	SavedFilterID int64
+}
+
+// IsErrUserDoesNotHaveAccessToSavedFilter checks if an error is ErrUserDoesNotHaveAccessToSavedFilter.
+func IsErrUserDoesNotHaveAccessToSavedFilter(err error) bool {
+	_, ok := err.(ErrUserDoesNotHaveAccessToSavedFilter)
+	return ok
+}
+
+func (err ErrUserDoesNotHaveAccessToSavedFilter) Error() string {
+	return fmt.Sprintf("User does not have access to the saved filter [UserID: %d, SavedFilterID: %d]", err.UserID, err.SavedFilterID)
+}
+
+// ErrCodeUserDoesNotHaveAccessToSavedFilter holds the unique world-error code of this error
+const ErrCodeUserDoesNotHaveAccessToSavedFilter = 11007
+
+// HTTPError holds the http error description
+func (err ErrUserDoesNotHaveAccessToSavedFilter) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeUserDoesNotHaveAccessToSavedFilter,
+		Message:  "This saved filter is not shared with this user.",
 	}
 }
 
-// =============
-// Saved Filters
-// =============
-
-// ErrSavedFilterDoesNotExist represents an error where a kanban bucket does not exist
-type ErrSavedFilterDoesNotExist struct {
+// ErrTeamDoesNotHaveAccessToSavedFilter represents an error where a saved filter is not shared with a team
+type ErrTeamDoesNotHaveAccessToSavedFilter struct {
+	TeamID        int64
 	SavedFilterID int64
 }
 
-// IsErrSavedFilterDoesNotExist checks if an error is ErrSavedFilterDoesNotExist.
-func IsErrSavedFilterDoesNotExist(err error) bool {
-	_, ok := err.(ErrSavedFilterDoesNotExist)
+// IsErrTeamDoesNotHaveAccessToSavedFilter checks if an error is ErrTeamDoesNotHaveAccessToSavedFilter.
+func IsErrTeamDoesNotHaveAccessToSavedFilter(err error) bool {
+	_, ok := err.(ErrTeamDoesNotHaveAccessToSavedFilter)
 	return ok
 }
 
-func (err ErrSavedFilterDoesNotExist) Error() string {
-	return fmt.Sprintf("Saved filter does not exist [SavedFilterID: %d]", err.SavedFilterID)
+func (err ErrTeamDoesNotHaveAccessToSavedFilter) Error() string {
+	return fmt.Sprintf("Team does not have access to the saved filter [TeamID: %d, SavedFilterID: %d]", err.TeamID, err.SavedFilterID)
 }
 
-// ErrCodeSavedFilterDoesNotExist holds the unique world-error code of this error
-const ErrCodeSavedFilterDoesNotExist = 11001
+// ErrCodeTeamDoesNotHaveAccessToSavedFilter holds the unique world-error code of this error
+const ErrCodeTeamDoesNotHaveAccessToSavedFilter = 11008
 
 // HTTPError holds the http error description
-func (err ErrSavedFilterDoesNotExist) HTTPError() web.HTTPError {
+func (err ErrTeamDoesNotHaveAccessToSavedFilter) HTTPError() web.HTTPError {
 	return web.HTTPError{
 		HTTPCode: http.StatusNotFound,
-		Code:     ErrCodeSavedFilterDoesNotExist,
-		Message:  "This saved filter does not exist.",
+		Code:     ErrCodeTeamDoesNotHaveAccessToSavedFilter,
+		Message:  "This saved filter is not shared with this team.",
 	}
 }
 
-// ErrSavedFilterNotAvailableForLinkShare represents an error where a kanban bucket does not exist
-type ErrSavedFilterNotAvailableForLinkShare struct {
+// ErrInvalidSavedFilterViewSettings represents an error where the view settings of a saved filter are invalid
+type ErrInvalidSavedFilterViewSettings struct {
 	SavedFilterID int64
-	LinkShareID   int64
+	Setting       string
 }
 
-// IsErrSavedFilterNotAvailableForLinkShare checks if an error is ErrSavedFilterNotAvailableForLinkShare.
-func IsErrSavedFilterNotAvailableForLinkShare(err error) bool {
-	_, ok := err.(ErrSavedFilterNotAvailableForLinkShare)
+// IsErrInvalidSavedFilterViewSettings checks if an error is ErrInvalidSavedFilterViewSettings.
+func IsErrInvalidSavedFilterViewSettings(err error) bool {
+	_, ok := err.(ErrInvalidSavedFilterViewSettings)
 	return ok
 }
 
-func (err ErrSavedFilterNotAvailableForLinkShare) Error() string {
-	return fmt.Sprintf("Saved filters are not available for link shares [SavedFilterID: %d, LinkShareID: %d]", err.SavedFilterID, err.LinkShareID)
+func (err ErrInvalidSavedFilterViewSettings) Error() string {
+	return fmt.Sprintf("Saved filter view settings are invalid [SavedFilterID: %d, Setting: %s]", err.SavedFilterID, err.Setting)
 }
 
-// ErrCodeSavedFilterNotAvailableForLinkShare holds the unique world-error code of this error
-const ErrCodeSavedFilterNotAvailableForLinkShare = 11002
+// ErrCodeInvalidSavedFilterViewSettings holds the unique world-error code of this error
+const ErrCodeInvalidSavedFilterViewSettings = 11009
 
 // HTTPError holds the http error description
-func (err ErrSavedFilterNotAvailableForLinkShare) HTTPError() web.HTTPError {
+func (err ErrInvalidSavedFilterViewSettings) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusPreconditionFailed,
-		Code:     ErrCodeSavedFilterNotAvailableForLinkShare,
-		Message:  "Saved filters are not available for link shares.",
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidSavedFilterViewSettings,
+		Message:  fmt.Sprintf("The view setting %s of this saved filter is invalid.", err.Setting),
 	}
 }
 
@@ -1855,3 +3908,422 @@ func (err ErrInvalidAPITokenPermission) HTTPError() web.HTTPError {
 		Message:  fmt.Sprintf("The permission %s of group %s is invalid.", err.Permission, err.Group),
 	}
 }
+
+// ErrAPITokenDoesNotExist represents an error where an api token does not exist
+type ErrAPITokenDoesNotExist struct {
+	TokenID int64
+}
+
+// IsErrAPITokenDoesNotExist checks if an error is ErrAPITokenDoesNotExist.
+func IsErrAPITokenDoesNotExist(err error) bool {
+	_, ok := err.(*ErrAPITokenDoesNotExist)
+	return ok
+}
+
+func (err *ErrAPITokenDoesNotExist) Error() string {
+	return fmt.Sprintf("API token does not exist [TokenID: %d]", err.TokenID)
+}
+
+// ErrCodeAPITokenDoesNotExist holds the unique world-error code of this error
+const ErrCodeAPITokenDoesNotExist = 14003
+
+// HTTPError holds the http error description
+func (err ErrAPITokenDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeAPITokenDoesNotExist,
+		Message:  "The api token does not exist.",
+	}
+}
+
+// ====================
+// Time tracking errors
+// ====================
+
+// ErrTimeEntryDoesNotExist represents an error where a time entry does not exist
+type ErrTimeEntryDoesNotExist struct {
+	ID int64
+}
+
+// IsErrTimeEntryDoesNotExist checks if an error is ErrTimeEntryDoesNotExist.
+func IsErrTimeEntryDoesNotExist(err error) bool {
+	_, ok := err.(*ErrTimeEntryDoesNotExist)
+	return ok
+}
+
+func (err *ErrTimeEntryDoesNotExist) Error() string {
+	return fmt.Sprintf("Time entry does not exist [ID: %d]", err.ID)
+}
+
+// ErrCodeTimeEntryDoesNotExist holds the unique world-error code of this error
+const ErrCodeTimeEntryDoesNotExist = 15001
+
+// HTTPError holds the http error description
+func (err *ErrTimeEntryDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeTimeEntryDoesNotExist,
+		Message:  "This time entry does not exist.",
+	}
+}
+
+// ErrInvalidTimeEntryPeriod represents an error where the start or end of a time entry is invalid
+type ErrInvalidTimeEntryPeriod struct {
+}
+
+// IsErrInvalidTimeEntryPeriod checks if an error is ErrInvalidTimeEntryPeriod.
+func IsErrInvalidTimeEntryPeriod(err error) bool {
+	_, ok := err.(*ErrInvalidTimeEntryPeriod)
+	return ok
+}
+
+func (err *ErrInvalidTimeEntryPeriod) Error() string {
+	return "Time entry period is invalid"
+}
+
+// ErrCodeInvalidTimeEntryPeriod holds the unique world-error code of this error
+const ErrCodeInvalidTimeEntryPeriod = 15002
+
+// HTTPError holds the http error description
+func (err *ErrInvalidTimeEntryPeriod) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTimeEntryPeriod,
+		Message:  "A time entry needs a start and an end after its start.",
+	}
+}
+
+// ErrNoRunningTimeEntry represents an error where a user tries to stop a timer which is not running
+type ErrNoRunningTimeEntry struct {
+	TaskID int64
+	UserID int64
+}
+
+// IsErrNoRunningTimeEntry checks if an error is ErrNoRunningTimeEntry.
+func IsErrNoRunningTimeEntry(err error) bool {
+	_, ok := err.(*ErrNoRunningTimeEntry)
+	return ok
+}
+
+func (err *ErrNoRunningTimeEntry) Error() string {
+	return fmt.Sprintf("No running time entry [TaskID: %d, UserID: %d]", err.TaskID, err.UserID)
+}
+
+// ErrCodeNoRunningTimeEntry holds the unique world-error code of this error
+const ErrCodeNoRunningTimeEntry = 15003
+
+// HTTPError holds the http error description
+func (err *ErrNoRunningTimeEntry) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeNoRunningTimeEntry,
+		Message:  "There is no running timer on this task.",
+	}
+}
+
+// ====================
+// Custom field errors
+// ====================
+
+// ErrCustomFieldDoesNotExist represents an error where a custom field does not exist
+type ErrCustomFieldDoesNotExist struct {
+	CustomFieldID int64
+}
+
+// IsErrCustomFieldDoesNotExist checks if an error is ErrCustomFieldDoesNotExist.
+func IsErrCustomFieldDoesNotExist(err error) bool {
+	_, ok := err.(*ErrCustomFieldDoesNotExist)
+	return ok
+}
+
+func (err *ErrCustomFieldDoesNotExist) Error() string {
+	return fmt.Sprintf("Custom field does not exist [CustomFieldID: %d]", err.CustomFieldID)
+}
+
+// ErrCodeCustomFieldDoesNotExist holds the unique world-error code of this error
+const ErrCodeCustomFieldDoesNotExist = 16001
+
+// HTTPError holds the http error description
+func (err *ErrCustomFieldDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeCustomFieldDoesNotExist,
+		Message:  "This custom field does not exist.",
+	}
+}
+
+// ErrInvalidCustomFieldType represents an error where a custom field has an invalid type
+type ErrInvalidCustomFieldType struct {
+	Type CustomFieldType
+}
+
+// IsErrInvalidCustomFieldType checks if an error is ErrInvalidCustomFieldType.
+func IsErrInvalidCustomFieldType(err error) bool {
+	_, ok := err.(*ErrInvalidCustomFieldType)
+	return ok
+}
+
+func (err *ErrInvalidCustomFieldType) Error() string {
+	return fmt.Sprintf("Custom field type is invalid [Type: %s]", err.Type)
+}
+
+// ErrCodeInvalidCustomFieldType holds the unique world-error code of this error
+const ErrCodeInvalidCustomFieldType = 16002
+
+// HTTPError holds the http error description
+func (err *ErrInvalidCustomFieldType) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidCustomFieldType,
+		Message:  fmt.Sprintf("The custom field type '%s' is invalid or cannot be changed.", err.Type),
+	}
+}
+
+// ErrInvalidCustomFieldOptions represents an error where a select custom field has no or duplicate options
+type ErrInvalidCustomFieldOptions struct {
+	CustomFieldID int64
+}
+
+// IsErrInvalidCustomFieldOptions checks if an error is ErrInvalidCustomFieldOptions.
+func IsErrInvalidCustomFieldOptions(err error) bool {
+	_, ok := err.(*ErrInvalidCustomFieldOptions)
+	return ok
+}
+
+func (err *ErrInvalidCustomFieldOptions) Error() string {
+	return fmt.Sprintf("Custom field options are invalid [CustomFieldID: %d]", err.CustomFieldID)
+}
+
+// ErrCodeInvalidCustomFieldOptions holds the unique world-error code of this error
+const ErrCodeInvalidCustomFieldOptions = 16003
+
+// HTTPError holds the http error description
+func (err *ErrInvalidCustomFieldOptions) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidCustomFieldOptions,
+		Message:  "Select custom fields need at least one option and all options must be unique and not empty.",
+	}
+}
+
+// ErrInvalidCustomFieldValue represents an error where a value does not match the type of its custom field
+type ErrInvalidCustomFieldValue struct {
+	CustomFieldID int64
+	Type          CustomFieldType
+}
+
+// IsErrInvalidCustomFieldValue checks if an error is ErrInvalidCustomFieldValue.
+func IsErrInvalidCustomFieldValue(err error) bool {
+	_, ok := err.(*ErrInvalidCustomFieldValue)
+	return ok
+}
+
+func (err *ErrInvalidCustomFieldValue) Error() string {
+	return fmt.Sprintf("Custom field value is invalid [CustomFieldID: %d, Type: %s]", err.CustomFieldID, err.Type)
+}
+
+// ErrCodeInvalidCustomFieldValue holds the unique world-error code of this error
+const ErrCodeInvalidCustomFieldValue = 16004
+
+// HTTPError holds the http error description
+func (err *ErrInvalidCustomFieldValue) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidCustomFieldValue,
+		Message:  fmt.Sprintf("The value for custom field %d is not a valid %s value.", err.CustomFieldID, err.Type),
+	}
+}
+
+// ========================
+// Push notification errors
+// ========================
+
+// ErrPushSubscriptionDoesNotExist represents an error where a push subscription does not exist
+type ErrPushSubscriptionDoesNotExist struct {
+	PushSubscriptionID int64
+}
+
+// IsErrPushSubscriptionDoesNotExist checks if an error is ErrPushSubscriptionDoesNotExist.
+func IsErrPushSubscriptionDoesNotExist(err error) bool {
+	_, ok := err.(*ErrPushSubscriptionDoesNotExist)
+	return ok
+}
+
+func (err *ErrPushSubscriptionDoesNotExist) Error() string {
+	return fmt.Sprintf("Push subscription does not exist [PushSubscriptionID: %d]", err.PushSubscriptionID)
+}
+
+// ErrCodePushSubscriptionDoesNotExist holds the unique world-error code of this error
+const ErrCodePushSubscriptionDoesNotExist = 17001
+
+// HTTPError holds the http error description
+func (err *ErrPushSubscriptionDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodePushSubscriptionDoesNotExist,
+		Message:  "This push subscription does not exist.",
+	}
+}
+
+// ErrInvalidPushSubscription represents an error where a push subscription has no valid endpoint or keys
+type ErrInvalidPushSubscription struct {
+	Endpoint string
+}
+
+// IsErrInvalidPushSubscription checks if an error is ErrInvalidPushSubscription.
+func IsErrInvalidPushSubscription(err error) bool {
+	_, ok := err.(*ErrInvalidPushSubscription)
+	return ok
+}
+
+func (err *ErrInvalidPushSubscription) Error() string {
+	return fmt.Sprintf("Push subscription is invalid [Endpoint: %s]", err.Endpoint)
+}
+
+// ErrCodeInvalidPushSubscription holds the unique world-error code of this error
+const ErrCodeInvalidPushSubscription = 17002
+
+// HTTPError holds the http error description
+func (err *ErrInvalidPushSubscription) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidPushSubscription,
+		Message:  "The push subscription needs an https endpoint and the p256dh and auth keys of the browser.",
+	}
+}
+
+// ErrWebPushDisabled represents an error where Web Push is not enabled on this instance
+type ErrWebPushDisabled struct{}
+
+// IsErrWebPushDisabled checks if an error is ErrWebPushDisabled.
+func IsErrWebPushDisabled(err error) bool {
+	_, ok := err.(*ErrWebPushDisabled)
+	return ok
+}
+
+func (err *ErrWebPushDisabled) Error() string {
+	return "Web Push is disabled"
+}
+
+// ErrCodeWebPushDisabled holds the unique world-error code of this error
+const ErrCodeWebPushDisabled = 17003
+
+// HTTPError holds the http error description
+func (err *ErrWebPushDisabled) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeWebPushDisabled,
+		Message:  "Web Push notifications are not enabled on this instance.",
+	}
+}
+
+// ==============
+// Webhook errors
+// ==============
+
+// ErrWebhookDoesNotExist represents an error where a webhook does not exist
+type ErrWebhookDoesNotExist struct {
+	WebhookID int64
+}
+
+// IsErrWebhookDoesNotExist checks if an error is ErrWebhookDoesNotExist.
+func IsErrWebhookDoesNotExist(err error) bool {
+	_, ok := err.(*ErrWebhookDoesNotExist)
+	return ok
+}
+
+func (err *ErrWebhookDoesNotExist) Error() string {
+	return fmt.Sprintf("Webhook does not exist [WebhookID: %d]", err.WebhookID)
+}
+
+// ErrCodeWebhookDoesNotExist holds the unique world-error code of this error
+const ErrCodeWebhookDoesNotExist = 18001
+
+// HTTPError holds the http error description
+func (err *ErrWebhookDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeWebhookDoesNotExist,
+		Message:  "This webhook does not exist.",
+	}
+}
+
+// ErrWebhookDeliveryDoesNotExist represents an error where a webhook delivery does not exist
+type ErrWebhookDeliveryDoesNotExist struct {
+	DeliveryID int64
+	WebhookID  int64
+}
+
+// IsErrWebhookDeliveryDoesNotExist checks if an error is ErrWebhookDeliveryDoesNotExist.
+func IsErrWebhookDeliveryDoesNotExist(err error) bool {
+	_, ok := err.(*ErrWebhookDeliveryDoesNotExist)
+	return ok
+}
+
+func (err *ErrWebhookDeliveryDoesNotExist) Error() string {
+	return fmt.Sprintf("Webhook delivery does not exist [DeliveryID: %d, WebhookID: %d]", err.DeliveryID, err.WebhookID)
+}
+
+// ErrCodeWebhookDeliveryDoesNotExist holds the unique world-error code of this error
+const ErrCodeWebhookDeliveryDoesNotExist = 18002
+
+// HTTPError holds the http error description
+func (err *ErrWebhookDeliveryDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeWebhookDeliveryDoesNotExist,
+		Message:  "This webhook delivery does not exist.",
+	}
+}
+
+// ErrInvalidWebhookPayloadTemplate represents an error where the payload template of a webhook is invalid
+type ErrInvalidWebhookPayloadTemplate struct {
+	Reason string
+}
+
+// IsErrInvalidWebhookPayloadTemplate checks if an error is ErrInvalidWebhookPayloadTemplate.
+func IsErrInvalidWebhookPayloadTemplate(err error) bool {
+	_, ok := err.(*ErrInvalidWebhookPayloadTemplate)
+	return ok
+}
+
+func (err *ErrInvalidWebhookPayloadTemplate) Error() string {
+	return fmt.Sprintf("Webhook payload template is invalid [Reason: %s]", err.Reason)
+}
+
+// ErrCodeInvalidWebhookPayloadTemplate holds the unique world-error code of this error
+const ErrCodeInvalidWebhookPayloadTemplate = 18003
+
+// HTTPError holds the http error description
+func (err *ErrInvalidWebhookPayloadTemplate) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidWebhookPayloadTemplate,
+		Message:  "The payload template is invalid: " + err.Reason,
+	}
+}
+
+// ErrInboundWebhookDoesNotExist represents an error where an inbound webhook does not exist
+type ErrInboundWebhookDoesNotExist struct{}
+
+// IsErrInboundWebhookDoesNotExist checks if an error is ErrInboundWebhookDoesNotExist.
+func IsErrInboundWebhookDoesNotExist(err error) bool {
+	_, ok := err.(*ErrInboundWebhookDoesNotExist)
+	return ok
+}
+
+func (err *ErrInboundWebhookDoesNotExist) Error() string {
+	return "Inbound webhook does not exist"
+}
+
+// ErrCodeInboundWebhookDoesNotExist holds the unique world-error code of this error
+const ErrCodeInboundWebhookDoesNotExist = 18004
+
+// HTTPError holds the http error description
+func (err *ErrInboundWebhookDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeInboundWebhookDoesNotExist,
+		Message:  "This inbound webhook does not exist.",
+	}
+}
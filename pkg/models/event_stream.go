@@ -0,0 +1,360 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"xorm.io/xorm"
+)
+
+// ProjectEvent is an event which happened in a project. Project events are kept for a while so that they can be
+// streamed to all users who subscribed to the project, even when a client reconnects to the stream.
+type ProjectEvent struct {
+	ID        int64                  `xorm:"bigint autoincr not null unique pk" json:"id"`
+	ProjectID int64                  `xorm:"bigint not null index" json:"project_id"`
+	Name      string                 `xorm:"varchar(250) not null" json:"name"`
+	Payload   map[string]interface{} `xorm:"json null" json:"payload"`
+	Created   time.Time              `xorm:"created not null index" json:"created"`
+}
+
+// TableName holds the table name for project events
+func (*ProjectEvent) TableName() string {
+	return "project_events"
+}
+
+// StreamEvent is one event of the event stream of a user
+type StreamEvent struct {
+	// The id of the event. Pass it as Last-Event-ID to resume the stream after this event.
+	ID string
+	// The name of the event. This is `notification` for notifications or the name of the event for changes in projects.
+	Name string
+	// The data of the event, either the notification or the project event.
+	Data interface{}
+}
+
+// The maximum number of notifications and project events each which are loaded at once
+const eventStreamBatchSize = 50
+
+// The names of all events which are recorded for the event stream
+var eventStreamEvents = []events.Event{
+	&TaskCreatedEvent{},
+	&TaskUpdatedEvent{},
+	&TaskDeletedEvent{},
+	&TaskMovedEvent{},
+	&TaskAssigneeCreatedEvent{},
+	&TaskAssigneeDeletedEvent{},
+	&TaskCommentCreatedEvent{},
+	&TaskCommentUpdatedEvent{},
+	&TaskCommentDeletedEvent{},
+	&TaskAttachmentCreatedEvent{},
+	&TaskAttachmentDeletedEvent{},
+	&TaskLabelCreatedEvent{},
+	&TaskLabelDeletedEvent{},
+	&TaskRelationCreatedEvent{},
+	&TaskRelationDeletedEvent{},
+	&BucketCreatedEvent{},
+	&BucketUpdatedEvent{},
+	&BucketDeletedEvent{},
+	&ProjectUpdatedEvent{},
+}
+
+func registerEventStreamListeners() {
+	for _, e := range eventStreamEvents {
+		events.RegisterListener(e.Name(), &RecordProjectEvent{EventName: e.Name()})
+	}
+}
+
+// RecordProjectEvent represents a listener
+type RecordProjectEvent struct {
+	EventName string
+}
+
+// Name defines the name for the RecordProjectEvent listener
+func (l *RecordProjectEvent) Name() string {
+	return "project.event.record"
+}
+
+// removeEmails removes all email addresses of users from an event payload since it is shown to all subscribers
+func removeEmails(v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		delete(val, "email")
+		for _, child := range val {
+			removeEmails(child)
+		}
+	case []interface{}:
+		for _, child := range val {
+			removeEmails(child)
+		}
+	}
+}
+
+// Handle is executed when the event RecordProjectEvent listens on is fired
+func (l *RecordProjectEvent) Handle(msg *message.Message) (err error) {
+	var payload map[string]interface{}
+	err = json.Unmarshal(msg.Payload, &payload)
+	if err != nil {
+		return err
+	}
+
+	projectID := getProjectIDFromAnyEvent(payload)
+	if projectID == 0 {
+		return nil
+	}
+
+	removeEmails(payload)
+
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err = s.Insert(&ProjectEvent{
+		ProjectID: projectID,
+		Name:      l.EventName,
+		Payload:   payload,
+	})
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	return s.Commit()
+}
+
+// eventStreamID holds the position in both the notifications and the project events of a user.
+// It is encoded as `<notification id>-<project event id>`.
+type eventStreamID struct {
+	notificationID int64
+	projectEventID int64
+}
+
+func (id *eventStreamID) String() string {
+	return strconv.FormatInt(id.notificationID, 10) + "-" + strconv.FormatInt(id.projectEventID, 10)
+}
+
+func parseEventStreamID(raw string) (*eventStreamID, error) {
+	parts := strings.Split(raw, "-")
+	if len(parts) != 2 {
+		return nil, &ErrInvalidEventStreamID{EventID: raw}
+	}
+
+	notificationID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || notificationID < 0 {
+		return nil, &ErrInvalidEventStreamID{EventID: raw}
+	}
+	projectEventID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || projectEventID < 0 {
+		return nil, &ErrInvalidEventStreamID{EventID: raw}
+	}
+
+	return &eventStreamID{notificationID: notificationID, projectEventID: projectEventID}, nil
+}
+
+func getEventStreamUser(a web.Auth) (*user.User, error) {
+	u, is := a.(*user.User)
+	if !is {
+		return nil, ErrGenericForbidden{}
+	}
+	return u, nil
+}
+
+// GetEventStreamStart returns the id from which the event stream of a user starts. If the client passed the id of
+// the last event it received, the stream continues after that event. Otherwise only new events are streamed.
+func GetEventStreamStart(s *xorm.Session, a web.Auth, lastEventID string) (string, error) {
+	u, err := getEventStreamUser(a)
+	if err != nil {
+		return "", err
+	}
+
+	if lastEventID != "" {
+		id, err := parseEventStreamID(lastEventID)
+		if err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	}
+
+	id := &eventStreamID{}
+	id.notificationID, err = notifications.GetLatestNotificationID(s, u.ID)
+	if err != nil {
+		return "", err
+	}
+
+	latest := &ProjectEvent{}
+	_, err = s.OrderBy("id DESC").Get(latest)
+	if err != nil {
+		return "", err
+	}
+	id.projectEventID = latest.ID
+
+	return id.String(), nil
+}
+
+// getProjectEventsForUser returns all project events after an id from projects the user subscribed to and can still read
+func getProjectEventsForUser(s *xorm.Session, u *user.User, afterID int64) (projectEvents []*ProjectEvent, err error) {
+	subscriptions := []*Subscription{}
+	err = s.
+		Where("user_id = ? AND entity_type = ?", u.ID, SubscriptionEntityProject).
+		Find(&subscriptions)
+	if err != nil || len(subscriptions) == 0 {
+		return nil, err
+	}
+
+	projectIDs := make([]int64, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		projectIDs = append(projectIDs, sub.EntityID)
+	}
+
+	projectEvents = []*ProjectEvent{}
+	err = s.
+		Where("id > ?", afterID).
+		In("project_id", projectIDs).
+		OrderBy("id ASC").
+		Limit(eventStreamBatchSize).
+		Find(&projectEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	readable := make(map[int64]bool, len(projectIDs))
+	for _, pe := range projectEvents {
+		if _, checked := readable[pe.ProjectID]; checked {
+			continue
+		}
+
+		can, _, err := (&Project{ID: pe.ProjectID}).CanRead(s, u)
+		if err != nil && !IsErrProjectDoesNotExist(err) {
+			return nil, err
+		}
+		readable[pe.ProjectID] = can
+	}
+
+	filtered := make([]*ProjectEvent, 0, len(projectEvents))
+	for _, pe := range projectEvents {
+		if readable[pe.ProjectID] {
+			filtered = append(filtered, pe)
+		}
+	}
+
+	// Events which are not readable anymore still move the position of the stream
+	if len(projectEvents) > 0 && (len(filtered) == 0 || filtered[len(filtered)-1].ID != projectEvents[len(projectEvents)-1].ID) {
+		filtered = append(filtered, &ProjectEvent{ID: projectEvents[len(projectEvents)-1].ID})
+	}
+
+	return filtered, nil
+}
+
+// GetStreamEvents returns all new notifications of a user and changes in projects the user subscribed to after the
+// passed event stream id, together with the id of the last returned event.
+func GetStreamEvents(s *xorm.Session, a web.Auth, lastEventID string) (streamEvents []*StreamEvent, nextID string, err error) {
+	u, err := getEventStreamUser(a)
+	if err != nil {
+		return nil, "", err
+	}
+
+	id, err := parseEventStreamID(lastEventID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ns, err := notifications.GetNotificationsForUserAfter(s, u.ID, id.notificationID, eventStreamBatchSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	projectEvents, err := getProjectEventsForUser(s, u, id.projectEventID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	streamEvents = make([]*StreamEvent, 0, len(ns)+len(projectEvents))
+	for _, n := range ns {
+		id.notificationID = n.ID
+		streamEvents = append(streamEvents, &StreamEvent{
+			ID:   id.String(),
+			Name: "notification",
+			Data: n,
+		})
+	}
+
+	for _, pe := range projectEvents {
+		id.projectEventID = pe.ID
+		if pe.Name == "" {
+			// Only moves the position past events the user can't read anymore
+			continue
+		}
+		streamEvents = append(streamEvents, &StreamEvent{
+			ID:   id.String(),
+			Name: pe.Name,
+			Data: pe,
+		})
+	}
+
+	return streamEvents, id.String(), nil
+}
+
+func pruneProjectEvents(s *xorm.Session, olderThan time.Time) (deleted int64, err error) {
+	return s.Where("created < ?", olderThan).Delete(&ProjectEvent{})
+}
+
+// RegisterProjectEventRetentionCron deletes project events which are older than the configured retention
+func RegisterProjectEventRetentionCron() {
+	if !config.EventStreamEnabled.GetBool() {
+		return
+	}
+
+	const logPrefix = "[Project Event Retention Cron] "
+
+	retention := time.Duration(config.EventStreamRetentionHours.GetInt64()) * time.Hour
+
+	err := cron.Schedule("*/10 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		deleted, err := pruneProjectEvents(s, time.Now().Add(-retention))
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not delete old project events: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit deleted project events: %s", err)
+			return
+		}
+
+		if deleted > 0 {
+			log.Debugf(logPrefix+"Deleted %d old project events", deleted)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register project event retention cron: %s", err)
+	}
+}
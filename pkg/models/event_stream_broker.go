@@ -0,0 +1,245 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sync"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// EventStreamSubscription is one open event stream. It receives a value every time there might be new events for
+// its user, which can then be loaded with GetStreamEvents.
+type EventStreamSubscription struct {
+	userID int64
+	// Buffered so that a wake up is never lost while the stream is still sending the previous events
+	wake chan struct{}
+}
+
+// Wake returns the channel which receives a value when there are new events for the user of the subscription.
+func (sub *EventStreamSubscription) Wake() <-chan struct{} {
+	return sub.wake
+}
+
+// Close removes the subscription so that it does not get woken up anymore.
+func (sub *EventStreamSubscription) Close() {
+	eventStreams.unsubscribe(sub)
+}
+
+func (sub *EventStreamSubscription) notify() {
+	select {
+	case sub.wake <- struct{}{}:
+	default:
+	}
+}
+
+// eventStreamBroker checks the database for new notifications and project events once for all open event streams
+// and only wakes up the streams of the users these events concern. This keeps the load on the database the same,
+// regardless of how many clients are connected.
+type eventStreamBroker struct {
+	mu                 sync.Mutex
+	running            bool
+	subscriptions      map[*EventStreamSubscription]bool
+	lastNotificationID int64
+	lastProjectEventID int64
+}
+
+var eventStreams = newEventStreamBroker()
+
+func newEventStreamBroker() *eventStreamBroker {
+	return &eventStreamBroker{
+		subscriptions: make(map[*EventStreamSubscription]bool),
+	}
+}
+
+// SubscribeToEventStream registers a new event stream of a user. Events which happened before the subscription
+// need to be loaded by the caller after subscribing. The subscription must be closed when the stream ends.
+func SubscribeToEventStream(a web.Auth) (*EventStreamSubscription, error) {
+	u, err := getEventStreamUser(a)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, start, err := eventStreams.subscribe(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if start {
+		go eventStreams.run()
+	}
+
+	return sub, nil
+}
+
+// subscribe adds a subscription and returns whether the broker needs to be started for it.
+func (b *eventStreamBroker) subscribe(userID int64) (sub *EventStreamSubscription, start bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.running {
+		// Everything before this point is loaded by the stream itself after subscribing
+		s := db.NewSession()
+		defer s.Close()
+
+		b.lastNotificationID, b.lastProjectEventID, err = getLatestEventStreamIDs(s)
+		if err != nil {
+			return nil, false, err
+		}
+		b.running = true
+		start = true
+	}
+
+	sub = &EventStreamSubscription{
+		userID: userID,
+		wake:   make(chan struct{}, 1),
+	}
+	b.subscriptions[sub] = true
+
+	return sub, start, nil
+}
+
+func (b *eventStreamBroker) unsubscribe(sub *EventStreamSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscriptions, sub)
+}
+
+// run polls the database until there are no subscriptions left
+func (b *eventStreamBroker) run() {
+	pollInterval := time.Duration(config.EventStreamPollIntervalSeconds.GetInt64()) * time.Second
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		b.mu.Lock()
+		if len(b.subscriptions) == 0 {
+			b.running = false
+			b.mu.Unlock()
+			return
+		}
+		b.mu.Unlock()
+
+		s := db.NewSession()
+		err := b.poll(s)
+		s.Close()
+		if err != nil {
+			log.Errorf("Could not check for new events for the event stream: %s", err)
+		}
+	}
+}
+
+func getLatestEventStreamIDs(s *xorm.Session) (notificationID, projectEventID int64, err error) {
+	latestNotification := &notifications.DatabaseNotification{}
+	_, err = s.Cols("id").OrderBy("id DESC").Get(latestNotification)
+	if err != nil {
+		return
+	}
+
+	latestProjectEvent := &ProjectEvent{}
+	_, err = s.Cols("id").OrderBy("id DESC").Get(latestProjectEvent)
+	if err != nil {
+		return
+	}
+
+	return latestNotification.ID, latestProjectEvent.ID, nil
+}
+
+// poll loads all notifications and project events since the last poll and wakes up the subscriptions of all users
+// who got a notification or subscribed to one of the projects.
+func (b *eventStreamBroker) poll(s *xorm.Session) error {
+	b.mu.Lock()
+	lastNotificationID := b.lastNotificationID
+	lastProjectEventID := b.lastProjectEventID
+	userIDs := make([]int64, 0, len(b.subscriptions))
+	for sub := range b.subscriptions {
+		userIDs = append(userIDs, sub.userID)
+	}
+	b.mu.Unlock()
+
+	ns := []*notifications.DatabaseNotification{}
+	err := s.
+		Cols("id", "notifiable_id").
+		Where("id > ?", lastNotificationID).
+		Find(&ns)
+	if err != nil {
+		return err
+	}
+
+	projectEvents := []*ProjectEvent{}
+	err = s.
+		Cols("id", "project_id").
+		Where("id > ?", lastProjectEventID).
+		Find(&projectEvents)
+	if err != nil {
+		return err
+	}
+
+	woken := make(map[int64]bool)
+	for _, n := range ns {
+		woken[n.NotifiableID] = true
+		if n.ID > lastNotificationID {
+			lastNotificationID = n.ID
+		}
+	}
+
+	projectIDs := make([]int64, 0, len(projectEvents))
+	for _, pe := range projectEvents {
+		projectIDs = append(projectIDs, pe.ProjectID)
+		if pe.ID > lastProjectEventID {
+			lastProjectEventID = pe.ID
+		}
+	}
+
+	if len(projectIDs) > 0 && len(userIDs) > 0 {
+		subscriptions := []*Subscription{}
+		err = s.
+			Where("entity_type = ?", SubscriptionEntityProject).
+			In("entity_id", projectIDs).
+			In("user_id", userIDs).
+			Find(&subscriptions)
+		if err != nil {
+			return err
+		}
+		for _, subscription := range subscriptions {
+			woken[subscription.UserID] = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastNotificationID = lastNotificationID
+	b.lastProjectEventID = lastProjectEventID
+	for sub := range b.subscriptions {
+		if woken[sub.userID] {
+			sub.notify()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEventStreamStart(t *testing.T) {
+	u := &user.User{ID: 6}
+
+	t.Run("new stream", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		id, err := GetEventStreamStart(s, u, "")
+		require.NoError(t, err)
+		assert.Equal(t, "0-3", id)
+	})
+	t.Run("resume", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		id, err := GetEventStreamStart(s, u, "4-1")
+		require.NoError(t, err)
+		assert.Equal(t, "4-1", id)
+	})
+	t.Run("invalid id", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := GetEventStreamStart(s, u, "abc")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidEventStreamID(err))
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := GetEventStreamStart(s, &LinkSharing{ID: 1}, "")
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
+
+func TestGetStreamEvents(t *testing.T) {
+	u := &user.User{ID: 6}
+
+	t.Run("project events of subscribed projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		streamEvents, next, err := GetStreamEvents(s, u, "0-0")
+		require.NoError(t, err)
+		require.Len(t, streamEvents, 2)
+		assert.Equal(t, "0-1", streamEvents[0].ID)
+		assert.Equal(t, "task.created", streamEvents[0].Name)
+		assert.Equal(t, int64(12), streamEvents[0].Data.(*ProjectEvent).ProjectID)
+		assert.Equal(t, "0-3", streamEvents[1].ID)
+		assert.Equal(t, "task.updated", streamEvents[1].Name)
+		assert.Equal(t, "0-3", next)
+	})
+	t.Run("resume after the last event", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		streamEvents, next, err := GetStreamEvents(s, u, "0-1")
+		require.NoError(t, err)
+		require.Len(t, streamEvents, 1)
+		assert.Equal(t, "0-3", streamEvents[0].ID)
+		assert.Equal(t, "0-3", next)
+	})
+	t.Run("no subscriptions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		streamEvents, next, err := GetStreamEvents(s, &user.User{ID: 1}, "0-0")
+		require.NoError(t, err)
+		assert.Empty(t, streamEvents)
+		assert.Equal(t, "0-0", next)
+	})
+	t.Run("notifications", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		// Rolled back when the session is closed
+		require.NoError(t, s.Begin())
+
+		n := &notifications.DatabaseNotification{
+			NotifiableID: 6,
+			Name:         "test",
+			Notification: []byte(`{"foo":"bar"}`),
+		}
+		_, err := s.Insert(n)
+		require.NoError(t, err)
+
+		streamEvents, next, err := GetStreamEvents(s, u, "0-3")
+		require.NoError(t, err)
+		require.Len(t, streamEvents, 1)
+		assert.Equal(t, "notification", streamEvents[0].Name)
+		assert.Equal(t, n.ID, streamEvents[0].Data.(*notifications.DatabaseNotification).ID)
+		assert.Equal(t, next, streamEvents[0].ID)
+	})
+	t.Run("invalid id", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, _, err := GetStreamEvents(s, u, "1-2-3")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidEventStreamID(err))
+	})
+}
+
+func isWoken(sub *EventStreamSubscription) bool {
+	select {
+	case <-sub.Wake():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestEventStreamBroker_poll(t *testing.T) {
+	subscribe := func(t *testing.T, b *eventStreamBroker, userID int64) *EventStreamSubscription {
+		sub, _, err := b.subscribe(userID)
+		require.NoError(t, err)
+		return sub
+	}
+
+	t.Run("notifications", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		b := newEventStreamBroker()
+		sub6 := subscribe(t, b, 6)
+		sub1 := subscribe(t, b, 1)
+
+		s := db.NewSession()
+		defer s.Close()
+		// Rolled back when the session is closed
+		require.NoError(t, s.Begin())
+
+		_, err := s.Insert(&notifications.DatabaseNotification{
+			NotifiableID: 6,
+			Name:         "test",
+			Notification: []byte(`{"foo":"bar"}`),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, b.poll(s))
+		assert.True(t, isWoken(sub6))
+		assert.False(t, isWoken(sub1))
+
+		// Events are only announced once
+		require.NoError(t, b.poll(s))
+		assert.False(t, isWoken(sub6))
+	})
+	t.Run("project events of subscribed projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		b := newEventStreamBroker()
+		sub6 := subscribe(t, b, 6)
+		sub1 := subscribe(t, b, 1)
+
+		s := db.NewSession()
+		defer s.Close()
+		require.NoError(t, s.Begin())
+
+		_, err := s.Insert(&ProjectEvent{
+			ProjectID: 12,
+			Name:      "task.updated",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, b.poll(s))
+		assert.True(t, isWoken(sub6))
+		assert.False(t, isWoken(sub1))
+	})
+	t.Run("nothing new", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		b := newEventStreamBroker()
+		sub6 := subscribe(t, b, 6)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		require.NoError(t, b.poll(s))
+		assert.False(t, isWoken(sub6))
+	})
+	t.Run("closed subscription", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		b := newEventStreamBroker()
+		sub6 := subscribe(t, b, 6)
+		b.unsubscribe(sub6)
+
+		s := db.NewSession()
+		defer s.Close()
+		require.NoError(t, s.Begin())
+
+		_, err := s.Insert(&ProjectEvent{
+			ProjectID: 12,
+			Name:      "task.updated",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, b.poll(s))
+		assert.False(t, isWoken(sub6))
+	})
+}
+
+func TestRecordProjectEvent_removeEmails(t *testing.T) {
+	payload := map[string]interface{}{
+		"task": map[string]interface{}{
+			"assignees": []interface{}{
+				map[string]interface{}{"id": float64(1), "email": "user1@example.com"},
+			},
+		},
+		"doer": map[string]interface{}{"id": float64(1), "email": "user1@example.com"},
+	}
+	removeEmails(payload)
+
+	assert.NotContains(t, payload["doer"], "email")
+	assert.NotContains(t, payload["task"].(map[string]interface{})["assignees"].([]interface{})[0], "email")
+}
@@ -47,6 +47,31 @@ func (t *TaskUpdatedEvent) Name() string {
 	return "task.updated"
 }
 
+// TaskDoneEvent represents an event where a task has been marked as done. It is dispatched in addition to the
+// TaskUpdatedEvent. Repeating tasks are already undone again with their new dates when it is dispatched.
+type TaskDoneEvent struct {
+	Task *Task      `json:"task"`
+	Doer *user.User `json:"doer"`
+}
+
+// Name defines the name for TaskDoneEvent
+func (t *TaskDoneEvent) Name() string {
+	return "task.done"
+}
+
+// TaskMovedEvent represents an event where a task was moved into another bucket of its project
+type TaskMovedEvent struct {
+	Task        *Task      `json:"task"`
+	OldBucketID int64      `json:"old_bucket_id"`
+	NewBucketID int64      `json:"new_bucket_id"`
+	Doer        *user.User `json:"doer"`
+}
+
+// Name defines the name for TaskMovedEvent
+func (t *TaskMovedEvent) Name() string {
+	return "task.moved"
+}
+
 // TaskDeletedEvent represents a TaskDeletedEvent event
 type TaskDeletedEvent struct {
 	Task *Task      `json:"task"`
@@ -118,6 +143,58 @@ func (t *TaskCommentDeletedEvent) Name() string {
 	return "task.comment.deleted"
 }
 
+// TaskChecklistItemCreatedEvent represents an event where an item was added to the checklist of a task
+type TaskChecklistItemCreatedEvent struct {
+	Task          *Task          `json:"task"`
+	ChecklistItem *ChecklistItem `json:"checklist_item"`
+	Doer          *user.User     `json:"doer"`
+}
+
+// Name defines the name for TaskChecklistItemCreatedEvent
+func (t *TaskChecklistItemCreatedEvent) Name() string {
+	return "task.checklist.item.created"
+}
+
+// TaskChecklistItemUpdatedEvent represents an event where an item of the checklist of a task was changed
+type TaskChecklistItemUpdatedEvent struct {
+	Task          *Task          `json:"task"`
+	ChecklistItem *ChecklistItem `json:"checklist_item"`
+	Doer          *user.User     `json:"doer"`
+}
+
+// Name defines the name for TaskChecklistItemUpdatedEvent
+func (t *TaskChecklistItemUpdatedEvent) Name() string {
+	return "task.checklist.item.updated"
+}
+
+// TaskReactionCreatedEvent represents an event where someone reacted to a task or a task comment
+type TaskReactionCreatedEvent struct {
+	Task *Task `json:"task"`
+	// Only set if the reaction was on a comment
+	Comment  *TaskComment `json:"comment"`
+	Reaction *Reaction    `json:"reaction"`
+	Doer     *user.User   `json:"doer"`
+}
+
+// Name defines the name for TaskReactionCreatedEvent
+func (t *TaskReactionCreatedEvent) Name() string {
+	return "task.reaction.created"
+}
+
+// TaskReactionDeletedEvent represents an event where someone removed their reaction from a task or a task comment
+type TaskReactionDeletedEvent struct {
+	Task *Task `json:"task"`
+	// Only set if the reaction was on a comment
+	Comment  *TaskComment `json:"comment"`
+	Reaction *Reaction    `json:"reaction"`
+	Doer     *user.User   `json:"doer"`
+}
+
+// Name defines the name for TaskReactionDeletedEvent
+func (t *TaskReactionDeletedEvent) Name() string {
+	return "task.reaction.deleted"
+}
+
 // TaskAttachmentCreatedEvent represents a TaskAttachmentCreatedEvent event
 type TaskAttachmentCreatedEvent struct {
 	Task       *Task           `json:"task"`
@@ -142,6 +219,30 @@ func (t *TaskAttachmentDeletedEvent) Name() string {
 	return "task.attachment.deleted"
 }
 
+// TaskLabelCreatedEvent represents an event where a label was added to a task
+type TaskLabelCreatedEvent struct {
+	Task  *Task      `json:"task"`
+	Label *Label     `json:"label"`
+	Doer  *user.User `json:"doer"`
+}
+
+// Name defines the name for TaskLabelCreatedEvent
+func (t *TaskLabelCreatedEvent) Name() string {
+	return "task.label.created"
+}
+
+// TaskLabelDeletedEvent represents an event where a label was removed from a task
+type TaskLabelDeletedEvent struct {
+	Task  *Task      `json:"task"`
+	Label *Label     `json:"label"`
+	Doer  *user.User `json:"doer"`
+}
+
+// Name defines the name for TaskLabelDeletedEvent
+func (t *TaskLabelDeletedEvent) Name() string {
+	return "task.label.deleted"
+}
+
 // TaskRelationCreatedEvent represents a TaskRelationCreatedEvent event
 type TaskRelationCreatedEvent struct {
 	Task     *Task         `json:"task"`
@@ -166,6 +267,113 @@ func (t *TaskRelationDeletedEvent) Name() string {
 	return "task.relation.deleted"
 }
 
+// TaskPriorityEscalatedEvent represents an event where the priority of an overdue task was raised automatically
+type TaskPriorityEscalatedEvent struct {
+	Task        *Task    `json:"task"`
+	Project     *Project `json:"project"`
+	OldPriority int64    `json:"old_priority"`
+	NewPriority int64    `json:"new_priority"`
+	// The number of seconds the task was overdue when its priority was raised.
+	OverdueFor int64 `json:"overdue_for"`
+}
+
+// Name defines the name for TaskPriorityEscalatedEvent
+func (t *TaskPriorityEscalatedEvent) Name() string {
+	return "task.priority.escalated"
+}
+
+// TaskSLABreachedEvent represents an event where a task got older than an sla rule of its project allows
+type TaskSLABreachedEvent struct {
+	Task    *Task    `json:"task"`
+	Project *Project `json:"project"`
+	Rule    *SLARule `json:"rule"`
+	// The number of seconds the task was old when the breach was detected.
+	Age int64 `json:"age"`
+}
+
+// Name defines the name for TaskSLABreachedEvent
+func (t *TaskSLABreachedEvent) Name() string {
+	return "task.sla.breached"
+}
+
+/////////////////////////
+// Saved Filter Events //
+/////////////////////////
+
+// SavedFilterTaskMatchedEvent represents an event where a task started matching a saved filter someone subscribed to
+type SavedFilterTaskMatchedEvent struct {
+	Task       *Task        `json:"task"`
+	Filter     *SavedFilter `json:"filter"`
+	Subscriber *user.User   `json:"subscriber"`
+}
+
+// Name defines the name for SavedFilterTaskMatchedEvent
+func (t *SavedFilterTaskMatchedEvent) Name() string {
+	return "filter.task.matched"
+}
+
+// SavedFilterTaskUnmatchedEvent represents an event where a task stopped matching a saved filter someone subscribed to
+type SavedFilterTaskUnmatchedEvent struct {
+	Task       *Task        `json:"task"`
+	Filter     *SavedFilter `json:"filter"`
+	Subscriber *user.User   `json:"subscriber"`
+}
+
+// Name defines the name for SavedFilterTaskUnmatchedEvent
+func (t *SavedFilterTaskUnmatchedEvent) Name() string {
+	return "filter.task.unmatched"
+}
+
+///////////////////
+// Bucket Events //
+///////////////////
+
+// BucketLimitExceededEvent represents an event where a task was moved into a bucket which now has more tasks than its limit
+type BucketLimitExceededEvent struct {
+	Task      *Task      `json:"task"`
+	Bucket    *Bucket    `json:"bucket"`
+	TaskCount int64      `json:"task_count"`
+	Doer      *user.User `json:"doer"`
+}
+
+// Name defines the name for BucketLimitExceededEvent
+func (t *BucketLimitExceededEvent) Name() string {
+	return "bucket.limit.exceeded"
+}
+
+// BucketCreatedEvent represents an event where a kanban bucket was created
+type BucketCreatedEvent struct {
+	Bucket *Bucket    `json:"bucket"`
+	Doer   *user.User `json:"doer"`
+}
+
+// Name defines the name for BucketCreatedEvent
+func (b *BucketCreatedEvent) Name() string {
+	return "bucket.created"
+}
+
+// BucketUpdatedEvent represents an event where a kanban bucket was updated
+type BucketUpdatedEvent struct {
+	Bucket *Bucket    `json:"bucket"`
+	Doer   *user.User `json:"doer"`
+}
+
+// Name defines the name for BucketUpdatedEvent
+func (b *BucketUpdatedEvent) Name() string {
+	return "bucket.updated"
+}
+
+// BucketDeletedEvent represents an event where a kanban bucket was deleted
+type BucketDeletedEvent struct {
+	Bucket *Bucket    `json:"bucket"`
+	Doer   *user.User `json:"doer"`
+}
+
+// Name defines the name for BucketDeletedEvent
+func (b *BucketDeletedEvent) Name() string {
+	return "bucket.deleted"
+}
+
 ////////////////////
 // Project Events //
 ////////////////////
@@ -78,6 +78,11 @@ func ExportUserData(s *xorm.Session, u *user.User) (err error) {
 	if err != nil {
 		return err
 	}
+	// Time entries
+	err = exportTimeEntries(s, u, dumpWriter)
+	if err != nil {
+		return err
+	}
 	// Vikunja Version
 	err = utils.WriteBytesToZip("VERSION", []byte(version.Version), dumpWriter)
 	if err != nil {
@@ -193,6 +198,15 @@ func exportProjectsAndTasks(s *xorm.Session, u *user.User, wr *zip.Writer) (task
 		taskMap[c.TaskID].Comments = append(taskMap[c.TaskID].Comments, c)
 	}
 
+	checklistItems, err := getChecklistItemsForTasks(s, taskIDs)
+	if err != nil {
+		return
+	}
+
+	for _, item := range checklistItems {
+		taskMap[item.TaskID].ChecklistItems = append(taskMap[item.TaskID].ChecklistItems, item)
+	}
+
 	buckets := []*Bucket{}
 	err = s.In("project_id", projectIDs).Find(&buckets)
 	if err != nil {
@@ -207,6 +221,15 @@ func exportProjectsAndTasks(s *xorm.Session, u *user.User, wr *zip.Writer) (task
 		projectsMap[b.ProjectID].Buckets = append(projectsMap[b.ProjectID].Buckets, b)
 	}
 
+	customFields, err := getCustomFieldsForProjects(s, projectIDs)
+	if err != nil {
+		return
+	}
+
+	for _, cf := range customFields {
+		projectsMap[cf.ProjectID].CustomFields = append(projectsMap[cf.ProjectID].CustomFields, cf)
+	}
+
 	data, err := json.Marshal(projects)
 	if err != nil {
 		return taskIDs, err
@@ -223,6 +246,9 @@ func exportTaskAttachments(s *xorm.Session, wr *zip.Writer, taskIDs []int64) (er
 
 	attachmentFiles := make(map[int64]io.ReadCloser)
 	for _, ta := range tas {
+		if ta.IsLink() {
+			continue
+		}
 		err = ta.File.LoadFileByID()
 		if err != nil {
 			var pathError *fs.PathError
@@ -238,7 +264,9 @@ func exportTaskAttachments(s *xorm.Session, wr *zip.Writer, taskIDs []int64) (er
 }
 
 func exportSavedFilters(s *xorm.Session, u *user.User, wr *zip.Writer) (err error) {
-	filters, err := getSavedFiltersForUser(s, u)
+	// Only the user's own filters are exported, not the ones shared with them
+	filters := []*SavedFilter{}
+	err = s.Where("owner_id = ?", u.ID).Find(&filters)
 	if err != nil {
 		return err
 	}
@@ -251,6 +279,29 @@ func exportSavedFilters(s *xorm.Session, u *user.User, wr *zip.Writer) (err erro
 	return utils.WriteBytesToZip("filters.json", data, wr)
 }
 
+func exportTimeEntries(s *xorm.Session, u *user.User, wr *zip.Writer) (err error) {
+	entries := []*TimeEntry{}
+	err = s.
+		Where("user_id = ?", u.ID).
+		OrderBy("start_time asc").
+		Find(&entries)
+	if err != nil {
+		return err
+	}
+
+	err = addUsersToTimeEntries(s, entries)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteBytesToZip("time_entries.json", data, wr)
+}
+
 func exportProjectBackgrounds(s *xorm.Session, u *user.User, wr *zip.Writer) (err error) {
 	projects, _, _, err := getRawProjectsForUser(
 		s,
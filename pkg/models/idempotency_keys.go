@@ -0,0 +1,167 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"xorm.io/xorm"
+)
+
+// The max length of an idempotency key passed by a client
+const maxIdempotencyKeyLength = 250
+
+// IdempotencyKey stores the response of a request made with an `Idempotency-Key` header so that it can be replayed
+// when the client retries the same request.
+type IdempotencyKey struct {
+	ID int64 `xorm:"bigint autoincr not null unique pk"`
+	// The key passed by the client.
+	ClientKey string `xorm:"varchar(250) not null unique(owner_client_key)"`
+	// Whom the key belongs to, for example `user_1`. Keys of different users never collide.
+	Owner string `xorm:"varchar(250) not null unique(owner_client_key)"`
+	// The method, path and hash of the body of the request, used to detect when the key is reused for another request.
+	Method      string `xorm:"varchar(10) not null"`
+	Path        string `xorm:"text not null"`
+	RequestHash string `xorm:"varchar(64) not null"`
+	// The status code of the stored response. 0 while the first request is still running.
+	ResponseStatus      int    `xorm:"int not null default 0"`
+	ResponseContentType string `xorm:"varchar(250) null"`
+	ResponseBody        []byte `xorm:"longblob null"`
+
+	Created time.Time `xorm:"created not null index"`
+	Updated time.Time `xorm:"updated not null"`
+}
+
+// TableName holds the table name for idempotency keys
+func (*IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsCompleted returns whether the response of the request was already stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.ResponseStatus != 0
+}
+
+// StartIdempotentRequest stores the idempotency key of a new request. If the key was used before for the same
+// request, the stored key is returned so that its response can be replayed.
+func StartIdempotentRequest(s *xorm.Session, key *IdempotencyKey) (existing *IdempotencyKey, err error) {
+	if key.ClientKey == "" || len(key.ClientKey) > maxIdempotencyKeyLength {
+		return nil, &ErrInvalidIdempotencyKey{}
+	}
+
+	existing = &IdempotencyKey{}
+	has, err := s.
+		Where("owner = ? AND client_key = ?", key.Owner, key.ClientKey).
+		Get(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if !has {
+		_, err = s.Insert(key)
+		if err != nil {
+			// Another request with the same key might have been started at the same time
+			exists, existsErr := idempotencyKeyExists(key)
+			if existsErr == nil && exists {
+				return nil, &ErrIdempotencyKeyInProgress{Key: key.ClientKey}
+			}
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	if existing.Method != key.Method || existing.Path != key.Path || existing.RequestHash != key.RequestHash {
+		return nil, &ErrIdempotencyKeyReused{Key: key.ClientKey}
+	}
+
+	if !existing.IsCompleted() {
+		return nil, &ErrIdempotencyKeyInProgress{Key: key.ClientKey}
+	}
+
+	return existing, nil
+}
+
+// idempotencyKeyExists checks if the key was stored by another request. It uses its own session because a failed
+// insert aborts the whole transaction of the session on postgres.
+func idempotencyKeyExists(key *IdempotencyKey) (bool, error) {
+	s := db.NewSession()
+	defer s.Close()
+
+	return s.
+		Where("owner = ? AND client_key = ?", key.Owner, key.ClientKey).
+		Exist(&IdempotencyKey{})
+}
+
+// CompleteIdempotentRequest stores the response of the request of an idempotency key
+func CompleteIdempotentRequest(s *xorm.Session, key *IdempotencyKey) (err error) {
+	_, err = s.
+		Where("owner = ? AND client_key = ?", key.Owner, key.ClientKey).
+		Cols("response_status", "response_content_type", "response_body").
+		Update(key)
+	return
+}
+
+// AbortIdempotentRequest removes an idempotency key if its request failed, so that the client can retry it
+func AbortIdempotentRequest(s *xorm.Session, key *IdempotencyKey) (err error) {
+	_, err = s.
+		Where("owner = ? AND client_key = ?", key.Owner, key.ClientKey).
+		Delete(&IdempotencyKey{})
+	return
+}
+
+func pruneIdempotencyKeys(s *xorm.Session, olderThan time.Time) (deleted int64, err error) {
+	return s.Where("created < ?", olderThan).Delete(&IdempotencyKey{})
+}
+
+// RegisterIdempotencyKeyCleanupCron deletes idempotency keys which are older than the configured retention
+func RegisterIdempotencyKeyCleanupCron() {
+	if !config.IdempotencyEnabled.GetBool() {
+		return
+	}
+
+	const logPrefix = "[Idempotency Key Cleanup Cron] "
+
+	retention := time.Duration(config.IdempotencyRetentionHours.GetInt64()) * time.Hour
+
+	err := cron.Schedule("0 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		deleted, err := pruneIdempotencyKeys(s, time.Now().Add(-retention))
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not delete old idempotency keys: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit deleted idempotency keys: %s", err)
+			return
+		}
+
+		if deleted > 0 {
+			log.Debugf(logPrefix+"Deleted %d old idempotency keys", deleted)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register idempotency key cleanup cron: %s", err)
+	}
+}
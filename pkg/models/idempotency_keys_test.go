@@ -0,0 +1,167 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixtureRequestHash = "c5a4a3d2e8f1b7e6a9d0c3b2a1f4e7d6c9b8a5f2e1d4c7b0a3f6e9d2c5b8a1f4"
+
+func newIdempotencyKey(clientKey string) *IdempotencyKey {
+	return &IdempotencyKey{
+		ClientKey:   clientKey,
+		Owner:       "user_1",
+		Method:      "PUT",
+		Path:        "/api/v1/projects/1/tasks",
+		RequestHash: fixtureRequestHash,
+	}
+}
+
+func TestStartIdempotentRequest(t *testing.T) {
+	t.Run("new key", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		existing, err := StartIdempotentRequest(s, newIdempotencyKey("new"))
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+		db.AssertExists(t, "idempotency_keys", map[string]interface{}{
+			"client_key":      "new",
+			"owner":           "user_1",
+			"response_status": 0,
+		}, false)
+	})
+	t.Run("completed key", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		existing, err := StartIdempotentRequest(s, newIdempotencyKey("completed"))
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, 201, existing.ResponseStatus)
+		assert.Equal(t, `{"id":42}`, string(existing.ResponseBody))
+	})
+	t.Run("same key of another user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		key := newIdempotencyKey("completed")
+		key.Owner = "user_2"
+		existing, err := StartIdempotentRequest(s, key)
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+	})
+	t.Run("in progress", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := StartIdempotentRequest(s, newIdempotencyKey("in-progress"))
+		require.Error(t, err)
+		assert.True(t, IsErrIdempotencyKeyInProgress(err))
+	})
+	t.Run("reused for another request", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		key := newIdempotencyKey("completed")
+		key.RequestHash = "other"
+		_, err := StartIdempotentRequest(s, key)
+		require.Error(t, err)
+		assert.True(t, IsErrIdempotencyKeyReused(err))
+	})
+	t.Run("too long", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := StartIdempotentRequest(s, newIdempotencyKey(strings.Repeat("a", 251)))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidIdempotencyKey(err))
+	})
+}
+
+func TestCompleteIdempotentRequest(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	key := newIdempotencyKey("in-progress")
+	key.ResponseStatus = 200
+	key.ResponseContentType = "application/json"
+	key.ResponseBody = []byte(`{"id":1}`)
+	require.NoError(t, CompleteIdempotentRequest(s, key))
+
+	existing, err := StartIdempotentRequest(s, newIdempotencyKey("in-progress"))
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, 200, existing.ResponseStatus)
+	assert.Equal(t, `{"id":1}`, string(existing.ResponseBody))
+}
+
+func TestAbortIdempotentRequest(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	require.NoError(t, AbortIdempotentRequest(s, newIdempotencyKey("in-progress")))
+	db.AssertMissing(t, "idempotency_keys", map[string]interface{}{
+		"client_key": "in-progress",
+	})
+}
+
+func TestPruneIdempotencyKeys(t *testing.T) {
+	t.Run("old keys", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		deleted, err := pruneIdempotencyKeys(s, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		db.AssertMissing(t, "idempotency_keys", map[string]interface{}{
+			"client_key": "completed",
+		})
+	})
+	t.Run("keys within the retention", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := StartIdempotentRequest(s, newIdempotencyKey("recent"))
+		require.NoError(t, err)
+
+		deleted, err := pruneIdempotencyKeys(s, time.Now().Add(-24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		db.AssertExists(t, "idempotency_keys", map[string]interface{}{
+			"client_key": "recent",
+		}, false)
+	})
+}
@@ -0,0 +1,277 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+
+	"xorm.io/xorm"
+)
+
+// inboundMailAttachment is a file attached to an inbound email
+type inboundMailAttachment struct {
+	name    string
+	content []byte
+}
+
+// inboundMail holds everything of an inbound email needed to create a task from it
+type inboundMail struct {
+	from        string
+	recipients  []string
+	subject     string
+	text        string
+	html        string
+	attachments []*inboundMailAttachment
+}
+
+// parseInboundMail reads a raw email in the RFC 5322 format
+func parseInboundMail(raw io.Reader) (m *inboundMail, err error) {
+	msg, err := mail.ReadMessage(raw)
+	if err != nil {
+		return nil, &ErrInvalidInboundMail{Message: err.Error()}
+	}
+
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) == 0 {
+		return nil, &ErrInvalidInboundMail{Message: "missing or invalid sender"}
+	}
+
+	m = &inboundMail{from: from[0].Address}
+
+	// Mail servers put the actual recipient in one of these headers when forwarding emails
+	for _, header := range []string{"To", "Cc", "Delivered-To", "X-Original-To", "Envelope-To"} {
+		addresses, err := msg.Header.AddressList(header)
+		if err != nil {
+			continue
+		}
+		for _, address := range addresses {
+			m.recipients = append(m.recipients, address.Address)
+		}
+	}
+
+	dec := &mime.WordDecoder{}
+	m.subject, err = dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		m.subject = msg.Header.Get("Subject")
+	}
+
+	err = m.parsePart(textproto.MIMEHeader(msg.Header), msg.Body)
+	if err != nil {
+		return nil, &ErrInvalidInboundMail{Message: err.Error()}
+	}
+
+	return m, nil
+}
+
+// parsePart walks through a (multipart) body and collects the text, html and attachments of an email
+func (m *inboundMail) parsePart(header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			err = m.parsePart(part.Header, part)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := dispositionParams["filename"]
+	if name == "" {
+		name = params["name"]
+	}
+
+	switch {
+	case disposition == "attachment" || name != "":
+		if name == "" {
+			name = "attachment"
+		}
+		m.attachments = append(m.attachments, &inboundMailAttachment{name: name, content: content})
+	case mediaType == "text/html" && m.html == "":
+		m.html = string(content)
+	case mediaType == "text/plain" && m.text == "":
+		m.text = string(content)
+	}
+
+	return nil
+}
+
+// description returns the html body of the email or the text body converted to html
+func (m *inboundMail) description() string {
+	if m.html != "" {
+		return m.html
+	}
+
+	return plainTextToHTML(m.text)
+}
+
+// plainTextToHTML converts a plain text to html paragraphs, keeping its line breaks
+func plainTextToHTML(text string) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(line)
+		}
+		buf.WriteString("<p>" + strings.Join(lines, "<br>") + "</p>")
+	}
+	return buf.String()
+}
+
+// title returns the subject of the email shortened to the maximum length of a task title
+func (m *inboundMail) title() string {
+	title := strings.TrimSpace(m.subject)
+	if title == "" {
+		title = fmt.Sprintf("Email from %s", m.from)
+	}
+
+	runes := []rune(title)
+	if len(runes) > 250 {
+		title = string(runes[:250])
+	}
+	return title
+}
+
+// getProjectEmailAddress returns the project email address one of the recipients of an email belongs to
+func (m *inboundMail) getProjectEmailAddress(s *xorm.Session) (address *ProjectEmailAddress, err error) {
+	domain := strings.ToLower(config.InboundMailDomain.GetString())
+
+	for _, recipient := range m.recipients {
+		at := strings.LastIndex(recipient, "@")
+		if at < 0 || strings.ToLower(recipient[at+1:]) != domain {
+			continue
+		}
+
+		address = &ProjectEmailAddress{}
+		exists, err := s.
+			Where("token = ?", strings.ToLower(recipient[:at])).
+			Get(address)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return address, nil
+		}
+	}
+
+	return nil, &ErrInboundMailRecipientDoesNotExist{Recipients: m.recipients}
+}
+
+// CreateTaskFromInboundMail creates a task from a raw email sent to the email address of a project.
+// The subject of the email becomes the title of the task, the body its description and all attachments are added
+// as task attachments.
+func CreateTaskFromInboundMail(s *xorm.Session, raw io.Reader) (task *Task, err error) {
+	m, err := parseInboundMail(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := m.getProjectEmailAddress(s)
+	if err != nil {
+		return nil, err
+	}
+
+	creator, err := user.GetUserByID(s, address.CreatedByID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !address.isSenderAllowed(m.from, creator) {
+		return nil, &ErrInboundMailSenderNotAllowed{ProjectID: address.ProjectID, Sender: m.from}
+	}
+
+	task = &Task{
+		Title:       m.title(),
+		Description: m.description(),
+		ProjectID:   address.ProjectID,
+		Source:      TaskSourceEmail,
+	}
+
+	// The creator of the address might have lost access to the project since the address was created
+	can, err := task.CanCreate(s, creator)
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, ErrGenericForbidden{}
+	}
+
+	err = task.Create(s, creator)
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.ServiceEnableTaskAttachments.GetBool() {
+		return task, nil
+	}
+
+	for _, a := range m.attachments {
+		ta := &TaskAttachment{TaskID: task.ID}
+		err = ta.NewAttachment(s, io.NopCloser(bytes.NewReader(a.content)), a.name, uint64(len(a.content)), creator)
+		if err != nil {
+			// A single attachment which can't be stored, for example because it is too large, should not lose the whole email
+			log.Warningf("Could not add attachment %s of inbound email to task %d: %s", a.name, task.ID, err)
+			continue
+		}
+		task.Attachments = append(task.Attachments, ta)
+	}
+
+	return task, nil
+}
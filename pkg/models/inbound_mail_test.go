@@ -0,0 +1,162 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTaskFromInboundMail(t *testing.T) {
+	config.InboundMailDomain.Set("tasks.example.com")
+
+	t.Run("text body", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		raw := "From: User 1 <user1@example.com>\r\n" +
+			"To: a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4@tasks.example.com\r\n" +
+			"Subject: =?UTF-8?Q?Buy_m=C3=BCsli?=\r\n" +
+			"Content-Type: text/plain; charset=utf-8\r\n" +
+			"\r\n" +
+			"First line\r\nsecond <line>\r\n\r\nNext paragraph\r\n"
+
+		task, err := CreateTaskFromInboundMail(s, strings.NewReader(raw))
+		require.NoError(t, err)
+		assert.Equal(t, "Buy müsli", task.Title)
+		assert.Equal(t, "<p>First line<br>second &lt;line&gt;</p><p>Next paragraph</p>", task.Description)
+		assert.Equal(t, int64(1), task.ProjectID)
+		assert.Equal(t, int64(1), task.CreatedByID)
+		assert.Equal(t, TaskSourceEmail, task.Source)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         task.ID,
+			"title":      "Buy müsli",
+			"project_id": 1,
+		}, false)
+	})
+	t.Run("multipart with attachment", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		raw := "From: someone@allowed.com\r\n" +
+			"Cc: a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4@Tasks.Example.com\r\n" +
+			"Subject: Report\r\n" +
+			"MIME-Version: 1.0\r\n" +
+			"Content-Type: multipart/mixed; boundary=outer\r\n" +
+			"\r\n" +
+			"--outer\r\n" +
+			"Content-Type: multipart/alternative; boundary=inner\r\n" +
+			"\r\n" +
+			"--inner\r\n" +
+			"Content-Type: text/plain; charset=utf-8\r\n" +
+			"\r\n" +
+			"Plain text\r\n" +
+			"--inner\r\n" +
+			"Content-Type: text/html; charset=utf-8\r\n" +
+			"Content-Transfer-Encoding: quoted-printable\r\n" +
+			"\r\n" +
+			"<p>HTML =3D text</p>\r\n" +
+			"--inner--\r\n" +
+			"--outer\r\n" +
+			"Content-Type: text/plain\r\n" +
+			"Content-Disposition: attachment; filename=\"report.txt\"\r\n" +
+			"Content-Transfer-Encoding: base64\r\n" +
+			"\r\n" +
+			"cmVwb3J0IGNvbnRlbnQ=\r\n" +
+			"--outer--\r\n"
+
+		task, err := CreateTaskFromInboundMail(s, strings.NewReader(raw))
+		require.NoError(t, err)
+		assert.Equal(t, "Report", task.Title)
+		assert.Equal(t, "<p>HTML = text</p>", strings.TrimSpace(task.Description))
+		require.Len(t, task.Attachments, 1)
+		assert.Equal(t, "report.txt", task.Attachments[0].File.Name)
+		assert.Equal(t, uint64(len("report content")), task.Attachments[0].File.Size)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"task_id": task.ID,
+		}, false)
+	})
+	t.Run("no subject", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		raw := "From: user1@example.com\r\n" +
+			"To: a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4@tasks.example.com\r\n" +
+			"\r\n" +
+			"Body\r\n"
+
+		task, err := CreateTaskFromInboundMail(s, strings.NewReader(raw))
+		require.NoError(t, err)
+		assert.Equal(t, "Email from user1@example.com", task.Title)
+	})
+	t.Run("sender not allowed", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		raw := "From: someone@example.com\r\n" +
+			"To: a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4@tasks.example.com\r\n" +
+			"Subject: Spam\r\n" +
+			"\r\n" +
+			"Body\r\n"
+
+		_, err := CreateTaskFromInboundMail(s, strings.NewReader(raw))
+		require.Error(t, err)
+		assert.True(t, IsErrInboundMailSenderNotAllowed(err))
+	})
+	t.Run("unknown recipient", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		raw := "From: user1@example.com\r\n" +
+			"To: a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4@other.example.com, unknown@tasks.example.com\r\n" +
+			"Subject: Lost\r\n" +
+			"\r\n" +
+			"Body\r\n"
+
+		_, err := CreateTaskFromInboundMail(s, strings.NewReader(raw))
+		require.Error(t, err)
+		assert.True(t, IsErrInboundMailRecipientDoesNotExist(err))
+	})
+	t.Run("invalid email", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := CreateTaskFromInboundMail(s, strings.NewReader("not an email"))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidInboundMail(err))
+	})
+}
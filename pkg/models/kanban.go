@@ -17,13 +17,15 @@
 package models
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
-	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web"
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -43,10 +45,25 @@ type Bucket struct {
 
 	// The number of tasks currently in this bucket
 	Count int64 `xorm:"-" json:"count"`
+	// The sum of the estimates of all undone tasks in this bucket in seconds. Like the count, this respects the filter of the request.
+	OpenEstimate int64 `xorm:"-" json:"open_estimate"`
 
 	// The position this bucket has when querying all buckets. See the tasks.position property on how to use this.
+	// If it is not set when updating a bucket, the bucket keeps its position.
 	Position float64 `xorm:"double null" json:"position"`
 
+	// Actions which will be run on a task when it is moved into this bucket. See the docs of models.BucketAction for all possible actions.
+	Actions []*BucketAction `xorm:"JSON null" json:"actions"`
+
+	// If set, only these users can move tasks into this bucket. Project admins can always move tasks into all buckets. Only project admins can change this.
+	MoveAllowedUserIDs []int64 `xorm:"'move_allowed_user_ids' JSON null" json:"move_allowed_user_ids"`
+	// If set, only members of these teams can move tasks into this bucket. Project admins can always move tasks into all buckets. Only project admins can change this.
+	MoveAllowedTeamIDs []int64 `xorm:"'move_allowed_team_ids' JSON null" json:"move_allowed_team_ids"`
+
+	// How the tasks in this bucket are sorted. Can have four possible values: 0 = manually by their kanban position, 1 = by due date with the earliest first, 2 = by priority with the highest first, 3 = by their created date with the newest first.
+	// If the bucket is not sorted manually, the kanban position of its tasks can't be changed.
+	SortMode BucketSortMode `xorm:"not null default 0" json:"sort_mode"`
+
 	// A timestamp when this bucket was created. You cannot change this value.
 	Created time.Time `xorm:"created not null" json:"created"`
 	// A timestamp when this bucket was last updated. You cannot change this value.
@@ -56,13 +73,39 @@ type Bucket struct {
 	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
 	CreatedByID int64      `xorm:"bigint not null" json:"-"`
 
+	// The cursor to get the next tasks of this bucket. Pass it as the `cursor` query parameter when getting all buckets.
+	// Empty if there are no more tasks in this bucket.
+	NextCursor string `xorm:"-" json:"next_cursor"`
+	// The cursor of the tasks to get. If set, only the bucket of the cursor is returned with the tasks after it.
+	Cursor string `xorm:"-" json:"-" query:"cursor"`
+
+	// Whether the current user collapsed this bucket. Use the /projects/{projectID}/buckets/{bucketID}/collapsed endpoint to change this.
+	IsCollapsed bool `xorm:"-" json:"is_collapsed"`
+
 	// Including the task collection type so we can use task filters on kanban
 	TaskCollection `xorm:"-" json:"-"`
 
+	// Set from the If-Unmodified-Since header of an update request, see CheckUnmodifiedSince.
+	unmodifiedSince time.Time
+
 	web.Rights   `xorm:"-" json:"-"`
 	web.CRUDable `xorm:"-" json:"-"`
 }
 
+// BucketSortMode defines how the tasks in a bucket are sorted
+type BucketSortMode int
+
+const (
+	// BucketSortModeManual sorts the tasks by their kanban position
+	BucketSortModeManual BucketSortMode = iota
+	// BucketSortModeDueDate sorts the tasks by their due date, tasks without a due date come last
+	BucketSortModeDueDate
+	// BucketSortModePriority sorts the tasks by their priority, the highest priority comes first
+	BucketSortModePriority
+	// BucketSortModeCreated sorts the tasks by their creation date, the newest task comes first
+	BucketSortModeCreated
+)
+
 // TableName returns the table name for this bucket.
 func (b *Bucket) TableName() string {
 	return "buckets"
@@ -80,6 +123,140 @@ func getBucketByID(s *xorm.Session, id int64) (b *Bucket, err error) {
 	return
 }
 
+func (b *Bucket) validateSortMode() error {
+	switch b.SortMode {
+	case BucketSortModeManual,
+		BucketSortModeDueDate,
+		BucketSortModePriority,
+		BucketSortModeCreated:
+		return nil
+	default:
+		return &ErrInvalidBucketSortMode{BucketID: b.ID, SortMode: b.SortMode}
+	}
+}
+
+// getTaskOrderByForBuckets returns the order by statement which sorts the tasks of each bucket according to the
+// sort mode of the bucket. The columns of a sort mode are null for the tasks of all buckets with another sort mode
+// so that they don't change the order of these.
+func getTaskOrderByForBuckets(buckets map[int64]*Bucket) string {
+	bucketIDsBySortMode := make(map[BucketSortMode][]string)
+	for id, b := range buckets {
+		bucketIDsBySortMode[b.SortMode] = append(bucketIDsBySortMode[b.SortMode], strconv.FormatInt(id, 10))
+	}
+	inSortMode := func(mode BucketSortMode) string {
+		return "CASE WHEN `bucket_id` IN (" + strings.Join(bucketIDsBySortMode[mode], ", ") + ") THEN "
+	}
+
+	// Pinned tasks always come first, regardless of the sort mode
+	orderby := "`is_pinned` DESC, COALESCE(`pinned_position`, 0) ASC, "
+
+	if _, has := bucketIDsBySortMode[BucketSortModeDueDate]; has {
+		// Tasks without a due date come last
+		orderby += inSortMode(BucketSortModeDueDate) + "`due_date` IS NULL END ASC, " +
+			inSortMode(BucketSortModeDueDate) + "`due_date` END ASC, "
+	}
+	if _, has := bucketIDsBySortMode[BucketSortModePriority]; has {
+		orderby += inSortMode(BucketSortModePriority) + "COALESCE(`priority`, 0) END DESC, "
+	}
+	if _, has := bucketIDsBySortMode[BucketSortModeCreated]; has {
+		orderby += inSortMode(BucketSortModeCreated) + "`created` END DESC, "
+	}
+	// Tasks in buckets sorted manually are only sorted by kanban position
+
+	return orderby + "COALESCE(`kanban_position`, 0) ASC, `id` ASC"
+}
+
+type bucketTaskCount struct {
+	BucketID     int64 `xorm:"'bucket_id'"`
+	Count        int64 `xorm:"'task_count'"`
+	OpenEstimate int64 `xorm:"'open_estimate'"`
+}
+
+// setBucketTaskCounts sets the number of tasks and the sum of the estimates of all undone tasks matching cond
+// for all buckets in the map.
+func setBucketTaskCounts(s *xorm.Session, cond builder.Cond, bucketMap map[int64]*Bucket) error {
+	counts := []*bucketTaskCount{}
+	err := s.
+		Table("tasks").
+		Select("bucket_id, COUNT(*) AS task_count, COALESCE(SUM(CASE WHEN done THEN 0 ELSE estimate END), 0) AS open_estimate").
+		Where(cond).
+		GroupBy("bucket_id").
+		Find(&counts)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range counts {
+		bucket, exists := bucketMap[c.BucketID]
+		if !exists {
+			continue
+		}
+		bucket.Count = c.Count
+		bucket.OpenEstimate = c.OpenEstimate
+	}
+
+	return nil
+}
+
+type rankedBucketTask struct {
+	Task      `xorm:"extends"`
+	BucketRow int64 `xorm:"'bucket_row'"`
+}
+
+// getBucketTasks returns the tasks matching cond of all buckets in the map with one query. Each bucket gets up to
+// limit tasks starting at start plus one more task if there are more, sorted per bucket according to its sort mode.
+// The tasks are ordered by bucket. If a cursor is passed, the map must only contain the bucket of the cursor.
+func getBucketTasks(s *xorm.Session, cond builder.Cond, buckets map[int64]*Bucket, cursor *bucketTaskCursor, limit, start int) (tasks []*Task, err error) {
+	if len(buckets) == 0 {
+		return []*Task{}, nil
+	}
+
+	bucketIDs := make([]int64, 0, len(buckets))
+	for id := range buckets {
+		bucketIDs = append(bucketIDs, id)
+	}
+
+	bucketCond := builder.And(cond, builder.In("bucket_id", bucketIDs))
+	if cursor != nil {
+		bucketCond = builder.And(bucketCond, cursor.getAfterCond(buckets[cursor.BucketID].SortMode))
+	}
+
+	// Numbering the tasks per bucket allows limiting the number of tasks of each bucket in the same query
+	ranked := builder.
+		Select("`tasks`.*", "ROW_NUMBER() OVER (PARTITION BY `bucket_id` ORDER BY "+getTaskOrderByForBuckets(buckets)+") AS `bucket_row`").
+		From("tasks").
+		Where(bucketCond)
+
+	query := builder.
+		Select("*").
+		From(ranked, "ranked_tasks").
+		OrderBy("`bucket_id` ASC, `bucket_row` ASC")
+	if limit > 0 {
+		// One more task than needed to know if there is a next page
+		query = query.Where(builder.And(
+			builder.Gt{"bucket_row": start},
+			builder.Lte{"bucket_row": start + limit + 1},
+		))
+	}
+
+	querySQL, args, err := query.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rankedTasks := []*rankedBucketTask{}
+	err = s.SQL(querySQL, args...).Find(&rankedTasks)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks = make([]*Task, 0, len(rankedTasks))
+	for _, rt := range rankedTasks {
+		tasks = append(tasks, &rt.Task)
+	}
+	return tasks, nil
+}
+
 func getDefaultBucketID(s *xorm.Session, project *Project) (bucketID int64, err error) {
 	if project.DefaultBucketID != 0 {
 		return project.DefaultBucketID, nil
@@ -99,7 +276,8 @@ func getDefaultBucketID(s *xorm.Session, project *Project) (bucketID int64, err
 
 // ReadAll returns all buckets with their tasks for a certain project
 // @Summary Get all kanban buckets of a project
-// @Description Returns all kanban buckets with belong to a project including their tasks. Buckets are always sorted by their `position` in ascending order. Tasks are sorted by their `kanban_position` in ascending order.
+// @Description Returns all kanban buckets with belong to a project including their tasks. Buckets are always sorted by their `position` in ascending order. Tasks are sorted by their `kanban_position` in ascending order, pinned tasks always come first.
+// @Description If the project is a saved filter, the buckets are built from the bucket configuration of the filter and contain tasks from all projects matching the filter. These buckets have a negative id and cannot be changed.
 // @tags project
 // @Accept json
 // @Produce json
@@ -107,6 +285,7 @@ func getDefaultBucketID(s *xorm.Session, project *Project) (bucketID int64, err
 // @Param id path int true "Project Id"
 // @Param page query int false "The page number for tasks. Used for pagination. If not provided, the first page of results is returned."
 // @Param per_page query int false "The maximum number of tasks per bucket per page. This parameter is limited by the configured maximum of items per page."
+// @Param cursor query string false "The `next_cursor` of a bucket to get the tasks after the last task of the previous request. Only the bucket of the cursor is returned and the `page` parameter is ignored. Other than pages, cursors are not affected by tasks being moved between the requests."
 // @Param s query string false "Search tasks by task text."
 // @Param filter query string false "The filter query to match tasks by. Check out https://vikunja.io/docs/filters for a full explanation of the feature."
 // @Param filter_timezone query string false "The time zone which should be used for date match (statements like "now" resolve to different actual times)"
@@ -116,6 +295,10 @@ func getDefaultBucketID(s *xorm.Session, project *Project) (bucketID int64, err
 // @Router /projects/{id}/buckets [get]
 func (b *Bucket) ReadAll(s *xorm.Session, auth web.Auth, search string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
 
+	if filterID := getSavedFilterIDFromProjectID(b.ProjectID); filterID > 0 {
+		return b.readAllForSavedFilter(s, auth, filterID, search, page, perPage)
+	}
+
 	project, err := GetProjectSimpleByID(s, b.ProjectID)
 	if err != nil {
 		return nil, 0, 0, err
@@ -157,83 +340,112 @@ func (b *Bucket) ReadAll(s *xorm.Session, auth web.Auth, search string, page int
 		bb.CreatedBy = users[bb.CreatedByID]
 	}
 
-	tasks := []*Task{}
+	err = setCollapsedStates(s, auth, bucketMap)
+	if err != nil {
+		return
+	}
 
 	opts, err := getTaskFilterOptsFromCollection(&b.TaskCollection)
 	if err != nil {
 		return nil, 0, 0, err
 	}
 
-	opts.sortby = []*sortParam{
-		{
-			orderBy: orderAscending,
-			sortBy:  taskPropertyKanbanPosition,
-		},
-	}
-	opts.page = page
-	opts.perPage = perPage
-	opts.search = search
-
 	for _, filter := range opts.parsedFilters {
-		if filter.field == taskPropertyBucketID {
-
-			// Limiting the map to the one filter we're looking for is the easiest way to ensure we only
-			// get tasks in this bucket
-			bucketID := filter.value.(int64)
-			bucket := bucketMap[bucketID]
-
-			bucketMap = make(map[int64]*Bucket, 1)
-			bucketMap[bucketID] = bucket
-			break
+		if filter.field != taskPropertyBucketID {
+			continue
 		}
-	}
 
-	originalFilter := opts.filter
-	for id, bucket := range bucketMap {
-
-		if !strings.Contains(originalFilter, "bucket_id") {
-			var filterString string
-			if originalFilter == "" {
-				filterString = "bucket_id = " + strconv.FormatInt(id, 10)
-			} else {
-				filterString = "(" + originalFilter + ") && bucket_id = " + strconv.FormatInt(id, 10)
+		var bucketIDs []int64
+		switch filter.comparator {
+		case taskFilterComparatorEquals:
+			if bucketID, is := filter.value.(int64); is {
+				bucketIDs = []int64{bucketID}
 			}
-			opts.parsedFilters, err = getTaskFiltersFromFilterString(filterString, opts.filterTimezone)
-			if err != nil {
-				return
+		case taskFilterComparatorIn:
+			values, _ := filter.value.([]interface{})
+			for _, value := range values {
+				if bucketID, is := value.(int64); is {
+					bucketIDs = append(bucketIDs, bucketID)
+				}
 			}
+		default:
+			// The filter itself is part of the task query, the buckets are only limited for exact matches
+			continue
 		}
 
-		ts, _, total, err := getRawTasksForProjects(s, []*Project{{ID: bucket.ProjectID}}, auth, opts)
+		// Limiting the map to the buckets we're looking for is the easiest way to ensure we only
+		// get tasks in these buckets
+		filteredBucketMap := make(map[int64]*Bucket, len(bucketIDs))
+		for _, bucketID := range bucketIDs {
+			if bucket, exists := bucketMap[bucketID]; exists {
+				filteredBucketMap[bucketID] = bucket
+			}
+		}
+		bucketMap = filteredBucketMap
+		break
+	}
+
+	var cursor *bucketTaskCursor
+	if b.Cursor != "" {
+		cursor, err = parseBucketTaskCursor(b.Cursor)
 		if err != nil {
 			return nil, 0, 0, err
 		}
 
-		bucket.Count = total
+		bucket, exists := bucketMap[cursor.BucketID]
+		if !exists {
+			return nil, 0, 0, &ErrInvalidBucketTaskCursor{}
+		}
 
-		tasks = append(tasks, ts...)
+		bucketMap = map[int64]*Bucket{bucket.ID: bucket}
+		buckets = []*Bucket{bucket}
 	}
 
-	taskMap := make(map[int64]*Task, len(tasks))
-	for _, t := range tasks {
-		taskMap[t.ID] = t
+	// The typesense searcher limits the number of results it returns, therefore this always uses the database.
+	opts.search = search
+	opts.projectIDs = []int64{b.ProjectID}
+	searcher := &dbTaskSearcher{
+		s: s,
+		a: auth,
+	}
+	cond, err := searcher.getSearchCond(opts)
+	if err != nil {
+		return nil, 0, 0, err
 	}
 
-	err = addMoreInfoToTasks(s, taskMap, auth)
+	err = setBucketTaskCounts(s, cond, bucketMap)
 	if err != nil {
 		return nil, 0, 0, err
 	}
 
-	// Put all tasks in their buckets
-	// All tasks which are not associated to any bucket will have bucket id 0 which is the nil value for int64
-	// Since we created a bucked with that id at the beginning, all tasks should be in there.
-	for _, task := range tasks {
-		// Check if the bucket exists in the map to prevent nil pointer panics
-		if _, exists := bucketMap[task.BucketID]; !exists {
-			log.Debugf("Tried to put task %d into bucket %d which does not exist in project %d", task.ID, task.BucketID, b.ProjectID)
+	limit, start := getLimitFromPageIndex(page, perPage)
+	if cursor != nil {
+		// The cursor replaces the page
+		limit, _ = getLimitFromPageIndex(1, perPage)
+		start = 0
+	}
+
+	tasks, err := getBucketTasks(s, cond, bucketMap, cursor, limit, start)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	taskMap := make(map[int64]*Task, len(tasks))
+	for _, t := range tasks {
+		bucket := bucketMap[t.BucketID]
+		if limit > 0 && len(bucket.Tasks) == limit {
+			// This is the one task more than needed which shows there is a next page
+			bucket.NextCursor = newBucketTaskCursor(bucket.ID, bucket.Tasks[limit-1])
 			continue
 		}
-		bucketMap[task.BucketID].Tasks = append(bucketMap[task.BucketID].Tasks, task)
+
+		bucket.Tasks = append(bucket.Tasks, t)
+		taskMap[t.ID] = t
+	}
+
+	err = addMoreInfoToTasks(s, taskMap, auth)
+	if err != nil {
+		return nil, 0, 0, err
 	}
 
 	return buckets, len(buckets), int64(len(buckets)), nil
@@ -254,6 +466,23 @@ func (b *Bucket) ReadAll(s *xorm.Session, auth web.Auth, search string, page int
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{id}/buckets [put]
 func (b *Bucket) Create(s *xorm.Session, a web.Auth) (err error) {
+	err = b.validateActions(s, a)
+	if err != nil {
+		return
+	}
+
+	err = b.validateSortMode()
+	if err != nil {
+		return
+	}
+
+	if b.hasMovePermissions() {
+		err = b.validateMovePermissions(s, a)
+		if err != nil {
+			return
+		}
+	}
+
 	b.CreatedBy, err = GetUserOrLinkShareUser(s, a)
 	if err != nil {
 		return
@@ -267,7 +496,15 @@ func (b *Bucket) Create(s *xorm.Session, a web.Auth) (err error) {
 
 	b.Position = calculateDefaultPosition(b.ID, b.Position)
 	_, err = s.Where("id = ?", b.ID).Update(b)
-	return
+	if err != nil {
+		return
+	}
+
+	doer, _ := user.GetFromAuth(a)
+	return events.DispatchOnCommit(s, &BucketCreatedEvent{
+		Bucket: b,
+		Doer:   doer,
+	})
 }
 
 // Update Updates an existing bucket
@@ -285,16 +522,84 @@ func (b *Bucket) Create(s *xorm.Session, a web.Auth) (err error) {
 // @Failure 404 {object} web.HTTPError "The bucket does not exist."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{projectID}/buckets/{bucketID} [post]
-func (b *Bucket) Update(s *xorm.Session, _ web.Auth) (err error) {
+func (b *Bucket) Update(s *xorm.Session, a web.Auth) (err error) {
+	err = CheckUnmodifiedSince(s, b, b.ID, b.unmodifiedSince)
+	if err != nil {
+		return err
+	}
+
+	err = b.validateActions(s, a)
+	if err != nil {
+		return
+	}
+
+	err = b.validateSortMode()
+	if err != nil {
+		return
+	}
+
+	bb, err := getBucketByID(s, b.ID)
+	if err != nil {
+		return
+	}
+
+	if !slices.Equal(b.MoveAllowedUserIDs, bb.MoveAllowedUserIDs) ||
+		!slices.Equal(b.MoveAllowedTeamIDs, bb.MoveAllowedTeamIDs) {
+		b.ProjectID = bb.ProjectID
+		err = b.validateMovePermissions(s, a)
+		if err != nil {
+			return
+		}
+	}
+
+	// A bucket without a position keeps its current one
+	if b.Position == 0 {
+		b.Position = bb.Position
+	}
+
 	_, err = s.
 		Where("id = ?", b.ID).
 		Cols(
 			"title",
 			"limit",
 			"position",
+			"actions",
+			"sort_mode",
+			"move_allowed_user_ids",
+			"move_allowed_team_ids",
 		).
 		Update(b)
-	return
+	if err != nil {
+		return
+	}
+
+	// Only a changed position can be too close to the one of another bucket
+	if b.Position != bb.Position {
+		var rebalance bool
+		rebalance, err = positionIsTooClose(s, &Bucket{}, "position", "project_id", bb.ProjectID, b.ID, b.Position)
+		if err != nil {
+			return
+		}
+		if rebalance {
+			err = recalculateBucketPositions(s, bb.ProjectID)
+			if err != nil {
+				return
+			}
+
+			rebalanced, err := getBucketByID(s, b.ID)
+			if err != nil {
+				return err
+			}
+			b.Position = rebalanced.Position
+		}
+	}
+
+	b.ProjectID = bb.ProjectID
+	doer, _ := user.GetFromAuth(a)
+	return events.DispatchOnCommit(s, &BucketUpdatedEvent{
+		Bucket: b,
+		Doer:   doer,
+	})
 }
 
 // Delete removes a bucket, but no tasks
@@ -338,6 +643,26 @@ func (b *Bucket) Delete(s *xorm.Session, a web.Auth) (err error) {
 		p.DoneBucketID = 0
 		updateProject = true
 	}
+	for source, bucketID := range p.SourceDefaultBuckets {
+		if bucketID == b.ID {
+			delete(p.SourceDefaultBuckets, source)
+			updateProject = true
+		}
+	}
+	if b.ID == p.DoneTasksRetentionBucketID {
+		p.DoneTasksRetentionBucketID = 0
+		updateProject = true
+	}
+	slaRules := make([]*SLARule, 0, len(p.SLARules))
+	for _, rule := range p.SLARules {
+		if rule.BucketID != b.ID {
+			slaRules = append(slaRules, rule)
+		}
+	}
+	if len(slaRules) != len(p.SLARules) {
+		p.SLARules = slaRules
+		updateProject = true
+	}
 	if updateProject {
 		err = p.Update(s, a)
 		if err != nil {
@@ -350,6 +675,12 @@ func (b *Bucket) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
+	tasks := []*Task{}
+	err = s.Where("bucket_id = ?", b.ID).Find(&tasks)
+	if err != nil {
+		return
+	}
+
 	// Remove all associations of tasks to that bucket
 	_, err = s.
 		Where("bucket_id = ?", b.ID).
@@ -359,7 +690,32 @@ func (b *Bucket) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	for _, t := range tasks {
+		err = recordBucketTransition(s, t.ID, b.ProjectID, b.ID, b.ProjectID, defaultBucketID)
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = s.Where("bucket_id = ?", b.ID).Delete(&BucketCollapsedState{})
+	if err != nil {
+		return
+	}
+
+	deleted, err := getBucketByID(s, b.ID)
+	if err != nil {
+		return
+	}
+
 	// Remove the bucket itself
 	_, err = s.Where("id = ?", b.ID).Delete(&Bucket{})
-	return
+	if err != nil {
+		return
+	}
+
+	doer, _ := user.GetFromAuth(a)
+	return events.DispatchOnCommit(s, &BucketDeletedEvent{
+		Bucket: deleted,
+		Doer:   doer,
+	})
 }
@@ -0,0 +1,197 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// BucketActionKind defines what a bucket action does with a task
+type BucketActionKind string
+
+const (
+	// BucketActionSetDone marks the task as done.
+	BucketActionSetDone BucketActionKind = "set_done"
+	// BucketActionAddAssignee assigns the user with the id from the action value to the task.
+	BucketActionAddAssignee BucketActionKind = "add_assignee"
+	// BucketActionClearAssignees removes all assignees from the task.
+	BucketActionClearAssignees BucketActionKind = "clear_assignees"
+	// BucketActionAddLabel adds the label with the id from the action value to the task.
+	BucketActionAddLabel BucketActionKind = "add_label"
+	// BucketActionRemoveLabel removes the label with the id from the action value from the task.
+	BucketActionRemoveLabel BucketActionKind = "remove_label"
+	// BucketActionSetPriority sets the priority of the task to the action value.
+	BucketActionSetPriority BucketActionKind = "set_priority"
+	// BucketActionSetDueDate sets the due date of the task to the time it was moved plus the action value in seconds.
+	BucketActionSetDueDate BucketActionKind = "set_due_date"
+)
+
+// BucketAction is an action which is run on a task when the task is moved into the bucket the action belongs to.
+type BucketAction struct {
+	// The kind of the action. Can be `set_done`, `add_assignee`, `clear_assignees`, `add_label`, `remove_label`, `set_priority` or `set_due_date`.
+	Kind BucketActionKind `json:"kind"`
+	// The value of the action. This is the user id for `add_assignee`, the label id for `add_label` and `remove_label`,
+	// the priority between 0 and 5 for `set_priority` and the offset in seconds from the time the task was moved for `set_due_date`.
+	// It is ignored for all other kinds.
+	Value int64 `json:"value"`
+}
+
+// validateActions checks all actions of a bucket are valid and the referenced entities are accessible
+func (b *Bucket) validateActions(s *xorm.Session, a web.Auth) (err error) {
+	var project *Project
+	for _, action := range b.Actions {
+		switch action.Kind {
+		case BucketActionSetDone,
+			BucketActionClearAssignees,
+			BucketActionSetDueDate:
+			// Nothing to validate
+		case BucketActionSetPriority:
+			if action.Value < 0 || action.Value > 5 {
+				return &ErrInvalidBucketAction{BucketID: b.ID, Kind: action.Kind}
+			}
+		case BucketActionAddAssignee:
+			if project == nil {
+				project, err = b.getProject(s)
+				if err != nil {
+					return err
+				}
+			}
+
+			assignee, err := user.GetUserByID(s, action.Value)
+			if err != nil {
+				return err
+			}
+			can, _, err := project.CanRead(s, assignee)
+			if err != nil {
+				return err
+			}
+			if !can {
+				return ErrUserDoesNotHaveAccessToProject{ProjectID: project.ID, UserID: assignee.ID}
+			}
+		case BucketActionAddLabel, BucketActionRemoveLabel:
+			label, err := getLabelByIDSimple(s, action.Value)
+			if err != nil {
+				return err
+			}
+			can, _, err := label.CanRead(s, a)
+			if err != nil {
+				return err
+			}
+			if !can {
+				return ErrUserHasNoAccessToLabel{LabelID: label.ID, UserID: a.GetID()}
+			}
+		default:
+			return &ErrInvalidBucketAction{BucketID: b.ID, Kind: action.Kind}
+		}
+	}
+
+	return nil
+}
+
+func (b *Bucket) getProject(s *xorm.Session) (*Project, error) {
+	projectID := b.ProjectID
+	if projectID == 0 {
+		bb, err := getBucketByID(s, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		projectID = bb.ProjectID
+	}
+
+	return GetProjectSimpleByID(s, projectID)
+}
+
+// applyTaskFieldActions changes the fields of a task according to the actions of the bucket it was moved into.
+// Labels are not stored with the task itself and therefore handled separately in applyLabelActions.
+func (b *Bucket) applyTaskFieldActions(t *Task, now time.Time) {
+	for _, action := range b.Actions {
+		switch action.Kind {
+		case BucketActionSetDone:
+			t.Done = true
+		case BucketActionAddAssignee:
+			var isAssigned bool
+			for _, assignee := range t.Assignees {
+				if assignee.ID == action.Value {
+					isAssigned = true
+					break
+				}
+			}
+			if !isAssigned {
+				t.Assignees = append(t.Assignees, &user.User{ID: action.Value})
+			}
+		case BucketActionClearAssignees:
+			t.Assignees = nil
+		case BucketActionSetPriority:
+			t.Priority = action.Value
+		case BucketActionSetDueDate:
+			t.DueDate = now.Add(time.Duration(action.Value) * time.Second)
+		case BucketActionAddLabel, BucketActionRemoveLabel:
+			// Handled in applyLabelActions
+		}
+	}
+}
+
+// applyLabelActions adds or removes labels of a task according to the actions of the bucket it was moved into.
+// It goes through the label relations so the user moving the task needs access to the labels it adds.
+func (b *Bucket) applyLabelActions(s *xorm.Session, a web.Auth, t *Task) (err error) {
+	for _, action := range b.Actions {
+		switch action.Kind {
+		case BucketActionAddLabel:
+			lt := &LabelTask{LabelID: action.Value, TaskID: t.ID}
+			exists, err := s.Exist(&LabelTask{LabelID: lt.LabelID, TaskID: lt.TaskID})
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			can, err := lt.CanCreate(s, a)
+			if IsErrLabelDoesNotExist(err) {
+				// The label was deleted after the action was saved
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if !can {
+				return ErrUserHasNoAccessToLabel{LabelID: lt.LabelID, UserID: a.GetID()}
+			}
+			err = lt.Create(s, a)
+			if err != nil {
+				return err
+			}
+		case BucketActionRemoveLabel:
+			lt := &LabelTask{LabelID: action.Value, TaskID: t.ID}
+			err = lt.Delete(s, a)
+			if err != nil {
+				return err
+			}
+		case BucketActionSetDone,
+			BucketActionAddAssignee,
+			BucketActionClearAssignees,
+			BucketActionSetPriority,
+			BucketActionSetDueDate:
+			// Handled in applyTaskFieldActions
+		}
+	}
+
+	return nil
+}
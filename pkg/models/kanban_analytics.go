@@ -0,0 +1,290 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskBucketTransition records a task being moved into or out of a bucket
+type TaskBucketTransition struct {
+	// The unique, numeric id of this transition.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The task which was moved.
+	TaskID int64 `xorm:"bigint not null INDEX" json:"task_id"`
+	// The project the buckets of this transition belong to.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id"`
+	// The bucket the task was moved out of. 0 if the task was created or moved into the project.
+	FromBucketID int64 `xorm:"bigint not null default 0" json:"from_bucket_id"`
+	// The bucket the task was moved into. 0 if the task was moved out of the project.
+	ToBucketID int64 `xorm:"bigint not null default 0" json:"to_bucket_id"`
+	// A timestamp when the task was moved.
+	Created time.Time `xorm:"created not null" json:"created"`
+}
+
+// TableName returns the table name for task bucket transitions
+func (*TaskBucketTransition) TableName() string {
+	return "task_bucket_transitions"
+}
+
+// recordBucketTransition saves a task moving between buckets. If the task was moved to another project,
+// it is recorded as leaving the old project and entering the new one.
+func recordBucketTransition(s *xorm.Session, taskID, fromProjectID, fromBucketID, toProjectID, toBucketID int64) (err error) {
+	if fromProjectID == toProjectID {
+		if fromBucketID == toBucketID {
+			return nil
+		}
+
+		_, err = s.Insert(&TaskBucketTransition{
+			TaskID:       taskID,
+			ProjectID:    toProjectID,
+			FromBucketID: fromBucketID,
+			ToBucketID:   toBucketID,
+		})
+		return
+	}
+
+	if fromBucketID != 0 {
+		_, err = s.Insert(&TaskBucketTransition{
+			TaskID:       taskID,
+			ProjectID:    fromProjectID,
+			FromBucketID: fromBucketID,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	if toBucketID != 0 {
+		_, err = s.Insert(&TaskBucketTransition{
+			TaskID:     taskID,
+			ProjectID:  toProjectID,
+			ToBucketID: toBucketID,
+		})
+	}
+	return
+}
+
+const (
+	kanbanAnalyticsDateFormat  = "2006-01-02"
+	kanbanAnalyticsDefaultDays = 30
+	kanbanAnalyticsMaxDays     = 366
+	kanbanAnalyticsDay         = 24 * time.Hour
+)
+
+// ProjectKanbanAnalytics holds cycle time and cumulative flow data of the kanban board of a project
+type ProjectKanbanAnalytics struct {
+	// The project the analytics belong to.
+	ProjectID int64 `json:"project_id" param:"project"`
+	// The first day of the analyzed period in the format YYYY-MM-DD. Defaults to 30 days ago.
+	From string `json:"from" query:"from"`
+	// The last day of the analyzed period in the format YYYY-MM-DD. Defaults to today.
+	To string `json:"to" query:"to"`
+
+	// The time tasks spent in each bucket of the project.
+	Buckets []*BucketAnalytics `json:"buckets"`
+	// The number of tasks per bucket at the end of each day of the analyzed period.
+	CumulativeFlow []*CumulativeFlowEntry `json:"cumulative_flow"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// BucketAnalytics holds how long tasks stay in a bucket
+type BucketAnalytics struct {
+	// The id of the bucket.
+	BucketID int64 `json:"bucket_id"`
+	// The title of the bucket.
+	Title string `json:"title"`
+	// The average number of seconds tasks stayed in this bucket before they were moved out of it.
+	// Only includes tasks which left the bucket during the analyzed period.
+	AverageTimeInBucket int64 `json:"average_time_in_bucket"`
+	// The number of times a task left this bucket during the analyzed period.
+	TasksMovedOut int64 `json:"tasks_moved_out"`
+}
+
+// CumulativeFlowEntry holds the number of tasks per bucket at the end of a day
+type CumulativeFlowEntry struct {
+	// The day in the format YYYY-MM-DD.
+	Date string `json:"date"`
+	// The number of tasks in each bucket at the end of the day, keyed by bucket id.
+	Buckets map[int64]int64 `json:"buckets"`
+}
+
+// CanRead checks if the user can see the analytics of a project
+func (pka *ProjectKanbanAnalytics) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	p := &Project{ID: pka.ProjectID}
+	return p.CanRead(s, a)
+}
+
+func (pka *ProjectKanbanAnalytics) getPeriod() (from, to time.Time, err error) {
+	tz := config.GetTimeZone()
+	now := time.Now().In(tz)
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+
+	if pka.To != "" {
+		to, err = time.ParseInLocation(kanbanAnalyticsDateFormat, pka.To, tz)
+		if err != nil {
+			return from, to, ErrInvalidData{Message: "The end date must be in the format YYYY-MM-DD."}
+		}
+	}
+
+	from = to.AddDate(0, 0, -(kanbanAnalyticsDefaultDays - 1))
+	if pka.From != "" {
+		from, err = time.ParseInLocation(kanbanAnalyticsDateFormat, pka.From, tz)
+		if err != nil {
+			return from, to, ErrInvalidData{Message: "The start date must be in the format YYYY-MM-DD."}
+		}
+	}
+
+	if from.After(to) {
+		return from, to, ErrInvalidData{Message: "The start date must be before the end date."}
+	}
+
+	if to.Sub(from) >= kanbanAnalyticsMaxDays*kanbanAnalyticsDay {
+		return from, to, ErrInvalidData{Message: "The analyzed period can't be longer than a year."}
+	}
+
+	pka.From = from.Format(kanbanAnalyticsDateFormat)
+	pka.To = to.Format(kanbanAnalyticsDateFormat)
+
+	return
+}
+
+// ReadOne returns the kanban analytics of a project
+// @Summary Get the kanban analytics of a project
+// @Description Returns how long tasks stayed in each bucket of the project on average and how many tasks were in each bucket at the end of every day of the analyzed period. This can be used to calculate cycle times and show a cumulative flow diagram.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Param from query string false "The first day of the analyzed period in the format YYYY-MM-DD. Defaults to 30 days ago."
+// @Param to query string false "The last day of the analyzed period in the format YYYY-MM-DD. Defaults to today."
+// @Success 200 {object} models.ProjectKanbanAnalytics "The kanban analytics of the project."
+// @Failure 400 {object} web.HTTPError "Invalid period provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/analytics/kanban [get]
+func (pka *ProjectKanbanAnalytics) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	from, to, err := pka.getPeriod()
+	if err != nil {
+		return err
+	}
+	// The analyzed period includes the whole last day
+	end := to.Add(kanbanAnalyticsDay)
+
+	buckets := []*Bucket{}
+	err = s.
+		Where("project_id = ?", pka.ProjectID).
+		OrderBy("position asc").
+		Find(&buckets)
+	if err != nil {
+		return err
+	}
+
+	transitions := []*TaskBucketTransition{}
+	err = s.
+		Where("project_id = ? AND created < ?", pka.ProjectID, end).
+		OrderBy("task_id asc, created asc, id asc").
+		Find(&transitions)
+	if err != nil {
+		return err
+	}
+
+	transitionsByTask := make(map[int64][]*TaskBucketTransition)
+	taskIDs := []int64{}
+	for _, tr := range transitions {
+		if _, has := transitionsByTask[tr.TaskID]; !has {
+			taskIDs = append(taskIDs, tr.TaskID)
+		}
+		transitionsByTask[tr.TaskID] = append(transitionsByTask[tr.TaskID], tr)
+	}
+
+	bucketAnalytics := make(map[int64]*BucketAnalytics, len(buckets))
+	timeInBucket := make(map[int64]time.Duration, len(buckets))
+	pka.Buckets = make([]*BucketAnalytics, 0, len(buckets))
+	for _, b := range buckets {
+		ba := &BucketAnalytics{
+			BucketID: b.ID,
+			Title:    b.Title,
+		}
+		bucketAnalytics[b.ID] = ba
+		pka.Buckets = append(pka.Buckets, ba)
+	}
+
+	// Time in bucket: Every transition ends the stay of the task in the bucket it was moved into with the previous transition.
+	for _, taskID := range taskIDs {
+		trs := transitionsByTask[taskID]
+		for i := 1; i < len(trs); i++ {
+			left := trs[i].Created
+			if left.Before(from) {
+				continue
+			}
+			ba, exists := bucketAnalytics[trs[i-1].ToBucketID]
+			if !exists {
+				continue
+			}
+			ba.TasksMovedOut++
+			timeInBucket[ba.BucketID] += left.Sub(trs[i-1].Created)
+		}
+	}
+
+	for id, ba := range bucketAnalytics {
+		if ba.TasksMovedOut > 0 {
+			ba.AverageTimeInBucket = int64((timeInBucket[id] / time.Duration(ba.TasksMovedOut)).Seconds())
+		}
+	}
+
+	// Cumulative flow: The last transition of each task before the end of a day determines its bucket on that day.
+	positions := make(map[int64]int, len(taskIDs))
+	pka.CumulativeFlow = []*CumulativeFlowEntry{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+		entry := &CumulativeFlowEntry{
+			Date:    day.Format(kanbanAnalyticsDateFormat),
+			Buckets: make(map[int64]int64, len(buckets)),
+		}
+		for _, b := range buckets {
+			entry.Buckets[b.ID] = 0
+		}
+
+		for _, taskID := range taskIDs {
+			trs := transitionsByTask[taskID]
+			pos := positions[taskID]
+			for pos < len(trs) && trs[pos].Created.Before(dayEnd) {
+				pos++
+			}
+			positions[taskID] = pos
+			if pos == 0 {
+				continue
+			}
+
+			if _, exists := entry.Buckets[trs[pos-1].ToBucketID]; exists {
+				entry.Buckets[trs[pos-1].ToBucketID]++
+			}
+		}
+
+		pka.CumulativeFlow = append(pka.CumulativeFlow, entry)
+	}
+
+	return nil
+}
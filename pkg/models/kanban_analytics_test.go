@@ -0,0 +1,182 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectKanbanAnalytics_ReadOne(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pka := &ProjectKanbanAnalytics{
+			ProjectID: 1,
+			From:      "2018-12-01",
+			To:        "2018-12-04",
+		}
+		err := pka.ReadOne(s, &user.User{ID: 1})
+		require.NoError(t, err)
+
+		assert.Len(t, pka.Buckets, 3)
+		assert.Equal(t, int64(1), pka.Buckets[0].BucketID)
+		assert.Equal(t, int64(1), pka.Buckets[0].TasksMovedOut)
+		assert.Equal(t, int64(24*60*60), pka.Buckets[0].AverageTimeInBucket)
+		assert.Equal(t, int64(2), pka.Buckets[1].BucketID)
+		assert.Equal(t, int64(1), pka.Buckets[1].TasksMovedOut)
+		assert.Equal(t, int64(2*24*60*60), pka.Buckets[1].AverageTimeInBucket)
+		assert.Equal(t, int64(0), pka.Buckets[2].TasksMovedOut)
+		assert.Equal(t, int64(0), pka.Buckets[2].AverageTimeInBucket)
+
+		require.Len(t, pka.CumulativeFlow, 4)
+		assert.Equal(t, "2018-12-01", pka.CumulativeFlow[0].Date)
+		assert.Equal(t, map[int64]int64{1: 2, 2: 0, 3: 0}, pka.CumulativeFlow[0].Buckets)
+		assert.Equal(t, map[int64]int64{1: 1, 2: 1, 3: 0}, pka.CumulativeFlow[1].Buckets)
+		assert.Equal(t, map[int64]int64{1: 1, 2: 1, 3: 0}, pka.CumulativeFlow[2].Buckets)
+		assert.Equal(t, "2018-12-04", pka.CumulativeFlow[3].Date)
+		assert.Equal(t, map[int64]int64{1: 1, 2: 0, 3: 1}, pka.CumulativeFlow[3].Buckets)
+	})
+	t.Run("only moves in the period", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pka := &ProjectKanbanAnalytics{
+			ProjectID: 1,
+			From:      "2018-12-03",
+			To:        "2018-12-04",
+		}
+		err := pka.ReadOne(s, &user.User{ID: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(0), pka.Buckets[0].TasksMovedOut)
+		assert.Equal(t, int64(1), pka.Buckets[1].TasksMovedOut)
+		assert.Len(t, pka.CumulativeFlow, 2)
+	})
+	t.Run("invalid date", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pka := &ProjectKanbanAnalytics{
+			ProjectID: 1,
+			From:      "yesterday",
+		}
+		err := pka.ReadOne(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidData(err))
+	})
+	t.Run("start after end", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pka := &ProjectKanbanAnalytics{
+			ProjectID: 1,
+			From:      "2018-12-04",
+			To:        "2018-12-01",
+		}
+		err := pka.ReadOne(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidData(err))
+	})
+}
+
+func TestRecordBucketTransition(t *testing.T) {
+	t.Run("moved between buckets", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:        1,
+			Title:     "test",
+			ProjectID: 1,
+			BucketID:  3,
+		}
+		err := task.Update(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_bucket_transitions", map[string]interface{}{
+			"task_id":        1,
+			"project_id":     1,
+			"from_bucket_id": 1,
+			"to_bucket_id":   3,
+		}, false)
+	})
+	t.Run("moved between projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:        1,
+			Title:     "test",
+			ProjectID: 2,
+		}
+		err := task.Update(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_bucket_transitions", map[string]interface{}{
+			"task_id":        1,
+			"project_id":     1,
+			"from_bucket_id": 1,
+			"to_bucket_id":   0,
+		}, false)
+		db.AssertExists(t, "task_bucket_transitions", map[string]interface{}{
+			"task_id":        1,
+			"project_id":     2,
+			"from_bucket_id": 0,
+			"to_bucket_id":   task.BucketID,
+		}, false)
+	})
+	t.Run("deleted task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1}
+		err := task.Delete(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		// The history of the task is kept, it is only removed from its bucket
+		db.AssertExists(t, "task_bucket_transitions", map[string]interface{}{
+			"id":      3,
+			"task_id": 1,
+		}, false)
+		db.AssertExists(t, "task_bucket_transitions", map[string]interface{}{
+			"task_id":        1,
+			"project_id":     1,
+			"from_bucket_id": 1,
+			"to_bucket_id":   0,
+		}, false)
+	})
+}
@@ -0,0 +1,207 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"math"
+	"strconv"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// BucketTaskMove moves multiple tasks from one bucket into another at once
+type BucketTaskMove struct {
+	// The project both buckets belong to.
+	ProjectID int64 `json:"-" param:"project"`
+	// The bucket to move the tasks out of.
+	BucketID int64 `json:"-" param:"bucket"`
+	// The bucket to move the tasks into.
+	TargetBucketID int64 `json:"target_bucket_id"`
+
+	// The ids of the tasks to move. All of them must be in the bucket from the url.
+	// If empty, all tasks of the bucket matching the filter are moved.
+	TaskIDs []int64 `json:"task_ids"`
+	// The filter query to match the tasks which should be moved. Only used if no task ids are provided. If it is empty as well, all tasks of the bucket are moved.
+	// Check out https://vikunja.io/docs/filters for a full explanation of the feature.
+	Filter string `json:"filter"`
+	// The time zone which should be used for date match (statements like "now" resolve to different actual times)
+	FilterTimezone string `json:"filter_timezone"`
+
+	// The tasks which were moved.
+	Tasks []*Task `json:"tasks"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanUpdate checks if the user can move tasks between the two buckets
+func (btm *BucketTaskMove) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	for _, id := range []int64{btm.BucketID, btm.TargetBucketID} {
+		bucket, err := getBucketByID(s, id)
+		if err != nil {
+			return false, err
+		}
+		if bucket.ProjectID != btm.ProjectID {
+			return false, ErrBucketDoesNotBelongToProject{BucketID: bucket.ID, ProjectID: btm.ProjectID}
+		}
+	}
+
+	project := &Project{ID: btm.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+func (btm *BucketTaskMove) getTasks(s *xorm.Session, a web.Auth) (tasks []*Task, err error) {
+	if len(btm.TaskIDs) > 0 {
+		err = s.
+			In("id", btm.TaskIDs).
+			OrderBy("kanban_position asc, id asc").
+			Find(&tasks)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(tasks) != len(btm.TaskIDs) {
+			found := make(map[int64]bool, len(tasks))
+			for _, t := range tasks {
+				found[t.ID] = true
+			}
+			for _, id := range btm.TaskIDs {
+				if !found[id] {
+					return nil, ErrTaskDoesNotExist{ID: id}
+				}
+			}
+		}
+
+		for _, t := range tasks {
+			if t.BucketID != btm.BucketID {
+				return nil, &ErrTaskIsNotInBucket{TaskID: t.ID, BucketID: btm.BucketID}
+			}
+		}
+
+		return tasks, nil
+	}
+
+	filter := "bucket_id = " + strconv.FormatInt(btm.BucketID, 10)
+	if btm.Filter != "" {
+		filter += " && (" + btm.Filter + ")"
+	}
+
+	opts, err := getTaskFilterOptsFromCollection(&TaskCollection{
+		Filter:         filter,
+		FilterTimezone: btm.FilterTimezone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	opts.projectIDs = []int64{btm.ProjectID}
+	opts.sortby = []*sortParam{
+		{
+			orderBy: orderAscending,
+			sortBy:  taskPropertyKanbanPosition,
+		},
+		{
+			orderBy: orderAscending,
+			sortBy:  taskPropertyID,
+		},
+	}
+
+	searcher := &dbTaskSearcher{
+		s: s,
+		a: a,
+	}
+	tasks, _, err = searcher.Search(opts)
+	return
+}
+
+// Update moves all tasks into the target bucket
+// @Summary Move multiple tasks into another bucket
+// @Description Moves a set of tasks or all tasks matching a filter from one bucket into another in one go. The moved tasks are put after all tasks already in the target bucket. Bucket limits, move permissions and bucket actions are respected the same way as when moving each task on its own.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Param bucketID path int true "The id of the bucket to move the tasks out of"
+// @Param move body models.BucketTaskMove true "The tasks to move and the bucket to move them to."
+// @Success 200 {object} models.BucketTaskMove "The moved tasks."
+// @Failure 400 {object} web.HTTPError "Invalid move object provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project or is not allowed to move tasks into the target bucket."
+// @Failure 404 {object} web.HTTPError "One of the buckets or tasks does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/buckets/{bucketID}/move [post]
+func (btm *BucketTaskMove) Update(s *xorm.Session, a web.Auth) (err error) {
+	tasks, err := btm.getTasks(s, a)
+	if err != nil {
+		return err
+	}
+
+	if len(tasks) == 0 {
+		return ErrBulkTasksNeedAtLeastOne{}
+	}
+
+	if btm.BucketID == btm.TargetBucketID {
+		btm.Tasks = tasks
+		return nil
+	}
+
+	project, err := GetProjectSimpleByID(s, btm.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	taskMap := make(map[int64]*Task, len(tasks))
+	for _, t := range tasks {
+		taskMap[t.ID] = t
+	}
+	// Task.Update replaces all fields of a task, so we need to get the full tasks first
+	err = addMoreInfoToTasks(s, taskMap, a)
+	if err != nil {
+		return err
+	}
+
+	// Put all moved tasks after the tasks already in the target bucket
+	last := &Task{}
+	_, err = s.
+		Where("bucket_id = ?", btm.TargetBucketID).
+		OrderBy("kanban_position desc").
+		Get(last)
+	if err != nil {
+		return err
+	}
+
+	for i, t := range tasks {
+		if btm.BucketID == project.DoneBucketID {
+			t.Done = false
+		}
+		t.BucketID = btm.TargetBucketID
+		t.KanbanPosition = last.KanbanPosition + float64(i+1)*math.Pow(2, 16)
+
+		err = t.Update(s, a)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = recalculateTaskKanbanPositions(s, btm.TargetBucketID)
+	if err != nil {
+		return err
+	}
+
+	btm.Tasks = tasks
+	return nil
+}
@@ -0,0 +1,178 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketTaskMove_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("task ids", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btm := &BucketTaskMove{
+			ProjectID:      1,
+			BucketID:       1,
+			TargetBucketID: 3,
+			TaskIDs:        []int64{1, 9},
+		}
+		err := btm.Update(s, u)
+		require.NoError(t, err)
+		assert.Len(t, btm.Tasks, 2)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		// Bucket 3 is the done bucket of project 1
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        1,
+			"bucket_id": 3,
+			"done":      true,
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        9,
+			"bucket_id": 3,
+			"done":      true,
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        10,
+			"bucket_id": 1,
+		}, false)
+	})
+	t.Run("filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btm := &BucketTaskMove{
+			ProjectID:      1,
+			BucketID:       1,
+			TargetBucketID: 3,
+			Filter:         "done = true",
+		}
+		err := btm.Update(s, u)
+		require.NoError(t, err)
+		assert.Len(t, btm.Tasks, 1)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        2,
+			"bucket_id": 3,
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        1,
+			"bucket_id": 1,
+		}, false)
+	})
+	t.Run("out of the done bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(6).Cols("done").Update(&Task{Done: true})
+		require.NoError(t, err)
+
+		btm := &BucketTaskMove{
+			ProjectID:      1,
+			BucketID:       3,
+			TargetBucketID: 1,
+			TaskIDs:        []int64{6},
+		}
+		err = btm.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        6,
+			"bucket_id": 1,
+			"done":      false,
+		}, false)
+	})
+	t.Run("task not in bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btm := &BucketTaskMove{
+			ProjectID:      1,
+			BucketID:       1,
+			TargetBucketID: 3,
+			TaskIDs:        []int64{1, 3},
+		}
+		err := btm.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskIsNotInBucket(err))
+	})
+	t.Run("bucket limit", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btm := &BucketTaskMove{
+			ProjectID:      1,
+			BucketID:       1,
+			TargetBucketID: 2,
+		}
+		err := btm.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBucketLimitExceeded(err))
+	})
+}
+
+func TestBucketTaskMove_CanUpdate(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btm := &BucketTaskMove{ProjectID: 1, BucketID: 1, TargetBucketID: 3}
+		can, err := btm.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("target bucket in a different project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btm := &BucketTaskMove{ProjectID: 1, BucketID: 1, TargetBucketID: 4}
+		_, err := btm.CanUpdate(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrBucketDoesNotBelongToProject(err))
+	})
+	t.Run("no write access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btm := &BucketTaskMove{ProjectID: 1, BucketID: 1, TargetBucketID: 3}
+		can, err := btm.CanUpdate(s, &user.User{ID: 13})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
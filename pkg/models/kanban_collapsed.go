@@ -0,0 +1,140 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// BucketCollapsedState holds whether a user collapsed a bucket
+type BucketCollapsedState struct {
+	// The unique, numeric id of this collapsed state.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	// The bucket this state belongs to.
+	BucketID int64 `xorm:"bigint not null unique(bucket_user)" json:"bucket_id" param:"bucket"`
+	// The user this state belongs to.
+	UserID int64 `xorm:"bigint not null unique(bucket_user)" json:"-"`
+	// The project the bucket belongs to. Only used to check the bucket belongs to the project from the url.
+	ProjectID int64 `xorm:"-" json:"-" param:"project"`
+
+	// Whether the bucket is collapsed for the current user.
+	IsCollapsed bool `xorm:"-" json:"is_collapsed"`
+
+	// A timestamp when this bucket was collapsed. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"-"`
+
+	web.Rights   `xorm:"-" json:"-"`
+	web.CRUDable `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for bucket collapsed states
+func (*BucketCollapsedState) TableName() string {
+	return "bucket_collapsed_states"
+}
+
+// Update sets whether a bucket is collapsed for the current user
+// @Summary Collapse or expand a bucket
+// @Description Saves whether a kanban bucket is collapsed for the current user. The state is returned with all buckets of a project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Param bucketID path int true "Bucket Id"
+// @Param state body models.BucketCollapsedState true "The collapsed state."
+// @Success 200 {object} models.BucketCollapsedState "The collapsed state."
+// @Failure 400 {object} web.HTTPError "Invalid collapsed state provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The bucket does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/buckets/{bucketID}/collapsed [post]
+func (bcs *BucketCollapsedState) Update(s *xorm.Session, a web.Auth) (err error) {
+	bcs.UserID = a.GetID()
+
+	exists, err := s.
+		Where("bucket_id = ? AND user_id = ?", bcs.BucketID, bcs.UserID).
+		Exist(&BucketCollapsedState{})
+	if err != nil {
+		return err
+	}
+
+	if bcs.IsCollapsed && !exists {
+		_, err = s.Insert(bcs)
+		return err
+	}
+
+	if !bcs.IsCollapsed && exists {
+		_, err = s.
+			Where("bucket_id = ? AND user_id = ?", bcs.BucketID, bcs.UserID).
+			Delete(&BucketCollapsedState{})
+	}
+	return err
+}
+
+// CanUpdate checks if a user can collapse a bucket. Everyone who can see a bucket can collapse it for themselves.
+func (bcs *BucketCollapsedState) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	// Link shares don't have their own state
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	bucket, err := getBucketByID(s, bcs.BucketID)
+	if err != nil {
+		return false, err
+	}
+
+	if bcs.ProjectID != 0 && bucket.ProjectID != bcs.ProjectID {
+		return false, ErrBucketDoesNotBelongToProject{BucketID: bucket.ID, ProjectID: bcs.ProjectID}
+	}
+
+	project := &Project{ID: bucket.ProjectID}
+	can, _, err := project.CanRead(s, a)
+	return can, err
+}
+
+// setCollapsedStates sets the collapsed flag on all buckets the current user collapsed
+func setCollapsedStates(s *xorm.Session, a web.Auth, bucketMap map[int64]*Bucket) error {
+	if _, is := a.(*LinkSharing); is {
+		return nil
+	}
+
+	bucketIDs := make([]int64, 0, len(bucketMap))
+	for id := range bucketMap {
+		bucketIDs = append(bucketIDs, id)
+	}
+
+	states := []*BucketCollapsedState{}
+	err := s.
+		Where(builder.And(
+			builder.Eq{"user_id": a.GetID()},
+			builder.In("bucket_id", bucketIDs),
+		)).
+		Find(&states)
+	if err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		bucketMap[state.BucketID].IsCollapsed = true
+	}
+
+	return nil
+}
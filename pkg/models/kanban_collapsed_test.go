@@ -0,0 +1,124 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestBucketCollapsedState_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("collapse", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bcs := &BucketCollapsedState{BucketID: 1, IsCollapsed: true}
+		err := bcs.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "bucket_collapsed_states", map[string]interface{}{
+			"bucket_id": 1,
+			"user_id":   1,
+		}, false)
+	})
+	t.Run("collapse twice", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bcs := &BucketCollapsedState{BucketID: 2, IsCollapsed: true}
+		err := bcs.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertCount(t, "bucket_collapsed_states", builder.Eq{
+			"bucket_id": 2,
+			"user_id":   1,
+		}, 1)
+	})
+	t.Run("expand", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bcs := &BucketCollapsedState{BucketID: 2, IsCollapsed: false}
+		err := bcs.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "bucket_collapsed_states", map[string]interface{}{
+			"bucket_id": 2,
+			"user_id":   1,
+		})
+	})
+}
+
+func TestBucketCollapsedState_CanUpdate(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bcs := &BucketCollapsedState{BucketID: 1, ProjectID: 1}
+		can, err := bcs.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("no access to the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bcs := &BucketCollapsedState{BucketID: 1, ProjectID: 1}
+		can, err := bcs.CanUpdate(s, &user.User{ID: 13})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("bucket of a different project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bcs := &BucketCollapsedState{BucketID: 4, ProjectID: 1}
+		_, err := bcs.CanUpdate(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrBucketDoesNotBelongToProject(err))
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bcs := &BucketCollapsedState{BucketID: 1, ProjectID: 1}
+		can, err := bcs.CanUpdate(s, &LinkSharing{ID: 1, ProjectID: 1, Right: RightWrite})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
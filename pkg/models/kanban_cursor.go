@@ -0,0 +1,122 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"xorm.io/builder"
+)
+
+// bucketTaskCursor holds all values of the last task of a page which are needed to find the tasks after it in the
+// database, regardless of the sort mode of the bucket.
+type bucketTaskCursor struct {
+	BucketID       int64     `json:"b"`
+	TaskID         int64     `json:"t"`
+	KanbanPosition float64   `json:"k"`
+	DueDate        time.Time `json:"d"`
+	Priority       int64     `json:"p"`
+	Created        time.Time `json:"c"`
+	IsPinned       bool      `json:"i"`
+	PinnedPosition float64   `json:"pp"`
+}
+
+func newBucketTaskCursor(bucketID int64, t *Task) string {
+	c, _ := json.Marshal(&bucketTaskCursor{
+		BucketID:       bucketID,
+		TaskID:         t.ID,
+		KanbanPosition: t.KanbanPosition,
+		DueDate:        t.DueDate,
+		Priority:       t.Priority,
+		Created:        t.Created,
+		IsPinned:       t.IsPinned,
+		PinnedPosition: t.PinnedPosition,
+	})
+	return base64.RawURLEncoding.EncodeToString(c)
+}
+
+func parseBucketTaskCursor(cursor string) (*bucketTaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, &ErrInvalidBucketTaskCursor{}
+	}
+
+	c := &bucketTaskCursor{}
+	err = json.Unmarshal(raw, c)
+	if err != nil || c.BucketID == 0 || c.TaskID == 0 {
+		return nil, &ErrInvalidBucketTaskCursor{}
+	}
+
+	return c, nil
+}
+
+// getAfterCond returns the condition which matches all tasks sorted after the task of the cursor in a bucket with
+// the sort mode. It follows the order of getTaskOrderByForBuckets.
+func (c *bucketTaskCursor) getAfterCond(sortMode BucketSortMode) builder.Cond {
+	// Tasks with the same value are sorted by their kanban position and id
+	after := builder.Or(
+		builder.Expr("COALESCE(kanban_position, 0) > ?", c.KanbanPosition),
+		builder.And(
+			builder.Expr("COALESCE(kanban_position, 0) = ?", c.KanbanPosition),
+			builder.Gt{"id": c.TaskID},
+		),
+	)
+
+	switch sortMode {
+	case BucketSortModeDueDate:
+		// Tasks without a due date come last
+		if c.DueDate.IsZero() {
+			after = builder.And(builder.IsNull{"due_date"}, after)
+			break
+		}
+		after = builder.Or(
+			builder.Gt{"due_date": c.DueDate},
+			builder.IsNull{"due_date"},
+			builder.And(builder.Eq{"due_date": c.DueDate}, after),
+		)
+	case BucketSortModePriority:
+		after = builder.Or(
+			builder.Expr("COALESCE(priority, 0) < ?", c.Priority),
+			builder.And(builder.Expr("COALESCE(priority, 0) = ?", c.Priority), after),
+		)
+	case BucketSortModeCreated:
+		after = builder.Or(
+			builder.Lt{"created": c.Created},
+			builder.And(builder.Eq{"created": c.Created}, after),
+		)
+	case BucketSortModeManual:
+		// Only sorted by kanban position
+	}
+
+	// Pinned tasks always come first
+	if !c.IsPinned {
+		return builder.And(builder.Eq{"is_pinned": false}, after)
+	}
+
+	return builder.Or(
+		builder.Eq{"is_pinned": false},
+		builder.And(
+			builder.Eq{"is_pinned": true},
+			builder.Or(
+				builder.Expr("COALESCE(pinned_position, 0) > ?", c.PinnedPosition),
+				builder.And(builder.Expr("COALESCE(pinned_position, 0) = ?", c.PinnedPosition), after),
+			),
+		),
+	)
+}
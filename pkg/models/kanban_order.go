@@ -0,0 +1,98 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// BucketOrder updates the positions of all buckets of a project at once
+type BucketOrder struct {
+	// The project the buckets belong to.
+	ProjectID int64 `json:"-" param:"project"`
+	// The ids of all buckets of the project in their new order.
+	BucketIDs []int64 `json:"bucket_ids"`
+
+	// The buckets with their new positions.
+	Buckets []*Bucket `json:"buckets"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanUpdate checks if the user can reorder the buckets of a project
+func (bo *BucketOrder) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	p := &Project{ID: bo.ProjectID}
+	return p.CanWrite(s, a)
+}
+
+// Update sets the positions of all buckets according to their order
+// @Summary Reorder all buckets of a project
+// @Description Updates the positions of all kanban buckets of a project at once. The list must contain the ids of all buckets of the project exactly once.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Param order body models.BucketOrder true "The ids of all buckets in their new order."
+// @Success 200 {object} models.BucketOrder "The buckets with their new positions."
+// @Failure 400 {object} web.HTTPError "Invalid bucket order provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/buckets/order [post]
+func (bo *BucketOrder) Update(s *xorm.Session, _ web.Auth) (err error) {
+	buckets := []*Bucket{}
+	err = s.
+		Where("project_id = ?", bo.ProjectID).
+		Find(&buckets)
+	if err != nil {
+		return err
+	}
+
+	bucketMap := make(map[int64]*Bucket, len(buckets))
+	for _, b := range buckets {
+		bucketMap[b.ID] = b
+	}
+
+	if len(bo.BucketIDs) != len(buckets) {
+		return ErrInvalidData{Message: "The bucket order must contain all buckets of the project."}
+	}
+
+	bo.Buckets = make([]*Bucket, 0, len(buckets))
+	for i, id := range bo.BucketIDs {
+		b, exists := bucketMap[id]
+		if !exists {
+			return ErrBucketDoesNotBelongToProject{BucketID: id, ProjectID: bo.ProjectID}
+		}
+		// Removing the bucket from the map makes sure each bucket is only used once
+		delete(bucketMap, id)
+
+		b.Position = calculateDefaultPosition(int64(i+1), 0)
+		_, err = s.
+			Where("id = ?", b.ID).
+			Cols("position").
+			Update(b)
+		if err != nil {
+			return err
+		}
+
+		bo.Buckets = append(bo.Buckets, b)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketOrder_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bo := &BucketOrder{
+			ProjectID: 1,
+			BucketIDs: []int64{3, 1, 2},
+		}
+		err := bo.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		b := &Bucket{ProjectID: 1}
+		bucketsInterface, _, _, err := b.ReadAll(s, u, "", 0, 0)
+		require.NoError(t, err)
+		buckets := bucketsInterface.([]*Bucket)
+		require.Len(t, buckets, 3)
+		assert.Equal(t, int64(3), buckets[0].ID)
+		assert.Equal(t, int64(1), buckets[1].ID)
+		assert.Equal(t, int64(2), buckets[2].ID)
+	})
+	t.Run("missing bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bo := &BucketOrder{
+			ProjectID: 1,
+			BucketIDs: []int64{3, 1},
+		}
+		err := bo.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidData(err))
+	})
+	t.Run("duplicate bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bo := &BucketOrder{
+			ProjectID: 1,
+			BucketIDs: []int64{3, 1, 1},
+		}
+		err := bo.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBucketDoesNotBelongToProject(err))
+	})
+	t.Run("bucket of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bo := &BucketOrder{
+			ProjectID: 1,
+			BucketIDs: []int64{3, 1, 4},
+		}
+		err := bo.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBucketDoesNotBelongToProject(err))
+	})
+}
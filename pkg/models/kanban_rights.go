@@ -17,7 +17,9 @@
 package models
 
 import (
+	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web"
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -46,3 +48,78 @@ func (b *Bucket) canDoBucket(s *xorm.Session, a web.Auth) (bool, error) {
 	l := &Project{ID: bb.ProjectID}
 	return l.CanWrite(s, a)
 }
+
+func (b *Bucket) hasMovePermissions() bool {
+	return len(b.MoveAllowedUserIDs) > 0 || len(b.MoveAllowedTeamIDs) > 0
+}
+
+// validateMovePermissions checks if the user is allowed to change who can move tasks into the bucket
+// and all users and teams exist.
+func (b *Bucket) validateMovePermissions(s *xorm.Session, a web.Auth) error {
+	project := &Project{ID: b.ProjectID}
+	isAdmin, err := project.IsAdmin(s, a)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return ErrGenericForbidden{}
+	}
+
+	for _, id := range b.MoveAllowedUserIDs {
+		if _, err := user.GetUserByID(s, id); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range b.MoveAllowedTeamIDs {
+		if _, err := GetTeamByID(s, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkCanMoveTaskInto checks if the user is allowed to move a task into this bucket
+func (b *Bucket) checkCanMoveTaskInto(s *xorm.Session, a web.Auth) error {
+	if !b.hasMovePermissions() {
+		return nil
+	}
+
+	project := &Project{ID: b.ProjectID}
+	isAdmin, err := project.IsAdmin(s, a)
+	if err != nil {
+		return err
+	}
+	if isAdmin {
+		return nil
+	}
+
+	// Link shares can't be added to the list of allowed users or teams
+	if _, is := a.(*LinkSharing); is {
+		return &ErrNotAllowedToMoveTaskIntoBucket{BucketID: b.ID}
+	}
+
+	for _, id := range b.MoveAllowedUserIDs {
+		if id == a.GetID() {
+			return nil
+		}
+	}
+
+	if len(b.MoveAllowedTeamIDs) > 0 {
+		isMember, err := s.
+			Where(builder.And(
+				builder.Eq{"user_id": a.GetID()},
+				builder.In("team_id", b.MoveAllowedTeamIDs),
+			)).
+			Exist(&TeamMember{})
+		if err != nil {
+			return err
+		}
+		if isMember {
+			return nil
+		}
+	}
+
+	return &ErrNotAllowedToMoveTaskIntoBucket{BucketID: b.ID, UserID: a.GetID()}
+}
@@ -0,0 +1,198 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strconv"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// SavedFilterBucketConfigurationMode defines how the kanban board of a saved filter is divided into buckets
+type SavedFilterBucketConfigurationMode int
+
+const (
+	// SavedFilterBucketConfigurationModeNone means the saved filter has no kanban board
+	SavedFilterBucketConfigurationModeNone SavedFilterBucketConfigurationMode = iota
+	// SavedFilterBucketConfigurationModeManual uses the buckets defined in the bucket configuration of the filter
+	SavedFilterBucketConfigurationModeManual
+	// SavedFilterBucketConfigurationModeDone creates one bucket for undone and one for done tasks
+	SavedFilterBucketConfigurationModeDone
+	// SavedFilterBucketConfigurationModePriority creates one bucket for every priority
+	SavedFilterBucketConfigurationModePriority
+	// SavedFilterBucketConfigurationModeLabel creates one bucket for every label the user has access to
+	SavedFilterBucketConfigurationModeLabel
+)
+
+// SavedFilterBucketConfiguration is a single bucket on the kanban board of a saved filter
+type SavedFilterBucketConfiguration struct {
+	// The title of the bucket.
+	Title string `json:"title"`
+	// The filter query which defines which tasks are shown in this bucket. It is combined with the filter of the saved filter.
+	Filter string `json:"filter"`
+}
+
+var priorityBucketTitles = []string{"Do now", "Urgent", "High", "Medium", "Low", "Unset"}
+
+func (sf *SavedFilter) validateBucketConfiguration() error {
+	switch sf.BucketConfigurationMode {
+	case SavedFilterBucketConfigurationModeNone,
+		SavedFilterBucketConfigurationModeDone,
+		SavedFilterBucketConfigurationModePriority,
+		SavedFilterBucketConfigurationModeLabel:
+		return nil
+	case SavedFilterBucketConfigurationModeManual:
+		if len(sf.BucketConfiguration) == 0 {
+			return ErrInvalidSavedFilterBucketConfiguration{SavedFilterID: sf.ID}
+		}
+		for _, bc := range sf.BucketConfiguration {
+			if bc.Title == "" || bc.Filter == "" {
+				return ErrInvalidSavedFilterBucketConfiguration{SavedFilterID: sf.ID}
+			}
+			if _, err := getTaskFiltersFromFilterString(bc.Filter, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return ErrInvalidSavedFilterBucketConfiguration{SavedFilterID: sf.ID}
+	}
+}
+
+// getBucketConfiguration returns the buckets of the kanban board of a saved filter, depending on its bucket configuration mode.
+func (sf *SavedFilter) getBucketConfiguration(s *xorm.Session, a web.Auth) (config []*SavedFilterBucketConfiguration, err error) {
+	switch sf.BucketConfigurationMode {
+	case SavedFilterBucketConfigurationModeManual:
+		return sf.BucketConfiguration, nil
+	case SavedFilterBucketConfigurationModeDone:
+		return []*SavedFilterBucketConfiguration{
+			{Title: "To do", Filter: "done = false"},
+			{Title: "Done", Filter: "done = true"},
+		}, nil
+	case SavedFilterBucketConfigurationModePriority:
+		config = make([]*SavedFilterBucketConfiguration, 0, len(priorityBucketTitles))
+		for i, title := range priorityBucketTitles {
+			priority := len(priorityBucketTitles) - 1 - i
+			config = append(config, &SavedFilterBucketConfiguration{
+				Title:  title,
+				Filter: "priority = " + strconv.Itoa(priority),
+			})
+		}
+		return config, nil
+	case SavedFilterBucketConfigurationModeLabel:
+		u, err := user.GetUserByID(s, a.GetID())
+		if err != nil {
+			return nil, err
+		}
+		u.APITokenProjectIDs = getAPITokenProjectIDs(a)
+		labels, _, _, err := GetLabelsByTaskIDs(s, &LabelByTaskIDsOptions{
+			User:                u,
+			GetForUser:          u.ID,
+			GetUnusedLabels:     true,
+			GroupByLabelIDsOnly: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		config = make([]*SavedFilterBucketConfiguration, 0, len(labels))
+		for _, l := range labels {
+			config = append(config, &SavedFilterBucketConfiguration{
+				Title:  l.Title,
+				Filter: "labels = " + strconv.FormatInt(l.ID, 10),
+			})
+		}
+		return config, nil
+	case SavedFilterBucketConfigurationModeNone:
+	}
+
+	return nil, ErrSavedFilterHasNoKanbanBoard{SavedFilterID: sf.ID}
+}
+
+// readAllForSavedFilter builds the kanban board of a saved filter. The buckets are not stored in the database and
+// get a negative id from their position on the board.
+func (b *Bucket) readAllForSavedFilter(s *xorm.Session, a web.Auth, filterID int64, search string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	sf := &SavedFilter{ID: filterID}
+	can, _, err := sf.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	// Saved filter buckets don't exist in the database and can't be paginated with a cursor
+	if b.Cursor != "" {
+		return nil, 0, 0, &ErrInvalidBucketTaskCursor{}
+	}
+
+	bucketConfig, err := sf.getBucketConfiguration(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if sf.Filters.FilterTimezone == "" {
+		u, err := user.GetUserByID(s, a.GetID())
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		sf.Filters.FilterTimezone = u.Timezone
+	}
+
+	buckets := make([]*Bucket, 0, len(bucketConfig))
+	for i, bc := range bucketConfig {
+		filter := bc.Filter
+		if sf.Filters.Filter != "" {
+			filter = "(" + sf.Filters.Filter + ") && (" + bc.Filter + ")"
+		}
+		if b.Filter != "" {
+			filter = "(" + filter + ") && (" + b.Filter + ")"
+		}
+
+		tc := *sf.Filters
+		tc.ProjectID = 0
+		tc.Filter = filter
+
+		ts, _, total, err := tc.ReadAll(s, a, search, page, perPage)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		openEstimate := sumOpenEstimates(ts.([]*Task))
+		if int64(len(ts.([]*Task))) < total {
+			// The estimates need to include the tasks of all pages
+			allTasks, _, _, err := tc.ReadAll(s, a, search, -1, 0)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			openEstimate = sumOpenEstimates(allTasks.([]*Task))
+		}
+
+		buckets = append(buckets, &Bucket{
+			ID:           int64(i+1) * -1,
+			Title:        bc.Title,
+			ProjectID:    b.ProjectID,
+			Position:     float64(i + 1),
+			Tasks:        ts.([]*Task),
+			Count:        total,
+			OpenEstimate: openEstimate,
+		})
+	}
+
+	return buckets, len(buckets), int64(len(buckets)), nil
+}
@@ -18,10 +18,12 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"xorm.io/xorm"
 
 	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/user"
 
 	"github.com/stretchr/testify/assert"
@@ -60,6 +62,11 @@ func TestBucket_ReadAll(t *testing.T) {
 		assert.Equal(t, int64(2), buckets[1].ID)
 		assert.Equal(t, int64(3), buckets[2].ID)
 
+		// Only the buckets collapsed by the current user should be collapsed
+		assert.False(t, buckets[0].IsCollapsed)
+		assert.True(t, buckets[1].IsCollapsed)
+		assert.False(t, buckets[2].IsCollapsed)
+
 		// Kinda assert all tasks are in the right buckets
 		assert.Equal(t, int64(1), buckets[0].Tasks[0].BucketID)
 		assert.Equal(t, int64(1), buckets[0].Tasks[1].BucketID)
@@ -72,6 +79,244 @@ func TestBucket_ReadAll(t *testing.T) {
 		assert.Equal(t, int64(3), buckets[2].Tasks[1].BucketID)
 		assert.Equal(t, int64(3), buckets[2].Tasks[2].BucketID)
 	})
+	t.Run("paginated", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{ProjectID: 1}
+		bucketsInterface, _, _, err := b.ReadAll(s, &user.User{ID: 1}, "", 2, 5)
+		require.NoError(t, err)
+
+		buckets := bucketsInterface.([]*Bucket)
+		require.Len(t, buckets, 3)
+		assert.Len(t, buckets[0].Tasks, 5)
+		assert.Equal(t, int64(12), buckets[0].Count)
+		assert.Empty(t, buckets[1].Tasks)
+		assert.Equal(t, int64(3), buckets[1].Count)
+	})
+	t.Run("paginated across several buckets", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(2).Cols("sort_mode").Update(&Bucket{SortMode: BucketSortModeDueDate})
+		require.NoError(t, err)
+
+		getTaskIDs := func(page, perPage int) (buckets []*Bucket, ids [][]int64) {
+			b := &Bucket{ProjectID: 1}
+			bucketsInterface, _, _, err := b.ReadAll(s, &user.User{ID: 1}, "", page, perPage)
+			require.NoError(t, err)
+
+			buckets = bucketsInterface.([]*Bucket)
+			require.Len(t, buckets, 3)
+			for _, bucket := range buckets {
+				bucketIDs := []int64{}
+				for _, task := range bucket.Tasks {
+					bucketIDs = append(bucketIDs, task.ID)
+				}
+				ids = append(ids, bucketIDs)
+			}
+			return
+		}
+
+		_, all := getTaskIDs(0, 0)
+		firstPage, firstIDs := getTaskIDs(1, 2)
+		secondPage, secondIDs := getTaskIDs(2, 2)
+
+		for i, bucket := range firstPage {
+			assert.Equal(t, all[i][:2], firstIDs[i], "bucket %d", bucket.ID)
+			assert.Equal(t, all[i][2:min(4, len(all[i]))], secondIDs[i], "bucket %d", bucket.ID)
+			assert.NotEmpty(t, bucket.NextCursor, "bucket %d", bucket.ID)
+		}
+		assert.Equal(t, int64(5), firstIDs[1][0]) // Bucket 2 is sorted by due date
+		assert.Equal(t, []int64{12, 3, 3}, []int64{firstPage[0].Count, firstPage[1].Count, firstPage[2].Count})
+		assert.Equal(t, []int64{12, 3, 3}, []int64{secondPage[0].Count, secondPage[1].Count, secondPage[2].Count})
+		assert.NotEmpty(t, secondPage[0].NextCursor)
+		assert.Empty(t, secondPage[1].NextCursor)
+		assert.Empty(t, secondPage[2].NextCursor)
+	})
+	t.Run("paginated with cursor", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1}
+		b := &Bucket{ProjectID: 1}
+		bucketsInterface, _, _, err := b.ReadAll(s, u, "", 1, 5)
+		require.NoError(t, err)
+
+		buckets := bucketsInterface.([]*Bucket)
+		require.Len(t, buckets, 3)
+		firstPage := buckets[0].Tasks
+		require.Len(t, firstPage, 5)
+		require.NotEmpty(t, buckets[0].NextCursor)
+		assert.Empty(t, buckets[1].NextCursor)
+
+		// Moving a task of the first page out of the bucket must not shift the next page
+		_, err = s.ID(firstPage[0].ID).Cols("bucket_id").Update(&Task{BucketID: 3})
+		require.NoError(t, err)
+
+		b = &Bucket{ProjectID: 1, Cursor: buckets[0].NextCursor}
+		bucketsInterface, _, _, err = b.ReadAll(s, u, "", 1, 5)
+		require.NoError(t, err)
+
+		buckets = bucketsInterface.([]*Bucket)
+		require.Len(t, buckets, 1)
+		assert.Equal(t, int64(1), buckets[0].ID)
+		secondPage := buckets[0].Tasks
+		require.Len(t, secondPage, 5)
+		for _, task := range firstPage {
+			for _, other := range secondPage {
+				assert.NotEqual(t, task.ID, other.ID)
+			}
+		}
+		require.NotEmpty(t, buckets[0].NextCursor)
+
+		b = &Bucket{ProjectID: 1, Cursor: buckets[0].NextCursor}
+		bucketsInterface, _, _, err = b.ReadAll(s, u, "", 1, 5)
+		require.NoError(t, err)
+
+		buckets = bucketsInterface.([]*Bucket)
+		require.Len(t, buckets, 1)
+		assert.Len(t, buckets[0].Tasks, 2)
+		assert.Empty(t, buckets[0].NextCursor)
+	})
+	t.Run("paginated with cursor in all sort modes", func(t *testing.T) {
+		for _, sortMode := range []BucketSortMode{BucketSortModeManual, BucketSortModeDueDate, BucketSortModePriority, BucketSortModeCreated} {
+			db.LoadAndAssertFixtures(t)
+			s := db.NewSession()
+
+			_, err := s.ID(1).Cols("sort_mode").Update(&Bucket{SortMode: sortMode})
+			require.NoError(t, err)
+			_, err = s.ID(12).Cols("is_pinned", "pinned_position").Update(&Task{IsPinned: true, PinnedPosition: 2})
+			require.NoError(t, err)
+			_, err = s.ID(27).Cols("is_pinned", "pinned_position").Update(&Task{IsPinned: true, PinnedPosition: 1})
+			require.NoError(t, err)
+			for id, task := range map[int64]*Task{
+				9:  {DueDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Priority: 3, Created: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), KanbanPosition: 3},
+				10: {DueDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Priority: 3, Created: time.Date(2018, 12, 1, 1, 12, 4, 0, time.UTC), KanbanPosition: 1},
+				11: {Priority: 5, Created: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), KanbanPosition: 2},
+				28: {DueDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Priority: 1, Created: time.Date(2018, 12, 1, 1, 12, 4, 0, time.UTC), KanbanPosition: 1},
+			} {
+				_, err = s.ID(id).Cols("due_date", "priority", "kanban_position").Update(task)
+				require.NoError(t, err)
+				// The created column is never updated through the struct
+				_, err = s.Table("tasks").Where("id = ?", id).Update(map[string]interface{}{"created": task.Created})
+				require.NoError(t, err)
+			}
+
+			u := &user.User{ID: 1}
+			b := &Bucket{ProjectID: 1}
+			bucketsInterface, _, _, err := b.ReadAll(s, u, "", 0, 0)
+			require.NoError(t, err)
+			expected := []int64{}
+			for _, task := range bucketsInterface.([]*Bucket)[0].Tasks {
+				expected = append(expected, task.ID)
+			}
+			require.Len(t, expected, 12)
+			assert.Equal(t, []int64{27, 12}, expected[:2])
+
+			bucketsInterface, _, _, err = b.ReadAll(s, u, "", 1, 5)
+			require.NoError(t, err)
+			bucket := bucketsInterface.([]*Bucket)[0]
+			actual := []int64{}
+			for {
+				for _, task := range bucket.Tasks {
+					actual = append(actual, task.ID)
+				}
+				if bucket.NextCursor == "" {
+					break
+				}
+
+				b = &Bucket{ProjectID: 1, Cursor: bucket.NextCursor}
+				bucketsInterface, _, _, err = b.ReadAll(s, u, "", 1, 5)
+				require.NoError(t, err)
+				require.Len(t, bucketsInterface.([]*Bucket), 1)
+				bucket = bucketsInterface.([]*Bucket)[0]
+			}
+			assert.Equal(t, expected, actual, "sort mode %d", sortMode)
+
+			s.Close()
+		}
+	})
+	t.Run("invalid cursor", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{ProjectID: 1, Cursor: "invalid"}
+		_, _, _, err := b.ReadAll(s, &user.User{ID: 1}, "", 1, 5)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidBucketTaskCursor(err))
+	})
+	t.Run("cursor of a bucket in another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{ProjectID: 1, Cursor: newBucketTaskCursor(4, &Task{ID: 1})}
+		_, _, _, err := b.ReadAll(s, &user.User{ID: 1}, "", 1, 5)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidBucketTaskCursor(err))
+	})
+	t.Run("sorted by due date", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(2).Cols("sort_mode").Update(&Bucket{SortMode: BucketSortModeDueDate})
+		require.NoError(t, err)
+
+		testuser := &user.User{ID: 1}
+		b := &Bucket{ProjectID: 1}
+		bucketsInterface, _, _, err := b.ReadAll(s, testuser, "", 0, 0)
+		require.NoError(t, err)
+
+		buckets := bucketsInterface.([]*Bucket)
+		require.Len(t, buckets[1].Tasks, 3)
+		assert.Equal(t, int64(5), buckets[1].Tasks[0].ID) // The only one with a due date
+	})
+	t.Run("saved filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("bucket_configuration_mode", "bucket_configuration").Update(&SavedFilter{
+			BucketConfigurationMode: SavedFilterBucketConfigurationModeManual,
+			BucketConfiguration: []*SavedFilterBucketConfiguration{
+				{Title: "Undone", Filter: "done = false"},
+				{Title: "Done", Filter: "done = true"},
+			},
+		})
+		require.NoError(t, err)
+
+		b := &Bucket{ProjectID: getProjectIDFromSavedFilterID(1)}
+		bucketsInterface, _, _, err := b.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+		require.NoError(t, err)
+
+		buckets := bucketsInterface.([]*Bucket)
+		require.Len(t, buckets, 2)
+		assert.Equal(t, int64(-1), buckets[0].ID)
+		assert.Equal(t, "Undone", buckets[0].Title)
+		require.NotEmpty(t, buckets[0].Tasks)
+		for _, task := range buckets[0].Tasks {
+			assert.False(t, task.Done)
+		}
+		for _, task := range buckets[1].Tasks {
+			assert.True(t, task.Done)
+		}
+	})
+	t.Run("saved filter without kanban board", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{ProjectID: getProjectIDFromSavedFilterID(1)}
+		_, _, _, err := b.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+		require.Error(t, err)
+		assert.True(t, IsErrSavedFilterHasNoKanbanBoard(err))
+	})
 	t.Run("filtered", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
@@ -116,6 +361,48 @@ func TestBucket_ReadAll(t *testing.T) {
 		assert.Equal(t, int64(4), buckets[1].Tasks[1].ID)
 		assert.Equal(t, int64(5), buckets[1].Tasks[2].ID)
 	})
+	t.Run("filtered by several buckets", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		testuser := &user.User{ID: 1}
+		b := &Bucket{
+			ProjectID: 1,
+			TaskCollection: TaskCollection{
+				Filter: "bucket_id in [2, 3]",
+			},
+		}
+		bucketsInterface, _, _, err := b.ReadAll(s, testuser, "", -1, 0)
+		require.NoError(t, err)
+
+		buckets := bucketsInterface.([]*Bucket)
+		assert.Len(t, buckets, 3)
+		assert.Empty(t, buckets[0].Tasks)
+		assert.Len(t, buckets[1].Tasks, 3)
+		assert.Len(t, buckets[2].Tasks, 3)
+	})
+	t.Run("filtered by a bucket of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		testuser := &user.User{ID: 1}
+		b := &Bucket{
+			ProjectID: 1,
+			TaskCollection: TaskCollection{
+				Filter: "bucket_id = 4",
+			},
+		}
+		bucketsInterface, _, _, err := b.ReadAll(s, testuser, "", -1, 0)
+		require.NoError(t, err)
+
+		buckets := bucketsInterface.([]*Bucket)
+		assert.Len(t, buckets, 3)
+		for _, bucket := range buckets {
+			assert.Empty(t, bucket.Tasks)
+		}
+	})
 	t.Run("accessed by link share", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
@@ -150,6 +437,31 @@ func TestBucket_ReadAll(t *testing.T) {
 	})
 }
 
+func TestBucket_Create(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			Title:     "New Bucket",
+			ProjectID: 1,
+		}
+		err := b.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "buckets", map[string]interface{}{
+			"id":            b.ID,
+			"title":         "New Bucket",
+			"project_id":    1,
+			"created_by_id": 1,
+		}, false)
+		events.AssertDispatched(t, &BucketCreatedEvent{})
+	})
+}
+
 func TestBucket_Delete(t *testing.T) {
 	user := &user.User{ID: 1}
 
@@ -176,6 +488,7 @@ func TestBucket_Delete(t *testing.T) {
 			"id":         2,
 			"project_id": 1,
 		})
+		events.AssertDispatched(t, &BucketDeletedEvent{})
 	})
 	t.Run("last bucket in project", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
@@ -230,6 +543,7 @@ func TestBucket_Update(t *testing.T) {
 			"title": b.Title,
 			"limit": b.Limit,
 		}, false)
+		events.AssertDispatched(t, &BucketUpdatedEvent{})
 	}
 
 	t.Run("normal", func(t *testing.T) {
@@ -258,4 +572,176 @@ func TestBucket_Update(t *testing.T) {
 
 		testAndAssertBucketUpdate(t, b, s)
 	})
+	t.Run("with actions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:    1,
+			Title: "testbucket1",
+			Actions: []*BucketAction{
+				{Kind: BucketActionSetDone},
+				{Kind: BucketActionAddLabel, Value: 1},
+			},
+		}
+
+		testAndAssertBucketUpdate(t, b, s)
+
+		bucket, err := getBucketByID(s, 1)
+		require.NoError(t, err)
+		assert.Len(t, bucket.Actions, 2)
+	})
+	t.Run("with move permissions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:                 1,
+			Title:              "testbucket1",
+			MoveAllowedUserIDs: []int64{2},
+			MoveAllowedTeamIDs: []int64{1},
+		}
+
+		testAndAssertBucketUpdate(t, b, s)
+
+		bucket, err := getBucketByID(s, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2}, bucket.MoveAllowedUserIDs)
+		assert.Equal(t, []int64{1}, bucket.MoveAllowedTeamIDs)
+	})
+	t.Run("move permissions changed by a non-admin", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:                 10,
+			Title:              "testbucket10",
+			MoveAllowedUserIDs: []int64{1},
+		}
+
+		err := b.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+	t.Run("move permissions with nonexisting team", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:                 1,
+			Title:              "testbucket1",
+			MoveAllowedTeamIDs: []int64{9999},
+		}
+
+		err := b.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrTeamDoesNotExist(err))
+	})
+	t.Run("invalid sort mode", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:       1,
+			Title:    "testbucket1",
+			SortMode: 42,
+		}
+
+		err := b.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidBucketSortMode(err))
+	})
+	t.Run("invalid action", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:    1,
+			Title: "testbucket1",
+			Actions: []*BucketAction{
+				{Kind: "explode"},
+			},
+		}
+
+		err := b.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidBucketAction(err))
+	})
+	t.Run("set priority action out of range", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:    1,
+			Title: "testbucket1",
+			Actions: []*BucketAction{
+				{Kind: BucketActionSetPriority, Value: 6},
+			},
+		}
+
+		err := b.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidBucketAction(err))
+	})
+	t.Run("action with inaccessible label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:    1,
+			Title: "testbucket1",
+			Actions: []*BucketAction{
+				{Kind: BucketActionAddLabel, Value: 3},
+			},
+		}
+
+		err := b.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrUserHasNoAccessToLabel(err))
+	})
+}
+
+func setTaskEstimates(t *testing.T, s *xorm.Session, estimates map[int64]int64) {
+	for id, estimate := range estimates {
+		_, err := s.
+			Where("id = ?", id).
+			Cols("estimate").
+			Update(&Task{Estimate: estimate})
+		require.NoError(t, err)
+	}
+}
+
+func TestBucket_ReadAll_OpenEstimate(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	setTaskEstimates(t, s, map[int64]int64{3: 3600, 4: 1800, 2: 600})
+
+	b := &Bucket{ProjectID: 1}
+	bucketsInterface, _, _, err := b.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+	require.NoError(t, err)
+
+	buckets := bucketsInterface.([]*Bucket)
+	// Task 2 is done and therefore not included
+	assert.Equal(t, int64(0), buckets[0].OpenEstimate)
+	assert.Equal(t, int64(5400), buckets[1].OpenEstimate)
+	assert.Equal(t, int64(0), buckets[2].OpenEstimate)
+
+	// The estimate covers all tasks of the bucket, not only the ones on the current page
+	bucketsInterface, _, _, err = b.ReadAll(s, &user.User{ID: 1}, "", 1, 1)
+	require.NoError(t, err)
+
+	buckets = bucketsInterface.([]*Bucket)
+	require.Len(t, buckets[1].Tasks, 1)
+	assert.Equal(t, int64(3), buckets[1].Count)
+	assert.Equal(t, int64(5400), buckets[1].OpenEstimate)
 }
@@ -156,6 +156,7 @@ func (l *Label) ReadAll(s *xorm.Session, a web.Auth, search string, page int, pe
 	if err != nil {
 		return nil, 0, 0, err
 	}
+	u.APITokenProjectIDs = getAPITokenProjectIDs(a)
 
 	return GetLabelsByTaskIDs(s, &LabelByTaskIDsOptions{
 		Search:              []string{search},
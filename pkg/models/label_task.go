@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
 
 	"code.vikunja.io/api/pkg/log"
 	"code.vikunja.io/api/pkg/user"
@@ -64,9 +65,13 @@ func (LabelTask) TableName() string {
 // @Failure 404 {object} web.HTTPError "Label not found."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /tasks/{task}/labels/{label} [delete]
-func (lt *LabelTask) Delete(s *xorm.Session, _ web.Auth) (err error) {
-	_, err = s.Delete(&LabelTask{LabelID: lt.LabelID, TaskID: lt.TaskID})
-	return err
+func (lt *LabelTask) Delete(s *xorm.Session, a web.Auth) (err error) {
+	deleted, err := s.Delete(&LabelTask{LabelID: lt.LabelID, TaskID: lt.TaskID})
+	if err != nil || deleted == 0 {
+		return err
+	}
+
+	return dispatchTaskLabelEvent(s, a, lt.TaskID, lt.LabelID, false)
 }
 
 // Create adds a label to a task
@@ -84,7 +89,7 @@ func (lt *LabelTask) Delete(s *xorm.Session, _ web.Auth) (err error) {
 // @Failure 404 {object} web.HTTPError "The label does not exist."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /tasks/{task}/labels [put]
-func (lt *LabelTask) Create(s *xorm.Session, _ web.Auth) (err error) {
+func (lt *LabelTask) Create(s *xorm.Session, a web.Auth) (err error) {
 	// Check if the label is already added
 	exists, err := s.Exist(&LabelTask{LabelID: lt.LabelID, TaskID: lt.TaskID})
 	if err != nil {
@@ -101,7 +106,40 @@ func (lt *LabelTask) Create(s *xorm.Session, _ web.Auth) (err error) {
 	}
 
 	err = updateProjectByTaskID(s, lt.TaskID)
-	return
+	if err != nil {
+		return err
+	}
+
+	return dispatchTaskLabelEvent(s, a, lt.TaskID, lt.LabelID, true)
+}
+
+// dispatchTaskLabelEvent fires the event for a label which was added to or removed from a task
+func dispatchTaskLabelEvent(s *xorm.Session, a web.Auth, taskID, labelID int64, added bool) error {
+	task, err := GetTaskByIDSimple(s, taskID)
+	if err != nil {
+		return err
+	}
+	label, err := getLabelByIDSimple(s, labelID)
+	if IsErrLabelDoesNotExist(err) {
+		label = &Label{ID: labelID}
+	} else if err != nil {
+		return err
+	}
+
+	doer, _ := user.GetFromAuth(a)
+	if added {
+		return events.DispatchOnCommit(s, &TaskLabelCreatedEvent{
+			Task:  &task,
+			Label: label,
+			Doer:  doer,
+		})
+	}
+
+	return events.DispatchOnCommit(s, &TaskLabelDeletedEvent{
+		Task:  &task,
+		Label: label,
+		Doer:  doer,
+	})
 }
 
 // ReadAll gets all labels on a task
@@ -177,8 +215,13 @@ func GetLabelsByTaskIDs(s *xorm.Session, opts *LabelByTaskIDsOptions) (ls []*Lab
 	}
 	if opts.GetForUser != 0 {
 
+		forUser := &user.User{ID: opts.GetForUser}
+		if opts.User != nil {
+			// Only labels of tasks in projects the api token is restricted to, if any
+			forUser.APITokenProjectIDs = opts.User.APITokenProjectIDs
+		}
 		projects, _, _, err := getRawProjectsForUser(s, &projectOptions{
-			user: &user.User{ID: opts.GetForUser},
+			user: forUser,
 		})
 		if err != nil {
 			return nil, 0, 0, err
@@ -298,7 +341,16 @@ func (t *Task) UpdateTaskLabels(s *xorm.Session, creator web.Auth, labels []*Lab
 	if len(labels) == 0 && len(t.Labels) > 0 {
 		_, err = s.Where("task_id = ?", t.ID).
 			Delete(LabelTask{})
-		return err
+		if err != nil {
+			return err
+		}
+		for _, l := range t.Labels {
+			err = dispatchTaskLabelEvent(s, creator, t.ID, l.ID, false)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
 	// If we didn't change anything (from 0 to zero) don't do anything.
@@ -343,6 +395,12 @@ func (t *Task) UpdateTaskLabels(s *xorm.Session, creator web.Auth, labels []*Lab
 		if err != nil {
 			return err
 		}
+		for _, labelID := range labelsToDelete {
+			err = dispatchTaskLabelEvent(s, creator, t.ID, labelID, false)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Loop through our labels and add them
@@ -375,6 +433,11 @@ func (t *Task) UpdateTaskLabels(s *xorm.Session, creator web.Auth, labels []*Lab
 			return err
 		}
 		t.Labels = append(t.Labels, label)
+
+		err = dispatchTaskLabelEvent(s, creator, t.ID, l.ID, true)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = updateProjectLastUpdated(s, &Project{ID: t.ProjectID})
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/user"
 	"gopkg.in/d4l3k/messagediff.v1"
 
@@ -253,6 +254,7 @@ func TestLabelTask_Create(t *testing.T) {
 					"task_id":  l.TaskID,
 					"label_id": l.LabelID,
 				}, false)
+				events.AssertDispatched(t, &TaskLabelCreatedEvent{})
 			}
 			s.Close()
 		})
@@ -352,6 +354,9 @@ func TestLabelTask_Delete(t *testing.T) {
 					"task_id":  l.TaskID,
 				})
 			}
+			if !tt.wantErr && !tt.wantForbidden {
+				events.AssertDispatched(t, &TaskLabelDeletedEvent{})
+			}
 			s.Close()
 		})
 	}
@@ -50,12 +50,20 @@ func RegisterListeners() {
 	}
 	events.RegisterListener((&TaskCommentCreatedEvent{}).Name(), &SendTaskCommentNotification{})
 	events.RegisterListener((&TaskAssigneeCreatedEvent{}).Name(), &SendTaskAssignedNotification{})
+	events.RegisterListener((&TaskReactionCreatedEvent{}).Name(), &SendTaskReactionNotification{})
 	events.RegisterListener((&TaskDeletedEvent{}).Name(), &SendTaskDeletedNotification{})
 	events.RegisterListener((&ProjectCreatedEvent{}).Name(), &SendProjectCreatedNotification{})
 	events.RegisterListener((&TeamMemberAddedEvent{}).Name(), &SendTeamMemberAddedNotification{})
 	events.RegisterListener((&TaskCommentUpdatedEvent{}).Name(), &HandleTaskCommentEditMentions{})
 	events.RegisterListener((&TaskCreatedEvent{}).Name(), &HandleTaskCreateMentions{})
 	events.RegisterListener((&TaskUpdatedEvent{}).Name(), &HandleTaskUpdatedMentions{})
+	events.RegisterListener((&TaskChecklistItemCreatedEvent{}).Name(), &HandleTaskChecklistMentions{})
+	events.RegisterListener((&TaskChecklistItemUpdatedEvent{}).Name(), &HandleTaskChecklistMentions{})
+	events.RegisterListener((&TaskCreatedEvent{}).Name(), &ApplyProjectLabelRules{})
+	events.RegisterListener((&TaskUpdatedEvent{}).Name(), &ApplyProjectLabelRules{})
+	events.RegisterListener((&TaskCreatedEvent{}).Name(), &RunAutomationRules{})
+	events.RegisterListener((&TaskMovedEvent{}).Name(), &RunAutomationRules{})
+	events.RegisterListener((&TaskLabelCreatedEvent{}).Name(), &RunAutomationRules{})
 	events.RegisterListener((&UserDataExportRequestedEvent{}).Name(), &HandleUserDataExport{})
 	events.RegisterListener((&TaskCommentCreatedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
 	events.RegisterListener((&TaskCommentUpdatedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
@@ -66,24 +74,52 @@ func RegisterListeners() {
 	events.RegisterListener((&TaskAttachmentDeletedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
 	events.RegisterListener((&TaskRelationCreatedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
 	events.RegisterListener((&TaskRelationDeletedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
+	events.RegisterListener((&BucketLimitExceededEvent{}).Name(), &SendBucketLimitExceededNotification{})
+	events.RegisterListener((&TaskSLABreachedEvent{}).Name(), &SendTaskSLABreachedNotification{})
+	events.RegisterListener((&SavedFilterTaskMatchedEvent{}).Name(), &SendSavedFilterTaskMatchedNotification{})
+	events.RegisterListener((&SavedFilterTaskUnmatchedEvent{}).Name(), &SendSavedFilterTaskUnmatchedNotification{})
 	if config.TypesenseEnabled.GetBool() {
 		events.RegisterListener((&TaskDeletedEvent{}).Name(), &RemoveTaskFromTypesense{})
 		events.RegisterListener((&TaskCreatedEvent{}).Name(), &AddTaskToTypesense{})
 		events.RegisterListener((&TaskUpdatedEvent{}).Name(), &UpdateTaskInTypesense{})
 	}
+	if projectIntegrationsEnabled() {
+		registerEventForProjectIntegrations(&TaskCreatedEvent{})
+		registerEventForProjectIntegrations(&TaskUpdatedEvent{})
+		registerEventForProjectIntegrations(&TaskDoneEvent{})
+		registerEventForProjectIntegrations(&TaskDeletedEvent{})
+		registerEventForProjectIntegrations(&TaskAssigneeCreatedEvent{})
+		registerEventForProjectIntegrations(&TaskCommentCreatedEvent{})
+	}
+	if config.EventStreamEnabled.GetBool() {
+		registerEventStreamListeners()
+	}
 	if config.WebhooksEnabled.GetBool() {
 		RegisterEventForWebhook(&TaskCreatedEvent{})
 		RegisterEventForWebhook(&TaskUpdatedEvent{})
 		RegisterEventForWebhook(&TaskDeletedEvent{})
+		RegisterEventForWebhook(&TaskMovedEvent{})
 		RegisterEventForWebhook(&TaskAssigneeCreatedEvent{})
 		RegisterEventForWebhook(&TaskAssigneeDeletedEvent{})
 		RegisterEventForWebhook(&TaskCommentCreatedEvent{})
 		RegisterEventForWebhook(&TaskCommentUpdatedEvent{})
 		RegisterEventForWebhook(&TaskCommentDeletedEvent{})
+		RegisterEventForWebhook(&TaskReactionCreatedEvent{})
+		RegisterEventForWebhook(&TaskReactionDeletedEvent{})
 		RegisterEventForWebhook(&TaskAttachmentCreatedEvent{})
 		RegisterEventForWebhook(&TaskAttachmentDeletedEvent{})
+		RegisterEventForWebhook(&TaskLabelCreatedEvent{})
+		RegisterEventForWebhook(&TaskLabelDeletedEvent{})
 		RegisterEventForWebhook(&TaskRelationCreatedEvent{})
 		RegisterEventForWebhook(&TaskRelationDeletedEvent{})
+		RegisterEventForWebhook(&TaskPriorityEscalatedEvent{})
+		RegisterEventForWebhook(&TaskSLABreachedEvent{})
+		RegisterEventForWebhook(&BucketCreatedEvent{})
+		RegisterEventForWebhook(&BucketUpdatedEvent{})
+		RegisterEventForWebhook(&BucketDeletedEvent{})
+		RegisterEventForWebhook(&BucketLimitExceededEvent{})
+		RegisterEventForWebhook(&SavedFilterTaskMatchedEvent{})
+		RegisterEventForWebhook(&SavedFilterTaskUnmatchedEvent{})
 		RegisterEventForWebhook(&ProjectUpdatedEvent{})
 		RegisterEventForWebhook(&ProjectDeletedEvent{})
 		RegisterEventForWebhook(&ProjectSharedWithUserEvent{})
@@ -187,6 +223,11 @@ func (s *SendTaskCommentNotification) Handle(msg *message.Message) (err error) {
 	sess := db.NewSession()
 	defer sess.Close()
 
+	suppressed, err := notificationsSuppressedForProject(sess, event.Task.ProjectID)
+	if err != nil || suppressed {
+		return err
+	}
+
 	n := &TaskCommentNotification{
 		Doer:      event.Doer,
 		Task:      event.Task,
@@ -203,6 +244,12 @@ func (s *SendTaskCommentNotification) Handle(msg *message.Message) (err error) {
 		return err
 	}
 
+	watchers, err := getTaskWatchers(sess, []int64{event.Task.ID})
+	if err != nil {
+		return err
+	}
+	subscribers = addWatchersToSubscribers(subscribers, watchers[event.Task.ID])
+
 	log.Debugf("Sending task comment notifications to %d subscribers for task %d", len(subscribers), event.Task.ID)
 
 	for _, subscriber := range subscribers {
@@ -228,6 +275,69 @@ func (s *SendTaskCommentNotification) Handle(msg *message.Message) (err error) {
 	return
 }
 
+// SendTaskReactionNotification represents a listener
+type SendTaskReactionNotification struct {
+}
+
+// Name defines the name for the SendTaskReactionNotification listener
+func (s *SendTaskReactionNotification) Name() string {
+	return "task.reaction.notification.send"
+}
+
+// Handle is executed when the event SendTaskReactionNotification listens on is fired
+func (s *SendTaskReactionNotification) Handle(msg *message.Message) (err error) {
+	event := &TaskReactionCreatedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	suppressed, err := notificationsSuppressedForProject(sess, event.Task.ProjectID)
+	if err != nil || suppressed {
+		return err
+	}
+
+	// Reactions on comments go to the author of the comment, reactions on tasks to its creator.
+	// Both ids are not part of the event payload, so we need to get them from the db.
+	var targetID int64
+	if event.Comment != nil {
+		comment := &TaskComment{ID: event.Comment.ID}
+		err = getTaskCommentSimple(sess, comment)
+		if err != nil {
+			return err
+		}
+		targetID = comment.AuthorID
+	} else {
+		task, err := GetTaskByIDSimple(sess, event.Task.ID)
+		if err != nil {
+			return err
+		}
+		targetID = task.CreatedByID
+	}
+
+	// Link shares cannot receive notifications
+	if targetID <= 0 || targetID == event.Doer.ID {
+		return nil
+	}
+
+	target, err := user.GetUserByID(sess, targetID)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Sending task reaction notification to user %d for task %d", target.ID, event.Task.ID)
+
+	return notifications.Notify(target, &TaskReactionNotification{
+		Doer:     event.Doer,
+		Task:     event.Task,
+		Comment:  event.Comment,
+		Reaction: event.Reaction,
+	})
+}
+
 // HandleTaskCommentEditMentions  represents a listener
 type HandleTaskCommentEditMentions struct {
 }
@@ -278,11 +388,22 @@ func (s *SendTaskAssignedNotification) Handle(msg *message.Message) (err error)
 	sess := db.NewSession()
 	defer sess.Close()
 
+	suppressed, err := notificationsSuppressedForProject(sess, event.Task.ProjectID)
+	if err != nil || suppressed {
+		return err
+	}
+
 	subscribers, err := getSubscribersForEntity(sess, SubscriptionEntityTask, event.Task.ID)
 	if err != nil {
 		return err
 	}
 
+	watchers, err := getTaskWatchers(sess, []int64{event.Task.ID})
+	if err != nil {
+		return err
+	}
+	subscribers = addWatchersToSubscribers(subscribers, watchers[event.Task.ID])
+
 	log.Debugf("Sending task assigned notifications to %d subscribers for task %d", len(subscribers), event.Task.ID)
 
 	task, err := GetTaskByIDSimple(sess, event.Task.ID)
@@ -339,6 +460,11 @@ func (s *SendTaskDeletedNotification) Handle(msg *message.Message) (err error) {
 	sess := db.NewSession()
 	defer sess.Close()
 
+	suppressed, err := notificationsSuppressedForProject(sess, event.Task.ProjectID)
+	if err != nil || suppressed {
+		return err
+	}
+
 	var subscribers []*Subscription
 	subscribers, err = getSubscribersForEntity(sess, SubscriptionEntityTask, event.Task.ID)
 	// If the task does not exist and no one has explicitly subscribed to it, we won't find any subscriptions for it.
@@ -350,6 +476,26 @@ func (s *SendTaskDeletedNotification) Handle(msg *message.Message) (err error) {
 		return err
 	}
 
+	// The watchers are already deleted together with the task, but they are part of the event.
+	// Not all user fields are part of the payload, so we need to get the users from the db.
+	watcherIDs := make([]int64, 0, len(event.Task.Watchers))
+	for _, w := range event.Task.Watchers {
+		watcherIDs = append(watcherIDs, w.ID)
+	}
+	if len(watcherIDs) > 0 {
+		watchers, err := user.GetUsersByIDs(sess, watcherIDs)
+		if err != nil {
+			return err
+		}
+		watcherList := make([]*user.User, 0, len(watchers))
+		for _, id := range watcherIDs {
+			if w, has := watchers[id]; has {
+				watcherList = append(watcherList, w)
+			}
+		}
+		subscribers = addWatchersToSubscribers(subscribers, watcherList)
+	}
+
 	log.Debugf("Sending task deleted notifications to %d subscribers for task %d", len(subscribers), event.Task.ID)
 
 	for _, subscriber := range subscribers {
@@ -395,8 +541,13 @@ func (s *HandleTaskCreateMentions) Handle(msg *message.Message) (err error) {
 		Doer:  event.Doer,
 		IsNew: true,
 	}
-	_, err = notifyMentionedUsers(sess, event.Task, event.Task.Description, n)
-	return err
+	err = notifyNewlyMentionedUsers(sess, event.Task, event.Task.Description, n)
+	if err != nil {
+		_ = sess.Rollback()
+		return err
+	}
+
+	return sess.Commit()
 }
 
 // HandleTaskUpdatedMentions  represents a listener
@@ -425,8 +576,51 @@ func (s *HandleTaskUpdatedMentions) Handle(msg *message.Message) (err error) {
 		IsNew: false,
 	}
 
-	_, err = notifyMentionedUsers(sess, event.Task, event.Task.Description, n)
-	return err
+	err = notifyNewlyMentionedUsers(sess, event.Task, event.Task.Description, n)
+	if err != nil {
+		_ = sess.Rollback()
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// HandleTaskChecklistMentions  represents a listener
+type HandleTaskChecklistMentions struct {
+}
+
+// Name defines the name for the HandleTaskChecklistMentions listener
+func (s *HandleTaskChecklistMentions) Name() string {
+	return "task.checklist.mentions"
+}
+
+// Handle is executed when the event HandleTaskChecklistMentions listens on is fired
+func (s *HandleTaskChecklistMentions) Handle(msg *message.Message) (err error) {
+	// Checklist item created and updated events have the same payload
+	event := &TaskChecklistItemUpdatedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+	if event.ChecklistItem == nil || event.Doer == nil {
+		return nil
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	n := &UserMentionedInTaskNotification{
+		Task:          event.Task,
+		Doer:          event.Doer,
+		ChecklistItem: event.ChecklistItem,
+	}
+	err = notifyNewlyMentionedUsers(sess, event.Task, event.ChecklistItem.Title, n)
+	if err != nil {
+		_ = sess.Rollback()
+		return err
+	}
+
+	return sess.Commit()
 }
 
 // HandleTaskUpdateLastUpdated  represents a listener
@@ -594,6 +788,187 @@ func (s *DecreaseAttachmentCounter) Handle(_ *message.Message) (err error) {
 	return keyvalue.DecrBy(metrics.AttachmentsCountKey, 1)
 }
 
+// SendTaskSLABreachedNotification  represents a listener
+type SendTaskSLABreachedNotification struct {
+}
+
+// Name defines the name for the SendTaskSLABreachedNotification listener
+func (s *SendTaskSLABreachedNotification) Name() string {
+	return "task.sla.breached.notification.send"
+}
+
+// Handle is executed when the event SendTaskSLABreachedNotification listens on is fired
+func (s *SendTaskSLABreachedNotification) Handle(msg *message.Message) (err error) {
+	event := &TaskSLABreachedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	suppressed, err := notificationsSuppressedForProject(sess, event.Task.ProjectID)
+	if err != nil || suppressed {
+		return err
+	}
+
+	subscribers, err := getSubscribersForEntity(sess, SubscriptionEntityTask, event.Task.ID)
+	if err != nil {
+		return err
+	}
+
+	watchers, err := getTaskWatchers(sess, []int64{event.Task.ID})
+	if err != nil {
+		return err
+	}
+	subscribers = addWatchersToSubscribers(subscribers, watchers[event.Task.ID])
+
+	log.Debugf("Sending task sla breached notifications to %d subscribers for task %d", len(subscribers), event.Task.ID)
+
+	for _, subscriber := range subscribers {
+		n := &TaskSLABreachedNotification{
+			Task:    event.Task,
+			Project: event.Project,
+			Rule:    event.Rule,
+			Age:     event.Age,
+		}
+		err = notifications.Notify(subscriber.User, n)
+		if err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+///////
+// Bucket Event Listeners
+
+// SendBucketLimitExceededNotification  represents a listener
+type SendBucketLimitExceededNotification struct {
+}
+
+// Name defines the name for the SendBucketLimitExceededNotification listener
+func (s *SendBucketLimitExceededNotification) Name() string {
+	return "bucket.limit.exceeded.notification.send"
+}
+
+// Handle is executed when the event SendBucketLimitExceededNotification listens on is fired
+func (s *SendBucketLimitExceededNotification) Handle(msg *message.Message) (err error) {
+	event := &BucketLimitExceededEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	suppressed, err := notificationsSuppressedForProject(sess, event.Bucket.ProjectID)
+	if err != nil || suppressed {
+		return err
+	}
+
+	subscribers, err := getSubscribersForEntity(sess, SubscriptionEntityProject, event.Bucket.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Sending bucket limit exceeded notifications to %d subscribers for bucket %d", len(subscribers), event.Bucket.ID)
+
+	for _, subscriber := range subscribers {
+		if event.Doer != nil && subscriber.UserID == event.Doer.ID {
+			continue
+		}
+
+		n := &BucketLimitExceededNotification{
+			Doer:      event.Doer,
+			Task:      event.Task,
+			Bucket:    event.Bucket,
+			TaskCount: event.TaskCount,
+		}
+		err = notifications.Notify(subscriber.User, n)
+		if err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+///////
+// Saved Filter Event Listeners
+
+// SendSavedFilterTaskMatchedNotification  represents a listener
+type SendSavedFilterTaskMatchedNotification struct {
+}
+
+// Name defines the name for the SendSavedFilterTaskMatchedNotification listener
+func (s *SendSavedFilterTaskMatchedNotification) Name() string {
+	return "filter.task.matched.notification.send"
+}
+
+// Handle is executed when the event SendSavedFilterTaskMatchedNotification listens on is fired
+func (s *SendSavedFilterTaskMatchedNotification) Handle(msg *message.Message) (err error) {
+	event := &SavedFilterTaskMatchedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	return notifySavedFilterSubscriber(event.Task, event.Subscriber, &SavedFilterTaskMatchedNotification{
+		Task:   event.Task,
+		Filter: event.Filter,
+	})
+}
+
+// SendSavedFilterTaskUnmatchedNotification  represents a listener
+type SendSavedFilterTaskUnmatchedNotification struct {
+}
+
+// Name defines the name for the SendSavedFilterTaskUnmatchedNotification listener
+func (s *SendSavedFilterTaskUnmatchedNotification) Name() string {
+	return "filter.task.unmatched.notification.send"
+}
+
+// Handle is executed when the event SendSavedFilterTaskUnmatchedNotification listens on is fired
+func (s *SendSavedFilterTaskUnmatchedNotification) Handle(msg *message.Message) (err error) {
+	event := &SavedFilterTaskUnmatchedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	return notifySavedFilterSubscriber(event.Task, event.Subscriber, &SavedFilterTaskUnmatchedNotification{
+		Task:   event.Task,
+		Filter: event.Filter,
+	})
+}
+
+func notifySavedFilterSubscriber(task *Task, subscriber *user.User, n notifications.Notification) error {
+	sess := db.NewSession()
+	defer sess.Close()
+
+	suppressed, err := notificationsSuppressedForProject(sess, task.ProjectID)
+	if err != nil || suppressed {
+		return err
+	}
+
+	// The subscriber in the event only contains the id
+	u, err := user.GetUserByID(sess, subscriber.ID)
+	if err != nil {
+		if user.IsErrUserDoesNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	log.Debugf("Sending %s notification to user %d for task %d", n.Name(), u.ID, task.ID)
+
+	return notifications.Notify(u, n)
+}
+
 ///////
 // Project Event Listeners
 
@@ -698,6 +1073,13 @@ func getProjectIDFromAnyEvent(eventPayload map[string]interface{}) int64 {
 		}
 	}
 
+	if bucket, has := eventPayload["bucket"]; has {
+		b := bucket.(map[string]interface{})
+		if projectID, has := b["project_id"]; has {
+			return getIDAsInt64(projectID)
+		}
+	}
+
 	if project, has := eventPayload["project"]; has {
 		t := project.(map[string]interface{})
 		if projectID, has := t["id"]; has {
@@ -746,11 +1128,17 @@ func (wl *WebhookListener) Handle(msg *message.Message) (err error) {
 
 	matchingWebhooks := []*Webhook{}
 	for _, w := range ws {
-		for _, e := range w.Events {
-			if e == wl.EventName {
-				matchingWebhooks = append(matchingWebhooks, w)
-				break
-			}
+		if !w.listensTo(wl.EventName) {
+			continue
+		}
+
+		matches, err := w.matchesFilter(s, event)
+		if err != nil {
+			log.Errorf("Could not check filter of webhook %d for event %s: %s", w.ID, wl.EventName, err)
+			continue
+		}
+		if matches {
+			matchingWebhooks = append(matchingWebhooks, w)
 		}
 	}
 
@@ -801,18 +1189,29 @@ func (wl *WebhookListener) Handle(msg *message.Message) (err error) {
 		}
 	}
 
+	payload, err := json.Marshal(&WebhookPayload{
+		EventName: wl.EventName,
+		Time:      time.Now(),
+		Data:      event,
+	})
+	if err != nil {
+		return err
+	}
+
 	for _, webhook := range matchingWebhooks {
-		err = webhook.sendWebhookPayload(&WebhookPayload{
-			EventName: wl.EventName,
-			Time:      time.Now(),
-			Data:      event,
-		})
+		body, err := webhook.renderPayload(payload)
+		if err != nil {
+			log.Errorf("Could not render payload template of webhook %d for event %s: %s", webhook.ID, wl.EventName, err)
+			continue
+		}
+
+		_, err = createWebhookDelivery(s, webhook, wl.EventName, body, 0)
 		if err != nil {
 			return err
 		}
 	}
 
-	return
+	return s.Commit()
 }
 
 ///////
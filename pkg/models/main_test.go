@@ -25,6 +25,7 @@ import (
 	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/outbound"
 	"code.vikunja.io/api/pkg/user"
 )
 
@@ -57,6 +58,12 @@ func TestMain(m *testing.M) {
 	config.InitDefaultConfig()
 	// We need to set the root path even if we're not using the config, otherwise fixtures are not loaded correctly
 	config.ServiceRootpath.Set(os.Getenv("VIKUNJA_SERVICE_ROOTPATH"))
+	// The tests send requests to local http servers
+	config.OutboundAllowedCIDRs.Set([]string{"127.0.0.0/8"})
+	if err := outbound.Init(); err != nil {
+		fmt.Printf("Error setting up outbound requests: %s", err)
+		os.Exit(1)
+	}
 
 	// Some tests use the file engine, so we'll need to initialize that
 	files.InitTests()
@@ -19,12 +19,29 @@ package models
 import (
 	"regexp"
 	"strings"
+	"time"
 
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
 	"code.vikunja.io/api/pkg/user"
 
 	"xorm.io/xorm"
 )
 
+// TaskMention records that a user was notified about being mentioned in the description or checklist of a task.
+// Editing the task again does not notify them again, regardless of which notification channels they use.
+type TaskMention struct {
+	ID      int64     `xorm:"bigint autoincr not null unique pk" json:"-"`
+	TaskID  int64     `xorm:"bigint not null unique(task_user)" json:"-"`
+	UserID  int64     `xorm:"bigint not null unique(task_user)" json:"-"`
+	Created time.Time `xorm:"created not null" json:"-"`
+}
+
+// TableName returns the table name for task mentions
+func (*TaskMention) TableName() string {
+	return "task_mentions"
+}
+
 func FindMentionedUsersInText(s *xorm.Session, text string) (users map[int64]*user.User, err error) {
 	reg := regexp.MustCompile(`@\w+`)
 	matches := reg.FindAllString(text, -1)
@@ -39,3 +56,51 @@ func FindMentionedUsersInText(s *xorm.Session, text string) (users map[int64]*us
 
 	return user.GetUsersByUsername(s, usernames, true)
 }
+
+// notifyNewlyMentionedUsers notifies all users mentioned in a text of a task who can see the task and were not
+// mentioned in its description or checklist before. The doer is never notified about mentioning themselves.
+func notifyNewlyMentionedUsers(s *xorm.Session, task *Task, text string, n *UserMentionedInTaskNotification) (err error) {
+	users, err := FindMentionedUsersInText(s, text)
+	if err != nil || len(users) == 0 {
+		return err
+	}
+
+	var notified int
+	for _, u := range users {
+		if n.Doer != nil && n.Doer.ID == u.ID {
+			continue
+		}
+
+		exists, err := s.
+			Where("task_id = ? AND user_id = ?", task.ID, u.ID).
+			Exist(&TaskMention{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		can, _, err := task.CanRead(s, u)
+		if err != nil {
+			return err
+		}
+		if !can {
+			continue
+		}
+
+		_, err = s.Insert(&TaskMention{TaskID: task.ID, UserID: u.ID})
+		if err != nil {
+			return err
+		}
+
+		err = notifications.Notify(u, n)
+		if err != nil {
+			return err
+		}
+		notified++
+	}
+
+	log.Debugf("Notified %d newly mentioned users for task %d", notified, task.ID)
+	return nil
+}
@@ -179,3 +179,95 @@ func TestSendingMentionNotification(t *testing.T) {
 		assert.Len(t, dbNotifications, 1)
 	})
 }
+
+func TestNotifyNewlyMentionedUsers(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("should notify newly mentioned users only once", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 32)
+		require.NoError(t, err)
+		n := &UserMentionedInTaskNotification{
+			Doer: u,
+			Task: &task,
+		}
+
+		err = notifyNewlyMentionedUsers(s, &task, "Lorem Ipsum @user2", n)
+		require.NoError(t, err)
+		err = notifyNewlyMentionedUsers(s, &task, "Lorem Ipsum @user2 dolor", n)
+		require.NoError(t, err)
+
+		dbNotifications, err := notifications.GetNotificationsForNameAndUser(s, 2, n.Name(), task.ID)
+		require.NoError(t, err)
+		assert.Len(t, dbNotifications, 1)
+		db.AssertExists(t, "task_mentions", map[string]interface{}{
+			"task_id": 32,
+			"user_id": 2,
+		}, false)
+	})
+	t.Run("should not notify users mentioned before", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 32)
+		require.NoError(t, err)
+		n := &UserMentionedInTaskNotification{
+			Doer: u,
+			Task: &task,
+		}
+
+		// user3 is already mentioned in task 32 through the fixtures
+		err = notifyNewlyMentionedUsers(s, &task, "Lorem Ipsum @user3", n)
+		require.NoError(t, err)
+
+		dbNotifications, err := notifications.GetNotificationsForNameAndUser(s, 3, n.Name(), task.ID)
+		require.NoError(t, err)
+		assert.Empty(t, dbNotifications)
+	})
+	t.Run("should not notify the doer", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		n := &UserMentionedInTaskNotification{
+			Doer: u,
+			Task: &task,
+		}
+
+		err = notifyNewlyMentionedUsers(s, &task, "Lorem Ipsum @user1", n)
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_mentions", map[string]interface{}{
+			"task_id": 1,
+			"user_id": 1,
+		})
+	})
+	t.Run("checklist item", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 32)
+		require.NoError(t, err)
+		n := &UserMentionedInTaskNotification{
+			Doer:          u,
+			Task:          &task,
+			ChecklistItem: &ChecklistItem{Title: "Ask @user2 about it"},
+		}
+
+		err = notifyNewlyMentionedUsers(s, &task, n.ChecklistItem.Title, n)
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_mentions", map[string]interface{}{
+			"task_id": 32,
+			"user_id": 2,
+		}, false)
+		assert.NotNil(t, n.ToMail())
+	})
+}
@@ -0,0 +1,364 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+const taskPropertyMilestoneID string = "milestone_id"
+const taskPropertyMilestoneDueDate string = "milestone_due_date"
+
+// Milestone is a goal in a project, for example a release, tasks can be planned for
+type Milestone struct {
+	// The unique, numeric id of this milestone.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"milestone"`
+	// The project this milestone belongs to.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id" param:"project"`
+	// The title of this milestone.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// The description of this milestone.
+	Description string `xorm:"longtext null" json:"description"`
+	// The date until all tasks of this milestone should be done.
+	DueDate time.Time `xorm:"DATETIME INDEX null 'due_date'" json:"due_date"`
+
+	// The number of tasks of this milestone which are not done yet.
+	OpenTasks int64 `xorm:"-" json:"open_tasks"`
+	// The number of tasks of this milestone which are done.
+	DoneTasks int64 `xorm:"-" json:"done_tasks"`
+
+	// The user who initially created the milestone.
+	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this milestone was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this milestone was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for milestones
+func (*Milestone) TableName() string {
+	return "milestones"
+}
+
+func getMilestoneByID(s *xorm.Session, id int64) (milestone *Milestone, err error) {
+	milestone = &Milestone{}
+	exists, err := s.
+		Where("id = ?", id).
+		Get(milestone)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrMilestoneDoesNotExist{MilestoneID: id}
+	}
+	return milestone, nil
+}
+
+func getMilestonesForProjects(s *xorm.Session, projectIDs []int64) (milestones []*Milestone, err error) {
+	milestones = []*Milestone{}
+	if len(projectIDs) == 0 {
+		return
+	}
+	err = s.
+		In("project_id", projectIDs).
+		OrderBy("due_date IS NULL, due_date asc, id asc").
+		Find(&milestones)
+	return
+}
+
+// validateMilestone checks the milestone of a task belongs to the project of the task
+func (t *Task) validateMilestone(s *xorm.Session) error {
+	if t.MilestoneID == 0 {
+		return nil
+	}
+
+	milestone, err := getMilestoneByID(s, t.MilestoneID)
+	if err != nil {
+		return err
+	}
+	if milestone.ProjectID != t.ProjectID {
+		return &ErrMilestoneDoesNotBelongToProject{MilestoneID: milestone.ID, ProjectID: t.ProjectID}
+	}
+	return nil
+}
+
+// addMilestonesToTasks adds the full milestone object to all tasks which have one
+func addMilestonesToTasks(s *xorm.Session, taskMap map[int64]*Task) (err error) {
+	milestoneIDs := []int64{}
+	for _, t := range taskMap {
+		if t.MilestoneID != 0 {
+			milestoneIDs = append(milestoneIDs, t.MilestoneID)
+		}
+	}
+	if len(milestoneIDs) == 0 {
+		return nil
+	}
+
+	milestones := make(map[int64]*Milestone, len(milestoneIDs))
+	err = s.In("id", milestoneIDs).Find(&milestones)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range taskMap {
+		t.Milestone = milestones[t.MilestoneID]
+	}
+	return nil
+}
+
+type milestoneTaskCount struct {
+	MilestoneID int64
+	Done        bool
+	Count       int64
+}
+
+// addProgressToMilestones counts the open and done tasks of all milestones
+func addProgressToMilestones(s *xorm.Session, milestones []*Milestone) (err error) {
+	if len(milestones) == 0 {
+		return nil
+	}
+
+	milestoneMap := make(map[int64]*Milestone, len(milestones))
+	milestoneIDs := make([]int64, 0, len(milestones))
+	for _, m := range milestones {
+		milestoneMap[m.ID] = m
+		milestoneIDs = append(milestoneIDs, m.ID)
+	}
+
+	counts := []*milestoneTaskCount{}
+	err = s.
+		Table("tasks").
+		Select("milestone_id, done, count(*) AS count").
+		In("milestone_id", milestoneIDs).
+		GroupBy("milestone_id, done").
+		Find(&counts)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range counts {
+		m, has := milestoneMap[c.MilestoneID]
+		if !has {
+			continue
+		}
+		if c.Done {
+			m.DoneTasks = c.Count
+		} else {
+			m.OpenTasks = c.Count
+		}
+	}
+	return nil
+}
+
+// getMilestoneDueDateColumn returns a subquery for the due date of the milestone of a task, used to sort tasks by it.
+func getMilestoneDueDateColumn() string {
+	return "(SELECT milestones.due_date FROM milestones WHERE milestones.id = tasks.milestone_id)"
+}
+
+// usesMilestoneDueDateSort checks whether the tasks are sorted by the due date of their milestone. This can only be handled by the db.
+func usesMilestoneDueDateSort(opts *taskSearchOptions) bool {
+	for _, param := range opts.sortby {
+		if param.sortBy == taskPropertyMilestoneDueDate {
+			return true
+		}
+	}
+	return false
+}
+
+// Create creates a new milestone
+// @Summary Create a milestone
+// @Description Creates a new milestone in a project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param milestone body models.Milestone true "The milestone"
+// @Success 201 {object} models.Milestone "The created milestone."
+// @Failure 400 {object} web.HTTPError "Invalid milestone provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/milestones [put]
+func (m *Milestone) Create(s *xorm.Session, a web.Auth) (err error) {
+	m.ID = 0
+	m.OpenTasks = 0
+	m.DoneTasks = 0
+
+	m.CreatedBy, err = GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return
+	}
+	m.CreatedByID = m.CreatedBy.ID
+
+	_, err = s.Insert(m)
+	return
+}
+
+// ReadAll returns all milestones of a project
+// @Summary Get all milestones of a project
+// @Description Returns all milestones of a project with the number of their open and done tasks, sorted by their due date. Milestones without a due date come last.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {array} models.Milestone "The milestones."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/milestones [get]
+func (m *Milestone) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	project := &Project{ID: m.ProjectID}
+	canRead, _, err := project.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	milestones, err := getMilestonesForProjects(s, []int64{m.ProjectID})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addProgressToMilestones(s, milestones)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addCreatorsToMilestones(s, milestones)
+	return milestones, len(milestones), int64(len(milestones)), err
+}
+
+// ReadOne returns one milestone
+// @Summary Get one milestone
+// @Description Returns one milestone of a project with the number of its open and done tasks.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param milestoneID path int true "Milestone ID"
+// @Success 200 {object} models.Milestone "The milestone."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The milestone does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/milestones/{milestoneID} [get]
+func (m *Milestone) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	milestone, err := getMilestoneByID(s, m.ID)
+	if err != nil {
+		return err
+	}
+	*m = *milestone
+
+	err = addProgressToMilestones(s, []*Milestone{m})
+	if err != nil {
+		return err
+	}
+
+	return addCreatorsToMilestones(s, []*Milestone{m})
+}
+
+// Update updates a milestone
+// @Summary Update a milestone
+// @Description Updates the title, description and due date of a milestone.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param milestoneID path int true "Milestone ID"
+// @Param milestone body models.Milestone true "The milestone"
+// @Success 200 {object} models.Milestone "The updated milestone."
+// @Failure 400 {object} web.HTTPError "Invalid milestone provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The milestone does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/milestones/{milestoneID} [post]
+func (m *Milestone) Update(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where("id = ?", m.ID).
+		Cols("title", "description", "due_date").
+		Update(m)
+	if err != nil {
+		return
+	}
+
+	return m.ReadOne(s, nil)
+}
+
+// Delete deletes a milestone
+// @Summary Delete a milestone
+// @Description Deletes a milestone. The tasks of the milestone are not deleted, they won't have a milestone afterwards.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param milestoneID path int true "Milestone ID"
+// @Success 200 {object} models.Message "The milestone was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The milestone does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/milestones/{milestoneID} [delete]
+func (m *Milestone) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where("milestone_id = ?", m.ID).
+		Cols("milestone_id").
+		NoAutoTime().
+		Update(&Task{MilestoneID: 0})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("id = ?", m.ID).Delete(&Milestone{})
+	return
+}
+
+func deleteMilestonesForProject(s *xorm.Session, projectID int64) (err error) {
+	_, err = s.Where("project_id = ?", projectID).Delete(&Milestone{})
+	return
+}
+
+func addCreatorsToMilestones(s *xorm.Session, milestones []*Milestone) error {
+	if len(milestones) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(milestones))
+	for _, m := range milestones {
+		userIDs = append(userIDs, m.CreatedByID)
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range milestones {
+		m.CreatedBy = users[m.CreatedByID]
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see a milestone
+func (m *Milestone) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	milestone, err := m.getForProject(s)
+	if err != nil {
+		return false, 0, err
+	}
+
+	project := &Project{ID: milestone.ProjectID}
+	return project.CanRead(s, a)
+}
+
+// CanCreate checks if the user can create a milestone in a project
+func (m *Milestone) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if getSavedFilterIDFromProjectID(m.ProjectID) > 0 {
+		return false, nil
+	}
+
+	project := &Project{ID: m.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// CanUpdate checks if the user can update a milestone
+func (m *Milestone) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return m.canDoMilestone(s, a)
+}
+
+// CanDelete checks if the user can delete a milestone
+func (m *Milestone) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return m.canDoMilestone(s, a)
+}
+
+func (m *Milestone) canDoMilestone(s *xorm.Session, a web.Auth) (bool, error) {
+	milestone, err := m.getForProject(s)
+	if err != nil {
+		return false, err
+	}
+
+	project := &Project{ID: milestone.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// getForProject returns the milestone and makes sure it belongs to the project from the request
+func (m *Milestone) getForProject(s *xorm.Session) (*Milestone, error) {
+	milestone, err := getMilestoneByID(s, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	if m.ProjectID != 0 && milestone.ProjectID != m.ProjectID {
+		return nil, &ErrMilestoneDoesNotExist{MilestoneID: m.ID}
+	}
+	return milestone, nil
+}
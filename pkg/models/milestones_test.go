@@ -0,0 +1,169 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMilestone_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	m := &Milestone{ProjectID: 1, Title: "v3.0", Description: "Lorem Ipsum"}
+	can, err := m.CanCreate(s, u)
+	require.NoError(t, err)
+	assert.True(t, can)
+	err = m.Create(s, u)
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertExists(t, "milestones", map[string]interface{}{
+		"id":            m.ID,
+		"project_id":    1,
+		"title":         "v3.0",
+		"created_by_id": 1,
+	}, false)
+}
+
+func TestMilestone_ReadAll(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("with progress", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// task 1 is open, task 2 is done
+		_, err := s.In("id", []int64{1, 2}).Cols("milestone_id").NoAutoTime().Update(&Task{MilestoneID: 1})
+		require.NoError(t, err)
+
+		m := &Milestone{ProjectID: 1}
+		result, _, _, err := m.ReadAll(s, u, "", 0, 0)
+		require.NoError(t, err)
+		milestones := result.([]*Milestone)
+		require.Len(t, milestones, 2)
+		// Milestones without a due date come last
+		assert.Equal(t, "v1.0", milestones[0].Title)
+		assert.Equal(t, int64(1), milestones[0].OpenTasks)
+		assert.Equal(t, int64(1), milestones[0].DoneTasks)
+		assert.Equal(t, "v2.0", milestones[1].Title)
+		assert.Zero(t, milestones[1].OpenTasks)
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		m := &Milestone{ProjectID: 1}
+		_, _, _, err := m.ReadAll(s, &user.User{ID: 13}, "", 0, 0)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
+
+func TestMilestone_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err := s.ID(int64(1)).Cols("milestone_id").Update(&Task{MilestoneID: 1})
+	require.NoError(t, err)
+
+	m := &Milestone{ID: 1, ProjectID: 1}
+	can, err := m.CanDelete(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.True(t, can)
+	err = m.Delete(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "milestones", map[string]interface{}{"id": 1})
+	db.AssertExists(t, "tasks", map[string]interface{}{"id": 1, "milestone_id": 0}, false)
+}
+
+func TestTask_Milestone(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("assign", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1, Title: "test", ProjectID: 1, MilestoneID: 2}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+
+		loaded := &Task{ID: 1}
+		err = loaded.ReadOne(s, u)
+		require.NoError(t, err)
+		require.NotNil(t, loaded.Milestone)
+		assert.Equal(t, "v2.0", loaded.Milestone.Title)
+	})
+	t.Run("milestone of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "test", ProjectID: 1, MilestoneID: 3}
+		err := task.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrMilestoneDoesNotBelongToProject(err))
+	})
+	t.Run("filter and sort", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(int64(3)).Cols("milestone_id").Update(&Task{MilestoneID: 2})
+		require.NoError(t, err)
+		_, err = s.ID(int64(4)).Cols("milestone_id").Update(&Task{MilestoneID: 1})
+		require.NoError(t, err)
+
+		tc := &TaskCollection{ProjectID: 1, Filter: "milestone_id = 1"}
+		result, _, _, err := tc.ReadAll(s, u, "", 1, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(4), tasks[0].ID)
+
+		tc = &TaskCollection{
+			ProjectID: 1,
+			Filter:    "milestone_id > 0",
+			SortBy:    []string{"milestone_due_date"},
+			OrderBy:   []string{"asc"},
+		}
+		result, _, _, err = tc.ReadAll(s, u, "", 1, 50)
+		require.NoError(t, err)
+		tasks = result.([]*Task)
+		require.Len(t, tasks, 2)
+		// Task 4 is in the milestone with a due date, milestones without one come last
+		assert.Equal(t, int64(4), tasks[0].ID)
+		assert.Equal(t, int64(3), tasks[1].ID)
+	})
+}
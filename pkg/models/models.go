@@ -61,7 +61,40 @@ func GetTables() []interface{} {
 		&APIToken{},
 		&TypesenseSync{},
 		&Webhook{},
+		&WebhookDelivery{},
+		&ProjectInboundWebhook{},
+		&ProjectEvent{},
+		&IdempotencyKey{},
 		&Reaction{},
+		&BucketTemplate{},
+		&BucketCollapsedState{},
+		&TaskBucketTransition{},
+		&TimeEntry{},
+		&CustomField{},
+		&TaskCustomFieldValue{},
+		&ChecklistItem{},
+		&TaskHistoryEntry{},
+		&TaskCommentRevision{},
+		&TaskWatcher{},
+		&TaskUserPosition{},
+		&TaskSLABreach{},
+		&TaskVote{},
+		&ProjectTemplate{},
+		&ProjectStatus{},
+		&Milestone{},
+		&ProjectRole{},
+		&ProjectUserPosition{},
+		&ProjectEmailAddress{},
+		&SavedFilterUser{},
+		&SavedFilterTeam{},
+		&SavedFilterTaskMatch{},
+		&TaskView{},
+		&ProjectLabelRule{},
+		&ProjectAutomationRule{},
+		&ProjectScheduledAutomation{},
+		&EntityTombstone{},
+		&ProjectIntegration{},
+		&TaskMention{},
 	}
 }
 
@@ -35,6 +35,8 @@ type ReminderDueNotification struct {
 	User    *user.User `json:"user,omitempty"`
 	Task    *Task      `json:"task"`
 	Project *Project   `json:"project"`
+	// The reminder which fired. Clients can pass its time to the snooze endpoint to be reminded again later.
+	Reminder *TaskReminder `json:"reminder"`
 }
 
 // ToMail returns the mail notification for ReminderDueNotification
@@ -51,8 +53,9 @@ func (n *ReminderDueNotification) ToMail() *notifications.Mail {
 // ToDB returns the ReminderDueNotification notification in a format which can be saved in the db
 func (n *ReminderDueNotification) ToDB() interface{} {
 	return &ReminderDueNotification{
-		Task:    n.Task,
-		Project: n.Project,
+		Task:     n.Task,
+		Project:  n.Project,
+		Reminder: n.Reminder,
 	}
 }
 
@@ -61,6 +64,55 @@ func (n *ReminderDueNotification) Name() string {
 	return "task.reminder"
 }
 
+// Urgent marks reminders as urgent so users can get them during their quiet hours
+func (n *ReminderDueNotification) Urgent() bool {
+	return true
+}
+
+// ReminderEscalatedNotification represents a ReminderEscalatedNotification notification
+type ReminderEscalatedNotification struct {
+	User    *user.User `json:"user,omitempty"`
+	Task    *Task      `json:"task"`
+	Project *Project   `json:"project"`
+	// The reminder which escalated because the task was not done in time.
+	Reminder *TaskReminder `json:"reminder"`
+}
+
+// ToMail returns the mail notification for ReminderEscalatedNotification
+func (n *ReminderEscalatedNotification) ToMail() *notifications.Mail {
+	projectTitle := ""
+	if n.Project != nil {
+		projectTitle = " (" + n.Project.Title + ")"
+	}
+
+	return notifications.NewMail().
+		To(n.User.Email).
+		Subject(`"`+n.Task.Title+`"`+projectTitle+` is still not done`).
+		Greeting("Hi "+n.User.GetName()+",").
+		Line(`The task "`+n.Task.Title+`"`+projectTitle+` is still not done `+
+			utils.HumanizeDuration(time.Duration(n.Reminder.EscalateAfter)*time.Second)+` after its reminder.`).
+		Action("Open Task", config.ServicePublicURL.GetString()+"tasks/"+strconv.FormatInt(n.Task.ID, 10))
+}
+
+// ToDB returns the ReminderEscalatedNotification notification in a format which can be saved in the db
+func (n *ReminderEscalatedNotification) ToDB() interface{} {
+	return &ReminderEscalatedNotification{
+		Task:     n.Task,
+		Project:  n.Project,
+		Reminder: n.Reminder,
+	}
+}
+
+// Name returns the name of the notification
+func (n *ReminderEscalatedNotification) Name() string {
+	return "task.reminder.escalated"
+}
+
+// Urgent marks escalated reminders as urgent so users can get them during their quiet hours
+func (n *ReminderEscalatedNotification) Urgent() bool {
+	return true
+}
+
 // TaskCommentNotification represents a TaskCommentNotification notification
 type TaskCommentNotification struct {
 	Doer      *user.User   `json:"doer"`
@@ -106,6 +158,59 @@ func (n *TaskCommentNotification) Name() string {
 	return "task.comment"
 }
 
+// GroupKey groups all comment notifications of the same task. Mentions are grouped separately.
+func (n *TaskCommentNotification) GroupKey() string {
+	if n.Mentioned {
+		return "task.comment.mentioned." + strconv.FormatInt(n.Task.ID, 10)
+	}
+	return "task.comment." + strconv.FormatInt(n.Task.ID, 10)
+}
+
+// TaskReactionNotification represents a TaskReactionNotification notification
+type TaskReactionNotification struct {
+	Doer *user.User `json:"doer"`
+	Task *Task      `json:"task"`
+	// Only set if the reaction was on a comment
+	Comment  *TaskComment `json:"comment"`
+	Reaction *Reaction    `json:"reaction"`
+}
+
+// ToMail returns the mail notification for TaskReactionNotification
+func (n *TaskReactionNotification) ToMail() *notifications.Mail {
+	mail := notifications.NewMail()
+	if n.Comment != nil {
+		mail.
+			Subject(n.Doer.GetName() + ` reacted to your comment in "` + n.Task.Title + `"`).
+			Line(n.Doer.GetName() + " reacted with " + n.Reaction.Value + " to your comment:")
+		lines := bufio.NewScanner(strings.NewReader(n.Comment.Comment))
+		for lines.Scan() {
+			mail.Line(lines.Text())
+		}
+	} else {
+		mail.
+			Subject(n.Doer.GetName() + ` reacted to "` + n.Task.Title + `"`).
+			Line(n.Doer.GetName() + " reacted with " + n.Reaction.Value + ` to your task "` + n.Task.Title + `".`)
+	}
+
+	return mail.
+		Action("View Task", n.Task.GetFrontendURL())
+}
+
+// ToDB returns the TaskReactionNotification notification in a format which can be saved in the db
+func (n *TaskReactionNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *TaskReactionNotification) Name() string {
+	return "task.reaction"
+}
+
+// GroupKey groups all reaction notifications of the same task
+func (n *TaskReactionNotification) GroupKey() string {
+	return "task.reaction." + strconv.FormatInt(n.Task.ID, 10)
+}
+
 // TaskAssignedNotification represents a TaskAssignedNotification notification
 type TaskAssignedNotification struct {
 	Doer     *user.User `json:"doer"`
@@ -162,6 +267,113 @@ func (n *TaskDeletedNotification) Name() string {
 	return "task.deleted"
 }
 
+// BucketLimitExceededNotification represents a BucketLimitExceededNotification notification
+type BucketLimitExceededNotification struct {
+	Doer      *user.User `json:"doer"`
+	Task      *Task      `json:"task"`
+	Bucket    *Bucket    `json:"bucket"`
+	TaskCount int64      `json:"task_count"`
+}
+
+// ToMail returns the mail notification for BucketLimitExceededNotification
+func (n *BucketLimitExceededNotification) ToMail() *notifications.Mail {
+	return notifications.NewMail().
+		Subject("The bucket "+n.Bucket.Title+" exceeds its limit").
+		Line("The task "+n.Task.Title+" ("+n.Task.GetFullIdentifier()+")"+" was moved into the bucket "+n.Bucket.Title+".").
+		Line("It now has "+strconv.FormatInt(n.TaskCount, 10)+" tasks, but its limit is "+strconv.FormatInt(n.Bucket.Limit, 10)+".").
+		Action("View Task", n.Task.GetFrontendURL())
+}
+
+// ToDB returns the BucketLimitExceededNotification notification in a format which can be saved in the db
+func (n *BucketLimitExceededNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *BucketLimitExceededNotification) Name() string {
+	return "bucket.limit.exceeded"
+}
+
+// TaskSLABreachedNotification represents a TaskSLABreachedNotification notification
+type TaskSLABreachedNotification struct {
+	Task    *Task    `json:"task"`
+	Project *Project `json:"project"`
+	Rule    *SLARule `json:"rule"`
+	Age     int64    `json:"age"`
+}
+
+// ToMail returns the mail notification for TaskSLABreachedNotification
+func (n *TaskSLABreachedNotification) ToMail() *notifications.Mail {
+	rule := "the sla rule"
+	if n.Rule.Title != "" {
+		rule = `the sla rule "` + n.Rule.Title + `"`
+	}
+
+	return notifications.NewMail().
+		Subject(`The task "`+n.Task.Title+`" (`+n.Task.GetFullIdentifier()+`) breaches `+rule).
+		Line(`The task "`+n.Task.Title+`" (`+n.Project.Title+`) is older than `+rule+` allows.`).
+		Line(`It is not done since `+utils.HumanizeDuration(time.Duration(n.Age)*time.Second)+`, but should be done within `+utils.HumanizeDuration(time.Duration(n.Rule.MaxAge)*time.Second)+`.`).
+		Action("View Task", n.Task.GetFrontendURL())
+}
+
+// ToDB returns the TaskSLABreachedNotification notification in a format which can be saved in the db
+func (n *TaskSLABreachedNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *TaskSLABreachedNotification) Name() string {
+	return "task.sla.breached"
+}
+
+// SavedFilterTaskMatchedNotification represents a SavedFilterTaskMatchedNotification notification
+type SavedFilterTaskMatchedNotification struct {
+	Task   *Task        `json:"task"`
+	Filter *SavedFilter `json:"filter"`
+}
+
+// ToMail returns the mail notification for SavedFilterTaskMatchedNotification
+func (n *SavedFilterTaskMatchedNotification) ToMail() *notifications.Mail {
+	return notifications.NewMail().
+		Subject(`The task "`+n.Task.Title+`" (`+n.Task.GetFullIdentifier()+`) now matches the filter "`+n.Filter.Title+`"`).
+		Line(`The task "`+n.Task.Title+`" now matches the saved filter "`+n.Filter.Title+`" you subscribed to.`).
+		Action("View Task", n.Task.GetFrontendURL())
+}
+
+// ToDB returns the SavedFilterTaskMatchedNotification notification in a format which can be saved in the db
+func (n *SavedFilterTaskMatchedNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *SavedFilterTaskMatchedNotification) Name() string {
+	return "filter.task.matched"
+}
+
+// SavedFilterTaskUnmatchedNotification represents a SavedFilterTaskUnmatchedNotification notification
+type SavedFilterTaskUnmatchedNotification struct {
+	Task   *Task        `json:"task"`
+	Filter *SavedFilter `json:"filter"`
+}
+
+// ToMail returns the mail notification for SavedFilterTaskUnmatchedNotification
+func (n *SavedFilterTaskUnmatchedNotification) ToMail() *notifications.Mail {
+	return notifications.NewMail().
+		Subject(`The task "`+n.Task.Title+`" (`+n.Task.GetFullIdentifier()+`) no longer matches the filter "`+n.Filter.Title+`"`).
+		Line(`The task "`+n.Task.Title+`" does not match the saved filter "`+n.Filter.Title+`" you subscribed to anymore.`).
+		Action("View Task", n.Task.GetFrontendURL())
+}
+
+// ToDB returns the SavedFilterTaskUnmatchedNotification notification in a format which can be saved in the db
+func (n *SavedFilterTaskUnmatchedNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *SavedFilterTaskUnmatchedNotification) Name() string {
+	return "filter.task.unmatched"
+}
+
 // ProjectCreatedNotification represents a ProjectCreatedNotification notification
 type ProjectCreatedNotification struct {
 	Doer    *user.User `json:"doer"`
@@ -290,6 +502,8 @@ type UserMentionedInTaskNotification struct {
 	Doer  *user.User `json:"doer"`
 	Task  *Task      `json:"task"`
 	IsNew bool       `json:"is_new"`
+	// The checklist item the user was mentioned in. Nil if they were mentioned in the description.
+	ChecklistItem *ChecklistItem `json:"checklist_item,omitempty"`
 }
 
 func (n *UserMentionedInTaskNotification) SubjectID() int64 {
@@ -303,6 +517,15 @@ func (n *UserMentionedInTaskNotification) ToMail() *notifications.Mail {
 		subject = n.Doer.GetName() + ` mentioned you in a task "` + n.Task.Title + `"`
 	}
 
+	if n.ChecklistItem != nil {
+		return notifications.NewMail().
+			From(n.Doer.GetNameAndFromEmail()).
+			Subject(n.Doer.GetName()+` mentioned you in the checklist of "`+n.Task.Title+`"`).
+			Line("**"+n.Doer.GetName()+"** mentioned you in a checklist item of a task:").
+			Line(n.ChecklistItem.Title).
+			Action("View Task", n.Task.GetFrontendURL())
+	}
+
 	mail := notifications.NewMail().
 		From(n.Doer.GetNameAndFromEmail()).
 		Subject(subject).
@@ -352,3 +575,90 @@ func (n *DataExportReadyNotification) ToDB() interface{} {
 func (n *DataExportReadyNotification) Name() string {
 	return "data.export.ready"
 }
+
+// DigestNotification represents a DigestNotification notification
+type DigestNotification struct {
+	User     *user.User
+	Weekly   bool
+	Now      time.Time
+	Due      []*Task
+	Overdue  []*Task
+	Activity []*digestActivity
+	Projects map[int64]*Project
+}
+
+func (n *DigestNotification) taskLine(task *Task) string {
+	line := `* [` + task.Title + `](` + config.ServicePublicURL.GetString() + "tasks/" + strconv.FormatInt(task.ID, 10) + `)`
+	if p, has := n.Projects[task.ProjectID]; has {
+		line += ` (` + p.Title + `)`
+	}
+	return line
+}
+
+// ToMail returns the mail notification for DigestNotification
+func (n *DigestNotification) ToMail() *notifications.Mail {
+	subject := "Your tasks for today"
+	dueHeadline := "These tasks are due today:"
+	activityHeadline := "This happened since yesterday in projects you subscribed to:"
+	if n.Weekly {
+		subject = "Your tasks for this week"
+		dueHeadline = "These tasks are due this week:"
+		activityHeadline = "This happened in the last week in projects you subscribed to:"
+	}
+
+	mail := notifications.NewMail().
+		Subject(subject).
+		Greeting("Hi " + n.User.GetName() + ",")
+
+	if len(n.Due) > 0 {
+		dueLine := ""
+		for _, task := range n.Due {
+			dueLine += n.taskLine(task) + ", due " + task.DueDate.In(n.Now.Location()).Format("Mon, Jan 2 15:04") + "\n"
+		}
+		mail.Line(dueHeadline).Line(dueLine)
+	}
+
+	if len(n.Overdue) > 0 {
+		overdueLine := ""
+		for _, task := range n.Overdue {
+			until := n.Now.Sub(task.DueDate).Round(1 * time.Hour)
+			overdueLine += n.taskLine(task) + ", overdue since " + utils.HumanizeDuration(until) + "\n"
+		}
+		mail.Line("These tasks are overdue:").Line(overdueLine)
+	}
+
+	if len(n.Activity) > 0 {
+		activityLine := ""
+		for _, a := range n.Activity {
+			changes := []string{}
+			if a.Created {
+				changes = append(changes, "created")
+			}
+			if a.Done {
+				changes = append(changes, "done")
+			}
+			if a.Comments == 1 {
+				changes = append(changes, "1 new comment")
+			}
+			if a.Comments > 1 {
+				changes = append(changes, strconv.FormatInt(a.Comments, 10)+" new comments")
+			}
+			activityLine += n.taskLine(a.Task) + ": " + strings.Join(changes, ", ") + "\n"
+		}
+		mail.Line(activityHeadline).Line(activityLine)
+	}
+
+	return mail.
+		Action("Open Vikunja", config.ServicePublicURL.GetString()).
+		Line("Have a nice day!")
+}
+
+// ToDB returns the DigestNotification notification in a format which can be saved in the db
+func (n *DigestNotification) ToDB() interface{} {
+	return nil
+}
+
+// Name returns the name of the notification
+func (n *DigestNotification) Name() string {
+	return "digest"
+}
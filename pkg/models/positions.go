@@ -0,0 +1,158 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"math"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"xorm.io/xorm"
+)
+
+// minPositionGap is the smallest distance two positions can have before all positions of their scope are
+// rebalanced. Clients calculate a new position as the average of its neighbours, after a lot of moves the
+// positions converge until they can't be distinguished anymore.
+const minPositionGap = 0.01
+
+// positionIsTooClose checks if another entity in the same scope (for example all tasks in the same bucket) has a
+// position which is too close to the provided one.
+func positionIsTooClose(s *xorm.Session, bean interface{}, column, scopeColumn string, scopeID, id int64, position float64) (bool, error) {
+	return s.
+		Where(scopeColumn+" = ? AND id != ?", scopeID, id).
+		And(column+" > ? AND "+column+" < ?", position-minPositionGap, position+minPositionGap).
+		Exist(bean)
+}
+
+// positionsHaveTooSmallGaps checks if any two neighbouring positions of a sorted list are too close to each other.
+func positionsHaveTooSmallGaps(positions []float64) bool {
+	for i := 1; i < len(positions); i++ {
+		if positions[i]-positions[i-1] < minPositionGap {
+			return true
+		}
+	}
+	return false
+}
+
+func recalculateBucketPositions(s *xorm.Session, projectID int64) (err error) {
+
+	allBuckets := []*Bucket{}
+	err = s.
+		Where("project_id = ?", projectID).
+		OrderBy("position asc").
+		Find(&allBuckets)
+	if err != nil {
+		return
+	}
+
+	maxPosition := math.Pow(2, 32)
+
+	for i, bucket := range allBuckets {
+
+		currentPosition := maxPosition / float64(len(allBuckets)) * (float64(i + 1))
+
+		_, err = s.Cols("position").
+			Where("id = ?", bucket.ID).
+			NoAutoTime().
+			Update(&Bucket{Position: currentPosition})
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func getSortedPositions(s *xorm.Session, table, column, scopeColumn string, scopeID int64) (positions []float64, err error) {
+	positions = []float64{}
+	err = s.
+		Table(table).
+		Where(scopeColumn+" = ?", scopeID).
+		OrderBy(column + " asc").
+		Select("COALESCE(" + column + ", 0)").
+		Find(&positions)
+	return
+}
+
+// RebalanceAllPositions checks the positions of all tasks and buckets and rebalances all of them where the
+// gaps between two positions got too small.
+func RebalanceAllPositions() (err error) {
+	s := db.NewSession()
+	defer s.Close()
+
+	projectIDs := []int64{}
+	err = s.Table("projects").Cols("id").Find(&projectIDs)
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	for _, projectID := range projectIDs {
+		positions, err := getSortedPositions(s, "tasks", "position", "project_id", projectID)
+		if err != nil {
+			_ = s.Rollback()
+			return err
+		}
+		if positionsHaveTooSmallGaps(positions) {
+			log.Debugf("Rebalancing task positions of project %d", projectID)
+			err = recalculateTaskPositions(s, projectID)
+			if err != nil {
+				_ = s.Rollback()
+				return err
+			}
+		}
+
+		positions, err = getSortedPositions(s, "buckets", "position", "project_id", projectID)
+		if err != nil {
+			_ = s.Rollback()
+			return err
+		}
+		if positionsHaveTooSmallGaps(positions) {
+			log.Debugf("Rebalancing bucket positions of project %d", projectID)
+			err = recalculateBucketPositions(s, projectID)
+			if err != nil {
+				_ = s.Rollback()
+				return err
+			}
+		}
+	}
+
+	bucketIDs := []int64{}
+	err = s.Table("buckets").Cols("id").Find(&bucketIDs)
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	for _, bucketID := range bucketIDs {
+		positions, err := getSortedPositions(s, "tasks", "kanban_position", "bucket_id", bucketID)
+		if err != nil {
+			_ = s.Rollback()
+			return err
+		}
+		if positionsHaveTooSmallGaps(positions) {
+			log.Debugf("Rebalancing kanban positions of bucket %d", bucketID)
+			err = recalculateTaskKanbanPositions(s, bucketID)
+			if err != nil {
+				_ = s.Rollback()
+				return err
+			}
+		}
+	}
+
+	return s.Commit()
+}
@@ -0,0 +1,119 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositionsHaveTooSmallGaps(t *testing.T) {
+	assert.False(t, positionsHaveTooSmallGaps([]float64{}))
+	assert.False(t, positionsHaveTooSmallGaps([]float64{1, 2, 3}))
+	assert.True(t, positionsHaveTooSmallGaps([]float64{1, 1.001, 3}))
+}
+
+func TestTask_Update_RebalancesKanbanPositions(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	u := &user.User{ID: 1}
+
+	_, err := s.Where("bucket_id = ?", 1).Cols("kanban_position").NoAutoTime().Update(&Task{KanbanPosition: 100})
+	require.NoError(t, err)
+
+	task := &Task{
+		ID:             1,
+		Title:          "test",
+		ProjectID:      1,
+		BucketID:       1,
+		KanbanPosition: 100.001,
+	}
+	err = task.Update(s, u)
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	positions, err := getSortedPositions(s, "tasks", "kanban_position", "bucket_id", 1)
+	require.NoError(t, err)
+	assert.False(t, positionsHaveTooSmallGaps(positions))
+}
+
+func TestBucket_Update_RebalancesPositions(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("position not changed", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{ID: 1, ProjectID: 1, Title: "renamed"}
+		err := b.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.InDelta(t, 1, b.Position, 0)
+		positions, err := getSortedPositions(s, "buckets", "position", "project_id", 1)
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1, 2, 3}, positions)
+	})
+	t.Run("position too close to another bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{ID: 1, ProjectID: 1, Title: "testbucket1", Position: 2.001}
+		err := b.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		positions, err := getSortedPositions(s, "buckets", "position", "project_id", 1)
+		require.NoError(t, err)
+		assert.False(t, positionsHaveTooSmallGaps(positions))
+
+		bb, err := getBucketByID(s, 1)
+		require.NoError(t, err)
+		assert.InDelta(t, bb.Position, b.Position, 0)
+		assert.NotEqual(t, 2.001, b.Position)
+	})
+}
+
+func TestRebalanceAllPositions(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err := s.Where("project_id = ?", 1).Cols("position").NoAutoTime().Update(&Bucket{Position: 5})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	err = RebalanceAllPositions()
+	require.NoError(t, err)
+
+	positions, err := getSortedPositions(s, "buckets", "position", "project_id", 1)
+	require.NoError(t, err)
+	assert.False(t, positionsHaveTooSmallGaps(positions))
+}
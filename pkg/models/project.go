@@ -47,6 +47,13 @@ type Project struct {
 	Identifier string `xorm:"varchar(10) null" json:"identifier" valid:"runelength(0|10)" minLength:"0" maxLength:"10"`
 	// The hex color of this project
 	HexColor string `xorm:"varchar(6) null" json:"hex_color" valid:"runelength(0|7)" maxLength:"7"`
+	// The hex color used to theme this project, for example for buttons and highlights.
+	AccentColor string `xorm:"varchar(6) null" json:"accent_color" valid:"runelength(0|7)" maxLength:"7"`
+	// A single emoji used as icon of this project. Setting an emoji removes a previously uploaded icon image.
+	Icon string `xorm:"varchar(50) null" json:"icon" maxLength:"50"`
+	// The id of the file this project has set as icon image. If not 0, the image can be accessed at /projects/{projectID}/icon.
+	// You can only read this property, use the icon endpoints to modify it.
+	IconFileID int64 `xorm:"bigint null" json:"icon_file_id"`
 
 	OwnerID         int64    `xorm:"bigint INDEX not null" json:"-"`
 	ParentProjectID int64    `xorm:"bigint INDEX null" json:"parent_project_id"`
@@ -54,14 +61,44 @@ type Project struct {
 
 	// The ID of the bucket where new tasks without a bucket are added to. By default, this is the leftmost bucket in a project.
 	DefaultBucketID int64 `xorm:"bigint INDEX null" json:"default_bucket_id"`
+	// The ids of the buckets where new tasks are added to depending on how they were created, if they were created without a bucket.
+	// The key is the source of the task and can be `api_token`, `link_share`, `caldav`, `email`, `slack` or `inbound_webhook`. Tasks from all other sources are added to the default bucket.
+	SourceDefaultBuckets map[TaskSource]int64 `xorm:"JSON null" json:"source_default_buckets"`
 	// If tasks are moved to the done bucket, they are marked as done. If they are marked as done individually, they are moved into the done bucket.
 	DoneBucketID int64 `xorm:"bigint INDEX null" json:"done_bucket_id"`
+	// The id of a bucket template to create the buckets of this project from. Only used when creating a new project,
+	// if not provided the project will get a single "Backlog" bucket.
+	BucketTemplateID int64 `xorm:"-" json:"bucket_template_id,omitempty"`
+	// The id of a project template to create this project from. Only used when creating a new project. The buckets,
+	// custom fields and tasks of the template are created in the new project, it takes precedence over bucket_template_id.
+	ProjectTemplateID int64 `xorm:"-" json:"project_template_id,omitempty"`
 
 	// The user who created this project.
 	Owner *user.User `xorm:"-" json:"owner" valid:"-"`
 
+	// If true, tasks which are blocked by other tasks which are not done yet can't be marked as done.
+	EnforceBlockingDependencies bool `xorm:"not null default false" json:"enforce_blocking_dependencies"`
+
+	// Rules to raise the priority of tasks which are overdue for a certain time. Priorities are only raised, never lowered.
+	PriorityEscalation []*PriorityEscalationRule `xorm:"JSON null" json:"priority_escalation"`
+
+	// Rules defining how old undone tasks in this project may get, for example while they are in a certain bucket.
+	// Tasks breaching a rule are exposed through their `sla` property and trigger a notification once.
+	SLARules []*SLARule `xorm:"'sla_rules' JSON null" json:"sla_rules"`
+
+	// If greater than 0, tasks which are done for more than this number of days are archived automatically.
+	DoneTasksRetentionDays int64 `xorm:"bigint not null default 0" json:"done_tasks_retention_days"`
+	// If set, tasks which are done for longer than the retention period are moved into this bucket instead of being archived.
+	DoneTasksRetentionBucketID int64 `xorm:"bigint null" json:"done_tasks_retention_bucket_id"`
+
+	// The labels, priority, assignee and reminder new tasks in this project get if they are created through the api, CalDAV or email without them.
+	TaskDefaults *ProjectTaskDefaults `xorm:"JSON null" json:"task_defaults"`
+
 	// Whether a project is archived.
 	IsArchived bool `xorm:"not null default false" json:"is_archived" query:"is_archived"`
+	// Controls what happens to child projects, tasks, reminders and notifications when the project is archived.
+	// Only used in the request archiving the project. While the project is archived, this contains the options it was archived with.
+	ArchiveOptions *ProjectArchiveOptions `xorm:"JSON null" json:"archive_options"`
 
 	// The id of the file this project has set as background
 	BackgroundFileID int64 `xorm:"null" json:"-"`
@@ -77,14 +114,26 @@ type Project struct {
 	// Will only returned when retreiving one project.
 	Subscription *Subscription `xorm:"-" json:"subscription,omitempty"`
 
+	// How clients show the tasks of a saved filter. Only set for the pseudo projects of saved filters, use the saved filter endpoints to modify it.
+	ViewSettings *SavedFilterViewSettings `xorm:"-" json:"view_settings,omitempty"`
+
 	// The position this project has when querying all projects. See the tasks.position property on how to use this.
 	Position float64 `xorm:"double null" json:"position"`
+	// The position the current user has set for this project among their top-level projects. Top-level projects are sorted by it.
+	// This is 0 if the user did not set a position, use the position endpoints to modify it.
+	UserPosition float64 `xorm:"-" json:"user_position"`
+	// The position the current user has set for this project among their favorite projects.
+	// This is 0 if the user did not set a position, use the position endpoints to modify it.
+	FavoritePosition float64 `xorm:"-" json:"favorite_position"`
 
 	// A timestamp when this project was created. You cannot change this value.
 	Created time.Time `xorm:"created not null" json:"created"`
 	// A timestamp when this project was last updated. You cannot change this value.
 	Updated time.Time `xorm:"updated not null" json:"updated"`
 
+	// Set from the If-Unmodified-Since header of an update request, see CheckUnmodifiedSince.
+	unmodifiedSince time.Time
+
 	web.CRUDable `xorm:"-" json:"-"`
 	web.Rights   `xorm:"-" json:"-"`
 }
@@ -98,6 +147,8 @@ type ProjectWithTasksAndBuckets struct {
 	// Only used for migration.
 	Buckets          []*Bucket `xorm:"-" json:"buckets"`
 	BackgroundFileID int64     `xorm:"null" json:"background_file_id"`
+	// Only used for migration.
+	CustomFields []*CustomField `xorm:"-" json:"custom_fields"`
 }
 
 // TableName returns a better name for the projects table
@@ -178,7 +229,7 @@ func (p *Project) ReadAll(s *xorm.Session, a web.Auth, search string, page int,
 		return nil, 0, 0, err
 	}
 
-	if len(savedFiltersProject) > 0 {
+	if len(savedFiltersProject) > 0 && len(getAPITokenProjectIDs(doer)) == 0 {
 		prs = append(prs, savedFiltersProject...)
 	}
 
@@ -227,6 +278,7 @@ func (p *Project) ReadOne(s *xorm.Session, a web.Auth) (err error) {
 		p.Created = sf.Created
 		p.Updated = sf.Updated
 		p.OwnerID = sf.OwnerID
+		p.ViewSettings = sf.getViewSettings()
 	}
 
 	// Get project owner
@@ -261,11 +313,13 @@ func (p *Project) ReadOne(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
-	p.Subscription, err = GetSubscription(s, SubscriptionEntityProject, p.ID, a)
-	if err != nil && IsErrProjectDoesNotExist(err) && isFilter {
-		return nil
+	if isFilter {
+		p.Subscription, err = GetSubscription(s, SubscriptionEntitySavedFilter, filterID, a)
+		return
 	}
 
+	p.Subscription, err = GetSubscription(s, SubscriptionEntityProject, p.ID, a)
+
 	return
 }
 
@@ -439,6 +493,8 @@ func getUserProjectsStatement(parentProjectIDs []int64, userID int64, search str
 		GroupBy("l.id")
 }
 
+// getAllProjectsForUser returns all projects the user has access to. If opts.user is set and the current request
+// was authenticated with an api token restricted to some projects, only those projects are returned.
 func getAllProjectsForUser(s *xorm.Session, userID int64, opts *projectOptions) (projects []*Project, totalCount int64, err error) {
 
 	limit, start := getLimitFromPageIndex(opts.page, opts.perPage)
@@ -459,9 +515,26 @@ UNION ALL
 SELECT p.* FROM projects p
 INNER JOIN all_projects ap ON p.parent_project_id = ap.id`
 
+	var onlyProjectIDs []int64
+	if opts.user != nil {
+		onlyProjectIDs, err = getAPITokenAccessibleProjectIDs(s, opts.user)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var onlyProjectsSQL string
+	if len(onlyProjectIDs) > 0 {
+		ids := make([]string, 0, len(onlyProjectIDs))
+		for _, id := range onlyProjectIDs {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		onlyProjectsSQL = ` WHERE all_projects.id IN (` + strings.Join(ids, ",") + `)`
+	}
+
 	currentProjects := []*Project{}
 	err = s.SQL(`WITH RECURSIVE all_projects as (`+baseQuery+`)
-SELECT DISTINCT * FROM all_projects ORDER BY position `+limitSQL, args...).Find(&currentProjects)
+SELECT * FROM (SELECT DISTINCT * FROM all_projects`+onlyProjectsSQL+`) AS ap ORDER BY `+getProjectUserPositionOrderColumn("ap", userID)+` `+limitSQL, args...).Find(&currentProjects)
 	if err != nil {
 		return
 	}
@@ -472,7 +545,7 @@ SELECT DISTINCT * FROM all_projects ORDER BY position `+limitSQL, args...).Find(
 
 	totalCount, err = s.
 		SQL(`WITH RECURSIVE all_projects as (`+baseQuery+`)
-SELECT COUNT(DISTINCT all_projects.id) FROM all_projects`, args...).
+SELECT COUNT(DISTINCT all_projects.id) FROM all_projects`+onlyProjectsSQL, args...).
 		Count(&Project{})
 	if err != nil {
 		return nil, 0, err
@@ -493,6 +566,11 @@ func getRawProjectsForUser(s *xorm.Session, opts *projectOptions) (projects []*P
 		return
 	}
 
+	// Tokens restricted to some projects can't access the favorites pseudo project since it contains tasks from other projects
+	if len(getAPITokenProjectIDs(opts.user)) > 0 {
+		return allProjects, len(allProjects), totalItems, nil
+	}
+
 	favoriteCount, err := s.
 		Where(builder.And(
 			builder.Eq{"user_id": opts.user.ID},
@@ -581,6 +659,11 @@ func addProjectDetails(s *xorm.Session, projects []*Project, a web.Auth) (err er
 		return err
 	}
 
+	err = addUserPositionsToProjects(s, projectIDs, projects, a)
+	if err != nil {
+		return err
+	}
+
 	subscriptions, err := GetSubscriptionsForProjects(s, projects, a)
 	if err != nil {
 		log.Errorf("An error occurred while getting project subscriptions for a project: %s", err.Error())
@@ -694,6 +777,11 @@ func checkProjectBeforeUpdateOrDelete(s *xorm.Session, project *Project) (err er
 
 			parentsVisited[parent.ID] = true
 		}
+
+		err = validateProjectDepth(s, project, allProjects)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Check if the identifier is unique and not empty
@@ -726,13 +814,55 @@ func CreateProject(s *xorm.Session, project *Project, auth web.Auth, createBackl
 
 	project.OwnerID = doer.ID
 	project.Owner = doer
+	// Archive options are only applied when archiving an existing project
+	project.ArchiveOptions = nil
 
 	err = checkProjectBeforeUpdateOrDelete(s, project)
 	if err != nil {
 		return
 	}
 
+	var projectTemplate *ProjectTemplate
+	if createBacklogBucket && project.ProjectTemplateID != 0 {
+		projectTemplate = &ProjectTemplate{ID: project.ProjectTemplateID}
+		can, _, err := projectTemplate.CanRead(s, auth)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return &ErrProjectTemplateDoesNotExist{ProjectTemplateID: project.ProjectTemplateID}
+		}
+		projectTemplate.applySettings(project)
+	}
+
+	err = project.validatePriorityEscalation()
+	if err != nil {
+		return
+	}
+
+	err = project.validateSLARules(s)
+	if err != nil {
+		return
+	}
+
+	err = project.validateDoneTasksRetention(s)
+	if err != nil {
+		return
+	}
+
+	err = project.validateIcon()
+	if err != nil {
+		return
+	}
+
+	err = project.validateTaskDefaults(s, auth)
+	if err != nil {
+		return
+	}
+
 	project.HexColor = utils.NormalizeHex(project.HexColor)
+	project.AccentColor = utils.NormalizeHex(project.AccentColor)
+	project.IconFileID = 0
 
 	_, err = s.Insert(project)
 	if err != nil {
@@ -750,7 +880,30 @@ func CreateProject(s *xorm.Session, project *Project, auth web.Auth, createBackl
 		}
 	}
 
-	if createBacklogBucket {
+	if projectTemplate != nil {
+		err = projectTemplate.applyToProject(s, project, auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	if createBacklogBucket && projectTemplate == nil && project.BucketTemplateID != 0 {
+		template := &BucketTemplate{ID: project.BucketTemplateID}
+		can, _, err := template.CanRead(s, auth)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return &ErrBucketTemplateDoesNotExist{BucketTemplateID: project.BucketTemplateID}
+		}
+
+		_, err = template.applyToProject(s, project, auth, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	if createBacklogBucket && projectTemplate == nil && project.BucketTemplateID == 0 {
 		// Create a new first bucket for this project
 		b := &Bucket{
 			ProjectID: project.ID,
@@ -762,7 +915,7 @@ func CreateProject(s *xorm.Session, project *Project, auth web.Auth, createBackl
 		}
 	}
 
-	return events.Dispatch(&ProjectCreatedEvent{
+	return events.DispatchOnCommit(s, &ProjectCreatedEvent{
 		Project: project,
 		Doer:    doer,
 	})
@@ -805,16 +958,55 @@ func UpdateProject(s *xorm.Session, project *Project, auth web.Auth, updateProje
 		}
 	}
 
+	err = project.validateSourceDefaultBuckets(s)
+	if err != nil {
+		return err
+	}
+
+	err = project.validatePriorityEscalation()
+	if err != nil {
+		return err
+	}
+
+	err = project.validateSLARules(s)
+	if err != nil {
+		return err
+	}
+
+	err = project.validateDoneTasksRetention(s)
+	if err != nil {
+		return err
+	}
+
+	err = project.validateIcon()
+	if err != nil {
+		return err
+	}
+
+	err = project.validateTaskDefaults(s, auth)
+	if err != nil {
+		return err
+	}
+
 	// We need to specify the cols we want to update here to be able to un-archive projects
 	colsToUpdate := []string{
 		"title",
 		"is_archived",
 		"identifier",
 		"hex_color",
+		"accent_color",
+		"icon",
 		"parent_project_id",
 		"position",
 		"done_bucket_id",
 		"default_bucket_id",
+		"source_default_buckets",
+		"enforce_blocking_dependencies",
+		"priority_escalation",
+		"sla_rules",
+		"done_tasks_retention_days",
+		"done_tasks_retention_bucket_id",
+		"task_defaults",
 	}
 	if project.Description != "" {
 		colsToUpdate = append(colsToUpdate, "description")
@@ -848,6 +1040,12 @@ func UpdateProject(s *xorm.Session, project *Project, auth web.Auth, updateProje
 	}
 
 	project.HexColor = utils.NormalizeHex(project.HexColor)
+	project.AccentColor = utils.NormalizeHex(project.AccentColor)
+
+	oldProject, err := GetProjectSimpleByID(s, project.ID)
+	if err != nil {
+		return err
+	}
 
 	_, err = s.
 		ID(project.ID).
@@ -857,7 +1055,28 @@ func UpdateProject(s *xorm.Session, project *Project, auth web.Auth, updateProje
 		return err
 	}
 
-	err = events.Dispatch(&ProjectUpdatedEvent{
+	if project.Icon != "" && oldProject.IconFileID != 0 {
+		err = removeProjectIconImage(s, oldProject)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !oldProject.IsArchived && project.IsArchived {
+		err = project.applyArchiveOptions(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	if oldProject.IsArchived && !project.IsArchived {
+		err = oldProject.revertArchiveOptions(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = events.DispatchOnCommit(s, &ProjectUpdatedEvent{
 		Project: project,
 		Doer:    auth,
 	})
@@ -918,6 +1137,11 @@ func recalculateProjectPositions(s *xorm.Session, parentProjectID int64) (err er
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{id} [post]
 func (p *Project) Update(s *xorm.Session, a web.Auth) (err error) {
+	err = CheckUnmodifiedSince(s, p, p.ID, p.unmodifiedSince)
+	if err != nil {
+		return err
+	}
+
 	fid := getSavedFilterIDFromProjectID(p.ID)
 	if fid > 0 {
 		f, err := getSavedFilterSimpleByID(s, fid)
@@ -1006,6 +1230,12 @@ func (p *Project) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return &ErrCannotDeleteDefaultProject{ProjectID: p.ID}
 	}
 
+	// Needs to happen before anything is deleted to know who had access to the project
+	err = recordProjectTombstone(s, p)
+	if err != nil {
+		return err
+	}
+
 	// Delete all tasks on that project
 	// Using the loop to make sure all related entities to all tasks are properly deleted as well.
 	tasks, _, _, err := getRawTasksForProjects(s, []*Project{p}, a, &taskSearchOptions{})
@@ -1030,6 +1260,11 @@ func (p *Project) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	err = fullProject.DeleteIconFileIfExists()
+	if err != nil {
+		return
+	}
+
 	// If we're deleting a default project, remove it as default
 	if isDefaultProject {
 		_, err = s.Where("default_project_id = ?", p.ID).
@@ -1040,13 +1275,63 @@ func (p *Project) Delete(s *xorm.Session, a web.Auth) (err error) {
 		}
 	}
 
+	err = deleteCustomFieldsForProject(s, p.ID)
+	if err != nil {
+		return
+	}
+
+	err = deleteProjectStatusesForProject(s, p.ID)
+	if err != nil {
+		return
+	}
+
+	err = deleteProjectLabelRulesForProject(s, p.ID)
+	if err != nil {
+		return
+	}
+
+	err = deleteAutomationRulesForProject(s, p.ID)
+	if err != nil {
+		return
+	}
+
+	err = deleteScheduledAutomationsForProject(s, p.ID)
+	if err != nil {
+		return
+	}
+
+	err = deleteProjectIntegrationsForProject(s, p.ID)
+	if err != nil {
+		return
+	}
+
+	err = deleteMilestonesForProject(s, p.ID)
+	if err != nil {
+		return
+	}
+
+	err = deleteProjectRolesForProject(s, p.ID)
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&ProjectUserPosition{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&ProjectEmailAddress{})
+	if err != nil {
+		return
+	}
+
 	// Delete the project
 	_, err = s.ID(p.ID).Delete(&Project{})
 	if err != nil {
 		return
 	}
 
-	err = events.Dispatch(&ProjectDeletedEvent{
+	err = events.DispatchOnCommit(s, &ProjectDeletedEvent{
 		Project: fullProject,
 		Doer:    a,
 	})
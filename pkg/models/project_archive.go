@@ -0,0 +1,259 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// ProjectArchiveOptions controls what happens when a project is archived.
+// The options are sent together with `is_archived` and are reverted when the project is un-archived.
+type ProjectArchiveOptions struct {
+	// If true, all child projects which are not archived yet are archived together with the project, using the same options.
+	ArchiveChildProjects bool `json:"archive_child_projects"`
+	// If true, all undone tasks are marked as on hold while the project is archived.
+	HoldOpenTasks bool `json:"hold_open_tasks"`
+	// If true, no reminders are sent for tasks of the project while it is archived.
+	PauseReminders bool `json:"pause_reminders"`
+	// If true, repeating tasks skip all repetitions which would have been due while the project was archived
+	// when it is un-archived. Only applies to tasks repeating in an interval or monthly.
+	PauseRecurringTasks bool `json:"pause_recurring_tasks"`
+	// If true, no notifications are sent about tasks of the project while it is archived. This includes reminders.
+	SuppressNotifications bool `json:"suppress_notifications"`
+	// The ids of the child projects which were archived together with this project. You cannot change this value.
+	ArchivedChildProjectIDs []int64 `json:"archived_child_project_ids"`
+}
+
+func (p *Project) remindersPaused() bool {
+	return p.IsArchived && p.ArchiveOptions != nil && (p.ArchiveOptions.PauseReminders || p.ArchiveOptions.SuppressNotifications)
+}
+
+// notificationsSuppressedForProject checks if the project was archived with the option to suppress all notifications
+func notificationsSuppressedForProject(s *xorm.Session, projectID int64) (bool, error) {
+	project, err := GetProjectSimpleByID(s, projectID)
+	if err != nil {
+		if IsErrProjectDoesNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return project.IsArchived && project.ArchiveOptions != nil && project.ArchiveOptions.SuppressNotifications, nil
+}
+
+// getAllChildProjectIDs returns the ids of all projects below the project, no matter how deep they are nested
+func getAllChildProjectIDs(s *xorm.Session, projectID int64) (ids []int64, err error) {
+	parents := []int64{projectID}
+	for len(parents) > 0 {
+		children := []int64{}
+		err = s.
+			Table("projects").
+			In("parent_project_id", parents).
+			Cols("id").
+			Find(&children)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, children...)
+		parents = children
+	}
+	return
+}
+
+// applyArchiveOptions runs all actions of the archive options after the project was archived.
+func (p *Project) applyArchiveOptions(s *xorm.Session) (err error) {
+	opts := p.ArchiveOptions
+	if opts == nil {
+		return nil
+	}
+
+	opts.ArchivedChildProjectIDs = []int64{}
+	if opts.ArchiveChildProjects {
+		childIDs, err := getAllChildProjectIDs(s, p.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(childIDs) > 0 {
+			err = s.
+				Table("projects").
+				Where(builder.And(
+					builder.In("id", childIDs),
+					builder.Eq{"is_archived": false},
+				)).
+				Cols("id").
+				Find(&opts.ArchivedChildProjectIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(opts.ArchivedChildProjectIDs) > 0 {
+			childOpts := *opts
+			childOpts.ArchiveChildProjects = false
+			childOpts.ArchivedChildProjectIDs = nil
+			_, err = s.
+				In("id", opts.ArchivedChildProjectIDs).
+				Cols("is_archived", "archive_options").
+				Update(&Project{IsArchived: true, ArchiveOptions: &childOpts})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.HoldOpenTasks {
+		projectIDs := append([]int64{p.ID}, opts.ArchivedChildProjectIDs...)
+		_, err = s.
+			Where(builder.And(
+				builder.In("project_id", projectIDs),
+				builder.Eq{"done": false},
+			)).
+			Cols("on_hold").
+			NoAutoTime().
+			Update(&Task{OnHold: true})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.
+		Where("id = ?", p.ID).
+		Cols("archive_options").
+		Update(&Project{ArchiveOptions: opts})
+	return
+}
+
+// revertArchiveOptions reverts the actions of the archive options the project was archived with.
+// It needs to be called with the project as it was before it was un-archived.
+func (p *Project) revertArchiveOptions(s *xorm.Session) (err error) {
+	opts := p.ArchiveOptions
+	if opts == nil {
+		return nil
+	}
+
+	projectIDs := append([]int64{p.ID}, opts.ArchivedChildProjectIDs...)
+
+	if len(opts.ArchivedChildProjectIDs) > 0 {
+		_, err = s.
+			In("id", opts.ArchivedChildProjectIDs).
+			Cols("is_archived").
+			Update(&Project{IsArchived: false})
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.HoldOpenTasks {
+		_, err = s.
+			In("project_id", projectIDs).
+			Cols("on_hold").
+			NoAutoTime().
+			Update(&Task{OnHold: false})
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.PauseRecurringTasks {
+		err = skipMissedRepetitions(s, projectIDs, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.
+		Table("projects").
+		In("id", projectIDs).
+		Update(map[string]interface{}{"archive_options": nil})
+	return
+}
+
+// skipMissedRepetitions moves the dates of all undone repeating tasks of the projects whose due date passed
+// forward to the first repetition in the future.
+func skipMissedRepetitions(s *xorm.Session, projectIDs []int64, now time.Time) (err error) {
+	tasks := []*Task{}
+	err = s.
+		Where(builder.And(
+			builder.In("project_id", projectIDs),
+			builder.Eq{"done": false},
+			builder.NotNull{"due_date"},
+			builder.Lt{"due_date": now},
+			builder.Or(
+				builder.Gt{"repeat_after": 0},
+				builder.Eq{"repeat_mode": TaskRepeatModeMonth},
+			),
+		)).
+		Find(&tasks)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		next := func(d time.Time) time.Time {
+			if t.RepeatMode == TaskRepeatModeMonth {
+				return addOneMonthToDate(d)
+			}
+			return d.Add(time.Duration(t.RepeatAfter) * time.Second)
+		}
+
+		// All dates of the task are moved by the same number of repetitions to keep their difference
+		repetitions := 0
+		for due := t.DueDate; !due.After(now); due = next(due) {
+			repetitions++
+		}
+		move := func(d time.Time) time.Time {
+			if d.IsZero() {
+				return d
+			}
+			for i := 0; i < repetitions; i++ {
+				d = next(d)
+			}
+			return d
+		}
+
+		t.DueDate = move(t.DueDate)
+		t.StartDate = move(t.StartDate)
+		t.EndDate = move(t.EndDate)
+		_, err = s.
+			ID(t.ID).
+			Cols("due_date", "start_date", "end_date").
+			Update(t)
+		if err != nil {
+			return err
+		}
+
+		reminders := []*TaskReminder{}
+		err = s.Where("task_id = ?", t.ID).Find(&reminders)
+		if err != nil {
+			return err
+		}
+		for _, r := range reminders {
+			r.Reminder = move(r.Reminder)
+			r.updateEscalationDate()
+			_, err = s.ID(r.ID).Cols("reminder", "escalates_at").Update(r)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func unarchiveProject22(t *testing.T, s *xorm.Session) {
+	// Project 22 is archived in the fixtures and the parent of project 21
+	_, err := s.
+		Where("id = ?", 22).
+		Cols("is_archived").
+		Update(&Project{IsArchived: false})
+	require.NoError(t, err)
+}
+
+func TestProject_Update_ArchiveOptions(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("archive and unarchive with options", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		unarchiveProject22(t, s)
+
+		_, err := s.
+			Where("id = ?", 36).
+			Cols("repeat_after").
+			Update(&Task{RepeatAfter: 7 * 24 * 60 * 60})
+		require.NoError(t, err)
+
+		project := &Project{
+			ID:         22,
+			Title:      "Test22",
+			IsArchived: true,
+			ArchiveOptions: &ProjectArchiveOptions{
+				ArchiveChildProjects:  true,
+				HoldOpenTasks:         true,
+				PauseRecurringTasks:   true,
+				SuppressNotifications: true,
+			},
+		}
+		err = project.Update(s, u)
+		require.NoError(t, err)
+
+		child, err := GetProjectSimpleByID(s, 21)
+		require.NoError(t, err)
+		assert.True(t, child.IsArchived)
+		require.NotNil(t, child.ArchiveOptions)
+		assert.True(t, child.ArchiveOptions.HoldOpenTasks)
+
+		archived, err := GetProjectSimpleByID(s, 22)
+		require.NoError(t, err)
+		require.NotNil(t, archived.ArchiveOptions)
+		assert.Equal(t, []int64{21}, archived.ArchiveOptions.ArchivedChildProjectIDs)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":      35,
+			"on_hold": true,
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":      36,
+			"on_hold": true,
+		}, false)
+		// Done tasks are not put on hold
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":      38,
+			"on_hold": false,
+		}, false)
+
+		suppressed, err := notificationsSuppressedForProject(s, 21)
+		require.NoError(t, err)
+		assert.True(t, suppressed)
+
+		project = &Project{
+			ID:         22,
+			Title:      "Test22",
+			IsArchived: false,
+		}
+		err = project.Update(s, u)
+		require.NoError(t, err)
+
+		child, err = GetProjectSimpleByID(s, 21)
+		require.NoError(t, err)
+		assert.False(t, child.IsArchived)
+		assert.Nil(t, child.ArchiveOptions)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":      35,
+			"on_hold": false,
+		}, false)
+
+		task, err := GetTaskByIDSimple(s, 36)
+		require.NoError(t, err)
+		assert.False(t, task.OnHold)
+		assert.True(t, task.DueDate.After(time.Now()))
+		assert.True(t, task.DueDate.Before(time.Now().Add(7*24*time.Hour)))
+
+		suppressed, err = notificationsSuppressedForProject(s, 22)
+		require.NoError(t, err)
+		assert.False(t, suppressed)
+	})
+	t.Run("archive without options", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		unarchiveProject22(t, s)
+
+		project := &Project{
+			ID:         22,
+			Title:      "Test22",
+			IsArchived: true,
+		}
+		err := project.Update(s, u)
+		require.NoError(t, err)
+
+		child, err := GetProjectSimpleByID(s, 21)
+		require.NoError(t, err)
+		assert.False(t, child.IsArchived)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":      36,
+			"on_hold": false,
+		}, false)
+	})
+	t.Run("child projects which are already archived", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		unarchiveProject22(t, s)
+
+		_, err := s.
+			Where("id = ?", 21).
+			Cols("is_archived").
+			Update(&Project{IsArchived: true})
+		require.NoError(t, err)
+
+		project := &Project{
+			ID:             22,
+			Title:          "Test22",
+			IsArchived:     true,
+			ArchiveOptions: &ProjectArchiveOptions{ArchiveChildProjects: true},
+		}
+		err = project.Update(s, u)
+		require.NoError(t, err)
+
+		project = &Project{
+			ID:    22,
+			Title: "Test22",
+		}
+		err = project.Update(s, u)
+		require.NoError(t, err)
+
+		// The child was archived before, so it stays archived
+		child, err := GetProjectSimpleByID(s, 21)
+		require.NoError(t, err)
+		assert.True(t, child.IsArchived)
+	})
+}
+
+func TestProject_RemindersPaused(t *testing.T) {
+	p := &Project{IsArchived: true}
+	assert.False(t, p.remindersPaused())
+
+	p.ArchiveOptions = &ProjectArchiveOptions{PauseReminders: true}
+	assert.True(t, p.remindersPaused())
+
+	p.ArchiveOptions = &ProjectArchiveOptions{SuppressNotifications: true}
+	assert.True(t, p.remindersPaused())
+
+	p.IsArchived = false
+	assert.False(t, p.remindersPaused())
+}
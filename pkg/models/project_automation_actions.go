@@ -0,0 +1,334 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/version"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// matches checks if the task matches the filter of the rule. A rule without a filter matches all tasks.
+func (r *ProjectAutomationRule) matches(s *xorm.Session, taskID int64) (bool, error) {
+	return taskMatchesFilter(s, taskID, r.Filter)
+}
+
+// runAutomationRules runs all enabled rules of the project of a task with the given trigger against the task.
+// The trigger value is the bucket id the task was moved into or the id of the label which was added.
+func runAutomationRules(s *xorm.Session, taskID int64, trigger AutomationTrigger, triggerValue int64, now time.Time) (err error) {
+	task, err := GetTaskByIDSimple(s, taskID)
+	if err != nil {
+		return err
+	}
+
+	rules := []*ProjectAutomationRule{}
+	err = s.
+		Where("project_id = ? AND trigger_event = ? AND enabled = ?", task.ProjectID, trigger, true).
+		And(builder.Or(builder.Eq{"trigger_value": 0}, builder.Eq{"trigger_value": triggerValue})).
+		OrderBy("id asc").
+		Find(&rules)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	for _, rule := range rules {
+		// A rule which became invalid or whose creator lost access should not prevent the other rules from running
+		err = rule.run(s, &task, now)
+		if err != nil {
+			log.Errorf("Could not run automation rule %d for task %d: %s", rule.ID, task.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// run executes all actions of the rule on behalf of its creator if the task matches the rule's filter.
+func (r *ProjectAutomationRule) run(s *xorm.Session, task *Task, now time.Time) (err error) {
+	creator, err := user.GetUserByID(s, r.CreatedByID)
+	if err != nil {
+		return err
+	}
+
+	can, err := (&Task{ID: task.ID}).CanUpdate(s, creator)
+	if err != nil {
+		return err
+	}
+	if !can {
+		return ErrGenericForbidden{}
+	}
+
+	matches, err := r.matches(s, task.ID)
+	if err != nil || !matches {
+		return err
+	}
+
+	for _, action := range r.Actions {
+		err = r.runAction(s, action, task, creator, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return updateProjectLastUpdated(s, &Project{ID: task.ProjectID})
+}
+
+// runAction executes a single action of a rule. Actions never dispatch the events automation rules are triggered by,
+// which prevents rules from triggering each other in a loop.
+func (r *ProjectAutomationRule) runAction(s *xorm.Session, action *AutomationAction, task *Task, doer *user.User, now time.Time) (err error) {
+	switch action.Kind {
+	case AutomationActionAddAssignee:
+		project, err := GetProjectSimpleByID(s, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		err = task.addNewAssigneeByID(s, action.Value, project, doer)
+		if err != nil && !IsErrUserAlreadyAssigned(err) {
+			return err
+		}
+	case AutomationActionAddLabel:
+		exists, err := s.Exist(&LabelTask{LabelID: action.Value, TaskID: task.ID})
+		if err != nil || exists {
+			return err
+		}
+		// The label might have been deleted since the rule was created
+		exists, err = s.Where("id = ?", action.Value).Exist(&Label{})
+		if err != nil || !exists {
+			return err
+		}
+		_, err = s.Insert(&LabelTask{LabelID: action.Value, TaskID: task.ID})
+		if err != nil {
+			return err
+		}
+	case AutomationActionMoveToBucket:
+		return r.moveTaskToBucket(s, task, action.Value)
+	case AutomationActionSetDueDate:
+		task.DueDate = now.Add(time.Duration(action.Value) * time.Second)
+		_, err = s.
+			Where("id = ?", task.ID).
+			Cols("due_date").
+			Update(task)
+		if err != nil {
+			return err
+		}
+	case AutomationActionAddComment:
+		comment := &TaskComment{
+			TaskID:  task.ID,
+			Comment: action.Text,
+		}
+		return comment.Create(s, doer)
+	case AutomationActionCallWebhook:
+		return r.callWebhook(action.Text, task)
+	}
+
+	return nil
+}
+
+func (r *ProjectAutomationRule) moveTaskToBucket(s *xorm.Session, task *Task, bucketID int64) (err error) {
+	if task.BucketID == bucketID {
+		return nil
+	}
+
+	bucket, err := getBucketByID(s, bucketID)
+	if err != nil {
+		return err
+	}
+	err = checkBucketAndTaskBelongToSameProject(task, bucket)
+	if err != nil {
+		return err
+	}
+	err = checkBucketLimit(s, task, bucket)
+	if err != nil {
+		return err
+	}
+
+	project, err := GetProjectSimpleByID(s, task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	oldBucketID := task.BucketID
+	task.BucketID = bucket.ID
+	cols := []string{"bucket_id"}
+	if bucket.ID == project.DoneBucketID && !task.Done {
+		task.Done = true
+		task.DoneAt = time.Now()
+		cols = append(cols, "done", "done_at")
+	}
+
+	_, err = s.
+		Where("id = ?", task.ID).
+		Cols(cols...).
+		Update(task)
+	if err != nil {
+		return err
+	}
+
+	return recordBucketTransition(s, task.ID, task.ProjectID, oldBucketID, task.ProjectID, bucket.ID)
+}
+
+func (r *ProjectAutomationRule) callWebhook(targetURL string, task *Task) (err error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"rule_id": r.ID,
+		"trigger": r.Trigger,
+		"task":    task,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, targetURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("User-Agent", "Vikunja/"+version.Version)
+
+	res, err := getWebhookHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("automation webhook returned status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// RunAutomationRules represents a listener
+type RunAutomationRules struct {
+}
+
+// Name defines the name for the RunAutomationRules listener
+func (l *RunAutomationRules) Name() string {
+	return "task.run.automation.rules"
+}
+
+// Handle is executed when the event RunAutomationRules listens on is fired
+func (l *RunAutomationRules) Handle(msg *message.Message) (err error) {
+	// Task created, moved and label created events all contain the task, the latter two also what triggered them
+	event := &struct {
+		Task        *Task  `json:"task"`
+		Label       *Label `json:"label"`
+		NewBucketID int64  `json:"new_bucket_id"`
+	}{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+	if event.Task == nil {
+		return nil
+	}
+
+	var (
+		trigger = AutomationTriggerTaskCreated
+		value   int64
+	)
+	switch {
+	case event.Label != nil:
+		trigger = AutomationTriggerLabelAdded
+		value = event.Label.ID
+	case event.NewBucketID != 0:
+		trigger = AutomationTriggerTaskMoved
+		value = event.NewBucketID
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	err = runAutomationRules(s, event.Task.ID, trigger, value, time.Now())
+	if err != nil {
+		if IsErrTaskDoesNotExist(err) {
+			return nil
+		}
+		_ = s.Rollback()
+		return err
+	}
+
+	return s.Commit()
+}
+
+// runDueAutomationRules runs the rules with the due trigger for all undone tasks which became due in the minute
+// before now.
+func runDueAutomationRules(s *xorm.Session, now time.Time) (ran int, err error) {
+	now = now.Truncate(time.Minute)
+
+	tasks := []*Task{}
+	err = s.
+		Where("due_date > ? AND due_date <= ? AND done = ?", now.Add(-time.Minute), now, false).
+		And(builder.In("project_id", builder.
+			Select("project_id").
+			From("project_automation_rules").
+			Where(builder.Eq{"trigger_event": AutomationTriggerTaskDue, "enabled": true}))).
+		Find(&tasks)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, task := range tasks {
+		err = runAutomationRules(s, task.ID, AutomationTriggerTaskDue, 0, now)
+		if err != nil {
+			return ran, err
+		}
+		ran++
+	}
+
+	return ran, nil
+}
+
+// RegisterAutomationDueCron registers a cron function which runs automation rules for tasks when they become due.
+func RegisterAutomationDueCron() {
+	const logPrefix = "[Automation Due Cron] "
+
+	err := cron.Schedule("* * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		ran, err := runDueAutomationRules(s, time.Now())
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not run automation rules for due tasks: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit automation rules for due tasks: %s", err)
+			return
+		}
+
+		if ran > 0 {
+			log.Debugf(logPrefix+"Ran automation rules for %d due tasks", ran)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register automation due cron: %s", err)
+	}
+}
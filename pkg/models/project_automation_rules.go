@@ -0,0 +1,401 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"net/url"
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// AutomationTrigger defines when an automation rule runs
+type AutomationTrigger string
+
+const (
+	// AutomationTriggerTaskCreated runs the rule when a task is created in the project.
+	AutomationTriggerTaskCreated AutomationTrigger = "task.created"
+	// AutomationTriggerTaskMoved runs the rule when a task is moved into another bucket. If the trigger value is set,
+	// only when it is moved into the bucket with that id.
+	AutomationTriggerTaskMoved AutomationTrigger = "task.moved"
+	// AutomationTriggerTaskDue runs the rule when the due date of an undone task is reached.
+	AutomationTriggerTaskDue AutomationTrigger = "task.due"
+	// AutomationTriggerLabelAdded runs the rule when a label is added to a task. If the trigger value is set,
+	// only when the label with that id is added.
+	AutomationTriggerLabelAdded AutomationTrigger = "task.label.created"
+)
+
+// AutomationActionKind defines what an automation action does with a task
+type AutomationActionKind string
+
+const (
+	// AutomationActionAddAssignee assigns the user with the id from the action value to the task.
+	AutomationActionAddAssignee AutomationActionKind = "add_assignee"
+	// AutomationActionAddLabel adds the label with the id from the action value to the task.
+	AutomationActionAddLabel AutomationActionKind = "add_label"
+	// AutomationActionMoveToBucket moves the task into the bucket with the id from the action value.
+	AutomationActionMoveToBucket AutomationActionKind = "move_to_bucket"
+	// AutomationActionSetDueDate sets the due date of the task to the time the rule ran plus the action value in seconds.
+	AutomationActionSetDueDate AutomationActionKind = "set_due_date"
+	// AutomationActionAddComment adds a comment with the action text to the task.
+	AutomationActionAddComment AutomationActionKind = "add_comment"
+	// AutomationActionCallWebhook posts the task to the url in the action text.
+	AutomationActionCallWebhook AutomationActionKind = "call_webhook"
+)
+
+// AutomationAction is something an automation rule does with a task
+type AutomationAction struct {
+	// The kind of the action. Can be `add_assignee`, `add_label`, `move_to_bucket`, `set_due_date`, `add_comment` or `call_webhook`.
+	Kind AutomationActionKind `json:"kind"`
+	// The user id for `add_assignee`, the label id for `add_label`, the bucket id for `move_to_bucket` and the offset
+	// in seconds from the time the rule ran for `set_due_date`. Ignored for all other kinds.
+	Value int64 `json:"value"`
+	// The comment for `add_comment` and the url for `call_webhook`. Ignored for all other kinds.
+	Text string `json:"text"`
+}
+
+// ProjectAutomationRule runs actions on tasks of a project when something happens with them.
+type ProjectAutomationRule struct {
+	// The unique, numeric id of this automation rule.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"automationrule"`
+	// The project this rule belongs to.
+	ProjectID int64 `xorm:"bigint not null index" json:"project_id" param:"project"`
+	// The title of the rule.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// Whether the rule runs. Defaults to true.
+	Enabled *bool `xorm:"bool not null default true" json:"enabled"`
+	// When the rule runs. Can be `task.created`, `task.moved`, `task.due` or `task.label.created`.
+	Trigger AutomationTrigger `xorm:"'trigger_event' varchar(50) not null index" json:"trigger"`
+	// The bucket id for `task.moved` and the label id for `task.label.created`. If 0, the rule runs for all buckets or labels.
+	TriggerValue int64 `xorm:"bigint not null default 0" json:"trigger_value"`
+	// An optional filter query. The rule only runs for tasks matching it. Check out https://vikunja.io/docs/filters for the syntax.
+	Filter string `xorm:"text null" json:"filter"`
+	// What the rule does with the task, in this order.
+	Actions []*AutomationAction `xorm:"JSON not null" json:"actions"`
+
+	// The user who created the rule. All actions are done on behalf of this user.
+	CreatedBy   *user.User `xorm:"-" json:"created_by"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this rule was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this rule was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project automation rules
+func (*ProjectAutomationRule) TableName() string {
+	return "project_automation_rules"
+}
+
+func getAutomationRuleByID(s *xorm.Session, id int64) (rule *ProjectAutomationRule, err error) {
+	rule = &ProjectAutomationRule{}
+	exists, err := s.Where("id = ?", id).Get(rule)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrAutomationRuleDoesNotExist{RuleID: id}
+	}
+	return rule, nil
+}
+
+// validate checks the trigger, condition and all actions of a rule. Entities referenced by the rule must be
+// accessible to the user who creates the rule, because all actions are done on behalf of them.
+func (r *ProjectAutomationRule) validate(s *xorm.Session, a web.Auth) (err error) {
+	bucketBelongsToProject := func(bucketID int64) error {
+		bucket, err := getBucketByID(s, bucketID)
+		if err != nil {
+			return err
+		}
+		if bucket.ProjectID != r.ProjectID {
+			return ErrBucketDoesNotBelongToProject{BucketID: bucket.ID, ProjectID: r.ProjectID}
+		}
+		return nil
+	}
+	canReadLabel := func(labelID int64) error {
+		label, err := getLabelByIDSimple(s, labelID)
+		if err != nil {
+			return err
+		}
+		can, _, err := label.CanRead(s, a)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return ErrUserHasNoAccessToLabel{LabelID: label.ID, UserID: a.GetID()}
+		}
+		return nil
+	}
+
+	switch r.Trigger {
+	case AutomationTriggerTaskCreated, AutomationTriggerTaskDue:
+		r.TriggerValue = 0
+	case AutomationTriggerTaskMoved:
+		if r.TriggerValue != 0 {
+			if err := bucketBelongsToProject(r.TriggerValue); err != nil {
+				return err
+			}
+		}
+	case AutomationTriggerLabelAdded:
+		if r.TriggerValue != 0 {
+			if err := canReadLabel(r.TriggerValue); err != nil {
+				return err
+			}
+		}
+	default:
+		return &ErrInvalidAutomationRule{Reason: "the trigger " + string(r.Trigger) + " does not exist"}
+	}
+
+	if r.Filter != "" {
+		if _, err := getTaskFiltersFromFilterString(r.Filter, ""); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Actions) == 0 {
+		return &ErrInvalidAutomationRule{Reason: "a rule needs at least one action"}
+	}
+
+	var project *Project
+	for _, action := range r.Actions {
+		switch action.Kind {
+		case AutomationActionAddAssignee:
+			if project == nil {
+				project, err = GetProjectSimpleByID(s, r.ProjectID)
+				if err != nil {
+					return err
+				}
+			}
+			assignee, err := user.GetUserByID(s, action.Value)
+			if err != nil {
+				return err
+			}
+			can, _, err := project.CanRead(s, assignee)
+			if err != nil {
+				return err
+			}
+			if !can {
+				return ErrUserDoesNotHaveAccessToProject{ProjectID: project.ID, UserID: assignee.ID}
+			}
+		case AutomationActionAddLabel:
+			if err := canReadLabel(action.Value); err != nil {
+				return err
+			}
+		case AutomationActionMoveToBucket:
+			if err := bucketBelongsToProject(action.Value); err != nil {
+				return err
+			}
+		case AutomationActionSetDueDate:
+			// Nothing to validate, negative offsets are allowed
+		case AutomationActionAddComment:
+			if action.Text == "" {
+				return &ErrInvalidAutomationRule{Reason: "a comment action needs a text"}
+			}
+		case AutomationActionCallWebhook:
+			u, err := url.Parse(action.Text)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return &ErrInvalidAutomationRule{Reason: "a webhook action needs an http or https url"}
+			}
+		default:
+			return &ErrInvalidAutomationRule{Reason: "the action " + string(action.Kind) + " does not exist"}
+		}
+	}
+
+	return nil
+}
+
+// Create creates a new automation rule
+// @Summary Create an automation rule
+// @Description Creates a new automation rule in a project. All actions of the rule are done on behalf of the current user.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param rule body models.ProjectAutomationRule true "The automation rule"
+// @Success 201 {object} models.ProjectAutomationRule "The created automation rule."
+// @Failure 400 {object} web.HTTPError "Invalid automation rule provided."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/automations [put]
+func (r *ProjectAutomationRule) Create(s *xorm.Session, a web.Auth) (err error) {
+	err = r.validate(s, a)
+	if err != nil {
+		return
+	}
+
+	if r.Enabled == nil {
+		enabled := true
+		r.Enabled = &enabled
+	}
+
+	r.ID = 0
+	r.CreatedByID = a.GetID()
+	_, err = s.Insert(r)
+	if err != nil {
+		return
+	}
+
+	return addCreatorsToAutomationRules(s, []*ProjectAutomationRule{r})
+}
+
+// ReadAll returns all automation rules of a project
+// @Summary Get all automation rules of a project
+// @Description Returns all automation rules of a project in the order they are run.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {array} models.ProjectAutomationRule "The automation rules."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/automations [get]
+func (r *ProjectAutomationRule) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	project := &Project{ID: r.ProjectID}
+	canRead, _, err := project.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	rules := []*ProjectAutomationRule{}
+	err = s.
+		Where("project_id = ?", r.ProjectID).
+		OrderBy("id asc").
+		Find(&rules)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addCreatorsToAutomationRules(s, rules)
+	return rules, len(rules), int64(len(rules)), err
+}
+
+// ReadOne returns one automation rule
+// @Summary Get one automation rule
+// @Description Returns one automation rule of a project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param ruleID path int true "Automation rule ID"
+// @Success 200 {object} models.ProjectAutomationRule "The automation rule."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The automation rule does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/automations/{ruleID} [get]
+func (r *ProjectAutomationRule) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	rule, err := getAutomationRuleByID(s, r.ID)
+	if err != nil {
+		return err
+	}
+	*r = *rule
+	return addCreatorsToAutomationRules(s, []*ProjectAutomationRule{r})
+}
+
+// Update updates an automation rule
+// @Summary Update an automation rule
+// @Description Updates the title, trigger, filter and actions of an automation rule or enables and disables it. The actions are still done on behalf of the user who created the rule.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param ruleID path int true "Automation rule ID"
+// @Param rule body models.ProjectAutomationRule true "The automation rule"
+// @Success 200 {object} models.ProjectAutomationRule "The updated automation rule."
+// @Failure 400 {object} web.HTTPError "Invalid automation rule provided."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the project."
+// @Failure 404 {object} web.HTTPError "The automation rule does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/automations/{ruleID} [post]
+func (r *ProjectAutomationRule) Update(s *xorm.Session, a web.Auth) (err error) {
+	err = r.validate(s, a)
+	if err != nil {
+		return
+	}
+
+	cols := []string{"title", "trigger_event", "trigger_value", "filter", "actions"}
+	if r.Enabled != nil {
+		cols = append(cols, "enabled")
+	}
+
+	_, err = s.
+		Where("id = ?", r.ID).
+		Cols(cols...).
+		Update(r)
+	if err != nil {
+		return
+	}
+
+	return r.ReadOne(s, a)
+}
+
+// Delete deletes an automation rule
+// @Summary Delete an automation rule
+// @Description Deletes an automation rule. Changes it already made to tasks are kept.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param ruleID path int true "Automation rule ID"
+// @Success 200 {object} models.Message "The automation rule was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the project."
+// @Failure 404 {object} web.HTTPError "The automation rule does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/automations/{ruleID} [delete]
+func (r *ProjectAutomationRule) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("id = ?", r.ID).Delete(&ProjectAutomationRule{})
+	return
+}
+
+func deleteAutomationRulesForProject(s *xorm.Session, projectID int64) (err error) {
+	_, err = s.Where("project_id = ?", projectID).Delete(&ProjectAutomationRule{})
+	return
+}
+
+func addCreatorsToAutomationRules(s *xorm.Session, rules []*ProjectAutomationRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(rules))
+	for _, r := range rules {
+		userIDs = append(userIDs, r.CreatedByID)
+	}
+
+	users, err := user.GetUsersByIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rules {
+		r.CreatedBy = users[r.CreatedByID]
+	}
+	return nil
+}
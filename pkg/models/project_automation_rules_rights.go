@@ -0,0 +1,84 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see an automation rule
+func (r *ProjectAutomationRule) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	rule, err := r.getForProject(s)
+	if err != nil {
+		return false, 0, err
+	}
+
+	project := &Project{ID: rule.ProjectID}
+	return project.CanRead(s, a)
+}
+
+// CanCreate checks if the user can create an automation rule in a project
+func (r *ProjectAutomationRule) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	// Actions of automation rules are done on behalf of the user who created them
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	if getSavedFilterIDFromProjectID(r.ProjectID) > 0 {
+		return false, nil
+	}
+
+	project := &Project{ID: r.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// CanUpdate checks if the user can update an automation rule
+func (r *ProjectAutomationRule) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return r.canDoAutomationRule(s, a)
+}
+
+// CanDelete checks if the user can delete an automation rule
+func (r *ProjectAutomationRule) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return r.canDoAutomationRule(s, a)
+}
+
+func (r *ProjectAutomationRule) canDoAutomationRule(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	rule, err := r.getForProject(s)
+	if err != nil {
+		return false, err
+	}
+
+	project := &Project{ID: rule.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// getForProject returns the rule and makes sure it belongs to the project from the request
+func (r *ProjectAutomationRule) getForProject(s *xorm.Session) (*ProjectAutomationRule, error) {
+	rule, err := getAutomationRuleByID(s, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	if r.ProjectID != 0 && rule.ProjectID != r.ProjectID {
+		return nil, &ErrAutomationRuleDoesNotExist{RuleID: r.ID}
+	}
+	return rule, nil
+}
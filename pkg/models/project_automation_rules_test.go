@@ -0,0 +1,323 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectAutomationRule_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectAutomationRule{
+			ProjectID:    1,
+			Title:        "Done",
+			Trigger:      AutomationTriggerLabelAdded,
+			TriggerValue: 2,
+			Filter:       "done = false",
+			Actions:      []*AutomationAction{{Kind: AutomationActionMoveToBucket, Value: 3}},
+		}
+		can, err := r.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = r.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		assert.True(t, *r.Enabled)
+		assert.Equal(t, int64(1), r.CreatedBy.ID)
+
+		db.AssertExists(t, "project_automation_rules", map[string]interface{}{
+			"id":            r.ID,
+			"project_id":    1,
+			"trigger_event": "task.label.created",
+			"trigger_value": 2,
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectAutomationRule{ProjectID: 1}
+		can, err := r.CanCreate(s, &LinkSharing{ID: 2, ProjectID: 1, Right: RightAdmin})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("invalid trigger", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectAutomationRule{
+			ProjectID: 1,
+			Title:     "Invalid",
+			Trigger:   "task.exploded",
+			Actions:   []*AutomationAction{{Kind: AutomationActionAddComment, Text: "Boom"}},
+		}
+		err := r.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidAutomationRule(err))
+	})
+	t.Run("no actions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectAutomationRule{ProjectID: 1, Title: "Nothing", Trigger: AutomationTriggerTaskCreated}
+		err := r.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidAutomationRule(err))
+	})
+	t.Run("invalid filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectAutomationRule{
+			ProjectID: 1,
+			Title:     "Invalid",
+			Trigger:   AutomationTriggerTaskCreated,
+			Filter:    "foo = bar",
+			Actions:   []*AutomationAction{{Kind: AutomationActionAddComment, Text: "Hi"}},
+		}
+		err := r.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskField(err))
+	})
+	t.Run("bucket of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectAutomationRule{
+			ProjectID: 1,
+			Title:     "Move",
+			Trigger:   AutomationTriggerTaskCreated,
+			Actions:   []*AutomationAction{{Kind: AutomationActionMoveToBucket, Value: 4}},
+		}
+		err := r.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBucketDoesNotBelongToProject(err))
+	})
+	t.Run("label without access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectAutomationRule{
+			ProjectID: 1,
+			Title:     "Label",
+			Trigger:   AutomationTriggerTaskCreated,
+			Actions:   []*AutomationAction{{Kind: AutomationActionAddLabel, Value: 3}},
+		}
+		err := r.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserHasNoAccessToLabel(err))
+	})
+	t.Run("invalid webhook url", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectAutomationRule{
+			ProjectID: 1,
+			Title:     "Webhook",
+			Trigger:   AutomationTriggerTaskCreated,
+			Actions:   []*AutomationAction{{Kind: AutomationActionCallWebhook, Text: "ftp://example.com"}},
+		}
+		err := r.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidAutomationRule(err))
+	})
+}
+
+func TestProjectAutomationRule_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	r := &ProjectAutomationRule{ProjectID: 1}
+	result, _, _, err := r.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+	require.NoError(t, err)
+	rules := result.([]*ProjectAutomationRule)
+	require.Len(t, rules, 3)
+	assert.Equal(t, AutomationTriggerTaskCreated, rules[0].Trigger)
+	require.Len(t, rules[1].Actions, 2)
+	assert.Equal(t, AutomationActionAddComment, rules[1].Actions[0].Kind)
+	assert.False(t, *rules[2].Enabled)
+
+	_, _, _, err = r.ReadAll(s, &user.User{ID: 13}, "", 0, 0)
+	require.Error(t, err)
+	assert.True(t, IsErrGenericForbidden(err))
+}
+
+func TestProjectAutomationRule_CanRead(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	// Rule 4 belongs to project 2
+	r := &ProjectAutomationRule{ID: 4, ProjectID: 1}
+	_, _, err := r.CanRead(s, &user.User{ID: 1})
+	require.Error(t, err)
+	assert.True(t, IsErrAutomationRuleDoesNotExist(err))
+}
+
+func TestRunAutomationRules(t *testing.T) {
+	now := time.Date(2018, 12, 1, 4, 0, 0, 0, time.UTC)
+
+	t.Run("task created", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := runAutomationRules(s, 1, AutomationTriggerTaskCreated, 0, now)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_assignees", map[string]interface{}{
+			"task_id": 1,
+			"user_id": 1,
+		}, false)
+		// Rule 3 is disabled
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		assert.True(t, task.DueDate.IsZero())
+	})
+	t.Run("task moved into the trigger bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := runAutomationRules(s, 3, AutomationTriggerTaskMoved, 2, now)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_comments", map[string]interface{}{
+			"task_id":   3,
+			"author_id": 1,
+			"comment":   "Moved by automation",
+		}, false)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  3,
+			"label_id": 1,
+		}, false)
+	})
+	t.Run("task moved into another bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := runAutomationRules(s, 3, AutomationTriggerTaskMoved, 3, now)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_comments", map[string]interface{}{
+			"task_id": 3,
+			"comment": "Moved by automation",
+		})
+	})
+	t.Run("task not matching the filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := runAutomationRules(s, 1, AutomationTriggerTaskMoved, 2, now)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_comments", map[string]interface{}{
+			"task_id": 1,
+			"comment": "Moved by automation",
+		})
+	})
+	t.Run("move into done bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rule := &ProjectAutomationRule{
+			ProjectID: 1,
+			Title:     "Done when labeled",
+			Trigger:   AutomationTriggerLabelAdded,
+			Actions:   []*AutomationAction{{Kind: AutomationActionMoveToBucket, Value: 3}},
+		}
+		err := rule.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+
+		err = runAutomationRules(s, 1, AutomationTriggerLabelAdded, 2, now)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        1,
+			"bucket_id": 3,
+			"done":      true,
+		}, false)
+		db.AssertExists(t, "task_bucket_transitions", map[string]interface{}{
+			"task_id":        1,
+			"from_bucket_id": 1,
+			"to_bucket_id":   3,
+		}, false)
+	})
+}
+
+func TestRunDueAutomationRules(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	rule := &ProjectAutomationRule{
+		ProjectID: 1,
+		Title:     "Label due tasks",
+		Trigger:   AutomationTriggerTaskDue,
+		Actions:   []*AutomationAction{{Kind: AutomationActionAddLabel, Value: 2}},
+	}
+	err := rule.Create(s, &user.User{ID: 1})
+	require.NoError(t, err)
+
+	// Task 5 is due at 2018-12-01 03:58:44
+	ran, err := runDueAutomationRules(s, time.Date(2018, 12, 1, 3, 59, 10, 0, time.Local))
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+
+	db.AssertExists(t, "label_tasks", map[string]interface{}{
+		"task_id":  5,
+		"label_id": 2,
+	}, false)
+}
@@ -0,0 +1,51 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// DownloadProjectBackground downloads an image from an http or https url to use it as background of a project.
+// Only public addresses can be reached. It returns the content of the image and a file name for it.
+func DownloadProjectBackground(projectID int64, imageURL string) (content []byte, name string, err error) {
+	u, err := url.Parse(imageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, "", &ErrInvalidProjectBackgroundURL{URL: imageURL}
+	}
+
+	content, err = downloadRemoteFile(imageURL, &ErrInvalidProjectBackgroundURL{URL: imageURL})
+	if err != nil {
+		return nil, "", err
+	}
+
+	mime := mimetype.Detect(content)
+	if !strings.HasPrefix(mime.String(), "image/") {
+		return nil, "", &ErrProjectBackgroundIsNotAnImage{ProjectID: projectID, Mime: mime.String()}
+	}
+
+	name = fileNameFromURL(u)
+	if path.Ext(name) == "" {
+		name += mime.Extension()
+	}
+
+	return content, name, nil
+}
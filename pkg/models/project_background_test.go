@@ -0,0 +1,99 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadProjectBackground(t *testing.T) {
+	img := &bytes.Buffer{}
+	err := png.Encode(img, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/background.png", "/background":
+			_, _ = w.Write(img.Bytes())
+		case "/text":
+			_, _ = w.Write([]byte("not an image"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	allowLocal := func(t *testing.T) {
+		remoteFileAllowNonPublicAddresses = true
+		remoteFileClient = nil
+		t.Cleanup(func() {
+			remoteFileAllowNonPublicAddresses = false
+			// Don't reuse connections which were allowed before
+			remoteFileClient = nil
+		})
+	}
+
+	t.Run("normal", func(t *testing.T) {
+		allowLocal(t)
+		content, name, err := DownloadProjectBackground(1, server.URL+"/background.png")
+		require.NoError(t, err)
+		assert.Equal(t, img.Bytes(), content)
+		assert.Equal(t, "background.png", name)
+	})
+	t.Run("without extension", func(t *testing.T) {
+		allowLocal(t)
+		_, name, err := DownloadProjectBackground(1, server.URL+"/background")
+		require.NoError(t, err)
+		assert.Equal(t, "background.png", name)
+	})
+	t.Run("not an image", func(t *testing.T) {
+		allowLocal(t)
+		_, _, err := DownloadProjectBackground(1, server.URL+"/text")
+		require.Error(t, err)
+		assert.True(t, IsErrProjectBackgroundIsNotAnImage(err))
+	})
+	t.Run("not found", func(t *testing.T) {
+		allowLocal(t)
+		_, _, err := DownloadProjectBackground(1, server.URL+"/missing.png")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidProjectBackgroundURL(err))
+	})
+	t.Run("invalid scheme", func(t *testing.T) {
+		_, _, err := DownloadProjectBackground(1, "file:///etc/passwd")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidProjectBackgroundURL(err))
+	})
+	t.Run("non-public addresses", func(t *testing.T) {
+		for _, backgroundURL := range []string{
+			server.URL + "/background.png",
+			"http://127.0.0.1/background.png",
+			"http://169.254.169.254/latest/meta-data/",
+		} {
+			_, _, err := DownloadProjectBackground(1, backgroundURL)
+			require.Error(t, err, backgroundURL)
+			assert.True(t, IsErrInvalidProjectBackgroundURL(err), backgroundURL)
+		}
+	})
+}
@@ -19,8 +19,10 @@ package models
 import (
 	"code.vikunja.io/api/pkg/files"
 	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/api/pkg/utils"
 	"code.vikunja.io/web"
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -31,6 +33,21 @@ type ProjectDuplicate struct {
 	// The target parent project
 	ParentProjectID int64 `json:"parent_project_id,omitempty"`
 
+	// Whether to copy the kanban configuration: the actions, sort modes and move permissions of all buckets, the default
+	// buckets per task source, the sla rules and which buckets are collapsed for which user. Defaults to true.
+	KanbanSettings *bool `json:"kanban_settings,omitempty"`
+	// Whether to copy which buckets are the done, default and retention bucket of the project. Defaults to true.
+	BucketSettings *bool `json:"bucket_settings,omitempty"`
+	// Whether to copy all user and team shares of the project. Defaults to true.
+	Shares *bool `json:"shares,omitempty"`
+	// Whether to create a new link share for every link share of the project. Defaults to true.
+	LinkShares *bool `json:"link_shares,omitempty"`
+	// Whether to copy the webhooks of the project. Only available to users who can edit the project.
+	Webhooks bool `json:"webhooks"`
+	// Whether to copy the subscriptions to the project and its tasks. Only subscriptions of users who have access
+	// to the new project are copied.
+	Subscriptions bool `json:"subscriptions"`
+
 	// The copied project
 	Project *Project `json:"duplicated_project,omitempty"`
 
@@ -47,6 +64,14 @@ func (pd *ProjectDuplicate) CanCreate(s *xorm.Session, a web.Auth) (canCreate bo
 		return canRead, err
 	}
 
+	if pd.Webhooks {
+		// Webhooks contain their secret, they can therefore only be copied by users who can see them.
+		canUpdate, err := (&Project{ID: pd.ProjectID}).CanUpdate(s, a)
+		if err != nil || !canUpdate {
+			return false, err
+		}
+	}
+
 	if pd.ParentProjectID == 0 { // no parent project
 		return canRead, err
 	}
@@ -58,7 +83,7 @@ func (pd *ProjectDuplicate) CanCreate(s *xorm.Session, a web.Auth) (canCreate bo
 
 // Create duplicates a project
 // @Summary Duplicate an existing project
-// @Description Copies the project, tasks, files, kanban data, assignees, comments, attachments, lables, relations, backgrounds, user/team rights and link shares from one project to a new one. The user needs read access in the project and write access in the parent of the new project.
+// @Description Copies the project, tasks, files, kanban data, assignees, comments, attachments, lables, relations, backgrounds, user/team rights and link shares from one project to a new one. The kanban configuration, bucket settings, shares and link shares can be excluded, webhooks and subscriptions can be included with the flags of the request. The user needs read access in the project and write access in the parent of the new project.
 // @tags project
 // @Accept json
 // @Produce json
@@ -81,6 +106,20 @@ func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
 	pd.Project.ParentProjectID = pd.ParentProjectID
 	// Set the owner to the current user
 	pd.Project.OwnerID = doer.GetID()
+	// All settings referencing buckets of the old project are added back once the buckets were duplicated.
+	slaRules := pd.Project.SLARules
+	pd.Project.SLARules = nil
+	retentionBucketID := pd.Project.DoneTasksRetentionBucketID
+	pd.Project.DoneTasksRetentionBucketID = 0
+	sourceDefaultBuckets := pd.Project.SourceDefaultBuckets
+	pd.Project.SourceDefaultBuckets = nil
+	doneBucketID := pd.Project.DoneBucketID
+	pd.Project.DoneBucketID = 0
+	defaultBucketID := pd.Project.DefaultBucketID
+	pd.Project.DefaultBucketID = 0
+	iconFileID := pd.Project.IconFileID
+	copyKanbanSettings := isDuplicateOptionEnabled(pd.KanbanSettings)
+	copyBucketSettings := isDuplicateOptionEnabled(pd.BucketSettings)
 	if err := CreateProject(s, pd.Project, doer, false); err != nil {
 		// If there is no available unique project identifier, just reset it.
 		if IsErrProjectIdentifierIsNotUnique(err) {
@@ -105,15 +144,123 @@ func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
 		oldID := b.ID
 		b.ID = 0
 		b.ProjectID = pd.Project.ID
+		if !copyKanbanSettings {
+			b.Actions = nil
+			b.MoveAllowedUserIDs = nil
+			b.MoveAllowedTeamIDs = nil
+			b.SortMode = BucketSortModeManual
+		}
 		if err := b.Create(s, doer); err != nil {
 			return err
 		}
 		bucketMap[oldID] = b.ID
 	}
 
+	if copyKanbanSettings && len(sourceDefaultBuckets) > 0 {
+		for source, bucketID := range sourceDefaultBuckets {
+			sourceDefaultBuckets[source] = bucketMap[bucketID]
+		}
+		pd.Project.SourceDefaultBuckets = sourceDefaultBuckets
+		_, err = s.
+			Where("id = ?", pd.Project.ID).
+			Cols("source_default_buckets").
+			Update(pd.Project)
+		if err != nil {
+			return
+		}
+	}
+
+	if copyBucketSettings && (doneBucketID != 0 || defaultBucketID != 0 || retentionBucketID != 0) {
+		pd.Project.DoneBucketID = bucketMap[doneBucketID]
+		pd.Project.DefaultBucketID = bucketMap[defaultBucketID]
+		pd.Project.DoneTasksRetentionBucketID = bucketMap[retentionBucketID]
+		_, err = s.
+			Where("id = ?", pd.Project.ID).
+			Cols("done_bucket_id", "default_bucket_id", "done_tasks_retention_bucket_id").
+			Update(pd.Project)
+		if err != nil {
+			return
+		}
+	}
+
+	if copyKanbanSettings {
+		err = duplicateBucketCollapsedStates(s, bucketMap)
+		if err != nil {
+			return
+		}
+	}
+
+	if copyKanbanSettings && len(slaRules) > 0 {
+		for _, rule := range slaRules {
+			rule.BucketID = bucketMap[rule.BucketID]
+		}
+		pd.Project.SLARules = slaRules
+		_, err = s.
+			Where("id = ?", pd.Project.ID).
+			Cols("sla_rules").
+			Update(pd.Project)
+		if err != nil {
+			return
+		}
+	}
+
 	log.Debugf("Duplicated all buckets from project %d into %d", pd.ProjectID, pd.Project.ID)
 
-	err = duplicateTasks(s, doer, pd, bucketMap)
+	// Duplicate custom fields
+	// Old custom field ID as key, new id as value
+	customFieldMap := make(map[int64]int64)
+	customFields, err := getCustomFieldsForProjects(s, []int64{pd.ProjectID})
+	if err != nil {
+		return
+	}
+	for _, cf := range customFields {
+		oldID := cf.ID
+		cf.ProjectID = pd.Project.ID
+		if err := cf.Create(s, doer); err != nil {
+			return err
+		}
+		customFieldMap[oldID] = cf.ID
+	}
+
+	log.Debugf("Duplicated all custom fields from project %d into %d", pd.ProjectID, pd.Project.ID)
+
+	// Duplicate statuses
+	// Old status ID as key, new id as value
+	statusMap := make(map[int64]int64)
+	statuses, err := getProjectStatusesForProjects(s, []int64{pd.ProjectID})
+	if err != nil {
+		return
+	}
+	for _, ps := range statuses {
+		oldID := ps.ID
+		ps.ProjectID = pd.Project.ID
+		if err := ps.Create(s, doer); err != nil {
+			return err
+		}
+		statusMap[oldID] = ps.ID
+	}
+
+	log.Debugf("Duplicated all statuses from project %d into %d", pd.ProjectID, pd.Project.ID)
+
+	// Duplicate milestones
+	// Old milestone ID as key, new id as value
+	milestoneMap := make(map[int64]int64)
+	milestones, err := getMilestonesForProjects(s, []int64{pd.ProjectID})
+	if err != nil {
+		return
+	}
+	for _, m := range milestones {
+		oldID := m.ID
+		m.ProjectID = pd.Project.ID
+		if err := m.Create(s, doer); err != nil {
+			return err
+		}
+		milestoneMap[oldID] = m.ID
+	}
+
+	log.Debugf("Duplicated all milestones from project %d into %d", pd.ProjectID, pd.Project.ID)
+
+	taskMap, err := duplicateTasks(s, doer, pd, bucketMap, customFieldMap, statusMap, milestoneMap)
 	if err != nil {
 		return
 	}
@@ -123,6 +270,91 @@ func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
 		return
 	}
 
+	err = duplicateProjectIcon(s, pd, iconFileID, doer)
+	if err != nil {
+		return
+	}
+
+	if isDuplicateOptionEnabled(pd.Shares) {
+		err = duplicateProjectShares(s, pd)
+		if err != nil {
+			return
+		}
+	}
+
+	if isDuplicateOptionEnabled(pd.LinkShares) {
+		err = duplicateProjectLinkShares(s, pd)
+		if err != nil {
+			return
+		}
+	}
+
+	if pd.Webhooks {
+		err = duplicateProjectWebhooks(s, pd, doer)
+		if err != nil {
+			return
+		}
+	}
+
+	if pd.Subscriptions {
+		err = duplicateProjectSubscriptions(s, pd, taskMap)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// isDuplicateOptionEnabled returns the value of a duplication flag which is enabled if it was not provided.
+func isDuplicateOptionEnabled(option *bool) bool {
+	return option == nil || *option
+}
+
+func duplicateBucketCollapsedStates(s *xorm.Session, bucketMap map[int64]int64) (err error) {
+	if len(bucketMap) == 0 {
+		return nil
+	}
+
+	oldBucketIDs := make([]int64, 0, len(bucketMap))
+	for oldID := range bucketMap {
+		oldBucketIDs = append(oldBucketIDs, oldID)
+	}
+
+	states := []*BucketCollapsedState{}
+	err = s.In("bucket_id", oldBucketIDs).Find(&states)
+	if err != nil {
+		return
+	}
+	for _, state := range states {
+		state.ID = 0
+		state.BucketID = bucketMap[state.BucketID]
+		if _, err := s.Insert(state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func duplicateProjectShares(s *xorm.Session, pd *ProjectDuplicate) (err error) {
+	// Roles of the project are duplicated as well so the duplicated shares don't reference roles of the old project
+	roles := []*ProjectRole{}
+	err = s.Where("project_id = ?", pd.ProjectID).Find(&roles)
+	if err != nil {
+		return
+	}
+	roleMap := make(map[int64]int64, len(roles))
+	for _, r := range roles {
+		oldID := r.ID
+		r.ID = 0
+		r.ProjectID = pd.Project.ID
+		if _, err := s.Insert(r); err != nil {
+			return err
+		}
+		roleMap[oldID] = r.ID
+	}
+
 	// Rights / Shares
 	// To keep it simple(r) we will only copy rights which are directly used with the project, not the parent
 	users := []*ProjectUser{}
@@ -133,6 +365,7 @@ func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
 	for _, u := range users {
 		u.ID = 0
 		u.ProjectID = pd.Project.ID
+		u.RoleID = roleMap[u.RoleID]
 		if _, err := s.Insert(u); err != nil {
 			return err
 		}
@@ -148,11 +381,18 @@ func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
 	for _, t := range teams {
 		t.ID = 0
 		t.ProjectID = pd.Project.ID
+		t.RoleID = roleMap[t.RoleID]
 		if _, err := s.Insert(t); err != nil {
 			return err
 		}
 	}
 
+	log.Debugf("Duplicated team shares from project %d into %d", pd.ProjectID, pd.Project.ID)
+
+	return nil
+}
+
+func duplicateProjectLinkShares(s *xorm.Session, pd *ProjectDuplicate) (err error) {
 	// Generate new link shares if any are available
 	linkShares := []*LinkSharing{}
 	err = s.Where("project_id = ?", pd.ProjectID).Find(&linkShares)
@@ -170,7 +410,90 @@ func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
 
 	log.Debugf("Duplicated all link shares from project %d into %d", pd.ProjectID, pd.Project.ID)
 
-	return
+	return nil
+}
+
+func duplicateProjectWebhooks(s *xorm.Session, pd *ProjectDuplicate, doer web.Auth) (err error) {
+	webhooks := []*Webhook{}
+	err = s.Where("project_id = ?", pd.ProjectID).Find(&webhooks)
+	if err != nil {
+		return
+	}
+	for _, w := range webhooks {
+		w.ID = 0
+		w.ProjectID = pd.Project.ID
+		w.CreatedByID = doer.GetID()
+		if _, err := s.Insert(w); err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("Duplicated all webhooks from project %d into %d", pd.ProjectID, pd.Project.ID)
+
+	return nil
+}
+
+func duplicateProjectSubscriptions(s *xorm.Session, pd *ProjectDuplicate, taskMap map[int64]int64) (err error) {
+	oldTaskIDs := make([]int64, 0, len(taskMap))
+	for oldID := range taskMap {
+		oldTaskIDs = append(oldTaskIDs, oldID)
+	}
+
+	cond := builder.And(
+		builder.Eq{"entity_type": SubscriptionEntityProject},
+		builder.Eq{"entity_id": pd.ProjectID},
+	)
+	if len(oldTaskIDs) > 0 {
+		cond = builder.Or(
+			cond,
+			builder.And(
+				builder.Eq{"entity_type": SubscriptionEntityTask},
+				builder.In("entity_id", oldTaskIDs),
+			),
+		)
+	}
+
+	subscriptions := []*Subscription{}
+	err = s.Where(cond).Find(&subscriptions)
+	if err != nil {
+		return
+	}
+
+	// Users who do not have access to the new project would otherwise get notified about it.
+	hasAccess := make(map[int64]bool)
+	for _, sub := range subscriptions {
+		access, checked := hasAccess[sub.UserID]
+		if !checked {
+			u, err := user.GetUserByID(s, sub.UserID)
+			if err != nil && !user.IsErrUserDoesNotExist(err) {
+				return err
+			}
+			if u != nil {
+				access, _, err = pd.Project.CanRead(s, u)
+				if err != nil {
+					return err
+				}
+			}
+			hasAccess[sub.UserID] = access
+		}
+		if !access {
+			continue
+		}
+
+		sub.ID = 0
+		if sub.EntityType == SubscriptionEntityProject {
+			sub.EntityID = pd.Project.ID
+		} else {
+			sub.EntityID = taskMap[sub.EntityID]
+		}
+		if _, err := s.Insert(sub); err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("Duplicated all subscriptions from project %d into %d", pd.ProjectID, pd.Project.ID)
+
+	return nil
 }
 
 func duplicateProjectBackground(s *xorm.Session, pd *ProjectDuplicate, doer web.Auth) (err error) {
@@ -221,44 +544,89 @@ func duplicateProjectBackground(s *xorm.Session, pd *ProjectDuplicate, doer web.
 	return
 }
 
-func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate, bucketMap map[int64]int64) (err error) {
+func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate, bucketMap map[int64]int64, customFieldMap map[int64]int64, statusMap map[int64]int64, milestoneMap map[int64]int64) (taskMap map[int64]int64, err error) {
+	// This map contains the old task id as key and the new duplicated task id as value.
+	// It is used to map old task items to new ones.
+	taskMap = make(map[int64]int64)
+
 	// Get all tasks + all task details
 	tasks, _, _, err := getTasksForProjects(s, []*Project{{ID: ld.ProjectID}}, doer, &taskSearchOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(tasks) == 0 {
-		return nil
+		return taskMap, nil
 	}
 
-	// This map contains the old task id as key and the new duplicated task id as value.
-	// It is used to map old task items to new ones.
-	taskMap := make(map[int64]int64)
 	// Create + update all tasks (includes reminders)
 	oldTaskIDs := make([]int64, 0, len(tasks))
+	// The parents are set after all tasks were created because the parent may not exist yet.
+	// The relations between them are duplicated below with all other relations.
+	oldParentTaskIDs := make(map[int64]int64)
 	for _, t := range tasks {
 		oldID := t.ID
 		t.ID = 0
 		t.ProjectID = ld.Project.ID
 		t.BucketID = bucketMap[t.BucketID]
+		t.StatusID = statusMap[t.StatusID]
+		t.MilestoneID = milestoneMap[t.MilestoneID]
 		t.UID = ""
+		oldParentTaskIDs[oldID] = t.ParentTaskID
+		t.ParentTaskID = 0
+		if t.CustomFields != nil {
+			customFields := make(map[int64]interface{}, len(t.CustomFields))
+			for fieldID, value := range t.CustomFields {
+				customFields[customFieldMap[fieldID]] = value
+			}
+			t.CustomFields = customFields
+		}
 		err := createTask(s, t, doer, false)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		taskMap[oldID] = t.ID
 		oldTaskIDs = append(oldTaskIDs, oldID)
 	}
 
+	for oldID, oldParentID := range oldParentTaskIDs {
+		newParentID, exists := taskMap[oldParentID]
+		if !exists {
+			continue
+		}
+		_, err = s.
+			Where("id = ?", taskMap[oldID]).
+			Cols("parent_task_id").
+			NoAutoTime().
+			Update(&Task{ParentTaskID: newParentID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	log.Debugf("Duplicated all tasks from project %d into %d", ld.ProjectID, ld.Project.ID)
 
+	checklistItems, err := getChecklistItemsForTasks(s, oldTaskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range checklistItems {
+		item.ID = 0
+		item.TaskID = taskMap[item.TaskID]
+		if _, err := s.Insert(item); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Debugf("Duplicated all checklist items from project %d into %d", ld.ProjectID, ld.Project.ID)
+
 	// Save all attachments
 	// We also duplicate all underlying files since they could be modified in one project which would result in
 	// file changes in the other project which is not something we want.
 	attachments, err := getTaskAttachmentsByTaskIDs(s, oldTaskIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, attachment := range attachments {
@@ -270,21 +638,31 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate, bucket
 			log.Debugf("Error duplicating attachment %d from old task %d to new task: Old task <-> new task does not seem to exist.", oldAttachmentID, attachment.TaskID)
 			continue
 		}
+
+		if attachment.IsLink() {
+			err := attachment.Create(s, doer)
+			if err != nil {
+				return nil, err
+			}
+			log.Debugf("Duplicated link attachment %d into %d from project %d into %d", oldAttachmentID, attachment.ID, ld.ProjectID, ld.Project.ID)
+			continue
+		}
+
 		attachment.File = &files.File{ID: attachment.FileID}
 		if err := attachment.File.LoadFileMetaByID(); err != nil {
 			if files.IsErrFileDoesNotExist(err) {
 				log.Debugf("Not duplicating attachment %d (file %d) because it does not exist from project %d into %d", oldAttachmentID, attachment.FileID, ld.ProjectID, ld.Project.ID)
 				continue
 			}
-			return err
+			return nil, err
 		}
 		if err := attachment.File.LoadFileByID(); err != nil {
-			return err
+			return nil, err
 		}
 
 		err := attachment.NewAttachment(s, attachment.File.File, attachment.File.Name, attachment.File.Size, doer)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if attachment.File.File != nil {
@@ -307,7 +685,7 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate, bucket
 		lt.ID = 0
 		lt.TaskID = taskMap[lt.TaskID]
 		if _, err := s.Insert(lt); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -329,7 +707,7 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate, bucket
 			if IsErrUserDoesNotHaveAccessToProject(err) {
 				continue
 			}
-			return err
+			return nil, err
 		}
 	}
 
@@ -345,7 +723,7 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate, bucket
 		c.ID = 0
 		c.TaskID = taskMap[c.TaskID]
 		if _, err := s.Insert(c); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -368,11 +746,50 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate, bucket
 		r.OtherTaskID = otherTaskID
 		r.TaskID = taskMap[r.TaskID]
 		if _, err := s.Insert(r); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	log.Debugf("Duplicated all task relations from project %d into %d", ld.ProjectID, ld.Project.ID)
 
-	return nil
+	return taskMap, nil
+}
+
+func duplicateProjectIcon(s *xorm.Session, pd *ProjectDuplicate, iconFileID int64, doer web.Auth) (err error) {
+	if iconFileID == 0 {
+		return
+	}
+
+	log.Debugf("Duplicating icon %d from project %d into %d", iconFileID, pd.ProjectID, pd.Project.ID)
+
+	f := &files.File{ID: iconFileID}
+	err = f.LoadFileMetaByID()
+	if err != nil && files.IsErrFileDoesNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := f.LoadFileByID(); err != nil {
+		return err
+	}
+	defer f.File.Close()
+
+	file, err := files.CreateWithMime(f.File, f.Name, f.Size, doer, f.Mime)
+	if err != nil {
+		return err
+	}
+
+	pd.Project.IconFileID = file.ID
+	_, err = s.
+		ID(pd.Project.ID).
+		Cols("icon_file_id").
+		Update(pd.Project)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Duplicated project icon from project %d into %d", pd.ProjectID, pd.Project.ID)
+
+	return
 }
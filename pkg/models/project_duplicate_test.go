@@ -54,6 +54,116 @@ func TestProjectDuplicate(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, numberOfOriginalBuckets, numberOfDuplicatedBuckets, "duplicated project does not have the same amount of buckets as the original one")
 
+	// assert the custom fields and their values were duplicated
+	duplicatedFields, err := getCustomFieldsForProjects(s, []int64{l.Project.ID})
+	require.NoError(t, err)
+	assert.Len(t, duplicatedFields, 4)
+	duplicatedValues, err := s.
+		In("custom_field_id", []int64{duplicatedFields[0].ID, duplicatedFields[1].ID, duplicatedFields[3].ID}).
+		Count(&TaskCustomFieldValue{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), duplicatedValues)
+
 	// To make this test 100% useful, it would need to assert a lot more stuff, but it is good enough for now.
 	// Also, we're lacking utility functions to do all needed assertions.
 }
+
+func TestProjectDuplicate_Options(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("defaults", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pd := &ProjectDuplicate{ProjectID: 1}
+		can, err := pd.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pd.Create(s, u)
+		require.NoError(t, err)
+
+		// The done bucket now points to the copy of the done bucket
+		doneBucket, err := getBucketByID(s, pd.Project.DoneBucketID)
+		require.NoError(t, err)
+		assert.Equal(t, pd.Project.ID, doneBucket.ProjectID)
+		assert.Equal(t, "testbucket3", doneBucket.Title)
+
+		linkShares, err := s.Where("project_id = ?", pd.Project.ID).Count(&LinkSharing{})
+		require.NoError(t, err)
+		assert.NotZero(t, linkShares)
+		subscriptions, err := s.Where("entity_type = ? AND entity_id = ?", SubscriptionEntityTask, 2).Count(&Subscription{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), subscriptions)
+	})
+	t.Run("without bucket settings and link shares", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		disabled := false
+		pd := &ProjectDuplicate{ProjectID: 1, BucketSettings: &disabled, LinkShares: &disabled}
+		can, err := pd.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pd.Create(s, u)
+		require.NoError(t, err)
+
+		project, err := GetProjectSimpleByID(s, pd.Project.ID)
+		require.NoError(t, err)
+		assert.Zero(t, project.DoneBucketID)
+		linkShares, err := s.Where("project_id = ?", pd.Project.ID).Count(&LinkSharing{})
+		require.NoError(t, err)
+		assert.Zero(t, linkShares)
+	})
+	t.Run("with webhooks and subscriptions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&Webhook{
+			TargetURL:   "https://example.com/hook",
+			Events:      []string{"task.created"},
+			ProjectID:   1,
+			Secret:      "secret",
+			CreatedByID: 1,
+		})
+		require.NoError(t, err)
+
+		pd := &ProjectDuplicate{ProjectID: 1, Webhooks: true, Subscriptions: true}
+		can, err := pd.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pd.Create(s, u)
+		require.NoError(t, err)
+
+		db.AssertExists(t, "webhooks", map[string]interface{}{
+			"project_id": pd.Project.ID,
+			"target_url": "https://example.com/hook",
+			"secret":     "secret",
+		}, false)
+
+		task := &Task{}
+		_, err = s.Where("project_id = ? AND title = ?", pd.Project.ID, "task #2 done").Get(task)
+		require.NoError(t, err)
+		db.AssertExists(t, "subscriptions", map[string]interface{}{
+			"entity_type": SubscriptionEntityTask,
+			"entity_id":   task.ID,
+			"user_id":     1,
+		}, false)
+	})
+	t.Run("webhooks without edit rights", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 can only read project 9
+		pd := &ProjectDuplicate{ProjectID: 9, Webhooks: true}
+		can, err := pd.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
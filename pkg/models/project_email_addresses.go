@@ -0,0 +1,250 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// ProjectEmailAddress is the email address of a project. Emails sent to it become tasks in the project.
+type ProjectEmailAddress struct {
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	// The project this email address belongs to.
+	ProjectID int64 `xorm:"bigint not null unique" json:"project_id" param:"project"`
+	// The random local part of the email address.
+	Token string `xorm:"varchar(50) not null unique" json:"-"`
+	// The email address of the project. Emails sent to it become tasks in the project.
+	Address string `xorm:"-" json:"address"`
+	// The email addresses allowed to send emails to the project. Entries starting with an @ allow all addresses of that domain.
+	// If empty, only the user who created the email address can send emails to it.
+	AllowedSenders []string `xorm:"JSON null" json:"allowed_senders"`
+
+	// The user who created the email address. All tasks created from emails are created by this user.
+	CreatedBy   *user.User `xorm:"-" json:"created_by"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this email address was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this email address was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project email addresses
+func (*ProjectEmailAddress) TableName() string {
+	return "project_email_addresses"
+}
+
+func getProjectEmailAddressByProjectID(s *xorm.Session, projectID int64) (address *ProjectEmailAddress, err error) {
+	address = &ProjectEmailAddress{}
+	exists, err := s.Where("project_id = ?", projectID).Get(address)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrProjectEmailAddressDoesNotExist{ProjectID: projectID}
+	}
+	return address, nil
+}
+
+func (pa *ProjectEmailAddress) setAddress() {
+	pa.Address = pa.Token + "@" + config.InboundMailDomain.GetString()
+}
+
+func (pa *ProjectEmailAddress) addCreatedBy(s *xorm.Session) error {
+	users, err := getUsersOrLinkSharesFromIDs(s, []int64{pa.CreatedByID})
+	if err != nil {
+		return err
+	}
+	pa.CreatedBy = users[pa.CreatedByID]
+	return nil
+}
+
+// validateAllowedSenders checks all allowed senders are either valid email addresses or a domain starting with an @
+func (pa *ProjectEmailAddress) validateAllowedSenders() error {
+	for i, sender := range pa.AllowedSenders {
+		sender = strings.ToLower(strings.TrimSpace(sender))
+		pa.AllowedSenders[i] = sender
+
+		if strings.HasPrefix(sender, "@") && len(sender) > 1 && !strings.Contains(sender[1:], "@") {
+			continue
+		}
+		if _, err := mail.ParseAddress(sender); err != nil {
+			return ErrInvalidData{Message: "Invalid allowed sender " + sender + "."}
+		}
+	}
+
+	return nil
+}
+
+// isSenderAllowed checks if an email from the sender may create tasks in the project
+func (pa *ProjectEmailAddress) isSenderAllowed(sender string, creator *user.User) bool {
+	sender = strings.ToLower(sender)
+
+	if len(pa.AllowedSenders) == 0 {
+		return strings.EqualFold(sender, creator.Email)
+	}
+
+	for _, allowed := range pa.AllowedSenders {
+		if strings.HasPrefix(allowed, "@") && strings.HasSuffix(sender, allowed) {
+			return true
+		}
+		if sender == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Create generates a new email address for a project
+// @Summary Create the email address of a project
+// @Description Generates a new random email address for a project. If the project already has one, it is replaced and the old address stops working. Emails sent to the address become tasks in the project, created by the current user. Only available if inbound mail is enabled.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param address body models.ProjectEmailAddress true "The allowed senders of the email address."
+// @Success 201 {object} models.ProjectEmailAddress "The created email address."
+// @Failure 400 {object} web.HTTPError "Invalid allowed sender provided."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/email [put]
+func (pa *ProjectEmailAddress) Create(s *xorm.Session, a web.Auth) (err error) {
+	err = pa.validateAllowedSenders()
+	if err != nil {
+		return err
+	}
+
+	token, err := utils.CryptoRandomString(32)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("project_id = ?", pa.ProjectID).Delete(&ProjectEmailAddress{})
+	if err != nil {
+		return err
+	}
+
+	pa.ID = 0
+	pa.Token = strings.ToLower(token)
+	pa.CreatedByID = a.GetID()
+	_, err = s.Insert(pa)
+	if err != nil {
+		return err
+	}
+
+	pa.setAddress()
+	return pa.addCreatedBy(s)
+}
+
+// ReadOne returns the email address of a project
+// @Summary Get the email address of a project
+// @Description Returns the email address of a project and the senders allowed to send emails to it.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 200 {object} models.ProjectEmailAddress "The email address."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 404 {object} web.HTTPError "The project does not have an email address."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/email [get]
+func (pa *ProjectEmailAddress) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	address, err := getProjectEmailAddressByProjectID(s, pa.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	*pa = *address
+	pa.setAddress()
+	return pa.addCreatedBy(s)
+}
+
+// Update changes the allowed senders of the email address of a project
+// @Summary Update the email address of a project
+// @Description Changes the senders allowed to send emails to the email address of a project. The address itself can only be changed by creating a new one.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param address body models.ProjectEmailAddress true "The allowed senders of the email address."
+// @Success 200 {object} models.ProjectEmailAddress "The updated email address."
+// @Failure 400 {object} web.HTTPError "Invalid allowed sender provided."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 404 {object} web.HTTPError "The project does not have an email address."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/email [post]
+func (pa *ProjectEmailAddress) Update(s *xorm.Session, a web.Auth) (err error) {
+	err = pa.validateAllowedSenders()
+	if err != nil {
+		return err
+	}
+
+	existing, err := getProjectEmailAddressByProjectID(s, pa.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	existing.AllowedSenders = pa.AllowedSenders
+	_, err = s.
+		ID(existing.ID).
+		Cols("allowed_senders").
+		Update(existing)
+	if err != nil {
+		return err
+	}
+
+	*pa = *existing
+	pa.setAddress()
+	return pa.addCreatedBy(s)
+}
+
+// Delete removes the email address of a project
+// @Summary Delete the email address of a project
+// @Description Removes the email address of a project. Emails sent to it will no longer create tasks.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 200 {object} models.Message "The email address was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 404 {object} web.HTTPError "The project does not have an email address."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/email [delete]
+func (pa *ProjectEmailAddress) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	existing, err := getProjectEmailAddressByProjectID(s, pa.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.ID(existing.ID).Delete(&ProjectEmailAddress{})
+	return err
+}
@@ -0,0 +1,53 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see the email address of a project
+func (pa *ProjectEmailAddress) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	can, err := pa.canDoProjectEmailAddress(s, a)
+	return can, int(RightAdmin), err
+}
+
+// CanCreate checks if the user can create an email address for a project
+func (pa *ProjectEmailAddress) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return pa.canDoProjectEmailAddress(s, a)
+}
+
+// CanUpdate checks if the user can update the email address of a project
+func (pa *ProjectEmailAddress) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return pa.canDoProjectEmailAddress(s, a)
+}
+
+// CanDelete checks if the user can delete the email address of a project
+func (pa *ProjectEmailAddress) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return pa.canDoProjectEmailAddress(s, a)
+}
+
+func (pa *ProjectEmailAddress) canDoProjectEmailAddress(s *xorm.Session, a web.Auth) (bool, error) {
+	// Tasks created from emails need a user to be created by
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	project := &Project{ID: pa.ProjectID}
+	return project.IsAdmin(s, a)
+}
@@ -0,0 +1,179 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectEmailAddress_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+	config.InboundMailDomain.Set("tasks.example.com")
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pa := &ProjectEmailAddress{ProjectID: 11, AllowedSenders: []string{" Someone@Example.com ", "@example.org"}}
+		can, err := pa.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pa.Create(s, u)
+		require.NoError(t, err)
+		assert.Len(t, pa.Token, 32)
+		assert.Equal(t, pa.Token+"@tasks.example.com", pa.Address)
+		assert.Equal(t, []string{"someone@example.com", "@example.org"}, pa.AllowedSenders)
+		assert.Equal(t, int64(1), pa.CreatedBy.ID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_email_addresses", map[string]interface{}{
+			"project_id":    11,
+			"token":         pa.Token,
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("replaces the existing address", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pa := &ProjectEmailAddress{ProjectID: 1}
+		err := pa.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "project_email_addresses", map[string]interface{}{
+			"token": "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4",
+		})
+		db.AssertExists(t, "project_email_addresses", map[string]interface{}{
+			"project_id": 1,
+			"token":      pa.Token,
+		}, false)
+	})
+	t.Run("invalid allowed sender", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pa := &ProjectEmailAddress{ProjectID: 11, AllowedSenders: []string{"not an email"}}
+		err := pa.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidData(err))
+	})
+	t.Run("no admin", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pa := &ProjectEmailAddress{ProjectID: 1}
+		can, err := pa.CanCreate(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestProjectEmailAddress_ReadOne(t *testing.T) {
+	u := &user.User{ID: 1}
+	config.InboundMailDomain.Set("tasks.example.com")
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pa := &ProjectEmailAddress{ProjectID: 1}
+		can, _, err := pa.CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pa.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4@tasks.example.com", pa.Address)
+		assert.Equal(t, []string{"user1@example.com", "@allowed.com"}, pa.AllowedSenders)
+	})
+	t.Run("nonexisting", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pa := &ProjectEmailAddress{ProjectID: 11}
+		err := pa.ReadOne(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectEmailAddressDoesNotExist(err))
+	})
+}
+
+func TestProjectEmailAddress_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	pa := &ProjectEmailAddress{ProjectID: 1, AllowedSenders: []string{}}
+	err := pa.Update(s, u)
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertExists(t, "project_email_addresses", map[string]interface{}{
+		"project_id":      1,
+		"token":           "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4",
+		"allowed_senders": "[]",
+	}, false)
+}
+
+func TestProjectEmailAddress_Delete(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	pa := &ProjectEmailAddress{ProjectID: 1}
+	err := pa.Delete(s, u)
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "project_email_addresses", map[string]interface{}{
+		"project_id": 1,
+	})
+}
+
+func TestProjectEmailAddress_isSenderAllowed(t *testing.T) {
+	creator := &user.User{ID: 1, Email: "user1@example.com"}
+
+	pa := &ProjectEmailAddress{AllowedSenders: []string{"someone@example.com", "@example.org"}}
+	assert.True(t, pa.isSenderAllowed("Someone@Example.com", creator))
+	assert.True(t, pa.isSenderAllowed("anyone@example.org", creator))
+	assert.False(t, pa.isSenderAllowed("user1@example.com", creator))
+	assert.False(t, pa.isSenderAllowed("someone@notexample.org", creator))
+
+	pa = &ProjectEmailAddress{}
+	assert.True(t, pa.isSenderAllowed("user1@example.com", creator))
+	assert.False(t, pa.isSenderAllowed("someone@example.com", creator))
+}
@@ -0,0 +1,161 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode"
+
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/web"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/rivo/uniseg"
+	"xorm.io/xorm"
+)
+
+// maxProjectIconSize is the maximum size in bytes of an image uploaded as project icon.
+const maxProjectIconSize = 512 * 1024
+
+// isEmojiRune checks if a rune may be part of an emoji sequence.
+func isEmojiRune(r rune) bool {
+	switch {
+	case unicode.Is(unicode.So, r),
+		unicode.Is(unicode.Sk, r),      // Skin tone modifiers
+		unicode.Is(unicode.Me, r),      // Combining enclosing keycap
+		r == '\u200d',                  // Zero width joiner
+		r == '\ufe0e' || r == '\ufe0f', // Variation selectors
+		r >= 0xe0020 && r <= 0xe007f,   // Tags used in subdivision flags
+		r >= '0' && r <= '9',
+		r == '#' || r == '*':
+		return true
+	}
+	return false
+}
+
+// validateIcon checks the icon of a project is empty or a single emoji
+func (p *Project) validateIcon() error {
+	if p.Icon == "" {
+		return nil
+	}
+
+	if len(p.Icon) > 50 || uniseg.GraphemeClusterCount(p.Icon) != 1 {
+		return &ErrInvalidProjectIcon{Icon: p.Icon}
+	}
+
+	var hasSymbol bool
+	for _, r := range p.Icon {
+		if !isEmojiRune(r) {
+			return &ErrInvalidProjectIcon{Icon: p.Icon}
+		}
+		if unicode.Is(unicode.So, r) || unicode.Is(unicode.Me, r) {
+			hasSymbol = true
+		}
+	}
+
+	// Plain digits, "#" or "*" are only emojis as part of a keycap sequence
+	if !hasSymbol {
+		return &ErrInvalidProjectIcon{Icon: p.Icon}
+	}
+
+	return nil
+}
+
+// DeleteIconFileIfExists deletes the icon image of a project from the db and the filesystem, if one exists
+func (p *Project) DeleteIconFileIfExists() (err error) {
+	if p.IconFileID == 0 {
+		return
+	}
+
+	file := files.File{ID: p.IconFileID}
+	err = file.Delete()
+	if err != nil && files.IsErrFileDoesNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// SetProjectIconImage stores an image as the icon of a project, replacing any emoji or image icon the project had before.
+// The content must be an image not larger than maxProjectIconSize.
+func SetProjectIconImage(s *xorm.Session, project *Project, src io.Reader, name string, auth web.Auth) (err error) {
+	// Read one byte more than allowed to find out if the file is too large
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(io.LimitReader(src, maxProjectIconSize+1))
+	if err != nil {
+		return err
+	}
+	if buf.Len() > maxProjectIconSize {
+		return &ErrProjectIconIsTooLarge{Size: uint64(buf.Len())}
+	}
+	content := buf.Bytes()
+
+	mime := mimetype.Detect(content)
+	if !strings.HasPrefix(mime.String(), "image/") {
+		return &ErrProjectIconIsNotAnImage{ProjectID: project.ID, Mime: mime.String()}
+	}
+
+	err = project.DeleteIconFileIfExists()
+	if err != nil {
+		return err
+	}
+
+	file, err := files.CreateWithMime(bytes.NewReader(content), name, uint64(len(content)), auth, mime.String())
+	if err != nil {
+		return err
+	}
+
+	project.Icon = ""
+	project.IconFileID = file.ID
+	_, err = s.
+		ID(project.ID).
+		Cols("icon", "icon_file_id").
+		Update(project)
+	return err
+}
+
+// removeProjectIconImage deletes the icon image of a project, keeping its emoji icon.
+func removeProjectIconImage(s *xorm.Session, project *Project) (err error) {
+	err = project.DeleteIconFileIfExists()
+	if err != nil {
+		return err
+	}
+
+	project.IconFileID = 0
+	_, err = s.
+		ID(project.ID).
+		Cols("icon_file_id").
+		Update(project)
+	return err
+}
+
+// RemoveProjectIcon removes the emoji or image icon of a project.
+func RemoveProjectIcon(s *xorm.Session, project *Project) (err error) {
+	err = removeProjectIconImage(s, project)
+	if err != nil {
+		return err
+	}
+
+	project.Icon = ""
+	_, err = s.
+		ID(project.ID).
+		Cols("icon").
+		Update(project)
+	return err
+}
@@ -0,0 +1,149 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProject_validateIcon(t *testing.T) {
+	valid := []string{
+		"",
+		"🚀",
+		"❤️",
+		"👍🏽",
+		"👨‍👩‍👧",
+		"🇩🇪",
+		"1️⃣",
+	}
+	for _, icon := range valid {
+		p := &Project{Icon: icon}
+		assert.NoError(t, p.validateIcon(), "icon %q", icon)
+	}
+
+	invalid := []string{
+		"a",
+		"1",
+		"🚀🚀",
+		"🚀a",
+		"icon",
+		strings.Repeat("🏳️‍🌈", 10),
+	}
+	for _, icon := range invalid {
+		p := &Project{Icon: icon}
+		err := p.validateIcon()
+		require.Error(t, err, "icon %q", icon)
+		assert.True(t, IsErrInvalidProjectIcon(err))
+	}
+}
+
+func TestSetProjectIconImage(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	img := &bytes.Buffer{}
+	err := png.Encode(img, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	require.NoError(t, err)
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		project.Icon = "🚀"
+
+		err = SetProjectIconImage(s, project, bytes.NewReader(img.Bytes()), "icon.png", u)
+		require.NoError(t, err)
+		assert.NotEqual(t, int64(0), project.IconFileID)
+		assert.Empty(t, project.Icon)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":           1,
+			"icon_file_id": project.IconFileID,
+		}, false)
+		db.AssertExists(t, "files", map[string]interface{}{
+			"id":   project.IconFileID,
+			"mime": "image/png",
+		}, false)
+	})
+	t.Run("not an image", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+
+		err = SetProjectIconImage(s, project, strings.NewReader("not an image"), "icon.txt", u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIconIsNotAnImage(err))
+	})
+	t.Run("too large", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+
+		content := append(img.Bytes(), make([]byte, maxProjectIconSize)...)
+		err = SetProjectIconImage(s, project, bytes.NewReader(content), "icon.png", u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIconIsTooLarge(err))
+	})
+	t.Run("emoji replaces image", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		err = SetProjectIconImage(s, project, bytes.NewReader(img.Bytes()), "icon.png", u)
+		require.NoError(t, err)
+		fileID := project.IconFileID
+
+		project.Icon = "🚀"
+		err = UpdateProject(s, project, u, false)
+		require.NoError(t, err)
+		assert.Equal(t, "🚀", project.Icon)
+		assert.Equal(t, int64(0), project.IconFileID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "files", map[string]interface{}{
+			"id": fileID,
+		})
+	})
+}
@@ -0,0 +1,255 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// ProjectInboundWebhook is a url of a project which creates tasks in the project when a json payload is posted to it.
+type ProjectInboundWebhook struct {
+	// The unique, numeric id of this inbound webhook.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"inboundwebhook"`
+	// The project tasks are created in.
+	ProjectID int64 `xorm:"bigint not null index" json:"project_id" param:"project"`
+	// A name to tell the inbound webhooks of a project apart, for example the name of the service using it.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// The random token which authenticates requests to the inbound webhook.
+	Token string `xorm:"varchar(50) not null unique" json:"-"`
+	// The url to post the json payload to. Anyone who knows this url can create tasks in the project.
+	URL string `xorm:"-" json:"url"`
+
+	// The user who created the inbound webhook. All tasks created with it are created by this user.
+	CreatedBy   *user.User `xorm:"-" json:"created_by"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this inbound webhook was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this inbound webhook was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project inbound webhooks
+func (*ProjectInboundWebhook) TableName() string {
+	return "project_inbound_webhooks"
+}
+
+// InboundWebhookPayload is the json payload inbound webhooks accept to create a task
+type InboundWebhookPayload struct {
+	// The title of the task. Required.
+	Title string `json:"title"`
+	// The description of the task as plain text.
+	Description string `json:"description"`
+	// The priority of the task, between 0 (unset) and 5 (do now).
+	Priority int64 `json:"priority"`
+	// The due date of the task.
+	DueDate time.Time `json:"due_date"`
+}
+
+// InboundWebhookTask is the response to an inbound webhook request. Since anyone who knows the url of an inbound
+// webhook can send requests to it, it only contains what is needed to reference the created task.
+type InboundWebhookTask struct {
+	// The unique, numeric id of the created task.
+	ID int64 `json:"id"`
+	// The task identifier, based on the project identifier and the task's index.
+	Identifier string `json:"identifier"`
+	// The task index, calculated per project.
+	Index int64 `json:"index"`
+}
+
+func (iw *ProjectInboundWebhook) setURL() {
+	iw.URL = config.ServicePublicURL.GetString() + "api/v1/inbound/webhooks/" + iw.Token
+}
+
+// Create generates a new inbound webhook for a project
+// @Summary Create an inbound webhook
+// @Description Generates a new inbound webhook url for a project. Json payloads posted to it create tasks in the project, created by the current user.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param webhook body models.ProjectInboundWebhook true "The inbound webhook with its title."
+// @Success 201 {object} models.ProjectInboundWebhook "The created inbound webhook."
+// @Failure 400 {object} web.HTTPError "Invalid inbound webhook provided."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/inboundwebhooks [put]
+func (iw *ProjectInboundWebhook) Create(s *xorm.Session, a web.Auth) (err error) {
+	token, err := utils.CryptoRandomString(32)
+	if err != nil {
+		return err
+	}
+
+	iw.ID = 0
+	iw.Token = strings.ToLower(token)
+	iw.CreatedByID = a.GetID()
+	_, err = s.Insert(iw)
+	if err != nil {
+		return err
+	}
+
+	iw.setURL()
+	iw.CreatedBy, err = user.GetUserByID(s, iw.CreatedByID)
+	return err
+}
+
+// ReadAll returns all inbound webhooks of a project
+// @Summary Get all inbound webhooks of a project
+// @Description Returns all inbound webhooks of a project including their urls.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.ProjectInboundWebhook "The inbound webhooks."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/inboundwebhooks [get]
+func (iw *ProjectInboundWebhook) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	can, err := iw.canDoProjectInboundWebhook(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	query := s.Where("project_id = ?", iw.ProjectID).OrderBy("id asc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+
+	webhooks := []*ProjectInboundWebhook{}
+	err = query.Find(&webhooks)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	total, err := s.Where("project_id = ?", iw.ProjectID).Count(&ProjectInboundWebhook{})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	userIDs := make([]int64, 0, len(webhooks))
+	for _, w := range webhooks {
+		userIDs = append(userIDs, w.CreatedByID)
+	}
+	users, err := user.GetUsersByIDs(s, userIDs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	for _, w := range webhooks {
+		w.setURL()
+		w.CreatedBy = users[w.CreatedByID]
+	}
+
+	return webhooks, len(webhooks), total, nil
+}
+
+// Delete removes an inbound webhook
+// @Summary Delete an inbound webhook
+// @Description Removes an inbound webhook of a project. Requests to its url will no longer create tasks.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param inboundwebhook path int true "Inbound webhook ID"
+// @Success 200 {object} models.Message "The inbound webhook was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 404 {object} web.HTTPError "The inbound webhook does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/inboundwebhooks/{inboundwebhook} [delete]
+func (iw *ProjectInboundWebhook) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	deleted, err := s.
+		Where("id = ? AND project_id = ?", iw.ID, iw.ProjectID).
+		Delete(&ProjectInboundWebhook{})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return &ErrInboundWebhookDoesNotExist{}
+	}
+	return nil
+}
+
+// CreateTaskFromInboundWebhook creates a task from a payload posted to the inbound webhook with the token
+func CreateTaskFromInboundWebhook(s *xorm.Session, token string, payload *InboundWebhookPayload) (task *Task, err error) {
+	if token == "" {
+		return nil, &ErrInboundWebhookDoesNotExist{}
+	}
+
+	iw := &ProjectInboundWebhook{}
+	exists, err := s.Where("token = ?", strings.ToLower(token)).Get(iw)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrInboundWebhookDoesNotExist{}
+	}
+
+	title := strings.TrimSpace(payload.Title)
+	if title == "" {
+		return nil, InvalidFieldError([]string{"title"})
+	}
+	if runes := []rune(title); len(runes) > 250 {
+		title = string(runes[:250])
+	}
+	if payload.Priority < 0 || payload.Priority > 5 {
+		return nil, InvalidFieldError([]string{"priority"})
+	}
+
+	creator, err := user.GetUserByID(s, iw.CreatedByID)
+	if err != nil {
+		return nil, err
+	}
+
+	task = &Task{
+		Title:       title,
+		Description: plainTextToHTML(payload.Description),
+		Priority:    payload.Priority,
+		DueDate:     payload.DueDate,
+		ProjectID:   iw.ProjectID,
+		Source:      TaskSourceInboundWebhook,
+	}
+
+	// The creator of the inbound webhook might have lost access to the project since it was created
+	can, err := task.CanCreate(s, creator)
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, ErrGenericForbidden{}
+	}
+
+	err = task.Create(s, creator)
+	return task, err
+}
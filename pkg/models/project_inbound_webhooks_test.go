@@ -0,0 +1,189 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectInboundWebhook_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		iw := &ProjectInboundWebhook{ProjectID: 1, Title: "CI"}
+		can, err := iw.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = iw.Create(s, u)
+		require.NoError(t, err)
+		assert.Len(t, iw.Token, 32)
+		assert.Contains(t, iw.URL, "api/v1/inbound/webhooks/"+iw.Token)
+		assert.Equal(t, int64(1), iw.CreatedBy.ID)
+
+		db.AssertExists(t, "project_inbound_webhooks", map[string]interface{}{
+			"id":            iw.ID,
+			"project_id":    1,
+			"title":         "CI",
+			"token":         iw.Token,
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("no admin", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 can only write to project 10
+		iw := &ProjectInboundWebhook{ProjectID: 10, Title: "CI"}
+		can, err := iw.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		iw := &ProjectInboundWebhook{ProjectID: 1, Title: "CI"}
+		can, err := iw.CanCreate(s, &LinkSharing{ID: 1, ProjectID: 1, Right: RightAdmin})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestProjectInboundWebhook_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	iw := &ProjectInboundWebhook{ProjectID: 1}
+	result, count, total, err := iw.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, int64(1), total)
+	webhooks := result.([]*ProjectInboundWebhook)
+	assert.Equal(t, "Monitoring", webhooks[0].Title)
+	assert.Contains(t, webhooks[0].URL, "inboundwebhooktoken1")
+	assert.Equal(t, int64(1), webhooks[0].CreatedBy.ID)
+
+	_, _, _, err = iw.ReadAll(s, &user.User{ID: 2}, "", 0, 50)
+	require.Error(t, err)
+	assert.IsType(t, ErrGenericForbidden{}, err)
+}
+
+func TestProjectInboundWebhook_Delete(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		iw := &ProjectInboundWebhook{ID: 1, ProjectID: 1}
+		err := iw.Delete(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		db.AssertMissing(t, "project_inbound_webhooks", map[string]interface{}{"id": 1})
+	})
+	t.Run("of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		iw := &ProjectInboundWebhook{ID: 2, ProjectID: 1}
+		err := iw.Delete(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInboundWebhookDoesNotExist(err))
+		db.AssertExists(t, "project_inbound_webhooks", map[string]interface{}{"id": 2}, false)
+	})
+}
+
+func TestCreateTaskFromInboundWebhook(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		due := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+		task, err := CreateTaskFromInboundWebhook(s, "inboundwebhooktoken1", &InboundWebhookPayload{
+			Title:       " Disk almost full ",
+			Description: "Server: db1\nUsage: 95%",
+			Priority:    4,
+			DueDate:     due,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Disk almost full", task.Title)
+		assert.Equal(t, "<p>Server: db1<br>Usage: 95%</p>", task.Description)
+		assert.Equal(t, TaskSourceInboundWebhook, task.Source)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":            task.ID,
+			"project_id":    1,
+			"title":         "Disk almost full",
+			"priority":      4,
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("unknown token", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := CreateTaskFromInboundWebhook(s, "doesnotexist", &InboundWebhookPayload{Title: "Test"})
+		require.Error(t, err)
+		assert.True(t, IsErrInboundWebhookDoesNotExist(err))
+
+		_, err = CreateTaskFromInboundWebhook(s, "", &InboundWebhookPayload{Title: "Test"})
+		require.Error(t, err)
+		assert.True(t, IsErrInboundWebhookDoesNotExist(err))
+	})
+	t.Run("no title", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := CreateTaskFromInboundWebhook(s, "inboundwebhooktoken1", &InboundWebhookPayload{Title: "  "})
+		require.Error(t, err)
+		assert.IsType(t, ValidationHTTPError{}, err)
+	})
+	t.Run("invalid priority", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := CreateTaskFromInboundWebhook(s, "inboundwebhooktoken1", &InboundWebhookPayload{Title: "Test", Priority: 6})
+		require.Error(t, err)
+		assert.IsType(t, ValidationHTTPError{}, err)
+	})
+	t.Run("creator lost access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := CreateTaskFromInboundWebhook(s, "inboundwebhooktoken2", &InboundWebhookPayload{Title: "Test"})
+		require.Error(t, err)
+		assert.IsType(t, ErrGenericForbidden{}, err)
+	})
+}
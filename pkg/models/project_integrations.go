@@ -0,0 +1,527 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"xorm.io/xorm"
+)
+
+// ProjectIntegrationKind is the chat service a project integration posts to
+type ProjectIntegrationKind string
+
+const (
+	// ProjectIntegrationKindMatrix posts to a Matrix room. The target is the id of the room, like `!abc:example.com`.
+	ProjectIntegrationKindMatrix ProjectIntegrationKind = "matrix"
+	// ProjectIntegrationKindSlack posts to a Slack channel. The target is the url of an incoming webhook of the channel.
+	ProjectIntegrationKindSlack ProjectIntegrationKind = "slack"
+	// ProjectIntegrationKindDiscord posts rich embeds to a Discord channel. The target is the url of a webhook of the channel.
+	ProjectIntegrationKindDiscord ProjectIntegrationKind = "discord"
+)
+
+// projectIntegrationKinds holds when each kind of integration can be used and how its messages are sent
+var projectIntegrationKinds = map[ProjectIntegrationKind]struct {
+	enabled     config.Key
+	validTarget func(target string) bool
+	send        func(i *ProjectIntegration, m *projectIntegrationMessage) error
+}{
+	ProjectIntegrationKindMatrix: {
+		enabled:     config.MatrixEnabled,
+		validTarget: isValidMatrixRoomID,
+		send:        sendProjectIntegrationMatrixMessage,
+	},
+	ProjectIntegrationKindSlack: {
+		enabled:     config.SlackEnabled,
+		validTarget: isValidSlackWebhookURL,
+		send:        sendProjectIntegrationSlackMessage,
+	},
+	ProjectIntegrationKindDiscord: {
+		enabled:     config.DiscordEnabled,
+		validTarget: isValidDiscordWebhookURL,
+		send:        sendProjectIntegrationDiscordMessage,
+	},
+}
+
+const projectIntegrationEventTaskReminder = "task.reminder"
+
+// projectIntegrationEvents holds all events a project integration can post about
+var projectIntegrationEvents = []string{
+	(&TaskCreatedEvent{}).Name(),
+	(&TaskUpdatedEvent{}).Name(),
+	(&TaskDoneEvent{}).Name(),
+	(&TaskDeletedEvent{}).Name(),
+	(&TaskAssigneeCreatedEvent{}).Name(),
+	(&TaskCommentCreatedEvent{}).Name(),
+	projectIntegrationEventTaskReminder,
+}
+
+// ProjectIntegration posts human-readable messages about the tasks of a project to a room or channel of a chat service.
+type ProjectIntegration struct {
+	// The unique, numeric id of this integration.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"integration"`
+	// The project this integration belongs to. It also gets the events of all child projects.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id" param:"project"`
+	// The chat service the messages are posted to. Can be `matrix`, `slack` or `discord`.
+	Kind ProjectIntegrationKind `xorm:"varchar(50) not null" json:"kind" valid:"required"`
+	// Where the messages are posted. For `matrix` this is the id of the room. The bot user of the instance needs
+	// to be invited to the room. For `slack` this is the url of an incoming webhook of the channel, for `discord` the url
+	// of a webhook of the channel.
+	Target string `xorm:"text not null" json:"target" valid:"required"`
+	// The id of the Slack channel of a `slack` integration. If set, the slash command and the buttons of the Slack
+	// app create and complete tasks in this project when used in that channel, on behalf of the user who created the integration.
+	ChannelID string `xorm:"varchar(250) null INDEX" json:"channel_id"`
+	// The events which are posted. Can be `task.created`, `task.updated`, `task.done`, `task.deleted`,
+	// `task.assignee.created`, `task.comment.created` and `task.reminder`.
+	Events []string `xorm:"JSON not null" json:"events" valid:"required"`
+
+	// The user who initially created the integration.
+	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this integration was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this integration was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project integrations
+func (*ProjectIntegration) TableName() string {
+	return "project_integrations"
+}
+
+func getProjectIntegrationByID(s *xorm.Session, id int64) (integration *ProjectIntegration, err error) {
+	integration = &ProjectIntegration{}
+	exists, err := s.
+		Where("id = ?", id).
+		Get(integration)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrProjectIntegrationDoesNotExist{IntegrationID: id}
+	}
+	return integration, nil
+}
+
+func (i *ProjectIntegration) validate() error {
+	kind, has := projectIntegrationKinds[i.Kind]
+	if !has || !kind.enabled.GetBool() {
+		return &ErrInvalidProjectIntegrationKind{Kind: i.Kind}
+	}
+
+	if !kind.validTarget(i.Target) {
+		return InvalidFieldError([]string{"target"})
+	}
+
+	if len(i.Events) == 0 {
+		return InvalidFieldError([]string{"events"})
+	}
+	for _, event := range i.Events {
+		if !isProjectIntegrationEvent(event) {
+			return InvalidFieldError([]string{"events"})
+		}
+	}
+
+	return nil
+}
+
+func isProjectIntegrationEvent(event string) bool {
+	for _, e := range projectIntegrationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Create creates a new project integration
+// @Summary Create a project integration
+// @Description Creates an integration which posts messages about the tasks of the project and all its child projects to a room of a chat service.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param integration body models.ProjectIntegration true "The integration"
+// @Success 201 {object} models.ProjectIntegration "The created integration."
+// @Failure 400 {object} web.HTTPError "Invalid integration provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/integrations [put]
+func (i *ProjectIntegration) Create(s *xorm.Session, a web.Auth) (err error) {
+	i.ID = 0
+
+	err = i.validate()
+	if err != nil {
+		return
+	}
+
+	i.CreatedByID = a.GetID()
+	_, err = s.Insert(i)
+	if err != nil {
+		return
+	}
+
+	i.CreatedBy, err = user.GetUserByID(s, a.GetID())
+	return
+}
+
+// ReadAll returns all integrations of a project
+// @Summary Get all integrations of a project
+// @Description Returns all integrations of a project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {array} models.ProjectIntegration "The integrations."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/integrations [get]
+func (i *ProjectIntegration) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	project := &Project{ID: i.ProjectID}
+	can, _, err := project.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	integrations := []*ProjectIntegration{}
+	err = s.
+		Where("project_id = ?", i.ProjectID).
+		OrderBy("id asc").
+		Find(&integrations)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addCreatorsToProjectIntegrations(s, integrations)
+	return integrations, len(integrations), int64(len(integrations)), err
+}
+
+// ReadOne returns one project integration
+// @Summary Get one project integration
+// @Description Returns one integration of a project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param integrationID path int true "Integration ID"
+// @Success 200 {object} models.ProjectIntegration "The integration."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The integration does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/integrations/{integrationID} [get]
+func (i *ProjectIntegration) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	integration, err := getProjectIntegrationByID(s, i.ID)
+	if err != nil {
+		return err
+	}
+	*i = *integration
+	return addCreatorsToProjectIntegrations(s, []*ProjectIntegration{i})
+}
+
+// Update updates a project integration
+// @Summary Update a project integration
+// @Description Updates the target and events of a project integration. The kind of an integration cannot be changed.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param integrationID path int true "Integration ID"
+// @Param integration body models.ProjectIntegration true "The integration"
+// @Success 200 {object} models.ProjectIntegration "The updated integration."
+// @Failure 400 {object} web.HTTPError "Invalid integration provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The integration does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/integrations/{integrationID} [post]
+func (i *ProjectIntegration) Update(s *xorm.Session, a web.Auth) (err error) {
+	existing, err := getProjectIntegrationByID(s, i.ID)
+	if err != nil {
+		return err
+	}
+	i.Kind = existing.Kind
+
+	err = i.validate()
+	if err != nil {
+		return
+	}
+
+	_, err = s.
+		Where("id = ?", i.ID).
+		Cols("target", "events", "channel_id").
+		Update(i)
+	if err != nil {
+		return
+	}
+
+	return i.ReadOne(s, a)
+}
+
+// Delete deletes a project integration
+// @Summary Delete a project integration
+// @Description Deletes a project integration. Messages which were already posted are kept.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param integrationID path int true "Integration ID"
+// @Success 200 {object} models.Message "The integration was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The integration does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/integrations/{integrationID} [delete]
+func (i *ProjectIntegration) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("id = ?", i.ID).Delete(&ProjectIntegration{})
+	return
+}
+
+func deleteProjectIntegrationsForProject(s *xorm.Session, projectID int64) (err error) {
+	_, err = s.Where("project_id = ?", projectID).Delete(&ProjectIntegration{})
+	return
+}
+
+func addCreatorsToProjectIntegrations(s *xorm.Session, integrations []*ProjectIntegration) error {
+	if len(integrations) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(integrations))
+	for _, i := range integrations {
+		userIDs = append(userIDs, i.CreatedByID)
+	}
+
+	users, err := user.GetUsersByIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range integrations {
+		i.CreatedBy = users[i.CreatedByID]
+	}
+	return nil
+}
+
+// projectIntegrationsEnabled checks if any kind of project integration can be used on this instance
+func projectIntegrationsEnabled() bool {
+	for _, kind := range projectIntegrationKinds {
+		if kind.enabled.GetBool() {
+			return true
+		}
+	}
+	return false
+}
+
+// registerEventForProjectIntegrations makes project integrations post about an event
+func registerEventForProjectIntegrations(event events.Event) {
+	events.RegisterListener(event.Name(), &ProjectIntegrationListener{
+		EventName: event.Name(),
+	})
+}
+
+// projectIntegrationMessage holds everything a message of a project integration is about. The json fields match
+// the ones of the task events.
+type projectIntegrationMessage struct {
+	Event    string       `json:"-"`
+	Project  *Project     `json:"-"`
+	Task     *Task        `json:"task"`
+	Comment  *TaskComment `json:"comment"`
+	Assignee *user.User   `json:"assignee"`
+	Doer     *user.User   `json:"doer"`
+}
+
+// format returns the sentence describing the message. taskTitle formats the title of the task, which is linked to
+// it unless the task was deleted. All other values are passed through escape.
+func (m *projectIntegrationMessage) format(taskTitle func(title, url string) string, escape func(string) string) string {
+	doer := "Someone"
+	if m.Doer != nil {
+		doer = m.Doer.GetName()
+	}
+	doer = escape(doer)
+
+	url := m.Task.GetFrontendURL()
+	if m.Event == (&TaskDeletedEvent{}).Name() {
+		url = ""
+	}
+	task := taskTitle(m.Task.Title, url)
+	project := escape(m.Project.Title)
+
+	switch m.Event {
+	case (&TaskCreatedEvent{}).Name():
+		return fmt.Sprintf("%s created %s in %s", doer, task, project)
+	case (&TaskDoneEvent{}).Name():
+		return fmt.Sprintf("%s marked %s as done in %s", doer, task, project)
+	case (&TaskDeletedEvent{}).Name():
+		return fmt.Sprintf("%s deleted %s in %s", doer, task, project)
+	case (&TaskAssigneeCreatedEvent{}).Name():
+		assignee := "someone"
+		if m.Assignee != nil {
+			assignee = m.Assignee.GetName()
+		}
+		return fmt.Sprintf("%s assigned %s to %s in %s", doer, escape(assignee), task, project)
+	case (&TaskCommentCreatedEvent{}).Name():
+		return fmt.Sprintf("%s commented on %s in %s", doer, task, project)
+	case projectIntegrationEventTaskReminder:
+		return fmt.Sprintf("Reminder: %s in %s", task, project)
+	default:
+		if m.Task.Done {
+			return fmt.Sprintf("%s updated %s in %s, it is done", doer, task, project)
+		}
+		return fmt.Sprintf("%s updated %s in %s", doer, task, project)
+	}
+}
+
+// commentText returns the text of the comment of the message without any formatting
+func (m *projectIntegrationMessage) commentText() string {
+	if m.Comment == nil {
+		return ""
+	}
+	return htmlToSearchText(m.Comment.Comment)
+}
+
+func getProjectIntegrationsForEvent(s *xorm.Session, projectID int64, event string) (integrations []*ProjectIntegration, err error) {
+	parents, err := GetAllParentProjects(s, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]int64, 0, len(parents)+1)
+	projectIDs = append(projectIDs, projectID)
+	for _, p := range parents {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	all := []*ProjectIntegration{}
+	err = s.
+		In("project_id", projectIDs).
+		OrderBy("id asc").
+		Find(&all)
+	if err != nil {
+		return nil, err
+	}
+
+	integrations = []*ProjectIntegration{}
+	for _, i := range all {
+		for _, e := range i.Events {
+			if e == event {
+				integrations = append(integrations, i)
+				break
+			}
+		}
+	}
+	return integrations, nil
+}
+
+// sendToProjectIntegrations posts a message to all integrations of the project of its task and the parents of it
+// which are configured for its event. A failing integration does not keep the message from being posted to the others.
+func sendToProjectIntegrations(s *xorm.Session, m *projectIntegrationMessage) error {
+	integrations, err := getProjectIntegrationsForEvent(s, m.Task.ProjectID, m.Event)
+	if err != nil || len(integrations) == 0 {
+		return err
+	}
+
+	if m.Project == nil {
+		m.Project, err = GetProjectSimpleByID(s, m.Task.ProjectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, i := range integrations {
+		kind, has := projectIntegrationKinds[i.Kind]
+		if !has || !kind.enabled.GetBool() {
+			continue
+		}
+
+		err = kind.send(i, m)
+		if err != nil {
+			log.Errorf("Could not post %s to %s integration %d: %s", m.Event, i.Kind, i.ID, err)
+			continue
+		}
+		log.Debugf("Posted %s for task %d to %s integration %d", m.Event, m.Task.ID, i.Kind, i.ID)
+	}
+
+	return nil
+}
+
+// sendTaskRemindersToProjectIntegrations posts one reminder per task to the project integrations, regardless of how
+// many users got the reminder.
+func sendTaskRemindersToProjectIntegrations(s *xorm.Session, reminders []*ReminderDueNotification) {
+	sent := make(map[int64]bool, len(reminders))
+	for _, r := range reminders {
+		if sent[r.Task.ID] {
+			continue
+		}
+		sent[r.Task.ID] = true
+
+		err := sendToProjectIntegrations(s, &projectIntegrationMessage{
+			Event:   projectIntegrationEventTaskReminder,
+			Project: r.Project,
+			Task:    r.Task,
+		})
+		if err != nil {
+			log.Errorf("[Task Reminder Cron] Could not post reminder for task %d to project integrations: %s", r.Task.ID, err)
+		}
+	}
+}
+
+// ProjectIntegrationListener represents a listener
+type ProjectIntegrationListener struct {
+	EventName string
+}
+
+// Name defines the name for the ProjectIntegrationListener listener
+func (l *ProjectIntegrationListener) Name() string {
+	return "project.integration.listener"
+}
+
+// Handle is executed when the event ProjectIntegrationListener listens on is fired
+func (l *ProjectIntegrationListener) Handle(msg *message.Message) (err error) {
+	m := &projectIntegrationMessage{}
+	err = json.Unmarshal(msg.Payload, m)
+	if err != nil {
+		return err
+	}
+	if m.Task == nil {
+		return nil
+	}
+	m.Event = l.EventName
+
+	s := db.NewSession()
+	defer s.Close()
+
+	return sendToProjectIntegrations(s, m)
+}
@@ -0,0 +1,125 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/notifications"
+)
+
+// The colors of the embeds, as decimal rgb values
+const (
+	discordColorCreated = 0x1973ff
+	discordColorDone    = 0x2ecc71
+	discordColorComment = 0xf1c40f
+	discordColorDeleted = 0xe74c3c
+	discordColorDefault = 0x99aab5
+)
+
+// Discord limits the length of the title and description of an embed
+const (
+	discordMaxTitleLength       = 256
+	discordMaxDescriptionLength = 4096
+)
+
+var discordEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`)
+
+func isValidDiscordWebhookURL(target string) bool {
+	u, err := url.Parse(target)
+	return err == nil && u.Scheme == "https" && u.Host != "" && strings.HasPrefix(u.Path, "/api/webhooks/")
+}
+
+func truncateRunes(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return string(runes[:maxLength-1]) + "…"
+}
+
+func discordColorForEvent(event string) int {
+	switch event {
+	case (&TaskCreatedEvent{}).Name():
+		return discordColorCreated
+	case (&TaskDoneEvent{}).Name():
+		return discordColorDone
+	case (&TaskCommentCreatedEvent{}).Name():
+		return discordColorComment
+	case (&TaskDeletedEvent{}).Name():
+		return discordColorDeleted
+	}
+	return discordColorDefault
+}
+
+// discordEmbedForMessage creates the embed for a message of a project integration. The title links to the task,
+// the fields show the project, due date and assignees of it.
+func discordEmbedForMessage(m *projectIntegrationMessage, now time.Time) *notifications.DiscordEmbed {
+	description := m.format(func(title, _ string) string {
+		return "**" + discordEscaper.Replace(title) + "**"
+	}, discordEscaper.Replace)
+	if comment := m.commentText(); comment != "" {
+		description += "\n> " + discordEscaper.Replace(comment)
+	}
+
+	embed := &notifications.DiscordEmbed{
+		Title:       truncateRunes(m.Task.Title, discordMaxTitleLength),
+		Description: truncateRunes(description, discordMaxDescriptionLength),
+		Color:       discordColorForEvent(m.Event),
+		Timestamp:   now.Format(time.RFC3339),
+		Fields: []*notifications.DiscordEmbedField{
+			{Name: "Project", Value: discordEscaper.Replace(m.Project.Title), Inline: true},
+		},
+		Footer: &notifications.DiscordEmbedFooter{Text: "Vikunja"},
+	}
+	if m.Event != (&TaskDeletedEvent{}).Name() {
+		embed.URL = m.Task.GetFrontendURL()
+	}
+	if m.Doer != nil {
+		embed.Author = &notifications.DiscordEmbedAuthor{Name: m.Doer.GetName()}
+	}
+	if !m.Task.DueDate.IsZero() {
+		// Discord shows timestamps in the time zone of whoever looks at them
+		embed.Fields = append(embed.Fields, &notifications.DiscordEmbedField{
+			Name:   "Due",
+			Value:  "<t:" + strconv.FormatInt(m.Task.DueDate.Unix(), 10) + ":f>",
+			Inline: true,
+		})
+	}
+	if len(m.Task.Assignees) > 0 {
+		names := make([]string, 0, len(m.Task.Assignees))
+		for _, a := range m.Task.Assignees {
+			names = append(names, discordEscaper.Replace(a.GetName()))
+		}
+		embed.Fields = append(embed.Fields, &notifications.DiscordEmbedField{
+			Name:   "Assignees",
+			Value:  strings.Join(names, ", "),
+			Inline: true,
+		})
+	}
+
+	return embed
+}
+
+func sendProjectIntegrationDiscordMessage(i *ProjectIntegration, m *projectIntegrationMessage) error {
+	return notifications.SendDiscordMessage(i.Target, &notifications.DiscordMessage{
+		Embeds: []*notifications.DiscordEmbed{discordEmbedForMessage(m, time.Now())},
+	})
+}
@@ -0,0 +1,127 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscordEmbedForMessage(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := &Task{
+		ID:        1,
+		Title:     "task_#1",
+		ProjectID: 1,
+		DueDate:   time.Unix(1700000000, 0),
+		Assignees: []*user.User{{Username: "user1"}, {Username: "user2", Name: "User Two"}},
+	}
+
+	t.Run("done", func(t *testing.T) {
+		embed := discordEmbedForMessage(&projectIntegrationMessage{
+			Event:   "task.done",
+			Project: &Project{Title: "Test1"},
+			Task:    task,
+			Doer:    &user.User{Username: "user1"},
+		}, now)
+
+		assert.Equal(t, "task_#1", embed.Title)
+		assert.Equal(t, task.GetFrontendURL(), embed.URL)
+		assert.Equal(t, `user1 marked **task\_#1** as done in Test1`, embed.Description)
+		assert.Equal(t, discordColorDone, embed.Color)
+		assert.Equal(t, "2023-03-01T12:00:00Z", embed.Timestamp)
+		assert.Equal(t, "user1", embed.Author.Name)
+		require.Len(t, embed.Fields, 3)
+		assert.Equal(t, "<t:1700000000:f>", embed.Fields[1].Value)
+		assert.Equal(t, "user1, User Two", embed.Fields[2].Value)
+	})
+	t.Run("comment", func(t *testing.T) {
+		embed := discordEmbedForMessage(&projectIntegrationMessage{
+			Event:   "task.comment.created",
+			Project: &Project{Title: "Test1"},
+			Task:    &Task{ID: 1, Title: "task #1"},
+			Comment: &TaskComment{Comment: "<p>Looks good</p>"},
+		}, now)
+
+		assert.Equal(t, "Someone commented on **task #1** in Test1\n> Looks good", embed.Description)
+		assert.Equal(t, discordColorComment, embed.Color)
+		assert.Nil(t, embed.Author)
+		assert.Len(t, embed.Fields, 1)
+	})
+	t.Run("deleted task is not linked", func(t *testing.T) {
+		embed := discordEmbedForMessage(&projectIntegrationMessage{
+			Event:   "task.deleted",
+			Project: &Project{Title: "Test1"},
+			Task:    &Task{ID: 1, Title: "task #1"},
+		}, now)
+
+		assert.Empty(t, embed.URL)
+	})
+}
+
+func TestSendProjectIntegrationDiscordMessage(t *testing.T) {
+	var messages []*notifications.DiscordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg := &notifications.DiscordMessage{}
+		err := json.NewDecoder(r.Body).Decode(msg)
+		require.NoError(t, err)
+		messages = append(messages, msg)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config.DiscordEnabled.Set(true)
+	defer config.DiscordEnabled.Set(false)
+
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err := s.Insert(&ProjectIntegration{
+		ProjectID:   1,
+		Kind:        ProjectIntegrationKindDiscord,
+		Target:      server.URL + "/api/webhooks/1/abc",
+		Events:      []string{"task.done"},
+		CreatedByID: 1,
+	})
+	require.NoError(t, err)
+
+	task, err := GetTaskByIDSimple(s, 1)
+	require.NoError(t, err)
+	err = sendToProjectIntegrations(s, &projectIntegrationMessage{Event: "task.done", Task: &task})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Len(t, messages[0].Embeds, 1)
+	assert.Equal(t, "task #1", messages[0].Embeds[0].Title)
+}
+
+func TestIsValidDiscordWebhookURL(t *testing.T) {
+	assert.True(t, isValidDiscordWebhookURL("https://discord.com/api/webhooks/123/abc"))
+	assert.False(t, isValidDiscordWebhookURL("http://discord.com/api/webhooks/123/abc"))
+	assert.False(t, isValidDiscordWebhookURL("https://discord.com/channels/123"))
+}
@@ -0,0 +1,55 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"html"
+	"strings"
+
+	"code.vikunja.io/api/pkg/notifications"
+)
+
+// isValidMatrixRoomID checks a target is a room id. Aliases like #room:example.com can't be used to post messages.
+func isValidMatrixRoomID(target string) bool {
+	return strings.HasPrefix(target, "!") && strings.Contains(target, ":")
+}
+
+func sendProjectIntegrationMatrixMessage(i *ProjectIntegration, m *projectIntegrationMessage) error {
+	plain := m.format(func(title, url string) string {
+		if url == "" {
+			return `"` + title + `"`
+		}
+		return `"` + title + `" (` + url + `)`
+	}, func(s string) string { return s })
+
+	formatted := m.format(func(title, url string) string {
+		if url == "" {
+			return "<strong>" + html.EscapeString(title) + "</strong>"
+		}
+		return `<a href="` + html.EscapeString(url) + `">` + html.EscapeString(title) + "</a>"
+	}, html.EscapeString)
+
+	if comment := m.commentText(); comment != "" {
+		plain += "\n> " + comment
+		formatted += "<blockquote>" + html.EscapeString(comment) + "</blockquote>"
+	}
+
+	return notifications.SendMatrixMessage(i.Target, &notifications.MatrixMessage{
+		Body:          plain,
+		FormattedBody: formatted,
+	})
+}
@@ -0,0 +1,79 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see a project integration
+func (i *ProjectIntegration) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	integration, err := i.getForProject(s)
+	if err != nil {
+		return false, 0, err
+	}
+
+	project := &Project{ID: integration.ProjectID}
+	return project.CanRead(s, a)
+}
+
+// CanCreate checks if the user can create an integration for a project
+func (i *ProjectIntegration) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if getSavedFilterIDFromProjectID(i.ProjectID) > 0 {
+		return false, nil
+	}
+
+	return canDoProjectIntegration(s, a, i.ProjectID)
+}
+
+// CanUpdate checks if the user can update a project integration
+func (i *ProjectIntegration) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	integration, err := i.getForProject(s)
+	if err != nil {
+		return false, err
+	}
+
+	return canDoProjectIntegration(s, a, integration.ProjectID)
+}
+
+// CanDelete checks if the user can delete a project integration
+func (i *ProjectIntegration) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return i.CanUpdate(s, a)
+}
+
+// Integrations post to rooms outside of Vikunja, only users who can change the project can manage them.
+func canDoProjectIntegration(s *xorm.Session, a web.Auth, projectID int64) (bool, error) {
+	if _, isShareAuth := a.(*LinkSharing); isShareAuth {
+		return false, nil
+	}
+
+	project := &Project{ID: projectID}
+	return project.CanUpdate(s, a)
+}
+
+// getForProject returns the integration and makes sure it belongs to the project from the request
+func (i *ProjectIntegration) getForProject(s *xorm.Session) (*ProjectIntegration, error) {
+	integration, err := getProjectIntegrationByID(s, i.ID)
+	if err != nil {
+		return nil, err
+	}
+	if i.ProjectID != 0 && integration.ProjectID != i.ProjectID {
+		return nil, &ErrProjectIntegrationDoesNotExist{IntegrationID: i.ID}
+	}
+	return integration, nil
+}
@@ -0,0 +1,252 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"xorm.io/xorm"
+)
+
+// slackActionTaskDone is the id of the button which marks a task as done
+const slackActionTaskDone = "vikunja_task_done"
+
+const slackCommandHelp = "Use `add <title>` to create a task in the project of this channel and `done <task id>` to mark one as done."
+
+func isValidSlackWebhookURL(target string) bool {
+	u, err := url.Parse(target)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+func slackTaskLink(title, url string) string {
+	if url == "" {
+		return "*" + notifications.SlackEscape(title) + "*"
+	}
+	return "<" + url + "|" + notifications.SlackEscape(title) + ">"
+}
+
+// slackTaskBlocks returns the blocks of a message about a task with a button to mark it as done
+func slackTaskBlocks(text string, task *Task) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		},
+		{
+			"type": "actions",
+			"elements": []map[string]interface{}{
+				{
+					"type":      "button",
+					"action_id": slackActionTaskDone,
+					"value":     strconv.FormatInt(task.ID, 10),
+					"text":      map[string]string{"type": "plain_text", "text": "Mark as done"},
+				},
+			},
+		},
+	}
+}
+
+func sendProjectIntegrationSlackMessage(i *ProjectIntegration, m *projectIntegrationMessage) error {
+	text := m.format(slackTaskLink, notifications.SlackEscape)
+	if comment := m.commentText(); comment != "" {
+		text += "\n>" + notifications.SlackEscape(comment)
+	}
+
+	msg := &notifications.SlackMessage{Text: text}
+	// The buttons only work in channels which are connected to the project
+	if i.ChannelID != "" && m.Event != (&TaskDeletedEvent{}).Name() && !m.Task.Done {
+		msg.Blocks = slackTaskBlocks(text, m.Task)
+	}
+
+	return notifications.SendSlackMessage(i.Target, msg)
+}
+
+// SlackCommand is a slash command of the Slack app
+type SlackCommand struct {
+	// The id of the channel the command was used in.
+	ChannelID string
+	// The name of the Slack user who used the command.
+	UserName string
+	// Everything after the command itself, for example `add Buy milk`.
+	Text string
+}
+
+// SlackAction is a click on a button of a message of the Slack app
+type SlackAction struct {
+	// The id of the channel the message with the button is in.
+	ChannelID string
+	// The name of the Slack user who clicked the button.
+	UserName string
+	// The action_id and value of the button.
+	ActionID string
+	Value    string
+}
+
+// getSlackIntegrationForChannel returns the integration a Slack channel is connected to and the user who created it,
+// who creates and completes the tasks for commands from that channel.
+func getSlackIntegrationForChannel(s *xorm.Session, channelID string) (integration *ProjectIntegration, creator *user.User, err error) {
+	integration = &ProjectIntegration{}
+	exists, err := s.
+		Where("kind = ? AND channel_id = ?", ProjectIntegrationKindSlack, channelID).
+		OrderBy("id asc").
+		Get(integration)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists || channelID == "" {
+		return nil, nil, &ErrSlackChannelNotConnected{ChannelID: channelID}
+	}
+
+	creator, err = user.GetUserByID(s, integration.CreatedByID)
+	return integration, creator, err
+}
+
+// HandleSlackCommand runs a slash command of the Slack app and returns the response for Slack
+func HandleSlackCommand(s *xorm.Session, cmd *SlackCommand) (*notifications.SlackMessage, error) {
+	integration, creator, err := getSlackIntegrationForChannel(s, cmd.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(cmd.Text)
+	command, args, _ := strings.Cut(text, " ")
+	args = strings.TrimSpace(args)
+
+	switch {
+	case strings.EqualFold(command, "add") && args != "":
+		return createTaskFromSlack(s, integration, creator, cmd.UserName, args)
+	case strings.EqualFold(command, "done") && args != "":
+		taskID, err := strconv.ParseInt(strings.TrimPrefix(args, "#"), 10, 64)
+		if err != nil {
+			break
+		}
+		return completeTaskFromSlack(s, integration, creator, cmd.UserName, taskID)
+	}
+
+	return &notifications.SlackMessage{ResponseType: "ephemeral", Text: slackCommandHelp}, nil
+}
+
+// HandleSlackAction runs the action of a button of the Slack app and returns the message which replaces the one
+// with the button. Returns nil for buttons which are not from Vikunja.
+func HandleSlackAction(s *xorm.Session, action *SlackAction) (*notifications.SlackMessage, error) {
+	if action.ActionID != slackActionTaskDone {
+		return nil, nil
+	}
+
+	taskID, err := strconv.ParseInt(action.Value, 10, 64)
+	if err != nil {
+		return nil, ErrTaskDoesNotExist{ID: taskID}
+	}
+
+	integration, creator, err := getSlackIntegrationForChannel(s, action.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := completeTaskFromSlack(s, integration, creator, action.UserName, taskID)
+	if err != nil {
+		return nil, err
+	}
+	msg.ReplaceOriginal = true
+	return msg, nil
+}
+
+func createTaskFromSlack(s *xorm.Session, integration *ProjectIntegration, creator *user.User, slackUser, title string) (*notifications.SlackMessage, error) {
+	runes := []rune(title)
+	if len(runes) > 250 {
+		title = string(runes[:250])
+	}
+
+	task := &Task{
+		Title:     title,
+		ProjectID: integration.ProjectID,
+		Source:    TaskSourceSlack,
+	}
+
+	// The creator of the integration might have lost access to the project since the integration was created
+	can, err := task.CanCreate(s, creator)
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, ErrGenericForbidden{}
+	}
+
+	err = task.Create(s, creator)
+	if err != nil {
+		return nil, err
+	}
+
+	text := notifications.SlackEscape(slackUser) + " created " + slackTaskLink(task.Title, task.GetFrontendURL())
+	return &notifications.SlackMessage{
+		ResponseType: "in_channel",
+		Text:         text,
+		Blocks:       slackTaskBlocks(text, task),
+	}, nil
+}
+
+func completeTaskFromSlack(s *xorm.Session, integration *ProjectIntegration, creator *user.User, slackUser string, taskID int64) (*notifications.SlackMessage, error) {
+	task, err := GetTaskByIDSimple(s, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only tasks of the project of the integration and its child projects can be completed from its channel
+	if task.ProjectID != integration.ProjectID {
+		parents, err := GetAllParentProjects(s, task.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if _, has := parents[integration.ProjectID]; !has {
+			return nil, ErrTaskDoesNotExist{ID: taskID}
+		}
+	}
+
+	link := slackTaskLink(task.Title, task.GetFrontendURL())
+	if task.Done {
+		return &notifications.SlackMessage{ResponseType: "ephemeral", Text: link + " is already done."}, nil
+	}
+
+	can, err := task.CanUpdate(s, creator)
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, ErrGenericForbidden{}
+	}
+
+	// Task.Update replaces all fields of a task, so we need to get the full task first
+	err = addMoreInfoToTasks(s, map[int64]*Task{task.ID: &task}, creator)
+	if err != nil {
+		return nil, err
+	}
+	task.Done = true
+	err = task.Update(s, creator)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notifications.SlackMessage{
+		ResponseType: "in_channel",
+		Text:         notifications.SlackEscape(slackUser) + " marked " + link + " as done.",
+	}, nil
+}
@@ -0,0 +1,126 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSlackCommand(t *testing.T) {
+	t.Run("add", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		msg, err := HandleSlackCommand(s, &SlackCommand{ChannelID: "C0PROJECT1", UserName: "jane", Text: "add Buy <milk>"})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, "in_channel", msg.ResponseType)
+		assert.Contains(t, msg.Text, "jane created <")
+		assert.Contains(t, msg.Text, "|Buy &lt;milk&gt;>")
+		assert.Len(t, msg.Blocks, 2)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"title":         "Buy <milk>",
+			"project_id":    1,
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		msg, err := HandleSlackCommand(s, &SlackCommand{ChannelID: "C0PROJECT1", UserName: "jane", Text: "done #1"})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Contains(t, msg.Text, "jane marked <")
+		db.AssertExists(t, "tasks", map[string]interface{}{"id": 1, "done": true}, false)
+		// Task.Update replaces all fields, make sure nothing was lost
+		db.AssertExists(t, "tasks", map[string]interface{}{"id": 1, "description": "Lorem Ipsum"}, false)
+	})
+	t.Run("done task of other project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := HandleSlackCommand(s, &SlackCommand{ChannelID: "C0PROJECT1", UserName: "jane", Text: "done 14"})
+		require.Error(t, err)
+		assert.True(t, IsErrTaskDoesNotExist(err))
+	})
+	t.Run("help", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		msg, err := HandleSlackCommand(s, &SlackCommand{ChannelID: "C0PROJECT1", UserName: "jane", Text: "done"})
+		require.NoError(t, err)
+		assert.Equal(t, "ephemeral", msg.ResponseType)
+		assert.Equal(t, slackCommandHelp, msg.Text)
+	})
+	t.Run("channel not connected", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := HandleSlackCommand(s, &SlackCommand{ChannelID: "C0OTHER", UserName: "jane", Text: "add Something"})
+		require.Error(t, err)
+		assert.True(t, IsErrSlackChannelNotConnected(err))
+	})
+}
+
+func TestHandleSlackAction(t *testing.T) {
+	t.Run("mark done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		msg, err := HandleSlackAction(s, &SlackAction{ChannelID: "C0PROJECT1", UserName: "jane", ActionID: slackActionTaskDone, Value: "1"})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.True(t, msg.ReplaceOriginal)
+		db.AssertExists(t, "tasks", map[string]interface{}{"id": 1, "done": true}, false)
+	})
+	t.Run("already done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		msg, err := HandleSlackAction(s, &SlackAction{ChannelID: "C0PROJECT1", UserName: "jane", ActionID: slackActionTaskDone, Value: "2"})
+		require.NoError(t, err)
+		assert.Contains(t, msg.Text, "is already done.")
+	})
+	t.Run("other button", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		msg, err := HandleSlackAction(s, &SlackAction{ChannelID: "C0PROJECT1", UserName: "jane", ActionID: "something_else", Value: "1"})
+		require.NoError(t, err)
+		assert.Nil(t, msg)
+	})
+}
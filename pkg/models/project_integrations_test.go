@@ -0,0 +1,216 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectIntegration_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+	config.MatrixEnabled.Set(true)
+	defer config.MatrixEnabled.Set(false)
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		i := &ProjectIntegration{ProjectID: 1, Kind: ProjectIntegrationKindMatrix, Target: "!room:example.com", Events: []string{"task.created", "task.reminder"}}
+		can, err := i.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = i.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_integrations", map[string]interface{}{
+			"id":            i.ID,
+			"project_id":    1,
+			"kind":          "matrix",
+			"target":        "!room:example.com",
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("disabled kind", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.MatrixEnabled.Set(false)
+		defer config.MatrixEnabled.Set(true)
+
+		i := &ProjectIntegration{ProjectID: 1, Kind: ProjectIntegrationKindMatrix, Target: "!room:example.com", Events: []string{"task.created"}}
+		err := i.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidProjectIntegrationKind(err))
+	})
+	t.Run("room alias", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		i := &ProjectIntegration{ProjectID: 1, Kind: ProjectIntegrationKindMatrix, Target: "#room:example.com", Events: []string{"task.created"}}
+		err := i.Create(s, u)
+		require.Error(t, err)
+		assert.IsType(t, ValidationHTTPError{}, err)
+	})
+	t.Run("invalid event", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		i := &ProjectIntegration{ProjectID: 1, Kind: ProjectIntegrationKindMatrix, Target: "!room:example.com", Events: []string{"team.created"}}
+		err := i.Create(s, u)
+		require.Error(t, err)
+		assert.IsType(t, ValidationHTTPError{}, err)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		i := &ProjectIntegration{ProjectID: 1}
+		can, err := i.CanCreate(s, &LinkSharing{ID: 1, ProjectID: 1, Right: RightAdmin})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestProjectIntegration_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	i := &ProjectIntegration{ProjectID: 1}
+	result, _, _, err := i.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+	require.NoError(t, err)
+	integrations := result.([]*ProjectIntegration)
+	require.Len(t, integrations, 2)
+	assert.Equal(t, "!project1:example.com", integrations[0].Target)
+	assert.Equal(t, ProjectIntegrationKindSlack, integrations[1].Kind)
+	assert.Equal(t, int64(1), integrations[0].CreatedBy.ID)
+
+	_, _, _, err = i.ReadAll(s, &user.User{ID: 13}, "", 0, 0)
+	require.Error(t, err)
+	assert.True(t, IsErrGenericForbidden(err))
+}
+
+func TestProjectIntegration_Update(t *testing.T) {
+	config.MatrixEnabled.Set(true)
+	defer config.MatrixEnabled.Set(false)
+
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	// Integration 2 belongs to project 3
+	i := &ProjectIntegration{ID: 2, ProjectID: 1}
+	_, err := i.CanUpdate(s, &user.User{ID: 1})
+	require.Error(t, err)
+	assert.True(t, IsErrProjectIntegrationDoesNotExist(err))
+
+	i = &ProjectIntegration{ID: 1, ProjectID: 1, Kind: "slack", Target: "!other:example.com", Events: []string{"task.deleted"}}
+	err = i.Update(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	assert.Equal(t, ProjectIntegrationKindMatrix, i.Kind)
+	db.AssertExists(t, "project_integrations", map[string]interface{}{
+		"id":     1,
+		"kind":   "matrix",
+		"target": "!other:example.com",
+	}, false)
+}
+
+func TestSendToProjectIntegrations(t *testing.T) {
+	var paths []string
+	var messages []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		msg := map[string]string{}
+		err := json.NewDecoder(r.Body).Decode(&msg)
+		require.NoError(t, err)
+		paths = append(paths, r.URL.Path)
+		messages = append(messages, msg)
+	}))
+	defer server.Close()
+
+	config.MatrixEnabled.Set(true)
+	config.MatrixHomeserverURL.Set(server.URL)
+	defer config.MatrixEnabled.Set(false)
+
+	t.Run("task created", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		paths, messages = nil, nil
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		err = sendToProjectIntegrations(s, &projectIntegrationMessage{
+			Event: "task.created",
+			Task:  &task,
+			Doer:  &user.User{ID: 1, Username: "user1"},
+		})
+		require.NoError(t, err)
+		require.Len(t, paths, 1)
+		assert.Contains(t, paths[0], "/_matrix/client/v3/rooms/!project1:example.com/send/m.room.message/")
+		assert.Equal(t, `user1 created "task #1" (`+task.GetFrontendURL()+`) in Test1`, messages[0]["body"])
+		assert.Contains(t, messages[0]["formatted_body"], `<a href="`+task.GetFrontendURL()+`">task #1</a>`)
+	})
+	t.Run("comment", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		paths, messages = nil, nil
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		err = sendToProjectIntegrations(s, &projectIntegrationMessage{
+			Event:   "task.comment.created",
+			Task:    &task,
+			Comment: &TaskComment{Comment: "<p>Looks <strong>good</strong></p>"},
+		})
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0]["body"], "\n> Looks good")
+	})
+	t.Run("event not configured", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		paths, messages = nil, nil
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		err = sendToProjectIntegrations(s, &projectIntegrationMessage{Event: "task.deleted", Task: &task})
+		require.NoError(t, err)
+		assert.Empty(t, paths)
+	})
+}
@@ -0,0 +1,392 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// ProjectLabelRule automatically adds labels to or sets the priority of all tasks of a project which match a filter
+// when they are created or updated.
+type ProjectLabelRule struct {
+	// The unique, numeric id of this rule.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"labelrule"`
+	// The project this rule belongs to.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id" param:"project"`
+	// A title to recognize the rule.
+	Title string `xorm:"varchar(250) null" json:"title" valid:"runelength(0|250)" maxLength:"250"`
+	// The filter query tasks need to match for the rule to apply, for example `title ~ bug`.
+	// Check out the docs about filters for all possible fields.
+	Filter string `xorm:"text not null" json:"filter" valid:"required"`
+	// The ids of the labels which are added to matching tasks.
+	LabelIDs []int64 `xorm:"'label_ids' JSON null" json:"label_ids"`
+	// The priority matching tasks get. 0 leaves the priority unchanged.
+	Priority int64 `xorm:"bigint null" json:"priority"`
+
+	// The user who initially created the rule.
+	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this rule was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this rule was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project label rules
+func (*ProjectLabelRule) TableName() string {
+	return "project_label_rules"
+}
+
+func getProjectLabelRuleByID(s *xorm.Session, id int64) (rule *ProjectLabelRule, err error) {
+	rule = &ProjectLabelRule{}
+	exists, err := s.
+		Where("id = ?", id).
+		Get(rule)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrProjectLabelRuleDoesNotExist{RuleID: id}
+	}
+	return rule, nil
+}
+
+func getProjectLabelRulesForProject(s *xorm.Session, projectID int64) (rules []*ProjectLabelRule, err error) {
+	rules = []*ProjectLabelRule{}
+	err = s.
+		Where("project_id = ?", projectID).
+		OrderBy("id asc").
+		Find(&rules)
+	return
+}
+
+// validate checks the filter of the rule can be parsed and the user has access to all labels of it
+func (r *ProjectLabelRule) validate(s *xorm.Session, a web.Auth) error {
+	if len(r.LabelIDs) == 0 && r.Priority == 0 {
+		return &ErrProjectLabelRuleHasNoEffect{}
+	}
+
+	if _, err := getTaskFiltersFromFilterString(r.Filter, ""); err != nil {
+		return err
+	}
+
+	for _, labelID := range r.LabelIDs {
+		label, err := getLabelByIDSimple(s, labelID)
+		if err != nil {
+			return err
+		}
+		can, _, err := label.CanRead(s, a)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return ErrUserHasNoAccessToLabel{LabelID: label.ID, UserID: a.GetID()}
+		}
+	}
+
+	return nil
+}
+
+// Create creates a new label rule
+// @Summary Create a label rule
+// @Description Creates a new rule which adds labels to or sets the priority of all tasks of the project matching its filter when they are created or updated.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param rule body models.ProjectLabelRule true "The rule"
+// @Success 201 {object} models.ProjectLabelRule "The created rule."
+// @Failure 400 {object} web.HTTPError "Invalid rule provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/label_rules [put]
+func (r *ProjectLabelRule) Create(s *xorm.Session, a web.Auth) (err error) {
+	r.ID = 0
+
+	err = r.validate(s, a)
+	if err != nil {
+		return
+	}
+
+	r.CreatedBy, err = GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return
+	}
+	r.CreatedByID = r.CreatedBy.ID
+
+	_, err = s.Insert(r)
+	return
+}
+
+// ReadAll returns all label rules of a project
+// @Summary Get all label rules of a project
+// @Description Returns all label rules of a project in the order they are applied.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {array} models.ProjectLabelRule "The rules."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/label_rules [get]
+func (r *ProjectLabelRule) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	project := &Project{ID: r.ProjectID}
+	canRead, _, err := project.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	rules, err := getProjectLabelRulesForProject(s, r.ProjectID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addCreatorsToProjectLabelRules(s, rules)
+	return rules, len(rules), int64(len(rules)), err
+}
+
+// ReadOne returns one label rule
+// @Summary Get one label rule
+// @Description Returns one label rule of a project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param ruleID path int true "Rule ID"
+// @Success 200 {object} models.ProjectLabelRule "The rule."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The rule does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/label_rules/{ruleID} [get]
+func (r *ProjectLabelRule) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	rule, err := getProjectLabelRuleByID(s, r.ID)
+	if err != nil {
+		return err
+	}
+	*r = *rule
+	return addCreatorsToProjectLabelRules(s, []*ProjectLabelRule{r})
+}
+
+// Update updates a label rule
+// @Summary Update a label rule
+// @Description Updates the title, filter, labels and priority of a label rule.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param ruleID path int true "Rule ID"
+// @Param rule body models.ProjectLabelRule true "The rule"
+// @Success 200 {object} models.ProjectLabelRule "The updated rule."
+// @Failure 400 {object} web.HTTPError "Invalid rule provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The rule does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/label_rules/{ruleID} [post]
+func (r *ProjectLabelRule) Update(s *xorm.Session, a web.Auth) (err error) {
+	err = r.validate(s, a)
+	if err != nil {
+		return
+	}
+
+	_, err = s.
+		Where("id = ?", r.ID).
+		Cols("title", "filter", "label_ids", "priority").
+		Update(r)
+	if err != nil {
+		return
+	}
+
+	return r.ReadOne(s, a)
+}
+
+// Delete deletes a label rule
+// @Summary Delete a label rule
+// @Description Deletes a label rule. Labels and priorities it already set on tasks are kept.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param ruleID path int true "Rule ID"
+// @Success 200 {object} models.Message "The rule was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The rule does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/label_rules/{ruleID} [delete]
+func (r *ProjectLabelRule) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("id = ?", r.ID).Delete(&ProjectLabelRule{})
+	return
+}
+
+func deleteProjectLabelRulesForProject(s *xorm.Session, projectID int64) (err error) {
+	_, err = s.Where("project_id = ?", projectID).Delete(&ProjectLabelRule{})
+	return
+}
+
+func addCreatorsToProjectLabelRules(s *xorm.Session, rules []*ProjectLabelRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(rules))
+	for _, r := range rules {
+		userIDs = append(userIDs, r.CreatedByID)
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rules {
+		r.CreatedBy = users[r.CreatedByID]
+	}
+	return nil
+}
+
+// matches checks if the task matches the filter of the rule
+func (r *ProjectLabelRule) matches(s *xorm.Session, taskID int64) (bool, error) {
+	filters, err := getTaskFiltersFromFilterString(r.Filter, "")
+	if err != nil {
+		return false, err
+	}
+
+	filterCond, err := convertFiltersToDBFilterCond(filters, false, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return s.
+		Where(builder.And(builder.Eq{"id": taskID}, filterCond)).
+		Exist(&Task{})
+}
+
+// applyProjectLabelRules runs all label rules of the project of a task against it. Rules only ever add labels, so
+// labels added by a rule stay when the task stops matching it. If multiple matching rules set a priority, the last one wins.
+func applyProjectLabelRules(s *xorm.Session, taskID int64) (err error) {
+	task, err := GetTaskByIDSimple(s, taskID)
+	if err != nil {
+		return err
+	}
+
+	rules, err := getProjectLabelRulesForProject(s, task.ProjectID)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	var priority int64
+	labelIDs := []int64{}
+	for _, rule := range rules {
+		matches, err := rule.matches(s, task.ID)
+		if err != nil {
+			// A filter which became invalid should not prevent the other rules from being applied
+			log.Errorf("Could not check label rule %d for task %d: %s", rule.ID, task.ID, err)
+			continue
+		}
+		if !matches {
+			continue
+		}
+
+		labelIDs = append(labelIDs, rule.LabelIDs...)
+		if rule.Priority != 0 {
+			priority = rule.Priority
+		}
+	}
+
+	if len(labelIDs) > 0 {
+		// Labels might have been deleted since the rule was created
+		existingLabels := []*Label{}
+		err = s.
+			In("id", labelIDs).
+			NotIn("id", builder.Select("label_id").From("label_tasks").Where(builder.Eq{"task_id": task.ID})).
+			Find(&existingLabels)
+		if err != nil {
+			return err
+		}
+		for _, label := range existingLabels {
+			_, err = s.Insert(&LabelTask{LabelID: label.ID, TaskID: task.ID})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if priority != 0 && priority != task.Priority {
+		_, err = s.
+			Where("id = ?", task.ID).
+			Cols("priority").
+			Update(&Task{Priority: priority})
+	}
+
+	return err
+}
+
+// ApplyProjectLabelRules represents a listener
+type ApplyProjectLabelRules struct {
+}
+
+// Name defines the name for the ApplyProjectLabelRules listener
+func (l *ApplyProjectLabelRules) Name() string {
+	return "task.apply.label.rules"
+}
+
+// Handle is executed when the event ApplyProjectLabelRules listens on is fired
+func (l *ApplyProjectLabelRules) Handle(msg *message.Message) (err error) {
+	// Task created and updated events have the same payload
+	event := &TaskUpdatedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+	if event.Task == nil {
+		return nil
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	err = applyProjectLabelRules(s, event.Task.ID)
+	if err != nil {
+		if IsErrTaskDoesNotExist(err) {
+			return nil
+		}
+		_ = s.Rollback()
+		return err
+	}
+
+	return s.Commit()
+}
@@ -0,0 +1,75 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see a label rule
+func (r *ProjectLabelRule) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	rule, err := r.getForProject(s)
+	if err != nil {
+		return false, 0, err
+	}
+
+	project := &Project{ID: rule.ProjectID}
+	return project.CanRead(s, a)
+}
+
+// CanCreate checks if the user can create a label rule in a project
+func (r *ProjectLabelRule) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if getSavedFilterIDFromProjectID(r.ProjectID) > 0 {
+		return false, nil
+	}
+
+	project := &Project{ID: r.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// CanUpdate checks if the user can update a label rule
+func (r *ProjectLabelRule) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return r.canDoProjectLabelRule(s, a)
+}
+
+// CanDelete checks if the user can delete a label rule
+func (r *ProjectLabelRule) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return r.canDoProjectLabelRule(s, a)
+}
+
+func (r *ProjectLabelRule) canDoProjectLabelRule(s *xorm.Session, a web.Auth) (bool, error) {
+	rule, err := r.getForProject(s)
+	if err != nil {
+		return false, err
+	}
+
+	project := &Project{ID: rule.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// getForProject returns the rule and makes sure it belongs to the project from the request
+func (r *ProjectLabelRule) getForProject(s *xorm.Session) (*ProjectLabelRule, error) {
+	rule, err := getProjectLabelRuleByID(s, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	if r.ProjectID != 0 && rule.ProjectID != r.ProjectID {
+		return nil, &ErrProjectLabelRuleDoesNotExist{RuleID: r.ID}
+	}
+	return rule, nil
+}
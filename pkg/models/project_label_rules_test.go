@@ -0,0 +1,158 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectLabelRule_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectLabelRule{ProjectID: 1, Title: "Bugs", Filter: "title ~ bug", LabelIDs: []int64{2}}
+		can, err := r.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = r.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_label_rules", map[string]interface{}{
+			"id":            r.ID,
+			"project_id":    1,
+			"filter":        "title ~ bug",
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("invalid filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectLabelRule{ProjectID: 1, Filter: "foo = bar", LabelIDs: []int64{1}}
+		err := r.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskField(err))
+	})
+	t.Run("no effect", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectLabelRule{ProjectID: 1, Filter: "done = false"}
+		err := r.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectLabelRuleHasNoEffect(err))
+	})
+	t.Run("label without access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &ProjectLabelRule{ProjectID: 1, Filter: "done = false", LabelIDs: []int64{3}}
+		err := r.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserHasNoAccessToLabel(err))
+	})
+}
+
+func TestProjectLabelRule_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	r := &ProjectLabelRule{ProjectID: 1}
+	result, _, _, err := r.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+	require.NoError(t, err)
+	rules := result.([]*ProjectLabelRule)
+	require.Len(t, rules, 2)
+	assert.Equal(t, []int64{1}, rules[0].LabelIDs)
+	assert.Equal(t, int64(5), rules[1].Priority)
+
+	_, _, _, err = r.ReadAll(s, &user.User{ID: 13}, "", 0, 0)
+	require.Error(t, err)
+	assert.True(t, IsErrGenericForbidden(err))
+}
+
+func TestProjectLabelRule_CanRead(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	// Rule 3 belongs to project 2
+	r := &ProjectLabelRule{ID: 3, ProjectID: 1}
+	_, _, err := r.CanRead(s, &user.User{ID: 1})
+	require.Error(t, err)
+	assert.True(t, IsErrProjectLabelRuleDoesNotExist(err))
+}
+
+func TestApplyProjectLabelRules(t *testing.T) {
+	t.Run("labels and priority", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := applyProjectLabelRules(s, 3)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "label_tasks", map[string]interface{}{"task_id": 3, "label_id": 1}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{"id": 3, "priority": 5}, false)
+	})
+	t.Run("only labels", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := applyProjectLabelRules(s, 4)
+		require.NoError(t, err)
+		err = applyProjectLabelRules(s, 4)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		count, err := s.Where("task_id = ? AND label_id = ?", 4, 1).Count(&LabelTask{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		db.AssertExists(t, "tasks", map[string]interface{}{"id": 4, "priority": 1}, false)
+	})
+	t.Run("no match", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := applyProjectLabelRules(s, 1)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "label_tasks", map[string]interface{}{"task_id": 1, "label_id": 1})
+	})
+}
@@ -0,0 +1,137 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// ProjectMove moves a project with all its child projects below another project
+type ProjectMove struct {
+	// The id of the project which should be moved.
+	ProjectID int64 `json:"-" param:"project"`
+	// The id of the new parent project. Set this to 0 to make the project a top-level project.
+	ParentProjectID int64 `json:"parent_project_id"`
+	// The position of the project below its new parent. If not set, the default position is used.
+	Position float64 `json:"position"`
+
+	// The project with its new parent.
+	Project *Project `json:"project"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanUpdate checks if the user can move the project below the new parent.
+// This needs write access to the project and to the new parent project.
+func (pm *ProjectMove) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	p := &Project{ID: pm.ProjectID, ParentProjectID: pm.ParentProjectID}
+	return p.CanUpdate(s, a)
+}
+
+// Update moves a project below another project
+// @Summary Move a project below another project
+// @Description Moves a project together with all of its child projects below another project. Set the parent project id to 0 to make the project a top-level project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param move body models.ProjectMove true "The new parent project."
+// @Success 200 {object} models.ProjectMove "The project with its new parent."
+// @Failure 400 {object} web.HTTPError "The hierarchy would become too deep or the project cannot be moved below one of its own child projects."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project or the new parent project."
+// @Failure 404 {object} web.HTTPError "The project or parent project does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/move [post]
+func (pm *ProjectMove) Update(s *xorm.Session, a web.Auth) (err error) {
+	project, err := GetProjectSimpleByID(s, pm.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	project.ParentProjectID = pm.ParentProjectID
+	err = checkProjectBeforeUpdateOrDelete(s, project)
+	if err != nil {
+		return err
+	}
+
+	project.Position = calculateDefaultPosition(project.ID, pm.Position)
+	_, err = s.
+		Where("id = ?", project.ID).
+		Cols("parent_project_id", "position").
+		Update(project)
+	if err != nil {
+		return err
+	}
+
+	if project.Position < 0.1 {
+		err = recalculateProjectPositions(s, project.ParentProjectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	pm.Project = project
+	return pm.Project.ReadOne(s, a)
+}
+
+// validateProjectDepth checks the project including all of its child projects does not exceed the
+// configured maximum depth of the hierarchy when placed below its parent.
+// The parents are all parent projects of the new parent, as returned by GetAllParentProjects.
+func validateProjectDepth(s *xorm.Session, project *Project, parents map[int64]*Project) error {
+	maxDepth := config.ServiceMaxProjectDepth.GetInt64()
+
+	// Walk up from the new parent to find out how deep the project would be placed
+	depth := int64(1)
+	visited := make(map[int64]bool)
+	parent := parents[project.ParentProjectID]
+	for parent != nil && parent.ParentProjectID != 0 && !visited[parent.ID] {
+		visited[parent.ID] = true
+		depth++
+		parent = parents[parent.ParentProjectID]
+	}
+
+	// All child projects move with the project, so we need to account for them as well
+	if project.ID != 0 {
+		levelIDs := []int64{project.ID}
+		for len(levelIDs) > 0 && depth <= maxDepth {
+			children := []int64{}
+			err := s.
+				Table("projects").
+				In("parent_project_id", levelIDs).
+				Cols("id").
+				Find(&children)
+			if err != nil {
+				return err
+			}
+			if len(children) == 0 {
+				break
+			}
+			depth++
+			levelIDs = children
+		}
+	}
+
+	if depth > maxDepth {
+		return &ErrProjectHierarchyTooDeep{ProjectID: project.ID, MaxDepth: maxDepth}
+	}
+
+	return nil
+}
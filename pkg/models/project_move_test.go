@@ -0,0 +1,150 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectMove_Update(t *testing.T) {
+	u := &user.User{ID: 6}
+
+	t.Run("to top-level", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMove{ProjectID: 25}
+		can, err := pm.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pm.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), pm.Project.ParentProjectID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":                25,
+			"parent_project_id": 0,
+		}, false)
+		// The child projects move with it
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":                26,
+			"parent_project_id": 25,
+		}, false)
+	})
+	t.Run("below another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMove{ProjectID: 25, ParentProjectID: 13, Position: 42}
+		err := pm.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(13), pm.Project.ParentProjectID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":                25,
+			"parent_project_id": 13,
+			"position":          42,
+		}, false)
+	})
+	t.Run("below own child project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMove{ProjectID: 12, ParentProjectID: 26}
+		err := pm.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectCannotHaveACyclicRelationship(err))
+	})
+	t.Run("below itself", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMove{ProjectID: 12, ParentProjectID: 12}
+		err := pm.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectCannotBeChildOfItsOwn(err))
+	})
+	t.Run("too deep", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.ServiceMaxProjectDepth.Set(2)
+		defer config.ServiceMaxProjectDepth.Set(10)
+
+		// Project 25 would be two levels deep below project 13, its child project 26 three levels
+		pm := &ProjectMove{ProjectID: 25, ParentProjectID: 13}
+		err := pm.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectHierarchyTooDeep(err))
+
+		// Without its child project, project 26 fits
+		pm = &ProjectMove{ProjectID: 26, ParentProjectID: 13}
+		err = pm.Update(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("no access to the new parent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMove{ProjectID: 25, ParentProjectID: 1}
+		_, err := pm.CanUpdate(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+	t.Run("no access to the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMove{ProjectID: 25}
+		can, err := pm.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestProject_CreateTooDeep(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	config.ServiceMaxProjectDepth.Set(3)
+	defer config.ServiceMaxProjectDepth.Set(10)
+
+	// Project 26 already is three levels deep
+	project := &Project{Title: "too deep", ParentProjectID: 26}
+	err := project.Create(s, &user.User{ID: 6})
+	require.Error(t, err)
+	assert.True(t, IsErrProjectHierarchyTooDeep(err))
+}
@@ -27,6 +27,10 @@ import (
 
 // CanWrite return whether the user can write on that project or not
 func (p *Project) CanWrite(s *xorm.Session, a web.Auth) (bool, error) {
+	can, err := apiTokenCanAccessProject(s, a, p.ID)
+	if !can || err != nil {
+		return false, err
+	}
 
 	// The favorite project can't be edited
 	if p.ID == FavoritesPseudoProject.ID {
@@ -70,6 +74,11 @@ func (p *Project) CanWrite(s *xorm.Session, a web.Auth) (bool, error) {
 
 // CanRead checks if a user has read access to a project
 func (p *Project) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	// Tokens restricted to some projects can't access pseudo projects since they contain tasks from other projects
+	can, err := apiTokenCanAccessProject(s, a, p.ID)
+	if !can || err != nil {
+		return false, 0, err
+	}
 
 	// The favorite project needs a special treatment
 	if p.ID == FavoritesPseudoProject.ID {
@@ -90,7 +99,6 @@ func (p *Project) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
 	}
 
 	// Check if the user is either owner or can read
-	var err error
 	originalProject, err := GetProjectSimpleByID(s, p.ID)
 	if err != nil {
 		return false, 0, err
@@ -173,11 +181,16 @@ func (p *Project) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
 	if is {
 		return false, nil
 	}
-	return true, nil
+	// Tokens restricted to some projects can only create child projects inside them
+	return len(getAPITokenProjectIDs(a)) == 0, nil
 }
 
 // IsAdmin returns whether the user has admin rights on the project or not
 func (p *Project) IsAdmin(s *xorm.Session, a web.Auth) (bool, error) {
+	can, err := apiTokenCanAccessProject(s, a, p.ID)
+	if !can || err != nil {
+		return false, err
+	}
 	// The favorite project can't be edited
 	if p.ID == FavoritesPseudoProject.ID {
 		return false, nil
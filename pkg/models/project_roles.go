@@ -0,0 +1,397 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// ProjectPermission is a single permission a project role can grant
+type ProjectPermission string
+
+const (
+	// ProjectPermissionCreateTasks allows creating new tasks in the project.
+	ProjectPermissionCreateTasks ProjectPermission = "create_tasks"
+	// ProjectPermissionUpdateTasks allows editing existing tasks of the project.
+	ProjectPermissionUpdateTasks ProjectPermission = "update_tasks"
+	// ProjectPermissionDeleteTasks allows deleting tasks of the project.
+	ProjectPermissionDeleteTasks ProjectPermission = "delete_tasks"
+	// ProjectPermissionComment allows commenting on tasks of the project.
+	ProjectPermissionComment ProjectPermission = "comment"
+	// ProjectPermissionManageAttachments allows uploading and deleting attachments of tasks of the project.
+	ProjectPermissionManageAttachments ProjectPermission = "manage_attachments"
+)
+
+// ProjectRole is a set of permissions which can be given to users or teams a project is shared with.
+// A role only adds permissions on top of the right of the share, so it is usually combined with read-only shares.
+type ProjectRole struct {
+	// The unique, numeric id of this role.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"role"`
+	// The project this role belongs to.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id" param:"project"`
+	// The title of this role.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// The description of this role.
+	Description string `xorm:"longtext null" json:"description"`
+
+	// Whether users with this role can create new tasks.
+	CreateTasks bool `xorm:"bool not null default false" json:"create_tasks"`
+	// Whether users with this role can edit tasks.
+	UpdateTasks bool `xorm:"bool not null default false" json:"update_tasks"`
+	// Whether users with this role can delete tasks.
+	DeleteTasks bool `xorm:"bool not null default false" json:"delete_tasks"`
+	// Whether users with this role can comment on tasks and edit their own comments.
+	Comment bool `xorm:"bool not null default false" json:"comment"`
+	// Whether users with this role can upload and delete task attachments.
+	ManageAttachments bool `xorm:"bool not null default false" json:"manage_attachments"`
+
+	// The user who initially created the role.
+	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this role was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this role was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project roles
+func (*ProjectRole) TableName() string {
+	return "project_roles"
+}
+
+func (r *ProjectRole) allows(permission ProjectPermission) bool {
+	switch permission {
+	case ProjectPermissionCreateTasks:
+		return r.CreateTasks
+	case ProjectPermissionUpdateTasks:
+		return r.UpdateTasks
+	case ProjectPermissionDeleteTasks:
+		return r.DeleteTasks
+	case ProjectPermissionComment:
+		return r.Comment
+	case ProjectPermissionManageAttachments:
+		return r.ManageAttachments
+	}
+	return false
+}
+
+func getProjectRoleByID(s *xorm.Session, id int64) (role *ProjectRole, err error) {
+	role = &ProjectRole{}
+	exists, err := s.
+		Where("id = ?", id).
+		Get(role)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrProjectRoleDoesNotExist{RoleID: id}
+	}
+	return role, nil
+}
+
+// validateShareRole checks the role of a user or team share belongs to the shared project
+func validateShareRole(s *xorm.Session, roleID, projectID int64) error {
+	if roleID == 0 {
+		return nil
+	}
+
+	role, err := getProjectRoleByID(s, roleID)
+	if err != nil {
+		return err
+	}
+	if role.ProjectID != projectID {
+		return &ErrProjectRoleDoesNotBelongToProject{RoleID: role.ID, ProjectID: projectID}
+	}
+	return nil
+}
+
+// getRolesForUser returns all roles the user has through direct or team shares of the project.
+// Like the rights of a share, roles are inherited from the parent project if the project itself is not shared with the user.
+func (p *Project) getRolesForUser(s *xorm.Session, userID int64) (roles []*ProjectRole, err error) {
+	roleIDs := []int64{}
+	err = s.
+		Table("users_projects").
+		Where("project_id = ? AND user_id = ?", p.ID, userID).
+		Cols("role_id").
+		Find(&roleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	teamRoleIDs := []int64{}
+	err = s.
+		Table("team_projects").
+		Alias("tl").
+		Join("INNER", []string{"team_members", "tm"}, "tm.team_id = tl.team_id").
+		Where("tl.project_id = ? AND tm.user_id = ?", p.ID, userID).
+		Cols("tl.role_id").
+		Find(&teamRoleIDs)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs = append(roleIDs, teamRoleIDs...)
+
+	if len(roleIDs) == 0 && p.ParentProjectID > 0 {
+		parent, err := GetProjectSimpleByID(s, p.ParentProjectID)
+		if err != nil {
+			return nil, err
+		}
+		return parent.getRolesForUser(s, userID)
+	}
+
+	ids := make([]int64, 0, len(roleIDs))
+	for _, id := range roleIDs {
+		if id != 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	roles = []*ProjectRole{}
+	if len(ids) == 0 {
+		return
+	}
+	err = s.In("id", ids).Find(&roles)
+	return
+}
+
+// canWriteOrHasPermission checks if the user can write to the project or was given a role on it which grants the permission.
+func (p *Project) canWriteOrHasPermission(s *xorm.Session, a web.Auth, permission ProjectPermission) (bool, error) {
+	can, err := p.CanWrite(s, a)
+	if can || err != nil {
+		return can, err
+	}
+
+	// Link shares can't have roles
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	// Roles don't grant access to projects an api token is not restricted to
+	can, err = apiTokenCanAccessProject(s, a, p.ID)
+	if !can || err != nil {
+		return false, err
+	}
+
+	project, err := GetProjectSimpleByID(s, p.ID)
+	if err != nil {
+		return false, err
+	}
+
+	roles, err := project.getRolesForUser(s, a.GetID())
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range roles {
+		if role.allows(permission) {
+			return true, project.CheckIsArchived(s)
+		}
+	}
+
+	return false, nil
+}
+
+// Create creates a new project role
+// @Summary Create a project role
+// @Description Creates a new role with a set of permissions which can be given to users and teams the project is shared with.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param role body models.ProjectRole true "The role"
+// @Success 201 {object} models.ProjectRole "The created role."
+// @Failure 400 {object} web.HTTPError "Invalid role provided."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/roles [put]
+func (r *ProjectRole) Create(s *xorm.Session, a web.Auth) (err error) {
+	r.ID = 0
+
+	r.CreatedBy, err = GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return
+	}
+	r.CreatedByID = r.CreatedBy.ID
+
+	_, err = s.Insert(r)
+	return
+}
+
+// ReadAll returns all roles of a project
+// @Summary Get all roles of a project
+// @Description Returns all roles of a project.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {array} models.ProjectRole "The roles."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/roles [get]
+func (r *ProjectRole) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	project := &Project{ID: r.ProjectID}
+	canRead, _, err := project.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	roles := []*ProjectRole{}
+	err = s.
+		Where("project_id = ?", r.ProjectID).
+		OrderBy("id asc").
+		Find(&roles)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addCreatorsToProjectRoles(s, roles)
+	return roles, len(roles), int64(len(roles)), err
+}
+
+// ReadOne returns one project role
+// @Summary Get one project role
+// @Description Returns one role of a project.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param roleID path int true "Role ID"
+// @Success 200 {object} models.ProjectRole "The role."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The role does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/roles/{roleID} [get]
+func (r *ProjectRole) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	role, err := getProjectRoleByID(s, r.ID)
+	if err != nil {
+		return err
+	}
+	*r = *role
+	return addCreatorsToProjectRoles(s, []*ProjectRole{r})
+}
+
+// Update updates a project role
+// @Summary Update a project role
+// @Description Updates the title, description and permissions of a role. The changes apply to all users and teams which have this role immediately.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param roleID path int true "Role ID"
+// @Param role body models.ProjectRole true "The role"
+// @Success 200 {object} models.ProjectRole "The updated role."
+// @Failure 400 {object} web.HTTPError "Invalid role provided."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the project."
+// @Failure 404 {object} web.HTTPError "The role does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/roles/{roleID} [post]
+func (r *ProjectRole) Update(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where("id = ?", r.ID).
+		Cols(
+			"title",
+			"description",
+			"create_tasks",
+			"update_tasks",
+			"delete_tasks",
+			"comment",
+			"manage_attachments",
+		).
+		Update(r)
+	if err != nil {
+		return
+	}
+
+	return r.ReadOne(s, nil)
+}
+
+// Delete deletes a project role
+// @Summary Delete a project role
+// @Description Deletes a role. All users and teams which had this role only keep the right of their share.
+// @tags sharing
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param roleID path int true "Role ID"
+// @Success 200 {object} models.Message "The role was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the project."
+// @Failure 404 {object} web.HTTPError "The role does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/roles/{roleID} [delete]
+func (r *ProjectRole) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where("role_id = ?", r.ID).
+		Cols("role_id").
+		NoAutoTime().
+		Update(&ProjectUser{RoleID: 0})
+	if err != nil {
+		return
+	}
+
+	_, err = s.
+		Where("role_id = ?", r.ID).
+		Cols("role_id").
+		NoAutoTime().
+		Update(&TeamProject{RoleID: 0})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("id = ?", r.ID).Delete(&ProjectRole{})
+	return
+}
+
+func deleteProjectRolesForProject(s *xorm.Session, projectID int64) (err error) {
+	_, err = s.Where("project_id = ?", projectID).Delete(&ProjectRole{})
+	return
+}
+
+func addCreatorsToProjectRoles(s *xorm.Session, roles []*ProjectRole) error {
+	if len(roles) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(roles))
+	for _, r := range roles {
+		userIDs = append(userIDs, r.CreatedByID)
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range roles {
+		r.CreatedBy = users[r.CreatedByID]
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see a project role
+func (r *ProjectRole) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	role, err := r.getForProject(s)
+	if err != nil {
+		return false, 0, err
+	}
+
+	project := &Project{ID: role.ProjectID}
+	return project.CanRead(s, a)
+}
+
+// CanCreate checks if the user can create a role in a project
+func (r *ProjectRole) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	// Link shares aren't allowed to manage sharing
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	project := &Project{ID: r.ProjectID}
+	return project.IsAdmin(s, a)
+}
+
+// CanUpdate checks if the user can update a project role
+func (r *ProjectRole) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return r.canDoProjectRole(s, a)
+}
+
+// CanDelete checks if the user can delete a project role
+func (r *ProjectRole) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return r.canDoProjectRole(s, a)
+}
+
+func (r *ProjectRole) canDoProjectRole(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	role, err := r.getForProject(s)
+	if err != nil {
+		return false, err
+	}
+
+	project := &Project{ID: role.ProjectID}
+	return project.IsAdmin(s, a)
+}
+
+// getForProject returns the role and makes sure it belongs to the project from the request
+func (r *ProjectRole) getForProject(s *xorm.Session) (*ProjectRole, error) {
+	role, err := getProjectRoleByID(s, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	if r.ProjectID != 0 && role.ProjectID != r.ProjectID {
+		return nil, &ErrProjectRoleDoesNotExist{RoleID: r.ID}
+	}
+	return role, nil
+}
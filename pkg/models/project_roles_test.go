@@ -0,0 +1,234 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func setProjectUserRole(t *testing.T, s *xorm.Session, shareID, roleID int64) {
+	_, err := s.
+		Where("id = ?", shareID).
+		Cols("role_id").
+		Update(&ProjectUser{RoleID: roleID})
+	require.NoError(t, err)
+}
+
+func TestProjectRole_Create(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1}
+		r := &ProjectRole{ProjectID: 1, Title: "Triage", UpdateTasks: true, Comment: true}
+		can, err := r.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = r.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_roles", map[string]interface{}{
+			"id":            r.ID,
+			"project_id":    1,
+			"title":         "Triage",
+			"update_tasks":  true,
+			"comment":       true,
+			"delete_tasks":  false,
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("no admin", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 only has read access to project 3
+		r := &ProjectRole{ProjectID: 3, Title: "Triage"}
+		can, err := r.CanCreate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestProjectRole_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	r := &ProjectRole{ProjectID: 3}
+	result, _, _, err := r.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+	require.NoError(t, err)
+	roles := result.([]*ProjectRole)
+	require.Len(t, roles, 2)
+	assert.Equal(t, "Commenter", roles[0].Title)
+	assert.Equal(t, "Contributor", roles[1].Title)
+	assert.Equal(t, int64(3), roles[0].CreatedBy.ID)
+}
+
+func TestProjectRole_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+	setProjectUserRole(t, s, 1, 1)
+
+	r := &ProjectRole{ID: 1, ProjectID: 3}
+	can, err := r.CanDelete(s, &user.User{ID: 3})
+	require.NoError(t, err)
+	assert.True(t, can)
+	err = r.Delete(s, &user.User{ID: 3})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "project_roles", map[string]interface{}{
+		"id": 1,
+	})
+	db.AssertExists(t, "users_projects", map[string]interface{}{
+		"id":      1,
+		"role_id": 0,
+	}, false)
+}
+
+func TestProjectRole_Permissions(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("without role", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskComment{TaskID: 32}
+		can, err := tc.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("comment only", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setProjectUserRole(t, s, 1, 1)
+
+		tc := &TaskComment{TaskID: 32}
+		can, err := tc.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		task := &Task{ID: 32}
+		can, err = task.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		task = &Task{ProjectID: 3}
+		can, err = task.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("contributor", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setProjectUserRole(t, s, 1, 2)
+
+		task := &Task{ProjectID: 3}
+		can, err := task.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		task = &Task{ID: 32}
+		can, err = task.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		task = &Task{ID: 32}
+		can, err = task.CanDelete(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		ta := &TaskAttachment{TaskID: 32}
+		can, err = ta.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		// Roles don't allow managing the project itself
+		project := &Project{ID: 3}
+		can, err = project.CanWrite(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("role of another user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		// Share 2 is the share of project 3 with user 2
+		setProjectUserRole(t, s, 2, 2)
+
+		task := &Task{ID: 32}
+		can, err := task.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestProjectUser_Create_Role(t *testing.T) {
+	t.Run("role of the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pu := &ProjectUser{Username: "user4", ProjectID: 3, RoleID: 1}
+		err := pu.Create(s, &user.User{ID: 3})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "users_projects", map[string]interface{}{
+			"user_id":    4,
+			"project_id": 3,
+			"role_id":    1,
+		}, false)
+	})
+	t.Run("role of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pu := &ProjectUser{Username: "user4", ProjectID: 3, RoleID: 3}
+		err := pu.Create(s, &user.User{ID: 3})
+		require.Error(t, err)
+		assert.True(t, IsErrProjectRoleDoesNotBelongToProject(err))
+	})
+	t.Run("nonexistent role", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pu := &ProjectUser{Username: "user4", ProjectID: 3, RoleID: 9999}
+		err := pu.Create(s, &user.User{ID: 3})
+		require.Error(t, err)
+		assert.True(t, IsErrProjectRoleDoesNotExist(err))
+	})
+}
@@ -0,0 +1,484 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// ScheduledAutomationActionKind defines what a scheduled automation does when it runs
+type ScheduledAutomationActionKind string
+
+const (
+	// ScheduledAutomationActionCreateTask creates a copy of the task with the id from the action value in the project.
+	ScheduledAutomationActionCreateTask ScheduledAutomationActionKind = "create_task"
+	// ScheduledAutomationActionClearBucket deletes all tasks in the bucket with the id from the action value.
+	ScheduledAutomationActionClearBucket ScheduledAutomationActionKind = "clear_bucket"
+)
+
+// ProjectScheduledAutomation does something in a project on a recurring schedule.
+type ProjectScheduledAutomation struct {
+	// The unique, numeric id of this scheduled automation.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"scheduledautomation"`
+	// The project this automation belongs to.
+	ProjectID int64 `xorm:"bigint not null index" json:"project_id" param:"project"`
+	// The title of the automation.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// Whether the automation runs. Defaults to true.
+	Enabled *bool `xorm:"bool not null default true" json:"enabled"`
+	// When the automation runs as a standard cron expression with five fields, for example `0 9 * * 1` for every
+	// Monday at 9:00. The expression is evaluated in the time zone of the user who created the automation.
+	Schedule string `xorm:"varchar(100) not null" json:"schedule" valid:"required"`
+	// What the automation does. Can be `create_task` or `clear_bucket`.
+	Action ScheduledAutomationActionKind `xorm:"varchar(50) not null" json:"action"`
+	// The id of the task which is copied for `create_task` and the id of the bucket which is cleared for `clear_bucket`.
+	ActionValue int64 `xorm:"bigint not null" json:"action_value"`
+
+	// When the automation will run next. You cannot change this value.
+	NextRunAt time.Time `xorm:"DATETIME null index" json:"next_run_at"`
+	// When the automation ran last. You cannot change this value.
+	LastRunAt time.Time `xorm:"DATETIME null" json:"last_run_at"`
+
+	// The user who created the automation. It runs on behalf of this user.
+	CreatedBy   *user.User `xorm:"-" json:"created_by"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this automation was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this automation was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for scheduled automations
+func (*ProjectScheduledAutomation) TableName() string {
+	return "project_scheduled_automations"
+}
+
+func getScheduledAutomationByID(s *xorm.Session, id int64) (automation *ProjectScheduledAutomation, err error) {
+	automation = &ProjectScheduledAutomation{}
+	exists, err := s.Where("id = ?", id).Get(automation)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrScheduledAutomationDoesNotExist{AutomationID: id}
+	}
+	return automation, nil
+}
+
+// validate checks the schedule and the action of the automation. The entity the action uses must be accessible to
+// the user who saves the automation.
+func (a *ProjectScheduledAutomation) validate(s *xorm.Session, auth web.Auth) (err error) {
+	if _, err := cron.Next(a.Schedule, time.Now()); err != nil {
+		return &ErrInvalidScheduledAutomation{Reason: "the schedule is not a valid cron expression: " + err.Error()}
+	}
+
+	switch a.Action {
+	case ScheduledAutomationActionCreateTask:
+		task := &Task{ID: a.ActionValue}
+		can, _, err := task.CanRead(s, auth)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return ErrGenericForbidden{}
+		}
+	case ScheduledAutomationActionClearBucket:
+		bucket, err := getBucketByID(s, a.ActionValue)
+		if err != nil {
+			return err
+		}
+		if bucket.ProjectID != a.ProjectID {
+			return ErrBucketDoesNotBelongToProject{BucketID: bucket.ID, ProjectID: a.ProjectID}
+		}
+	default:
+		return &ErrInvalidScheduledAutomation{Reason: "the action " + string(a.Action) + " does not exist"}
+	}
+
+	return nil
+}
+
+// calculateNextRun returns the next time the schedule of the automation matches after now, in the time zone of its
+// creator.
+func (a *ProjectScheduledAutomation) calculateNextRun(s *xorm.Session, now time.Time) (next time.Time, err error) {
+	creator, err := user.GetUserByID(s, a.CreatedByID)
+	if err != nil {
+		return
+	}
+
+	tz := config.GetTimeZone()
+	if creator.Timezone != "" {
+		tz, err = time.LoadLocation(creator.Timezone)
+		if err != nil {
+			return
+		}
+	}
+
+	return cron.Next(a.Schedule, now.In(tz))
+}
+
+// Create creates a new scheduled automation
+// @Summary Create a scheduled automation
+// @Description Creates a new scheduled automation in a project. The automation runs on behalf of the current user.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param automation body models.ProjectScheduledAutomation true "The scheduled automation"
+// @Success 201 {object} models.ProjectScheduledAutomation "The created scheduled automation."
+// @Failure 400 {object} web.HTTPError "Invalid scheduled automation provided."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/scheduled_automations [put]
+func (a *ProjectScheduledAutomation) Create(s *xorm.Session, auth web.Auth) (err error) {
+	err = a.validate(s, auth)
+	if err != nil {
+		return
+	}
+
+	if a.Enabled == nil {
+		enabled := true
+		a.Enabled = &enabled
+	}
+
+	a.ID = 0
+	a.CreatedByID = auth.GetID()
+	a.LastRunAt = time.Time{}
+	a.NextRunAt, err = a.calculateNextRun(s, time.Now())
+	if err != nil {
+		return
+	}
+
+	_, err = s.Insert(a)
+	if err != nil {
+		return
+	}
+
+	return addCreatorsToScheduledAutomations(s, []*ProjectScheduledAutomation{a})
+}
+
+// ReadAll returns all scheduled automations of a project
+// @Summary Get all scheduled automations of a project
+// @Description Returns all scheduled automations of a project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {array} models.ProjectScheduledAutomation "The scheduled automations."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/scheduled_automations [get]
+func (a *ProjectScheduledAutomation) ReadAll(s *xorm.Session, auth web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	project := &Project{ID: a.ProjectID}
+	canRead, _, err := project.CanRead(s, auth)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	automations := []*ProjectScheduledAutomation{}
+	err = s.
+		Where("project_id = ?", a.ProjectID).
+		OrderBy("id asc").
+		Find(&automations)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addCreatorsToScheduledAutomations(s, automations)
+	return automations, len(automations), int64(len(automations)), err
+}
+
+// ReadOne returns one scheduled automation
+// @Summary Get one scheduled automation
+// @Description Returns one scheduled automation of a project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param automationID path int true "Scheduled automation ID"
+// @Success 200 {object} models.ProjectScheduledAutomation "The scheduled automation."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The scheduled automation does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/scheduled_automations/{automationID} [get]
+func (a *ProjectScheduledAutomation) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	automation, err := getScheduledAutomationByID(s, a.ID)
+	if err != nil {
+		return err
+	}
+	*a = *automation
+	return addCreatorsToScheduledAutomations(s, []*ProjectScheduledAutomation{a})
+}
+
+// Update updates a scheduled automation
+// @Summary Update a scheduled automation
+// @Description Updates the title, schedule and action of a scheduled automation or enables and disables it. The automation still runs on behalf of the user who created it.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param automationID path int true "Scheduled automation ID"
+// @Param automation body models.ProjectScheduledAutomation true "The scheduled automation"
+// @Success 200 {object} models.ProjectScheduledAutomation "The updated scheduled automation."
+// @Failure 400 {object} web.HTTPError "Invalid scheduled automation provided."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the project."
+// @Failure 404 {object} web.HTTPError "The scheduled automation does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/scheduled_automations/{automationID} [post]
+func (a *ProjectScheduledAutomation) Update(s *xorm.Session, auth web.Auth) (err error) {
+	err = a.validate(s, auth)
+	if err != nil {
+		return
+	}
+
+	existing, err := getScheduledAutomationByID(s, a.ID)
+	if err != nil {
+		return
+	}
+
+	cols := []string{"title", "schedule", "action", "action_value", "next_run_at"}
+	if a.Enabled != nil {
+		cols = append(cols, "enabled")
+	}
+
+	a.CreatedByID = existing.CreatedByID
+	a.NextRunAt, err = a.calculateNextRun(s, time.Now())
+	if err != nil {
+		return
+	}
+
+	_, err = s.
+		Where("id = ?", a.ID).
+		Cols(cols...).
+		Update(a)
+	if err != nil {
+		return
+	}
+
+	return a.ReadOne(s, auth)
+}
+
+// Delete deletes a scheduled automation
+// @Summary Delete a scheduled automation
+// @Description Deletes a scheduled automation. Tasks it already created are kept.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param automationID path int true "Scheduled automation ID"
+// @Success 200 {object} models.Message "The scheduled automation was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the project."
+// @Failure 404 {object} web.HTTPError "The scheduled automation does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/scheduled_automations/{automationID} [delete]
+func (a *ProjectScheduledAutomation) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("id = ?", a.ID).Delete(&ProjectScheduledAutomation{})
+	return
+}
+
+func deleteScheduledAutomationsForProject(s *xorm.Session, projectID int64) (err error) {
+	_, err = s.Where("project_id = ?", projectID).Delete(&ProjectScheduledAutomation{})
+	return
+}
+
+func addCreatorsToScheduledAutomations(s *xorm.Session, automations []*ProjectScheduledAutomation) error {
+	if len(automations) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(automations))
+	for _, a := range automations {
+		userIDs = append(userIDs, a.CreatedByID)
+	}
+
+	users, err := user.GetUsersByIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range automations {
+		a.CreatedBy = users[a.CreatedByID]
+	}
+	return nil
+}
+
+// run executes the action of the automation on behalf of its creator.
+func (a *ProjectScheduledAutomation) run(s *xorm.Session) (err error) {
+	creator, err := user.GetUserByID(s, a.CreatedByID)
+	if err != nil {
+		return err
+	}
+
+	project := &Project{ID: a.ProjectID}
+	can, err := project.CanWrite(s, creator)
+	if err != nil {
+		return err
+	}
+	if !can {
+		return ErrGenericForbidden{}
+	}
+
+	switch a.Action {
+	case ScheduledAutomationActionCreateTask:
+		return a.createTaskFromTemplate(s, creator)
+	case ScheduledAutomationActionClearBucket:
+		return a.clearBucket(s, creator)
+	}
+
+	return nil
+}
+
+// createTaskFromTemplate creates a copy of the template task with its labels and assignees in the project of the
+// automation.
+func (a *ProjectScheduledAutomation) createTaskFromTemplate(s *xorm.Session, creator *user.User) (err error) {
+	template := &Task{ID: a.ActionValue}
+	err = template.ReadOne(s, creator)
+	if err != nil {
+		return err
+	}
+
+	task := &Task{
+		Title:       template.Title,
+		Description: template.Description,
+		Priority:    template.Priority,
+		HexColor:    template.HexColor,
+		PercentDone: template.PercentDone,
+		ProjectID:   a.ProjectID,
+		Assignees:   template.Assignees,
+	}
+	err = createTask(s, task, creator, true)
+	if err != nil {
+		return err
+	}
+
+	for _, label := range template.Labels {
+		_, err = s.Insert(&LabelTask{LabelID: label.ID, TaskID: task.ID})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clearBucket deletes all tasks in the bucket of the automation.
+func (a *ProjectScheduledAutomation) clearBucket(s *xorm.Session, creator *user.User) (err error) {
+	tasks := []*Task{}
+	err = s.
+		Where("bucket_id = ? AND project_id = ?", a.ActionValue, a.ProjectID).
+		Find(&tasks)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		err = task.Delete(s, creator)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runScheduledAutomations runs all enabled automations which are due and schedules their next run.
+func runScheduledAutomations(s *xorm.Session, now time.Time) (ran int, err error) {
+	automations := []*ProjectScheduledAutomation{}
+	err = s.
+		Where("enabled = ? AND next_run_at <= ?", true, now).
+		OrderBy("next_run_at asc").
+		Find(&automations)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, automation := range automations {
+		// An automation which fails, for example because its creator lost access to the project, should not
+		// prevent the others from running. It is run again on its next schedule.
+		err = automation.run(s)
+		if err != nil {
+			log.Errorf("Could not run scheduled automation %d: %s", automation.ID, err)
+		} else {
+			ran++
+		}
+
+		automation.LastRunAt = now
+		automation.NextRunAt, err = automation.calculateNextRun(s, now)
+		if err != nil {
+			return ran, err
+		}
+
+		_, err = s.
+			Where("id = ?", automation.ID).
+			Cols("last_run_at", "next_run_at").
+			NoAutoTime().
+			Update(automation)
+		if err != nil {
+			return ran, err
+		}
+	}
+
+	return ran, nil
+}
+
+// RegisterScheduledAutomationCron registers a cron function which runs all scheduled automations when they are due.
+func RegisterScheduledAutomationCron() {
+	const logPrefix = "[Scheduled Automation Cron] "
+
+	err := cron.Schedule("* * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		ran, err := runScheduledAutomations(s, time.Now())
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not run scheduled automations: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit scheduled automations: %s", err)
+			return
+		}
+
+		if ran > 0 {
+			log.Debugf(logPrefix+"Ran %d scheduled automations", ran)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register scheduled automation cron: %s", err)
+	}
+}
@@ -0,0 +1,84 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see a scheduled automation
+func (a *ProjectScheduledAutomation) CanRead(s *xorm.Session, auth web.Auth) (bool, int, error) {
+	automation, err := a.getForProject(s)
+	if err != nil {
+		return false, 0, err
+	}
+
+	project := &Project{ID: automation.ProjectID}
+	return project.CanRead(s, auth)
+}
+
+// CanCreate checks if the user can create a scheduled automation in a project
+func (a *ProjectScheduledAutomation) CanCreate(s *xorm.Session, auth web.Auth) (bool, error) {
+	// Scheduled automations run on behalf of the user who created them
+	if _, is := auth.(*LinkSharing); is {
+		return false, nil
+	}
+
+	if getSavedFilterIDFromProjectID(a.ProjectID) > 0 {
+		return false, nil
+	}
+
+	project := &Project{ID: a.ProjectID}
+	return project.CanWrite(s, auth)
+}
+
+// CanUpdate checks if the user can update a scheduled automation
+func (a *ProjectScheduledAutomation) CanUpdate(s *xorm.Session, auth web.Auth) (bool, error) {
+	return a.canDoScheduledAutomation(s, auth)
+}
+
+// CanDelete checks if the user can delete a scheduled automation
+func (a *ProjectScheduledAutomation) CanDelete(s *xorm.Session, auth web.Auth) (bool, error) {
+	return a.canDoScheduledAutomation(s, auth)
+}
+
+func (a *ProjectScheduledAutomation) canDoScheduledAutomation(s *xorm.Session, auth web.Auth) (bool, error) {
+	if _, is := auth.(*LinkSharing); is {
+		return false, nil
+	}
+
+	automation, err := a.getForProject(s)
+	if err != nil {
+		return false, err
+	}
+
+	project := &Project{ID: automation.ProjectID}
+	return project.CanWrite(s, auth)
+}
+
+// getForProject returns the automation and makes sure it belongs to the project from the request
+func (a *ProjectScheduledAutomation) getForProject(s *xorm.Session) (*ProjectScheduledAutomation, error) {
+	automation, err := getScheduledAutomationByID(s, a.ID)
+	if err != nil {
+		return nil, err
+	}
+	if a.ProjectID != 0 && automation.ProjectID != a.ProjectID {
+		return nil, &ErrScheduledAutomationDoesNotExist{AutomationID: a.ID}
+	}
+	return automation, nil
+}
@@ -0,0 +1,217 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectScheduledAutomation_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		a := &ProjectScheduledAutomation{
+			ProjectID:   1,
+			Title:       "Monthly cleanup",
+			Schedule:    "0 0 1 * *",
+			Action:      ScheduledAutomationActionClearBucket,
+			ActionValue: 3,
+		}
+		can, err := a.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = a.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		assert.True(t, *a.Enabled)
+		assert.True(t, a.NextRunAt.After(time.Now()))
+		assert.Equal(t, 1, a.NextRunAt.Day())
+
+		db.AssertExists(t, "project_scheduled_automations", map[string]interface{}{
+			"id":            a.ID,
+			"project_id":    1,
+			"schedule":      "0 0 1 * *",
+			"action":        "clear_bucket",
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		a := &ProjectScheduledAutomation{ProjectID: 1}
+		can, err := a.CanCreate(s, &LinkSharing{ID: 2, ProjectID: 1, Right: RightAdmin})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("invalid schedule", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		a := &ProjectScheduledAutomation{
+			ProjectID:   1,
+			Title:       "Invalid",
+			Schedule:    "every monday",
+			Action:      ScheduledAutomationActionCreateTask,
+			ActionValue: 1,
+		}
+		err := a.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidScheduledAutomation(err))
+	})
+	t.Run("invalid action", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		a := &ProjectScheduledAutomation{
+			ProjectID: 1,
+			Title:     "Invalid",
+			Schedule:  "0 9 * * 1",
+			Action:    "archive_everything",
+		}
+		err := a.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidScheduledAutomation(err))
+	})
+	t.Run("bucket of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		a := &ProjectScheduledAutomation{
+			ProjectID:   1,
+			Title:       "Clear",
+			Schedule:    "0 9 * * 1",
+			Action:      ScheduledAutomationActionClearBucket,
+			ActionValue: 4,
+		}
+		err := a.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBucketDoesNotBelongToProject(err))
+	})
+	t.Run("template task without access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		a := &ProjectScheduledAutomation{
+			ProjectID:   1,
+			Title:       "Copy",
+			Schedule:    "0 9 * * 1",
+			Action:      ScheduledAutomationActionCreateTask,
+			ActionValue: 13,
+		}
+		err := a.Create(s, u)
+		require.Error(t, err)
+		assert.IsType(t, ErrGenericForbidden{}, err)
+	})
+}
+
+func TestProjectScheduledAutomation_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	a := &ProjectScheduledAutomation{ProjectID: 1}
+	result, _, _, err := a.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+	require.NoError(t, err)
+	automations := result.([]*ProjectScheduledAutomation)
+	require.Len(t, automations, 2)
+	assert.Equal(t, ScheduledAutomationActionCreateTask, automations[0].Action)
+	assert.Equal(t, int64(1), automations[0].CreatedBy.ID)
+
+	_, _, _, err = a.ReadAll(s, &user.User{ID: 13}, "", 0, 0)
+	require.Error(t, err)
+	assert.True(t, IsErrGenericForbidden(err))
+}
+
+func TestProjectScheduledAutomation_CanRead(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	// Automation 3 belongs to project 2
+	a := &ProjectScheduledAutomation{ID: 3, ProjectID: 1}
+	_, _, err := a.CanRead(s, &user.User{ID: 1})
+	require.Error(t, err)
+	assert.True(t, IsErrScheduledAutomationDoesNotExist(err))
+}
+
+func TestRunScheduledAutomations(t *testing.T) {
+	t.Run("create task from template", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		now := time.Date(2018, 12, 3, 9, 0, 30, 0, time.UTC)
+		ran, err := runScheduledAutomations(s, now)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		assert.Equal(t, 1, ran)
+
+		task := &Task{}
+		exists, err := s.Where("project_id = ? AND title = ? AND id != ?", 1, "task #1", 1).Get(task)
+		require.NoError(t, err)
+		require.True(t, exists)
+		assert.Equal(t, "Lorem Ipsum", task.Description)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  task.ID,
+			"label_id": 4,
+		}, false)
+
+		automation, err := getScheduledAutomationByID(s, 1)
+		require.NoError(t, err)
+		assert.True(t, automation.NextRunAt.After(now))
+		assert.Equal(t, time.Monday, automation.NextRunAt.Weekday())
+	})
+	t.Run("clear bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		enabled := true
+		_, err := s.Where("id = ?", 2).Cols("enabled").Update(&ProjectScheduledAutomation{Enabled: &enabled})
+		require.NoError(t, err)
+
+		ran, err := runScheduledAutomations(s, time.Date(2018, 12, 1, 0, 0, 10, 0, time.UTC))
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		assert.Equal(t, 1, ran)
+
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"bucket_id":  3,
+			"project_id": 1,
+		})
+	})
+}
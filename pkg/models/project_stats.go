@@ -0,0 +1,344 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+const (
+	projectStatsDateFormat  = "2006-01-02"
+	projectStatsDefaultDays = 30
+	projectStatsMaxDays     = 366
+	projectStatsDay         = 24 * time.Hour
+)
+
+// ProjectStats holds statistics about the tasks of a project
+type ProjectStats struct {
+	// The project the statistics belong to.
+	ProjectID int64 `json:"project_id" param:"project"`
+	// The first day of the analyzed period in the format YYYY-MM-DD. Defaults to 30 days ago.
+	From string `json:"from" query:"from"`
+	// The last day of the analyzed period in the format YYYY-MM-DD. Defaults to today.
+	To string `json:"to" query:"to"`
+
+	// The number of undone tasks in the project.
+	OpenTasks int64 `json:"open_tasks"`
+	// The sum of the estimates of all undone tasks in the project in seconds.
+	OpenEstimate int64 `json:"open_estimate"`
+	// The number of tasks of the project which are not done and whose due date has passed.
+	OverdueTasks int64 `json:"overdue_tasks"`
+	// The number of tasks of the project which are done.
+	DoneTasks int64 `json:"done_tasks"`
+
+	// The number of created, completed and open tasks for every day of the analyzed period.
+	Timeline []*ProjectStatsDay `json:"timeline"`
+	// The current number of open and overdue tasks in each bucket of the project.
+	Buckets []*ProjectStatsGroup `json:"buckets"`
+	// The current number of open and overdue tasks with each label. Only labels of open tasks are included.
+	Labels []*ProjectStatsGroup `json:"labels"`
+
+	// The average number of seconds between creating and completing a task.
+	// Only includes tasks which were completed during the analyzed period.
+	AverageLeadTime int64 `json:"average_lead_time"`
+	// The average number of seconds between the first time a task was moved out of the bucket it was created in and completing it.
+	// Only includes tasks which were completed during the analyzed period and moved between buckets before.
+	AverageCycleTime int64 `json:"average_cycle_time"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// ProjectStatsDay holds the task counts of a project on one day
+type ProjectStatsDay struct {
+	// The day in the format YYYY-MM-DD.
+	Date string `json:"date"`
+	// The number of tasks created on this day.
+	Created int64 `json:"created"`
+	// The number of tasks completed on this day.
+	Completed int64 `json:"completed"`
+	// The number of tasks which were open at the end of this day. This can be used for a burndown chart.
+	Open int64 `json:"open"`
+}
+
+// ProjectStatsGroup holds the number of open tasks in a bucket or with a label
+type ProjectStatsGroup struct {
+	// The id of the bucket or label.
+	ID int64 `json:"id"`
+	// The title of the bucket or label.
+	Title string `json:"title"`
+	// The number of open tasks.
+	OpenTasks int64 `json:"open_tasks"`
+	// The number of open tasks whose due date has passed.
+	OverdueTasks int64 `json:"overdue_tasks"`
+}
+
+// CanRead checks if the user can see the statistics of a project
+func (ps *ProjectStats) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	p := &Project{ID: ps.ProjectID}
+	return p.CanRead(s, a)
+}
+
+func (ps *ProjectStats) getPeriod() (from, to time.Time, err error) {
+	tz := config.GetTimeZone()
+	now := time.Now().In(tz)
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+
+	if ps.To != "" {
+		to, err = time.ParseInLocation(projectStatsDateFormat, ps.To, tz)
+		if err != nil {
+			return from, to, ErrInvalidData{Message: "The end date must be in the format YYYY-MM-DD."}
+		}
+	}
+
+	from = to.AddDate(0, 0, -(projectStatsDefaultDays - 1))
+	if ps.From != "" {
+		from, err = time.ParseInLocation(projectStatsDateFormat, ps.From, tz)
+		if err != nil {
+			return from, to, ErrInvalidData{Message: "The start date must be in the format YYYY-MM-DD."}
+		}
+	}
+
+	if from.After(to) {
+		return from, to, ErrInvalidData{Message: "The start date must be before the end date."}
+	}
+
+	if to.Sub(from) >= projectStatsMaxDays*projectStatsDay {
+		return from, to, ErrInvalidData{Message: "The analyzed period can't be longer than a year."}
+	}
+
+	ps.From = from.Format(projectStatsDateFormat)
+	ps.To = to.Format(projectStatsDateFormat)
+
+	return
+}
+
+func isTaskOverdue(t *Task, now time.Time) bool {
+	return !t.Done && !t.DueDate.IsZero() && t.DueDate.Before(now)
+}
+
+// ReadOne returns the statistics of a project
+// @Summary Get the statistics of a project
+// @Description Returns the number of open tasks and the sum of their estimates, how many tasks were created and completed on every day of the analyzed period and how many were open at the end of each day, the current number of open and overdue tasks per bucket and label and the average lead and cycle time of tasks completed during the period.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Param from query string false "The first day of the analyzed period in the format YYYY-MM-DD. Defaults to 30 days ago."
+// @Param to query string false "The last day of the analyzed period in the format YYYY-MM-DD. Defaults to today."
+// @Success 200 {object} models.ProjectStats "The statistics of the project."
+// @Failure 400 {object} web.HTTPError "Invalid period provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/stats [get]
+func (ps *ProjectStats) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	from, to, err := ps.getPeriod()
+	if err != nil {
+		return err
+	}
+	// The analyzed period includes the whole last day
+	end := to.Add(projectStatsDay)
+	now := time.Now()
+
+	tasks := []*Task{}
+	err = s.
+		Where("project_id = ?", ps.ProjectID).
+		OrderBy("id asc").
+		Find(&tasks)
+	if err != nil {
+		return err
+	}
+
+	buckets := []*Bucket{}
+	err = s.
+		Where("project_id = ?", ps.ProjectID).
+		OrderBy("position asc").
+		Find(&buckets)
+	if err != nil {
+		return err
+	}
+
+	bucketStats := make(map[int64]*ProjectStatsGroup, len(buckets))
+	ps.Buckets = make([]*ProjectStatsGroup, 0, len(buckets))
+	for _, b := range buckets {
+		g := &ProjectStatsGroup{
+			ID:    b.ID,
+			Title: b.Title,
+		}
+		bucketStats[b.ID] = g
+		ps.Buckets = append(ps.Buckets, g)
+	}
+
+	ps.OpenEstimate = sumOpenEstimates(tasks)
+
+	openTasks := make(map[int64]*Task)
+	openTaskIDs := []int64{}
+	completedTasks := []*Task{}
+	for _, t := range tasks {
+		if t.Done {
+			ps.DoneTasks++
+			if !t.DoneAt.IsZero() && !t.DoneAt.Before(from) && t.DoneAt.Before(end) {
+				completedTasks = append(completedTasks, t)
+			}
+			continue
+		}
+
+		ps.OpenTasks++
+		openTasks[t.ID] = t
+		openTaskIDs = append(openTaskIDs, t.ID)
+		overdue := isTaskOverdue(t, now)
+		if overdue {
+			ps.OverdueTasks++
+		}
+		if g, has := bucketStats[t.BucketID]; has {
+			g.OpenTasks++
+			if overdue {
+				g.OverdueTasks++
+			}
+		}
+	}
+
+	// Timeline: Tasks without a done date don't show up as completed and are not open once they are done.
+	ps.Timeline = []*ProjectStatsDay{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+		entry := &ProjectStatsDay{
+			Date: day.Format(projectStatsDateFormat),
+		}
+		for _, t := range tasks {
+			if !t.Created.Before(dayEnd) {
+				continue
+			}
+			if !t.Created.Before(day) {
+				entry.Created++
+			}
+			if !t.Done || (!t.DoneAt.IsZero() && !t.DoneAt.Before(dayEnd)) {
+				entry.Open++
+			}
+			if t.Done && !t.DoneAt.Before(day) && t.DoneAt.Before(dayEnd) {
+				entry.Completed++
+			}
+		}
+		ps.Timeline = append(ps.Timeline, entry)
+	}
+
+	ps.Labels = []*ProjectStatsGroup{}
+	if len(openTaskIDs) > 0 {
+		labelTasks := []*LabelTask{}
+		err = s.
+			In("task_id", openTaskIDs).
+			Find(&labelTasks)
+		if err != nil {
+			return err
+		}
+
+		labelIDs := []int64{}
+		labelStats := make(map[int64]*ProjectStatsGroup)
+		for _, lt := range labelTasks {
+			g, has := labelStats[lt.LabelID]
+			if !has {
+				g = &ProjectStatsGroup{ID: lt.LabelID}
+				labelStats[lt.LabelID] = g
+				labelIDs = append(labelIDs, lt.LabelID)
+			}
+			g.OpenTasks++
+			if isTaskOverdue(openTasks[lt.TaskID], now) {
+				g.OverdueTasks++
+			}
+		}
+
+		if len(labelIDs) > 0 {
+			labels := []*Label{}
+			err = s.
+				In("id", labelIDs).
+				OrderBy("id asc").
+				Find(&labels)
+			if err != nil {
+				return err
+			}
+			for _, l := range labels {
+				labelStats[l.ID].Title = l.Title
+				ps.Labels = append(ps.Labels, labelStats[l.ID])
+			}
+		}
+	}
+
+	if len(completedTasks) == 0 {
+		return nil
+	}
+
+	var leadTime time.Duration
+	completedIDs := make([]int64, 0, len(completedTasks))
+	for _, t := range completedTasks {
+		leadTime += t.DoneAt.Sub(t.Created)
+		completedIDs = append(completedIDs, t.ID)
+	}
+	ps.AverageLeadTime = int64((leadTime / time.Duration(len(completedTasks))).Seconds())
+
+	// Cycle time: The work on a task starts when it is moved out of the bucket it was created in for the first time.
+	transitions := []*TaskBucketTransition{}
+	err = s.
+		Where(builder.And(
+			builder.Eq{"project_id": ps.ProjectID},
+			builder.In("task_id", completedIDs),
+			builder.Neq{"from_bucket_id": 0},
+		)).
+		OrderBy("created asc, id asc").
+		Find(&transitions)
+	if err != nil {
+		return err
+	}
+
+	started := make(map[int64]time.Time, len(completedIDs))
+	for _, tr := range transitions {
+		if _, has := started[tr.TaskID]; !has {
+			started[tr.TaskID] = tr.Created
+		}
+	}
+
+	var cycleTime time.Duration
+	var cycleTasks int64
+	for _, t := range completedTasks {
+		start, has := started[t.ID]
+		if !has || start.After(t.DoneAt) {
+			continue
+		}
+		cycleTime += t.DoneAt.Sub(start)
+		cycleTasks++
+	}
+	if cycleTasks > 0 {
+		ps.AverageCycleTime = int64((cycleTime / time.Duration(cycleTasks)).Seconds())
+	}
+
+	return nil
+}
+
+// sumOpenEstimates returns the sum of the estimates of all undone tasks.
+func sumOpenEstimates(tasks []*Task) (sum int64) {
+	for _, t := range tasks {
+		if !t.Done {
+			sum += t.Estimate
+		}
+	}
+	return
+}
@@ -0,0 +1,137 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectStats_ReadOne(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	setTaskEstimates(t, s, map[int64]int64{3: 3600, 4: 1800, 2: 600})
+
+	stats := &ProjectStats{ProjectID: 1}
+	err := stats.ReadOne(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(17), stats.OpenTasks)
+	assert.Equal(t, int64(5400), stats.OpenEstimate)
+}
+
+func TestProjectStats_ReadOne_Period(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tz := config.GetTimeZone()
+		transitionCreated := time.Date(2018, 12, 2, 1, 12, 4, 0, tz)
+		doneAt := time.Date(2018, 12, 3, 1, 12, 4, 0, tz)
+
+		_, err := s.
+			Where("id = ?", 40).
+			Cols("done", "done_at").
+			Update(&Task{Done: true, DoneAt: doneAt})
+		require.NoError(t, err)
+		_, err = s.NoAutoTime().Insert(&TaskBucketTransition{
+			TaskID:       40,
+			ProjectID:    36,
+			FromBucketID: 38,
+			ToBucketID:   38,
+			Created:      transitionCreated,
+		})
+		require.NoError(t, err)
+		_, err = s.Insert(&LabelTask{TaskID: 41, LabelID: 1})
+		require.NoError(t, err)
+
+		stats := &ProjectStats{
+			ProjectID: 36,
+			From:      "2018-11-30",
+			To:        "2018-12-03",
+		}
+		err = stats.ReadOne(s, &user.User{ID: 15})
+		require.NoError(t, err)
+
+		// All tasks of the project are due in 2023
+		assert.Equal(t, int64(4), stats.OpenTasks)
+		assert.Equal(t, int64(4), stats.OverdueTasks)
+		assert.Equal(t, int64(1), stats.DoneTasks)
+
+		require.Len(t, stats.Timeline, 4)
+		assert.Equal(t, &ProjectStatsDay{Date: "2018-11-30"}, stats.Timeline[0])
+		assert.Equal(t, &ProjectStatsDay{Date: "2018-12-01", Created: 5, Open: 5}, stats.Timeline[1])
+		assert.Equal(t, &ProjectStatsDay{Date: "2018-12-02", Open: 5}, stats.Timeline[2])
+		assert.Equal(t, &ProjectStatsDay{Date: "2018-12-03", Completed: 1, Open: 4}, stats.Timeline[3])
+
+		require.Len(t, stats.Buckets, 1)
+		assert.Equal(t, int64(38), stats.Buckets[0].ID)
+		assert.Equal(t, int64(4), stats.Buckets[0].OpenTasks)
+		assert.Equal(t, int64(4), stats.Buckets[0].OverdueTasks)
+
+		require.Len(t, stats.Labels, 1)
+		assert.Equal(t, int64(1), stats.Labels[0].ID)
+		assert.Equal(t, "Label #1", stats.Labels[0].Title)
+		assert.Equal(t, int64(1), stats.Labels[0].OpenTasks)
+
+		assert.Equal(t, int64(2*24*60*60), stats.AverageLeadTime)
+		assert.Equal(t, int64(24*60*60), stats.AverageCycleTime)
+	})
+	t.Run("completed outside of the period", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.
+			Where("id = ?", 40).
+			Cols("done", "done_at").
+			Update(&Task{Done: true, DoneAt: time.Date(2018, 12, 3, 1, 12, 4, 0, config.GetTimeZone())})
+		require.NoError(t, err)
+
+		stats := &ProjectStats{
+			ProjectID: 36,
+			From:      "2018-12-01",
+			To:        "2018-12-02",
+		}
+		err = stats.ReadOne(s, &user.User{ID: 15})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats.AverageLeadTime)
+		assert.Equal(t, int64(5), stats.Timeline[1].Open)
+	})
+	t.Run("invalid date", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		stats := &ProjectStats{
+			ProjectID: 1,
+			From:      "yesterday",
+		}
+		err := stats.ReadOne(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidData(err))
+	})
+}
@@ -0,0 +1,298 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// ProjectStatus is a workflow state the tasks of a project can be in. Unlike buckets, statuses don't change when
+// a kanban board is reorganized.
+type ProjectStatus struct {
+	// The unique, numeric id of this status.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"status"`
+	// The project this status belongs to.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id" param:"project"`
+	// The title of this status.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// The color of this status in hex format.
+	HexColor string `xorm:"varchar(6) null" json:"hex_color" valid:"runelength(0|7)" maxLength:"7"`
+	// The position of this status in relation to the other statuses of the project.
+	Position float64 `xorm:"double null" json:"position"`
+
+	// The user who initially created the status.
+	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this status was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this status was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project statuses
+func (*ProjectStatus) TableName() string {
+	return "project_statuses"
+}
+
+func getProjectStatusByID(s *xorm.Session, id int64) (status *ProjectStatus, err error) {
+	status = &ProjectStatus{}
+	exists, err := s.
+		Where("id = ?", id).
+		Get(status)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrProjectStatusDoesNotExist{StatusID: id}
+	}
+	return status, nil
+}
+
+func getProjectStatusesForProjects(s *xorm.Session, projectIDs []int64) (statuses []*ProjectStatus, err error) {
+	statuses = []*ProjectStatus{}
+	if len(projectIDs) == 0 {
+		return
+	}
+	err = s.
+		In("project_id", projectIDs).
+		OrderBy("position asc, id asc").
+		Find(&statuses)
+	return
+}
+
+// validateStatus checks the status of a task belongs to the project of the task
+func (t *Task) validateStatus(s *xorm.Session) error {
+	if t.StatusID == 0 {
+		return nil
+	}
+
+	status, err := getProjectStatusByID(s, t.StatusID)
+	if err != nil {
+		return err
+	}
+	if status.ProjectID != t.ProjectID {
+		return &ErrProjectStatusDoesNotBelongToProject{StatusID: status.ID, ProjectID: t.ProjectID}
+	}
+	return nil
+}
+
+// addStatusesToTasks adds the full status object to all tasks which have one
+func addStatusesToTasks(s *xorm.Session, taskMap map[int64]*Task) (err error) {
+	statusIDs := []int64{}
+	for _, t := range taskMap {
+		if t.StatusID != 0 {
+			statusIDs = append(statusIDs, t.StatusID)
+		}
+	}
+	if len(statusIDs) == 0 {
+		return nil
+	}
+
+	statuses := make(map[int64]*ProjectStatus, len(statusIDs))
+	err = s.In("id", statusIDs).Find(&statuses)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range taskMap {
+		t.Status = statuses[t.StatusID]
+	}
+	return nil
+}
+
+// Create creates a new status
+// @Summary Create a status
+// @Description Creates a new status the tasks of a project can have.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param status body models.ProjectStatus true "The status"
+// @Success 201 {object} models.ProjectStatus "The created status."
+// @Failure 400 {object} web.HTTPError "Invalid status provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/statuses [put]
+func (ps *ProjectStatus) Create(s *xorm.Session, a web.Auth) (err error) {
+	ps.ID = 0
+	ps.HexColor = utils.NormalizeHex(ps.HexColor)
+
+	ps.CreatedBy, err = GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return
+	}
+	ps.CreatedByID = ps.CreatedBy.ID
+
+	_, err = s.Insert(ps)
+	if err != nil {
+		return
+	}
+
+	ps.Position = calculateDefaultPosition(ps.ID, ps.Position)
+	_, err = s.
+		Where("id = ?", ps.ID).
+		Cols("position").
+		Update(ps)
+	return
+}
+
+// ReadAll returns all statuses of a project
+// @Summary Get all statuses of a project
+// @Description Returns all statuses of a project, sorted by their position.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {array} models.ProjectStatus "The statuses."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/statuses [get]
+func (ps *ProjectStatus) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	project := &Project{ID: ps.ProjectID}
+	canRead, _, err := project.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	statuses, err := getProjectStatusesForProjects(s, []int64{ps.ProjectID})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addCreatorsToProjectStatuses(s, statuses)
+	return statuses, len(statuses), int64(len(statuses)), err
+}
+
+// ReadOne returns one status
+// @Summary Get one status
+// @Description Returns one status of a project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param statusID path int true "Status ID"
+// @Success 200 {object} models.ProjectStatus "The status."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The status does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/statuses/{statusID} [get]
+func (ps *ProjectStatus) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	status, err := getProjectStatusByID(s, ps.ID)
+	if err != nil {
+		return err
+	}
+	*ps = *status
+	return addCreatorsToProjectStatuses(s, []*ProjectStatus{ps})
+}
+
+// Update updates a status
+// @Summary Update a status
+// @Description Updates the title, color and position of a status.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param statusID path int true "Status ID"
+// @Param status body models.ProjectStatus true "The status"
+// @Success 200 {object} models.ProjectStatus "The updated status."
+// @Failure 400 {object} web.HTTPError "Invalid status provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The status does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/statuses/{statusID} [post]
+func (ps *ProjectStatus) Update(s *xorm.Session, _ web.Auth) (err error) {
+	ps.HexColor = utils.NormalizeHex(ps.HexColor)
+	ps.Position = calculateDefaultPosition(ps.ID, ps.Position)
+	_, err = s.
+		Where("id = ?", ps.ID).
+		Cols("title", "hex_color", "position").
+		Update(ps)
+	if err != nil {
+		return
+	}
+
+	return ps.ReadOne(s, nil)
+}
+
+// Delete deletes a status
+// @Summary Delete a status
+// @Description Deletes a status. All tasks which had this status won't have a status afterwards.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param statusID path int true "Status ID"
+// @Success 200 {object} models.Message "The status was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The status does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/statuses/{statusID} [delete]
+func (ps *ProjectStatus) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where("status_id = ?", ps.ID).
+		Cols("status_id").
+		NoAutoTime().
+		Update(&Task{StatusID: 0})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("id = ?", ps.ID).Delete(&ProjectStatus{})
+	return
+}
+
+func deleteProjectStatusesForProject(s *xorm.Session, projectID int64) (err error) {
+	_, err = s.Where("project_id = ?", projectID).Delete(&ProjectStatus{})
+	return
+}
+
+func addCreatorsToProjectStatuses(s *xorm.Session, statuses []*ProjectStatus) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(statuses))
+	for _, ps := range statuses {
+		userIDs = append(userIDs, ps.CreatedByID)
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, ps := range statuses {
+		ps.CreatedBy = users[ps.CreatedByID]
+	}
+	return nil
+}
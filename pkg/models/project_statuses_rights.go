@@ -0,0 +1,75 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see a status
+func (ps *ProjectStatus) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	status, err := ps.getForProject(s)
+	if err != nil {
+		return false, 0, err
+	}
+
+	project := &Project{ID: status.ProjectID}
+	return project.CanRead(s, a)
+}
+
+// CanCreate checks if the user can create a status in a project
+func (ps *ProjectStatus) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if getSavedFilterIDFromProjectID(ps.ProjectID) > 0 {
+		return false, nil
+	}
+
+	project := &Project{ID: ps.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// CanUpdate checks if the user can update a status
+func (ps *ProjectStatus) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return ps.canDoProjectStatus(s, a)
+}
+
+// CanDelete checks if the user can delete a status
+func (ps *ProjectStatus) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return ps.canDoProjectStatus(s, a)
+}
+
+func (ps *ProjectStatus) canDoProjectStatus(s *xorm.Session, a web.Auth) (bool, error) {
+	status, err := ps.getForProject(s)
+	if err != nil {
+		return false, err
+	}
+
+	project := &Project{ID: status.ProjectID}
+	return project.CanWrite(s, a)
+}
+
+// getForProject returns the status and makes sure it belongs to the project from the request
+func (ps *ProjectStatus) getForProject(s *xorm.Session) (*ProjectStatus, error) {
+	status, err := getProjectStatusByID(s, ps.ID)
+	if err != nil {
+		return nil, err
+	}
+	if ps.ProjectID != 0 && status.ProjectID != ps.ProjectID {
+		return nil, &ErrProjectStatusDoesNotExist{StatusID: ps.ID}
+	}
+	return status, nil
+}
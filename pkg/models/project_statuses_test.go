@@ -0,0 +1,179 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectStatus_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	ps := &ProjectStatus{ProjectID: 1, Title: "Blocked", HexColor: "#ff0000"}
+	can, err := ps.CanCreate(s, u)
+	require.NoError(t, err)
+	assert.True(t, can)
+	err = ps.Create(s, u)
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertExists(t, "project_statuses", map[string]interface{}{
+		"id":            ps.ID,
+		"project_id":    1,
+		"title":         "Blocked",
+		"hex_color":     "ff0000",
+		"created_by_id": 1,
+	}, false)
+}
+
+func TestProjectStatus_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	ps := &ProjectStatus{ProjectID: 1}
+	result, _, _, err := ps.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+	require.NoError(t, err)
+	statuses := result.([]*ProjectStatus)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "Open", statuses[0].Title)
+	assert.Equal(t, "Waiting for feedback", statuses[1].Title)
+
+	_, _, _, err = ps.ReadAll(s, &user.User{ID: 13}, "", 0, 0)
+	require.Error(t, err)
+	assert.True(t, IsErrGenericForbidden(err))
+}
+
+func TestProjectStatus_CanRead(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	// Status 3 belongs to project 2
+	ps := &ProjectStatus{ID: 3, ProjectID: 1}
+	_, _, err := ps.CanRead(s, &user.User{ID: 1})
+	require.Error(t, err)
+	assert.True(t, IsErrProjectStatusDoesNotExist(err))
+}
+
+func TestProjectStatus_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	task := &Task{ID: 1, StatusID: 2}
+	_, err := s.ID(task.ID).Cols("status_id").Update(task)
+	require.NoError(t, err)
+
+	ps := &ProjectStatus{ID: 2, ProjectID: 1}
+	err = ps.Delete(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "project_statuses", map[string]interface{}{"id": 2})
+	db.AssertExists(t, "tasks", map[string]interface{}{"id": 1, "status_id": 0}, false)
+}
+
+func TestTask_Status(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("set on update", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1, Title: "test", ProjectID: 1, StatusID: 2}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{"id": 1, "status_id": 2}, false)
+
+		loaded := &Task{ID: 1}
+		err = loaded.ReadOne(s, u)
+		require.NoError(t, err)
+		require.NotNil(t, loaded.Status)
+		assert.Equal(t, "Waiting for feedback", loaded.Status.Title)
+	})
+	t.Run("status of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "test", ProjectID: 1, StatusID: 3}
+		err := task.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectStatusDoesNotBelongToProject(err))
+	})
+	t.Run("does not change when the bucket changes", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "test", ProjectID: 1, StatusID: 1}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+
+		task.BucketID = 3
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), task.StatusID)
+	})
+	t.Run("removed when moving to another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "test", ProjectID: 1, StatusID: 1}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+
+		task.ProjectID = 9
+		task.BucketID = 0
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		assert.Zero(t, task.StatusID)
+	})
+	t.Run("filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(int64(2)).Cols("status_id").Update(&Task{StatusID: 1})
+		require.NoError(t, err)
+
+		tc := &TaskCollection{ProjectID: 1, Filter: "status_id = 1"}
+		result, _, _, err := tc.ReadAll(s, u, "", 1, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(2), tasks[0].ID)
+	})
+}
@@ -0,0 +1,170 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// ProjectTaskDefaults holds the properties every new task in a project gets if it was created without them.
+// The bucket new tasks are added to is configured with the default_bucket_id and source_default_buckets properties of the project.
+type ProjectTaskDefaults struct {
+	// The ids of labels which are added to every new task.
+	LabelIDs []int64 `json:"label_ids"`
+	// The priority of new tasks which were created without a priority.
+	Priority int64 `json:"priority"`
+	// The id of the user new tasks without assignees are assigned to. The user needs access to the project.
+	AssigneeID int64 `json:"assignee_id"`
+	// If set, new tasks with a due date but without reminders get a reminder relative to their due date.
+	// This is a period in seconds, negative values mean the reminder triggers before the due date.
+	DueDateReminderOffset *int64 `json:"due_date_reminder_offset"`
+}
+
+// validateTaskDefaults checks all labels and the assignee of the task defaults of a project are accessible
+func (p *Project) validateTaskDefaults(s *xorm.Session, a web.Auth) error {
+	if p.TaskDefaults == nil {
+		return nil
+	}
+
+	for _, labelID := range p.TaskDefaults.LabelIDs {
+		label, err := getLabelByIDSimple(s, labelID)
+		if err != nil {
+			return err
+		}
+		can, _, err := label.CanRead(s, a)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return ErrUserHasNoAccessToLabel{LabelID: label.ID, UserID: a.GetID()}
+		}
+	}
+
+	if p.TaskDefaults.AssigneeID == 0 {
+		return nil
+	}
+
+	assignee, err := user.GetUserByID(s, p.TaskDefaults.AssigneeID)
+	if err != nil {
+		return err
+	}
+
+	// New projects are only accessible by their owner
+	if p.ID == 0 {
+		if assignee.ID != a.GetID() {
+			return ErrUserDoesNotHaveAccessToProject{ProjectID: p.ID, UserID: assignee.ID}
+		}
+		return nil
+	}
+
+	can, _, err := p.CanRead(s, assignee)
+	if err != nil {
+		return err
+	}
+	if !can {
+		return ErrUserDoesNotHaveAccessToProject{ProjectID: p.ID, UserID: assignee.ID}
+	}
+
+	return nil
+}
+
+// applyProjectTaskDefaults sets the priority, assignee and reminder defaults of the project of a new task
+// if the task was created without them. It returns the default labels, they can only be added once the task exists.
+func (t *Task) applyProjectTaskDefaults(s *xorm.Session) (labelIDs []int64, err error) {
+	p, err := GetProjectSimpleByID(s, t.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := p.TaskDefaults
+	if defaults == nil {
+		return nil, nil
+	}
+
+	if t.Priority == 0 {
+		t.Priority = defaults.Priority
+	}
+
+	if len(t.Assignees) == 0 && defaults.AssigneeID != 0 {
+		assignee, err := user.GetUserByID(s, defaults.AssigneeID)
+		if err != nil && !user.IsErrUserDoesNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			// The assignee might have lost access to the project since the defaults were set
+			can, _, err := p.CanRead(s, assignee)
+			if err != nil {
+				return nil, err
+			}
+			if can {
+				t.Assignees = []*user.User{assignee}
+			}
+		}
+	}
+
+	if len(t.Reminders) == 0 && defaults.DueDateReminderOffset != nil && !t.DueDate.IsZero() {
+		t.Reminders = []*TaskReminder{
+			{
+				RelativeTo:     ReminderRelationDueDate,
+				RelativePeriod: *defaults.DueDateReminderOffset,
+			},
+		}
+	}
+
+	return defaults.LabelIDs, nil
+}
+
+// addDefaultLabels adds the default labels of its project to a new task.
+// The labels are added regardless of the label rights of the user creating the task just like bucket actions do.
+func (t *Task) addDefaultLabels(s *xorm.Session, labelIDs []int64) error {
+	if len(labelIDs) == 0 {
+		return nil
+	}
+
+	existing := make(map[int64]bool, len(t.Labels))
+	for _, l := range t.Labels {
+		existing[l.ID] = true
+	}
+
+	for _, labelID := range labelIDs {
+		if existing[labelID] {
+			continue
+		}
+
+		label, err := getLabelByIDSimple(s, labelID)
+		if err != nil {
+			// Labels might have been deleted since the defaults were set
+			if IsErrLabelDoesNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		_, err = s.Insert(&LabelTask{LabelID: label.ID, TaskID: t.ID})
+		if err != nil {
+			return err
+		}
+
+		existing[label.ID] = true
+		t.Labels = append(t.Labels, label)
+	}
+
+	return nil
+}
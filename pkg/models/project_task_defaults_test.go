@@ -0,0 +1,161 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestTask_Create_ProjectTaskDefaults(t *testing.T) {
+	u := &user.User{ID: 1}
+	offset := int64(-3600)
+
+	setDefaults := func(t *testing.T, s *xorm.Session) {
+		_, err := s.
+			ID(1).
+			Cols("task_defaults").
+			Update(&Project{TaskDefaults: &ProjectTaskDefaults{
+				LabelIDs:              []int64{1, 2},
+				Priority:              3,
+				AssigneeID:            1,
+				DueDateReminderOffset: &offset,
+			}})
+		require.NoError(t, err)
+	}
+
+	t.Run("defaults are applied", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setDefaults(t, s)
+
+		dueDate := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+		task := &Task{Title: "Lorem", ProjectID: 1, DueDate: dueDate}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(3), task.Priority)
+		require.Len(t, task.Assignees, 1)
+		assert.Equal(t, int64(1), task.Assignees[0].ID)
+		require.Len(t, task.Labels, 2)
+		require.Len(t, task.Reminders, 1)
+		assert.Equal(t, dueDate.Add(-time.Hour), task.Reminders[0].Reminder.UTC())
+
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  task.ID,
+			"label_id": 1,
+		}, false)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  task.ID,
+			"label_id": 2,
+		}, false)
+		db.AssertExists(t, "task_assignees", map[string]interface{}{
+			"task_id": task.ID,
+			"user_id": 1,
+		}, false)
+		db.AssertExists(t, "task_reminders", map[string]interface{}{
+			"task_id":         task.ID,
+			"relative_to":     ReminderRelationDueDate,
+			"relative_period": offset,
+		}, false)
+	})
+	t.Run("provided values are kept", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setDefaults(t, s)
+
+		task := &Task{
+			Title:     "Lorem",
+			ProjectID: 1,
+			Priority:  1,
+			Reminders: []*TaskReminder{{Reminder: time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)}},
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), task.Priority)
+		require.Len(t, task.Reminders, 1)
+		assert.Empty(t, task.Reminders[0].RelativeTo)
+	})
+	t.Run("no reminder without due date", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setDefaults(t, s)
+
+		task := &Task{Title: "Lorem", ProjectID: 1}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.Empty(t, task.Reminders)
+	})
+	t.Run("project without defaults", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "Lorem", ProjectID: 1}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), task.Priority)
+		assert.Empty(t, task.Assignees)
+		assert.Empty(t, task.Labels)
+	})
+}
+
+func TestProject_validateTaskDefaults(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("valid", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &Project{ID: 1, TaskDefaults: &ProjectTaskDefaults{LabelIDs: []int64{1}, AssigneeID: 1}}
+		require.NoError(t, p.validateTaskDefaults(s, u))
+	})
+	t.Run("label without access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &Project{ID: 1, TaskDefaults: &ProjectTaskDefaults{LabelIDs: []int64{3}}}
+		err := p.validateTaskDefaults(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserHasNoAccessToLabel(err))
+	})
+	t.Run("assignee without access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &Project{ID: 1, TaskDefaults: &ProjectTaskDefaults{AssigneeID: 2}}
+		err := p.validateTaskDefaults(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserDoesNotHaveAccessToProject(err))
+	})
+}
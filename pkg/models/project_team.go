@@ -37,6 +37,8 @@ type TeamProject struct {
 	ProjectID int64 `xorm:"bigint not null INDEX" json:"-" param:"project"`
 	// The right this team has. 0 = Read only, 1 = Read & Write, 2 = Admin. See the docs for more details.
 	Right Right `xorm:"bigint INDEX not null default 0" json:"right" valid:"length(0|2)" maximum:"2" default:"0"`
+	// The id of a role of the project which gives additional permissions on top of the right. 0 means no role.
+	RoleID int64 `xorm:"bigint null default 0" json:"role_id"`
 
 	// A timestamp when this relation was created. You cannot change this value.
 	Created time.Time `xorm:"created not null" json:"created"`
@@ -54,8 +56,9 @@ func (TeamProject) TableName() string {
 
 // TeamWithRight represents a team, combined with rights.
 type TeamWithRight struct {
-	Team  `xorm:"extends"`
-	Right Right `json:"right"`
+	Team   `xorm:"extends"`
+	Right  Right `json:"right"`
+	RoleID int64 `json:"role_id"`
 }
 
 // Create creates a new team <-> project relation
@@ -92,6 +95,10 @@ func (tl *TeamProject) Create(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
+	if err = validateShareRole(s, tl.RoleID, tl.ProjectID); err != nil {
+		return err
+	}
+
 	// Check if the team is already on the project
 	exists, err := s.Where("team_id = ?", tl.TeamID).
 		And("project_id = ?", tl.ProjectID).
@@ -109,7 +116,7 @@ func (tl *TeamProject) Create(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
-	err = events.Dispatch(&ProjectSharedWithTeamEvent{
+	err = events.DispatchOnCommit(s, &ProjectSharedWithTeamEvent{
 		Project: l,
 		Team:    team,
 		Doer:    a,
@@ -254,9 +261,13 @@ func (tl *TeamProject) Update(s *xorm.Session, _ web.Auth) (err error) {
 		return err
 	}
 
+	if err := validateShareRole(s, tl.RoleID, tl.ProjectID); err != nil {
+		return err
+	}
+
 	_, err = s.
 		Where("project_id = ? AND team_id = ?", tl.ProjectID, tl.TeamID).
-		Cols("right").
+		Cols("right", "role_id").
 		Update(tl)
 	if err != nil {
 		return err
@@ -0,0 +1,703 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// ProjectTemplate is a reusable blueprint of a project which can be used to create new projects
+type ProjectTemplate struct {
+	// The unique, numeric id of this project template. Templates configured for the whole instance have a negative id.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"projecttemplate"`
+	// The title of this project template.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// The description new projects created from this template will get.
+	Description string `xorm:"longtext null" json:"description"`
+	// The color new projects created from this template will get.
+	HexColor string `xorm:"varchar(6) null" json:"hex_color" valid:"runelength(0|7)" maxLength:"7"`
+	// The buckets which will be created from this template, in this order. If empty, the new project gets the default backlog bucket.
+	Buckets []*BucketTemplateBucket `xorm:"JSON null" json:"buckets"`
+	// The labels used by the tasks of this template.
+	Labels []*ProjectTemplateLabel `xorm:"JSON null" json:"labels"`
+	// The custom fields which will be created in new projects.
+	CustomFields []*ProjectTemplateCustomField `xorm:"JSON null" json:"custom_fields"`
+	// The tasks which will be created in new projects.
+	Tasks []*ProjectTemplateTask `xorm:"JSON null" json:"tasks"`
+	// True if this template is configured for the whole instance. These templates cannot be changed through the api.
+	IsInstanceTemplate bool `xorm:"-" json:"is_instance_template"`
+
+	OwnerID int64 `xorm:"bigint not null INDEX" json:"-"`
+	// The user who owns this template. Empty for instance templates.
+	Owner *user.User `xorm:"-" json:"owner" valid:"-"`
+
+	// A timestamp when this template was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this template was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// ProjectTemplateLabel is a label used by the tasks of a project template
+type ProjectTemplateLabel struct {
+	// The title of the label. When a project is created from the template, an existing label of the user with the same title is used instead of creating a new one.
+	Title string `json:"title"`
+	// The color of the label in hex format.
+	HexColor string `json:"hex_color"`
+}
+
+// ProjectTemplateCustomField is a custom field of a project template
+type ProjectTemplateCustomField struct {
+	// The title of the custom field.
+	Title string `json:"title"`
+	// The type of the custom field. Can be `text`, `number`, `date`, `select`, `multiselect`, `checkbox` or `url`.
+	Type CustomFieldType `json:"type"`
+	// The options of `select` and `multiselect` fields.
+	Options []string `json:"options"`
+}
+
+// ProjectTemplateTask is a task of a project template
+type ProjectTemplateTask struct {
+	// The title of the task.
+	Title string `json:"title"`
+	// The description of the task.
+	Description string `json:"description"`
+	// The priority of the task.
+	Priority int64 `json:"priority"`
+	// The color of the task in hex format.
+	HexColor string `json:"hex_color"`
+	// The position of the bucket the task will be put in, starting at 0 for the first bucket of the template.
+	BucketIndex int `json:"bucket_index"`
+	// The titles of the labels of the template the task will get.
+	Labels []string `json:"labels"`
+	// The titles of the checklist items the task will get, in this order.
+	ChecklistItems []string `json:"checklist_items"`
+}
+
+// TableName returns the table name for project templates
+func (pt *ProjectTemplate) TableName() string {
+	return "project_templates"
+}
+
+// getInstanceProjectTemplates returns all project templates from the config. Like instance bucket templates, they
+// get a negative id derived from their position in the config.
+func getInstanceProjectTemplates() (templates []*ProjectTemplate, err error) {
+	templates = []*ProjectTemplate{}
+	err = config.ProjectsTemplates.Unmarshal(&templates)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, t := range templates {
+		t.ID = int64(i+1) * -1
+		t.IsInstanceTemplate = true
+	}
+
+	return
+}
+
+func getProjectTemplateByID(s *xorm.Session, id int64) (template *ProjectTemplate, err error) {
+	if id < 0 {
+		templates, err := getInstanceProjectTemplates()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range templates {
+			if t.ID == id {
+				return t, nil
+			}
+		}
+		return nil, &ErrProjectTemplateDoesNotExist{ProjectTemplateID: id}
+	}
+
+	template = &ProjectTemplate{}
+	exists, err := s.Where("id = ?", id).Get(template)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrProjectTemplateDoesNotExist{ProjectTemplateID: id}
+	}
+	return
+}
+
+func (pt *ProjectTemplate) invalid(reason string) error {
+	return &ErrInvalidProjectTemplate{ProjectTemplateID: pt.ID, Reason: reason}
+}
+
+func (pt *ProjectTemplate) validate() error {
+	var doneBuckets int
+	for _, b := range pt.Buckets {
+		if b.Title == "" || b.Limit < 0 {
+			return pt.invalid("all buckets need a title and a limit which is not negative")
+		}
+		if b.IsDoneBucket {
+			doneBuckets++
+		}
+	}
+	if doneBuckets > 1 {
+		return pt.invalid("there can be only one done bucket")
+	}
+
+	labels := make(map[string]bool, len(pt.Labels))
+	for _, l := range pt.Labels {
+		if l.Title == "" {
+			return pt.invalid("all labels need a title")
+		}
+		if labels[l.Title] {
+			return pt.invalid("the label " + l.Title + " exists more than once")
+		}
+		labels[l.Title] = true
+	}
+
+	for _, f := range pt.CustomFields {
+		if f.Title == "" {
+			return pt.invalid("all custom fields need a title")
+		}
+		cf := &CustomField{Type: f.Type, Options: f.Options}
+		err := cf.validate()
+		if err != nil {
+			return err
+		}
+		f.Options = cf.Options
+	}
+
+	for _, t := range pt.Tasks {
+		if t.Title == "" {
+			return pt.invalid("all tasks need a title")
+		}
+		if t.BucketIndex < 0 || (t.BucketIndex > 0 && t.BucketIndex >= len(pt.Buckets)) {
+			return pt.invalid("the task " + t.Title + " references a bucket which does not exist")
+		}
+		for _, label := range t.Labels {
+			if !labels[label] {
+				return pt.invalid("the task " + t.Title + " references the label " + label + " which is not part of the template")
+			}
+		}
+	}
+
+	return nil
+}
+
+// applySettings sets all fields the template defines on a project which is about to be created, as long as
+// the project does not have a value for them already.
+func (pt *ProjectTemplate) applySettings(project *Project) {
+	if project.Description == "" {
+		project.Description = pt.Description
+	}
+	if project.HexColor == "" {
+		project.HexColor = pt.HexColor
+	}
+}
+
+// applyToProject creates all buckets, custom fields, labels and tasks of the template in a newly created project.
+func (pt *ProjectTemplate) applyToProject(s *xorm.Session, project *Project, a web.Auth) (err error) {
+	doer, err := user.GetFromAuth(a)
+	if err != nil {
+		return err
+	}
+
+	var buckets []*Bucket
+	if len(pt.Buckets) > 0 {
+		bt := &BucketTemplate{Buckets: pt.Buckets}
+		buckets, err = bt.applyToProject(s, project, a, false)
+		if err != nil {
+			return err
+		}
+	} else {
+		b := &Bucket{
+			ProjectID: project.ID,
+			Title:     "Backlog",
+		}
+		err = b.Create(s, a)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, f := range pt.CustomFields {
+		cf := &CustomField{
+			ProjectID: project.ID,
+			Title:     f.Title,
+			Type:      f.Type,
+			Options:   f.Options,
+		}
+		err = cf.Create(s, a)
+		if err != nil {
+			return err
+		}
+	}
+
+	labelIDs, err := pt.getOrCreateLabels(s, doer)
+	if err != nil {
+		return err
+	}
+
+	for _, tt := range pt.Tasks {
+		t := &Task{
+			Title:       tt.Title,
+			Description: tt.Description,
+			Priority:    tt.Priority,
+			HexColor:    tt.HexColor,
+			ProjectID:   project.ID,
+		}
+		if len(buckets) > 0 {
+			t.BucketID = buckets[tt.BucketIndex].ID
+		}
+		err = createTask(s, t, a, false)
+		if err != nil {
+			return err
+		}
+
+		for _, label := range tt.Labels {
+			_, err = s.Insert(&LabelTask{LabelID: labelIDs[label], TaskID: t.ID})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, title := range tt.ChecklistItems {
+			err = createChecklistItem(s, &ChecklistItem{TaskID: t.ID, Title: title}, doer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getOrCreateLabels returns the ids of all labels used by the tasks of the template, mapped by their title.
+// Labels the user already created are reused, all others are created.
+func (pt *ProjectTemplate) getOrCreateLabels(s *xorm.Session, doer *user.User) (labelIDs map[string]int64, err error) {
+	labelIDs = make(map[string]int64)
+	used := make(map[string]bool)
+	for _, t := range pt.Tasks {
+		for _, label := range t.Labels {
+			used[label] = true
+		}
+	}
+	if len(used) == 0 {
+		return
+	}
+
+	titles := make([]string, 0, len(used))
+	for title := range used {
+		titles = append(titles, title)
+	}
+
+	existing := []*Label{}
+	err = s.
+		Where("created_by_id = ?", doer.ID).
+		In("title", titles).
+		OrderBy("id asc").
+		Find(&existing)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range existing {
+		if _, has := labelIDs[l.Title]; !has {
+			labelIDs[l.Title] = l.ID
+		}
+	}
+
+	for _, tl := range pt.Labels {
+		if !used[tl.Title] {
+			continue
+		}
+		if _, has := labelIDs[tl.Title]; has {
+			continue
+		}
+		l := &Label{
+			Title:    tl.Title,
+			HexColor: tl.HexColor,
+		}
+		err = l.Create(s, doer)
+		if err != nil {
+			return nil, err
+		}
+		labelIDs[l.Title] = l.ID
+	}
+
+	return
+}
+
+// Create creates a new project template
+// @Summary Create a new project template
+// @Description Creates a new project template for the current user. It can be used to create new projects. To save an existing project as a template, use the `/projects/{id}/projecttemplate` endpoint instead.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param template body models.ProjectTemplate true "The project template"
+// @Success 201 {object} models.ProjectTemplate "The created project template."
+// @Failure 400 {object} web.HTTPError "Invalid project template object provided."
+// @Failure 403 {object} web.HTTPError "Link shares cannot create project templates."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projecttemplates [put]
+func (pt *ProjectTemplate) Create(s *xorm.Session, a web.Auth) (err error) {
+	pt.ID = 0
+	pt.IsInstanceTemplate = false
+
+	err = pt.validate()
+	if err != nil {
+		return err
+	}
+
+	pt.Owner, err = user.GetUserByID(s, a.GetID())
+	if err != nil {
+		return err
+	}
+	pt.OwnerID = pt.Owner.ID
+
+	_, err = s.Insert(pt)
+	return
+}
+
+// ReadOne returns one project template
+// @Summary Get a project template
+// @Description Returns a single project template by its id.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project template ID"
+// @Success 200 {object} models.ProjectTemplate "The project template"
+// @Failure 403 {object} web.HTTPError "The user does not have access to that project template."
+// @Failure 404 {object} web.HTTPError "The project template does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projecttemplates/{id} [get]
+func (pt *ProjectTemplate) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	// The template was already loaded in CanRead
+	if pt.IsInstanceTemplate {
+		return nil
+	}
+
+	pt.Owner, err = user.GetUserByID(s, pt.OwnerID)
+	return
+}
+
+// ReadAll returns all project templates available to the current user
+// @Summary Get all project templates
+// @Description Returns all project templates configured for this instance and all project templates the current user created.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param s query string false "Search project templates by their title."
+// @Success 200 {array} models.ProjectTemplate "The project templates"
+// @Failure 403 {object} web.HTTPError "Link shares cannot access project templates."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projecttemplates [get]
+func (pt *ProjectTemplate) ReadAll(s *xorm.Session, a web.Auth, search string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	if _, is := a.(*LinkSharing); is {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	instanceTemplates, err := getInstanceProjectTemplates()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	templates := make([]*ProjectTemplate, 0, len(instanceTemplates))
+	for _, t := range instanceTemplates {
+		if search != "" && !strings.Contains(strings.ToLower(t.Title), strings.ToLower(search)) {
+			continue
+		}
+		templates = append(templates, t)
+	}
+
+	var where builder.Cond = builder.Eq{"owner_id": a.GetID()}
+	if search != "" {
+		where = builder.And(
+			where,
+			db.ILIKE("title", search),
+		)
+	}
+
+	userTemplates := []*ProjectTemplate{}
+	err = s.
+		Where(where).
+		OrderBy("id asc").
+		Find(&userTemplates)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if len(userTemplates) > 0 {
+		owner, err := user.GetUserByID(s, a.GetID())
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		for _, t := range userTemplates {
+			t.Owner = owner
+		}
+	}
+
+	templates = append(templates, userTemplates...)
+
+	return templates, len(templates), int64(len(templates)), nil
+}
+
+// Update updates a project template
+// @Summary Update a project template
+// @Description Updates a project template of the current user. Templates configured for the instance cannot be updated.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project template ID"
+// @Param template body models.ProjectTemplate true "The project template"
+// @Success 200 {object} models.ProjectTemplate "The updated project template."
+// @Failure 400 {object} web.HTTPError "Invalid project template object provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to that project template."
+// @Failure 404 {object} web.HTTPError "The project template does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projecttemplates/{id} [post]
+func (pt *ProjectTemplate) Update(s *xorm.Session, _ web.Auth) (err error) {
+	err = pt.validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("id = ?", pt.ID).
+		Cols(
+			"title",
+			"description",
+			"hex_color",
+			"buckets",
+			"labels",
+			"custom_fields",
+			"tasks",
+		).
+		Update(pt)
+	return
+}
+
+// Delete deletes a project template
+// @Summary Delete a project template
+// @Description Deletes a project template of the current user. Projects created from it are not changed.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project template ID"
+// @Success 200 {object} models.Message "The project template was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to that project template."
+// @Failure 404 {object} web.HTTPError "The project template does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projecttemplates/{id} [delete]
+func (pt *ProjectTemplate) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("id = ?", pt.ID).Delete(&ProjectTemplate{})
+	return
+}
+
+// ProjectSaveAsTemplate holds everything needed to save an existing project as a project template
+type ProjectSaveAsTemplate struct {
+	// The project which will be saved as template
+	ProjectID int64 `json:"-" param:"project"`
+	// The title of the new template. Defaults to the title of the project.
+	Title string `json:"title"`
+	// If true, all tasks of the project which are not done are saved as example tasks of the template.
+	IncludeTasks bool `json:"include_tasks"`
+
+	// The created project template
+	Template *ProjectTemplate `json:"template"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// Create saves a project as template
+// @Summary Save a project as template
+// @Description Creates a new project template for the current user from an existing project. The template contains the buckets, custom fields and the labels used by the tasks of the project. If `include_tasks` is set, all tasks which are not done are saved with their labels and checklist items as well.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param template body models.ProjectSaveAsTemplate true "The options for the new template"
+// @Success 201 {object} models.ProjectSaveAsTemplate "The created project template."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/projecttemplate [put]
+func (psat *ProjectSaveAsTemplate) Create(s *xorm.Session, a web.Auth) (err error) {
+	project, err := GetProjectSimpleByID(s, psat.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	template := &ProjectTemplate{
+		Title:        psat.Title,
+		Description:  project.Description,
+		HexColor:     project.HexColor,
+		Buckets:      []*BucketTemplateBucket{},
+		Labels:       []*ProjectTemplateLabel{},
+		CustomFields: []*ProjectTemplateCustomField{},
+		Tasks:        []*ProjectTemplateTask{},
+	}
+	if template.Title == "" {
+		template.Title = project.Title
+	}
+
+	buckets := []*Bucket{}
+	err = s.
+		Where("project_id = ?", project.ID).
+		OrderBy("position asc, id asc").
+		Find(&buckets)
+	if err != nil {
+		return err
+	}
+	bucketIndexes := make(map[int64]int, len(buckets))
+	for i, b := range buckets {
+		bucketIndexes[b.ID] = i
+		template.Buckets = append(template.Buckets, &BucketTemplateBucket{
+			Title:        b.Title,
+			Limit:        b.Limit,
+			IsDoneBucket: b.ID == project.DoneBucketID,
+		})
+	}
+
+	customFields, err := getCustomFieldsForProjects(s, []int64{project.ID})
+	if err != nil {
+		return err
+	}
+	for _, cf := range customFields {
+		template.CustomFields = append(template.CustomFields, &ProjectTemplateCustomField{
+			Title:   cf.Title,
+			Type:    cf.Type,
+			Options: cf.Options,
+		})
+	}
+
+	tasks := []*Task{}
+	err = s.
+		Where("project_id = ?", project.ID).
+		OrderBy("id asc").
+		Find(&tasks)
+	if err != nil {
+		return err
+	}
+	taskIDs := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		taskIDs = append(taskIDs, t.ID)
+	}
+
+	labelTasks := []*LabelTask{}
+	labels := make(map[int64]*Label)
+	if len(taskIDs) > 0 {
+		err = s.In("task_id", taskIDs).OrderBy("id asc").Find(&labelTasks)
+		if err != nil {
+			return err
+		}
+	}
+	if len(labelTasks) > 0 {
+		labelIDs := make([]int64, 0, len(labelTasks))
+		for _, lt := range labelTasks {
+			labelIDs = append(labelIDs, lt.LabelID)
+		}
+		err = s.In("id", labelIDs).OrderBy("id asc").Find(&labels)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Labels are referenced by their title in the template, labels with the same title are therefore only saved once.
+	savedLabels := make(map[string]bool, len(labels))
+	for _, lt := range labelTasks {
+		l, has := labels[lt.LabelID]
+		if !has || savedLabels[l.Title] {
+			continue
+		}
+		savedLabels[l.Title] = true
+		template.Labels = append(template.Labels, &ProjectTemplateLabel{
+			Title:    l.Title,
+			HexColor: l.HexColor,
+		})
+	}
+
+	if psat.IncludeTasks {
+		err = psat.addTasksToTemplate(s, template, tasks, labelTasks, labels, bucketIndexes)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = template.Create(s, a)
+	if err != nil {
+		return err
+	}
+
+	psat.Template = template
+	return nil
+}
+
+func (psat *ProjectSaveAsTemplate) addTasksToTemplate(s *xorm.Session, template *ProjectTemplate, tasks []*Task, labelTasks []*LabelTask, labels map[int64]*Label, bucketIndexes map[int64]int) (err error) {
+	taskLabels := make(map[int64][]string)
+	seen := make(map[int64]map[string]bool)
+	for _, lt := range labelTasks {
+		l, has := labels[lt.LabelID]
+		if !has || seen[lt.TaskID][l.Title] {
+			continue
+		}
+		if seen[lt.TaskID] == nil {
+			seen[lt.TaskID] = make(map[string]bool)
+		}
+		seen[lt.TaskID][l.Title] = true
+		taskLabels[lt.TaskID] = append(taskLabels[lt.TaskID], l.Title)
+	}
+
+	taskIDs := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		taskIDs = append(taskIDs, t.ID)
+	}
+	checklistItems, err := getChecklistItemsForTasks(s, taskIDs)
+	if err != nil {
+		return err
+	}
+	taskChecklistItems := make(map[int64][]string)
+	for _, item := range checklistItems {
+		taskChecklistItems[item.TaskID] = append(taskChecklistItems[item.TaskID], item.Title)
+	}
+
+	for _, t := range tasks {
+		if t.Done {
+			continue
+		}
+		template.Tasks = append(template.Tasks, &ProjectTemplateTask{
+			Title:          t.Title,
+			Description:    t.Description,
+			Priority:       t.Priority,
+			HexColor:       t.HexColor,
+			BucketIndex:    bucketIndexes[t.BucketID],
+			Labels:         taskLabels[t.ID],
+			ChecklistItems: taskChecklistItems[t.ID],
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if a user has the right to read a project template
+func (pt *ProjectTemplate) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, 0, nil
+	}
+
+	template, err := getProjectTemplateByID(s, pt.ID)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if !template.IsInstanceTemplate && template.OwnerID != a.GetID() {
+		return false, 0, nil
+	}
+
+	*pt = *template
+	if template.IsInstanceTemplate {
+		return true, int(RightRead), nil
+	}
+	return true, int(RightAdmin), nil
+}
+
+// CanCreate checks if a user has the right to create a project template
+func (pt *ProjectTemplate) CanCreate(_ *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// CanUpdate checks if a user has the right to update a project template
+func (pt *ProjectTemplate) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	// A normal check would replace the passed struct which in our case would override the values we want to update.
+	ptt := &ProjectTemplate{ID: pt.ID}
+	return ptt.canDoProjectTemplate(s, a)
+}
+
+// CanDelete checks if a user has the right to delete a project template
+func (pt *ProjectTemplate) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return pt.canDoProjectTemplate(s, a)
+}
+
+// canDoProjectTemplate checks if the template can be changed by the user. Only owners can change their templates,
+// instance templates can only be changed through the config.
+func (pt *ProjectTemplate) canDoProjectTemplate(s *xorm.Session, a web.Auth) (bool, error) {
+	can, maxRight, err := pt.CanRead(s, a)
+	if err != nil || !can {
+		return false, err
+	}
+
+	return maxRight == int(RightAdmin), nil
+}
+
+// CanCreate checks if a user has the right to save a project as template
+func (psat *ProjectSaveAsTemplate) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	project := &Project{ID: psat.ProjectID}
+	canRead, _, err := project.CanRead(s, a)
+	return canRead, err
+}
@@ -0,0 +1,312 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectTemplate_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pt := &ProjectTemplate{
+			Title: "New template",
+			Buckets: []*BucketTemplateBucket{
+				{Title: "Todo"},
+			},
+			Labels: []*ProjectTemplateLabel{{Title: "Bug"}},
+			Tasks: []*ProjectTemplateTask{
+				{Title: "First task", Labels: []string{"Bug"}},
+			},
+		}
+		err := pt.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_templates", map[string]interface{}{
+			"id":       pt.ID,
+			"title":    "New template",
+			"owner_id": 1,
+		}, false)
+	})
+	t.Run("task with unknown label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pt := &ProjectTemplate{
+			Title: "New template",
+			Tasks: []*ProjectTemplateTask{
+				{Title: "First task", Labels: []string{"Bug"}},
+			},
+		}
+		err := pt.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidProjectTemplate(err))
+	})
+	t.Run("task with unknown bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pt := &ProjectTemplate{
+			Title:   "New template",
+			Buckets: []*BucketTemplateBucket{{Title: "Todo"}},
+			Tasks: []*ProjectTemplateTask{
+				{Title: "First task", BucketIndex: 1},
+			},
+		}
+		err := pt.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidProjectTemplate(err))
+	})
+	t.Run("invalid custom field", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pt := &ProjectTemplate{
+			Title:        "New template",
+			CustomFields: []*ProjectTemplateCustomField{{Title: "Field", Type: CustomFieldTypeSelect}},
+		}
+		err := pt.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidCustomFieldOptions(err))
+	})
+}
+
+func TestProjectTemplate_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	config.ProjectsTemplates.Set([]map[string]interface{}{
+		{
+			"title":   "Instance template",
+			"buckets": []map[string]interface{}{{"title": "Todo"}},
+		},
+	})
+	defer config.ProjectsTemplates.Set(nil)
+
+	result, _, _, err := (&ProjectTemplate{}).ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+	require.NoError(t, err)
+	templates := result.([]*ProjectTemplate)
+	require.Len(t, templates, 2)
+	assert.Equal(t, int64(-1), templates[0].ID)
+	assert.True(t, templates[0].IsInstanceTemplate)
+	assert.Equal(t, int64(1), templates[1].ID)
+	assert.Equal(t, "Sprint", templates[1].Title)
+}
+
+func TestProjectTemplate_CanRead(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	can, _, err := (&ProjectTemplate{ID: 2}).CanRead(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.False(t, can)
+
+	can, maxRight, err := (&ProjectTemplate{ID: 1}).CanRead(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.True(t, can)
+	assert.Equal(t, int(RightAdmin), maxRight)
+}
+
+func TestProjectTemplate_Instantiate(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("from user template", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project := &Project{
+			Title:             "From template",
+			ProjectTemplateID: 1,
+		}
+		err := project.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, "A project for a single sprint", project.Description)
+		assert.Equal(t, "e8e8e8", project.HexColor)
+		assert.NotZero(t, project.DoneBucketID)
+
+		buckets := []*Bucket{}
+		err = s.Where("project_id = ?", project.ID).OrderBy("position asc").Find(&buckets)
+		require.NoError(t, err)
+		require.Len(t, buckets, 3)
+		assert.Equal(t, "Doing", buckets[1].Title)
+		assert.Equal(t, int64(2), buckets[1].Limit)
+
+		db.AssertExists(t, "custom_fields", map[string]interface{}{
+			"project_id": project.ID,
+			"title":      "Story points",
+			"type":       "number",
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"project_id": project.ID,
+			"title":      "Plan the sprint",
+			"bucket_id":  buckets[1].ID,
+			"priority":   2,
+		}, false)
+
+		task := &Task{}
+		_, err = s.Where("project_id = ? AND title = ?", project.ID, "Plan the sprint").Get(task)
+		require.NoError(t, err)
+		// The existing label of the user is reused
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  task.ID,
+			"label_id": 1,
+		}, false)
+		db.AssertExists(t, "labels", map[string]interface{}{
+			"title":         "Retro",
+			"hex_color":     "ff0000",
+			"created_by_id": 1,
+		}, false)
+		db.AssertExists(t, "task_checklist_items", map[string]interface{}{
+			"task_id": task.ID,
+			"title":   "Estimate",
+		}, false)
+	})
+	t.Run("from instance template", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.ProjectsTemplates.Set([]map[string]interface{}{
+			{
+				"title": "Instance template",
+				"tasks": []map[string]interface{}{{"title": "Onboarding"}},
+			},
+		})
+		defer config.ProjectsTemplates.Set(nil)
+
+		project := &Project{
+			Title:             "From template",
+			ProjectTemplateID: -1,
+		}
+		err := project.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		// Templates without buckets get the default backlog bucket
+		db.AssertExists(t, "buckets", map[string]interface{}{
+			"project_id": project.ID,
+			"title":      "Backlog",
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"project_id": project.ID,
+			"title":      "Onboarding",
+		}, false)
+	})
+	t.Run("template of another user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project := &Project{
+			Title:             "From template",
+			ProjectTemplateID: 2,
+		}
+		err := project.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectTemplateDoesNotExist(err))
+	})
+}
+
+func TestProjectSaveAsTemplate_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("without tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		psat := &ProjectSaveAsTemplate{ProjectID: 1}
+		can, err := psat.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = psat.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, "Test1", psat.Template.Title)
+		assert.NotEmpty(t, psat.Template.Buckets)
+		assert.Empty(t, psat.Template.Tasks)
+		var hasLabel bool
+		for _, l := range psat.Template.Labels {
+			if l.Title == "Label #4 - visible via other task" {
+				hasLabel = true
+			}
+		}
+		assert.True(t, hasLabel)
+		db.AssertExists(t, "project_templates", map[string]interface{}{
+			"id":       psat.Template.ID,
+			"title":    "Test1",
+			"owner_id": 1,
+		}, false)
+	})
+	t.Run("with tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		psat := &ProjectSaveAsTemplate{ProjectID: 1, Title: "With tasks", IncludeTasks: true}
+		err := psat.Create(s, u)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, psat.Template.Tasks)
+		for _, task := range psat.Template.Tasks {
+			assert.NotEqual(t, "task #2 done", task.Title)
+		}
+		assert.Equal(t, "task #1", psat.Template.Tasks[0].Title)
+		assert.Equal(t, []string{"Label #4 - visible via other task"}, psat.Template.Tasks[0].Labels)
+
+		// The saved template can be used to create a new project again
+		project := &Project{Title: "Copy", ProjectTemplateID: psat.Template.ID}
+		err = project.Create(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("no access to project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		psat := &ProjectSaveAsTemplate{ProjectID: 20}
+		can, err := psat.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -57,6 +57,43 @@ func TestProject_CreateOrUpdate(t *testing.T) {
 				"project_id": project.ID,
 			}, false)
 		})
+		t.Run("with bucket template", func(t *testing.T) {
+			db.LoadAndAssertFixtures(t)
+			s := db.NewSession()
+			project := Project{
+				Title:            "test",
+				BucketTemplateID: 1,
+			}
+			err := project.Create(s, usr)
+			require.NoError(t, err)
+			err = s.Commit()
+			require.NoError(t, err)
+			db.AssertExists(t, "buckets", map[string]interface{}{
+				"project_id": project.ID,
+				"title":      "In Progress",
+				"limit":      3,
+			}, false)
+			db.AssertExists(t, "buckets", map[string]interface{}{
+				"id":         project.DoneBucketID,
+				"project_id": project.ID,
+				"title":      "Done",
+			}, false)
+			count, err := db.NewSession().Where("project_id = ?", project.ID).Count(&Bucket{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(4), count)
+		})
+		t.Run("with bucket template of another user", func(t *testing.T) {
+			db.LoadAndAssertFixtures(t)
+			s := db.NewSession()
+			project := Project{
+				Title:            "test",
+				BucketTemplateID: 2,
+			}
+			err := project.Create(s, usr)
+			require.Error(t, err)
+			assert.True(t, IsErrBucketTemplateDoesNotExist(err))
+			_ = s.Close()
+		})
 		t.Run("nonexistant parent project", func(t *testing.T) {
 			db.LoadAndAssertFixtures(t)
 			s := db.NewSession()
@@ -135,6 +172,23 @@ func TestProject_CreateOrUpdate(t *testing.T) {
 				"description": project.Description,
 			}, false)
 		})
+		t.Run("accent color", func(t *testing.T) {
+			db.LoadAndAssertFixtures(t)
+			s := db.NewSession()
+			project := Project{
+				ID:          1,
+				Title:       "test",
+				AccentColor: "#ff00AA",
+			}
+			err := project.Update(s, usr)
+			require.NoError(t, err)
+			err = s.Commit()
+			require.NoError(t, err)
+			db.AssertExists(t, "projects", map[string]interface{}{
+				"id":           project.ID,
+				"accent_color": "ff00AA",
+			}, false)
+		})
 		t.Run("nonexistant", func(t *testing.T) {
 			db.LoadAndAssertFixtures(t)
 			s := db.NewSession()
@@ -161,6 +215,54 @@ func TestProject_CreateOrUpdate(t *testing.T) {
 			assert.True(t, IsErrProjectIdentifierIsNotUnique(err))
 			_ = s.Close()
 		})
+		t.Run("source default buckets", func(t *testing.T) {
+			db.LoadAndAssertFixtures(t)
+			s := db.NewSession()
+			defer s.Close()
+			project := Project{
+				ID:    1,
+				Title: "Test1",
+				SourceDefaultBuckets: map[TaskSource]int64{
+					TaskSourceLinkShare: 3,
+				},
+			}
+			err := project.Update(s, usr)
+			require.NoError(t, err)
+
+			p, err := GetProjectSimpleByID(s, 1)
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), p.SourceDefaultBuckets[TaskSourceLinkShare])
+		})
+		t.Run("source default bucket of another project", func(t *testing.T) {
+			db.LoadAndAssertFixtures(t)
+			s := db.NewSession()
+			defer s.Close()
+			project := Project{
+				ID:    1,
+				Title: "Test1",
+				SourceDefaultBuckets: map[TaskSource]int64{
+					TaskSourceLinkShare: 4,
+				},
+			}
+			err := project.Update(s, usr)
+			require.Error(t, err)
+			assert.True(t, IsErrBucketDoesNotBelongToProject(err))
+		})
+		t.Run("invalid task source", func(t *testing.T) {
+			db.LoadAndAssertFixtures(t)
+			s := db.NewSession()
+			defer s.Close()
+			project := Project{
+				ID:    1,
+				Title: "Test1",
+				SourceDefaultBuckets: map[TaskSource]int64{
+					"carrier_pigeon": 3,
+				},
+			}
+			err := project.Update(s, usr)
+			require.Error(t, err)
+			assert.True(t, IsErrInvalidData(err))
+		})
 		t.Run("change parent project", func(t *testing.T) {
 			t.Run("own", func(t *testing.T) {
 				usr := &user.User{
@@ -0,0 +1,207 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// ProjectUserPositionKind defines in which list of projects a user position is used
+type ProjectUserPositionKind string
+
+const (
+	// ProjectUserPositionKindRoot is used for the order of the top-level projects of a user.
+	ProjectUserPositionKindRoot ProjectUserPositionKind = "root"
+	// ProjectUserPositionKindFavorites is used for the order of the favorite projects of a user.
+	ProjectUserPositionKindFavorites ProjectUserPositionKind = "favorites"
+)
+
+// ProjectUserPosition holds the position of a project in the top-level or favorite projects of a single user.
+// Other than the position of a project, which is the same for everyone, it only changes the order the user who set it sees.
+type ProjectUserPosition struct {
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	// The project this position belongs to.
+	ProjectID int64 `xorm:"bigint not null unique(project_user_kind)" json:"project_id" param:"project"`
+	UserID    int64 `xorm:"bigint not null unique(project_user_kind) INDEX" json:"-"`
+	// The list of projects this position is used in. Can be `root` for the top-level projects or `favorites` for the favorite projects.
+	Kind ProjectUserPositionKind `xorm:"varchar(20) not null unique(project_user_kind)" json:"kind" param:"kind"`
+	// The position of the project in the list of the current user. Works the same way as the position of projects.
+	Position float64 `xorm:"double not null" json:"position"`
+
+	// A timestamp when this position was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this position was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name for project user positions
+func (ProjectUserPosition) TableName() string {
+	return "project_user_positions"
+}
+
+// getProjectUserPositionOrderColumn returns an sql expression which resolves to the position the user set for a top-level project
+// or the position of the project itself if the user did not set one.
+func getProjectUserPositionOrderColumn(table string, userID int64) string {
+	return fmt.Sprintf("COALESCE((SELECT project_user_positions.position FROM project_user_positions WHERE project_user_positions.project_id = %[1]s.id AND project_user_positions.user_id = %[2]d AND project_user_positions.kind = '%[3]s'), %[1]s.position)", table, userID, ProjectUserPositionKindRoot)
+}
+
+// addUserPositionsToProjects sets the positions the user has set for the projects
+func addUserPositionsToProjects(s *xorm.Session, projectIDs []int64, projects []*Project, a web.Auth) (err error) {
+	if _, isShare := a.(*LinkSharing); isShare || a == nil {
+		return nil
+	}
+
+	positions := []*ProjectUserPosition{}
+	err = s.
+		Where("user_id = ?", a.GetID()).
+		In("project_id", projectIDs).
+		Find(&positions)
+	if err != nil {
+		return err
+	}
+
+	if len(positions) == 0 {
+		return nil
+	}
+
+	projectMap := make(map[int64]*Project, len(projects))
+	for _, p := range projects {
+		projectMap[p.ID] = p
+	}
+
+	for _, pos := range positions {
+		p, has := projectMap[pos.ProjectID]
+		if !has {
+			continue
+		}
+		switch pos.Kind {
+		case ProjectUserPositionKindRoot:
+			p.UserPosition = pos.Position
+		case ProjectUserPositionKindFavorites:
+			p.FavoritePosition = pos.Position
+		}
+	}
+
+	return nil
+}
+
+func (pp *ProjectUserPosition) validate() error {
+	if pp.Kind != ProjectUserPositionKindRoot && pp.Kind != ProjectUserPositionKindFavorites {
+		return &ErrInvalidProjectUserPositionKind{Kind: pp.Kind}
+	}
+	return nil
+}
+
+// CanUpdate checks if the user can set their own position of a project
+func (pp *ProjectUserPosition) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, isShare := a.(*LinkSharing); isShare {
+		return false, nil
+	}
+
+	p := &Project{ID: pp.ProjectID}
+	can, _, err := p.CanRead(s, a)
+	return can, err
+}
+
+// CanDelete checks if the user can reset their own position of a project
+func (pp *ProjectUserPosition) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return pp.CanUpdate(s, a)
+}
+
+// Update sets the position of a project in the top-level or favorite projects of the current user
+// @Summary Set the position of a project for the current user
+// @Description Sets the position of a project in the top-level (`root`) or favorite (`favorites`) projects of the current user without changing the order other users see. Top-level projects are returned sorted by these positions, the positions are returned as `user_position` and `favorite_position` with every project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param kind path string true "The list of projects the position is used in. Can be `root` or `favorites`."
+// @Param position body models.ProjectUserPosition true "The position of the project."
+// @Success 200 {object} models.ProjectUserPosition "The position has been saved."
+// @Failure 400 {object} web.HTTPError "The kind is invalid."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The project does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/position/{kind} [post]
+func (pp *ProjectUserPosition) Update(s *xorm.Session, a web.Auth) (err error) {
+	err = pp.validate()
+	if err != nil {
+		return err
+	}
+
+	pp.UserID = a.GetID()
+
+	existing := &ProjectUserPosition{}
+	exists, err := s.
+		Where("project_id = ? AND user_id = ? AND kind = ?", pp.ProjectID, pp.UserID, pp.Kind).
+		Get(existing)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		_, err = s.Insert(pp)
+		return err
+	}
+
+	pp.ID = existing.ID
+	_, err = s.
+		ID(pp.ID).
+		Cols("position").
+		Update(pp)
+	if err != nil {
+		return err
+	}
+
+	pp.Created = existing.Created
+	return nil
+}
+
+// Delete resets the position of a project for the current user to the position everyone else sees
+// @Summary Reset the position of a project for the current user
+// @Description Removes the position the current user has set for a project in their top-level (`root`) or favorite (`favorites`) projects so that the project is sorted by its own position again.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param kind path string true "The list of projects the position is used in. Can be `root` or `favorites`."
+// @Success 200 {object} models.Message "The position has been reset."
+// @Failure 400 {object} web.HTTPError "The kind is invalid."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The project does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/position/{kind} [delete]
+func (pp *ProjectUserPosition) Delete(s *xorm.Session, a web.Auth) (err error) {
+	err = pp.validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("project_id = ? AND user_id = ? AND kind = ?", pp.ProjectID, a.GetID(), pp.Kind).
+		Delete(&ProjectUserPosition{})
+	return err
+}
@@ -0,0 +1,149 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectUserPosition_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("new position", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pp := &ProjectUserPosition{ProjectID: 6, Kind: ProjectUserPositionKindRoot, Position: 0.5}
+		can, err := pp.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pp.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_user_positions", map[string]interface{}{
+			"project_id": 6,
+			"user_id":    1,
+			"kind":       ProjectUserPositionKindRoot,
+			"position":   0.5,
+		}, false)
+
+		// The project is now sorted before the project with the lowest position
+		projects, _, _, err := (&Project{}).ReadAll(s, u, "", 1, 50)
+		require.NoError(t, err)
+		ls := projects.([]*Project)
+		assert.Equal(t, int64(6), ls[0].ID)
+		assert.InDelta(t, 0.5, ls[0].UserPosition, 0)
+		assert.Equal(t, int64(3), ls[1].ID)
+	})
+	t.Run("existing position", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pp := &ProjectUserPosition{ProjectID: 1, Kind: ProjectUserPositionKindFavorites, Position: 42}
+		err := pp.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), pp.ID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_user_positions", map[string]interface{}{
+			"id":         2,
+			"project_id": 1,
+			"user_id":    1,
+			"kind":       ProjectUserPositionKindFavorites,
+			"position":   42,
+		}, false)
+	})
+	t.Run("invalid kind", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pp := &ProjectUserPosition{ProjectID: 1, Kind: "sidebar", Position: 42}
+		err := pp.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidProjectUserPositionKind(err))
+	})
+	t.Run("no access to the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pp := &ProjectUserPosition{ProjectID: 2, Kind: ProjectUserPositionKindRoot, Position: 42}
+		can, err := pp.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pp := &ProjectUserPosition{ProjectID: 1, Kind: ProjectUserPositionKindRoot, Position: 42}
+		can, err := pp.CanUpdate(s, &LinkSharing{ID: 1, ProjectID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestProjectUserPosition_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	u := &user.User{ID: 1}
+	pp := &ProjectUserPosition{ProjectID: 1, Kind: ProjectUserPositionKindFavorites}
+	err := pp.Delete(s, u)
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "project_user_positions", map[string]interface{}{
+		"id": 2,
+	})
+}
+
+func TestProject_ReadAll_UserPositions(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	projects, _, _, err := (&Project{}).ReadAll(s, &user.User{ID: 1}, "", 1, 50)
+	require.NoError(t, err)
+	for _, p := range projects.([]*Project) {
+		if p.ID == 1 {
+			assert.InDelta(t, 20, p.FavoritePosition, 0)
+			assert.InDelta(t, 0, p.UserPosition, 0)
+		}
+	}
+
+	projects, _, _, err = (&Project{}).ReadAll(s, &user.User{ID: 6}, "", 1, 50)
+	require.NoError(t, err)
+	ls := projects.([]*Project)
+	assert.Equal(t, int64(7), ls[0].ID)
+	assert.InDelta(t, 0.5, ls[0].UserPosition, 0)
+}
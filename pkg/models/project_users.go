@@ -40,6 +40,8 @@ type ProjectUser struct {
 	ProjectID int64 `xorm:"bigint not null INDEX" json:"-" param:"project"`
 	// The right this user has. 0 = Read only, 1 = Read & Write, 2 = Admin. See the docs for more details.
 	Right Right `xorm:"bigint INDEX not null default 0" json:"right" valid:"length(0|2)" maximum:"2" default:"0"`
+	// The id of a role of the project which gives additional permissions on top of the right. 0 means no role.
+	RoleID int64 `xorm:"bigint null default 0" json:"role_id"`
 
 	// A timestamp when this relation was created. You cannot change this value.
 	Created time.Time `xorm:"created not null" json:"created"`
@@ -59,6 +61,7 @@ func (ProjectUser) TableName() string {
 type UserWithRight struct {
 	user.User `xorm:"extends"`
 	Right     Right `json:"right"`
+	RoleID    int64 `json:"role_id"`
 }
 
 // Create creates a new project <-> user relation
@@ -89,6 +92,10 @@ func (lu *ProjectUser) Create(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	if err = validateShareRole(s, lu.RoleID, lu.ProjectID); err != nil {
+		return err
+	}
+
 	// Check if the user exists
 	u, err := user.GetUserByUsername(s, lu.Username)
 	if err != nil {
@@ -116,7 +123,7 @@ func (lu *ProjectUser) Create(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
-	err = events.Dispatch(&ProjectSharedWithUserEvent{
+	err = events.DispatchOnCommit(s, &ProjectSharedWithUserEvent{
 		Project: l,
 		User:    u,
 		Doer:    a,
@@ -251,6 +258,10 @@ func (lu *ProjectUser) Update(s *xorm.Session, _ web.Auth) (err error) {
 		return err
 	}
 
+	if err := validateShareRole(s, lu.RoleID, lu.ProjectID); err != nil {
+		return err
+	}
+
 	// Check if the user exists
 	u, err := user.GetUserByUsername(s, lu.Username)
 	if err != nil {
@@ -260,7 +271,7 @@ func (lu *ProjectUser) Update(s *xorm.Session, _ web.Auth) (err error) {
 
 	_, err = s.
 		Where("project_id = ? AND user_id = ?", lu.ProjectID, lu.UserID).
-		Cols("right").
+		Cols("right", "role_id").
 		Update(lu)
 	if err != nil {
 		return err
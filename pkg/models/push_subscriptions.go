@@ -0,0 +1,143 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"net/url"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// PushSubscription is a wrapper around the crud operations of a Web Push subscription of a browser or device.
+type PushSubscription struct {
+	notifications.PushSubscription
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+func checkWebPushAllowed(a web.Auth) error {
+	if !config.WebPushEnabled.GetBool() {
+		return &ErrWebPushDisabled{}
+	}
+	if _, is := a.(*LinkSharing); is {
+		return ErrGenericForbidden{}
+	}
+	return nil
+}
+
+// CanCreate checks if the user can register a push subscription
+func (p *PushSubscription) CanCreate(_ *xorm.Session, a web.Auth) (bool, error) {
+	if err := checkWebPushAllowed(a); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CanDelete checks if the user can remove a push subscription. Users can only remove their own ones.
+func (p *PushSubscription) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	exists, err := s.
+		Where("id = ? AND notifiable_id = ?", p.ID, a.GetID()).
+		Exist(&notifications.PushSubscription{})
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, &ErrPushSubscriptionDoesNotExist{PushSubscriptionID: p.ID}
+	}
+	return true, nil
+}
+
+// Create registers a push subscription
+// @Summary Register a push subscription
+// @Description Registers a browser or device to get notifications as Web Push messages. Send the result of `PushSubscription.toJSON()` of the browser, optionally with a name of the device. Registering the same endpoint again updates the existing subscription. Use the `web_push_public_key` from `/info` as `applicationServerKey` when subscribing in the browser.
+// @tags subscriptions
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param subscription body models.PushSubscription true "The push subscription"
+// @Success 201 {object} models.PushSubscription "The push subscription."
+// @Failure 400 {object} web.HTTPError "The push subscription is invalid."
+// @Failure 403 {object} web.HTTPError "Link shares cannot have push subscriptions."
+// @Failure 412 {object} web.HTTPError "Web Push is disabled."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /notifications/push [put]
+func (p *PushSubscription) Create(s *xorm.Session, a web.Auth) (err error) {
+	endpoint, err := url.Parse(p.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" || !notifications.ValidatePushSubscriptionKeys(p.Keys) {
+		return &ErrInvalidPushSubscription{Endpoint: p.Endpoint}
+	}
+
+	p.ID = 0
+	p.NotifiableID = a.GetID()
+	err = notifications.SavePushSubscription(s, &p.PushSubscription)
+	p.Keys = nil
+	return
+}
+
+// ReadAll returns all push subscriptions of the current user
+// @Summary Get all push subscriptions
+// @Description Returns all browsers and devices the current user registered for Web Push messages.
+// @tags subscriptions
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {array} models.PushSubscription "The push subscriptions."
+// @Failure 403 {object} web.HTTPError "Link shares cannot have push subscriptions."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /notifications/push [get]
+func (p *PushSubscription) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	if _, is := a.(*LinkSharing); is {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	subscriptions, err := notifications.GetPushSubscriptionsForNotifiable(s, a.GetID())
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	for _, subscription := range subscriptions {
+		subscription.Keys = nil
+	}
+
+	return subscriptions, len(subscriptions), int64(len(subscriptions)), nil
+}
+
+// Delete removes a push subscription
+// @Summary Remove a push subscription
+// @Description Removes a push subscription, the browser or device will not get any Web Push messages anymore.
+// @tags subscriptions
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Push subscription ID"
+// @Success 200 {object} models.Message "The push subscription was removed."
+// @Failure 403 {object} web.HTTPError "Link shares cannot have push subscriptions."
+// @Failure 404 {object} web.HTTPError "The push subscription does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /notifications/push/{id} [delete]
+func (p *PushSubscription) Delete(s *xorm.Session, a web.Auth) (err error) {
+	_, err = notifications.DeletePushSubscription(s, p.ID, a.GetID())
+	return
+}
@@ -0,0 +1,123 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushSubscription(t *testing.T) {
+	config.WebPushEnabled.Set(true)
+	defer config.WebPushEnabled.Set(false)
+
+	browserKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys := &notifications.PushSubscriptionKeys{
+		P256dh: base64.RawURLEncoding.EncodeToString(browserKey.PublicKey().Bytes()),
+		Auth:   base64.RawURLEncoding.EncodeToString([]byte("0123456789abcdef")),
+	}
+	u := &user.User{ID: 1}
+
+	t.Run("create, read and delete", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &PushSubscription{PushSubscription: notifications.PushSubscription{
+			Endpoint: "https://push.example.com/abc",
+			Keys:     keys,
+			Name:     "Firefox",
+		}}
+		can, err := p.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = p.Create(s, u)
+		require.NoError(t, err)
+		assert.NotZero(t, p.ID)
+		assert.Nil(t, p.Keys)
+
+		all, _, total, err := (&PushSubscription{}).ReadAll(s, u, "", 1, 50)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		subscriptions := all.([]*notifications.PushSubscription)
+		assert.Equal(t, "Firefox", subscriptions[0].Name)
+		assert.Nil(t, subscriptions[0].Keys)
+
+		toDelete := &PushSubscription{PushSubscription: notifications.PushSubscription{ID: p.ID}}
+		_, err = toDelete.CanDelete(s, &user.User{ID: 2})
+		require.Error(t, err)
+		assert.True(t, IsErrPushSubscriptionDoesNotExist(err))
+
+		can, err = toDelete.CanDelete(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = toDelete.Delete(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertMissing(t, "push_subscriptions", map[string]interface{}{
+			"id": p.ID,
+		})
+	})
+	t.Run("invalid endpoint", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &PushSubscription{PushSubscription: notifications.PushSubscription{
+			Endpoint: "http://push.example.com/abc",
+			Keys:     keys,
+		}}
+		err := p.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidPushSubscription(err))
+	})
+	t.Run("invalid keys", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &PushSubscription{PushSubscription: notifications.PushSubscription{
+			Endpoint: "https://push.example.com/abc",
+			Keys:     &notifications.PushSubscriptionKeys{P256dh: "nope", Auth: keys.Auth},
+		}}
+		err := p.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidPushSubscription(err))
+	})
+	t.Run("disabled", func(t *testing.T) {
+		config.WebPushEnabled.Set(false)
+		defer config.WebPushEnabled.Set(true)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := (&PushSubscription{}).CanCreate(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrWebPushDisabled(err))
+	})
+}
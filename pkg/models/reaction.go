@@ -23,6 +23,7 @@ import (
 	"xorm.io/builder"
 	"xorm.io/xorm"
 
+	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/user"
 )
 
@@ -154,9 +155,23 @@ func getReactionsForEntityIDs(s *xorm.Session, entityKind ReactionKind, entityID
 func (r *Reaction) Delete(s *xorm.Session, a web.Auth) (err error) {
 	r.UserID = a.GetID()
 
-	_, err = s.Where("user_id = ? AND entity_id = ? AND entity_kind = ? AND value = ?", r.UserID, r.EntityID, r.EntityKind, r.Value).
+	deleted, err := s.Where("user_id = ? AND entity_id = ? AND entity_kind = ? AND value = ?", r.UserID, r.EntityID, r.EntityKind, r.Value).
 		Delete(&Reaction{})
-	return
+	if err != nil || deleted == 0 {
+		return err
+	}
+
+	task, comment, doer, err := r.getEventEntities(s, a)
+	if err != nil {
+		return err
+	}
+
+	return events.DispatchOnCommit(s, &TaskReactionDeletedEvent{
+		Task:     task,
+		Comment:  comment,
+		Reaction: r,
+		Doer:     doer,
+	})
 }
 
 // Create adds a new reaction to an entity
@@ -187,5 +202,55 @@ func (r *Reaction) Create(s *xorm.Session, a web.Auth) (err error) {
 	}
 
 	_, err = s.Insert(r)
-	return
+	if err != nil {
+		return err
+	}
+
+	task, comment, doer, err := r.getEventEntities(s, a)
+	if err != nil {
+		return err
+	}
+	r.User = doer
+
+	return events.DispatchOnCommit(s, &TaskReactionCreatedEvent{
+		Task:     task,
+		Comment:  comment,
+		Reaction: r,
+		Doer:     doer,
+	})
+}
+
+// getEventEntities returns the task and comment a reaction belongs to and the user who reacted
+func (r *Reaction) getEventEntities(s *xorm.Session, a web.Auth) (task *Task, comment *TaskComment, doer *user.User, err error) {
+	taskID := r.EntityID
+	if r.EntityKind == ReactionKindComment {
+		comment = &TaskComment{ID: r.EntityID}
+		err = getTaskCommentSimple(s, comment)
+		if err != nil {
+			return
+		}
+		taskID = comment.TaskID
+	}
+
+	t, err := GetTaskByIDSimple(s, taskID)
+	if err != nil {
+		return
+	}
+
+	doer, err = GetUserOrLinkShareUser(s, a)
+	return &t, comment, doer, err
+}
+
+func deleteReactionsForEntities(s *xorm.Session, entityKind ReactionKind, entityIDs []int64) error {
+	if len(entityIDs) == 0 {
+		return nil
+	}
+
+	_, err := s.
+		Where(builder.And(
+			builder.Eq{"entity_kind": entityKind},
+			builder.In("entity_id", entityIDs),
+		)).
+		Delete(&Reaction{})
+	return err
 }
@@ -20,6 +20,8 @@ import (
 	"testing"
 
 	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/notifications"
 	"code.vikunja.io/api/pkg/user"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -152,6 +154,88 @@ func TestReaction_Create(t *testing.T) {
 			"user_id":     u.ID,
 			"value":       r.Value,
 		}, false)
+		events.AssertDispatched(t, &TaskReactionCreatedEvent{})
+	})
+	t.Run("on a comment", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1}
+		r := &Reaction{
+			EntityID:         1,
+			EntityKindString: "comments",
+			Value:            "🦙",
+		}
+
+		can, err := r.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		err = r.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), r.User.ID)
+
+		task, comment, _, err := r.getEventEntities(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), task.ID)
+		require.NotNil(t, comment)
+		assert.Equal(t, int64(1), comment.ID)
+
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "reactions", map[string]interface{}{
+			"entity_id":   1,
+			"entity_kind": ReactionKindComment,
+			"user_id":     u.ID,
+			"value":       r.Value,
+		}, false)
+	})
+	t.Run("should notify the comment author", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		comment := &TaskComment{ID: 1}
+		err = getTaskCommentSimple(s, comment)
+		require.NoError(t, err)
+
+		ev := &TaskReactionCreatedEvent{
+			Task:     &task,
+			Comment:  comment,
+			Reaction: &Reaction{Value: "🦙"},
+			Doer:     &user.User{ID: 2, Username: "user2"},
+		}
+
+		events.TestListener(t, ev, &SendTaskReactionNotification{})
+		db.AssertExists(t, "notifications", map[string]interface{}{
+			"notifiable_id": 1,
+			"name":          (&TaskReactionNotification{}).Name(),
+		}, false)
+	})
+	t.Run("should not notify when reacting to own task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+
+		ev := &TaskReactionCreatedEvent{
+			Task:     &task,
+			Reaction: &Reaction{Value: "🦙"},
+			Doer:     &user.User{ID: 1},
+		}
+
+		before, err := s.Where("name = ?", (&TaskReactionNotification{}).Name()).Count(&notifications.DatabaseNotification{})
+		require.NoError(t, err)
+		events.TestListener(t, ev, &SendTaskReactionNotification{})
+		after, err := s.Where("name = ?", (&TaskReactionNotification{}).Name()).Count(&notifications.DatabaseNotification{})
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
 	})
 	t.Run("no permission to access task", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
@@ -213,5 +297,22 @@ func TestReaction_Delete(t *testing.T) {
 			"entity_kind": ReactionKindTask,
 			"value":       "👋",
 		})
+		events.AssertDispatched(t, &TaskReactionDeletedEvent{})
+	})
+	t.Run("with the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1}
+		err := task.Delete(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "reactions", map[string]interface{}{
+			"entity_id":   1,
+			"entity_kind": ReactionKindTask,
+		})
 	})
 }
@@ -0,0 +1,230 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+
+	"xorm.io/xorm"
+)
+
+// SavedFilterTaskMatch holds a task which matched a saved filter the last time it was evaluated for a subscription.
+// The filter is evaluated for each subscriber separately because subscribers may have access to different tasks.
+type SavedFilterTaskMatch struct {
+	ID             int64 `xorm:"autoincr not null unique pk"`
+	SubscriptionID int64 `xorm:"bigint not null INDEX"`
+	TaskID         int64 `xorm:"bigint not null INDEX"`
+}
+
+// TableName returns the table name for saved filter task matches
+func (*SavedFilterTaskMatch) TableName() string {
+	return "saved_filter_task_matches"
+}
+
+func getTasksMatchingSavedFilter(s *xorm.Session, sf *SavedFilter, u *user.User) (tasks []*Task, err error) {
+	tc := &TaskCollection{ProjectID: getProjectIDFromSavedFilterID(sf.ID)}
+	result, _, _, err := tc.ReadAll(s, u, "", -1, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, _ = result.([]*Task)
+	return tasks, nil
+}
+
+// updateSavedFilterTaskMatches evaluates the saved filter of a subscription for its subscriber and stores which tasks
+// matched. It returns all tasks which started or stopped matching since the last evaluation.
+func (sb *Subscription) updateSavedFilterTaskMatches(s *xorm.Session) (matched, unmatched []*Task, err error) {
+	u, err := user.GetUserByID(s, sb.UserID)
+	if err != nil {
+		if user.IsErrUserDoesNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	sf, err := getSavedFilterSimpleByID(s, sb.EntityID)
+	if err != nil {
+		if IsErrSavedFilterDoesNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	// The subscriber may have lost access to the filter since subscribing
+	can, _, err := sf.CanRead(s, u)
+	if err != nil || !can {
+		return nil, nil, err
+	}
+
+	tasks, err := getTasksMatchingSavedFilter(s, sf, u)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previousMatches := []*SavedFilterTaskMatch{}
+	err = s.Where("subscription_id = ?", sb.ID).Find(&previousMatches)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previousTaskIDs := make(map[int64]bool, len(previousMatches))
+	for _, m := range previousMatches {
+		previousTaskIDs[m.TaskID] = true
+	}
+
+	currentTaskIDs := make(map[int64]bool, len(tasks))
+	newMatches := []*SavedFilterTaskMatch{}
+	for _, t := range tasks {
+		currentTaskIDs[t.ID] = true
+		if previousTaskIDs[t.ID] {
+			continue
+		}
+		matched = append(matched, t)
+		newMatches = append(newMatches, &SavedFilterTaskMatch{SubscriptionID: sb.ID, TaskID: t.ID})
+	}
+
+	if len(newMatches) > 0 {
+		_, err = s.Insert(&newMatches)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	unmatchedTaskIDs := []int64{}
+	for _, m := range previousMatches {
+		if currentTaskIDs[m.TaskID] {
+			continue
+		}
+		unmatchedTaskIDs = append(unmatchedTaskIDs, m.TaskID)
+	}
+
+	if len(unmatchedTaskIDs) == 0 {
+		return matched, nil, nil
+	}
+
+	_, err = s.
+		Where("subscription_id = ?", sb.ID).
+		In("task_id", unmatchedTaskIDs).
+		Delete(&SavedFilterTaskMatch{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Deleted tasks don't exist anymore and are therefore not returned here
+	unmatchedTasks, err := GetTasksSimpleByIDs(s, unmatchedTaskIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(unmatchedTasks, func(i, j int) bool {
+		return unmatchedTasks[i].ID < unmatchedTasks[j].ID
+	})
+
+	return matched, unmatchedTasks, nil
+}
+
+// evaluateSavedFilterSubscriptions checks all saved filter subscriptions for tasks which started or stopped
+// matching the filter and dispatches an event for each of them.
+func evaluateSavedFilterSubscriptions(s *xorm.Session) (changes int, err error) {
+	subscriptions := []*Subscription{}
+	err = s.
+		Where("entity_type = ?", SubscriptionEntitySavedFilter).
+		OrderBy("id asc").
+		Find(&subscriptions)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sb := range subscriptions {
+		matched, unmatched, err := sb.updateSavedFilterTaskMatches(s)
+		if err != nil {
+			return changes, err
+		}
+
+		if len(matched) == 0 && len(unmatched) == 0 {
+			continue
+		}
+
+		sf, err := getSavedFilterSimpleByID(s, sb.EntityID)
+		if err != nil {
+			return changes, err
+		}
+		subscriber := &user.User{ID: sb.UserID}
+
+		for _, t := range matched {
+			err = events.DispatchOnCommit(s, &SavedFilterTaskMatchedEvent{
+				Task:       t,
+				Filter:     sf,
+				Subscriber: subscriber,
+			})
+			if err != nil {
+				return changes, err
+			}
+			changes++
+		}
+
+		for _, t := range unmatched {
+			err = events.DispatchOnCommit(s, &SavedFilterTaskUnmatchedEvent{
+				Task:       t,
+				Filter:     sf,
+				Subscriber: subscriber,
+			})
+			if err != nil {
+				return changes, err
+			}
+			changes++
+		}
+	}
+
+	return changes, nil
+}
+
+// RegisterSavedFilterSubscriptionCron registers a function which periodically checks subscribed saved filters for
+// tasks which started or stopped matching them.
+func RegisterSavedFilterSubscriptionCron() {
+	const logPrefix = "[Saved Filter Subscription Cron] "
+
+	err := cron.Schedule("*/5 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		changes, err := evaluateSavedFilterSubscriptions(s)
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not evaluate saved filter subscriptions: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit saved filter task matches: %s", err)
+			return
+		}
+
+		if changes > 0 {
+			log.Debugf(logPrefix+"Found %d tasks which started or stopped matching subscribed saved filters", changes)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register saved filter subscription cron: %s", err)
+	}
+}
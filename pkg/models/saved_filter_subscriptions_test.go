@@ -0,0 +1,219 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getTaskIDs(tasks []*Task) []int64 {
+	ids := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestSubscription_SavedFilter(t *testing.T) {
+	t.Run("subscribe", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 4}
+		sb := &Subscription{
+			Entity:   "filter",
+			EntityID: 1,
+		}
+		can, err := sb.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		err = sb.Create(s, u)
+		require.NoError(t, err)
+
+		db.AssertExists(t, "subscriptions", map[string]interface{}{
+			"entity_type": SubscriptionEntitySavedFilter,
+			"entity_id":   1,
+			"user_id":     u.ID,
+		}, false)
+	})
+	t.Run("subscribe without access to the filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sb := &Subscription{
+			Entity:   "filter",
+			EntityID: 1,
+		}
+		can, err := sb.CanCreate(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("subscribing remembers the current matches", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1}
+		sb := &Subscription{Entity: "filter", EntityType: SubscriptionEntitySavedFilter, EntityID: 1}
+		err := sb.Delete(s, u)
+		require.NoError(t, err)
+		db.AssertMissing(t, "saved_filter_task_matches", map[string]interface{}{
+			"subscription_id": 9,
+		})
+
+		sb = &Subscription{Entity: "filter", EntityType: SubscriptionEntitySavedFilter, EntityID: 1}
+		err = sb.Create(s, u)
+		require.NoError(t, err)
+
+		for _, taskID := range []int64{5, 6, 7, 8, 9} {
+			db.AssertExists(t, "saved_filter_task_matches", map[string]interface{}{
+				"subscription_id": sb.ID,
+				"task_id":         taskID,
+			}, false)
+		}
+
+		matched, unmatched, err := sb.updateSavedFilterTaskMatches(s)
+		require.NoError(t, err)
+		assert.Empty(t, matched)
+		assert.Empty(t, unmatched)
+	})
+	t.Run("read one filter with subscription", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sf := &SavedFilter{ID: 1}
+		_, _, err := sf.CanRead(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = sf.ReadOne(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		require.NotNil(t, sf.Subscription)
+		assert.Equal(t, int64(9), sf.Subscription.ID)
+	})
+}
+
+func TestSubscription_updateSavedFilterTaskMatches(t *testing.T) {
+	t.Run("new and removed matches", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sb := &Subscription{ID: 9, EntityType: SubscriptionEntitySavedFilter, EntityID: 1, UserID: 1}
+		matched, unmatched, err := sb.updateSavedFilterTaskMatches(s)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{7, 8, 9}, getTaskIDs(matched))
+		assert.Equal(t, []int64{1}, getTaskIDs(unmatched))
+
+		db.AssertMissing(t, "saved_filter_task_matches", map[string]interface{}{
+			"subscription_id": 9,
+			"task_id":         1,
+		})
+		db.AssertExists(t, "saved_filter_task_matches", map[string]interface{}{
+			"subscription_id": 9,
+			"task_id":         7,
+		}, false)
+
+		// Evaluating again does not report the same changes twice
+		matched, unmatched, err = sb.updateSavedFilterTaskMatches(s)
+		require.NoError(t, err)
+		assert.Empty(t, matched)
+		assert.Empty(t, unmatched)
+	})
+	t.Run("task stops matching", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sb := &Subscription{ID: 9, EntityType: SubscriptionEntitySavedFilter, EntityID: 1, UserID: 1}
+		_, _, err := sb.updateSavedFilterTaskMatches(s)
+		require.NoError(t, err)
+
+		_, err = s.Where("id = ?", 7).Cols("start_date", "end_date", "due_date").NoAutoCondition().Update(&Task{})
+		require.NoError(t, err)
+
+		matched, unmatched, err := sb.updateSavedFilterTaskMatches(s)
+		require.NoError(t, err)
+		assert.Empty(t, matched)
+		assert.Equal(t, []int64{7}, getTaskIDs(unmatched))
+	})
+	t.Run("deleted tasks are not reported", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&SavedFilterTaskMatch{SubscriptionID: 9, TaskID: 99999})
+		require.NoError(t, err)
+
+		sb := &Subscription{ID: 9, EntityType: SubscriptionEntitySavedFilter, EntityID: 1, UserID: 1}
+		_, unmatched, err := sb.updateSavedFilterTaskMatches(s)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, getTaskIDs(unmatched))
+		db.AssertMissing(t, "saved_filter_task_matches", map[string]interface{}{
+			"task_id": 99999,
+		})
+	})
+	t.Run("subscriber without access anymore", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sb := &Subscription{ID: 9, EntityType: SubscriptionEntitySavedFilter, EntityID: 1, UserID: 2}
+		matched, unmatched, err := sb.updateSavedFilterTaskMatches(s)
+		require.NoError(t, err)
+		assert.Empty(t, matched)
+		assert.Empty(t, unmatched)
+	})
+}
+
+func TestEvaluateSavedFilterSubscriptions(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	changes, err := evaluateSavedFilterSubscriptions(s)
+	require.NoError(t, err)
+	assert.Equal(t, 4, changes)
+	events.AssertDispatched(t, &SavedFilterTaskMatchedEvent{})
+	events.AssertDispatched(t, &SavedFilterTaskUnmatchedEvent{})
+}
+
+func TestSavedFilter_Delete_Subscriptions(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	sf := &SavedFilter{ID: 1}
+	err := sf.Delete(s, &user.User{ID: 1})
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "subscriptions", map[string]interface{}{
+		"id": 9,
+	})
+	db.AssertMissing(t, "saved_filter_task_matches", map[string]interface{}{
+		"subscription_id": 9,
+	})
+}
@@ -0,0 +1,220 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// SavedFilterTeam represents a saved filter <-> team relation
+type SavedFilterTeam struct {
+	// The unique, numeric id of this saved filter <-> team relation.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The team id.
+	TeamID int64 `xorm:"bigint not null INDEX" json:"team_id" param:"team"`
+	// The saved filter id.
+	FilterID int64 `xorm:"bigint not null INDEX" json:"-" param:"filter"`
+	// The right this team has. 0 = Read only, 1 = Read & Write, 2 = Admin. Admins can share the filter with others.
+	Right Right `xorm:"bigint INDEX not null default 0" json:"right" valid:"length(0|2)" maximum:"2" default:"0"`
+
+	// A timestamp when this relation was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this relation was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName is the table name for SavedFilterTeam
+func (SavedFilterTeam) TableName() string {
+	return "saved_filter_teams"
+}
+
+// Create shares a saved filter with a team
+// @Summary Share a saved filter with a team
+// @Description Gives all members of a team access to a saved filter.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param filter path int true "Filter ID"
+// @Param share body models.SavedFilterTeam true "The team you want to share the filter with."
+// @Success 201 {object} models.SavedFilterTeam "The created team <-> saved filter relation."
+// @Failure 400 {object} web.HTTPError "Invalid team saved filter object provided."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the saved filter."
+// @Failure 404 {object} web.HTTPError "The team does not exist."
+// @Failure 409 {object} web.HTTPError "The team already has access to the saved filter."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/teams [put]
+func (ft *SavedFilterTeam) Create(s *xorm.Session, _ web.Auth) (err error) {
+	if err = ft.Right.isValid(); err != nil {
+		return err
+	}
+
+	_, err = getSavedFilterSimpleByID(s, ft.FilterID)
+	if err != nil {
+		return err
+	}
+
+	_, err = GetTeamByID(s, ft.TeamID)
+	if err != nil {
+		return err
+	}
+
+	exists, err := s.
+		Where("filter_id = ? AND team_id = ?", ft.FilterID, ft.TeamID).
+		Exist(&SavedFilterTeam{})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrTeamAlreadyHasAccessToSavedFilter{TeamID: ft.TeamID, SavedFilterID: ft.FilterID}
+	}
+
+	_, err = s.Insert(ft)
+	return err
+}
+
+// Delete removes a team from a saved filter
+// @Summary Remove a team from a saved filter
+// @Description Removes a team from a saved filter. The members of the team won't have access to the saved filter through it anymore.
+// @tags sharing
+// @Produce json
+// @Security JWTKeyAuth
+// @Param filter path int true "Filter ID"
+// @Param team path int true "Team ID"
+// @Success 200 {object} models.Message "The team was successfully removed from the saved filter."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the saved filter."
+// @Failure 404 {object} web.HTTPError "The team or the share does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/teams/{team} [delete]
+func (ft *SavedFilterTeam) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	deleted, err := s.
+		Where("filter_id = ? AND team_id = ?", ft.FilterID, ft.TeamID).
+		Delete(&SavedFilterTeam{})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrTeamDoesNotHaveAccessToSavedFilter{TeamID: ft.TeamID, SavedFilterID: ft.FilterID}
+	}
+
+	return nil
+}
+
+// ReadAll gets all teams a saved filter is shared with
+// @Summary Get the teams a saved filter is shared with
+// @Description Returns all teams which have access to a saved filter.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Param filter path int true "Filter ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param s query string false "Search teams by its name."
+// @Security JWTKeyAuth
+// @Success 200 {array} models.TeamWithRight "The teams with their right."
+// @Failure 403 {object} web.HTTPError "No right to see the saved filter."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/teams [get]
+func (ft *SavedFilterTeam) ReadAll(s *xorm.Session, a web.Auth, search string, page int, perPage int) (result interface{}, resultCount int, totalItems int64, err error) {
+	sf := &SavedFilter{ID: ft.FilterID}
+	can, _, err := sf.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+
+	all := []*TeamWithRight{}
+	query := s.
+		Table("teams").
+		Join("INNER", "saved_filter_teams", "saved_filter_teams.team_id = teams.id").
+		Where("saved_filter_teams.filter_id = ?", ft.FilterID).
+		Where(db.ILIKE("teams.name", search))
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&all)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	teams := []*Team{}
+	for i := range all {
+		teams = append(teams, &all[i].Team)
+	}
+
+	err = addMoreInfoToTeams(s, teams)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalItems, err = s.
+		Table("teams").
+		Join("INNER", "saved_filter_teams", "saved_filter_teams.team_id = teams.id").
+		Where("saved_filter_teams.filter_id = ?", ft.FilterID).
+		Where(db.ILIKE("teams.name", search)).
+		Count(&TeamWithRight{})
+
+	return all, len(all), totalItems, err
+}
+
+// Update changes the right of a team on a saved filter
+// @Summary Update a team <-> saved filter relation
+// @Description Updates the right a team has on a saved filter.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Param filter path int true "Filter ID"
+// @Param team path int true "Team ID"
+// @Param share body models.SavedFilterTeam true "The team you want to update."
+// @Security JWTKeyAuth
+// @Success 200 {object} models.SavedFilterTeam "The updated team <-> saved filter relation."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the saved filter."
+// @Failure 404 {object} web.HTTPError "The team or the share does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/teams/{team} [post]
+func (ft *SavedFilterTeam) Update(s *xorm.Session, _ web.Auth) (err error) {
+	if err = ft.Right.isValid(); err != nil {
+		return err
+	}
+
+	exists, err := s.
+		Where("filter_id = ? AND team_id = ?", ft.FilterID, ft.TeamID).
+		Exist(&SavedFilterTeam{})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTeamDoesNotHaveAccessToSavedFilter{TeamID: ft.TeamID, SavedFilterID: ft.FilterID}
+	}
+
+	_, err = s.
+		Where("filter_id = ? AND team_id = ?", ft.FilterID, ft.TeamID).
+		Cols("right").
+		Update(ft)
+	return err
+}
@@ -0,0 +1,151 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedFilterTeam_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ft := &SavedFilterTeam{FilterID: 1, TeamID: 9}
+		can, err := ft.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = ft.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "saved_filter_teams", map[string]interface{}{
+			"filter_id": 1,
+			"team_id":   9,
+			"right":     RightRead,
+		}, false)
+
+		// User 2 is a member of team 9
+		can, _, err = (&SavedFilter{ID: 1}).CanRead(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("already shared", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ft := &SavedFilterTeam{FilterID: 1, TeamID: 11}
+		err := ft.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTeamAlreadyHasAccessToSavedFilter(err))
+	})
+	t.Run("nonexisting team", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ft := &SavedFilterTeam{FilterID: 1, TeamID: 9999}
+		err := ft.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTeamDoesNotExist(err))
+	})
+	t.Run("nonexisting filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ft := &SavedFilterTeam{FilterID: 9999, TeamID: 9}
+		err := ft.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrSavedFilterDoesNotExist(err))
+	})
+}
+
+func TestSavedFilterTeam_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	ft := &SavedFilterTeam{FilterID: 1}
+	teams, _, total, err := ft.ReadAll(s, &user.User{ID: 1}, "", 1, 50)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	all := teams.([]*TeamWithRight)
+	require.Len(t, all, 1)
+	assert.Equal(t, int64(11), all[0].ID)
+	assert.Equal(t, RightAdmin, all[0].Right)
+}
+
+func TestSavedFilterTeam_Update(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	ft := &SavedFilterTeam{FilterID: 1, TeamID: 11, Right: RightRead}
+	err := ft.Update(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertExists(t, "saved_filter_teams", map[string]interface{}{
+		"filter_id": 1,
+		"team_id":   11,
+		"right":     RightRead,
+	}, false)
+}
+
+func TestSavedFilterTeam_Delete(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ft := &SavedFilterTeam{FilterID: 1, TeamID: 11}
+		err := ft.Delete(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "saved_filter_teams", map[string]interface{}{
+			"filter_id": 1,
+			"team_id":   11,
+		})
+	})
+	t.Run("not shared", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ft := &SavedFilterTeam{FilterID: 1, TeamID: 9}
+		err := ft.Delete(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTeamDoesNotHaveAccessToSavedFilter(err))
+	})
+}
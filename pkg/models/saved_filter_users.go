@@ -0,0 +1,235 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// SavedFilterUser represents a saved filter <-> user relation
+type SavedFilterUser struct {
+	// The unique, numeric id of this saved filter <-> user relation.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The username.
+	Username string `xorm:"-" json:"user_id" param:"user"`
+	// Used internally to reference the user
+	UserID int64 `xorm:"bigint not null INDEX" json:"-"`
+	// The saved filter id.
+	FilterID int64 `xorm:"bigint not null INDEX" json:"-" param:"filter"`
+	// The right this user has. 0 = Read only, 1 = Read & Write, 2 = Admin. Admins can share the filter with others.
+	Right Right `xorm:"bigint INDEX not null default 0" json:"right" valid:"length(0|2)" maximum:"2" default:"0"`
+
+	// A timestamp when this relation was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this relation was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName is the table name for SavedFilterUser
+func (SavedFilterUser) TableName() string {
+	return "saved_filter_users"
+}
+
+// Create shares a saved filter with a user
+// @Summary Share a saved filter with a user
+// @Description Gives a user access to a saved filter.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param filter path int true "Filter ID"
+// @Param share body models.SavedFilterUser true "The user you want to share the filter with."
+// @Success 201 {object} models.SavedFilterUser "The created user <-> saved filter relation."
+// @Failure 400 {object} web.HTTPError "Invalid user saved filter object provided."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the saved filter."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 409 {object} web.HTTPError "The user already has access to the saved filter."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/users [put]
+func (fu *SavedFilterUser) Create(s *xorm.Session, _ web.Auth) (err error) {
+	if err := fu.Right.isValid(); err != nil {
+		return err
+	}
+
+	sf, err := getSavedFilterSimpleByID(s, fu.FilterID)
+	if err != nil {
+		return err
+	}
+
+	u, err := user.GetUserByUsername(s, fu.Username)
+	if err != nil {
+		return err
+	}
+	fu.UserID = u.ID
+
+	if sf.OwnerID == fu.UserID {
+		return ErrUserAlreadyHasAccessToSavedFilter{UserID: fu.UserID, SavedFilterID: fu.FilterID}
+	}
+
+	exists, err := s.
+		Where("filter_id = ? AND user_id = ?", fu.FilterID, fu.UserID).
+		Exist(&SavedFilterUser{})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrUserAlreadyHasAccessToSavedFilter{UserID: fu.UserID, SavedFilterID: fu.FilterID}
+	}
+
+	_, err = s.Insert(fu)
+	return err
+}
+
+// Delete removes a user from a saved filter
+// @Summary Remove a user from a saved filter
+// @Description Removes a user from a saved filter. The user won't have access to the saved filter anymore.
+// @tags sharing
+// @Produce json
+// @Security JWTKeyAuth
+// @Param filter path int true "Filter ID"
+// @Param user path string true "The username"
+// @Success 200 {object} models.Message "The user was successfully removed from the saved filter."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the saved filter."
+// @Failure 404 {object} web.HTTPError "The user or the share does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/users/{user} [delete]
+func (fu *SavedFilterUser) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	u, err := user.GetUserByUsername(s, fu.Username)
+	if err != nil {
+		return err
+	}
+	fu.UserID = u.ID
+
+	deleted, err := s.
+		Where("filter_id = ? AND user_id = ?", fu.FilterID, fu.UserID).
+		Delete(&SavedFilterUser{})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrUserDoesNotHaveAccessToSavedFilter{UserID: fu.UserID, SavedFilterID: fu.FilterID}
+	}
+
+	return nil
+}
+
+// ReadAll gets all users a saved filter is shared with
+// @Summary Get the users a saved filter is shared with
+// @Description Returns all users which have access to a saved filter through a share.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Param filter path int true "Filter ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param s query string false "Search users by its name."
+// @Security JWTKeyAuth
+// @Success 200 {array} models.UserWithRight "The users with the right they have."
+// @Failure 403 {object} web.HTTPError "No right to see the saved filter."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/users [get]
+func (fu *SavedFilterUser) ReadAll(s *xorm.Session, a web.Auth, search string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	sf := &SavedFilter{ID: fu.FilterID}
+	can, _, err := sf.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+
+	all := []*UserWithRight{}
+	query := s.
+		Table("users").
+		Join("INNER", "saved_filter_users", "saved_filter_users.user_id = users.id").
+		Where("saved_filter_users.filter_id = ?", fu.FilterID).
+		Where(db.ILIKE("users.username", search))
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&all)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// Obfuscate all user emails
+	for _, u := range all {
+		u.User.Email = ""
+	}
+
+	numberOfTotalItems, err = s.
+		Table("users").
+		Join("INNER", "saved_filter_users", "saved_filter_users.user_id = users.id").
+		Where("saved_filter_users.filter_id = ?", fu.FilterID).
+		Where(db.ILIKE("users.username", search)).
+		Count(&UserWithRight{})
+
+	return all, len(all), numberOfTotalItems, err
+}
+
+// Update changes the right of a user on a saved filter
+// @Summary Update a user <-> saved filter relation
+// @Description Updates the right a user has on a saved filter.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Param filter path int true "Filter ID"
+// @Param user path string true "The username"
+// @Param share body models.SavedFilterUser true "The user you want to update."
+// @Security JWTKeyAuth
+// @Success 200 {object} models.SavedFilterUser "The updated user <-> saved filter relation."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the saved filter."
+// @Failure 404 {object} web.HTTPError "The user or the share does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/users/{user} [post]
+func (fu *SavedFilterUser) Update(s *xorm.Session, _ web.Auth) (err error) {
+	if err := fu.Right.isValid(); err != nil {
+		return err
+	}
+
+	u, err := user.GetUserByUsername(s, fu.Username)
+	if err != nil {
+		return err
+	}
+	fu.UserID = u.ID
+
+	exists, err := s.
+		Where("filter_id = ? AND user_id = ?", fu.FilterID, fu.UserID).
+		Exist(&SavedFilterUser{})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUserDoesNotHaveAccessToSavedFilter{UserID: fu.UserID, SavedFilterID: fu.FilterID}
+	}
+
+	_, err = s.
+		Where("filter_id = ? AND user_id = ?", fu.FilterID, fu.UserID).
+		Cols("right").
+		Update(fu)
+	return err
+}
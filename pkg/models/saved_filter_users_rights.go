@@ -0,0 +1,42 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanCreate checks if the user can share a saved filter with a user
+func (fu *SavedFilterUser) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return fu.canDoSavedFilterUser(s, a)
+}
+
+// CanDelete checks if the user can remove a user from a saved filter
+func (fu *SavedFilterUser) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return fu.canDoSavedFilterUser(s, a)
+}
+
+// CanUpdate checks if the user can update a user <-> saved filter relation
+func (fu *SavedFilterUser) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return fu.canDoSavedFilterUser(s, a)
+}
+
+func (fu *SavedFilterUser) canDoSavedFilterUser(s *xorm.Session, a web.Auth) (bool, error) {
+	sf := &SavedFilter{ID: fu.FilterID}
+	return sf.IsAdmin(s, a)
+}
@@ -0,0 +1,187 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedFilterUser_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "user2", Right: RightWrite}
+		can, err := fu.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = fu.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "saved_filter_users", map[string]interface{}{
+			"filter_id": 1,
+			"user_id":   2,
+			"right":     RightWrite,
+		}, false)
+
+		can, _, err = (&SavedFilter{ID: 1}).CanRead(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("already shared", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "user3"}
+		err := fu.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserAlreadyHasAccessToSavedFilter(err))
+	})
+	t.Run("owner", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "user1"}
+		err := fu.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserAlreadyHasAccessToSavedFilter(err))
+	})
+	t.Run("invalid right", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "user2", Right: 500}
+		err := fu.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidRight(err))
+	})
+	t.Run("nonexisting user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "nonexisting"}
+		err := fu.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, user.IsErrUserDoesNotExist(err))
+	})
+	t.Run("no admin", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "user2"}
+		can, err := fu.CanCreate(s, &user.User{ID: 4})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestSavedFilterUser_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	fu := &SavedFilterUser{FilterID: 1}
+	users, _, total, err := fu.ReadAll(s, &user.User{ID: 3}, "", 1, 50)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	all := users.([]*UserWithRight)
+	require.Len(t, all, 2)
+	assert.Empty(t, all[0].Email)
+
+	_, _, _, err = fu.ReadAll(s, &user.User{ID: 2}, "", 1, 50)
+	require.Error(t, err)
+	assert.True(t, IsErrGenericForbidden(err))
+}
+
+func TestSavedFilterUser_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "user3", Right: RightAdmin}
+		err := fu.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "saved_filter_users", map[string]interface{}{
+			"filter_id": 1,
+			"user_id":   3,
+			"right":     RightAdmin,
+		}, false)
+	})
+	t.Run("not shared", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "user2", Right: RightAdmin}
+		err := fu.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserDoesNotHaveAccessToSavedFilter(err))
+	})
+}
+
+func TestSavedFilterUser_Delete(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "user3"}
+		err := fu.Delete(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "saved_filter_users", map[string]interface{}{
+			"filter_id": 1,
+			"user_id":   3,
+		})
+	})
+	t.Run("not shared", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		fu := &SavedFilterUser{FilterID: 1, Username: "user2"}
+		err := fu.Delete(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserDoesNotHaveAccessToSavedFilter(err))
+	})
+}
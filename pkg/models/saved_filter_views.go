@@ -0,0 +1,147 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+// SavedFilterViewKind is one of the ways clients can show the tasks of a saved filter
+type SavedFilterViewKind string
+
+const (
+	// SavedFilterViewList shows the tasks as a list
+	SavedFilterViewList SavedFilterViewKind = "list"
+	// SavedFilterViewGantt shows the tasks in a gantt chart
+	SavedFilterViewGantt SavedFilterViewKind = "gantt"
+	// SavedFilterViewTable shows the tasks in a table
+	SavedFilterViewTable SavedFilterViewKind = "table"
+	// SavedFilterViewKanban shows the tasks on the kanban board of the filter
+	SavedFilterViewKanban SavedFilterViewKind = "kanban"
+)
+
+// SavedFilterViewSettings holds how the tasks of a saved filter are shown in the views of a client. They are saved
+// with the filter so that it looks the same on every client. How the kanban board is grouped into buckets is
+// configured with the bucket configuration of the filter.
+type SavedFilterViewSettings struct {
+	// The view which is shown when opening the filter. Can be `list`, `gantt`, `table` or `kanban`.
+	// `kanban` is only possible if the filter has a kanban board.
+	DefaultView SavedFilterViewKind `json:"default_view"`
+	// The date field of the tasks used as the start of a bar in the gantt chart.
+	GanttStartDateField string `json:"gantt_start_date_field"`
+	// The date field of the tasks used as the end of a bar in the gantt chart.
+	GanttEndDateField string `json:"gantt_end_date_field"`
+	// Whether the gantt chart also shows tasks without a value in the start or end date field.
+	GanttShowTasksWithoutDates bool `json:"gantt_show_tasks_without_dates"`
+	// The task fields shown as columns of the table view, in the order they are shown.
+	TableColumns []string `json:"table_columns"`
+}
+
+var savedFilterGanttDateFields = map[string]bool{
+	taskPropertyStartDate: true,
+	taskPropertyEndDate:   true,
+	taskPropertyDueDate:   true,
+	taskPropertyDoneAt:    true,
+	taskPropertyCreated:   true,
+	taskPropertyUpdated:   true,
+}
+
+// Fields which can be shown in the table but can't be filtered by directly
+var savedFilterExtraTableColumns = map[string]bool{
+	"labels":    true,
+	"assignees": true,
+}
+
+func defaultSavedFilterViewSettings() *SavedFilterViewSettings {
+	return &SavedFilterViewSettings{
+		DefaultView:         SavedFilterViewList,
+		GanttStartDateField: taskPropertyStartDate,
+		GanttEndDateField:   taskPropertyEndDate,
+		TableColumns: []string{
+			taskPropertyIndex,
+			taskPropertyTitle,
+			taskPropertyPriority,
+			"labels",
+			"assignees",
+			taskPropertyDueDate,
+			taskPropertyPercentDone,
+		},
+	}
+}
+
+// getViewSettings returns the view settings of the filter with defaults for everything which is not set
+func (sf *SavedFilter) getViewSettings() *SavedFilterViewSettings {
+	settings := defaultSavedFilterViewSettings()
+	if sf.ViewSettings == nil {
+		return settings
+	}
+
+	if sf.ViewSettings.DefaultView != "" {
+		settings.DefaultView = sf.ViewSettings.DefaultView
+	}
+	if sf.ViewSettings.GanttStartDateField != "" {
+		settings.GanttStartDateField = sf.ViewSettings.GanttStartDateField
+	}
+	if sf.ViewSettings.GanttEndDateField != "" {
+		settings.GanttEndDateField = sf.ViewSettings.GanttEndDateField
+	}
+	if len(sf.ViewSettings.TableColumns) > 0 {
+		settings.TableColumns = sf.ViewSettings.TableColumns
+	}
+	settings.GanttShowTasksWithoutDates = sf.ViewSettings.GanttShowTasksWithoutDates
+
+	return settings
+}
+
+func (sf *SavedFilter) validateViewSettings() error {
+	if sf.ViewSettings == nil {
+		return nil
+	}
+
+	invalid := func(setting string) error {
+		return ErrInvalidSavedFilterViewSettings{SavedFilterID: sf.ID, Setting: setting}
+	}
+
+	switch sf.ViewSettings.DefaultView {
+	case "", SavedFilterViewList, SavedFilterViewGantt, SavedFilterViewTable:
+	case SavedFilterViewKanban:
+		if sf.BucketConfigurationMode == SavedFilterBucketConfigurationModeNone {
+			return invalid("default_view")
+		}
+	default:
+		return invalid("default_view")
+	}
+
+	if sf.ViewSettings.GanttStartDateField != "" && !savedFilterGanttDateFields[sf.ViewSettings.GanttStartDateField] {
+		return invalid("gantt_start_date_field")
+	}
+	if sf.ViewSettings.GanttEndDateField != "" && !savedFilterGanttDateFields[sf.ViewSettings.GanttEndDateField] {
+		return invalid("gantt_end_date_field")
+	}
+
+	seen := make(map[string]bool, len(sf.ViewSettings.TableColumns))
+	for _, column := range sf.ViewSettings.TableColumns {
+		if seen[column] {
+			return invalid("table_columns")
+		}
+		seen[column] = true
+		if savedFilterExtraTableColumns[column] {
+			continue
+		}
+		if err := validateTaskField(column); err != nil {
+			return invalid("table_columns")
+		}
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 
 	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web"
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -39,9 +40,21 @@ type SavedFilter struct {
 	// The user who owns this filter
 	Owner *user.User `xorm:"-" json:"owner" valid:"-"`
 
+	// The subscription status for the user reading this filter. You can only read this property, use the subscription endpoints to modify it.
+	// Subscribers get notified when tasks start or stop matching the filter.
+	Subscription *Subscription `xorm:"-" json:"subscription,omitempty"`
+
 	// True if the filter is a favorite. Favorite filters show up in a separate parent project together with favorite projects.
 	IsFavorite bool `xorm:"default false" json:"is_favorite"`
 
+	// How the kanban board of this filter is divided into buckets. Can have five possible values: 0 = the filter has no kanban board, 1 = the buckets are defined in bucket_configuration, 2 = one bucket for undone and one for done tasks, 3 = one bucket per priority, 4 = one bucket per label.
+	BucketConfigurationMode SavedFilterBucketConfigurationMode `xorm:"not null default 0" json:"bucket_configuration_mode"`
+	// The buckets of the kanban board of this filter. Only used if bucket_configuration_mode is 1.
+	BucketConfiguration []*SavedFilterBucketConfiguration `xorm:"JSON null" json:"bucket_configuration"`
+	// How clients show the tasks of this filter in their list, gantt, table and kanban views. Settings which are not
+	// provided are returned with their defaults.
+	ViewSettings *SavedFilterViewSettings `xorm:"JSON null" json:"view_settings"`
+
 	// A timestamp when this filter was created. You cannot change this value.
 	Created time.Time `xorm:"created not null" json:"created"`
 	// A timestamp when this filter was last updated. You cannot change this value.
@@ -89,19 +102,33 @@ func getSavedFiltersForUser(s *xorm.Session, auth web.Auth) (filters []*SavedFil
 		return nil, ErrSavedFilterNotAvailableForLinkShare{LinkShareID: auth.GetID()}
 	}
 
-	err = s.Where("owner_id = ?", auth.GetID()).Find(&filters)
+	err = s.
+		Where(builder.Or(
+			builder.Eq{"owner_id": auth.GetID()},
+			builder.In("id", builder.
+				Select("filter_id").
+				From("saved_filter_users").
+				Where(builder.Eq{"user_id": auth.GetID()})),
+			builder.In("id", builder.
+				Select("saved_filter_teams.filter_id").
+				From("saved_filter_teams").
+				Join("INNER", "team_members", "team_members.team_id = saved_filter_teams.team_id").
+				Where(builder.Eq{"team_members.user_id": auth.GetID()})),
+		)).
+		Find(&filters)
 	return
 }
 
 func (sf *SavedFilter) toProject() *Project {
 	return &Project{
-		ID:          getProjectIDFromSavedFilterID(sf.ID),
-		Title:       sf.Title,
-		Description: sf.Description,
-		IsFavorite:  sf.IsFavorite,
-		Created:     sf.Created,
-		Updated:     sf.Updated,
-		Owner:       sf.Owner,
+		ID:           getProjectIDFromSavedFilterID(sf.ID),
+		Title:        sf.Title,
+		Description:  sf.Description,
+		IsFavorite:   sf.IsFavorite,
+		Created:      sf.Created,
+		Updated:      sf.Updated,
+		Owner:        sf.Owner,
+		ViewSettings: sf.getViewSettings(),
 	}
 }
 
@@ -117,6 +144,13 @@ func (sf *SavedFilter) toProject() *Project {
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /filters [put]
 func (sf *SavedFilter) Create(s *xorm.Session, auth web.Auth) error {
+	if err := sf.validateBucketConfiguration(); err != nil {
+		return err
+	}
+	if err := sf.validateViewSettings(); err != nil {
+		return err
+	}
+
 	sf.OwnerID = auth.GetID()
 	_, err := s.Insert(sf)
 	return err
@@ -148,10 +182,16 @@ func getSavedFilterSimpleByID(s *xorm.Session, id int64) (sf *SavedFilter, err e
 // @Failure 403 {object} web.HTTPError "The user does not have access to that saved filter."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /filters/{id} [get]
-func (sf *SavedFilter) ReadOne(s *xorm.Session, _ web.Auth) error {
+func (sf *SavedFilter) ReadOne(s *xorm.Session, a web.Auth) error {
 	// s already contains almost the full saved filter from the rights check, we only need to add the user
 	u, err := user.GetUserByID(s, sf.OwnerID)
+	if err != nil {
+		return err
+	}
 	sf.Owner = u
+	sf.ViewSettings = sf.getViewSettings()
+
+	sf.Subscription, err = GetSubscription(s, SubscriptionEntitySavedFilter, sf.ID, a)
 	return err
 }
 
@@ -177,6 +217,16 @@ func (sf *SavedFilter) Update(s *xorm.Session, _ web.Auth) error {
 	if sf.Filters == nil {
 		sf.Filters = origFilter.Filters
 	}
+	if sf.ViewSettings == nil {
+		sf.ViewSettings = origFilter.ViewSettings
+	}
+
+	if err := sf.validateBucketConfiguration(); err != nil {
+		return err
+	}
+	if err := sf.validateViewSettings(); err != nil {
+		return err
+	}
 
 	_, err = s.
 		Where("id = ?", sf.ID).
@@ -185,6 +235,9 @@ func (sf *SavedFilter) Update(s *xorm.Session, _ web.Auth) error {
 			"description",
 			"filters",
 			"is_favorite",
+			"bucket_configuration_mode",
+			"bucket_configuration",
+			"view_settings",
 		).
 		Update(sf)
 	return err
@@ -205,6 +258,37 @@ func (sf *SavedFilter) Update(s *xorm.Session, _ web.Auth) error {
 // @Router /filters/{id} [delete]
 func (sf *SavedFilter) Delete(s *xorm.Session, _ web.Auth) error {
 	_, err := s.
+		Where("filter_id = ?", sf.ID).
+		Delete(&SavedFilterUser{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("filter_id = ?", sf.ID).
+		Delete(&SavedFilterTeam{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		In("subscription_id", builder.
+			Select("id").
+			From("subscriptions").
+			Where(builder.Eq{"entity_id": sf.ID, "entity_type": SubscriptionEntitySavedFilter})).
+		Delete(&SavedFilterTaskMatch{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("entity_id = ? AND entity_type = ?", sf.ID, SubscriptionEntitySavedFilter).
+		Delete(&Subscription{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
 		Where("id = ?", sf.ID).
 		Delete(sf)
 	return err
@@ -23,20 +23,21 @@ import (
 
 // CanRead checks if a user has the right to read a saved filter
 func (sf *SavedFilter) CanRead(s *xorm.Session, auth web.Auth) (bool, int, error) {
-	can, err := sf.canDoFilter(s, auth)
-	return can, int(RightAdmin), err
+	can, right, err := sf.canDoFilter(s, auth, RightRead)
+	return can, int(right), err
 }
 
 // CanDelete checks if a user has the right to delete a saved filter
 func (sf *SavedFilter) CanDelete(s *xorm.Session, auth web.Auth) (bool, error) {
-	return sf.canDoFilter(s, auth)
+	return sf.IsAdmin(s, auth)
 }
 
 // CanUpdate checks if a user has the right to update a saved filter
 func (sf *SavedFilter) CanUpdate(s *xorm.Session, auth web.Auth) (bool, error) {
 	// A normal check would replace the passed struct which in our case would override the values we want to update.
 	sff := &SavedFilter{ID: sf.ID}
-	return sff.canDoFilter(s, auth)
+	can, _, err := sff.canDoFilter(s, auth, RightWrite)
+	return can, err
 }
 
 // CanCreate checks if a user has the right to update a saved filter
@@ -48,24 +49,71 @@ func (sf *SavedFilter) CanCreate(_ *xorm.Session, auth web.Auth) (bool, error) {
 	return true, nil
 }
 
-// Helper function to check saved filter rights sind they all have the same logic
-func (sf *SavedFilter) canDoFilter(s *xorm.Session, auth web.Auth) (can bool, err error) {
+// IsAdmin checks if a user is the owner of a saved filter or has admin rights on it through a share
+func (sf *SavedFilter) IsAdmin(s *xorm.Session, auth web.Auth) (bool, error) {
+	can, _, err := sf.canDoFilter(s, auth, RightAdmin)
+	return can, err
+}
+
+// getRightForUser returns the highest right a user has on a saved filter. The owner always has admin rights,
+// everyone else gets their rights through sharing the filter with them directly or with one of their teams.
+func (sf *SavedFilter) getRightForUser(s *xorm.Session, userID int64) (right Right, has bool, err error) {
+	if sf.OwnerID == userID {
+		return RightAdmin, true, nil
+	}
+
+	rights := []Right{}
+	err = s.
+		Table("saved_filter_users").
+		Where("filter_id = ? AND user_id = ?", sf.ID, userID).
+		Cols("right").
+		Find(&rights)
+	if err != nil {
+		return
+	}
+
+	teamRights := []Right{}
+	err = s.
+		Table("saved_filter_teams").
+		Join("INNER", "team_members", "team_members.team_id = saved_filter_teams.team_id").
+		Where("saved_filter_teams.filter_id = ? AND team_members.user_id = ?", sf.ID, userID).
+		Cols("saved_filter_teams.right").
+		Find(&teamRights)
+	if err != nil {
+		return
+	}
+
+	for _, r := range append(rights, teamRights...) {
+		if !has || r > right {
+			right = r
+		}
+		has = true
+	}
+
+	return
+}
+
+// Helper function to check saved filter rights since they all have the same logic
+func (sf *SavedFilter) canDoFilter(s *xorm.Session, auth web.Auth, minRight Right) (can bool, right Right, err error) {
 	// Link shares can't view or modify saved filters, therefore we can error out right away
 	if _, is := auth.(*LinkSharing); is {
-		return false, ErrSavedFilterNotAvailableForLinkShare{LinkShareID: auth.GetID(), SavedFilterID: sf.ID}
+		return false, 0, ErrSavedFilterNotAvailableForLinkShare{LinkShareID: auth.GetID(), SavedFilterID: sf.ID}
 	}
 
 	sff, err := getSavedFilterSimpleByID(s, sf.ID)
 	if err != nil {
-		return false, err
+		return false, 0, err
 	}
 
-	// Only owners are allowed to do something with a saved filter
-	if sff.OwnerID != auth.GetID() {
-		return false, nil
+	right, has, err := sff.getRightForUser(s, auth.GetID())
+	if err != nil {
+		return false, 0, err
+	}
+	if !has || right < minRight {
+		return false, 0, nil
 	}
 
 	*sf = *sff
 
-	return true, nil
+	return true, right, nil
 }
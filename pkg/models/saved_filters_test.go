@@ -91,6 +91,9 @@ func TestSavedFilter_ReadOne(t *testing.T) {
 	err = sf.ReadOne(s, user1)
 	require.NoError(t, err)
 	assert.NotNil(t, sf.Owner)
+	require.NotNil(t, sf.ViewSettings)
+	assert.Equal(t, SavedFilterViewList, sf.ViewSettings.DefaultView)
+	assert.Equal(t, "start_date", sf.ViewSettings.GanttStartDateField)
 }
 
 func TestSavedFilter_Update(t *testing.T) {
@@ -134,6 +137,91 @@ func TestSavedFilter_Update(t *testing.T) {
 			"is_favorite": true,
 		}, false)
 	})
+	t.Run("invalid bucket configuration", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sf := &SavedFilter{
+			ID:                      1,
+			Filters:                 &TaskCollection{},
+			BucketConfigurationMode: SavedFilterBucketConfigurationModeManual,
+			BucketConfiguration: []*SavedFilterBucketConfiguration{
+				{Title: "No filter"},
+			},
+		}
+		err := sf.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidSavedFilterBucketConfiguration(err))
+	})
+	t.Run("view settings", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sf := &SavedFilter{
+			ID:      1,
+			Title:   "testfilter1",
+			Filters: &TaskCollection{},
+			ViewSettings: &SavedFilterViewSettings{
+				DefaultView:         SavedFilterViewGantt,
+				GanttStartDateField: "created",
+				GanttEndDateField:   "due_date",
+				TableColumns:        []string{"title", "labels", "done"},
+			},
+		}
+		err := sf.Update(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		p := &Project{ID: getProjectIDFromSavedFilterID(1)}
+		err = p.ReadOne(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		require.NotNil(t, p.ViewSettings)
+		assert.Equal(t, SavedFilterViewGantt, p.ViewSettings.DefaultView)
+		assert.Equal(t, "created", p.ViewSettings.GanttStartDateField)
+		assert.Equal(t, "due_date", p.ViewSettings.GanttEndDateField)
+		assert.Equal(t, []string{"title", "labels", "done"}, p.ViewSettings.TableColumns)
+	})
+	t.Run("invalid view settings", func(t *testing.T) {
+		tests := map[string]*SavedFilterViewSettings{
+			"default_view":           {DefaultView: "calendar"},
+			"gantt_start_date_field": {GanttStartDateField: "title"},
+			"table_columns":          {TableColumns: []string{"title", "title"}},
+		}
+		for setting, settings := range tests {
+			t.Run(setting, func(t *testing.T) {
+				db.LoadAndAssertFixtures(t)
+				s := db.NewSession()
+				defer s.Close()
+
+				sf := &SavedFilter{
+					ID:           1,
+					Filters:      &TaskCollection{},
+					ViewSettings: settings,
+				}
+				err := sf.Update(s, &user.User{ID: 1})
+				require.Error(t, err)
+				assert.True(t, IsErrInvalidSavedFilterViewSettings(err))
+				assert.Equal(t, setting, err.(ErrInvalidSavedFilterViewSettings).Setting)
+			})
+		}
+	})
+	t.Run("kanban without board", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sf := &SavedFilter{
+			ID:           1,
+			Filters:      &TaskCollection{},
+			ViewSettings: &SavedFilterViewSettings{DefaultView: SavedFilterViewKanban},
+		}
+		err := sf.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidSavedFilterViewSettings(err))
+	})
 }
 
 func TestSavedFilter_Delete(t *testing.T) {
@@ -335,4 +423,55 @@ func TestSavedFilter_Rights(t *testing.T) {
 			assert.False(t, can)
 		})
 	})
+	t.Run("shared", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			userID    int64
+			right     Right
+			canUpdate bool
+			canDelete bool
+		}{
+			{name: "read with user", userID: 3, right: RightRead},
+			{name: "write with user", userID: 4, right: RightWrite, canUpdate: true},
+			{name: "admin with team", userID: 8, right: RightAdmin, canUpdate: true, canDelete: true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				db.LoadAndAssertFixtures(t)
+				s := db.NewSession()
+				defer s.Close()
+
+				u := &user.User{ID: tt.userID}
+
+				can, max, err := (&SavedFilter{ID: 1}).CanRead(s, u)
+				require.NoError(t, err)
+				assert.True(t, can)
+				assert.Equal(t, int(tt.right), max)
+
+				can, err = (&SavedFilter{ID: 1}).CanUpdate(s, u)
+				require.NoError(t, err)
+				assert.Equal(t, tt.canUpdate, can)
+
+				can, err = (&SavedFilter{ID: 1}).CanDelete(s, u)
+				require.NoError(t, err)
+				assert.Equal(t, tt.canDelete, can)
+			})
+		}
+	})
+}
+
+func TestSavedFilter_getSavedFiltersForUser(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	filters, err := getSavedFiltersForUser(s, &user.User{ID: 8})
+	require.NoError(t, err)
+	require.Len(t, filters, 1)
+	assert.Equal(t, int64(1), filters[0].ID)
+
+	filters, err = getSavedFiltersForUser(s, &user.User{ID: 2})
+	require.NoError(t, err)
+	assert.Empty(t, filters)
 }
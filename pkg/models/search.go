@@ -0,0 +1,249 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// SearchResultType is the kind of entity a global search result is
+type SearchResultType string
+
+const (
+	// SearchResultTypeProject is a project the user has access to.
+	SearchResultTypeProject SearchResultType = "project"
+	// SearchResultTypeTask is a task in one of the projects the user has access to.
+	SearchResultTypeTask SearchResultType = "task"
+	// SearchResultTypeLabel is a label the user has access to.
+	SearchResultTypeLabel SearchResultType = "label"
+	// SearchResultTypeTeam is a team the user is a member of.
+	SearchResultTypeTeam SearchResultType = "team"
+	// SearchResultTypeComment is a comment on a task in one of the projects the user has access to.
+	SearchResultTypeComment SearchResultType = "comment"
+)
+
+// The order of result types with the same score
+var searchResultTypeOrder = map[SearchResultType]int{
+	SearchResultTypeProject: 0,
+	SearchResultTypeTask:    1,
+	SearchResultTypeLabel:   2,
+	SearchResultTypeTeam:    3,
+	SearchResultTypeComment: 4,
+}
+
+// SearchResult is a single result of a global search
+type SearchResult struct {
+	// The kind of entity this result is. One of `project`, `task`, `label`, `team` or `comment`.
+	Type SearchResultType `json:"type"`
+	// The id of the entity.
+	ID int64 `json:"id"`
+	// The title of the project, task or label, the name of the team or the text of the comment.
+	Title string `json:"title"`
+	// An excerpt of the title around the search term. The html is escaped, the search term itself is wrapped in `<mark>` tags.
+	// For tasks found through another property, this is the first of their `search_matches`.
+	Highlight string `json:"highlight"`
+	// The project the task or comment belongs to.
+	ProjectID int64 `json:"project_id,omitempty"`
+	// The task the comment belongs to.
+	TaskID int64 `json:"task_id,omitempty"`
+	// How well the entity matches the search term, between 0 and 1. Results are sorted by this.
+	Score float64 `json:"score"`
+	// The entity itself. Either a project, task, label, team or comment.
+	Entity interface{} `json:"entity"`
+}
+
+// searchScore rates how well a text matches a search term. Exact matches rank highest, followed by matches at the
+// beginning of the text, at the beginning of a word anywhere in the text and everywhere else.
+func searchScore(text, search string) float64 {
+	text = strings.ToLower(strings.TrimSpace(text))
+	search = strings.ToLower(strings.TrimSpace(search))
+
+	switch {
+	case text == search:
+		return 1
+	case strings.HasPrefix(text, search):
+		return 0.8
+	case strings.Contains(text, " "+search):
+		return 0.6
+	case strings.Contains(text, search):
+		return 0.4
+	default:
+		// Found through another property
+		return 0.1
+	}
+}
+
+func newSearchResult(resultType SearchResultType, id int64, title, search string, entity interface{}) *SearchResult {
+	return &SearchResult{
+		Type:      resultType,
+		ID:        id,
+		Title:     title,
+		Highlight: highlightSearchMatch(title, search),
+		Score:     searchScore(title, search),
+		Entity:    entity,
+	}
+}
+
+// Search searches projects, tasks, labels, teams and task comments the user has access to and returns the results
+// ranked by how well they match. At most limit results are returned.
+func Search(s *xorm.Session, a web.Auth, search string, limit int) (results []*SearchResult, err error) {
+	if _, is := a.(*LinkSharing); is {
+		return nil, ErrGenericForbidden{}
+	}
+
+	results = []*SearchResult{}
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return results, nil
+	}
+
+	if limit <= 0 || limit > config.ServiceMaxItemsPerPage.GetInt() {
+		limit = config.ServiceMaxItemsPerPage.GetInt()
+	}
+
+	u, err := user.GetUserByID(s, a.GetID())
+	if err != nil {
+		return nil, err
+	}
+	u.APITokenProjectIDs = getAPITokenProjectIDs(a)
+
+	projects, _, _, err := getRawProjectsForUser(s, &projectOptions{
+		search:  search,
+		user:    u,
+		page:    1,
+		perPage: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		// Saved filters are not searched
+		if p.ID < 0 {
+			continue
+		}
+		result := newSearchResult(SearchResultTypeProject, p.ID, p.Title, search, p)
+		result.ProjectID = p.ParentProjectID
+		results = append(results, result)
+	}
+
+	taskResult, _, _, err := (&TaskCollection{}).ReadAll(s, a, search, 1, limit)
+	if err != nil {
+		return nil, err
+	}
+	tasks, _ := taskResult.([]*Task)
+	for _, t := range tasks {
+		result := newSearchResult(SearchResultTypeTask, t.ID, t.Title, search, t)
+		result.ProjectID = t.ProjectID
+		if result.Highlight == "" && len(t.SearchMatches) > 0 {
+			result.Highlight = t.SearchMatches[0].Highlight
+		}
+		results = append(results, result)
+	}
+
+	labelResult, _, _, err := (&Label{}).ReadAll(s, a, search, 1, limit)
+	if err != nil {
+		return nil, err
+	}
+	labels, _ := labelResult.([]*LabelWithTaskID)
+	for _, l := range labels {
+		results = append(results, newSearchResult(SearchResultTypeLabel, l.ID, l.Title, search, &l.Label))
+	}
+
+	teamResult, _, _, err := (&Team{}).ReadAll(s, a, search, 1, limit)
+	if err != nil {
+		return nil, err
+	}
+	teams, _ := teamResult.([]*Team)
+	for _, t := range teams {
+		results = append(results, newSearchResult(SearchResultTypeTeam, t.ID, t.Name, search, t))
+	}
+
+	comments, err := searchTaskComments(s, u, search, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range comments {
+		result := newSearchResult(SearchResultTypeComment, c.ID, htmlToSearchText(c.Comment), search, &c.TaskComment)
+		result.TaskID = c.TaskID
+		result.ProjectID = c.ProjectID
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Type != results[j].Type {
+			return searchResultTypeOrder[results[i].Type] < searchResultTypeOrder[results[j].Type]
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+type taskCommentSearchResult struct {
+	TaskComment `xorm:"extends"`
+	ProjectID   int64 `xorm:"project_id" json:"-"`
+}
+
+func searchTaskComments(s *xorm.Session, u *user.User, search string, limit int) (comments []*taskCommentSearchResult, err error) {
+	projects, _, _, err := getRawProjectsForUser(s, &projectOptions{
+		user: u,
+		page: -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]int64, 0, len(projects))
+	for _, p := range projects {
+		if p.ID > 0 {
+			projectIDs = append(projectIDs, p.ID)
+		}
+	}
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	comments = []*taskCommentSearchResult{}
+	err = s.
+		Select("task_comments.*, tasks.project_id").
+		Table("task_comments").
+		Join("INNER", "tasks", "tasks.id = task_comments.task_id").
+		Where(builder.And(
+			builder.In("tasks.project_id", projectIDs),
+			db.ILIKE("task_comments.comment", search),
+		)).
+		OrderBy("task_comments.id desc").
+		Limit(limit).
+		Find(&comments)
+	return
+}
@@ -0,0 +1,140 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getSearchResultsOfType(results []*SearchResult, resultType SearchResultType) (ids []int64) {
+	for _, r := range results {
+		if r.Type == resultType {
+			ids = append(ids, r.ID)
+		}
+	}
+	return
+}
+
+func TestSearch(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("empty search", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		results, err := Search(s, u, " ", 0)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+	t.Run("labels", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		results, err := Search(s, u, "label #", 0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{1, 2, 4}, getSearchResultsOfType(results, SearchResultTypeLabel))
+		// Label 3 belongs to another user
+		assert.NotContains(t, getSearchResultsOfType(results, SearchResultTypeLabel), int64(3))
+	})
+	t.Run("exact matches rank first", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		results, err := Search(s, u, "testteam1", 0)
+		require.NoError(t, err)
+		require.NotEmpty(t, results)
+		assert.Equal(t, SearchResultTypeTeam, results[0].Type)
+		assert.Equal(t, int64(1), results[0].ID)
+		assert.InDelta(t, 1, results[0].Score, 0.001)
+		assert.Equal(t, "<mark>testteam1</mark>", results[0].Highlight)
+
+		for i := 1; i < len(results); i++ {
+			assert.GreaterOrEqual(t, results[i-1].Score, results[i].Score)
+		}
+	})
+	t.Run("projects and tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		results, err := Search(s, u, "Test1", 0)
+		require.NoError(t, err)
+		require.NotEmpty(t, results)
+		assert.Equal(t, SearchResultTypeProject, results[0].Type)
+		assert.Equal(t, int64(1), results[0].ID)
+	})
+	t.Run("comments", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		results, err := Search(s, u, "dolor sit", 0)
+		require.NoError(t, err)
+
+		var comment *SearchResult
+		for _, r := range results {
+			if r.Type == SearchResultTypeComment {
+				comment = r
+				break
+			}
+		}
+		require.NotNil(t, comment)
+		assert.Equal(t, int64(1), comment.ID)
+		assert.Equal(t, int64(1), comment.TaskID)
+		assert.Equal(t, int64(1), comment.ProjectID)
+		assert.Equal(t, "Lorem Ipsum <mark>Dolor Sit</mark> Amet", comment.Highlight)
+
+		// The task is found through its comment
+		assert.Contains(t, getSearchResultsOfType(results, SearchResultTypeTask), int64(1))
+	})
+	t.Run("comments in projects without access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		results, err := Search(s, &user.User{ID: 13}, "dolor sit", 0)
+		require.NoError(t, err)
+		assert.Empty(t, getSearchResultsOfType(results, SearchResultTypeComment))
+	})
+	t.Run("limit", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		results, err := Search(s, u, "task", 3)
+		require.NoError(t, err)
+		assert.Len(t, results, 3)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := Search(s, &LinkSharing{ID: 1, ProjectID: 1}, "task", 0)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
@@ -34,11 +34,13 @@ const (
 	SubscriptionEntityNamespace // Kept even though not used anymore since we don't want to manually change all ids
 	SubscriptionEntityProject
 	SubscriptionEntityTask
+	SubscriptionEntitySavedFilter
 )
 
 const (
-	entityProject = `project`
-	entityTask    = `task`
+	entityProject     = `project`
+	entityTask        = `task`
+	entitySavedFilter = `filter`
 )
 
 // Subscription represents a subscription for an entity
@@ -73,6 +75,8 @@ func getEntityTypeFromString(entityType string) SubscriptionEntityType {
 		return SubscriptionEntityProject
 	case entityTask:
 		return SubscriptionEntityTask
+	case entitySavedFilter:
+		return SubscriptionEntitySavedFilter
 	}
 
 	return SubscriptionEntityUnknown
@@ -85,6 +89,8 @@ func (et SubscriptionEntityType) String() string {
 		return entityProject
 	case SubscriptionEntityTask:
 		return entityTask
+	case SubscriptionEntitySavedFilter:
+		return entitySavedFilter
 	}
 
 	return ""
@@ -92,7 +98,8 @@ func (et SubscriptionEntityType) String() string {
 
 func (et SubscriptionEntityType) validate() error {
 	if et == SubscriptionEntityProject ||
-		et == SubscriptionEntityTask {
+		et == SubscriptionEntityTask ||
+		et == SubscriptionEntitySavedFilter {
 		return nil
 	}
 
@@ -106,7 +113,7 @@ func (et SubscriptionEntityType) validate() error {
 // @Accept json
 // @Produce json
 // @Security JWTKeyAuth
-// @Param entity path string true "The entity the user subscribes to. Can be either `project` or `task`."
+// @Param entity path string true "The entity the user subscribes to. Can be either `project`, `task` or `filter`. Subscribers of a saved filter get notified when tasks start or stop matching the filter."
 // @Param entityID path string true "The numeric id of the entity to subscribe to."
 // @Success 201 {object} models.Subscription "The subscription"
 // @Failure 403 {object} web.HTTPError "The user does not have access to subscribe to this entity."
@@ -136,6 +143,14 @@ func (sb *Subscription) Create(s *xorm.Session, auth web.Auth) (err error) {
 		return
 	}
 
+	if sb.EntityType == SubscriptionEntitySavedFilter {
+		// Remember which tasks match the filter right now so that only changes from now on are notified
+		_, _, err = sb.updateSavedFilterTaskMatches(s)
+		if err != nil {
+			return
+		}
+	}
+
 	sb.User, err = user.GetFromAuth(auth)
 	return
 }
@@ -147,7 +162,7 @@ func (sb *Subscription) Create(s *xorm.Session, auth web.Auth) (err error) {
 // @Accept json
 // @Produce json
 // @Security JWTKeyAuth
-// @Param entity path string true "The entity the user subscribed to. Can be either `project` or `task`."
+// @Param entity path string true "The entity the user subscribed to. Can be either `project`, `task` or `filter`."
 // @Param entityID path string true "The numeric id of the subscribed entity to."
 // @Success 200 {object} models.Subscription "The subscription"
 // @Failure 403 {object} web.HTTPError "The user does not have access to subscribe to this entity."
@@ -157,6 +172,18 @@ func (sb *Subscription) Create(s *xorm.Session, auth web.Auth) (err error) {
 func (sb *Subscription) Delete(s *xorm.Session, auth web.Auth) (err error) {
 	sb.UserID = auth.GetID()
 
+	if sb.EntityType == SubscriptionEntitySavedFilter {
+		_, err = s.
+			In("subscription_id", builder.
+				Select("id").
+				From("subscriptions").
+				Where(builder.Eq{"entity_id": sb.EntityID, "entity_type": sb.EntityType, "user_id": sb.UserID})).
+			Delete(&SavedFilterTaskMatch{})
+		if err != nil {
+			return
+		}
+	}
+
 	_, err = s.
 		Where("entity_id = ? AND entity_type = ? AND user_id = ?", sb.EntityID, sb.EntityType, sb.UserID).
 		Delete(&Subscription{})
@@ -171,6 +198,13 @@ func getSubscriberCondForEntities(entityType SubscriptionEntityType, entityIDs [
 		)
 	}
 
+	if entityType == SubscriptionEntitySavedFilter {
+		return builder.And(
+			builder.In("entity_id", entityIDs),
+			builder.Eq{"entity_type": SubscriptionEntitySavedFilter},
+		)
+	}
+
 	if entityType == SubscriptionEntityTask {
 		return builder.Or(
 			builder.And(
@@ -268,6 +302,8 @@ func GetSubscriptions(s *xorm.Session, entityType SubscriptionEntityType, entity
 		}
 
 		return subs, nil
+	case SubscriptionEntitySavedFilter:
+		return getSubscriptionsForSavedFilters(s, entityIDs, u)
 	}
 
 	return
@@ -388,6 +424,27 @@ func getSubscriptionsForTasks(s *xorm.Session, taskIDs []int64, u *user.User) (p
 	return
 }
 
+func getSubscriptionsForSavedFilters(s *xorm.Session, filterIDs []int64, u *user.User) (filtersToSubscriptions map[int64][]*Subscription, err error) {
+	cond := getSubscriberCondForEntities(SubscriptionEntitySavedFilter, filterIDs)
+	if u != nil {
+		cond = builder.And(cond, builder.Eq{"user_id": u.ID})
+	}
+
+	var subscriptions []*Subscription
+	err = s.Where(cond).Find(&subscriptions)
+	if err != nil {
+		return nil, err
+	}
+
+	filtersToSubscriptions = make(map[int64][]*Subscription)
+	for _, sub := range subscriptions {
+		sub.Entity = sub.EntityType.String()
+		filtersToSubscriptions[sub.EntityID] = append(filtersToSubscriptions[sub.EntityID], sub)
+	}
+
+	return
+}
+
 func getSubscribersForEntity(s *xorm.Session, entityType SubscriptionEntityType, entityID int64) (subscriptions []*Subscription, err error) {
 	if err := entityType.validate(); err != nil {
 		return nil, err
@@ -35,6 +35,10 @@ func TestSubscriptionGetTypeFromString(t *testing.T) {
 		entityType := getEntityTypeFromString("task")
 		assert.Equal(t, SubscriptionEntityType(SubscriptionEntityTask), entityType)
 	})
+	t.Run("filter", func(t *testing.T) {
+		entityType := getEntityTypeFromString("filter")
+		assert.Equal(t, SubscriptionEntityType(SubscriptionEntitySavedFilter), entityType)
+	})
 	t.Run("invalid", func(t *testing.T) {
 		entityType := getEntityTypeFromString("someomejghsd")
 		assert.Equal(t, SubscriptionEntityType(SubscriptionEntityUnknown), entityType)
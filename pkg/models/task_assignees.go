@@ -189,7 +189,7 @@ func (la *TaskAssginee) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
-	return events.Dispatch(&TaskAssigneeDeletedEvent{
+	return events.DispatchOnCommit(s, &TaskAssigneeDeletedEvent{
 		Task:     &task,
 		Assignee: &user.User{ID: la.UserID},
 		Doer:     doer,
@@ -272,7 +272,7 @@ func (t *Task) addNewAssigneeByID(s *xorm.Session, newAssigneeID int64, project
 	if err != nil {
 		return err
 	}
-	err = events.Dispatch(&TaskAssigneeCreatedEvent{
+	err = events.DispatchOnCommit(s, &TaskAssigneeCreatedEvent{
 		Task:     &task,
 		Assignee: newAssignee,
 		Doer:     doer,
@@ -39,6 +39,17 @@ type TaskAttachment struct {
 
 	File *files.File `xorm:"-" json:"file"`
 
+	// The url of a link attachment. Link attachments do not have a file.
+	URL string `xorm:"text null" json:"url"`
+	// The title of a link attachment. If none is provided when creating the attachment, the title of the website is used.
+	Title string `xorm:"text null" json:"title"`
+	// Metadata of the website a link attachment points to.
+	Preview *TaskAttachmentLinkPreview `xorm:"json null" json:"preview"`
+	// Whether this attachment is an image embedded in the task description. Identical inline images share the same file.
+	IsInline bool `xorm:"not null default false" json:"is_inline"`
+	// The text extracted from the file to make it searchable. Only set for supported file types.
+	TextContent string `xorm:"longtext null" json:"-"`
+
 	Created time.Time `xorm:"created" json:"created"`
 
 	web.CRUDable `xorm:"-" json:"-"`
@@ -55,7 +66,13 @@ func (*TaskAttachment) TableName() string {
 func (ta *TaskAttachment) NewAttachment(s *xorm.Session, f io.ReadCloser, realname string, realsize uint64, a web.Auth) error {
 
 	// Store the file
-	file, err := files.Create(f, realname, realsize, a)
+	var file *files.File
+	var err error
+	if ta.IsInline {
+		file, err = files.CreateDeduplicated(f, realname, realsize, a)
+	} else {
+		file, err = files.Create(f, realname, realsize, a)
+	}
 	if err != nil {
 		if files.IsErrFileIsTooLarge(err) {
 			return ErrTaskAttachmentIsTooLarge{Size: realsize}
@@ -67,10 +84,14 @@ func (ta *TaskAttachment) NewAttachment(s *xorm.Session, f io.ReadCloser, realna
 	// Add an entry to the db
 	ta.FileID = file.ID
 
+	if !ta.IsInline {
+		ta.TextContent = extractAttachmentText(file)
+	}
+
 	ta.CreatedBy, err = GetUserOrLinkShareUser(s, a)
 	if err != nil {
 		// remove the  uploaded file if adding it to the db fails
-		if err2 := file.Delete(); err2 != nil {
+		if err2 := deleteAttachmentFileIfUnused(s, file, ta.ID); err2 != nil {
 			return err2
 		}
 		return err
@@ -80,7 +101,7 @@ func (ta *TaskAttachment) NewAttachment(s *xorm.Session, f io.ReadCloser, realna
 	_, err = s.Insert(ta)
 	if err != nil {
 		// remove the  uploaded file if adding it to the db fails
-		if err2 := file.Delete(); err2 != nil {
+		if err2 := deleteAttachmentFileIfUnused(s, file, ta.ID); err2 != nil {
 			return err2
 		}
 		return err
@@ -91,7 +112,7 @@ func (ta *TaskAttachment) NewAttachment(s *xorm.Session, f io.ReadCloser, realna
 		return err
 	}
 
-	return events.Dispatch(&TaskAttachmentCreatedEvent{
+	return events.DispatchOnCommit(s, &TaskAttachmentCreatedEvent{
 		Task:       &task,
 		Attachment: ta,
 		Doer:       ta.CreatedBy,
@@ -111,6 +132,10 @@ func (ta *TaskAttachment) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
 		}
 	}
 
+	if ta.IsLink() {
+		return
+	}
+
 	// Get the file
 	ta.File = &files.File{ID: ta.FileID}
 	err = ta.File.LoadFileMetaByID()
@@ -215,13 +240,15 @@ func (ta *TaskAttachment) Delete(s *xorm.Session, a web.Auth) error {
 	}
 
 	// Delete the underlying file
-	err = ta.File.Delete()
-	// If the file does not exist, we don't want to error out
-	if err != nil && files.IsErrFileDoesNotExist(err) {
-		return nil
-	}
-	if err != nil {
-		return err
+	if !ta.IsLink() {
+		err = deleteAttachmentFileIfUnused(s, ta.File, ta.ID)
+		// If the file does not exist, we don't want to error out
+		if err != nil && files.IsErrFileDoesNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 	}
 
 	doer, _ := user.GetFromAuth(a)
@@ -230,17 +257,34 @@ func (ta *TaskAttachment) Delete(s *xorm.Session, a web.Auth) error {
 		return err
 	}
 
-	return events.Dispatch(&TaskAttachmentDeletedEvent{
+	return events.DispatchOnCommit(s, &TaskAttachmentDeletedEvent{
 		Task:       &task,
 		Attachment: ta,
 		Doer:       doer,
 	})
 }
 
+// deleteAttachmentFileIfUnused removes the file of an attachment unless another attachment still uses it.
+// This is the case for inline attachments with the same content.
+func deleteAttachmentFileIfUnused(s *xorm.Session, file *files.File, attachmentID int64) error {
+	used, err := s.
+		Where("file_id = ? AND id != ?", file.ID, attachmentID).
+		Exist(&TaskAttachment{})
+	if err != nil {
+		return err
+	}
+	if used {
+		return nil
+	}
+
+	return file.Delete()
+}
+
 func getTaskAttachmentsByTaskIDs(s *xorm.Session, taskIDs []int64) (attachments []*TaskAttachment, err error) {
 	attachments = []*TaskAttachment{}
 	err = s.
 		In("task_id", taskIDs).
+		Omit("text_content").
 		Find(&attachments)
 	if err != nil {
 		return
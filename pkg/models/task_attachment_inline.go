@@ -0,0 +1,135 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+
+	"xorm.io/xorm"
+)
+
+// Inline attachments are only removed once they were not used for this long. This gives clients
+// enough time to save the description after uploading an image.
+const inlineAttachmentCleanupGracePeriod = 24 * time.Hour
+
+// isInlineAttachmentUsed checks if an inline attachment is still embedded in the description of any task
+// or in any comment.
+func isInlineAttachmentUsed(s *xorm.Session, ta *TaskAttachment) (bool, error) {
+	reference := "/tasks/" + strconv.FormatInt(ta.TaskID, 10) + "/attachments/" + strconv.FormatInt(ta.ID, 10)
+	// The like query also matches attachments with an id starting with the id of this attachment,
+	// hence the additional check with the regex.
+	referenceRegex := regexp.MustCompile(regexp.QuoteMeta(reference) + `(\D|$)`)
+
+	tasks := []*Task{}
+	err := s.
+		Where(db.ILIKE("description", reference)).
+		Cols("id", "description").
+		Find(&tasks)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range tasks {
+		if referenceRegex.MatchString(t.Description) {
+			return true, nil
+		}
+	}
+
+	comments := []*TaskComment{}
+	err = s.
+		Where(db.ILIKE("comment", reference)).
+		Cols("id", "comment").
+		Find(&comments)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range comments {
+		if referenceRegex.MatchString(c.Comment) {
+			return true, nil
+		}
+	}
+
+	return s.
+		Where("cover_image_attachment_id = ?", ta.ID).
+		Exist(&Task{})
+}
+
+// cleanupUnusedInlineAttachments removes all inline attachments which are not embedded anywhere anymore.
+// Their files are only removed if no other inline attachment with the same content still uses them.
+func cleanupUnusedInlineAttachments(s *xorm.Session, now time.Time) (removed int, err error) {
+	attachments := []*TaskAttachment{}
+	err = s.
+		Where("is_inline = ? AND created < ?", true, now.Add(-inlineAttachmentCleanupGracePeriod)).
+		OrderBy("id asc").
+		Find(&attachments)
+	if err != nil {
+		return
+	}
+
+	for _, ta := range attachments {
+		used, err := isInlineAttachmentUsed(s, ta)
+		if err != nil {
+			return removed, err
+		}
+		if used {
+			continue
+		}
+
+		err = ta.Delete(s, nil)
+		if err != nil && !IsErrTaskAttachmentDoesNotExist(err) {
+			return removed, err
+		}
+		removed++
+	}
+
+	return
+}
+
+// RegisterInlineAttachmentCleanupCron registers a cron function which removes inline attachments
+// which are no longer embedded in any task description or comment.
+func RegisterInlineAttachmentCleanupCron() {
+	const logPrefix = "[Inline Attachment Cleanup Cron] "
+
+	err := cron.Schedule("0 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		removed, err := cleanupUnusedInlineAttachments(s, time.Now())
+		if err != nil {
+			log.Errorf(logPrefix+"Could not remove unused inline attachments: %s", err)
+			_ = s.Rollback()
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit removing unused inline attachments: %s", err)
+			return
+		}
+
+		if removed > 0 {
+			log.Debugf(logPrefix+"Removed %d unused inline attachments", removed)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register inline attachment cleanup cron: %s", err)
+	}
+}
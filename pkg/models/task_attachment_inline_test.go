@@ -0,0 +1,120 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func createInlineAttachment(t *testing.T, s *xorm.Session, taskID int64, content string) *TaskAttachment {
+	ta := &TaskAttachment{TaskID: taskID, IsInline: true}
+	err := ta.NewAttachment(s, io.NopCloser(bytes.NewReader([]byte(content))), "image.png", uint64(len(content)), &user.User{ID: 1})
+	require.NoError(t, err)
+	return ta
+}
+
+func TestTaskAttachment_NewAttachment_Inline(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	files.InitTestFileFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	first := createInlineAttachment(t, s, 1, "image")
+	second := createInlineAttachment(t, s, 2, "image")
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.Equal(t, first.FileID, second.FileID)
+
+	t.Run("deleting keeps the shared file", func(t *testing.T) {
+		err := first.Delete(s, &user.User{ID: 1})
+		require.NoError(t, err)
+
+		f := &files.File{ID: second.FileID}
+		err = f.LoadFileMetaByID()
+		require.NoError(t, err)
+	})
+	t.Run("deleting the last attachment removes the file", func(t *testing.T) {
+		err := second.Delete(s, &user.User{ID: 1})
+		require.NoError(t, err)
+
+		f := &files.File{ID: second.FileID}
+		err = f.LoadFileMetaByID()
+		require.Error(t, err)
+		assert.True(t, files.IsErrFileDoesNotExist(err))
+	})
+}
+
+func TestCleanupUnusedInlineAttachments(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	files.InitTestFileFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	used := createInlineAttachment(t, s, 1, "used")
+	inComment := createInlineAttachment(t, s, 1, "comment")
+	unused := createInlineAttachment(t, s, 1, "unused")
+	// Must not count as a reference to the unused attachment
+	similar := &TaskAttachment{ID: unused.ID * 10, TaskID: 1}
+
+	_, err := s.ID(1).Cols("description").Update(&Task{
+		Description: `<p><img src="http://localhost/api/v1/tasks/1/attachments/` + strconv.FormatInt(similar.ID, 10) + `"></p>` +
+			`<p><img src="http://localhost/api/v1/tasks/1/attachments/` + strconv.FormatInt(used.ID, 10) + `"></p>`,
+	})
+	require.NoError(t, err)
+	_, err = s.ID(1).Cols("comment").Update(&TaskComment{
+		Comment: `<img src="/api/v1/tasks/1/attachments/` + strconv.FormatInt(inComment.ID, 10) + `">`,
+	})
+	require.NoError(t, err)
+
+	t.Run("grace period", func(t *testing.T) {
+		removed, err := cleanupUnusedInlineAttachments(s, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 0, removed)
+	})
+	t.Run("removes unused", func(t *testing.T) {
+		removed, err := cleanupUnusedInlineAttachments(s, time.Now().Add(inlineAttachmentCleanupGracePeriod+time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_attachments", map[string]interface{}{
+			"id": unused.ID,
+		})
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"id": used.ID,
+		}, false)
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"id": inComment.ID,
+		}, false)
+		// Normal attachments are never removed
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"id": 1,
+		}, false)
+	})
+}
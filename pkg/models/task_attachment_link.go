@@ -0,0 +1,244 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/outbound"
+	"code.vikunja.io/api/pkg/version"
+	"code.vikunja.io/web"
+
+	"golang.org/x/net/html"
+	"xorm.io/xorm"
+)
+
+// TaskAttachmentLinkPreview holds metadata of the website a link attachment points to
+type TaskAttachmentLinkPreview struct {
+	// The title of the website.
+	Title string `json:"title"`
+	// The description of the website.
+	Description string `json:"description"`
+	// The url of the preview image of the website.
+	ImageURL string `json:"image_url"`
+	// The name of the site the website belongs to.
+	SiteName string `json:"site_name"`
+}
+
+// The maximum size of a website which is read to get its preview metadata
+const maxLinkPreviewSize = 1024 * 1024
+
+// linkPreviewAllowNonPublicAddresses allows fetching link previews from private or local addresses. Only used in tests.
+var linkPreviewAllowNonPublicAddresses = false
+
+var linkPreviewClient *http.Client
+
+// IsLink returns whether the attachment is a link attachment instead of a file attachment
+func (ta *TaskAttachment) IsLink() bool {
+	return ta.URL != ""
+}
+
+// Create creates a new link attachment
+// @Summary Attach a link to a task
+// @Description Creates a new attachment which points to an external url instead of an uploaded file. If no preview is provided, the title, description and image of the website are fetched by the server if link previews are enabled.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param attachment body models.TaskAttachment true "The link attachment. Only `url`, `title` and `preview` are used."
+// @Success 201 {object} models.TaskAttachment "The created link attachment."
+// @Failure 400 {object} web.HTTPError "The url is invalid."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/link [put]
+func (ta *TaskAttachment) Create(s *xorm.Session, a web.Auth) (err error) {
+	ta.URL = strings.TrimSpace(ta.URL)
+	u, err := url.Parse(ta.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidTaskAttachmentURL{URL: ta.URL}
+	}
+
+	ta.ID = 0
+	ta.FileID = 0
+	ta.File = nil
+
+	if ta.Preview == nil && config.ServiceEnableLinkPreviews.GetBool() {
+		ta.Preview, err = fetchLinkPreview(ta.URL)
+		if err != nil {
+			// The preview is optional, the link is attached anyway
+			log.Debugf("Could not fetch link preview for %s: %s", ta.URL, err)
+		}
+	}
+
+	ta.Title = strings.TrimSpace(ta.Title)
+	if ta.Title == "" && ta.Preview != nil {
+		ta.Title = ta.Preview.Title
+	}
+	if ta.Title == "" {
+		ta.Title = ta.URL
+	}
+
+	ta.CreatedBy, err = GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+	ta.CreatedByID = ta.CreatedBy.ID
+
+	_, err = s.Insert(ta)
+	if err != nil {
+		return err
+	}
+
+	task, err := GetTaskByIDSimple(s, ta.TaskID)
+	if err != nil {
+		return err
+	}
+
+	return events.DispatchOnCommit(s, &TaskAttachmentCreatedEvent{
+		Task:       &task,
+		Attachment: ta,
+		Doer:       ta.CreatedBy,
+	})
+}
+
+func getLinkPreviewHTTPClient() *http.Client {
+	if linkPreviewClient != nil {
+		return linkPreviewClient
+	}
+
+	linkPreviewClient = outbound.NewClient(&outbound.Options{
+		Timeout:    10 * time.Second,
+		PublicOnly: !linkPreviewAllowNonPublicAddresses,
+	})
+
+	return linkPreviewClient
+}
+
+func fetchLinkPreview(link string) (preview *TaskAttachmentLinkPreview, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("User-Agent", "Vikunja/"+version.Version)
+	req.Header.Add("Accept", "text/html")
+
+	resp, err := getLinkPreviewHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 399 {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	if !strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
+		return nil, nil
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxLinkPreviewSize))
+	if err != nil {
+		return nil, err
+	}
+
+	preview = parseLinkPreview(doc, resp.Request.URL)
+	if *preview == (TaskAttachmentLinkPreview{}) {
+		return nil, nil
+	}
+
+	return preview, nil
+}
+
+// parseLinkPreview gets the preview metadata from the open graph tags of a website, falling back to the title and description meta tags.
+func parseLinkPreview(doc *html.Node, base *url.URL) *TaskAttachmentLinkPreview {
+	preview := &TaskAttachmentLinkPreview{}
+	var title, description string
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if title == "" && n.FirstChild != nil && n.FirstChild.Type == html.TextNode {
+					title = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case "meta":
+				var key, content string
+				for _, attr := range n.Attr {
+					switch attr.Key {
+					case "property", "name":
+						key = strings.ToLower(attr.Val)
+					case "content":
+						content = strings.TrimSpace(attr.Val)
+					}
+				}
+
+				switch key {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "description":
+					description = content
+				case "og:image":
+					preview.ImageURL = content
+				case "og:site_name":
+					preview.SiteName = content
+				}
+			case "body":
+				// All relevant metadata is in the head
+				return
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if preview.Title == "" {
+		preview.Title = title
+	}
+	if preview.Description == "" {
+		preview.Description = description
+	}
+	if preview.ImageURL != "" {
+		imageURL, err := base.Parse(preview.ImageURL)
+		if err != nil || (imageURL.Scheme != "http" && imageURL.Scheme != "https") {
+			preview.ImageURL = ""
+		} else {
+			preview.ImageURL = imageURL.String()
+		}
+	}
+
+	return preview
+}
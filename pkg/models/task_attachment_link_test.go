@@ -0,0 +1,162 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskAttachment_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/doc":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(`<html><head>
+<title>Fallback title</title>
+<meta property="og:title" content="The Doc">
+<meta name="description" content="All about the doc">
+<meta property="og:image" content="/preview.png">
+<meta property="og:site_name" content="Docs">
+</head><body><meta property="og:title" content="Not this one"></body></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	allowLocal := func(t *testing.T) {
+		linkPreviewAllowNonPublicAddresses = true
+		t.Cleanup(func() {
+			linkPreviewAllowNonPublicAddresses = false
+			// Don't reuse connections which were allowed before
+			linkPreviewClient = nil
+		})
+	}
+
+	t.Run("with preview", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		allowLocal(t)
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL + "/doc"}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+		assert.True(t, ta.IsLink())
+		assert.Equal(t, "The Doc", ta.Title)
+		assert.Nil(t, ta.File)
+		require.NotNil(t, ta.Preview)
+		assert.Equal(t, &TaskAttachmentLinkPreview{
+			Title:       "The Doc",
+			Description: "All about the doc",
+			ImageURL:    server.URL + "/preview.png",
+			SiteName:    "Docs",
+		}, ta.Preview)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"id":      ta.ID,
+			"task_id": 1,
+			"file_id": 0,
+			"url":     server.URL + "/doc",
+			"title":   "The Doc",
+		}, false)
+	})
+	t.Run("with title", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		allowLocal(t)
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL + "/doc", Title: "My title"}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "My title", ta.Title)
+		require.NotNil(t, ta.Preview)
+		assert.Equal(t, "The Doc", ta.Preview.Title)
+	})
+	t.Run("preview not found", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		allowLocal(t)
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL + "/missing"}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+		assert.Nil(t, ta.Preview)
+		assert.Equal(t, server.URL+"/missing", ta.Title)
+	})
+	t.Run("private address is not fetched", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL + "/doc"}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+		assert.Nil(t, ta.Preview)
+		assert.Equal(t, server.URL+"/doc", ta.Title)
+	})
+	t.Run("invalid scheme", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{TaskID: 1, URL: "file:///etc/passwd"}
+		err := ta.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskAttachmentURL(err))
+	})
+	t.Run("read and delete", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{TaskID: 1, URL: "https://example.com", Preview: &TaskAttachmentLinkPreview{Title: "Example"}}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "Example", ta.Title)
+
+		read := &TaskAttachment{TaskID: 1, ID: ta.ID}
+		err = read.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com", read.URL)
+		assert.Equal(t, "Example", read.Preview.Title)
+
+		del := &TaskAttachment{TaskID: 1, ID: ta.ID}
+		err = del.Delete(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_attachments", map[string]interface{}{
+			"id": ta.ID,
+		})
+	})
+}
@@ -30,14 +30,11 @@ func (ta *TaskAttachment) CanRead(s *xorm.Session, a web.Auth) (bool, int, error
 // CanDelete checks if the user can delete an attachment
 func (ta *TaskAttachment) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
 	t := &Task{ID: ta.TaskID}
-	return t.CanWrite(s, a)
+	return t.canDoTask(s, a, ProjectPermissionManageAttachments)
 }
 
 // CanCreate checks if the user can create an attachment
 func (ta *TaskAttachment) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
-	t, err := GetTaskByIDSimple(s, ta.TaskID)
-	if err != nil {
-		return false, err
-	}
-	return t.CanCreate(s, a)
+	t := &Task{ID: ta.TaskID}
+	return t.canDoTask(s, a, ProjectPermissionManageAttachments)
 }
@@ -0,0 +1,326 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/log"
+)
+
+const (
+	// Larger files are not searched for text to keep uploads fast
+	maxAttachmentTextExtractionSize = 20 * 1024 * 1024
+	// The maximum number of bytes of text stored per attachment
+	maxAttachmentTextLength = 100 * 1024
+)
+
+var pdfStreamRegex = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+
+// extractAttachmentText returns the text content of an attachment file so that it can be searched.
+// Plain text files, docx documents and pdf files are supported, for all other files this returns an empty string.
+func extractAttachmentText(file *files.File) string {
+	if file.Size > maxAttachmentTextExtractionSize {
+		return ""
+	}
+
+	var extract func(content []byte) (string, error)
+	switch strings.ToLower(filepath.Ext(file.Name)) {
+	case ".txt", ".md", ".markdown", ".csv", ".log", ".json", ".xml", ".yml", ".yaml":
+		extract = func(content []byte) (string, error) {
+			return string(content), nil
+		}
+	case ".docx":
+		extract = extractDocxText
+	case ".pdf":
+		extract = extractPDFText
+	default:
+		return ""
+	}
+
+	err := file.LoadFileByID()
+	if err != nil {
+		log.Errorf("Could not open file %d to extract its text: %s", file.ID, err)
+		return ""
+	}
+	defer file.File.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file.File, maxAttachmentTextExtractionSize))
+	if err != nil {
+		log.Errorf("Could not read file %d to extract its text: %s", file.ID, err)
+		return ""
+	}
+
+	text, err := extract(content)
+	if err != nil {
+		log.Debugf("Could not extract text from file %d: %s", file.ID, err)
+		return ""
+	}
+
+	return normalizeAttachmentText(text)
+}
+
+// normalizeAttachmentText removes invalid characters and collapses all whitespace of an extracted text
+func normalizeAttachmentText(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, text)
+	text = strings.Join(strings.Fields(text), " ")
+
+	if len(text) > maxAttachmentTextLength {
+		text = text[:maxAttachmentTextLength]
+		for !utf8.ValidString(text) {
+			text = text[:len(text)-1]
+		}
+	}
+
+	return text
+}
+
+// extractDocxText returns the text of all paragraphs of a word document
+func extractDocxText(content []byte) (string, error) {
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range r.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+
+		var text strings.Builder
+		var inText bool
+		decoder := xml.NewDecoder(io.LimitReader(rc, maxAttachmentTextExtractionSize))
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				return text.String(), nil
+			}
+			if err != nil {
+				return "", err
+			}
+
+			switch t := token.(type) {
+			case xml.StartElement:
+				inText = t.Name.Local == "t"
+				if t.Name.Local == "tab" {
+					text.WriteString(" ")
+				}
+			case xml.EndElement:
+				inText = false
+				if t.Name.Local == "p" {
+					text.WriteString("\n")
+				}
+			case xml.CharData:
+				if inText {
+					text.Write(t)
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// extractPDFText returns the text shown with the text operators of all content streams of a pdf file.
+// This is a best-effort extraction which does not support fonts with custom encodings.
+func extractPDFText(content []byte) (string, error) {
+	var text strings.Builder
+	for _, match := range pdfStreamRegex.FindAllSubmatch(content, -1) {
+		stream := match[1]
+		if r, err := zlib.NewReader(bytes.NewReader(stream)); err == nil {
+			decoded, err := io.ReadAll(io.LimitReader(r, maxAttachmentTextExtractionSize))
+			if err == nil {
+				stream = decoded
+			}
+		}
+		extractPDFContentStreamText(stream, &text)
+	}
+
+	return text.String(), nil
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/% \t\r\n\f\x00", c) >= 0
+}
+
+func extractPDFContentStreamText(stream []byte, text *strings.Builder) {
+	var pending []string
+	var inText bool
+	for i := 0; i < len(stream); i++ {
+		c := stream[i]
+		switch {
+		case c == '(':
+			str, end := readPDFLiteralString(stream, i)
+			pending = append(pending, str)
+			i = end
+		case c == '<' && i+1 < len(stream) && stream[i+1] == '<':
+			i++
+		case c == '<':
+			end := bytes.IndexByte(stream[i:], '>')
+			if end < 0 {
+				return
+			}
+			decoded, err := hex.DecodeString(strings.Join(strings.Fields(string(stream[i+1:i+end])), ""))
+			// Hex strings of fonts with custom encodings contain glyph ids instead of characters
+			if err == nil && isPrintableASCII(decoded) {
+				pending = append(pending, string(decoded))
+			}
+			i += end
+		case c == '%':
+			for i < len(stream) && stream[i] != '\n' && stream[i] != '\r' {
+				i++
+			}
+		case c == '/':
+			for i+1 < len(stream) && !isPDFDelimiter(stream[i+1]) {
+				i++
+			}
+		case !isPDFDelimiter(c):
+			start := i
+			for i+1 < len(stream) && !isPDFDelimiter(stream[i+1]) {
+				i++
+			}
+			switch string(stream[start : i+1]) {
+			case "BT":
+				inText = true
+			case "ET":
+				inText = false
+				text.WriteString("\n")
+			case "Tj", "TJ", "'", "\"":
+				if inText {
+					text.WriteString(strings.Join(pending, ""))
+					text.WriteString(" ")
+				}
+				pending = nil
+			case "T*", "Td", "TD":
+				if inText {
+					text.WriteString(" ")
+				}
+				pending = nil
+			default:
+				// Numbers are operands, everything else is an operator which does not show text
+				if !strings.ContainsAny(string(stream[start:start+1]), "+-.0123456789") {
+					pending = nil
+				}
+			}
+		}
+	}
+}
+
+func isPrintableASCII(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// readPDFLiteralString reads a string in parentheses starting at start and returns it together with the index of its
+// closing parenthesis.
+func readPDFLiteralString(stream []byte, start int) (string, int) {
+	var str bytes.Buffer
+	depth := 0
+	for i := start; i < len(stream); i++ {
+		c := stream[i]
+		switch c {
+		case '(':
+			if depth > 0 {
+				str.WriteByte(c)
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return decodePDFString(str.Bytes()), i
+			}
+			str.WriteByte(c)
+		case '\\':
+			i++
+			if i >= len(stream) {
+				return decodePDFString(str.Bytes()), i
+			}
+			switch e := stream[i]; e {
+			case 'n':
+				str.WriteByte('\n')
+			case 'r':
+				str.WriteByte('\r')
+			case 't':
+				str.WriteByte('\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// A line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					octal := int(e - '0')
+					for j := 0; j < 2 && i+1 < len(stream) && stream[i+1] >= '0' && stream[i+1] <= '7'; j++ {
+						i++
+						octal = octal*8 + int(stream[i]-'0')
+					}
+					str.WriteByte(byte(octal))
+					continue
+				}
+				str.WriteByte(e)
+			}
+		default:
+			str.WriteByte(c)
+		}
+	}
+
+	return decodePDFString(str.Bytes()), len(stream)
+}
+
+// decodePDFString converts the bytes of a pdf string to text. Strings starting with a byte order mark are
+// UTF-16 encoded, all others are treated as latin-1 which is close enough to the standard pdf encodings.
+func decodePDFString(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		units := make([]uint16, 0, len(b)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
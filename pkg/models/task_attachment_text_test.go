@@ -0,0 +1,116 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractDocxText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("word/document.xml")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Hello</w:t></w:r><w:r><w:tab/><w:t>docx</w:t></w:r></w:p>
+<w:p><w:r><w:t>Second paragraph</w:t></w:r></w:p>
+</w:body></w:document>`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	text, err := extractDocxText(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "Hello docx Second paragraph", normalizeAttachmentText(text))
+
+	_, err = extractDocxText([]byte("not a zip file"))
+	require.Error(t, err)
+}
+
+func TestExtractPDFText(t *testing.T) {
+	t.Run("uncompressed", func(t *testing.T) {
+		pdf := "%PDF-1.4\n1 0 obj\n<< /Length 60 >>\nstream\n" +
+			"BT /F1 12 Tf 72 712 Td (Hello \\(PDF\\) W\\366rld) Tj ET\n" +
+			"endstream\nendobj\n"
+
+		text, err := extractPDFText([]byte(pdf))
+		require.NoError(t, err)
+		assert.Equal(t, "Hello (PDF) Wörld", normalizeAttachmentText(text))
+	})
+	t.Run("compressed", func(t *testing.T) {
+		content := &bytes.Buffer{}
+		zw := zlib.NewWriter(content)
+		_, err := zw.Write([]byte("BT /F1 12 Tf [(Quarterly) -250 (report)] TJ T* <696e766f69636573> Tj ET"))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		pdf := "%PDF-1.4\n1 0 obj\n<< /Filter /FlateDecode >>\nstream\n" + content.String() + "\nendstream\nendobj\n"
+
+		text, err := extractPDFText([]byte(pdf))
+		require.NoError(t, err)
+		assert.Equal(t, "Quarterlyreport invoices", normalizeAttachmentText(text))
+	})
+}
+
+func TestNormalizeAttachmentText(t *testing.T) {
+	assert.Equal(t, "a b c", normalizeAttachmentText(" a\n\tb\x00  c "))
+	assert.Equal(t, "ab", normalizeAttachmentText("a\xffb"))
+
+	long := normalizeAttachmentText(strings.Repeat("ä", maxAttachmentTextLength))
+	assert.LessOrEqual(t, len(long), maxAttachmentTextLength)
+}
+
+func TestTaskAttachment_NewAttachment_TextContent(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("text file", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		content := "The quarterly\nreport lists all invoices."
+		ta := &TaskAttachment{TaskID: 1}
+		err := ta.NewAttachment(s, io.NopCloser(strings.NewReader(content)), "notes.txt", uint64(len(content)), u)
+		require.NoError(t, err)
+		assert.Equal(t, "The quarterly report lists all invoices.", ta.TextContent)
+	})
+	t.Run("unsupported file", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		content := "some binary content"
+		ta := &TaskAttachment{TaskID: 1}
+		err := ta.NewAttachment(s, io.NopCloser(strings.NewReader(content)), "image.png", uint64(len(content)), u)
+		require.NoError(t, err)
+		assert.Empty(t, ta.TextContent)
+	})
+}
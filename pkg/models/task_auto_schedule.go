@@ -0,0 +1,364 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// autoScheduleDefaultDuration is used for tasks which have neither a duration nor a start and end date.
+const autoScheduleDefaultDuration = 24 * time.Hour
+
+// ProjectSchedule holds the computed schedule of all undone tasks of a project based on their blocking relations
+type ProjectSchedule struct {
+	// The project the schedule belongs to.
+	ProjectID int64 `json:"project_id" param:"project"`
+	// The first day tasks can be scheduled on in the format YYYY-MM-DD. Defaults to today.
+	From string `json:"from" query:"from"`
+
+	// All undone tasks of the project with their computed dates, ordered by their earliest start.
+	Tasks []*ScheduledTask `json:"tasks"`
+	// The ids of all critical tasks in the order they need to be done. Any delay of one of these tasks delays the whole project.
+	CriticalPath []int64 `json:"critical_path"`
+	// The date the last task of the project ends.
+	EndDate time.Time `json:"end_date"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// ScheduledTask is a task with its computed schedule
+type ScheduledTask struct {
+	// The id of the task.
+	TaskID int64 `json:"task_id"`
+	// The title of the task.
+	Title string `json:"title"`
+	// The task identifier, based on the project identifier and the task's index.
+	Identifier string `json:"identifier"`
+	// The duration of the task in seconds which was used to compute the schedule.
+	Duration int64 `json:"duration"`
+	// The earliest date the task can start.
+	EarliestStart time.Time `json:"earliest_start"`
+	// The earliest date the task can end.
+	EarliestEnd time.Time `json:"earliest_end"`
+	// The latest date the task can start without delaying the end of the project.
+	LatestStart time.Time `json:"latest_start"`
+	// The latest date the task can end without delaying the end of the project.
+	LatestEnd time.Time `json:"latest_end"`
+	// The number of seconds the task can be delayed without delaying the end of the project.
+	Slack int64 `json:"slack"`
+	// Whether the task is on the critical path.
+	IsCritical bool `json:"is_critical"`
+	// Whether the computed dates differ from the start and end date currently saved for the task.
+	Changed bool `json:"changed"`
+
+	task     *Task
+	blockers []int64
+	blocking []int64
+}
+
+// CanRead checks if the user can see the schedule of a project
+func (ps *ProjectSchedule) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	p := &Project{ID: ps.ProjectID}
+	return p.CanRead(s, a)
+}
+
+// CanCreate checks if the user can save the computed schedule of a project to its tasks
+func (ps *ProjectSchedule) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	p := &Project{ID: ps.ProjectID}
+	return p.CanWrite(s, a)
+}
+
+func (ps *ProjectSchedule) getFrom() (from time.Time, err error) {
+	tz := config.GetTimeZone()
+	now := time.Now().In(tz)
+	from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+
+	if ps.From != "" {
+		from, err = time.ParseInLocation(scheduleConflictsDateFormat, ps.From, tz)
+		if err != nil {
+			return from, ErrInvalidData{Message: "The start date must be in the format YYYY-MM-DD."}
+		}
+	}
+
+	ps.From = from.Format(scheduleConflictsDateFormat)
+	return
+}
+
+func getScheduleDuration(t *Task) time.Duration {
+	if t.Duration > 0 {
+		return time.Duration(t.Duration) * time.Second
+	}
+
+	if !t.StartDate.IsZero() && t.EndDate.After(t.StartDate) {
+		return t.EndDate.Sub(t.StartDate)
+	}
+
+	return autoScheduleDefaultDuration
+}
+
+// compute calculates the earliest and latest dates of all undone tasks of the project.
+// Tasks without unfinished blockers keep their start date or start on the first day of the schedule if they don't have one.
+// Blocked tasks start as soon as all of their blockers end. Blockers from other projects are only taken into account
+// with the end date saved for them.
+func (ps *ProjectSchedule) compute(s *xorm.Session) (err error) {
+	from, err := ps.getFrom()
+	if err != nil {
+		return err
+	}
+
+	project, err := GetProjectSimpleByID(s, ps.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	tasks := []*Task{}
+	err = s.
+		Where("project_id = ? AND done = ?", ps.ProjectID, false).
+		OrderBy("id asc").
+		Find(&tasks)
+	if err != nil {
+		return err
+	}
+
+	ps.Tasks = []*ScheduledTask{}
+	ps.CriticalPath = []int64{}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	scheduled := make(map[int64]*ScheduledTask, len(tasks))
+	taskIDs := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		t.setIdentifier(project)
+		scheduled[t.ID] = &ScheduledTask{
+			TaskID:     t.ID,
+			Title:      t.Title,
+			Identifier: t.Identifier,
+			Duration:   int64(getScheduleDuration(t).Seconds()),
+			task:       t,
+		}
+		taskIDs = append(taskIDs, t.ID)
+	}
+
+	relations := []*TaskRelation{}
+	err = s.
+		Select("task_relations.*").
+		Join("INNER", "tasks", "tasks.id = task_relations.task_id").
+		Where(builder.And(
+			builder.In("task_relations.other_task_id", taskIDs),
+			builder.Eq{"task_relations.relation_kind": RelationKindBlocking},
+			builder.Eq{"tasks.done": false},
+		)).
+		OrderBy("task_relations.task_id asc, task_relations.other_task_id asc").
+		Find(&relations)
+	if err != nil {
+		return err
+	}
+
+	externalBlockerIDs := []int64{}
+	for _, rel := range relations {
+		blocked := scheduled[rel.OtherTaskID]
+		blocked.blockers = append(blocked.blockers, rel.TaskID)
+		if blocker, has := scheduled[rel.TaskID]; has {
+			blocker.blocking = append(blocker.blocking, rel.OtherTaskID)
+			continue
+		}
+		externalBlockerIDs = append(externalBlockerIDs, rel.TaskID)
+	}
+
+	externalEnds := make(map[int64]time.Time)
+	if len(externalBlockerIDs) > 0 {
+		externalBlockers := []*Task{}
+		err = s.In("id", externalBlockerIDs).Find(&externalBlockers)
+		if err != nil {
+			return err
+		}
+		for _, t := range externalBlockers {
+			t.normalizeSchedule()
+			if !t.EndDate.IsZero() {
+				externalEnds[t.ID] = t.EndDate
+			}
+		}
+	}
+
+	order, err := sortScheduledTasks(ps.ProjectID, taskIDs, scheduled)
+	if err != nil {
+		return err
+	}
+
+	// Forward pass: earliest start and end
+	for _, st := range order {
+		duration := time.Duration(st.Duration) * time.Second
+		if len(st.blockers) == 0 {
+			st.EarliestStart = st.task.StartDate
+			if st.EarliestStart.IsZero() {
+				st.EarliestStart = from
+			}
+		} else {
+			st.EarliestStart = from
+			for _, id := range st.blockers {
+				end := externalEnds[id]
+				if blocker, has := scheduled[id]; has {
+					end = blocker.EarliestEnd
+				}
+				if end.After(st.EarliestStart) {
+					st.EarliestStart = end
+				}
+			}
+		}
+		st.EarliestEnd = st.EarliestStart.Add(duration)
+		if st.EarliestEnd.After(ps.EndDate) {
+			ps.EndDate = st.EarliestEnd
+		}
+	}
+
+	// Backward pass: latest start and end without delaying the end of the project
+	for i := len(order) - 1; i >= 0; i-- {
+		st := order[i]
+		st.LatestEnd = ps.EndDate
+		for _, id := range st.blocking {
+			if ls := scheduled[id].LatestStart; ls.Before(st.LatestEnd) {
+				st.LatestEnd = ls
+			}
+		}
+		st.LatestStart = st.LatestEnd.Add(-time.Duration(st.Duration) * time.Second)
+		st.Slack = int64(st.LatestStart.Sub(st.EarliestStart).Seconds())
+		st.IsCritical = st.Slack == 0
+		st.Changed = !st.task.StartDate.Equal(st.EarliestStart) || !st.task.EndDate.Equal(st.EarliestEnd)
+	}
+
+	ps.Tasks = order
+	for _, st := range order {
+		if st.IsCritical {
+			ps.CriticalPath = append(ps.CriticalPath, st.TaskID)
+		}
+	}
+
+	return nil
+}
+
+// sortScheduledTasks orders the tasks so that every task comes after all of its blockers.
+// Tasks which become available at the same time are ordered by their id.
+func sortScheduledTasks(projectID int64, taskIDs []int64, scheduled map[int64]*ScheduledTask) (order []*ScheduledTask, err error) {
+	pending := make(map[int64]int, len(taskIDs))
+	queue := []int64{}
+	for _, id := range taskIDs {
+		for _, blocker := range scheduled[id].blockers {
+			if _, has := scheduled[blocker]; has {
+				pending[id]++
+			}
+		}
+		if pending[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+
+	order = make([]*ScheduledTask, 0, len(taskIDs))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		st := scheduled[id]
+		order = append(order, st)
+		for _, next := range st.blocking {
+			pending[next]--
+			if pending[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if len(order) < len(taskIDs) {
+		cycle := []int64{}
+		for _, id := range taskIDs {
+			if pending[id] > 0 {
+				cycle = append(cycle, id)
+			}
+		}
+		return nil, ErrTaskDependencyCycle{ProjectID: projectID, TaskIDs: cycle}
+	}
+
+	return order, nil
+}
+
+// ReadOne computes the schedule of a project
+// @Summary Compute the schedule of a project
+// @Description Computes the earliest and latest start and end dates of all undone tasks of a project based on their duration and blocking relations. Tasks which are not blocked keep their start date or start on the given day if they don't have one, blocked tasks start as soon as all their blockers end. Tasks without a duration or start and end date are assumed to take one day. The computed dates are not saved.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Param from query string false "The first day tasks can be scheduled on in the format YYYY-MM-DD. Defaults to today."
+// @Success 200 {object} models.ProjectSchedule "The computed schedule of the project."
+// @Failure 400 {object} web.HTTPError "Invalid start date provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 412 {object} web.HTTPError "The blocking relations of the tasks form a cycle."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/schedule [get]
+func (ps *ProjectSchedule) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	return ps.compute(s)
+}
+
+// Create computes the schedule of a project and saves it
+// @Summary Apply the computed schedule to the tasks of a project
+// @Description Computes the schedule of a project the same way as the get endpoint does and saves the earliest start and end dates as start and end date of all tasks whose dates changed.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Param schedule body models.ProjectSchedule true "The day the schedule should start on."
+// @Success 201 {object} models.ProjectSchedule "The saved schedule of the project."
+// @Failure 400 {object} web.HTTPError "Invalid start date provided."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the project."
+// @Failure 412 {object} web.HTTPError "The blocking relations of the tasks form a cycle."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/schedule [put]
+func (ps *ProjectSchedule) Create(s *xorm.Session, _ web.Auth) (err error) {
+	err = ps.compute(s)
+	if err != nil {
+		return err
+	}
+
+	for _, st := range ps.Tasks {
+		if !st.Changed {
+			continue
+		}
+
+		_, err = s.
+			Where("id = ?", st.TaskID).
+			Cols("start_date", "end_date", "duration").
+			Update(&Task{
+				StartDate: st.EarliestStart,
+				EndDate:   st.EarliestEnd,
+				Duration:  st.Duration,
+			})
+		if err != nil {
+			return err
+		}
+		st.Changed = false
+	}
+
+	return updateProjectLastUpdated(s, &Project{ID: ps.ProjectID})
+}
@@ -0,0 +1,148 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestProjectSchedule(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	setup := func(t *testing.T, s *xorm.Session) {
+		createBlockingRelation(t, s, 1, 3)
+		createBlockingRelation(t, s, 3, 4)
+		createBlockingRelation(t, s, 5, 4)
+
+		_, err := s.
+			Where("id = ?", 1).
+			Cols("duration").
+			Update(&Task{Duration: 2 * 24 * 60 * 60})
+		require.NoError(t, err)
+	}
+
+	getTask := func(schedule *ProjectSchedule, id int64) *ScheduledTask {
+		for _, st := range schedule.Tasks {
+			if st.TaskID == id {
+				return st
+			}
+		}
+		return nil
+	}
+
+	t.Run("compute", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setup(t, s)
+
+		schedule := &ProjectSchedule{ProjectID: 1, From: "2030-01-01"}
+		err := schedule.ReadOne(s, u)
+		require.NoError(t, err)
+
+		from := time.Date(2030, 1, 1, 0, 0, 0, 0, config.GetTimeZone())
+		day := 24 * time.Hour
+
+		t1 := getTask(schedule, 1)
+		require.NotNil(t, t1)
+		assert.Equal(t, from, t1.EarliestStart)
+		assert.Equal(t, from.Add(2*day), t1.EarliestEnd)
+		assert.True(t, t1.IsCritical)
+
+		t3 := getTask(schedule, 3)
+		require.NotNil(t, t3)
+		assert.Equal(t, from.Add(2*day), t3.EarliestStart)
+		assert.Equal(t, from.Add(3*day), t3.EarliestEnd)
+		assert.True(t, t3.IsCritical)
+
+		t4 := getTask(schedule, 4)
+		require.NotNil(t, t4)
+		assert.Equal(t, from.Add(3*day), t4.EarliestStart)
+		assert.Equal(t, from.Add(4*day), t4.EarliestEnd)
+		assert.True(t, t4.IsCritical)
+
+		t5 := getTask(schedule, 5)
+		require.NotNil(t, t5)
+		assert.Equal(t, from, t5.EarliestStart)
+		assert.Equal(t, from.Add(2*day), t5.LatestStart)
+		assert.Equal(t, int64(2*24*60*60), t5.Slack)
+		assert.False(t, t5.IsCritical)
+
+		assert.Equal(t, from.Add(4*day), schedule.EndDate)
+		assert.Equal(t, []int64{1, 3, 4}, schedule.CriticalPath)
+
+		// Done tasks are not scheduled
+		assert.Nil(t, getTask(schedule, 2))
+
+		// Nothing is saved
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"id":         4,
+			"start_date": from.Add(3 * day),
+		})
+	})
+	t.Run("apply", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setup(t, s)
+
+		schedule := &ProjectSchedule{ProjectID: 1, From: "2030-01-01"}
+		err := schedule.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		from := time.Date(2030, 1, 1, 0, 0, 0, 0, config.GetTimeZone())
+		task := &Task{ID: 4}
+		err = task.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, from.Add(3*24*time.Hour), task.StartDate)
+		assert.Equal(t, from.Add(4*24*time.Hour), task.EndDate)
+		assert.Equal(t, int64(24*60*60), task.Duration)
+	})
+	t.Run("cycle", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setup(t, s)
+		createBlockingRelation(t, s, 4, 1)
+
+		schedule := &ProjectSchedule{ProjectID: 1}
+		err := schedule.ReadOne(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskDependencyCycle(err))
+	})
+	t.Run("invalid start date", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		schedule := &ProjectSchedule{ProjectID: 1, From: "tomorrow"}
+		err := schedule.ReadOne(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidData(err))
+	})
+}
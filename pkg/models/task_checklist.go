@@ -0,0 +1,332 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// ChecklistItem is one item of the checklist of a task
+type ChecklistItem struct {
+	// The unique, numeric id of this checklist item.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"checklistitem"`
+	// The task this checklist item belongs to.
+	TaskID int64 `xorm:"bigint not null INDEX" json:"task_id" param:"task"`
+	// The title of this checklist item.
+	Title string `xorm:"text not null" json:"title" valid:"required" minLength:"1"`
+	// Whether this checklist item is done.
+	Done bool `xorm:"INDEX null" json:"done"`
+	// The time when this checklist item was marked as done.
+	DoneAt time.Time `xorm:"DATETIME null 'done_at'" json:"done_at"`
+	// The position of this item in the checklist of the task.
+	Position float64 `xorm:"double null" json:"position"`
+
+	// The user who created this checklist item.
+	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this checklist item was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this checklist item was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for checklist items
+func (*ChecklistItem) TableName() string {
+	return "task_checklist_items"
+}
+
+func getChecklistItemByID(s *xorm.Session, id int64) (item *ChecklistItem, err error) {
+	item = &ChecklistItem{}
+	exists, err := s.
+		Where("id = ?", id).
+		Get(item)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrChecklistItemDoesNotExist{ID: id}
+	}
+	return item, nil
+}
+
+func getChecklistItemsForTasks(s *xorm.Session, taskIDs []int64) (items []*ChecklistItem, err error) {
+	items = []*ChecklistItem{}
+	if len(taskIDs) == 0 {
+		return
+	}
+	err = s.
+		In("task_id", taskIDs).
+		OrderBy("position asc, id asc").
+		Find(&items)
+	return
+}
+
+func (item *ChecklistItem) setDoneAt(wasDone bool) {
+	if item.Done && !wasDone {
+		item.DoneAt = time.Now()
+	}
+	if !item.Done {
+		item.DoneAt = time.Time{}
+	}
+}
+
+func createChecklistItem(s *xorm.Session, item *ChecklistItem, createdBy *user.User) (err error) {
+	item.ID = 0
+	item.CreatedBy = createdBy
+	item.CreatedByID = createdBy.ID
+	if item.DoneAt.IsZero() {
+		item.setDoneAt(false)
+	}
+
+	_, err = s.Insert(item)
+	if err != nil {
+		return
+	}
+
+	item.Position = calculateDefaultPosition(item.ID, item.Position)
+	_, err = s.
+		Where("id = ?", item.ID).
+		Cols("position").
+		Update(item)
+	if err != nil {
+		return
+	}
+
+	return updateAutomaticPercentDoneByID(s, item.TaskID)
+}
+
+// Create adds a new item to the checklist of a task
+// @Summary Add a checklist item
+// @Description Adds a new item to the checklist of a task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param item body models.ChecklistItem true "The checklist item"
+// @Success 201 {object} models.ChecklistItem "The created checklist item."
+// @Failure 400 {object} web.HTTPError "Invalid checklist item provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/checklist [put]
+func (item *ChecklistItem) Create(s *xorm.Session, a web.Auth) (err error) {
+	createdBy, err := GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+
+	err = createChecklistItem(s, item, createdBy)
+	if err != nil {
+		return err
+	}
+
+	task, err := GetTaskByIDSimple(s, item.TaskID)
+	if err != nil {
+		return err
+	}
+
+	return events.DispatchOnCommit(s, &TaskChecklistItemCreatedEvent{
+		Task:          &task,
+		ChecklistItem: item,
+		Doer:          createdBy,
+	})
+}
+
+// ReadAll returns the checklist of a task
+// @Summary Get the checklist of a task
+// @Description Returns all checklist items of a task, sorted by their position.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Success 200 {array} models.ChecklistItem "The checklist items."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/checklist [get]
+func (item *ChecklistItem) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	task := &Task{ID: item.TaskID}
+	canRead, _, err := task.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	items, err := getChecklistItemsForTasks(s, []int64{item.TaskID})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addCreatorsToChecklistItems(s, items)
+	return items, len(items), int64(len(items)), err
+}
+
+// Update updates a checklist item
+// @Summary Update a checklist item
+// @Description Updates the title, done state and position of a checklist item.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param itemID path int true "Checklist item ID"
+// @Param item body models.ChecklistItem true "The checklist item"
+// @Success 200 {object} models.ChecklistItem "The updated checklist item."
+// @Failure 400 {object} web.HTTPError "Invalid checklist item provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The checklist item does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/checklist/{itemID} [post]
+func (item *ChecklistItem) Update(s *xorm.Session, a web.Auth) (err error) {
+	old, err := getChecklistItemByID(s, item.ID)
+	if err != nil {
+		return err
+	}
+
+	item.DoneAt = old.DoneAt
+	item.setDoneAt(old.Done)
+	item.Position = calculateDefaultPosition(item.ID, item.Position)
+
+	_, err = s.
+		Where("id = ?", item.ID).
+		Cols("title", "done", "done_at", "position").
+		Update(item)
+	if err != nil {
+		return
+	}
+
+	updated, err := getChecklistItemByID(s, item.ID)
+	if err != nil {
+		return err
+	}
+	*item = *updated
+
+	err = updateAutomaticPercentDoneByID(s, item.TaskID)
+	if err != nil {
+		return err
+	}
+
+	err = addCreatorsToChecklistItems(s, []*ChecklistItem{item})
+	if err != nil {
+		return err
+	}
+
+	task, err := GetTaskByIDSimple(s, item.TaskID)
+	if err != nil {
+		return err
+	}
+
+	doer, _ := GetUserOrLinkShareUser(s, a)
+	return events.DispatchOnCommit(s, &TaskChecklistItemUpdatedEvent{
+		Task:          &task,
+		ChecklistItem: item,
+		Doer:          doer,
+	})
+}
+
+// Delete removes an item from the checklist of a task
+// @Summary Delete a checklist item
+// @Description Removes an item from the checklist of a task.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param itemID path int true "Checklist item ID"
+// @Success 200 {object} models.Message "The checklist item was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The checklist item does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/checklist/{itemID} [delete]
+func (item *ChecklistItem) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	old, err := getChecklistItemByID(s, item.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("id = ?", item.ID).Delete(&ChecklistItem{})
+	if err != nil {
+		return
+	}
+
+	return updateAutomaticPercentDoneByID(s, old.TaskID)
+}
+
+func addCreatorsToChecklistItems(s *xorm.Session, items []*ChecklistItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		userIDs = append(userIDs, item.CreatedByID)
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		item.CreatedBy = users[item.CreatedByID]
+	}
+
+	return nil
+}
+
+type checklistCount struct {
+	TaskID int64
+	Done   bool
+	Count  int64
+}
+
+// addChecklistProgressToTasks counts how many of the checklist items of all tasks are done.
+func addChecklistProgressToTasks(s *xorm.Session, taskIDs []int64, taskMap map[int64]*Task) (err error) {
+	counts := []*checklistCount{}
+	err = s.
+		Table("task_checklist_items").
+		Select("task_id, done, count(*) AS count").
+		In("task_id", taskIDs).
+		GroupBy("task_id, done").
+		Find(&counts)
+	if err != nil {
+		return
+	}
+
+	for _, c := range counts {
+		task, has := taskMap[c.TaskID]
+		if !has {
+			continue
+		}
+		task.ChecklistItemsTotal += c.Count
+		if c.Done {
+			task.ChecklistItemsDone += c.Count
+		}
+	}
+
+	return
+}
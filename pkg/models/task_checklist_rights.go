@@ -0,0 +1,58 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see the checklist of a task
+func (item *ChecklistItem) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	t := &Task{ID: item.TaskID}
+	return t.CanRead(s, a)
+}
+
+// CanCreate checks if the user can add items to the checklist of a task
+func (item *ChecklistItem) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	t := &Task{ID: item.TaskID}
+	return t.CanWrite(s, a)
+}
+
+// CanUpdate checks if the user can update a checklist item
+func (item *ChecklistItem) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return item.canDoChecklistItem(s, a)
+}
+
+// CanDelete checks if the user can delete a checklist item
+func (item *ChecklistItem) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return item.canDoChecklistItem(s, a)
+}
+
+func (item *ChecklistItem) canDoChecklistItem(s *xorm.Session, a web.Auth) (bool, error) {
+	old, err := getChecklistItemByID(s, item.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if old.TaskID != item.TaskID {
+		return false, ErrChecklistItemDoesNotExist{ID: item.ID}
+	}
+
+	t := &Task{ID: item.TaskID}
+	return t.CanWrite(s, a)
+}
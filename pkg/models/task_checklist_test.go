@@ -0,0 +1,206 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecklistItem_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		item := &ChecklistItem{
+			TaskID: 1,
+			Title:  "Sit amet",
+		}
+		can, err := item.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = item.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), item.CreatedBy.ID)
+		assert.True(t, item.DoneAt.IsZero())
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_checklist_items", map[string]interface{}{
+			"id":      item.ID,
+			"task_id": 1,
+			"title":   "Sit amet",
+			"done":    false,
+		}, false)
+	})
+	t.Run("done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		item := &ChecklistItem{
+			TaskID: 1,
+			Title:  "Sit amet",
+			Done:   true,
+		}
+		err := item.Create(s, u)
+		require.NoError(t, err)
+		assert.False(t, item.DoneAt.IsZero())
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		item := &ChecklistItem{TaskID: 14}
+		can, err := item.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestChecklistItem_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	item := &ChecklistItem{TaskID: 1}
+	result, count, _, err := item.ReadAll(s, &user.User{ID: 1}, "", 1, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	items := result.([]*ChecklistItem)
+	assert.Equal(t, "Lorem", items[0].Title)
+	assert.True(t, items[0].Done)
+	assert.Equal(t, "Dolor", items[2].Title)
+	assert.Equal(t, int64(1), items[2].CreatedBy.ID)
+}
+
+func TestChecklistItem_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("mark as done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		item := &ChecklistItem{
+			ID:     2,
+			TaskID: 1,
+			Title:  "Ipsum",
+			Done:   true,
+		}
+		can, err := item.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = item.Update(s, u)
+		require.NoError(t, err)
+		assert.False(t, item.DoneAt.IsZero())
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_checklist_items", map[string]interface{}{
+			"id":   2,
+			"done": true,
+		}, false)
+
+		task := &Task{ID: 1}
+		err = task.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), task.ChecklistItemsTotal)
+		assert.Equal(t, int64(2), task.ChecklistItemsDone)
+		assert.Len(t, task.ChecklistItems, 3)
+	})
+	t.Run("mark as undone", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		item := &ChecklistItem{
+			ID:     1,
+			TaskID: 1,
+			Title:  "Lorem",
+		}
+		err := item.Update(s, u)
+		require.NoError(t, err)
+		assert.True(t, item.DoneAt.IsZero())
+	})
+	t.Run("item of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		item := &ChecklistItem{
+			ID:     1,
+			TaskID: 2,
+		}
+		_, err := item.CanUpdate(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrChecklistItemDoesNotExist(err))
+	})
+}
+
+func TestChecklistItem_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	item := &ChecklistItem{ID: 3, TaskID: 1}
+	can, err := item.CanDelete(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.True(t, can)
+	err = item.Delete(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "task_checklist_items", map[string]interface{}{
+		"id": 3,
+	})
+}
+
+func TestTask_Create_WithChecklist(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	task := &Task{
+		Title:     "Lorem",
+		ProjectID: 1,
+		ChecklistItems: []*ChecklistItem{
+			{Title: "First"},
+			{Title: "Second", Done: true},
+		},
+	}
+	err := task.Create(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertExists(t, "task_checklist_items", map[string]interface{}{
+		"task_id": task.ID,
+		"title":   "Second",
+		"done":    true,
+	}, false)
+}
@@ -41,6 +41,10 @@ type TaskCollection struct {
 	// If set to true, the result will also include null values
 	FilterIncludeNulls bool `query:"filter_include_nulls" json:"filter_include_nulls"`
 
+	// If set to true, tasks sorted by `position` are sorted by the positions the current user has set for them instead
+	// of the positions everyone in the project sees.
+	UserPositions bool `query:"user_positions" json:"user_positions,omitempty"`
+
 	web.CRUDable `xorm:"-" json:"-"`
 	web.Rights   `xorm:"-" json:"-"`
 }
@@ -62,13 +66,19 @@ func validateTaskField(fieldName string) error {
 		taskPropertyEndDate,
 		taskPropertyHexColor,
 		taskPropertyPercentDone,
+		taskPropertyEstimate,
 		taskPropertyUID,
 		taskPropertyCreated,
 		taskPropertyUpdated,
 		taskPropertyPosition,
 		taskPropertyKanbanPosition,
 		taskPropertyBucketID,
-		taskPropertyIndex:
+		taskPropertyIndex,
+		taskPropertyIsArchived,
+		taskPropertyStatusID,
+		taskPropertyMilestoneID,
+		taskPropertyMilestoneDueDate,
+		taskPropertyVotes:
 		return nil
 	}
 	return ErrInvalidTaskField{TaskField: fieldName}
@@ -122,17 +132,23 @@ func getTaskFilterOptsFromCollection(tf *TaskCollection) (opts *taskSearchOption
 // @Param id path int true "The project ID."
 // @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
 // @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
-// @Param s query string false "Search tasks by task text."
-// @Param sort_by query string false "The sorting parameter. You can pass this multiple times to get the tasks ordered by multiple different parametes, along with `order_by`. Possible values to sort by are `id`, `title`, `description`, `done`, `done_at`, `due_date`, `created_by_id`, `project_id`, `repeat_after`, `priority`, `start_date`, `end_date`, `hex_color`, `percent_done`, `uid`, `created`, `updated`. Default is `id`."
+// @Param s query string false "Search tasks by their title, description, comments and the text of their attachments. Where the search term was found is returned in `search_matches`."
+// @Param sort_by query string false "The sorting parameter. You can pass this multiple times to get the tasks ordered by multiple different parametes, along with `order_by`. Possible values to sort by are `id`, `title`, `description`, `done`, `done_at`, `due_date`, `created_by_id`, `project_id`, `repeat_after`, `priority`, `start_date`, `end_date`, `hex_color`, `percent_done`, `estimate`, `uid`, `created`, `updated`, `votes`, `status_id`, `milestone_id`, `milestone_due_date`. Default is `id`."
 // @Param order_by query string false "The ordering parameter. Possible values to order by are `asc` or `desc`. Default is `asc`."
 // @Param filter query string false "The filter query to match tasks by. Check out https://vikunja.io/docs/filters for a full explanation of the feature."
 // @Param filter_timezone query string false "The time zone which should be used for date match (statements like "now" resolve to different actual times)"
 // @Param filter_include_nulls query string false "If set to true the result will include filtered fields whose value is set to `null`. Available values are `true` or `false`. Defaults to `false`."
+// @Param user_positions query string false "If set to true, tasks sorted by `position` are sorted by the positions the current user has set for them. Available values are `true` or `false`. Defaults to `false`."
 // @Security JWTKeyAuth
 // @Success 200 {array} models.Task "The tasks"
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{id}/tasks [get]
 func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, page int, perPage int) (result interface{}, resultCount int, totalItems int64, err error) {
+	return tf.readAll(s, a, search, page, perPage, nil)
+}
+
+// readAll gets all tasks for a collection. If facets is not nil, it is filled with the facets of all matching tasks.
+func (tf *TaskCollection) readAll(s *xorm.Session, a web.Auth, search string, page int, perPage int, facets *TaskSearchFacets) (result []*Task, resultCount int, totalItems int64, err error) {
 
 	// If the project id is < -1 this means we're dealing with a saved filter - in that case we get and populate the filter
 	// -1 is the favorites project which works as intended
@@ -157,6 +173,7 @@ func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, pa
 		sf.Filters.SortByArr = nil
 		sf.Filters.OrderBy = orderby
 		sf.Filters.OrderByArr = nil
+		sf.Filters.UserPositions = sf.Filters.UserPositions || tf.UserPositions
 
 		if sf.Filters.FilterTimezone == "" {
 			u, err := user.GetUserByID(s, a.GetID())
@@ -166,7 +183,7 @@ func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, pa
 			sf.Filters.FilterTimezone = u.Timezone
 		}
 
-		return sf.getTaskCollection().ReadAll(s, a, search, page, perPage)
+		return sf.getTaskCollection().readAll(s, a, search, page, perPage, facets)
 	}
 
 	taskopts, err := getTaskFilterOptsFromCollection(tf)
@@ -177,6 +194,11 @@ func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, pa
 	taskopts.search = search
 	taskopts.page = page
 	taskopts.perPage = perPage
+	taskopts.facets = facets
+
+	if _, isShare := a.(*LinkSharing); tf.UserPositions && !isShare {
+		taskopts.userPositionsFor = a.GetID()
+	}
 
 	shareAuth, is := a.(*LinkSharing)
 	if is {
@@ -184,6 +206,7 @@ func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, pa
 		if err != nil {
 			return nil, 0, 0, err
 		}
+		taskopts.pinnedFirst = true
 		return getTasksForProjects(s, []*Project{project}, a, taskopts)
 	}
 
@@ -194,7 +217,7 @@ func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, pa
 		projects, _, _, err = getRawProjectsForUser(
 			s,
 			&projectOptions{
-				user: &user.User{ID: a.GetID()},
+				user: &user.User{ID: a.GetID(), APITokenProjectIDs: getAPITokenProjectIDs(a)},
 				page: -1,
 			},
 		)
@@ -215,6 +238,8 @@ func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, pa
 			}
 		}
 		projects = []*Project{{ID: tf.ProjectID}}
+		// Pinned tasks only make sense in the views of their project
+		taskopts.pinnedFirst = tf.ProjectID > 0
 	}
 
 	return getTasksForProjects(s, projects, a, taskopts)
@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/ganigeorgiev/fexpr"
 
@@ -127,16 +128,37 @@ func parseFilterFromExpression(f fexpr.ExprGroup, loc *time.Location) (filter *t
 		return
 	}
 
+	if strings.HasPrefix(filter.field, customFieldFilterPrefix) {
+		filter.value, err = parseCustomFieldFilterValue(filter, value, loc)
+		return filter, err
+	}
+
+	if isLocationFilterField(filter.field) {
+		filter.value, err = parseLocationFilterValue(filter, value)
+		return filter, err
+	}
+
+	if isInteractionFilterField(filter.field) {
+		filter.value, err = parseInteractionFilterValue(filter, value, loc)
+		return filter, err
+	}
+
 	// Cast the field value to its native type
 	var reflectValue *reflect.StructField
 	if filter.field == "project" {
 		filter.field = "project_id"
 	}
+	if filter.field == "archived" {
+		filter.field = taskPropertyIsArchived
+	}
 	reflectValue, filter.value, err = getNativeValueForTaskField(filter.field, filter.comparator, value, loc)
 	if err != nil {
+		if IsErrInvalidTaskField(err) {
+			return nil, err
+		}
 		return nil, ErrInvalidTaskFilterValue{
-			Value: filter.field,
-			Field: value,
+			Value: value,
+			Field: filter.field,
 		}
 	}
 	if reflectValue != nil {
@@ -146,25 +168,57 @@ func parseFilterFromExpression(f fexpr.ExprGroup, loc *time.Location) (filter *t
 	return filter, nil
 }
 
-func getTaskFiltersFromFilterString(filter string, filterTimezone string) (filters []*taskFilter, err error) {
+var filterInValueRegex = regexp.MustCompile(`\?=\s+([^&|']+)`)
 
-	if filter == "" {
-		return
+// prepareFilterString rewrites the `in` operator and encloses its values in quotes so that the filter can be
+// parsed by fexpr. For each byte of the prepared filter, offsets contains the offset of the byte in the original
+// filter it originates from. The last entry of offsets is the length of the original filter.
+func prepareFilterString(filter string) (prepared string, offsets []int) {
+	filter = strings.ReplaceAll(filter, " in ", " ?= ")
+
+	var b strings.Builder
+	offsets = make([]int, 0, len(filter)+1)
+	write := func(s string, offset int) {
+		b.WriteString(s)
+		for i := 0; i < len(s); i++ {
+			offsets = append(offsets, offset+i)
+		}
 	}
 
-	filter = strings.ReplaceAll(filter, " in ", " ?= ")
+	last := 0
+	for _, match := range filterInValueRegex.FindAllStringIndex(filter, -1) {
+		write(filter[last:match[0]], last)
 
-	// Replaces all occurrences with in with a string so that it passes the filter
-	pattern := `\?=\s+([^&|']+)`
-	re := regexp.MustCompile(pattern)
+		value := strings.TrimPrefix(filter[match[0]:match[1]], "?=")
+		valueStart := match[0] + 2 + len(value) - len(strings.TrimLeftFunc(value, unicode.IsSpace))
+		value = strings.TrimSpace(value)
 
-	filter = re.ReplaceAllStringFunc(filter, func(match string) string {
-		value := strings.TrimSpace(strings.TrimPrefix(match, "?="))
-		value = strings.ReplaceAll(value, "'", `\'`)
-		enclosedValue := "'" + value + "'"
+		write("?= ", match[0])
+		write("'", valueStart)
+		for i := 0; i < len(value); i++ {
+			if value[i] == '\'' {
+				write(`\`, valueStart+i)
+			}
+			write(value[i:i+1], valueStart+i)
+		}
+		// The closing quote belongs to the last character of the value
+		write("'", valueStart+max(len(value)-1, 0))
 
-		return "?= " + enclosedValue
-	})
+		last = match[1]
+	}
+	write(filter[last:], last)
+	offsets = append(offsets, len(filter))
+
+	return b.String(), offsets
+}
+
+func getTaskFiltersFromFilterString(filter string, filterTimezone string) (filters []*taskFilter, err error) {
+
+	if filter == "" {
+		return
+	}
+
+	filter, _ = prepareFilterString(filter)
 
 	parsedFilter, err := fexpr.Parse(filter)
 	if err != nil {
@@ -178,7 +232,7 @@ func getTaskFiltersFromFilterString(filter string, filterTimezone string) (filte
 	if filterTimezone != "" {
 		loc, err = time.LoadLocation(filterTimezone)
 		if err != nil {
-			return
+			return nil, &ErrInvalidFilterTimezone{Timezone: filterTimezone}
 		}
 	}
 
@@ -249,12 +303,22 @@ func getValueForField(field reflect.StructField, rawValue string, loc *time.Loca
 		value, err = strconv.ParseInt(rawValue, 10, 64)
 	case reflect.Float64:
 		value, err = strconv.ParseFloat(rawValue, 64)
+	case reflect.Ptr:
+		// Optional values like the location of a task are stored as pointers, the filter value is the pointed to type
+		elem := field
+		elem.Type = field.Type.Elem()
+		return getValueForField(elem, rawValue, loc)
 	case reflect.String:
 		value = rawValue
 	case reflect.Bool:
 		value, err = strconv.ParseBool(rawValue)
 	case reflect.Struct:
 		if field.Type == schemas.TimeType {
+			if relative, is := parseRelativeDate(rawValue, time.Now().In(loc)); is {
+				value = relative
+				break
+			}
+
 			var t datemath.Expression
 			t, err = datemath.Parse(rawValue)
 			if err == nil {
@@ -286,14 +350,26 @@ func getValueForField(field reflect.StructField, rawValue string, loc *time.Loca
 	return
 }
 
+// splitFilterValues splits a list of filter values like "1,2,3" or "[1, 2, 3]" into its parts.
+func splitFilterValues(value string) []string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = value[1 : len(value)-1]
+	}
+
+	vals := strings.Split(value, ",")
+	for i, val := range vals {
+		vals[i] = strings.TrimSpace(val)
+	}
+	return vals
+}
+
 func getNativeValueForTaskField(fieldName string, comparator taskFilterComparator, value string, loc *time.Location) (reflectField *reflect.StructField, nativeValue interface{}, err error) {
 
 	realFieldName := strings.ReplaceAll(strcase.ToCamel(fieldName), "Id", "ID")
 
 	if realFieldName == "Assignees" {
-		vals := strings.Split(value, ",")
-		valueSlice := append([]string{}, vals...)
-		return nil, valueSlice, nil
+		return nil, splitFilterValues(value), nil
 	}
 
 	field, ok := reflect.TypeOf(&Task{}).Elem().FieldByName(realFieldName)
@@ -309,9 +385,8 @@ func getNativeValueForTaskField(fieldName string, comparator taskFilterComparato
 	}
 
 	if comparator == taskFilterComparatorIn {
-		vals := strings.Split(value, ",")
 		valueSlice := []interface{}{}
-		for _, val := range vals {
+		for _, val := range splitFilterValues(value) {
 			v, err := getValueForField(field, val, loc)
 			if err != nil {
 				return nil, nil, err
@@ -0,0 +1,169 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+var relativeDateWeekdays = map[string]time.Weekday{
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+}
+
+// parseRelativeDate resolves human-readable date expressions like "end of week", "next monday"
+// or "in 3 days" relative to now. The result is in the same location as now, weeks start on monday.
+// If the expression is not a known relative date, the second return value is false.
+func parseRelativeDate(raw string, now time.Time) (time.Time, bool) {
+	parts := strings.Fields(strings.ToLower(raw))
+	if len(parts) == 0 {
+		return time.Time{}, false
+	}
+
+	switch strings.Join(parts, " ") {
+	case "now":
+		return now, true
+	case "today":
+		return startOfPeriod(now, "day"), true
+	case "tomorrow":
+		return startOfPeriod(now, "day").AddDate(0, 0, 1), true
+	case "yesterday":
+		return startOfPeriod(now, "day").AddDate(0, 0, -1), true
+	}
+
+	// start of [this|next|last] <period>, end of [this|next|last] <period>
+	if len(parts) >= 3 && (parts[0] == "start" || parts[0] == "end") && parts[1] == "of" {
+		offset := 0
+		period := parts[2]
+		if len(parts) == 4 {
+			switch parts[2] {
+			case "this":
+			case "next":
+				offset = 1
+			case "last":
+				offset = -1
+			default:
+				return time.Time{}, false
+			}
+			period = parts[3]
+		}
+		if len(parts) > 4 {
+			return time.Time{}, false
+		}
+		if period == "today" {
+			period = "day"
+		}
+
+		if !isRelativeDatePeriod(period) {
+			return time.Time{}, false
+		}
+
+		start := addPeriods(startOfPeriod(now, period), period, offset)
+		if parts[0] == "start" {
+			return start, true
+		}
+		return addPeriods(start, period, 1).Add(-time.Nanosecond), true
+	}
+
+	// next <weekday>, last <weekday>, this <weekday>
+	if len(parts) == 2 {
+		if weekday, exists := relativeDateWeekdays[parts[1]]; exists {
+			today := startOfPeriod(now, "day")
+			diff := int(weekday - today.Weekday())
+			switch parts[0] {
+			case "next":
+				if diff <= 0 {
+					diff += 7
+				}
+			case "last":
+				if diff >= 0 {
+					diff -= 7
+				}
+			case "this":
+				weekStart := startOfPeriod(now, "week")
+				return weekStart.AddDate(0, 0, (int(weekday)+6)%7), true
+			default:
+				return time.Time{}, false
+			}
+			return today.AddDate(0, 0, diff), true
+		}
+	}
+
+	// in <n> <periods>, <n> <periods> ago
+	if len(parts) == 3 && (parts[0] == "in" || parts[2] == "ago") {
+		amount, unit := parts[1], parts[2]
+		sign := 1
+		if parts[2] == "ago" {
+			amount, unit = parts[0], parts[1]
+			sign = -1
+		}
+		n, err := strconv.Atoi(amount)
+		if err != nil {
+			return time.Time{}, false
+		}
+		period := strings.TrimSuffix(unit, "s")
+		if !isRelativeDatePeriod(period) {
+			return time.Time{}, false
+		}
+		return addPeriods(now, period, sign*n), true
+	}
+
+	return time.Time{}, false
+}
+
+func isRelativeDatePeriod(period string) bool {
+	switch period {
+	case "day", "week", "month", "year":
+		return true
+	}
+	return false
+}
+
+func startOfPeriod(t time.Time, period string) time.Time {
+	y, m, d := t.Date()
+	switch period {
+	case "week":
+		day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	case "year":
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func addPeriods(t time.Time, period string, n int) time.Time {
+	switch period {
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	case "year":
+		return t.AddDate(n, 0, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
@@ -0,0 +1,93 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelativeDate(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// A wednesday
+	now := time.Date(2024, time.March, 13, 15, 30, 0, 0, loc)
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+	endOfDay := func(y int, m time.Month, d int) time.Time {
+		return day(y, m, d).AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	tests := map[string]time.Time{
+		"now":                now,
+		"today":              day(2024, time.March, 13),
+		"Tomorrow":           day(2024, time.March, 14),
+		"yesterday":          day(2024, time.March, 12),
+		"end of day":         endOfDay(2024, time.March, 13),
+		"start of week":      day(2024, time.March, 11),
+		"end of week":        endOfDay(2024, time.March, 17),
+		"end of  next week":  endOfDay(2024, time.March, 24),
+		"start of last week": day(2024, time.March, 4),
+		"start of month":     day(2024, time.March, 1),
+		"end of month":       endOfDay(2024, time.March, 31),
+		"end of next month":  endOfDay(2024, time.April, 30),
+		"start of year":      day(2024, time.January, 1),
+		"end of year":        endOfDay(2024, time.December, 31),
+		"next monday":        day(2024, time.March, 18),
+		"next wednesday":     day(2024, time.March, 20),
+		"next friday":        day(2024, time.March, 15),
+		"last wednesday":     day(2024, time.March, 6),
+		"last monday":        day(2024, time.March, 11),
+		"this sunday":        day(2024, time.March, 17),
+		"in 3 days":          now.AddDate(0, 0, 3),
+		"in 1 week":          now.AddDate(0, 0, 7),
+		"2 months ago":       now.AddDate(0, -2, 0),
+	}
+
+	for expression, expected := range tests {
+		t.Run(expression, func(t *testing.T) {
+			result, is := parseRelativeDate(expression, now)
+			assert.True(t, is)
+			assert.Equal(t, expected, result)
+		})
+	}
+
+	t.Run("unknown expressions", func(t *testing.T) {
+		for _, expression := range []string{"", "2024-03-13", "next", "end of fortnight", "in many days", "next funday", "now/d"} {
+			_, is := parseRelativeDate(expression, now)
+			assert.False(t, is, expression)
+		}
+	})
+	t.Run("resolved in filter timezone", func(t *testing.T) {
+		filters, err := getTaskFiltersFromFilterString(`due_date < "end of day"`, "Asia/Tokyo")
+		require.NoError(t, err)
+		require.Len(t, filters, 1)
+
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		value, is := filters[0].value.(time.Time)
+		require.True(t, is)
+		inTokyo := value.In(tokyo)
+		assert.Equal(t, 23, inTokyo.Hour())
+		assert.Equal(t, 59, inTokyo.Minute())
+	})
+}
@@ -0,0 +1,326 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"code.vikunja.io/web"
+
+	"github.com/ganigeorgiev/fexpr"
+)
+
+// FilterExplanationRequest holds a filter query which should be explained
+type FilterExplanationRequest struct {
+	// The filter query to explain.
+	Filter string `json:"filter"`
+	// The time zone which should be used to resolve dates. Defaults to the time zone of the server.
+	FilterTimezone string `json:"filter_timezone"`
+}
+
+// FilterExplanation is the structured representation of a filter query
+type FilterExplanation struct {
+	// Whether the filter query is valid and can be used to filter tasks.
+	Valid bool `json:"valid"`
+	// All expressions of the filter query in the order they appear in it.
+	Expressions []*FilterExplanationExpression `json:"expressions"`
+	// The first error in the filter query, if it is not valid.
+	Error *FilterExplanationError `json:"error,omitempty"`
+}
+
+// FilterExplanationExpression is a single comparison or a group of expressions in a filter query
+type FilterExplanationExpression struct {
+	// How this expression is joined with the previous one. Either `&&` or `||`, empty for the first expression.
+	Join string `json:"join,omitempty"`
+	// The task field this expression compares.
+	Field string `json:"field,omitempty"`
+	// The comparator of this expression. One of `=`, `!=`, `>`, `>=`, `<`, `<=`, `like` or `in`.
+	Comparator string `json:"comparator,omitempty"`
+	// The value as written in the filter query.
+	Value string `json:"value,omitempty"`
+	// The value used to filter tasks. Relative dates like `now+7d` or `end of week` are resolved to actual dates.
+	ResolvedValue interface{} `json:"resolved_value,omitempty"`
+	// If this expression is a group in parentheses, the expressions in it.
+	Expressions []*FilterExplanationExpression `json:"expressions,omitempty"`
+	// The position of the first character of this expression in the filter query, starting at 0.
+	Start int `json:"start"`
+	// The position after the last character of this expression in the filter query.
+	End int `json:"end"`
+}
+
+// FilterExplanationError describes why a filter query is invalid
+type FilterExplanationError struct {
+	// The error code, if the error has one. See the error docs for all codes.
+	Code int `json:"code,omitempty"`
+	// A description of the error.
+	Message string `json:"message"`
+	// The position of the first character of the invalid part in the filter query, starting at 0.
+	Start int `json:"start"`
+	// The position after the last character of the invalid part in the filter query.
+	End int `json:"end"`
+}
+
+type filterExplanationToken struct {
+	fexpr.Token
+	// Byte offsets in the prepared filter
+	start int
+	end   int
+}
+
+type filterExplainer struct {
+	original string
+	prepared string
+	offsets  []int
+	loc      *time.Location
+}
+
+// ExplainFilter parses a filter query and returns its structured representation. If the query is invalid, the
+// explanation contains the first error and its position. Positions are counted in characters of the original query.
+func ExplainFilter(filter string, filterTimezone string) (explanation *FilterExplanation, err error) {
+	fe := &filterExplainer{original: filter}
+
+	if filterTimezone != "" {
+		fe.loc, err = time.LoadLocation(filterTimezone)
+		if err != nil {
+			return nil, &ErrInvalidFilterTimezone{Timezone: filterTimezone}
+		}
+	}
+
+	explanation = &FilterExplanation{
+		Valid:       true,
+		Expressions: []*FilterExplanationExpression{},
+	}
+	if filter == "" {
+		return
+	}
+
+	fe.prepared, fe.offsets = prepareFilterString(filter)
+
+	expressions, explanationErr := fe.explain(0, len(fe.prepared))
+	if explanationErr != nil {
+		explanation.Valid = false
+		explanation.Error = explanationErr
+		return explanation, nil
+	}
+
+	explanation.Expressions = expressions
+	return explanation, nil
+}
+
+// position converts a byte offset in the prepared filter to a character offset in the original filter
+func (fe *filterExplainer) position(offset int) int {
+	return utf8.RuneCountInString(fe.original[:fe.offsets[offset]])
+}
+
+// endPosition converts an exclusive end byte offset in the prepared filter to a character offset in the original filter
+func (fe *filterExplainer) endPosition(start, end int) int {
+	if end <= start {
+		return fe.position(end)
+	}
+	return utf8.RuneCountInString(fe.original[:fe.offsets[end-1]]) + 1
+}
+
+func (fe *filterExplainer) error(err error, start, end int) *FilterExplanationError {
+	e := &FilterExplanationError{
+		Message: err.Error(),
+		Start:   fe.position(start),
+		End:     fe.endPosition(start, end),
+	}
+	if httpErr, is := err.(web.HTTPErrorProcessor); is {
+		details := httpErr.HTTPError()
+		e.Code = details.Code
+		e.Message = details.Message
+	}
+	return e
+}
+
+// scan returns all tokens between start and end of the prepared filter, along with their positions.
+func (fe *filterExplainer) scan(start, end int) (tokens []*filterExplanationToken, err *FilterExplanationError) {
+	text := fe.prepared[start:end]
+	sr := strings.NewReader(text)
+	// fexpr reuses a bufio.Reader which is at least as large as its default size instead of wrapping it again,
+	// which allows to know how much of the text was consumed for each token.
+	br := bufio.NewReader(sr)
+	scanner := fexpr.NewScanner(br)
+	consumed := func() int {
+		return start + len(text) - sr.Len() - br.Buffered()
+	}
+
+	for {
+		tokenStart := consumed()
+		t, scanErr := scanner.Scan()
+		tokenEnd := consumed()
+		if scanErr != nil {
+			return nil, fe.error(scanErr, tokenStart, tokenEnd)
+		}
+
+		switch t.Type {
+		case fexpr.TokenEOF:
+			return tokens, nil
+		case fexpr.TokenWS, fexpr.TokenComment:
+			continue
+		}
+
+		tokens = append(tokens, &filterExplanationToken{Token: t, start: tokenStart, end: tokenEnd})
+	}
+}
+
+func isFilterOperand(t *filterExplanationToken) bool {
+	return t.Type == fexpr.TokenIdentifier || t.Type == fexpr.TokenText || t.Type == fexpr.TokenNumber
+}
+
+// explain mirrors fexpr.Parse but keeps track of the position of every expression.
+func (fe *filterExplainer) explain(start, end int) (expressions []*FilterExplanationExpression, err *FilterExplanationError) {
+	tokens, err := fe.scan(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		stepLeft = iota
+		stepSign
+		stepRight
+		stepJoin
+	)
+
+	expressions = []*FilterExplanationExpression{}
+	step := stepLeft
+	join := fexpr.JoinAnd
+	var left, sign *filterExplanationToken
+
+	for _, t := range tokens {
+		if t.Type == fexpr.TokenGroup && step == stepLeft {
+			// The group token spans the parentheses
+			groupExpressions, err := fe.explain(t.start+1, t.end-1)
+			if err != nil {
+				return nil, err
+			}
+			expressions = append(expressions, &FilterExplanationExpression{
+				Join:        fe.join(expressions, join),
+				Expressions: groupExpressions,
+				Start:       fe.position(t.start),
+				End:         fe.endPosition(t.start, t.end),
+			})
+			step = stepJoin
+			continue
+		}
+
+		switch step {
+		case stepLeft:
+			if !isFilterOperand(t) {
+				return nil, fe.error(fmt.Errorf("expected left operand (identifier, text or number), got %q (%s)", t.Literal, t.Type), t.start, t.end)
+			}
+			left = t
+			step = stepSign
+		case stepSign:
+			if t.Type != fexpr.TokenSign {
+				return nil, fe.error(fmt.Errorf("expected a sign operator, got %q (%s)", t.Literal, t.Type), t.start, t.end)
+			}
+			sign = t
+			step = stepRight
+		case stepRight:
+			if !isFilterOperand(t) {
+				return nil, fe.error(fmt.Errorf("expected right operand (identifier, text or number), got %q (%s)", t.Literal, t.Type), t.start, t.end)
+			}
+			expression, err := fe.expression(fe.join(expressions, join), join, left, sign, t)
+			if err != nil {
+				return nil, err
+			}
+			expressions = append(expressions, expression)
+			step = stepJoin
+		case stepJoin:
+			if t.Type != fexpr.TokenJoin {
+				return nil, fe.error(fmt.Errorf("expected && or ||, got %q (%s)", t.Literal, t.Type), t.start, t.end)
+			}
+			join = fexpr.JoinAnd
+			if t.Literal == string(fexpr.JoinOr) {
+				join = fexpr.JoinOr
+			}
+			step = stepLeft
+		}
+	}
+
+	if step != stepJoin {
+		if len(expressions) == 0 && left == nil {
+			return nil, fe.error(fexpr.ErrEmpty, start, end)
+		}
+		return nil, fe.error(fexpr.ErrIncomplete, end, end)
+	}
+
+	return expressions, nil
+}
+
+func (fe *filterExplainer) join(previous []*FilterExplanationExpression, join fexpr.JoinOp) string {
+	if len(previous) == 0 {
+		return ""
+	}
+	return string(join)
+}
+
+// expression validates a single comparison and resolves its value the same way it is done when filtering tasks.
+func (fe *filterExplainer) expression(joinString string, join fexpr.JoinOp, left, sign, right *filterExplanationToken) (*FilterExplanationExpression, *FilterExplanationError) {
+	filter, err := parseFilterFromExpression(fexpr.ExprGroup{
+		Join: join,
+		Item: fexpr.Expr{
+			Left:  left.Token,
+			Op:    fexpr.SignOp(sign.Literal),
+			Right: right.Token,
+		},
+	}, fe.loc)
+	if err != nil {
+		invalid := []*filterExplanationToken{left, right}
+		switch {
+		case IsErrInvalidTaskField(err):
+			invalid = []*filterExplanationToken{left, left}
+		case IsErrInvalidTaskFilterComparator(err):
+			invalid = []*filterExplanationToken{sign, sign}
+		case IsErrInvalidTaskFilterValue(err):
+			invalid = []*filterExplanationToken{right, right}
+		}
+		return nil, fe.error(err, invalid[0].start, invalid[1].end)
+	}
+
+	return &FilterExplanationExpression{
+		Join:          joinString,
+		Field:         filter.field,
+		Comparator:    string(filter.comparator),
+		Value:         right.Literal,
+		ResolvedValue: explainFilterValue(filter.value),
+		Start:         fe.position(left.start),
+		End:           fe.endPosition(left.start, right.end),
+	}, nil
+}
+
+func explainFilterValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *customFieldFilter:
+		if len(v.dates) > 0 {
+			return v.dates
+		}
+		return v.strings
+	case *locationFilter:
+		return nil
+	case *interactionFilter:
+		return v.value
+	default:
+		return v
+	}
+}
@@ -0,0 +1,177 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainFilter(t *testing.T) {
+	t.Run("empty filter", func(t *testing.T) {
+		explanation, err := ExplainFilter("", "")
+		require.NoError(t, err)
+		assert.True(t, explanation.Valid)
+		assert.Empty(t, explanation.Expressions)
+		assert.Nil(t, explanation.Error)
+	})
+	t.Run("simple expressions", func(t *testing.T) {
+		explanation, err := ExplainFilter("done = false && priority >= 3", "")
+		require.NoError(t, err)
+		assert.True(t, explanation.Valid)
+		require.Len(t, explanation.Expressions, 2)
+
+		assert.Equal(t, &FilterExplanationExpression{
+			Field:         "done",
+			Comparator:    "=",
+			Value:         "false",
+			ResolvedValue: false,
+			Start:         0,
+			End:           12,
+		}, explanation.Expressions[0])
+		assert.Equal(t, &FilterExplanationExpression{
+			Join:          "&&",
+			Field:         "priority",
+			Comparator:    ">=",
+			Value:         "3",
+			ResolvedValue: int64(3),
+			Start:         16,
+			End:           29,
+		}, explanation.Expressions[1])
+	})
+	t.Run("in with a list", func(t *testing.T) {
+		explanation, err := ExplainFilter("labels in [4, 5] || id in 1, 2", "")
+		require.NoError(t, err)
+		assert.True(t, explanation.Valid)
+		require.Len(t, explanation.Expressions, 2)
+
+		assert.Equal(t, "labels", explanation.Expressions[0].Field)
+		assert.Equal(t, "in", explanation.Expressions[0].Comparator)
+		assert.Equal(t, []interface{}{int64(4), int64(5)}, explanation.Expressions[0].ResolvedValue)
+		assert.Equal(t, 0, explanation.Expressions[0].Start)
+		assert.Equal(t, 16, explanation.Expressions[0].End)
+
+		assert.Equal(t, "||", explanation.Expressions[1].Join)
+		assert.Equal(t, []interface{}{int64(1), int64(2)}, explanation.Expressions[1].ResolvedValue)
+		assert.Equal(t, 20, explanation.Expressions[1].Start)
+		assert.Equal(t, 30, explanation.Expressions[1].End)
+	})
+	t.Run("resolves relative dates in the filter timezone", func(t *testing.T) {
+		explanation, err := ExplainFilter(`due_date < "end of day"`, "Asia/Tokyo")
+		require.NoError(t, err)
+		assert.True(t, explanation.Valid)
+		require.Len(t, explanation.Expressions, 1)
+
+		resolved, is := explanation.Expressions[0].ResolvedValue.(time.Time)
+		require.True(t, is)
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		assert.Equal(t, 23, resolved.In(tokyo).Hour())
+		assert.Equal(t, "end of day", explanation.Expressions[0].Value)
+	})
+	t.Run("groups", func(t *testing.T) {
+		explanation, err := ExplainFilter("(done = true || priority = 1) && id = 1", "")
+		require.NoError(t, err)
+		assert.True(t, explanation.Valid)
+		require.Len(t, explanation.Expressions, 2)
+
+		group := explanation.Expressions[0]
+		assert.Equal(t, 0, group.Start)
+		assert.Equal(t, 29, group.End)
+		require.Len(t, group.Expressions, 2)
+		assert.Equal(t, "done", group.Expressions[0].Field)
+		assert.Equal(t, 1, group.Expressions[0].Start)
+		assert.Equal(t, "||", group.Expressions[1].Join)
+		assert.Equal(t, 16, group.Expressions[1].Start)
+		assert.Equal(t, 28, group.Expressions[1].End)
+
+		assert.Equal(t, "&&", explanation.Expressions[1].Join)
+		assert.Equal(t, "id", explanation.Expressions[1].Field)
+	})
+	t.Run("incomplete filter", func(t *testing.T) {
+		explanation, err := ExplainFilter("done = false && ", "")
+		require.NoError(t, err)
+		assert.False(t, explanation.Valid)
+		require.NotNil(t, explanation.Error)
+		assert.Equal(t, 16, explanation.Error.Start)
+		assert.Equal(t, 16, explanation.Error.End)
+	})
+	t.Run("missing sign operator", func(t *testing.T) {
+		explanation, err := ExplainFilter("done false", "")
+		require.NoError(t, err)
+		assert.False(t, explanation.Valid)
+		require.NotNil(t, explanation.Error)
+		assert.Equal(t, 5, explanation.Error.Start)
+		assert.Equal(t, 10, explanation.Error.End)
+	})
+	t.Run("unterminated text", func(t *testing.T) {
+		explanation, err := ExplainFilter("title = 'abc", "")
+		require.NoError(t, err)
+		assert.False(t, explanation.Valid)
+		require.NotNil(t, explanation.Error)
+		assert.Equal(t, 8, explanation.Error.Start)
+		assert.Equal(t, 12, explanation.Error.End)
+	})
+	t.Run("invalid field", func(t *testing.T) {
+		explanation, err := ExplainFilter("done = true && foo = 1", "")
+		require.NoError(t, err)
+		assert.False(t, explanation.Valid)
+		require.NotNil(t, explanation.Error)
+		assert.Equal(t, ErrCodeInvalidTaskField, explanation.Error.Code)
+		assert.Equal(t, 15, explanation.Error.Start)
+		assert.Equal(t, 18, explanation.Error.End)
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		explanation, err := ExplainFilter("priority = abc", "")
+		require.NoError(t, err)
+		assert.False(t, explanation.Valid)
+		require.NotNil(t, explanation.Error)
+		assert.Equal(t, ErrCodeInvalidTaskFilterValue, explanation.Error.Code)
+		assert.Equal(t, 11, explanation.Error.Start)
+		assert.Equal(t, 14, explanation.Error.End)
+	})
+	t.Run("invalid value in a group", func(t *testing.T) {
+		explanation, err := ExplainFilter("done = true && (id = 1 || priority = abc)", "")
+		require.NoError(t, err)
+		assert.False(t, explanation.Valid)
+		require.NotNil(t, explanation.Error)
+		assert.Equal(t, 37, explanation.Error.Start)
+		assert.Equal(t, 40, explanation.Error.End)
+	})
+	t.Run("positions are counted in characters", func(t *testing.T) {
+		explanation, err := ExplainFilter("title = 'äöü' && foo = 1", "")
+		require.NoError(t, err)
+		assert.False(t, explanation.Valid)
+		require.NotNil(t, explanation.Error)
+		assert.Equal(t, 17, explanation.Error.Start)
+		assert.Equal(t, 20, explanation.Error.End)
+	})
+	t.Run("only whitespace", func(t *testing.T) {
+		explanation, err := ExplainFilter("   ", "")
+		require.NoError(t, err)
+		assert.False(t, explanation.Valid)
+		require.NotNil(t, explanation.Error)
+	})
+	t.Run("invalid timezone", func(t *testing.T) {
+		_, err := ExplainFilter("done = true", "Mars/Olympus_Mons")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidFilterTimezone(err))
+	})
+}
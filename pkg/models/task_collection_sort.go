@@ -40,6 +40,7 @@ const (
 	taskPropertyEndDate        string = "end_date"
 	taskPropertyHexColor       string = "hex_color"
 	taskPropertyPercentDone    string = "percent_done"
+	taskPropertyEstimate       string = "estimate"
 	taskPropertyUID            string = "uid"
 	taskPropertyCreated        string = "created"
 	taskPropertyUpdated        string = "updated"
@@ -47,6 +48,8 @@ const (
 	taskPropertyKanbanPosition string = "kanban_position"
 	taskPropertyBucketID       string = "bucket_id"
 	taskPropertyIndex          string = "index"
+	taskPropertyIsArchived     string = "is_archived"
+	taskPropertyStatusID       string = "status_id"
 )
 
 const (
@@ -104,6 +104,14 @@ func TestTaskCollection_ReadAll(t *testing.T) {
 		Labels: []*Label{
 			label4,
 		},
+		CustomFields: map[int64]interface{}{
+			1: "ACME",
+			2: float64(5),
+		},
+		ChecklistItemsTotal: 3,
+		ChecklistItemsDone:  1,
+		Votes:               2,
+		Voted:               true,
 		RelatedTasks: map[RelationKind][]*Task{
 			RelationKindSubtask: {
 				{
@@ -175,6 +183,10 @@ func TestTaskCollection_ReadAll(t *testing.T) {
 		Labels: []*Label{
 			label4,
 		},
+		CustomFields: map[int64]interface{}{
+			2: float64(8),
+			4: []string{"web", "mobile"},
+		},
 		RelatedTasks: map[RelationKind][]*Task{},
 		Reminders: []*TaskReminder{
 			{
@@ -200,6 +212,7 @@ func TestTaskCollection_ReadAll(t *testing.T) {
 		Updated:      time.Unix(1543626724, 0).In(loc),
 		Priority:     100,
 		BucketID:     2,
+		Votes:        1,
 	}
 	task4 := &Task{
 		ID:           4,
@@ -1199,6 +1212,35 @@ func TestTaskCollection_ReadAll(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "filter labels in list",
+			fields: fields{
+				Filter: "labels in [4, 5]",
+			},
+			args: defaultArgs,
+			want: []*Task{
+				task1,
+				task2,
+				task35,
+			},
+			wantErr: false,
+		},
+		{
+			name: "filter bucket_id in list",
+			fields: fields{
+				Filter: "bucket_id in [2, 3] && project_id = 1",
+			},
+			args: defaultArgs,
+			want: []*Task{
+				task3,
+				task4,
+				task5,
+				task6,
+				task7,
+				task8,
+			},
+			wantErr: false,
+		},
 		{
 			name: "filter project_id",
 			fields: fields{
@@ -1464,3 +1506,112 @@ func TestTaskCollection_ReadAll(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskCollection_ReadAll_Estimate(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	setTaskEstimates(t, s, map[int64]int64{3: 3600, 4: 1800})
+
+	tc := &TaskCollection{
+		ProjectID: 1,
+		Filter:    "estimate > 1000",
+		SortBy:    []string{"estimate"},
+		OrderBy:   []string{"desc"},
+	}
+	result, _, _, err := tc.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+	if err != nil {
+		t.Fatalf("Task.ReadAll() error = %v", err)
+	}
+
+	tasks := result.([]*Task)
+	if len(tasks) != 2 || tasks[0].ID != 3 || tasks[1].ID != 4 {
+		t.Errorf("Expected tasks 3 and 4, got %v", tasks)
+	}
+}
+
+func TestTaskCollection_ReadAll_Archived(t *testing.T) {
+	getTaskIDs := func(t *testing.T, filter string) []int64 {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 3).Cols("is_archived").Update(&Task{IsArchived: true})
+		if err != nil {
+			t.Fatalf("Could not archive task: %v", err)
+		}
+
+		tc := &TaskCollection{
+			ProjectID: 1,
+			Filter:    filter,
+		}
+		result, _, _, err := tc.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+		if err != nil {
+			t.Fatalf("Task.ReadAll() error = %v", err)
+		}
+
+		ids := []int64{}
+		for _, task := range result.([]*Task) {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	t.Run("hidden by default", func(t *testing.T) {
+		ids := getTaskIDs(t, "")
+		if len(ids) == 0 {
+			t.Fatalf("Expected tasks, got none")
+		}
+		for _, id := range ids {
+			if id == 3 {
+				t.Errorf("Expected archived task 3 to be hidden, got %v", ids)
+			}
+		}
+	})
+	t.Run("hidden when searching", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 3).Cols("is_archived").Update(&Task{IsArchived: true})
+		if err != nil {
+			t.Fatalf("Could not archive task: %v", err)
+		}
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, _, _, err := tc.ReadAll(s, &user.User{ID: 1}, "task #3 high prio", 0, 50)
+		if err != nil {
+			t.Fatalf("Task.ReadAll() error = %v", err)
+		}
+		if len(result.([]*Task)) != 0 {
+			t.Errorf("Expected no tasks, got %v", result)
+		}
+	})
+	t.Run("only archived", func(t *testing.T) {
+		ids := getTaskIDs(t, "archived = true")
+		if len(ids) != 1 || ids[0] != 3 {
+			t.Errorf("Expected only task 3, got %v", ids)
+		}
+	})
+	t.Run("explicitly unarchived", func(t *testing.T) {
+		ids := getTaskIDs(t, "is_archived = false")
+		for _, id := range ids {
+			if id == 3 {
+				t.Errorf("Expected archived task 3 to be hidden, got %v", ids)
+			}
+		}
+	})
+	t.Run("nested archived filter", func(t *testing.T) {
+		ids := getTaskIDs(t, "(archived = true || done = true)")
+		var found bool
+		for _, id := range ids {
+			if id == 3 {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected archived task 3 to be included, got %v", ids)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TaskCommentRevision holds the content a comment had before it was edited
+type TaskCommentRevision struct {
+	// The unique, numeric id of this revision.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The comment this revision belongs to.
+	CommentID int64 `xorm:"bigint not null INDEX" json:"comment_id" param:"commentid"`
+	TaskID    int64 `xorm:"-" json:"-" param:"task"`
+	// The content of the comment before the edit.
+	Comment string `xorm:"text not null" json:"comment"`
+
+	// The user who edited the comment.
+	Editor   *user.User `xorm:"-" json:"editor"`
+	EditorID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when the comment was edited. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name for the task comment revisions
+func (*TaskCommentRevision) TableName() string {
+	return "task_comment_revisions"
+}
+
+// CanRead checks if the user can see the revisions of a comment
+func (tcr *TaskCommentRevision) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	tc := &TaskComment{ID: tcr.CommentID, TaskID: tcr.TaskID}
+	return tc.CanRead(s, a)
+}
+
+// ReadAll returns all revisions of a comment
+// @Summary Get the edit history of a task comment
+// @Description Returns all previous versions of a comment with the user who edited it, newest first.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param commentID path int true "Comment ID"
+// @Success 200 {array} models.TaskCommentRevision "The revisions of the comment."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task comment was not found."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/comments/{commentID}/revisions [get]
+func (tcr *TaskCommentRevision) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	canRead, _, err := tcr.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	tc := &TaskComment{ID: tcr.CommentID}
+	err = getTaskCommentSimple(s, tc)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if tc.TaskID != tcr.TaskID {
+		return nil, 0, 0, ErrTaskCommentDoesNotExist{ID: tcr.CommentID, TaskID: tcr.TaskID}
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	revisions := []*TaskCommentRevision{}
+	query := s.
+		Where("comment_id = ?", tcr.CommentID).
+		OrderBy("created desc, id desc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&revisions)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	editorIDs := make([]int64, 0, len(revisions))
+	for _, r := range revisions {
+		editorIDs = append(editorIDs, r.EditorID)
+	}
+	editors, err := getUsersOrLinkSharesFromIDs(s, editorIDs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for _, r := range revisions {
+		r.Editor = editors[r.EditorID]
+		r.TaskID = tcr.TaskID
+	}
+
+	numberOfTotalItems, err = s.
+		Where("comment_id = ?", tcr.CommentID).
+		Count(&TaskCommentRevision{})
+	return revisions, len(revisions), numberOfTotalItems, err
+}
+
+// addEditedToComments marks all comments which have at least one revision as edited
+func addEditedToComments(s *xorm.Session, comments []*TaskComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	commentIDs := make([]int64, 0, len(comments))
+	for _, c := range comments {
+		commentIDs = append(commentIDs, c.ID)
+	}
+
+	edited := []int64{}
+	err := s.
+		Table("task_comment_revisions").
+		In("comment_id", commentIDs).
+		Distinct("comment_id").
+		Find(&edited)
+	if err != nil {
+		return err
+	}
+
+	editedMap := make(map[int64]bool, len(edited))
+	for _, id := range edited {
+		editedMap[id] = true
+	}
+	for _, c := range comments {
+		c.Edited = editedMap[c.ID]
+	}
+
+	return nil
+}
+
+func deleteRevisionsForComments(s *xorm.Session, commentIDs []int64) error {
+	if len(commentIDs) == 0 {
+		return nil
+	}
+
+	_, err := s.Where(builder.In("comment_id", commentIDs)).Delete(&TaskCommentRevision{})
+	return err
+}
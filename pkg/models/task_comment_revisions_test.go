@@ -0,0 +1,144 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCommentRevision_ReadAll(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tcr := &TaskCommentRevision{CommentID: 1, TaskID: 1}
+		result, resultCount, total, err := tcr.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		revisions := result.([]*TaskCommentRevision)
+		assert.Equal(t, 2, resultCount)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, "Lorem Ipsum Dolor", revisions[0].Comment)
+		assert.Equal(t, "Lorem Ipsum", revisions[1].Comment)
+		require.NotNil(t, revisions[0].Editor)
+		assert.Equal(t, int64(1), revisions[0].Editor.ID)
+	})
+	t.Run("comment of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tcr := &TaskCommentRevision{CommentID: 1, TaskID: 2}
+		_, _, _, err := tcr.ReadAll(s, u, "", 0, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskCommentDoesNotExist(err))
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tcr := &TaskCommentRevision{CommentID: 1, TaskID: 1}
+		_, _, _, err := tcr.ReadAll(s, &user.User{ID: 13}, "", 0, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
+
+func TestTaskComment_Update_Revisions(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("stores the old content", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskComment{
+			ID:      1,
+			TaskID:  1,
+			Comment: "testing",
+		}
+		err := tc.Update(s, u)
+		require.NoError(t, err)
+		assert.True(t, tc.Edited)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_comment_revisions", map[string]interface{}{
+			"comment_id": 1,
+			"comment":    "Lorem Ipsum Dolor Sit Amet",
+			"editor_id":  1,
+		}, false)
+	})
+	t.Run("unchanged content", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskComment{
+			ID:      2,
+			TaskID:  14,
+			Comment: "comment 2",
+		}
+		err := tc.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_comment_revisions", map[string]interface{}{
+			"comment_id": 2,
+		})
+	})
+	t.Run("marks comments as edited", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskComment{ID: 1, TaskID: 1}
+		err := tc.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.True(t, tc.Edited)
+
+		tc = &TaskComment{ID: 2, TaskID: 14}
+		err = tc.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.False(t, tc.Edited)
+	})
+	t.Run("deleting the comment removes the revisions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskComment{ID: 1, TaskID: 1}
+		err := tc.Delete(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_comment_revisions", map[string]interface{}{
+			"comment_id": 1,
+		})
+	})
+}
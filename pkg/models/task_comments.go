@@ -38,6 +38,8 @@ type TaskComment struct {
 	TaskID   int64      `xorm:"not null" json:"-" param:"task"`
 
 	Reactions ReactionMap `xorm:"-" json:"reactions"`
+	// Whether the comment was edited after it was created. Use the revisions endpoint to get the previous versions.
+	Edited bool `xorm:"-" json:"edited"`
 
 	Created time.Time `xorm:"created" json:"created"`
 	Updated time.Time `xorm:"updated" json:"updated"`
@@ -82,7 +84,7 @@ func (tc *TaskComment) Create(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
-	return events.Dispatch(&TaskCommentCreatedEvent{
+	return events.DispatchOnCommit(s, &TaskCommentCreatedEvent{
 		Task:    &task,
 		Comment: tc,
 		Doer:    tc.Author,
@@ -116,12 +118,22 @@ func (tc *TaskComment) Delete(s *xorm.Session, _ web.Auth) error {
 		return err
 	}
 
+	err = deleteReactionsForEntities(s, ReactionKindComment, []int64{tc.ID})
+	if err != nil {
+		return err
+	}
+
+	err = deleteRevisionsForComments(s, []int64{tc.ID})
+	if err != nil {
+		return err
+	}
+
 	task, err := GetTaskByIDSimple(s, tc.TaskID)
 	if err != nil {
 		return err
 	}
 
-	return events.Dispatch(&TaskCommentDeletedEvent{
+	return events.DispatchOnCommit(s, &TaskCommentDeletedEvent{
 		Task:    &task,
 		Comment: tc,
 		Doer:    tc.Author,
@@ -142,7 +154,29 @@ func (tc *TaskComment) Delete(s *xorm.Session, _ web.Auth) error {
 // @Failure 404 {object} web.HTTPError "The task comment was not found."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /tasks/{taskID}/comments/{commentID} [post]
-func (tc *TaskComment) Update(s *xorm.Session, _ web.Auth) error {
+func (tc *TaskComment) Update(s *xorm.Session, a web.Auth) error {
+	old := &TaskComment{ID: tc.ID}
+	err := getTaskCommentSimple(s, old)
+	if err != nil {
+		return err
+	}
+
+	if old.Comment != tc.Comment {
+		editor, err := GetUserOrLinkShareUser(s, a)
+		if err != nil {
+			return err
+		}
+		_, err = s.Insert(&TaskCommentRevision{
+			CommentID: tc.ID,
+			Comment:   old.Comment,
+			EditorID:  editor.ID,
+		})
+		if err != nil {
+			return err
+		}
+		tc.Edited = true
+	}
+
 	updated, err := s.
 		ID(tc.ID).
 		Cols("comment").
@@ -160,7 +194,7 @@ func (tc *TaskComment) Update(s *xorm.Session, _ web.Auth) error {
 		return err
 	}
 
-	return events.Dispatch(&TaskCommentUpdatedEvent{
+	return events.DispatchOnCommit(s, &TaskCommentUpdatedEvent{
 		Task:    &task,
 		Comment: tc,
 		Doer:    tc.Author,
@@ -210,8 +244,12 @@ func (tc *TaskComment) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
 	_, err = s.
 		Where("id = ?", tc.AuthorID).
 		Get(author)
+	if err != nil {
+		return err
+	}
 	tc.Author = author
-	return
+
+	return addEditedToComments(s, []*TaskComment{tc})
 }
 
 // ReadAll returns all comments for a task
@@ -289,6 +327,11 @@ func (tc *TaskComment) ReadAll(s *xorm.Session, auth web.Auth, search string, pa
 		}
 	}
 
+	err = addEditedToComments(s, comments)
+	if err != nil {
+		return
+	}
+
 	numberOfTotalItems, err = s.
 		Where("task_id = ? AND comment like ?", tc.TaskID, "%"+search+"%").
 		Count(&TaskCommentWithAuthor{})
@@ -0,0 +1,193 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/outbound"
+	"code.vikunja.io/web"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/gabriel-vasile/mimetype"
+	"xorm.io/xorm"
+)
+
+// remoteFileAllowNonPublicAddresses allows downloading remote files from private or local addresses. Only used in tests.
+var remoteFileAllowNonPublicAddresses = false
+
+var remoteFileClient *http.Client
+
+// TaskCoverImageFromURL sets the cover image of a task to an image downloaded from an external url.
+type TaskCoverImageFromURL struct {
+	// The task this cover image belongs to.
+	TaskID int64 `json:"-" param:"task"`
+	// The url of the image. Must be a http or https url.
+	URL string `json:"url"`
+	// The attachment which was created from the downloaded image and is now the cover image of the task.
+	Attachment *TaskAttachment `json:"attachment"`
+
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+// CanUpdate checks if the user can change the cover image of the task
+func (tc *TaskCoverImageFromURL) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	t := &Task{ID: tc.TaskID}
+	return t.CanUpdate(s, a)
+}
+
+// Update downloads the image and sets it as the cover image of the task
+// @Summary Set a task cover image from a url
+// @Description Downloads the image from the provided url, stores it as an attachment of the task and sets it as the cover image of the task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param cover body models.TaskCoverImageFromURL true "The url of the image."
+// @Success 200 {object} models.TaskCoverImageFromURL "The cover image has been set."
+// @Failure 400 {object} web.HTTPError "The url is invalid or does not point to an image."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/cover [post]
+func (tc *TaskCoverImageFromURL) Update(s *xorm.Session, a web.Auth) (err error) {
+	u, err := url.Parse(tc.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidTaskCoverURL{URL: tc.URL}
+	}
+
+	content, err := downloadRemoteFile(tc.URL, ErrInvalidTaskCoverURL{URL: tc.URL})
+	if err != nil {
+		return err
+	}
+
+	name := fileNameFromURL(u)
+
+	tc.Attachment, err = SetTaskCoverImage(s, tc.TaskID, content, name, a)
+	return
+}
+
+// fileNameFromURL returns the last path segment of a url or its host if the path is empty.
+func fileNameFromURL(u *url.URL) string {
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		name = u.Host
+	}
+	return name
+}
+
+func getRemoteFileHTTPClient() *http.Client {
+	if remoteFileClient != nil {
+		return remoteFileClient
+	}
+
+	remoteFileClient = outbound.NewClient(&outbound.Options{
+		Timeout:    30 * time.Second,
+		PublicOnly: !remoteFileAllowNonPublicAddresses,
+	})
+
+	return remoteFileClient
+}
+
+// downloadRemoteFile downloads a file from a url, up to the configured maximum file size.
+// Since the url is provided by users, only public addresses can be reached.
+// If the file can not be downloaded, invalidURLErr is returned.
+func downloadRemoteFile(fileURL string, invalidURLErr error) (content []byte, err error) {
+	var maxSize datasize.ByteSize
+	err = maxSize.UnmarshalText([]byte(config.FilesMaxSize.GetString()))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, invalidURLErr
+	}
+	resp, err := getRemoteFileHTTPClient().Do(req)
+	if err != nil {
+		return nil, invalidURLErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 399 {
+		return nil, invalidURLErr
+	}
+
+	// Read one byte more than allowed to find out if the file is too large
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(io.LimitReader(resp.Body, int64(maxSize.Bytes())+1))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(buf.Len()) > maxSize.Bytes() {
+		return nil, ErrTaskAttachmentIsTooLarge{Size: uint64(buf.Len())}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SetTaskCoverImage stores an image as a new attachment of a task and makes it the cover image of that task.
+// The content must be an image.
+func SetTaskCoverImage(s *xorm.Session, taskID int64, content []byte, name string, a web.Auth) (attachment *TaskAttachment, err error) {
+	mime := mimetype.Detect(content)
+	if !strings.HasPrefix(mime.String(), "image/") {
+		return nil, ErrTaskCoverIsNotAnImage{TaskID: taskID, Mime: mime.String()}
+	}
+
+	if path.Ext(name) == "" {
+		name += mime.Extension()
+	}
+
+	task, err := GetTaskByIDSimple(s, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment = &TaskAttachment{TaskID: taskID}
+	err = attachment.NewAttachment(s, io.NopCloser(bytes.NewReader(content)), name, uint64(len(content)), a)
+	if err != nil {
+		return nil, err
+	}
+
+	task.CoverImageAttachmentID = attachment.ID
+	_, err = s.ID(task.ID).
+		Cols("cover_image_attachment_id").
+		Update(&task)
+	if err != nil {
+		return nil, err
+	}
+
+	err = updateTaskLastUpdated(s, &task)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, updateProjectByTaskID(s, task.ID)
+}
@@ -0,0 +1,147 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCoverImageFromURL_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	img := &bytes.Buffer{}
+	err := png.Encode(img, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cover.png":
+			_, _ = w.Write(img.Bytes())
+		case "/text":
+			_, _ = w.Write([]byte("not an image"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	allowLocal := func(t *testing.T) {
+		remoteFileAllowNonPublicAddresses = true
+		remoteFileClient = nil
+		t.Cleanup(func() {
+			remoteFileAllowNonPublicAddresses = false
+			// Don't reuse connections which were allowed before
+			remoteFileClient = nil
+		})
+	}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		allowLocal(t)
+
+		tc := &TaskCoverImageFromURL{TaskID: 1, URL: server.URL + "/cover.png"}
+		err := tc.Update(s, u)
+		require.NoError(t, err)
+		require.NotNil(t, tc.Attachment)
+		assert.Equal(t, "cover.png", tc.Attachment.File.Name)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"id":      tc.Attachment.ID,
+			"task_id": 1,
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":                        1,
+			"cover_image_attachment_id": tc.Attachment.ID,
+		}, false)
+	})
+	t.Run("not an image", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		allowLocal(t)
+
+		tc := &TaskCoverImageFromURL{TaskID: 1, URL: server.URL + "/text"}
+		err := tc.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskCoverIsNotAnImage(err))
+	})
+	t.Run("not found", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		allowLocal(t)
+
+		tc := &TaskCoverImageFromURL{TaskID: 1, URL: server.URL + "/missing.png"}
+		err := tc.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskCoverURL(err))
+	})
+	t.Run("invalid scheme", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCoverImageFromURL{TaskID: 1, URL: "file:///etc/passwd"}
+		err := tc.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskCoverURL(err))
+	})
+	t.Run("non-public addresses", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		for _, coverURL := range []string{
+			server.URL + "/cover.png",
+			"http://127.0.0.1/cover.png",
+			"http://169.254.169.254/latest/meta-data/",
+		} {
+			tc := &TaskCoverImageFromURL{TaskID: 1, URL: coverURL}
+			err := tc.Update(s, u)
+			require.Error(t, err, coverURL)
+			assert.True(t, IsErrInvalidTaskCoverURL(err), coverURL)
+		}
+	})
+	t.Run("no access to the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCoverImageFromURL{TaskID: 14}
+		can, err := tc.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -0,0 +1,305 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"time"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TaskCustomFieldValue holds the value a task has for a custom field.
+// Depending on the type of the field, the value is stored in one of the typed columns.
+// Values of multi select fields are stored as json array in the string column.
+type TaskCustomFieldValue struct {
+	ID            int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID        int64     `xorm:"bigint not null INDEX"`
+	CustomFieldID int64     `xorm:"bigint not null INDEX"`
+	StringValue   string    `xorm:"text null"`
+	NumberValue   float64   `xorm:"double null"`
+	DateValue     time.Time `xorm:"DATETIME null"`
+	BoolValue     bool      `xorm:"null"`
+}
+
+// TableName returns the table name for custom field values
+func (*TaskCustomFieldValue) TableName() string {
+	return "task_custom_field_values"
+}
+
+// newValue converts a value as sent by a client to the stored representation for this field.
+func (cf *CustomField) newValue(taskID int64, raw interface{}) (v *TaskCustomFieldValue, err error) {
+	v = &TaskCustomFieldValue{
+		TaskID:        taskID,
+		CustomFieldID: cf.ID,
+	}
+	invalid := &ErrInvalidCustomFieldValue{CustomFieldID: cf.ID, Type: cf.Type}
+
+	switch cf.Type {
+	case CustomFieldTypeText:
+		str, is := raw.(string)
+		if !is {
+			return nil, invalid
+		}
+		v.StringValue = str
+	case CustomFieldTypeURL:
+		str, is := raw.(string)
+		if !is {
+			return nil, invalid
+		}
+		u, err := url.Parse(str)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, invalid
+		}
+		v.StringValue = str
+	case CustomFieldTypeSelect:
+		str, is := raw.(string)
+		if !is || !slices.Contains(cf.Options, str) {
+			return nil, invalid
+		}
+		v.StringValue = str
+	case CustomFieldTypeMultiSelect:
+		var selected []string
+		switch vals := raw.(type) {
+		case []string:
+			selected = vals
+		case []interface{}:
+			for _, val := range vals {
+				str, is := val.(string)
+				if !is {
+					return nil, invalid
+				}
+				selected = append(selected, str)
+			}
+		default:
+			return nil, invalid
+		}
+		options := make([]string, 0, len(selected))
+		for _, str := range selected {
+			if !slices.Contains(cf.Options, str) {
+				return nil, invalid
+			}
+			if !slices.Contains(options, str) {
+				options = append(options, str)
+			}
+		}
+		encoded, err := json.Marshal(options)
+		if err != nil {
+			return nil, err
+		}
+		v.StringValue = string(encoded)
+	case CustomFieldTypeNumber:
+		switch number := raw.(type) {
+		case float64:
+			v.NumberValue = number
+		case int64:
+			v.NumberValue = float64(number)
+		case int:
+			v.NumberValue = float64(number)
+		default:
+			return nil, invalid
+		}
+	case CustomFieldTypeDate:
+		switch date := raw.(type) {
+		case time.Time:
+			v.DateValue = date
+		case string:
+			v.DateValue, err = parseTimeFromUserInput(date)
+			if err != nil {
+				return nil, invalid
+			}
+		default:
+			return nil, invalid
+		}
+	case CustomFieldTypeCheckbox:
+		checked, is := raw.(bool)
+		if !is {
+			return nil, invalid
+		}
+		v.BoolValue = checked
+	default:
+		return nil, &ErrInvalidCustomFieldType{Type: cf.Type}
+	}
+
+	return v, nil
+}
+
+// value returns the stored value in the representation of the field's type.
+func (v *TaskCustomFieldValue) value(fieldType CustomFieldType) interface{} {
+	switch fieldType {
+	case CustomFieldTypeText, CustomFieldTypeURL, CustomFieldTypeSelect:
+		return v.StringValue
+	case CustomFieldTypeMultiSelect:
+		return v.selectedOptions()
+	case CustomFieldTypeNumber:
+		return v.NumberValue
+	case CustomFieldTypeDate:
+		return v.DateValue
+	case CustomFieldTypeCheckbox:
+		return v.BoolValue
+	}
+	return nil
+}
+
+func (v *TaskCustomFieldValue) selectedOptions() []string {
+	options := []string{}
+	if v.StringValue == "" {
+		return options
+	}
+	_ = json.Unmarshal([]byte(v.StringValue), &options)
+	return options
+}
+
+// setCustomFieldValues saves the custom field values of a task. Fields which are not part of
+// the map are left untouched, fields with a nil value are removed from the task.
+func (t *Task) setCustomFieldValues(s *xorm.Session) (err error) {
+	if t.CustomFields == nil {
+		return
+	}
+
+	fields, err := getCustomFieldsForProjects(s, []int64{t.ProjectID})
+	if err != nil {
+		return
+	}
+	fieldMap := make(map[int64]*CustomField, len(fields))
+	for _, cf := range fields {
+		fieldMap[cf.ID] = cf
+	}
+
+	for fieldID, raw := range t.CustomFields {
+		cf, has := fieldMap[fieldID]
+		if !has {
+			return &ErrCustomFieldDoesNotExist{CustomFieldID: fieldID}
+		}
+
+		_, err = s.
+			Where("task_id = ? AND custom_field_id = ?", t.ID, fieldID).
+			Delete(&TaskCustomFieldValue{})
+		if err != nil {
+			return
+		}
+
+		if raw == nil {
+			delete(t.CustomFields, fieldID)
+			continue
+		}
+
+		v, err := cf.newValue(t.ID, raw)
+		if err != nil {
+			return err
+		}
+		_, err = s.Insert(v)
+		if err != nil {
+			return err
+		}
+		t.CustomFields[fieldID] = v.value(cf.Type)
+	}
+
+	return
+}
+
+// removeForeignCustomFieldValues removes all values of a task for custom fields which
+// do not belong to its project, for example after the task was moved to another project.
+func (t *Task) removeForeignCustomFieldValues(s *xorm.Session) (err error) {
+	_, err = s.
+		Where("task_id = ?", t.ID).
+		And(builder.NotIn("custom_field_id", builder.Select("id").From("custom_fields").Where(builder.Eq{"project_id": t.ProjectID}))).
+		Delete(&TaskCustomFieldValue{})
+	return
+}
+
+func addCustomFieldValuesToTasks(s *xorm.Session, taskIDs []int64, taskMap map[int64]*Task) (err error) {
+	values := []*TaskCustomFieldValue{}
+	err = s.
+		In("task_id", taskIDs).
+		Find(&values)
+	if err != nil || len(values) == 0 {
+		return
+	}
+
+	fieldIDs := make([]int64, 0, len(values))
+	for _, v := range values {
+		fieldIDs = append(fieldIDs, v.CustomFieldID)
+	}
+	fields := make(map[int64]*CustomField, len(fieldIDs))
+	err = s.In("id", fieldIDs).Find(&fields)
+	if err != nil {
+		return
+	}
+
+	for _, v := range values {
+		task, has := taskMap[v.TaskID]
+		cf, hasField := fields[v.CustomFieldID]
+		if !has || !hasField {
+			continue
+		}
+		if task.CustomFields == nil {
+			task.CustomFields = make(map[int64]interface{})
+		}
+		task.CustomFields[cf.ID] = v.value(cf.Type)
+	}
+
+	return
+}
+
+// removeStaleCustomFieldOptions removes options which no longer exist for a select field from all tasks.
+func removeStaleCustomFieldOptions(s *xorm.Session, cf *CustomField) (err error) {
+	if cf.Type == CustomFieldTypeSelect {
+		_, err = s.
+			Where("custom_field_id = ?", cf.ID).
+			NotIn("string_value", cf.Options).
+			Delete(&TaskCustomFieldValue{})
+		return
+	}
+
+	values := []*TaskCustomFieldValue{}
+	err = s.Where("custom_field_id = ?", cf.ID).Find(&values)
+	if err != nil {
+		return
+	}
+
+	for _, v := range values {
+		selected := v.selectedOptions()
+		remaining := make([]string, 0, len(selected))
+		for _, option := range selected {
+			if slices.Contains(cf.Options, option) {
+				remaining = append(remaining, option)
+			}
+		}
+		if len(remaining) == len(selected) {
+			continue
+		}
+
+		encoded, err := json.Marshal(remaining)
+		if err != nil {
+			return err
+		}
+		v.StringValue = string(encoded)
+		_, err = s.
+			Where("id = ?", v.ID).
+			Cols("string_value").
+			Update(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
@@ -0,0 +1,190 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// getUnresolvedBlockers returns the ids of all tasks which are not done yet and block one of the given tasks, grouped by the blocked task.
+func getUnresolvedBlockers(s *xorm.Session, taskIDs []int64) (blockers map[int64][]int64, err error) {
+	blockers = make(map[int64][]int64)
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	relations := []*TaskRelation{}
+	err = s.
+		Select("task_relations.*").
+		Join("INNER", "tasks", "tasks.id = task_relations.other_task_id").
+		Where(builder.And(
+			builder.In("task_relations.task_id", taskIDs),
+			builder.Eq{"task_relations.relation_kind": RelationKindBlocked},
+			builder.Eq{"tasks.done": false},
+		)).
+		OrderBy("task_relations.other_task_id asc").
+		Find(&relations)
+	if err != nil {
+		return
+	}
+
+	for _, rel := range relations {
+		blockers[rel.TaskID] = append(blockers[rel.TaskID], rel.OtherTaskID)
+	}
+
+	return
+}
+
+// checkBlockersResolved makes sure a task can be marked as done in a project which enforces blocking dependencies.
+func (t *Task) checkBlockersResolved(s *xorm.Session) error {
+	blockers, err := getUnresolvedBlockers(s, []int64{t.ID})
+	if err != nil {
+		return err
+	}
+
+	if len(blockers[t.ID]) > 0 {
+		return ErrTaskIsBlocked{TaskID: t.ID, BlockingTaskIDs: blockers[t.ID]}
+	}
+
+	return nil
+}
+
+// ProjectDependencyGraph holds all blocking dependencies between the tasks of a project
+type ProjectDependencyGraph struct {
+	// The project the dependencies belong to.
+	ProjectID int64 `json:"project_id" param:"project"`
+	// All tasks of the project which block or are blocked by another task of the project.
+	Nodes []*DependencyGraphNode `json:"nodes"`
+	// All blocking relations between the tasks of the project.
+	Edges []*DependencyGraphEdge `json:"edges"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// DependencyGraphNode is a task in the dependency graph
+type DependencyGraphNode struct {
+	// The id of the task.
+	TaskID int64 `json:"task_id"`
+	// The title of the task.
+	Title string `json:"title"`
+	// The task identifier, based on the project identifier and the task's index.
+	Identifier string `json:"identifier"`
+	// Whether the task is done.
+	Done bool `json:"done"`
+	// Whether the task is blocked by at least one task which is not done yet. This includes blockers from other projects.
+	IsBlocked bool `json:"is_blocked"`
+}
+
+// DependencyGraphEdge is a blocking relation between two tasks
+type DependencyGraphEdge struct {
+	// The id of the task which blocks the other one.
+	BlockingTaskID int64 `json:"blocking_task_id"`
+	// The id of the task which is blocked.
+	BlockedTaskID int64 `json:"blocked_task_id"`
+}
+
+// CanRead checks if the user can see the dependency graph of a project
+func (g *ProjectDependencyGraph) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	p := &Project{ID: g.ProjectID}
+	return p.CanRead(s, a)
+}
+
+// ReadOne returns the dependency graph of a project
+// @Summary Get the dependency graph of a project
+// @Description Returns all tasks of the project which are part of a blocking relation together with the relations between them. Relations to tasks in other projects are not part of the graph, but they are taken into account for the `is_blocked` property of each task.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Success 200 {object} models.ProjectDependencyGraph "The dependency graph of the project."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/dependencies [get]
+func (g *ProjectDependencyGraph) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	project, err := GetProjectSimpleByID(s, g.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	relations := []*TaskRelation{}
+	err = s.
+		Select("task_relations.*").
+		Join("INNER", []string{"tasks", "t1"}, "t1.id = task_relations.task_id").
+		Join("INNER", []string{"tasks", "t2"}, "t2.id = task_relations.other_task_id").
+		Where(builder.And(
+			builder.Eq{"task_relations.relation_kind": RelationKindBlocking},
+			builder.Eq{"t1.project_id": g.ProjectID},
+			builder.Eq{"t2.project_id": g.ProjectID},
+		)).
+		OrderBy("task_relations.task_id asc, task_relations.other_task_id asc").
+		Find(&relations)
+	if err != nil {
+		return err
+	}
+
+	g.Nodes = []*DependencyGraphNode{}
+	g.Edges = make([]*DependencyGraphEdge, 0, len(relations))
+	if len(relations) == 0 {
+		return nil
+	}
+
+	taskIDs := []int64{}
+	seen := make(map[int64]bool)
+	for _, rel := range relations {
+		g.Edges = append(g.Edges, &DependencyGraphEdge{
+			BlockingTaskID: rel.TaskID,
+			BlockedTaskID:  rel.OtherTaskID,
+		})
+		for _, id := range []int64{rel.TaskID, rel.OtherTaskID} {
+			if !seen[id] {
+				seen[id] = true
+				taskIDs = append(taskIDs, id)
+			}
+		}
+	}
+
+	tasks := []*Task{}
+	err = s.
+		In("id", taskIDs).
+		OrderBy("id asc").
+		Find(&tasks)
+	if err != nil {
+		return err
+	}
+
+	blockers, err := getUnresolvedBlockers(s, taskIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		t.setIdentifier(project)
+		g.Nodes = append(g.Nodes, &DependencyGraphNode{
+			TaskID:     t.ID,
+			Title:      t.Title,
+			Identifier: t.Identifier,
+			Done:       t.Done,
+			IsBlocked:  len(blockers[t.ID]) > 0,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func createBlockingRelation(t *testing.T, s *xorm.Session, blockingTaskID, blockedTaskID int64) {
+	rel := &TaskRelation{
+		TaskID:       blockedTaskID,
+		OtherTaskID:  blockingTaskID,
+		RelationKind: RelationKindBlocked,
+	}
+	err := rel.Create(s, &user.User{ID: 1})
+	require.NoError(t, err)
+}
+
+func TestTask_IsBlocked(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("unresolved blocker", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		createBlockingRelation(t, s, 3, 1)
+
+		task := &Task{ID: 1}
+		err := task.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.True(t, task.IsBlocked)
+	})
+	t.Run("blocker is done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		createBlockingRelation(t, s, 2, 1)
+
+		task := &Task{ID: 1}
+		err := task.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.False(t, task.IsBlocked)
+	})
+}
+
+func TestTask_Update_BlockingDependencies(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	enforce := func(t *testing.T, s *xorm.Session) {
+		_, err := s.
+			Where("id = ?", 1).
+			Cols("enforce_blocking_dependencies").
+			Update(&Project{EnforceBlockingDependencies: true})
+		require.NoError(t, err)
+	}
+
+	t.Run("enforced", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		enforce(t, s)
+		createBlockingRelation(t, s, 3, 1)
+
+		task := &Task{ID: 1, Title: "test", Done: true, ProjectID: 1}
+		err := task.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskIsBlocked(err))
+	})
+	t.Run("enforced with resolved blocker", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		enforce(t, s)
+		createBlockingRelation(t, s, 2, 1)
+
+		task := &Task{ID: 1, Title: "test", Done: true, ProjectID: 1}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("not enforced", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		createBlockingRelation(t, s, 3, 1)
+
+		task := &Task{ID: 1, Title: "test", Done: true, ProjectID: 1}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+	})
+}
+
+func TestProjectDependencyGraph_ReadOne(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	createBlockingRelation(t, s, 3, 1)
+	createBlockingRelation(t, s, 2, 1)
+	// Task 13 belongs to another project and should not show up
+	createBlockingRelation(t, s, 13, 4)
+
+	graph := &ProjectDependencyGraph{ProjectID: 1}
+	err := graph.ReadOne(s, &user.User{ID: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*DependencyGraphEdge{
+		{BlockingTaskID: 2, BlockedTaskID: 1},
+		{BlockingTaskID: 3, BlockedTaskID: 1},
+	}, graph.Edges)
+	require.Len(t, graph.Nodes, 3)
+	assert.Equal(t, int64(1), graph.Nodes[0].TaskID)
+	assert.True(t, graph.Nodes[0].IsBlocked)
+	assert.Equal(t, int64(2), graph.Nodes[1].TaskID)
+	assert.True(t, graph.Nodes[1].Done)
+	assert.Equal(t, int64(3), graph.Nodes[2].TaskID)
+	assert.False(t, graph.Nodes[2].IsBlocked)
+}
@@ -0,0 +1,61 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import "code.vikunja.io/api/pkg/utils"
+
+// TaskDescriptionFormat defines in which format the description of a task is stored
+type TaskDescriptionFormat string
+
+const (
+	// TaskDescriptionFormatHTML stores the description as (sanitized) html. This is what the frontend uses.
+	TaskDescriptionFormatHTML TaskDescriptionFormat = "html"
+	// TaskDescriptionFormatMarkdown stores the description as markdown. The rendered html is returned as description_html.
+	TaskDescriptionFormatMarkdown TaskDescriptionFormat = "markdown"
+)
+
+// sanitizeDescription validates the description format of a task and makes sure html descriptions
+// can't contain anything harmful. If the task does not specify a format, the fallback is used.
+func (t *Task) sanitizeDescription(fallback TaskDescriptionFormat) error {
+	if t.DescriptionFormat == "" {
+		t.DescriptionFormat = fallback
+	}
+	if t.DescriptionFormat == "" {
+		t.DescriptionFormat = TaskDescriptionFormatHTML
+	}
+
+	switch t.DescriptionFormat {
+	case TaskDescriptionFormatHTML:
+		t.Description = utils.SanitizeHTML(t.Description)
+	case TaskDescriptionFormatMarkdown:
+		// Markdown is stored as-is and only sanitized after rendering it
+	default:
+		return ErrInvalidDescriptionFormat{Format: t.DescriptionFormat}
+	}
+
+	return nil
+}
+
+// renderDescription renders markdown descriptions to html.
+func (t *Task) renderDescription() (err error) {
+	if t.DescriptionFormat != TaskDescriptionFormatMarkdown {
+		return nil
+	}
+
+	t.DescriptionHTML, err = utils.MarkdownToHTML(t.Description)
+	return
+}
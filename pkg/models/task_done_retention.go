@@ -0,0 +1,146 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// validateDoneTasksRetention checks the done tasks retention settings of a project are valid
+func (p *Project) validateDoneTasksRetention(s *xorm.Session) error {
+	if p.DoneTasksRetentionDays < 0 {
+		return &ErrInvalidDoneTasksRetention{ProjectID: p.ID, Days: p.DoneTasksRetentionDays, BucketID: p.DoneTasksRetentionBucketID}
+	}
+
+	if p.DoneTasksRetentionBucketID == 0 {
+		return nil
+	}
+
+	bucket, err := getBucketByID(s, p.DoneTasksRetentionBucketID)
+	if err != nil && !IsErrBucketDoesNotExist(err) {
+		return err
+	}
+	if err != nil || bucket.ProjectID != p.ID {
+		return &ErrInvalidDoneTasksRetention{ProjectID: p.ID, Days: p.DoneTasksRetentionDays, BucketID: p.DoneTasksRetentionBucketID}
+	}
+
+	return nil
+}
+
+// retainDoneTasks archives all tasks which are done for longer than the retention period of their project allows
+// or moves them into the retention bucket of the project if it has one.
+func retainDoneTasks(s *xorm.Session, now time.Time) (retained int, err error) {
+	projects := []*Project{}
+	err = s.
+		Where("is_archived = ? AND done_tasks_retention_days > 0", false).
+		OrderBy("id asc").
+		Find(&projects)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, p := range projects {
+		cond := builder.And(
+			builder.Eq{"project_id": p.ID},
+			builder.Eq{"done": true},
+			builder.Eq{"is_archived": false},
+			builder.NotNull{"done_at"},
+			builder.Lt{"done_at": now.Add(-time.Duration(p.DoneTasksRetentionDays) * 24 * time.Hour)},
+		)
+		if p.DoneTasksRetentionBucketID != 0 {
+			cond = builder.And(cond, builder.Or(
+				builder.IsNull{"bucket_id"},
+				builder.Neq{"bucket_id": p.DoneTasksRetentionBucketID},
+			))
+		}
+
+		tasks := []*Task{}
+		err = s.Where(cond).Find(&tasks)
+		if err != nil {
+			return retained, err
+		}
+
+		sort.Slice(tasks, func(i, j int) bool {
+			return tasks[i].ID < tasks[j].ID
+		})
+
+		for _, t := range tasks {
+			if p.DoneTasksRetentionBucketID == 0 {
+				t.IsArchived = true
+				_, err = s.ID(t.ID).Cols("is_archived").Update(t)
+				if err != nil {
+					return retained, err
+				}
+				retained++
+				continue
+			}
+
+			oldBucketID := t.BucketID
+			t.BucketID = p.DoneTasksRetentionBucketID
+			_, err = s.ID(t.ID).Cols("bucket_id").Update(t)
+			if err != nil {
+				return retained, err
+			}
+
+			err = recordBucketTransition(s, t.ID, p.ID, oldBucketID, p.ID, t.BucketID)
+			if err != nil {
+				return retained, err
+			}
+			retained++
+		}
+	}
+
+	return retained, nil
+}
+
+// RegisterDoneTasksRetentionCron registers a function which archives done tasks once they are done for longer
+// than the retention period of their project.
+func RegisterDoneTasksRetentionCron() {
+	const logPrefix = "[Done Tasks Retention Cron] "
+
+	err := cron.Schedule("0 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		retained, err := retainDoneTasks(s, time.Now())
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not archive done tasks: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit archived done tasks: %s", err)
+			return
+		}
+
+		if retained > 0 {
+			log.Debugf(logPrefix+"Archived or moved %d done tasks", retained)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register done tasks retention cron: %s", err)
+	}
+}
@@ -0,0 +1,146 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestRetainDoneTasks(t *testing.T) {
+	setRetention := func(t *testing.T, s *xorm.Session, days, bucketID int64) {
+		_, err := s.ID(1).
+			Cols("done_tasks_retention_days", "done_tasks_retention_bucket_id").
+			Update(&Project{DoneTasksRetentionDays: days, DoneTasksRetentionBucketID: bucketID})
+		require.NoError(t, err)
+		_, err = s.ID(2).
+			Cols("done_at").
+			NoAutoTime().
+			Update(&Task{DoneAt: time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+	}
+
+	t.Run("archives done tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRetention(t, s, 7, 0)
+
+		retained, err := retainDoneTasks(s, time.Date(2018, 12, 10, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 1, retained)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":          2,
+			"is_archived": true,
+		}, false)
+	})
+	t.Run("not done long enough", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRetention(t, s, 7, 0)
+
+		retained, err := retainDoneTasks(s, time.Date(2018, 12, 5, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 0, retained)
+	})
+	t.Run("moves into bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRetention(t, s, 7, 3)
+
+		now := time.Date(2018, 12, 10, 0, 0, 0, 0, time.UTC)
+		retained, err := retainDoneTasks(s, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, retained)
+
+		// Tasks already in the bucket are not moved again
+		retained, err = retainDoneTasks(s, now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, retained)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":          2,
+			"bucket_id":   3,
+			"is_archived": false,
+		}, false)
+		db.AssertExists(t, "task_bucket_transitions", map[string]interface{}{
+			"task_id":        2,
+			"from_bucket_id": 1,
+			"to_bucket_id":   3,
+		}, false)
+	})
+}
+
+func TestProject_validateDoneTasksRetention(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("negative days", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &Project{ID: 1, Title: "Test1", DoneTasksRetentionDays: -1}
+		err := p.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidDoneTasksRetention(err))
+	})
+	t.Run("bucket of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &Project{ID: 1, Title: "Test1", DoneTasksRetentionDays: 7, DoneTasksRetentionBucketID: 4}
+		err := p.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidDoneTasksRetention(err))
+	})
+	t.Run("reset when the bucket is deleted", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &Project{ID: 1, Title: "Test1", DoneTasksRetentionDays: 7, DoneTasksRetentionBucketID: 3}
+		err := p.Update(s, u)
+		require.NoError(t, err)
+
+		b := &Bucket{ID: 3, ProjectID: 1}
+		err = b.Delete(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":                             1,
+			"done_tasks_retention_days":      7,
+			"done_tasks_retention_bucket_id": 0,
+		}, false)
+	})
+}
@@ -0,0 +1,164 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+const (
+	// Tasks with a title similarity below this are not suggested as duplicates
+	taskDuplicateMinSimilarity = 0.5
+	// The maximum number of suggested duplicates
+	taskDuplicateMaxResults = 10
+	// Words shorter than this are ignored when searching for duplicates
+	taskDuplicateMinWordLength = 3
+)
+
+// TaskDuplicateCandidate is an undone task which looks like a duplicate of a new task
+type TaskDuplicateCandidate struct {
+	// The project to look for duplicates in.
+	ProjectID int64 `json:"-" param:"project"`
+
+	// The task which might be a duplicate.
+	Task *Task `json:"task"`
+	// How similar the title of the task is to the searched title, between 0 and 1.
+	Similarity float64 `json:"similarity"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanRead checks if the user can look for duplicates in the project
+func (tdc *TaskDuplicateCandidate) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	project := &Project{ID: tdc.ProjectID}
+	return project.CanRead(s, a)
+}
+
+// titleWords returns the distinct lower case words of a title
+func titleWords(title string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// titleSimilarity returns the jaccard index of the words of both titles
+func titleSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	var common int
+	for w := range a {
+		if b[w] {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// ReadAll returns undone tasks with a title similar to the search string
+// @Summary Find possible duplicates of a task
+// @Description Returns undone tasks of a project which have a title similar to the provided one, most similar first. Use this to suggest existing tasks before creating a new one.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project ID"
+// @Param s query string true "The title of the new task."
+// @Success 200 {array} models.TaskDuplicateCandidate "The possible duplicates."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/tasks/duplicates [get]
+func (tdc *TaskDuplicateCandidate) ReadAll(s *xorm.Session, a web.Auth, search string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	canRead, _, err := tdc.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	candidates := []*TaskDuplicateCandidate{}
+
+	words := titleWords(search)
+	conds := []builder.Cond{}
+	for w := range words {
+		if len([]rune(w)) >= taskDuplicateMinWordLength {
+			conds = append(conds, db.ILIKE("title", w))
+		}
+	}
+	if len(conds) == 0 {
+		return candidates, 0, 0, nil
+	}
+
+	tasks := []*Task{}
+	err = s.
+		Where("project_id = ? AND done = ?", tdc.ProjectID, false).
+		And(builder.Or(conds...)).
+		Find(&tasks)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	for _, t := range tasks {
+		similarity := titleSimilarity(words, titleWords(t.Title))
+		if similarity < taskDuplicateMinSimilarity {
+			continue
+		}
+		candidates = append(candidates, &TaskDuplicateCandidate{
+			ProjectID:  tdc.ProjectID,
+			Task:       t,
+			Similarity: similarity,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Similarity == candidates[j].Similarity {
+			return candidates[i].Task.ID < candidates[j].Task.ID
+		}
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+	if len(candidates) > taskDuplicateMaxResults {
+		candidates = candidates[:taskDuplicateMaxResults]
+	}
+
+	taskMap := make(map[int64]*Task, len(candidates))
+	for _, c := range candidates {
+		taskMap[c.Task.ID] = c.Task
+	}
+	err = addMoreInfoToTasks(s, taskMap, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return candidates, len(candidates), int64(len(candidates)), nil
+}
@@ -0,0 +1,185 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskHistoryEntry records the change of one field of a task
+type TaskHistoryEntry struct {
+	// The unique, numeric id of this history entry.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The task which was changed.
+	TaskID int64 `xorm:"bigint not null INDEX" json:"task_id" param:"task"`
+	// The name of the field which was changed, for example `title` or `due_date`.
+	Field string `xorm:"varchar(250) not null" json:"field"`
+	// The value of the field before the change. Dates are formatted as ISO 8601, empty dates are an empty string.
+	OldValue string `xorm:"longtext null" json:"old_value"`
+	// The value of the field after the change.
+	NewValue string `xorm:"longtext null" json:"new_value"`
+
+	// The user who changed the task.
+	User   *user.User `xorm:"-" json:"user"`
+	UserID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when the change happened. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name for task history entries
+func (*TaskHistoryEntry) TableName() string {
+	return "task_history"
+}
+
+func formatTaskHistoryTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// taskHistoryFields holds all task fields which are recorded in the history
+var taskHistoryFields = []struct {
+	name  string
+	value func(t *Task) string
+}{
+	{"title", func(t *Task) string { return t.Title }},
+	{"description", func(t *Task) string { return t.Description }},
+	{"done", func(t *Task) string { return strconv.FormatBool(t.Done) }},
+	{"is_archived", func(t *Task) string { return strconv.FormatBool(t.IsArchived) }},
+	{"due_date", func(t *Task) string { return formatTaskHistoryTime(t.DueDate) }},
+	{"start_date", func(t *Task) string { return formatTaskHistoryTime(t.StartDate) }},
+	{"end_date", func(t *Task) string { return formatTaskHistoryTime(t.EndDate) }},
+	{"priority", func(t *Task) string { return strconv.FormatInt(t.Priority, 10) }},
+	{"percent_done", func(t *Task) string { return strconv.FormatFloat(t.PercentDone, 'f', -1, 64) }},
+	{"estimate", func(t *Task) string { return strconv.FormatInt(t.Estimate, 10) }},
+	{"duration", func(t *Task) string { return strconv.FormatInt(t.Duration, 10) }},
+	{"hex_color", func(t *Task) string { return t.HexColor }},
+	{"location", func(t *Task) string { return t.formatLocation() }},
+	{"place_name", func(t *Task) string { return t.PlaceName }},
+	{"project_id", func(t *Task) string { return strconv.FormatInt(t.ProjectID, 10) }},
+	{"bucket_id", func(t *Task) string { return strconv.FormatInt(t.BucketID, 10) }},
+	{"status_id", func(t *Task) string { return strconv.FormatInt(t.StatusID, 10) }},
+	{"milestone_id", func(t *Task) string { return strconv.FormatInt(t.MilestoneID, 10) }},
+	{"repeat_after", func(t *Task) string { return strconv.FormatInt(t.RepeatAfter, 10) }},
+	{"repeat_mode", func(t *Task) string { return strconv.Itoa(int(t.RepeatMode)) }},
+	{"repeat_rule", func(t *Task) string { return t.RepeatRule }},
+	{"cover_image_attachment_id", func(t *Task) string { return strconv.FormatInt(t.CoverImageAttachmentID, 10) }},
+}
+
+// recordTaskChanges saves a history entry for every field which differs between the old and the new task.
+func recordTaskChanges(s *xorm.Session, a web.Auth, oldTask, newTask *Task) error {
+	entries := []*TaskHistoryEntry{}
+	for _, field := range taskHistoryFields {
+		oldValue := field.value(oldTask)
+		newValue := field.value(newTask)
+		if oldValue == newValue {
+			continue
+		}
+		entries = append(entries, &TaskHistoryEntry{
+			TaskID:   newTask.ID,
+			Field:    field.name,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	doer, err := GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		entry.UserID = doer.ID
+	}
+
+	_, err = s.Insert(&entries)
+	return err
+}
+
+// CanRead checks if the user can see the history of a task
+func (th *TaskHistoryEntry) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	t := &Task{ID: th.TaskID}
+	return t.CanRead(s, a)
+}
+
+// ReadAll returns the history of a task
+// @Summary Get the change history of a task
+// @Description Returns all recorded field changes of a task with the user who made them, newest first.
+// @tags task
+// @Accept json
+// @Produce json
+// @Param taskID path int true "Task ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Security JWTKeyAuth
+// @Success 200 {array} models.TaskHistoryEntry "The history entries"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/history [get]
+func (th *TaskHistoryEntry) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	canRead, _, err := th.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	entries := []*TaskHistoryEntry{}
+	query := s.
+		Where("task_id = ?", th.TaskID).
+		OrderBy("created desc, id desc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&entries)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	userIDs := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		userIDs = append(userIDs, entry.UserID)
+	}
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for _, entry := range entries {
+		entry.User = users[entry.UserID]
+	}
+
+	numberOfTotalItems, err = s.
+		Where("task_id = ?", th.TaskID).
+		Count(&TaskHistoryEntry{})
+	return entries, len(entries), numberOfTotalItems, err
+}
@@ -0,0 +1,164 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskHistoryEntry_ReadAll(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		th := &TaskHistoryEntry{TaskID: 1}
+		result, resultCount, total, err := th.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+		require.NoError(t, err)
+		entries := result.([]*TaskHistoryEntry)
+		assert.Equal(t, 3, resultCount)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, int64(3), entries[0].ID)
+		assert.Equal(t, "description", entries[0].Field)
+		assert.Equal(t, int64(1), entries[2].ID)
+		require.NotNil(t, entries[0].User)
+		assert.Equal(t, "user1", entries[0].User.Username)
+	})
+	t.Run("paginated", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		th := &TaskHistoryEntry{TaskID: 1}
+		result, resultCount, total, err := th.ReadAll(s, &user.User{ID: 1}, "", 2, 2)
+		require.NoError(t, err)
+		entries := result.([]*TaskHistoryEntry)
+		assert.Equal(t, 1, resultCount)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, int64(1), entries[0].ID)
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		th := &TaskHistoryEntry{TaskID: 1}
+		_, _, _, err := th.ReadAll(s, &user.User{ID: 13}, "", 0, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
+
+func TestTask_Update_History(t *testing.T) {
+	t.Run("changed fields", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:          1,
+			Title:       "task #1 changed",
+			Description: "Lorem Ipsum",
+			ProjectID:   1,
+			BucketID:    1,
+			Priority:    3,
+			DueDate:     time.Date(2023, 3, 1, 15, 0, 0, 0, time.UTC),
+		}
+		err := task.Update(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_history", map[string]interface{}{
+			"task_id":   1,
+			"field":     "title",
+			"old_value": "task #1",
+			"new_value": "task #1 changed",
+			"user_id":   1,
+		}, false)
+		db.AssertExists(t, "task_history", map[string]interface{}{
+			"task_id":   1,
+			"field":     "priority",
+			"old_value": "0",
+			"new_value": "3",
+		}, false)
+		db.AssertExists(t, "task_history", map[string]interface{}{
+			"task_id":   1,
+			"field":     "due_date",
+			"old_value": "",
+			"new_value": "2023-03-01T15:00:00Z",
+		}, false)
+		db.AssertMissing(t, "task_history", map[string]interface{}{
+			"task_id":   1,
+			"field":     "description",
+			"new_value": "Lorem Ipsum",
+			"old_value": "Lorem Ipsum",
+		})
+	})
+	t.Run("nothing changed", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:          1,
+			Title:       "task #1",
+			Description: "Lorem Ipsum",
+			ProjectID:   1,
+			BucketID:    1,
+		}
+		err := task.Update(s, &user.User{ID: 1})
+		require.NoError(t, err)
+
+		count, err := s.Where("task_id = ?", 1).Count(&TaskHistoryEntry{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+	t.Run("bulk update", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bt := &BulkTask{
+			IDs:  []int64{10, 11},
+			Task: Task{Done: true},
+		}
+		allowed, err := bt.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		require.True(t, allowed)
+		err = bt.Update(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		for _, id := range []int64{10, 11} {
+			db.AssertExists(t, "task_history", map[string]interface{}{
+				"task_id":   id,
+				"field":     "done",
+				"old_value": "false",
+				"new_value": "true",
+			}, false)
+		}
+	})
+}
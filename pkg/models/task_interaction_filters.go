@@ -0,0 +1,190 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"xorm.io/builder"
+)
+
+const (
+	// taskFilterFieldMentionsMe matches all tasks where the current user is mentioned in the description or a comment.
+	taskFilterFieldMentionsMe = "mentions_me"
+	// taskFilterFieldCommentedBy matches all tasks with a comment by one of the given users. The value `me` is the current user.
+	taskFilterFieldCommentedBy = "commented_by"
+	// taskFilterFieldLastViewed compares the time the current user last opened a task.
+	taskFilterFieldLastViewed = "last_viewed"
+
+	// taskFilterValueMe is replaced with the current user in interaction filters.
+	taskFilterValueMe = "me"
+)
+
+// interactionFilter is a filter on how the current user interacted with a task.
+// Because these filters depend on who is asking, they can only be handled by the db.
+type interactionFilter struct {
+	field      string
+	comparator taskFilterComparator
+	// A bool for mentions_me, a slice of usernames for commented_by and the time or times for last_viewed.
+	value interface{}
+}
+
+func isInteractionFilterField(field string) bool {
+	return field == taskFilterFieldMentionsMe ||
+		field == taskFilterFieldCommentedBy ||
+		field == taskFilterFieldLastViewed
+}
+
+func parseInteractionFilterValue(filter *taskFilter, value string, loc *time.Location) (inf *interactionFilter, err error) {
+	inf = &interactionFilter{
+		field:      filter.field,
+		comparator: filter.comparator,
+	}
+
+	switch filter.field {
+	case taskFilterFieldMentionsMe:
+		if filter.comparator != taskFilterComparatorEquals && filter.comparator != taskFilterComparatorNotEquals {
+			return nil, ErrInvalidTaskFilterComparator{Comparator: filter.comparator}
+		}
+		mentioned, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, ErrInvalidTaskFilterValue{Value: value, Field: filter.field}
+		}
+		inf.value = mentioned
+	case taskFilterFieldCommentedBy:
+		if filter.comparator != taskFilterComparatorEquals &&
+			filter.comparator != taskFilterComparatorNotEquals &&
+			filter.comparator != taskFilterComparatorIn {
+			return nil, ErrInvalidTaskFilterComparator{Comparator: filter.comparator}
+		}
+		inf.value = splitFilterValues(value)
+	case taskFilterFieldLastViewed:
+		if filter.comparator == taskFilterComparatorLike {
+			return nil, ErrInvalidTaskFilterComparator{Comparator: filter.comparator}
+		}
+		field, _ := reflect.TypeOf(&TaskView{}).Elem().FieldByName("Viewed")
+		if filter.comparator == taskFilterComparatorIn {
+			values := []interface{}{}
+			for _, val := range splitFilterValues(value) {
+				v, err := getValueForField(field, val, loc)
+				if err != nil {
+					return nil, ErrInvalidTaskFilterValue{Value: value, Field: filter.field}
+				}
+				values = append(values, v)
+			}
+			inf.value = values
+			return inf, nil
+		}
+		inf.value, err = getValueForField(field, value, loc)
+		if err != nil {
+			return nil, ErrInvalidTaskFilterValue{Value: value, Field: filter.field}
+		}
+	}
+
+	return inf, nil
+}
+
+// hasInteractionFilter checks whether the filters contain any interaction filter.
+func hasInteractionFilter(filters []*taskFilter) bool {
+	for _, f := range filters {
+		if nested, is := f.value.([]*taskFilter); is && hasInteractionFilter(nested) {
+			return true
+		}
+		if _, is := f.value.(*interactionFilter); is {
+			return true
+		}
+	}
+	return false
+}
+
+// getInteractionFilterCond returns the db condition for an interaction filter of the given user.
+// Link shares have no interactions, doer is nil for them.
+func getInteractionFilterCond(inf *interactionFilter, doer *user.User, includeNulls bool) (cond builder.Cond, err error) {
+	matchesNothing := builder.Expr("1 = 0")
+
+	switch inf.field {
+	case taskFilterFieldMentionsMe:
+		mentioned := inf.value.(bool)
+		if inf.comparator == taskFilterComparatorNotEquals {
+			mentioned = !mentioned
+		}
+		if doer == nil {
+			if mentioned {
+				return matchesNothing, nil
+			}
+			return builder.Expr("1 = 1"), nil
+		}
+
+		mention := "@" + doer.Username
+		cond = builder.Or(
+			db.ILIKE("description", mention),
+			getFilterCondForSeparateTable("task_comments", db.ILIKE("comment", mention)),
+		)
+		if !mentioned {
+			cond = builder.Not{cond}
+		}
+		return cond, nil
+	case taskFilterFieldCommentedBy:
+		usernames := []string{}
+		var authorIDs []int64
+		for _, username := range inf.value.([]string) {
+			if username == taskFilterValueMe {
+				if doer != nil {
+					authorIDs = append(authorIDs, doer.ID)
+				}
+				continue
+			}
+			usernames = append(usernames, username)
+		}
+
+		cond = getFilterCondForSeparateTable("task_comments", builder.Or(
+			builder.In("author_id", authorIDs),
+			builder.In("author_id", builder.Select("id").From("users").Where(builder.In("username", usernames))),
+		))
+		if inf.comparator == taskFilterComparatorNotEquals {
+			cond = builder.Not{cond}
+		}
+		return cond, nil
+	case taskFilterFieldLastViewed:
+		if doer == nil {
+			return matchesNothing, nil
+		}
+
+		filter, err := getFilterCond(&taskFilter{
+			field:      "viewed",
+			value:      inf.value,
+			comparator: inf.comparator,
+		}, false)
+		if err != nil {
+			return nil, err
+		}
+
+		ownViews := builder.Eq{"user_id": doer.ID}
+		cond = getFilterCondForSeparateTable("task_views", builder.And(ownViews, filter))
+		if includeNulls {
+			cond = builder.Or(cond, builder.NotIn("id", builder.Select("task_id").From("task_views").Where(ownViews)))
+		}
+		return cond, nil
+	}
+
+	return nil, ErrInvalidTaskField{TaskField: inf.field}
+}
@@ -0,0 +1,92 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCollection_ReadAll_InteractionFilters(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	getTaskIDs := func(t *testing.T, filter string) []int64 {
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1, Filter: filter}
+		result, _, _, err := tc.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		ids := []int64{}
+		for _, task := range result.([]*Task) {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	t.Run("mentions_me", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		_, err := s.Where("id = ?", 2).Cols("description").Update(&Task{Description: "<p>Can you have a look, @user1?</p>"})
+		require.NoError(t, err)
+		_, err = s.Insert(&TaskComment{TaskID: 3, AuthorID: 2, Comment: "@user1 done"})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		s.Close()
+
+		assert.Equal(t, []int64{2, 3}, getTaskIDs(t, "mentions_me = true"))
+		assert.NotContains(t, getTaskIDs(t, "mentions_me = false"), int64(2))
+		assert.NotContains(t, getTaskIDs(t, "mentions_me != true"), int64(3))
+	})
+	t.Run("commented_by me", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		assert.Equal(t, []int64{1}, getTaskIDs(t, "commented_by = me"))
+		assert.NotContains(t, getTaskIDs(t, "commented_by != me"), int64(1))
+	})
+	t.Run("commented_by username", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		_, err := s.Insert(&TaskComment{TaskID: 4, AuthorID: 2, Comment: "Lorem"})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		s.Close()
+
+		assert.Equal(t, []int64{4}, getTaskIDs(t, "commented_by = user2"))
+		assert.Equal(t, []int64{1, 4}, getTaskIDs(t, "commented_by in [me, user2]"))
+	})
+	t.Run("last_viewed", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		assert.Equal(t, []int64{1, 3}, getTaskIDs(t, "last_viewed > '2018-11-30'"))
+		assert.Equal(t, []int64{3}, getTaskIDs(t, "last_viewed > '2018-12-02'"))
+	})
+	t.Run("invalid comparator", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1, Filter: "mentions_me > true"}
+		_, _, _, err := tc.ReadAll(s, u, "", 0, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskFilterComparator(err))
+	})
+}
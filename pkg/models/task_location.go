@@ -0,0 +1,161 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"math"
+	"strconv"
+
+	"xorm.io/builder"
+)
+
+const (
+	// taskFilterFieldNear matches all tasks within a radius around a point. The value is `latitude,longitude,radius`
+	// with the radius in kilometers.
+	taskFilterFieldNear = "near"
+	// taskFilterFieldHasLocation matches all tasks with or without a location.
+	taskFilterFieldHasLocation = "has_location"
+)
+
+// The length of one degree of latitude in kilometers
+const kilometersPerDegree = 111.195
+
+type locationFilter struct {
+	near        bool
+	latitude    float64
+	longitude   float64
+	radius      float64
+	hasLocation bool
+}
+
+func (t *Task) validateLocation() error {
+	if t.Latitude == nil && t.Longitude == nil {
+		return nil
+	}
+
+	if t.Latitude == nil || t.Longitude == nil ||
+		*t.Latitude < -90 || *t.Latitude > 90 ||
+		*t.Longitude < -180 || *t.Longitude > 180 {
+		return ErrInvalidTaskLocation{TaskID: t.ID, Latitude: t.Latitude, Longitude: t.Longitude}
+	}
+
+	return nil
+}
+
+// formatLocation returns the coordinates of the task as `latitude,longitude` or an empty string if it has no location.
+func (t *Task) formatLocation() string {
+	if t.Latitude == nil || t.Longitude == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*t.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(*t.Longitude, 'f', -1, 64)
+}
+
+func isLocationFilterField(field string) bool {
+	return field == taskFilterFieldNear || field == taskFilterFieldHasLocation
+}
+
+func parseLocationFilterValue(filter *taskFilter, value string) (lf *locationFilter, err error) {
+	invalid := ErrInvalidTaskFilterValue{Value: value, Field: filter.field}
+
+	if filter.field == taskFilterFieldHasLocation {
+		if filter.comparator != taskFilterComparatorEquals && filter.comparator != taskFilterComparatorNotEquals {
+			return nil, ErrInvalidTaskFilterComparator{Comparator: filter.comparator}
+		}
+		hasLocation, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, invalid
+		}
+		if filter.comparator == taskFilterComparatorNotEquals {
+			hasLocation = !hasLocation
+		}
+		return &locationFilter{hasLocation: hasLocation}, nil
+	}
+
+	if filter.comparator != taskFilterComparatorEquals {
+		return nil, ErrInvalidTaskFilterComparator{Comparator: filter.comparator}
+	}
+
+	parts := splitFilterValues(value)
+	if len(parts) != 3 {
+		return nil, invalid
+	}
+	values := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, invalid
+		}
+		values = append(values, v)
+	}
+
+	lf = &locationFilter{
+		near:      true,
+		latitude:  values[0],
+		longitude: values[1],
+		radius:    values[2],
+	}
+	if lf.latitude < -90 || lf.latitude > 90 || lf.longitude < -180 || lf.longitude > 180 || lf.radius <= 0 {
+		return nil, invalid
+	}
+
+	return lf, nil
+}
+
+// hasLocationFilter checks whether the filters contain any location filter. These can only be handled by the db.
+func hasLocationFilter(filters []*taskFilter) bool {
+	for _, f := range filters {
+		if nested, is := f.value.([]*taskFilter); is && hasLocationFilter(nested) {
+			return true
+		}
+		if _, is := f.value.(*locationFilter); is {
+			return true
+		}
+	}
+	return false
+}
+
+// getLocationFilterCond returns the db condition for a location filter.
+// The distance for near filters is calculated with an equirectangular projection around the point, which is
+// accurate enough for the distances people usually filter for and only needs basic arithmetic in the db.
+func getLocationFilterCond(lf *locationFilter) builder.Cond {
+	hasLocation := builder.And(
+		builder.NotNull{"latitude"},
+		builder.NotNull{"longitude"},
+	)
+
+	if !lf.near {
+		if lf.hasLocation {
+			return hasLocation
+		}
+		return builder.Or(
+			builder.IsNull{"latitude"},
+			builder.IsNull{"longitude"},
+		)
+	}
+
+	radius := lf.radius / kilometersPerDegree
+	cos := math.Cos(lf.latitude * math.Pi / 180)
+
+	return builder.And(
+		hasLocation,
+		builder.Between{Col: "latitude", LessVal: lf.latitude - radius, MoreVal: lf.latitude + radius},
+		builder.Expr(
+			"(latitude - ?) * (latitude - ?) + (longitude - ?) * (longitude - ?) * ? <= ?",
+			lf.latitude, lf.latitude, lf.longitude, lf.longitude, cos*cos, radius*radius,
+		),
+	)
+}
@@ -0,0 +1,161 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func setTaskLocation(t *testing.T, s *xorm.Session, taskID int64, latitude, longitude float64) {
+	_, err := s.
+		Where("id = ?", taskID).
+		Cols("latitude", "longitude").
+		Update(&Task{Latitude: &latitude, Longitude: &longitude})
+	require.NoError(t, err)
+}
+
+func TestTask_Location(t *testing.T) {
+	u := &user.User{ID: 1}
+	latitude := 52.52
+	longitude := 13.405
+
+	t.Run("set and clear", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1, Title: "test", ProjectID: 1, Latitude: &latitude, Longitude: &longitude, PlaceName: "Berlin"}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         1,
+			"latitude":   latitude,
+			"longitude":  longitude,
+			"place_name": "Berlin",
+		}, false)
+
+		s = db.NewSession()
+		defer s.Close()
+		task = &Task{ID: 1, Title: "test", ProjectID: 1}
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		assert.Nil(t, task.Latitude)
+		assert.Nil(t, task.Longitude)
+		assert.Empty(t, task.PlaceName)
+	})
+	t.Run("only latitude", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "test", ProjectID: 1, Latitude: &latitude}
+		err := task.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskLocation(err))
+	})
+	t.Run("out of range", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		invalid := 91.0
+		task := &Task{Title: "test", ProjectID: 1, Latitude: &invalid, Longitude: &longitude}
+		err := task.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskLocation(err))
+	})
+}
+
+func TestTaskCollection_ReadAll_Location(t *testing.T) {
+	getTaskIDs := func(t *testing.T, filter string) ([]int64, error) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// Berlin
+		setTaskLocation(t, s, 1, 52.52, 13.405)
+		// Potsdam, about 27km from Berlin
+		setTaskLocation(t, s, 2, 52.391, 13.063)
+
+		tc := &TaskCollection{
+			ProjectID: 1,
+			Filter:    filter,
+		}
+		result, _, _, err := tc.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := []int64{}
+		for _, task := range result.([]*Task) {
+			ids = append(ids, task.ID)
+		}
+		return ids, nil
+	}
+
+	t.Run("near", func(t *testing.T) {
+		ids, err := getTaskIDs(t, "near = '52.5,13.4,10'")
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, ids)
+	})
+	t.Run("near with larger radius", func(t *testing.T) {
+		ids, err := getTaskIDs(t, "near = '52.5,13.4,50'")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{1, 2}, ids)
+	})
+	t.Run("near with brackets", func(t *testing.T) {
+		ids, err := getTaskIDs(t, "near = '[52.5, 13.4, 10]'")
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, ids)
+	})
+	t.Run("has location", func(t *testing.T) {
+		ids, err := getTaskIDs(t, "has_location = true")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{1, 2}, ids)
+	})
+	t.Run("without location", func(t *testing.T) {
+		ids, err := getTaskIDs(t, "has_location = false")
+		require.NoError(t, err)
+		assert.NotEmpty(t, ids)
+		assert.NotContains(t, ids, int64(1))
+		assert.NotContains(t, ids, int64(2))
+	})
+	t.Run("latitude", func(t *testing.T) {
+		ids, err := getTaskIDs(t, "latitude > 52.45")
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, ids)
+	})
+	t.Run("invalid near value", func(t *testing.T) {
+		_, err := getTaskIDs(t, "near = '52.5,13.4'")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskFilterValue(err))
+	})
+	t.Run("invalid comparator", func(t *testing.T) {
+		_, err := getTaskIDs(t, "near > '52.5,13.4,10'")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskFilterComparator(err))
+	})
+}
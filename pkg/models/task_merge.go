@@ -0,0 +1,258 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskMerge merges a task into another one
+type TaskMerge struct {
+	// The task which is merged into the target task. It is marked as done afterwards.
+	TaskID int64 `json:"-" param:"task"`
+	// The task which receives the comments, attachments, relations, assignees, labels and votes of the merged task.
+	TargetTaskID int64 `json:"target_task_id"`
+
+	// The target task with everything merged into it.
+	Task *Task `json:"task"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanUpdate checks if the user can merge the two tasks. This needs write access to both of them.
+func (tm *TaskMerge) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	for _, id := range []int64{tm.TaskID, tm.TargetTaskID} {
+		t := &Task{ID: id}
+		can, err := t.CanWrite(s, a)
+		if err != nil || !can {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (tm *TaskMerge) moveLabels(s *xorm.Session) error {
+	labelTasks := []*LabelTask{}
+	err := s.Where("task_id = ?", tm.TaskID).Find(&labelTasks)
+	if err != nil {
+		return err
+	}
+
+	for _, lt := range labelTasks {
+		exists, err := s.Exist(&LabelTask{LabelID: lt.LabelID, TaskID: tm.TargetTaskID})
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		_, err = s.Insert(&LabelTask{LabelID: lt.LabelID, TaskID: tm.TargetTaskID})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.Where("task_id = ?", tm.TaskID).Delete(&LabelTask{})
+	return err
+}
+
+func (tm *TaskMerge) moveAssignees(s *xorm.Session, target *Task, a web.Auth) error {
+	assignees := []*TaskAssginee{}
+	err := s.In("task_id", []int64{tm.TaskID, tm.TargetTaskID}).Find(&assignees)
+	if err != nil {
+		return err
+	}
+
+	assigned := make(map[int64]bool, len(assignees))
+	for _, assignee := range assignees {
+		if assignee.TaskID == tm.TargetTaskID {
+			assigned[assignee.UserID] = true
+		}
+	}
+
+	project, err := GetProjectSimpleByID(s, target.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	for _, assignee := range assignees {
+		if assignee.TaskID != tm.TaskID || assigned[assignee.UserID] {
+			continue
+		}
+		err = target.addNewAssigneeByID(s, assignee.UserID, project, a)
+		// Users who cannot see the target task are not assigned to it
+		if IsErrUserDoesNotHaveAccessToProject(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		assigned[assignee.UserID] = true
+	}
+
+	_, err = s.Where("task_id = ?", tm.TaskID).Delete(&TaskAssginee{})
+	return err
+}
+
+func (tm *TaskMerge) moveRelations(s *xorm.Session, a web.Auth) error {
+	relations := []*TaskRelation{}
+	err := s.Where("task_id = ?", tm.TaskID).Find(&relations)
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range relations {
+		err = rel.Delete(s, a)
+		if err != nil && !IsErrRelationDoesNotExist(err) {
+			return err
+		}
+
+		if rel.OtherTaskID == tm.TargetTaskID {
+			continue
+		}
+
+		moved := &TaskRelation{
+			TaskID:       tm.TargetTaskID,
+			OtherTaskID:  rel.OtherTaskID,
+			RelationKind: rel.RelationKind,
+		}
+		err = moved.Create(s, a)
+		// The target task may already be related to the other task or the relation would
+		// create a cycle, in both cases we just drop the relation of the merged task.
+		if err != nil && !IsErrRelationAlreadyExists(err) && !IsErrTaskRelationCycle(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update merges the task into the target task
+// @Summary Merge a task into another task
+// @Description Moves all comments, attachments, relations, assignees, labels and votes of a task to the target task. Assignees who do not have access to the target task are dropped. The merged task is marked as done afterwards and gets a "duplicate of" relation to the target task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "The id of the task to merge into the target"
+// @Param merge body models.TaskMerge true "The target task."
+// @Success 200 {object} models.TaskMerge "The target task with everything merged into it."
+// @Failure 400 {object} web.HTTPError "A task cannot be merged into itself."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to one of the tasks."
+// @Failure 404 {object} web.HTTPError "One of the tasks does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/merge [post]
+func (tm *TaskMerge) Update(s *xorm.Session, a web.Auth) (err error) {
+	if tm.TaskID == tm.TargetTaskID {
+		return ErrRelationTasksCannotBeTheSame{
+			TaskID:      tm.TaskID,
+			OtherTaskID: tm.TargetTaskID,
+		}
+	}
+
+	target, err := GetTaskByIDSimple(s, tm.TargetTaskID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("task_id = ?", tm.TaskID).
+		Cols("task_id").
+		NoAutoTime().
+		Update(&TaskComment{TaskID: tm.TargetTaskID})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("task_id = ?", tm.TaskID).
+		Cols("task_id").
+		NoAutoTime().
+		Update(&TaskAttachment{TaskID: tm.TargetTaskID})
+	if err != nil {
+		return err
+	}
+
+	err = tm.moveLabels(s)
+	if err != nil {
+		return err
+	}
+
+	err = tm.moveAssignees(s, &target, a)
+	if err != nil {
+		return err
+	}
+
+	err = tm.moveRelations(s, a)
+	if err != nil {
+		return err
+	}
+
+	err = tm.moveVotes(s)
+	if err != nil {
+		return err
+	}
+
+	duplicate := &TaskRelation{
+		TaskID:       tm.TaskID,
+		OtherTaskID:  tm.TargetTaskID,
+		RelationKind: RelationKindDuplicateOf,
+	}
+	err = duplicate.Create(s, a)
+	if err != nil {
+		return err
+	}
+
+	// A duplicate should not come back once it is done. This needs to happen before the update
+	// because the repeating logic uses the values stored in the database.
+	_, err = s.
+		Where("id = ?", tm.TaskID).
+		Cols("repeat_after", "repeat_rule", "repeat_mode").
+		NoAutoTime().
+		Update(&Task{})
+	if err != nil {
+		return err
+	}
+
+	// Task.Update replaces all fields of a task, so we need to get the full task first
+	source, err := GetTaskByIDSimple(s, tm.TaskID)
+	if err != nil {
+		return err
+	}
+	err = addMoreInfoToTasks(s, map[int64]*Task{source.ID: &source}, a)
+	if err != nil {
+		return err
+	}
+
+	// The attachments were moved to the target, so the cover image cannot stay
+	source.CoverImageAttachmentID = 0
+	source.Done = true
+	err = source.Update(s, a)
+	if err != nil {
+		return err
+	}
+
+	err = updateTaskLastUpdated(s, &target)
+	if err != nil {
+		return err
+	}
+
+	tm.Task = &Task{ID: tm.TargetTaskID}
+	return tm.Task.ReadOne(s, a)
+}
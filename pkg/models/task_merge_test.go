@@ -0,0 +1,218 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskMerge_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tm := &TaskMerge{
+			TaskID:       1,
+			TargetTaskID: 30,
+		}
+		err := tm.Update(s, u)
+		require.NoError(t, err)
+		require.NotNil(t, tm.Task)
+		assert.Equal(t, int64(30), tm.Task.ID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":   1,
+			"done": true,
+		}, false)
+		db.AssertMissing(t, "task_comments", map[string]interface{}{
+			"task_id": 1,
+		})
+		db.AssertMissing(t, "task_attachments", map[string]interface{}{
+			"task_id": 1,
+		})
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"id":      1,
+			"task_id": 30,
+		}, false)
+		db.AssertMissing(t, "label_tasks", map[string]interface{}{
+			"task_id": 1,
+		})
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  30,
+			"label_id": 4,
+		}, false)
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       30,
+			"other_task_id": 29,
+			"relation_kind": RelationKindSubtask,
+		}, false)
+		db.AssertMissing(t, "task_relations", map[string]interface{}{
+			"task_id":       1,
+			"other_task_id": 29,
+		})
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       1,
+			"other_task_id": 30,
+			"relation_kind": RelationKindDuplicateOf,
+		}, false)
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       30,
+			"other_task_id": 1,
+			"relation_kind": RelationKindDuplicates,
+		}, false)
+	})
+	t.Run("with assignees", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tm := &TaskMerge{
+			TaskID:       30,
+			TargetTaskID: 1,
+		}
+		err := tm.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_assignees", map[string]interface{}{
+			"task_id": 1,
+			"user_id": 1,
+		}, false)
+		// User 2 does not have access to the project of the target task
+		db.AssertMissing(t, "task_assignees", map[string]interface{}{
+			"task_id": 1,
+			"user_id": 2,
+		})
+		db.AssertMissing(t, "task_assignees", map[string]interface{}{
+			"task_id": 30,
+		})
+	})
+	t.Run("repeating task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tm := &TaskMerge{
+			TaskID:       28,
+			TargetTaskID: 1,
+		}
+		err := tm.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":           28,
+			"done":         true,
+			"repeat_after": 0,
+		}, false)
+	})
+	t.Run("into itself", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tm := &TaskMerge{
+			TaskID:       1,
+			TargetTaskID: 1,
+		}
+		err := tm.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrRelationTasksCannotBeTheSame(err))
+	})
+}
+
+func TestTaskMerge_CanUpdate(t *testing.T) {
+	t.Run("both tasks writable", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tm := &TaskMerge{TaskID: 1, TargetTaskID: 30}
+		can, err := tm.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("target not writable", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tm := &TaskMerge{TaskID: 1, TargetTaskID: 14}
+		can, err := tm.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestTaskDuplicateCandidate_ReadAll(t *testing.T) {
+	t.Run("similar title", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tdc := &TaskDuplicateCandidate{ProjectID: 1}
+		result, _, _, err := tdc.ReadAll(s, &user.User{ID: 1}, "Task #27 with reminders", 0, 0)
+		require.NoError(t, err)
+		candidates := result.([]*TaskDuplicateCandidate)
+		require.Len(t, candidates, 1)
+		assert.Equal(t, int64(27), candidates[0].Task.ID)
+		assert.InDelta(t, 4.0/7.0, candidates[0].Similarity, 0.001)
+	})
+	t.Run("no similar title", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tdc := &TaskDuplicateCandidate{ProjectID: 1}
+		result, _, _, err := tdc.ReadAll(s, &user.User{ID: 1}, "Something completely different", 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+	t.Run("no access to the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tdc := &TaskDuplicateCandidate{ProjectID: 1}
+		_, _, _, err := tdc.ReadAll(s, &user.User{ID: 13}, "Task #27 with reminders", 0, 0)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+	t.Run("only short words", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tdc := &TaskDuplicateCandidate{ProjectID: 1}
+		result, _, _, err := tdc.ReadAll(s, &user.User{ID: 1}, "a #1", 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
@@ -39,7 +39,7 @@ func getUndoneOverdueTasks(s *xorm.Session, now time.Time) (usersWithTasks map[i
 	err = s.
 		Where("due_date is not null AND due_date < ? AND projects.is_archived = false", nextMinute.Add(time.Hour*14).Format(dbTimeFormat)).
 		Join("LEFT", "projects", "projects.id = tasks.project_id").
-		And("done = false").
+		And("done = false AND tasks.is_archived = false").
 		Find(&tasks)
 	if err != nil {
 		return
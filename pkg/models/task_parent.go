@@ -0,0 +1,341 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TaskParent moves a task below another task
+type TaskParent struct {
+	// The id of the task which should be moved.
+	TaskID int64 `json:"-" param:"projecttask"`
+	// The id of the new parent task. Set this to 0 to make the task a top-level task again.
+	ParentTaskID int64 `json:"parent_task_id"`
+
+	// The task with its new parent.
+	Task *Task `json:"task"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanUpdate checks if the user can move the task below the new parent.
+// This needs write access to the task and at least read access to the parent task.
+func (tp *TaskParent) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	t := &Task{ID: tp.TaskID}
+	can, err := t.CanUpdate(s, a)
+	if err != nil || !can {
+		return can, err
+	}
+
+	if tp.ParentTaskID == 0 {
+		return true, nil
+	}
+
+	parent := &Task{ID: tp.ParentTaskID}
+	can, _, err = parent.CanRead(s, a)
+	return can, err
+}
+
+// Update sets the parent of a task
+// @Summary Move a task below another task
+// @Description Sets the parent of a task. The task relations are updated accordingly, which means the task will show up as a subtask of its new parent. Set the parent task id to 0 to make the task a top-level task again.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "The task id"
+// @Param parent body models.TaskParent true "The new parent task."
+// @Success 200 {object} models.TaskParent "The task with its new parent."
+// @Failure 400 {object} web.HTTPError "The hierarchy would become too deep."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task or the parent task."
+// @Failure 404 {object} web.HTTPError "The task or parent task does not exist."
+// @Failure 409 {object} web.HTTPError "The task cannot be moved below one of its own subtasks."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/parent [post]
+func (tp *TaskParent) Update(s *xorm.Session, a web.Auth) (err error) {
+	t, err := GetTaskByIDSimple(s, tp.TaskID)
+	if err != nil {
+		return err
+	}
+
+	err = setTaskParent(s, &t, tp.ParentTaskID, a)
+	if err != nil {
+		return err
+	}
+
+	tp.Task = &Task{ID: t.ID}
+	err = tp.Task.ReadOne(s, a)
+	if err != nil {
+		return err
+	}
+
+	return updateProjectLastUpdated(s, &Project{ID: t.ProjectID})
+}
+
+// validateParentTask checks if a task can be moved below the parent task without creating
+// a cycle or exceeding the configured maximum depth of the hierarchy.
+func validateParentTask(s *xorm.Session, t *Task, parentTaskID int64) error {
+	if t.ID != 0 && t.ID == parentTaskID {
+		return ErrParentTaskCannotBeTheSame{TaskID: t.ID}
+	}
+
+	maxDepth := config.ServiceMaxTaskDepth.GetInt64()
+
+	// Walk up from the new parent to find out how deep the task would be placed
+	depth := int64(1)
+	visited := make(map[int64]bool)
+	currentID := parentTaskID
+	for currentID != 0 {
+		if t.ID != 0 && currentID == t.ID {
+			return ErrTaskParentCycle{TaskID: t.ID, ParentTaskID: parentTaskID}
+		}
+		if visited[currentID] {
+			// An existing cycle in the data, nothing we could fix here
+			break
+		}
+		visited[currentID] = true
+
+		current, err := GetTaskByIDSimple(s, currentID)
+		if err != nil {
+			return err
+		}
+		if current.ParentTaskID != 0 {
+			depth++
+		}
+		currentID = current.ParentTaskID
+	}
+
+	// All subtasks of the task move with it, so we need to account for them as well
+	if t.ID != 0 {
+		levelIDs := []int64{t.ID}
+		for len(levelIDs) > 0 {
+			children := []int64{}
+			err := s.
+				Table("tasks").
+				In("parent_task_id", levelIDs).
+				Cols("id").
+				Find(&children)
+			if err != nil {
+				return err
+			}
+			if len(children) == 0 {
+				break
+			}
+			depth++
+			if depth > maxDepth {
+				break
+			}
+			levelIDs = children
+		}
+	}
+
+	if depth > maxDepth {
+		return ErrTaskHierarchyTooDeep{TaskID: t.ID, MaxDepth: maxDepth}
+	}
+
+	return nil
+}
+
+// setTaskParent validates and saves the new parent of a task and keeps the
+// subtask and parenttask relations in sync with it.
+func setTaskParent(s *xorm.Session, t *Task, parentTaskID int64, a web.Auth) (err error) {
+	if t.ParentTaskID == parentTaskID {
+		return nil
+	}
+
+	if parentTaskID != 0 {
+		err = validateParentTask(s, t, parentTaskID)
+		if err != nil {
+			return err
+		}
+	}
+
+	oldParentTaskID := t.ParentTaskID
+	if t.ParentTaskID != 0 {
+		rel := &TaskRelation{
+			TaskID:       t.ID,
+			OtherTaskID:  t.ParentTaskID,
+			RelationKind: RelationKindParenttask,
+		}
+		err = rel.Delete(s, a)
+		if err != nil && !IsErrRelationDoesNotExist(err) {
+			return err
+		}
+	}
+
+	t.ParentTaskID = parentTaskID
+	_, err = s.
+		Where("id = ?", t.ID).
+		Cols("parent_task_id").
+		NoAutoTime().
+		Update(t)
+	if err != nil {
+		return err
+	}
+
+	err = updateAutomaticPercentDoneByID(s, oldParentTaskID)
+	if err != nil {
+		return err
+	}
+	err = updateAutomaticPercentDoneByID(s, parentTaskID)
+	if err != nil {
+		return err
+	}
+
+	if parentTaskID == 0 {
+		return nil
+	}
+
+	return createParentTaskRelation(s, t.ID, parentTaskID, a)
+}
+
+// createParentTaskRelation creates the subtask and parenttask relations between a task and its parent
+// so that clients which only know about relations still see the hierarchy.
+func createParentTaskRelation(s *xorm.Session, taskID, parentTaskID int64, a web.Auth) error {
+	exists, err := s.
+		Where("task_id = ? AND other_task_id = ? AND relation_kind = ?", taskID, parentTaskID, RelationKindParenttask).
+		Exist(&TaskRelation{})
+	if err != nil || exists {
+		return err
+	}
+
+	rel := &TaskRelation{
+		TaskID:       taskID,
+		OtherTaskID:  parentTaskID,
+		RelationKind: RelationKindParenttask,
+	}
+	return rel.Create(s, a)
+}
+
+// syncParentTaskFromRelation updates the parent of a task when a subtask or parenttask relation was created
+// or removed through the relation endpoints. When a task already has a parent, a new relation does not change it.
+// The same applies if the hierarchy would become too deep, the relation is still created in that case.
+func syncParentTaskFromRelation(s *xorm.Session, rel *TaskRelation, deleted bool) error {
+	var childID, parentID int64
+	switch rel.RelationKind {
+	case RelationKindSubtask:
+		childID, parentID = rel.OtherTaskID, rel.TaskID
+	case RelationKindParenttask:
+		childID, parentID = rel.TaskID, rel.OtherTaskID
+	case RelationKindUnknown,
+		RelationKindRelated,
+		RelationKindDuplicateOf,
+		RelationKindDuplicates,
+		RelationKindBlocking,
+		RelationKindBlocked,
+		RelationKindPreceeds,
+		RelationKindFollows,
+		RelationKindCopiedFrom,
+		RelationKindCopiedTo,
+		RelationKindCausedBy,
+		RelationKindCauses,
+		RelationKindFixes,
+		RelationKindFixedBy:
+		return nil
+	}
+
+	if deleted {
+		_, err := s.
+			Where("id = ? AND parent_task_id = ?", childID, parentID).
+			Cols("parent_task_id").
+			NoAutoTime().
+			Update(&Task{ParentTaskID: 0})
+		if err != nil {
+			return err
+		}
+		return updateAutomaticPercentDoneByID(s, parentID)
+	}
+
+	child, err := GetTaskByIDSimple(s, childID)
+	if err != nil {
+		return err
+	}
+	if child.ParentTaskID != 0 {
+		return nil
+	}
+
+	err = validateParentTask(s, &child, parentID)
+	if IsErrTaskHierarchyTooDeep(err) || IsErrTaskParentCycle(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("id = ?", childID).
+		Cols("parent_task_id").
+		NoAutoTime().
+		Update(&Task{ParentTaskID: parentID})
+	if err != nil {
+		return err
+	}
+
+	return updateAutomaticPercentDoneByID(s, parentID)
+}
+
+// addSubtasksToTask adds all direct subtasks of a task the user has access to.
+func addSubtasksToTask(s *xorm.Session, t *Task, a web.Auth) error {
+	subtasks := []*Task{}
+	err := s.
+		Where(builder.Eq{"parent_task_id": t.ID}).
+		OrderBy("position asc, id asc").
+		Find(&subtasks)
+	if err != nil {
+		return err
+	}
+
+	t.Subtasks = make([]*Task, 0, len(subtasks))
+	if len(subtasks) == 0 {
+		return nil
+	}
+
+	// Subtasks may live in other projects, only show those the user can see
+	canReadProject := make(map[int64]bool)
+	taskMap := make(map[int64]*Task, len(subtasks))
+	for _, st := range subtasks {
+		can, has := canReadProject[st.ProjectID]
+		if !has {
+			can, _, err = (&Project{ID: st.ProjectID}).CanRead(s, a)
+			if err != nil && !IsErrProjectDoesNotExist(err) {
+				return err
+			}
+			canReadProject[st.ProjectID] = can
+		}
+		if can {
+			taskMap[st.ID] = st
+		}
+	}
+
+	err = addMoreInfoToTasks(s, taskMap, a)
+	if err != nil {
+		return err
+	}
+
+	for _, st := range subtasks {
+		if subtask, has := taskMap[st.ID]; has {
+			t.Subtasks = append(t.Subtasks, subtask)
+		}
+	}
+
+	return nil
+}
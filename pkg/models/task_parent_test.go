@@ -0,0 +1,291 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskParent_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 2, ParentTaskID: 1}
+		err := tp.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), tp.Task.ParentTaskID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":             2,
+			"parent_task_id": 1,
+		}, false)
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       2,
+			"other_task_id": 1,
+			"relation_kind": RelationKindParenttask,
+		}, false)
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       1,
+			"other_task_id": 2,
+			"relation_kind": RelationKindSubtask,
+		}, false)
+
+		task := &Task{ID: 1}
+		err = task.ReadOne(s, u)
+		require.NoError(t, err)
+		require.Len(t, task.Subtasks, 1)
+		assert.Equal(t, int64(2), task.Subtasks[0].ID)
+	})
+	t.Run("change parent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 2, ParentTaskID: 1}
+		err := tp.Update(s, u)
+		require.NoError(t, err)
+		tp = &TaskParent{TaskID: 2, ParentTaskID: 3}
+		err = tp.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":             2,
+			"parent_task_id": 3,
+		}, false)
+		db.AssertMissing(t, "task_relations", map[string]interface{}{
+			"task_id":       2,
+			"other_task_id": 1,
+			"relation_kind": RelationKindParenttask,
+		})
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       3,
+			"other_task_id": 2,
+			"relation_kind": RelationKindSubtask,
+		}, false)
+	})
+	t.Run("remove parent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 2, ParentTaskID: 1}
+		err := tp.Update(s, u)
+		require.NoError(t, err)
+		tp = &TaskParent{TaskID: 2, ParentTaskID: 0}
+		err = tp.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":             2,
+			"parent_task_id": 0,
+		}, false)
+		db.AssertMissing(t, "task_relations", map[string]interface{}{
+			"task_id":       1,
+			"other_task_id": 2,
+			"relation_kind": RelationKindSubtask,
+		})
+	})
+	t.Run("same task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 1, ParentTaskID: 1}
+		err := tp.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrParentTaskCannotBeTheSame(err))
+	})
+	t.Run("cycle", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 2, ParentTaskID: 1}
+		err := tp.Update(s, u)
+		require.NoError(t, err)
+		tp = &TaskParent{TaskID: 3, ParentTaskID: 2}
+		err = tp.Update(s, u)
+		require.NoError(t, err)
+
+		tp = &TaskParent{TaskID: 1, ParentTaskID: 3}
+		err = tp.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskParentCycle(err))
+	})
+	t.Run("too deep", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.ServiceMaxTaskDepth.Set(1)
+		defer config.ServiceMaxTaskDepth.Set(10)
+
+		tp := &TaskParent{TaskID: 3, ParentTaskID: 2}
+		err := tp.Update(s, u)
+		require.NoError(t, err)
+
+		// Moving task 2 below task 1 would put task 3 two levels deep
+		tp = &TaskParent{TaskID: 2, ParentTaskID: 1}
+		err = tp.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskHierarchyTooDeep(err))
+	})
+	t.Run("nonexisting parent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 2, ParentTaskID: 9999}
+		err := tp.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskDoesNotExist(err))
+	})
+}
+
+func TestTaskParent_CanUpdate(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 2, ParentTaskID: 1}
+		can, err := tp.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("no write access to the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 15, ParentTaskID: 1}
+		can, err := tp.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("no read access to the parent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 1, ParentTaskID: 14}
+		can, err := tp.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestTaskParent_Relations(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("creating a subtask relation sets the parent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rel := &TaskRelation{TaskID: 1, OtherTaskID: 2, RelationKind: RelationKindSubtask}
+		err := rel.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":             2,
+			"parent_task_id": 1,
+		}, false)
+	})
+	t.Run("deleting the relation removes the parent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 2, ParentTaskID: 1}
+		err := tp.Update(s, u)
+		require.NoError(t, err)
+
+		rel := &TaskRelation{TaskID: 1, OtherTaskID: 2, RelationKind: RelationKindSubtask}
+		err = rel.Delete(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":             2,
+			"parent_task_id": 0,
+		}, false)
+	})
+	t.Run("create a task with a parent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:        "Lorem",
+			ProjectID:    1,
+			ParentTaskID: 1,
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       1,
+			"other_task_id": task.ID,
+			"relation_kind": RelationKindSubtask,
+		}, false)
+	})
+	t.Run("deleting the parent makes subtasks top-level tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskParent{TaskID: 2, ParentTaskID: 1}
+		err := tp.Update(s, u)
+		require.NoError(t, err)
+
+		task := &Task{ID: 1}
+		err = task.Delete(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":             2,
+			"parent_task_id": 0,
+		}, false)
+	})
+}
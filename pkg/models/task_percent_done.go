@@ -0,0 +1,137 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"math"
+
+	"xorm.io/xorm"
+)
+
+// TaskPercentDoneMode defines how the percent done of a task is determined
+type TaskPercentDoneMode string
+
+const (
+	// TaskPercentDoneModeManual lets users set the percent done of a task. This is the default.
+	TaskPercentDoneModeManual TaskPercentDoneMode = "manual"
+	// TaskPercentDoneModeSubtasks derives the percent done from how many direct subtasks of the task are done.
+	TaskPercentDoneModeSubtasks TaskPercentDoneMode = "subtasks"
+	// TaskPercentDoneModeChecklist derives the percent done from how many checklist items of the task are done.
+	TaskPercentDoneModeChecklist TaskPercentDoneMode = "checklist"
+	// TaskPercentDoneModeSubtasksAndChecklist derives the percent done from the done subtasks and checklist items together.
+	TaskPercentDoneModeSubtasksAndChecklist TaskPercentDoneMode = "subtasks_and_checklist"
+)
+
+func (m TaskPercentDoneMode) validate() error {
+	switch m {
+	case "",
+		TaskPercentDoneModeManual,
+		TaskPercentDoneModeSubtasks,
+		TaskPercentDoneModeChecklist,
+		TaskPercentDoneModeSubtasksAndChecklist:
+		return nil
+	}
+	return ErrInvalidTaskPercentDoneMode{Mode: m}
+}
+
+func (m TaskPercentDoneMode) isAutomatic() bool {
+	return m != "" && m != TaskPercentDoneModeManual
+}
+
+func (m TaskPercentDoneMode) usesSubtasks() bool {
+	return m == TaskPercentDoneModeSubtasks || m == TaskPercentDoneModeSubtasksAndChecklist
+}
+
+func (m TaskPercentDoneMode) usesChecklist() bool {
+	return m == TaskPercentDoneModeChecklist || m == TaskPercentDoneModeSubtasksAndChecklist
+}
+
+// calculatePercentDone returns the percent done of a task according to its percent done mode.
+// Tasks without any subtasks or checklist items are 0% done.
+func (t *Task) calculatePercentDone(s *xorm.Session) (percentDone float64, err error) {
+	var total, done int64
+
+	if t.PercentDoneMode.usesSubtasks() {
+		subtasks, err := s.Where("parent_task_id = ?", t.ID).Count(&Task{})
+		if err != nil {
+			return 0, err
+		}
+		doneSubtasks, err := s.Where("parent_task_id = ? AND done = ?", t.ID, true).Count(&Task{})
+		if err != nil {
+			return 0, err
+		}
+		total += subtasks
+		done += doneSubtasks
+	}
+
+	if t.PercentDoneMode.usesChecklist() {
+		items, err := s.Where("task_id = ?", t.ID).Count(&ChecklistItem{})
+		if err != nil {
+			return 0, err
+		}
+		doneItems, err := s.Where("task_id = ? AND done = ?", t.ID, true).Count(&ChecklistItem{})
+		if err != nil {
+			return 0, err
+		}
+		total += items
+		done += doneItems
+	}
+
+	if total == 0 {
+		return 0, nil
+	}
+
+	return math.Round(float64(done)/float64(total)*100) / 100, nil
+}
+
+// updateAutomaticPercentDone recalculates and stores the percent done of a task if it is derived automatically.
+func (t *Task) updateAutomaticPercentDone(s *xorm.Session) (err error) {
+	if !t.PercentDoneMode.isAutomatic() {
+		return nil
+	}
+
+	percentDone, err := t.calculatePercentDone(s)
+	if err != nil || percentDone == t.PercentDone {
+		return err
+	}
+
+	t.PercentDone = percentDone
+	_, err = s.
+		Where("id = ?", t.ID).
+		Cols("percent_done").
+		Update(&Task{PercentDone: percentDone})
+	return err
+}
+
+// updateAutomaticPercentDoneByID does the same as updateAutomaticPercentDone for a task which is not loaded yet.
+// This needs to be called whenever a subtask or checklist item of the task changes.
+func updateAutomaticPercentDoneByID(s *xorm.Session, taskID int64) error {
+	if taskID == 0 {
+		return nil
+	}
+
+	t := &Task{}
+	exists, err := s.
+		Where("id = ?", taskID).
+		Cols("id", "percent_done", "percent_done_mode").
+		Get(t)
+	if err != nil || !exists {
+		return err
+	}
+
+	return t.updateAutomaticPercentDone(s)
+}
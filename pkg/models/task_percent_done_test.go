@@ -0,0 +1,124 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask_PercentDoneMode(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("checklist", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:              1,
+			Title:           "test",
+			ProjectID:       1,
+			PercentDone:     0.9,
+			PercentDoneMode: TaskPercentDoneModeChecklist,
+		}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.33, task.PercentDone, 0.001)
+
+		item := &ChecklistItem{ID: 2, TaskID: 1, Title: "Ipsum", Done: true}
+		err = item.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":                1,
+			"percent_done":      0.67,
+			"percent_done_mode": TaskPercentDoneModeChecklist,
+		}, false)
+	})
+	t.Run("checklist on create", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:           "test",
+			ProjectID:       1,
+			PercentDone:     0.9,
+			PercentDoneMode: TaskPercentDoneModeChecklist,
+			ChecklistItems: []*ChecklistItem{
+				{Title: "one", Done: true},
+				{Title: "two"},
+			},
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.5, task.PercentDone, 0.001)
+	})
+	t.Run("subtasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		parent := &Task{
+			Title:           "parent",
+			ProjectID:       1,
+			PercentDoneMode: TaskPercentDoneModeSubtasks,
+		}
+		err := parent.Create(s, u)
+		require.NoError(t, err)
+
+		first := &Task{Title: "first", ProjectID: 1, ParentTaskID: parent.ID}
+		err = first.Create(s, u)
+		require.NoError(t, err)
+		second := &Task{Title: "second", ProjectID: 1, ParentTaskID: parent.ID}
+		err = second.Create(s, u)
+		require.NoError(t, err)
+
+		first.Done = true
+		err = first.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":           parent.ID,
+			"percent_done": 0.5,
+		}, false)
+	})
+	t.Run("invalid mode", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:           "test",
+			ProjectID:       1,
+			PercentDoneMode: "invalid",
+		}
+		err := task.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskPercentDoneMode(err))
+	})
+}
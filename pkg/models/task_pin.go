@@ -0,0 +1,137 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"math"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskPin pins a task to the top of all list and kanban views of its project.
+type TaskPin struct {
+	// The task to pin.
+	TaskID int64 `json:"-" param:"task"`
+	// The position of the task among the other pinned tasks of the project. If not provided, an already pinned task keeps its position and all other tasks are pinned below the pinned tasks of the project.
+	PinnedPosition float64 `json:"pinned_position"`
+
+	// The pinned task.
+	Task *Task `json:"task"`
+
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+// CanUpdate checks if the user can pin the task
+func (tp *TaskPin) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	t := &Task{ID: tp.TaskID}
+	return t.CanUpdate(s, a)
+}
+
+// CanDelete checks if the user can unpin the task
+func (tp *TaskPin) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	t := &Task{ID: tp.TaskID}
+	return t.CanUpdate(s, a)
+}
+
+// Update pins a task or changes its position among the pinned tasks
+// @Summary Pin a task
+// @Description Pins a task so that it is always shown above all other tasks in the list and kanban views of its project, for everyone with access to the project. Pinned tasks are sorted by their `pinned_position` among each other. Pinning an already pinned task with a new `pinned_position` moves it.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param pin body models.TaskPin true "The position of the pinned task."
+// @Success 200 {object} models.TaskPin "The task has been pinned."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/pin [post]
+func (tp *TaskPin) Update(s *xorm.Session, _ web.Auth) (err error) {
+	task, err := GetTaskByIDSimple(s, tp.TaskID)
+	if err != nil {
+		return err
+	}
+
+	if tp.PinnedPosition == 0 && task.IsPinned {
+		tp.PinnedPosition = task.PinnedPosition
+	}
+	if tp.PinnedPosition == 0 {
+		tp.PinnedPosition, err = getNextPinnedPosition(s, &task)
+		if err != nil {
+			return err
+		}
+	}
+
+	task.IsPinned = true
+	task.PinnedPosition = tp.PinnedPosition
+	return setTaskPinned(s, tp, &task)
+}
+
+// Delete unpins a task
+// @Summary Unpin a task
+// @Description Unpins a task so that it is sorted like all other tasks again.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} models.Message "The task has been unpinned."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/pin [delete]
+func (tp *TaskPin) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	task, err := GetTaskByIDSimple(s, tp.TaskID)
+	if err != nil {
+		return err
+	}
+
+	task.IsPinned = false
+	task.PinnedPosition = 0
+	return setTaskPinned(s, tp, &task)
+}
+
+func setTaskPinned(s *xorm.Session, tp *TaskPin, task *Task) (err error) {
+	_, err = s.ID(task.ID).
+		Cols("is_pinned", "pinned_position").
+		Update(task)
+	if err != nil {
+		return err
+	}
+
+	tp.Task = task
+	return updateProjectByTaskID(s, task.ID)
+}
+
+// getNextPinnedPosition returns a position below all pinned tasks of the project of the task.
+func getNextPinnedPosition(s *xorm.Session, task *Task) (position float64, err error) {
+	last := &Task{}
+	exists, err := s.
+		Where("project_id = ? AND is_pinned = ? AND id != ?", task.ProjectID, true, task.ID).
+		OrderBy("pinned_position desc").
+		Get(last)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return math.Pow(2, 16), nil
+	}
+
+	return last.PinnedPosition + math.Pow(2, 16), nil
+}
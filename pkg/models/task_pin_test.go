@@ -0,0 +1,168 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskPin_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("pin below other pinned tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		first := &TaskPin{TaskID: 1}
+		err := first.Update(s, u)
+		require.NoError(t, err)
+		second := &TaskPin{TaskID: 3}
+		err = second.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.True(t, second.Task.IsPinned)
+		assert.Greater(t, second.PinnedPosition, first.PinnedPosition)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        3,
+			"is_pinned": true,
+		}, false)
+	})
+	t.Run("keep position when pinning again", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pin := &TaskPin{TaskID: 1, PinnedPosition: 42}
+		err := pin.Update(s, u)
+		require.NoError(t, err)
+		pin = &TaskPin{TaskID: 1}
+		err = pin.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, float64(42), pin.PinnedPosition)
+	})
+	t.Run("nonexisting task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pin := &TaskPin{TaskID: 9999}
+		err := pin.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskDoesNotExist(err))
+	})
+	t.Run("no write access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// Task 15 is in project 6 to which user 1 only has read access
+		pin := &TaskPin{TaskID: 15}
+		can, err := pin.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestTaskPin_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	u := &user.User{ID: 1}
+	pin := &TaskPin{TaskID: 1}
+	err := pin.Update(s, u)
+	require.NoError(t, err)
+	err = pin.Delete(s, u)
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertExists(t, "tasks", map[string]interface{}{
+		"id":        1,
+		"is_pinned": false,
+	}, false)
+}
+
+func TestTaskPin_Sorting(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("list view", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskPin{TaskID: 10, PinnedPosition: 2}).Update(s, u)
+		require.NoError(t, err)
+		err = (&TaskPin{TaskID: 4, PinnedPosition: 1}).Update(s, u)
+		require.NoError(t, err)
+
+		tc := &TaskCollection{
+			ProjectID: 1,
+			SortBy:    []string{"id"},
+			OrderBy:   []string{"asc"},
+		}
+		result, _, _, err := tc.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.Greater(t, len(tasks), 2)
+		assert.Equal(t, int64(4), tasks[0].ID)
+		assert.Equal(t, int64(10), tasks[1].ID)
+		assert.Equal(t, int64(1), tasks[2].ID)
+	})
+	t.Run("not in views of multiple projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskPin{TaskID: 10}).Update(s, u)
+		require.NoError(t, err)
+
+		tc := &TaskCollection{
+			SortBy:  []string{"id"},
+			OrderBy: []string{"asc"},
+		}
+		result, _, _, err := tc.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.NotEmpty(t, tasks)
+		assert.Equal(t, int64(1), tasks[0].ID)
+	})
+	t.Run("kanban view", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskPin{TaskID: 5}).Update(s, u)
+		require.NoError(t, err)
+
+		b := &Bucket{ProjectID: 1}
+		bucketsInterface, _, _, err := b.ReadAll(s, u, "", 0, 0)
+		require.NoError(t, err)
+		buckets := bucketsInterface.([]*Bucket)
+		require.Len(t, buckets[1].Tasks, 3)
+		assert.Equal(t, int64(5), buckets[1].Tasks[0].ID)
+	})
+}
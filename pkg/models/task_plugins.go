@@ -0,0 +1,85 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/modules/plugins"
+)
+
+func timeToPluginValue(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func timeFromPluginValue(value interface{}) time.Time {
+	unix, _ := value.(int64)
+	if unix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
+
+// runPluginHook passes the task to the hook of all plugins and applies the changes they made to it.
+// The id, project id and bucket id of the task are read only. Dates are passed as unix timestamps, 0 if unset.
+func (t *Task) runPluginHook(hook plugins.Hook) (err error) {
+	if !plugins.Enabled() {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":           t.ID,
+		"project_id":   t.ProjectID,
+		"bucket_id":    t.BucketID,
+		"title":        t.Title,
+		"description":  t.Description,
+		"done":         t.Done,
+		"priority":     t.Priority,
+		"percent_done": t.PercentDone,
+		"hex_color":    t.HexColor,
+		"due_date":     timeToPluginValue(t.DueDate),
+		"start_date":   timeToPluginValue(t.StartDate),
+		"end_date":     timeToPluginValue(t.EndDate),
+	}
+
+	err = plugins.Run(hook, data)
+	if veto, is := err.(*plugins.VetoError); is {
+		return &ErrTaskVetoedByPlugin{TaskID: t.ID, Plugin: veto.Plugin, Reason: veto.Reason}
+	}
+	if err != nil {
+		return err
+	}
+
+	if hook == plugins.HookTaskDelete {
+		return nil
+	}
+
+	t.Title = data["title"].(string)
+	t.Description = data["description"].(string)
+	t.Done = data["done"].(bool)
+	t.Priority = data["priority"].(int64)
+	t.PercentDone = data["percent_done"].(float64)
+	t.HexColor = data["hex_color"].(string)
+	t.DueDate = timeFromPluginValue(data["due_date"])
+	t.StartDate = timeFromPluginValue(data["start_date"])
+	t.EndDate = timeFromPluginValue(data["end_date"])
+
+	return nil
+}
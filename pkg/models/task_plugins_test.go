@@ -0,0 +1,97 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/modules/plugins"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask_runPluginHook(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "rules.lua"), []byte(`
+		function on_task_create(task)
+			if task.title == "forbidden" then
+				return false, "this title is not allowed"
+			end
+			task.priority = 2
+			task.due_date = 1543622400
+		end
+		function on_task_delete(task)
+			if task.priority == 100 then
+				return false, "high priority tasks can't be deleted"
+			end
+		end
+	`), 0o600)
+	require.NoError(t, err)
+
+	config.PluginsEnabled.Set(true)
+	config.PluginsPath.Set(dir)
+	defer func() {
+		config.PluginsEnabled.Set(false)
+		_ = plugins.Init()
+	}()
+	err = plugins.Init()
+	require.NoError(t, err)
+
+	u := &user.User{ID: 1}
+
+	t.Run("change task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "Plugin task", ProjectID: 1}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), task.Priority)
+		assert.Equal(t, int64(1543622400), task.DueDate.Unix())
+	})
+	t.Run("veto create", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "forbidden", ProjectID: 1}
+		err := task.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskVetoedByPlugin(err))
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"title": "forbidden",
+		})
+	})
+	t.Run("veto delete", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// Task 3 has priority 100
+		task := &Task{ID: 3}
+		err := task.Delete(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskVetoedByPlugin(err))
+	})
+}
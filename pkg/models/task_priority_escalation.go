@@ -0,0 +1,168 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+const maxTaskPriority = 5
+
+// PriorityEscalationRule raises the priority of overdue tasks
+type PriorityEscalationRule struct {
+	// The time in seconds a task needs to be overdue before its priority is raised.
+	OverdueFor int64 `json:"overdue_for"`
+	// The priority an overdue task is raised to. Between 1 and 5.
+	Priority int64 `json:"priority"`
+}
+
+// validatePriorityEscalation checks all escalation rules of a project are valid
+func (p *Project) validatePriorityEscalation() error {
+	for _, rule := range p.PriorityEscalation {
+		if rule.OverdueFor <= 0 || rule.Priority < 1 || rule.Priority > maxTaskPriority {
+			return &ErrInvalidPriorityEscalationRule{ProjectID: p.ID, OverdueFor: rule.OverdueFor, Priority: rule.Priority}
+		}
+	}
+
+	return nil
+}
+
+// escalatedPriority returns the priority a task should have according to the rules of its project.
+// Priorities are only raised, never lowered.
+func escalatedPriority(rules []*PriorityEscalationRule, t *Task, now time.Time) (priority int64, overdueFor time.Duration) {
+	priority = t.Priority
+	overdueFor = now.Sub(t.DueDate)
+	for _, rule := range rules {
+		if overdueFor >= time.Duration(rule.OverdueFor)*time.Second && rule.Priority > priority {
+			priority = rule.Priority
+		}
+	}
+
+	return
+}
+
+// escalateOverdueTaskPriorities raises the priority of all undone overdue tasks in projects with escalation rules.
+func escalateOverdueTaskPriorities(s *xorm.Session, now time.Time) (escalated int, err error) {
+	projects := []*Project{}
+	err = s.
+		Where("is_archived = ? AND priority_escalation IS NOT NULL", false).
+		Find(&projects)
+	if err != nil {
+		return 0, err
+	}
+
+	projectIDs := []int64{}
+	projectMap := make(map[int64]*Project, len(projects))
+	for _, p := range projects {
+		if len(p.PriorityEscalation) == 0 {
+			continue
+		}
+		projectIDs = append(projectIDs, p.ID)
+		projectMap[p.ID] = p
+	}
+
+	if len(projectIDs) == 0 {
+		return 0, nil
+	}
+
+	tasks := []*Task{}
+	err = s.
+		Where(builder.And(
+			builder.In("project_id", projectIDs),
+			builder.Eq{"done": false},
+			builder.Eq{"is_archived": false},
+			builder.NotNull{"due_date"},
+			builder.Lt{"due_date": now},
+			builder.Or(builder.IsNull{"priority"}, builder.Lt{"priority": maxTaskPriority}),
+		)).
+		Find(&tasks)
+	if err != nil {
+		return 0, err
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+
+	for _, t := range tasks {
+		project := projectMap[t.ProjectID]
+		priority, overdueFor := escalatedPriority(project.PriorityEscalation, t, now)
+		if priority == t.Priority {
+			continue
+		}
+
+		oldPriority := t.Priority
+		t.Priority = priority
+		_, err = s.ID(t.ID).Cols("priority").Update(t)
+		if err != nil {
+			return escalated, err
+		}
+
+		err = events.DispatchOnCommit(s, &TaskPriorityEscalatedEvent{
+			Task:        t,
+			Project:     project,
+			OldPriority: oldPriority,
+			NewPriority: priority,
+			OverdueFor:  int64(overdueFor.Seconds()),
+		})
+		if err != nil {
+			return escalated, err
+		}
+
+		escalated++
+	}
+
+	return escalated, nil
+}
+
+// RegisterPriorityEscalationCron registers a function which periodically raises the priority of overdue tasks.
+func RegisterPriorityEscalationCron() {
+	const logPrefix = "[Priority Escalation Cron] "
+
+	err := cron.Schedule("*/10 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		escalated, err := escalateOverdueTaskPriorities(s, time.Now())
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not escalate task priorities: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit escalated task priorities: %s", err)
+			return
+		}
+
+		if escalated > 0 {
+			log.Debugf(logPrefix+"Raised the priority of %d overdue tasks", escalated)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register priority escalation cron: %s", err)
+	}
+}
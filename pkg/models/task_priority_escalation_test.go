@@ -0,0 +1,163 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestEscalateOverdueTaskPriorities(t *testing.T) {
+	setRules := func(t *testing.T, s *xorm.Session) {
+		_, err := s.ID(1).Cols("priority_escalation").Update(&Project{
+			PriorityEscalation: []*PriorityEscalationRule{
+				{OverdueFor: 86400, Priority: 3},
+				{OverdueFor: 7 * 86400, Priority: 5},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("raises the priority", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRules(t, s)
+
+		// Task 5 is due 2018-12-01 03:58:44, task 6 2018-11-30 22:25:24
+		escalated, err := escalateOverdueTaskPriorities(s, time.Date(2018, 12, 8, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 2, escalated)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":       5,
+			"priority": 3,
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":       6,
+			"priority": 5,
+		}, false)
+		events.AssertDispatched(t, &TaskPriorityEscalatedEvent{})
+	})
+	t.Run("not overdue long enough", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRules(t, s)
+
+		escalated, err := escalateOverdueTaskPriorities(s, time.Date(2018, 12, 1, 12, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 0, escalated)
+	})
+	t.Run("does not lower priorities", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRules(t, s)
+
+		_, err := s.ID(5).Cols("priority").Update(&Task{Priority: 4})
+		require.NoError(t, err)
+
+		escalated, err := escalateOverdueTaskPriorities(s, time.Date(2018, 12, 3, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 1, escalated)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":       5,
+			"priority": 4,
+		}, false)
+	})
+	t.Run("archived project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRules(t, s)
+
+		_, err := s.ID(1).Cols("is_archived").Update(&Project{IsArchived: true})
+		require.NoError(t, err)
+
+		escalated, err := escalateOverdueTaskPriorities(s, time.Date(2018, 12, 8, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 0, escalated)
+	})
+}
+
+func TestProject_validatePriorityEscalation(t *testing.T) {
+	usr := &user.User{ID: 1}
+
+	t.Run("invalid priority", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project := &Project{
+			Title:              "test",
+			PriorityEscalation: []*PriorityEscalationRule{{OverdueFor: 3600, Priority: 6}},
+		}
+		err := project.Create(s, usr)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidPriorityEscalationRule(err))
+	})
+	t.Run("invalid overdue time", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project := &Project{
+			ID:                 1,
+			Title:              "test",
+			PriorityEscalation: []*PriorityEscalationRule{{OverdueFor: 0, Priority: 3}},
+		}
+		err := project.Update(s, usr)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidPriorityEscalationRule(err))
+	})
+	t.Run("valid", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project := &Project{
+			ID:                 1,
+			Title:              "test",
+			PriorityEscalation: []*PriorityEscalationRule{{OverdueFor: 3600, Priority: 3}},
+		}
+		err := project.Update(s, usr)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		s = db.NewSession()
+		defer s.Close()
+		p, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		require.Len(t, p.PriorityEscalation, 1)
+		assert.Equal(t, int64(3), p.PriorityEscalation[0].Priority)
+	})
+}
@@ -0,0 +1,501 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// QuickAddMagicMode defines which prefixes are used to parse a task text with quick add magic
+type QuickAddMagicMode string
+
+const (
+	// QuickAddMagicModeDisabled does not parse the text at all.
+	QuickAddMagicModeDisabled QuickAddMagicMode = "disabled"
+	// QuickAddMagicModeVikunja uses `*` for labels, `+` for projects, `!` for priorities and `@` for assignees.
+	QuickAddMagicModeVikunja QuickAddMagicMode = "vikunja"
+	// QuickAddMagicModeTodoist uses `@` for labels, `#` for projects, `!` for priorities and `+` for assignees.
+	QuickAddMagicModeTodoist QuickAddMagicMode = "todoist"
+)
+
+type quickAddPrefixes struct {
+	label    string
+	project  string
+	priority string
+	assignee string
+}
+
+var quickAddPrefixesByMode = map[QuickAddMagicMode]*quickAddPrefixes{
+	QuickAddMagicModeVikunja: {
+		label:    "*",
+		project:  "+",
+		priority: "!",
+		assignee: "@",
+	},
+	QuickAddMagicModeTodoist: {
+		label:    "@",
+		project:  "#",
+		priority: "!",
+		assignee: "+",
+	},
+}
+
+var quickAddRepeatRegex = regexp.MustCompile(`(?i)(^| )(((every|each) (([0-9]+|one|two|three|four|five|six|seven|eight|nine|ten) )?(hours?|days?|weeks?|months?|years?))|(annually|biannually|semiannually|biennially|daily|hourly|monthly|weekly|yearly))($| )`)
+
+var quickAddNumbers = map[string]int64{
+	"one":   1,
+	"two":   2,
+	"three": 3,
+	"four":  4,
+	"five":  5,
+	"six":   6,
+	"seven": 7,
+	"eight": 8,
+	"nine":  9,
+	"ten":   10,
+}
+
+const (
+	quickAddSecondsADay   = 60 * 60 * 24
+	quickAddSecondsAMonth = quickAddSecondsADay * 30
+	quickAddSecondsAYear  = quickAddSecondsADay * 365
+)
+
+// QuickAddMagic holds the result of parsing a task text with quick add magic
+type QuickAddMagic struct {
+	// The text to parse, for example `Buy milk *groceries @frederick !3 tomorrow at 15:00 +shopping`.
+	Text string `query:"text" json:"text"`
+	// Which prefixes to use. Can be `vikunja` (the default), `todoist` or `disabled`.
+	Mode QuickAddMagicMode `query:"mode" json:"mode"`
+
+	// The text without all recognized parts. This would be the title of the task.
+	Title string `json:"title"`
+	// The due date found in the text. Relative dates like "tomorrow" are resolved in the time zone of the user.
+	DueDate time.Time `json:"due_date"`
+	// The titles of all labels found in the text.
+	Labels []string `json:"labels"`
+	// The title or identifier of the project found in the text.
+	Project string `json:"project"`
+	// The priority found in the text.
+	Priority int64 `json:"priority"`
+	// The usernames, names or emails of all assignees found in the text. They are not removed from the title as only
+	// existing users are assigned.
+	Assignees []string `json:"assignees"`
+	// The repeat interval in seconds found in the text, for example "every week".
+	RepeatAfter int64 `json:"repeat_after"`
+
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+// CanRead checks if the user can parse a text. Everyone can.
+func (q *QuickAddMagic) CanRead(_ *xorm.Session, _ web.Auth) (bool, int, error) {
+	return true, int(RightRead), nil
+}
+
+// ReadOne parses a text with quick add magic
+// @Summary Parse a task text with quick add magic
+// @Description Parses a text for labels, a project, a priority, assignees, a repeating interval and a due date the same way the quick add magic of the web frontend does it. Nothing is created, use the `quick_add_magic` property when creating a task for that.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param text query string true "The text to parse."
+// @Param mode query string false "Which prefixes to use. Can be `vikunja`, `todoist` or `disabled`. Defaults to `vikunja`."
+// @Success 200 {object} models.QuickAddMagic "The parsed text."
+// @Failure 400 {object} web.HTTPError "The mode is invalid."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/quickadd [get]
+func (q *QuickAddMagic) ReadOne(s *xorm.Session, a web.Auth) (err error) {
+	loc, err := getQuickAddTimeZone(s, a)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := parseQuickAddMagic(q.Text, q.Mode, time.Now().In(loc))
+	if err != nil {
+		return err
+	}
+
+	*q = *parsed
+	return nil
+}
+
+// getQuickAddTimeZone returns the time zone relative dates are resolved in.
+func getQuickAddTimeZone(s *xorm.Session, a web.Auth) (*time.Location, error) {
+	if _, isShare := a.(*LinkSharing); isShare {
+		return config.GetTimeZone(), nil
+	}
+
+	u, err := user.GetUserByID(s, a.GetID())
+	if err != nil {
+		return nil, err
+	}
+	if u.Timezone == "" {
+		return config.GetTimeZone(), nil
+	}
+
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return config.GetTimeZone(), nil
+	}
+	return loc, nil
+}
+
+// parseQuickAddMagic parses a task text for dates, assignees, labels, projects, priorities and repeating intervals.
+func parseQuickAddMagic(text string, mode QuickAddMagicMode, now time.Time) (result *QuickAddMagic, err error) {
+	if mode == "" {
+		mode = QuickAddMagicModeVikunja
+	}
+
+	result = &QuickAddMagic{
+		Text:      text,
+		Mode:      mode,
+		Title:     text,
+		Labels:    []string{},
+		Assignees: []string{},
+	}
+
+	if mode == QuickAddMagicModeDisabled {
+		return result, nil
+	}
+
+	prefixes, has := quickAddPrefixesByMode[mode]
+	if !has {
+		return nil, ErrInvalidQuickAddMagicMode{Mode: mode}
+	}
+
+	result.Labels = getQuickAddItemsFromPrefix(result.Title, prefixes.label)
+	result.Title = cleanupQuickAddItemText(result.Title, result.Labels, prefixes.label)
+
+	projects := getQuickAddItemsFromPrefix(result.Title, prefixes.project)
+	if len(projects) > 0 {
+		result.Project = projects[0]
+		result.Title = cleanupQuickAddItemText(result.Title, []string{result.Project}, prefixes.project)
+	}
+
+	result.Priority = getQuickAddPriority(result.Title, prefixes.priority)
+	if result.Priority > 0 {
+		result.Title = cleanupQuickAddItemText(result.Title, []string{strconv.FormatInt(result.Priority, 10)}, prefixes.priority)
+	}
+
+	result.Assignees = getQuickAddItemsFromPrefix(result.Title, prefixes.assignee)
+
+	result.Title, result.RepeatAfter = getQuickAddRepeats(result.Title)
+
+	result.Title, result.DueDate = parseQuickAddDate(result.Title, now)
+
+	result.Title = strings.Join(strings.Fields(result.Title), " ")
+	return result, nil
+}
+
+// getQuickAddItemsFromPrefix returns all items in the text which start with the prefix.
+// Items containing spaces can be quoted with ' or ".
+func getQuickAddItemsFromPrefix(text, prefix string) []string {
+	parts := strings.Split(text, " "+prefix)
+	if strings.HasPrefix(text, prefix) {
+		parts[0] = strings.TrimPrefix(parts[0], prefix)
+	} else {
+		// The first part is the text before the first item
+		parts = parts[1:]
+	}
+
+	items := []string{}
+	seen := make(map[string]bool)
+	for _, p := range parts {
+		p = strings.TrimPrefix(p, prefix)
+
+		var item string
+		switch {
+		case strings.HasPrefix(p, "'"):
+			item = strings.Split(p, "'")[1]
+		case strings.HasPrefix(p, `"`):
+			item = strings.Split(p, `"`)[1]
+		default:
+			// Only until the next space
+			item = strings.Split(p, " ")[0]
+		}
+
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// cleanupQuickAddItemText removes all items with their prefix from the text.
+func cleanupQuickAddItemText(text string, items []string, prefix string) string {
+	for _, item := range items {
+		if item == "" {
+			continue
+		}
+		quoted := regexp.QuoteMeta(item)
+		text = regexp.
+			MustCompile(`(?i)`+regexp.QuoteMeta(prefix)+`('`+quoted+`'|"`+quoted+`"|`+quoted+`) ?`).
+			ReplaceAllLiteralString(text, "")
+	}
+	return text
+}
+
+func getQuickAddPriority(text, prefix string) int64 {
+	for _, p := range getQuickAddItemsFromPrefix(text, prefix) {
+		priority, err := strconv.ParseInt(p, 10, 64)
+		if err == nil && priority >= 1 && priority <= maxTaskPriority {
+			return priority
+		}
+	}
+	return 0
+}
+
+// getQuickAddRepeats returns the repeating interval in seconds and the text without it.
+func getQuickAddRepeats(text string) (string, int64) {
+	results := quickAddRepeatRegex.FindStringSubmatch(text)
+	if results == nil {
+		return text, 0
+	}
+
+	amount := int64(1)
+	if rawAmount := strings.ToLower(strings.TrimSpace(results[6])); rawAmount != "" {
+		if n, is := quickAddNumbers[rawAmount]; is {
+			amount = n
+		} else {
+			amount, _ = strconv.ParseInt(rawAmount, 10, 64)
+		}
+	}
+
+	var seconds int64
+	switch strings.ToLower(results[2]) {
+	case "biennially":
+		seconds = quickAddSecondsAYear * 2
+	case "biannually", "semiannually":
+		seconds = quickAddSecondsAMonth * 6
+	case "yearly", "annually":
+		seconds = quickAddSecondsAYear
+	case "daily":
+		seconds = quickAddSecondsADay
+	case "hourly":
+		seconds = 60 * 60
+	case "monthly":
+		seconds = quickAddSecondsAMonth
+	case "weekly":
+		seconds = quickAddSecondsADay * 7
+	default:
+		switch strings.TrimSuffix(strings.ToLower(results[7]), "s") {
+		case "hour":
+			seconds = 60 * 60 * amount
+		case "day":
+			seconds = quickAddSecondsADay * amount
+		case "week":
+			seconds = quickAddSecondsADay * 7 * amount
+		case "month":
+			seconds = quickAddSecondsAMonth * amount
+		case "year":
+			seconds = quickAddSecondsAYear * amount
+		}
+	}
+
+	return strings.Replace(text, results[0], " ", 1), seconds
+}
+
+// applyQuickAddMagic parses the title of a new task with quick add magic and sets all found properties
+// which were not explicitly provided. It returns the titles of all labels which should be added to
+// the task after it was created.
+func (t *Task) applyQuickAddMagic(s *xorm.Session, a web.Auth) (labels []string, err error) {
+	if t.QuickAddMagic == "" || t.QuickAddMagic == QuickAddMagicModeDisabled {
+		return nil, nil
+	}
+
+	loc, err := getQuickAddTimeZone(s, a)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseQuickAddMagic(t.Title, t.QuickAddMagic, time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	t.Title = parsed.Title
+	if t.DueDate.IsZero() {
+		t.DueDate = parsed.DueDate
+	}
+	if t.Priority == 0 {
+		t.Priority = parsed.Priority
+	}
+	if t.RepeatAfter == 0 {
+		t.RepeatAfter = parsed.RepeatAfter
+	}
+
+	if parsed.Project != "" {
+		err = t.setQuickAddMagicProject(s, a, parsed.Project)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(parsed.Assignees) > 0 && len(t.Assignees) == 0 {
+		err = t.setQuickAddMagicAssignees(s, parsed.Assignees)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return parsed.Labels, nil
+}
+
+// setQuickAddMagicProject moves the new task into the project with the title or identifier.
+// If the project does not exist or the user cannot create tasks in it, the task stays in its project.
+func (t *Task) setQuickAddMagicProject(s *xorm.Session, a web.Auth, title string) error {
+	if _, isShare := a.(*LinkSharing); isShare {
+		return nil
+	}
+
+	projects, _, _, err := getRawProjectsForUser(s, &projectOptions{
+		user: &user.User{ID: a.GetID(), APITokenProjectIDs: getAPITokenProjectIDs(a)},
+		page: -1,
+	})
+	if err != nil {
+		return err
+	}
+
+	var found *Project
+	for _, p := range projects {
+		if strings.EqualFold(p.Title, title) {
+			found = p
+			break
+		}
+	}
+	if found == nil {
+		for _, p := range projects {
+			if p.Identifier != "" && strings.EqualFold(p.Identifier, title) {
+				found = p
+				break
+			}
+		}
+	}
+	if found == nil || found.ID == t.ProjectID {
+		return nil
+	}
+
+	can, err := (&Task{ProjectID: found.ID}).CanCreate(s, a)
+	if err != nil {
+		return err
+	}
+	if can {
+		t.ProjectID = found.ID
+	}
+	return nil
+}
+
+// setQuickAddMagicAssignees assigns all users with access to the project which match by username, name or email
+// and removes them from the title. Names which don't match a user stay in the title.
+func (t *Task) setQuickAddMagicAssignees(s *xorm.Session, names []string) error {
+	prefixes := quickAddPrefixesByMode[t.QuickAddMagic]
+
+	project, err := GetProjectSimpleByID(s, t.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		users, err := ListUsersFromProject(s, project, name)
+		if err != nil {
+			return err
+		}
+
+		var found *user.User
+		for _, match := range []func(u *user.User) string{
+			func(u *user.User) string { return u.Username },
+			func(u *user.User) string { return u.Name },
+			func(u *user.User) string { return u.Email },
+		} {
+			for _, u := range users {
+				if strings.EqualFold(match(u), name) {
+					found = u
+					break
+				}
+			}
+			if found != nil {
+				break
+			}
+		}
+		if found == nil {
+			continue
+		}
+
+		t.Assignees = append(t.Assignees, found)
+		t.Title = cleanupQuickAddItemText(t.Title, []string{name}, prefixes.assignee)
+	}
+
+	t.Title = strings.Join(strings.Fields(t.Title), " ")
+	return nil
+}
+
+// addQuickAddMagicLabels adds the labels with the titles to the task. Labels which don't exist yet are created.
+func (t *Task) addQuickAddMagicLabels(s *xorm.Session, a web.Auth, titles []string) error {
+	// Link shares can neither see nor create labels
+	if _, isShare := a.(*LinkSharing); isShare || len(titles) == 0 {
+		return nil
+	}
+
+	u := &user.User{ID: a.GetID(), APITokenProjectIDs: getAPITokenProjectIDs(a)}
+	labels := make([]*Label, 0, len(titles))
+	for _, title := range titles {
+		existing, _, _, err := GetLabelsByTaskIDs(s, &LabelByTaskIDsOptions{
+			Search:              []string{title},
+			User:                u,
+			GetForUser:          u.ID,
+			Page:                -1,
+			GetUnusedLabels:     true,
+			GroupByLabelIDsOnly: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		var found *Label
+		for _, l := range existing {
+			if strings.EqualFold(l.Title, title) {
+				found = &l.Label
+				break
+			}
+		}
+
+		if found == nil {
+			found = &Label{Title: title}
+			err = found.Create(s, a)
+			if err != nil {
+				return err
+			}
+		}
+
+		labels = append(labels, found)
+	}
+
+	return t.UpdateTaskLabels(s, a, labels)
+}
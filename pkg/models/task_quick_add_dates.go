@@ -0,0 +1,355 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const quickAddMonthsRegexGroup = `(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)`
+
+var (
+	quickAddTimeRegex        = regexp.MustCompile(`(?i) (at|@) ([0-9][0-9]?(:[0-9][0-9]?)?( ?(a|p)m)?)`)
+	quickAddWeekdayRegex     = regexp.MustCompile(`(?i)(^| )(next )?(monday|mon|tuesday|tue|wednesday|wed|thursday|thu|friday|fri|saturday|sat|sunday|sun)($| )`)
+	quickAddDayOfMonthRegex  = regexp.MustCompile(`(?i)(^| )(([1-2][0-9])|(3[01])|(0?[1-9]))(st|nd|rd|th|\.)($| )`)
+	quickAddMonthRegex       = regexp.MustCompile(`(?i)` + quickAddMonthsRegexGroup + `[a-z]*`)
+	quickAddInRegex          = regexp.MustCompile(`(?i)(^| )in ([0-9]+) (hours?|days?|weeks?|months?)`)
+	quickAddFullDateRegex    = regexp.MustCompile(`(^| )([0-9][0-9]?/[0-9][0-9]?/[0-9][0-9]([0-9][0-9])?|[0-9][0-9][0-9][0-9]/[0-9][0-9]?/[0-9][0-9]?|[0-9][0-9][0-9][0-9]-[0-9][0-9]?-[0-9][0-9]?)`)
+	quickAddMonthAndDayRegex = regexp.MustCompile(`(?i)(^| )(` + quickAddMonthsRegexGroup + ` [0-9][0-9]?|[0-9][0-9]? ` + quickAddMonthsRegexGroup + `)`)
+	quickAddDayAndMonthRegex = regexp.MustCompile(`(^| )([0-9][0-9]?/[0-9][0-9]?)`)
+	quickAddLeadingDigits    = regexp.MustCompile(`^[0-9]+`)
+)
+
+var quickAddMonths = map[string]time.Month{
+	"jan": time.January,
+	"feb": time.February,
+	"mar": time.March,
+	"apr": time.April,
+	"may": time.May,
+	"jun": time.June,
+	"jul": time.July,
+	"aug": time.August,
+	"sep": time.September,
+	"oct": time.October,
+	"nov": time.November,
+	"dec": time.December,
+}
+
+var quickAddWeekdays = map[string]time.Weekday{
+	"mon":       time.Monday,
+	"monday":    time.Monday,
+	"tue":       time.Tuesday,
+	"tuesday":   time.Tuesday,
+	"wed":       time.Wednesday,
+	"wednesday": time.Wednesday,
+	"thu":       time.Thursday,
+	"thursday":  time.Thursday,
+	"fri":       time.Friday,
+	"friday":    time.Friday,
+	"sat":       time.Saturday,
+	"saturday":  time.Saturday,
+	"sun":       time.Sunday,
+	"sunday":    time.Sunday,
+}
+
+// replaceAllCaseInsensitive replaces all occurrences of search in text, no matter the case.
+func replaceAllCaseInsensitive(text, search, replace string) string {
+	if search == "" {
+		return text
+	}
+	return regexp.MustCompile(`(?i)`+regexp.QuoteMeta(search)).ReplaceAllLiteralString(text, replace)
+}
+
+// removeQuickAddMatch removes the first standalone occurrence of a match from the text.
+func removeQuickAddMatch(text, match string) string {
+	match = strings.TrimSpace(match)
+	if match == "" {
+		return text
+	}
+
+	loc := regexp.MustCompile(`(?i)(^|\s)` + regexp.QuoteMeta(match) + `(\s|$)`).FindStringIndex(text)
+	if loc == nil {
+		return replaceAllCaseInsensitive(text, match, "")
+	}
+	return text[:loc[0]] + " " + text[loc[1]:]
+}
+
+// calculateNearestHours returns the next "round" hour of the day for a new due date.
+func calculateNearestHours(now time.Time) int {
+	switch h := now.Hour(); {
+	case h <= 9 || h > 21:
+		return 9
+	case h <= 12:
+		return 12
+	case h <= 15:
+		return 15
+	case h <= 18:
+		return 18
+	default:
+		return 21
+	}
+}
+
+func getQuickAddDateFromInterval(now time.Time, days int) time.Time {
+	date := now.AddDate(0, 0, days)
+	return time.Date(date.Year(), date.Month(), date.Day(), calculateNearestHours(now), 0, 0, 0, now.Location())
+}
+
+// getQuickAddDayInterval returns the number of days from now for date keywords like "next monday".
+func getQuickAddDayInterval(keyword string, now time.Time) int {
+	currentDay := int(now.Weekday())
+	switch keyword {
+	case "tomorrow":
+		return 1
+	case "next monday":
+		days := (8 - currentDay) % 7
+		if days == 0 {
+			days = 7
+		}
+		return days
+	case "this weekend":
+		return (6 - currentDay) % 6
+	case "later this week":
+		if now.Weekday() == time.Friday || now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
+			return 0
+		}
+		return 2
+	case "later next week":
+		return getQuickAddDayInterval("later this week", now) + 7
+	case "next week":
+		return 7
+	}
+	return 0
+}
+
+func matchesQuickAddDateKeyword(text, keyword string) bool {
+	return regexp.MustCompile(`(?i)(^| )` + keyword).MatchString(text)
+}
+
+// parseQuickAddDate finds the first date in the text and returns the text without it.
+// It understands the same date formats as the quick add magic of the web frontend.
+func parseQuickAddDate(text string, now time.Time) (newText string, date time.Time) {
+	for _, keyword := range []string{"today", "tomorrow", "next monday", "this weekend", "later this week", "later next week", "next week"} {
+		if matchesQuickAddDateKeyword(text, keyword) {
+			return addQuickAddTimeToDate(text, getQuickAddDateFromInterval(now, getQuickAddDayInterval(keyword, now)), keyword)
+		}
+	}
+
+	if matchesQuickAddDateKeyword(text, "next month") {
+		date = time.Date(now.Year(), now.Month()+1, 1, calculateNearestHours(now), 0, 0, 0, now.Location())
+		return addQuickAddTimeToDate(text, date, "next month")
+	}
+	if matchesQuickAddDateKeyword(text, "end of month") {
+		date = time.Date(now.Year(), now.Month()+1, 0, calculateNearestHours(now), 0, 0, 0, now.Location())
+		return addQuickAddTimeToDate(text, date, "end of month")
+	}
+
+	if found, date := getQuickAddDateFromWeekday(text, now); found != "" {
+		return addQuickAddTimeToDate(text, date, found)
+	}
+
+	if found, date := getQuickAddDayOfMonth(text, now); found != "" {
+		text, date = getQuickAddMonthForDay(text, date, now)
+		return addQuickAddTimeToDate(text, date, found)
+	}
+
+	if found, date := getQuickAddDateFromTextIn(text, now); found != "" {
+		return addQuickAddTimeToDate(text, date, found)
+	}
+
+	if found, date := getQuickAddDateFromText(text, now); found != "" {
+		return addQuickAddTimeToDate(text, date, found)
+	}
+
+	return text, time.Time{}
+}
+
+// addQuickAddTimeToDate removes the matched date from the text and sets the time of the date
+// if the text contains one, like "at 15:00".
+func addQuickAddTimeToDate(text string, date time.Time, match string) (string, time.Time) {
+	text = removeQuickAddMatch(text, match)
+
+	results := quickAddTimeRegex.FindStringSubmatch(text)
+	if results == nil {
+		return strings.TrimSpace(text), date
+	}
+
+	t := strings.ToLower(results[2])
+	parts := strings.Split(t, ":")
+	hours, _ := strconv.Atoi(quickAddLeadingDigits.FindString(parts[0]))
+	minutes := 0
+	if len(parts) > 1 {
+		minutes, _ = strconv.Atoi(quickAddLeadingDigits.FindString(parts[1]))
+	}
+	if strings.HasSuffix(t, "pm") && hours < 12 {
+		hours += 12
+	}
+	if strings.HasSuffix(t, "am") && hours == 12 {
+		hours = 0
+	}
+
+	date = time.Date(date.Year(), date.Month(), date.Day(), hours, minutes, 0, 0, date.Location())
+	return strings.TrimSpace(strings.Replace(text, results[0], "", 1)), date
+}
+
+func getQuickAddDateFromWeekday(text string, now time.Time) (found string, date time.Time) {
+	results := quickAddWeekdayRegex.FindStringSubmatch(text)
+	if results == nil {
+		return "", time.Time{}
+	}
+
+	day := quickAddWeekdays[strings.ToLower(results[3])]
+	distance := (int(day) + 7 - int(now.Weekday())) % 7
+	date = now.AddDate(0, 0, distance)
+	date = time.Date(date.Year(), date.Month(), date.Day(), date.Hour(), date.Minute(), 0, 0, date.Location())
+
+	// Only the trailing space is removed to not break parsing a time like "at 14:00" right after the weekday
+	return strings.TrimSuffix(results[0], " "), date
+}
+
+func getQuickAddDayOfMonth(text string, now time.Time) (found string, date time.Time) {
+	results := quickAddDayOfMonthRegex.FindStringSubmatch(text)
+	if results == nil {
+		return "", time.Time{}
+	}
+
+	day, _ := strconv.Atoi(results[2])
+	// Use the first month from now on which has that day and where the day is not in the past
+	for i := 0; i < 12; i++ {
+		date = time.Date(now.Year(), now.Month()+time.Month(i), day, now.Hour(), now.Minute(), 0, 0, now.Location())
+		if date.Day() == day && !date.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+			break
+		}
+	}
+
+	return results[0], date
+}
+
+func getQuickAddMonthForDay(text string, date time.Time, now time.Time) (string, time.Time) {
+	month := quickAddMonthRegex.FindString(text)
+	if month == "" {
+		return text, date
+	}
+
+	date = time.Date(now.Year(), quickAddMonths[strings.ToLower(month[:3])], date.Day(), date.Hour(), date.Minute(), 0, 0, date.Location())
+	if date.Before(now) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return removeQuickAddMatch(text, month), date
+}
+
+func getQuickAddDateFromTextIn(text string, now time.Time) (found string, date time.Time) {
+	results := quickAddInRegex.FindStringSubmatch(text)
+	if results == nil {
+		return "", time.Time{}
+	}
+
+	amount, _ := strconv.Atoi(results[2])
+	switch strings.TrimSuffix(strings.ToLower(results[3]), "s") {
+	case "hour":
+		date = now.Add(time.Duration(amount) * time.Hour)
+	case "day":
+		date = now.AddDate(0, 0, amount)
+	case "week":
+		date = now.AddDate(0, 0, amount*7)
+	case "month":
+		date = now.AddDate(0, amount, 0)
+	}
+
+	return results[0], date
+}
+
+// getQuickAddDateFromText parses dates like 2021-06-24, 06/24/2021, jan 21, 21 jan or 27/01.
+func getQuickAddDateFromText(text string, now time.Time) (found string, date time.Time) {
+	containsYear := true
+	var year, day int
+	var month time.Month
+
+	if results := quickAddFullDateRegex.FindStringSubmatch(text); results != nil {
+		found = results[0]
+		raw := results[2]
+		var parts []string
+		if strings.Contains(raw, "-") {
+			parts = strings.Split(raw, "-")
+		} else {
+			parts = strings.Split(raw, "/")
+		}
+		first, _ := strconv.Atoi(parts[0])
+		second, _ := strconv.Atoi(parts[1])
+		third, _ := strconv.Atoi(parts[2])
+
+		if len(parts[0]) == 4 {
+			// 2021-06-24 or 2021/06/24
+			year, month, day = first, time.Month(second), third
+		} else {
+			// 06/24/2021 or 06/24/21
+			year, month, day = third, time.Month(first), second
+			if len(parts[2]) == 2 {
+				year += 2000
+			}
+		}
+	}
+
+	if found == "" {
+		if results := quickAddMonthAndDayRegex.FindStringSubmatch(text); results != nil {
+			found = strings.TrimSpace(results[0])
+			containsYear = false
+			year = now.Year()
+			for _, part := range strings.Split(strings.ToLower(found), " ") {
+				if m, is := quickAddMonths[part]; is {
+					month = m
+					continue
+				}
+				day, _ = strconv.Atoi(part)
+			}
+		}
+	}
+
+	if found == "" {
+		if results := quickAddDayAndMonthRegex.FindStringSubmatch(text); results != nil {
+			found = results[0]
+			containsYear = false
+			year = now.Year()
+			parts := strings.Split(results[2], "/")
+			first, _ := strconv.Atoi(parts[0])
+			second, _ := strconv.Atoi(parts[1])
+			month, day = time.Month(first), second
+			if first > 12 {
+				month, day = time.Month(second), first
+			}
+		}
+	}
+
+	if found == "" {
+		return "", time.Time{}
+	}
+
+	date = time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	// Invalid dates like 2021-02-31 would overflow into the next month
+	if month < time.January || month > time.December || date.Day() != day || date.Month() != month {
+		return "", time.Time{}
+	}
+
+	if !containsYear && date.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+		date = date.AddDate(1, 0, 0)
+	}
+
+	return found, date
+}
@@ -0,0 +1,402 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuickAddMagic(t *testing.T) {
+	// A thursday
+	now := time.Date(2021, 6, 24, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		text   string
+		mode   QuickAddMagicMode
+		result *QuickAddMagic
+	}{
+		{
+			name: "plain text",
+			text: "Lorem Ipsum",
+			result: &QuickAddMagic{
+				Title: "Lorem Ipsum",
+			},
+		},
+		{
+			name: "labels",
+			text: "Lorem *label1 Ipsum *'label with spaces' *\"other label\"",
+			result: &QuickAddMagic{
+				Title:  "Lorem Ipsum",
+				Labels: []string{"label1", "label with spaces", "other label"},
+			},
+		},
+		{
+			name: "project at the start",
+			text: "+project Lorem Ipsum",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				Project: "project",
+			},
+		},
+		{
+			name: "priority",
+			text: "Lorem Ipsum !4",
+			result: &QuickAddMagic{
+				Title:    "Lorem Ipsum",
+				Priority: 4,
+			},
+		},
+		{
+			name: "invalid priority",
+			text: "Lorem Ipsum !9",
+			result: &QuickAddMagic{
+				Title: "Lorem Ipsum !9",
+			},
+		},
+		{
+			name: "assignees stay in the title",
+			text: "Lorem @user1 Ipsum",
+			result: &QuickAddMagic{
+				Title:     "Lorem @user1 Ipsum",
+				Assignees: []string{"user1"},
+			},
+		},
+		{
+			name: "todoist prefixes",
+			text: "Lorem @label #project +user1",
+			mode: QuickAddMagicModeTodoist,
+			result: &QuickAddMagic{
+				Title:     "Lorem +user1",
+				Labels:    []string{"label"},
+				Project:   "project",
+				Assignees: []string{"user1"},
+			},
+		},
+		{
+			name: "disabled",
+			text: "Lorem *label tomorrow",
+			mode: QuickAddMagicModeDisabled,
+			result: &QuickAddMagic{
+				Title: "Lorem *label tomorrow",
+			},
+		},
+		{
+			name: "repeating every two weeks",
+			text: "Lorem every two weeks Ipsum",
+			result: &QuickAddMagic{
+				Title:       "Lorem Ipsum",
+				RepeatAfter: 14 * 24 * 60 * 60,
+			},
+		},
+		{
+			name: "repeating monthly",
+			text: "Lorem Ipsum monthly",
+			result: &QuickAddMagic{
+				Title:       "Lorem Ipsum",
+				RepeatAfter: 30 * 24 * 60 * 60,
+			},
+		},
+		{
+			name: "today",
+			text: "Lorem Ipsum today",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 6, 24, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "tomorrow with time",
+			text: "Lorem tomorrow at 3pm Ipsum",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 6, 25, 15, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "next monday",
+			text: "Lorem Ipsum next monday",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 6, 28, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "end of month",
+			text: "Lorem Ipsum end of month",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "weekday",
+			text: "Lorem Ipsum saturday at 17:15",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 6, 26, 17, 15, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "weekday as part of a word",
+			text: "Buy lemons sun",
+			result: &QuickAddMagic{
+				Title:   "Buy lemons",
+				DueDate: time.Date(2021, 6, 27, 10, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "in some days",
+			text: "Lorem Ipsum in 3 days",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 6, 27, 10, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "day of month",
+			text: "Lorem Ipsum 17th",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 7, 17, 10, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "day of month with month",
+			text: "Lorem Ipsum 3rd february",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2022, 2, 3, 10, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "iso date",
+			text: "Lorem Ipsum 2021-08-01",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "us date",
+			text: "Lorem Ipsum 08/01/2021",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "month and day in the past",
+			text: "Lorem Ipsum jan 21",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2022, 1, 21, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "day and month numeric",
+			text: "Lorem Ipsum 27/07",
+			result: &QuickAddMagic{
+				Title:   "Lorem Ipsum",
+				DueDate: time.Date(2021, 7, 27, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "everything",
+			text: "Buy milk *groceries +shopping !3 @user1 tomorrow at 8",
+			result: &QuickAddMagic{
+				Title:     "Buy milk @user1",
+				Labels:    []string{"groceries"},
+				Project:   "shopping",
+				Priority:  3,
+				Assignees: []string{"user1"},
+				DueDate:   time.Date(2021, 6, 25, 8, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseQuickAddMagic(tt.text, tt.mode, now)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.result.Title, result.Title)
+			assert.Equal(t, tt.result.Project, result.Project)
+			assert.Equal(t, tt.result.Priority, result.Priority)
+			assert.Equal(t, tt.result.RepeatAfter, result.RepeatAfter)
+			assert.Equal(t, tt.result.DueDate, result.DueDate)
+			if len(tt.result.Labels) > 0 {
+				assert.Equal(t, tt.result.Labels, result.Labels)
+			} else {
+				assert.Empty(t, result.Labels)
+			}
+			if len(tt.result.Assignees) > 0 {
+				assert.Equal(t, tt.result.Assignees, result.Assignees)
+			} else {
+				assert.Empty(t, result.Assignees)
+			}
+		})
+	}
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := parseQuickAddMagic("Lorem Ipsum", "invalid", now)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidQuickAddMagicMode(err))
+	})
+}
+
+func TestTask_Create_QuickAddMagic(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("labels, priority and assignees", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:         "Lorem *'Label #1' *newlabel !2 @user1 Ipsum every day",
+			ProjectID:     1,
+			QuickAddMagic: QuickAddMagicModeVikunja,
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, "Lorem Ipsum", task.Title)
+		assert.Equal(t, int64(2), task.Priority)
+		assert.Equal(t, int64(24*60*60), task.RepeatAfter)
+		require.Len(t, task.Assignees, 1)
+		assert.Equal(t, int64(1), task.Assignees[0].ID)
+		require.Len(t, task.Labels, 2)
+		assert.Equal(t, int64(1), task.Labels[0].ID)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":       task.ID,
+			"title":    "Lorem Ipsum",
+			"priority": 2,
+		}, false)
+		db.AssertExists(t, "labels", map[string]interface{}{
+			"title":         "newlabel",
+			"created_by_id": 1,
+		}, false)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  task.ID,
+			"label_id": 1,
+		}, false)
+		db.AssertExists(t, "task_assignees", map[string]interface{}{
+			"task_id": task.ID,
+			"user_id": 1,
+		}, false)
+	})
+	t.Run("unknown assignee stays in the title", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:         "Lorem @nobody",
+			ProjectID:     1,
+			QuickAddMagic: QuickAddMagicModeVikunja,
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "Lorem @nobody", task.Title)
+		assert.Empty(t, task.Assignees)
+	})
+	t.Run("explicit properties take precedence", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:         "Lorem !2",
+			Priority:      5,
+			ProjectID:     1,
+			QuickAddMagic: QuickAddMagicModeVikunja,
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "Lorem", task.Title)
+		assert.Equal(t, int64(5), task.Priority)
+	})
+	t.Run("project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:         "Lorem +test10",
+			ProjectID:     1,
+			QuickAddMagic: QuickAddMagicModeVikunja,
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "Lorem", task.Title)
+		assert.Equal(t, int64(10), task.ProjectID)
+	})
+	t.Run("project without write access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:         "Lorem +Test3",
+			ProjectID:     1,
+			QuickAddMagic: QuickAddMagicModeVikunja,
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), task.ProjectID)
+	})
+	t.Run("api token restricted to a project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		token := &user.User{ID: 1, AuthenticatedWithAPIToken: true, APITokenProjectIDs: []int64{1}}
+		task := &Task{
+			Title:         "Lorem +test10",
+			ProjectID:     1,
+			QuickAddMagic: QuickAddMagicModeVikunja,
+		}
+		err := task.Create(s, token)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), task.ProjectID)
+	})
+	t.Run("without quick add magic", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:     "Lorem *label !2",
+			ProjectID: 1,
+		}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "Lorem *label !2", task.Title)
+		assert.Equal(t, int64(0), task.Priority)
+	})
+}
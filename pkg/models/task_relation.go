@@ -45,6 +45,10 @@ const (
 	RelationKindFollows     RelationKind = `follows`
 	RelationKindCopiedFrom  RelationKind = `copiedfrom`
 	RelationKindCopiedTo    RelationKind = `copiedto`
+	RelationKindCausedBy    RelationKind = `causedby`
+	RelationKindCauses      RelationKind = `causes`
+	RelationKindFixes       RelationKind = `fixes`
+	RelationKindFixedBy     RelationKind = `fixedby`
 )
 
 /*
@@ -74,7 +78,11 @@ func (rk RelationKind) isValid() bool {
 		rk == RelationKindPreceeds ||
 		rk == RelationKindFollows ||
 		rk == RelationKindCopiedFrom ||
-		rk == RelationKindCopiedTo
+		rk == RelationKindCopiedTo ||
+		rk == RelationKindCausedBy ||
+		rk == RelationKindCauses ||
+		rk == RelationKindFixes ||
+		rk == RelationKindFixedBy
 }
 
 // TaskRelation represents a kind of relation between two tasks
@@ -132,6 +140,14 @@ func getInverseRelation(kind RelationKind) RelationKind {
 		return RelationKindCopiedTo
 	case RelationKindCopiedTo:
 		return RelationKindCopiedFrom
+	case RelationKindCausedBy:
+		return RelationKindCauses
+	case RelationKindCauses:
+		return RelationKindCausedBy
+	case RelationKindFixes:
+		return RelationKindFixedBy
+	case RelationKindFixedBy:
+		return RelationKindFixes
 	case RelationKindUnknown:
 		// Nothing to do
 	}
@@ -256,13 +272,18 @@ func (rel *TaskRelation) Create(s *xorm.Session, a web.Auth) error {
 		return err
 	}
 
+	err = syncParentTaskFromRelation(s, rel, false)
+	if err != nil {
+		return err
+	}
+
 	doer, _ := user.GetFromAuth(a)
 	task, err := GetTaskByIDSimple(s, rel.TaskID)
 	if err != nil {
 		return err
 	}
 
-	return events.Dispatch(&TaskRelationCreatedEvent{
+	return events.DispatchOnCommit(s, &TaskRelationCreatedEvent{
 		Task:     &task,
 		Relation: rel,
 		Doer:     doer,
@@ -321,13 +342,18 @@ func (rel *TaskRelation) Delete(s *xorm.Session, a web.Auth) error {
 		return err
 	}
 
+	err = syncParentTaskFromRelation(s, rel, true)
+	if err != nil {
+		return err
+	}
+
 	doer, _ := user.GetFromAuth(a)
 	task, err := GetTaskByIDSimple(s, rel.TaskID)
 	if err != nil {
 		return err
 	}
 
-	return events.Dispatch(&TaskRelationDeletedEvent{
+	return events.DispatchOnCommit(s, &TaskRelationDeletedEvent{
 		Task:     &task,
 		Relation: rel,
 		Doer:     doer,
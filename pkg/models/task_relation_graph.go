@@ -0,0 +1,168 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// ProjectRelationGraph holds all relations between the tasks of a project
+type ProjectRelationGraph struct {
+	// The project the relations belong to.
+	ProjectID int64 `json:"project_id" param:"project"`
+	// All tasks of the project which are related to another task of the project.
+	Nodes []*RelationGraphNode `json:"nodes"`
+	// All relations between the tasks of the project. Each relation is only contained once, in the direction from the task with the lower id to the one with the higher id.
+	Edges []*RelationGraphEdge `json:"edges"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// RelationGraphNode is a task in the relation graph
+type RelationGraphNode struct {
+	// The id of the task.
+	TaskID int64 `json:"task_id"`
+	// The title of the task.
+	Title string `json:"title"`
+	// The task identifier, based on the project identifier and the task's index.
+	Identifier string `json:"identifier"`
+	// Whether the task is done.
+	Done bool `json:"done"`
+}
+
+// RelationGraphEdge is a typed relation between two tasks
+type RelationGraphEdge struct {
+	// The id of the task the relation starts from.
+	TaskID int64 `json:"task_id"`
+	// The id of the task the relation points to.
+	OtherTaskID int64 `json:"other_task_id"`
+	// The kind of the relation, seen from the task with the id `task_id`.
+	RelationKind RelationKind `json:"relation_kind"`
+}
+
+// CanRead checks if the user can see the relation graph of a project
+func (g *ProjectRelationGraph) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	p := &Project{ID: g.ProjectID}
+	return p.CanRead(s, a)
+}
+
+// ReadOne returns the relation graph of a project
+// @Summary Get the relation graph of a project
+// @Description Returns all tasks of the project which are related to another task of the project together with all typed relations between them. Relations to tasks in other projects are not part of the graph.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Success 200 {object} models.ProjectRelationGraph "The relation graph of the project."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/relations [get]
+func (g *ProjectRelationGraph) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	project, err := GetProjectSimpleByID(s, g.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	relations := []*TaskRelation{}
+	err = s.
+		Select("task_relations.*").
+		Join("INNER", []string{"tasks", "t1"}, "t1.id = task_relations.task_id").
+		Join("INNER", []string{"tasks", "t2"}, "t2.id = task_relations.other_task_id").
+		Where(builder.And(
+			builder.Eq{"t1.project_id": g.ProjectID},
+			builder.Eq{"t2.project_id": g.ProjectID},
+		)).
+		OrderBy("task_relations.id asc").
+		Find(&relations)
+	if err != nil {
+		return err
+	}
+
+	g.Nodes = []*RelationGraphNode{}
+	g.Edges = []*RelationGraphEdge{}
+	if len(relations) == 0 {
+		return nil
+	}
+
+	// Every relation is stored once per direction. To return each of them only once,
+	// all relations are turned around to start at the task with the lower id.
+	taskIDs := []int64{}
+	seen := make(map[int64]bool)
+	seenEdges := make(map[RelationGraphEdge]bool)
+	for _, rel := range relations {
+		edge := RelationGraphEdge{
+			TaskID:       rel.TaskID,
+			OtherTaskID:  rel.OtherTaskID,
+			RelationKind: rel.RelationKind,
+		}
+		if edge.TaskID > edge.OtherTaskID {
+			edge = RelationGraphEdge{
+				TaskID:       rel.OtherTaskID,
+				OtherTaskID:  rel.TaskID,
+				RelationKind: getInverseRelation(rel.RelationKind),
+			}
+		}
+		if seenEdges[edge] {
+			continue
+		}
+		seenEdges[edge] = true
+		g.Edges = append(g.Edges, &edge)
+		for _, id := range []int64{rel.TaskID, rel.OtherTaskID} {
+			if !seen[id] {
+				seen[id] = true
+				taskIDs = append(taskIDs, id)
+			}
+		}
+	}
+
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].TaskID != g.Edges[j].TaskID {
+			return g.Edges[i].TaskID < g.Edges[j].TaskID
+		}
+		if g.Edges[i].OtherTaskID != g.Edges[j].OtherTaskID {
+			return g.Edges[i].OtherTaskID < g.Edges[j].OtherTaskID
+		}
+		return g.Edges[i].RelationKind < g.Edges[j].RelationKind
+	})
+
+	tasks := []*Task{}
+	err = s.
+		In("id", taskIDs).
+		OrderBy("id asc").
+		Find(&tasks)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		t.setIdentifier(project)
+		g.Nodes = append(g.Nodes, &RelationGraphNode{
+			TaskID:     t.ID,
+			Title:      t.Title,
+			Identifier: t.Identifier,
+			Done:       t.Done,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectRelationGraph_ReadOne(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rels := []*TaskRelation{
+			{TaskID: 2, OtherTaskID: 3, RelationKind: RelationKindCausedBy},
+			{TaskID: 3, OtherTaskID: 1, RelationKind: RelationKindFixes},
+			// Task 13 belongs to another project and should not show up
+			{TaskID: 1, OtherTaskID: 13, RelationKind: RelationKindRelated},
+		}
+		for _, rel := range rels {
+			err := rel.Create(s, u)
+			require.NoError(t, err)
+		}
+
+		graph := &ProjectRelationGraph{ProjectID: 1}
+		err := graph.ReadOne(s, u)
+		require.NoError(t, err)
+
+		assert.Equal(t, []*RelationGraphEdge{
+			{TaskID: 1, OtherTaskID: 3, RelationKind: RelationKindFixedBy},
+			{TaskID: 1, OtherTaskID: 29, RelationKind: RelationKindSubtask},
+			{TaskID: 2, OtherTaskID: 3, RelationKind: RelationKindCausedBy},
+		}, graph.Edges)
+		require.Len(t, graph.Nodes, 4)
+		assert.Equal(t, int64(1), graph.Nodes[0].TaskID)
+		assert.Equal(t, "test1-1", graph.Nodes[0].Identifier)
+		assert.Equal(t, int64(2), graph.Nodes[1].TaskID)
+		assert.True(t, graph.Nodes[1].Done)
+		assert.Equal(t, int64(3), graph.Nodes[2].TaskID)
+		assert.Equal(t, int64(29), graph.Nodes[3].TaskID)
+	})
+	t.Run("no relations", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		graph := &ProjectRelationGraph{ProjectID: 2}
+		err := graph.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Empty(t, graph.Edges)
+		assert.Empty(t, graph.Nodes)
+	})
+}
+
+func TestProjectRelationGraph_CanRead(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	graph := &ProjectRelationGraph{ProjectID: 1}
+	can, _, err := graph.CanRead(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.True(t, can)
+
+	graph = &ProjectRelationGraph{ProjectID: 2}
+	can, _, err = graph.CanRead(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.False(t, can)
+}
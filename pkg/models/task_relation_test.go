@@ -48,6 +48,31 @@ func TestTaskRelation_Create(t *testing.T) {
 			"created_by_id": 1,
 		}, false)
 	})
+	t.Run("Fixes", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rel := TaskRelation{
+			TaskID:       1,
+			OtherTaskID:  2,
+			RelationKind: RelationKindFixes,
+		}
+		err := rel.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       1,
+			"other_task_id": 2,
+			"relation_kind": RelationKindFixes,
+		}, false)
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       2,
+			"other_task_id": 1,
+			"relation_kind": RelationKindFixedBy,
+		}, false)
+	})
 	t.Run("Two Tasks In Different Projects", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
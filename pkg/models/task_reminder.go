@@ -56,6 +56,17 @@ type TaskReminder struct {
 	RelativePeriod int64 `xorm:"bigint null" json:"relative_period"`
 	// The name of the date field to which the relative period refers to.
 	RelativeTo ReminderRelation `xorm:"varchar(50) null" json:"relative_to"`
+
+	// If set, everyone who got the reminder is notified again if the task is still not done this many seconds after the reminder fired.
+	EscalateAfter int64 `xorm:"bigint null" json:"escalate_after"`
+	// If true, the owner of the project is notified as well when the reminder escalates.
+	EscalateToProjectOwner bool `xorm:"null" json:"escalate_to_project_owner"`
+	// The id of a fallback user who is notified as well when the reminder escalates.
+	EscalateToUserID int64 `xorm:"bigint null" json:"escalate_to_user_id"`
+	// The id of a fallback team whose members are notified as well when the reminder escalates.
+	EscalateToTeamID int64 `xorm:"bigint null" json:"escalate_to_team_id"`
+	// The time the reminder escalates, computed from the reminder and escalate_after.
+	EscalatesAt time.Time `xorm:"DATETIME null INDEX 'escalates_at'" json:"-"`
 }
 
 // TableName returns a pretty table name
@@ -185,6 +196,10 @@ func getTasksWithRemindersDueAndTheirUsers(s *xorm.Session, now time.Time) (remi
 
 		for _, u := range usersPerTask[r.TaskID] {
 
+			if p, has := projects[u.Task.ProjectID]; has && p.remindersPaused() {
+				continue
+			}
+
 			// This ensures we send each reminder only once to each user
 			if seen[r.TaskID] == nil {
 				seen[r.TaskID] = make(map[int64]bool)
@@ -213,9 +228,10 @@ func getTasksWithRemindersDueAndTheirUsers(s *xorm.Session, now time.Time) (remi
 			actualReminder := r.Reminder.In(tz)
 			if (actualReminder.After(now) && actualReminder.Before(now.Add(time.Minute))) || actualReminder.Equal(now) {
 				reminderNotifications = append(reminderNotifications, &ReminderDueNotification{
-					User:    u.User,
-					Task:    u.Task,
-					Project: projects[u.Task.ProjectID],
+					User:     u.User,
+					Task:     u.Task,
+					Project:  projects[u.Task.ProjectID],
+					Reminder: r,
 				})
 			}
 		}
@@ -245,6 +261,8 @@ func RegisterReminderCron() {
 		defer s.Close()
 
 		now := time.Now()
+		sendReminderEscalations(s, now)
+
 		reminders, err := getTasksWithRemindersDueAndTheirUsers(s, now)
 		if err != nil {
 			log.Errorf("[Task Reminder Cron] Could not get tasks with reminders in the next minute: %s", err)
@@ -266,6 +284,8 @@ func RegisterReminderCron() {
 
 			log.Debugf("[Task Reminder Cron] Sent reminder email for task %d to user %d", n.Task.ID, n.User.ID)
 		}
+
+		sendTaskRemindersToProjectIntegrations(s, reminders)
 	})
 	if err != nil {
 		log.Fatalf("Could not register reminder cron: %s", err)
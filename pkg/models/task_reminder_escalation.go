@@ -0,0 +1,224 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// validateEscalation checks the escalation settings of a reminder are valid and the fallback user can access the project.
+func (r *TaskReminder) validateEscalation(s *xorm.Session, projectID int64) (err error) {
+	if r.EscalateAfter < 0 {
+		return InvalidFieldError([]string{"escalate_after"})
+	}
+
+	if r.EscalateToUserID != 0 {
+		u, err := user.GetUserByID(s, r.EscalateToUserID)
+		if err != nil {
+			return err
+		}
+		can, _, err := (&Project{ID: projectID}).CanRead(s, u)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return ErrUserDoesNotHaveAccessToProject{ProjectID: projectID, UserID: u.ID}
+		}
+	}
+
+	if r.EscalateToTeamID != 0 {
+		_, err = GetTeamByID(s, r.EscalateToTeamID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateEscalationDate computes the time the reminder escalates from the reminder and its escalation delay.
+func (r *TaskReminder) updateEscalationDate() {
+	r.EscalatesAt = time.Time{}
+	if r.EscalateAfter > 0 && !r.Reminder.IsZero() {
+		r.EscalatesAt = r.Reminder.Add(time.Duration(r.EscalateAfter) * time.Second)
+	}
+}
+
+// getReminderEscalationRecipients returns everyone who should be notified when a reminder escalates:
+// The users who got the reminder and, if configured, the project owner, the fallback user and the
+// members of the fallback team. Only users who can see the task and have reminders enabled are included.
+func getReminderEscalationRecipients(s *xorm.Session, r *TaskReminder, taskUsers []*taskUser, project *Project) (recipients []*user.User, err error) {
+	seen := make(map[int64]bool)
+	for _, tu := range taskUsers {
+		if seen[tu.User.ID] {
+			continue
+		}
+		seen[tu.User.ID] = true
+		recipients = append(recipients, tu.User)
+	}
+
+	var fallbackUserIDs []int64
+	if r.EscalateToProjectOwner && project != nil {
+		fallbackUserIDs = append(fallbackUserIDs, project.OwnerID)
+	}
+	if r.EscalateToUserID != 0 {
+		fallbackUserIDs = append(fallbackUserIDs, r.EscalateToUserID)
+	}
+	if r.EscalateToTeamID != 0 {
+		members := []*TeamMember{}
+		err = s.Where("team_id = ?", r.EscalateToTeamID).Find(&members)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			fallbackUserIDs = append(fallbackUserIDs, m.UserID)
+		}
+	}
+
+	if len(fallbackUserIDs) == 0 || project == nil {
+		return
+	}
+
+	fallbackUsers := make(map[int64]*user.User)
+	err = s.
+		In("id", fallbackUserIDs).
+		And("email_reminders_enabled = ?", true).
+		Find(&fallbackUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range fallbackUserIDs {
+		u, has := fallbackUsers[id]
+		if !has || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		can, _, err := project.CanRead(s, u)
+		if err != nil {
+			return nil, err
+		}
+		if !can {
+			continue
+		}
+
+		recipients = append(recipients, u)
+	}
+
+	return
+}
+
+// getReminderEscalationsDue returns notifications for all reminders which escalate in the minute after now
+// and belong to tasks which are still not done.
+func getReminderEscalationsDue(s *xorm.Session, now time.Time) (escalations []*ReminderEscalatedNotification, err error) {
+	now = utils.GetTimeWithoutNanoSeconds(now)
+	nextMinute := now.Add(1 * time.Minute)
+
+	reminders := []*TaskReminder{}
+	err = s.
+		Join("INNER", "tasks", "tasks.id = task_reminders.task_id").
+		Where("escalates_at >= ? and escalates_at < ?", now.Format(dbTimeFormat), nextMinute.Format(dbTimeFormat)).
+		And("tasks.done = false").
+		Find(&reminders)
+	if err != nil || len(reminders) == 0 {
+		return
+	}
+
+	log.Debugf("[Task Reminder Cron] Found %d reminders to escalate", len(reminders))
+
+	taskIDs := make([]int64, 0, len(reminders))
+	for _, r := range reminders {
+		taskIDs = append(taskIDs, r.TaskID)
+	}
+
+	usersWithReminders, err := getTaskUsersForTasks(s, taskIDs, builder.Eq{"users.email_reminders_enabled": true})
+	if err != nil {
+		return
+	}
+
+	usersPerTask := make(map[int64][]*taskUser, len(usersWithReminders))
+	for _, ur := range usersWithReminders {
+		usersPerTask[ur.Task.ID] = append(usersPerTask[ur.Task.ID], ur)
+	}
+
+	tasks := make(map[int64]*Task, len(taskIDs))
+	err = s.In("id", taskIDs).Find(&tasks)
+	if err != nil {
+		return
+	}
+
+	projects, err := GetProjectsMapSimplByTaskIDs(s, taskIDs)
+	if err != nil {
+		return
+	}
+
+	for _, r := range reminders {
+		task, has := tasks[r.TaskID]
+		if !has {
+			continue
+		}
+
+		project := projects[task.ProjectID]
+		if project != nil && project.remindersPaused() {
+			continue
+		}
+
+		recipients, err := getReminderEscalationRecipients(s, r, usersPerTask[r.TaskID], project)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range recipients {
+			escalations = append(escalations, &ReminderEscalatedNotification{
+				User:     u,
+				Task:     task,
+				Project:  project,
+				Reminder: r,
+			})
+		}
+	}
+
+	return
+}
+
+// sendReminderEscalations notifies everyone about reminders which escalate in the minute after now.
+func sendReminderEscalations(s *xorm.Session, now time.Time) {
+	escalations, err := getReminderEscalationsDue(s, now)
+	if err != nil {
+		log.Errorf("[Task Reminder Cron] Could not get reminders to escalate in the next minute: %s", err)
+		return
+	}
+
+	for _, n := range escalations {
+		err = notifications.Notify(n.User, n)
+		if err != nil {
+			log.Errorf("[Task Reminder Cron] Could not notify user %d about escalated reminder: %s", n.User.ID, err)
+			continue
+		}
+
+		log.Debugf("[Task Reminder Cron] Sent escalated reminder for task %d to user %d", n.Task.ID, n.User.ID)
+	}
+}
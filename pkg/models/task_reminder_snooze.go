@@ -0,0 +1,123 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskReminderSnooze snoozes a reminder of a task by creating a follow-up reminder
+type TaskReminderSnooze struct {
+	// The task the reminder belongs to.
+	TaskID int64 `json:"-" param:"task"`
+	// The time of the reminder which fired and should be snoozed. If provided, the task must have a reminder at this time.
+	Reminder time.Time `json:"reminder"`
+	// The number of seconds from now after which the follow-up reminder should fire. Either this or `until` must be provided.
+	Duration int64 `json:"duration"`
+	// The absolute time when the follow-up reminder should fire. Must be in the future.
+	Until time.Time `json:"until"`
+
+	// The newly created follow-up reminder.
+	SnoozedReminder *TaskReminder `json:"snoozed_reminder"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanUpdate checks if the user can snooze a reminder of the task
+func (rs *TaskReminderSnooze) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	t := &Task{ID: rs.TaskID}
+	return t.CanUpdate(s, a)
+}
+
+func (rs *TaskReminderSnooze) snoozedUntil(now time.Time) (until time.Time, err error) {
+	switch {
+	case rs.Duration > 0 && rs.Until.IsZero():
+		until = now.Add(time.Duration(rs.Duration) * time.Second)
+	case rs.Duration == 0 && rs.Until.After(now):
+		until = rs.Until
+	default:
+		return until, ErrInvalidReminderSnooze{TaskID: rs.TaskID}
+	}
+
+	return until.Truncate(time.Second), nil
+}
+
+// Update creates the follow-up reminder
+// @Summary Snooze a reminder
+// @Description Snoozes a fired reminder of a task by creating a follow-up reminder, either after a duration in seconds from now or at a specific time. The original reminder is kept.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param snooze body models.TaskReminderSnooze true "The reminder to snooze and when to be reminded again."
+// @Success 200 {object} models.TaskReminderSnooze "The follow-up reminder."
+// @Failure 400 {object} web.HTTPError "Neither a valid duration nor a time in the future was provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not have a reminder at the provided time."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/reminders/snooze [post]
+func (rs *TaskReminderSnooze) Update(s *xorm.Session, _ web.Auth) (err error) {
+	until, err := rs.snoozedUntil(time.Now())
+	if err != nil {
+		return err
+	}
+
+	reminders, err := getRemindersForTasks(s, []int64{rs.TaskID})
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for _, r := range reminders {
+		if !rs.Reminder.IsZero() && r.Reminder.Unix() == rs.Reminder.Unix() {
+			found = true
+		}
+		if r.Reminder.Unix() == until.Unix() {
+			// The task already has a reminder at the snoozed time, no need to add another one
+			rs.SnoozedReminder = r
+		}
+	}
+
+	if !rs.Reminder.IsZero() && !found {
+		return ErrReminderDoesNotExist{TaskID: rs.TaskID, Reminder: rs.Reminder}
+	}
+
+	if rs.SnoozedReminder != nil {
+		return nil
+	}
+
+	rs.SnoozedReminder = &TaskReminder{
+		TaskID:   rs.TaskID,
+		Reminder: until,
+	}
+	_, err = s.Insert(rs.SnoozedReminder)
+	if err != nil {
+		return err
+	}
+
+	err = updateTaskLastUpdated(s, &Task{ID: rs.TaskID})
+	if err != nil {
+		return err
+	}
+
+	return updateProjectByTaskID(s, rs.TaskID)
+}
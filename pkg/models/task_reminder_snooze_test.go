@@ -0,0 +1,170 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskReminderSnooze_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+	fired := time.Date(2018, 12, 1, 1, 12, 4, 0, time.UTC)
+
+	t.Run("by duration", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		before := time.Now().Truncate(time.Second)
+		rs := &TaskReminderSnooze{
+			TaskID:   27,
+			Reminder: fired,
+			Duration: 3600,
+		}
+		err := rs.Update(s, u)
+		require.NoError(t, err)
+		require.NotNil(t, rs.SnoozedReminder)
+		assert.NotZero(t, rs.SnoozedReminder.ID)
+		assert.False(t, rs.SnoozedReminder.Reminder.Before(before.Add(time.Hour)))
+		assert.True(t, rs.SnoozedReminder.Reminder.Before(time.Now().Add(time.Hour+time.Second)))
+
+		reminders, err := getRemindersForTasks(s, []int64{27})
+		require.NoError(t, err)
+		assert.Len(t, reminders, 3)
+	})
+	t.Run("until a time", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		until := time.Now().Add(48 * time.Hour).Truncate(time.Second).In(config.GetTimeZone())
+		rs := &TaskReminderSnooze{
+			TaskID: 27,
+			Until:  until,
+		}
+		err := rs.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, until.Unix(), rs.SnoozedReminder.Reminder.Unix())
+
+		reminders, err := getRemindersForTasks(s, []int64{27})
+		require.NoError(t, err)
+		require.Len(t, reminders, 3)
+		assert.Equal(t, until.Unix(), reminders[2].Reminder.Unix())
+	})
+	t.Run("twice to the same time", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		until := time.Now().Add(time.Hour)
+		rs := &TaskReminderSnooze{TaskID: 27, Until: until}
+		err := rs.Update(s, u)
+		require.NoError(t, err)
+		rs = &TaskReminderSnooze{TaskID: 27, Until: until}
+		err = rs.Update(s, u)
+		require.NoError(t, err)
+
+		reminders, err := getRemindersForTasks(s, []int64{27})
+		require.NoError(t, err)
+		assert.Len(t, reminders, 3)
+	})
+	t.Run("without duration or time", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rs := &TaskReminderSnooze{TaskID: 27, Reminder: fired}
+		err := rs.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidReminderSnooze(err))
+	})
+	t.Run("with both duration and time", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rs := &TaskReminderSnooze{TaskID: 27, Duration: 60, Until: time.Now().Add(time.Hour)}
+		err := rs.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidReminderSnooze(err))
+	})
+	t.Run("until a time in the past", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rs := &TaskReminderSnooze{TaskID: 27, Until: time.Now().Add(-time.Hour)}
+		err := rs.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidReminderSnooze(err))
+	})
+	t.Run("nonexisting reminder", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rs := &TaskReminderSnooze{TaskID: 27, Reminder: fired.Add(time.Minute), Duration: 60}
+		err := rs.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrReminderDoesNotExist(err))
+	})
+}
+
+func TestTaskReminderSnooze_CanUpdate(t *testing.T) {
+	t.Run("own task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rs := &TaskReminderSnooze{TaskID: 27}
+		can, err := rs.CanUpdate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rs := &TaskReminderSnooze{TaskID: 27}
+		can, err := rs.CanUpdate(s, &user.User{ID: 13})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestReminderDueNotification_Reminder(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	now, err := time.Parse(time.RFC3339Nano, "2018-12-01T01:12:00Z")
+	require.NoError(t, err)
+	notifications, err := getTasksWithRemindersDueAndTheirUsers(s, now)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.NotNil(t, notifications[0].Reminder)
+	assert.Equal(t, int64(1), notifications[0].Reminder.ID)
+}
@@ -51,3 +51,101 @@ func TestReminderGetTasksInTheNextMinute(t *testing.T) {
 		assert.Empty(t, taskIDs)
 	})
 }
+
+func TestReminderGetEscalationsInTheNextMinute(t *testing.T) {
+	t.Run("escalates to users who can see the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-12-01T02:12:00Z")
+		require.NoError(t, err)
+		escalations, err := getReminderEscalationsDue(s, now)
+		require.NoError(t, err)
+		// The owner of project 22 created the task and user 2 from team 1 is assigned to it, both are only notified once
+		require.Len(t, escalations, 2)
+		assert.Equal(t, int64(36), escalations[0].Task.ID)
+		assert.ElementsMatch(t, []int64{1, 2}, []int64{escalations[0].User.ID, escalations[1].User.ID})
+	})
+	t.Run("no escalation when the task is done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(36).Cols("done").Update(&Task{Done: true})
+		require.NoError(t, err)
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-12-01T02:12:00Z")
+		require.NoError(t, err)
+		escalations, err := getReminderEscalationsDue(s, now)
+		require.NoError(t, err)
+		assert.Empty(t, escalations)
+	})
+	t.Run("fallback recipients", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 3)
+		require.NoError(t, err)
+		r := &TaskReminder{EscalateAfter: 3600, EscalateToProjectOwner: true, EscalateToTeamID: 1}
+		recipients, err := getReminderEscalationRecipients(s, r, nil, project)
+		require.NoError(t, err)
+		assert.Len(t, recipients, 3)
+	})
+	t.Run("fallback recipients without access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		r := &TaskReminder{EscalateAfter: 3600, EscalateToTeamID: 1}
+		recipients, err := getReminderEscalationRecipients(s, r, nil, project)
+		require.NoError(t, err)
+		// User 2 from team 1 cannot see project 1
+		require.Len(t, recipients, 1)
+		assert.Equal(t, int64(1), recipients[0].ID)
+	})
+}
+
+func TestTaskReminder_validateEscalation(t *testing.T) {
+	t.Run("negative delay", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &TaskReminder{EscalateAfter: -1}
+		err := r.validateEscalation(s, 1)
+		require.Error(t, err)
+		assert.IsType(t, ValidationHTTPError{}, err)
+	})
+	t.Run("fallback user without access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &TaskReminder{EscalateAfter: 3600, EscalateToUserID: 2}
+		err := r.validateEscalation(s, 1)
+		require.Error(t, err)
+		assert.True(t, IsErrUserDoesNotHaveAccessToProject(err))
+	})
+	t.Run("nonexistent team", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &TaskReminder{EscalateAfter: 3600, EscalateToTeamID: 9999}
+		err := r.validateEscalation(s, 1)
+		require.Error(t, err)
+		assert.True(t, IsErrTeamDoesNotExist(err))
+	})
+	t.Run("escalation date", func(t *testing.T) {
+		r := &TaskReminder{
+			Reminder:      time.Date(2018, 12, 1, 1, 12, 4, 0, time.UTC),
+			EscalateAfter: 3600,
+		}
+		r.updateEscalationDate()
+		assert.Equal(t, time.Date(2018, 12, 1, 2, 12, 4, 0, time.UTC), r.EscalatesAt)
+	})
+}
@@ -0,0 +1,529 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+)
+
+type repeatRuleFrequency string
+
+const (
+	repeatRuleFrequencyDaily   repeatRuleFrequency = "DAILY"
+	repeatRuleFrequencyWeekly  repeatRuleFrequency = "WEEKLY"
+	repeatRuleFrequencyMonthly repeatRuleFrequency = "MONTHLY"
+	repeatRuleFrequencyYearly  repeatRuleFrequency = "YEARLY"
+)
+
+// The maximum number of periods searched for the next occurrence of a repeat rule.
+// This prevents endless loops for rules which never match, like every 30th of February.
+const repeatRuleMaxPeriods = 50000
+
+var repeatRuleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+type repeatRuleWeekday struct {
+	weekday time.Weekday
+	// 0 matches every weekday in the period, otherwise the nth weekday from the start (or end, if negative) of the period
+	n int
+}
+
+// repeatRule is a parsed RFC 5545 recurrence rule.
+// Only rules with a daily or larger frequency are supported, since tasks are not repeated more often than that.
+type repeatRule struct {
+	freq       repeatRuleFrequency
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []repeatRuleWeekday
+	byMonthDay []int
+	byMonth    []time.Month
+	bySetPos   []int
+	weekStart  time.Weekday
+}
+
+func weekdayToRepeatRule(weekday time.Weekday) string {
+	for name, day := range repeatRuleWeekdays {
+		if day == weekday {
+			return name
+		}
+	}
+	return ""
+}
+
+func parseRepeatRuleInts(value string, minValue, maxValue int, allowNegative bool) (ints []int, err error) {
+	for _, part := range strings.Split(value, ",") {
+		i, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		abs := i
+		if abs < 0 && allowNegative {
+			abs = -abs
+		}
+		if abs < minValue || abs > maxValue {
+			return nil, strconv.ErrRange
+		}
+		ints = append(ints, i)
+	}
+	return
+}
+
+func parseRepeatRuleUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+	if until, err := time.ParseInLocation("20060102T150405", value, config.GetTimeZone()); err == nil {
+		return until, nil
+	}
+	until, err := time.ParseInLocation("20060102", value, config.GetTimeZone())
+	if err != nil {
+		return until, err
+	}
+	// A date only includes the whole day
+	return until.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+//nolint:gocyclo
+func parseRepeatRule(rule string) (r *repeatRule, err error) {
+	invalid := func(reason string) error {
+		return ErrInvalidRepeatRule{Rule: rule, Reason: reason}
+	}
+
+	r = &repeatRule{
+		interval:  1,
+		weekStart: time.Monday,
+	}
+
+	raw := strings.TrimSpace(rule)
+	if strings.HasPrefix(strings.ToUpper(raw), "RRULE:") {
+		raw = raw[len("RRULE:"):]
+	}
+
+	for _, part := range strings.Split(raw, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found || value == "" {
+			return nil, invalid("every part needs a key and a value")
+		}
+		key = strings.ToUpper(key)
+		value = strings.ToUpper(value)
+
+		switch key {
+		case "FREQ":
+			r.freq = repeatRuleFrequency(value)
+			switch r.freq {
+			case repeatRuleFrequencyDaily,
+				repeatRuleFrequencyWeekly,
+				repeatRuleFrequencyMonthly,
+				repeatRuleFrequencyYearly:
+			default:
+				return nil, invalid("only daily, weekly, monthly and yearly frequencies are supported")
+			}
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err != nil || r.interval < 1 {
+				return nil, invalid("the interval must be a positive number")
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+			if err != nil || r.count < 1 {
+				return nil, invalid("the count must be a positive number")
+			}
+		case "UNTIL":
+			r.until, err = parseRepeatRuleUntil(value)
+			if err != nil {
+				return nil, invalid("the until date is invalid")
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				if len(day) < 2 {
+					return nil, invalid("invalid weekday " + day)
+				}
+				weekday, exists := repeatRuleWeekdays[day[len(day)-2:]]
+				if !exists {
+					return nil, invalid("invalid weekday " + day)
+				}
+				bd := repeatRuleWeekday{weekday: weekday}
+				if ordinal := day[:len(day)-2]; ordinal != "" {
+					bd.n, err = strconv.Atoi(ordinal)
+					if err != nil || bd.n == 0 || bd.n > 53 || bd.n < -53 {
+						return nil, invalid("invalid weekday " + day)
+					}
+				}
+				r.byDay = append(r.byDay, bd)
+			}
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseRepeatRuleInts(value, 1, 31, true)
+			if err != nil {
+				return nil, invalid("the month days must be between 1 and 31 or -31 and -1")
+			}
+		case "BYMONTH":
+			months, err := parseRepeatRuleInts(value, 1, 12, false)
+			if err != nil {
+				return nil, invalid("the months must be between 1 and 12")
+			}
+			for _, m := range months {
+				r.byMonth = append(r.byMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			r.bySetPos, err = parseRepeatRuleInts(value, 1, 366, true)
+			if err != nil {
+				return nil, invalid("the set positions must be between 1 and 366 or -366 and -1")
+			}
+		case "WKST":
+			weekday, exists := repeatRuleWeekdays[value]
+			if !exists {
+				return nil, invalid("invalid week start " + value)
+			}
+			r.weekStart = weekday
+		default:
+			return nil, invalid(key + " is not supported")
+		}
+	}
+
+	if r.freq == "" {
+		return nil, invalid("the frequency is required")
+	}
+	if r.count > 0 && !r.until.IsZero() {
+		return nil, invalid("count and until cannot be used together")
+	}
+	if len(r.byMonthDay) > 0 && r.freq == repeatRuleFrequencyWeekly {
+		return nil, invalid("month days cannot be used with a weekly frequency")
+	}
+	if len(r.bySetPos) > 0 && len(r.byDay) == 0 && len(r.byMonthDay) == 0 && len(r.byMonth) == 0 {
+		return nil, invalid("set positions need another by rule")
+	}
+	for _, bd := range r.byDay {
+		if bd.n != 0 && r.freq != repeatRuleFrequencyMonthly && r.freq != repeatRuleFrequencyYearly {
+			return nil, invalid("numbered weekdays can only be used with a monthly or yearly frequency")
+		}
+	}
+
+	return r, nil
+}
+
+// String returns the rule in its normalized RFC 5545 form, without the RRULE: prefix.
+func (r *repeatRule) String() string {
+	parts := []string{"FREQ=" + string(r.freq)}
+	if r.interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.interval))
+	}
+	if r.count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.count))
+	}
+	if !r.until.IsZero() {
+		parts = append(parts, "UNTIL="+r.until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.byMonth) > 0 {
+		months := make([]string, 0, len(r.byMonth))
+		for _, m := range r.byMonth {
+			months = append(months, strconv.Itoa(int(m)))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if len(r.byMonthDay) > 0 {
+		days := make([]string, 0, len(r.byMonthDay))
+		for _, d := range r.byMonthDay {
+			days = append(days, strconv.Itoa(d))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.byDay) > 0 {
+		days := make([]string, 0, len(r.byDay))
+		for _, bd := range r.byDay {
+			day := weekdayToRepeatRule(bd.weekday)
+			if bd.n != 0 {
+				day = strconv.Itoa(bd.n) + day
+			}
+			days = append(days, day)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.bySetPos) > 0 {
+		positions := make([]string, 0, len(r.bySetPos))
+		for _, p := range r.bySetPos {
+			positions = append(positions, strconv.Itoa(p))
+		}
+		parts = append(parts, "BYSETPOS="+strings.Join(positions, ","))
+	}
+	if r.weekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayToRepeatRule(r.weekStart))
+	}
+	return strings.Join(parts, ";")
+}
+
+// NormalizeRepeatRule checks a repeat rule is valid and supported and returns it in its normalized form.
+func NormalizeRepeatRule(rule string) (string, error) {
+	r, err := parseRepeatRule(rule)
+	if err != nil {
+		return "", err
+	}
+	return r.String(), nil
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// matchesWeekday checks if the date matches one of the weekdays of the rule, ignoring their position.
+func (r *repeatRule) matchesWeekday(d time.Time) bool {
+	for _, bd := range r.byDay {
+		if bd.weekday == d.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *repeatRule) matchesMonthDay(d time.Time) bool {
+	dim := daysInMonth(d.Year(), d.Month())
+	for _, md := range r.byMonthDay {
+		if md == d.Day() || (md < 0 && dim+1+md == d.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdaysInRange returns all days in [first, last] matching the weekdays of the rule, taking their position
+// in the range into account.
+func (r *repeatRule) weekdaysInRange(first, last time.Time) (days []time.Time) {
+	for _, bd := range r.byDay {
+		var matching []time.Time
+		for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+			if d.Weekday() == bd.weekday {
+				matching = append(matching, d)
+			}
+		}
+		switch {
+		case bd.n == 0:
+			days = append(days, matching...)
+		case bd.n > 0 && bd.n <= len(matching):
+			days = append(days, matching[bd.n-1])
+		case bd.n < 0 && -bd.n <= len(matching):
+			days = append(days, matching[len(matching)+bd.n])
+		}
+	}
+	return
+}
+
+// expandMonth returns all days of the month of the given date matching the rule.
+func (r *repeatRule) expandMonth(start time.Time, year int, month time.Month) (days []time.Time) {
+	first := time.Date(year, month, 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	dim := daysInMonth(year, month)
+	last := first.AddDate(0, 0, dim-1)
+
+	switch {
+	case len(r.byDay) > 0:
+		for _, d := range r.weekdaysInRange(first, last) {
+			if len(r.byMonthDay) == 0 || r.matchesMonthDay(d) {
+				days = append(days, d)
+			}
+		}
+	case len(r.byMonthDay) > 0:
+		for _, md := range r.byMonthDay {
+			if md < 0 {
+				md = dim + 1 + md
+			}
+			if md >= 1 && md <= dim {
+				days = append(days, first.AddDate(0, 0, md-1))
+			}
+		}
+	default:
+		if start.Day() <= dim {
+			days = append(days, first.AddDate(0, 0, start.Day()-1))
+		}
+	}
+	return
+}
+
+// periodCandidates returns all occurrences of the rule in the period with the given offset from the start period.
+func (r *repeatRule) periodCandidates(start time.Time, offset int) (candidates []time.Time) {
+	switch r.freq {
+	case repeatRuleFrequencyDaily:
+		d := start.AddDate(0, 0, offset)
+		if (len(r.byMonth) == 0 || slices.Contains(r.byMonth, d.Month())) &&
+			(len(r.byMonthDay) == 0 || r.matchesMonthDay(d)) &&
+			(len(r.byDay) == 0 || r.matchesWeekday(d)) {
+			candidates = append(candidates, d)
+		}
+	case repeatRuleFrequencyWeekly:
+		diff := (int(start.Weekday()) - int(r.weekStart) + 7) % 7
+		weekStart := start.AddDate(0, 0, 7*offset-diff)
+		if len(r.byDay) == 0 {
+			candidates = append(candidates, weekStart.AddDate(0, 0, diff))
+		}
+		for _, bd := range r.byDay {
+			candidates = append(candidates, weekStart.AddDate(0, 0, (int(bd.weekday)-int(r.weekStart)+7)%7))
+		}
+		if len(r.byMonth) > 0 {
+			candidates = slices.DeleteFunc(candidates, func(d time.Time) bool {
+				return !slices.Contains(r.byMonth, d.Month())
+			})
+		}
+	case repeatRuleFrequencyMonthly:
+		month := time.Date(start.Year(), start.Month()+time.Month(offset), 1, 0, 0, 0, 0, start.Location())
+		if len(r.byMonth) == 0 || slices.Contains(r.byMonth, month.Month()) {
+			candidates = r.expandMonth(start, month.Year(), month.Month())
+		}
+	case repeatRuleFrequencyYearly:
+		year := start.Year() + offset
+		switch {
+		case len(r.byMonth) > 0:
+			for _, m := range r.byMonth {
+				candidates = append(candidates, r.expandMonth(start, year, m)...)
+			}
+		case len(r.byMonthDay) > 0:
+			for m := time.January; m <= time.December; m++ {
+				candidates = append(candidates, r.expandMonth(start, year, m)...)
+			}
+		case len(r.byDay) > 0:
+			first := time.Date(year, time.January, 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+			candidates = r.weekdaysInRange(first, first.AddDate(1, 0, -1))
+		default:
+			if start.Day() <= daysInMonth(year, start.Month()) {
+				candidates = append(candidates, time.Date(year, start.Month(), start.Day(), start.Hour(), start.Minute(), start.Second(), 0, start.Location()))
+			}
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+	candidates = slices.CompactFunc(candidates, func(a, b time.Time) bool {
+		return a.Equal(b)
+	})
+
+	if len(r.bySetPos) == 0 {
+		return candidates
+	}
+
+	selected := []time.Time{}
+	for _, pos := range r.bySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(candidates) + pos
+		}
+		if i >= 0 && i < len(candidates) {
+			selected = append(selected, candidates[i])
+		}
+	}
+	slices.SortFunc(selected, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+	return slices.CompactFunc(selected, func(a, b time.Time) bool {
+		return a.Equal(b)
+	})
+}
+
+// occurrences calls fn with all occurrences of the rule after start in chronological order until fn returns false.
+func (r *repeatRule) occurrences(start time.Time, fn func(occurrence time.Time) bool) {
+	for i := 0; i < repeatRuleMaxPeriods; i++ {
+		for _, occurrence := range r.periodCandidates(start, i*r.interval) {
+			if !occurrence.After(start) {
+				continue
+			}
+			if !r.until.IsZero() && occurrence.After(r.until) {
+				return
+			}
+			if !fn(occurrence) {
+				return
+			}
+		}
+	}
+}
+
+// setTaskDatesFromRepeatRule moves all dates of a task to the next occurrence of its repeat rule which is in the future.
+// The due date is used as start of the rule, if it is not set, the start or end date is used.
+// If the rule does not have another occurrence, the task stays done.
+func setTaskDatesFromRepeatRule(oldTask, newTask *Task) {
+	rule, err := parseRepeatRule(oldTask.RepeatRule)
+	if err != nil {
+		// Rules are validated when saving the task
+		return
+	}
+
+	base := oldTask.DueDate
+	if base.IsZero() {
+		base = oldTask.StartDate
+	}
+	if base.IsZero() {
+		base = oldTask.EndDate
+	}
+	if base.IsZero() {
+		newTask.Done = false
+		return
+	}
+
+	now := time.Now()
+	var next time.Time
+	var passed int
+	rule.occurrences(base, func(occurrence time.Time) bool {
+		passed++
+		// The count includes the current occurrence
+		if rule.count > 0 && passed >= rule.count {
+			return false
+		}
+		if occurrence.After(now) {
+			next = occurrence
+			return false
+		}
+		return true
+	})
+
+	if next.IsZero() {
+		return
+	}
+
+	diff := next.Sub(base)
+	if !oldTask.DueDate.IsZero() {
+		newTask.DueDate = oldTask.DueDate.Add(diff)
+	}
+	if !oldTask.StartDate.IsZero() {
+		newTask.StartDate = oldTask.StartDate.Add(diff)
+	}
+	if !oldTask.EndDate.IsZero() {
+		newTask.EndDate = oldTask.EndDate.Add(diff)
+	}
+
+	newTask.Reminders = oldTask.Reminders
+	for _, r := range newTask.Reminders {
+		r.Reminder = r.Reminder.Add(diff)
+	}
+
+	if rule.count > 0 {
+		rule.count -= passed
+		newTask.RepeatRule = rule.String()
+	}
+
+	newTask.Done = false
+}
@@ -0,0 +1,249 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nextOccurrences(t *testing.T, rule string, start time.Time, n int) []time.Time {
+	r, err := parseRepeatRule(rule)
+	require.NoError(t, err)
+
+	occurrences := []time.Time{}
+	r.occurrences(start, func(occurrence time.Time) bool {
+		occurrences = append(occurrences, occurrence)
+		return len(occurrences) < n
+	})
+	return occurrences
+}
+
+func TestRepeatRule_Occurrences(t *testing.T) {
+	// A Tuesday
+	start := time.Date(2024, 1, 9, 10, 0, 0, 0, time.UTC)
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2024, month, day, 10, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		rule string
+		want []time.Time
+	}{
+		{
+			name: "daily",
+			rule: "FREQ=DAILY",
+			want: []time.Time{day(1, 10), day(1, 11), day(1, 12)},
+		},
+		{
+			name: "every other day",
+			rule: "RRULE:FREQ=DAILY;INTERVAL=2",
+			want: []time.Time{day(1, 11), day(1, 13), day(1, 15)},
+		},
+		{
+			name: "weekly on weekdays",
+			rule: "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			want: []time.Time{day(1, 10), day(1, 12), day(1, 15)},
+		},
+		{
+			name: "every second week",
+			rule: "FREQ=WEEKLY;INTERVAL=2",
+			want: []time.Time{day(1, 23), day(2, 6), day(2, 20)},
+		},
+		{
+			name: "every 2nd tuesday of the month",
+			rule: "FREQ=MONTHLY;BYDAY=2TU",
+			want: []time.Time{day(2, 13), day(3, 12), day(4, 9)},
+		},
+		{
+			name: "last weekday of the month",
+			rule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			want: []time.Time{day(1, 31), day(2, 29), day(3, 29)},
+		},
+		{
+			name: "last day of the month",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=-1",
+			want: []time.Time{day(1, 31), day(2, 29), day(3, 31)},
+		},
+		{
+			name: "monthly skips months without the day",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=31",
+			want: []time.Time{day(1, 31), day(3, 31), day(5, 31)},
+		},
+		{
+			name: "yearly in march and june",
+			rule: "FREQ=YEARLY;BYMONTH=3,6",
+			want: []time.Time{day(3, 9), day(6, 9), time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "until",
+			rule: "FREQ=WEEKLY;UNTIL=20240124T000000Z",
+			want: []time.Time{day(1, 16), day(1, 23)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextOccurrences(t, tt.rule, start, 3))
+		})
+	}
+}
+
+func TestNormalizeRepeatRule(t *testing.T) {
+	t.Run("normalized", func(t *testing.T) {
+		rule, err := NormalizeRepeatRule("RRULE:freq=monthly;byday=-1fr;interval=1;wkst=su")
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=MONTHLY;BYDAY=-1FR;WKST=SU", rule)
+	})
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=WEEKLY;BYDAY=2TU",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYSETPOS=1",
+		"FREQ=MONTHLY;BYHOUR=10",
+	}
+	for _, rule := range invalid {
+		_, err := NormalizeRepeatRule(rule)
+		require.Error(t, err, rule)
+		assert.True(t, IsErrInvalidRepeatRule(err), rule)
+	}
+}
+
+func TestSetTaskDatesFromRepeatRule(t *testing.T) {
+	// Use dates in the future so the next occurrence does not depend on the current date
+	due := time.Date(time.Now().Year()+1, 1, 9, 10, 0, 0, 0, time.UTC)
+
+	t.Run("moves all dates", func(t *testing.T) {
+		oldTask := &Task{
+			Done:       false,
+			DueDate:    due,
+			StartDate:  due.Add(-48 * time.Hour),
+			RepeatRule: "FREQ=MONTHLY;BYDAY=2TU",
+			Reminders: []*TaskReminder{
+				{Reminder: due.Add(-time.Hour)},
+			},
+		}
+		newTask := &Task{Done: true}
+		updateDone(oldTask, newTask)
+
+		next := nextOccurrences(t, oldTask.RepeatRule, due, 1)[0]
+		assert.False(t, newTask.Done)
+		assert.Equal(t, next, newTask.DueDate)
+		assert.Equal(t, next.Add(-48*time.Hour), newTask.StartDate)
+		assert.Equal(t, next.Add(-time.Hour), newTask.Reminders[0].Reminder)
+	})
+	t.Run("count is decreased", func(t *testing.T) {
+		oldTask := &Task{
+			DueDate:    due,
+			RepeatRule: "FREQ=DAILY;COUNT=3",
+		}
+		newTask := &Task{Done: true}
+		updateDone(oldTask, newTask)
+
+		assert.False(t, newTask.Done)
+		assert.Equal(t, due.AddDate(0, 0, 1), newTask.DueDate)
+		assert.Equal(t, "FREQ=DAILY;COUNT=2", newTask.RepeatRule)
+	})
+	t.Run("no more occurrences", func(t *testing.T) {
+		oldTask := &Task{
+			DueDate:    due,
+			RepeatRule: "FREQ=DAILY;COUNT=1",
+		}
+		newTask := &Task{Done: true}
+		updateDone(oldTask, newTask)
+
+		assert.True(t, newTask.Done)
+		assert.True(t, newTask.DueDate.IsZero())
+	})
+	t.Run("skips occurrences in the past", func(t *testing.T) {
+		oldTask := &Task{
+			DueDate:    time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC),
+			RepeatRule: "FREQ=WEEKLY",
+		}
+		newTask := &Task{Done: true}
+		updateDone(oldTask, newTask)
+
+		assert.False(t, newTask.Done)
+		assert.True(t, newTask.DueDate.After(time.Now()))
+		assert.Equal(t, time.Monday, newTask.DueDate.Weekday())
+	})
+}
+
+func TestTask_Update_RepeatRule(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("invalid rule", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:         1,
+			Title:      "task #1",
+			RepeatRule: "FREQ=SECONDLY",
+		}
+		err := task.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidRepeatRule(err))
+	})
+	t.Run("mark as done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		due := time.Date(time.Now().Year()+1, 1, 9, 10, 0, 0, 0, time.Local)
+		task := &Task{
+			ID:         1,
+			Title:      "task #1",
+			DueDate:    due,
+			RepeatRule: "rrule:freq=monthly;byday=2tu",
+		}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=MONTHLY;BYDAY=2TU", task.RepeatRule)
+
+		task.Done = true
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		assert.False(t, task.Done)
+		assert.Equal(t, time.Tuesday, task.DueDate.Weekday())
+		assert.True(t, task.DueDate.After(due))
+		assert.True(t, task.DueDate.Day() >= 8 && task.DueDate.Day() <= 14)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":          1,
+			"done":        false,
+			"repeat_rule": "FREQ=MONTHLY;BYDAY=2TU",
+		}, false)
+	})
+}
@@ -0,0 +1,223 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+const (
+	scheduleConflictsDateFormat  = "2006-01-02"
+	scheduleConflictsDefaultDays = 30
+	scheduleConflictsMaxDays     = 366
+	scheduleConflictsDay         = 24 * time.Hour
+)
+
+// normalizeSchedule keeps start date, end date and duration of a task consistent.
+func (t *Task) normalizeSchedule() {
+	if t.StartDate.IsZero() {
+		return
+	}
+
+	if t.EndDate.IsZero() && t.Duration > 0 {
+		t.EndDate = t.StartDate.Add(time.Duration(t.Duration) * time.Second)
+		return
+	}
+
+	if !t.EndDate.IsZero() && t.EndDate.After(t.StartDate) {
+		t.Duration = int64(t.EndDate.Sub(t.StartDate).Seconds())
+	}
+}
+
+// ScheduleConflicts holds all tasks of a user whose schedules overlap in a period
+type ScheduleConflicts struct {
+	// The user whose tasks should be checked. Defaults to the current user.
+	UserID int64 `json:"user_id" query:"user_id"`
+	// The first day of the checked period in the format YYYY-MM-DD. Defaults to today.
+	From string `json:"from" query:"from"`
+	// The last day of the checked period in the format YYYY-MM-DD. Defaults to 30 days after the first day.
+	To string `json:"to" query:"to"`
+
+	// All scheduled tasks which overlap with at least one other task of the user.
+	Conflicts []*TaskScheduleConflict `json:"conflicts"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// TaskScheduleConflict holds a task and all other tasks scheduled at the same time
+type TaskScheduleConflict struct {
+	Task *Task `json:"task"`
+	// All other tasks of the user whose schedule overlaps with this task.
+	OverlapsWith []*Task `json:"overlaps_with"`
+}
+
+// CanRead checks if the user can check schedule conflicts
+func (sc *ScheduleConflicts) CanRead(_ *xorm.Session, a web.Auth) (bool, int, error) {
+	// Link shares don't have a schedule
+	if _, is := a.(*LinkSharing); is {
+		return false, 0, nil
+	}
+
+	return true, int(RightRead), nil
+}
+
+func (sc *ScheduleConflicts) getPeriod() (from, to time.Time, err error) {
+	tz := config.GetTimeZone()
+	now := time.Now().In(tz)
+	from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+
+	if sc.From != "" {
+		from, err = time.ParseInLocation(scheduleConflictsDateFormat, sc.From, tz)
+		if err != nil {
+			return from, to, ErrInvalidData{Message: "The start date must be in the format YYYY-MM-DD."}
+		}
+	}
+
+	to = from.AddDate(0, 0, scheduleConflictsDefaultDays-1)
+	if sc.To != "" {
+		to, err = time.ParseInLocation(scheduleConflictsDateFormat, sc.To, tz)
+		if err != nil {
+			return from, to, ErrInvalidData{Message: "The end date must be in the format YYYY-MM-DD."}
+		}
+	}
+
+	if from.After(to) {
+		return from, to, ErrInvalidData{Message: "The start date must be before the end date."}
+	}
+
+	if to.Sub(from) >= scheduleConflictsMaxDays*scheduleConflictsDay {
+		return from, to, ErrInvalidData{Message: "The checked period can't be longer than a year."}
+	}
+
+	sc.From = from.Format(scheduleConflictsDateFormat)
+	sc.To = to.Format(scheduleConflictsDateFormat)
+
+	return
+}
+
+// ReadOne returns all overlapping tasks of a user
+// @Summary Get schedule conflicts
+// @Description Returns all undone tasks assigned to a user which have a start and end date in the given period and overlap with at least one other task assigned to the same user. Only tasks in projects the current user has access to are checked.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param user_id query int false "The user whose tasks should be checked. Defaults to the current user."
+// @Param from query string false "The first day of the checked period in the format YYYY-MM-DD. Defaults to today."
+// @Param to query string false "The last day of the checked period in the format YYYY-MM-DD. Defaults to 30 days after the first day."
+// @Success 200 {object} models.ScheduleConflicts "The conflicting tasks."
+// @Failure 400 {object} web.HTTPError "Invalid period provided."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/schedule/conflicts [get]
+func (sc *ScheduleConflicts) ReadOne(s *xorm.Session, a web.Auth) (err error) {
+	from, to, err := sc.getPeriod()
+	if err != nil {
+		return err
+	}
+	// The checked period includes the whole last day
+	end := to.Add(scheduleConflictsDay)
+
+	if sc.UserID == 0 {
+		sc.UserID = a.GetID()
+	}
+	_, err = user.GetUserByID(s, sc.UserID)
+	if err != nil {
+		return err
+	}
+
+	projects, _, _, err := getRawProjectsForUser(s, &projectOptions{
+		user: &user.User{ID: a.GetID(), APITokenProjectIDs: getAPITokenProjectIDs(a)},
+	})
+	if err != nil {
+		return err
+	}
+	projectIDs := make([]int64, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	sc.Conflicts = []*TaskScheduleConflict{}
+	if len(projectIDs) == 0 {
+		return nil
+	}
+
+	tasks := []*Task{}
+	err = s.
+		Where(builder.And(
+			builder.In("id", builder.Select("task_id").From("task_assignees").Where(builder.Eq{"user_id": sc.UserID})),
+			builder.In("project_id", projectIDs),
+			builder.Eq{"done": false},
+			builder.NotNull{"start_date"},
+			builder.NotNull{"end_date"},
+			builder.Lt{"start_date": end},
+			builder.Gt{"end_date": from},
+		)).
+		OrderBy("start_date asc, id asc").
+		Find(&tasks)
+	if err != nil {
+		return err
+	}
+
+	overlaps := make(map[int64][]*Task)
+	for i, t := range tasks {
+		for _, other := range tasks[i+1:] {
+			// The tasks are sorted by start date, so no later task can overlap once one starts after this task ends
+			if !other.StartDate.Before(t.EndDate) {
+				break
+			}
+			overlaps[t.ID] = append(overlaps[t.ID], other)
+			overlaps[other.ID] = append(overlaps[other.ID], t)
+		}
+	}
+
+	if len(overlaps) == 0 {
+		return nil
+	}
+
+	taskMap := make(map[int64]*Task, len(overlaps))
+	for _, t := range tasks {
+		if _, has := overlaps[t.ID]; has {
+			taskMap[t.ID] = t
+		}
+	}
+	err = addMoreInfoToTasks(s, taskMap, a)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		others, has := overlaps[t.ID]
+		if !has {
+			continue
+		}
+		sc.Conflicts = append(sc.Conflicts, &TaskScheduleConflict{
+			Task:         t,
+			OverlapsWith: others,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestTask_normalizeSchedule(t *testing.T) {
+	start := time.Date(2018, 12, 12, 8, 0, 0, 0, time.UTC)
+
+	t.Run("end date from duration", func(t *testing.T) {
+		task := &Task{StartDate: start, Duration: 3600}
+		task.normalizeSchedule()
+		assert.Equal(t, start.Add(time.Hour), task.EndDate)
+	})
+	t.Run("duration from start and end date", func(t *testing.T) {
+		task := &Task{StartDate: start, EndDate: start.Add(2 * time.Hour), Duration: 3600}
+		task.normalizeSchedule()
+		assert.Equal(t, int64(7200), task.Duration)
+	})
+	t.Run("no start date", func(t *testing.T) {
+		task := &Task{Duration: 3600}
+		task.normalizeSchedule()
+		assert.True(t, task.EndDate.IsZero())
+		assert.Equal(t, int64(3600), task.Duration)
+	})
+}
+
+func TestScheduleConflicts_ReadOne(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	prepare := func(t *testing.T, s *xorm.Session) {
+		_, err := s.Insert(&TaskAssginee{TaskID: 9, UserID: 1}, &TaskAssginee{TaskID: 10, UserID: 1})
+		require.NoError(t, err)
+		// Task 9 runs from 2018-12-12 07:33:20 to 2018-12-13 11:20:00
+		_, err = s.ID(10).Cols("start_date", "end_date").Update(&Task{
+			StartDate: time.Date(2018, 12, 13, 10, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2018, 12, 14, 10, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+		_, err = s.ID(30).Cols("start_date", "end_date").Update(&Task{
+			StartDate: time.Date(2018, 12, 20, 10, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2018, 12, 21, 10, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("overlapping tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		prepare(t, s)
+
+		sc := &ScheduleConflicts{From: "2018-12-01", To: "2018-12-31"}
+		err := sc.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), sc.UserID)
+		require.Len(t, sc.Conflicts, 2)
+		assert.Equal(t, int64(9), sc.Conflicts[0].Task.ID)
+		require.Len(t, sc.Conflicts[0].OverlapsWith, 1)
+		assert.Equal(t, int64(10), sc.Conflicts[0].OverlapsWith[0].ID)
+		assert.Equal(t, int64(10), sc.Conflicts[1].Task.ID)
+		require.Len(t, sc.Conflicts[1].OverlapsWith, 1)
+		assert.Equal(t, int64(9), sc.Conflicts[1].OverlapsWith[0].ID)
+	})
+	t.Run("outside of the period", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		prepare(t, s)
+
+		sc := &ScheduleConflicts{From: "2018-12-15", To: "2018-12-31"}
+		err := sc.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Empty(t, sc.Conflicts)
+	})
+	t.Run("other user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		prepare(t, s)
+
+		sc := &ScheduleConflicts{UserID: 2, From: "2018-12-01", To: "2018-12-31"}
+		err := sc.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Empty(t, sc.Conflicts)
+	})
+	t.Run("api token restricted to a project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		prepare(t, s)
+
+		token := &user.User{ID: 1, AuthenticatedWithAPIToken: true, APITokenProjectIDs: []int64{22}}
+		sc := &ScheduleConflicts{From: "2018-12-01", To: "2018-12-31"}
+		err := sc.ReadOne(s, token)
+		require.NoError(t, err)
+		assert.Empty(t, sc.Conflicts)
+	})
+	t.Run("invalid period", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sc := &ScheduleConflicts{From: "2018-12-31", To: "2018-12-01"}
+		err := sc.ReadOne(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidData(err))
+	})
+	t.Run("nonexistent user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sc := &ScheduleConflicts{UserID: 9999}
+		err := sc.ReadOne(s, u)
+		require.Error(t, err)
+		assert.True(t, user.IsErrUserDoesNotExist(err))
+	})
+}
@@ -22,8 +22,10 @@ import (
 	"strings"
 	"time"
 
+	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/api/pkg/db"
 	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web"
 	"github.com/typesense/typesense-go/typesense/api"
 	"github.com/typesense/typesense-go/typesense/api/pointer"
@@ -47,20 +49,38 @@ func getOrderByDBStatement(opts *taskSearchOptions) (orderby string, err error)
 	// Since xorm does not use placeholders for order by, it is possible to expose this with sql injection if we're directly
 	// passing user input to the db.
 	// As a workaround to prevent this, we check for valid column names here prior to passing it to the db.
+	// Pinned tasks are sorted first, regardless of the sort parameters.
+	if opts.pinnedFirst {
+		orderby = "`is_pinned` DESC, `pinned_position` ASC"
+		if len(opts.sortby) > 0 {
+			orderby += ", "
+		}
+	}
 	for i, param := range opts.sortby {
 		// Validate the params
 		if err := param.validate(); err != nil {
 			return "", err
 		}
 
+		column := "`" + param.sortBy + "`"
+		if param.sortBy == taskPropertyPosition && opts.userPositionsFor > 0 {
+			column = getUserPositionOrderColumn(opts.userPositionsFor)
+		}
+		if param.sortBy == taskPropertyVotes {
+			column = getTaskVoteCountColumn()
+		}
+		if param.sortBy == taskPropertyMilestoneDueDate {
+			column = getMilestoneDueDateColumn()
+		}
+
 		// Mysql sorts columns with null values before ones without null value.
 		// Because it does not have support for NULLS FIRST or NULLS LAST we work around this by
 		// first sorting for null (or not null) values and then the order we actually want to.
 		if db.Type() == schemas.MYSQL {
-			orderby += "`" + param.sortBy + "` IS NULL, "
+			orderby += column + " IS NULL, "
 		}
 
-		orderby += "`" + param.sortBy + "` " + param.orderBy.String()
+		orderby += column + " " + param.orderBy.String()
 
 		// Postgres and sqlite allow us to control how columns with null values are sorted.
 		// To make that consistent with the sort order we have and other dbms, we're adding a separate clause here.
@@ -76,14 +96,14 @@ func getOrderByDBStatement(opts *taskSearchOptions) (orderby string, err error)
 	return
 }
 
-func convertFiltersToDBFilterCond(rawFilters []*taskFilter, includeNulls bool) (filterCond builder.Cond, err error) {
+func convertFiltersToDBFilterCond(rawFilters []*taskFilter, includeNulls bool, doer *user.User) (filterCond builder.Cond, err error) {
 
 	var dbFilters = make([]builder.Cond, 0, len(rawFilters))
 	// To still find tasks with nil values, we exclude 0s when comparing with >/< values.
 	for _, f := range rawFilters {
 
 		if nested, is := f.value.([]*taskFilter); is {
-			nestedDBFilters, err := convertFiltersToDBFilterCond(nested, includeNulls)
+			nestedDBFilters, err := convertFiltersToDBFilterCond(nested, includeNulls, doer)
 			if err != nil {
 				return nil, err
 			}
@@ -91,6 +111,34 @@ func convertFiltersToDBFilterCond(rawFilters []*taskFilter, includeNulls bool) (
 			continue
 		}
 
+		if cf, is := f.value.(*customFieldFilter); is {
+			dbFilters = append(dbFilters, getCustomFieldFilterCond(cf, f.comparator, includeNulls))
+			continue
+		}
+
+		if lf, is := f.value.(*locationFilter); is {
+			dbFilters = append(dbFilters, getLocationFilterCond(lf))
+			continue
+		}
+
+		if inf, is := f.value.(*interactionFilter); is {
+			filter, err := getInteractionFilterCond(inf, doer, includeNulls)
+			if err != nil {
+				return nil, err
+			}
+			dbFilters = append(dbFilters, filter)
+			continue
+		}
+
+		if f.field == taskPropertyVotes {
+			filter, err := getVotesFilterCond(f, includeNulls)
+			if err != nil {
+				return nil, err
+			}
+			dbFilters = append(dbFilters, filter)
+			continue
+		}
+
 		if f.field == "reminders" {
 			filter, err := getFilterCond(&taskFilter{
 				// recreating the struct here to avoid modifying it when reusing the opts struct
@@ -196,17 +244,59 @@ func convertFiltersToDBFilterCond(rawFilters []*taskFilter, includeNulls bool) (
 	return filterCond, nil
 }
 
-//nolint:gocyclo
-func (d *dbTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task, totalCount int64, err error) {
+// taskMatchesFilter checks if the task with the given id matches a filter query.
+// An empty filter matches every task.
+func taskMatchesFilter(s *xorm.Session, taskID int64, filter string) (bool, error) {
+	if filter == "" {
+		return true, nil
+	}
 
-	orderby, err := getOrderByDBStatement(opts)
+	filters, err := getTaskFiltersFromFilterString(filter, "")
 	if err != nil {
-		return nil, 0, err
+		return false, err
 	}
 
-	filterCond, err := convertFiltersToDBFilterCond(opts.parsedFilters, opts.filterIncludeNulls)
+	filterCond, err := convertFiltersToDBFilterCond(filters, false, nil)
 	if err != nil {
-		return nil, 0, err
+		return false, err
+	}
+
+	return s.
+		Where(builder.And(builder.Eq{"id": taskID}, filterCond)).
+		Exist(&Task{})
+}
+
+// hasArchivedFilter checks whether the filters explicitly ask for archived or unarchived tasks.
+// If they don't, archived tasks are hidden from the results.
+func hasArchivedFilter(filters []*taskFilter) bool {
+	for _, f := range filters {
+		if nested, is := f.value.([]*taskFilter); is && hasArchivedFilter(nested) {
+			return true
+		}
+		if f.field == taskPropertyIsArchived {
+			return true
+		}
+	}
+	return false
+}
+
+// getSearchCond returns the condition which matches all tasks for the search options. If fuzzy search is enabled
+// and no task matches the search exactly, the condition matches the tasks with a similar title instead.
+//
+//nolint:gocyclo
+func (d *dbTaskSearcher) getSearchCond(opts *taskSearchOptions) (cond builder.Cond, err error) {
+
+	var doer *user.User
+	if _, isShare := d.a.(*LinkSharing); !isShare && hasInteractionFilter(opts.parsedFilters) {
+		doer, err = user.GetUserByID(d.s, d.a.GetID())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filterCond, err := convertFiltersToDBFilterCond(opts.parsedFilters, opts.filterIncludeNulls, doer)
+	if err != nil {
+		return nil, err
 	}
 
 	// Then return all tasks for that projects
@@ -217,8 +307,20 @@ func (d *dbTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task, totalCo
 			builder.Or(
 				db.ILIKE("title", opts.search),
 				db.ILIKE("description", opts.search),
+				builder.In("id", builder.
+					Select("task_id").
+					From("task_comments").
+					Where(db.ILIKE("comment", opts.search))),
+				builder.In("id", builder.
+					Select("task_id").
+					From("task_attachments").
+					Where(db.ILIKE("text_content", opts.search))),
 			)
 
+		if config.ServiceEnableFuzzySearch.GetBool() && isPgTrgmAvailable(d.s) {
+			where = builder.Or(where, builder.Expr("word_similarity(?, title) >= ?", opts.search, pgTrgmWordSimilarityThreshold))
+		}
+
 		searchIndex := getTaskIndexFromSearchString(opts.search)
 		if searchIndex > 0 {
 			where = builder.Or(where, builder.Eq{"`index`": searchIndex})
@@ -245,9 +347,48 @@ func (d *dbTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task, totalCo
 		favoritesCond = builder.In("id", favCond)
 	}
 
-	limit, start := getLimitFromPageIndex(opts.page, opts.perPage)
-	cond := builder.And(builder.Or(projectIDCond, favoritesCond), where, filterCond)
+	var archivedCond builder.Cond
+	if !hasArchivedFilter(opts.parsedFilters) {
+		archivedCond = builder.Eq{"is_archived": false}
+	}
+
+	baseCond := builder.And(builder.Or(projectIDCond, favoritesCond), filterCond, archivedCond)
+	cond = builder.And(baseCond, where)
+
+	if opts.search == "" || !config.ServiceEnableFuzzySearch.GetBool() {
+		return cond, nil
+	}
+
+	exists, err := d.s.Where(cond).Exist(&Task{})
+	if err != nil || exists {
+		return cond, err
+	}
+
+	// Nothing matched exactly, try again with tolerance for small typos in the title.
+	fuzzyIDs, err := d.findTaskIDsByNormalizedTitle(opts.search, baseCond)
+	if err != nil {
+		return nil, err
+	}
+	if len(fuzzyIDs) > 0 {
+		cond = builder.And(baseCond, builder.In("id", fuzzyIDs))
+	}
+
+	return cond, nil
+}
+
+func (d *dbTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task, totalCount int64, err error) {
 
+	orderby, err := getOrderByDBStatement(opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cond, err := d.getSearchCond(opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	limit, start := getLimitFromPageIndex(opts.page, opts.perPage)
 	query := d.s.Where(cond)
 	if limit > 0 {
 		query = query.Limit(limit, start)
@@ -256,15 +397,18 @@ func (d *dbTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task, totalCo
 	tasks = []*Task{}
 	err = query.OrderBy(orderby).Find(&tasks)
 	if err != nil {
-		return nil, totalCount, err
+		return nil, 0, err
 	}
 
-	queryCount := d.s.Where(cond)
-	totalCount, err = queryCount.
+	totalCount, err = d.s.
+		Where(cond).
 		Count(&Task{})
 	if err != nil {
-		return nil, totalCount, err
+		return nil, 0, err
+	}
 
+	if opts.facets != nil {
+		err = d.fillFacets(opts.facets, cond)
 	}
 
 	return
@@ -405,24 +549,28 @@ func convertParsedFilterToTypesense(rawFilters []*taskFilter) (filterBy string,
 func (t *typesenseTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task, totalCount int64, err error) {
 
 	var sortbyFields []string
-	for i, param := range opts.sortby {
+	if opts.pinnedFirst {
+		// Only pinned tasks have a pinned position in the index, all others come after them
+		sortbyFields = append(sortbyFields, "pinned_position(missing_values:last):asc")
+	}
+	for _, param := range opts.sortby {
 		// Validate the params
 		if err := param.validate(); err != nil {
 			return nil, totalCount, err
 		}
 
+		// Typesense supports up to 3 sorting parameters
+		// https://typesense.org/docs/0.25.0/api/search.html#ranking-and-sorting-parameters
+		if len(sortbyFields) == 3 {
+			break
+		}
+
 		// Typesense does not allow sorting by ID, so we sort by created timestamp instead
 		if param.sortBy == "id" {
 			param.sortBy = "created"
 		}
 
 		sortbyFields = append(sortbyFields, param.sortBy+"(missing_values:last):"+param.orderBy.String())
-
-		if i == 2 {
-			// Typesense supports up to 3 sorting parameters
-			// https://typesense.org/docs/0.25.0/api/search.html#ranking-and-sorting-parameters
-			break
-		}
 	}
 
 	sortby := strings.Join(sortbyFields, ",")
@@ -441,6 +589,9 @@ func (t *typesenseTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task,
 		"project_id: [" + strings.Join(projectIDStrings, ", ") + "]",
 		"(" + filter + ")",
 	}
+	if !hasArchivedFilter(opts.parsedFilters) {
+		filterBy = append(filterBy, "is_archived:false")
+	}
 
 	////////////////
 	// Actual search
@@ -451,7 +602,7 @@ func (t *typesenseTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task,
 
 	params := &api.SearchCollectionParams{
 		Q:                opts.search,
-		QueryBy:          "title, identifier, description, comments.comment",
+		QueryBy:          "title, identifier, description, comments.comment, attachment_contents",
 		Page:             pointer.Int(opts.page),
 		ExhaustiveSearch: pointer.True(),
 		FilterBy:         pointer.String(strings.Join(filterBy, " && ")),
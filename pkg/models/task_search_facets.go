@@ -0,0 +1,188 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TaskSearchFacet is the number of matching tasks with one label, in one project or assigned to one user
+type TaskSearchFacet struct {
+	// The id of the label, project or user.
+	ID int64 `json:"id"`
+	// The title of the label or project or the display name of the user.
+	Title string `json:"title"`
+	// The value to use in a filter query to only get the tasks of this facet, for example `labels in 1` or `assignees in user1`.
+	Value string `json:"value"`
+	// The number of matching tasks in this facet.
+	Count int64 `json:"count"`
+}
+
+// TaskSearchFacets holds the facets of all tasks matching a search, not only the ones on the current page
+type TaskSearchFacets struct {
+	Labels    []*TaskSearchFacet `json:"labels"`
+	Projects  []*TaskSearchFacet `json:"projects"`
+	Assignees []*TaskSearchFacet `json:"assignees"`
+}
+
+// TaskSearchResult is one page of tasks matching a search together with the total number of matching tasks and their facets
+type TaskSearchResult struct {
+	// The tasks on the requested page.
+	Tasks []*Task `json:"tasks"`
+	// The number of tasks matching the search on all pages.
+	TotalCount int64 `json:"total_count"`
+	// The requested page.
+	Page int `json:"page"`
+	// The number of tasks per page.
+	PerPage int `json:"per_page"`
+	// The number of pages with matching tasks.
+	TotalPages int `json:"total_pages"`
+	// How many of the matching tasks have which label, project and assignee.
+	Facets *TaskSearchFacets `json:"facets"`
+}
+
+type taskSearchFacetCount struct {
+	ID    int64 `xorm:"'id'"`
+	Count int64 `xorm:"'count'"`
+}
+
+// Search returns one page of tasks of the collection together with the total number of matching tasks and facets
+// for them. Facets are always calculated by the database, even if Typesense is enabled.
+func (tf *TaskCollection) Search(s *xorm.Session, a web.Auth, search string, page int, perPage int) (result *TaskSearchResult, err error) {
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := getLimitFromPageIndex(page, perPage)
+
+	facets := &TaskSearchFacets{
+		Labels:    []*TaskSearchFacet{},
+		Projects:  []*TaskSearchFacet{},
+		Assignees: []*TaskSearchFacet{},
+	}
+	tasks, _, totalCount, err := tf.readAll(s, a, search, page, limit, facets)
+	if err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		tasks = []*Task{}
+	}
+
+	return &TaskSearchResult{
+		Tasks:      tasks,
+		TotalCount: totalCount,
+		Page:       page,
+		PerPage:    limit,
+		TotalPages: int(math.Ceil(float64(totalCount) / float64(limit))),
+		Facets:     facets,
+	}, nil
+}
+
+func (d *dbTaskSearcher) countFacet(table, column string, taskCond builder.Cond) (counts []*taskSearchFacetCount, err error) {
+	counts = []*taskSearchFacetCount{}
+	err = d.s.
+		Table(table).
+		Select(column + " AS id, COUNT(*) AS count").
+		Where(taskCond).
+		GroupBy(column).
+		Find(&counts)
+	return
+}
+
+// fillFacets counts the labels, projects and assignees of all tasks matching cond
+func (d *dbTaskSearcher) fillFacets(facets *TaskSearchFacets, cond builder.Cond) error {
+	matchingTasks := builder.Select("id").From("tasks").Where(cond)
+
+	labelCounts, err := d.countFacet("label_tasks", "label_id", builder.In("task_id", matchingTasks))
+	if err != nil {
+		return err
+	}
+	projectCounts, err := d.countFacet("tasks", "project_id", cond)
+	if err != nil {
+		return err
+	}
+	assigneeCounts, err := d.countFacet("task_assignees", "user_id", builder.In("task_id", matchingTasks))
+	if err != nil {
+		return err
+	}
+
+	labels := make(map[int64]*Label)
+	if len(labelCounts) > 0 {
+		err = d.s.In("id", facetIDs(labelCounts)).Find(&labels)
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range labelCounts {
+		if l, has := labels[c.ID]; has {
+			facets.Labels = append(facets.Labels, &TaskSearchFacet{ID: l.ID, Title: l.Title, Value: strconv.FormatInt(l.ID, 10), Count: c.Count})
+		}
+	}
+
+	projects := make(map[int64]*Project)
+	if len(projectCounts) > 0 {
+		err = d.s.In("id", facetIDs(projectCounts)).Find(&projects)
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range projectCounts {
+		if p, has := projects[c.ID]; has {
+			facets.Projects = append(facets.Projects, &TaskSearchFacet{ID: p.ID, Title: p.Title, Value: strconv.FormatInt(p.ID, 10), Count: c.Count})
+		}
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(d.s, facetIDs(assigneeCounts))
+	if err != nil {
+		return err
+	}
+	for _, c := range assigneeCounts {
+		if u, has := users[c.ID]; has {
+			facets.Assignees = append(facets.Assignees, &TaskSearchFacet{ID: u.ID, Title: u.GetName(), Value: u.Username, Count: c.Count})
+		}
+	}
+
+	sortTaskSearchFacets(facets.Labels)
+	sortTaskSearchFacets(facets.Projects)
+	sortTaskSearchFacets(facets.Assignees)
+
+	return nil
+}
+
+func facetIDs(counts []*taskSearchFacetCount) []int64 {
+	ids := make([]int64, 0, len(counts))
+	for _, c := range counts {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+// sortTaskSearchFacets sorts facets with the most tasks first
+func sortTaskSearchFacets(facets []*TaskSearchFacet) {
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].ID < facets[j].ID
+	})
+}
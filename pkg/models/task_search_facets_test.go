@@ -0,0 +1,119 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findTaskSearchFacet(facets []*TaskSearchFacet, id int64) *TaskSearchFacet {
+	for _, f := range facets {
+		if f.ID == id {
+			return f
+		}
+	}
+	return nil
+}
+
+func TestTaskCollection_Search(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("total count and pages", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1}
+		all, err := tc.Search(s, u, "", 1, 50)
+		require.NoError(t, err)
+
+		tc = &TaskCollection{ProjectID: 1}
+		result, err := tc.Search(s, u, "", 2, 5)
+		require.NoError(t, err)
+		assert.Equal(t, all.TotalCount, result.TotalCount)
+		assert.Equal(t, int64(len(all.Tasks)), result.TotalCount)
+		assert.Len(t, result.Tasks, 5)
+		assert.Equal(t, 2, result.Page)
+		assert.Equal(t, 5, result.PerPage)
+		assert.Equal(t, int((result.TotalCount+4)/5), result.TotalPages)
+	})
+	t.Run("facets", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, err := tc.Search(s, u, "", 1, 1)
+		require.NoError(t, err)
+		assert.Len(t, result.Tasks, 1)
+
+		project := findTaskSearchFacet(result.Facets.Projects, 1)
+		require.NotNil(t, project)
+		assert.Equal(t, result.TotalCount, project.Count)
+		assert.Len(t, result.Facets.Projects, 1)
+
+		labelCount, err := s.
+			Where("task_id IN (SELECT id FROM tasks WHERE project_id = 1)").
+			And("label_id = 4").
+			Count(&LabelTask{})
+		require.NoError(t, err)
+		label := findTaskSearchFacet(result.Facets.Labels, 4)
+		require.NotNil(t, label)
+		assert.Equal(t, labelCount, label.Count)
+		assert.Equal(t, "4", label.Value)
+
+		assigneeCount, err := s.
+			Where("task_id IN (SELECT id FROM tasks WHERE project_id = 1)").
+			And("user_id = 1").
+			Count(&TaskAssginee{})
+		require.NoError(t, err)
+		assignee := findTaskSearchFacet(result.Facets.Assignees, 1)
+		require.NotNil(t, assignee)
+		assert.Equal(t, assigneeCount, assignee.Count)
+		assert.Equal(t, "user1", assignee.Value)
+	})
+	t.Run("facets only count matching tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, err := tc.Search(s, u, "high prio", 1, 50)
+		require.NoError(t, err)
+		require.Len(t, result.Tasks, 1)
+		assert.Equal(t, int64(1), result.TotalCount)
+		assert.Equal(t, 1, result.TotalPages)
+		require.Len(t, result.Facets.Projects, 1)
+		assert.Equal(t, int64(1), result.Facets.Projects[0].Count)
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 5}
+		_, err := tc.Search(s, u, "", 1, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrUserDoesNotHaveAccessToProject(err))
+	})
+}
@@ -0,0 +1,143 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"sync"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/utils"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+const (
+	// pgTrgmWordSimilarityThreshold is the minimum word similarity of a search string and a task title
+	// for the task to be considered a match when searching with pg_trgm.
+	pgTrgmWordSimilarityThreshold = 0.4
+	// fuzzySearchMinWordLength is the minimum length of a search word for it to be matched with typos.
+	// Shorter words need to match the beginning of a word in the title exactly.
+	fuzzySearchMinWordLength = 4
+	// fuzzySearchMaxCandidates limits the number of tasks which are compared in the normalized title fallback.
+	fuzzySearchMaxCandidates = 10000
+)
+
+var (
+	pgTrgmOnce      sync.Once
+	pgTrgmAvailable bool
+)
+
+// isPgTrgmAvailable checks once whether the database is Postgres with the pg_trgm extension installed.
+func isPgTrgmAvailable(s *xorm.Session) bool {
+	if db.Type() != schemas.POSTGRES {
+		return false
+	}
+
+	pgTrgmOnce.Do(func() {
+		var count int64
+		_, err := s.SQL("SELECT count(*) FROM pg_extension WHERE extname = 'pg_trgm'").Get(&count)
+		if err != nil {
+			log.Errorf("Could not check if the pg_trgm extension is installed: %s", err)
+			return
+		}
+		pgTrgmAvailable = count > 0
+	})
+
+	return pgTrgmAvailable
+}
+
+// findTaskIDsByNormalizedTitle returns the ids of all tasks matching cond whose normalized title matches
+// every word of the search string, allowing a small number of typos per word.
+func (d *dbTaskSearcher) findTaskIDsByNormalizedTitle(search string, cond builder.Cond) (ids []int64, err error) {
+	searchWords := strings.Fields(utils.NormalizeSearchText(search))
+	if len(searchWords) == 0 {
+		return nil, nil
+	}
+
+	candidates := []*Task{}
+	err = d.s.
+		Where(cond).
+		Cols("id", "title", "normalized_title").
+		OrderBy("id desc").
+		Limit(fuzzySearchMaxCandidates).
+		Find(&candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range candidates {
+		normalized := t.NormalizedTitle
+		if normalized == "" {
+			normalized = utils.NormalizeSearchText(t.Title)
+		}
+		if normalizedTitleMatches(strings.Fields(normalized), searchWords) {
+			ids = append(ids, t.ID)
+		}
+	}
+
+	return
+}
+
+func normalizedTitleMatches(titleWords, searchWords []string) bool {
+	for _, searchWord := range searchWords {
+		var found bool
+		for _, titleWord := range titleWords {
+			if fuzzyWordMatches(titleWord, searchWord) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fuzzyWordMatches checks if a search word matches a title word or the beginning of it,
+// allowing one typo for words with at least four and two typos for words with at least eight characters.
+func fuzzyWordMatches(titleWord, searchWord string) bool {
+	if strings.HasPrefix(titleWord, searchWord) {
+		return true
+	}
+
+	searchRunes := []rune(searchWord)
+	if len(searchRunes) < fuzzySearchMinWordLength {
+		return false
+	}
+
+	maxDistance := 1
+	if len(searchRunes) >= 8 {
+		maxDistance = 2
+	}
+
+	if utils.EditDistance(titleWord, searchWord) <= maxDistance {
+		return true
+	}
+
+	titleRunes := []rune(titleWord)
+	if len(titleRunes) > len(searchRunes) {
+		return utils.EditDistance(string(titleRunes[:len(searchRunes)]), searchWord) <= maxDistance
+	}
+
+	return false
+}
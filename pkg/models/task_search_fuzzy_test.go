@@ -0,0 +1,107 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyWordMatches(t *testing.T) {
+	assert.True(t, fuzzyWordMatches("task", "task"))
+	assert.True(t, fuzzyWordMatches("task", "ta"))
+	assert.True(t, fuzzyWordMatches("task", "tsak"))
+	assert.True(t, fuzzyWordMatches("reminders", "remniders"))
+	assert.True(t, fuzzyWordMatches("reminders", "remnider"))
+	assert.True(t, fuzzyWordMatches("assignees", "asigneees"))
+	assert.False(t, fuzzyWordMatches("low", "lwo"))
+	assert.False(t, fuzzyWordMatches("high", "hxyz"))
+}
+
+func TestTaskCollection_ReadAll_FuzzySearch(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("typo in title", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, _, total, err := tc.ReadAll(s, u, "hihg prio", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(3), tasks[0].ID)
+		assert.Equal(t, int64(1), total)
+	})
+	t.Run("diacritics and punctuation", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, _, _, err := tc.ReadAll(s, u, "lów-príó", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(4), tasks[0].ID)
+	})
+	t.Run("exact matches take precedence", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, _, _, err := tc.ReadAll(s, u, "high prio", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(3), tasks[0].ID)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.ServiceEnableFuzzySearch.Set(false)
+		defer config.ServiceEnableFuzzySearch.Set(true)
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, _, _, err := tc.ReadAll(s, u, "hihg prio", 0, 50)
+		require.NoError(t, err)
+		assert.Empty(t, result.([]*Task))
+	})
+	t.Run("stores the normalized title", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "Crème Brûlée!", ProjectID: 1}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":               task.ID,
+			"normalized_title": "creme brulee",
+		}, false)
+	})
+}
@@ -0,0 +1,175 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/microcosm-cc/bluemonday"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TaskSearchMatchSource defines in which part of a task a search term was found
+type TaskSearchMatchSource string
+
+const (
+	// TaskSearchMatchSourceTitle is a match in the title of the task.
+	TaskSearchMatchSourceTitle TaskSearchMatchSource = "title"
+	// TaskSearchMatchSourceDescription is a match in the description of the task.
+	TaskSearchMatchSourceDescription TaskSearchMatchSource = "description"
+	// TaskSearchMatchSourceComment is a match in one of the comments of the task.
+	TaskSearchMatchSourceComment TaskSearchMatchSource = "comment"
+	// TaskSearchMatchSourceAttachment is a match in the text of one of the attachments of the task.
+	TaskSearchMatchSourceAttachment TaskSearchMatchSource = "attachment"
+)
+
+// The number of characters shown around the search term in a highlight
+const searchMatchContextLength = 40
+
+// TaskSearchMatch describes where a search term was found in a task
+type TaskSearchMatch struct {
+	// Where the search term was found. Can be `title`, `description`, `comment` or `attachment`.
+	Source TaskSearchMatchSource `json:"source"`
+	// The id of the comment or attachment the search term was found in. Not set for the title and description.
+	ID int64 `json:"id,omitempty"`
+	// An excerpt of the text around the search term. The html is escaped, the search term itself is wrapped in `<mark>` tags.
+	Highlight string `json:"highlight"`
+}
+
+// highlightSearchMatch returns an excerpt of the text around the first occurrence of the search term with the
+// search term wrapped in mark tags. If the text does not contain the search term, it returns an empty string.
+func highlightSearchMatch(text, search string) string {
+	runes := []rune(text)
+	searchRunes := []rune(strings.TrimSpace(search))
+	if len(searchRunes) == 0 || len(searchRunes) > len(runes) {
+		return ""
+	}
+
+	index := -1
+	for i := 0; i+len(searchRunes) <= len(runes); i++ {
+		matches := true
+		for j, r := range searchRunes {
+			if unicode.ToLower(runes[i+j]) != unicode.ToLower(r) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return ""
+	}
+
+	start := index - searchMatchContextLength
+	prefix := "…"
+	if start <= 0 {
+		start = 0
+		prefix = ""
+	}
+	end := index + len(searchRunes) + searchMatchContextLength
+	suffix := "…"
+	if end >= len(runes) {
+		end = len(runes)
+		suffix = ""
+	}
+
+	return prefix +
+		html.EscapeString(string(runes[start:index])) +
+		"<mark>" + html.EscapeString(string(runes[index:index+len(searchRunes)])) + "</mark>" +
+		html.EscapeString(string(runes[index+len(searchRunes):end])) +
+		suffix
+}
+
+// htmlToSearchText converts html to plain text to look for search terms in it
+func htmlToSearchText(content string) string {
+	text := bluemonday.StrictPolicy().AddSpaceWhenStrippingTag(true).Sanitize(content)
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+// addSearchMatchesToTasks adds all places where the search term was found to the tasks
+func addSearchMatchesToTasks(s *xorm.Session, taskMap map[int64]*Task, search string) (err error) {
+	if len(taskMap) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]int64, 0, len(taskMap))
+	for id, t := range taskMap {
+		taskIDs = append(taskIDs, id)
+
+		if h := highlightSearchMatch(t.Title, search); h != "" {
+			t.SearchMatches = append(t.SearchMatches, &TaskSearchMatch{Source: TaskSearchMatchSourceTitle, Highlight: h})
+		}
+		if h := highlightSearchMatch(htmlToSearchText(t.Description), search); h != "" {
+			t.SearchMatches = append(t.SearchMatches, &TaskSearchMatch{Source: TaskSearchMatchSourceDescription, Highlight: h})
+		}
+	}
+
+	comments := []*TaskComment{}
+	err = s.
+		Where(builder.And(
+			builder.In("task_id", taskIDs),
+			db.ILIKE("comment", search),
+		)).
+		OrderBy("id asc").
+		Find(&comments)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range comments {
+		if h := highlightSearchMatch(htmlToSearchText(c.Comment), search); h != "" {
+			taskMap[c.TaskID].SearchMatches = append(taskMap[c.TaskID].SearchMatches, &TaskSearchMatch{
+				Source:    TaskSearchMatchSourceComment,
+				ID:        c.ID,
+				Highlight: h,
+			})
+		}
+	}
+
+	attachments := []*TaskAttachment{}
+	err = s.
+		Where(builder.And(
+			builder.In("task_id", taskIDs),
+			db.ILIKE("text_content", search),
+		)).
+		Cols("id", "task_id", "text_content").
+		OrderBy("id asc").
+		Find(&attachments)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range attachments {
+		if h := highlightSearchMatch(a.TextContent, search); h != "" {
+			taskMap[a.TaskID].SearchMatches = append(taskMap[a.TaskID].SearchMatches, &TaskSearchMatch{
+				Source:    TaskSearchMatchSourceAttachment,
+				ID:        a.ID,
+				Highlight: h,
+			})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlightSearchMatch(t *testing.T) {
+	assert.Equal(t, "Buy <mark>Milk</mark> &amp; eggs", highlightSearchMatch("Buy Milk & eggs", "milk"))
+	assert.Equal(t, "<mark>Äpfel</mark> kaufen", highlightSearchMatch("Äpfel kaufen", "äpfel"))
+	assert.Empty(t, highlightSearchMatch("Buy Milk", "bread"))
+	assert.Empty(t, highlightSearchMatch("Buy Milk", " "))
+
+	long := strings.Repeat("a", 100) + "needle" + strings.Repeat("b", 100)
+	assert.Equal(t,
+		"…"+strings.Repeat("a", searchMatchContextLength)+"<mark>needle</mark>"+strings.Repeat("b", searchMatchContextLength)+"…",
+		highlightSearchMatch(long, "needle"),
+	)
+}
+
+func TestTaskCollection_ReadAll_SearchMatches(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("comment", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, _, _, err := tc.ReadAll(s, u, "dolor sit", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(1), tasks[0].ID)
+		require.Len(t, tasks[0].SearchMatches, 1)
+		assert.Equal(t, TaskSearchMatchSourceComment, tasks[0].SearchMatches[0].Source)
+		assert.Equal(t, int64(1), tasks[0].SearchMatches[0].ID)
+		assert.Equal(t, "Lorem Ipsum <mark>Dolor Sit</mark> Amet", tasks[0].SearchMatches[0].Highlight)
+	})
+	t.Run("attachment", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		content := "The quarterly report lists all invoices."
+		ta := &TaskAttachment{TaskID: 2}
+		err := ta.NewAttachment(s, io.NopCloser(strings.NewReader(content)), "report.txt", uint64(len(content)), u)
+		require.NoError(t, err)
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, _, _, err := tc.ReadAll(s, u, "quarterly report", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(2), tasks[0].ID)
+		require.Len(t, tasks[0].SearchMatches, 1)
+		assert.Equal(t, TaskSearchMatchSourceAttachment, tasks[0].SearchMatches[0].Source)
+		assert.Equal(t, ta.ID, tasks[0].SearchMatches[0].ID)
+		assert.Equal(t, "The <mark>quarterly report</mark> lists all invoices.", tasks[0].SearchMatches[0].Highlight)
+	})
+	t.Run("title", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1}
+		result, _, _, err := tc.ReadAll(s, u, "task #1", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.NotEmpty(t, tasks)
+		assert.Equal(t, TaskSearchMatchSourceTitle, tasks[0].SearchMatches[0].Source)
+	})
+}
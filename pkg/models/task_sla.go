@@ -0,0 +1,329 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// SLARule defines how old an undone task in a project may get before the rule is breached
+type SLARule struct {
+	// A short description of the rule, used in notifications.
+	Title string `json:"title"`
+	// The bucket this rule applies to. If set, the rule only applies to tasks in that bucket and
+	// their age is the time since they were moved into it. If 0, the rule applies to all tasks of the project
+	// and their age is the time since they were created.
+	BucketID int64 `json:"bucket_id"`
+	// The maximum age of a task in seconds.
+	MaxAge int64 `json:"max_age"`
+}
+
+// TaskSLAStatus holds the age of a task and whether it breaches any sla rules of its project
+type TaskSLAStatus struct {
+	// The number of seconds since the task was created.
+	Age int64 `json:"age"`
+	// The number of seconds since the task was moved into its current bucket.
+	BucketAge int64 `json:"bucket_age"`
+	// True if the task breaches at least one sla rule.
+	Breached bool `json:"breached"`
+	// All sla rules the task currently breaches.
+	BreachedRules []*SLARule `json:"breached_rules"`
+}
+
+// TaskSLABreach records that a task breached an sla rule, this is used to only notify about every breach once.
+type TaskSLABreach struct {
+	ID        int64 `xorm:"bigint autoincr not null unique pk"`
+	TaskID    int64 `xorm:"bigint not null INDEX"`
+	ProjectID int64 `xorm:"bigint not null INDEX"`
+	BucketID  int64 `xorm:"bigint not null default 0"`
+	MaxAge    int64 `xorm:"bigint not null"`
+	// The time from which the age of the task was measured when it breached the rule.
+	// When a task is moved out of a bucket and back into it later, this changes and the task can breach the rule again.
+	Since   time.Time `xorm:"not null"`
+	Created time.Time `xorm:"created not null"`
+}
+
+// TableName returns the table name for sla breaches
+func (*TaskSLABreach) TableName() string {
+	return "task_sla_breaches"
+}
+
+// validateSLARules checks all sla rules of a project are valid
+func (p *Project) validateSLARules(s *xorm.Session) error {
+	for _, rule := range p.SLARules {
+		if rule.MaxAge <= 0 {
+			return &ErrInvalidSLARule{ProjectID: p.ID, BucketID: rule.BucketID, MaxAge: rule.MaxAge}
+		}
+
+		if rule.BucketID == 0 {
+			continue
+		}
+
+		bucket, err := getBucketByID(s, rule.BucketID)
+		if err != nil && !IsErrBucketDoesNotExist(err) {
+			return err
+		}
+		if err != nil || bucket.ProjectID != p.ID {
+			return &ErrInvalidSLARule{ProjectID: p.ID, BucketID: rule.BucketID, MaxAge: rule.MaxAge}
+		}
+	}
+
+	return nil
+}
+
+// slaRuleSince returns the time from which the age of a task is measured for a rule.
+// The second return value is false if the rule does not apply to the task.
+func slaRuleSince(rule *SLARule, t *Task, bucketEntered time.Time) (since time.Time, applies bool) {
+	if rule.BucketID == 0 {
+		return t.Created, true
+	}
+
+	return bucketEntered, t.BucketID == rule.BucketID
+}
+
+// getSLAStatus returns the sla status of a task according to the rules of its project.
+func getSLAStatus(rules []*SLARule, t *Task, bucketEntered time.Time, now time.Time) *TaskSLAStatus {
+	status := &TaskSLAStatus{
+		Age:           int64(now.Sub(t.Created).Seconds()),
+		BucketAge:     int64(now.Sub(bucketEntered).Seconds()),
+		BreachedRules: []*SLARule{},
+	}
+
+	if t.Done {
+		return status
+	}
+
+	for _, rule := range rules {
+		since, applies := slaRuleSince(rule, t, bucketEntered)
+		if applies && now.Sub(since) >= time.Duration(rule.MaxAge)*time.Second {
+			status.BreachedRules = append(status.BreachedRules, rule)
+		}
+	}
+	status.Breached = len(status.BreachedRules) > 0
+
+	return status
+}
+
+// getBucketEnteredTimes returns when each task was moved into its current bucket.
+// Tasks without a recorded transition into their bucket are treated as if they were moved there when they were created.
+func getBucketEnteredTimes(s *xorm.Session, taskMap map[int64]*Task) (entered map[int64]time.Time, err error) {
+	entered = make(map[int64]time.Time, len(taskMap))
+	taskIDs := make([]int64, 0, len(taskMap))
+	for _, t := range taskMap {
+		entered[t.ID] = t.Created
+		taskIDs = append(taskIDs, t.ID)
+	}
+
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	transitions := []*TaskBucketTransition{}
+	err = s.
+		In("task_id", taskIDs).
+		Where("to_bucket_id != 0").
+		OrderBy("created asc, id asc").
+		Find(&transitions)
+	if err != nil {
+		return
+	}
+
+	for _, transition := range transitions {
+		t := taskMap[transition.TaskID]
+		if t != nil && t.BucketID == transition.ToBucketID {
+			entered[t.ID] = transition.Created
+		}
+	}
+
+	return
+}
+
+// addSLAStatusToTasks adds the sla status to all tasks in projects with sla rules.
+func addSLAStatusToTasks(s *xorm.Session, taskMap map[int64]*Task, projects map[int64]*Project, now time.Time) (err error) {
+	tasksWithRules := make(map[int64]*Task)
+	for _, t := range taskMap {
+		p, has := projects[t.ProjectID]
+		if has && len(p.SLARules) > 0 {
+			tasksWithRules[t.ID] = t
+		}
+	}
+
+	if len(tasksWithRules) == 0 {
+		return nil
+	}
+
+	entered, err := getBucketEnteredTimes(s, tasksWithRules)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasksWithRules {
+		t.SLA = getSLAStatus(projects[t.ProjectID].SLARules, t, entered[t.ID], now)
+	}
+
+	return nil
+}
+
+func slaBreachKey(taskID, bucketID, maxAge int64, since time.Time) string {
+	return fmt.Sprintf("%d-%d-%d-%d", taskID, bucketID, maxAge, since.Unix())
+}
+
+// checkSLABreaches looks for tasks which newly breach an sla rule of their project, records them and dispatches an event
+// for each of them.
+func checkSLABreaches(s *xorm.Session, now time.Time) (breached int, err error) {
+	projects := []*Project{}
+	err = s.
+		Where("is_archived = ? AND sla_rules IS NOT NULL", false).
+		Find(&projects)
+	if err != nil {
+		return 0, err
+	}
+
+	projectIDs := []int64{}
+	projectMap := make(map[int64]*Project, len(projects))
+	for _, p := range projects {
+		if len(p.SLARules) == 0 {
+			continue
+		}
+		projectIDs = append(projectIDs, p.ID)
+		projectMap[p.ID] = p
+	}
+
+	if len(projectIDs) == 0 {
+		return 0, nil
+	}
+
+	taskMap := make(map[int64]*Task)
+	err = s.
+		Where(builder.And(
+			builder.In("project_id", projectIDs),
+			builder.Eq{"done": false},
+			builder.Eq{"is_archived": false},
+		)).
+		Find(&taskMap)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(taskMap) == 0 {
+		return 0, nil
+	}
+
+	entered, err := getBucketEnteredTimes(s, taskMap)
+	if err != nil {
+		return 0, err
+	}
+
+	taskIDs := make([]int64, 0, len(taskMap))
+	for id := range taskMap {
+		taskIDs = append(taskIDs, id)
+	}
+	sort.Slice(taskIDs, func(i, j int) bool {
+		return taskIDs[i] < taskIDs[j]
+	})
+
+	existing := []*TaskSLABreach{}
+	err = s.In("task_id", taskIDs).Find(&existing)
+	if err != nil {
+		return 0, err
+	}
+	recorded := make(map[string]bool, len(existing))
+	for _, b := range existing {
+		recorded[slaBreachKey(b.TaskID, b.BucketID, b.MaxAge, b.Since)] = true
+	}
+
+	for _, id := range taskIDs {
+		t := taskMap[id]
+		project := projectMap[t.ProjectID]
+		status := getSLAStatus(project.SLARules, t, entered[t.ID], now)
+
+		for _, rule := range status.BreachedRules {
+			since, _ := slaRuleSince(rule, t, entered[t.ID])
+			key := slaBreachKey(t.ID, rule.BucketID, rule.MaxAge, since)
+			if recorded[key] {
+				continue
+			}
+
+			_, err = s.Insert(&TaskSLABreach{
+				TaskID:    t.ID,
+				ProjectID: t.ProjectID,
+				BucketID:  rule.BucketID,
+				MaxAge:    rule.MaxAge,
+				Since:     since,
+			})
+			if err != nil {
+				return breached, err
+			}
+			recorded[key] = true
+
+			t.SLA = status
+			err = events.DispatchOnCommit(s, &TaskSLABreachedEvent{
+				Task:    t,
+				Project: project,
+				Rule:    rule,
+				Age:     int64(now.Sub(since).Seconds()),
+			})
+			if err != nil {
+				return breached, err
+			}
+
+			breached++
+		}
+	}
+
+	return breached, nil
+}
+
+// RegisterSLACheckCron registers a function which periodically checks all tasks in projects with sla rules
+// for new breaches.
+func RegisterSLACheckCron() {
+	const logPrefix = "[SLA Check Cron] "
+
+	err := cron.Schedule("*/10 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		breached, err := checkSLABreaches(s, time.Now())
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not check sla rules: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit sla breaches: %s", err)
+			return
+		}
+
+		if breached > 0 {
+			log.Debugf(logPrefix+"Found %d new sla breaches", breached)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register sla check cron: %s", err)
+	}
+}
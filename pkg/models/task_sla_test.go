@@ -0,0 +1,175 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestCheckSLABreaches(t *testing.T) {
+	setRules := func(t *testing.T, s *xorm.Session, rules ...*SLARule) {
+		_, err := s.ID(1).Cols("sla_rules").Update(&Project{SLARules: rules})
+		require.NoError(t, err)
+	}
+
+	t.Run("records new breaches once", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRules(t, s, &SLARule{Title: "In review", BucketID: 2, MaxAge: 3 * 86400})
+
+		now := time.Date(2018, 12, 8, 0, 0, 0, 0, time.UTC)
+		breached, err := checkSLABreaches(s, now)
+		require.NoError(t, err)
+		assert.Positive(t, breached)
+		events.AssertDispatched(t, &TaskSLABreachedEvent{})
+
+		breached, err = checkSLABreaches(s, now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, breached)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_sla_breaches", map[string]interface{}{
+			"task_id":   4,
+			"bucket_id": 2,
+			"max_age":   3 * 86400,
+		}, false)
+		// Task 1 is in another bucket
+		db.AssertMissing(t, "task_sla_breaches", map[string]interface{}{
+			"task_id":   1,
+			"bucket_id": 2,
+		})
+	})
+	t.Run("not old enough", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRules(t, s, &SLARule{BucketID: 2, MaxAge: 3 * 86400})
+
+		breached, err := checkSLABreaches(s, time.Date(2018, 12, 2, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 0, breached)
+	})
+	t.Run("already recorded breach", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		setRules(t, s, &SLARule{MaxAge: 86400})
+
+		_, err := checkSLABreaches(s, time.Date(2018, 12, 8, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+
+		count, err := s.Where("task_id = ?", 3).Count(&TaskSLABreach{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestGetSLAStatus(t *testing.T) {
+	created := time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC)
+	entered := time.Date(2018, 12, 3, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2018, 12, 5, 0, 0, 0, 0, time.UTC)
+	rules := []*SLARule{
+		{BucketID: 2, MaxAge: 3 * 86400},
+		{MaxAge: 3 * 86400},
+	}
+
+	t.Run("breached", func(t *testing.T) {
+		status := getSLAStatus(rules, &Task{Created: created, BucketID: 2}, entered, now)
+		assert.Equal(t, int64(4*86400), status.Age)
+		assert.Equal(t, int64(2*86400), status.BucketAge)
+		assert.True(t, status.Breached)
+		require.Len(t, status.BreachedRules, 1)
+		assert.Equal(t, int64(0), status.BreachedRules[0].BucketID)
+	})
+	t.Run("done", func(t *testing.T) {
+		status := getSLAStatus(rules, &Task{Created: created, BucketID: 2, Done: true}, entered, now)
+		assert.False(t, status.Breached)
+		assert.Empty(t, status.BreachedRules)
+	})
+}
+
+func TestTask_ReadOne_SLA(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err := s.ID(1).Cols("sla_rules").Update(&Project{SLARules: []*SLARule{{MaxAge: 86400}}})
+	require.NoError(t, err)
+
+	task := &Task{ID: 1}
+	err = task.ReadOne(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	require.NotNil(t, task.SLA)
+	assert.True(t, task.SLA.Breached)
+
+	task = &Task{ID: 13}
+	err = task.ReadOne(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.Nil(t, task.SLA)
+}
+
+func TestProject_validateSLARules(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("invalid max age", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &Project{ID: 1, Title: "Test1", SLARules: []*SLARule{{MaxAge: 0}}}
+		err := p.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidSLARule(err))
+	})
+	t.Run("bucket of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &Project{ID: 1, Title: "Test1", SLARules: []*SLARule{{BucketID: 4, MaxAge: 86400}}}
+		err := p.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidSLARule(err))
+	})
+	t.Run("valid", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		p := &Project{ID: 1, Title: "Test1", SLARules: []*SLARule{{Title: "In review", BucketID: 2, MaxAge: 86400}}}
+		err := p.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		p, err = GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		require.Len(t, p.SLARules, 1)
+		assert.Equal(t, int64(2), p.SLARules[0].BucketID)
+	})
+}
@@ -0,0 +1,92 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskSource defines how a task was created
+type TaskSource string
+
+const (
+	// TaskSourceAPIToken is used for tasks created with an api token.
+	TaskSourceAPIToken TaskSource = "api_token"
+	// TaskSourceLinkShare is used for tasks created through a link share.
+	TaskSourceLinkShare TaskSource = "link_share"
+	// TaskSourceCalDAV is used for tasks created with a CalDAV client.
+	TaskSourceCalDAV TaskSource = "caldav"
+	// TaskSourceEmail is used for tasks created from an email.
+	TaskSourceEmail TaskSource = "email"
+	// TaskSourceSlack is used for tasks created with the Slack slash command.
+	TaskSourceSlack TaskSource = "slack"
+	// TaskSourceInboundWebhook is used for tasks created with an inbound webhook.
+	TaskSourceInboundWebhook TaskSource = "inbound_webhook"
+)
+
+// getTaskSourceFromAuth returns the source of a task created with the auth. Returns an empty source for
+// tasks created by a user directly.
+func getTaskSourceFromAuth(a web.Auth) TaskSource {
+	switch auth := a.(type) {
+	case *LinkSharing:
+		return TaskSourceLinkShare
+	case *user.User:
+		if auth.AuthenticatedWithAPIToken {
+			return TaskSourceAPIToken
+		}
+	}
+
+	return ""
+}
+
+// validateSourceDefaultBuckets checks all sources exist and all buckets belong to the project
+func (p *Project) validateSourceDefaultBuckets(s *xorm.Session) error {
+	for source, bucketID := range p.SourceDefaultBuckets {
+		switch source {
+		case TaskSourceAPIToken,
+			TaskSourceLinkShare,
+			TaskSourceCalDAV,
+			TaskSourceEmail,
+			TaskSourceSlack,
+			TaskSourceInboundWebhook:
+			// Valid source
+		default:
+			return ErrInvalidData{Message: "Invalid task source " + string(source) + "."}
+		}
+
+		bucket, err := getBucketByID(s, bucketID)
+		if err != nil {
+			return err
+		}
+		if bucket.ProjectID != p.ID {
+			return ErrBucketDoesNotBelongToProject{BucketID: bucket.ID, ProjectID: p.ID}
+		}
+	}
+
+	return nil
+}
+
+// getDefaultBucketIDForSource returns the default bucket of a project for tasks of the given source
+func getDefaultBucketIDForSource(s *xorm.Session, project *Project, source TaskSource) (bucketID int64, err error) {
+	if bucketID, has := project.SourceDefaultBuckets[source]; has && source != "" {
+		return bucketID, nil
+	}
+
+	return getDefaultBucketID(s, project)
+}
@@ -0,0 +1,159 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// TaskUserPosition holds the position of a task in the list view of a single user.
+// Other than the position of a task, which is the same for everyone in the project, it only changes
+// the order the user who set it sees.
+type TaskUserPosition struct {
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	// The task this position belongs to.
+	TaskID int64 `xorm:"bigint not null unique(task_user)" json:"task_id" param:"task"`
+	UserID int64 `xorm:"bigint not null unique(task_user) INDEX" json:"-"`
+	// The position of the task in the list view of the current user. Works the same way as the position of tasks.
+	Position float64 `xorm:"double not null" json:"position"`
+
+	// A timestamp when this position was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this position was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name for task user positions
+func (TaskUserPosition) TableName() string {
+	return "task_user_positions"
+}
+
+// getUserPositionOrderColumn returns an sql expression which resolves to the position the user set for a task
+// or the position of the task itself if the user did not set one.
+func getUserPositionOrderColumn(userID int64) string {
+	return fmt.Sprintf("COALESCE((SELECT task_user_positions.position FROM task_user_positions WHERE task_user_positions.task_id = tasks.id AND task_user_positions.user_id = %d), tasks.position)", userID)
+}
+
+// addUserPositionsToTasks sets the positions the user has set for the tasks
+func addUserPositionsToTasks(s *xorm.Session, taskIDs []int64, taskMap map[int64]*Task, a web.Auth) (err error) {
+	if _, isShare := a.(*LinkSharing); isShare || a == nil {
+		return nil
+	}
+
+	positions := []*TaskUserPosition{}
+	err = s.
+		Where("user_id = ?", a.GetID()).
+		In("task_id", taskIDs).
+		Find(&positions)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range positions {
+		if task, has := taskMap[p.TaskID]; has {
+			task.UserPosition = p.Position
+		}
+	}
+
+	return nil
+}
+
+// CanUpdate checks if the user can set their own position of a task
+func (tp *TaskUserPosition) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, isShare := a.(*LinkSharing); isShare {
+		return false, nil
+	}
+
+	t := &Task{ID: tp.TaskID}
+	can, _, err := t.CanRead(s, a)
+	return can, err
+}
+
+// CanDelete checks if the user can reset their own position of a task
+func (tp *TaskUserPosition) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return tp.CanUpdate(s, a)
+}
+
+// Update sets the position of a task in the list view of the current user
+// @Summary Set the position of a task for the current user
+// @Description Sets the position of a task in the list view of the current user without changing the order other members of the project see. These positions are used when loading tasks with `user_positions=true`.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param position body models.TaskUserPosition true "The position of the task."
+// @Success 200 {object} models.TaskUserPosition "The position has been saved."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/position/user [post]
+func (tp *TaskUserPosition) Update(s *xorm.Session, a web.Auth) (err error) {
+	tp.UserID = a.GetID()
+
+	existing := &TaskUserPosition{}
+	exists, err := s.
+		Where("task_id = ? AND user_id = ?", tp.TaskID, tp.UserID).
+		Get(existing)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		_, err = s.Insert(tp)
+		return err
+	}
+
+	tp.ID = existing.ID
+	_, err = s.
+		ID(tp.ID).
+		Cols("position").
+		Update(tp)
+	if err != nil {
+		return err
+	}
+
+	tp.Created = existing.Created
+	return nil
+}
+
+// Delete resets the position of a task for the current user to the position everyone else sees
+// @Summary Reset the position of a task for the current user
+// @Description Removes the position the current user has set for a task so that the task is sorted by its project-wide position again.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} models.Message "The position has been reset."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/position/user [delete]
+func (tp *TaskUserPosition) Delete(s *xorm.Session, a web.Auth) (err error) {
+	_, err = s.
+		Where("task_id = ? AND user_id = ?", tp.TaskID, a.GetID()).
+		Delete(&TaskUserPosition{})
+	return err
+}
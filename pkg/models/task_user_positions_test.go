@@ -0,0 +1,161 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskUserPosition_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("new position", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskUserPosition{TaskID: 1, Position: 42}
+		err := tp.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_user_positions", map[string]interface{}{
+			"task_id":  1,
+			"user_id":  1,
+			"position": 42,
+		}, false)
+	})
+	t.Run("existing position", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskUserPosition{TaskID: 40, Position: 42}
+		err := tp.Update(s, &user.User{ID: 15})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), tp.ID)
+		db.AssertExists(t, "task_user_positions", map[string]interface{}{
+			"id":       1,
+			"task_id":  40,
+			"user_id":  15,
+			"position": 42,
+		}, false)
+	})
+}
+
+func TestTaskUserPosition_CanUpdate(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("allowed", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&TaskUserPosition{TaskID: 1}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("read only access is enough", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&TaskUserPosition{TaskID: 15}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&TaskUserPosition{TaskID: 14}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&TaskUserPosition{TaskID: 1}).CanUpdate(s, &LinkSharing{ID: 1, ProjectID: 1, Right: RightAdmin})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestTaskUserPosition_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	tp := &TaskUserPosition{TaskID: 40}
+	err := tp.Delete(s, &user.User{ID: 15})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "task_user_positions", map[string]interface{}{
+		"task_id": 40,
+		"user_id": 15,
+	})
+}
+
+func TestTaskCollection_ReadAll_UserPositions(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	getTasks := func(t *testing.T, userPositions bool) []*Task {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskUserPosition{TaskID: 12, Position: 0.5}).Update(s, u)
+		require.NoError(t, err)
+
+		tc := &TaskCollection{
+			ProjectID:     1,
+			SortBy:        []string{"position", "id"},
+			OrderBy:       []string{"asc", "asc"},
+			UserPositions: userPositions,
+		}
+		result, _, _, err := tc.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.NotEmpty(t, tasks)
+		return tasks
+	}
+
+	t.Run("with user positions", func(t *testing.T) {
+		tasks := getTasks(t, true)
+		assert.Equal(t, int64(12), tasks[0].ID)
+		assert.Equal(t, 0.5, tasks[0].UserPosition)
+	})
+	t.Run("without user positions", func(t *testing.T) {
+		tasks := getTasks(t, false)
+		assert.NotEqual(t, int64(12), tasks[0].ID)
+	})
+}
@@ -0,0 +1,173 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TaskView holds when a user last looked at a task
+type TaskView struct {
+	ID     int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	TaskID int64 `xorm:"bigint INDEX not null" json:"-"`
+	UserID int64 `xorm:"bigint INDEX not null" json:"-"`
+	// When the user last opened the task.
+	Viewed time.Time `xorm:"DATETIME INDEX not null" json:"-"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name for task views
+func (*TaskView) TableName() string {
+	return "task_views"
+}
+
+// recordTaskView saves that the user has just looked at the task. Views of link shares are not tracked.
+func recordTaskView(s *xorm.Session, taskID int64, a web.Auth) error {
+	if _, is := a.(*LinkSharing); is {
+		return nil
+	}
+
+	view := &TaskView{
+		TaskID: taskID,
+		UserID: a.GetID(),
+		Viewed: time.Now(),
+	}
+
+	updated, err := s.
+		Where("task_id = ? AND user_id = ?", view.TaskID, view.UserID).
+		Cols("viewed").
+		Update(view)
+	if err != nil || updated > 0 {
+		return err
+	}
+
+	_, err = s.Insert(view)
+	return err
+}
+
+// CanRead checks if the user can see their recently viewed tasks. Only the tasks the user still has access to are returned.
+func (tv *TaskView) CanRead(_ *xorm.Session, a web.Auth) (bool, int, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, 0, nil
+	}
+	return true, int(RightRead), nil
+}
+
+// ReadAll returns the tasks the current user has recently looked at
+// @Summary Get recently viewed tasks
+// @Description Returns all tasks the current user has opened, the most recently viewed first. Only tasks the user still has access to are returned.
+// @tags task
+// @Accept json
+// @Produce json
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param s query string false "Search tasks by title."
+// @Security JWTKeyAuth
+// @Success 200 {array} models.Task "The tasks"
+// @Failure 403 {object} web.HTTPError "Link shares cannot have recently viewed tasks."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/recently_viewed [get]
+func (tv *TaskView) ReadAll(s *xorm.Session, a web.Auth, search string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	if _, is := a.(*LinkSharing); is {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	u, err := user.GetUserByID(s, a.GetID())
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	u.APITokenProjectIDs = getAPITokenProjectIDs(a)
+
+	projects, _, _, err := getRawProjectsForUser(s, &projectOptions{
+		user: u,
+		page: -1,
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	projectIDs := make([]int64, 0, len(projects))
+	for _, p := range projects {
+		if p.ID > 0 {
+			projectIDs = append(projectIDs, p.ID)
+		}
+	}
+
+	tasks := []*Task{}
+	if len(projectIDs) == 0 {
+		return tasks, 0, 0, nil
+	}
+
+	cond := builder.And(
+		builder.Eq{"task_views.user_id": u.ID},
+		builder.In("tasks.project_id", projectIDs),
+	)
+	if search != "" {
+		cond = builder.And(cond, db.ILIKE("tasks.title", search))
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	query := s.
+		Select("tasks.*").
+		Table("tasks").
+		Join("INNER", "task_views", "task_views.task_id = tasks.id").
+		Where(cond).
+		OrderBy("task_views.viewed desc, tasks.id desc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&tasks)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	numberOfTotalItems, err = s.
+		Table("tasks").
+		Join("INNER", "task_views", "task_views.task_id = tasks.id").
+		Where(cond).
+		Count()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	taskMap := make(map[int64]*Task, len(tasks))
+	for _, t := range tasks {
+		taskMap[t.ID] = t
+	}
+	err = addMoreInfoToTasks(s, taskMap, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	viewed := make([]*Task, 0, len(tasks))
+	for _, t := range tasks {
+		if task, has := taskMap[t.ID]; has {
+			viewed = append(viewed, task)
+		}
+	}
+
+	return viewed, len(viewed), numberOfTotalItems, nil
+}
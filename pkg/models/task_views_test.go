@@ -0,0 +1,119 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask_ReadOne_RecordsView(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("first view", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 2}
+		err := task.ReadOne(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertExists(t, "task_views", map[string]interface{}{
+			"task_id": 2,
+			"user_id": 1,
+		}, false)
+	})
+	t.Run("updates existing view", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1}
+		err := task.ReadOne(s, u)
+		require.NoError(t, err)
+
+		views := []*TaskView{}
+		err = s.Where("task_id = ? AND user_id = ?", 1, 1).Find(&views)
+		require.NoError(t, err)
+		require.Len(t, views, 1)
+		assert.Equal(t, int64(1), views[0].ID)
+		assert.True(t, views[0].Viewed.Year() > 2018)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		linkShare := &LinkSharing{ID: 1, ProjectID: 1, Right: RightRead}
+		task := &Task{ID: 2}
+		err := task.ReadOne(s, linkShare)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertMissing(t, "task_views", map[string]interface{}{
+			"task_id": 2,
+		})
+	})
+}
+
+func TestTaskView_ReadAll(t *testing.T) {
+	t.Run("most recent first", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tv := &TaskView{}
+		result, count, total, err := tv.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		// Task 14 was viewed, but user 1 does not have access to it anymore
+		require.Len(t, tasks, 2)
+		assert.Equal(t, int64(3), tasks[0].ID)
+		assert.Equal(t, int64(1), tasks[1].ID)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, int64(2), total)
+	})
+	t.Run("search", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tv := &TaskView{}
+		result, _, _, err := tv.ReadAll(s, &user.User{ID: 1}, "high prio", 0, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(3), tasks[0].ID)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tv := &TaskView{}
+		_, _, _, err := tv.ReadAll(s, &LinkSharing{ID: 1, ProjectID: 1}, "", 0, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
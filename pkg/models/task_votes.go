@@ -0,0 +1,292 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+const taskPropertyVotes string = "votes"
+
+// TaskVote represents an upvote of a user or link share on a task
+type TaskVote struct {
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	// The task which was voted for.
+	TaskID int64 `xorm:"bigint INDEX not null" json:"-" param:"task"`
+	// The user who voted. Votes of link shares are saved with the negative id of the share, like all other
+	// things link shares can create.
+	UserID int64      `xorm:"bigint INDEX not null" json:"-"`
+	User   *user.User `xorm:"-" json:"user"`
+	// A timestamp when this vote was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name for task votes
+func (*TaskVote) TableName() string {
+	return "task_votes"
+}
+
+// getVoterID returns the id votes of the user or link share are saved with
+func getVoterID(a web.Auth) int64 {
+	if share, is := a.(*LinkSharing); is {
+		return share.getUserID()
+	}
+	return a.GetID()
+}
+
+type taskVoteCount struct {
+	TaskID int64
+	Count  int64
+}
+
+// addVotesToTasks sets the number of votes of all tasks and whether the current user voted for them
+func addVotesToTasks(s *xorm.Session, taskIDs []int64, taskMap map[int64]*Task, a web.Auth) (err error) {
+	counts := []*taskVoteCount{}
+	err = s.
+		Table("task_votes").
+		Select("task_id, count(*) AS count").
+		In("task_id", taskIDs).
+		GroupBy("task_id").
+		Find(&counts)
+	if err != nil {
+		return
+	}
+
+	for _, c := range counts {
+		if task, has := taskMap[c.TaskID]; has {
+			task.Votes = c.Count
+		}
+	}
+
+	if a == nil {
+		return
+	}
+
+	votes := []*TaskVote{}
+	err = s.
+		Where("user_id = ?", getVoterID(a)).
+		In("task_id", taskIDs).
+		Find(&votes)
+	if err != nil {
+		return
+	}
+
+	for _, v := range votes {
+		if task, has := taskMap[v.TaskID]; has {
+			task.Voted = true
+		}
+	}
+
+	return
+}
+
+// getTaskVoteCountColumn returns an sql expression with the number of votes of a task, to sort or filter by it.
+func getTaskVoteCountColumn() string {
+	return "(SELECT COUNT(*) FROM task_votes WHERE task_votes.task_id = tasks.id)"
+}
+
+// getVotesFilterCond returns the db condition for a filter on the number of votes of tasks.
+func getVotesFilterCond(f *taskFilter, includeNulls bool) (cond builder.Cond, err error) {
+	column := getTaskVoteCountColumn()
+	switch f.comparator {
+	case taskFilterComparatorEquals:
+		cond = builder.Eq{column: f.value}
+	case taskFilterComparatorNotEquals:
+		cond = builder.Neq{column: f.value}
+	case taskFilterComparatorGreater:
+		cond = builder.Gt{column: f.value}
+	case taskFilterComparatorGreateEquals:
+		cond = builder.Gte{column: f.value}
+	case taskFilterComparatorLess:
+		cond = builder.Lt{column: f.value}
+	case taskFilterComparatorLessEquals:
+		cond = builder.Lte{column: f.value}
+	case taskFilterComparatorIn:
+		cond = builder.In(column, f.value)
+	default:
+		return nil, ErrInvalidTaskFilterValue{Field: f.field, Value: f.value}
+	}
+
+	if includeNulls {
+		cond = builder.Or(cond, builder.Eq{column: 0})
+	}
+
+	return
+}
+
+// usesTaskVotes checks whether the tasks are filtered or sorted by their votes. This can only be handled by the db.
+func usesTaskVotes(opts *taskSearchOptions) bool {
+	for _, param := range opts.sortby {
+		if param.sortBy == taskPropertyVotes {
+			return true
+		}
+	}
+	return hasVotesFilter(opts.parsedFilters)
+}
+
+func hasVotesFilter(filters []*taskFilter) bool {
+	for _, f := range filters {
+		if nested, is := f.value.([]*taskFilter); is && hasVotesFilter(nested) {
+			return true
+		}
+		if f.field == taskPropertyVotes {
+			return true
+		}
+	}
+	return false
+}
+
+// moveVotes moves the votes of the merged task to the target task. Users who voted for both keep only one vote.
+func (tm *TaskMerge) moveVotes(s *xorm.Session) error {
+	_, err := s.
+		Where("task_id = ?", tm.TaskID).
+		And(builder.NotIn("user_id", builder.Select("user_id").From("task_votes").Where(builder.Eq{"task_id": tm.TargetTaskID}))).
+		Cols("task_id").
+		NoAutoTime().
+		Update(&TaskVote{TaskID: tm.TargetTaskID})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("task_id = ?", tm.TaskID).Delete(&TaskVote{})
+	return err
+}
+
+// Create adds a vote to a task
+// @Summary Vote for a task
+// @Description Adds an upvote of the current user or link share to a task. Everyone who can see a task can vote for it. Will do nothing if the user already voted for the task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Success 201 {object} models.TaskVote "The created vote."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/votes [put]
+func (tv *TaskVote) Create(s *xorm.Session, a web.Auth) (err error) {
+	tv.UserID = getVoterID(a)
+
+	existing := &TaskVote{}
+	exists, err := s.
+		Where("task_id = ? AND user_id = ?", tv.TaskID, tv.UserID).
+		Get(existing)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		tv.ID = existing.ID
+		tv.Created = existing.Created
+	} else {
+		tv.ID = 0
+		_, err = s.Insert(tv)
+		if err != nil {
+			return err
+		}
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, []int64{tv.UserID})
+	if err != nil {
+		return err
+	}
+	tv.User = users[tv.UserID]
+	return nil
+}
+
+// Delete removes the vote of the current user from a task
+// @Summary Remove a vote from a task
+// @Description Removes the vote of the current user or link share from a task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Success 200 {object} models.Message "The vote was successfully removed."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/votes [delete]
+func (tv *TaskVote) Delete(s *xorm.Session, a web.Auth) (err error) {
+	_, err = s.
+		Where("task_id = ? AND user_id = ?", tv.TaskID, getVoterID(a)).
+		Delete(&TaskVote{})
+	return
+}
+
+// ReadAll returns all votes of a task
+// @Summary Get all votes of a task
+// @Description Returns all votes of a task together with the users who voted, the oldest first.
+// @tags task
+// @Accept json
+// @Produce json
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param taskID path int true "Task ID"
+// @Security JWTKeyAuth
+// @Success 200 {array} models.TaskVote "The votes"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/votes [get]
+func (tv *TaskVote) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	task := &Task{ID: tv.TaskID}
+	can, _, err := task.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	votes := []*TaskVote{}
+	query := s.
+		Where("task_id = ?", tv.TaskID).
+		OrderBy("id asc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&votes)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	userIDs := make([]int64, 0, len(votes))
+	for _, v := range votes {
+		userIDs = append(userIDs, v.UserID)
+	}
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for _, v := range votes {
+		v.User = users[v.UserID]
+	}
+
+	numberOfTotalItems, err = s.
+		Where("task_id = ?", tv.TaskID).
+		Count(&TaskVote{})
+	return votes, len(votes), numberOfTotalItems, err
+}
@@ -0,0 +1,38 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanCreate checks if a user can vote for a task. Everyone who can see a task can vote for it.
+func (tv *TaskVote) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return canDoTaskVote(s, tv, a)
+}
+
+// CanDelete checks if a user can remove their vote from a task
+func (tv *TaskVote) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return canDoTaskVote(s, tv, a)
+}
+
+func canDoTaskVote(s *xorm.Session, tv *TaskVote, a web.Auth) (bool, error) {
+	task := &Task{ID: tv.TaskID}
+	can, _, err := task.CanRead(s, a)
+	return can, err
+}
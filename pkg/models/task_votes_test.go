@@ -0,0 +1,182 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskVote_Create(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tv := &TaskVote{TaskID: 2}
+		can, err := tv.CanCreate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = tv.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), tv.User.ID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_votes", map[string]interface{}{
+			"task_id": 2,
+			"user_id": 1,
+		}, false)
+	})
+	t.Run("already voted", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tv := &TaskVote{TaskID: 1}
+		err := tv.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), tv.ID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		count, err := s.Where("task_id = ?", 1).Count(&TaskVote{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		share := &LinkSharing{ID: 2, ProjectID: 2, Right: RightRead}
+		tv := &TaskVote{TaskID: 13}
+		can, err := tv.CanCreate(s, share)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = tv.Create(s, share)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_votes", map[string]interface{}{
+			"task_id": 13,
+			"user_id": -2,
+		}, false)
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tv := &TaskVote{TaskID: 14}
+		can, err := tv.CanCreate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestTaskVote_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	tv := &TaskVote{TaskID: 1}
+	err := tv.Delete(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "task_votes", map[string]interface{}{
+		"task_id": 1,
+		"user_id": 1,
+	})
+	db.AssertExists(t, "task_votes", map[string]interface{}{
+		"task_id": 1,
+		"user_id": -1,
+	}, false)
+}
+
+func TestTaskVote_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	tv := &TaskVote{TaskID: 1}
+	result, count, total, err := tv.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, int64(2), total)
+	votes := result.([]*TaskVote)
+	assert.Equal(t, int64(1), votes[0].User.ID)
+	assert.Equal(t, int64(-1), votes[1].User.ID)
+}
+
+func TestTaskVote_SortAndFilter(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	tc := &TaskCollection{
+		ProjectID: 1,
+		SortBy:    []string{"votes", "id"},
+		OrderBy:   []string{"desc", "asc"},
+	}
+	result, _, _, err := tc.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+	require.NoError(t, err)
+	tasks := result.([]*Task)
+	require.NotEmpty(t, tasks)
+	assert.Equal(t, int64(1), tasks[0].ID)
+	assert.Equal(t, int64(2), tasks[0].Votes)
+	assert.True(t, tasks[0].Voted)
+	assert.Equal(t, int64(3), tasks[1].ID)
+	assert.False(t, tasks[1].Voted)
+
+	tc = &TaskCollection{
+		ProjectID: 1,
+		Filter:    "votes >= 1",
+	}
+	result, _, _, err = tc.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+	require.NoError(t, err)
+	tasks = result.([]*Task)
+	require.Len(t, tasks, 2)
+}
+
+func TestTaskMerge_Votes(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	tm := &TaskMerge{TaskID: 3, TargetTaskID: 1}
+	err := tm.Update(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertExists(t, "task_votes", map[string]interface{}{
+		"task_id": 1,
+		"user_id": 2,
+	}, false)
+	db.AssertMissing(t, "task_votes", map[string]interface{}{
+		"task_id": 3,
+	})
+}
@@ -0,0 +1,224 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TaskWatcher represents a user who watches a task and gets notified about all changes to it,
+// without being assigned to it.
+type TaskWatcher struct {
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	// The task the user watches.
+	TaskID int64 `xorm:"bigint INDEX not null" json:"-" param:"task"`
+	// The id of the watching user.
+	UserID int64 `xorm:"bigint INDEX not null" json:"user_id" param:"user"`
+	// A timestamp when this user started watching the task. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name for task watchers
+func (TaskWatcher) TableName() string {
+	return "task_watchers"
+}
+
+// getTaskWatchers returns all users watching the given tasks, keyed by task id
+func getTaskWatchers(s *xorm.Session, taskIDs []int64) (watchers map[int64][]*user.User, err error) {
+	watchers = make(map[int64][]*user.User)
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	type watcherWithUser struct {
+		TaskID    int64
+		user.User `xorm:"extends"`
+	}
+
+	raw := []*watcherWithUser{}
+	err = s.Table("task_watchers").
+		Select("task_watchers.task_id, users.*").
+		Join("INNER", "users", "task_watchers.user_id = users.id").
+		In("task_watchers.task_id", taskIDs).
+		OrderBy("task_watchers.id asc").
+		Find(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range raw {
+		u := w.User
+		watchers[w.TaskID] = append(watchers[w.TaskID], &u)
+	}
+
+	return
+}
+
+// addWatchersToSubscribers adds all watchers who are not subscribed already to a list of subscribers
+func addWatchersToSubscribers(subscribers []*Subscription, watchers []*user.User) []*Subscription {
+	subscribed := make(map[int64]bool, len(subscribers))
+	for _, sub := range subscribers {
+		subscribed[sub.UserID] = true
+	}
+
+	for _, w := range watchers {
+		if subscribed[w.ID] {
+			continue
+		}
+		subscribers = append(subscribers, &Subscription{
+			EntityType: SubscriptionEntityTask,
+			UserID:     w.ID,
+			User:       w,
+		})
+		subscribed[w.ID] = true
+	}
+
+	return subscribers
+}
+
+// Create adds a watcher to a task
+// @Summary Add a watcher to a task
+// @Description Adds a user as watcher to a task. Watchers get notified about everything that happens to the task. The user needs to have access to the task. Everyone who can see a task can watch it themselves, adding other users as watchers requires write access to the task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param watcher body models.TaskWatcher true "The watcher"
+// @Success 201 {object} models.TaskWatcher "The created watcher."
+// @Failure 400 {object} web.HTTPError "The user already watches the task or does not have access to it."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/watchers [put]
+func (tw *TaskWatcher) Create(s *xorm.Session, a web.Auth) (err error) {
+	if tw.UserID == 0 {
+		tw.UserID = a.GetID()
+	}
+
+	watcher, err := user.GetUserByID(s, tw.UserID)
+	if err != nil {
+		return err
+	}
+
+	project, err := GetProjectSimplByTaskID(s, tw.TaskID)
+	if err != nil {
+		return err
+	}
+	canRead, _, err := project.CanRead(s, watcher)
+	if err != nil {
+		return err
+	}
+	if !canRead {
+		return ErrUserDoesNotHaveAccessToProject{ProjectID: project.ID, UserID: watcher.ID}
+	}
+
+	exists, err := s.
+		Where("task_id = ? AND user_id = ?", tw.TaskID, tw.UserID).
+		Exist(&TaskWatcher{})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrUserAlreadyWatchesTask{TaskID: tw.TaskID, UserID: tw.UserID}
+	}
+
+	tw.ID = 0
+	_, err = s.Insert(tw)
+	return err
+}
+
+// Delete removes a watcher from a task
+// @Summary Remove a watcher from a task
+// @Description Removes a user from the watchers of a task. Everyone can stop watching a task themselves, removing other watchers requires write access to the task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param userID path int true "The user id of the watcher"
+// @Success 200 {object} models.Message "The watcher was successfully removed."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/watchers/{userID} [delete]
+func (tw *TaskWatcher) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where("task_id = ? AND user_id = ?", tw.TaskID, tw.UserID).
+		Delete(&TaskWatcher{})
+	return
+}
+
+// ReadAll returns all watchers of a task
+// @Summary Get all watchers of a task
+// @Description Returns all users watching a task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param s query string false "Search watchers by their username."
+// @Param taskID path int true "Task ID"
+// @Security JWTKeyAuth
+// @Success 200 {array} user.User "The watchers"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/watchers [get]
+func (tw *TaskWatcher) ReadAll(s *xorm.Session, a web.Auth, search string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	task := &Task{ID: tw.TaskID}
+	can, _, err := task.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	cond := builder.And(
+		builder.Eq{"task_watchers.task_id": tw.TaskID},
+		db.ILIKE("users.username", search),
+	)
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	watchers := []*user.User{}
+	query := s.Table("task_watchers").
+		Select("users.*").
+		Join("INNER", "users", "task_watchers.user_id = users.id").
+		Where(cond).
+		OrderBy("task_watchers.id asc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&watchers)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	numberOfTotalItems, err = s.Table("task_watchers").
+		Join("INNER", "users", "task_watchers.user_id = users.id").
+		Where(cond).
+		Count(&TaskWatcher{})
+	return watchers, len(watchers), numberOfTotalItems, err
+}
@@ -0,0 +1,49 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanCreate checks if a user can add a watcher to a task
+func (tw *TaskWatcher) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return canDoTaskWatcher(s, tw, a)
+}
+
+// CanDelete checks if a user can remove a watcher from a task
+func (tw *TaskWatcher) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return canDoTaskWatcher(s, tw, a)
+}
+
+func canDoTaskWatcher(s *xorm.Session, tw *TaskWatcher, a web.Auth) (bool, error) {
+	// Link shares can't watch tasks
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	task := &Task{ID: tw.TaskID}
+
+	// Users can always watch or stop watching a task they have access to
+	if tw.UserID == 0 || tw.UserID == a.GetID() {
+		can, _, err := task.CanRead(s, a)
+		return can, err
+	}
+
+	return task.CanUpdate(s, a)
+}
@@ -0,0 +1,235 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskWatcher_Create(t *testing.T) {
+	t.Run("watch yourself", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1}
+		tw := &TaskWatcher{TaskID: 32}
+		can, err := tw.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		err = tw.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_watchers", map[string]interface{}{
+			"task_id": 32,
+			"user_id": 1,
+		}, false)
+	})
+	t.Run("add another user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 3}
+		tw := &TaskWatcher{TaskID: 32, UserID: 2}
+		can, err := tw.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		err = tw.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_watchers", map[string]interface{}{
+			"task_id": 32,
+			"user_id": 2,
+		}, false)
+	})
+	t.Run("add another user without write access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tw := &TaskWatcher{TaskID: 32, UserID: 2}
+		can, err := tw.CanCreate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("user without access to the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tw := &TaskWatcher{TaskID: 1, UserID: 2}
+		err := tw.Create(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrUserDoesNotHaveAccessToProject(err))
+	})
+	t.Run("already watching", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tw := &TaskWatcher{TaskID: 40, UserID: 15}
+		err := tw.Create(s, &user.User{ID: 15})
+		require.Error(t, err)
+		assert.True(t, IsErrUserAlreadyWatchesTask(err))
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tw := &TaskWatcher{TaskID: 1}
+		can, err := tw.CanCreate(s, &LinkSharing{ID: 1, ProjectID: 1, Right: RightAdmin})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestTaskWatcher_ReadAll(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tw := &TaskWatcher{TaskID: 40}
+		result, resultCount, total, err := tw.ReadAll(s, &user.User{ID: 15}, "", 1, 50)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resultCount)
+		assert.Equal(t, int64(1), total)
+		watchers := result.([]*user.User)
+		assert.Equal(t, int64(15), watchers[0].ID)
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tw := &TaskWatcher{TaskID: 40}
+		_, _, _, err := tw.ReadAll(s, &user.User{ID: 1}, "", 1, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+	t.Run("part of the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 40}
+		err := task.ReadOne(s, &user.User{ID: 15})
+		require.NoError(t, err)
+		require.Len(t, task.Watchers, 1)
+		assert.Equal(t, int64(15), task.Watchers[0].ID)
+	})
+}
+
+func TestTaskWatcher_Delete(t *testing.T) {
+	t.Run("stop watching", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 15}
+		tw := &TaskWatcher{TaskID: 40, UserID: 15}
+		can, err := tw.CanDelete(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		err = tw.Delete(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_watchers", map[string]interface{}{
+			"task_id": 40,
+			"user_id": 15,
+		})
+	})
+	t.Run("removed with the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 40}
+		err := task.Delete(s, &user.User{ID: 15})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "task_watchers", map[string]interface{}{
+			"task_id": 40,
+		})
+	})
+}
+
+func TestTaskWatcher_Notifications(t *testing.T) {
+	t.Run("comment", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 40)
+		require.NoError(t, err)
+
+		ev := &TaskCommentCreatedEvent{
+			Task:    &task,
+			Comment: &TaskComment{ID: 1, Comment: "Lorem Ipsum", TaskID: 40},
+			Doer:    &user.User{ID: 1},
+		}
+
+		before, err := s.Where("notifiable_id = ? AND name = ?", 15, (&TaskCommentNotification{}).Name()).Count(&notifications.DatabaseNotification{})
+		require.NoError(t, err)
+		events.TestListener(t, ev, &SendTaskCommentNotification{})
+		after, err := s.Where("notifiable_id = ? AND name = ?", 15, (&TaskCommentNotification{}).Name()).Count(&notifications.DatabaseNotification{})
+		require.NoError(t, err)
+		assert.Equal(t, before+1, after)
+	})
+	t.Run("deleted task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 40)
+		require.NoError(t, err)
+		task.Watchers = []*user.User{{ID: 15}}
+
+		ev := &TaskDeletedEvent{
+			Task: &task,
+			Doer: &user.User{ID: 1},
+		}
+
+		before, err := s.Where("notifiable_id = ? AND name = ?", 15, (&TaskDeletedNotification{}).Name()).Count(&notifications.DatabaseNotification{})
+		require.NoError(t, err)
+		events.TestListener(t, ev, &SendTaskDeletedNotification{})
+		after, err := s.Where("notifiable_id = ? AND name = ?", 15, (&TaskDeletedNotification{}).Name()).Count(&notifications.DatabaseNotification{})
+		require.NoError(t, err)
+		assert.Equal(t, before+1, after)
+	})
+}
@@ -27,6 +27,7 @@ import (
 	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/modules/plugins"
 	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/api/pkg/utils"
 	"code.vikunja.io/web"
@@ -52,12 +53,36 @@ type Task struct {
 	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"projecttask"`
 	// The task text. This is what you'll see in the project.
 	Title string `xorm:"TEXT not null" json:"title" valid:"minstringlength(1)" minLength:"1"`
-	// The task description.
+	// A normalized version of the title, used to find tasks with small typos in the search.
+	NormalizedTitle string `xorm:"TEXT null" json:"-"`
+	// The task description. Depending on the description format this is either html or markdown.
 	Description string `xorm:"longtext null" json:"description"`
+	// The format of the description. Can be `html` or `markdown`, defaults to `html`.
+	DescriptionFormat TaskDescriptionFormat `xorm:"varchar(20) null" json:"description_format"`
+	// The description rendered as html. Only set if the description is stored as markdown.
+	DescriptionHTML string `xorm:"-" json:"description_html"`
 	// Whether a task is done or not.
 	Done bool `xorm:"INDEX null" json:"done"`
 	// The time when a task was marked as done.
 	DoneAt time.Time `xorm:"INDEX null 'done_at'" json:"done_at"`
+	// Whether a task is archived. Archived tasks are hidden from all task lists and searches unless they are explicitly filtered for with `archived = true`.
+	IsArchived bool `xorm:"not null default false" json:"is_archived"`
+	// Whether a task is on hold because its project was archived with the `hold_open_tasks` option. You cannot change this value.
+	OnHold bool `xorm:"not null default false" json:"on_hold"`
+	// If set to `vikunja` or `todoist` when creating a task, its title is parsed with quick add magic using the prefixes
+	// of that mode. All recognized labels, the project, priority, assignees, repeating interval and due date are set on the
+	// task and removed from its title. Properties which are provided explicitly take precedence.
+	QuickAddMagic QuickAddMagicMode `xorm:"-" json:"quick_add_magic,omitempty"`
+	// Whether a task is pinned to the top of all list and kanban views of its project. Use the pin endpoint to change this.
+	IsPinned bool `xorm:"not null default false" json:"is_pinned"`
+	// The position of a pinned task among the other pinned tasks of its project.
+	PinnedPosition float64 `xorm:"double null" json:"pinned_position"`
+	// The latitude of the location of this task in degrees. Must be set together with the longitude, both are null if the task does not have a location.
+	Latitude *float64 `xorm:"double null" json:"latitude"`
+	// The longitude of the location of this task in degrees. Must be set together with the latitude, both are null if the task does not have a location.
+	Longitude *float64 `xorm:"double null" json:"longitude"`
+	// A human readable name of the location of this task, for example an address.
+	PlaceName string `xorm:"varchar(250) null" json:"place_name" valid:"runelength(0|250)"`
 	// The time when the task is due.
 	DueDate time.Time `xorm:"DATETIME INDEX null 'due_date'" json:"due_date"`
 	// An array of reminders that are associated with this task.
@@ -68,20 +93,38 @@ type Task struct {
 	RepeatAfter int64 `xorm:"bigint INDEX null" json:"repeat_after" valid:"range(0|9223372036854775807)"`
 	// Can have three possible values which will trigger when the task is marked as done: 0 = repeats after the amount specified in repeat_after, 1 = repeats all dates each months (ignoring repeat_after), 3 = repeats from the current date rather than the last set date.
 	RepeatMode TaskRepeatMode `xorm:"not null default 0" json:"repeat_mode"`
+	// An RFC 5545 recurrence rule like `FREQ=MONTHLY;BYDAY=2TU`. If this is set, it takes precedence over repeat_after and repeat_mode:
+	// when marking the task as done, all dates are moved to the next occurrence of the rule after the current due date which is in the future.
+	// Only daily, weekly, monthly and yearly frequencies are supported.
+	RepeatRule string `xorm:"text null" json:"repeat_rule"`
 	// The task priority. Can be anything you want, it is possible to sort by this later.
 	Priority int64 `xorm:"bigint null" json:"priority"`
 	// When this task starts.
 	StartDate time.Time `xorm:"DATETIME INDEX null 'start_date'" json:"start_date" query:"-"`
 	// When this task ends.
 	EndDate time.Time `xorm:"DATETIME INDEX null 'end_date'" json:"end_date" query:"-"`
+	// The planned duration of this task in seconds. If a start date and a duration but no end date are set,
+	// the end date is calculated from them. If both start and end date are set, the duration is calculated from them.
+	Duration int64 `xorm:"bigint null default 0" json:"duration" valid:"range(0|9223372036854775807)"`
 	// An array of users who are assigned to this task
 	Assignees []*user.User `xorm:"-" json:"assignees"`
+	// All users who watch this task. Watchers get notified about everything that happens to the task.
+	Watchers []*user.User `xorm:"-" json:"watchers"`
 	// An array of labels which are associated with this task.
 	Labels []*Label `xorm:"-" json:"labels"`
 	// The task color in hex
 	HexColor string `xorm:"varchar(6) null" json:"hex_color" valid:"runelength(0|7)" maxLength:"7"`
-	// Determines how far a task is left from being done
+	// Determines how far a task is left from being done. This is read-only if the percent done mode is not `manual`.
 	PercentDone float64 `xorm:"DOUBLE null" json:"percent_done"`
+	// How the percent done of the task is determined. Can be `manual` (the default), `subtasks`, `checklist` or
+	// `subtasks_and_checklist`. With any mode other than `manual`, the percent done is calculated from how many
+	// of the direct subtasks and/or checklist items of the task are done and kept up to date when they change.
+	PercentDoneMode TaskPercentDoneMode `xorm:"varchar(30) null" json:"percent_done_mode"`
+	// The estimated effort to complete this task in seconds.
+	Estimate int64 `xorm:"bigint null default 0" json:"estimate" valid:"range(0|9223372036854775807)"`
+	// The values of the custom fields of the task's project, keyed by the id of the custom field.
+	// When updating a task, only the fields included here are changed. Set a field to null to remove its value.
+	CustomFields map[int64]interface{} `xorm:"-" json:"custom_fields" valid:"-"`
 
 	// The task identifier, based on the project identifier and the task's index
 	Identifier string `xorm:"-" json:"identifier"`
@@ -94,9 +137,27 @@ type Task struct {
 	// All related tasks, grouped by their relation kind
 	RelatedTasks RelatedTaskMap `xorm:"-" json:"related_tasks"`
 
+	// The id of the parent task. Can only be set when creating a task, use the /tasks/{id}/parent endpoint to change it later.
+	ParentTaskID int64 `xorm:"bigint null INDEX" json:"parent_task_id"`
+	// All direct subtasks of this task. Will only returned when retrieving one task.
+	Subtasks []*Task `xorm:"-" json:"subtasks,omitempty"`
+
 	// All attachments this task has
 	Attachments []*TaskAttachment `xorm:"-" json:"attachments"`
 
+	// The checklist of this task. Will only returned when retrieving one task, use the /tasks/{id}/checklist endpoints to change it.
+	ChecklistItems []*ChecklistItem `xorm:"-" json:"checklist_items,omitempty"`
+	// The number of checklist items of this task.
+	ChecklistItemsTotal int64 `xorm:"-" json:"checklist_items_total"`
+	// The number of checklist items of this task which are done.
+	ChecklistItemsDone int64 `xorm:"-" json:"checklist_items_done"`
+
+	// True if the task is blocked by at least one other task which is not done yet.
+	IsBlocked bool `xorm:"-" json:"is_blocked"`
+
+	// The age of the task and whether it breaches the sla rules of its project. Only set if the project has sla rules.
+	SLA *TaskSLAStatus `xorm:"-" json:"sla,omitempty"`
+
 	// If this task has a cover image, the field will return the id of the attachment that is the cover image.
 	CoverImageAttachmentID int64 `xorm:"bigint default 0" json:"cover_image_attachment_id"`
 
@@ -112,9 +173,22 @@ type Task struct {
 	// A timestamp when this task was last updated. You cannot change this value.
 	Updated time.Time `xorm:"updated not null" json:"updated"`
 
+	// How the task was created. Only used to find the default bucket for new tasks.
+	Source TaskSource `xorm:"-" json:"-"`
+
 	// BucketID is the ID of the kanban bucket this task belongs to.
 	BucketID int64 `xorm:"bigint null" json:"bucket_id"`
 
+	// The id of the status of this task. Statuses are defined per project and don't depend on the bucket a task is in.
+	// Set to 0 to remove the status.
+	StatusID int64 `xorm:"bigint null INDEX" json:"status_id"`
+	// The status of this task. Only filled when the task has a status.
+	Status *ProjectStatus `xorm:"-" json:"status,omitempty"`
+	// The id of the milestone this task is planned for. Set to 0 to remove the task from its milestone.
+	MilestoneID int64 `xorm:"bigint null INDEX" json:"milestone_id"`
+	// The milestone this task is planned for. Only filled when the task has a milestone.
+	Milestone *Milestone `xorm:"-" json:"milestone,omitempty"`
+
 	// The position of the task - any task project can be sorted as usual by this parameter.
 	// When accessing tasks via kanban buckets, this is primarily used to sort them based on a range
 	// We're using a float64 here to make it possible to put any task within any two other tasks (by changing the number).
@@ -124,14 +198,29 @@ type Task struct {
 	Position float64 `xorm:"double null" json:"position"`
 	// The position of tasks in the kanban board. See the docs for the `position` property on how to use this.
 	KanbanPosition float64 `xorm:"double null" json:"kanban_position"`
+	// The position the current user has set for the task in their own list view. 0 if the user did not set one,
+	// in that case the task is sorted by its `position` when requesting tasks with `user_positions=true`.
+	UserPosition float64 `xorm:"-" json:"user_position"`
 
 	// Reactions on that task.
 	Reactions ReactionMap `xorm:"-" json:"reactions"`
 
+	// The number of users and link shares who voted for this task. Use the /tasks/{id}/votes endpoints to vote.
+	Votes int64 `xorm:"-" json:"votes"`
+	// True if the user or link share making the call to the api voted for this task.
+	Voted bool `xorm:"-" json:"voted"`
+
+	// Where the search term was found in the task, including an excerpt with the matching text highlighted.
+	// Only set when searching tasks.
+	SearchMatches []*TaskSearchMatch `xorm:"-" json:"search_matches,omitempty"`
+
 	// The user who initially created the task.
 	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
 	CreatedByID int64      `xorm:"bigint not null" json:"-"` // ID of the user who put that task on the project
 
+	// Set from the If-Unmodified-Since header of an update request, see CheckUnmodifiedSince.
+	unmodifiedSince time.Time
+
 	web.CRUDable `xorm:"-" json:"-"`
 	web.Rights   `xorm:"-" json:"-"`
 }
@@ -179,6 +268,12 @@ type taskSearchOptions struct {
 	filter             string
 	filterTimezone     string
 	projectIDs         []int64
+	// If set, tasks sorted by position use the positions of this user
+	userPositionsFor int64
+	// If set, the searcher fills it with the facets of all matching tasks
+	facets *TaskSearchFacets
+	// If set, pinned tasks are sorted above all other tasks. Only used for views of a single project.
+	pinnedFirst bool
 }
 
 // ReadAll is a dummy function to still have that endpoint documented
@@ -189,8 +284,8 @@ type taskSearchOptions struct {
 // @Produce json
 // @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
 // @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
-// @Param s query string false "Search tasks by task text."
-// @Param sort_by query string false "The sorting parameter. You can pass this multiple times to get the tasks ordered by multiple different parameters, along with `order_by`. Possible values to sort by are `id`, `title`, `description`, `done`, `done_at`, `due_date`, `created_by_id`, `project_id`, `repeat_after`, `priority`, `start_date`, `end_date`, `hex_color`, `percent_done`, `uid`, `created`, `updated`. Default is `id`."
+// @Param s query string false "Search tasks by their title, description, comments and the text of their attachments. Where the search term was found is returned in `search_matches`."
+// @Param sort_by query string false "The sorting parameter. You can pass this multiple times to get the tasks ordered by multiple different parameters, along with `order_by`. Possible values to sort by are `id`, `title`, `description`, `done`, `done_at`, `due_date`, `created_by_id`, `project_id`, `repeat_after`, `priority`, `start_date`, `end_date`, `hex_color`, `percent_done`, `estimate`, `uid`, `created`, `updated`, `votes`. Default is `id`."
 // @Param order_by query string false "The ordering parameter. Possible values to order by are `asc` or `desc`. Default is `asc`."
 // @Param filter_by query string false "The name of the field to filter by. Allowed values are all task properties. Task properties which are their own object require passing in the id of that entity. Accepts an array for multiple filters which will be chanied together, all supplied filter must match."
 // @Param filter_value query string false "The value to filter for."
@@ -294,7 +389,8 @@ func getRawTasksForProjects(s *xorm.Session, projects []*Project, a web.Auth, op
 		a:                   a,
 		hasFavoritesProject: hasFavoritesProject,
 	}
-	if config.TypesenseEnabled.GetBool() {
+	// Typesense does not know about custom fields or the positions of users and can't calculate facets
+	if config.TypesenseEnabled.GetBool() && !hasCustomFieldFilter(opts.parsedFilters) && !hasLocationFilter(opts.parsedFilters) && !hasInteractionFilter(opts.parsedFilters) && !usesTaskVotes(opts) && !usesMilestoneDueDateSort(opts) && opts.userPositionsFor == 0 && opts.facets == nil && typesenseTaskCollectionIsUpToDate() {
 		searcher = &typesenseTaskSearcher{
 			s: s,
 		}
@@ -321,6 +417,13 @@ func getTasksForProjects(s *xorm.Session, projects []*Project, a web.Auth, opts
 		return nil, 0, 0, err
 	}
 
+	if opts.search != "" {
+		err = addSearchMatchesToTasks(s, taskMap, opts.search)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
 	return tasks, resultCount, totalItems, err
 }
 
@@ -592,6 +695,51 @@ func addMoreInfoToTasks(s *xorm.Session, taskMap map[int64]*Task, a web.Auth) (e
 		return
 	}
 
+	blockers, err := getUnresolvedBlockers(s, taskIDs)
+	if err != nil {
+		return
+	}
+
+	err = addCustomFieldValuesToTasks(s, taskIDs, taskMap)
+	if err != nil {
+		return
+	}
+
+	err = addChecklistProgressToTasks(s, taskIDs, taskMap)
+	if err != nil {
+		return
+	}
+
+	watchers, err := getTaskWatchers(s, taskIDs)
+	if err != nil {
+		return
+	}
+
+	err = addUserPositionsToTasks(s, taskIDs, taskMap, a)
+	if err != nil {
+		return
+	}
+
+	err = addVotesToTasks(s, taskIDs, taskMap, a)
+	if err != nil {
+		return
+	}
+
+	err = addSLAStatusToTasks(s, taskMap, projects, time.Now())
+	if err != nil {
+		return
+	}
+
+	err = addStatusesToTasks(s, taskMap)
+	if err != nil {
+		return
+	}
+
+	err = addMilestonesToTasks(s, taskMap)
+	if err != nil {
+		return
+	}
+
 	// Add all objects to their tasks
 	for _, task := range taskMap {
 
@@ -609,6 +757,15 @@ func addMoreInfoToTasks(s *xorm.Session, taskMap map[int64]*Task, a web.Auth) (e
 
 		task.IsFavorite = taskFavorites[task.ID]
 
+		task.IsBlocked = len(blockers[task.ID]) > 0
+
+		task.Watchers = watchers[task.ID]
+
+		err = task.renderDescription()
+		if err != nil {
+			return err
+		}
+
 		r, has := reactions[task.ID]
 		if has {
 			task.Reactions = r
@@ -647,6 +804,32 @@ func checkBucketLimit(s *xorm.Session, t *Task, bucket *Bucket) (err error) {
 	return nil
 }
 
+// dispatchIfBucketLimitExceeded notifies about a task being moved into a bucket which now has more tasks than its limit.
+// This can happen when tasks are moved automatically, for example into the done bucket when they are marked as done.
+func dispatchIfBucketLimitExceeded(s *xorm.Session, t *Task, bucket *Bucket, a web.Auth) error {
+	if bucket.Limit <= 0 {
+		return nil
+	}
+
+	taskCount, err := s.
+		Where("bucket_id = ?", bucket.ID).
+		Count(&Task{})
+	if err != nil {
+		return err
+	}
+	if taskCount <= bucket.Limit {
+		return nil
+	}
+
+	doer, _ := user.GetFromAuth(a)
+	return events.DispatchOnCommit(s, &BucketLimitExceededEvent{
+		Task:      t,
+		Bucket:    bucket,
+		TaskCount: taskCount,
+		Doer:      doer,
+	})
+}
+
 // Contains all the task logic to figure out what bucket to use for this task.
 func setTaskBucket(s *xorm.Session, task *Task, originalTask *Task, doCheckBucketLimit bool, project *Project) (targetBucket *Bucket, err error) {
 
@@ -672,7 +855,11 @@ func setTaskBucket(s *xorm.Session, task *Task, originalTask *Task, doCheckBucke
 	// because then we have it already updated to the done bucket.
 	if task.BucketID == 0 ||
 		(originalTask != nil && task.ProjectID != 0 && originalTask.ProjectID != task.ProjectID && !task.Done) {
-		task.BucketID, err = getDefaultBucketID(s, project)
+		if originalTask == nil {
+			task.BucketID, err = getDefaultBucketIDForSource(s, project, task.Source)
+		} else {
+			task.BucketID, err = getDefaultBucketID(s, project)
+		}
 		if err != nil {
 			return
 		}
@@ -742,12 +929,38 @@ func getNextTaskIndex(s *xorm.Session, projectID int64) (nextIndex int64, err er
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{id}/tasks [put]
 func (t *Task) Create(s *xorm.Session, a web.Auth) (err error) {
-	return createTask(s, t, a, true)
+	labels, err := t.applyQuickAddMagic(s, a)
+	if err != nil {
+		return err
+	}
+
+	defaultLabelIDs, err := t.applyProjectTaskDefaults(s)
+	if err != nil {
+		return err
+	}
+
+	err = createTask(s, t, a, true)
+	if err != nil {
+		return err
+	}
+
+	err = t.addQuickAddMagicLabels(s, a, labels)
+	if err != nil {
+		return err
+	}
+
+	return t.addDefaultLabels(s, defaultLabelIDs)
 }
 
 func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool) (err error) {
 
 	t.ID = 0
+	t.OnHold = false
+
+	err = t.runPluginHook(plugins.HookTaskCreate)
+	if err != nil {
+		return err
+	}
 
 	// Check if we have at least a title
 	if t.Title == "" {
@@ -760,6 +973,40 @@ func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool) (err
 		return err
 	}
 
+	if t.RepeatRule != "" {
+		t.RepeatRule, err = NormalizeRepeatRule(t.RepeatRule)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = t.validateLocation()
+	if err != nil {
+		return err
+	}
+
+	err = t.PercentDoneMode.validate()
+	if err != nil {
+		return err
+	}
+
+	err = t.validateStatus(s)
+	if err != nil {
+		return err
+	}
+
+	err = t.validateMilestone(s)
+	if err != nil {
+		return err
+	}
+
+	err = t.sanitizeDescription(TaskDescriptionFormatHTML)
+	if err != nil {
+		return err
+	}
+
+	t.normalizeSchedule()
+
 	createdBy, err := GetUserOrLinkShareUser(s, a)
 	if err != nil {
 		return err
@@ -771,12 +1018,37 @@ func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool) (err
 		t.UID = uuid.NewString()
 	}
 
+	if t.Source == "" {
+		t.Source = getTaskSourceFromAuth(a)
+	}
+
 	// Get the default bucket and move the task there
-	_, err = setTaskBucket(s, t, nil, true, nil)
+	bucketProvided := t.BucketID != 0
+	bucket, err := setTaskBucket(s, t, nil, true, nil)
 	if err != nil {
 		return
 	}
 
+	if bucketProvided {
+		if err := bucket.checkCanMoveTaskInto(s, a); err != nil {
+			return err
+		}
+	}
+
+	if t.ParentTaskID != 0 {
+		parent := &Task{ID: t.ParentTaskID}
+		canRead, _, err := parent.CanRead(s, a)
+		if err != nil {
+			return err
+		}
+		if !canRead {
+			return ErrGenericForbidden{}
+		}
+		if err := validateParentTask(s, t, t.ParentTaskID); err != nil {
+			return err
+		}
+	}
+
 	// Get the index for this task
 	t.Index, err = getNextTaskIndex(s, t.ProjectID)
 	if err != nil {
@@ -788,14 +1060,60 @@ func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool) (err
 	t.KanbanPosition = calculateDefaultPosition(t.Index, t.KanbanPosition)
 
 	t.HexColor = utils.NormalizeHex(t.HexColor)
+	t.NormalizedTitle = utils.NormalizeSearchText(t.Title)
+
+	if t.PercentDoneMode.isAutomatic() {
+		t.PercentDone = 0
+	}
 
 	_, err = s.Insert(t)
 	if err != nil {
 		return err
 	}
 
+	err = recordBucketTransition(s, t.ID, 0, 0, t.ProjectID, t.BucketID)
+	if err != nil {
+		return err
+	}
+
+	err = dispatchIfBucketLimitExceeded(s, t, bucket, a)
+	if err != nil {
+		return err
+	}
+
+	if t.ParentTaskID != 0 {
+		err = createParentTaskRelation(s, t.ID, t.ParentTaskID, a)
+		if err != nil {
+			return err
+		}
+	}
+
 	t.CreatedBy = createdBy
 
+	err = t.setCustomFieldValues(s)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range t.ChecklistItems {
+		item.TaskID = t.ID
+		err = createChecklistItem(s, item, createdBy)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The new task can only have checklist items yet
+	err = t.updateAutomaticPercentDone(s)
+	if err != nil {
+		return err
+	}
+
+	err = updateAutomaticPercentDoneByID(s, t.ParentTaskID)
+	if err != nil {
+		return err
+	}
+
 	// Update the assignees
 	if updateAssignees {
 		if err := t.updateTaskAssignees(s, t.Assignees, a); err != nil {
@@ -816,7 +1134,7 @@ func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool) (err
 		}
 	}
 
-	err = events.Dispatch(&TaskCreatedEvent{
+	err = events.DispatchOnCommit(s, &TaskCreatedEvent{
 		Task: t,
 		Doer: createdBy,
 	})
@@ -845,17 +1163,70 @@ func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool) (err
 //
 //nolint:gocyclo
 func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
+	err = CheckUnmodifiedSince(s, t, t.ID, t.unmodifiedSince)
+	if err != nil {
+		return err
+	}
 
 	// Check if the task exists and get the old values
 	ot, err := GetTaskByIDSimple(s, t.ID)
 	if err != nil {
 		return
 	}
+	// Keep the values from before the update for the history
+	oldTask := ot
 
 	if t.ProjectID == 0 {
 		t.ProjectID = ot.ProjectID
 	}
 
+	err = t.runPluginHook(plugins.HookTaskUpdate)
+	if err != nil {
+		return err
+	}
+
+	if t.RepeatRule != "" {
+		t.RepeatRule, err = NormalizeRepeatRule(t.RepeatRule)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = t.validateLocation()
+	if err != nil {
+		return err
+	}
+
+	err = t.PercentDoneMode.validate()
+	if err != nil {
+		return err
+	}
+
+	// Statuses and milestones belong to a project, a task moved into another project therefore loses them.
+	if t.ProjectID != ot.ProjectID && t.StatusID == ot.StatusID {
+		t.StatusID = 0
+	}
+	if t.ProjectID != ot.ProjectID && t.MilestoneID == ot.MilestoneID {
+		t.MilestoneID = 0
+	}
+
+	err = t.validateStatus(s)
+	if err != nil {
+		return err
+	}
+
+	err = t.validateMilestone(s)
+	if err != nil {
+		return err
+	}
+
+	err = t.sanitizeDescription(ot.DescriptionFormat)
+	if err != nil {
+		return err
+	}
+
+	t.normalizeSchedule()
+
 	// Get the stored reminders
 	reminders, err := getRemindersForTasks(s, []int64{t.ID})
 	if err != nil {
@@ -875,14 +1246,44 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
+	if targetBucket.ID != ot.BucketID {
+		if err := targetBucket.checkCanMoveTaskInto(s, a); err != nil {
+			return err
+		}
+	}
+
+	// Tasks in automatically sorted buckets can't be reordered manually
+	if targetBucket.ID == ot.BucketID &&
+		targetBucket.SortMode != BucketSortModeManual &&
+		t.KanbanPosition != 0 &&
+		t.KanbanPosition != ot.KanbanPosition {
+		return &ErrBucketIsSortedAutomatically{BucketID: targetBucket.ID, SortMode: targetBucket.SortMode}
+	}
+
 	// If the task was moved into the done bucket and the task has a repeating cycle we should not update
 	// the bucket.
-	if targetBucket.ID == project.DoneBucketID && t.RepeatAfter > 0 {
+	if targetBucket.ID == project.DoneBucketID && (t.RepeatAfter > 0 || t.RepeatRule != "") {
 		t.Done = true // This will trigger the correct re-scheduling of the task (happening in updateDone later)
 		t.BucketID = ot.BucketID
 	}
 
+	// Run all actions of the bucket the task was moved into
+	moved := targetBucket.ID != ot.BucketID
+	originalProjectID := ot.ProjectID
+	originalBucketID := ot.BucketID
+	if moved {
+		targetBucket.applyTaskFieldActions(t, time.Now())
+	}
+
+	// Tasks with unresolved blockers can't be marked as done if the project enforces it
+	if t.Done && !ot.Done && project.EnforceBlockingDependencies {
+		if err := ot.checkBlockersResolved(s); err != nil {
+			return err
+		}
+	}
+
 	// When a repeating task is marked as done, we update all deadlines and reminders and set it as undone
+	markedDone := t.Done && !ot.Done
 	updateDone(&ot, t)
 
 	// Update the assignees
@@ -898,22 +1299,34 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	// All columns to update in a separate variable to be able to add to them
 	colsToUpdate := []string{
 		"title",
+		"normalized_title",
 		"description",
 		"done",
 		"due_date",
 		"repeat_after",
+		"repeat_rule",
 		"priority",
 		"start_date",
 		"end_date",
 		"hex_color",
 		"done_at",
 		"percent_done",
+		"estimate",
 		"project_id",
 		"bucket_id",
 		"position",
 		"repeat_mode",
 		"kanban_position",
 		"cover_image_attachment_id",
+		"description_format",
+		"duration",
+		"is_archived",
+		"percent_done_mode",
+		"latitude",
+		"longitude",
+		"place_name",
+		"status_id",
+		"milestone_id",
 	}
 
 	// If the task is being moved between projects, make sure to move the bucket + index as well
@@ -992,6 +1405,10 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	if !t.Done {
 		ot.Done = false
 	}
+	// Archived
+	if !t.IsArchived {
+		ot.IsArchived = false
+	}
 	// Priority
 	if t.Priority == 0 {
 		ot.Priority = 0
@@ -1008,6 +1425,10 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	if t.RepeatAfter == 0 {
 		ot.RepeatAfter = 0
 	}
+	// Repeat rule
+	if t.RepeatRule == "" {
+		ot.RepeatRule = ""
+	}
 	// Start date
 	if t.StartDate.IsZero() {
 		ot.StartDate = time.Time{}
@@ -1016,6 +1437,10 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	if t.EndDate.IsZero() {
 		ot.EndDate = time.Time{}
 	}
+	// Duration
+	if t.Duration == 0 {
+		ot.Duration = 0
+	}
 	// Color
 	if t.HexColor == "" {
 		ot.HexColor = ""
@@ -1024,6 +1449,10 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	if t.PercentDone == 0 {
 		ot.PercentDone = 0
 	}
+	// Estimate
+	if t.Estimate == 0 {
+		ot.Estimate = 0
+	}
 	// Position
 	if t.Position == 0 {
 		ot.Position = 0
@@ -1043,23 +1472,101 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	if t.CoverImageAttachmentID == 0 {
 		ot.CoverImageAttachmentID = 0
 	}
+	// Percent done mode
+	if t.PercentDoneMode == "" {
+		ot.PercentDoneMode = ""
+	}
+	if ot.PercentDoneMode.isAutomatic() {
+		ot.PercentDone, err = ot.calculatePercentDone(s)
+		if err != nil {
+			return err
+		}
+	}
+	// Location
+	if t.Latitude == nil || t.Longitude == nil {
+		ot.Latitude = nil
+		ot.Longitude = nil
+	}
+	if t.PlaceName == "" {
+		ot.PlaceName = ""
+	}
+	// Status
+	if t.StatusID == 0 {
+		ot.StatusID = 0
+	}
+	// Milestone
+	if t.MilestoneID == 0 {
+		ot.MilestoneID = 0
+	}
+
+	ot.NormalizedTitle = utils.NormalizeSearchText(ot.Title)
 
 	_, err = s.ID(t.ID).
 		Cols(colsToUpdate...).
-		Update(ot)
+		Update(&ot)
 	*t = ot
 	if err != nil {
 		return err
 	}
 
-	// Update all positions if the newly saved position is < 0.1
-	if ot.Position < 0.1 {
+	err = recordBucketTransition(s, t.ID, originalProjectID, originalBucketID, t.ProjectID, t.BucketID)
+	if err != nil {
+		return err
+	}
+
+	err = updateAutomaticPercentDoneByID(s, t.ParentTaskID)
+	if err != nil {
+		return err
+	}
+
+	if t.BucketID != originalBucketID && t.BucketID == targetBucket.ID {
+		err = dispatchIfBucketLimitExceeded(s, t, targetBucket, a)
+		if err != nil {
+			return err
+		}
+	}
+
+	if moved {
+		err = targetBucket.applyLabelActions(s, a, t)
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.ProjectID != originalProjectID {
+		err = t.removeForeignCustomFieldValues(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = t.setCustomFieldValues(s)
+	if err != nil {
+		return err
+	}
+
+	// Update all positions if the newly saved position is < 0.1 or too close to another one
+	rebalancePositions := ot.Position < 0.1
+	if !rebalancePositions {
+		rebalancePositions, err = positionIsTooClose(s, &Task{}, "position", "project_id", t.ProjectID, t.ID, ot.Position)
+		if err != nil {
+			return err
+		}
+	}
+	if rebalancePositions {
 		err = recalculateTaskPositions(s, t.ProjectID)
 		if err != nil {
 			return err
 		}
 	}
-	if ot.KanbanPosition < 0.1 {
+	rebalanceKanbanPositions := ot.KanbanPosition < 0.1
+	if !rebalanceKanbanPositions {
+		rebalanceKanbanPositions, err = positionIsTooClose(s, &Task{}, "kanban_position", "bucket_id", t.BucketID, t.ID, ot.KanbanPosition)
+		if err != nil {
+			return err
+		}
+	}
+	if rebalanceKanbanPositions {
 		err = recalculateTaskKanbanPositions(s, t.BucketID)
 		if err != nil {
 			return err
@@ -1077,8 +1584,13 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	t.Position = nt.Position
 	t.KanbanPosition = nt.KanbanPosition
 
+	err = recordTaskChanges(s, a, &oldTask, t)
+	if err != nil {
+		return err
+	}
+
 	doer, _ := user.GetFromAuth(a)
-	err = events.Dispatch(&TaskUpdatedEvent{
+	err = events.DispatchOnCommit(s, &TaskUpdatedEvent{
 		Task: t,
 		Doer: doer,
 	})
@@ -1086,6 +1598,28 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
+	if markedDone {
+		err = events.DispatchOnCommit(s, &TaskDoneEvent{
+			Task: t,
+			Doer: doer,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.ProjectID == originalProjectID && t.BucketID != originalBucketID {
+		err = events.DispatchOnCommit(s, &TaskMovedEvent{
+			Task:        t,
+			OldBucketID: originalBucketID,
+			NewBucketID: t.BucketID,
+			Doer:        doer,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return updateProjectLastUpdated(s, &Project{ID: t.ProjectID})
 }
 
@@ -1314,12 +1848,14 @@ func setTaskDatesFromCurrentDateRepeat(oldTask, newTask *Task) {
 //  2. Because of 1., this functions should not be used to update values other than Done in the same go
 func updateDone(oldTask *Task, newTask *Task) {
 	if !oldTask.Done && newTask.Done {
-		switch oldTask.RepeatMode {
-		case TaskRepeatModeMonth:
+		switch {
+		case oldTask.RepeatRule != "":
+			setTaskDatesFromRepeatRule(oldTask, newTask)
+		case oldTask.RepeatMode == TaskRepeatModeMonth:
 			setTaskDatesMonthRepeat(oldTask, newTask)
-		case TaskRepeatModeFromCurrentDate:
+		case oldTask.RepeatMode == TaskRepeatModeFromCurrentDate:
 			setTaskDatesFromCurrentDateRepeat(oldTask, newTask)
-		case TaskRepeatModeDefault:
+		case oldTask.RepeatMode == TaskRepeatModeDefault:
 			setTaskDatesDefault(oldTask, newTask)
 		}
 
@@ -1393,10 +1929,20 @@ func (t *Task) updateReminders(s *xorm.Session, task *Task) (err error) {
 	// Loop through all reminders and add them
 	for _, r := range reminderMap {
 		taskReminder := &TaskReminder{
-			TaskID:         t.ID,
-			Reminder:       r.Reminder,
-			RelativePeriod: r.RelativePeriod,
-			RelativeTo:     r.RelativeTo}
+			TaskID:                 t.ID,
+			Reminder:               r.Reminder,
+			RelativePeriod:         r.RelativePeriod,
+			RelativeTo:             r.RelativeTo,
+			EscalateAfter:          r.EscalateAfter,
+			EscalateToProjectOwner: r.EscalateToProjectOwner,
+			EscalateToUserID:       r.EscalateToUserID,
+			EscalateToTeamID:       r.EscalateToTeamID,
+		}
+		err = taskReminder.validateEscalation(s, t.ProjectID)
+		if err != nil {
+			return err
+		}
+		taskReminder.updateEscalationDate()
 		_, err = s.Insert(taskReminder)
 		if err != nil {
 			return err
@@ -1443,6 +1989,11 @@ func (t *Task) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
+	err = fullTask.runPluginHook(plugins.HookTaskDelete)
+	if err != nil {
+		return err
+	}
+
 	// Delete assignees
 	if _, err = s.Where("task_id = ?", t.ID).Delete(TaskAssginee{}); err != nil {
 		return err
@@ -1473,6 +2024,25 @@ func (t *Task) Delete(s *xorm.Session, a web.Auth) (err error) {
 		}
 	}
 
+	// Delete all reactions on the task and its comments and the comment revisions
+	err = deleteReactionsForEntities(s, ReactionKindTask, []int64{t.ID})
+	if err != nil {
+		return
+	}
+	commentIDs := []int64{}
+	err = s.Table("task_comments").Where("task_id = ?", t.ID).Cols("id").Find(&commentIDs)
+	if err != nil {
+		return
+	}
+	err = deleteReactionsForEntities(s, ReactionKindComment, commentIDs)
+	if err != nil {
+		return
+	}
+	err = deleteRevisionsForComments(s, commentIDs)
+	if err != nil {
+		return
+	}
+
 	// Delete all comments
 	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskComment{})
 	if err != nil {
@@ -1485,20 +2055,105 @@ func (t *Task) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	// Delete all recorded sla breaches
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskSLABreach{})
+	if err != nil {
+		return
+	}
+
 	// Delete all reminders
 	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskReminder{})
 	if err != nil {
 		return
 	}
 
+	// The bucket transitions are kept for the analytics of the project, the task is recorded as removed from its bucket
+	err = recordBucketTransition(s, t.ID, fullTask.ProjectID, fullTask.BucketID, 0, 0)
+	if err != nil {
+		return
+	}
+
+	// Delete all time entries
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TimeEntry{})
+	if err != nil {
+		return
+	}
+
+	// Delete all custom field values
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskCustomFieldValue{})
+	if err != nil {
+		return
+	}
+
+	// Delete the checklist
+	_, err = s.Where("task_id = ?", t.ID).Delete(&ChecklistItem{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskMention{})
+	if err != nil {
+		return
+	}
+
+	// Delete the history
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskHistoryEntry{})
+	if err != nil {
+		return
+	}
+
+	// Delete the watchers
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskWatcher{})
+	if err != nil {
+		return
+	}
+
+	// Delete the positions of users
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskUserPosition{})
+	if err != nil {
+		return
+	}
+
+	// Delete the votes
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskVote{})
+	if err != nil {
+		return
+	}
+
+	// Delete the views
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskView{})
+	if err != nil {
+		return
+	}
+
+	// Make all subtasks top-level tasks
+	_, err = s.
+		Where("parent_task_id = ?", t.ID).
+		Cols("parent_task_id").
+		NoAutoTime().
+		Update(&Task{ParentTaskID: 0})
+	if err != nil {
+		return
+	}
+
 	// Actually delete the task
 	_, err = s.ID(t.ID).Delete(Task{})
 	if err != nil {
 		return err
 	}
 
+	err = recordTaskTombstone(s, fullTask)
+	if err != nil {
+		return err
+	}
+
+	err = updateAutomaticPercentDoneByID(s, fullTask.ParentTaskID)
+	if err != nil {
+		return err
+	}
+
 	doer, _ := user.GetFromAuth(a)
-	err = events.Dispatch(&TaskDeletedEvent{
+	err = events.DispatchOnCommit(s, &TaskDeletedEvent{
 		Task: fullTask,
 		Doer: doer,
 	})
@@ -1542,6 +2197,25 @@ func (t *Task) ReadOne(s *xorm.Session, a web.Auth) (err error) {
 
 	*t = *taskMap[t.ID]
 
+	err = addSubtasksToTask(s, t, a)
+	if err != nil {
+		return
+	}
+
+	t.ChecklistItems, err = getChecklistItemsForTasks(s, []int64{t.ID})
+	if err != nil {
+		return
+	}
+	err = addCreatorsToChecklistItems(s, t.ChecklistItems)
+	if err != nil {
+		return
+	}
+
+	err = recordTaskView(s, t.ID, a)
+	if err != nil {
+		return
+	}
+
 	t.Subscription, err = GetSubscription(s, SubscriptionEntityTask, t.ID, a)
 	if err != nil && IsErrProjectDoesNotExist(err) {
 		return nil
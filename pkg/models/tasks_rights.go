@@ -23,19 +23,19 @@ import (
 
 // CanDelete checks if the user can delete an task
 func (t *Task) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
-	return t.canDoTask(s, a)
+	return t.canDoTask(s, a, ProjectPermissionDeleteTasks)
 }
 
 // CanUpdate determines if a user has the right to update a project task
 func (t *Task) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
-	return t.canDoTask(s, a)
+	return t.canDoTask(s, a, ProjectPermissionUpdateTasks)
 }
 
 // CanCreate determines if a user has the right to create a project task
 func (t *Task) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
-	// A user can do a task if he has write acces to its project
+	// A user can do a task if he has write acces to its project or a role which allows creating tasks
 	l := &Project{ID: t.ProjectID}
-	return l.CanWrite(s, a)
+	return l.canWriteOrHasPermission(s, a, ProjectPermissionCreateTasks)
 }
 
 // CanRead determines if a user can read a task
@@ -53,11 +53,12 @@ func (t *Task) CanRead(s *xorm.Session, a web.Auth) (canRead bool, maxRight int,
 
 // CanWrite checks if a user has write access to a task
 func (t *Task) CanWrite(s *xorm.Session, a web.Auth) (canWrite bool, err error) {
-	return t.canDoTask(s, a)
+	return t.canDoTask(s, a, ProjectPermissionUpdateTasks)
 }
 
-// Helper function to check if a user can do stuff on a project task
-func (t *Task) canDoTask(s *xorm.Session, a web.Auth) (bool, error) {
+// Helper function to check if a user can do stuff on a project task.
+// Users without write access to the project can still do it if one of their roles grants the permission.
+func (t *Task) canDoTask(s *xorm.Session, a web.Auth, permission ProjectPermission) (bool, error) {
 	// Get the task
 	ot, err := GetTaskByIDSimple(s, t.ID)
 	if err != nil {
@@ -67,7 +68,7 @@ func (t *Task) canDoTask(s *xorm.Session, a web.Auth) (bool, error) {
 	// Check if we're moving the task into a different project to check if the user has sufficient rights for that on the new project
 	if t.ProjectID != 0 && t.ProjectID != ot.ProjectID {
 		newProject := &Project{ID: t.ProjectID}
-		can, err := newProject.CanWrite(s, a)
+		can, err := newProject.canWriteOrHasPermission(s, a, ProjectPermissionCreateTasks)
 		if err != nil {
 			return false, err
 		}
@@ -78,5 +79,5 @@ func (t *Task) canDoTask(s *xorm.Session, a web.Auth) (bool, error) {
 
 	// A user can do a task if it has write acces to its project
 	l := &Project{ID: ot.ProjectID}
-	return l.CanWrite(s, a)
+	return l.canWriteOrHasPermission(s, a, permission)
 }
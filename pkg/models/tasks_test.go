@@ -188,6 +188,117 @@ func TestTask_Create(t *testing.T) {
 			"bucket_id": 22, // default bucket of project 6 but with a position of 2
 		}, false)
 	})
+	t.Run("default bucket for the task source", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("source_default_buckets").Update(&Project{
+			SourceDefaultBuckets: map[TaskSource]int64{
+				TaskSourceAPIToken: 3,
+			},
+		})
+		require.NoError(t, err)
+
+		task := &Task{
+			Title:     "Lorem",
+			ProjectID: 1,
+		}
+		err = task.Create(s, &user.User{ID: 1, AuthenticatedWithAPIToken: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), task.BucketID)
+
+		// Tasks from other sources still end up in the default bucket
+		task = &Task{
+			Title:     "Lorem",
+			ProjectID: 1,
+			Source:    TaskSourceCalDAV,
+		}
+		err = task.Create(s, usr)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), task.BucketID)
+	})
+	t.Run("sanitizes html description", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:       "Lorem",
+			Description: "<p>Lorem</p><script>alert(1)</script>",
+			ProjectID:   1,
+		}
+		err := task.Create(s, usr)
+		require.NoError(t, err)
+		assert.Equal(t, TaskDescriptionFormatHTML, task.DescriptionFormat)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":                 task.ID,
+			"description":        "<p>Lorem</p>",
+			"description_format": "html",
+		}, false)
+	})
+	t.Run("markdown description", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:             "Lorem",
+			Description:       "Lorem **Ipsum**",
+			DescriptionFormat: TaskDescriptionFormatMarkdown,
+			ProjectID:         1,
+		}
+		err := task.Create(s, usr)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":                 task.ID,
+			"description":        "Lorem **Ipsum**",
+			"description_format": "markdown",
+		}, false)
+
+		s = db.NewSession()
+		defer s.Close()
+		read := &Task{ID: task.ID}
+		err = read.ReadOne(s, usr)
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Lorem <strong>Ipsum</strong></p>\n", read.DescriptionHTML)
+	})
+	t.Run("end date from duration", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		start := time.Date(2018, 12, 12, 8, 0, 0, 0, time.UTC)
+		task := &Task{
+			Title:     "Lorem",
+			ProjectID: 1,
+			StartDate: start,
+			Duration:  5400,
+		}
+		err := task.Create(s, usr)
+		require.NoError(t, err)
+		assert.Equal(t, start.Add(90*time.Minute), task.EndDate)
+	})
+	t.Run("invalid description format", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			Title:             "Lorem",
+			DescriptionFormat: "rtf",
+			ProjectID:         1,
+		}
+		err := task.Create(s, usr)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidDescriptionFormat(err))
+	})
 }
 
 func TestTask_Update(t *testing.T) {
@@ -216,6 +327,52 @@ func TestTask_Update(t *testing.T) {
 			"project_id":  1,
 		}, false)
 	})
+	t.Run("keeps the description format", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("description_format").Update(&Task{DescriptionFormat: TaskDescriptionFormatMarkdown})
+		require.NoError(t, err)
+
+		task := &Task{
+			ID:          1,
+			Title:       "test10000",
+			Description: "<b>not html</b> *but markdown*",
+			ProjectID:   1,
+		}
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":                 1,
+			"description":        "<b>not html</b> *but markdown*",
+			"description_format": "markdown",
+		}, false)
+	})
+	t.Run("estimate", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:        1,
+			Title:     "test",
+			ProjectID: 1,
+			Estimate:  3600,
+		}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":       1,
+			"estimate": 3600,
+		}, false)
+	})
 	t.Run("nonexistant task", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
@@ -304,6 +461,178 @@ func TestTask_Update(t *testing.T) {
 			"bucket_id":  3,
 		}, false)
 	})
+	t.Run("moving a task into a bucket with actions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("actions").Update(&Bucket{
+			Actions: []*BucketAction{
+				{Kind: BucketActionSetPriority, Value: 4},
+				{Kind: BucketActionAddAssignee, Value: 1},
+				{Kind: BucketActionAddLabel, Value: 1},
+			},
+		})
+		require.NoError(t, err)
+
+		task := &Task{
+			ID:        3,
+			Title:     "test",
+			ProjectID: 1,
+			BucketID:  1,
+		}
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), task.Priority)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        3,
+			"bucket_id": 1,
+			"priority":  4,
+		}, false)
+		db.AssertExists(t, "task_assignees", map[string]interface{}{
+			"task_id": 3,
+			"user_id": 1,
+		}, false)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  3,
+			"label_id": 1,
+		}, false)
+		events.AssertDispatched(t, &TaskLabelCreatedEvent{})
+	})
+	t.Run("moving a task into a bucket with a remove label action", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(3).Cols("actions").Update(&Bucket{
+			Actions: []*BucketAction{
+				{Kind: BucketActionRemoveLabel, Value: 4},
+			},
+		})
+		require.NoError(t, err)
+
+		task := &Task{
+			ID:        1,
+			Title:     "test",
+			ProjectID: 1,
+			BucketID:  3,
+		}
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "label_tasks", map[string]interface{}{
+			"task_id":  1,
+			"label_id": 4,
+		})
+		events.AssertDispatched(t, &TaskLabelDeletedEvent{})
+	})
+	t.Run("moving a task into a bucket with an add label action for an inaccessible label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("actions").Update(&Bucket{
+			Actions: []*BucketAction{
+				{Kind: BucketActionAddLabel, Value: 3},
+			},
+		})
+		require.NoError(t, err)
+
+		task := &Task{
+			ID:        3,
+			Title:     "test",
+			ProjectID: 1,
+			BucketID:  1,
+		}
+		err = task.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserHasNoAccessToLabel(err))
+	})
+	t.Run("changing the position in an automatically sorted bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("sort_mode").Update(&Bucket{SortMode: BucketSortModePriority})
+		require.NoError(t, err)
+
+		task := &Task{
+			ID:             1,
+			Title:          "test",
+			ProjectID:      1,
+			BucketID:       1,
+			KanbanPosition: 42,
+		}
+		err = task.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBucketIsSortedAutomatically(err))
+	})
+	t.Run("moving a task into a bucket with move permissions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(26).Cols("move_allowed_user_ids").Update(&Bucket{MoveAllowedUserIDs: []int64{2}})
+		require.NoError(t, err)
+
+		task := &Task{
+			ID:        19,
+			Title:     "test",
+			ProjectID: 10,
+			BucketID:  26,
+		}
+		err = task.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrNotAllowedToMoveTaskIntoBucket(err))
+	})
+	t.Run("moving a task into a bucket with move permissions as team member", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(26).Cols("move_allowed_team_ids").Update(&Bucket{MoveAllowedTeamIDs: []int64{1}})
+		require.NoError(t, err)
+
+		task := &Task{
+			ID:        19,
+			Title:     "test",
+			ProjectID: 10,
+			BucketID:  26,
+		}
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":        19,
+			"bucket_id": 26,
+		}, false)
+	})
+	t.Run("marking a task as done which exceeds the limit of the done bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(3).Cols("limit").Update(&Bucket{Limit: 3})
+		require.NoError(t, err)
+
+		task := &Task{
+			ID:        1,
+			Title:     "test",
+			ProjectID: 1,
+			Done:      true,
+		}
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), task.BucketID)
+		events.AssertDispatched(t, &BucketLimitExceededEvent{})
+	})
 	t.Run("moving a repeating task to the done bucket", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
@@ -362,6 +691,7 @@ func TestTask_Update(t *testing.T) {
 		require.NoError(t, err)
 		assert.True(t, task.Done)
 		assert.Equal(t, int64(3), task.BucketID)
+		events.AssertDispatched(t, &TaskDoneEvent{})
 
 		db.AssertExists(t, "tasks", map[string]interface{}{
 			"id":        1,
@@ -449,6 +779,28 @@ func TestTask_Update(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, int64(3), task.Index)
 	})
+	t.Run("archiving a task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:         1,
+			Title:      "task #1",
+			ProjectID:  1,
+			IsArchived: true,
+		}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":          1,
+			"is_archived": true,
+			"done":        false,
+		}, false)
+	})
 
 	t.Run("reminders will be updated", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
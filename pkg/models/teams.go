@@ -249,7 +249,7 @@ func (t *Team) CreateNewTeam(s *xorm.Session, a web.Auth, firstUserShouldBeAdmin
 		return err
 	}
 
-	return events.Dispatch(&TeamCreatedEvent{
+	return events.DispatchOnCommit(s, &TeamCreatedEvent{
 		Team: t,
 		Doer: a,
 	})
@@ -388,7 +388,13 @@ func (t *Team) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
-	return events.Dispatch(&TeamDeletedEvent{
+	// Delete team <-> saved filters relations
+	_, err = s.Where("team_id = ?", t.ID).Delete(&SavedFilterTeam{})
+	if err != nil {
+		return
+	}
+
+	return events.DispatchOnCommit(s, &TeamDeletedEvent{
 		Team: t,
 		Doer: a,
 	})
@@ -0,0 +1,260 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TimeEntry holds the time a user spent working on a task
+type TimeEntry struct {
+	// The unique, numeric id of this time entry.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"timeentry"`
+	// The task this time entry belongs to.
+	TaskID int64 `xorm:"bigint not null INDEX" json:"task_id" param:"task"`
+	// The user who tracked this time entry.
+	UserID int64      `xorm:"bigint not null INDEX" json:"-"`
+	User   *user.User `xorm:"-" json:"user"`
+
+	// When the user started working on the task.
+	Start time.Time `xorm:"DATETIME not null 'start_time'" json:"start"`
+	// When the user stopped working on the task. If this is not set, the timer of this entry is still running.
+	End time.Time `xorm:"DATETIME null 'end_time'" json:"end"`
+	// An optional note about what the user did in this time.
+	Note string `xorm:"text null" json:"note"`
+	// The duration of this time entry in seconds. For running entries, this is the time since the entry was started.
+	Duration int64 `xorm:"-" json:"duration"`
+
+	// A timestamp when this time entry was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this time entry was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for time entries
+func (*TimeEntry) TableName() string {
+	return "time_entries"
+}
+
+func (te *TimeEntry) setDuration(now time.Time) {
+	end := te.End
+	if end.IsZero() {
+		end = now
+	}
+	te.Duration = int64(end.Sub(te.Start).Seconds())
+}
+
+func (te *TimeEntry) validatePeriod() error {
+	if te.Start.IsZero() {
+		return &ErrInvalidTimeEntryPeriod{}
+	}
+	if !te.End.IsZero() && !te.End.After(te.Start) {
+		return &ErrInvalidTimeEntryPeriod{}
+	}
+	return nil
+}
+
+func getTimeEntryByID(s *xorm.Session, id int64) (te *TimeEntry, err error) {
+	te = &TimeEntry{}
+	exists, err := s.
+		Where("id = ?", id).
+		Get(te)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrTimeEntryDoesNotExist{ID: id}
+	}
+	return te, nil
+}
+
+func addUsersToTimeEntries(s *xorm.Session, entries []*TimeEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(entries))
+	for _, te := range entries {
+		userIDs = append(userIDs, te.UserID)
+	}
+
+	users, err := user.GetUsersByIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, te := range entries {
+		te.User = users[te.UserID]
+		te.setDuration(now)
+	}
+
+	return nil
+}
+
+// Create creates a new time entry
+// @Summary Add a time entry to a task
+// @Description Adds a time entry for the current user to a task. Use the timer endpoints to track time while working on a task.
+// @tags time tracking
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param entry body models.TimeEntry true "The time entry"
+// @Success 201 {object} models.TimeEntry "The created time entry."
+// @Failure 400 {object} web.HTTPError "Invalid time entry provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/time [put]
+func (te *TimeEntry) Create(s *xorm.Session, a web.Auth) (err error) {
+	te.ID = 0
+	if err := te.validatePeriod(); err != nil {
+		return err
+	}
+
+	// Running entries can only be created with the timer endpoints
+	if te.End.IsZero() {
+		return &ErrInvalidTimeEntryPeriod{}
+	}
+
+	te.UserID = a.GetID()
+	_, err = s.Insert(te)
+	if err != nil {
+		return err
+	}
+
+	return addUsersToTimeEntries(s, []*TimeEntry{te})
+}
+
+// ReadAll returns all time entries of a task
+// @Summary Get all time entries of a task
+// @Description Returns the time entries of all users for a task, sorted by the time they were started.
+// @tags time tracking
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.TimeEntry "The time entries."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/time [get]
+func (te *TimeEntry) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	canRead, _, err := te.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	entries := []*TimeEntry{}
+	query := s.
+		Where("task_id = ?", te.TaskID).
+		OrderBy("start_time asc, id asc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&entries)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = addUsersToTimeEntries(s, entries)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	numberOfTotalItems, err = s.
+		Where("task_id = ?", te.TaskID).
+		Count(&TimeEntry{})
+	return entries, len(entries), numberOfTotalItems, err
+}
+
+// Update updates a time entry
+// @Summary Update a time entry
+// @Description Updates the start, end and note of a time entry. Only the user who tracked the entry can change it.
+// @tags time tracking
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param entryID path int true "Time entry ID"
+// @Param entry body models.TimeEntry true "The time entry"
+// @Success 200 {object} models.TimeEntry "The updated time entry."
+// @Failure 400 {object} web.HTTPError "Invalid time entry provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the time entry."
+// @Failure 404 {object} web.HTTPError "The time entry does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/time/{entryID} [post]
+func (te *TimeEntry) Update(s *xorm.Session, _ web.Auth) (err error) {
+	old, err := getTimeEntryByID(s, te.ID)
+	if err != nil {
+		return err
+	}
+
+	// A stopped entry can't be turned into a running one
+	if te.End.IsZero() && !old.End.IsZero() {
+		return &ErrInvalidTimeEntryPeriod{}
+	}
+
+	if err := te.validatePeriod(); err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("id = ?", te.ID).
+		Cols("start_time", "end_time", "note").
+		Update(te)
+	if err != nil {
+		return err
+	}
+
+	te.UserID = old.UserID
+	te.Created = old.Created
+	return addUsersToTimeEntries(s, []*TimeEntry{te})
+}
+
+// Delete removes a time entry
+// @Summary Delete a time entry
+// @Description Deletes a time entry. Only the user who tracked the entry can delete it.
+// @tags time tracking
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param entryID path int true "Time entry ID"
+// @Success 200 {object} models.Message "The time entry was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the time entry."
+// @Failure 404 {object} web.HTTPError "The time entry does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/time/{entryID} [delete]
+func (te *TimeEntry) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where(builder.Eq{"id": te.ID}).
+		Delete(&TimeEntry{})
+	return
+}
@@ -0,0 +1,70 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see the time entries of a task
+func (te *TimeEntry) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	t := &Task{ID: te.TaskID}
+	return t.CanRead(s, a)
+}
+
+// CanCreate checks if the user can track time on a task
+func (te *TimeEntry) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	t := &Task{ID: te.TaskID}
+	return t.CanWrite(s, a)
+}
+
+func (te *TimeEntry) canModify(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	old, err := getTimeEntryByID(s, te.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if old.TaskID != te.TaskID {
+		return false, &ErrTimeEntryDoesNotExist{ID: te.ID}
+	}
+
+	if old.UserID != a.GetID() {
+		return false, nil
+	}
+
+	t := &Task{ID: te.TaskID}
+	return t.CanWrite(s, a)
+}
+
+// CanUpdate checks if the user can change a time entry
+func (te *TimeEntry) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return te.canModify(s, a)
+}
+
+// CanDelete checks if the user can delete a time entry
+func (te *TimeEntry) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return te.canModify(s, a)
+}
@@ -0,0 +1,171 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TimeEntrySummary holds the tracked time of a task, a project or the current user
+type TimeEntrySummary struct {
+	// The task the summary belongs to, if it is the summary of a task.
+	TaskID int64 `json:"task_id,omitempty" param:"task"`
+	// The project the summary belongs to, if it is the summary of a project.
+	ProjectID int64 `json:"project_id,omitempty" param:"project"`
+
+	// The total tracked time in seconds, including running timers.
+	TotalDuration int64 `json:"total_duration"`
+	// The tracked time per task.
+	Tasks []*TimeEntryTaskSummary `json:"tasks"`
+	// The tracked time per user.
+	Users []*TimeEntryUserSummary `json:"users"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// TimeEntryTaskSummary holds the tracked time of one task
+type TimeEntryTaskSummary struct {
+	TaskID int64  `json:"task_id"`
+	Title  string `json:"title"`
+	// The tracked time in seconds.
+	Duration int64 `json:"duration"`
+}
+
+// TimeEntryUserSummary holds the time tracked by one user
+type TimeEntryUserSummary struct {
+	User *user.User `json:"user"`
+	// The tracked time in seconds.
+	Duration int64 `json:"duration"`
+}
+
+// CanRead checks if the user can see the summary
+func (tes *TimeEntrySummary) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	if tes.TaskID != 0 {
+		t := &Task{ID: tes.TaskID}
+		return t.CanRead(s, a)
+	}
+
+	if tes.ProjectID != 0 {
+		p := &Project{ID: tes.ProjectID}
+		return p.CanRead(s, a)
+	}
+
+	// Link shares don't track time
+	if _, is := a.(*LinkSharing); is {
+		return false, 0, nil
+	}
+
+	return true, int(RightRead), nil
+}
+
+// ReadOne returns the summary of all tracked time
+// @Summary Get a summary of the tracked time
+// @Description Returns the total tracked time and the tracked time per task and user. Depending on the route, this includes all time entries of a task, of all tasks in a project or of the current user.
+// @tags time tracking
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int false "Task ID"
+// @Param projectID path int false "Project ID"
+// @Success 200 {object} models.TimeEntrySummary "The summary of the tracked time."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task or project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/time/summary [get]
+// @Router /projects/{projectID}/time/summary [get]
+// @Router /user/time/summary [get]
+func (tes *TimeEntrySummary) ReadOne(s *xorm.Session, a web.Auth) (err error) {
+	var cond builder.Cond
+	switch {
+	case tes.TaskID != 0:
+		cond = builder.Eq{"task_id": tes.TaskID}
+	case tes.ProjectID != 0:
+		cond = builder.In("task_id", builder.Select("id").From("tasks").Where(builder.Eq{"project_id": tes.ProjectID}))
+	default:
+		cond = builder.Eq{"user_id": a.GetID()}
+	}
+
+	entries := []*TimeEntry{}
+	err = s.
+		Where(cond).
+		Find(&entries)
+	if err != nil {
+		return err
+	}
+
+	err = addUsersToTimeEntries(s, entries)
+	if err != nil {
+		return err
+	}
+
+	taskDurations := make(map[int64]int64)
+	userDurations := make(map[int64]*TimeEntryUserSummary)
+	taskIDs := []int64{}
+	tes.TotalDuration = 0
+	for _, te := range entries {
+		tes.TotalDuration += te.Duration
+
+		if _, has := taskDurations[te.TaskID]; !has {
+			taskIDs = append(taskIDs, te.TaskID)
+		}
+		taskDurations[te.TaskID] += te.Duration
+
+		if _, has := userDurations[te.UserID]; !has {
+			u := te.User
+			if u == nil {
+				u = &user.User{ID: te.UserID}
+			}
+			userDurations[te.UserID] = &TimeEntryUserSummary{User: u}
+		}
+		userDurations[te.UserID].Duration += te.Duration
+	}
+
+	tasks := make(map[int64]*Task, len(taskIDs))
+	if len(taskIDs) > 0 {
+		err = s.In("id", taskIDs).Find(&tasks)
+		if err != nil {
+			return err
+		}
+	}
+
+	tes.Tasks = make([]*TimeEntryTaskSummary, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		summary := &TimeEntryTaskSummary{TaskID: id, Duration: taskDurations[id]}
+		if t, has := tasks[id]; has {
+			summary.Title = t.Title
+		}
+		tes.Tasks = append(tes.Tasks, summary)
+	}
+	sort.Slice(tes.Tasks, func(i, j int) bool {
+		return tes.Tasks[i].TaskID < tes.Tasks[j].TaskID
+	})
+
+	tes.Users = make([]*TimeEntryUserSummary, 0, len(userDurations))
+	for _, summary := range userDurations {
+		tes.Users = append(tes.Users, summary)
+	}
+	sort.Slice(tes.Users, func(i, j int) bool {
+		return tes.Users[i].User.ID < tes.Users[j].User.ID
+	})
+
+	return nil
+}
@@ -0,0 +1,284 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeEntry_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		te := &TimeEntry{
+			TaskID: 1,
+			Start:  start,
+			End:    start.Add(2 * time.Hour),
+			Note:   "Lorem",
+		}
+		err := te.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7200), te.Duration)
+		assert.Equal(t, int64(1), te.User.ID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "time_entries", map[string]interface{}{
+			"id":      te.ID,
+			"task_id": 1,
+			"user_id": 1,
+			"note":    "Lorem",
+		}, false)
+	})
+	t.Run("end before start", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		te := &TimeEntry{
+			TaskID: 1,
+			Start:  start,
+			End:    start.Add(-time.Hour),
+		}
+		err := te.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTimeEntryPeriod(err))
+	})
+	t.Run("without end", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		te := &TimeEntry{
+			TaskID: 1,
+			Start:  start,
+		}
+		err := te.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTimeEntryPeriod(err))
+	})
+}
+
+func TestTimeEntry_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	te := &TimeEntry{TaskID: 1}
+	result, count, total, err := te.ReadAll(s, &user.User{ID: 1}, "", 0, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, int64(2), total)
+
+	entries := result.([]*TimeEntry)
+	assert.Equal(t, int64(1), entries[0].ID)
+	assert.Equal(t, int64(3600), entries[0].Duration)
+	assert.Equal(t, int64(1), entries[0].User.ID)
+	assert.Equal(t, int64(2), entries[1].ID)
+	assert.Equal(t, int64(1800), entries[1].Duration)
+}
+
+func TestTimeEntry_Update(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	te := &TimeEntry{
+		ID:     1,
+		TaskID: 1,
+		Start:  start,
+		End:    start.Add(30 * time.Minute),
+		Note:   "Changed",
+	}
+	err := te.Update(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1800), te.Duration)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertExists(t, "time_entries", map[string]interface{}{
+		"id":   1,
+		"note": "Changed",
+	}, false)
+}
+
+func TestTimeEntry_Rights(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("can update own entry", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		te := &TimeEntry{ID: 1, TaskID: 1}
+		can, err := te.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("can't update entries of other users", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		te := &TimeEntry{ID: 2, TaskID: 1}
+		can, err := te.CanDelete(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("entry of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		te := &TimeEntry{ID: 3, TaskID: 1}
+		_, err := te.CanUpdate(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTimeEntryDoesNotExist(err))
+	})
+	t.Run("link shares can't track time", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		te := &TimeEntry{TaskID: 1}
+		can, err := te.CanCreate(s, &LinkSharing{ID: 2, ProjectID: 1, Right: RightWrite})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestTimeEntry_Timer(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("start and stop", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		start := &TimeEntryStart{TaskID: 1, Note: "Working"}
+		err := start.Update(s, u)
+		require.NoError(t, err)
+		require.NotNil(t, start.TimeEntry)
+		assert.True(t, start.TimeEntry.End.IsZero())
+		assert.Equal(t, "Working", start.TimeEntry.Note)
+
+		stop := &TimeEntryStop{TaskID: 1}
+		err = stop.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, start.TimeEntry.ID, stop.TimeEntry.ID)
+		assert.False(t, stop.TimeEntry.End.IsZero())
+	})
+	t.Run("starting a timer stops the running one", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		first := &TimeEntryStart{TaskID: 1}
+		err := first.Update(s, u)
+		require.NoError(t, err)
+
+		second := &TimeEntryStart{TaskID: 3}
+		err = second.Update(s, u)
+		require.NoError(t, err)
+
+		running, err := getRunningTimeEntries(s, u.ID)
+		require.NoError(t, err)
+		require.Len(t, running, 1)
+		assert.Equal(t, second.TimeEntry.ID, running[0].ID)
+	})
+	t.Run("starting a running timer again", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		first := &TimeEntryStart{TaskID: 1}
+		err := first.Update(s, u)
+		require.NoError(t, err)
+
+		second := &TimeEntryStart{TaskID: 1}
+		err = second.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, first.TimeEntry.ID, second.TimeEntry.ID)
+	})
+	t.Run("stop without running timer", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		stop := &TimeEntryStop{TaskID: 1}
+		err := stop.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrNoRunningTimeEntry(err))
+	})
+}
+
+func TestTimeEntrySummary_ReadOne(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		summary := &TimeEntrySummary{TaskID: 1}
+		err := summary.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5400), summary.TotalDuration)
+		require.Len(t, summary.Users, 2)
+		assert.Equal(t, int64(3600), summary.Users[0].Duration)
+		assert.Equal(t, int64(1800), summary.Users[1].Duration)
+	})
+	t.Run("project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		summary := &TimeEntrySummary{ProjectID: 1}
+		err := summary.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6300), summary.TotalDuration)
+		require.Len(t, summary.Tasks, 2)
+		assert.Equal(t, int64(1), summary.Tasks[0].TaskID)
+		assert.Equal(t, int64(5400), summary.Tasks[0].Duration)
+		assert.Equal(t, int64(2), summary.Tasks[1].TaskID)
+		assert.Equal(t, int64(900), summary.Tasks[1].Duration)
+	})
+	t.Run("user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		summary := &TimeEntrySummary{}
+		err := summary.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4500), summary.TotalDuration)
+		require.Len(t, summary.Users, 1)
+	})
+}
@@ -0,0 +1,161 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TimeEntryStart starts a timer on a task
+type TimeEntryStart struct {
+	// The task to start the timer on.
+	TaskID int64 `json:"-" param:"task"`
+	// An optional note about what the user is working on.
+	Note string `json:"note"`
+
+	// The running time entry.
+	TimeEntry *TimeEntry `json:"time_entry"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// TimeEntryStop stops the running timer on a task
+type TimeEntryStop struct {
+	// The task to stop the timer of.
+	TaskID int64 `json:"-" param:"task"`
+
+	// The stopped time entry.
+	TimeEntry *TimeEntry `json:"time_entry"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+func canTrackTimeOnTask(s *xorm.Session, a web.Auth, taskID int64) (bool, error) {
+	te := &TimeEntry{TaskID: taskID}
+	return te.CanCreate(s, a)
+}
+
+// CanUpdate checks if the user can start a timer on the task
+func (ts *TimeEntryStart) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return canTrackTimeOnTask(s, a, ts.TaskID)
+}
+
+// CanUpdate checks if the user can stop a timer on the task
+func (ts *TimeEntryStop) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return canTrackTimeOnTask(s, a, ts.TaskID)
+}
+
+func getRunningTimeEntries(s *xorm.Session, userID int64) (entries []*TimeEntry, err error) {
+	entries = []*TimeEntry{}
+	err = s.
+		Where("user_id = ? AND end_time IS NULL", userID).
+		Find(&entries)
+	return
+}
+
+// Update starts a new timer
+// @Summary Start a timer on a task
+// @Description Starts tracking time on a task for the current user. A user can only have one running timer, if another timer is running it will be stopped. If a timer is already running on this task, it is returned unchanged.
+// @tags time tracking
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param timer body models.TimeEntryStart true "An optional note for the new time entry."
+// @Success 200 {object} models.TimeEntryStart "The running time entry."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/time/start [post]
+func (ts *TimeEntryStart) Update(s *xorm.Session, a web.Auth) (err error) {
+	running, err := getRunningTimeEntries(s, a.GetID())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, te := range running {
+		if te.TaskID == ts.TaskID {
+			ts.TimeEntry = te
+			return addUsersToTimeEntries(s, []*TimeEntry{te})
+		}
+
+		te.End = now
+		_, err = s.
+			Where("id = ?", te.ID).
+			Cols("end_time").
+			Update(te)
+		if err != nil {
+			return err
+		}
+	}
+
+	ts.TimeEntry = &TimeEntry{
+		TaskID: ts.TaskID,
+		UserID: a.GetID(),
+		Start:  now,
+		Note:   ts.Note,
+	}
+	_, err = s.Insert(ts.TimeEntry)
+	if err != nil {
+		return err
+	}
+
+	return addUsersToTimeEntries(s, []*TimeEntry{ts.TimeEntry})
+}
+
+// Update stops the running timer
+// @Summary Stop the timer on a task
+// @Description Stops the running timer of the current user on a task.
+// @tags time tracking
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Success 200 {object} models.TimeEntryStop "The stopped time entry."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "There is no running timer on this task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/time/stop [post]
+func (ts *TimeEntryStop) Update(s *xorm.Session, a web.Auth) (err error) {
+	te := &TimeEntry{}
+	exists, err := s.
+		Where("user_id = ? AND task_id = ? AND end_time IS NULL", a.GetID(), ts.TaskID).
+		Get(te)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &ErrNoRunningTimeEntry{TaskID: ts.TaskID, UserID: a.GetID()}
+	}
+
+	te.End = time.Now()
+	_, err = s.
+		Where("id = ?", te.ID).
+		Cols("end_time").
+		Update(te)
+	if err != nil {
+		return err
+	}
+
+	ts.TimeEntry = te
+	return addUsersToTimeEntries(s, []*TimeEntry{te})
+}
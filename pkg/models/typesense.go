@@ -19,6 +19,7 @@ package models
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"code.vikunja.io/api/pkg/config"
@@ -50,6 +51,49 @@ func InitTypesense() {
 		typesense.WithAPIKey(config.TypesenseAPIKey.GetString()))
 }
 
+// The fields which were added to the tasks collection after it was first created. Collections created by an older
+// version do not have them and need to be recreated with a full reindex.
+var typesenseTaskFieldsAddedLater = []string{"is_archived", "pinned_position"}
+
+var (
+	typesenseTaskCollectionCheckLock sync.Mutex
+	typesenseTaskCollectionUpToDate  bool
+	typesenseTaskCollectionCheckedAt time.Time
+)
+
+// typesenseTaskCollectionIsUpToDate checks if the tasks collection in Typesense has all fields the searcher uses.
+// As long as it doesn't, all searches use the database instead. A missing field is checked again every few minutes
+// so that the collection is used again after it was reindexed.
+func typesenseTaskCollectionIsUpToDate() bool {
+	typesenseTaskCollectionCheckLock.Lock()
+	defer typesenseTaskCollectionCheckLock.Unlock()
+
+	if typesenseTaskCollectionUpToDate || time.Since(typesenseTaskCollectionCheckedAt) < 5*time.Minute {
+		return typesenseTaskCollectionUpToDate
+	}
+	typesenseTaskCollectionCheckedAt = time.Now()
+
+	collection, err := typesenseClient.Collection("tasks").Retrieve(context.Background())
+	if err != nil {
+		log.Errorf("[Typesense] Could not check the tasks collection, using the database for searching: %s", err)
+		return false
+	}
+
+	fields := make(map[string]bool, len(collection.Fields))
+	for _, f := range collection.Fields {
+		fields[f.Name] = true
+	}
+	for _, name := range typesenseTaskFieldsAddedLater {
+		if !fields[name] {
+			log.Warningf("[Typesense] The tasks collection does not have the field %s yet, using the database for searching. Run \"vikunja index\" to update it.", name)
+			return false
+		}
+	}
+
+	typesenseTaskCollectionUpToDate = true
+	return true
+}
+
 func CreateTypesenseCollections() error {
 	taskSchema := &api.CollectionSchema{
 		Name:               "tasks",
@@ -74,6 +118,10 @@ func CreateTypesenseCollections() error {
 				Name: "done",
 				Type: "bool",
 			},
+			{
+				Name: "is_archived",
+				Type: "bool",
+			},
 			{
 				Name:     "done_at",
 				Type:     "int64", // unix timestamp
@@ -115,6 +163,10 @@ func CreateTypesenseCollections() error {
 				Type: "string",
 				Sort: pointer.True(),
 			},
+			{
+				Name: "estimate",
+				Type: "int64",
+			},
 			{
 				Name: "percent_done",
 				Type: "float",
@@ -149,6 +201,14 @@ func CreateTypesenseCollections() error {
 				Name: "bucket_id",
 				Type: "int64",
 			},
+			{
+				Name: "status_id",
+				Type: "int64",
+			},
+			{
+				Name: "milestone_id",
+				Type: "int64",
+			},
 			{
 				Name: "position",
 				Type: "float",
@@ -157,6 +217,11 @@ func CreateTypesenseCollections() error {
 				Name: "kanban_position",
 				Type: "float",
 			},
+			{
+				Name:     "pinned_position",
+				Type:     "float",
+				Optional: pointer.True(),
+			},
 			{
 				Name: "created_by_id",
 				Type: "int64",
@@ -191,6 +256,11 @@ func CreateTypesenseCollections() error {
 				Type:     "object[]", // TODO
 				Optional: pointer.True(),
 			},
+			{
+				Name:     "attachment_contents",
+				Type:     "string[]",
+				Optional: pointer.True(),
+			},
 		},
 	}
 
@@ -274,6 +344,16 @@ func getTypesenseTaskForTask(s *xorm.Session, task *Task, projectsCache map[int6
 		return nil, fmt.Errorf("could not fetch comments for task %d: %s", task.ID, err.Error())
 	}
 
+	ttask.AttachmentContents = []string{}
+	err = s.
+		Table("task_attachments").
+		Where("task_id = ? AND text_content IS NOT NULL AND text_content != ''", task.ID).
+		Cols("text_content").
+		Find(&ttask.AttachmentContents)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch attachment contents for task %d: %s", task.ID, err.Error())
+	}
+
 	return
 }
 
@@ -379,6 +459,7 @@ func indexDummyTask() (err error) {
 				},
 			},
 		},
+		AttachmentContents: []string{"Lorem Ipsum Dummy"},
 	}
 
 	_, err = typesenseClient.Collection("tasks").
@@ -399,6 +480,7 @@ type typesenseTask struct {
 	Title                  string      `json:"title"`
 	Description            string      `json:"description"`
 	Done                   bool        `json:"done"`
+	IsArchived             bool        `json:"is_archived"`
 	DoneAt                 *int64      `json:"done_at"`
 	DueDate                *int64      `json:"due_date"`
 	ProjectID              int64       `json:"project_id"`
@@ -409,6 +491,7 @@ type typesenseTask struct {
 	EndDate                *int64      `json:"end_date"`
 	HexColor               string      `json:"hex_color"`
 	PercentDone            float64     `json:"percent_done"`
+	Estimate               int64       `json:"estimate"`
 	Identifier             string      `json:"identifier"`
 	Index                  int64       `json:"index"`
 	UID                    string      `json:"uid"`
@@ -416,15 +499,19 @@ type typesenseTask struct {
 	Created                int64       `json:"created"`
 	Updated                int64       `json:"updated"`
 	BucketID               int64       `json:"bucket_id"`
+	StatusID               int64       `json:"status_id"`
+	MilestoneID            int64       `json:"milestone_id"`
 	Position               float64     `json:"position"`
 	KanbanPosition         float64     `json:"kanban_position"`
+	PinnedPosition         *float64    `json:"pinned_position"`
 	CreatedByID            int64       `json:"created_by_id"`
 	Reminders              interface{} `json:"reminders"`
 	Assignees              interface{} `json:"assignees"`
 	Labels                 interface{} `json:"labels"`
 	//RelatedTasks           interface{} `json:"related_tasks"` // TODO
-	Attachments interface{} `json:"attachments"`
-	Comments    interface{} `json:"comments"`
+	Attachments        interface{} `json:"attachments"`
+	Comments           interface{} `json:"comments"`
+	AttachmentContents []string    `json:"attachment_contents"`
 }
 
 func convertTaskToTypesenseTask(task *Task) *typesenseTask {
@@ -433,6 +520,7 @@ func convertTaskToTypesenseTask(task *Task) *typesenseTask {
 		Title:                  task.Title,
 		Description:            task.Description,
 		Done:                   task.Done,
+		IsArchived:             task.IsArchived,
 		DoneAt:                 pointer.Int64(task.DoneAt.UTC().Unix()),
 		DueDate:                pointer.Int64(task.DueDate.UTC().Unix()),
 		ProjectID:              task.ProjectID,
@@ -443,6 +531,7 @@ func convertTaskToTypesenseTask(task *Task) *typesenseTask {
 		EndDate:                pointer.Int64(task.EndDate.UTC().Unix()),
 		HexColor:               task.HexColor,
 		PercentDone:            task.PercentDone,
+		Estimate:               task.Estimate,
 		Identifier:             task.Identifier,
 		Index:                  task.Index,
 		UID:                    task.UID,
@@ -450,6 +539,8 @@ func convertTaskToTypesenseTask(task *Task) *typesenseTask {
 		Created:                task.Created.UTC().Unix(),
 		Updated:                task.Updated.UTC().Unix(),
 		BucketID:               task.BucketID,
+		StatusID:               task.StatusID,
+		MilestoneID:            task.MilestoneID,
 		Position:               task.Position,
 		KanbanPosition:         task.KanbanPosition,
 		CreatedByID:            task.CreatedByID,
@@ -472,6 +563,9 @@ func convertTaskToTypesenseTask(task *Task) *typesenseTask {
 	if task.EndDate.IsZero() {
 		tt.EndDate = nil
 	}
+	if task.IsPinned {
+		tt.PinnedPosition = &task.PinnedPosition
+	}
 
 	return tt
 }
@@ -66,6 +66,40 @@ func SetupTests() {
 		"favorites",
 		"api_tokens",
 		"reactions",
+		"bucket_templates",
+		"bucket_collapsed_states",
+		"task_bucket_transitions",
+		"time_entries",
+		"custom_fields",
+		"task_custom_field_values",
+		"task_checklist_items",
+		"task_history",
+		"task_comment_revisions",
+		"task_watchers",
+		"task_user_positions",
+		"task_sla_breaches",
+		"task_votes",
+		"project_templates",
+		"project_statuses",
+		"milestones",
+		"project_roles",
+		"project_user_positions",
+		"project_email_addresses",
+		"saved_filter_users",
+		"saved_filter_teams",
+		"saved_filter_task_matches",
+		"task_views",
+		"project_label_rules",
+		"project_automation_rules",
+		"project_scheduled_automations",
+		"entity_tombstones",
+		"project_integrations",
+		"task_mentions",
+		"webhooks",
+		"webhook_deliveries",
+		"project_inbound_webhooks",
+		"project_events",
+		"idempotency_keys",
 	)
 	if err != nil {
 		log.Fatal(err)
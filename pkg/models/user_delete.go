@@ -149,6 +149,46 @@ func DeleteUser(s *xorm.Session, u *user.User) (err error) {
 		}
 	}
 
+	_, err = s.Where("user_id = ?", u.ID).Delete(&TimeEntry{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&TaskUserPosition{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&ProjectUserPosition{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("created_by_id = ?", u.ID).Delete(&ProjectEmailAddress{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&SavedFilterUser{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&TaskVote{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&TaskView{})
+	if err != nil {
+		return err
+	}
+
+	err = notifications.DeleteAllPushSubscriptions(s, u.ID)
+	if err != nil {
+		return err
+	}
+
 	_, err = s.Where("id = ?", u.ID).Delete(&user.User{})
 	if err != nil {
 		return err
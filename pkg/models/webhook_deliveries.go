@@ -0,0 +1,360 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// WebhookDeliveryStatus is the state a webhook delivery is in
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPending is used for deliveries which were not attempted yet.
+	WebhookDeliveryStatusPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryStatusSuccess is used for deliveries where the target responded with a 2xx status code.
+	WebhookDeliveryStatusSuccess WebhookDeliveryStatus = "success"
+	// WebhookDeliveryStatusFailed is used for deliveries which failed and will be retried.
+	WebhookDeliveryStatusFailed WebhookDeliveryStatus = "failed"
+	// WebhookDeliveryStatusDead is used for deliveries which failed and will not be retried anymore.
+	WebhookDeliveryStatusDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is a single attempt to send the payload of an event to a webhook target, including all of its retries.
+type WebhookDelivery struct {
+	// The unique, numeric id of this delivery.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"delivery"`
+	// The webhook this delivery belongs to.
+	WebhookID int64 `xorm:"bigint not null index" json:"webhook_id" param:"webhook"`
+	// The project of the webhook, only used to check permissions.
+	ProjectID int64 `xorm:"-" json:"-" param:"project"`
+	// The name of the event which triggered this delivery.
+	EventName string `xorm:"varchar(250) not null" json:"event_name"`
+	// The json payload which is sent to the webhook target.
+	Payload string `xorm:"longtext not null" json:"payload"`
+	// The state of this delivery. Can be `pending`, `success`, `failed` or `dead`. Failed deliveries are retried
+	// with an exponential backoff until the configured maximum number of retries is reached. They are then marked as dead.
+	Status WebhookDeliveryStatus `xorm:"varchar(20) not null index" json:"status"`
+	// How many times the payload was sent to the webhook target.
+	Attempts int64 `xorm:"bigint not null default 0" json:"attempts"`
+	// The http status code of the last response of the webhook target. 0 if the target could not be reached.
+	ResponseStatusCode int `xorm:"int not null default 0" json:"response_status_code"`
+	// The error of the last attempt, if it failed.
+	LastError string `xorm:"text null" json:"last_error"`
+	// If this delivery was created by manually redelivering another delivery, this is the id of that other delivery.
+	RedeliveryOf int64 `xorm:"bigint null" json:"redelivery_of"`
+	// When the next retry of this delivery will happen. Only set for failed deliveries.
+	NextAttemptAt time.Time `xorm:"DATETIME null index" json:"next_attempt_at"`
+	// When the payload was successfully delivered.
+	DeliveredAt time.Time `xorm:"DATETIME null" json:"delivered_at"`
+
+	// A timestamp when this delivery was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this delivery was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for webhook deliveries
+func (wd *WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// getWebhookRetryDelay returns how long to wait before retrying a delivery which failed for the nth time.
+func getWebhookRetryDelay(attempts int64) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 16 {
+		attempts = 16
+	}
+
+	return time.Duration(config.WebhooksRetryDelaySeconds.GetInt64()) * time.Second * time.Duration(int64(1)<<(attempts-1))
+}
+
+// createWebhookDelivery persists a new delivery of a payload to a webhook and immediately attempts to send it.
+func createWebhookDelivery(s *xorm.Session, w *Webhook, eventName string, payload []byte, redeliveryOf int64) (*WebhookDelivery, error) {
+	d := &WebhookDelivery{
+		WebhookID:    w.ID,
+		EventName:    eventName,
+		Payload:      string(payload),
+		Status:       WebhookDeliveryStatusPending,
+		RedeliveryOf: redeliveryOf,
+	}
+	_, err := s.Insert(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, attemptWebhookDelivery(s, w, d, time.Now())
+}
+
+// attemptWebhookDelivery sends the payload of a delivery to the webhook and records the outcome.
+// Failing to reach the webhook target is not returned as an error, it is only recorded with the delivery.
+func attemptWebhookDelivery(s *xorm.Session, w *Webhook, d *WebhookDelivery, now time.Time) (err error) {
+	d.Attempts++
+	statusCode, sendErr := w.sendWebhookRequest(d.EventName, []byte(d.Payload))
+	d.ResponseStatusCode = statusCode
+
+	if sendErr == nil {
+		d.Status = WebhookDeliveryStatusSuccess
+		d.LastError = ""
+		d.DeliveredAt = now
+		d.NextAttemptAt = time.Time{}
+	} else {
+		log.Debugf("Could not deliver payload of event %s to webhook %d: %s", d.EventName, w.ID, sendErr)
+		d.LastError = sendErr.Error()
+		d.Status = WebhookDeliveryStatusFailed
+		d.NextAttemptAt = now.Add(getWebhookRetryDelay(d.Attempts))
+		if d.Attempts > config.WebhooksMaxRetries.GetInt64() {
+			d.Status = WebhookDeliveryStatusDead
+			d.NextAttemptAt = time.Time{}
+		}
+	}
+
+	_, err = s.
+		Where("id = ?", d.ID).
+		Cols("status", "attempts", "response_status_code", "last_error", "next_attempt_at", "delivered_at").
+		Update(d)
+	return
+}
+
+// retryWebhookDeliveries attempts all failed deliveries which are due for a retry.
+func retryWebhookDeliveries(s *xorm.Session, now time.Time) (retried int, err error) {
+	deliveries := []*WebhookDelivery{}
+	err = s.
+		Where("status = ? AND next_attempt_at <= ?", WebhookDeliveryStatusFailed, now).
+		OrderBy("next_attempt_at asc").
+		Find(&deliveries)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	webhookIDs := make([]int64, 0, len(deliveries))
+	for _, d := range deliveries {
+		webhookIDs = append(webhookIDs, d.WebhookID)
+	}
+
+	webhooks := make(map[int64]*Webhook, len(webhookIDs))
+	err = s.In("id", webhookIDs).Find(&webhooks)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range deliveries {
+		w, has := webhooks[d.WebhookID]
+		if !has {
+			d.Status = WebhookDeliveryStatusDead
+			_, err = s.Where("id = ?", d.ID).Cols("status").Update(d)
+			if err != nil {
+				return retried, err
+			}
+			continue
+		}
+
+		err = attemptWebhookDelivery(s, w, d, now)
+		if err != nil {
+			return retried, err
+		}
+		retried++
+	}
+
+	return retried, nil
+}
+
+// pruneWebhookDeliveries removes all deliveries older than the configured retention which won't be retried anymore.
+func pruneWebhookDeliveries(s *xorm.Session, now time.Time) (int64, error) {
+	days := config.WebhooksDeliveryRetentionDays.GetInt()
+	if days <= 0 {
+		return 0, nil
+	}
+
+	return s.
+		Where("created < ? AND status != ?", now.Add(-time.Duration(days)*24*time.Hour), WebhookDeliveryStatusFailed).
+		Delete(&WebhookDelivery{})
+}
+
+// RegisterWebhookDeliveryCron registers a cron function which retries failed webhook deliveries and removes old ones
+// from the delivery log.
+func RegisterWebhookDeliveryCron() {
+	if !config.WebhooksEnabled.GetBool() {
+		return
+	}
+
+	const logPrefix = "[Webhook Delivery Cron] "
+
+	err := cron.Schedule("* * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		now := time.Now()
+		retried, err := retryWebhookDeliveries(s, now)
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not retry webhook deliveries: %s", err)
+			return
+		}
+
+		pruned, err := pruneWebhookDeliveries(s, now)
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not remove old webhook deliveries: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit webhook deliveries: %s", err)
+			return
+		}
+
+		if retried > 0 || pruned > 0 {
+			log.Debugf(logPrefix+"Retried %d and removed %d webhook deliveries", retried, pruned)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register webhook delivery cron: %s", err)
+	}
+}
+
+// getWebhookOfProject returns the webhook with the given id if it belongs to the project.
+func getWebhookOfProject(s *xorm.Session, webhookID, projectID int64) (*Webhook, error) {
+	w := &Webhook{}
+	exists, err := s.Where("id = ? AND project_id = ?", webhookID, projectID).Get(w)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrWebhookDoesNotExist{WebhookID: webhookID}
+	}
+	return w, nil
+}
+
+// ReadAll returns the delivery log of a webhook
+// @Summary Get the delivery log of a webhook
+// @Description Returns all deliveries of a webhook target, newest first. Use the search parameter to only get deliveries with a specific status.
+// @tags webhooks
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param s query string false "Only return deliveries with this status. Can be `pending`, `success`, `failed` or `dead`."
+// @Param id path int true "Project ID"
+// @Param webhookID path int true "Webhook ID"
+// @Success 200 {array} models.WebhookDelivery "The deliveries of the webhook target"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The webhook target does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/webhooks/{webhookID}/deliveries [get]
+func (wd *WebhookDelivery) ReadAll(s *xorm.Session, a web.Auth, search string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	w := &Webhook{ID: wd.WebhookID, ProjectID: wd.ProjectID}
+	can, err := w.canDoWebhook(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	_, err = getWebhookOfProject(s, wd.WebhookID, wd.ProjectID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	cond := builder.Eq{"webhook_id": wd.WebhookID}
+	if search != "" {
+		cond["status"] = search
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	query := s.Where(cond).OrderBy("id desc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+
+	deliveries := []*WebhookDelivery{}
+	err = query.Find(&deliveries)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	total, err := s.Where(cond).Count(&WebhookDelivery{})
+	return deliveries, len(deliveries), total, err
+}
+
+// WebhookRedelivery sends the payload of an existing webhook delivery again
+type WebhookRedelivery struct {
+	ProjectID  int64 `json:"-" param:"project"`
+	WebhookID  int64 `json:"-" param:"webhook"`
+	DeliveryID int64 `json:"-" param:"delivery"`
+
+	// The newly created delivery.
+	Delivery *WebhookDelivery `json:"delivery"`
+
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+// CanCreate checks if a user can redeliver a webhook payload
+func (wr *WebhookRedelivery) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	w := &Webhook{ID: wr.WebhookID, ProjectID: wr.ProjectID}
+	return w.canDoWebhook(s, a)
+}
+
+// Create sends the payload of a delivery again
+// @Summary Redeliver a webhook payload
+// @Description Sends the payload of an existing delivery to the webhook target again. This creates a new delivery which is retried like every other delivery if it fails. It can be used for dead deliveries as well.
+// @tags webhooks
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param webhookID path int true "Webhook ID"
+// @Param deliveryID path int true "Delivery ID"
+// @Success 201 {object} models.WebhookRedelivery "The new delivery."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The webhook target or delivery does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver [put]
+func (wr *WebhookRedelivery) Create(s *xorm.Session, _ web.Auth) (err error) {
+	w, err := getWebhookOfProject(s, wr.WebhookID, wr.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	original := &WebhookDelivery{}
+	exists, err := s.Where("id = ? AND webhook_id = ?", wr.DeliveryID, w.ID).Get(original)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &ErrWebhookDeliveryDoesNotExist{DeliveryID: wr.DeliveryID, WebhookID: w.ID}
+	}
+
+	wr.Delivery, err = createWebhookDelivery(s, w, original.EventName, []byte(original.Payload), original.ID)
+	return
+}
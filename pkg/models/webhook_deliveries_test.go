@@ -0,0 +1,268 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebhookTestServer(t *testing.T, status int) (*httptest.Server, *int) {
+	received := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestCreateWebhookDelivery(t *testing.T) {
+	t.Run("successful delivery", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		server, received := newWebhookTestServer(t, http.StatusOK)
+		w := &Webhook{ID: 1, TargetURL: server.URL}
+
+		d, err := createWebhookDelivery(s, w, "task.created", []byte(`{}`), 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, *received)
+		assert.Equal(t, WebhookDeliveryStatusSuccess, d.Status)
+
+		db.AssertExists(t, "webhook_deliveries", map[string]interface{}{
+			"id":                   d.ID,
+			"webhook_id":           1,
+			"status":               WebhookDeliveryStatusSuccess,
+			"attempts":             1,
+			"response_status_code": http.StatusOK,
+		}, false)
+	})
+	t.Run("failed delivery is scheduled for a retry", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		server, _ := newWebhookTestServer(t, http.StatusInternalServerError)
+		w := &Webhook{ID: 1, TargetURL: server.URL}
+
+		before := time.Now()
+		d, err := createWebhookDelivery(s, w, "task.created", []byte(`{}`), 0)
+		require.NoError(t, err)
+		assert.Equal(t, WebhookDeliveryStatusFailed, d.Status)
+		assert.Equal(t, http.StatusInternalServerError, d.ResponseStatusCode)
+		assert.NotEmpty(t, d.LastError)
+		assert.True(t, d.NextAttemptAt.After(before.Add(time.Duration(config.WebhooksRetryDelaySeconds.GetInt64())*time.Second-time.Second)))
+	})
+	t.Run("failed delivery without retries is dead", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.WebhooksMaxRetries.Set(0)
+		defer config.WebhooksMaxRetries.Set(5)
+
+		server, _ := newWebhookTestServer(t, http.StatusBadGateway)
+		w := &Webhook{ID: 1, TargetURL: server.URL}
+
+		d, err := createWebhookDelivery(s, w, "task.created", []byte(`{}`), 0)
+		require.NoError(t, err)
+		assert.Equal(t, WebhookDeliveryStatusDead, d.Status)
+		assert.True(t, d.NextAttemptAt.IsZero())
+	})
+}
+
+func TestGetWebhookRetryDelay(t *testing.T) {
+	base := time.Duration(config.WebhooksRetryDelaySeconds.GetInt64()) * time.Second
+	assert.Equal(t, base, getWebhookRetryDelay(1))
+	assert.Equal(t, base*2, getWebhookRetryDelay(2))
+	assert.Equal(t, base*8, getWebhookRetryDelay(4))
+}
+
+func TestRetryWebhookDeliveries(t *testing.T) {
+	t.Run("due delivery", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		server, received := newWebhookTestServer(t, http.StatusOK)
+		_, err := s.Where("id = ?", 1).Cols("target_url").Update(&Webhook{TargetURL: server.URL})
+		require.NoError(t, err)
+
+		retried, err := retryWebhookDeliveries(s, time.Date(2018, 12, 1, 16, 20, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 1, retried)
+		assert.Equal(t, 1, *received)
+
+		db.AssertExists(t, "webhook_deliveries", map[string]interface{}{
+			"id":       2,
+			"status":   WebhookDeliveryStatusSuccess,
+			"attempts": 3,
+		}, false)
+	})
+	t.Run("not due yet", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		retried, err := retryWebhookDeliveries(s, time.Date(2018, 12, 1, 16, 14, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 0, retried)
+	})
+	t.Run("deleted webhook", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 1).Delete(&Webhook{})
+		require.NoError(t, err)
+
+		retried, err := retryWebhookDeliveries(s, time.Date(2018, 12, 1, 16, 20, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 0, retried)
+		db.AssertExists(t, "webhook_deliveries", map[string]interface{}{
+			"id":     2,
+			"status": WebhookDeliveryStatusDead,
+		}, false)
+	})
+}
+
+func TestPruneWebhookDeliveries(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	pruned, err := pruneWebhookDeliveries(s, time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pruned)
+
+	db.AssertMissing(t, "webhook_deliveries", map[string]interface{}{"id": 1})
+	db.AssertMissing(t, "webhook_deliveries", map[string]interface{}{"id": 3})
+	db.AssertExists(t, "webhook_deliveries", map[string]interface{}{"id": 2}, false)
+}
+
+func TestWebhookDelivery_ReadAll(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		wd := &WebhookDelivery{WebhookID: 1, ProjectID: 1}
+		result, count, total, err := wd.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Equal(t, int64(3), total)
+		deliveries := result.([]*WebhookDelivery)
+		assert.Equal(t, int64(3), deliveries[0].ID)
+	})
+	t.Run("filtered by status", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		wd := &WebhookDelivery{WebhookID: 1, ProjectID: 1}
+		result, count, _, err := wd.ReadAll(s, u, string(WebhookDeliveryStatusDead), 0, 50)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, int64(3), result.([]*WebhookDelivery)[0].ID)
+	})
+	t.Run("webhook of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		wd := &WebhookDelivery{WebhookID: 1, ProjectID: 10}
+		_, _, _, err := wd.ReadAll(s, u, "", 0, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrWebhookDoesNotExist(err))
+	})
+	t.Run("no rights", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		wd := &WebhookDelivery{WebhookID: 1, ProjectID: 1}
+		_, _, _, err := wd.ReadAll(s, &user.User{ID: 2}, "", 0, 50)
+		require.Error(t, err)
+		assert.IsType(t, ErrGenericForbidden{}, err)
+	})
+}
+
+func TestWebhookRedelivery_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		server, received := newWebhookTestServer(t, http.StatusOK)
+		_, err := s.Where("id = ?", 1).Cols("target_url").Update(&Webhook{TargetURL: server.URL})
+		require.NoError(t, err)
+
+		wr := &WebhookRedelivery{ProjectID: 1, WebhookID: 1, DeliveryID: 3}
+		can, err := wr.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = wr.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, 1, *received)
+
+		db.AssertExists(t, "webhook_deliveries", map[string]interface{}{
+			"id":            wr.Delivery.ID,
+			"webhook_id":    1,
+			"status":        WebhookDeliveryStatusSuccess,
+			"redelivery_of": 3,
+		}, false)
+		db.AssertExists(t, "webhook_deliveries", map[string]interface{}{
+			"id":     3,
+			"status": WebhookDeliveryStatusDead,
+		}, false)
+	})
+	t.Run("nonexisting delivery", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		wr := &WebhookRedelivery{ProjectID: 1, WebhookID: 1, DeliveryID: 9999}
+		err := wr.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrWebhookDeliveryDoesNotExist(err))
+	})
+	t.Run("no rights", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		wr := &WebhookRedelivery{ProjectID: 1, WebhookID: 1, DeliveryID: 3}
+		can, err := wr.CanCreate(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -0,0 +1,71 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"xorm.io/xorm"
+)
+
+// validateFilter checks the filter query of a webhook can be parsed
+func (w *Webhook) validateFilter() error {
+	if w.Filter == "" {
+		return nil
+	}
+
+	_, err := getTaskFiltersFromFilterString(w.Filter, "")
+	return err
+}
+
+// listensTo checks if the webhook should be fired for an event
+func (w *Webhook) listensTo(eventName string) bool {
+	for _, e := range w.Events {
+		if e == eventName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesFilter checks if the task of an event matches the filter of the webhook.
+// Events without a task and events about tasks which do not exist anymore always match, because there is nothing
+// the filter could be checked against.
+func (w *Webhook) matchesFilter(s *xorm.Session, event map[string]interface{}) (bool, error) {
+	if w.Filter == "" {
+		return true, nil
+	}
+
+	task, has := event["task"].(map[string]interface{})
+	if !has {
+		return true, nil
+	}
+	rawTaskID, has := task["id"]
+	if !has {
+		return true, nil
+	}
+
+	taskID := getIDAsInt64(rawTaskID)
+	exists, err := s.Where("id = ?", taskID).Exist(&Task{})
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return true, nil
+	}
+
+	return taskMatchesFilter(s, taskID, w.Filter)
+}
@@ -0,0 +1,108 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_validateFilter(t *testing.T) {
+	t.Run("no filter", func(t *testing.T) {
+		w := &Webhook{}
+		require.NoError(t, w.validateFilter())
+	})
+	t.Run("valid filter", func(t *testing.T) {
+		w := &Webhook{Filter: "labels in 4 && bucket_id = 1"}
+		require.NoError(t, w.validateFilter())
+	})
+	t.Run("invalid filter", func(t *testing.T) {
+		w := &Webhook{Filter: "unknown = 1"}
+		err := w.validateFilter()
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskField(err))
+	})
+}
+
+func TestWebhook_matchesFilter(t *testing.T) {
+	taskEvent := func(taskID int64) map[string]interface{} {
+		return map[string]interface{}{
+			"task": map[string]interface{}{
+				"id": float64(taskID),
+			},
+		}
+	}
+
+	t.Run("no filter", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		w := &Webhook{}
+		matches, err := w.matchesFilter(s, taskEvent(1))
+		require.NoError(t, err)
+		assert.True(t, matches)
+	})
+	t.Run("matching label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		w := &Webhook{Filter: "labels in 4"}
+		matches, err := w.matchesFilter(s, taskEvent(1))
+		require.NoError(t, err)
+		assert.True(t, matches)
+	})
+	t.Run("other bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		w := &Webhook{Filter: "bucket_id = 2"}
+		matches, err := w.matchesFilter(s, taskEvent(1))
+		require.NoError(t, err)
+		assert.False(t, matches)
+	})
+	t.Run("event without task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		w := &Webhook{Filter: "bucket_id = 2"}
+		matches, err := w.matchesFilter(s, map[string]interface{}{
+			"bucket": map[string]interface{}{
+				"id": float64(1),
+			},
+		})
+		require.NoError(t, err)
+		assert.True(t, matches)
+	})
+	t.Run("deleted task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		w := &Webhook{Filter: "bucket_id = 2"}
+		matches, err := w.matchesFilter(s, taskEvent(9999))
+		require.NoError(t, err)
+		assert.True(t, matches)
+	})
+}
@@ -0,0 +1,90 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"text/template"
+)
+
+// maxWebhookPayloadTemplateLength is the maximum length of a webhook payload template in bytes
+const maxWebhookPayloadTemplateLength = 16384
+
+var webhookTemplateFuncs = template.FuncMap{
+	// json encodes any value as json. Use this to safely put strings into a json payload: {{ json .data.task.title }}
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+func parseWebhookPayloadTemplate(tpl string) (*template.Template, error) {
+	return template.New("webhook").
+		Funcs(webhookTemplateFuncs).
+		Option("missingkey=zero").
+		Parse(tpl)
+}
+
+// validatePayloadTemplate checks the payload template of a webhook can be parsed
+func (w *Webhook) validatePayloadTemplate() error {
+	if w.PayloadTemplate == "" {
+		return nil
+	}
+
+	if len(w.PayloadTemplate) > maxWebhookPayloadTemplateLength {
+		return &ErrInvalidWebhookPayloadTemplate{Reason: "the template is too long"}
+	}
+
+	_, err := parseWebhookPayloadTemplate(w.PayloadTemplate)
+	if err != nil {
+		return &ErrInvalidWebhookPayloadTemplate{Reason: err.Error()}
+	}
+
+	return nil
+}
+
+// renderPayload returns the body which is sent to the webhook target for an encoded webhook payload.
+// If the webhook has no payload template, this is the payload itself. Otherwise, the template is executed with
+// the payload and has access to the same properties, for example {{ .event_name }} or {{ .data.task.title }}.
+func (w *Webhook) renderPayload(payload []byte) ([]byte, error) {
+	if w.PayloadTemplate == "" {
+		return payload, nil
+	}
+
+	tpl, err := parseWebhookPayloadTemplate(w.PayloadTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+	err = json.Unmarshal(payload, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = tpl.Execute(&buf, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
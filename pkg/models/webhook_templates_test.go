@@ -0,0 +1,70 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_validatePayloadTemplate(t *testing.T) {
+	t.Run("no template", func(t *testing.T) {
+		w := &Webhook{}
+		require.NoError(t, w.validatePayloadTemplate())
+	})
+	t.Run("valid template", func(t *testing.T) {
+		w := &Webhook{PayloadTemplate: `{"text": {{ json .data.task.title }}}`}
+		require.NoError(t, w.validatePayloadTemplate())
+	})
+	t.Run("invalid template", func(t *testing.T) {
+		w := &Webhook{PayloadTemplate: `{"text": {{ .data.task.title }`}
+		err := w.validatePayloadTemplate()
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidWebhookPayloadTemplate(err))
+	})
+	t.Run("unknown function", func(t *testing.T) {
+		w := &Webhook{PayloadTemplate: `{{ exec "rm" }}`}
+		err := w.validatePayloadTemplate()
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidWebhookPayloadTemplate(err))
+	})
+}
+
+func TestWebhook_renderPayload(t *testing.T) {
+	payload := []byte(`{"event_name":"task.created","time":"2023-10-17T19:39:32Z","data":{"task":{"id":1,"title":"Say \"hi\""}}}`)
+
+	t.Run("no template", func(t *testing.T) {
+		w := &Webhook{}
+		body, err := w.renderPayload(payload)
+		require.NoError(t, err)
+		assert.Equal(t, payload, body)
+	})
+	t.Run("with template", func(t *testing.T) {
+		w := &Webhook{PayloadTemplate: `{"text": {{ json (printf "%s: %s" (upper .event_name) .data.task.title) }}}`}
+		body, err := w.renderPayload(payload)
+		require.NoError(t, err)
+		assert.Equal(t, `{"text": "TASK.CREATED: Say \"hi\""}`, string(body))
+	})
+	t.Run("missing property", func(t *testing.T) {
+		w := &Webhook{PayloadTemplate: `{"text": {{ json .data.project }}}`}
+		body, err := w.renderPayload(payload)
+		require.NoError(t, err)
+		assert.Equal(t, `{"text": null}`, string(body))
+	})
+}
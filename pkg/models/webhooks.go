@@ -23,7 +23,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"sort"
@@ -34,6 +34,7 @@ import (
 	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/outbound"
 	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/api/pkg/version"
 	"code.vikunja.io/web"
@@ -53,6 +54,12 @@ type Webhook struct {
 	ProjectID int64 `xorm:"bigint not null index" json:"project_id" param:"project"`
 	// If provided, webhook requests will be signed using HMAC. Check out the docs about how to use this: https://vikunja.io/docs/webhooks/#signing
 	Secret string `xorm:"null" json:"secret"`
+	// An optional go template which is used to build the body of the webhook requests instead of the default payload.
+	// It has access to the same properties as the default payload. Check out the docs about how to use this: https://vikunja.io/docs/webhooks/#payload-templates
+	PayloadTemplate string `xorm:"text null" json:"payload_template"`
+	// An optional filter query like the ones of saved filters. If provided, events about a task are only sent when the task
+	// matches the filter, for example `labels in 4` or `bucket_id = 2`. Check out the docs about how to use this: https://vikunja.io/docs/webhooks/#filters
+	Filter string `xorm:"text null" json:"filter"`
 
 	// The user who initially created the webhook target.
 	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
@@ -125,6 +132,16 @@ func (w *Webhook) Create(s *xorm.Session, a web.Auth) (err error) {
 		}
 	}
 
+	err = w.validatePayloadTemplate()
+	if err != nil {
+		return err
+	}
+
+	err = w.validateFilter()
+	if err != nil {
+		return err
+	}
+
 	w.CreatedByID = a.GetID()
 	_, err = s.Insert(w)
 	if err != nil {
@@ -191,8 +208,8 @@ func (w *Webhook) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPa
 }
 
 // Update updates a webhook target
-// @Summary Change a webhook target's events.
-// @Description Change a webhook target's events. You cannot change other values of a webhook.
+// @Summary Change a webhook target's events, filter and payload template.
+// @Description Change a webhook target's events, filter and payload template. You cannot change other values of a webhook.
 // @tags webhooks
 // @Accept json
 // @Produce json
@@ -210,8 +227,18 @@ func (w *Webhook) Update(s *xorm.Session, _ web.Auth) (err error) {
 		}
 	}
 
+	err = w.validatePayloadTemplate()
+	if err != nil {
+		return err
+	}
+
+	err = w.validateFilter()
+	if err != nil {
+		return err
+	}
+
 	_, err = s.Where("id = ?", w.ID).
-		Cols("events").
+		Cols("events", "payload_template", "filter").
 		Update(w)
 	return
 }
@@ -230,6 +257,11 @@ func (w *Webhook) Update(s *xorm.Session, _ web.Auth) (err error) {
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{id}/webhooks/{webhookID} [delete]
 func (w *Webhook) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("webhook_id = ?", w.ID).Delete(&WebhookDelivery{})
+	if err != nil {
+		return
+	}
+
 	_, err = s.Where("id = ?", w.ID).Delete(&Webhook{})
 	return
 }
@@ -240,38 +272,29 @@ func getWebhookHTTPClient() (client *http.Client) {
 		return webhookClient
 	}
 
-	client = http.DefaultClient
-	client.Timeout = time.Duration(config.WebhooksTimeoutSeconds.GetInt()) * time.Second
-
-	if config.WebhooksProxyURL.GetString() == "" || config.WebhooksProxyPassword.GetString() == "" {
-		webhookClient = client
-		return
+	opts := &outbound.Options{
+		Timeout: time.Duration(config.WebhooksTimeoutSeconds.GetInt()) * time.Second,
 	}
 
-	proxyURL, _ := url.Parse(config.WebhooksProxyURL.GetString())
-
-	client.Transport = &http.Transport{
-		Proxy: http.ProxyURL(proxyURL),
-		ProxyConnectHeader: http.Header{
+	if config.WebhooksProxyURL.GetString() != "" && config.WebhooksProxyPassword.GetString() != "" {
+		opts.ProxyURL, _ = url.Parse(config.WebhooksProxyURL.GetString())
+		opts.ProxyConnectHeader = http.Header{
 			"Proxy-Authorization": []string{"Basic " + base64.StdEncoding.EncodeToString([]byte("vikunja:"+config.WebhooksProxyPassword.GetString()))},
 			"User-Agent":          []string{"Vikunja/" + version.Version},
-		},
+		}
 	}
 
-	webhookClient = client
+	webhookClient = outbound.NewClient(opts)
 
-	return
+	return webhookClient
 }
 
-func (w *Webhook) sendWebhookPayload(p *WebhookPayload) (err error) {
-	payload, err := json.Marshal(p)
-	if err != nil {
-		return err
-	}
-
+// sendWebhookRequest posts an already encoded payload to the webhook target and returns the status code of the
+// response. Any response with a status code other than 2xx is treated as an error.
+func (w *Webhook) sendWebhookRequest(eventName string, payload []byte) (statusCode int, err error) {
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, w.TargetURL, bytes.NewReader(payload))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if len(w.Secret) > 0 {
@@ -290,10 +313,14 @@ func (w *Webhook) sendWebhookPayload(p *WebhookPayload) (err error) {
 	client := getWebhookHTTPClient()
 	res, err := client.Do(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	defer res.Body.Close()
-	log.Debugf("Sent webhook payload for webhook %d for event %s", w.ID, p.EventName)
-	return
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook target responded with status %d", res.StatusCode)
+	}
+
+	log.Debugf("Sent webhook payload for webhook %d for event %s", w.ID, eventName)
+	return res.StatusCode, nil
 }
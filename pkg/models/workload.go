@@ -0,0 +1,276 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// AssigneeWorkload holds the open tasks assigned to a user
+type AssigneeWorkload struct {
+	// The assignee.
+	User *user.User `json:"user"`
+	// The number of undone tasks assigned to the user.
+	OpenTasks int64 `json:"open_tasks"`
+	// The number of undone tasks assigned to the user which are overdue.
+	OverdueTasks int64 `json:"overdue_tasks"`
+	// The sum of the estimates of all undone tasks assigned to the user in seconds.
+	OpenEstimate int64 `json:"open_estimate"`
+}
+
+// ProjectWorkload holds the workload of all assignees of a project
+type ProjectWorkload struct {
+	// The project the workload belongs to.
+	ProjectID int64 `json:"project_id" param:"project"`
+	// If set, only tasks due on or after this day are counted. In the format YYYY-MM-DD.
+	From string `json:"from" query:"from"`
+	// If set, only tasks due on or before this day are counted. In the format YYYY-MM-DD.
+	To string `json:"to" query:"to"`
+
+	// The workload of every user with at least one open task in the project.
+	Assignees []*AssigneeWorkload `json:"assignees"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// TeamWorkload holds the workload of all members of a team
+type TeamWorkload struct {
+	// The team the workload belongs to.
+	TeamID int64 `json:"team_id" param:"team"`
+	// If set, only tasks due on or after this day are counted. In the format YYYY-MM-DD.
+	From string `json:"from" query:"from"`
+	// If set, only tasks due on or before this day are counted. In the format YYYY-MM-DD.
+	To string `json:"to" query:"to"`
+
+	// The workload of every member of the team.
+	Assignees []*AssigneeWorkload `json:"assignees"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// getWorkloadPeriodCond returns the condition for tasks due in the period. Both ends of the period are optional.
+func getWorkloadPeriodCond(from, to string) (cond builder.Cond, err error) {
+	tz := config.GetTimeZone()
+	cond = builder.Eq{"1": 1}
+
+	var start, end time.Time
+	if from != "" {
+		start, err = time.ParseInLocation(kanbanAnalyticsDateFormat, from, tz)
+		if err != nil {
+			return nil, ErrInvalidData{Message: "The start date must be in the format YYYY-MM-DD."}
+		}
+		cond = builder.And(cond, builder.Gte{"tasks.due_date": start})
+	}
+
+	if to != "" {
+		end, err = time.ParseInLocation(kanbanAnalyticsDateFormat, to, tz)
+		if err != nil {
+			return nil, ErrInvalidData{Message: "The end date must be in the format YYYY-MM-DD."}
+		}
+		// The period includes the whole last day
+		cond = builder.And(cond, builder.Lt{"tasks.due_date": end.Add(kanbanAnalyticsDay)})
+	}
+
+	if from != "" && to != "" && start.After(end) {
+		return nil, ErrInvalidData{Message: "The start date must be before the end date."}
+	}
+
+	if from != "" || to != "" {
+		cond = builder.And(cond, builder.NotNull{"tasks.due_date"})
+	}
+
+	return
+}
+
+type workloadTask struct {
+	UserID   int64     `xorm:"user_id"`
+	DueDate  time.Time `xorm:"due_date"`
+	Estimate int64     `xorm:"estimate"`
+}
+
+// getAssigneeWorkloads returns the workload of all users with open tasks in the projects. If userIDs is not empty,
+// only these users are included, even if they don't have any open tasks.
+func getAssigneeWorkloads(s *xorm.Session, projectIDs []int64, userIDs []int64, periodCond builder.Cond, now time.Time) (workloads []*AssigneeWorkload, err error) {
+	workloadMap := make(map[int64]*AssigneeWorkload)
+	for _, id := range userIDs {
+		workloadMap[id] = &AssigneeWorkload{}
+	}
+
+	if len(projectIDs) > 0 {
+		cond := builder.And(
+			builder.In("tasks.project_id", projectIDs),
+			builder.Eq{"tasks.done": false},
+			builder.Eq{"tasks.is_archived": false},
+			periodCond,
+		)
+		if len(userIDs) > 0 {
+			cond = builder.And(cond, builder.In("task_assignees.user_id", userIDs))
+		}
+
+		tasks := []*workloadTask{}
+		err = s.
+			Table("tasks").
+			Select("task_assignees.user_id, tasks.due_date, tasks.estimate").
+			Join("INNER", "task_assignees", "task_assignees.task_id = tasks.id").
+			Where(cond).
+			Find(&tasks)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range tasks {
+			w, has := workloadMap[t.UserID]
+			if !has {
+				w = &AssigneeWorkload{}
+				workloadMap[t.UserID] = w
+			}
+			w.OpenTasks++
+			w.OpenEstimate += t.Estimate
+			if !t.DueDate.IsZero() && t.DueDate.Before(now) {
+				w.OverdueTasks++
+			}
+		}
+	}
+
+	ids := make([]int64, 0, len(workloadMap))
+	for id := range workloadMap {
+		ids = append(ids, id)
+	}
+
+	users, err := user.GetUsersByIDs(s, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	workloads = make([]*AssigneeWorkload, 0, len(workloadMap))
+	for id, w := range workloadMap {
+		u, has := users[id]
+		if !has {
+			continue
+		}
+		w.User = u
+		workloads = append(workloads, w)
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].OpenTasks != workloads[j].OpenTasks {
+			return workloads[i].OpenTasks > workloads[j].OpenTasks
+		}
+		return workloads[i].User.ID < workloads[j].User.ID
+	})
+
+	return
+}
+
+// CanRead checks if the user can see the workload of a project
+func (pw *ProjectWorkload) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	p := &Project{ID: pw.ProjectID}
+	return p.CanRead(s, a)
+}
+
+// ReadOne returns the workload of all assignees of a project
+// @Summary Get the workload of the assignees of a project
+// @Description Returns the number of open and overdue tasks and the sum of their estimates for every user with open tasks in the project, the busiest first.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "Project Id"
+// @Param from query string false "If set, only tasks due on or after this day are counted. In the format YYYY-MM-DD."
+// @Param to query string false "If set, only tasks due on or before this day are counted. In the format YYYY-MM-DD."
+// @Success 200 {object} models.ProjectWorkload "The workload of the assignees of the project."
+// @Failure 400 {object} web.HTTPError "Invalid period provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/workload [get]
+func (pw *ProjectWorkload) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	periodCond, err := getWorkloadPeriodCond(pw.From, pw.To)
+	if err != nil {
+		return err
+	}
+
+	pw.Assignees, err = getAssigneeWorkloads(s, []int64{pw.ProjectID}, nil, periodCond, time.Now())
+	return err
+}
+
+// CanRead checks if the user can see the workload of a team. Only members of the team can see it.
+func (tw *TeamWorkload) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, 0, nil
+	}
+
+	t := &Team{ID: tw.TeamID}
+	return t.CanRead(s, a)
+}
+
+// ReadOne returns the workload of all members of a team
+// @Summary Get the workload of the members of a team
+// @Description Returns the number of open and overdue tasks and the sum of their estimates for every member of the team, the busiest first. Only tasks in projects the current user has access to are counted.
+// @tags team
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param teamID path int true "Team Id"
+// @Param from query string false "If set, only tasks due on or after this day are counted. In the format YYYY-MM-DD."
+// @Param to query string false "If set, only tasks due on or before this day are counted. In the format YYYY-MM-DD."
+// @Success 200 {object} models.TeamWorkload "The workload of the members of the team."
+// @Failure 400 {object} web.HTTPError "Invalid period provided."
+// @Failure 403 {object} web.HTTPError "The user is not a member of the team."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /teams/{teamID}/workload [get]
+func (tw *TeamWorkload) ReadOne(s *xorm.Session, a web.Auth) (err error) {
+	periodCond, err := getWorkloadPeriodCond(tw.From, tw.To)
+	if err != nil {
+		return err
+	}
+
+	members := []*TeamMember{}
+	err = s.Where("team_id = ?", tw.TeamID).Find(&members)
+	if err != nil {
+		return err
+	}
+
+	userIDs := make([]int64, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+	}
+
+	projects, _, err := getAllProjectsForUser(s, a.GetID(), &projectOptions{
+		user: &user.User{ID: a.GetID(), APITokenProjectIDs: getAPITokenProjectIDs(a)},
+	})
+	if err != nil {
+		return err
+	}
+
+	projectIDs := make([]int64, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	tw.Assignees, err = getAssigneeWorkloads(s, projectIDs, userIDs, periodCond, time.Now())
+	return err
+}
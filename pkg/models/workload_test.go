@@ -0,0 +1,142 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectWorkload_ReadOne(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(30).
+			Cols("estimate", "due_date").
+			Update(&Task{Estimate: 3600, DueDate: time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+
+		pw := &ProjectWorkload{ProjectID: 1}
+		can, _, err := pw.CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pw.ReadOne(s, u)
+		require.NoError(t, err)
+
+		require.Len(t, pw.Assignees, 2)
+		assert.Equal(t, int64(1), pw.Assignees[0].User.ID)
+		assert.Equal(t, int64(1), pw.Assignees[0].OpenTasks)
+		assert.Equal(t, int64(1), pw.Assignees[0].OverdueTasks)
+		assert.Equal(t, int64(3600), pw.Assignees[0].OpenEstimate)
+		assert.Equal(t, int64(2), pw.Assignees[1].User.ID)
+	})
+	t.Run("period", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(30).
+			Cols("due_date").
+			Update(&Task{DueDate: time.Date(2018, 12, 1, 12, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+
+		pw := &ProjectWorkload{ProjectID: 1, From: "2018-12-01", To: "2018-12-01"}
+		err = pw.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Len(t, pw.Assignees, 2)
+
+		pw = &ProjectWorkload{ProjectID: 1, From: "2018-12-02"}
+		err = pw.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Empty(t, pw.Assignees)
+	})
+	t.Run("invalid period", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pw := &ProjectWorkload{ProjectID: 1, From: "2018-12-02", To: "2018-12-01"}
+		err := pw.ReadOne(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidData(err))
+	})
+}
+
+func TestTeamWorkload_ReadOne(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tw := &TeamWorkload{TeamID: 1}
+		can, _, err := tw.CanRead(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = tw.ReadOne(s, &user.User{ID: 1})
+		require.NoError(t, err)
+
+		require.Len(t, tw.Assignees, 2)
+		for _, w := range tw.Assignees {
+			assert.Positive(t, w.OpenTasks)
+		}
+	})
+	t.Run("api token restricted to a project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 2 is also assigned to tasks 35 and 36 in projects 21 and 22
+		token := &user.User{ID: 1, AuthenticatedWithAPIToken: true, APITokenProjectIDs: []int64{1}}
+		tw := &TeamWorkload{TeamID: 1}
+		err := tw.ReadOne(s, token)
+		require.NoError(t, err)
+		require.Len(t, tw.Assignees, 2)
+		for _, w := range tw.Assignees {
+			assert.Equal(t, int64(1), w.OpenTasks)
+		}
+	})
+	t.Run("not a member", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tw := &TeamWorkload{TeamID: 1}
+		can, _, err := tw.CanRead(s, &user.User{ID: 3})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tw := &TeamWorkload{TeamID: 1}
+		can, _, err := tw.CanRead(s, &LinkSharing{ID: 1, ProjectID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -111,6 +111,8 @@ func GetAuthFromClaims(c echo.Context) (a web.Auth, err error) {
 		if err != nil {
 			return nil, err
 		}
+		u.AuthenticatedWithAPIToken = true
+		u.APITokenProjectIDs = apiToken.ProjectIDs
 		return u, nil
 	}
 
@@ -39,3 +39,9 @@ type Provider interface {
 	// Set sets an image which was most likely previously obtained by Search as project background
 	Set(s *xorm.Session, image *Image, project *models.Project, auth web.Auth) (err error)
 }
+
+// TaskCoverProvider is a Provider whose images can also be used as task cover images
+type TaskCoverProvider interface {
+	// Download returns the content and a file name of an image which was most likely previously obtained by Search
+	Download(s *xorm.Session, image *Image) (content []byte, name string, err error)
+}
@@ -146,6 +146,77 @@ func (bp *BackgroundProvider) SetBackground(c echo.Context) error {
 	return c.JSON(http.StatusOK, project)
 }
 
+// SetTaskCover sets an image from a background provider as task cover image
+// @Summary Set an unsplash photo as task cover image
+// @Description Downloads a photo from unsplash, stores it as an attachment of the task and sets it as the cover image of the task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param image body background.Image true "The image you want to set as cover image"
+// @Success 200 {object} models.TaskAttachment "The attachment which is now the cover image of the task."
+// @Failure 400 {object} web.HTTPError "Invalid image object provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/cover/unsplash [post]
+func (bp *BackgroundProvider) SetTaskCover(c echo.Context) error {
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid auth token: "+err.Error())
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("task"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid task ID: "+err.Error())
+	}
+
+	p, is := bp.Provider().(background.TaskCoverProvider)
+	if !is {
+		return echo.NewHTTPError(http.StatusBadRequest, "This provider can't be used for task cover images.")
+	}
+
+	image := &background.Image{}
+	err = c.Bind(image)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No or invalid model provided: "+err.Error())
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	task := &models.Task{ID: taskID}
+	can, err := task.CanUpdate(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		_ = s.Rollback()
+		log.Infof("Tried to set the cover image of task %d while not having the rights for it (User: %v)", taskID, auth)
+		return handler.HandleHTTPError(models.ErrGenericForbidden{}, c)
+	}
+
+	content, name, err := p.Download(s, image)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	attachment, err := models.SetTaskCoverImage(s, taskID, content, name, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, attachment)
+}
+
 func CreateBlurHash(srcf io.Reader) (hash string, err error) {
 	src, _, err := image.Decode(srcf)
 	if err != nil {
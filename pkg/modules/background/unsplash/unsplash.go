@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -255,35 +256,13 @@ func (p *Provider) Set(s *xorm.Session, image *background.Image, project *models
 	}
 
 	// Download the photo from unsplash
-	// The parameters crop the image to a max width of 2560 and a max height of 2048 to save bandwidth and storage.
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, photo.Urls.Raw+"&w=2560&h=2048&q=90", nil)
-	if err != nil {
-		return
-	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode > 399 {
-		b := bytes.Buffer{}
-		_, _ = b.ReadFrom(resp.Body)
-		log.Errorf("Error getting unsplash photo %s: Request failed with status %d, message was %s", photo.ID, resp.StatusCode, b.String())
-		return
-	}
-
-	log.Debugf("Downloaded unsplash photo %s", image.ID)
-
-	// Ping the unsplash download endpoint (again, unsplash api guidelines)
-	err = doGet(strings.Replace(photo.Links.DownloadLocation, unsplashAPIURL, "", 1))
+	content, err := downloadPhoto(photo)
 	if err != nil {
 		return
 	}
-	log.Debugf("Pinged unsplash download endpoint for photo %s", image.ID)
 
 	// Save it as a file in vikunja
-	file, err := files.Create(resp.Body, "", 0, auth)
+	file, err := files.Create(bytes.NewReader(content), "", 0, auth)
 	if err != nil {
 		return
 	}
@@ -323,6 +302,52 @@ func (p *Provider) Set(s *xorm.Session, image *background.Image, project *models
 	return models.SetProjectBackground(s, project.ID, file, photo.BlurHash)
 }
 
+// Download downloads an unsplash photo to use it as a task cover image
+func (p *Provider) Download(_ *xorm.Session, image *background.Image) (content []byte, name string, err error) {
+	photo, err := getUnsplashPhotoInfoByID(image.ID)
+	if err != nil {
+		return
+	}
+
+	content, err = downloadPhoto(photo)
+	return content, "unsplash-" + photo.ID + ".jpg", err
+}
+
+// downloadPhoto downloads the photo from unsplash and pings their download endpoint, as required by the api guidelines.
+func downloadPhoto(photo *Photo) (content []byte, err error) {
+	// The parameters crop the image to a max width of 2560 and a max height of 2048 to save bandwidth and storage.
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, photo.Urls.Raw+"&w=2560&h=2048&q=90", nil)
+	if err != nil {
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b := bytes.Buffer{}
+	_, err = b.ReadFrom(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode > 399 {
+		log.Errorf("Error getting unsplash photo %s: Request failed with status %d, message was %s", photo.ID, resp.StatusCode, b.String())
+		return nil, fmt.Errorf("getting unsplash photo %s failed with status %d", photo.ID, resp.StatusCode)
+	}
+
+	log.Debugf("Downloaded unsplash photo %s", photo.ID)
+
+	err = doGet(strings.Replace(photo.Links.DownloadLocation, unsplashAPIURL, "", 1))
+	if err != nil {
+		return
+	}
+	log.Debugf("Pinged unsplash download endpoint for photo %s", photo.ID)
+
+	return b.Bytes(), nil
+}
+
 // Pingback pings the unsplash api if an unsplash photo has been accessed.
 func Pingback(s *xorm.Session, f *files.File) {
 	// Check if the file is actually downloaded from unsplash
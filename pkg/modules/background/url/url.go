@@ -0,0 +1,60 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package url
+
+import (
+	"bytes"
+
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/modules/background"
+	"code.vikunja.io/api/pkg/modules/background/handler"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// Provider represents a provider which downloads backgrounds from arbitrary urls
+type Provider struct {
+}
+
+// Search is only used to implement the interface
+func (p *Provider) Search(_ *xorm.Session, _ string, _ int64) (result []*background.Image, err error) {
+	return
+}
+
+// Set downloads an image from a url and stores it as project background
+// @Summary Set a project background from a url
+// @Description Downloads the image from the provided url and stores it as background of the project. Only the url property of the image is used.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param image body background.Image true "The image with the url you want to set as background"
+// @Success 200 {object} models.Project "The background has been successfully set."
+// @Failure 400 {object} web.HTTPError "The url is invalid or does not point to an image."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/backgrounds/url [post]
+func (p *Provider) Set(s *xorm.Session, image *background.Image, project *models.Project, auth web.Auth) (err error) {
+	content, name, err := models.DownloadProjectBackground(project.ID, image.URL)
+	if err != nil {
+		return err
+	}
+
+	return handler.SaveBackgroundFile(s, auth, project, bytes.NewReader(content), name, uint64(len(content)))
+}
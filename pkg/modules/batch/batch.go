@@ -0,0 +1,351 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package batch runs multiple create, update and delete operations of the REST api in a single database transaction.
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/web"
+	"code.vikunja.io/web/handler"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"xorm.io/xorm"
+)
+
+const (
+	sessionKey = "batch_session"
+	authKey    = "batch_auth"
+	resultKey  = "batch_result"
+)
+
+// All operations are matched against the routes registered here, independently of the routes of the api itself.
+var router = echo.New()
+
+// A reference to a property of the result of an earlier operation, for example `$0.id`
+var referenceRegex = regexp.MustCompile(`\$(\d+)\.([a-z_]+)`)
+
+// Operation is one create, update or delete operation of a batch request
+type Operation struct {
+	// The http method of the operation. `PUT` creates, `POST` updates and `DELETE` deletes, the same as with the REST api.
+	Method string `json:"method"`
+	// The path of the operation, relative to `/api/v1`, for example `/projects/1/tasks`.
+	// It can reference properties of the results of earlier operations like `/tasks/$0.id/labels`.
+	Path string `json:"path"`
+	// The body of the operation, the same as the body of the request to the REST api.
+	// String values can reference properties of the results of earlier operations like `"$0.id"`.
+	Body json.RawMessage `json:"body"`
+}
+
+// Request is a batch request
+type Request struct {
+	// The operations to run, in order.
+	Operations []*Operation `json:"operations"`
+}
+
+// Result is the result of one operation
+type Result struct {
+	// The http status code the REST api would have returned for the operation.
+	Status int `json:"status"`
+	// The body the REST api would have returned for the operation.
+	Body interface{} `json:"body"`
+}
+
+// Response is the response to a batch request
+type Response struct {
+	// Whether all operations succeeded. If not, none of the changes are saved.
+	Success bool `json:"success"`
+	// The results of all operations which ran. If an operation failed, its result is the last one.
+	Results []*Result `json:"results"`
+}
+
+// Register adds a route operations can use. The method of the route defines what is done with the struct:
+// `PUT` creates, `POST` updates and `DELETE` deletes it.
+func Register(method, path string, emptyStruct func() handler.CObject) {
+	router.Add(method, path, func(c echo.Context) error {
+		s := c.Get(sessionKey).(*xorm.Session)
+		a := c.Get(authKey).(web.Auth)
+
+		obj := emptyStruct()
+		if err := c.Bind(obj); err != nil {
+			return &models.ErrInvalidBatchOperation{Message: "The body is invalid."}
+		}
+
+		result, err := run(s, a, method, obj)
+		if err != nil {
+			return err
+		}
+
+		c.Set(resultKey, result)
+		return nil
+	})
+}
+
+func validate(i interface{}) error {
+	if _, err := govalidator.ValidateStruct(i); err != nil {
+		var errs []string
+		for field, e := range govalidator.ErrorsByField(err) {
+			errs = append(errs, field+": "+e)
+		}
+
+		return models.InvalidFieldError(errs)
+	}
+	return nil
+}
+
+func run(s *xorm.Session, a web.Auth, method string, obj handler.CObject) (*Result, error) {
+	switch method {
+	case http.MethodPut:
+		if err := validate(obj); err != nil {
+			return nil, err
+		}
+		can, err := obj.CanCreate(s, a)
+		if err != nil {
+			return nil, err
+		}
+		if !can {
+			return nil, models.ErrGenericForbidden{}
+		}
+		if err := obj.Create(s, a); err != nil {
+			return nil, err
+		}
+		return &Result{Status: http.StatusCreated, Body: obj}, nil
+	case http.MethodPost:
+		if err := validate(obj); err != nil {
+			return nil, err
+		}
+		can, err := obj.CanUpdate(s, a)
+		if err != nil {
+			return nil, err
+		}
+		if !can {
+			return nil, models.ErrGenericForbidden{}
+		}
+		if err := obj.Update(s, a); err != nil {
+			return nil, err
+		}
+		return &Result{Status: http.StatusOK, Body: obj}, nil
+	case http.MethodDelete:
+		can, err := obj.CanDelete(s, a)
+		if err != nil {
+			return nil, err
+		}
+		if !can {
+			return nil, models.ErrGenericForbidden{}
+		}
+		if err := obj.Delete(s, a); err != nil {
+			return nil, err
+		}
+		return &Result{Status: http.StatusOK, Body: models.Message{Message: "Successfully deleted."}}, nil
+	}
+
+	return nil, &models.ErrInvalidBatchOperation{Message: "The method is not supported."}
+}
+
+// errorResult converts an error to the result the REST api would have returned and hides all unknown errors
+func errorResult(err error) *Result {
+	var validationErr models.ValidationHTTPError
+	if errors.As(err, &validationErr) {
+		return &Result{Status: validationErr.HTTPCode, Body: validationErr}
+	}
+
+	var processor web.HTTPErrorProcessor
+	if errors.As(err, &processor) {
+		httpErr := processor.HTTPError()
+		return &Result{Status: httpErr.HTTPCode, Body: httpErr}
+	}
+
+	log.Errorf("Batch: %s", err)
+	return &Result{Status: http.StatusInternalServerError, Body: models.Message{Message: "Internal Server Error"}}
+}
+
+// resolveReference returns the value of a property of the result of an earlier operation
+func resolveReference(results []*Result, index int, property string) (interface{}, error) {
+	if index >= len(results) {
+		return nil, fmt.Errorf("operation %d has not run yet", index)
+	}
+
+	raw, err := json.Marshal(results[index].Body)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("the result of operation %d has no property %s", index, property)
+	}
+
+	value, has := values[property]
+	if !has {
+		return nil, fmt.Errorf("the result of operation %d has no property %s", index, property)
+	}
+	return value, nil
+}
+
+// replaceReferences replaces all references in a string with the values they reference
+func replaceReferences(results []*Result, str string) (string, error) {
+	var resolveErr error
+	replaced := referenceRegex.ReplaceAllStringFunc(str, func(ref string) string {
+		match := referenceRegex.FindStringSubmatch(ref)
+		index, _ := strconv.Atoi(match[1])
+		value, err := resolveReference(results, index, match[2])
+		if err != nil {
+			resolveErr = err
+			return ref
+		}
+		return fmt.Sprint(value)
+	})
+	return replaced, resolveErr
+}
+
+// replaceBodyReferences replaces all references in string values of a body. A string which only consists of a
+// reference is replaced with the referenced value itself, so that numbers stay numbers.
+func replaceBodyReferences(results []*Result, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if match := referenceRegex.FindStringSubmatch(v); match != nil && match[0] == v {
+			index, _ := strconv.Atoi(match[1])
+			return resolveReference(results, index, match[2])
+		}
+		return replaceReferences(results, v)
+	case map[string]interface{}:
+		for key, child := range v {
+			replaced, err := replaceBodyReferences(results, child)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = replaced
+		}
+	case []interface{}:
+		for i, child := range v {
+			replaced, err := replaceBodyReferences(results, child)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = replaced
+		}
+	}
+	return value, nil
+}
+
+func prepareOperation(results []*Result, op *Operation) (path string, body []byte, err error) {
+	path, err = replaceReferences(results, strings.TrimPrefix(op.Path, "/api/v1"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(op.Body) == 0 {
+		return path, nil, nil
+	}
+
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(op.Body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return "", nil, errors.New("the body is not valid json")
+	}
+
+	decoded, err = replaceBodyReferences(results, decoded)
+	if err != nil {
+		return "", nil, err
+	}
+
+	body, err = json.Marshal(decoded)
+	return path, body, err
+}
+
+func runOperation(s *xorm.Session, a web.Auth, results []*Result, index int, op *Operation) (*Result, error) {
+	method := strings.ToUpper(op.Method)
+
+	path, body, err := prepareOperation(results, op)
+	if err != nil {
+		return nil, &models.ErrInvalidBatchOperation{Index: index, Method: method, Path: op.Path, Message: err.Error()}
+	}
+
+	req, err := http.NewRequest(method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, &models.ErrInvalidBatchOperation{Index: index, Method: method, Path: op.Path, Message: "The path is invalid."}
+	}
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	c := router.NewContext(req, nil)
+	router.Router().Find(method, req.URL.Path, c)
+	c.Set(sessionKey, s)
+	c.Set(authKey, a)
+
+	err = c.Handler()(c)
+	var invalidOp *models.ErrInvalidBatchOperation
+	if errors.As(err, &invalidOp) {
+		invalidOp.Index = index
+		invalidOp.Method = method
+		invalidOp.Path = op.Path
+		return nil, invalidOp
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		// The router did not find a route for the operation
+		return nil, &models.ErrInvalidBatchOperation{Index: index, Method: method, Path: op.Path, Message: "The operation is not supported in batch requests."}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Get(resultKey).(*Result), nil
+}
+
+// Execute runs all operations of a batch request in order with the session and permissions of the auth. It stops at
+// the first operation which fails. The caller is responsible for committing the session if the response is successful.
+func Execute(s *xorm.Session, a web.Auth, req *Request) (*Response, error) {
+	maxOperations := config.ServiceMaxBatchOperations.GetInt()
+	if len(req.Operations) > maxOperations {
+		return nil, &models.ErrTooManyBatchOperations{Operations: len(req.Operations), Max: maxOperations}
+	}
+
+	res := &Response{Results: make([]*Result, 0, len(req.Operations))}
+	for i, op := range req.Operations {
+		result, err := runOperation(s, a, res.Results, i, op)
+		if err != nil {
+			res.Results = append(res.Results, errorResult(err))
+			return res, nil
+		}
+		res.Results = append(res.Results, result)
+	}
+
+	res.Success = true
+	return res, nil
+}
+
+// Status returns the http status code for the response, which is the status of the failed operation if there is one
+func (r *Response) Status() int {
+	if r.Success || len(r.Results) == 0 {
+		return http.StatusOK
+	}
+	return r.Results[len(r.Results)-1].Status
+}
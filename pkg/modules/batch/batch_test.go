@@ -0,0 +1,164 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package batch
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func operation(method, path, body string) *Operation {
+	op := &Operation{Method: method, Path: path}
+	if body != "" {
+		op.Body = json.RawMessage(body)
+	}
+	return op
+}
+
+func execute(t *testing.T, ops ...*Operation) *Response {
+	s := db.NewSession()
+	defer s.Close()
+	require.NoError(t, s.Begin())
+	events.DeferUntilCommit(s)
+	defer events.DiscardPending(s)
+
+	res, err := Execute(s, &user.User{ID: 1}, &Request{Operations: ops})
+	require.NoError(t, err)
+
+	if !res.Success {
+		require.NoError(t, s.Rollback())
+		return res
+	}
+
+	require.NoError(t, s.Commit())
+	events.DispatchPending(s)
+
+	return res
+}
+
+func TestExecute(t *testing.T) {
+	t.Run("create task with labels and relations", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		events.Fake()
+
+		res := execute(t,
+			operation(http.MethodPut, "/projects/1/tasks", `{"title":"batch task"}`),
+			operation(http.MethodPut, "/projects/1/tasks", `{"title":"other batch task"}`),
+			operation(http.MethodPut, "/tasks/$0.id/labels", `{"label_id":1}`),
+			operation(http.MethodPut, "/api/v1/tasks/$0.id/relations", `{"other_task_id":"$1.id","relation_kind":"related"}`),
+		)
+		require.True(t, res.Success)
+		require.Len(t, res.Results, 4)
+		assert.Equal(t, http.StatusCreated, res.Results[0].Status)
+		assert.Equal(t, http.StatusOK, res.Status())
+		events.AssertDispatched(t, &models.TaskCreatedEvent{})
+		events.AssertDispatched(t, &models.TaskLabelCreatedEvent{})
+
+		task := res.Results[0].Body.(*models.Task)
+		otherTask := res.Results[1].Body.(*models.Task)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         task.ID,
+			"title":      "batch task",
+			"project_id": 1,
+		}, false)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  task.ID,
+			"label_id": 1,
+		}, false)
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       task.ID,
+			"other_task_id": otherTask.ID,
+			"relation_kind": "related",
+		}, false)
+	})
+	t.Run("rolls back when an operation fails", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		events.Fake()
+
+		res := execute(t,
+			operation(http.MethodPut, "/projects/1/tasks", `{"title":"batch task"}`),
+			operation(http.MethodPost, "/tasks/1", `{"title":"changed"}`),
+			// Project 20 belongs to another user
+			operation(http.MethodPut, "/projects/20/tasks", `{"title":"forbidden"}`),
+			operation(http.MethodDelete, "/tasks/1", ""),
+		)
+		assert.False(t, res.Success)
+		require.Len(t, res.Results, 3)
+		assert.Equal(t, http.StatusForbidden, res.Results[2].Status)
+		assert.Equal(t, http.StatusForbidden, res.Status())
+		// The changes were rolled back, therefore no events for them must be dispatched
+		events.AssertNotDispatched(t, &models.TaskCreatedEvent{})
+		events.AssertNotDispatched(t, &models.TaskUpdatedEvent{})
+
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"title": "batch task",
+		})
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":    1,
+			"title": "task #1",
+		}, false)
+	})
+	t.Run("unsupported operation", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		res := execute(t, operation(http.MethodPut, "/teams", `{"name":"test"}`))
+		assert.False(t, res.Success)
+		require.Len(t, res.Results, 1)
+		assert.Equal(t, http.StatusBadRequest, res.Results[0].Status)
+		assert.Equal(t, models.ErrCodeInvalidBatchOperation, res.Results[0].Body.(web.HTTPError).Code)
+	})
+	t.Run("reference to an operation which did not run yet", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		res := execute(t, operation(http.MethodPut, "/tasks/$1.id/labels", `{"label_id":1}`))
+		assert.False(t, res.Success)
+		require.Len(t, res.Results, 1)
+		assert.Equal(t, models.ErrCodeInvalidBatchOperation, res.Results[0].Body.(web.HTTPError).Code)
+	})
+	t.Run("invalid task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		res := execute(t, operation(http.MethodPut, "/projects/1/tasks", `{"title":""}`))
+		assert.False(t, res.Success)
+		require.Len(t, res.Results, 1)
+		assert.Equal(t, http.StatusBadRequest, res.Results[0].Status)
+		assert.Equal(t, models.ErrCodeTaskCannotBeEmpty, res.Results[0].Body.(web.HTTPError).Code)
+	})
+	t.Run("too many operations", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ops := make([]*Operation, 51)
+		for i := range ops {
+			ops[i] = operation(http.MethodDelete, "/tasks/1", "")
+		}
+		_, err := Execute(s, &user.User{ID: 1}, &Request{Operations: ops})
+		require.Error(t, err)
+		assert.True(t, models.IsErrTooManyBatchOperations(err))
+	})
+}
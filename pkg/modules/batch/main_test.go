@@ -0,0 +1,46 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package batch
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web/handler"
+)
+
+// TestMain is the main test function used to bootstrap the test env
+func TestMain(m *testing.M) {
+	user.InitTests()
+	files.InitTests()
+	models.SetupTests()
+	events.Fake()
+
+	task := func() handler.CObject { return &models.Task{} }
+	Register(http.MethodPut, "/projects/:project/tasks", task)
+	Register(http.MethodPost, "/tasks/:projecttask", task)
+	Register(http.MethodDelete, "/tasks/:projecttask", task)
+	Register(http.MethodPut, "/tasks/:projecttask/labels", func() handler.CObject { return &models.LabelTask{} })
+	Register(http.MethodPut, "/tasks/:task/relations", func() handler.CObject { return &models.TaskRelation{} })
+
+	os.Exit(m.Run())
+}
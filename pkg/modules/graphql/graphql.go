@@ -0,0 +1,165 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package graphql provides a GraphQL api next to the REST api. All queries and mutations use the same models and
+// rights checks as the REST api.
+package graphql
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/web"
+
+	"github.com/asaskevich/govalidator"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"xorm.io/xorm"
+)
+
+type contextKey int
+
+const (
+	sessionKey contextKey = iota
+	authKey
+)
+
+var (
+	schema     gql.Schema
+	schemaErr  error
+	schemaOnce sync.Once
+)
+
+// Request is a GraphQL request
+type Request struct {
+	// The GraphQL query or mutation
+	Query string `json:"query"`
+	// The values of the variables used in the query
+	Variables map[string]interface{} `json:"variables"`
+	// The name of the operation to run if the query contains more than one
+	OperationName string `json:"operationName"`
+}
+
+// Error is returned for errors of the models. It contains the same error code as the REST api.
+type Error struct {
+	httpError     web.HTTPError
+	invalidFields []string
+}
+
+func (e *Error) Error() string {
+	return e.httpError.Message
+}
+
+// Extensions returns the error code and http status code of the error
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{
+		"code":      e.httpError.Code,
+		"http_code": e.httpError.HTTPCode,
+	}
+	if len(e.invalidFields) > 0 {
+		extensions["invalid_fields"] = e.invalidFields
+	}
+	return extensions
+}
+
+// handleError converts models errors to errors with their error code and hides all other errors
+func handleError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var validationErr models.ValidationHTTPError
+	if errors.As(err, &validationErr) {
+		return &Error{httpError: validationErr.HTTPError, invalidFields: validationErr.InvalidFields}
+	}
+
+	var processor web.HTTPErrorProcessor
+	if errors.As(err, &processor) {
+		return &Error{httpError: processor.HTTPError()}
+	}
+
+	log.Errorf("GraphQL: %s", err)
+	return errors.New("internal server error")
+}
+
+// formatError formats an error which happened outside of a resolver the same way as errors of resolvers
+func formatError(err error) gqlerrors.FormattedError {
+	err = handleError(err)
+	formatted := gqlerrors.FormatError(err)
+	if extended, is := err.(gqlerrors.ExtendedError); is {
+		formatted.Extensions = extended.Extensions()
+	}
+	return formatted
+}
+
+func validate(i interface{}) error {
+	if _, err := govalidator.ValidateStruct(i); err != nil {
+		var errs []string
+		for field, e := range govalidator.ErrorsByField(err) {
+			errs = append(errs, field+": "+e)
+		}
+
+		return models.InvalidFieldError(errs)
+	}
+	return nil
+}
+
+func getSchema() (gql.Schema, error) {
+	schemaOnce.Do(func() {
+		schema, schemaErr = gql.NewSchema(gql.SchemaConfig{
+			Query:    queryType,
+			Mutation: mutationType,
+		})
+	})
+	return schema, schemaErr
+}
+
+func fromContext(ctx context.Context) (*xorm.Session, web.Auth) {
+	return ctx.Value(sessionKey).(*xorm.Session), ctx.Value(authKey).(web.Auth)
+}
+
+// Execute runs a GraphQL request with the session and permissions of the auth.
+// The caller is responsible for committing the session if the result has no errors.
+func Execute(s *xorm.Session, a web.Auth, req *Request) (*gql.Result, error) {
+	sc, err := getSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	// Syntax errors are returned by the execution of the query
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err == nil {
+		err = checkQueryLimits(doc)
+		if err != nil {
+			return &gql.Result{Errors: []gqlerrors.FormattedError{formatError(err)}}, nil
+		}
+	}
+
+	ctx := context.WithValue(context.Background(), sessionKey, s)
+	ctx = context.WithValue(ctx, authKey, a)
+
+	return gql.Do(gql.Params{
+		Schema:         sc,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	}), nil
+}
@@ -0,0 +1,291 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execute(t *testing.T, query string, result interface{}) []map[string]interface{} {
+	s := db.NewSession()
+	defer s.Close()
+	require.NoError(t, s.Begin())
+	events.DeferUntilCommit(s)
+	defer events.DiscardPending(s)
+
+	res, err := Execute(s, &user.User{ID: 1}, &Request{Query: query})
+	require.NoError(t, err)
+
+	if res.HasErrors() {
+		require.NoError(t, s.Rollback())
+	} else {
+		require.NoError(t, s.Commit())
+		events.DispatchPending(s)
+	}
+
+	raw, err := json.Marshal(res)
+	require.NoError(t, err)
+
+	response := struct {
+		Data   json.RawMessage          `json:"data"`
+		Errors []map[string]interface{} `json:"errors"`
+	}{}
+	require.NoError(t, json.Unmarshal(raw, &response))
+	if result != nil {
+		require.NoError(t, json.Unmarshal(response.Data, result))
+	}
+
+	return response.Errors
+}
+
+func TestQuery(t *testing.T) {
+	t.Run("board", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		result := struct {
+			Project struct {
+				Title   string `json:"title"`
+				Buckets []struct {
+					ID    int64 `json:"id"`
+					Tasks []struct {
+						ID     int64  `json:"id"`
+						Title  string `json:"title"`
+						Labels []struct {
+							ID int64 `json:"id"`
+						} `json:"labels"`
+					} `json:"tasks"`
+				} `json:"buckets"`
+			} `json:"project"`
+		}{}
+		errs := execute(t, `{ project(id: 1) { title buckets { id tasks { id title labels { id } } } } }`, &result)
+		require.Empty(t, errs)
+
+		assert.Equal(t, "Test1", result.Project.Title)
+		require.Len(t, result.Project.Buckets, 3)
+		assert.Equal(t, int64(1), result.Project.Buckets[0].ID)
+		require.NotEmpty(t, result.Project.Buckets[0].Tasks)
+
+		var found bool
+		for _, task := range result.Project.Buckets[0].Tasks {
+			if task.ID == 1 {
+				found = true
+				assert.Equal(t, "task #1", task.Title)
+				require.Len(t, task.Labels, 1)
+				assert.Equal(t, int64(4), task.Labels[0].ID)
+			}
+		}
+		assert.True(t, found)
+	})
+	t.Run("task with comments", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		result := struct {
+			Task struct {
+				Title    string `json:"title"`
+				Comments []struct {
+					Comment string `json:"comment"`
+					Author  struct {
+						Username string `json:"username"`
+					} `json:"author"`
+				} `json:"comments"`
+			} `json:"task"`
+		}{}
+		errs := execute(t, `{ task(id: 1) { title comments { comment author { username } } } }`, &result)
+		require.Empty(t, errs)
+		assert.Equal(t, "task #1", result.Task.Title)
+	})
+	t.Run("me", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		result := struct {
+			Me struct {
+				Username string  `json:"username"`
+				Email    *string `json:"email"`
+			} `json:"me"`
+		}{}
+		errs := execute(t, `{ me { username } }`, &result)
+		require.Empty(t, errs)
+		assert.Equal(t, "user1", result.Me.Username)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		result := struct {
+			Project *struct {
+				Title string `json:"title"`
+			} `json:"project"`
+		}{}
+		errs := execute(t, `{ project(id: 20) { title } }`, &result)
+		require.Len(t, errs, 1)
+		assert.Nil(t, result.Project)
+		extensions := errs[0]["extensions"].(map[string]interface{})
+		assert.InDelta(t, models.ErrorCodeGenericForbidden, extensions["code"], 0)
+	})
+	t.Run("email is not exposed", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		errs := execute(t, `{ me { email } }`, nil)
+		require.NotEmpty(t, errs)
+	})
+}
+
+func TestQueryLimits(t *testing.T) {
+	assertTooComplex := func(t *testing.T, errs []map[string]interface{}) {
+		require.Len(t, errs, 1)
+		extensions := errs[0]["extensions"].(map[string]interface{})
+		assert.InDelta(t, models.ErrCodeGraphQLQueryTooComplex, extensions["code"], 0)
+	}
+
+	t.Run("too deeply nested", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		config.GraphQLMaxDepth.Set(3)
+		defer config.GraphQLMaxDepth.Set(10)
+
+		result := map[string]interface{}{}
+		errs := execute(t, `{ project(id: 1) { buckets { tasks { labels { id } } } } }`, &result)
+		assertTooComplex(t, errs)
+		assert.Empty(t, result)
+	})
+	t.Run("too many aliases", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		aliases := []string{}
+		for i := 0; i < 300; i++ {
+			aliases = append(aliases, "p"+strconv.Itoa(i)+`: project(id: 1) { id }`)
+		}
+		result := map[string]interface{}{}
+		errs := execute(t, "{ "+strings.Join(aliases, " ")+" }", &result)
+		assertTooComplex(t, errs)
+		assert.Empty(t, result)
+	})
+	t.Run("fragments count every time they are used", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		config.GraphQLMaxComplexity.Set(9)
+		defer config.GraphQLMaxComplexity.Set(500)
+
+		query := `
+			fragment taskFields on Task { id title done priority }
+			{ a: task(id: 1) { ...taskFields } b: task(id: 1) { ...taskFields } }`
+		errs := execute(t, query, nil)
+		assertTooComplex(t, errs)
+	})
+	t.Run("within the limits", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		config.GraphQLMaxDepth.Set(5)
+		config.GraphQLMaxComplexity.Set(5)
+		defer config.GraphQLMaxDepth.Set(10)
+		defer config.GraphQLMaxComplexity.Set(500)
+
+		errs := execute(t, `{ project(id: 1) { buckets { tasks { labels { id } } } } }`, nil)
+		assert.Empty(t, errs)
+	})
+}
+
+func TestMutation(t *testing.T) {
+	t.Run("create task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		events.Fake()
+
+		result := struct {
+			CreateTask struct {
+				ID    int64  `json:"id"`
+				Title string `json:"title"`
+			} `json:"createTask"`
+		}{}
+		errs := execute(t, `mutation { createTask(project_id: 1, title: "Created with GraphQL", priority: 3) { id title } }`, &result)
+		require.Empty(t, errs)
+		assert.Equal(t, "Created with GraphQL", result.CreateTask.Title)
+		events.AssertDispatched(t, &models.TaskCreatedEvent{})
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         result.CreateTask.ID,
+			"title":      "Created with GraphQL",
+			"project_id": 1,
+			"priority":   3,
+		}, false)
+	})
+	t.Run("update task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		result := struct {
+			UpdateTask struct {
+				Title       string `json:"title"`
+				Description string `json:"description"`
+				Done        bool   `json:"done"`
+			} `json:"updateTask"`
+		}{}
+		errs := execute(t, `mutation { updateTask(id: 1, done: true) { title description done } }`, &result)
+		require.Empty(t, errs)
+		assert.True(t, result.UpdateTask.Done)
+		assert.Equal(t, "task #1", result.UpdateTask.Title)
+		assert.Equal(t, "Lorem Ipsum", result.UpdateTask.Description)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":          1,
+			"title":       "task #1",
+			"description": "Lorem Ipsum",
+			"done":        true,
+		}, false)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		errs := execute(t, `mutation { createTask(project_id: 20, title: "Not allowed") { id } }`, nil)
+		require.Len(t, errs, 1)
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"title": "Not allowed",
+		})
+	})
+	t.Run("no events for rolled back mutations", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		events.Fake()
+
+		errs := execute(t, `mutation {
+			allowed: createTask(project_id: 1, title: "Rolled back") { id }
+			forbidden: createTask(project_id: 20, title: "Not allowed") { id }
+		}`, nil)
+		require.Len(t, errs, 1)
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"title": "Rolled back",
+		})
+		events.AssertNotDispatched(t, &models.TaskCreatedEvent{})
+	})
+	t.Run("add label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		result := struct {
+			AddLabelToTask bool `json:"addLabelToTask"`
+		}{}
+		errs := execute(t, `mutation { addLabelToTask(task_id: 1, label_id: 1) }`, &result)
+		require.Empty(t, errs)
+		assert.True(t, result.AddLabelToTask)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  1,
+			"label_id": 1,
+		}, false)
+	})
+}
@@ -0,0 +1,102 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/models"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// queryLimits checks a query does not exceed the configured depth and complexity. Since every nested list field
+// is resolved with its own database queries, the cost of unlimited queries would grow exponentially.
+type queryLimits struct {
+	maxDepth      int
+	maxComplexity int
+	fragments     map[string]*ast.FragmentDefinition
+	// The number of fields selected so far, fragments are counted every time they are used
+	complexity int
+}
+
+func checkQueryLimits(doc *ast.Document) error {
+	l := &queryLimits{
+		maxDepth:      config.GraphQLMaxDepth.GetInt(),
+		maxComplexity: config.GraphQLMaxComplexity.GetInt(),
+		fragments:     make(map[string]*ast.FragmentDefinition),
+	}
+
+	for _, def := range doc.Definitions {
+		if fragment, is := def.(*ast.FragmentDefinition); is {
+			l.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		if operation, is := def.(*ast.OperationDefinition); is {
+			if err := l.checkSelectionSet(operation.SelectionSet, 1, map[string]bool{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *queryLimits) checkSelectionSet(set *ast.SelectionSet, depth int, usedFragments map[string]bool) error {
+	if set == nil {
+		return nil
+	}
+	if depth > l.maxDepth {
+		return l.tooComplex()
+	}
+
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			l.complexity++
+			if l.complexity > l.maxComplexity {
+				return l.tooComplex()
+			}
+			if err := l.checkSelectionSet(selection.SelectionSet, depth+1, usedFragments); err != nil {
+				return err
+			}
+		case *ast.InlineFragment:
+			if err := l.checkSelectionSet(selection.SelectionSet, depth, usedFragments); err != nil {
+				return err
+			}
+		case *ast.FragmentSpread:
+			fragment, has := l.fragments[selection.Name.Value]
+			// Unknown and cyclic fragments are rejected by the validation of the query
+			if !has || usedFragments[fragment.Name.Value] {
+				continue
+			}
+			usedFragments[fragment.Name.Value] = true
+			err := l.checkSelectionSet(fragment.SelectionSet, depth, usedFragments)
+			delete(usedFragments, fragment.Name.Value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *queryLimits) tooComplex() error {
+	return &models.ErrGraphQLQueryTooComplex{MaxDepth: l.maxDepth, MaxComplexity: l.maxComplexity}
+}
@@ -0,0 +1,252 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/models"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+func idArgs(names ...string) gql.FieldConfigArgument {
+	args := gql.FieldConfigArgument{}
+	for _, name := range names {
+		args[name] = &gql.ArgumentConfig{Type: gql.NewNonNull(gql.Int)}
+	}
+	return args
+}
+
+func withArgs(args gql.FieldConfigArgument, more gql.FieldConfigArgument) gql.FieldConfigArgument {
+	for name, arg := range more {
+		args[name] = arg
+	}
+	return args
+}
+
+var taskInputArgs = gql.FieldConfigArgument{
+	"title":        &gql.ArgumentConfig{Type: gql.String},
+	"description":  &gql.ArgumentConfig{Type: gql.String},
+	"done":         &gql.ArgumentConfig{Type: gql.Boolean},
+	"due_date":     &gql.ArgumentConfig{Type: gql.DateTime},
+	"start_date":   &gql.ArgumentConfig{Type: gql.DateTime},
+	"end_date":     &gql.ArgumentConfig{Type: gql.DateTime},
+	"priority":     &gql.ArgumentConfig{Type: gql.Int},
+	"percent_done": &gql.ArgumentConfig{Type: gql.Float},
+	"hex_color":    &gql.ArgumentConfig{Type: gql.String},
+	"bucket_id":    &gql.ArgumentConfig{Type: gql.Int},
+}
+
+// applyTaskArgs sets all task properties which were passed as arguments
+func applyTaskArgs(p gql.ResolveParams, t *models.Task) {
+	for name, value := range p.Args {
+		switch name {
+		case "title":
+			t.Title = value.(string)
+		case "description":
+			t.Description = value.(string)
+		case "done":
+			t.Done = value.(bool)
+		case "due_date":
+			t.DueDate = value.(time.Time)
+		case "start_date":
+			t.StartDate = value.(time.Time)
+		case "end_date":
+			t.EndDate = value.(time.Time)
+		case "priority":
+			t.Priority = int64(value.(int))
+		case "percent_done":
+			t.PercentDone = value.(float64)
+		case "hex_color":
+			t.HexColor = value.(string)
+		case "bucket_id":
+			t.BucketID = int64(value.(int))
+		case "project_id":
+			t.ProjectID = int64(value.(int))
+		}
+	}
+}
+
+var projectInputArgs = gql.FieldConfigArgument{
+	"title":             &gql.ArgumentConfig{Type: gql.String},
+	"description":       &gql.ArgumentConfig{Type: gql.String},
+	"hex_color":         &gql.ArgumentConfig{Type: gql.String},
+	"parent_project_id": &gql.ArgumentConfig{Type: gql.Int},
+	"is_archived":       &gql.ArgumentConfig{Type: gql.Boolean},
+}
+
+// applyProjectArgs sets all project properties which were passed as arguments
+func applyProjectArgs(p gql.ResolveParams, project *models.Project) {
+	for name, value := range p.Args {
+		switch name {
+		case "title":
+			project.Title = value.(string)
+		case "description":
+			project.Description = value.(string)
+		case "hex_color":
+			project.HexColor = value.(string)
+		case "parent_project_id":
+			project.ParentProjectID = int64(value.(int))
+		case "is_archived":
+			project.IsArchived = value.(bool)
+		}
+	}
+}
+
+var mutationType = gql.NewObject(gql.ObjectConfig{
+	Name: "Mutation",
+	Fields: gql.Fields{
+		"createTask": &gql.Field{
+			Type: taskType,
+			Args: withArgs(idArgs("project_id"), taskInputArgs),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				t := &models.Task{}
+				applyTaskArgs(p, t)
+				return create(p, t)
+			},
+		},
+		"updateTask": &gql.Field{
+			Type:        taskType,
+			Description: "Changes the passed properties of a task, all others keep their value.",
+			Args: withArgs(idArgs("id"), withArgs(gql.FieldConfigArgument{
+				"project_id": &gql.ArgumentConfig{Type: gql.Int, Description: "Moves the task to another project."},
+			}, taskInputArgs)),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				t := &models.Task{ID: int64Arg(p, "id")}
+				_, err := readOne(p, t)
+				if err != nil {
+					return nil, err
+				}
+				applyTaskArgs(p, t)
+				return update(p, t)
+			},
+		},
+		"deleteTask": &gql.Field{
+			Type: gql.Boolean,
+			Args: idArgs("id"),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return remove(p, &models.Task{ID: int64Arg(p, "id")})
+			},
+		},
+		"createProject": &gql.Field{
+			Type: projectType,
+			Args: projectInputArgs,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				project := &models.Project{}
+				applyProjectArgs(p, project)
+				return create(p, project)
+			},
+		},
+		"updateProject": &gql.Field{
+			Type:        projectType,
+			Description: "Changes the passed properties of a project, all others keep their value.",
+			Args:        withArgs(idArgs("id"), projectInputArgs),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				project := &models.Project{ID: int64Arg(p, "id")}
+				_, err := readOne(p, project)
+				if err != nil {
+					return nil, err
+				}
+				applyProjectArgs(p, project)
+				return update(p, project)
+			},
+		},
+		"deleteProject": &gql.Field{
+			Type: gql.Boolean,
+			Args: idArgs("id"),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return remove(p, &models.Project{ID: int64Arg(p, "id")})
+			},
+		},
+		"createBucket": &gql.Field{
+			Type: bucketType,
+			Args: withArgs(idArgs("project_id"), gql.FieldConfigArgument{
+				"title": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+				"limit": &gql.ArgumentConfig{Type: gql.Int},
+			}),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return create(p, &models.Bucket{
+					ProjectID: int64Arg(p, "project_id"),
+					Title:     stringArg(p, "title"),
+					Limit:     int64Arg(p, "limit"),
+				})
+			},
+		},
+		"deleteBucket": &gql.Field{
+			Type: gql.Boolean,
+			Args: idArgs("id", "project_id"),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return remove(p, &models.Bucket{ID: int64Arg(p, "id"), ProjectID: int64Arg(p, "project_id")})
+			},
+		},
+		"createLabel": &gql.Field{
+			Type: labelType,
+			Args: gql.FieldConfigArgument{
+				"title":       &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+				"description": &gql.ArgumentConfig{Type: gql.String},
+				"hex_color":   &gql.ArgumentConfig{Type: gql.String},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return create(p, &models.Label{
+					Title:       stringArg(p, "title"),
+					Description: stringArg(p, "description"),
+					HexColor:    stringArg(p, "hex_color"),
+				})
+			},
+		},
+		"addLabelToTask": &gql.Field{
+			Type: gql.Boolean,
+			Args: idArgs("task_id", "label_id"),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				_, err := create(p, &models.LabelTask{TaskID: int64Arg(p, "task_id"), LabelID: int64Arg(p, "label_id")})
+				return err == nil, err
+			},
+		},
+		"removeLabelFromTask": &gql.Field{
+			Type: gql.Boolean,
+			Args: idArgs("task_id", "label_id"),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return remove(p, &models.LabelTask{TaskID: int64Arg(p, "task_id"), LabelID: int64Arg(p, "label_id")})
+			},
+		},
+		"addAssignee": &gql.Field{
+			Type: gql.Boolean,
+			Args: idArgs("task_id", "user_id"),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				_, err := create(p, &models.TaskAssginee{TaskID: int64Arg(p, "task_id"), UserID: int64Arg(p, "user_id")})
+				return err == nil, err
+			},
+		},
+		"removeAssignee": &gql.Field{
+			Type: gql.Boolean,
+			Args: idArgs("task_id", "user_id"),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return remove(p, &models.TaskAssginee{TaskID: int64Arg(p, "task_id"), UserID: int64Arg(p, "user_id")})
+			},
+		},
+		"createComment": &gql.Field{
+			Type: commentType,
+			Args: withArgs(idArgs("task_id"), gql.FieldConfigArgument{
+				"comment": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+			}),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return create(p, &models.TaskComment{TaskID: int64Arg(p, "task_id"), Comment: stringArg(p, "comment")})
+			},
+		},
+	},
+})
@@ -0,0 +1,282 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+type crudable interface {
+	web.CRUDable
+	web.Rights
+}
+
+// The following functions do the same rights checks as the web handlers of the REST api before calling the models.
+
+func readOne(p gql.ResolveParams, obj crudable) (interface{}, error) {
+	s, a := fromContext(p.Context)
+	can, _, err := obj.CanRead(s, a)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if !can {
+		return nil, handleError(models.ErrGenericForbidden{})
+	}
+
+	err = obj.ReadOne(s, a)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	return obj, nil
+}
+
+func readAll(p gql.ResolveParams, obj crudable, search string) (interface{}, error) {
+	s, a := fromContext(p.Context)
+	page, perPage := getPagination(p)
+	result, _, _, err := obj.ReadAll(s, a, search, page, perPage)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	return result, nil
+}
+
+func create(p gql.ResolveParams, obj crudable) (interface{}, error) {
+	s, a := fromContext(p.Context)
+	err := validate(obj)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	can, err := obj.CanCreate(s, a)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if !can {
+		return nil, handleError(models.ErrGenericForbidden{})
+	}
+
+	err = obj.Create(s, a)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	return obj, nil
+}
+
+func update(p gql.ResolveParams, obj crudable) (interface{}, error) {
+	s, a := fromContext(p.Context)
+	err := validate(obj)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	can, err := obj.CanUpdate(s, a)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if !can {
+		return nil, handleError(models.ErrGenericForbidden{})
+	}
+
+	err = obj.Update(s, a)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	return obj, nil
+}
+
+func remove(p gql.ResolveParams, obj crudable) (interface{}, error) {
+	s, a := fromContext(p.Context)
+	can, err := obj.CanDelete(s, a)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if !can {
+		return nil, handleError(models.ErrGenericForbidden{})
+	}
+
+	err = obj.Delete(s, a)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	return true, nil
+}
+
+func getPagination(p gql.ResolveParams) (page, perPage int) {
+	page, _ = p.Args["page"].(int)
+	if page < 1 {
+		page = 1
+	}
+
+	maxPerPage := config.ServiceMaxItemsPerPage.GetInt()
+	perPage, _ = p.Args["per_page"].(int)
+	if perPage < 1 || perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	return
+}
+
+func int64Arg(p gql.ResolveParams, name string) int64 {
+	v, _ := p.Args[name].(int)
+	return int64(v)
+}
+
+func stringArg(p gql.ResolveParams, name string) string {
+	v, _ := p.Args[name].(string)
+	return v
+}
+
+func stringSliceArg(p gql.ResolveParams, name string) (values []string) {
+	raw, _ := p.Args[name].([]interface{})
+	for _, v := range raw {
+		if str, is := v.(string); is {
+			values = append(values, str)
+		}
+	}
+	return
+}
+
+func newTaskCollection(p gql.ResolveParams, projectID int64) *models.TaskCollection {
+	return &models.TaskCollection{
+		ProjectID: projectID,
+		Filter:    stringArg(p, "filter"),
+		SortBy:    stringSliceArg(p, "sort_by"),
+		OrderBy:   stringSliceArg(p, "order_by"),
+	}
+}
+
+func resolveProjectTasks(p gql.ResolveParams) (interface{}, error) {
+	project := p.Source.(*models.Project)
+	return readAll(p, newTaskCollection(p, project.ID), stringArg(p, "search"))
+}
+
+func resolveProjectBuckets(p gql.ResolveParams) (interface{}, error) {
+	project := p.Source.(*models.Project)
+	b := &models.Bucket{
+		ProjectID: project.ID,
+		TaskCollection: models.TaskCollection{
+			ProjectID: project.ID,
+			Filter:    stringArg(p, "filter"),
+		},
+	}
+	return readAll(p, b, stringArg(p, "search"))
+}
+
+func resolveTaskComments(p gql.ResolveParams) (interface{}, error) {
+	task := p.Source.(*models.Task)
+	return readAll(p, &models.TaskComment{TaskID: task.ID}, "")
+}
+
+var queryType = gql.NewObject(gql.ObjectConfig{
+	Name: "Query",
+	Fields: gql.Fields{
+		"me": &gql.Field{
+			Type:        userType,
+			Description: "The current user. Null when authenticated with a link share.",
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				s, a := fromContext(p.Context)
+				if _, is := a.(*user.User); !is {
+					return nil, nil
+				}
+				u, err := user.GetUserByID(s, a.GetID())
+				return u, handleError(err)
+			},
+		},
+		"users": &gql.Field{
+			Type:        gql.NewList(userType),
+			Description: "Search for users, the same way as the /users endpoint of the REST api.",
+			Args: gql.FieldConfigArgument{
+				"search": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				s, a := fromContext(p.Context)
+				if _, is := a.(*user.User); !is {
+					return nil, handleError(models.ErrGenericForbidden{})
+				}
+				users, err := user.ListUsers(s, stringArg(p, "search"), nil)
+				return users, handleError(err)
+			},
+		},
+		"projects": &gql.Field{
+			Type: gql.NewList(projectType),
+			Args: func() gql.FieldConfigArgument {
+				args := paginationArgs()
+				args["search"] = &gql.ArgumentConfig{Type: gql.String}
+				args["is_archived"] = &gql.ArgumentConfig{
+					Type:        gql.Boolean,
+					Description: "If true, archived projects are returned as well.",
+				}
+				return args
+			}(),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				archived, _ := p.Args["is_archived"].(bool)
+				return readAll(p, &models.Project{IsArchived: archived}, stringArg(p, "search"))
+			},
+		},
+		"project": &gql.Field{
+			Type: projectType,
+			Args: gql.FieldConfigArgument{
+				"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.Int)},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return readOne(p, &models.Project{ID: int64Arg(p, "id")})
+			},
+		},
+		"tasks": &gql.Field{
+			Type:        gql.NewList(taskType),
+			Description: "All tasks of all projects the user has access to.",
+			Args:        taskListArgs(),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return readAll(p, newTaskCollection(p, 0), stringArg(p, "search"))
+			},
+		},
+		"task": &gql.Field{
+			Type: taskType,
+			Args: gql.FieldConfigArgument{
+				"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.Int)},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return readOne(p, &models.Task{ID: int64Arg(p, "id")})
+			},
+		},
+		"labels": &gql.Field{
+			Type: gql.NewList(labelType),
+			Args: func() gql.FieldConfigArgument {
+				args := paginationArgs()
+				args["search"] = &gql.ArgumentConfig{Type: gql.String}
+				return args
+			}(),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				result, err := readAll(p, &models.Label{}, stringArg(p, "search"))
+				if err != nil {
+					return nil, err
+				}
+				labelsWithTaskIDs := result.([]*models.LabelWithTaskID)
+				labels := make([]*models.Label, 0, len(labelsWithTaskIDs))
+				for _, l := range labelsWithTaskIDs {
+					labels = append(labels, &l.Label)
+				}
+				return labels, nil
+			},
+		},
+	},
+})
@@ -0,0 +1,186 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"time"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+// All fields use the same names as the properties of the REST api. They are resolved from the json names of the models.
+
+// nullableTime resolves a time field and returns null instead of a zero time
+func nullableTime(p gql.ResolveParams) (interface{}, error) {
+	v, err := gql.DefaultResolveFn(p)
+	if t, is := v.(time.Time); is && t.IsZero() {
+		return nil, err
+	}
+	return v, err
+}
+
+var userType = gql.NewObject(gql.ObjectConfig{
+	Name:        "User",
+	Description: "A user. The email address of a user is never exposed.",
+	Fields: gql.Fields{
+		"id":       &gql.Field{Type: gql.NewNonNull(gql.Int)},
+		"username": &gql.Field{Type: gql.NewNonNull(gql.String)},
+		"name":     &gql.Field{Type: gql.String},
+		"created":  &gql.Field{Type: gql.DateTime},
+		"updated":  &gql.Field{Type: gql.DateTime},
+	},
+})
+
+var labelType = gql.NewObject(gql.ObjectConfig{
+	Name: "Label",
+	Fields: gql.Fields{
+		"id":          &gql.Field{Type: gql.NewNonNull(gql.Int)},
+		"title":       &gql.Field{Type: gql.NewNonNull(gql.String)},
+		"description": &gql.Field{Type: gql.String},
+		"hex_color":   &gql.Field{Type: gql.String},
+		"created_by":  &gql.Field{Type: userType},
+		"created":     &gql.Field{Type: gql.DateTime},
+		"updated":     &gql.Field{Type: gql.DateTime},
+	},
+})
+
+var commentType = gql.NewObject(gql.ObjectConfig{
+	Name: "Comment",
+	Fields: gql.Fields{
+		"id":      &gql.Field{Type: gql.NewNonNull(gql.Int)},
+		"comment": &gql.Field{Type: gql.NewNonNull(gql.String)},
+		"author":  &gql.Field{Type: userType},
+		"created": &gql.Field{Type: gql.DateTime},
+		"updated": &gql.Field{Type: gql.DateTime},
+	},
+})
+
+var taskType = gql.NewObject(gql.ObjectConfig{
+	Name: "Task",
+	Fields: gql.Fields{
+		"id":              &gql.Field{Type: gql.NewNonNull(gql.Int)},
+		"title":           &gql.Field{Type: gql.NewNonNull(gql.String)},
+		"description":     &gql.Field{Type: gql.String},
+		"done":            &gql.Field{Type: gql.Boolean},
+		"done_at":         &gql.Field{Type: gql.DateTime, Resolve: nullableTime},
+		"due_date":        &gql.Field{Type: gql.DateTime, Resolve: nullableTime},
+		"start_date":      &gql.Field{Type: gql.DateTime, Resolve: nullableTime},
+		"end_date":        &gql.Field{Type: gql.DateTime, Resolve: nullableTime},
+		"priority":        &gql.Field{Type: gql.Int},
+		"percent_done":    &gql.Field{Type: gql.Float},
+		"hex_color":       &gql.Field{Type: gql.String},
+		"identifier":      &gql.Field{Type: gql.String},
+		"index":           &gql.Field{Type: gql.Int},
+		"project_id":      &gql.Field{Type: gql.Int},
+		"bucket_id":       &gql.Field{Type: gql.Int},
+		"position":        &gql.Field{Type: gql.Float},
+		"kanban_position": &gql.Field{Type: gql.Float},
+		"is_favorite":     &gql.Field{Type: gql.Boolean},
+		"created_by":      &gql.Field{Type: userType},
+		"assignees":       &gql.Field{Type: gql.NewList(userType)},
+		"labels":          &gql.Field{Type: gql.NewList(labelType)},
+		"comments": &gql.Field{
+			Type:    gql.NewList(commentType),
+			Args:    paginationArgs(),
+			Resolve: resolveTaskComments,
+		},
+		"created": &gql.Field{Type: gql.DateTime},
+		"updated": &gql.Field{Type: gql.DateTime},
+	},
+})
+
+var bucketType = gql.NewObject(gql.ObjectConfig{
+	Name: "Bucket",
+	Fields: gql.Fields{
+		"id":         &gql.Field{Type: gql.NewNonNull(gql.Int)},
+		"title":      &gql.Field{Type: gql.NewNonNull(gql.String)},
+		"project_id": &gql.Field{Type: gql.Int},
+		"limit":      &gql.Field{Type: gql.Int},
+		"count":      &gql.Field{Type: gql.Int},
+		"position":   &gql.Field{Type: gql.Float},
+		"created_by": &gql.Field{Type: userType},
+		"tasks": &gql.Field{
+			Type:        gql.NewList(taskType),
+			Description: "The tasks in this bucket. Only available when the bucket was loaded through the buckets of a project.",
+		},
+		"created": &gql.Field{Type: gql.DateTime},
+		"updated": &gql.Field{Type: gql.DateTime},
+	},
+})
+
+var projectType = gql.NewObject(gql.ObjectConfig{
+	Name: "Project",
+	Fields: gql.Fields{
+		"id":                &gql.Field{Type: gql.NewNonNull(gql.Int)},
+		"title":             &gql.Field{Type: gql.NewNonNull(gql.String)},
+		"description":       &gql.Field{Type: gql.String},
+		"identifier":        &gql.Field{Type: gql.String},
+		"hex_color":         &gql.Field{Type: gql.String},
+		"parent_project_id": &gql.Field{Type: gql.Int},
+		"done_bucket_id":    &gql.Field{Type: gql.Int},
+		"default_bucket_id": &gql.Field{Type: gql.Int},
+		"is_archived":       &gql.Field{Type: gql.Boolean},
+		"is_favorite":       &gql.Field{Type: gql.Boolean},
+		"position":          &gql.Field{Type: gql.Float},
+		"owner":             &gql.Field{Type: userType},
+		"buckets": &gql.Field{
+			Type:        gql.NewList(bucketType),
+			Description: "The kanban buckets of the project, with their tasks.",
+			Args: gql.FieldConfigArgument{
+				"filter": &gql.ArgumentConfig{Type: gql.String, Description: "A filter query for the tasks in the buckets."},
+				"search": &gql.ArgumentConfig{Type: gql.String},
+				"per_page": &gql.ArgumentConfig{
+					Type:        gql.Int,
+					Description: "The maximum number of tasks per bucket.",
+				},
+			},
+			Resolve: resolveProjectBuckets,
+		},
+		"tasks": &gql.Field{
+			Type:    gql.NewList(taskType),
+			Args:    taskListArgs(),
+			Resolve: resolveProjectTasks,
+		},
+		"created": &gql.Field{Type: gql.DateTime},
+		"updated": &gql.Field{Type: gql.DateTime},
+	},
+})
+
+func paginationArgs() gql.FieldConfigArgument {
+	return gql.FieldConfigArgument{
+		"page": &gql.ArgumentConfig{
+			Type:         gql.Int,
+			DefaultValue: 1,
+		},
+		"per_page": &gql.ArgumentConfig{
+			Type:        gql.Int,
+			Description: "The maximum number of items per page. This is limited by the configured maximum of items per page.",
+		},
+	}
+}
+
+func taskListArgs() gql.FieldConfigArgument {
+	args := paginationArgs()
+	args["filter"] = &gql.ArgumentConfig{
+		Type:        gql.String,
+		Description: "The filter query to match tasks by. Check out https://vikunja.io/docs/filters for a full explanation.",
+	}
+	args["search"] = &gql.ArgumentConfig{Type: gql.String}
+	args["sort_by"] = &gql.ArgumentConfig{Type: gql.NewList(gql.String)}
+	args["order_by"] = &gql.ArgumentConfig{Type: gql.NewList(gql.String)}
+	return args
+}
@@ -124,6 +124,7 @@ func createProjectWithEverything(s *xorm.Session, project *models.ProjectWithTas
 	// to be able to still loop over them aftere the project was created.
 	tasks := project.Tasks
 	originalBuckets := project.Buckets
+	originalCustomFields := project.CustomFields
 	originalBackgroundInformation := project.BackgroundInformation
 	needsDefaultBucket := false
 
@@ -174,6 +175,19 @@ func createProjectWithEverything(s *xorm.Session, project *models.ProjectWithTas
 		oldID := bucket.ID
 		bucket.ID = 0 // We want a new id
 		bucket.ProjectID = project.ID
+
+		// Actions referencing users or labels can't be mapped to the new instance
+		actions := make([]*models.BucketAction, 0, len(bucket.Actions))
+		for _, action := range bucket.Actions {
+			if action.Kind == models.BucketActionAddAssignee ||
+				action.Kind == models.BucketActionAddLabel ||
+				action.Kind == models.BucketActionRemoveLabel {
+				continue
+			}
+			actions = append(actions, action)
+		}
+		bucket.Actions = actions
+
 		err = bucket.Create(s, user)
 		if err != nil {
 			return
@@ -182,6 +196,35 @@ func createProjectWithEverything(s *xorm.Session, project *models.ProjectWithTas
 		log.Debugf("[creating structure] Created bucket %d, old ID was %d", bucket.ID, oldID)
 	}
 
+	// Create all custom fields
+	customFields := make(map[int64]int64) // old custom field id is the key, new id the value
+	for _, cf := range originalCustomFields {
+		oldID := cf.ID
+		cf.ProjectID = project.ID
+		err = cf.Create(s, user)
+		if err != nil {
+			return
+		}
+		customFields[oldID] = cf.ID
+		log.Debugf("[creating structure] Created custom field %d, old ID was %d", cf.ID, oldID)
+	}
+
+	setCustomFieldIDs := func(task *models.Task) {
+		if task.CustomFields == nil {
+			return
+		}
+		values := make(map[int64]interface{}, len(task.CustomFields))
+		for oldID, value := range task.CustomFields {
+			newID, exists := customFields[oldID]
+			if !exists {
+				log.Debugf("[creating structure] No custom field created for original custom field id %d", oldID)
+				continue
+			}
+			values[newID] = value
+		}
+		task.CustomFields = values
+	}
+
 	log.Debugf("[creating structure] Creating %d tasks", len(tasks))
 
 	setBucketOrDefault := func(task *models.Task) {
@@ -201,9 +244,12 @@ func createProjectWithEverything(s *xorm.Session, project *models.ProjectWithTas
 	// Create all tasks
 	for i, t := range tasks {
 		setBucketOrDefault(&tasks[i].Task)
+		setCustomFieldIDs(&tasks[i].Task)
 
 		oldid := t.ID
 		t.ProjectID = project.ID
+		// The parent is restored from the subtask relations below
+		t.ParentTaskID = 0
 		err = t.Create(s, user)
 
 		if err != nil && models.IsErrTaskCannotBeEmpty(err) {
@@ -232,7 +278,9 @@ func createProjectWithEverything(s *xorm.Session, project *models.ProjectWithTas
 				if _, exists := tasksByOldID[rt.ID]; !exists || rt.ID == 0 {
 					oldid := rt.ID
 					setBucketOrDefault(rt)
+					setCustomFieldIDs(rt)
 					rt.ProjectID = t.ProjectID
+					rt.ParentTaskID = 0
 					err = rt.Create(s, user)
 					if err != nil {
 						return
@@ -265,8 +313,19 @@ func createProjectWithEverything(s *xorm.Session, project *models.ProjectWithTas
 			log.Debugf("[creating structure] Creating %d attachments", len(t.Attachments))
 		}
 		for _, a := range t.Attachments {
+			if a.IsLink() {
+				a.ID = 0
+				a.TaskID = t.ID
+				err = a.Create(s, user)
+				if err != nil {
+					return
+				}
+				log.Debugf("[creating structure] Created new link attachment %d", a.ID)
+				continue
+			}
+
 			// Check if we have a file to create
-			if len(a.File.FileContent) > 0 {
+			if a.File != nil && len(a.File.FileContent) > 0 {
 				oldID := a.ID
 				a.ID = 0
 				a.TaskID = t.ID
@@ -352,6 +411,7 @@ func createProjectWithEverything(s *xorm.Session, project *models.ProjectWithTas
 
 	project.Tasks = tasks
 	project.Buckets = originalBuckets
+	project.CustomFields = originalCustomFields
 
 	return nil
 }
@@ -22,8 +22,25 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+
+	"code.vikunja.io/api/pkg/outbound"
+)
+
+var (
+	httpClient     *http.Client
+	httpClientOnce sync.Once
 )
 
+// HTTPClient returns the client which is used for all requests of migrations.
+// It does not have a timeout since attachments of migrated tasks can be large.
+func HTTPClient() *http.Client {
+	httpClientOnce.Do(func() {
+		httpClient = outbound.NewClient(&outbound.Options{})
+	})
+	return httpClient
+}
+
 // DownloadFile downloads a file and returns its contents
 func DownloadFile(url string) (buf *bytes.Buffer, err error) {
 	return DownloadFileWithHeaders(url, nil)
@@ -42,8 +59,7 @@ func DownloadFileWithHeaders(url string, headers http.Header) (buf *bytes.Buffer
 		}
 	}
 
-	hc := http.Client{}
-	resp, err := hc.Do(req)
+	resp, err := HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +88,5 @@ func DoPostWithHeaders(url string, form url.Values, headers map[string]string) (
 		req.Header.Add(key, value)
 	}
 
-	hc := http.Client{}
-	return hc.Do(req)
+	return HTTPClient().Do(req)
 }
@@ -17,17 +17,15 @@
 package trello
 
 import (
-	"bytes"
-
 	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/api/pkg/files"
 	"code.vikunja.io/api/pkg/log"
 	"code.vikunja.io/api/pkg/models"
 	"code.vikunja.io/api/pkg/modules/migration"
 	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
 
 	"github.com/adlio/trello"
-	"github.com/yuin/goldmark"
 )
 
 // Migration represents the trello migration struct
@@ -184,16 +182,6 @@ func getTrelloData(token string) (trelloData []*trello.Board, err error) {
 	return
 }
 
-func convertMarkdownToHTML(input string) (output string, err error) {
-	var buf bytes.Buffer
-	err = goldmark.Convert([]byte(input), &buf)
-	if err != nil {
-		return
-	}
-	//#nosec - we are not responsible to escape this as we don't know the context where it is used
-	return buf.String(), nil
-}
-
 // Converts all previously obtained data from trello into the vikunja format.
 // `trelloData` should contain all boards with their projects and cards respectively.
 func convertTrelloDataToVikunja(trelloData []*trello.Board, token string) (fullVikunjaHierachie []*models.ProjectWithTasksAndBuckets, err error) {
@@ -258,7 +246,7 @@ func convertTrelloDataToVikunja(trelloData []*trello.Board, token string) (fullV
 					BucketID:       bucketID,
 				}
 
-				task.Description, err = convertMarkdownToHTML(card.Desc)
+				task.Description, err = utils.MarkdownToHTML(card.Desc)
 				if err != nil {
 					return nil, err
 				}
@@ -267,19 +255,14 @@ func convertTrelloDataToVikunja(trelloData []*trello.Board, token string) (fullV
 					task.DueDate = *card.Due
 				}
 
-				// Checklists (as markdown in description)
+				// Checklists
 				for _, checklist := range card.Checklists {
-					task.Description += "\n\n<h2> " + checklist.Name + "</h2>\n\n" + `<ul data-type="taskList">`
-
 					for _, item := range checklist.CheckItems {
-						task.Description += "\n"
-						if item.State == "complete" {
-							task.Description += `<li data-checked="true" data-type="taskItem"><label><input type="checkbox" checked="checked"><span></span></label><div><p>` + item.Name + `</p></div></li>`
-						} else {
-							task.Description += `<li data-checked="false" data-type="taskItem"><label><input type="checkbox"><span></span></label><div><p>` + item.Name + `</p></div></li>`
-						}
+						task.ChecklistItems = append(task.ChecklistItems, &models.ChecklistItem{
+							Title: item.Name,
+							Done:  item.State == "complete",
+						})
 					}
-					task.Description += "</ul>"
 				}
 				if len(card.Checklists) > 0 {
 					log.Debugf("[Trello Migration] Converted %d checklists from card %s", len(card.Checklists), card.ID)
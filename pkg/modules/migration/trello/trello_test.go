@@ -258,19 +258,12 @@ func TestConvertTrelloToVikunja(t *testing.T) {
 				{
 					Task: models.Task{
 						Title: "Test Card 2",
-						Description: `
-
-<h2> Checkproject 1</h2>
-
-<ul data-type="taskList">
-<li data-checked="false" data-type="taskItem"><label><input type="checkbox"><span></span></label><div><p>Pending Task</p></div></li>
-<li data-checked="true" data-type="taskItem"><label><input type="checkbox" checked="checked"><span></span></label><div><p>Completed Task</p></div></li></ul>
-
-<h2> Checkproject 2</h2>
-
-<ul data-type="taskList">
-<li data-checked="false" data-type="taskItem"><label><input type="checkbox"><span></span></label><div><p>Pending Task</p></div></li>
-<li data-checked="false" data-type="taskItem"><label><input type="checkbox"><span></span></label><div><p>Another Pending Task</p></div></li></ul>`,
+						ChecklistItems: []*models.ChecklistItem{
+							{Title: "Pending Task"},
+							{Title: "Completed Task", Done: true},
+							{Title: "Pending Task"},
+							{Title: "Another Pending Task"},
+						},
 						BucketID:       1,
 						KanbanPosition: 124,
 					},
@@ -249,6 +249,10 @@ func addDetailsToProject(l *models.ProjectWithTasksAndBuckets, storedFiles map[i
 			comment.ID = 0
 		}
 		for _, attachment := range t.Attachments {
+			if attachment.IsLink() {
+				attachment.ID = 0
+				continue
+			}
 			attachmentFile, exists := storedFiles[attachment.File.ID]
 			if !exists {
 				log.Debugf(logPrefix+"Could not find attachment file %d for attachment %d", attachment.File.ID, attachment.ID)
@@ -0,0 +1,258 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package plugins
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// Hook is the name of a global Lua function a plugin can define to be called at a certain point.
+type Hook string
+
+const (
+	// HookTaskCreate is called before a task is created.
+	HookTaskCreate Hook = "on_task_create"
+	// HookTaskUpdate is called before a task is updated.
+	HookTaskUpdate Hook = "on_task_update"
+	// HookTaskDelete is called before a task is deleted. Changes to the task are ignored.
+	HookTaskDelete Hook = "on_task_delete"
+)
+
+// VetoError is returned when a plugin rejected an operation
+type VetoError struct {
+	Plugin string
+	Reason string
+}
+
+func (err *VetoError) Error() string {
+	return "plugin " + err.Plugin + " rejected the operation: " + err.Reason
+}
+
+type plugin struct {
+	name  string
+	proto *lua.FunctionProto
+}
+
+var plugins []*plugin
+
+// Enabled returns whether plugins are enabled and at least one was loaded.
+func Enabled() bool {
+	return config.PluginsEnabled.GetBool() && len(plugins) > 0
+}
+
+// Init loads and compiles all Lua scripts from the configured plugin directory, in alphabetical order.
+func Init() (err error) {
+	plugins = nil
+
+	if !config.PluginsEnabled.GetBool() {
+		return nil
+	}
+
+	dir := config.PluginsPath.GetString()
+	if dir == "" {
+		dir = filepath.Join(config.ServiceRootpath.GetString(), "plugins")
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.lua"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		p, err := compile(path)
+		if err != nil {
+			return err
+		}
+		plugins = append(plugins, p)
+		log.Infof("Loaded plugin %s", p.name)
+	}
+
+	return nil
+}
+
+func compile(path string) (*plugin, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), ".lua")
+	chunk, err := parse.Parse(f, name)
+	if err != nil {
+		return nil, err
+	}
+
+	proto, err := lua.Compile(chunk, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plugin{name: name, proto: proto}, nil
+}
+
+// newState creates a sandboxed Lua state which only has access to the base, table, string and math libraries.
+// Functions which could load other code or access the file system are removed.
+func newState(name string) *lua.LState {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+
+	for _, fn := range []string{"dofile", "loadfile", "load", "loadstring", "require", "module"} {
+		L.SetGlobal(fn, lua.LNil)
+	}
+
+	L.SetGlobal("log", L.NewFunction(func(L *lua.LState) int {
+		log.Infof("[Plugin %s] %s", name, L.CheckString(1))
+		return 0
+	}))
+
+	return L
+}
+
+// Run calls the hook of all plugins which define it, one after another. The data is passed to the hook as a table.
+// A plugin can change the values of the table, which are then written back to the data and passed to the next plugin.
+// Only existing keys are written back and only if the new value has the same type. If a hook returns false, the
+// operation is rejected with a VetoError, using the second return value as reason.
+// A plugin which fails or runs longer than the configured timeout is skipped, so a broken plugin does not block
+// all operations.
+func Run(hook Hook, data map[string]interface{}) (err error) {
+	if !Enabled() {
+		return nil
+	}
+
+	for _, p := range plugins {
+		err = p.run(hook, data)
+		if _, is := err.(*VetoError); is {
+			return err
+		}
+		if err != nil {
+			log.Errorf("Could not run hook %s of plugin %s: %s", hook, p.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *plugin) run(hook Hook, data map[string]interface{}) (err error) {
+	L := newState(p.name)
+	defer L.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.PluginsTimeoutMilliseconds.GetInt())*time.Millisecond)
+	defer cancel()
+	L.SetContext(ctx)
+
+	L.Push(L.NewFunctionFromProto(p.proto))
+	err = L.PCall(0, lua.MultRet, nil)
+	if err != nil {
+		return err
+	}
+
+	fn, is := L.GetGlobal(string(hook)).(*lua.LFunction)
+	if !is {
+		return nil
+	}
+
+	table := L.NewTable()
+	for key, value := range data {
+		table.RawSetString(key, toLuaValue(value))
+	}
+
+	err = L.CallByParam(lua.P{Fn: fn, NRet: 2, Protect: true}, table)
+	if err != nil {
+		return err
+	}
+
+	result := L.Get(-2)
+	reason := L.Get(-1)
+	L.Pop(2)
+
+	if result == lua.LFalse {
+		veto := &VetoError{Plugin: p.name, Reason: "no reason given"}
+		if reason != lua.LNil {
+			veto.Reason = reason.String()
+		}
+		return veto
+	}
+
+	for key, value := range data {
+		data[key] = fromLuaValue(table.RawGetString(key), value)
+	}
+
+	return nil
+}
+
+func toLuaValue(value interface{}) lua.LValue {
+	switch v := value.(type) {
+	case string:
+		return lua.LString(v)
+	case bool:
+		return lua.LBool(v)
+	case int64:
+		return lua.LNumber(v)
+	case float64:
+		return lua.LNumber(v)
+	}
+	return lua.LNil
+}
+
+// fromLuaValue converts a Lua value back to the type of the original value. If the types don't match, the original
+// value is kept.
+func fromLuaValue(value lua.LValue, original interface{}) interface{} {
+	switch original.(type) {
+	case string:
+		if s, is := value.(lua.LString); is {
+			return string(s)
+		}
+	case bool:
+		if b, is := value.(lua.LBool); is {
+			return bool(b)
+		}
+	case int64:
+		if n, is := value.(lua.LNumber); is {
+			return int64(n)
+		}
+	case float64:
+		if n, is := value.(lua.LNumber); is {
+			return float64(n)
+		}
+	}
+	return original
+}
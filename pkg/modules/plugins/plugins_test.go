@@ -0,0 +1,168 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadPlugins(t *testing.T, scripts map[string]string) {
+	dir := t.TempDir()
+	for name, script := range scripts {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o600)
+		require.NoError(t, err)
+	}
+
+	config.PluginsEnabled.Set(true)
+	config.PluginsPath.Set(dir)
+	config.PluginsTimeoutMilliseconds.Set(1000)
+	t.Cleanup(func() {
+		config.PluginsEnabled.Set(false)
+		plugins = nil
+	})
+
+	err := Init()
+	require.NoError(t, err)
+}
+
+func TestRun(t *testing.T) {
+	t.Run("change values", func(t *testing.T) {
+		loadPlugins(t, map[string]string{
+			"a_prefix.lua": `function on_task_create(task)
+				task.title = "[" .. task.project_id .. "] " .. task.title
+				task.priority = 3
+				task.done = "not a bool"
+			end`,
+			"b_upper.lua": `function on_task_create(task)
+				task.title = string.upper(task.title)
+			end`,
+		})
+
+		data := map[string]interface{}{
+			"title":      "Task",
+			"project_id": int64(1),
+			"priority":   int64(0),
+			"done":       false,
+		}
+		err := Run(HookTaskCreate, data)
+		require.NoError(t, err)
+		assert.Equal(t, "[1] TASK", data["title"])
+		assert.Equal(t, int64(3), data["priority"])
+		// Values of another type are ignored
+		assert.Equal(t, false, data["done"])
+	})
+	t.Run("veto", func(t *testing.T) {
+		loadPlugins(t, map[string]string{
+			"validate.lua": `function on_task_update(task)
+				if task.title == "" then
+					return false, "a title is required"
+				end
+			end`,
+		})
+
+		err := Run(HookTaskUpdate, map[string]interface{}{"title": ""})
+		require.Error(t, err)
+		veto, is := err.(*VetoError)
+		require.True(t, is)
+		assert.Equal(t, "validate", veto.Plugin)
+		assert.Equal(t, "a title is required", veto.Reason)
+
+		err = Run(HookTaskUpdate, map[string]interface{}{"title": "Task"})
+		require.NoError(t, err)
+	})
+	t.Run("other hook", func(t *testing.T) {
+		loadPlugins(t, map[string]string{
+			"delete.lua": `function on_task_delete(task)
+				return false, "nothing may be deleted"
+			end`,
+		})
+
+		err := Run(HookTaskCreate, map[string]interface{}{"title": "Task"})
+		require.NoError(t, err)
+	})
+	t.Run("broken plugin is skipped", func(t *testing.T) {
+		loadPlugins(t, map[string]string{
+			"a_broken.lua": `function on_task_create(task)
+				error("boom")
+			end`,
+			"b_works.lua": `function on_task_create(task)
+				task.title = "changed"
+			end`,
+		})
+
+		data := map[string]interface{}{"title": "Task"}
+		err := Run(HookTaskCreate, data)
+		require.NoError(t, err)
+		assert.Equal(t, "changed", data["title"])
+	})
+	t.Run("timeout", func(t *testing.T) {
+		loadPlugins(t, map[string]string{
+			"loop.lua": `function on_task_create(task)
+				task.title = "changed"
+				while true do end
+			end`,
+		})
+		config.PluginsTimeoutMilliseconds.Set(50)
+
+		data := map[string]interface{}{"title": "Task"}
+		err := Run(HookTaskCreate, data)
+		require.NoError(t, err)
+		assert.Equal(t, "Task", data["title"])
+	})
+	t.Run("sandbox", func(t *testing.T) {
+		loadPlugins(t, map[string]string{
+			"sandbox.lua": `function on_task_create(task)
+				if os == nil and io == nil and require == nil and dofile == nil then
+					task.title = "sandboxed"
+				end
+			end`,
+		})
+
+		data := map[string]interface{}{"title": "Task"}
+		err := Run(HookTaskCreate, data)
+		require.NoError(t, err)
+		assert.Equal(t, "sandboxed", data["title"])
+	})
+}
+
+func TestInit(t *testing.T) {
+	t.Run("syntax error", func(t *testing.T) {
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "broken.lua"), []byte("function ("), 0o600)
+		require.NoError(t, err)
+
+		config.PluginsEnabled.Set(true)
+		config.PluginsPath.Set(dir)
+		defer config.PluginsEnabled.Set(false)
+
+		err = Init()
+		require.Error(t, err)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		config.PluginsEnabled.Set(false)
+		err := Init()
+		require.NoError(t, err)
+		assert.False(t, Enabled())
+	})
+}
@@ -19,6 +19,11 @@ package notifications
 import (
 	"time"
 
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+
 	"xorm.io/xorm"
 )
 
@@ -67,6 +72,31 @@ func GetNotificationsForUser(s *xorm.Session, notifiableID int64, limit, start i
 	return notifications, len(notifications), total, err
 }
 
+// GetNotificationsForUserAfter returns up to limit notifications of a user which are newer than the notification
+// with the id afterID, oldest first.
+func GetNotificationsForUserAfter(s *xorm.Session, notifiableID, afterID int64, limit int) (notifications []*DatabaseNotification, err error) {
+	notifications = []*DatabaseNotification{}
+	err = s.
+		Where("notifiable_id = ? AND id > ?", notifiableID, afterID).
+		OrderBy("id ASC").
+		Limit(limit).
+		Find(&notifications)
+	return
+}
+
+// GetLatestNotificationID returns the id of the newest notification of a user or 0 if the user has none.
+func GetLatestNotificationID(s *xorm.Session, notifiableID int64) (id int64, err error) {
+	notification := &DatabaseNotification{}
+	has, err := s.
+		Where("notifiable_id = ?", notifiableID).
+		OrderBy("id DESC").
+		Get(notification)
+	if err != nil || !has {
+		return 0, err
+	}
+	return notification.ID, nil
+}
+
 func GetNotificationsForNameAndUser(s *xorm.Session, notifiableID int64, event string, subjectID int64) (notifications []*DatabaseNotification, err error) {
 	notifications = []*DatabaseNotification{}
 	err = s.Where("notifiable_id = ? AND name = ? AND subject_id = ?", notifiableID, event, subjectID).
@@ -100,8 +130,74 @@ func MarkNotificationAsRead(s *xorm.Session, notification *DatabaseNotification,
 
 func MarkAllNotificationsAsRead(s *xorm.Session, userID int64) (err error) {
 	_, err = s.
-		Where("notifiable_id = ?", userID).
+		Where("notifiable_id = ? AND read_at IS NULL", userID).
 		Cols("read_at").
 		Update(&DatabaseNotification{ReadAt: time.Now()})
 	return
 }
+
+// DeleteReadNotifications deletes all notifications of a user which were marked as read.
+func DeleteReadNotifications(s *xorm.Session, userID int64) (deleted int64, err error) {
+	return s.
+		Where("notifiable_id = ? AND read_at IS NOT NULL", userID).
+		Delete(&DatabaseNotification{})
+}
+
+// pruneNotifications deletes all read notifications older than readRetention and all notifications older than
+// unreadRetention, regardless if they were read or not. A retention of 0 keeps the notifications forever.
+func pruneNotifications(s *xorm.Session, now time.Time, readRetention, unreadRetention time.Duration) (deleted int64, err error) {
+	if readRetention > 0 {
+		deleted, err = s.
+			Where("read_at IS NOT NULL AND created < ?", now.Add(-readRetention)).
+			Delete(&DatabaseNotification{})
+		if err != nil {
+			return
+		}
+	}
+
+	if unreadRetention > 0 {
+		var unread int64
+		unread, err = s.
+			Where("created < ?", now.Add(-unreadRetention)).
+			Delete(&DatabaseNotification{})
+		deleted += unread
+	}
+
+	return
+}
+
+// RegisterNotificationRetentionCron registers a cron function which deletes database notifications once they are
+// older than the configured retention period.
+func RegisterNotificationRetentionCron() {
+	const logPrefix = "[Notification Retention Cron] "
+
+	readRetention := time.Duration(config.NotificationsRetentionDays.GetInt64()) * 24 * time.Hour
+	unreadRetention := time.Duration(config.NotificationsUnreadRetentionDays.GetInt64()) * 24 * time.Hour
+	if readRetention <= 0 && unreadRetention <= 0 {
+		return
+	}
+
+	err := cron.Schedule("0 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		deleted, err := pruneNotifications(s, time.Now(), readRetention, unreadRetention)
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not delete old notifications: %s", err)
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit deleted notifications: %s", err)
+			return
+		}
+
+		if deleted > 0 {
+			log.Debugf(logPrefix+"Deleted %d old notifications", deleted)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register notification retention cron: %s", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertTestNotifications(t *testing.T, now time.Time) {
+	s := db.NewSession()
+	defer s.Close()
+	_, err := s.Exec("delete from notifications")
+	require.NoError(t, err)
+
+	dbNotifications := []*DatabaseNotification{
+		{ID: 1, NotifiableID: 1, Name: "test.notification", Notification: "{}", Created: now.Add(-40 * 24 * time.Hour), ReadAt: now.Add(-39 * 24 * time.Hour)},
+		{ID: 2, NotifiableID: 1, Name: "test.notification", Notification: "{}", Created: now.Add(-40 * 24 * time.Hour)},
+		{ID: 3, NotifiableID: 1, Name: "test.notification", Notification: "{}", Created: now.Add(-time.Hour), ReadAt: now},
+		{ID: 4, NotifiableID: 2, Name: "test.notification", Notification: "{}", Created: now.Add(-time.Hour), ReadAt: now},
+	}
+	_, err = s.NoAutoTime().Insert(&dbNotifications)
+	require.NoError(t, err)
+}
+
+func TestDeleteReadNotifications(t *testing.T) {
+	insertTestNotifications(t, time.Now())
+	s := db.NewSession()
+	defer s.Close()
+
+	deleted, err := DeleteReadNotifications(s, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	db.AssertMissing(t, "notifications", map[string]interface{}{"id": 1})
+	db.AssertMissing(t, "notifications", map[string]interface{}{"id": 3})
+	db.AssertExists(t, "notifications", map[string]interface{}{"id": 2}, false)
+	db.AssertExists(t, "notifications", map[string]interface{}{"id": 4}, false)
+}
+
+func TestPruneNotifications(t *testing.T) {
+	t.Run("read notifications only", func(t *testing.T) {
+		now := time.Now()
+		insertTestNotifications(t, now)
+		s := db.NewSession()
+		defer s.Close()
+
+		deleted, err := pruneNotifications(s, now, 30*24*time.Hour, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		db.AssertMissing(t, "notifications", map[string]interface{}{"id": 1})
+		db.AssertExists(t, "notifications", map[string]interface{}{"id": 2}, false)
+		db.AssertExists(t, "notifications", map[string]interface{}{"id": 3}, false)
+	})
+	t.Run("unread notifications as well", func(t *testing.T) {
+		now := time.Now()
+		insertTestNotifications(t, now)
+		s := db.NewSession()
+		defer s.Close()
+
+		deleted, err := pruneNotifications(s, now, 0, 30*24*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		db.AssertMissing(t, "notifications", map[string]interface{}{"id": 1})
+		db.AssertMissing(t, "notifications", map[string]interface{}{"id": 2})
+		db.AssertExists(t, "notifications", map[string]interface{}{"id": 3}, false)
+	})
+	t.Run("keep forever", func(t *testing.T) {
+		now := time.Now()
+		insertTestNotifications(t, now)
+		s := db.NewSession()
+		defer s.Close()
+
+		deleted, err := pruneNotifications(s, now, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), deleted)
+	})
+}
+
+func TestMarkAllNotificationsAsRead(t *testing.T) {
+	now := time.Now()
+	insertTestNotifications(t, now)
+	s := db.NewSession()
+	defer s.Close()
+
+	err := MarkAllNotificationsAsRead(s, 1)
+	require.NoError(t, err)
+
+	n := &DatabaseNotification{}
+	_, err = s.ID(2).Get(n)
+	require.NoError(t, err)
+	assert.False(t, n.ReadAt.IsZero())
+
+	// Notifications which were read before keep their read time
+	n = &DatabaseNotification{}
+	_, err = s.ID(1).Get(n)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-39*24*time.Hour).Unix(), n.ReadAt.Unix())
+}
@@ -20,5 +20,7 @@ package notifications
 func GetTables() []interface{} {
 	return []interface{}{
 		&DatabaseNotification{},
+		&PushSubscription{},
+		&QueuedNotification{},
 	}
 }
@@ -0,0 +1,76 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"code.vikunja.io/api/pkg/outbound"
+)
+
+// DiscordMessage is a message posted to a Discord webhook
+type DiscordMessage struct {
+	Content string          `json:"content,omitempty"`
+	Embeds  []*DiscordEmbed `json:"embeds,omitempty"`
+}
+
+// DiscordEmbed is a rich embed of a Discord message
+type DiscordEmbed struct {
+	Title       string               `json:"title,omitempty"`
+	Description string               `json:"description,omitempty"`
+	URL         string               `json:"url,omitempty"`
+	Color       int                  `json:"color,omitempty"`
+	Timestamp   string               `json:"timestamp,omitempty"`
+	Author      *DiscordEmbedAuthor  `json:"author,omitempty"`
+	Fields      []*DiscordEmbedField `json:"fields,omitempty"`
+	Footer      *DiscordEmbedFooter  `json:"footer,omitempty"`
+}
+
+// DiscordEmbedAuthor is shown above the title of an embed
+type DiscordEmbedAuthor struct {
+	Name string `json:"name"`
+}
+
+// DiscordEmbedField is a name and value shown in an embed
+type DiscordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// DiscordEmbedFooter is shown below the fields of an embed
+type DiscordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// SendDiscordMessage posts a message to a Discord webhook
+func SendDiscordMessage(url string, msg *DiscordMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doChannelRequest(outbound.DefaultClient(), req, "discord")
+}
@@ -0,0 +1,99 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/outbound"
+)
+
+// NotifiableWithGotify is a notifiable which gets notifications as messages of a Gotify application
+type NotifiableWithGotify interface {
+	Notifiable
+	// RouteForGotify should return the token of the Gotify application of this notifiable.
+	// Returning an empty token skips Gotify.
+	RouteForGotify() (token string, err error)
+}
+
+type gotifyMessage struct {
+	Title    string                 `json:"title"`
+	Message  string                 `json:"message"`
+	Priority int                    `json:"priority"`
+	Extras   map[string]interface{} `json:"extras,omitempty"`
+}
+
+func notifyGotify(notifiable Notifiable, notification Notification) error {
+	if !config.GotifyEnabled.GetBool() {
+		return nil
+	}
+
+	routed, is := notifiable.(NotifiableWithGotify)
+	if !is {
+		return nil
+	}
+
+	token, err := routed.RouteForGotify()
+	if err != nil || token == "" {
+		return err
+	}
+
+	msg := toPushMessage(notification)
+	if msg == nil {
+		return nil
+	}
+
+	return sendGotifyMessage(outbound.ConfiguredClient(), config.GotifyURL.GetString(), token, msg)
+}
+
+// sendGotifyMessage sends a message to the application with the token on a Gotify server
+func sendGotifyMessage(client *http.Client, serverURL, token string, msg *PushMessage) error {
+	message := &gotifyMessage{
+		Title:    msg.Title,
+		Message:  msg.Body,
+		Priority: config.GotifyPriority.GetInt(),
+	}
+	if msg.URL != "" {
+		message.Extras = map[string]interface{}{
+			"client::notification": map[string]interface{}{
+				"click": map[string]string{"url": msg.URL},
+			},
+		}
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		strings.TrimSuffix(serverURL, "/")+"/message",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", token)
+
+	return doChannelRequest(client, req, "gotify")
+}
@@ -0,0 +1,64 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyGotify(t *testing.T) {
+	var requests []*http.Request
+	var messages []*gotifyMessage
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		msg := &gotifyMessage{}
+		err := json.NewDecoder(r.Body).Decode(msg)
+		require.NoError(t, err)
+		requests = append(requests, r)
+		messages = append(messages, msg)
+	}))
+	defer server.Close()
+
+	config.GotifyEnabled.Set(true)
+	config.GotifyURL.Set(server.URL)
+	defer config.GotifyEnabled.Set(false)
+
+	t.Run("sends to the application of the notifiable", func(t *testing.T) {
+		requests, messages = nil, nil
+		err := notifyGotify(&testNotifiableWithTargets{GotifyToken: "app-token"}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/message", requests[0].URL.Path)
+		assert.Equal(t, "app-token", requests[0].Header.Get("X-Gotify-Key"))
+		assert.Equal(t, "Test Notification", messages[0].Title)
+		assert.Equal(t, "somethingsomething", messages[0].Message)
+		assert.Equal(t, 5, messages[0].Priority)
+	})
+	t.Run("no token", func(t *testing.T) {
+		requests, messages = nil, nil
+		err := notifyGotify(&testNotifiableWithTargets{}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		assert.Empty(t, requests)
+	})
+}
@@ -0,0 +1,176 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+)
+
+// GroupableNotification is a notification which can be grouped with other notifications of the same group
+// sent to the same notifiable shortly after each other.
+// Instead of one message per notification, the notifiable gets a single message for all notifications sent
+// within the configured grouping window via all channels except the database.
+type GroupableNotification interface {
+	Notification
+	// GroupKey returns a key which is the same for all notifications which should be grouped together,
+	// for example all comments on the same task.
+	GroupKey() string
+}
+
+// groupedNotification holds all notifications of the same group sent within the grouping window.
+type groupedNotification struct {
+	notifications []Notification
+}
+
+// ToMail returns a mail which contains the content of all grouped notifications
+func (g *groupedNotification) ToMail() *Mail {
+	var mails []*Mail
+	for _, n := range g.notifications {
+		if m := n.ToMail(); m != nil {
+			mails = append(mails, m)
+		}
+	}
+	if len(mails) == 0 {
+		return nil
+	}
+
+	first, last := mails[0], mails[len(mails)-1]
+	mail := NewMail().
+		From(first.from).
+		Subject(first.subject + " (" + strconv.Itoa(len(mails)) + " notifications)").
+		Greeting(first.greeting)
+
+	for i, m := range mails {
+		if m.from != first.from {
+			mail.From("")
+		}
+		if i > 0 {
+			mail.Line("---")
+		}
+		for _, line := range m.introLines {
+			mail.Line(line)
+		}
+	}
+
+	mail.Action(last.actionText, last.actionURL)
+	for _, line := range last.outroLines {
+		mail.Line(line)
+	}
+
+	return mail
+}
+
+// ToDB is never used because database notifications are saved one by one
+func (g *groupedNotification) ToDB() interface{} {
+	return nil
+}
+
+// Name returns the name of the grouped notifications
+func (g *groupedNotification) Name() string {
+	return g.notifications[0].Name()
+}
+
+type notificationGroup struct {
+	notifiable    Notifiable
+	notifications []Notification
+}
+
+// notificationGrouper collects groupable notifications per notifiable and group until the grouping window
+// of the first notification in a group is over and then sends them all at once.
+type notificationGrouper struct {
+	mutex  sync.Mutex
+	window time.Duration
+	groups map[string]*notificationGroup
+	send   func(notifiable Notifiable, notification Notification)
+}
+
+func newNotificationGrouper(window time.Duration, send func(notifiable Notifiable, notification Notification)) *notificationGrouper {
+	return &notificationGrouper{
+		window: window,
+		groups: make(map[string]*notificationGroup),
+		send:   send,
+	}
+}
+
+func (g *notificationGrouper) add(notifiable Notifiable, notification GroupableNotification) {
+	key := strconv.FormatInt(notifiable.RouteForDB(), 10) + ":" + notification.GroupKey()
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	group, exists := g.groups[key]
+	if exists {
+		group.notifications = append(group.notifications, notification)
+		return
+	}
+
+	g.groups[key] = &notificationGroup{
+		notifiable:    notifiable,
+		notifications: []Notification{notification},
+	}
+	time.AfterFunc(g.window, func() {
+		g.flush(key)
+	})
+}
+
+func (g *notificationGrouper) flush(key string) {
+	g.mutex.Lock()
+	group, exists := g.groups[key]
+	delete(g.groups, key)
+	g.mutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	if len(group.notifications) == 1 {
+		g.send(group.notifiable, group.notifications[0])
+		return
+	}
+
+	log.Debugf("Sending %d grouped notifications to notifiable %d", len(group.notifications), group.notifiable.RouteForDB())
+	g.send(group.notifiable, &groupedNotification{notifications: group.notifications})
+}
+
+var (
+	grouper     *notificationGrouper
+	grouperOnce sync.Once
+)
+
+// getNotificationGrouper returns the grouper for all notifications or nil if grouping is disabled
+func getNotificationGrouper() *notificationGrouper {
+	grouperOnce.Do(func() {
+		window := time.Duration(config.NotificationsGroupingWindow.GetInt64()) * time.Second
+		if window <= 0 {
+			return
+		}
+		grouper = newNotificationGrouper(window, func(notifiable Notifiable, notification Notification) {
+			err := notifyChannels(notifiable, notification, func(channel Channel) bool {
+				return channel != ChannelDB
+			})
+			if err != nil {
+				log.Errorf("Could not send grouped notifications to notifiable %d: %s", notifiable.RouteForDB(), err)
+			}
+		})
+	})
+	return grouper
+}
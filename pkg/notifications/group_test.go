@@ -0,0 +1,89 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testGroupableNotification struct {
+	testNotification
+	Group string
+}
+
+func (n *testGroupableNotification) GroupKey() string {
+	return n.Group
+}
+
+func TestNotificationGrouper(t *testing.T) {
+	t.Run("groups notifications within the window", func(t *testing.T) {
+		var mutex sync.Mutex
+		var sent []Notification
+		g := newNotificationGrouper(50*time.Millisecond, func(_ Notifiable, n Notification) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			sent = append(sent, n)
+		})
+
+		tnf := &testNotifiable{ShouldSendNotification: true}
+		g.add(tnf, &testGroupableNotification{testNotification: testNotification{Test: "first"}, Group: "task.1"})
+		g.add(tnf, &testGroupableNotification{testNotification: testNotification{Test: "second"}, Group: "task.1"})
+		g.add(tnf, &testGroupableNotification{testNotification: testNotification{Test: "other"}, Group: "task.2"})
+
+		assert.Eventually(t, func() bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return len(sent) == 2
+		}, time.Second, 10*time.Millisecond)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		var grouped *groupedNotification
+		var single *testGroupableNotification
+		for _, n := range sent {
+			switch v := n.(type) {
+			case *groupedNotification:
+				grouped = v
+			case *testGroupableNotification:
+				single = v
+			}
+		}
+		require.NotNil(t, grouped)
+		require.NotNil(t, single)
+		assert.Len(t, grouped.notifications, 2)
+		assert.Equal(t, "other", single.Test)
+	})
+}
+
+func TestGroupedNotification_ToMail(t *testing.T) {
+	g := &groupedNotification{notifications: []Notification{
+		&testNotification{Test: "first"},
+		&testNotification{Test: "second"},
+	}}
+
+	mail := g.ToMail()
+	require.NotNil(t, mail)
+	assert.Equal(t, "Test Notification (2 notifications)", mail.subject)
+	assert.Equal(t, []string{"first", "---", "second"}, mail.introLines)
+	assert.Equal(t, "test.notification", g.Name())
+	assert.Nil(t, g.ToDB())
+}
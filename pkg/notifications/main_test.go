@@ -24,6 +24,7 @@ import (
 	"code.vikunja.io/api/pkg/db"
 	"code.vikunja.io/api/pkg/log"
 	"code.vikunja.io/api/pkg/mail"
+	"code.vikunja.io/api/pkg/outbound"
 )
 
 // SetupTests initializes all db tests
@@ -34,7 +35,7 @@ func SetupTests() {
 		log.Fatal(err)
 	}
 
-	err = x.Sync2(&DatabaseNotification{})
+	err = x.Sync2(&DatabaseNotification{}, &PushSubscription{}, &QueuedNotification{})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -46,6 +47,11 @@ func TestMain(m *testing.M) {
 	config.InitDefaultConfig()
 	// We need to set the root path even if we're not using the config, otherwise fixtures are not loaded correctly
 	config.ServiceRootpath.Set(os.Getenv("VIKUNJA_SERVICE_ROOTPATH"))
+	// The tests send requests to local http servers
+	config.OutboundAllowedCIDRs.Set([]string{"127.0.0.0/8"})
+	if err := outbound.Init(); err != nil {
+		log.Fatal(err)
+	}
 
 	SetupTests()
 
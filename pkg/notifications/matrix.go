@@ -0,0 +1,172 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/outbound"
+)
+
+// NotifiableWithMatrix is a notifiable which gets notifications as messages in a Matrix room
+type NotifiableWithMatrix interface {
+	Notifiable
+	// RouteForMatrix should return the id of the Matrix room which should get the notifications of this notifiable,
+	// usually a direct message room with the Vikunja bot user. Returning an empty room id skips Matrix.
+	RouteForMatrix() (roomID string, err error)
+}
+
+// MatrixMessage is a message posted to a Matrix room
+type MatrixMessage struct {
+	// The plain text of the message, shown by clients which do not render html.
+	Body string
+	// The html formatted text of the message. Optional.
+	FormattedBody string
+}
+
+type matrixRoomMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+// matrixTxnCounter makes the transaction ids of messages unique within one run, together with the start time
+var matrixTxnCounter int64
+
+func notifyMatrix(notifiable Notifiable, notification Notification) error {
+	if !config.MatrixEnabled.GetBool() {
+		return nil
+	}
+
+	routed, is := notifiable.(NotifiableWithMatrix)
+	if !is {
+		return nil
+	}
+
+	roomID, err := routed.RouteForMatrix()
+	if err != nil || roomID == "" {
+		return err
+	}
+
+	msg := toPushMessage(notification)
+	if msg == nil {
+		return nil
+	}
+
+	return SendMatrixMessage(roomID, pushMessageToMatrix(msg))
+}
+
+func pushMessageToMatrix(msg *PushMessage) *MatrixMessage {
+	plain := msg.Title
+	formatted := "<strong>" + html.EscapeString(msg.Title) + "</strong>"
+	if msg.Body != "" {
+		plain += "\n" + msg.Body
+		formatted += "<br>" + html.EscapeString(msg.Body)
+	}
+	if msg.URL != "" {
+		plain += "\n" + msg.URL
+		formatted += `<br><a href="` + html.EscapeString(msg.URL) + `">` + html.EscapeString(msg.URL) + "</a>"
+	}
+
+	return &MatrixMessage{Body: plain, FormattedBody: formatted}
+}
+
+// SendMatrixMessage posts a message to a Matrix room as the user of the configured access token.
+// If that user is invited to the room but did not join it yet, it joins the room and tries again.
+func SendMatrixMessage(roomID string, msg *MatrixMessage) error {
+	if !config.MatrixEnabled.GetBool() {
+		return nil
+	}
+
+	content := &matrixRoomMessage{
+		MsgType: "m.notice",
+		Body:    msg.Body,
+	}
+	if msg.FormattedBody != "" {
+		content.Format = "org.matrix.custom.html"
+		content.FormattedBody = msg.FormattedBody
+	}
+
+	body, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	txnID := strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.FormatInt(atomic.AddInt64(&matrixTxnCounter, 1), 36)
+	path := "/rooms/" + url.PathEscape(roomID) + "/send/m.room.message/" + txnID
+
+	status, err := doMatrixRequest(http.MethodPut, path, body)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusForbidden {
+		return checkMatrixStatus(status)
+	}
+
+	status, err = doMatrixRequest(http.MethodPost, "/rooms/"+url.PathEscape(roomID)+"/join", []byte("{}"))
+	if err != nil {
+		return err
+	}
+	if err := checkMatrixStatus(status); err != nil {
+		return err
+	}
+
+	status, err = doMatrixRequest(http.MethodPut, path, body)
+	if err != nil {
+		return err
+	}
+	return checkMatrixStatus(status)
+}
+
+func doMatrixRequest(method, path string, body []byte) (status int, err error) {
+	req, err := http.NewRequest(
+		method,
+		strings.TrimSuffix(config.MatrixHomeserverURL.GetString(), "/")+"/_matrix/client/v3"+path,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+config.MatrixAccessToken.GetString())
+
+	res, err := outbound.ConfiguredClient().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	return res.StatusCode, nil
+}
+
+func checkMatrixStatus(status int) error {
+	if status >= 300 {
+		return fmt.Errorf("matrix homeserver returned status %d", status)
+	}
+	return nil
+}
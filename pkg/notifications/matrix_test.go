@@ -0,0 +1,89 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyMatrix(t *testing.T) {
+	var requests []*http.Request
+	var messages []*matrixRoomMessage
+	joined := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		room := strings.Split(strings.TrimPrefix(r.URL.Path, "/_matrix/client/v3/rooms/"), "/")[0]
+		if strings.HasSuffix(r.URL.Path, "/join") {
+			joined[room] = true
+			return
+		}
+		if room == "!invited:example.com" && !joined[room] {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		msg := &matrixRoomMessage{}
+		err := json.NewDecoder(r.Body).Decode(msg)
+		require.NoError(t, err)
+		messages = append(messages, msg)
+	}))
+	defer server.Close()
+
+	config.MatrixEnabled.Set(true)
+	config.MatrixHomeserverURL.Set(server.URL + "/")
+	config.MatrixAccessToken.Set("bot-token")
+	defer func() {
+		config.MatrixEnabled.Set(false)
+		config.MatrixAccessToken.Set("")
+	}()
+
+	t.Run("posts to the room of the notifiable", func(t *testing.T) {
+		requests, messages = nil, nil
+		err := notifyMatrix(&testNotifiableWithTargets{MatrixRoomID: "!dm:example.com"}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, http.MethodPut, requests[0].Method)
+		assert.True(t, strings.HasPrefix(requests[0].URL.Path, "/_matrix/client/v3/rooms/!dm:example.com/send/m.room.message/"))
+		assert.Equal(t, "Bearer bot-token", requests[0].Header.Get("Authorization"))
+		assert.Equal(t, "m.notice", messages[0].MsgType)
+		assert.Equal(t, "Test Notification\nsomethingsomething", messages[0].Body)
+		assert.Equal(t, "<strong>Test Notification</strong><br>somethingsomething", messages[0].FormattedBody)
+	})
+	t.Run("joins rooms it was invited to", func(t *testing.T) {
+		requests, messages = nil, nil
+		err := notifyMatrix(&testNotifiableWithTargets{MatrixRoomID: "!invited:example.com"}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.Len(t, requests, 3)
+		assert.Equal(t, "/_matrix/client/v3/rooms/!invited:example.com/join", requests[1].URL.Path)
+		assert.Equal(t, requests[0].URL.Path, requests[2].URL.Path)
+		assert.Len(t, messages, 1)
+	})
+	t.Run("no room", func(t *testing.T) {
+		requests, messages = nil, nil
+		err := notifyMatrix(&testNotifiableWithTargets{}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		assert.Empty(t, requests)
+	})
+}
@@ -50,6 +50,48 @@ type Notifiable interface {
 	ShouldNotify() (should bool, err error)
 }
 
+// Channel is a way a notification is delivered to a notifiable.
+type Channel string
+
+const (
+	// ChannelMail sends a notification via email.
+	ChannelMail Channel = "mail"
+	// ChannelDB saves a notification in the database to show it in the app.
+	ChannelDB Channel = "db"
+	// ChannelPush sends a notification as Web Push message to all browsers and devices of a notifiable.
+	ChannelPush Channel = "push"
+	// ChannelNtfy publishes a notification to the ntfy topic of a notifiable.
+	ChannelNtfy Channel = "ntfy"
+	// ChannelGotify sends a notification to the Gotify application of a notifiable.
+	ChannelGotify Channel = "gotify"
+	// ChannelMatrix posts a notification to the Matrix room of a notifiable.
+	ChannelMatrix Channel = "matrix"
+	// ChannelTargets sends a notification to all services a notifiable configured through target urls.
+	ChannelTargets Channel = "targets"
+)
+
+// channelNotifiers holds how a notification is sent via each channel, in the order they are used
+var channelNotifiers = []struct {
+	channel Channel
+	notify  func(notifiable Notifiable, notification Notification) error
+}{
+	{channel: ChannelMail, notify: notifyMail},
+	{channel: ChannelDB, notify: notifyDB},
+	{channel: ChannelPush, notify: notifyPush},
+	{channel: ChannelNtfy, notify: notifyNtfy},
+	{channel: ChannelGotify, notify: notifyGotify},
+	{channel: ChannelMatrix, notify: notifyMatrix},
+	{channel: ChannelTargets, notify: notifyTargets},
+}
+
+// NotifiableWithPreferences is a notifiable which can opt out of single kinds of notifications per channel.
+type NotifiableWithPreferences interface {
+	Notifiable
+	// ShouldNotifyVia will be called before a notification is sent via a channel. Returning false skips
+	// only that channel for that notification.
+	ShouldNotifyVia(channel Channel, notificationName string) (should bool, err error)
+}
+
 // Notify notifies a notifiable of a notification
 func Notify(notifiable Notifiable, notification Notification) (err error) {
 	if isUnderTest {
@@ -57,18 +99,81 @@ func Notify(notifiable Notifiable, notification Notification) (err error) {
 		return nil
 	}
 
+	groupable, isGroupable := notification.(GroupableNotification)
+	if g := getNotificationGrouper(); isGroupable && g != nil {
+		// Database notifications are saved right away, everything else is sent once the grouping window is over
+		err = notifyChannels(notifiable, notification, func(channel Channel) bool {
+			return channel == ChannelDB
+		})
+		if err != nil {
+			return err
+		}
+
+		g.add(notifiable, groupable)
+		return nil
+	}
+
+	return notifyChannels(notifiable, notification, func(Channel) bool {
+		return true
+	})
+}
+
+// notifyChannels sends a notification via all channels for which the include function returns true
+func notifyChannels(notifiable Notifiable, notification Notification, include func(channel Channel) bool) (err error) {
 	should, err := notifiable.ShouldNotify()
 	if err != nil || !should {
 		log.Debugf("Not notifying user %d because they are disabled", notifiable.RouteForDB())
 		return err
 	}
 
-	err = notifyMail(notifiable, notification)
+	quietUntil, err := quietHoursEnd(notifiable, notification)
 	if err != nil {
-		return
+		return err
+	}
+
+	var queue bool
+	for _, c := range channelNotifiers {
+		if !include(c.channel) {
+			continue
+		}
+
+		should, err = shouldNotifyVia(notifiable, c.channel, notification)
+		if err != nil {
+			return err
+		}
+		if !should {
+			continue
+		}
+
+		if c.channel != ChannelDB && !quietUntil.IsZero() {
+			queue = true
+			continue
+		}
+
+		err = c.notify(notifiable, notification)
+		if err != nil {
+			return err
+		}
+	}
+
+	if queue {
+		return queueNotification(notifiable, notification, quietUntil)
 	}
 
-	return notifyDB(notifiable, notification)
+	return nil
+}
+
+func shouldNotifyVia(notifiable Notifiable, channel Channel, notification Notification) (should bool, err error) {
+	withPreferences, is := notifiable.(NotifiableWithPreferences)
+	if !is {
+		return true, nil
+	}
+
+	should, err = withPreferences.ShouldNotifyVia(channel, notification.Name())
+	if err == nil && !should {
+		log.Debugf("Not notifying user %d of %s via %s because they disabled it", notifiable.RouteForDB(), notification.Name(), channel)
+	}
+	return
 }
 
 func notifyMail(notifiable Notifiable, notification Notification) error {
@@ -18,9 +18,11 @@ package notifications
 
 import (
 	"testing"
+	"time"
 
 	"code.vikunja.io/api/pkg/db"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"xorm.io/xorm/schemas"
 )
@@ -68,6 +70,15 @@ func (t *testNotifiable) ShouldNotify() (should bool, err error) {
 	return t.ShouldSendNotification, nil
 }
 
+type testNotifiableWithPreferences struct {
+	testNotifiable
+	DisabledChannel Channel
+}
+
+func (t *testNotifiableWithPreferences) ShouldNotifyVia(channel Channel, _ string) (should bool, err error) {
+	return channel != t.DisabledChannel, nil
+}
+
 func TestNotify(t *testing.T) {
 	t.Run("normal", func(t *testing.T) {
 
@@ -124,4 +135,85 @@ func TestNotify(t *testing.T) {
 			"notifiable_id": 42,
 		})
 	})
+	t.Run("channel disabled by preferences", func(t *testing.T) {
+
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Exec("delete from notifications")
+		require.NoError(t, err)
+
+		tn := &testNotification{
+			Test:       "somethingsomething",
+			OtherValue: 42,
+		}
+		tnf := &testNotifiableWithPreferences{
+			testNotifiable:  testNotifiable{ShouldSendNotification: true},
+			DisabledChannel: ChannelDB,
+		}
+
+		err = Notify(tnf, tn)
+		require.NoError(t, err)
+		db.AssertMissing(t, "notifications", map[string]interface{}{
+			"notifiable_id": 42,
+		})
+	})
+}
+
+type testNotifiableWithQuietHours struct {
+	testNotifiable
+	Until time.Time
+}
+
+func (t *testNotifiableWithQuietHours) QuietUntil(_ time.Time, _ string, _ bool) (time.Time, error) {
+	return t.Until, nil
+}
+
+func TestNotifyDuringQuietHours(t *testing.T) {
+	s := db.NewSession()
+	defer s.Close()
+	_, err := s.Exec("delete from notifications")
+	require.NoError(t, err)
+	_, err = s.Exec("delete from queued_notifications")
+	require.NoError(t, err)
+
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	tnf := &testNotifiableWithQuietHours{
+		testNotifiable: testNotifiable{ShouldSendNotification: true},
+		Until:          until,
+	}
+	tn := &testNotification{
+		Test:       "somethingsomething",
+		OtherValue: 42,
+	}
+
+	err = Notify(tnf, tn)
+	require.NoError(t, err)
+
+	// The notification is shown in the app right away
+	db.AssertExists(t, "notifications", map[string]interface{}{
+		"notifiable_id": 42,
+	}, false)
+	db.AssertExists(t, "queued_notifications", map[string]interface{}{
+		"notifiable_id": 42,
+		"name":          "test.notification",
+	}, false)
+
+	queued, err := GetDueQueuedNotifications(s, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, queued)
+
+	queued, err = GetDueQueuedNotifications(s, until)
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	mail := queued[0].ToMail()
+	require.NotNil(t, mail)
+	assert.Equal(t, "Test Notification", mail.subject)
+	assert.Equal(t, []string{"somethingsomething"}, mail.introLines)
+
+	tnf.Until = time.Time{}
+	err = DeliverQueuedNotification(s, tnf, queued[0])
+	require.NoError(t, err)
+	db.AssertMissing(t, "queued_notifications", map[string]interface{}{
+		"notifiable_id": 42,
+	})
 }
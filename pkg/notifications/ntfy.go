@@ -0,0 +1,105 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/outbound"
+)
+
+// NotifiableWithNtfy is a notifiable which gets notifications published to an ntfy topic
+type NotifiableWithNtfy interface {
+	Notifiable
+	// RouteForNtfy should return the ntfy topic of this notifiable and optionally an access token for it.
+	// Returning an empty topic skips ntfy.
+	RouteForNtfy() (topic, token string, err error)
+}
+
+func notifyNtfy(notifiable Notifiable, notification Notification) error {
+	if !config.NtfyEnabled.GetBool() {
+		return nil
+	}
+
+	routed, is := notifiable.(NotifiableWithNtfy)
+	if !is {
+		return nil
+	}
+
+	topic, token, err := routed.RouteForNtfy()
+	if err != nil || topic == "" {
+		return err
+	}
+
+	msg := toPushMessage(notification)
+	if msg == nil {
+		return nil
+	}
+
+	if token == "" {
+		token = config.NtfyToken.GetString()
+	}
+
+	return sendNtfyMessage(outbound.ConfiguredClient(), config.NtfyURL.GetString(), topic, token, msg)
+}
+
+// sendNtfyMessage publishes a message to a topic on an ntfy server
+func sendNtfyMessage(client *http.Client, serverURL, topic, token string, msg *PushMessage) error {
+	req, err := http.NewRequest(
+		http.MethodPost,
+		strings.TrimSuffix(serverURL, "/")+"/"+url.PathEscape(topic),
+		strings.NewReader(msg.Body),
+	)
+	if err != nil {
+		return err
+	}
+	// ntfy reads headers as latin1, the RFC 2047 encoding keeps titles with other characters intact
+	req.Header.Set("Title", mime.QEncoding.Encode("utf-8", msg.Title))
+	req.Header.Set("Priority", strconv.Itoa(config.NtfyPriority.GetInt()))
+	req.Header.Set("Tags", "vikunja,"+msg.Name)
+	if msg.URL != "" {
+		req.Header.Set("Click", msg.URL)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return doChannelRequest(client, req, "ntfy")
+}
+
+// doChannelRequest sends a request to an external service and returns an error if it was not successful
+func doChannelRequest(client *http.Client, req *http.Request, service string) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s returned status %d: %s", service, res.StatusCode, body)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNotifiableWithTargets struct {
+	testNotifiable
+	NtfyTopic    string
+	NtfyToken    string
+	GotifyToken  string
+	MatrixRoomID string
+	URLs         []string
+}
+
+func (t *testNotifiableWithTargets) RouteForNtfy() (topic, token string, err error) {
+	return t.NtfyTopic, t.NtfyToken, nil
+}
+
+func (t *testNotifiableWithTargets) RouteForGotify() (token string, err error) {
+	return t.GotifyToken, nil
+}
+
+func (t *testNotifiableWithTargets) RouteForMatrix() (roomID string, err error) {
+	return t.MatrixRoomID, nil
+}
+
+func (t *testNotifiableWithTargets) RouteForTargets() (targets []string, err error) {
+	return t.URLs, nil
+}
+
+func TestNotifyNtfy(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	config.NtfyEnabled.Set(true)
+	config.NtfyURL.Set(server.URL + "/")
+	config.NtfyToken.Set("instance-token")
+	defer func() {
+		config.NtfyEnabled.Set(false)
+		config.NtfyToken.Set("")
+	}()
+
+	t.Run("publishes to the topic of the notifiable", func(t *testing.T) {
+		requests, bodies = nil, nil
+		err := notifyNtfy(&testNotifiableWithTargets{NtfyTopic: "my-topic"}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/my-topic", requests[0].URL.Path)
+		assert.Equal(t, "Test Notification", requests[0].Header.Get("Title"))
+		assert.Equal(t, "Bearer instance-token", requests[0].Header.Get("Authorization"))
+		assert.Equal(t, "somethingsomething", bodies[0])
+	})
+	t.Run("token of the notifiable", func(t *testing.T) {
+		requests, bodies = nil, nil
+		err := notifyNtfy(&testNotifiableWithTargets{NtfyTopic: "my-topic", NtfyToken: "user-token"}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "Bearer user-token", requests[0].Header.Get("Authorization"))
+	})
+	t.Run("no topic", func(t *testing.T) {
+		requests, bodies = nil, nil
+		err := notifyNtfy(&testNotifiableWithTargets{}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		assert.Empty(t, requests)
+	})
+}
@@ -0,0 +1,401 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/outbound"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/hkdf"
+	"xorm.io/xorm"
+)
+
+// PushSubscriptionKeys are the keys a browser generated for a push subscription to encrypt messages for it
+type PushSubscriptionKeys struct {
+	// The public key of the subscription, encoded as base64url.
+	P256dh string `json:"p256dh"`
+	// The authentication secret of the subscription, encoded as base64url.
+	Auth string `json:"auth"`
+}
+
+// PushSubscription is a browser or device a notifiable wants to get notifications as Web Push messages on
+type PushSubscription struct {
+	// The unique, numeric id of this push subscription.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"pushsubscription"`
+
+	// The ID of the notifiable this push subscription belongs to.
+	NotifiableID int64 `xorm:"bigint not null index" json:"-"`
+	// The url of the push service to send the messages to, as provided by the browser.
+	Endpoint string `xorm:"text not null" json:"endpoint"`
+	// The keys of the subscription. They are only accepted when registering a subscription and never returned.
+	Keys *PushSubscriptionKeys `xorm:"'subscription_keys' json not null" json:"keys,omitempty"`
+	// A name for the device, for example the name of the browser, to recognize the subscription later.
+	Name string `xorm:"varchar(250) null" json:"name"`
+
+	// A timestamp when this push subscription was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+}
+
+// TableName resolves to a better table name for push subscriptions
+func (p *PushSubscription) TableName() string {
+	return "push_subscriptions"
+}
+
+// PushMessage is the short form of a notification which is sent as Web Push message and to chat or push services
+// like ntfy. The service worker of the frontend shows it as a notification.
+type PushMessage struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url,omitempty"`
+	Name  string `json:"name"`
+}
+
+// NotificationWithPush is a notification which provides its own short message for Web Push and similar channels.
+// All other notifications are sent with the subject, the first line and the action of their mail.
+type NotificationWithPush interface {
+	Notification
+	ToPush() *PushMessage
+}
+
+// toPushMessage returns the Web Push message for a notification or nil if it has none
+func toPushMessage(notification Notification) *PushMessage {
+	if withPush, is := notification.(NotificationWithPush); is {
+		return withPush.ToPush()
+	}
+
+	mail := notification.ToMail()
+	if mail == nil {
+		return nil
+	}
+
+	msg := &PushMessage{
+		Title: mail.subject,
+		URL:   mail.actionURL,
+		Name:  notification.Name(),
+	}
+	if len(mail.introLines) > 0 {
+		msg.Body = strings.TrimSpace(mail.introLines[0])
+	}
+	return msg
+}
+
+// GetPushSubscriptionsForNotifiable returns all push subscriptions of a notifiable.
+func GetPushSubscriptionsForNotifiable(s *xorm.Session, notifiableID int64) (subscriptions []*PushSubscription, err error) {
+	subscriptions = []*PushSubscription{}
+	err = s.
+		Where("notifiable_id = ?", notifiableID).
+		OrderBy("id ASC").
+		Find(&subscriptions)
+	return
+}
+
+// SavePushSubscription creates a push subscription or updates the existing one with the same endpoint, because
+// browsers return the same endpoint again when a subscription is registered twice.
+func SavePushSubscription(s *xorm.Session, subscription *PushSubscription) (err error) {
+	existing := &PushSubscription{}
+	exists, err := s.
+		Where("notifiable_id = ? AND endpoint = ?", subscription.NotifiableID, subscription.Endpoint).
+		Get(existing)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		_, err = s.Insert(subscription)
+		return
+	}
+
+	subscription.ID = existing.ID
+	subscription.Created = existing.Created
+	_, err = s.
+		Where("id = ?", subscription.ID).
+		Cols("subscription_keys", "name").
+		Update(subscription)
+	return
+}
+
+// DeletePushSubscription removes a push subscription of a notifiable. It returns false if there was none to remove.
+func DeletePushSubscription(s *xorm.Session, subscriptionID, notifiableID int64) (deleted bool, err error) {
+	affected, err := s.
+		Where("id = ? AND notifiable_id = ?", subscriptionID, notifiableID).
+		Delete(&PushSubscription{})
+	return affected > 0, err
+}
+
+// DeleteAllPushSubscriptions removes all push subscriptions of a notifiable.
+func DeleteAllPushSubscriptions(s *xorm.Session, notifiableID int64) (err error) {
+	_, err = s.
+		Where("notifiable_id = ?", notifiableID).
+		Delete(&PushSubscription{})
+	return
+}
+
+func notifyPush(notifiable Notifiable, notification Notification) error {
+	if !config.WebPushEnabled.GetBool() {
+		return nil
+	}
+
+	msg := toPushMessage(notification)
+	if msg == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	subscriptions, err := GetPushSubscriptionsForNotifiable(s, notifiable.RouteForDB())
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		gone, err := sendPushMessage(subscription, payload)
+		if err != nil {
+			// A broken subscription of one device should not prevent the other devices from getting the message
+			log.Errorf("Could not send push message to push subscription %d: %s", subscription.ID, err)
+			continue
+		}
+		if gone {
+			log.Debugf("Push subscription %d expired, removing it", subscription.ID)
+			_, err = DeletePushSubscription(s, subscription.ID, subscription.NotifiableID)
+			if err != nil {
+				_ = s.Rollback()
+				return err
+			}
+		}
+	}
+
+	return s.Commit()
+}
+
+// sendPushMessage encrypts and sends one message to a push subscription. It returns true if the push service
+// reported the subscription does not exist anymore.
+func sendPushMessage(subscription *PushSubscription, payload []byte) (gone bool, err error) {
+	body, err := encryptPushPayload(subscription.Keys, payload)
+	if err != nil {
+		return false, err
+	}
+
+	authorization, err := getVAPIDAuthorization(subscription.Endpoint)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", "86400")
+	req.Header.Set("Authorization", authorization)
+
+	res, err := outbound.DefaultClient().Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone {
+		return true, nil
+	}
+	if res.StatusCode >= 300 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return false, fmt.Errorf("push service returned status %d: %s", res.StatusCode, resBody)
+	}
+
+	return false, nil
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// ValidatePushSubscriptionKeys checks the keys of a push subscription can be used to encrypt messages
+func ValidatePushSubscriptionKeys(keys *PushSubscriptionKeys) bool {
+	if keys == nil {
+		return false
+	}
+	p256dh, err := decodeBase64URL(keys.P256dh)
+	if err != nil {
+		return false
+	}
+	if _, err = ecdh.P256().NewPublicKey(p256dh); err != nil {
+		return false
+	}
+	auth, err := decodeBase64URL(keys.Auth)
+	return err == nil && len(auth) == 16
+}
+
+// encryptPushPayload encrypts a push message for a subscription as described in RFC 8291
+func encryptPushPayload(keys *PushSubscriptionKeys, payload []byte) ([]byte, error) {
+	if keys == nil {
+		return nil, fmt.Errorf("push subscription has no keys")
+	}
+	p256dh, err := decodeBase64URL(keys.P256dh)
+	if err != nil {
+		return nil, err
+	}
+	authSecret, err := decodeBase64URL(keys.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgentPublic, err := ecdh.P256().NewPublicKey(p256dh)
+	if err != nil {
+		return nil, err
+	}
+	serverPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serverPublic := serverPrivate.PublicKey().Bytes()
+	sharedSecret, err := serverPrivate.ECDH(userAgentPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := append(append([]byte("WebPush: info\x00"), p256dh...), serverPublic...)
+	ikm := make([]byte, 32)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, sharedSecret, authSecret, keyInfo), ikm); err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	contentKey := make([]byte, 16)
+	if _, err = io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), contentKey); err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 12)
+	if _, err = io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The whole message is sent as a single record, 0x02 marks it as the last one.
+	record := gcm.Seal(nil, nonce, append(append([]byte{}, payload...), 0x02), nil)
+
+	header := make([]byte, 0, 16+4+1+len(serverPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, 4096)
+	header = append(header, byte(len(serverPublic)))
+	header = append(header, serverPublic...)
+
+	return append(header, record...), nil
+}
+
+// getVAPIDAuthorization returns the Authorization header identifying this instance to the push service of an endpoint
+func getVAPIDAuthorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	privateKey, err := getVAPIDPrivateKey()
+	if err != nil {
+		return "", err
+	}
+
+	subject := config.WebPushSubject.GetString()
+	if subject == "" {
+		subject = "mailto:" + config.MailerFromEmail.GetString()
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": subject,
+	}).SignedString(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return "vapid t=" + token + ", k=" + config.WebPushPublicKey.GetString(), nil
+}
+
+func getVAPIDPrivateKey() (*ecdsa.PrivateKey, error) {
+	d, err := decodeBase64URL(config.WebPushPrivateKey.GetString())
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The public key is encoded uncompressed: 0x04 || x || y
+	public := key.PublicKey().Bytes()
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}, nil
+}
+
+// GenerateVAPIDKeys generates a new key pair to use for Web Push, both encoded as unpadded base64url
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(key.Bytes()),
+		nil
+}
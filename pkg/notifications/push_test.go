@@ -0,0 +1,179 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/hkdf"
+)
+
+// decryptPushPayload decrypts a push message the way a browser does, see RFC 8291
+func decryptPushPayload(t *testing.T, private *ecdh.PrivateKey, authSecret, body []byte) []byte {
+	salt := body[:16]
+	rs := binary.BigEndian.Uint32(body[16:20])
+	assert.Equal(t, uint32(4096), rs)
+	idLen := int(body[20])
+	serverPublicBytes := body[21 : 21+idLen]
+	record := body[21+idLen:]
+
+	serverPublic, err := ecdh.P256().NewPublicKey(serverPublicBytes)
+	require.NoError(t, err)
+	sharedSecret, err := private.ECDH(serverPublic)
+	require.NoError(t, err)
+
+	keyInfo := append(append([]byte("WebPush: info\x00"), private.PublicKey().Bytes()...), serverPublicBytes...)
+	ikm := make([]byte, 32)
+	_, err = io.ReadFull(hkdf.New(sha256.New, sharedSecret, authSecret, keyInfo), ikm)
+	require.NoError(t, err)
+
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	contentKey := make([]byte, 16)
+	_, err = io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), contentKey)
+	require.NoError(t, err)
+	nonce := make([]byte, 12)
+	_, err = io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(contentKey)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	plaintext, err := gcm.Open(nil, nonce, record, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, byte(0x02), plaintext[len(plaintext)-1])
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestNotifyPush(t *testing.T) {
+	publicKey, privateKey, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+	config.WebPushEnabled.Set(true)
+	config.WebPushPublicKey.Set(publicKey)
+	config.WebPushPrivateKey.Set(privateKey)
+	defer config.WebPushEnabled.Set(false)
+
+	browserKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	authSecret := make([]byte, 16)
+	_, err = rand.Read(authSecret)
+	require.NoError(t, err)
+	keys := &PushSubscriptionKeys{
+		P256dh: base64.RawURLEncoding.EncodeToString(browserKey.PublicKey().Bytes()),
+		Auth:   base64.RawURLEncoding.EncodeToString(authSecret),
+	}
+
+	t.Run("sends encrypted message", func(t *testing.T) {
+		var received *PushMessage
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "aes128gcm", r.Header.Get("Content-Encoding"))
+
+			authorization := r.Header.Get("Authorization")
+			require.True(t, strings.HasPrefix(authorization, "vapid t="))
+			assert.True(t, strings.HasSuffix(authorization, ", k="+publicKey))
+			token := strings.TrimSuffix(strings.TrimPrefix(authorization, "vapid t="), ", k="+publicKey)
+			vapidKey, err := getVAPIDPrivateKey()
+			require.NoError(t, err)
+			parsed, err := jwt.Parse(token, func(_ *jwt.Token) (interface{}, error) {
+				return &vapidKey.PublicKey, nil
+			}, jwt.WithValidMethods([]string{"ES256"}))
+			require.NoError(t, err)
+			aud, err := parsed.Claims.GetAudience()
+			require.NoError(t, err)
+			assert.Equal(t, jwt.ClaimStrings{"http://" + r.Host}, aud)
+
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			received = &PushMessage{}
+			err = json.Unmarshal(decryptPushPayload(t, browserKey, authSecret, body), received)
+			require.NoError(t, err)
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Exec("delete from push_subscriptions")
+		require.NoError(t, err)
+		err = SavePushSubscription(s, &PushSubscription{NotifiableID: 42, Endpoint: server.URL + "/push/1", Keys: keys})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		err = notifyPush(&testNotifiable{ShouldSendNotification: true}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.NotNil(t, received)
+		assert.Equal(t, "Test Notification", received.Title)
+		assert.Equal(t, "somethingsomething", received.Body)
+		assert.Equal(t, "test.notification", received.Name)
+	})
+	t.Run("removes expired subscriptions", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		}))
+		defer server.Close()
+
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Exec("delete from push_subscriptions")
+		require.NoError(t, err)
+		err = SavePushSubscription(s, &PushSubscription{NotifiableID: 42, Endpoint: server.URL, Keys: keys})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		err = notifyPush(&testNotifiable{ShouldSendNotification: true}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		db.AssertMissing(t, "push_subscriptions", map[string]interface{}{
+			"notifiable_id": 42,
+		})
+	})
+	t.Run("same endpoint is saved only once", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Exec("delete from push_subscriptions")
+		require.NoError(t, err)
+
+		err = SavePushSubscription(s, &PushSubscription{NotifiableID: 42, Endpoint: "https://push.example.com/1", Keys: keys})
+		require.NoError(t, err)
+		err = SavePushSubscription(s, &PushSubscription{NotifiableID: 42, Endpoint: "https://push.example.com/1", Keys: keys, Name: "Firefox"})
+		require.NoError(t, err)
+
+		subscriptions, err := GetPushSubscriptionsForNotifiable(s, 42)
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 1)
+		assert.Equal(t, "Firefox", subscriptions[0].Name)
+	})
+}
@@ -0,0 +1,186 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+
+	"xorm.io/xorm"
+)
+
+// NotifiableWithQuietHours is a notifiable which does not want to be disturbed at certain times.
+// Notifications sent during their quiet hours are saved in the database right away and delivered via all other
+// channels once the quiet hours are over.
+type NotifiableWithQuietHours interface {
+	Notifiable
+	// QuietUntil should return when the current quiet hours of the notifiable end or a zero time if the
+	// notification should be delivered right away. Urgent is true for notifications like reminders which
+	// the notifiable might want to get during their quiet hours anyway.
+	QuietUntil(now time.Time, notificationName string, urgent bool) (end time.Time, err error)
+}
+
+// UrgentNotification is a notification which can be delivered during quiet hours if the notifiable wants that.
+type UrgentNotification interface {
+	Notification
+	Urgent() bool
+}
+
+// queuedMail holds the content of a mail of a queued notification
+type queuedMail struct {
+	From       string   `json:"from"`
+	Subject    string   `json:"subject"`
+	ActionText string   `json:"action_text"`
+	ActionURL  string   `json:"action_url"`
+	Greeting   string   `json:"greeting"`
+	IntroLines []string `json:"intro_lines"`
+	OutroLines []string `json:"outro_lines"`
+}
+
+// QueuedNotification is a notification which was sent during the quiet hours of a notifiable and waits for them
+// to be over. It holds the content of the notification at the time it was sent.
+type QueuedNotification struct {
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	// The ID of the notifiable this notification is queued for.
+	NotifiableID int64 `xorm:"bigint not null INDEX" json:"-"`
+	// The name of the queued notification
+	NotificationName string `xorm:"varchar(250) not null 'name'" json:"-"`
+
+	Mail *queuedMail  `xorm:"json null" json:"-"`
+	Push *PushMessage `xorm:"json null" json:"-"`
+
+	// When the quiet hours of the notifiable end and the notification is delivered.
+	DeliverAt time.Time `xorm:"datetime not null INDEX" json:"-"`
+	Created   time.Time `xorm:"created not null" json:"-"`
+}
+
+// TableName returns the table name for queued notifications
+func (q *QueuedNotification) TableName() string {
+	return "queued_notifications"
+}
+
+// ToMail returns the mail of the notification as it was when the notification was queued
+func (q *QueuedNotification) ToMail() *Mail {
+	if q.Mail == nil {
+		return nil
+	}
+
+	return &Mail{
+		from:       q.Mail.From,
+		subject:    q.Mail.Subject,
+		actionText: q.Mail.ActionText,
+		actionURL:  q.Mail.ActionURL,
+		greeting:   q.Mail.Greeting,
+		introLines: q.Mail.IntroLines,
+		outroLines: q.Mail.OutroLines,
+	}
+}
+
+// ToDB is never used because database notifications are not queued
+func (q *QueuedNotification) ToDB() interface{} {
+	return nil
+}
+
+// Name returns the name of the queued notification
+func (q *QueuedNotification) Name() string {
+	return q.NotificationName
+}
+
+// ToPush returns the push message of the notification as it was when the notification was queued
+func (q *QueuedNotification) ToPush() *PushMessage {
+	return q.Push
+}
+
+func quietHoursEnd(notifiable Notifiable, notification Notification) (end time.Time, err error) {
+	withQuietHours, is := notifiable.(NotifiableWithQuietHours)
+	if !is {
+		return
+	}
+
+	var urgent bool
+	if u, is := notification.(UrgentNotification); is {
+		urgent = u.Urgent()
+	}
+
+	return withQuietHours.QuietUntil(time.Now(), notification.Name(), urgent)
+}
+
+func queueNotification(notifiable Notifiable, notification Notification, deliverAt time.Time) (err error) {
+	queued := &QueuedNotification{
+		NotifiableID:     notifiable.RouteForDB(),
+		NotificationName: notification.Name(),
+		Push:             toPushMessage(notification),
+		DeliverAt:        deliverAt,
+	}
+
+	if mail := notification.ToMail(); mail != nil {
+		queued.Mail = &queuedMail{
+			From:       mail.from,
+			Subject:    mail.subject,
+			ActionText: mail.actionText,
+			ActionURL:  mail.actionURL,
+			Greeting:   mail.greeting,
+			IntroLines: mail.introLines,
+			OutroLines: mail.outroLines,
+		}
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err = s.Insert(queued)
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	log.Debugf("Queued notification %s for notifiable %d until %s", queued.NotificationName, queued.NotifiableID, deliverAt)
+
+	return s.Commit()
+}
+
+// GetDueQueuedNotifications returns all queued notifications which should be delivered at or before now.
+func GetDueQueuedNotifications(s *xorm.Session, now time.Time) (queued []*QueuedNotification, err error) {
+	queued = []*QueuedNotification{}
+	err = s.
+		Where("deliver_at <= ?", now).
+		OrderBy("id asc").
+		Find(&queued)
+	return
+}
+
+// DeliverQueuedNotification sends a queued notification via all channels except the database and removes it
+// from the queue.
+func DeliverQueuedNotification(s *xorm.Session, notifiable Notifiable, queued *QueuedNotification) (err error) {
+	_, err = s.
+		Where("id = ?", queued.ID).
+		Delete(&QueuedNotification{})
+	if err != nil {
+		return err
+	}
+
+	if isUnderTest {
+		sentTestNotifications = append(sentTestNotifications, queued)
+		return nil
+	}
+
+	return notifyChannels(notifiable, queued, func(channel Channel) bool {
+		return channel != ChannelDB
+	})
+}
@@ -0,0 +1,92 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/outbound"
+)
+
+// SlackMessage is a message posted to a Slack incoming webhook or as response to a slash command or button
+type SlackMessage struct {
+	// Either `in_channel` to show the response to a command to everyone in the channel or `ephemeral` to only
+	// show it to the user who used the command.
+	ResponseType string `json:"response_type,omitempty"`
+	// If true, the response to a button replaces the message the button belongs to.
+	ReplaceOriginal bool `json:"replace_original,omitempty"`
+	// The text of the message in the Slack mrkdwn format. It is also the fallback for notifications if the message
+	// has blocks.
+	Text   string                   `json:"text"`
+	Blocks []map[string]interface{} `json:"blocks,omitempty"`
+}
+
+// slackRequestMaxAge is how old a request from Slack can be before it is rejected because it might be replayed
+const slackRequestMaxAge = 5 * time.Minute
+
+// SlackEscape escapes the characters which have a special meaning in the Slack mrkdwn format
+func SlackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// SendSlackMessage posts a message to a Slack incoming webhook or the response url of a command
+func SendSlackMessage(url string, msg *SlackMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doChannelRequest(outbound.DefaultClient(), req, "slack")
+}
+
+// VerifySlackRequest checks a request was signed by Slack with the signing secret of the app.
+// See https://api.slack.com/authentication/verifying-requests-from-slack
+func VerifySlackRequest(signingSecret, timestamp, signature string, body []byte, now time.Time) bool {
+	if signingSecret == "" {
+		return false
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(ts, 0))
+	if age > slackRequestMaxAge || age < -slackRequestMaxAge {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,57 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySlackRequest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("command=%2Fvikunja&text=add+Buy+milk")
+	sign := func(secret, timestamp string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte("v0:" + timestamp + ":"))
+		mac.Write(body)
+		return "v0=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	t.Run("valid", func(t *testing.T) {
+		assert.True(t, VerifySlackRequest("secret", timestamp, sign("secret", timestamp), body, now))
+	})
+	t.Run("wrong secret", func(t *testing.T) {
+		assert.False(t, VerifySlackRequest("secret", timestamp, sign("other", timestamp), body, now))
+	})
+	t.Run("too old", func(t *testing.T) {
+		assert.False(t, VerifySlackRequest("secret", timestamp, sign("secret", timestamp), body, now.Add(10*time.Minute)))
+	})
+	t.Run("no secret configured", func(t *testing.T) {
+		assert.False(t, VerifySlackRequest("", timestamp, sign("", timestamp), body, now))
+	})
+}
+
+func TestSlackEscape(t *testing.T) {
+	assert.Equal(t, "a &amp; b &lt;c&gt;", SlackEscape("a & b <c>"))
+}
@@ -0,0 +1,244 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/outbound"
+)
+
+// NotifiableWithTargets is a notifiable which gets notifications on services configured through target urls.
+type NotifiableWithTargets interface {
+	Notifiable
+	// RouteForTargets should return the target urls of all services the notifiable wants to get notifications on.
+	RouteForTargets() (targets []string, err error)
+}
+
+// targetSchemes holds how messages are sent to target urls with a scheme Vikunja supports natively.
+// Target urls with any other scheme are passed to the configured Apprise API server.
+var targetSchemes = map[string]func(target *url.URL, msg *PushMessage) error{
+	"ntfy":    sendToNtfyTarget,
+	"ntfys":   sendToNtfyTarget,
+	"gotify":  sendToGotifyTarget,
+	"gotifys": sendToGotifyTarget,
+	"discord": sendToDiscordTarget,
+	"slack":   sendToSlackTarget,
+	"json":    sendToJSONTarget,
+	"jsons":   sendToJSONTarget,
+}
+
+// ValidateTarget checks a notification target url is well-formed and Vikunja knows how to send messages to it.
+func ValidateTarget(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return errors.New("the target needs a scheme and a host")
+	}
+
+	if _, native := targetSchemes[u.Scheme]; native {
+		return nil
+	}
+	if config.NotificationTargetsAppriseURL.GetString() == "" {
+		return errors.New("the scheme " + u.Scheme + " is not supported")
+	}
+	return nil
+}
+
+func notifyTargets(notifiable Notifiable, notification Notification) error {
+	if !config.NotificationTargetsEnabled.GetBool() {
+		return nil
+	}
+
+	routed, is := notifiable.(NotifiableWithTargets)
+	if !is {
+		return nil
+	}
+
+	targets, err := routed.RouteForTargets()
+	if err != nil || len(targets) == 0 {
+		return err
+	}
+
+	msg := toPushMessage(notification)
+	if msg == nil {
+		return nil
+	}
+
+	for _, target := range targets {
+		err = SendToTarget(target, msg)
+		if err != nil {
+			// One broken target should not keep the notification from the others
+			log.Errorf("Could not send notification %s to a target of notifiable %d: %s", msg.Name, notifiable.RouteForDB(), err)
+		}
+	}
+
+	return nil
+}
+
+// SendToTarget sends a message to the service a target url points to
+func SendToTarget(target string, msg *PushMessage) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+
+	if send, native := targetSchemes[u.Scheme]; native {
+		return send(u, msg)
+	}
+
+	return sendToAppriseTarget(target, msg)
+}
+
+// targetBaseURL returns the http base url of a target, using https if the scheme ends with an s
+func targetBaseURL(target *url.URL, path string) string {
+	scheme := "http"
+	if strings.HasSuffix(target.Scheme, "s") {
+		scheme = "https"
+	}
+	return scheme + "://" + target.Host + path
+}
+
+// sendToNtfyTarget publishes a message to ntfy://[token@]host/topic or ntfys://[token@]host/topic
+func sendToNtfyTarget(target *url.URL, msg *PushMessage) error {
+	topic := strings.Trim(target.Path, "/")
+	if topic == "" {
+		return errors.New("the ntfy target has no topic")
+	}
+	return sendNtfyMessage(outbound.DefaultClient(), targetBaseURL(target, ""), topic, target.User.Username(), msg)
+}
+
+// sendToGotifyTarget sends a message to gotify://host[/path]/token or gotifys://host[/path]/token
+func sendToGotifyTarget(target *url.URL, msg *PushMessage) error {
+	path := strings.Trim(target.Path, "/")
+	i := strings.LastIndex(path, "/")
+	token := path[i+1:]
+	if token == "" {
+		return errors.New("the gotify target has no token")
+	}
+
+	base := ""
+	if i > 0 {
+		base = "/" + path[:i]
+	}
+	return sendGotifyMessage(outbound.DefaultClient(), targetBaseURL(target, base), token, msg)
+}
+
+// sendToDiscordTarget posts a message to discord://webhook_id/webhook_token
+func sendToDiscordTarget(target *url.URL, msg *PushMessage) error {
+	token := strings.Trim(target.Path, "/")
+	if token == "" {
+		return errors.New("the discord target has no webhook token")
+	}
+
+	return SendDiscordMessage("https://discord.com/api/webhooks/"+url.PathEscape(target.Host)+"/"+url.PathEscape(token), &DiscordMessage{
+		Embeds: []*DiscordEmbed{{
+			Title:       msg.Title,
+			Description: msg.Body,
+			URL:         msg.URL,
+		}},
+	})
+}
+
+// sendToSlackTarget posts a message to slack://TokenA/TokenB/TokenC, the parts of the url of an incoming webhook
+func sendToSlackTarget(target *url.URL, msg *PushMessage) error {
+	tokens := strings.Split(strings.Trim(target.Path, "/"), "/")
+	if len(tokens) != 2 {
+		return errors.New("the slack target needs three tokens")
+	}
+
+	text := "*" + SlackEscape(msg.Title) + "*"
+	if msg.Body != "" {
+		text += "\n" + SlackEscape(msg.Body)
+	}
+	if msg.URL != "" {
+		text += "\n<" + msg.URL + ">"
+	}
+
+	return SendSlackMessage("https://hooks.slack.com/services/"+target.Host+"/"+tokens[0]+"/"+tokens[1], &SlackMessage{Text: text})
+}
+
+// sendToJSONTarget posts the message as json to json://host/path or jsons://host/path
+func sendToJSONTarget(target *url.URL, msg *PushMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	endpoint := targetBaseURL(target, target.EscapedPath())
+	if target.RawQuery != "" {
+		endpoint += "?" + target.RawQuery
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if target.User != nil {
+		password, _ := target.User.Password()
+		req.SetBasicAuth(target.User.Username(), password)
+	}
+
+	return doChannelRequest(outbound.DefaultClient(), req, "json target")
+}
+
+type appriseMessage struct {
+	URLs  string `json:"urls"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// sendToAppriseTarget passes a message for any other target url to the stateless notify endpoint of the
+// configured Apprise API server, which supports a lot more services.
+func sendToAppriseTarget(target string, msg *PushMessage) error {
+	server := config.NotificationTargetsAppriseURL.GetString()
+	if server == "" {
+		return errors.New("no apprise api server configured")
+	}
+
+	body := msg.Body
+	if msg.URL != "" {
+		body = strings.TrimSpace(body + "\n" + msg.URL)
+	}
+	if body == "" {
+		// Apprise requires a body
+		body = msg.Title
+	}
+
+	payload, err := json.Marshal(&appriseMessage{URLs: target, Title: msg.Title, Body: body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(server, "/")+"/notify/", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doChannelRequest(outbound.ConfiguredClient(), req, "apprise")
+}
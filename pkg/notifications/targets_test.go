@@ -0,0 +1,110 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTarget(t *testing.T) {
+	require.NoError(t, ValidateTarget("ntfys://token@ntfy.example.com/topic"))
+	require.NoError(t, ValidateTarget("discord://1234/abcd"))
+	require.Error(t, ValidateTarget("ntfy.example.com/topic"))
+	require.Error(t, ValidateTarget("tgram://bottoken/chatid"))
+
+	config.NotificationTargetsAppriseURL.Set("https://apprise.example.com")
+	defer config.NotificationTargetsAppriseURL.Set("")
+	require.NoError(t, ValidateTarget("tgram://bottoken/chatid"))
+}
+
+func TestNotifyTargets(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	config.NotificationTargetsEnabled.Set(true)
+	config.NotificationTargetsAppriseURL.Set(server.URL + "/apprise")
+	defer func() {
+		config.NotificationTargetsEnabled.Set(false)
+		config.NotificationTargetsAppriseURL.Set("")
+	}()
+
+	t.Run("json", func(t *testing.T) {
+		requests, bodies = nil, nil
+		err := notifyTargets(&testNotifiableWithTargets{URLs: []string{"json://" + host + "/hook"}}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/hook", requests[0].URL.Path)
+		msg := &PushMessage{}
+		require.NoError(t, json.Unmarshal([]byte(bodies[0]), msg))
+		assert.Equal(t, "Test Notification", msg.Title)
+		assert.Equal(t, "somethingsomething", msg.Body)
+	})
+	t.Run("ntfy", func(t *testing.T) {
+		requests, bodies = nil, nil
+		err := notifyTargets(&testNotifiableWithTargets{URLs: []string{"ntfy://secret@" + host + "/my-topic"}}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/my-topic", requests[0].URL.Path)
+		assert.Equal(t, "Bearer secret", requests[0].Header.Get("Authorization"))
+		assert.Equal(t, "somethingsomething", bodies[0])
+	})
+	t.Run("gotify", func(t *testing.T) {
+		requests, bodies = nil, nil
+		err := notifyTargets(&testNotifiableWithTargets{URLs: []string{"gotify://" + host + "/sub/apptoken"}}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/sub/message", requests[0].URL.Path)
+		assert.Equal(t, "apptoken", requests[0].Header.Get("X-Gotify-Key"))
+	})
+	t.Run("other schemes through apprise", func(t *testing.T) {
+		requests, bodies = nil, nil
+		err := notifyTargets(&testNotifiableWithTargets{URLs: []string{"tgram://bottoken/chatid"}}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/apprise/notify/", requests[0].URL.Path)
+		msg := &appriseMessage{}
+		require.NoError(t, json.Unmarshal([]byte(bodies[0]), msg))
+		assert.Equal(t, "tgram://bottoken/chatid", msg.URLs)
+		assert.Equal(t, "somethingsomething", msg.Body)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		requests, bodies = nil, nil
+		config.NotificationTargetsEnabled.Set(false)
+		defer config.NotificationTargetsEnabled.Set(true)
+		err := notifyTargets(&testNotifiableWithTargets{URLs: []string{"json://" + host + "/hook"}}, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+		assert.Empty(t, requests)
+	})
+}
@@ -0,0 +1,263 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package outbound provides the http client which is used for all requests Vikunja makes to urls provided by users,
+// like webhooks, remote file downloads, migrations and notification channels.
+package outbound
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/version"
+)
+
+var (
+	allowedNetworks    []*net.IPNet
+	deniedNetworks     []*net.IPNet
+	proxyURL           *url.URL
+	sharedAddressSpace *net.IPNet
+
+	defaultClient     *http.Client
+	configuredClient  *http.Client
+	defaultClientLock sync.Mutex
+)
+
+// The timeout of the default client
+const defaultTimeout = 30 * time.Second
+
+func init() {
+	_, sharedAddressSpace, _ = net.ParseCIDR("100.64.0.0/10")
+}
+
+// Options change how a client created with NewClient makes requests
+type Options struct {
+	// The timeout of all requests made with the client.
+	Timeout time.Duration
+	// If true, only public addresses can be reached, regardless of the configuration. The configured proxy is not
+	// used either, so that the check can't be bypassed.
+	PublicOnly bool
+	// If true, non-public addresses can be reached even if outbound.blocknonpublicaddresses is enabled.
+	// Denied networks are still rejected. Only use this for destinations which were configured by the administrator,
+	// never for urls provided by users.
+	AllowNonPublic bool
+	// The url of a proxy which should be used instead of the configured one.
+	ProxyURL *url.URL
+	// Headers which are sent to the proxy when connecting to it.
+	ProxyConnectHeader http.Header
+}
+
+// ErrAddressNotAllowed is returned when a request would connect to an address which is not allowed
+type ErrAddressNotAllowed struct {
+	Address string
+}
+
+func (err *ErrAddressNotAllowed) Error() string {
+	return fmt.Sprintf("connecting to %s is not allowed", err.Address)
+}
+
+func parseNetworks(key config.Key) (networks []*net.IPNet, err error) {
+	for _, raw := range key.GetStringSlice() {
+		_, network, err := net.ParseCIDR(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %s in %s: %w", raw, key, err)
+		}
+		networks = append(networks, network)
+	}
+
+	return
+}
+
+// Init parses the configured allowed and denied networks and the proxy url
+func Init() (err error) {
+	allowedNetworks, err = parseNetworks(config.OutboundAllowedCIDRs)
+	if err != nil {
+		return err
+	}
+
+	deniedNetworks, err = parseNetworks(config.OutboundDeniedCIDRs)
+	if err != nil {
+		return err
+	}
+
+	defaultClientLock.Lock()
+	defaultClient = nil
+	configuredClient = nil
+	defaultClientLock.Unlock()
+
+	proxyURL = nil
+	if config.OutboundProxyURL.GetString() != "" {
+		proxyURL, err = url.Parse(config.OutboundProxyURL.GetString())
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", config.OutboundProxyURL, err)
+		}
+	}
+
+	return nil
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPublicIP returns whether an ip address is reachable from the public internet
+func IsPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// CheckIP checks if a connection to an ip address is allowed.
+// Denied networks always win. Non-public addresses are only rejected if publicOnly is true or
+// outbound.blocknonpublicaddresses is enabled and the address is not part of an allowed network.
+func CheckIP(ip net.IP, publicOnly bool) error {
+	return checkIP(ip, &Options{PublicOnly: publicOnly})
+}
+
+func checkIP(ip net.IP, opts *Options) error {
+	if ip == nil {
+		return &ErrAddressNotAllowed{Address: "<nil>"}
+	}
+
+	if containsIP(deniedNetworks, ip) {
+		return &ErrAddressNotAllowed{Address: ip.String()}
+	}
+
+	if IsPublicIP(ip) {
+		return nil
+	}
+
+	if opts.PublicOnly {
+		return &ErrAddressNotAllowed{Address: ip.String()}
+	}
+
+	if opts.AllowNonPublic {
+		return nil
+	}
+
+	if config.OutboundBlockNonPublicAddresses.GetBool() && !containsIP(allowedNetworks, ip) {
+		return &ErrAddressNotAllowed{Address: ip.String()}
+	}
+
+	return nil
+}
+
+// checkAddress is called right before a connection is made, after the host name was resolved.
+// Checking the address here instead of before the request also prevents dns rebinding and redirects to internal addresses.
+func checkAddress(opts *Options) func(_, address string, _ syscall.RawConn) error {
+	return func(_, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+
+		return checkIP(net.ParseIP(host), opts)
+	}
+}
+
+func getProxy(opts *Options) (*url.URL, http.Header) {
+	if opts.PublicOnly {
+		return nil, nil
+	}
+
+	if opts.ProxyURL != nil {
+		return opts.ProxyURL, opts.ProxyConnectHeader
+	}
+
+	if proxyURL == nil {
+		return nil, nil
+	}
+
+	// Credentials in the proxy url are sent by the transport itself
+	return proxyURL, http.Header{
+		"User-Agent": []string{"Vikunja/" + version.Version},
+	}
+}
+
+// NewClient creates a http client which checks every address it connects to against the configured allowed and
+// denied networks. If a proxy is used, the client only connects to the proxy which is then responsible for
+// checking the addresses.
+func NewClient(opts *Options) *http.Client {
+	proxy, proxyHeader := getProxy(opts)
+
+	dialer := &net.Dialer{
+		Timeout: opts.Timeout,
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+		transport.ProxyConnectHeader = proxyHeader
+	} else {
+		dialer.Control = checkAddress(opts)
+	}
+
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+	}
+}
+
+// DefaultClient returns a shared client with the default timeout and no special options.
+// Use it for all requests to urls provided by users.
+func DefaultClient() *http.Client {
+	defaultClientLock.Lock()
+	defer defaultClientLock.Unlock()
+
+	if defaultClient == nil {
+		defaultClient = NewClient(&Options{Timeout: defaultTimeout})
+	}
+
+	return defaultClient
+}
+
+// ConfiguredClient returns a shared client with the default timeout which can reach non-public addresses.
+// Use it only for services the administrator configured in the config file, like the matrix homeserver.
+func ConfiguredClient() *http.Client {
+	defaultClientLock.Lock()
+	defer defaultClientLock.Unlock()
+
+	if configuredClient == nil {
+		configuredClient = NewClient(&Options{Timeout: defaultTimeout, AllowNonPublic: true})
+	}
+
+	return configuredClient
+}
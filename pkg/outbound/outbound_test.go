@@ -0,0 +1,152 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package outbound
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"1.1.1.1":     true,
+		"127.0.0.1":   false,
+		"10.1.2.3":    false,
+		"192.168.1.1": false,
+		"169.254.1.1": false,
+		"100.64.0.1":  false,
+		"0.0.0.0":     false,
+		"::1":         false,
+		"fd00::1":     false,
+		"2606:4700::": true,
+	} {
+		assert.Equal(t, public, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func setConfig(t *testing.T, blockNonPublic bool, allowed, denied []string) {
+	config.OutboundBlockNonPublicAddresses.Set(blockNonPublic)
+	config.OutboundAllowedCIDRs.Set(allowed)
+	config.OutboundDeniedCIDRs.Set(denied)
+	require.NoError(t, Init())
+
+	t.Cleanup(func() {
+		config.OutboundBlockNonPublicAddresses.Set(true)
+		config.OutboundAllowedCIDRs.Set([]string{})
+		config.OutboundDeniedCIDRs.Set([]string{})
+		require.NoError(t, Init())
+	})
+}
+
+func TestCheckIP(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		setConfig(t, false, nil, nil)
+		require.NoError(t, CheckIP(net.ParseIP("1.1.1.1"), false))
+		require.NoError(t, CheckIP(net.ParseIP("10.1.2.3"), false))
+	})
+	t.Run("public only", func(t *testing.T) {
+		setConfig(t, false, []string{"10.1.2.0/24"}, nil)
+		require.NoError(t, CheckIP(net.ParseIP("1.1.1.1"), true))
+		require.Error(t, CheckIP(net.ParseIP("10.1.2.3"), true))
+	})
+	t.Run("block non public", func(t *testing.T) {
+		setConfig(t, true, []string{"10.1.2.0/24"}, nil)
+		require.NoError(t, CheckIP(net.ParseIP("1.1.1.1"), false))
+		require.NoError(t, CheckIP(net.ParseIP("10.1.2.3"), false))
+		err := CheckIP(net.ParseIP("169.254.169.254"), false)
+		require.Error(t, err)
+		assert.IsType(t, &ErrAddressNotAllowed{}, err)
+	})
+	t.Run("denied takes precedence", func(t *testing.T) {
+		setConfig(t, true, []string{"10.1.2.0/24"}, []string{"10.1.2.3/32", "1.1.1.0/24"})
+		require.Error(t, CheckIP(net.ParseIP("10.1.2.3"), false))
+		require.Error(t, CheckIP(net.ParseIP("1.1.1.1"), false))
+		require.NoError(t, CheckIP(net.ParseIP("10.1.2.4"), false))
+	})
+	t.Run("invalid network", func(t *testing.T) {
+		config.OutboundDeniedCIDRs.Set([]string{"10.1.2.3"})
+		t.Cleanup(func() {
+			config.OutboundDeniedCIDRs.Set([]string{})
+			require.NoError(t, Init())
+		})
+		require.Error(t, Init())
+	})
+}
+
+func get(client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	t.Run("allowed", func(t *testing.T) {
+		setConfig(t, false, nil, nil)
+		res, err := get(NewClient(&Options{Timeout: time.Second}), server.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	})
+	t.Run("blocked", func(t *testing.T) {
+		setConfig(t, true, nil, nil)
+		res, err := get(NewClient(&Options{Timeout: time.Second}), server.URL)
+		if res != nil {
+			res.Body.Close()
+		}
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not allowed")
+	})
+	t.Run("allowed network", func(t *testing.T) {
+		setConfig(t, true, []string{"127.0.0.0/8"}, nil)
+		res, err := get(NewClient(&Options{Timeout: time.Second}), server.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	})
+	t.Run("configured destination", func(t *testing.T) {
+		setConfig(t, true, nil, nil)
+		res, err := get(NewClient(&Options{Timeout: time.Second, AllowNonPublic: true}), server.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	})
+	t.Run("configured destination in denied network", func(t *testing.T) {
+		setConfig(t, true, nil, []string{"127.0.0.0/8"})
+		res, err := get(NewClient(&Options{Timeout: time.Second, AllowNonPublic: true}), server.URL)
+		if res != nil {
+			res.Body.Close()
+		}
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not allowed")
+	})
+}
@@ -0,0 +1,85 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/api/pkg/modules/batch"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Batch runs multiple operations in a single transaction
+// @Summary Run multiple operations at once
+// @Description Runs multiple create, update and delete operations in a single transaction. Each operation uses the same path and body as the corresponding route of the REST api. Later operations can reference properties of the results of earlier ones with `$<index>.<property>`, for example `/tasks/$0.id/labels`. If any operation fails, none of the changes are saved and the response contains the results up to and including the failed one. Check out the docs for all supported operations: https://vikunja.io/docs/batch-requests
+// @tags batch
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param request body batch.Request true "The operations to run."
+// @Success 200 {object} batch.Response "All operations succeeded."
+// @Failure 400 {object} batch.Response "An operation is invalid or not supported."
+// @Failure 403 {object} batch.Response "The user does not have access to the entity of an operation."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /batch [post]
+func Batch(c echo.Context) error {
+	req := &batch.Request{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No or invalid batch request provided.")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	if err := s.Begin(); err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	// Events are only dispatched once all changes are saved, the ones of rolled back changes are discarded
+	events.DeferUntilCommit(s)
+	defer events.DiscardPending(s)
+
+	res, err := batch.Execute(s, auth, req)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if !res.Success {
+		_ = s.Rollback()
+		return c.JSON(res.Status(), res)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	events.DispatchPending(s)
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,103 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+	"xorm.io/xorm"
+)
+
+func handleChanges(c echo.Context, getChanges func(s *xorm.Session, a web.Auth, cursor string, limit int) (interface{}, error)) error {
+	perPage := config.ServiceMaxItemsPerPage.GetInt()
+	if p := c.QueryParam("per_page"); p != "" {
+		var err error
+		perPage, err = strconv.Atoi(p)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid per_page.")
+		}
+	}
+	if perPage < 1 || perPage > config.ServiceMaxItemsPerPage.GetInt() {
+		perPage = config.ServiceMaxItemsPerPage.GetInt()
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	changes, err := getChanges(s, auth, c.QueryParam("cursor"), perPage)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, changes)
+}
+
+// GetTaskChanges returns all tasks which changed after a cursor
+// @Summary Get changed tasks
+// @Description Returns all tasks the user has access to which were created or updated after the cursor, ordered by when they were changed, and all tasks which were deleted after it. Pass the returned cursor to the next request to only get newer changes. Without a cursor, all tasks are returned. Changes from the current second are only returned by the next request, to make sure no change is missed.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param cursor query string false "The cursor returned by the last request. Leave empty to start from the beginning."
+// @Param per_page query int false "The maximum number of changed and deleted tasks each. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {object} models.TaskChanges "The changed and deleted tasks and the cursor for the next request."
+// @Failure 400 {object} web.HTTPError "Invalid cursor."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /tasks/changes [get]
+func GetTaskChanges(c echo.Context) error {
+	return handleChanges(c, func(s *xorm.Session, a web.Auth, cursor string, limit int) (interface{}, error) {
+		return models.GetTaskChanges(s, a, cursor, limit)
+	})
+}
+
+// GetProjectChanges returns all projects which changed after a cursor
+// @Summary Get changed projects
+// @Description Returns all projects the user has access to which were created or updated after the cursor, ordered by when they were changed, and all projects which were deleted after it. Pass the returned cursor to the next request to only get newer changes. Without a cursor, all projects are returned. Changes from the current second are only returned by the next request, to make sure no change is missed.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param cursor query string false "The cursor returned by the last request. Leave empty to start from the beginning."
+// @Param per_page query int false "The maximum number of changed and deleted projects each. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {object} models.ProjectChanges "The changed and deleted projects and the cursor for the next request."
+// @Failure 400 {object} web.HTTPError "Invalid cursor."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /projects/changes [get]
+func GetProjectChanges(c echo.Context) error {
+	return handleChanges(c, func(s *xorm.Session, a web.Auth, cursor string, limit int) (interface{}, error) {
+		return models.GetProjectChanges(s, a, cursor, limit)
+	})
+}
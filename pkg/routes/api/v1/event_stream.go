@@ -0,0 +1,157 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// How often a comment is sent to keep the connection open when there are no events
+const eventStreamKeepAliveInterval = 30 * time.Second
+
+func writeStreamEvent(w *echo.Response, id, name string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, name, payload)
+	if err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
+
+func getStreamEvents(auth web.Auth, lastEventID string) (streamEvents []*models.StreamEvent, nextID string, err error) {
+	s := db.NewSession()
+	defer s.Close()
+
+	return models.GetStreamEvents(s, auth, lastEventID)
+}
+
+// EventStream streams notifications and changes in subscribed projects
+// @Summary Stream notifications and changes
+// @Description Opens a server-sent events stream with all new notifications of the current user and all changes in projects the user subscribed to. Each event has an id which can be passed as `Last-Event-ID` header or `last_event_id` query parameter when reconnecting to receive all events which happened in the meantime. Without it, only new events are streamed. Check out the docs for all event types: https://vikunja.io/docs/event-stream
+// @tags subscriptions
+// @Produce text/event-stream
+// @Security JWTKeyAuth
+// @Param Last-Event-ID header string false "The id of the last event the client received."
+// @Param last_event_id query string false "The id of the last event the client received. Used if the header is not set."
+// @Success 200 {string} string "The event stream."
+// @Failure 400 {object} web.HTTPError "The event id is invalid."
+// @Failure 403 {object} web.HTTPError "Link shares cannot use the event stream."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /events/stream [get]
+func EventStream(c echo.Context) error {
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+
+	s := db.NewSession()
+	lastEventID, err = models.GetEventStreamStart(s, auth, lastEventID)
+	s.Close()
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	// Prevents proxies like nginx from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	err = writeStreamEvent(w, lastEventID, "connected", map[string]string{"last_event_id": lastEventID})
+	if err != nil {
+		return nil
+	}
+
+	sub, err := models.SubscribeToEventStream(auth)
+	if err != nil {
+		log.Errorf("Could not subscribe to the event stream of user %d: %s", auth.GetID(), err)
+		return nil
+	}
+	defer sub.Close()
+
+	keepAlive := time.NewTicker(eventStreamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	// Sends all events since the last sent one. Returns false when the connection is gone.
+	sendEvents := func() bool {
+		for {
+			streamEvents, nextID, err := getStreamEvents(auth, lastEventID)
+			if err != nil {
+				log.Errorf("Could not get events for the event stream of user %d: %s", auth.GetID(), err)
+				return true
+			}
+
+			for _, e := range streamEvents {
+				if err := writeStreamEvent(w, e.ID, e.Name, e.Data); err != nil {
+					return false
+				}
+			}
+
+			// Events are loaded in batches, there might be more
+			if nextID == lastEventID {
+				return true
+			}
+			lastEventID = nextID
+		}
+	}
+
+	// Events which happened between the start of the stream and the subscription
+	if !sendEvents() {
+		return nil
+	}
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		case <-sub.Wake():
+			if !sendEvents() {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ExplainFilter parses a filter query and returns its structured representation
+// @Summary Explain a filter query
+// @Description Parses a filter query and returns all expressions in it with their values resolved the same way they are when filtering tasks, for example relative dates. If the filter query is invalid, `valid` is false and `error` contains what is wrong and where in the query it is, so clients can validate filters while they are typed.
+// @tags filter
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param filter body models.FilterExplanationRequest true "The filter query to explain."
+// @Success 200 {object} models.FilterExplanation "The explained filter query."
+// @Failure 400 {object} web.HTTPError "The filter time zone does not exist."
+// @Failure 500 {object} models.Message "Internal server error"
+// @Router /filters/explain [post]
+func ExplainFilter(c echo.Context) error {
+	req := &models.FilterExplanationRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No or invalid filter provided.")
+	}
+
+	explanation, err := models.ExplainFilter(req.Filter, req.FilterTimezone)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, explanation)
+}
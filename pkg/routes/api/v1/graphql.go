@@ -0,0 +1,84 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/api/pkg/modules/graphql"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GraphQL runs a GraphQL query or mutation
+// @Summary Run a GraphQL query
+// @Description Runs a GraphQL query or mutation against projects, tasks, buckets, labels, comments and users. All queries use the same permissions as the REST api. If any part of the request fails, no changes are saved. Check out the docs for the schema: https://vikunja.io/docs/graphql
+// @tags graphql
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param query body graphql.Request true "The GraphQL request"
+// @Success 200 {object} map[string]interface{} "The GraphQL response with the data and errors, if any."
+// @Failure 400 {object} web.HTTPError "Invalid request."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /graphql [post]
+func GraphQL(c echo.Context) error {
+	req := &graphql.Request{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No or invalid GraphQL request provided.")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	if err := s.Begin(); err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	// Events are only dispatched once all changes are saved, the ones of rolled back changes are discarded
+	events.DeferUntilCommit(s)
+	defer events.DiscardPending(s)
+
+	result, err := graphql.Execute(s, auth, req)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if result.HasErrors() {
+		_ = s.Rollback()
+		return c.JSON(http.StatusOK, result)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	events.DispatchPending(s)
+
+	return c.JSON(http.StatusOK, result)
+}
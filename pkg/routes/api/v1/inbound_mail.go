@@ -0,0 +1,65 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleInboundMail creates a task from an email sent to the email address of a project
+// @Summary Create a task from an inbound email
+// @Description Creates a task from a raw email in the RFC 5322 format. This endpoint is meant to be called by the mail server receiving emails for the configured inbound mail domain. You need to provide the configured inbound mail secret in the `Authorization: <secret>` header when making requests to this endpoint.
+// @tags task
+// @Accept plain
+// @Produce json
+// @Success 201 {object} models.Task "The created task."
+// @Failure 400 {object} web.HTTPError "The email could not be parsed."
+// @Failure 403 {object} web.HTTPError "The sender is not allowed to create tasks in the project."
+// @Failure 404 {object} web.HTTPError "The email was not sent to the email address of a project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /mail/inbound [post]
+func HandleInboundMail(c echo.Context) error {
+	secret := config.InboundMailSecret.GetString()
+	token := c.Request().Header.Get("Authorization")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return echo.ErrForbidden
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	task, err := models.CreateTaskFromInboundMail(s, c.Request().Body)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusCreated, task)
+}
@@ -0,0 +1,67 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleInboundWebhook creates a task from a json payload posted to an inbound webhook of a project
+// @Summary Create a task with an inbound webhook
+// @Description Creates a task in the project of the inbound webhook. The token in the url authenticates the request, no other authentication is needed. The task is created by the user who created the inbound webhook.
+// @tags task
+// @Accept json
+// @Produce json
+// @Param token path string true "The token of the inbound webhook"
+// @Param payload body models.InboundWebhookPayload true "The task to create"
+// @Success 201 {object} models.InboundWebhookTask "The id and identifier of the created task."
+// @Failure 400 {object} web.HTTPError "Invalid payload provided."
+// @Failure 404 {object} web.HTTPError "The inbound webhook does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /inbound/webhooks/{token} [post]
+func HandleInboundWebhook(c echo.Context) error {
+	payload := &models.InboundWebhookPayload{}
+	if err := c.Bind(payload); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid payload provided.")
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	task, err := models.CreateTaskFromInboundWebhook(s, c.Param("token"), payload)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusCreated, &models.InboundWebhookTask{
+		ID:         task.ID,
+		Identifier: task.Identifier,
+		Index:      task.Index,
+	})
+}
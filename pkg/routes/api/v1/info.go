@@ -52,6 +52,13 @@ type vikunjaInfos struct {
 	DemoModeEnabled            bool      `json:"demo_mode_enabled"`
 	WebhooksEnabled            bool      `json:"webhooks_enabled"`
 	PublicTeamsEnabled         bool      `json:"public_teams_enabled"`
+	WebPushPublicKey           string    `json:"web_push_public_key"`
+	NtfyEnabled                bool      `json:"ntfy_enabled"`
+	GotifyEnabled              bool      `json:"gotify_enabled"`
+	MatrixEnabled              bool      `json:"matrix_enabled"`
+	SlackEnabled               bool      `json:"slack_enabled"`
+	DiscordEnabled             bool      `json:"discord_enabled"`
+	NotificationTargetsEnabled bool      `json:"notification_targets_enabled"`
 }
 
 type authInfo struct {
@@ -82,21 +89,27 @@ type legalInfo struct {
 // @Router /info [get]
 func Info(c echo.Context) error {
 	info := vikunjaInfos{
-		Version:                version.Version,
-		FrontendURL:            config.ServicePublicURL.GetString(),
-		Motd:                   config.ServiceMotd.GetString(),
-		LinkSharingEnabled:     config.ServiceEnableLinkSharing.GetBool(),
-		MaxFileSize:            config.FilesMaxSize.GetString(),
-		RegistrationEnabled:    config.ServiceEnableRegistration.GetBool(),
-		TaskAttachmentsEnabled: config.ServiceEnableTaskAttachments.GetBool(),
-		TotpEnabled:            config.ServiceEnableTotp.GetBool(),
-		CaldavEnabled:          config.ServiceEnableCaldav.GetBool(),
-		EmailRemindersEnabled:  config.ServiceEnableEmailReminders.GetBool(),
-		UserDeletionEnabled:    config.ServiceEnableUserDeletion.GetBool(),
-		TaskCommentsEnabled:    config.ServiceEnableTaskComments.GetBool(),
-		DemoModeEnabled:        config.ServiceDemoMode.GetBool(),
-		WebhooksEnabled:        config.WebhooksEnabled.GetBool(),
-		PublicTeamsEnabled:     config.ServiceEnablePublicTeams.GetBool(),
+		Version:                    version.Version,
+		FrontendURL:                config.ServicePublicURL.GetString(),
+		Motd:                       config.ServiceMotd.GetString(),
+		LinkSharingEnabled:         config.ServiceEnableLinkSharing.GetBool(),
+		MaxFileSize:                config.FilesMaxSize.GetString(),
+		RegistrationEnabled:        config.ServiceEnableRegistration.GetBool(),
+		TaskAttachmentsEnabled:     config.ServiceEnableTaskAttachments.GetBool(),
+		TotpEnabled:                config.ServiceEnableTotp.GetBool(),
+		CaldavEnabled:              config.ServiceEnableCaldav.GetBool(),
+		EmailRemindersEnabled:      config.ServiceEnableEmailReminders.GetBool(),
+		UserDeletionEnabled:        config.ServiceEnableUserDeletion.GetBool(),
+		TaskCommentsEnabled:        config.ServiceEnableTaskComments.GetBool(),
+		DemoModeEnabled:            config.ServiceDemoMode.GetBool(),
+		WebhooksEnabled:            config.WebhooksEnabled.GetBool(),
+		PublicTeamsEnabled:         config.ServiceEnablePublicTeams.GetBool(),
+		NtfyEnabled:                config.NtfyEnabled.GetBool(),
+		GotifyEnabled:              config.GotifyEnabled.GetBool(),
+		MatrixEnabled:              config.MatrixEnabled.GetBool(),
+		SlackEnabled:               config.SlackEnabled.GetBool(),
+		DiscordEnabled:             config.DiscordEnabled.GetBool(),
+		NotificationTargetsEnabled: config.NotificationTargetsEnabled.GetBool(),
 		AvailableMigrators: []string{
 			(&vikunja_file.FileMigrator{}).Name(),
 			(&ticktick.Migrator{}).Name(),
@@ -141,10 +154,17 @@ func Info(c echo.Context) error {
 		if config.BackgroundsUploadEnabled.GetBool() {
 			info.EnabledBackgroundProviders = append(info.EnabledBackgroundProviders, "upload")
 		}
+		if config.BackgroundsURLEnabled.GetBool() {
+			info.EnabledBackgroundProviders = append(info.EnabledBackgroundProviders, "url")
+		}
 		if config.BackgroundsUnsplashEnabled.GetBool() {
 			info.EnabledBackgroundProviders = append(info.EnabledBackgroundProviders, "unsplash")
 		}
 	}
 
+	if config.WebPushEnabled.GetBool() {
+		info.WebPushPublicKey = config.WebPushPublicKey.GetString()
+	}
+
 	return c.JSON(http.StatusOK, info)
 }
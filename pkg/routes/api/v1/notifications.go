@@ -54,3 +54,39 @@ func MarkAllNotificationsAsRead(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, models.Message{Message: "success"})
 }
+
+// DeleteReadNotifications deletes all notifications of a user which are marked as read
+// @Summary Delete all read notifications
+// @Description Deletes all notifications of the current user which were marked as read. Unread notifications are kept.
+// @tags subscriptions
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {object} models.Message "All read notifications deleted."
+// @Failure 403 {object} web.HTTPError "Link shares cannot have notifications."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /notifications/read [delete]
+func DeleteReadNotifications(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	a, err := auth.GetAuthFromClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if _, is := a.(*models.LinkSharing); is {
+		return echo.ErrForbidden
+	}
+
+	_, err = notifications.DeleteReadNotifications(s, a.GetID())
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	if err := s.Commit(); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, models.Message{Message: "success"})
+}
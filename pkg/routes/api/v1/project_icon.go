@@ -0,0 +1,198 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+	"xorm.io/xorm"
+)
+
+// getProjectForIcon checks the rights of the current user and returns the project from the request
+func getProjectForIcon(s *xorm.Session, c echo.Context, write bool) (project *models.Project, auth web.Auth, err error) {
+	auth, err = auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return nil, nil, handler.HandleHTTPError(err, c)
+	}
+
+	projectID, err := strconv.ParseInt(c.Param("project"), 10, 64)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid project ID: "+err.Error())
+	}
+
+	project = &models.Project{ID: projectID}
+	var can bool
+	if write {
+		can, err = project.CanUpdate(s, auth)
+	} else {
+		can, _, err = project.CanRead(s, auth)
+	}
+	if err != nil {
+		return nil, nil, handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		return nil, nil, echo.ErrForbidden
+	}
+
+	project, err = models.GetProjectSimpleByID(s, projectID)
+	if err != nil {
+		return nil, nil, handler.HandleHTTPError(err, c)
+	}
+
+	return
+}
+
+// UploadProjectIcon uploads an image and sets it as the icon of a project
+// @Summary Upload a project icon
+// @Description Uploads a small image and sets it as the icon of the project. This replaces the emoji icon of the project, if it has one.
+// @tags project
+// @Accept mpfd
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param icon formData string true "The image as single file."
+// @Security JWTKeyAuth
+// @Success 200 {object} models.Project "The project with the new icon."
+// @Failure 400 {object} web.HTTPError "The file is not an image or too large."
+// @Failure 403 {object} models.Message "No access to the project."
+// @Failure 404 {object} models.Message "The project does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/icon [put]
+func UploadProjectIcon(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	project, auth, err := getProjectForIcon(s, c, true)
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	file, err := c.FormFile("icon")
+	if err != nil {
+		_ = s.Rollback()
+		return echo.NewHTTPError(http.StatusBadRequest, "No icon provided")
+	}
+	srcf, err := file.Open()
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	defer srcf.Close()
+
+	err = models.SetProjectIconImage(s, project, srcf, file.Filename, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, project)
+}
+
+// GetProjectIcon serves the icon image of a project
+// @Summary Get the project icon
+// @Description Get the icon image of a project. Only projects with an uploaded icon image have one, emoji icons are returned with the project itself. **Returns json on error.**
+// @tags project
+// @Produce octet-stream
+// @Param id path int true "Project ID"
+// @Security JWTKeyAuth
+// @Success 200 {file} blob "The project icon image."
+// @Failure 403 {object} models.Message "No access to this project."
+// @Failure 404 {object} models.Message "The project does not exist or does not have an icon image."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/icon [get]
+func GetProjectIcon(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	project, _, err := getProjectForIcon(s, c, false)
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	if project.IconFileID == 0 {
+		_ = s.Rollback()
+		return echo.NotFoundHandler(c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	iconFile := &files.File{ID: project.IconFileID}
+	if err := iconFile.LoadFileMetaByID(); err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+	if err := iconFile.LoadFileByID(); err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+	defer iconFile.File.Close()
+
+	http.ServeContent(c.Response(), c.Request(), iconFile.Name, iconFile.Created, iconFile.File)
+	return nil
+}
+
+// RemoveProjectIcon removes the icon of a project
+// @Summary Remove a project icon
+// @Description Removes the emoji or image icon of a project. It does not throw an error if the project does not have an icon.
+// @tags project
+// @Produce json
+// @Param id path int true "Project ID"
+// @Security JWTKeyAuth
+// @Success 200 {object} models.Project "The project"
+// @Failure 403 {object} models.Message "No access to this project."
+// @Failure 404 {object} models.Message "The project does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/icon [delete]
+func RemoveProjectIcon(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	project, _, err := getProjectForIcon(s, c, true)
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	err = models.RemoveProjectIcon(s, project)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, project)
+}
@@ -0,0 +1,191 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/web"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Slack sends small form encoded requests, anything bigger is not from Slack
+const slackMaxRequestSize = 1 << 20
+
+type slackActionPayload struct {
+	Type string `json:"type"`
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// readSlackRequest checks the signature of a request from Slack and returns its form values
+func readSlackRequest(c echo.Context) (url.Values, error) {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, slackMaxRequestSize))
+	if err != nil {
+		return nil, err
+	}
+
+	valid := notifications.VerifySlackRequest(
+		config.SlackSigningSecret.GetString(),
+		c.Request().Header.Get("X-Slack-Request-Timestamp"),
+		c.Request().Header.Get("X-Slack-Signature"),
+		body,
+		time.Now(),
+	)
+	if !valid {
+		return nil, echo.ErrForbidden
+	}
+
+	return url.ParseQuery(string(body))
+}
+
+// slackErrorMessage returns the message shown in Slack for an error, only Vikunja errors are shown to the user
+func slackErrorMessage(err error) (*notifications.SlackMessage, bool) {
+	httpErr, is := err.(web.HTTPErrorProcessor)
+	if !is {
+		return nil, false
+	}
+	return &notifications.SlackMessage{ResponseType: "ephemeral", Text: httpErr.HTTPError().Message}, true
+}
+
+// HandleSlackCommand runs a slash command of the Slack app
+// @Summary Run a Slack slash command
+// @Description Creates or completes a task in the project the Slack channel of the command is connected to with a project integration. This endpoint is meant to be configured as the request url of a slash command of your Slack app. All requests need to be signed with the signing secret configured in `slack.signingsecret`.
+// @tags project
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 200 {object} notifications.SlackMessage "The message shown in Slack."
+// @Failure 403 {object} web.HTTPError "The request was not signed by Slack."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /integrations/slack/commands [post]
+func HandleSlackCommand(c echo.Context) error {
+	form, err := readSlackRequest(c)
+	if err != nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	msg, err := models.HandleSlackCommand(s, &models.SlackCommand{
+		ChannelID: form.Get("channel_id"),
+		UserName:  form.Get("user_name"),
+		Text:      form.Get("text"),
+	})
+	if err != nil {
+		_ = s.Rollback()
+		if errMsg, is := slackErrorMessage(err); is {
+			return c.JSON(http.StatusOK, errMsg)
+		}
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, msg)
+}
+
+// HandleSlackActions runs the actions of the buttons of messages of the Slack app
+// @Summary Run a Slack button action
+// @Description Runs the action of a button in a message of the Slack app, like marking a task as done. This endpoint is meant to be configured as the interactivity request url of your Slack app. All requests need to be signed with the signing secret configured in `slack.signingsecret`. The result is posted to the response url of the action.
+// @tags project
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 200 "The action was handled."
+// @Failure 400 {object} web.HTTPError "The payload is invalid."
+// @Failure 403 {object} web.HTTPError "The request was not signed by Slack."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /integrations/slack/actions [post]
+func HandleSlackActions(c echo.Context) error {
+	form, err := readSlackRequest(c)
+	if err != nil {
+		return err
+	}
+
+	payload := &slackActionPayload{}
+	if err := json.Unmarshal([]byte(form.Get("payload")), payload); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid payload.")
+	}
+	if payload.Type != "block_actions" {
+		return c.NoContent(http.StatusOK)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	responses := []*notifications.SlackMessage{}
+	for _, action := range payload.Actions {
+		msg, err := models.HandleSlackAction(s, &models.SlackAction{
+			ChannelID: payload.Channel.ID,
+			UserName:  payload.User.Username,
+			ActionID:  action.ActionID,
+			Value:     action.Value,
+		})
+		if err != nil {
+			errMsg, is := slackErrorMessage(err)
+			if !is {
+				_ = s.Rollback()
+				return handler.HandleHTTPError(err, c)
+			}
+			msg = errMsg
+		}
+		if msg != nil {
+			responses = append(responses, msg)
+		}
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	// Only post to Slack itself, the response url is part of the signed payload but should never point anywhere else
+	if !strings.HasPrefix(payload.ResponseURL, "https://hooks.slack.com/") {
+		return c.NoContent(http.StatusOK)
+	}
+	for _, msg := range responses {
+		if err := notifications.SendSlackMessage(payload.ResponseURL, msg); err != nil {
+			log.Errorf("Could not post the response of a Slack action: %s", err)
+		}
+	}
+
+	return c.NoContent(http.StatusOK)
+}
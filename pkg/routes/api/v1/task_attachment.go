@@ -18,6 +18,7 @@ package v1
 
 import (
 	"net/http"
+	"strconv"
 
 	"code.vikunja.io/api/pkg/db"
 
@@ -35,6 +36,7 @@ import (
 // @Produce json
 // @Param id path int true "Task ID"
 // @Param files formData string true "The file, as multipart form file. You can pass multiple."
+// @Param inline formData bool false "Set to true if the files are images which are embedded in the task description. Identical inline images are only stored once and removed automatically once they are no longer used in any description."
 // @Security JWTKeyAuth
 // @Success 200 {object} models.Message "Attachments were uploaded successfully."
 // @Failure 403 {object} models.Message "No access to the task."
@@ -78,11 +80,13 @@ func UploadTaskAttachment(c echo.Context) error {
 		Success []*models.TaskAttachment `json:"success"`
 	}
 	r := &result{}
+	isInline, _ := strconv.ParseBool(c.FormValue("inline"))
 	fileHeaders := form.File["files"]
 	for _, file := range fileHeaders {
 		// We create a new attachment object here to have a clean start
 		ta := &models.TaskAttachment{
-			TaskID: taskAttachment.TaskID,
+			TaskID:   taskAttachment.TaskID,
+			IsInline: isInline,
 		}
 
 		f, err := file.Open()
@@ -153,6 +157,11 @@ func GetTaskAttachment(c echo.Context) error {
 		return handler.HandleHTTPError(err, c)
 	}
 
+	if taskAttachment.IsLink() {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(models.ErrTaskAttachmentIsLink{TaskID: taskAttachment.TaskID, AttachmentID: taskAttachment.ID}, c)
+	}
+
 	// Open an send the file to the client
 	err = taskAttachment.File.LoadFileByID()
 	if err != nil {
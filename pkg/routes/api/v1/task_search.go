@@ -0,0 +1,97 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SearchTasks returns one page of tasks with the total number of matching tasks and facets
+// @Summary Search tasks with facets
+// @Description Returns one page of tasks like the regular task endpoints, together with the total number of matching tasks and how many of them have which label, project and assignee. Facets are calculated for all matching tasks, not only the ones on the requested page. Use `/projects/{id}/tasks/search` to only search the tasks of one project.
+// @tags task
+// @Accept json
+// @Produce json
+// @Param id path int false "The project ID. Only for `/projects/{id}/tasks/search`."
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param s query string false "Search tasks by their title, description, comments and the text of their attachments."
+// @Param sort_by query string false "The sorting parameter. Takes the same values as the regular task endpoints."
+// @Param order_by query string false "The ordering parameter. Possible values to order by are `asc` or `desc`. Default is `asc`."
+// @Param filter query string false "The filter query to match tasks by. Check out https://vikunja.io/docs/filters for a full explanation of the feature."
+// @Param filter_timezone query string false "The time zone which should be used for date match (statements like "now" resolve to different actual times)"
+// @Param filter_include_nulls query string false "If set to true the result will include filtered fields whose value is set to `null`. Available values are `true` or `false`. Defaults to `false`."
+// @Security JWTKeyAuth
+// @Success 200 {object} models.TaskSearchResult "The tasks, total count and facets."
+// @Failure 400 {object} web.HTTPError "Invalid filter or pagination."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /tasks/search [get]
+func SearchTasks(c echo.Context) error {
+	tc := &models.TaskCollection{}
+	if err := c.Bind(tc); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid search parameters.")
+	}
+
+	var page, perPage int
+	var err error
+	if p := c.QueryParam("page"); p != "" {
+		page, err = strconv.Atoi(p)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid page.")
+		}
+	}
+	if p := c.QueryParam("per_page"); p != "" {
+		perPage, err = strconv.Atoi(p)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid per_page.")
+		}
+	}
+	if perPage < 1 || perPage > config.ServiceMaxItemsPerPage.GetInt() {
+		perPage = config.ServiceMaxItemsPerPage.GetInt()
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	result, err := tc.Search(s, auth, c.QueryParam("s"), page, perPage)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -61,6 +61,25 @@ type UserSettings struct {
 	Timezone string `json:"timezone"`
 	// Additional settings only used by the frontend
 	FrontendSettings interface{} `json:"frontend_settings"`
+	// Via which channels the user gets which notifications. The keys are the names of the notifications, for example
+	// `task.assigned` or `task.comment`. Notifications not set here are sent via email and shown in the app.
+	// If not provided, the current preferences are not changed.
+	NotificationPreferences user2.NotificationPreferences `json:"notification_preferences"`
+	// Where the user gets their notifications on external services like ntfy, Gotify or Matrix.
+	// If not provided, the current targets are not changed.
+	NotificationTargets *user2.NotificationTargets `json:"notification_targets"`
+	// How often the user gets a digest email with their tasks due soon, overdue tasks and the recent activity in the
+	// projects they subscribed to. Can be `daily`, `weekly` or empty to not send a digest.
+	DigestFrequency string `json:"digest_frequency"`
+	// The time when the digest will be sent via email. Weekly digests are sent on the first day of the week.
+	DigestTime string `json:"digest_time" valid:"time"`
+	// The time in the time zone of the user when their quiet hours start. During the quiet hours, notifications are
+	// only shown in the app and sent via all other channels once the quiet hours are over. Empty to disable quiet hours.
+	QuietHoursStart string `json:"quiet_hours_start" valid:"time"`
+	// The time in the time zone of the user when their quiet hours end. Can be before the start to span the night.
+	QuietHoursEnd string `json:"quiet_hours_end" valid:"time"`
+	// If true, task reminders are sent right away even during the quiet hours.
+	QuietHoursExemptUrgent bool `json:"quiet_hours_exempt_urgent"`
 }
 
 // GetUserAvatarProvider returns the currently set user avatar
@@ -201,6 +220,17 @@ func UpdateGeneralUserSettings(c echo.Context) error {
 	user.Timezone = us.Timezone
 	user.OverdueTasksRemindersTime = us.OverdueTasksRemindersTime
 	user.FrontendSettings = us.FrontendSettings
+	user.DigestFrequency = us.DigestFrequency
+	user.DigestTime = us.DigestTime
+	user.QuietHoursStart = us.QuietHoursStart
+	user.QuietHoursEnd = us.QuietHoursEnd
+	user.QuietHoursExemptUrgent = us.QuietHoursExemptUrgent
+	if us.NotificationPreferences != nil {
+		user.NotificationPreferences = us.NotificationPreferences
+	}
+	if us.NotificationTargets != nil {
+		user.NotificationTargets = us.NotificationTargets
+	}
 
 	_, err = user2.UpdateUser(s, user, true)
 	if err != nil {
@@ -63,6 +63,16 @@ func UserShow(c echo.Context) error {
 		return handler.HandleHTTPError(err, c)
 	}
 
+	notificationTargets := u.NotificationTargets
+	if notificationTargets == nil {
+		notificationTargets = &user.NotificationTargets{}
+	}
+
+	digestTime := u.DigestTime
+	if digestTime == "" {
+		digestTime = user.DefaultDigestTime
+	}
+
 	us := &userWithSettings{
 		User: *u,
 		Settings: &UserSettings{
@@ -77,6 +87,13 @@ func UserShow(c echo.Context) error {
 			Timezone:                     u.Timezone,
 			OverdueTasksRemindersTime:    u.OverdueTasksRemindersTime,
 			FrontendSettings:             u.FrontendSettings,
+			NotificationPreferences:      u.NotificationPreferences.WithDefaults(),
+			NotificationTargets:          notificationTargets,
+			DigestFrequency:              u.DigestFrequency,
+			DigestTime:                   digestTime,
+			QuietHoursStart:              u.QuietHoursStart,
+			QuietHoursEnd:                u.QuietHoursEnd,
+			QuietHoursExemptUrgent:       u.QuietHoursExemptUrgent,
 		},
 		DeletionScheduledAt: u.DeletionScheduledAt,
 		IsLocalUser:         u.Issuer == user.IssuerLocal,
@@ -269,6 +269,7 @@ func (vcls *VikunjaCaldavProjectStorage) CreateResource(rpath, content string) (
 	}
 
 	vTask.ProjectID = vcls.project.ID
+	vTask.Source = models.TaskSourceCalDAV
 
 	// Check the rights
 	canCreate, err := vTask.CanCreate(s, vcls.user)
@@ -0,0 +1,141 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package routes
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// conditionalResponseBuffer holds back the response until the etag of it is known
+type conditionalResponseBuffer struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *conditionalResponseBuffer) WriteHeader(code int) {
+	b.status = code
+}
+
+func (b *conditionalResponseBuffer) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// etagMatches checks if the etag is one of the etags in an If-None-Match header
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// getLastModifiedFromBody returns the updated timestamp of a json object
+func getLastModifiedFromBody(body []byte) (lastModified time.Time, has bool) {
+	if len(body) == 0 || body[0] != '{' {
+		return
+	}
+
+	entity := struct {
+		Updated time.Time `json:"updated"`
+	}{}
+	if err := json.Unmarshal(body, &entity); err != nil || entity.Updated.IsZero() {
+		return
+	}
+	return entity.Updated, true
+}
+
+// ConditionalRequest adds an ETag and, for single entities, a Last-Modified header to successful responses.
+// Reads with an If-None-Match header which matches the etag return 304 Not Modified.
+func ConditionalRequest() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			res := c.Response()
+			original := res.Writer
+			buffer := &conditionalResponseBuffer{ResponseWriter: original, status: http.StatusOK}
+			res.Writer = buffer
+
+			err := next(c)
+			res.Writer = original
+			// Nothing was written yet, the error handler writes the response
+			if err != nil && buffer.body.Len() == 0 {
+				return err
+			}
+
+			body := buffer.body.Bytes()
+			if buffer.status == http.StatusOK {
+				hash := sha256.Sum256(body)
+				etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+				res.Header().Set("ETag", etag)
+				if lastModified, has := getLastModifiedFromBody(body); has {
+					res.Header().Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+				}
+
+				if req.Method == http.MethodGet && etagMatches(req.Header.Get("If-None-Match"), etag) {
+					res.Header().Del(echo.HeaderContentType)
+					original.WriteHeader(http.StatusNotModified)
+					return err
+				}
+			}
+
+			original.WriteHeader(buffer.status)
+			_, writeErr := original.Write(body)
+			if err != nil {
+				return err
+			}
+			return writeErr
+		}
+	}
+}
+
+// conditionalBinder binds requests like the default binder of echo. For updates, it additionally passes the time
+// from the If-Unmodified-Since header to the entities which support conditional updates. These then check it
+// in their update, after the rights check and in the same transaction.
+type conditionalBinder struct {
+	echo.DefaultBinder
+}
+
+func (b *conditionalBinder) Bind(i interface{}, c echo.Context) error {
+	if err := b.DefaultBinder.Bind(i, c); err != nil {
+		return err
+	}
+
+	setter, is := i.(models.UnmodifiedSinceSetter)
+	if !is || c.Request().Method != http.MethodPost {
+		return nil
+	}
+
+	since, err := http.ParseTime(c.Request().Header.Get("If-Unmodified-Since"))
+	if err == nil {
+		setter.SetUnmodifiedSince(since)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package routes
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// The responses of these routes contain secrets like tokens or totp keys. They must never be stored in the
+// database, therefore requests to them are not idempotent.
+var idempotencyExcludedRoutes = map[string]bool{
+	"/api/v1/user/password":                       true,
+	"/api/v1/user/token":                          true,
+	"/api/v1/user/export/download":                true,
+	"/api/v1/user/settings/token/caldav":          true,
+	"/api/v1/user/settings/totp/enroll":           true,
+	"/api/v1/user/settings/totp/enable":           true,
+	"/api/v1/user/settings/totp/disable":          true,
+	"/api/v1/tokens":                              true,
+	"/api/v1/projects/:project/shares":            true,
+	"/api/v1/projects/:project/webhooks":          true,
+	"/api/v1/projects/:project/webhooks/:webhook": true,
+	"/api/v1/projects/:project/inboundwebhooks":   true,
+}
+
+// idempotencyResponseRecorder keeps a copy of everything written to the response
+type idempotencyResponseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *idempotencyResponseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func getIdempotencyKeyOwner(c echo.Context) (string, error) {
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return "", err
+	}
+
+	if _, is := auth.(*models.LinkSharing); is {
+		return "link_share_" + strconv.FormatInt(auth.GetID(), 10), nil
+	}
+	return "user_" + strconv.FormatInt(auth.GetID(), 10), nil
+}
+
+func startIdempotentRequest(key *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	s := db.NewSession()
+	defer s.Close()
+
+	existing, err := models.StartIdempotentRequest(s, key)
+	if err != nil {
+		_ = s.Rollback()
+		return nil, err
+	}
+
+	return existing, s.Commit()
+}
+
+func finishIdempotentRequest(key *models.IdempotencyKey, success bool) {
+	s := db.NewSession()
+	defer s.Close()
+
+	var err error
+	if success {
+		err = models.CompleteIdempotentRequest(s, key)
+	} else {
+		err = models.AbortIdempotentRequest(s, key)
+	}
+	if err != nil {
+		_ = s.Rollback()
+		log.Errorf("Could not save the response for idempotency key %s of %s: %s", key.ClientKey, key.Owner, err)
+		return
+	}
+
+	if err := s.Commit(); err != nil {
+		log.Errorf("Could not save the response for idempotency key %s of %s: %s", key.ClientKey, key.Owner, err)
+	}
+}
+
+// Idempotency is a middleware which stores the response of POST and PUT requests with an `Idempotency-Key` header
+// and returns the stored response again when a request with the same key is retried. Routes whose responses contain
+// secrets are excluded.
+func Idempotency() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			clientKey := req.Header.Get(idempotencyKeyHeader)
+			if clientKey == "" || (req.Method != http.MethodPost && req.Method != http.MethodPut) ||
+				idempotencyExcludedRoutes[c.Path()] {
+				return next(c)
+			}
+
+			owner, err := getIdempotencyKeyOwner(c)
+			if err != nil {
+				return handler.HandleHTTPError(err, c)
+			}
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			hash := sha256.Sum256(body)
+
+			key := &models.IdempotencyKey{
+				ClientKey:   clientKey,
+				Owner:       owner,
+				Method:      req.Method,
+				Path:        req.URL.RequestURI(),
+				RequestHash: hex.EncodeToString(hash[:]),
+			}
+
+			existing, err := startIdempotentRequest(key)
+			if err != nil {
+				return handler.HandleHTTPError(err, c)
+			}
+
+			if existing != nil {
+				c.Response().Header().Set("Idempotent-Replayed", "true")
+				return c.Blob(existing.ResponseStatus, existing.ResponseContentType, existing.ResponseBody)
+			}
+
+			recorder := &idempotencyResponseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			err = next(c)
+
+			status := c.Response().Status
+			// Only successful responses are stored, failed requests can be retried with the same key
+			if err != nil || status < 200 || status >= 300 {
+				finishIdempotentRequest(key, false)
+				return err
+			}
+
+			key.ResponseStatus = status
+			key.ResponseContentType = c.Response().Header().Get(echo.HeaderContentType)
+			key.ResponseBody = recorder.body.Bytes()
+			finishIdempotentRequest(key, true)
+			return nil
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
 	auth2 "code.vikunja.io/api/pkg/modules/auth"
 	"code.vikunja.io/api/pkg/red"
 	"github.com/labstack/echo/v4"
@@ -48,7 +49,14 @@ func RateLimit(rateLimiter *limiter.Limiter, rateLimitKind string) echo.Middlewa
 			default:
 				log.Errorf("Unknown rate limit kind configured: %s", rateLimitKind)
 			}
-			limiterCtx, err := rateLimiter.Get(c.Request().Context(), rateLimitKey)
+			// Requests with an api token are limited per token instead
+			rate := rateLimiter.Rate
+			if token, is := c.Get("api_token").(*models.APIToken); is {
+				rateLimitKey = "token_" + strconv.FormatInt(token.ID, 10)
+				rate = getAPITokenRate(token, rate)
+			}
+
+			limiterCtx, err := rateLimiter.Store.Get(c.Request().Context(), rateLimitKey, rate)
 			if err != nil {
 				log.Errorf("IPRateLimit - rateLimiter.Get - err: %v, %s on %s", err, rateLimitKey, c.Request().URL)
 				return c.JSON(http.StatusInternalServerError, echo.Map{
@@ -61,7 +69,17 @@ func RateLimit(rateLimiter *limiter.Limiter, rateLimitKind string) echo.Middlewa
 			h.Set("X-RateLimit-Remaining", strconv.FormatInt(limiterCtx.Remaining, 10))
 			h.Set("X-RateLimit-Reset", strconv.FormatInt(limiterCtx.Reset, 10))
 
+			// The standard headers contain the seconds until the limit resets instead of a timestamp
+			resetIn := limiterCtx.Reset - time.Now().Unix()
+			if resetIn < 0 {
+				resetIn = 0
+			}
+			h.Set("RateLimit-Limit", strconv.FormatInt(limiterCtx.Limit, 10))
+			h.Set("RateLimit-Remaining", strconv.FormatInt(limiterCtx.Remaining, 10))
+			h.Set("RateLimit-Reset", strconv.FormatInt(resetIn, 10))
+
 			if limiterCtx.Reached {
+				h.Set("Retry-After", strconv.FormatInt(resetIn, 10))
 				log.Infof("Too Many Requests from %s on %s", rateLimitKey, c.Request().URL)
 				return c.JSON(http.StatusTooManyRequests, echo.Map{
 					"message": "Too Many Requests on " + c.Request().URL.String(),
@@ -74,6 +92,18 @@ func RateLimit(rateLimiter *limiter.Limiter, rateLimitKind string) echo.Middlewa
 	}
 }
 
+// getAPITokenRate returns the rate limit for an api token. The limit configured for the token itself takes
+// precedence over the one configured for all tokens, which again takes precedence over the general limit.
+func getAPITokenRate(token *models.APIToken, rate limiter.Rate) limiter.Rate {
+	if limit := config.RateLimitAPITokenLimit.GetInt64(); limit > 0 {
+		rate.Limit = limit
+	}
+	if token.RateLimit > 0 {
+		rate.Limit = token.RateLimit
+	}
+	return rate
+}
+
 func createRateLimiter(rate limiter.Rate) *limiter.Limiter {
 	var store limiter.Store
 	var err error
@@ -53,6 +53,7 @@ package routes
 
 import (
 	"errors"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
@@ -67,6 +68,8 @@ import (
 	backgroundHandler "code.vikunja.io/api/pkg/modules/background/handler"
 	"code.vikunja.io/api/pkg/modules/background/unsplash"
 	"code.vikunja.io/api/pkg/modules/background/upload"
+	backgroundURL "code.vikunja.io/api/pkg/modules/background/url"
+	"code.vikunja.io/api/pkg/modules/batch"
 	"code.vikunja.io/api/pkg/modules/migration"
 	migrationHandler "code.vikunja.io/api/pkg/modules/migration/handler"
 	microsofttodo "code.vikunja.io/api/pkg/modules/migration/microsoft-todo"
@@ -118,6 +121,7 @@ func NewEcho() *echo.Echo {
 
 	// Validation
 	e.Validator = &CustomValidator{}
+	e.Binder = &conditionalBinder{}
 
 	// Handler config
 	handler.SetAuthProvider(&web.Auths{
@@ -280,12 +284,32 @@ func registerAPIRoutes(a *echo.Group) {
 		ur.POST("/shares/:share/auth", apiv1.AuthenticateLinkShare)
 	}
 
+	// Inbound emails
+	if config.InboundMailEnabled.GetBool() {
+		n.POST("/mail/inbound", apiv1.HandleInboundMail)
+	}
+
+	// Inbound webhooks
+	if config.WebhooksInboundEnabled.GetBool() {
+		n.POST("/inbound/webhooks/:token", apiv1.HandleInboundWebhook)
+	}
+
+	// Slack app
+	if config.SlackEnabled.GetBool() {
+		n.POST("/integrations/slack/commands", apiv1.HandleSlackCommand)
+		n.POST("/integrations/slack/actions", apiv1.HandleSlackActions)
+	}
+
 	// ===== Routes with Authentication =====
 	a.Use(SetupTokenMiddleware())
 
 	// Rate limit
 	setupRateLimit(a, config.RateLimitKind.GetString())
 
+	if config.IdempotencyEnabled.GetBool() {
+		a.Use(Idempotency())
+	}
+
 	// Middleware to collect metrics
 	setupMetricsMiddleware(a)
 
@@ -331,12 +355,42 @@ func registerAPIRoutes(a *echo.Group) {
 			return &models.Project{}
 		},
 	}
-	a.GET("/projects", projectHandler.ReadAllWeb)
-	a.GET("/projects/:project", projectHandler.ReadOneWeb)
-	a.POST("/projects/:project", projectHandler.UpdateWeb)
+	a.GET("/projects", projectHandler.ReadAllWeb, ConditionalRequest())
+	a.GET("/projects/:project", projectHandler.ReadOneWeb, ConditionalRequest())
+	a.POST("/projects/:project", projectHandler.UpdateWeb, ConditionalRequest())
 	a.DELETE("/projects/:project", projectHandler.DeleteWeb)
 	a.PUT("/projects", projectHandler.CreateWeb)
 	a.GET("/projects/:project/projectusers", apiv1.ListUsersForProject)
+	a.GET("/projects/:project/icon", apiv1.GetProjectIcon)
+	a.PUT("/projects/:project/icon", apiv1.UploadProjectIcon)
+	a.DELETE("/projects/:project/icon", apiv1.RemoveProjectIcon)
+
+	projectUserPositionHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectUserPosition{}
+		},
+	}
+	a.POST("/projects/:project/position/:kind", projectUserPositionHandler.UpdateWeb)
+	a.DELETE("/projects/:project/position/:kind", projectUserPositionHandler.DeleteWeb)
+
+	projectMoveHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectMove{}
+		},
+	}
+	a.POST("/projects/:project/move", projectMoveHandler.UpdateWeb)
+
+	if config.InboundMailEnabled.GetBool() {
+		projectEmailAddressHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.ProjectEmailAddress{}
+			},
+		}
+		a.PUT("/projects/:project/email", projectEmailAddressHandler.CreateWeb)
+		a.GET("/projects/:project/email", projectEmailAddressHandler.ReadOneWeb)
+		a.POST("/projects/:project/email", projectEmailAddressHandler.UpdateWeb)
+		a.DELETE("/projects/:project/email", projectEmailAddressHandler.DeleteWeb)
+	}
 
 	if config.ServiceEnableLinkSharing.GetBool() {
 		projectSharingHandler := &handler.WebHandler{
@@ -355,18 +409,194 @@ func registerAPIRoutes(a *echo.Group) {
 			return &models.TaskCollection{}
 		},
 	}
-	a.GET("/projects/:project/tasks", taskCollectionHandler.ReadAllWeb)
+	a.GET("/projects/:project/tasks", taskCollectionHandler.ReadAllWeb, ConditionalRequest())
 
 	kanbanBucketHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.Bucket{}
 		},
 	}
-	a.GET("/projects/:project/buckets", kanbanBucketHandler.ReadAllWeb)
+	bucketOrderHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.BucketOrder{}
+		},
+	}
+	a.GET("/projects/:project/buckets", kanbanBucketHandler.ReadAllWeb, ConditionalRequest())
 	a.PUT("/projects/:project/buckets", kanbanBucketHandler.CreateWeb)
-	a.POST("/projects/:project/buckets/:bucket", kanbanBucketHandler.UpdateWeb)
+	a.POST("/projects/:project/buckets/:bucket", kanbanBucketHandler.UpdateWeb, ConditionalRequest())
+	a.POST("/projects/:project/buckets/order", bucketOrderHandler.UpdateWeb)
 	a.DELETE("/projects/:project/buckets/:bucket", kanbanBucketHandler.DeleteWeb)
 
+	bucketCollapsedStateHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.BucketCollapsedState{}
+		},
+	}
+	a.POST("/projects/:project/buckets/:bucket/collapsed", bucketCollapsedStateHandler.UpdateWeb)
+
+	bucketTaskMoveHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.BucketTaskMove{}
+		},
+	}
+	a.POST("/projects/:project/buckets/:bucket/move", bucketTaskMoveHandler.UpdateWeb)
+
+	projectKanbanAnalyticsHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectKanbanAnalytics{}
+		},
+	}
+	a.GET("/projects/:project/analytics/kanban", projectKanbanAnalyticsHandler.ReadOneWeb)
+
+	projectStatsHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectStats{}
+		},
+	}
+	a.GET("/projects/:project/stats", projectStatsHandler.ReadOneWeb)
+
+	projectWorkloadHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectWorkload{}
+		},
+	}
+	a.GET("/projects/:project/workload", projectWorkloadHandler.ReadOneWeb)
+
+	projectDependencyGraphHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectDependencyGraph{}
+		},
+	}
+	a.GET("/projects/:project/dependencies", projectDependencyGraphHandler.ReadOneWeb)
+
+	projectScheduleHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectSchedule{}
+		},
+	}
+	a.GET("/projects/:project/schedule", projectScheduleHandler.ReadOneWeb)
+	a.PUT("/projects/:project/schedule", projectScheduleHandler.CreateWeb)
+
+	projectRelationGraphHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectRelationGraph{}
+		},
+	}
+	a.GET("/projects/:project/relations", projectRelationGraphHandler.ReadOneWeb)
+
+	customFieldHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.CustomField{}
+		},
+	}
+	a.GET("/projects/:project/customfields", customFieldHandler.ReadAllWeb)
+	a.PUT("/projects/:project/customfields", customFieldHandler.CreateWeb)
+	a.GET("/projects/:project/customfields/:customfield", customFieldHandler.ReadOneWeb)
+	a.POST("/projects/:project/customfields/:customfield", customFieldHandler.UpdateWeb)
+	a.DELETE("/projects/:project/customfields/:customfield", customFieldHandler.DeleteWeb)
+
+	projectStatusHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectStatus{}
+		},
+	}
+	a.GET("/projects/:project/statuses", projectStatusHandler.ReadAllWeb)
+	a.PUT("/projects/:project/statuses", projectStatusHandler.CreateWeb)
+	a.GET("/projects/:project/statuses/:status", projectStatusHandler.ReadOneWeb)
+	a.POST("/projects/:project/statuses/:status", projectStatusHandler.UpdateWeb)
+	a.DELETE("/projects/:project/statuses/:status", projectStatusHandler.DeleteWeb)
+
+	projectLabelRuleHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectLabelRule{}
+		},
+	}
+	a.GET("/projects/:project/label_rules", projectLabelRuleHandler.ReadAllWeb)
+	a.PUT("/projects/:project/label_rules", projectLabelRuleHandler.CreateWeb)
+	a.GET("/projects/:project/label_rules/:labelrule", projectLabelRuleHandler.ReadOneWeb)
+	a.POST("/projects/:project/label_rules/:labelrule", projectLabelRuleHandler.UpdateWeb)
+	a.DELETE("/projects/:project/label_rules/:labelrule", projectLabelRuleHandler.DeleteWeb)
+
+	projectAutomationRuleHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectAutomationRule{}
+		},
+	}
+	a.GET("/projects/:project/automations", projectAutomationRuleHandler.ReadAllWeb)
+	a.PUT("/projects/:project/automations", projectAutomationRuleHandler.CreateWeb)
+	a.GET("/projects/:project/automations/:automationrule", projectAutomationRuleHandler.ReadOneWeb)
+	a.POST("/projects/:project/automations/:automationrule", projectAutomationRuleHandler.UpdateWeb)
+	a.DELETE("/projects/:project/automations/:automationrule", projectAutomationRuleHandler.DeleteWeb)
+
+	projectScheduledAutomationHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectScheduledAutomation{}
+		},
+	}
+	a.GET("/projects/:project/scheduled_automations", projectScheduledAutomationHandler.ReadAllWeb)
+	a.PUT("/projects/:project/scheduled_automations", projectScheduledAutomationHandler.CreateWeb)
+	a.GET("/projects/:project/scheduled_automations/:scheduledautomation", projectScheduledAutomationHandler.ReadOneWeb)
+	a.POST("/projects/:project/scheduled_automations/:scheduledautomation", projectScheduledAutomationHandler.UpdateWeb)
+	a.DELETE("/projects/:project/scheduled_automations/:scheduledautomation", projectScheduledAutomationHandler.DeleteWeb)
+
+	projectIntegrationHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectIntegration{}
+		},
+	}
+	a.GET("/projects/:project/integrations", projectIntegrationHandler.ReadAllWeb)
+	a.PUT("/projects/:project/integrations", projectIntegrationHandler.CreateWeb)
+	a.GET("/projects/:project/integrations/:integration", projectIntegrationHandler.ReadOneWeb)
+	a.POST("/projects/:project/integrations/:integration", projectIntegrationHandler.UpdateWeb)
+	a.DELETE("/projects/:project/integrations/:integration", projectIntegrationHandler.DeleteWeb)
+
+	milestoneHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.Milestone{}
+		},
+	}
+	a.GET("/projects/:project/milestones", milestoneHandler.ReadAllWeb)
+	a.PUT("/projects/:project/milestones", milestoneHandler.CreateWeb)
+	a.GET("/projects/:project/milestones/:milestone", milestoneHandler.ReadOneWeb)
+	a.POST("/projects/:project/milestones/:milestone", milestoneHandler.UpdateWeb)
+	a.DELETE("/projects/:project/milestones/:milestone", milestoneHandler.DeleteWeb)
+
+	bucketTemplateHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.BucketTemplate{}
+		},
+	}
+	a.GET("/buckettemplates", bucketTemplateHandler.ReadAllWeb)
+	a.GET("/buckettemplates/:buckettemplate", bucketTemplateHandler.ReadOneWeb)
+	a.PUT("/buckettemplates", bucketTemplateHandler.CreateWeb)
+	a.POST("/buckettemplates/:buckettemplate", bucketTemplateHandler.UpdateWeb)
+	a.DELETE("/buckettemplates/:buckettemplate", bucketTemplateHandler.DeleteWeb)
+
+	projectBucketTemplateHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectBucketTemplate{}
+		},
+	}
+	a.PUT("/projects/:project/buckettemplate", projectBucketTemplateHandler.CreateWeb)
+
+	projectTemplateHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectTemplate{}
+		},
+	}
+	a.GET("/projecttemplates", projectTemplateHandler.ReadAllWeb)
+	a.GET("/projecttemplates/:projecttemplate", projectTemplateHandler.ReadOneWeb)
+	a.PUT("/projecttemplates", projectTemplateHandler.CreateWeb)
+	a.POST("/projecttemplates/:projecttemplate", projectTemplateHandler.UpdateWeb)
+	a.DELETE("/projecttemplates/:projecttemplate", projectTemplateHandler.DeleteWeb)
+
+	projectSaveAsTemplateHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectSaveAsTemplate{}
+		},
+	}
+	a.PUT("/projects/:project/projecttemplate", projectSaveAsTemplateHandler.CreateWeb)
+
 	projectDuplicateHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.ProjectDuplicate{}
@@ -380,10 +610,14 @@ func registerAPIRoutes(a *echo.Group) {
 		},
 	}
 	a.PUT("/projects/:project/tasks", taskHandler.CreateWeb)
-	a.GET("/tasks/:projecttask", taskHandler.ReadOneWeb)
-	a.GET("/tasks/all", taskCollectionHandler.ReadAllWeb)
+	a.GET("/tasks/:projecttask", taskHandler.ReadOneWeb, ConditionalRequest())
+	a.GET("/tasks/all", taskCollectionHandler.ReadAllWeb, ConditionalRequest())
+	a.GET("/tasks/search", apiv1.SearchTasks)
+	a.GET("/projects/:project/tasks/search", apiv1.SearchTasks)
+	a.GET("/tasks/changes", apiv1.GetTaskChanges)
+	a.GET("/projects/changes", apiv1.GetProjectChanges)
 	a.DELETE("/tasks/:projecttask", taskHandler.DeleteWeb)
-	a.POST("/tasks/:projecttask", taskHandler.UpdateWeb)
+	a.POST("/tasks/:projecttask", taskHandler.UpdateWeb, ConditionalRequest())
 
 	bulkTaskHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
@@ -392,6 +626,62 @@ func registerAPIRoutes(a *echo.Group) {
 	}
 	a.POST("/tasks/bulk", bulkTaskHandler.UpdateWeb)
 
+	scheduleConflictsHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ScheduleConflicts{}
+		},
+	}
+	a.GET("/tasks/schedule/conflicts", scheduleConflictsHandler.ReadOneWeb)
+
+	quickAddMagicHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.QuickAddMagic{}
+		},
+	}
+	a.GET("/tasks/quickadd", quickAddMagicHandler.ReadOneWeb)
+
+	recentlyViewedTasksHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskView{}
+		},
+	}
+	a.GET("/tasks/recently_viewed", recentlyViewedTasksHandler.ReadAllWeb)
+
+	bulkTaskFilterUpdateHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.BulkTaskFilterUpdate{}
+		},
+	}
+	a.POST("/projects/:project/tasks/bulk", bulkTaskFilterUpdateHandler.UpdateWeb)
+
+	taskHistoryHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskHistoryEntry{}
+		},
+	}
+	a.GET("/tasks/:task/history", taskHistoryHandler.ReadAllWeb)
+
+	taskMergeHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskMerge{}
+		},
+	}
+	a.POST("/tasks/:task/merge", taskMergeHandler.UpdateWeb)
+
+	taskDuplicateHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskDuplicateCandidate{}
+		},
+	}
+	a.GET("/projects/:project/tasks/duplicates", taskDuplicateHandler.ReadAllWeb)
+
+	taskParentHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskParent{}
+		},
+	}
+	a.POST("/tasks/:projecttask/parent", taskParentHandler.UpdateWeb)
+
 	assigneeTaskHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.TaskAssginee{}
@@ -408,6 +698,24 @@ func registerAPIRoutes(a *echo.Group) {
 	}
 	a.POST("/tasks/:projecttask/assignees/bulk", bulkAssigneeHandler.CreateWeb)
 
+	taskWatcherHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskWatcher{}
+		},
+	}
+	a.PUT("/tasks/:task/watchers", taskWatcherHandler.CreateWeb)
+	a.DELETE("/tasks/:task/watchers/:user", taskWatcherHandler.DeleteWeb)
+	a.GET("/tasks/:task/watchers", taskWatcherHandler.ReadAllWeb)
+
+	taskVoteHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskVote{}
+		},
+	}
+	a.PUT("/tasks/:task/votes", taskVoteHandler.CreateWeb)
+	a.DELETE("/tasks/:task/votes", taskVoteHandler.DeleteWeb)
+	a.GET("/tasks/:task/votes", taskVoteHandler.ReadAllWeb)
+
 	labelTaskHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.LabelTask{}
@@ -424,6 +732,56 @@ func registerAPIRoutes(a *echo.Group) {
 	}
 	a.POST("/tasks/:projecttask/labels/bulk", bulkLabelTaskHandler.CreateWeb)
 
+	checklistItemHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ChecklistItem{}
+		},
+	}
+	a.GET("/tasks/:task/checklist", checklistItemHandler.ReadAllWeb)
+	a.PUT("/tasks/:task/checklist", checklistItemHandler.CreateWeb)
+	a.POST("/tasks/:task/checklist/:checklistitem", checklistItemHandler.UpdateWeb)
+	a.DELETE("/tasks/:task/checklist/:checklistitem", checklistItemHandler.DeleteWeb)
+
+	reminderSnoozeHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskReminderSnooze{}
+		},
+	}
+	a.POST("/tasks/:task/reminders/snooze", reminderSnoozeHandler.UpdateWeb)
+
+	timeEntryHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TimeEntry{}
+		},
+	}
+	a.GET("/tasks/:task/time", timeEntryHandler.ReadAllWeb)
+	a.PUT("/tasks/:task/time", timeEntryHandler.CreateWeb)
+	a.POST("/tasks/:task/time/:timeentry", timeEntryHandler.UpdateWeb)
+	a.DELETE("/tasks/:task/time/:timeentry", timeEntryHandler.DeleteWeb)
+
+	timeEntryStartHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TimeEntryStart{}
+		},
+	}
+	a.POST("/tasks/:task/time/start", timeEntryStartHandler.UpdateWeb)
+
+	timeEntryStopHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TimeEntryStop{}
+		},
+	}
+	a.POST("/tasks/:task/time/stop", timeEntryStopHandler.UpdateWeb)
+
+	timeEntrySummaryHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TimeEntrySummary{}
+		},
+	}
+	a.GET("/tasks/:task/time/summary", timeEntrySummaryHandler.ReadOneWeb)
+	a.GET("/projects/:project/time/summary", timeEntrySummaryHandler.ReadOneWeb)
+	a.GET("/user/time/summary", timeEntrySummaryHandler.ReadOneWeb)
+
 	taskRelationHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.TaskRelation{}
@@ -432,6 +790,22 @@ func registerAPIRoutes(a *echo.Group) {
 	a.PUT("/tasks/:task/relations", taskRelationHandler.CreateWeb)
 	a.DELETE("/tasks/:task/relations/:relationKind/:otherTask", taskRelationHandler.DeleteWeb)
 
+	taskPinHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskPin{}
+		},
+	}
+	a.POST("/tasks/:task/pin", taskPinHandler.UpdateWeb)
+	a.DELETE("/tasks/:task/pin", taskPinHandler.DeleteWeb)
+
+	taskUserPositionHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskUserPosition{}
+		},
+	}
+	a.POST("/tasks/:task/position/user", taskUserPositionHandler.UpdateWeb)
+	a.DELETE("/tasks/:task/position/user", taskUserPositionHandler.DeleteWeb)
+
 	if config.ServiceEnableTaskAttachments.GetBool() {
 		taskAttachmentHandler := &handler.WebHandler{
 			EmptyStruct: func() handler.CObject {
@@ -441,7 +815,15 @@ func registerAPIRoutes(a *echo.Group) {
 		a.GET("/tasks/:task/attachments", taskAttachmentHandler.ReadAllWeb)
 		a.DELETE("/tasks/:task/attachments/:attachment", taskAttachmentHandler.DeleteWeb)
 		a.PUT("/tasks/:task/attachments", apiv1.UploadTaskAttachment)
+		a.PUT("/tasks/:task/attachments/link", taskAttachmentHandler.CreateWeb)
 		a.GET("/tasks/:task/attachments/:attachment", apiv1.GetTaskAttachment)
+
+		taskCoverHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskCoverImageFromURL{}
+			},
+		}
+		a.POST("/tasks/:task/cover", taskCoverHandler.UpdateWeb)
 	}
 
 	if config.ServiceEnableTaskComments.GetBool() {
@@ -455,6 +837,13 @@ func registerAPIRoutes(a *echo.Group) {
 		a.DELETE("/tasks/:task/comments/:commentid", taskCommentHandler.DeleteWeb)
 		a.POST("/tasks/:task/comments/:commentid", taskCommentHandler.UpdateWeb)
 		a.GET("/tasks/:task/comments/:commentid", taskCommentHandler.ReadOneWeb)
+
+		taskCommentRevisionHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskCommentRevision{}
+			},
+		}
+		a.GET("/tasks/:task/comments/:commentid/revisions", taskCommentRevisionHandler.ReadAllWeb)
 	}
 
 	labelHandler := &handler.WebHandler{
@@ -488,6 +877,17 @@ func registerAPIRoutes(a *echo.Group) {
 	a.DELETE("/projects/:project/users/:user", projectUserHandler.DeleteWeb)
 	a.POST("/projects/:project/users/:user", projectUserHandler.UpdateWeb)
 
+	projectRoleHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectRole{}
+		},
+	}
+	a.GET("/projects/:project/roles", projectRoleHandler.ReadAllWeb)
+	a.PUT("/projects/:project/roles", projectRoleHandler.CreateWeb)
+	a.GET("/projects/:project/roles/:role", projectRoleHandler.ReadOneWeb)
+	a.POST("/projects/:project/roles/:role", projectRoleHandler.UpdateWeb)
+	a.DELETE("/projects/:project/roles/:role", projectRoleHandler.DeleteWeb)
+
 	savedFiltersHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.SavedFilter{}
@@ -497,6 +897,27 @@ func registerAPIRoutes(a *echo.Group) {
 	a.PUT("/filters", savedFiltersHandler.CreateWeb)
 	a.DELETE("/filters/:filter", savedFiltersHandler.DeleteWeb)
 	a.POST("/filters/:filter", savedFiltersHandler.UpdateWeb)
+	a.POST("/filters/explain", apiv1.ExplainFilter)
+
+	savedFilterUserHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.SavedFilterUser{}
+		},
+	}
+	a.GET("/filters/:filter/users", savedFilterUserHandler.ReadAllWeb)
+	a.PUT("/filters/:filter/users", savedFilterUserHandler.CreateWeb)
+	a.POST("/filters/:filter/users/:user", savedFilterUserHandler.UpdateWeb)
+	a.DELETE("/filters/:filter/users/:user", savedFilterUserHandler.DeleteWeb)
+
+	savedFilterTeamHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.SavedFilterTeam{}
+		},
+	}
+	a.GET("/filters/:filter/teams", savedFilterTeamHandler.ReadAllWeb)
+	a.PUT("/filters/:filter/teams", savedFilterTeamHandler.CreateWeb)
+	a.POST("/filters/:filter/teams/:team", savedFilterTeamHandler.UpdateWeb)
+	a.DELETE("/filters/:filter/teams/:team", savedFilterTeamHandler.DeleteWeb)
 
 	teamHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
@@ -509,6 +930,13 @@ func registerAPIRoutes(a *echo.Group) {
 	a.POST("/teams/:team", teamHandler.UpdateWeb)
 	a.DELETE("/teams/:team", teamHandler.DeleteWeb)
 
+	teamWorkloadHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TeamWorkload{}
+		},
+	}
+	a.GET("/teams/:team/workload", teamWorkloadHandler.ReadOneWeb)
+
 	teamMemberHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.TeamMember{}
@@ -536,6 +964,30 @@ func registerAPIRoutes(a *echo.Group) {
 	a.GET("/notifications", notificationHandler.ReadAllWeb)
 	a.POST("/notifications/:notificationid", notificationHandler.UpdateWeb)
 	a.POST("/notifications", apiv1.MarkAllNotificationsAsRead)
+	a.DELETE("/notifications/read", apiv1.DeleteReadNotifications)
+
+	pushSubscriptionHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.PushSubscription{}
+		},
+	}
+	a.GET("/notifications/push", pushSubscriptionHandler.ReadAllWeb)
+	a.PUT("/notifications/push", pushSubscriptionHandler.CreateWeb)
+	a.DELETE("/notifications/push/:pushsubscription", pushSubscriptionHandler.DeleteWeb)
+
+	if config.EventStreamEnabled.GetBool() {
+		a.GET("/events/stream", apiv1.EventStream)
+	}
+
+	// Global search
+	a.GET("/search", apiv1.Search)
+
+	// GraphQL
+	a.POST("/graphql", apiv1.GraphQL)
+
+	// Batch
+	registerBatchOperations()
+	a.POST("/batch", apiv1.Batch)
 
 	// Migrations
 	m := a.Group("/migration")
@@ -553,6 +1005,14 @@ func registerAPIRoutes(a *echo.Group) {
 			}
 			a.PUT("/projects/:project/backgrounds/upload", uploadBackgroundProvider.UploadBackground)
 		}
+		if config.BackgroundsURLEnabled.GetBool() {
+			urlBackgroundProvider := &backgroundHandler.BackgroundProvider{
+				Provider: func() background.Provider {
+					return &backgroundURL.Provider{}
+				},
+			}
+			a.POST("/projects/:project/backgrounds/url", urlBackgroundProvider.SetBackground)
+		}
 		if config.BackgroundsUnsplashEnabled.GetBool() {
 			unsplashBackgroundProvider := &backgroundHandler.BackgroundProvider{
 				Provider: func() background.Provider {
@@ -561,6 +1021,9 @@ func registerAPIRoutes(a *echo.Group) {
 			}
 			a.GET("/backgrounds/unsplash/search", unsplashBackgroundProvider.SearchBackgrounds)
 			a.POST("/projects/:project/backgrounds/unsplash", unsplashBackgroundProvider.SetBackground)
+			if config.ServiceEnableTaskAttachments.GetBool() {
+				a.POST("/tasks/:task/cover/unsplash", unsplashBackgroundProvider.SetTaskCover)
+			}
 			a.GET("/backgrounds/unsplash/images/:image/thumb", unsplash.ProxyUnsplashThumb)
 			a.GET("/backgrounds/unsplash/images/:image", unsplash.ProxyUnsplashImage)
 		}
@@ -587,9 +1050,33 @@ func registerAPIRoutes(a *echo.Group) {
 		a.PUT("/projects/:project/webhooks", webhookProvider.CreateWeb)
 		a.DELETE("/projects/:project/webhooks/:webhook", webhookProvider.DeleteWeb)
 		a.POST("/projects/:project/webhooks/:webhook", webhookProvider.UpdateWeb)
+
+		webhookDeliveryProvider := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.WebhookDelivery{}
+			},
+		}
+		a.GET("/projects/:project/webhooks/:webhook/deliveries", webhookDeliveryProvider.ReadAllWeb)
+		webhookRedeliveryProvider := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.WebhookRedelivery{}
+			},
+		}
+		a.PUT("/projects/:project/webhooks/:webhook/deliveries/:delivery/redeliver", webhookRedeliveryProvider.CreateWeb)
 		a.GET("/webhooks/events", apiv1.GetAvailableWebhookEvents)
 	}
 
+	if config.WebhooksInboundEnabled.GetBool() {
+		inboundWebhookProvider := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.ProjectInboundWebhook{}
+			},
+		}
+		a.GET("/projects/:project/inboundwebhooks", inboundWebhookProvider.ReadAllWeb)
+		a.PUT("/projects/:project/inboundwebhooks", inboundWebhookProvider.CreateWeb)
+		a.DELETE("/projects/:project/inboundwebhooks/:inboundwebhook", inboundWebhookProvider.DeleteWeb)
+	}
+
 	reactionProvider := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.Reaction{}
@@ -600,6 +1087,49 @@ func registerAPIRoutes(a *echo.Group) {
 	a.PUT("/:entitykind/:entityid/reactions", reactionProvider.CreateWeb)
 }
 
+// registerBatchOperations registers all create, update and delete routes which can be used in batch requests.
+// They use the same paths as the routes of the REST api.
+func registerBatchOperations() {
+	project := func() handler.CObject { return &models.Project{} }
+	batch.Register(http.MethodPut, "/projects", project)
+	batch.Register(http.MethodPost, "/projects/:project", project)
+	batch.Register(http.MethodDelete, "/projects/:project", project)
+
+	bucket := func() handler.CObject { return &models.Bucket{} }
+	batch.Register(http.MethodPut, "/projects/:project/buckets", bucket)
+	batch.Register(http.MethodPost, "/projects/:project/buckets/:bucket", bucket)
+	batch.Register(http.MethodDelete, "/projects/:project/buckets/:bucket", bucket)
+
+	task := func() handler.CObject { return &models.Task{} }
+	batch.Register(http.MethodPut, "/projects/:project/tasks", task)
+	batch.Register(http.MethodPost, "/tasks/:projecttask", task)
+	batch.Register(http.MethodDelete, "/tasks/:projecttask", task)
+
+	assignee := func() handler.CObject { return &models.TaskAssginee{} }
+	batch.Register(http.MethodPut, "/tasks/:projecttask/assignees", assignee)
+	batch.Register(http.MethodDelete, "/tasks/:projecttask/assignees/:user", assignee)
+
+	labelTask := func() handler.CObject { return &models.LabelTask{} }
+	batch.Register(http.MethodPut, "/tasks/:projecttask/labels", labelTask)
+	batch.Register(http.MethodDelete, "/tasks/:projecttask/labels/:label", labelTask)
+
+	relation := func() handler.CObject { return &models.TaskRelation{} }
+	batch.Register(http.MethodPut, "/tasks/:task/relations", relation)
+	batch.Register(http.MethodDelete, "/tasks/:task/relations/:relationKind/:otherTask", relation)
+
+	if config.ServiceEnableTaskComments.GetBool() {
+		comment := func() handler.CObject { return &models.TaskComment{} }
+		batch.Register(http.MethodPut, "/tasks/:task/comments", comment)
+		batch.Register(http.MethodPost, "/tasks/:task/comments/:commentid", comment)
+		batch.Register(http.MethodDelete, "/tasks/:task/comments/:commentid", comment)
+	}
+
+	label := func() handler.CObject { return &models.Label{} }
+	batch.Register(http.MethodPut, "/labels", label)
+	batch.Register(http.MethodPost, "/labels/:label", label)
+	batch.Register(http.MethodDelete, "/labels/:label", label)
+}
+
 func registerMigrations(m *echo.Group) {
 	// Todoist
 	if config.MigrationTodoistEnable.GetBool() {
@@ -532,3 +532,112 @@ func (err *ErrUsernameMustNotContainSpaces) HTTPError() web.HTTPError {
 		Message:  "The username must not contain spaces.",
 	}
 }
+
+// ErrInvalidNotificationPreference represents an error where a notification preference is invalid
+type ErrInvalidNotificationPreference struct {
+	Name string
+}
+
+// IsErrInvalidNotificationPreference checks if an error is a ErrInvalidNotificationPreference.
+func IsErrInvalidNotificationPreference(err error) bool {
+	_, ok := err.(*ErrInvalidNotificationPreference)
+	return ok
+}
+
+func (err *ErrInvalidNotificationPreference) Error() string {
+	return fmt.Sprintf("invalid notification preference [Name: %s]", err.Name)
+}
+
+// ErrCodeInvalidNotificationPreference holds the unique world-error code of this error
+const ErrCodeInvalidNotificationPreference = 1023
+
+// HTTPError holds the http error description
+func (err *ErrInvalidNotificationPreference) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeInvalidNotificationPreference,
+		Message:  "This notification does not exist or cannot be disabled.",
+	}
+}
+
+// ErrInvalidDigestFrequency represents an error where the digest frequency of a user is invalid
+type ErrInvalidDigestFrequency struct {
+	DigestFrequency string
+}
+
+// IsErrInvalidDigestFrequency checks if an error is a ErrInvalidDigestFrequency.
+func IsErrInvalidDigestFrequency(err error) bool {
+	_, ok := err.(*ErrInvalidDigestFrequency)
+	return ok
+}
+
+func (err *ErrInvalidDigestFrequency) Error() string {
+	return fmt.Sprintf("invalid digest frequency [DigestFrequency: %s]", err.DigestFrequency)
+}
+
+// ErrCodeInvalidDigestFrequency holds the unique world-error code of this error
+const ErrCodeInvalidDigestFrequency = 1024
+
+// HTTPError holds the http error description
+func (err *ErrInvalidDigestFrequency) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeInvalidDigestFrequency,
+		Message:  "The digest frequency is invalid. Valid values are daily, weekly or an empty string to disable the digest.",
+	}
+}
+
+// ErrInvalidQuietHours represents an error where the quiet hours of a user are incomplete
+type ErrInvalidQuietHours struct {
+	Start string
+	End   string
+}
+
+// IsErrInvalidQuietHours checks if an error is ErrInvalidQuietHours.
+func IsErrInvalidQuietHours(err error) bool {
+	_, ok := err.(*ErrInvalidQuietHours)
+	return ok
+}
+
+func (err *ErrInvalidQuietHours) Error() string {
+	return fmt.Sprintf("invalid quiet hours [Start: %s, End: %s]", err.Start, err.End)
+}
+
+// ErrCodeInvalidQuietHours holds the unique world-error code of this error
+const ErrCodeInvalidQuietHours = 1025
+
+// HTTPError holds the http error description
+func (err *ErrInvalidQuietHours) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeInvalidQuietHours,
+		Message:  "The quiet hours need both a start and an end time.",
+	}
+}
+
+// ErrInvalidNotificationTarget represents an error where a notification target url of a user is invalid
+type ErrInvalidNotificationTarget struct {
+	Reason string
+}
+
+// IsErrInvalidNotificationTarget checks if an error is ErrInvalidNotificationTarget.
+func IsErrInvalidNotificationTarget(err error) bool {
+	_, ok := err.(*ErrInvalidNotificationTarget)
+	return ok
+}
+
+func (err *ErrInvalidNotificationTarget) Error() string {
+	return fmt.Sprintf("invalid notification target [Reason: %s]", err.Reason)
+}
+
+// ErrCodeInvalidNotificationTarget holds the unique world-error code of this error
+const ErrCodeInvalidNotificationTarget = 1026
+
+// HTTPError holds the http error description
+func (err *ErrInvalidNotificationTarget) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeInvalidNotificationTarget,
+		Message:  "A notification target url is invalid: " + err.Reason,
+	}
+}
@@ -0,0 +1,343 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"encoding/json"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+)
+
+// NotificationChannelPreferences holds via which channels a user gets a kind of notification
+type NotificationChannelPreferences struct {
+	// If true, the notification is sent via email.
+	Email bool `json:"email"`
+	// If true, the notification is shown in the app.
+	InApp bool `json:"in_app"`
+	// If true, the notification is sent as Web Push message to all browsers and devices the user registered.
+	Push bool `json:"push"`
+	// If true, the notification is published to the ntfy topic of the user.
+	Ntfy bool `json:"ntfy"`
+	// If true, the notification is sent to the Gotify application of the user.
+	Gotify bool `json:"gotify"`
+	// If true, the notification is posted to the Matrix room of the user.
+	Matrix bool `json:"matrix"`
+	// If true, the notification is sent to all target urls of the user.
+	Targets bool `json:"targets"`
+}
+
+// UnmarshalJSON enables all channels which are not explicitly disabled, also for preferences stored before a
+// channel existed.
+func (p *NotificationChannelPreferences) UnmarshalJSON(data []byte) error {
+	type plain NotificationChannelPreferences
+	preferences := plain{Email: true, InApp: true, Push: true, Ntfy: true, Gotify: true, Matrix: true, Targets: true}
+	if err := json.Unmarshal(data, &preferences); err != nil {
+		return err
+	}
+	*p = NotificationChannelPreferences(preferences)
+	return nil
+}
+
+// NotificationPreferences maps the names of notifications to the channels a user wants to get them.
+// Notifications which are not in the map are sent via all channels.
+type NotificationPreferences map[string]*NotificationChannelPreferences
+
+// ConfigurableNotifications holds the names of all notifications a user can enable or disable.
+// Notifications about the account itself, like password resets, are always sent.
+var ConfigurableNotifications = []string{
+	"task.assigned",
+	"task.mentioned",
+	"task.comment",
+	"task.reaction",
+	"task.reminder",
+	"task.reminder.escalated",
+	"task.undone.overdue",
+	"task.deleted",
+	"task.sla.breached",
+	"team.member.added",
+	"project.created",
+	"bucket.limit.exceeded",
+	"filter.task.matched",
+	"filter.task.unmatched",
+	"data.export.ready",
+	"migration.done",
+}
+
+func isConfigurableNotification(name string) bool {
+	for _, n := range ConfigurableNotifications {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p NotificationPreferences) validate() error {
+	for name, channels := range p {
+		if !isConfigurableNotification(name) || channels == nil {
+			return &ErrInvalidNotificationPreference{Name: name}
+		}
+	}
+	return nil
+}
+
+// For returns the channel preferences of one notification
+func (p NotificationPreferences) For(name string) *NotificationChannelPreferences {
+	if channels, has := p[name]; has && channels != nil {
+		return channels
+	}
+	return &NotificationChannelPreferences{Email: true, InApp: true, Push: true, Ntfy: true, Gotify: true, Matrix: true, Targets: true}
+}
+
+// WithDefaults returns the preferences for all configurable notifications, including the ones the user never changed.
+func (p NotificationPreferences) WithDefaults() NotificationPreferences {
+	all := make(NotificationPreferences, len(ConfigurableNotifications))
+	for _, name := range ConfigurableNotifications {
+		all[name] = p.For(name)
+	}
+	return all
+}
+
+// ShouldNotifyVia checks the notification preferences of a user for one notification and channel
+func (u *User) ShouldNotifyVia(channel notifications.Channel, notificationName string) (bool, error) {
+	s := db.NewSession()
+	defer s.Close()
+	user, err := getUser(s, &User{ID: u.ID}, true)
+	if err != nil {
+		return false, err
+	}
+
+	channels := user.NotificationPreferences.For(notificationName)
+	switch channel {
+	case notifications.ChannelMail:
+		return channels.Email, nil
+	case notifications.ChannelDB:
+		return channels.InApp, nil
+	case notifications.ChannelPush:
+		return channels.Push, nil
+	case notifications.ChannelNtfy:
+		return channels.Ntfy, nil
+	case notifications.ChannelGotify:
+		return channels.Gotify, nil
+	case notifications.ChannelMatrix:
+		return channels.Matrix, nil
+	case notifications.ChannelTargets:
+		return channels.Targets, nil
+	}
+
+	return true, nil
+}
+
+// NotificationTargets holds where a user gets their notifications on external services
+type NotificationTargets struct {
+	// The ntfy topic to publish the notifications of the user to.
+	NtfyTopic string `json:"ntfy_topic"`
+	// An access token for the ntfy topic. If empty, the token configured for the instance is used.
+	NtfyToken string `json:"ntfy_token"`
+	// The token of the Gotify application which should get the notifications of the user.
+	GotifyToken string `json:"gotify_token"`
+	// The id of the Matrix room the bot user of the instance posts the notifications of the user to,
+	// usually a direct message room with it.
+	MatrixRoomID string `json:"matrix_room_id"`
+	// Urls of other services the notifications of the user are sent to, for example
+	// `ntfys://ntfy.example.com/topic` or `discord://webhook_id/webhook_token`.
+	URLs []string `json:"urls"`
+}
+
+// maxNotificationTargetURLs is how many target urls a user can configure
+const maxNotificationTargetURLs = 10
+
+func (t *NotificationTargets) validate() error {
+	if t == nil {
+		return nil
+	}
+	if len(t.URLs) > maxNotificationTargetURLs {
+		return &ErrInvalidNotificationTarget{Reason: "too many target urls"}
+	}
+	for _, target := range t.URLs {
+		if err := notifications.ValidateTarget(target); err != nil {
+			return &ErrInvalidNotificationTarget{Reason: err.Error()}
+		}
+	}
+	return nil
+}
+
+func (u *User) getNotificationTargets() (*NotificationTargets, error) {
+	s := db.NewSession()
+	defer s.Close()
+	user, err := getUser(s, &User{ID: u.ID}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.NotificationTargets == nil {
+		return &NotificationTargets{}, nil
+	}
+	return user.NotificationTargets, nil
+}
+
+// RouteForNtfy routes all notifications for a user to their ntfy topic
+func (u *User) RouteForNtfy() (topic, token string, err error) {
+	targets, err := u.getNotificationTargets()
+	if err != nil {
+		return "", "", err
+	}
+	return targets.NtfyTopic, targets.NtfyToken, nil
+}
+
+// RouteForGotify routes all notifications for a user to their Gotify application
+func (u *User) RouteForGotify() (token string, err error) {
+	targets, err := u.getNotificationTargets()
+	if err != nil {
+		return "", err
+	}
+	return targets.GotifyToken, nil
+}
+
+// RouteForTargets routes all notifications for a user to their target urls
+func (u *User) RouteForTargets() (targets []string, err error) {
+	t, err := u.getNotificationTargets()
+	if err != nil {
+		return nil, err
+	}
+	return t.URLs, nil
+}
+
+// RouteForMatrix routes all notifications for a user to their Matrix room
+func (u *User) RouteForMatrix() (roomID string, err error) {
+	targets, err := u.getNotificationTargets()
+	if err != nil {
+		return "", err
+	}
+	return targets.MatrixRoomID, nil
+}
+
+// quietHoursEndAt returns when the quiet hours of the user which include t end or a zero time if t is not
+// within the quiet hours of the user.
+func (u *User) quietHoursEndAt(t time.Time) (end time.Time, err error) {
+	if u.QuietHoursStart == "" || u.QuietHoursEnd == "" {
+		return
+	}
+
+	tz := config.GetTimeZone()
+	if u.Timezone != "" {
+		tz, err = time.LoadLocation(u.Timezone)
+		if err != nil {
+			return
+		}
+	}
+
+	startTime, err := time.Parse("15:04", u.QuietHoursStart)
+	if err != nil {
+		return
+	}
+	endTime, err := time.Parse("15:04", u.QuietHoursEnd)
+	if err != nil {
+		return
+	}
+
+	t = t.In(tz)
+	start := time.Date(t.Year(), t.Month(), t.Day(), startTime.Hour(), startTime.Minute(), 0, 0, tz)
+	end = time.Date(t.Year(), t.Month(), t.Day(), endTime.Hour(), endTime.Minute(), 0, 0, tz)
+
+	switch {
+	case start.Equal(end):
+		return time.Time{}, nil
+	case start.Before(end):
+		// Quiet hours within the same day, for example 12:00 to 14:00
+		if !t.Before(start) && t.Before(end) {
+			return end, nil
+		}
+	default:
+		// Quiet hours over night, for example 22:00 to 07:00
+		if !t.Before(start) {
+			return end.AddDate(0, 0, 1), nil
+		}
+		if t.Before(end) {
+			return end, nil
+		}
+	}
+
+	return time.Time{}, nil
+}
+
+// QuietUntil returns when the current quiet hours of the user end. Notifications about the account itself
+// are never held back and urgent ones only if the user did not exempt them.
+func (u *User) QuietUntil(now time.Time, notificationName string, urgent bool) (end time.Time, err error) {
+	if !isConfigurableNotification(notificationName) {
+		return
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+	user, err := getUser(s, &User{ID: u.ID}, true)
+	if err != nil {
+		return
+	}
+
+	if urgent && user.QuietHoursExemptUrgent {
+		return
+	}
+
+	return user.quietHoursEndAt(now)
+}
+
+// RegisterQueuedNotificationsCron registers a cron function which delivers all notifications which were held
+// back during the quiet hours of their users once the quiet hours are over.
+func RegisterQueuedNotificationsCron() {
+	const logPrefix = "[Queued Notifications Cron] "
+
+	err := cron.Schedule("* * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		queued, err := notifications.GetDueQueuedNotifications(s, time.Now())
+		if err != nil {
+			log.Errorf(logPrefix+"Could not get queued notifications: %s", err)
+			return
+		}
+
+		for _, q := range queued {
+			u, err := GetUserByID(s, q.NotifiableID)
+			if IsErrUserDoesNotExist(err) {
+				// The user was deleted after the notification was queued
+				_, err = s.Where("id = ?", q.ID).Delete(&notifications.QueuedNotification{})
+				if err != nil {
+					log.Errorf(logPrefix+"Could not delete queued notification %d: %s", q.ID, err)
+				}
+				continue
+			}
+			if err != nil {
+				log.Errorf(logPrefix+"Could not get user %d: %s", q.NotifiableID, err)
+				continue
+			}
+
+			err = notifications.DeliverQueuedNotification(s, u, q)
+			if err != nil {
+				log.Errorf(logPrefix+"Could not deliver queued notification %d to user %d: %s", q.ID, q.NotifiableID, err)
+			}
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register queued notifications cron: %s", err)
+	}
+}
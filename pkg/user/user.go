@@ -65,6 +65,15 @@ func (s Status) String() string {
 	return "Unknown"
 }
 
+const (
+	// DigestFrequencyDaily sends the digest every day.
+	DigestFrequencyDaily = "daily"
+	// DigestFrequencyWeekly sends the digest on the first day of the week of the user.
+	DigestFrequencyWeekly = "weekly"
+	// DefaultDigestTime is the time the digest is sent if the user did not set one.
+	DefaultDigestTime = "08:00"
+)
+
 const (
 	StatusActive Status = iota
 	StatusEmailConfirmationRequired
@@ -107,6 +116,16 @@ type User struct {
 
 	FrontendSettings interface{} `xorm:"json null" json:"-"`
 
+	NotificationPreferences NotificationPreferences `xorm:"json null" json:"-"`
+	NotificationTargets     *NotificationTargets    `xorm:"json null" json:"-"`
+
+	DigestFrequency string `xorm:"varchar(10) null" json:"-"`
+	DigestTime      string `xorm:"varchar(5) null" json:"-"`
+
+	QuietHoursStart        string `xorm:"varchar(5) null" json:"-"`
+	QuietHoursEnd          string `xorm:"varchar(5) null" json:"-"`
+	QuietHoursExemptUrgent bool   `xorm:"bool null" json:"-"`
+
 	ExportFileID int64 `xorm:"bigint null" json:"-"`
 
 	// A timestamp when this task was created. You cannot change this value.
@@ -114,6 +133,11 @@ type User struct {
 	// A timestamp when this task was last updated. You cannot change this value.
 	Updated time.Time `xorm:"updated not null" json:"updated"`
 
+	// Whether the user authenticated with an api token for the current request.
+	AuthenticatedWithAPIToken bool `xorm:"-" json:"-"`
+	// The projects the api token used for the current request is restricted to. Empty if it is not restricted.
+	APITokenProjectIDs []int64 `xorm:"-" json:"-"`
+
 	web.Auth `xorm:"-" json:"-"`
 }
 
@@ -507,6 +531,27 @@ func UpdateUser(s *xorm.Session, user *User, forceOverride bool) (updatedUser *U
 		return
 	}
 
+	err = user.NotificationPreferences.validate()
+	if err != nil {
+		return nil, err
+	}
+	err = user.NotificationTargets.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DigestFrequency != "" &&
+		user.DigestFrequency != DigestFrequencyDaily &&
+		user.DigestFrequency != DigestFrequencyWeekly {
+		return nil, &ErrInvalidDigestFrequency{DigestFrequency: user.DigestFrequency}
+	}
+	if user.DigestTime == "" {
+		user.DigestTime = DefaultDigestTime
+	}
+	if (user.QuietHoursStart == "") != (user.QuietHoursEnd == "") {
+		return nil, &ErrInvalidQuietHours{Start: user.QuietHoursStart, End: user.QuietHoursEnd}
+	}
+
 	frontendSettingsJSON, err := json.Marshal(user.FrontendSettings)
 	if err != nil {
 		return nil, err
@@ -533,6 +578,13 @@ func UpdateUser(s *xorm.Session, user *User, forceOverride bool) (updatedUser *U
 			"timezone",
 			"overdue_tasks_reminders_time",
 			"frontend_settings",
+			"notification_preferences",
+			"notification_targets",
+			"digest_frequency",
+			"digest_time",
+			"quiet_hours_start",
+			"quiet_hours_end",
+			"quiet_hours_exempt_urgent",
 		).
 		Update(user)
 	if err != nil {
@@ -18,8 +18,10 @@ package user
 
 import (
 	"testing"
+	"time"
 
 	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -335,6 +337,162 @@ func TestUpdateUser(t *testing.T) {
 		require.Error(t, err)
 		assert.True(t, IsErrUserDoesNotExist(err))
 	})
+	t.Run("notification preferences", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		uuser, err := UpdateUser(s, &User{
+			ID: 1,
+			NotificationPreferences: NotificationPreferences{
+				"task.comment": {Email: false, InApp: true},
+			},
+		}, false)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		assert.False(t, uuser.NotificationPreferences.For("task.comment").Email)
+		assert.True(t, uuser.NotificationPreferences.For("task.comment").InApp)
+		assert.True(t, uuser.NotificationPreferences.For("task.assigned").Email)
+
+		should, err := uuser.ShouldNotifyVia(notifications.ChannelMail, "task.comment")
+		require.NoError(t, err)
+		assert.False(t, should)
+		should, err = uuser.ShouldNotifyVia(notifications.ChannelDB, "task.comment")
+		require.NoError(t, err)
+		assert.True(t, should)
+		should, err = uuser.ShouldNotifyVia(notifications.ChannelMail, "task.assigned")
+		require.NoError(t, err)
+		assert.True(t, should)
+	})
+	t.Run("invalid notification preference", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := UpdateUser(s, &User{
+			ID: 1,
+			NotificationPreferences: NotificationPreferences{
+				"user.password.reset": {Email: false, InApp: false},
+			},
+		}, false)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidNotificationPreference(err))
+	})
+	t.Run("invalid digest frequency", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := UpdateUser(s, &User{
+			ID:              1,
+			DigestFrequency: "hourly",
+		}, false)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidDigestFrequency(err))
+	})
+	t.Run("quiet hours", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		uuser, err := UpdateUser(s, &User{
+			ID:                     1,
+			Timezone:               "Europe/Berlin",
+			QuietHoursStart:        "22:00",
+			QuietHoursEnd:          "07:00",
+			QuietHoursExemptUrgent: true,
+		}, false)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		assert.Equal(t, "22:00", uuser.QuietHoursStart)
+
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		now := time.Date(2023, 1, 10, 23, 30, 0, 0, berlin)
+
+		until, err := uuser.QuietUntil(now, "task.comment", false)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 1, 11, 7, 0, 0, 0, berlin).Unix(), until.Unix())
+
+		// Urgent notifications are exempt
+		until, err = uuser.QuietUntil(now, "task.reminder", true)
+		require.NoError(t, err)
+		assert.True(t, until.IsZero())
+
+		// Notifications about the account are never held back
+		until, err = uuser.QuietUntil(now, "", false)
+		require.NoError(t, err)
+		assert.True(t, until.IsZero())
+	})
+	t.Run("incomplete quiet hours", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := UpdateUser(s, &User{
+			ID:              1,
+			QuietHoursStart: "22:00",
+		}, false)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidQuietHours(err))
+	})
+	t.Run("invalid notification target", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := UpdateUser(s, &User{
+			ID: 1,
+			NotificationTargets: &NotificationTargets{
+				URLs: []string{"unknown://example.com"},
+			},
+		}, false)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidNotificationTarget(err))
+	})
+}
+
+func TestUser_quietHoursEndAt(t *testing.T) {
+	u := &User{Timezone: "UTC", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"}
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "before the quiet hours",
+			now:  time.Date(2023, 1, 10, 21, 59, 0, 0, time.UTC),
+		},
+		{
+			name: "at the start",
+			now:  time.Date(2023, 1, 10, 22, 0, 0, 0, time.UTC),
+			want: time.Date(2023, 1, 11, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "after midnight",
+			now:  time.Date(2023, 1, 11, 3, 0, 0, 0, time.UTC),
+			want: time.Date(2023, 1, 11, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "at the end",
+			now:  time.Date(2023, 1, 11, 7, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, err := u.quietHoursEndAt(tt.now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Unix(), end.Unix())
+			assert.Equal(t, tt.want.IsZero(), end.IsZero())
+		})
+	}
+
+	t.Run("during the day", func(t *testing.T) {
+		u := &User{Timezone: "UTC", QuietHoursStart: "12:00", QuietHoursEnd: "14:00"}
+		end, err := u.quietHoursEndAt(time.Date(2023, 1, 10, 13, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 1, 10, 14, 0, 0, 0, time.UTC).Unix(), end.Unix())
+	})
 }
 
 func TestUpdateUserPassword(t *testing.T) {
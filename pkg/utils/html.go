@@ -0,0 +1,73 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	htmlPolicy     *bluemonday.Policy
+	htmlPolicyOnce sync.Once
+)
+
+func getHTMLPolicy() *bluemonday.Policy {
+	htmlPolicyOnce.Do(func() {
+		htmlPolicy = bluemonday.UGCPolicy()
+		// Task lists as created by the editor in the frontend
+		htmlPolicy.AllowAttrs("data-type").Matching(bluemonday.SpaceSeparatedTokens).OnElements("ul", "li")
+		htmlPolicy.AllowAttrs("data-checked").Matching(bluemonday.SpaceSeparatedTokens).OnElements("li")
+		htmlPolicy.AllowAttrs("type", "checked", "disabled").OnElements("input")
+		htmlPolicy.AllowElements("input", "label")
+		// Mentions
+		htmlPolicy.AllowAttrs("data-id", "data-label").OnElements("span")
+		// Syntax highlighting of code blocks
+		htmlPolicy.AllowAttrs("class").Matching(bluemonday.SpaceSeparatedTokens).OnElements("code", "span")
+		htmlPolicy.AllowAttrs("alt", "title", "src", "width", "height").OnElements("img")
+		htmlPolicy.AllowDataURIImages()
+	})
+	return htmlPolicy
+}
+
+// SanitizeHTML removes everything which could be used to run scripts or otherwise harm a user from an html
+// string while keeping the formatting the frontend editor produces.
+func SanitizeHTML(input string) string {
+	if input == "" {
+		return ""
+	}
+	return getHTMLPolicy().Sanitize(input)
+}
+
+// MarkdownToHTML renders markdown to sanitized html.
+func MarkdownToHTML(input string) (output string, err error) {
+	if input == "" {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+	err = md.Convert([]byte(input), &buf)
+	if err != nil {
+		return
+	}
+	return SanitizeHTML(buf.String()), nil
+}
@@ -0,0 +1,62 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	t.Run("removes scripts", func(t *testing.T) {
+		assert.Equal(t, "<p>Lorem</p>", SanitizeHTML("<p>Lorem</p><script>alert(1)</script>"))
+	})
+	t.Run("removes event handlers", func(t *testing.T) {
+		assert.Equal(t, `<img src="https://example.com/a.png">`, SanitizeHTML(`<img src="https://example.com/a.png" onerror="alert(1)">`))
+	})
+	t.Run("removes javascript links", func(t *testing.T) {
+		assert.Equal(t, "click", SanitizeHTML(`<a href="javascript:alert(1)">click</a>`))
+	})
+	t.Run("keeps task lists", func(t *testing.T) {
+		in := `<ul data-type="taskList"><li data-checked="true" data-type="taskItem"><p>Done</p></li></ul>`
+		assert.Equal(t, in, SanitizeHTML(in))
+	})
+	t.Run("keeps formatting", func(t *testing.T) {
+		in := `<p><strong>bold</strong> <em>italic</em> <code class="language-go">code</code></p>`
+		assert.Equal(t, in, SanitizeHTML(in))
+	})
+}
+
+func TestMarkdownToHTML(t *testing.T) {
+	t.Run("renders markdown", func(t *testing.T) {
+		out, err := MarkdownToHTML("Card Description **bold**")
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Card Description <strong>bold</strong></p>\n", out)
+	})
+	t.Run("sanitizes embedded html", func(t *testing.T) {
+		out, err := MarkdownToHTML("Lorem <script>alert(1)</script>")
+		require.NoError(t, err)
+		assert.NotContains(t, out, "script")
+	})
+	t.Run("empty", func(t *testing.T) {
+		out, err := MarkdownToHTML("")
+		require.NoError(t, err)
+		assert.Equal(t, "", out)
+	})
+}
@@ -0,0 +1,47 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSearchText(t *testing.T) {
+	t.Run("lowercases", func(t *testing.T) {
+		assert.Equal(t, "task 1", NormalizeSearchText("Task #1"))
+	})
+	t.Run("strips diacritics", func(t *testing.T) {
+		assert.Equal(t, "creme brulee", NormalizeSearchText("Crème Brûlée"))
+	})
+	t.Run("collapses separators", func(t *testing.T) {
+		assert.Equal(t, "buy milk and eggs", NormalizeSearchText("  buy milk,   and--eggs! "))
+	})
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, "", NormalizeSearchText(" - "))
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, EditDistance("task", "task"))
+	assert.Equal(t, 1, EditDistance("task", "tsak"))
+	assert.Equal(t, 1, EditDistance("task", "tasks"))
+	assert.Equal(t, 1, EditDistance("task", "tusk"))
+	assert.Equal(t, 2, EditDistance("task", "ta"))
+	assert.Equal(t, 4, EditDistance("", "task"))
+}